@@ -0,0 +1,55 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	completionShells      = []string{"bash", "zsh", "fish"}
+	notificationTypeNames = []string{"email", "sms"}
+	logLevelNames         = []string{"DEBUG", "INFO", "WARN", "ERROR"}
+)
+
+// buildCompletionCommand replaces cobra's default completion command so the supported
+// shells are explicit. Completions are computed locally and never dial the server.
+func buildCompletionCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion [bash|zsh|fish]",
+		Short: "Generate a shell completion script",
+		Long: `Generate a shell completion script for pinguin-cli.
+
+  bash:  source <(pinguin-cli completion bash)
+  zsh:   pinguin-cli completion zsh > "${fpath[1]}/_pinguin-cli"
+  fish:  pinguin-cli completion fish > ~/.config/fish/completions/pinguin-cli.fish`,
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs:             completionShells,
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			output := cmd.OutOrStdout()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(output, true)
+			case "zsh":
+				return root.GenZshCompletion(output)
+			case "fish":
+				return root.GenFishCompletion(output, true)
+			default:
+				return fmt.Errorf("unsupported shell %q", args[0])
+			}
+		},
+	}
+}
+
+// registerFlagCompletions attaches static value completions and suppresses file
+// completion for flags that never take a path.
+func registerFlagCompletions(command *cobra.Command, choices map[string][]string, freeText ...string) {
+	for flagName, values := range choices {
+		_ = command.RegisterFlagCompletionFunc(flagName, cobra.FixedCompletions(values, cobra.ShellCompDirectiveNoFileComp))
+	}
+	for _, flagName := range freeText {
+		_ = command.RegisterFlagCompletionFunc(flagName, cobra.NoFileCompletions)
+	}
+}
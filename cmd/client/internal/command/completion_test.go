@@ -0,0 +1,74 @@
+package command
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/tyemirov/pinguin/pkg/client"
+)
+
+func runOfflineCommand(t *testing.T, arguments ...string) (string, error) {
+	t.Helper()
+	var stdout bytes.Buffer
+	command := NewRootCommand(Dependencies{
+		NewSender: func(*slog.Logger, client.Settings) (NotificationSender, io.Closer, error) {
+			t.Fatalf("completion must not build a sender")
+			return nil, nil, nil
+		},
+	})
+	command.SetOut(&stdout)
+	command.SetErr(io.Discard)
+	command.SetArgs(arguments)
+	err := command.Execute()
+	return stdout.String(), err
+}
+
+func TestCompletionCommandGeneratesShellScripts(t *testing.T) {
+	for shell, marker := range map[string]string{
+		"bash": "# bash completion V2 for pinguin-cli",
+		"zsh":  "#compdef pinguin-cli",
+		"fish": "# fish completion for pinguin-cli",
+	} {
+		output, err := runOfflineCommand(t, "completion", shell)
+		if err != nil {
+			t.Fatalf("%s completion error: %v", shell, err)
+		}
+		if !strings.Contains(output, marker) {
+			t.Fatalf("expected %s script to contain %q", shell, marker)
+		}
+	}
+	if _, err := runOfflineCommand(t, "completion", "tcsh"); err == nil {
+		t.Fatalf("expected an unsupported shell to be rejected")
+	}
+}
+
+func TestFlagCompletionsSuggestStaticValues(t *testing.T) {
+	testCases := []struct {
+		name      string
+		arguments []string
+		expected  []string
+	}{
+		{name: "notification type", arguments: []string{"__complete", "send", "--type", ""}, expected: []string{"email", "sms"}},
+		{name: "log level", arguments: []string{"__complete", "send", "--log-level", ""}, expected: []string{"DEBUG", "INFO", "WARN", "ERROR"}},
+		{name: "free text", arguments: []string{"__complete", "send", "--recipient", ""}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			output, err := runOfflineCommand(t, testCase.arguments...)
+			if err != nil {
+				t.Fatalf("complete error: %v", err)
+			}
+			lines := strings.Split(strings.TrimSpace(output), "\n")
+			directive := lines[len(lines)-1]
+			if directive != ":4" {
+				t.Fatalf("expected file completion to be disabled, got %q", directive)
+			}
+			if got := lines[:len(lines)-1]; strings.Join(got, ",") != strings.Join(testCase.expected, ",") {
+				t.Fatalf("expected %v, got %v", testCase.expected, got)
+			}
+		})
+	}
+}
@@ -32,15 +32,21 @@ func NewRootCommand(dependencies Dependencies) *cobra.Command {
 		SilenceUsage:  true,
 		SilenceErrors: true,
 	}
+	root.CompletionOptions.DisableDefaultCmd = true
 
-	root.PersistentFlags().String("grpc-server-addr", "localhost:50051", "Target gRPC endpoint")
+	root.PersistentFlags().String("grpc-server-addr", "localhost:50051", "Target gRPC endpoint (host:port, tcp4://, tcp6://, or unix://)")
 	root.PersistentFlags().String("grpc-auth-token", "", "Bearer token used for gRPC authentication")
 	root.PersistentFlags().String("tenant-id", "", "Tenant identifier used for requests")
 	root.PersistentFlags().Int("connection-timeout-sec", 5, "Dial timeout in seconds")
 	root.PersistentFlags().Int("operation-timeout-sec", 30, "Per-command timeout in seconds")
 	root.PersistentFlags().String("log-level", "INFO", "CLI log level (DEBUG, INFO, WARN, ERROR)")
 
+	registerFlagCompletions(root, map[string][]string{"log-level": logLevelNames},
+		"grpc-server-addr", "grpc-auth-token", "tenant-id", "connection-timeout-sec", "operation-timeout-sec")
+
 	root.AddCommand(buildSendCommand(dependencies))
+	root.AddCommand(buildCompletionCommand())
+	root.AddCommand(buildSchemaCommand())
 	return root
 }
 
@@ -51,6 +57,7 @@ func buildSendCommand(dependencies Dependencies) *cobra.Command {
 		subjectInput   string
 		messageInput   string
 		scheduledInput string
+		expiresInput   string
 		attachmentArgs []string
 	)
 
@@ -166,6 +173,13 @@ func buildSendCommand(dependencies Dependencies) *cobra.Command {
 				}
 				request.ScheduledTime = timestamppb.New(scheduledTime.UTC())
 			}
+			if expiresInput != "" {
+				expiresAt, parseErr := time.Parse(time.RFC3339, expiresInput)
+				if parseErr != nil {
+					return fmt.Errorf("invalid expiry time %q: %w", expiresInput, parseErr)
+				}
+				request.ExpiresAt = timestamppb.New(expiresAt.UTC())
+			}
 
 			timeout := settings.OperationTimeout()
 
@@ -197,7 +211,10 @@ func buildSendCommand(dependencies Dependencies) *cobra.Command {
 	command.Flags().StringVar(&subjectInput, "subject", "", "Email subject (ignored for sms)")
 	command.Flags().StringVar(&messageInput, "message", "", "Notification message")
 	command.Flags().StringVar(&scheduledInput, "scheduled-time", "", "RFC3339 timestamp for scheduled delivery")
+	command.Flags().StringVar(&expiresInput, "expires-at", "", "RFC3339 timestamp after which the notification must not be sent")
 	command.Flags().StringArrayVar(&attachmentArgs, "attachment", nil, "Attachment path (repeatable). Use path::content-type to override MIME type")
+	registerFlagCompletions(command, map[string][]string{"type": notificationTypeNames},
+		"recipient", "to", "subject", "message", "scheduled-time", "expires-at")
 
 	return command
 }
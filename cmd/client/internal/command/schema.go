@@ -0,0 +1,85 @@
+package command
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	schemaCommandName = "__schema"
+	schemaVersion     = 1
+)
+
+// cliSchema is the machine-readable command tree printed by __schema. Commands and
+// flags are sorted by name so the output only changes when the CLI does.
+type cliSchema struct {
+	SchemaVersion int           `json:"schema_version"`
+	Command       commandSchema `json:"command"`
+}
+
+type commandSchema struct {
+	Name     string          `json:"name"`
+	Path     string          `json:"path"`
+	Use      string          `json:"use"`
+	Short    string          `json:"short,omitempty"`
+	Flags    []flagSchema    `json:"flags"`
+	Commands []commandSchema `json:"commands,omitempty"`
+}
+
+type flagSchema struct {
+	Name       string `json:"name"`
+	Shorthand  string `json:"shorthand,omitempty"`
+	Type       string `json:"type"`
+	Default    string `json:"default"`
+	Usage      string `json:"usage"`
+	Persistent bool   `json:"persistent"`
+}
+
+// buildSchemaCommand prints the command tree as JSON for wrapper tooling.
+func buildSchemaCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:    schemaCommandName,
+		Short:  "Print the command tree with flags as JSON",
+		Hidden: true,
+		Args:   cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(cliSchema{SchemaVersion: schemaVersion, Command: describeCommand(cmd.Root())})
+		},
+	}
+}
+
+func describeCommand(command *cobra.Command) commandSchema {
+	schema := commandSchema{
+		Name:  command.Name(),
+		Path:  command.CommandPath(),
+		Use:   command.Use,
+		Short: command.Short,
+		Flags: []flagSchema{},
+	}
+	persistentFlags := command.PersistentFlags()
+	command.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+		if flag.Name == "help" {
+			return
+		}
+		schema.Flags = append(schema.Flags, flagSchema{
+			Name:       flag.Name,
+			Shorthand:  flag.Shorthand,
+			Type:       flag.Value.Type(),
+			Default:    flag.DefValue,
+			Usage:      flag.Usage,
+			Persistent: persistentFlags.Lookup(flag.Name) != nil,
+		})
+	})
+	for _, child := range command.Commands() {
+		if child.Hidden || strings.HasPrefix(child.Name(), "__") || child.Name() == "help" {
+			continue
+		}
+		schema.Commands = append(schema.Commands, describeCommand(child))
+	}
+	return schema
+}
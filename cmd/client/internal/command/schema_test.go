@@ -0,0 +1,81 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/tyemirov/pinguin/pkg/client"
+)
+
+func runSchemaCommand(t *testing.T) []byte {
+	t.Helper()
+	var stdout bytes.Buffer
+	command := NewRootCommand(Dependencies{
+		NewSender: func(*slog.Logger, client.Settings) (NotificationSender, io.Closer, error) {
+			t.Fatalf("schema must not build a sender")
+			return nil, nil, nil
+		},
+	})
+	command.SetOut(&stdout)
+	command.SetErr(io.Discard)
+	command.SetArgs([]string{schemaCommandName})
+	if err := command.Execute(); err != nil {
+		t.Fatalf("schema command error: %v", err)
+	}
+	return stdout.Bytes()
+}
+
+func TestSchemaCommandIsStableAndDescribesSendFlags(t *testing.T) {
+	first := runSchemaCommand(t)
+	if second := runSchemaCommand(t); !bytes.Equal(first, second) {
+		t.Fatalf("expected identical schema output across runs")
+	}
+
+	var schema cliSchema
+	if err := json.Unmarshal(first, &schema); err != nil {
+		t.Fatalf("decode schema: %v", err)
+	}
+	if schema.SchemaVersion != schemaVersion || schema.Command.Name != "pinguin-cli" {
+		t.Fatalf("unexpected schema header %+v", schema)
+	}
+	var commandNames []string
+	var send commandSchema
+	for _, child := range schema.Command.Commands {
+		commandNames = append(commandNames, child.Name)
+		if child.Name == "send" {
+			send = child
+		}
+	}
+	if !reflect.DeepEqual(commandNames, []string{"completion", "send"}) {
+		t.Fatalf("expected the hidden schema and help commands to be omitted, got %v", commandNames)
+	}
+	if send.Path != "pinguin-cli send" {
+		t.Fatalf("unexpected send path %q", send.Path)
+	}
+
+	expectedSendFlags := []flagSchema{
+		{Name: "attachment", Type: "stringArray", Default: "[]", Usage: "Attachment path (repeatable). Use path::content-type to override MIME type"},
+		{Name: "expires-at", Type: "string", Usage: "RFC3339 timestamp after which the notification must not be sent"},
+		{Name: "message", Type: "string", Usage: "Notification message"},
+		{Name: "recipient", Type: "string", Usage: "Notification recipient"},
+		{Name: "scheduled-time", Type: "string", Usage: "RFC3339 timestamp for scheduled delivery"},
+		{Name: "subject", Type: "string", Usage: "Email subject (ignored for sms)"},
+		{Name: "to", Type: "string", Usage: "Alias for --recipient"},
+		{Name: "type", Type: "string", Default: "email", Usage: "Notification type (email or sms)"},
+	}
+	if !reflect.DeepEqual(send.Flags, expectedSendFlags) {
+		t.Fatalf("unexpected send flags:\n%+v", send.Flags)
+	}
+
+	persistent := map[string]flagSchema{}
+	for _, flag := range schema.Command.Flags {
+		persistent[flag.Name] = flag
+	}
+	if flag := persistent["operation-timeout-sec"]; flag.Type != "int" || flag.Default != "30" || !flag.Persistent {
+		t.Fatalf("unexpected root flag %+v", flag)
+	}
+}
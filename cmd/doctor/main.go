@@ -12,9 +12,13 @@ import (
 )
 
 const (
-	flagCrossValidate = "cross-validate"
-	flagExpandEnv     = "expand-env"
-	flagOutputJSON    = "json"
+	flagCrossValidate   = "cross-validate"
+	flagExpandEnv       = "expand-env"
+	flagOutputJSON      = "json"
+	flagRemote          = "remote"
+	flagRemoteTokenFile = "remote-token-file"
+	flagDatabase        = "database"
+	flagRepairOrphans   = "repair-orphans"
 )
 
 func main() {
@@ -45,22 +49,42 @@ func newRootCommand() *cobra.Command {
 The doctor command performs comprehensive validation including:
 - Configuration file syntax and structure
 - Server configuration requirements (database, auth token, encryption key)
+- Listen address syntax (server.grpcListenAddr, web.listenAddr)
 - Web interface configuration (when enabled)
 - Tenant configuration requirements (domains, admins)
 - Cross-config validation (when multiple configs are provided)
+- Database integrity (with --database): orphaned attachment rows, attachment sizes
+  that disagree with the stored data, and notification ids shared across tenants;
+  --repair-orphans also deletes the orphaned rows
+
+With --remote, the doctor instead asks a running server for the status of every
+tenant (ListTenantsStatus) and prints it as JSON. The token must have the admin
+scope and is read from --remote-token-file.
+
+The schema subcommand prints the JSON Schema of the notification and webhook
+payloads, the same catalog the server serves at /api/schema.
 
 Examples:
   pinguin-doctor config.yml
   pinguin-doctor config.yml other-config.yml --cross-validate
   pinguin-doctor ./configs/*.yml --json
-  pinguin-doctor config.yml --expand-env`,
-		Args: cobra.MinimumNArgs(1),
+  pinguin-doctor config.yml --expand-env
+  pinguin-doctor config.yml --database --repair-orphans
+  pinguin-doctor --remote localhost:50051 --remote-token-file ./admin.token
+  pinguin-doctor --remote unix:///var/run/pinguin.sock --remote-token-file ./admin.token
+  pinguin-doctor schema`,
+		Args: cobra.ArbitraryArgs,
 		RunE: runDoctor,
 	}
+	command.AddCommand(newSchemaCommand())
 
 	command.Flags().Bool(flagCrossValidate, false, "Validate cross-config consistency (domains, google client IDs)")
 	command.Flags().Bool(flagExpandEnv, false, "Expand environment variables in config files before validation")
 	command.Flags().Bool(flagOutputJSON, false, "Output results as JSON instead of human-readable summary")
+	command.Flags().Bool(flagDatabase, false, "Open each config's database and check attachment integrity")
+	command.Flags().Bool(flagRepairOrphans, false, "With --database, delete attachment rows whose notification no longer exists")
+	command.Flags().String(flagRemote, "", "gRPC address (host:port, tcp4://, tcp6://, or unix://) of a running server; prints every tenant's status as JSON")
+	command.Flags().String(flagRemoteTokenFile, "", "File holding an admin-scoped gRPC token for --remote")
 
 	return command
 }
@@ -78,11 +102,35 @@ func runDoctor(command *cobra.Command, arguments []string) error {
 	if jsonErr != nil {
 		return jsonErr
 	}
+	checkDatabase, databaseErr := command.Flags().GetBool(flagDatabase)
+	if databaseErr != nil {
+		return databaseErr
+	}
+	repairOrphans, repairErr := command.Flags().GetBool(flagRepairOrphans)
+	if repairErr != nil {
+		return repairErr
+	}
+	if repairOrphans && !checkDatabase {
+		return fmt.Errorf("doctor: --%s requires --%s", flagRepairOrphans, flagDatabase)
+	}
+	remoteAddress, remoteErr := command.Flags().GetString(flagRemote)
+	if remoteErr != nil {
+		return remoteErr
+	}
+	if remoteAddress != "" {
+		tokenFile, tokenFileErr := command.Flags().GetString(flagRemoteTokenFile)
+		if tokenFileErr != nil {
+			return tokenFileErr
+		}
+		return runRemoteStatus(command, remoteAddress, tokenFile, arguments)
+	}
 
 	options := doctor.Options{
 		ConfigPaths:          arguments,
 		ValidateCrossConfigs: crossValidate,
 		ExpandEnv:            expandEnv,
+		CheckDatabase:        checkDatabase,
+		RepairOrphans:        repairOrphans,
 	}
 
 	report, runErr := doctor.Run(context.Background(), options)
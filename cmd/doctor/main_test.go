@@ -2,13 +2,20 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"io"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
+	"github.com/tyemirov/pinguin/internal/schema"
+	"github.com/tyemirov/pinguin/pkg/grpcapi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 func TestRootCommandWritesSummaryForValidConfig(t *testing.T) {
@@ -161,6 +168,102 @@ func TestDoctorMainHelpReturns(t *testing.T) {
 	}
 }
 
+type remoteStatusServer struct {
+	grpcapi.UnimplementedNotificationServiceServer
+	authorization []string
+}
+
+func (server *remoteStatusServer) ListTenantsStatus(ctx context.Context, _ *grpcapi.ListTenantsStatusRequest) (*grpcapi.ListTenantsStatusResponse, error) {
+	incoming, _ := metadata.FromIncomingContext(ctx)
+	server.authorization = incoming.Get("authorization")
+	return &grpcapi.ListTenantsStatusResponse{Tenants: []*grpcapi.TenantStatus{{TenantId: "tenant-one", DisplayName: "Tenant One", Status: "active", DomainCount: 2}}}, nil
+}
+
+func TestRunDoctorRemotePrintsTenantStatusJSON(t *testing.T) {
+	listener, listenErr := net.Listen("tcp", "127.0.0.1:0")
+	if listenErr != nil {
+		t.Fatalf("listen: %v", listenErr)
+	}
+	statusServer := &remoteStatusServer{}
+	grpcServer := grpc.NewServer()
+	grpcapi.RegisterNotificationServiceServer(grpcServer, statusServer)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+	tokenPath := filepath.Join(t.TempDir(), "admin.token")
+	if err := os.WriteFile(tokenPath, []byte("admin-token\n"), 0o600); err != nil {
+		t.Fatalf("write token: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	command := newRootCommand()
+	command.SetOut(&stdout)
+	command.SetErr(io.Discard)
+	command.SetArgs([]string{"--remote", listener.Addr().String(), "--remote-token-file", tokenPath})
+	if err := command.Execute(); err != nil {
+		t.Fatalf("execute doctor remote: %v", err)
+	}
+	var decoded struct {
+		Tenants []struct {
+			TenantID    string `json:"tenant_id"`
+			DomainCount int    `json:"domain_count"`
+			QueuedCount string `json:"queued_count"`
+		} `json:"tenants"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &decoded); err != nil {
+		t.Fatalf("decode output %q: %v", stdout.String(), err)
+	}
+	if len(decoded.Tenants) != 1 || decoded.Tenants[0].TenantID != "tenant-one" || decoded.Tenants[0].DomainCount != 2 || decoded.Tenants[0].QueuedCount != "0" {
+		t.Fatalf("unexpected remote status %+v", decoded)
+	}
+	if len(statusServer.authorization) != 1 || statusServer.authorization[0] != "Bearer admin-token" {
+		t.Fatalf("expected the token file contents as bearer token, got %v", statusServer.authorization)
+	}
+}
+
+func TestRunDoctorRemoteRejectsInvalidUsage(t *testing.T) {
+	testCases := []struct {
+		name      string
+		arguments []string
+		expected  string
+	}{
+		{name: "missing token file", arguments: []string{"--remote", "localhost:50051"}, expected: "--remote-token-file is required"},
+		{name: "config paths", arguments: []string{"config.yml", "--remote", "localhost:50051", "--remote-token-file", "token"}, expected: "cannot be combined with config paths"},
+		{name: "unreadable token", arguments: []string{"--remote", "localhost:50051", "--remote-token-file", filepath.Join(t.TempDir(), "missing")}, expected: "read token"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			command := newRootCommand()
+			command.SetOut(io.Discard)
+			command.SetErr(io.Discard)
+			command.SetArgs(testCase.arguments)
+			if err := command.Execute(); err == nil || !strings.Contains(err.Error(), testCase.expected) {
+				t.Fatalf("expected %q error, got %v", testCase.expected, err)
+			}
+		})
+	}
+}
+
+func TestRunDoctorRejectsRepairWithoutDatabaseCheck(t *testing.T) {
+	err := run([]string{writeDoctorConfig(t, validDoctorConfig), "--repair-orphans"}, io.Discard, io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "--repair-orphans requires --database") {
+		t.Fatalf("expected a flag combination error, got %v", err)
+	}
+}
+
+func TestSchemaCommandPrintsCatalog(t *testing.T) {
+	var stdout bytes.Buffer
+	if err := run([]string{"schema"}, &stdout, io.Discard); err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	var catalog schema.Catalog
+	if err := json.Unmarshal(stdout.Bytes(), &catalog); err != nil {
+		t.Fatalf("decode schema output: %v", err)
+	}
+	if catalog.Version != schema.Version || catalog.NotificationResponse == nil || catalog.WebhookEvent == nil {
+		t.Fatalf("unexpected schema output: %s", stdout.String())
+	}
+}
+
 type failingDoctorWriter struct{}
 
 func (writer failingDoctorWriter) Write([]byte) (int, error) {
@@ -191,6 +294,8 @@ web:
 tenants:
   - id: demo
     displayName: Demo Tenant
+    emailProfile:
+      fromAddress: noreply@example.com
     domains:
       - demo.example.com
 `
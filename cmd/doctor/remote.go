@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tyemirov/pinguin/pkg/client"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	remoteConnectionTimeoutSeconds = 5
+	remoteOperationTimeoutSeconds  = 10
+)
+
+// runRemoteStatus prints a running server's ListTenantsStatus view as JSON. The
+// admin token is read from a file so it never appears in the process list.
+func runRemoteStatus(command *cobra.Command, address string, tokenFile string, arguments []string) error {
+	if len(arguments) > 0 {
+		return fmt.Errorf("doctor.remote: --%s cannot be combined with config paths", flagRemote)
+	}
+	if strings.TrimSpace(tokenFile) == "" {
+		return fmt.Errorf("doctor.remote: --%s is required with --%s", flagRemoteTokenFile, flagRemote)
+	}
+	token, readErr := os.ReadFile(tokenFile)
+	if readErr != nil {
+		return fmt.Errorf("doctor.remote: read token: %w", readErr)
+	}
+	settings, settingsErr := client.NewAdminSettings(address, strings.TrimSpace(string(token)), remoteConnectionTimeoutSeconds, remoteOperationTimeoutSeconds)
+	if settingsErr != nil {
+		return fmt.Errorf("doctor.remote: %w", settingsErr)
+	}
+	notificationClient, clientErr := client.NewNotificationClient(slog.New(slog.NewTextHandler(command.ErrOrStderr(), nil)), settings)
+	if clientErr != nil {
+		return fmt.Errorf("doctor.remote: %w", clientErr)
+	}
+	defer notificationClient.Close()
+
+	response, statusErr := notificationClient.ListTenantsStatus()
+	if statusErr != nil {
+		return fmt.Errorf("doctor.remote: list tenants status: %w", statusErr)
+	}
+	output, marshalErr := protojson.MarshalOptions{Multiline: true, UseProtoNames: true, EmitUnpopulated: true}.Marshal(response)
+	if marshalErr != nil {
+		return fmt.Errorf("doctor.remote: encode: %w", marshalErr)
+	}
+	if _, writeErr := command.OutOrStdout().Write(append(output, '\n')); writeErr != nil {
+		return fmt.Errorf("doctor.write_output: %w", writeErr)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tyemirov/pinguin/internal/schema"
+)
+
+// newSchemaCommand prints the payload schema catalog that the server serves at
+// /api/schema, so consumers can pin it without a running server.
+func newSchemaCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema of notification and webhook payloads",
+		Args:  cobra.NoArgs,
+		RunE: func(command *cobra.Command, _ []string) error {
+			catalog, buildErr := schema.Build()
+			if buildErr != nil {
+				return fmt.Errorf("doctor.schema: %w", buildErr)
+			}
+			output, _ := json.MarshalIndent(catalog, "", "  ")
+			if _, writeErr := command.OutOrStdout().Write(append(output, '\n')); writeErr != nil {
+				return fmt.Errorf("doctor.write_output: %w", writeErr)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"log/slog"
+	"strings"
+
+	"github.com/tyemirov/pinguin/internal/config"
+	"github.com/tyemirov/pinguin/internal/tenant"
+	"github.com/tyemirov/pinguin/pkg/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	grpcScopeDeniedMessage  = "token scope does not permit this call"
+	grpcTenantDeniedMessage = "token is not permitted for this tenant"
+)
+
+// grpcGrant records what the presented bearer token may do for the current call.
+type grpcGrant struct {
+	scope     string
+	tenantIDs map[string]struct{}
+}
+
+type grpcGrantContextKey struct{}
+
+type grpcCredential struct {
+	tokenDigest [sha256.Size]byte
+	grant       grpcGrant
+}
+
+// grpcTokenGrants lists every accepted bearer token; the legacy token keeps full access.
+func grpcTokenGrants(configuration config.Config) []config.GRPCTokenConfig {
+	tokens := make([]config.GRPCTokenConfig, 0, len(configuration.GRPCTokens)+1)
+	if strings.TrimSpace(configuration.GRPCAuthToken) != "" {
+		tokens = append(tokens, config.GRPCTokenConfig{Token: configuration.GRPCAuthToken, Scope: config.GRPCTokenScopeWrite})
+	}
+	return append(tokens, configuration.GRPCTokens...)
+}
+
+func newGRPCCredentials(tokens []config.GRPCTokenConfig) []grpcCredential {
+	credentials := make([]grpcCredential, 0, len(tokens))
+	for _, token := range tokens {
+		var tenantIDs map[string]struct{}
+		if len(token.TenantIDs) > 0 {
+			tenantIDs = make(map[string]struct{}, len(token.TenantIDs))
+			for _, tenantID := range token.TenantIDs {
+				tenantIDs[tenantID] = struct{}{}
+			}
+		}
+		credentials = append(credentials, grpcCredential{
+			tokenDigest: sha256.Sum256([]byte(token.Token)),
+			grant:       grpcGrant{scope: token.Scope, tenantIDs: tenantIDs},
+		})
+	}
+	return credentials
+}
+
+// matchGRPCCredential compares the presented token against every credential in constant time.
+func matchGRPCCredential(credentials []grpcCredential, presentedToken string) (grpcGrant, bool) {
+	presentedDigest := sha256.Sum256([]byte(presentedToken))
+	matchedIndex := -1
+	for index := range credentials {
+		if subtle.ConstantTimeCompare(credentials[index].tokenDigest[:], presentedDigest[:]) == 1 {
+			matchedIndex = index
+		}
+	}
+	if matchedIndex < 0 {
+		return grpcGrant{}, false
+	}
+	return credentials[matchedIndex].grant, true
+}
+
+func withGRPCGrant(ctx context.Context, grant grpcGrant) context.Context {
+	return context.WithValue(ctx, grpcGrantContextKey{}, grant)
+}
+
+func buildAuthInterceptor(logger *slog.Logger, tokens []config.GRPCTokenConfig) grpc.UnaryServerInterceptor {
+	credentials := newGRPCCredentials(tokens)
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		grantedCtx, err := authenticateGRPCCall(ctx, logger, credentials)
+		if err != nil {
+			return nil, err
+		}
+		return handler(grantedCtx, req)
+	}
+}
+
+// buildAuthStreamInterceptor applies the bearer-token check of buildAuthInterceptor to streaming RPCs.
+func buildAuthStreamInterceptor(logger *slog.Logger, tokens []config.GRPCTokenConfig) grpc.StreamServerInterceptor {
+	credentials := newGRPCCredentials(tokens)
+	return func(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		grantedCtx, err := authenticateGRPCCall(stream.Context(), logger, credentials)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextServerStream{ServerStream: stream, ctx: grantedCtx})
+	}
+}
+
+// authenticateGRPCCall matches the bearer token in the call metadata and attaches its grant.
+func authenticateGRPCCall(ctx context.Context, logger *slog.Logger, credentials []grpcCredential) (context.Context, error) {
+	metadataValues, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		logging.LogEvent(ctx, logger, logging.EventAuthRejected, "Missing metadata in gRPC request", "transport", "grpc", "reason", "missing_metadata")
+		return nil, status.Error(codes.Unauthenticated, "missing metadata")
+	}
+	authorizationHeaders := metadataValues.Get("authorization")
+	if len(authorizationHeaders) == 0 {
+		logging.LogEvent(ctx, logger, logging.EventAuthRejected, "Missing authorization header", "transport", "grpc", "reason", "missing_authorization")
+		return nil, status.Error(codes.Unauthenticated, "missing authorization header")
+	}
+	headerValue := authorizationHeaders[0]
+	if !strings.HasPrefix(headerValue, "Bearer ") {
+		logging.LogEvent(ctx, logger, logging.EventAuthRejected, "Invalid authorization header format", "transport", "grpc", "reason", "malformed_authorization")
+		return nil, status.Error(codes.Unauthenticated, "invalid authorization header")
+	}
+	grant, matched := matchGRPCCredential(credentials, strings.TrimPrefix(headerValue, "Bearer "))
+	if !matched {
+		logging.LogEvent(ctx, logger, logging.EventAuthRejected, "Invalid token provided", "transport", "grpc", "reason", "invalid_token")
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	return withGRPCGrant(ctx, grant), nil
+}
+
+// authorizeGRPCCall enforces the token scope and tenant restriction attached by buildAuthInterceptor.
+func authorizeGRPCCall(ctx context.Context, requiredScope string) error {
+	grant, ok := ctx.Value(grpcGrantContextKey{}).(grpcGrant)
+	if !ok {
+		return status.Error(codes.PermissionDenied, grpcScopeDeniedMessage)
+	}
+	if grant.scope == config.GRPCTokenScopeAdmin || requiredScope == config.GRPCTokenScopeAdmin {
+		// Admin tokens reach only the cross-tenant operator RPCs, and those accept no other token.
+		if grant.scope != requiredScope {
+			return status.Error(codes.PermissionDenied, grpcScopeDeniedMessage)
+		}
+		return nil
+	}
+	if requiredScope == config.GRPCTokenScopeWrite && grant.scope != config.GRPCTokenScopeWrite {
+		return status.Error(codes.PermissionDenied, grpcScopeDeniedMessage)
+	}
+	if len(grant.tenantIDs) == 0 {
+		return nil
+	}
+	runtimeCfg, hasRuntime := tenant.RuntimeFromContext(ctx)
+	if !hasRuntime {
+		return status.Error(codes.PermissionDenied, grpcTenantDeniedMessage)
+	}
+	if _, allowed := grant.tenantIDs[runtimeCfg.Tenant.ID]; !allowed {
+		return status.Error(codes.PermissionDenied, grpcTenantDeniedMessage)
+	}
+	return nil
+}
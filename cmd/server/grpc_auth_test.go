@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/tyemirov/pinguin/internal/config"
+	"github.com/tyemirov/pinguin/internal/tenant"
+	"github.com/tyemirov/pinguin/pkg/grpcapi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCTokenGrantsKeepsLegacyTokenFullAccess(t *testing.T) {
+	grants := grpcTokenGrants(config.Config{
+		GRPCAuthToken: "legacy",
+		GRPCTokens:    []config.GRPCTokenConfig{{Token: "reader", Scope: config.GRPCTokenScopeRead}},
+	})
+	if len(grants) != 2 {
+		t.Fatalf("expected two grants, got %+v", grants)
+	}
+	if grants[0].Token != "legacy" || grants[0].Scope != config.GRPCTokenScopeWrite || len(grants[0].TenantIDs) != 0 {
+		t.Fatalf("expected legacy token to keep full access, got %+v", grants[0])
+	}
+	if grants[1].Token != "reader" || grants[1].Scope != config.GRPCTokenScopeRead {
+		t.Fatalf("unexpected scoped grant %+v", grants[1])
+	}
+}
+
+func TestScopedTokensEnforcedByHandlers(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	interceptor := buildAuthInterceptor(logger, []config.GRPCTokenConfig{
+		{Token: "writer", Scope: config.GRPCTokenScopeWrite},
+		{Token: "reader", Scope: config.GRPCTokenScopeRead},
+		{Token: "operator", Scope: config.GRPCTokenScopeAdmin},
+	})
+	notificationService := &recordingNotificationService{}
+	server := &notificationServiceServer{notificationService: notificationService, logger: logger}
+	callThroughInterceptor := func(token string, call func(context.Context) error) error {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ interface{}) (interface{}, error) {
+			return nil, call(ctx)
+		})
+		return err
+	}
+	cancelCall := func(ctx context.Context) error {
+		_, err := server.CancelNotification(ctx, &grpcapi.CancelNotificationRequest{NotificationId: "notif-1"})
+		return err
+	}
+	statusCall := func(ctx context.Context) error {
+		_, err := server.GetNotificationStatus(ctx, &grpcapi.GetNotificationStatusRequest{NotificationId: "notif-1"})
+		return err
+	}
+
+	tenantsStatusCall := func(ctx context.Context) error {
+		_, err := server.ListTenantsStatus(ctx, &grpcapi.ListTenantsStatusRequest{})
+		return err
+	}
+
+	testCases := []struct {
+		name         string
+		token        string
+		call         func(context.Context) error
+		expectedCode codes.Code
+	}{
+		{name: "read token may read", token: "reader", call: statusCall, expectedCode: codes.OK},
+		{name: "read token may not mutate", token: "reader", call: cancelCall, expectedCode: codes.PermissionDenied},
+		{name: "write token may mutate", token: "writer", call: cancelCall, expectedCode: codes.OK},
+		{name: "write token may read", token: "writer", call: statusCall, expectedCode: codes.OK},
+		{name: "unknown token", token: "other", call: statusCall, expectedCode: codes.Unauthenticated},
+		{name: "admin token may list tenants status", token: "operator", call: tenantsStatusCall, expectedCode: codes.OK},
+		{name: "admin token may not read tenant data", token: "operator", call: statusCall, expectedCode: codes.PermissionDenied},
+		{name: "write token may not list tenants status", token: "writer", call: tenantsStatusCall, expectedCode: codes.PermissionDenied},
+		{name: "read token may not list tenants status", token: "reader", call: tenantsStatusCall, expectedCode: codes.PermissionDenied},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			notificationService.cancelID = ""
+			err := callThroughInterceptor(testCase.token, testCase.call)
+			if status.Code(err) != testCase.expectedCode {
+				t.Fatalf("expected %v, got %v", testCase.expectedCode, err)
+			}
+		})
+	}
+	if err := callThroughInterceptor("reader", cancelCall); status.Code(err) != codes.PermissionDenied || notificationService.cancelID != "" {
+		t.Fatalf("expected denied mutation to skip the service, got err=%v cancelID=%q", err, notificationService.cancelID)
+	}
+}
+
+func TestAuthorizeGRPCCallEnforcesTenantRestriction(t *testing.T) {
+	restrictedGrant := grpcGrant{scope: config.GRPCTokenScopeRead, tenantIDs: map[string]struct{}{"tenant-a": {}}}
+	testCases := []struct {
+		name         string
+		ctx          context.Context
+		expectedCode codes.Code
+	}{
+		{
+			name:         "allowed tenant",
+			ctx:          tenant.WithRuntime(withGRPCGrant(context.Background(), restrictedGrant), tenant.RuntimeConfig{Tenant: tenant.Tenant{ID: "tenant-a"}}),
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "other tenant",
+			ctx:          tenant.WithRuntime(withGRPCGrant(context.Background(), restrictedGrant), tenant.RuntimeConfig{Tenant: tenant.Tenant{ID: "tenant-b"}}),
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "missing tenant runtime",
+			ctx:          withGRPCGrant(context.Background(), restrictedGrant),
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "missing grant",
+			ctx:          context.Background(),
+			expectedCode: codes.PermissionDenied,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if err := authorizeGRPCCall(testCase.ctx, config.GRPCTokenScopeRead); status.Code(err) != testCase.expectedCode {
+				t.Fatalf("expected %v, got %v", testCase.expectedCode, err)
+			}
+		})
+	}
+}
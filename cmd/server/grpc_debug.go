@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/tyemirov/pinguin/pkg/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// buildDebugInterceptor honors the x-debug metadata of an authenticated call: the call's
+// context logs at DEBUG whatever the configured level, and the call's outcome and
+// duration are logged when it finishes. It runs after the auth interceptor, so an
+// unauthenticated caller cannot raise the verbosity.
+func buildDebugInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !grpcDebugRequested(ctx) {
+			return handler(ctx, req)
+		}
+		debugCtx := logging.WithDebug(ctx)
+		startedAt := time.Now()
+		response, err := handler(debugCtx, req)
+		logger.DebugContext(debugCtx, "grpc_request_debug", "method", info.FullMethod, "code", status.Code(err).String(), "duration_ms", time.Since(startedAt).Milliseconds())
+		return response, err
+	}
+}
+
+// buildDebugStreamInterceptor is buildDebugInterceptor for streaming RPCs.
+func buildDebugStreamInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !grpcDebugRequested(stream.Context()) {
+			return handler(srv, stream)
+		}
+		debugCtx := logging.WithDebug(stream.Context())
+		startedAt := time.Now()
+		err := handler(srv, &contextServerStream{ServerStream: stream, ctx: debugCtx})
+		logger.DebugContext(debugCtx, "grpc_request_debug", "method", info.FullMethod, "code", status.Code(err).String(), "duration_ms", time.Since(startedAt).Milliseconds())
+		return err
+	}
+}
+
+func grpcDebugRequested(ctx context.Context) bool {
+	metadataValues, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	values := metadataValues.Get(logging.DebugHeader)
+	return len(values) > 0 && logging.DebugHeaderEnabled(values[0])
+}
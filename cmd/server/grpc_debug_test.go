@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/tyemirov/pinguin/pkg/grpcapi"
+	"github.com/tyemirov/pinguin/pkg/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestDebugInterceptorElevatesOnlyTheFlaggedCall(t *testing.T) {
+	var logBuffer bytes.Buffer
+	logger := slog.New(logging.NewDebugOverrideHandler(slog.NewTextHandler(&logBuffer, &slog.HandlerOptions{Level: slog.LevelDebug}), slog.LevelInfo))
+	interceptor := buildDebugInterceptor(logger)
+	info := &grpc.UnaryServerInfo{FullMethod: grpcapi.NotificationService_SendNotification_FullMethodName}
+
+	call := func(md metadata.MD) bool {
+		ctx := context.Background()
+		if md != nil {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		elevated := false
+		if _, err := interceptor(ctx, nil, info, func(handlerCtx context.Context, _ interface{}) (interface{}, error) {
+			elevated = logging.DebugRequested(handlerCtx)
+			logger.DebugContext(handlerCtx, "handler_debug", "recipient", "user@example.com")
+			return nil, nil
+		}); err != nil {
+			t.Fatalf("interceptor: %v", err)
+		}
+		return elevated
+	}
+
+	if !call(metadata.Pairs("x-debug", "1")) {
+		t.Fatalf("expected the flagged call to be elevated")
+	}
+	if call(nil) || call(metadata.Pairs("x-debug", "true")) || call(metadata.Pairs("authorization", "Bearer token")) {
+		t.Fatalf("expected calls without x-debug: 1 to keep the configured level")
+	}
+	output := logBuffer.String()
+	if strings.Count(output, "msg=handler_debug") != 1 || strings.Count(output, "msg=grpc_request_debug") != 1 {
+		t.Fatalf("expected debug lines for the flagged call only, got %q", output)
+	}
+	if !strings.Contains(output, "method="+grpcapi.NotificationService_SendNotification_FullMethodName) || !strings.Contains(output, "code=OK") {
+		t.Fatalf("expected the call's method and code, got %q", output)
+	}
+	if strings.Contains(output, "user@example.com") {
+		t.Fatalf("expected the recipient to be redacted, got %q", output)
+	}
+}
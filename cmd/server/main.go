@@ -2,36 +2,48 @@ package main
 
 import (
 	"context"
-	"crypto/sha256"
 	"crypto/tls"
-	"encoding/hex"
 	"errors"
 	"flag"
 	"fmt"
 	"net"
 	"net/http"
+	"net/netip"
 	"os"
+	"os/signal"
+	"runtime/debug"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/tyemirov/pinguin/internal/certreload"
 	"github.com/tyemirov/pinguin/internal/config"
 	"github.com/tyemirov/pinguin/internal/db"
 	"github.com/tyemirov/pinguin/internal/httpapi"
 	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/opentracking"
+	"github.com/tyemirov/pinguin/internal/queueintake"
+	"github.com/tyemirov/pinguin/internal/savedfilter"
+	"github.com/tyemirov/pinguin/internal/seed"
 	"github.com/tyemirov/pinguin/internal/service"
 	"github.com/tyemirov/pinguin/internal/smtpforwarding"
 	"github.com/tyemirov/pinguin/internal/smtpidentity"
 	"github.com/tyemirov/pinguin/internal/smtpsubmission"
 	"github.com/tyemirov/pinguin/internal/tenant"
+	"github.com/tyemirov/pinguin/pkg/endpoint"
 	"github.com/tyemirov/pinguin/pkg/grpcapi"
 	"github.com/tyemirov/pinguin/pkg/grpcutil"
 	"github.com/tyemirov/pinguin/pkg/logging"
 	sessionvalidator "github.com/tyemirov/tauth/pkg/sessionvalidator"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"gorm.io/gorm"
 	"log/slog"
@@ -41,20 +53,75 @@ import (
 type notificationServiceServer struct {
 	grpcapi.UnimplementedNotificationServiceServer
 	notificationService service.NotificationService
+	queueIntake         queueIntakeStatsReporter
 	logger              *slog.Logger
 }
 
 const (
 	tenantMetadataKey                = "x-tenant-id"
+	forwardedHostMetadataKey         = "x-forwarded-host"
+	authorityMetadataKey             = ":authority"
 	tenantIDRequiredMessage          = "tenant_id is required"
 	tenantNotFoundMessage            = "tenant not found"
+	tenantLookupFailedMessage        = "tenant lookup failed; retry later"
+	tenantCallerNotAllowedMessage    = "caller address is not allowed for this tenant"
 	tenantRepositoryUnavailableError = "tenant repository unavailable"
 	notificationIDRequiredMessage    = "notification_id is required"
+	groupIDRequiredMessage           = "group_id is required"
 	scheduledTimeRequiredMessage     = "scheduled_time is required"
 	scheduledTimeFutureMessage       = "scheduled_time must be in the future"
+	costSummaryRangeRequiredMessage  = "start_time and end_time are required"
+	// drainingRetryDelay is the RetryInfo hint attached to sends refused while draining.
+	drainingRetryDelay = 5 * time.Second
+	// databaseBusyRetryDelay is the RetryInfo hint attached when SQLite stayed locked.
+	databaseBusyRetryDelay = time.Second
+	// certificateReloadInterval is how often TLS certificate files are checked for rotation.
+	certificateReloadInterval = 30 * time.Second
+	// smtpSubmissionCertificateName labels the SMTP submission certificate in /healthz.
+	smtpSubmissionCertificateName = "smtp_submission"
 )
 
 func (server *notificationServiceServer) SendNotification(ctx context.Context, req *grpcapi.NotificationRequest) (*grpcapi.NotificationResponse, error) {
+	if err := authorizeGRPCCall(ctx, config.GRPCTokenScopeWrite); err != nil {
+		return nil, err
+	}
+	modelRequest, err := server.notificationRequestFromGRPC(req)
+	if err != nil {
+		return nil, err
+	}
+
+	modelResponse, err := server.notificationService.SendNotification(ctx, modelRequest)
+	if err != nil {
+		server.logger.Error("Service SendNotification error", "error", err)
+		return nil, serviceStatusError(err)
+	}
+
+	return mapModelToGrpcResponse(modelResponse), nil
+}
+
+// EstimateNotificationSize reports the size of the message a send request would hand
+// to its provider, applying the same validation as SendNotification without sending.
+func (server *notificationServiceServer) EstimateNotificationSize(ctx context.Context, req *grpcapi.NotificationRequest) (*grpcapi.NotificationSizeEstimate, error) {
+	if err := authorizeGRPCCall(ctx, config.GRPCTokenScopeRead); err != nil {
+		return nil, err
+	}
+	modelRequest, err := server.notificationRequestFromGRPC(req)
+	if err != nil {
+		return nil, err
+	}
+	estimate, err := server.notificationService.EstimateNotificationSize(ctx, modelRequest)
+	if err != nil {
+		server.logger.Error("Service EstimateNotificationSize error", "error", err)
+		return nil, serviceStatusError(err)
+	}
+	return &grpcapi.NotificationSizeEstimate{
+		MessageSizeBytes: int64(estimate.MessageSizeBytes),
+		AttachmentBytes:  int64(estimate.AttachmentBytes),
+	}, nil
+}
+
+// notificationRequestFromGRPC validates a gRPC send request and converts it to the model request.
+func (server *notificationServiceServer) notificationRequestFromGRPC(req *grpcapi.NotificationRequest) (model.NotificationRequest, error) {
 	var internalType model.NotificationType
 	switch req.NotificationType {
 	case grpcapi.NotificationType_EMAIL:
@@ -63,14 +130,14 @@ func (server *notificationServiceServer) SendNotification(ctx context.Context, r
 		internalType = model.NotificationSMS
 	default:
 		server.logger.Error("Unsupported notification type", "type", req.NotificationType)
-		return nil, fmt.Errorf("unsupported notification type: %v", req.NotificationType)
+		return model.NotificationRequest{}, fmt.Errorf("unsupported notification type: %v", req.NotificationType)
 	}
 
 	var scheduledFor *time.Time
 	if req.ScheduledTime != nil {
 		if err := req.ScheduledTime.CheckValid(); err != nil {
 			server.logger.Error("Invalid scheduled timestamp", "error", err)
-			return nil, status.Errorf(codes.InvalidArgument, "invalid scheduled_time: %v", err)
+			return model.NotificationRequest{}, status.Errorf(codes.InvalidArgument, "invalid scheduled_time: %v", err)
 		}
 		normalizedScheduled := req.ScheduledTime.AsTime().UTC()
 		scheduledFor = &normalizedScheduled
@@ -87,76 +154,239 @@ func (server *notificationServiceServer) SendNotification(ctx context.Context, r
 	)
 	if requestError != nil {
 		server.logger.Error("Invalid notification request", "error", requestError)
-		return nil, status.Error(codes.InvalidArgument, requestError.Error())
+		return model.NotificationRequest{}, status.Error(codes.InvalidArgument, requestError.Error())
 	}
+	if calendarEvent := req.GetCalendarEvent(); calendarEvent != nil {
+		modelRequest, requestError = modelRequest.WithCalendarEvent(calendarEvent.GetIcs(), calendarEvent.GetMethod())
+		if requestError != nil {
+			server.logger.Error("Invalid calendar event", "error", requestError)
+			return model.NotificationRequest{}, status.Error(codes.InvalidArgument, requestError.Error())
+		}
+	}
+	modelRequest, requestError = modelRequest.WithSMSOverflowPolicy(req.GetSmsOverflowPolicy())
+	if requestError != nil {
+		server.logger.Error("Invalid SMS overflow policy", "error", requestError)
+		return model.NotificationRequest{}, status.Error(codes.InvalidArgument, requestError.Error())
+	}
+	if req.GetContentEncrypted() {
+		modelRequest, requestError = modelRequest.WithEncryptedContent()
+		if requestError != nil {
+			server.logger.Error("Invalid encrypted content", "error", requestError)
+			return model.NotificationRequest{}, status.Error(codes.InvalidArgument, requestError.Error())
+		}
+	}
+	modelRequest, requestError = modelRequest.WithSenderOverrides(req.GetReplyTo(), req.GetFromDisplayName())
+	if requestError != nil {
+		server.logger.Error("Invalid sender overrides", "error", requestError)
+		return model.NotificationRequest{}, status.Error(codes.InvalidArgument, requestError.Error())
+	}
+	if req.FailOnImmediateError != nil {
+		modelRequest = modelRequest.WithFailOnImmediateError(req.GetFailOnImmediateError())
+	}
+	if req.ExpiresAt != nil {
+		if err := req.ExpiresAt.CheckValid(); err != nil {
+			server.logger.Error("Invalid expiry timestamp", "error", err)
+			return model.NotificationRequest{}, status.Errorf(codes.InvalidArgument, "invalid expires_at: %v", err)
+		}
+		modelRequest, requestError = modelRequest.WithExpiresAt(req.ExpiresAt.AsTime())
+		if requestError != nil {
+			server.logger.Error("Invalid notification expiry", "error", requestError)
+			return model.NotificationRequest{}, status.Error(codes.InvalidArgument, requestError.Error())
+		}
+	}
+	return modelRequest, nil
+}
 
-	recipientDigest := digestForLogging(modelRequest.Recipient())
-	subjectDigest := digestForLogging(modelRequest.Subject())
-	server.logger.Info(
-		"notification_request_received",
-		"notification_type", req.NotificationType.String(),
-		"subject_digest", subjectDigest,
-		"recipient_digest", recipientDigest,
-		"scheduled", scheduledFor != nil,
-		"attachment_count", len(attachments),
-	)
-
-	modelResponse, err := server.notificationService.SendNotification(ctx, modelRequest)
-	if err != nil {
-		server.logger.Error("Service SendNotification error", "error", err)
+// SendNotificationGroup sends one notification per channel under a shared group id.
+// Empty channel subjects and messages fall back to the group's; every channel must be
+// valid before any is stored.
+func (server *notificationServiceServer) SendNotificationGroup(ctx context.Context, req *grpcapi.NotificationGroupRequest) (*grpcapi.NotificationGroupResponse, error) {
+	if err := authorizeGRPCCall(ctx, config.GRPCTokenScopeWrite); err != nil {
 		return nil, err
 	}
+	var scheduledFor *time.Time
+	if req.ScheduledTime != nil {
+		if err := req.ScheduledTime.CheckValid(); err != nil {
+			server.logger.Error("Invalid scheduled timestamp", "error", err)
+			return nil, status.Errorf(codes.InvalidArgument, "invalid scheduled_time: %v", err)
+		}
+		normalizedScheduled := req.ScheduledTime.AsTime().UTC()
+		scheduledFor = &normalizedScheduled
+	}
+	if req.ExpiresAt != nil {
+		if err := req.ExpiresAt.CheckValid(); err != nil {
+			server.logger.Error("Invalid expiry timestamp", "error", err)
+			return nil, status.Errorf(codes.InvalidArgument, "invalid expires_at: %v", err)
+		}
+	}
+	channels := make([]model.NotificationRequest, 0, len(req.GetChannels()))
+	for channelIndex, channel := range req.GetChannels() {
+		channelRequest, requestError := newGroupChannelRequest(req, channel, scheduledFor)
+		if requestError != nil {
+			server.logger.Error("Invalid notification group channel", "channel", channelIndex+1, "error", requestError)
+			return nil, status.Errorf(codes.InvalidArgument, "channel %d: %v", channelIndex+1, requestError)
+		}
+		channels = append(channels, channelRequest)
+	}
+	groupRequest, requestError := model.NewNotificationGroupRequest(channels)
+	if requestError != nil {
+		server.logger.Error("Invalid notification group request", "error", requestError)
+		return nil, status.Error(codes.InvalidArgument, requestError.Error())
+	}
 
+	modelResponse, err := server.notificationService.SendNotificationGroup(ctx, groupRequest)
+	if err != nil {
+		server.logger.Error("Service SendNotificationGroup error", "error", err)
+		return nil, serviceStatusError(err)
+	}
 	server.logger.Info(
-		"notification_request_completed",
-		"notification_id", modelResponse.NotificationID,
+		"notification_group_request_completed",
+		"group_id", modelResponse.GroupID,
 		"status", modelResponse.Status,
-		"recipient_digest", recipientDigest,
+		"channels", len(modelResponse.Notifications),
 	)
+	return mapModelGroupToGrpcResponse(modelResponse), nil
+}
 
-	return mapModelToGrpcResponse(modelResponse), nil
+// newGroupChannelRequest validates one channel of a group send, filling in the group's
+// subject, message, schedule and expiry.
+func newGroupChannelRequest(req *grpcapi.NotificationGroupRequest, channel *grpcapi.NotificationChannel, scheduledFor *time.Time) (model.NotificationRequest, error) {
+	var internalType model.NotificationType
+	switch channel.GetNotificationType() {
+	case grpcapi.NotificationType_EMAIL:
+		internalType = model.NotificationEmail
+	case grpcapi.NotificationType_SMS:
+		internalType = model.NotificationSMS
+	default:
+		return model.NotificationRequest{}, model.ErrNotificationTypeUnsupported
+	}
+	subject := channel.GetSubject()
+	if strings.TrimSpace(subject) == "" && internalType == model.NotificationEmail {
+		subject = req.GetSubject()
+	}
+	message := channel.GetMessage()
+	if strings.TrimSpace(message) == "" {
+		message = req.GetMessage()
+	}
+	channelRequest, err := model.NewNotificationRequest(internalType, channel.GetRecipient(), subject, message, scheduledFor, mapGrpcAttachments(channel.GetAttachments()))
+	if err != nil {
+		return model.NotificationRequest{}, err
+	}
+	if req.ExpiresAt != nil {
+		return channelRequest.WithExpiresAt(req.ExpiresAt.AsTime())
+	}
+	return channelRequest, nil
+}
+
+// GetNotificationGroup returns the combined state of a multi-channel send.
+func (server *notificationServiceServer) GetNotificationGroup(ctx context.Context, req *grpcapi.GetNotificationGroupRequest) (*grpcapi.NotificationGroupResponse, error) {
+	if err := authorizeGRPCCall(ctx, config.GRPCTokenScopeRead); err != nil {
+		return nil, err
+	}
+	groupID := strings.TrimSpace(req.GetGroupId())
+	if groupID == "" {
+		server.logger.Error("Missing group ID")
+		return nil, status.Error(codes.InvalidArgument, groupIDRequiredMessage)
+	}
+	modelResponse, err := server.notificationService.GetNotificationGroup(ctx, groupID)
+	if err != nil {
+		server.logger.Error("Service GetNotificationGroup error", "error", err)
+		return nil, serviceStatusError(err)
+	}
+	return mapModelGroupToGrpcResponse(modelResponse), nil
 }
 
 func (server *notificationServiceServer) GetNotificationStatus(ctx context.Context, req *grpcapi.GetNotificationStatusRequest) (*grpcapi.NotificationResponse, error) {
+	if err := authorizeGRPCCall(ctx, config.GRPCTokenScopeRead); err != nil {
+		return nil, err
+	}
 	notificationID := strings.TrimSpace(req.GetNotificationId())
 	if notificationID == "" {
 		server.logger.Error("Missing notification ID")
-		return nil, status.Error(codes.InvalidArgument, notificationIDRequiredMessage)
+		return nil, serviceStatusError(service.ErrMissingNotificationID)
+	}
+	fields, err := newGRPCNotificationFields(req.GetFields())
+	if err != nil {
+		return nil, serviceStatusError(err)
 	}
 
-	modelResponse, err := server.notificationService.GetNotificationStatus(ctx, notificationID)
+	var modelResponse model.NotificationResponse
+	if req.GetIncludeRendered() {
+		modelResponse, err = server.notificationService.GetRenderedNotification(ctx, notificationID)
+	} else {
+		modelResponse, err = server.notificationService.GetNotificationStatus(ctx, notificationID)
+	}
 	if err != nil {
 		server.logger.Error("Service GetNotificationStatus error", "error", err)
-		return nil, err
+		return nil, serviceStatusError(err)
 	}
-	return mapModelToGrpcResponse(modelResponse), nil
+	return fields.project(mapModelToGrpcResponse(modelResponse)), nil
 }
 
 func (server *notificationServiceServer) ListNotifications(ctx context.Context, req *grpcapi.ListNotificationsRequest) (*grpcapi.ListNotificationsResponse, error) {
+	if err := authorizeGRPCCall(ctx, config.GRPCTokenScopeRead); err != nil {
+		return nil, err
+	}
 	filters := model.NotificationListFilters{}
 	if req != nil {
 		filters.Statuses = mapGrpcStatuses(req.GetStatuses())
 	}
+	fields, err := newGRPCNotificationFields(req.GetFields())
+	if err != nil {
+		return nil, serviceStatusError(err)
+	}
 
 	responses, err := server.notificationService.ListNotifications(ctx, filters)
 	if err != nil {
 		server.logger.Error("Service ListNotifications error", "error", err)
-		return nil, err
+		return nil, serviceStatusError(err)
 	}
 
 	grpcNotifications := make([]*grpcapi.NotificationResponse, 0, len(responses))
 	for _, response := range responses {
-		grpcNotifications = append(grpcNotifications, mapModelToGrpcResponse(response))
+		grpcNotifications = append(grpcNotifications, fields.project(mapModelToGrpcResponse(response)))
 	}
 
 	return &grpcapi.ListNotificationsResponse{Notifications: grpcNotifications}, nil
 }
 
+// ListNotificationsStream sends the tenant's notifications oldest first, without
+// attachments. stream.Send blocks while the client is not reading, so the server holds at
+// most one batch however large the result is.
+func (server *notificationServiceServer) ListNotificationsStream(req *grpcapi.ListNotificationsRequest, stream grpcapi.NotificationService_ListNotificationsStreamServer) error {
+	ctx := stream.Context()
+	if err := authorizeGRPCCall(ctx, config.GRPCTokenScopeRead); err != nil {
+		return err
+	}
+	filters := model.NotificationListFilters{}
+	if req != nil {
+		filters.Statuses = mapGrpcStatuses(req.GetStatuses())
+	}
+	fields, err := newGRPCNotificationFields(req.GetFields())
+	if err != nil {
+		return serviceStatusError(err)
+	}
+	err = server.notificationService.StreamNotifications(ctx, filters, func(response model.NotificationResponse) error {
+		return stream.Send(fields.project(mapModelToGrpcResponse(response)))
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		server.logger.Error("Service ListNotificationsStream error", "error", err)
+		return serviceStatusError(err)
+	}
+	return nil
+}
+
 func (server *notificationServiceServer) RescheduleNotification(ctx context.Context, req *grpcapi.RescheduleNotificationRequest) (*grpcapi.NotificationResponse, error) {
+	if err := authorizeGRPCCall(ctx, config.GRPCTokenScopeWrite); err != nil {
+		return nil, err
+	}
 	notificationID := strings.TrimSpace(req.GetNotificationId())
 	if notificationID == "" {
 		server.logger.Error("Missing notification ID for reschedule")
-		return nil, status.Error(codes.InvalidArgument, notificationIDRequiredMessage)
+		return nil, serviceStatusError(service.ErrMissingNotificationID)
 	}
 	if req.ScheduledTime == nil {
 		server.logger.Error("Missing scheduled time for reschedule")
@@ -168,33 +398,371 @@ func (server *notificationServiceServer) RescheduleNotification(ctx context.Cont
 	}
 
 	scheduledFor := req.ScheduledTime.AsTime().UTC()
-	if scheduledFor.Before(time.Now().UTC()) {
-		server.logger.Error("Scheduled time is in the past", "notification_id", notificationID, "scheduled_for", scheduledFor)
-		return nil, status.Error(codes.InvalidArgument, scheduledTimeFutureMessage)
-	}
 	modelResponse, err := server.notificationService.RescheduleNotification(ctx, notificationID, scheduledFor)
 	if err != nil {
 		server.logger.Error("Service RescheduleNotification error", "error", err)
-		return nil, err
+		return nil, serviceStatusError(err)
 	}
 	return mapModelToGrpcResponse(modelResponse), nil
 }
 
 func (server *notificationServiceServer) CancelNotification(ctx context.Context, req *grpcapi.CancelNotificationRequest) (*grpcapi.NotificationResponse, error) {
+	if err := authorizeGRPCCall(ctx, config.GRPCTokenScopeWrite); err != nil {
+		return nil, err
+	}
 	notificationID := strings.TrimSpace(req.GetNotificationId())
 	if notificationID == "" {
 		server.logger.Error("Missing notification ID for cancel")
-		return nil, status.Error(codes.InvalidArgument, notificationIDRequiredMessage)
+		return nil, serviceStatusError(service.ErrMissingNotificationID)
 	}
 
-	modelResponse, err := server.notificationService.CancelNotification(ctx, notificationID)
+	modelResponse, err := server.notificationService.CancelNotification(ctx, notificationID, req.GetReason(), model.CancelActorGRPC)
 	if err != nil {
 		server.logger.Error("Service CancelNotification error", "error", err)
-		return nil, err
+		return nil, serviceStatusError(err)
 	}
 	return mapModelToGrpcResponse(modelResponse), nil
 }
 
+func (server *notificationServiceServer) GetCostSummary(ctx context.Context, req *grpcapi.CostSummaryRequest) (*grpcapi.CostSummaryResponse, error) {
+	if err := authorizeGRPCCall(ctx, config.GRPCTokenScopeRead); err != nil {
+		return nil, err
+	}
+	if req.GetStartTime() == nil || req.GetEndTime() == nil {
+		return nil, status.Error(codes.InvalidArgument, costSummaryRangeRequiredMessage)
+	}
+	if err := req.GetStartTime().CheckValid(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid start_time: %v", err)
+	}
+	if err := req.GetEndTime().CheckValid(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid end_time: %v", err)
+	}
+	summaryRange, rangeErr := model.NewCostSummaryRange(req.GetStartTime().AsTime(), req.GetEndTime().AsTime())
+	if rangeErr != nil {
+		return nil, status.Error(codes.InvalidArgument, rangeErr.Error())
+	}
+	summary, err := server.notificationService.GetCostSummary(ctx, summaryRange)
+	if err != nil {
+		server.logger.Error("Service GetCostSummary error", "error", err)
+		return nil, serviceStatusError(err)
+	}
+	return mapCostSummaryToGrpcResponse(summary), nil
+}
+
+func (server *notificationServiceServer) GetCapabilities(ctx context.Context, _ *grpcapi.GetCapabilitiesRequest) (*grpcapi.CapabilitiesResponse, error) {
+	if err := authorizeGRPCCall(ctx, config.GRPCTokenScopeRead); err != nil {
+		return nil, err
+	}
+	capabilities, err := server.notificationService.GetCapabilities(ctx)
+	if err != nil {
+		server.logger.Error("Service GetCapabilities error", "error", err)
+		return nil, serviceStatusError(err)
+	}
+	notificationTypes := make([]grpcapi.NotificationType, 0, len(capabilities.NotificationTypes))
+	for _, notificationType := range capabilities.NotificationTypes {
+		if notificationType == model.NotificationSMS {
+			notificationTypes = append(notificationTypes, grpcapi.NotificationType_SMS)
+			continue
+		}
+		notificationTypes = append(notificationTypes, grpcapi.NotificationType_EMAIL)
+	}
+	return &grpcapi.CapabilitiesResponse{
+		NotificationTypes:         notificationTypes,
+		MaxAttachmentCount:        int32(capabilities.MaxAttachmentCount),
+		MaxAttachmentSizeBytes:    int64(capabilities.MaxAttachmentSizeBytes),
+		MaxAttachmentsTotalBytes:  int64(capabilities.MaxAttachmentsTotalBytes),
+		AttachmentDataPersisted:   capabilities.AttachmentDataPersisted,
+		MaxScheduleHorizonSeconds: capabilities.MaxScheduleHorizonSeconds,
+	}, nil
+}
+
+// TestTenantDelivery checks the tenant's provider credentials without sending a message.
+// It needs a write-scoped token because it exercises the tenant's secrets.
+func (server *notificationServiceServer) TestTenantDelivery(ctx context.Context, req *grpcapi.TestTenantDeliveryRequest) (*grpcapi.TestTenantDeliveryResponse, error) {
+	if err := authorizeGRPCCall(ctx, config.GRPCTokenScopeWrite); err != nil {
+		return nil, err
+	}
+	var internalType model.NotificationType
+	switch req.GetNotificationType() {
+	case grpcapi.NotificationType_EMAIL:
+		internalType = model.NotificationEmail
+	case grpcapi.NotificationType_SMS:
+		internalType = model.NotificationSMS
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported notification type: %v", req.GetNotificationType())
+	}
+	check, err := server.notificationService.TestTenantDelivery(ctx, internalType)
+	if err != nil {
+		server.logger.Error("Service TestTenantDelivery error", "error", err)
+		return nil, serviceStatusError(err)
+	}
+	return &grpcapi.TestTenantDeliveryResponse{
+		NotificationType: req.GetNotificationType(),
+		Success:          check.Success,
+		Error:            check.Error,
+	}, nil
+}
+
+// ListTenantsStatus returns the cross-tenant operator view; only admin-scoped tokens may call it.
+func (server *notificationServiceServer) ListTenantsStatus(ctx context.Context, _ *grpcapi.ListTenantsStatusRequest) (*grpcapi.ListTenantsStatusResponse, error) {
+	if err := authorizeGRPCCall(ctx, config.GRPCTokenScopeAdmin); err != nil {
+		return nil, err
+	}
+	statuses, err := server.notificationService.ListTenantsStatus(ctx)
+	if err != nil {
+		server.logger.Error("Service ListTenantsStatus error", "error", err)
+		return nil, serviceStatusError(err)
+	}
+	tenants := make([]*grpcapi.TenantStatus, 0, len(statuses))
+	for _, tenantStatus := range statuses {
+		var lastDispatchedAt *timestamppb.Timestamp
+		if tenantStatus.LastDispatchedAt != nil {
+			lastDispatchedAt = timestamppb.New(tenantStatus.LastDispatchedAt.UTC())
+		}
+		tenants = append(tenants, &grpcapi.TenantStatus{
+			TenantId:                tenantStatus.TenantID,
+			DisplayName:             tenantStatus.DisplayName,
+			Status:                  tenantStatus.Status,
+			DomainCount:             int32(tenantStatus.DomainCount),
+			EmailProfileConfigured:  tenantStatus.EmailProfileConfigured,
+			SmsProfileConfigured:    tenantStatus.SMSProfileConfigured,
+			EmailSenderCached:       tenantStatus.EmailSenderCached,
+			SmsSenderCached:         tenantStatus.SMSSenderCached,
+			QueuedCount:             tenantStatus.QueuedCount,
+			ErroredCount:            tenantStatus.ErroredCount,
+			LastDispatchedAt:        lastDispatchedAt,
+			OldestPendingAgeSeconds: tenantStatus.OldestPendingAgeSec,
+			PendingAgeExceeded:      tenantStatus.PendingAgeExceeded,
+			OpenedCount:             tenantStatus.OpenedCount,
+			ProviderLatencies:       mapProviderLatencies(tenantStatus.ProviderLatencies),
+			AttachmentIntegrity:     mapTenantIntegrity(tenantStatus.Integrity),
+			AttachmentSizes:         mapAttachmentSizes(tenantStatus.AttachmentSizes),
+			ProviderBreakers:        mapProviderBreakers(tenantStatus.ProviderBreakers),
+		})
+	}
+	return &grpcapi.ListTenantsStatusResponse{Tenants: tenants, QueueIntake: mapQueueIntakeStats(server.queueIntake)}, nil
+}
+
+// drainingStatusError reports Unavailable with a RetryInfo hint so clients and load
+// balancers fail over to another instance.
+func drainingStatusError(err error) error {
+	return unavailableStatusError(err, drainingRetryDelay)
+}
+
+// unavailableStatusError reports Unavailable with a RetryInfo hint of retryDelay.
+func unavailableStatusError(err error, retryDelay time.Duration) error {
+	unavailable := status.New(codes.Unavailable, err.Error())
+	withRetryInfo, detailsErr := unavailable.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryDelay)})
+	if detailsErr != nil {
+		return unavailable.Err()
+	}
+	return withRetryInfo.Err()
+}
+
+// DrainInstance stops this instance accepting sends; it exits once its queues are empty
+// or server.drainTimeoutSec passes. Only admin-scoped tokens may call it.
+func (server *notificationServiceServer) DrainInstance(ctx context.Context, _ *grpcapi.DrainInstanceRequest) (*grpcapi.DrainStatus, error) {
+	if err := authorizeGRPCCall(ctx, config.GRPCTokenScopeAdmin); err != nil {
+		return nil, err
+	}
+	drainStatus, err := server.notificationService.Drain(ctx)
+	if err != nil {
+		server.logger.Error("Service DrainInstance error", "error", err)
+		return nil, serviceStatusError(err)
+	}
+	return mapDrainStatus(drainStatus), nil
+}
+
+// GetDrainStatus reports how many due notifications a draining instance still owes.
+func (server *notificationServiceServer) GetDrainStatus(ctx context.Context, _ *grpcapi.GetDrainStatusRequest) (*grpcapi.DrainStatus, error) {
+	if err := authorizeGRPCCall(ctx, config.GRPCTokenScopeAdmin); err != nil {
+		return nil, err
+	}
+	drainStatus, err := server.notificationService.GetDrainStatus(ctx)
+	if err != nil {
+		server.logger.Error("Service GetDrainStatus error", "error", err)
+		return nil, serviceStatusError(err)
+	}
+	return mapDrainStatus(drainStatus), nil
+}
+
+// TransferNotifications moves a tenant's notifications to another tenant for an org
+// migration. Only admin-scoped tokens may call it.
+func (server *notificationServiceServer) TransferNotifications(ctx context.Context, req *grpcapi.TransferNotificationsRequest) (*grpcapi.TransferNotificationsResponse, error) {
+	if err := authorizeGRPCCall(ctx, config.GRPCTokenScopeAdmin); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.GetSourceTenantId()) == "" || strings.TrimSpace(req.GetDestinationTenantId()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "source_tenant_id and destination_tenant_id are required")
+	}
+	result, err := server.notificationService.TransferNotifications(ctx, service.NotificationTransferRequest{
+		SourceTenantID:      strings.TrimSpace(req.GetSourceTenantId()),
+		DestinationTenantID: strings.TrimSpace(req.GetDestinationTenantId()),
+		Filters:             model.NotificationListFilters{Statuses: mapGrpcStatuses(req.GetStatuses())},
+		IncludeQueued:       req.GetIncludeQueued(),
+		Actor:               model.CancelActorGRPC,
+	})
+	if err != nil {
+		server.logger.Error("Service TransferNotifications error", "error", err, "transferred", result.Transferred)
+		return nil, serviceStatusError(err)
+	}
+	response := &grpcapi.TransferNotificationsResponse{TransferredCount: result.Transferred}
+	for _, rename := range result.Renamed {
+		response.Renamed = append(response.Renamed, &grpcapi.NotificationIdMapping{
+			SourceNotificationId:      rename.SourceNotificationID,
+			DestinationNotificationId: rename.DestinationNotificationID,
+		})
+	}
+	for _, skipped := range result.Skipped {
+		response.Skipped = append(response.Skipped, &grpcapi.SkippedNotification{NotificationId: skipped.NotificationID, Reason: skipped.Reason})
+	}
+	return response, nil
+}
+
+// ListCaches reports the serving process's tenant and sender caches. Only admin-scoped
+// tokens may call it.
+func (server *notificationServiceServer) ListCaches(ctx context.Context, _ *grpcapi.ListCachesRequest) (*grpcapi.ListCachesResponse, error) {
+	if err := authorizeGRPCCall(ctx, config.GRPCTokenScopeAdmin); err != nil {
+		return nil, err
+	}
+	return mapCacheStates(server.notificationService.CacheStates(ctx)), nil
+}
+
+// FlushCaches empties the serving process's tenant and sender caches and reports what
+// they held. Only admin-scoped tokens may call it.
+func (server *notificationServiceServer) FlushCaches(ctx context.Context, _ *grpcapi.FlushCachesRequest) (*grpcapi.ListCachesResponse, error) {
+	if err := authorizeGRPCCall(ctx, config.GRPCTokenScopeAdmin); err != nil {
+		return nil, err
+	}
+	return mapCacheStates(server.notificationService.FlushCaches(ctx, model.CancelActorGRPC)), nil
+}
+
+func mapCacheStates(states []service.CacheState) *grpcapi.ListCachesResponse {
+	response := &grpcapi.ListCachesResponse{}
+	for _, state := range states {
+		response.Caches = append(response.Caches, &grpcapi.CacheState{
+			Name:    state.Name,
+			Entries: int64(state.Entries),
+			Hits:    state.Hits,
+			Misses:  state.Misses,
+		})
+	}
+	return response
+}
+
+// Ping answers once the auth interceptor accepted the caller's token. It needs no
+// tenant and reads nothing, so clients and monitors can check connectivity cheaply.
+func (server *notificationServiceServer) Ping(_ context.Context, _ *grpcapi.PingRequest) (*grpcapi.PingResponse, error) {
+	return &grpcapi.PingResponse{
+		ServerTime: timestamppb.New(time.Now().UTC()),
+		Version:    serverVersion(),
+	}, nil
+}
+
+// buildVersion is set at link time with -ldflags "-X main.buildVersion=<version>".
+var buildVersion string
+
+// serverVersion reports buildVersion, else the module version the binary was built
+// from, else "devel".
+func serverVersion() string {
+	if buildVersion != "" {
+		return buildVersion
+	}
+	if buildInfo, ok := debug.ReadBuildInfo(); ok && buildInfo.Main.Version != "" && buildInfo.Main.Version != "(devel)" {
+		return buildInfo.Main.Version
+	}
+	return "devel"
+}
+
+func mapDrainStatus(drainStatus service.DrainStatus) *grpcapi.DrainStatus {
+	if !drainStatus.Draining {
+		return &grpcapi.DrainStatus{}
+	}
+	return &grpcapi.DrainStatus{
+		Draining:        true,
+		Drained:         drainStatus.Drained,
+		StartedAt:       timestamppb.New(drainStatus.StartedAt.UTC()),
+		Deadline:        timestamppb.New(drainStatus.Deadline.UTC()),
+		RemainingQueued: drainStatus.RemainingQueued,
+	}
+}
+
+func mapTenantIntegrity(integrity *service.TenantIntegrity) *grpcapi.AttachmentIntegrity {
+	if integrity == nil {
+		return nil
+	}
+	return &grpcapi.AttachmentIntegrity{
+		CheckedAt:                timestamppb.New(integrity.CheckedAt.UTC()),
+		OrphanedAttachments:      integrity.OrphanedAttachments,
+		AttachmentSizeMismatches: integrity.AttachmentSizeMismatches,
+		DuplicateNotificationIds: integrity.DuplicateNotificationIDs,
+		RepairedOrphans:          integrity.RepairedOrphans,
+	}
+}
+
+func mapAttachmentSizes(metrics *service.AttachmentSizeMetrics) *grpcapi.AttachmentSizes {
+	if metrics == nil {
+		return nil
+	}
+	return &grpcapi.AttachmentSizes{
+		Sends:        metrics.Sends,
+		Attachments:  metrics.Attachments,
+		TotalBytes:   metrics.TotalBytes,
+		MaxBytes:     metrics.MaxBytes,
+		SizeBuckets:  mapHistogramBuckets(metrics.SizeBuckets),
+		CountBuckets: mapHistogramBuckets(metrics.CountBuckets),
+	}
+}
+
+func mapHistogramBuckets(buckets []service.HistogramBucket) []*grpcapi.HistogramBucket {
+	mapped := make([]*grpcapi.HistogramBucket, 0, len(buckets))
+	for _, bucket := range buckets {
+		mapped = append(mapped, &grpcapi.HistogramBucket{UpperBound: bucket.UpperBound, Count: bucket.Count})
+	}
+	return mapped
+}
+
+func mapProviderLatencies(latencies []service.ProviderLatency) []*grpcapi.ProviderLatency {
+	mapped := make([]*grpcapi.ProviderLatency, 0, len(latencies))
+	for _, latency := range latencies {
+		provider := grpcapi.NotificationType_EMAIL
+		if latency.Provider == model.NotificationSMS {
+			provider = grpcapi.NotificationType_SMS
+		}
+		mapped = append(mapped, &grpcapi.ProviderLatency{
+			Provider:   provider,
+			Dispatches: latency.Dispatches,
+			AverageMs:  latency.AverageMs,
+			MaxMs:      latency.MaxMs,
+			LastMs:     latency.LastMs,
+		})
+	}
+	return mapped
+}
+
+func mapProviderBreakers(breakers []service.ProviderBreakerState) []*grpcapi.ProviderBreaker {
+	mapped := make([]*grpcapi.ProviderBreaker, 0, len(breakers))
+	for _, breaker := range breakers {
+		provider := grpcapi.NotificationType_EMAIL
+		if breaker.Provider == model.NotificationSMS {
+			provider = grpcapi.NotificationType_SMS
+		}
+		var lastProbeAt *timestamppb.Timestamp
+		if breaker.LastProbeAt != nil {
+			lastProbeAt = timestamppb.New(breaker.LastProbeAt.UTC())
+		}
+		mapped = append(mapped, &grpcapi.ProviderBreaker{
+			Provider:       provider,
+			Reason:         breaker.Reason,
+			OpenedAt:       timestamppb.New(breaker.OpenedAt.UTC()),
+			LastProbeAt:    lastProbeAt,
+			NextProbeAt:    timestamppb.New(breaker.NextProbeAt.UTC()),
+			AdminsNotified: breaker.AdminsNotified,
+		})
+	}
+	return mapped
+}
+
 // mapModelToGrpcResponse converts a model.NotificationResponse to a grpcapi.NotificationResponse.
 func mapModelToGrpcResponse(modelResp model.NotificationResponse) *grpcapi.NotificationResponse {
 	var grpcNotifType grpcapi.NotificationType
@@ -207,49 +775,129 @@ func mapModelToGrpcResponse(modelResp model.NotificationResponse) *grpcapi.Notif
 		grpcNotifType = grpcapi.NotificationType_EMAIL
 	}
 
-	var grpcStatus grpcapi.Status
-	switch modelResp.Status {
+	var scheduledTime *timestamppb.Timestamp
+	if modelResp.ScheduledFor != nil {
+		scheduledTime = timestamppb.New(modelResp.ScheduledFor.UTC())
+	}
+	var expiresAt *timestamppb.Timestamp
+	if modelResp.ExpiresAt != nil {
+		expiresAt = timestamppb.New(modelResp.ExpiresAt.UTC())
+	}
+	var cancelledAt *timestamppb.Timestamp
+	if modelResp.CancelledAt != nil {
+		cancelledAt = timestamppb.New(modelResp.CancelledAt.UTC())
+	}
+
+	return &grpcapi.NotificationResponse{
+		NotificationId:        modelResp.NotificationID,
+		NotificationType:      grpcNotifType,
+		Recipient:             modelResp.Recipient,
+		Subject:               modelResp.Subject,
+		Message:               modelResp.Message,
+		Status:                mapModelStatus(modelResp.Status),
+		ProviderMessageId:     modelResp.ProviderMessageID,
+		RetryCount:            int32(modelResp.RetryCount),
+		CreatedAt:             modelResp.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:             modelResp.UpdatedAt.Format(time.RFC3339),
+		ScheduledTime:         scheduledTime,
+		Attachments:           mapModelAttachments(modelResp.Attachments),
+		TenantId:              modelResp.TenantID,
+		EstimatedCost:         modelResp.EstimatedCost,
+		CostCurrency:          modelResp.CostCurrency,
+		ExpiresAt:             expiresAt,
+		CancelReason:          modelResp.CancelReason,
+		CancelledBy:           modelResp.CancelledBy,
+		CancelledAt:           cancelledAt,
+		LastError:             modelResp.LastError,
+		Source:                string(modelResp.Source),
+		Truncated:             modelResp.Truncated,
+		ContentEncrypted:      modelResp.ContentEncrypted,
+		ReplyTo:               modelResp.ReplyTo,
+		FromDisplayName:       modelResp.FromDisplayName,
+		OriginalMessageLength: int32(modelResp.OriginalMessageLength),
+		Warnings:              modelResp.Warnings,
+		Rendered:              mapRenderedContent(modelResp.Rendered),
+		Opens:                 mapNotificationOpens(modelResp.Opens),
+		PendingReason:         string(modelResp.PendingReason),
+		GroupId:               modelResp.GroupID,
+	}
+}
+
+func mapModelStatus(modelStatus model.NotificationStatus) grpcapi.Status {
+	switch modelStatus {
 	case model.StatusQueued:
-		grpcStatus = grpcapi.Status_QUEUED
+		return grpcapi.Status_QUEUED
 	case model.StatusSent:
-		grpcStatus = grpcapi.Status_SENT
+		return grpcapi.Status_SENT
 	case model.StatusCancelled:
-		grpcStatus = grpcapi.Status_CANCELLED
+		return grpcapi.Status_CANCELLED
 	case model.StatusErrored:
-		grpcStatus = grpcapi.Status_ERRORED
+		return grpcapi.Status_ERRORED
 	default:
-		grpcStatus = grpcapi.Status_UNKNOWN
+		return grpcapi.Status_UNKNOWN
 	}
+}
 
-	var scheduledTime *timestamppb.Timestamp
-	if modelResp.ScheduledFor != nil {
-		scheduledTime = timestamppb.New(modelResp.ScheduledFor.UTC())
+func mapModelGroupToGrpcResponse(group model.NotificationGroupResponse) *grpcapi.NotificationGroupResponse {
+	notifications := make([]*grpcapi.NotificationResponse, 0, len(group.Notifications))
+	for _, notification := range group.Notifications {
+		notifications = append(notifications, mapModelToGrpcResponse(notification))
 	}
+	return &grpcapi.NotificationGroupResponse{
+		GroupId:       group.GroupID,
+		Status:        mapModelStatus(group.Status),
+		Notifications: notifications,
+	}
+}
 
-	return &grpcapi.NotificationResponse{
-		NotificationId:    modelResp.NotificationID,
-		NotificationType:  grpcNotifType,
-		Recipient:         modelResp.Recipient,
-		Subject:           modelResp.Subject,
-		Message:           modelResp.Message,
-		Status:            grpcStatus,
-		ProviderMessageId: modelResp.ProviderMessageID,
-		RetryCount:        int32(modelResp.RetryCount),
-		CreatedAt:         modelResp.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:         modelResp.UpdatedAt.Format(time.RFC3339),
-		ScheduledTime:     scheduledTime,
-		Attachments:       mapModelAttachments(modelResp.Attachments),
-		TenantId:          modelResp.TenantID,
+func mapRenderedContent(content *model.RenderedContent) *grpcapi.RenderedContent {
+	if content == nil {
+		return nil
+	}
+	return &grpcapi.RenderedContent{
+		Subject:       content.Subject,
+		Message:       content.Message,
+		SameAsInput:   content.SameAsInput,
+		MimeStructure: content.MIMEStructure,
+		SizeBytes:     int64(content.SizeBytes),
+		RenderedAt:    timestamppb.New(content.RenderedAt.UTC()),
+	}
+}
+
+func mapNotificationOpens(summary *model.NotificationOpenSummary) *grpcapi.NotificationOpens {
+	if summary == nil {
+		return nil
 	}
+	opens := &grpcapi.NotificationOpens{OpenCount: summary.OpenCount}
+	if summary.FirstOpenedAt != nil {
+		opens.FirstOpenedAt = timestamppb.New(summary.FirstOpenedAt.UTC())
+	}
+	if summary.LastOpenedAt != nil {
+		opens.LastOpenedAt = timestamppb.New(summary.LastOpenedAt.UTC())
+	}
+	return opens
 }
 
-func digestForLogging(value string) string {
-	trimmed := strings.TrimSpace(strings.ToLower(value))
-	if trimmed == "" {
-		return ""
+// mapCostSummaryToGrpcResponse converts a model.CostSummary to a grpcapi.CostSummaryResponse.
+func mapCostSummaryToGrpcResponse(summary model.CostSummary) *grpcapi.CostSummaryResponse {
+	buckets := make([]*grpcapi.CostSummaryBucket, 0, len(summary.Buckets))
+	for _, bucket := range summary.Buckets {
+		grpcNotifType := grpcapi.NotificationType_EMAIL
+		if bucket.NotificationType == model.NotificationSMS {
+			grpcNotifType = grpcapi.NotificationType_SMS
+		}
+		buckets = append(buckets, &grpcapi.CostSummaryBucket{
+			Day:               bucket.Day,
+			NotificationType:  grpcNotifType,
+			NotificationCount: int32(bucket.NotificationCount),
+			EstimatedCost:     bucket.EstimatedCost,
+		})
+	}
+	return &grpcapi.CostSummaryResponse{
+		Currency:  summary.Currency,
+		TotalCost: summary.TotalCost,
+		Buckets:   buckets,
 	}
-	digest := sha256.Sum256([]byte(trimmed))
-	return hex.EncodeToString(digest[:8])
 }
 
 func mapGrpcAttachments(source []*grpcapi.EmailAttachment) []model.EmailAttachment {
@@ -261,12 +909,12 @@ func mapGrpcAttachments(source []*grpcapi.EmailAttachment) []model.EmailAttachme
 		if attachment == nil {
 			continue
 		}
-		clonedData := make([]byte, len(attachment.Data))
-		copy(clonedData, attachment.Data)
+		// The decoded request is not touched after the call, so the model takes its
+		// attachment data over instead of copying it.
 		result = append(result, model.EmailAttachment{
 			Filename:    attachment.GetFilename(),
 			ContentType: attachment.GetContentType(),
-			Data:        clonedData,
+			Data:        attachment.GetData(),
 		})
 	}
 	return result
@@ -278,12 +926,10 @@ func mapModelAttachments(source []model.EmailAttachment) []*grpcapi.EmailAttachm
 	}
 	result := make([]*grpcapi.EmailAttachment, 0, len(source))
 	for _, attachment := range source {
-		clonedData := make([]byte, len(attachment.Data))
-		copy(clonedData, attachment.Data)
 		result = append(result, &grpcapi.EmailAttachment{
 			Filename:    attachment.Filename,
 			ContentType: attachment.ContentType,
-			Data:        clonedData,
+			Data:        attachment.Data,
 		})
 	}
 	return result
@@ -306,6 +952,9 @@ func mapGrpcStatuses(source []grpcapi.Status) []model.NotificationStatus {
 			result = append(result, model.StatusErrored)
 		case grpcapi.Status_UNKNOWN:
 			result = append(result, model.StatusUnknown)
+		default:
+			// Kept so server.strictStatusFilters can reject it; the list ignores it otherwise.
+			result = append(result, model.NotificationStatus(statusValue.String()))
 		}
 	}
 	if len(result) == 0 {
@@ -314,64 +963,255 @@ func mapGrpcStatuses(source []grpcapi.Status) []model.NotificationStatus {
 	return result
 }
 
-func buildAuthInterceptor(logger *slog.Logger, requiredToken string) grpc.UnaryServerInterceptor {
-	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		metadataValues, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			logger.Error("Missing metadata in gRPC request")
-			return nil, status.Error(codes.Unauthenticated, "missing metadata")
-		}
-		authorizationHeaders := metadataValues.Get("authorization")
-		if len(authorizationHeaders) == 0 {
-			logger.Error("Missing authorization header")
-			return nil, status.Error(codes.Unauthenticated, "missing authorization header")
-		}
-		headerValue := authorizationHeaders[0]
-		if !strings.HasPrefix(headerValue, "Bearer ") {
-			logger.Error("Invalid authorization header format")
-			return nil, status.Error(codes.Unauthenticated, "invalid authorization header")
+type tenantIDGetter interface {
+	GetTenantId() string
+}
+
+// crossTenantGRPCMethods carry no tenant; authorizeGRPCCall restricts them to admin tokens.
+var crossTenantGRPCMethods = map[string]struct{}{
+	grpcapi.NotificationService_ListTenantsStatus_FullMethodName:     {},
+	grpcapi.NotificationService_DrainInstance_FullMethodName:         {},
+	grpcapi.NotificationService_GetDrainStatus_FullMethodName:        {},
+	grpcapi.NotificationService_TransferNotifications_FullMethodName: {},
+	grpcapi.NotificationService_ListCaches_FullMethodName:            {},
+	grpcapi.NotificationService_FlushCaches_FullMethodName:           {},
+}
+
+// openDatabase opens and migrates the database and bounds its statements by timeouts.
+func openDatabase(databasePath string, timeouts model.QueryTimeouts, logger *slog.Logger) (*gorm.DB, error) {
+	database, err := db.InitDB(databasePath, logger)
+	if err != nil {
+		return nil, err
+	}
+	if err := model.RegisterQueryTimeouts(database, timeouts); err != nil {
+		return nil, err
+	}
+	return database, nil
+}
+
+// queryTimeouts resolves server.queryTimeoutSec and server.aggregateQueryTimeoutSec,
+// using the defaults for zero.
+func queryTimeouts(configuration config.Config) model.QueryTimeouts {
+	pointSec := configuration.QueryTimeoutSec
+	if pointSec == 0 {
+		pointSec = config.DefaultQueryTimeoutSec
+	}
+	aggregateSec := configuration.AggregateQueryTimeoutSec
+	if aggregateSec == 0 {
+		aggregateSec = config.DefaultAggregateQueryTimeoutSec
+	}
+	return model.QueryTimeouts{
+		Point:     time.Duration(pointSec) * time.Second,
+		Aggregate: time.Duration(aggregateSec) * time.Second,
+	}
+}
+
+// buildDatabaseBusyInterceptor turns a handler error that wraps model.ErrDatabaseBusy
+// into Unavailable with a RetryInfo hint, so clients back off instead of seeing an
+// opaque Unknown error, and one that wraps model.ErrQueryTimeout into DeadlineExceeded.
+func buildDatabaseBusyInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		response, err := handler(ctx, req)
+		if mapped, isDatabaseErr := databaseStatusError(logger, err); isDatabaseErr {
+			return nil, mapped
 		}
-		token := strings.TrimPrefix(headerValue, "Bearer ")
-		if token != requiredToken {
-			logger.Error("Invalid token provided")
-			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		return response, err
+	}
+}
+
+// buildDatabaseBusyStreamInterceptor is buildDatabaseBusyInterceptor for streaming RPCs.
+func buildDatabaseBusyStreamInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, stream)
+		if mapped, isDatabaseErr := databaseStatusError(logger, err); isDatabaseErr {
+			return mapped
 		}
-		return handler(ctx, req)
+		return err
 	}
 }
 
-type tenantIDGetter interface {
-	GetTenantId() string
+// databaseStatusError maps the database errors clients should treat as transient.
+func databaseStatusError(logger *slog.Logger, err error) (error, bool) {
+	switch {
+	case err == nil:
+		return nil, false
+	case errors.Is(err, model.ErrDatabaseBusy):
+		logger.Warn("grpc_database_busy", "error", err)
+		return unavailableStatusError(err, databaseBusyRetryDelay), true
+	case errors.Is(err, model.ErrQueryTimeout):
+		logger.Warn("grpc_database_timeout", "error", err)
+		return status.Error(codes.DeadlineExceeded, "database query timed out"), true
+	default:
+		return nil, false
+	}
 }
 
 func buildTenantInterceptor(logger *slog.Logger, repo *tenant.Repository) grpc.UnaryServerInterceptor {
-	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		if repo == nil {
-			logger.Error(tenantRepositoryUnavailableError)
-			return nil, status.Error(codes.Internal, tenantRepositoryUnavailableError)
-		}
-		var tenantID string
-		if requestWithTenantID, ok := req.(tenantIDGetter); ok {
-			tenantID = strings.TrimSpace(requestWithTenantID.GetTenantId())
-		}
-		if tenantID == "" {
-			if metadataValues, ok := metadata.FromIncomingContext(ctx); ok {
-				if values := metadataValues.Get(tenantMetadataKey); len(values) > 0 {
-					tenantID = strings.TrimSpace(values[0])
-				}
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if info != nil {
+			if _, crossTenant := crossTenantGRPCMethods[info.FullMethod]; crossTenant || info.FullMethod == grpcapi.NotificationService_Ping_FullMethodName {
+				return handler(ctx, req)
 			}
 		}
-		if tenantID == "" {
-			return nil, status.Error(codes.InvalidArgument, tenantIDRequiredMessage)
+		tenantCtx, err := resolveGRPCTenant(ctx, logger, repo, req)
+		if err != nil {
+			return nil, err
 		}
+		return handler(tenantCtx, req)
+	}
+}
+
+// buildTenantStreamInterceptor resolves the tenant for streaming RPCs once the request
+// message arrives, since the tenant may be named in it.
+func buildTenantStreamInterceptor(logger *slog.Logger, repo *tenant.Repository) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &tenantServerStream{contextServerStream: contextServerStream{ServerStream: stream, ctx: stream.Context()}, logger: logger, repo: repo})
+	}
+}
+
+// contextServerStream replaces the context a streaming handler sees.
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *contextServerStream) Context() context.Context {
+	return stream.ctx
+}
+
+type tenantServerStream struct {
+	contextServerStream
+	logger   *slog.Logger
+	repo     *tenant.Repository
+	resolved bool
+}
+
+func (stream *tenantServerStream) RecvMsg(message interface{}) error {
+	if err := stream.ServerStream.RecvMsg(message); err != nil {
+		return err
+	}
+	if stream.resolved {
+		return nil
+	}
+	tenantCtx, err := resolveGRPCTenant(stream.ctx, stream.logger, stream.repo, message)
+	if err != nil {
+		return err
+	}
+	stream.ctx = tenantCtx
+	stream.resolved = true
+	return nil
+}
+
+// resolveGRPCTenant attaches the tenant named by the request, the x-tenant-id metadata or
+// the forwarded host, after checking the caller against the tenant's allowlist.
+func resolveGRPCTenant(ctx context.Context, logger *slog.Logger, repo *tenant.Repository, req interface{}) (context.Context, error) {
+	if repo == nil {
+		logger.Error(tenantRepositoryUnavailableError)
+		return nil, status.Error(codes.Internal, tenantRepositoryUnavailableError)
+	}
+	var tenantID string
+	if requestWithTenantID, ok := req.(tenantIDGetter); ok {
+		tenantID = strings.TrimSpace(requestWithTenantID.GetTenantId())
+	}
+	metadataValues, _ := metadata.FromIncomingContext(ctx)
+	if tenantID == "" {
+		tenantID = firstMetadataValue(metadataValues, tenantMetadataKey)
+	}
+	if tenantID != "" {
 		runtimeCfg, err := repo.ResolveByID(ctx, tenantID)
 		if err != nil {
-			logger.Error("tenant_resolution_failed", "tenant_id", tenantID, "error", err)
-			return nil, status.Error(codes.NotFound, tenantNotFoundMessage)
+			logging.LogEvent(ctx, logger, logging.EventTenantResolutionFailed, "tenant_resolution_failed", "transport", "grpc", "tenant_id", tenantID, "error", err)
+			if tenantMissing(err) {
+				return nil, status.Error(codes.NotFound, tenantNotFoundMessage)
+			}
+			return nil, tenantLookupStatusError(logger, err)
+		}
+		if err := authorizeTenantCaller(ctx, logger, runtimeCfg); err != nil {
+			return nil, err
+		}
+		logging.LogEvent(ctx, logger, logging.EventTenantResolved, "tenant_resolved", "transport", "grpc", "tenant_id", runtimeCfg.Tenant.ID, "source", "tenant_id")
+		return tenant.WithRuntime(ctx, runtimeCfg), nil
+	}
+	host := tenantHostFromMetadata(metadataValues)
+	if host == "" {
+		return nil, status.Error(codes.InvalidArgument, tenantIDRequiredMessage)
+	}
+	runtimeCfg, err := repo.ResolveByHost(ctx, host)
+	if err != nil && !tenantMissing(err) {
+		logging.LogEvent(ctx, logger, logging.EventTenantResolutionFailed, "tenant_resolution_failed", "transport", "grpc", "host", host, "error", err)
+		return nil, tenantLookupStatusError(logger, err)
+	}
+	if err != nil {
+		// A host that maps to no tenant is only a missed hint; the caller still owes an explicit tenant.
+		logger.Debug("tenant_host_resolution_failed", "host", host, "error", err)
+		return nil, status.Error(codes.InvalidArgument, tenantIDRequiredMessage)
+	}
+	if err := authorizeTenantCaller(ctx, logger, runtimeCfg); err != nil {
+		return nil, err
+	}
+	logging.LogEvent(ctx, logger, logging.EventTenantResolved, "tenant_resolved", "transport", "grpc", "tenant_id", runtimeCfg.Tenant.ID, "source", "host")
+	return tenant.WithRuntime(ctx, runtimeCfg), nil
+}
+
+// tenantMissing reports whether a tenant lookup failed because no such tenant is
+// configured, as opposed to the database failing to answer.
+func tenantMissing(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, tenant.ErrInvalidTenantID)
+}
+
+// tenantLookupStatusError maps a tenant lookup the database failed to answer. A busy or
+// slow database keeps its usual codes; any other failure is Unavailable, so callers
+// retry and monitoring sees an outage rather than a misconfigured tenant.
+func tenantLookupStatusError(logger *slog.Logger, err error) error {
+	if mapped, isDatabaseErr := databaseStatusError(logger, err); isDatabaseErr {
+		return mapped
+	}
+	return status.Error(codes.Unavailable, tenantLookupFailedMessage)
+}
+
+// authorizeTenantCaller checks the connection's peer against the tenant's
+// callerAllowlist. The gRPC listener speaks no proxy protocol, so the peer is the
+// socket's remote address.
+func authorizeTenantCaller(ctx context.Context, logger *slog.Logger, runtimeCfg tenant.RuntimeConfig) error {
+	if len(runtimeCfg.CallerAllowlist) == 0 {
+		return nil
+	}
+	var peerAddress netip.Addr
+	peerLabel := ""
+	if callerPeer, ok := peer.FromContext(ctx); ok && callerPeer.Addr != nil {
+		peerLabel = callerPeer.Addr.String()
+		if addressPort, err := netip.ParseAddrPort(peerLabel); err == nil {
+			peerAddress = addressPort.Addr()
 		}
-		ctxWithTenant := tenant.WithRuntime(ctx, runtimeCfg)
-		return handler(ctxWithTenant, req)
 	}
+	if runtimeCfg.AllowsCaller(peerAddress) {
+		return nil
+	}
+	logging.LogEvent(ctx, logger, logging.EventAuthRejected, "tenant_caller_rejected", "transport", "grpc", "reason", "caller_not_allowed", "tenant_id", runtimeCfg.Tenant.ID, "peer", peerLabel)
+	return status.Error(codes.PermissionDenied, tenantCallerNotAllowedMessage)
+}
+
+// tenantHostFromMetadata prefers the gateway-forwarded host over the HTTP/2 authority.
+func tenantHostFromMetadata(metadataValues metadata.MD) string {
+	if forwardedHost := firstMetadataValue(metadataValues, forwardedHostMetadataKey); forwardedHost != "" {
+		firstHost, _, _ := strings.Cut(forwardedHost, ",")
+		return strings.TrimSpace(firstHost)
+	}
+	return firstMetadataValue(metadataValues, authorityMetadataKey)
+}
+
+func firstMetadataValue(metadataValues metadata.MD, key string) string {
+	if values := metadataValues.Get(key); len(values) > 0 {
+		return strings.TrimSpace(values[0])
+	}
+	return ""
+}
+
+// certificateWatcher serves a TLS certificate that follows rotations on disk.
+type certificateWatcher interface {
+	TLSConfig() *tls.Config
+	Watch(context.Context, time.Duration)
+	Status() certreload.Status
 }
 
 type smtpSubmissionStarter interface {
@@ -390,27 +1230,38 @@ type httpServerRunner interface {
 type serverDependencies struct {
 	loadConfig                func() (config.Config, error)
 	newLogger                 func(string) *slog.Logger
-	initDB                    func(string, *slog.Logger) (*gorm.DB, error)
-	newSecretKeeper           func(string) (*tenant.SecretKeeper, error)
+	initDB                    func(string, model.QueryTimeouts, *slog.Logger) (*gorm.DB, error)
+	newSecretKeeper           func(config.Config) (*tenant.SecretKeeper, error)
 	bootstrapTenants          func(context.Context, *gorm.DB, *tenant.SecretKeeper, tenant.BootstrapConfig) error
 	bootstrapTenantsFromFile  func(context.Context, *gorm.DB, *tenant.SecretKeeper, string) error
+	seedDatabase              func(context.Context, *gorm.DB, *tenant.SecretKeeper, seed.Options) (seed.Summary, error)
 	newTenantRepository       func(*gorm.DB, *tenant.SecretKeeper) *tenant.Repository
 	newSMTPIdentityRepository func(*gorm.DB, string) (*smtpidentity.Repository, error)
 	newSMTPIdentityService    func(*smtpidentity.Repository, smtpidentity.PublicSettings) *smtpidentity.Service
 	newNotificationService    func(*gorm.DB, *slog.Logger, config.Config, *tenant.Repository) service.NotificationService
-	loadTLSConfig             func(string, string) (*tls.Config, error)
+	loadCertificate           func(string, string, *slog.Logger) (certificateWatcher, error)
 	newSMTPRelay              func(*slog.Logger, config.Config) smtpsubmission.RawRelay
 	newSMTPSubmissionServer   func(smtpsubmission.Config) (smtpSubmissionStarter, error)
 	newSMTPForwarder          func(*slog.Logger, config.Config) (smtpforwarding.Forwarder, error)
 	newSMTPForwardingServer   func(smtpforwarding.Config) (smtpForwardingStarter, error)
 	newSessionValidator       func(sessionvalidator.Config) (httpapi.SessionValidator, error)
 	newHTTPServer             func(httpapi.Config) (httpServerRunner, error)
+	newQueueIntakeConsumer    func(queueintake.Config) (queueIntakeRunner, error)
 	listen                    func(string, string) (net.Listener, error)
-	serveGRPC                 func(net.Listener, service.NotificationService, *tenant.Repository, *slog.Logger, string) error
+	serveGRPC                 func(net.Listener, service.NotificationService, queueIntakeStatsReporter, *tenant.Repository, *slog.Logger, []config.GRPCTokenConfig, config.GRPCKeepaliveConfig) error
 	exit                      func(int)
 }
 
-const grpcReadinessEvent = "pinguin.grpc.ready"
+// grpcShutdownContext ends when the process is asked to stop. serveGRPC then stops
+// gracefully, which closes the listener and removes a Unix socket file.
+var grpcShutdownContext = func() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+// drainSignalContext ends when the operator sends SIGUSR1 to decommission the instance.
+var drainSignalContext = func() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), syscall.SIGUSR1)
+}
 
 func main() {
 	runServerAndExit(os.Args[1:], productionServerDependencies())
@@ -428,15 +1279,22 @@ func productionServerDependencies() serverDependencies {
 	return serverDependencies{
 		loadConfig:                config.LoadConfig,
 		newLogger:                 logging.NewLogger,
-		initDB:                    db.InitDB,
-		newSecretKeeper:           tenant.NewSecretKeeper,
+		initDB:                    openDatabase,
+		newSecretKeeper:           newConfiguredSecretKeeper,
 		bootstrapTenants:          tenant.Bootstrap,
 		bootstrapTenantsFromFile:  tenant.BootstrapFromFile,
+		seedDatabase:              seed.Run,
 		newTenantRepository:       tenant.NewRepository,
 		newSMTPIdentityRepository: smtpidentity.NewRepository,
 		newSMTPIdentityService:    smtpidentity.NewService,
 		newNotificationService:    service.NewNotificationService,
-		loadTLSConfig:             smtpsubmission.LoadTLSConfig,
+		loadCertificate: func(certPath string, keyPath string, logger *slog.Logger) (certificateWatcher, error) {
+			reloader, err := certreload.New(certPath, keyPath, logger)
+			if err != nil {
+				return nil, err
+			}
+			return reloader, nil
+		},
 		newSMTPRelay: func(logger *slog.Logger, cfg config.Config) smtpsubmission.RawRelay {
 			if cfg.SMTPSubmission.DeliveryMode == "direct" {
 				return smtpsubmission.NewDirectMXRelay(logger, cfg)
@@ -466,7 +1324,10 @@ func productionServerDependencies() serverDependencies {
 		newHTTPServer: func(cfg httpapi.Config) (httpServerRunner, error) {
 			return httpapi.NewServer(cfg)
 		},
-		listen:    net.Listen,
+		newQueueIntakeConsumer: func(cfg queueintake.Config) (queueIntakeRunner, error) {
+			return queueintake.NewConsumer(cfg)
+		},
+		listen:    endpoint.Listen,
 		serveGRPC: serveGRPC,
 		exit:      os.Exit,
 	}
@@ -474,6 +1335,9 @@ func productionServerDependencies() serverDependencies {
 
 func runServer(args []string, dependencies serverDependencies) int {
 	dependencies = withServerDependencyDefaults(dependencies)
+	if len(args) > 0 && args[0] == seedCommandName {
+		return runSeed(args[1:], dependencies)
+	}
 	flags := flag.NewFlagSet("pinguin-server", flag.ContinueOnError)
 	flags.SetOutput(os.Stderr)
 	if parseErr := flags.Parse(args); parseErr != nil {
@@ -486,22 +1350,32 @@ func runServer(args []string, dependencies serverDependencies) int {
 	configuration, configErr := dependencies.loadConfig()
 	if configErr != nil {
 		fallbackLogger := dependencies.newLogger("INFO")
-		for _, errMsg := range strings.Split(configErr.Error(), ", ") {
-			fallbackLogger.Error("Configuration error", "detail", errMsg)
+		var validationErr *config.ValidationError
+		if !errors.As(configErr, &validationErr) {
+			fallbackLogger.Error("Configuration error", "detail", configErr.Error())
+			return 1
+		}
+		for _, problem := range validationErr.Problems {
+			fallbackLogger.Error("Configuration error", "detail", problem)
 		}
 		return 1
 	}
 
 	mainLogger := dependencies.newLogger(configuration.LogLevel)
-	mainLogger.Info("Starting gRPC Notification Server on :50051")
+	grpcEndpoint, endpointErr := endpoint.Parse(configuration.GRPCListenAddress())
+	if endpointErr != nil {
+		mainLogger.Error("Invalid gRPC listen address", "error", endpointErr)
+		return 1
+	}
+	mainLogger.Info("Starting gRPC Notification Server", "listen_addr", grpcEndpoint.String())
 
-	databaseInstance, dbErr := dependencies.initDB(configuration.DatabasePath, mainLogger)
+	databaseInstance, dbErr := dependencies.initDB(configuration.DatabasePath, queryTimeouts(configuration), mainLogger)
 	if dbErr != nil {
 		mainLogger.Error("Failed to initialize DB", "error", dbErr)
 		return 1
 	}
 
-	secretKeeper, keeperErr := dependencies.newSecretKeeper(configuration.MasterEncryptionKey)
+	secretKeeper, keeperErr := dependencies.newSecretKeeper(configuration)
 	if keeperErr != nil {
 		mainLogger.Error("Failed to initialize secret keeper", "error", keeperErr)
 		return 1
@@ -523,7 +1397,11 @@ func runServer(args []string, dependencies serverDependencies) int {
 		mainLogger.Error("Failed to bootstrap tenants", "error", "no tenant config supplied")
 		return 1
 	}
-	tenantRepo := dependencies.newTenantRepository(databaseInstance, secretKeeper)
+	tenantRepo := dependencies.newTenantRepository(databaseInstance, secretKeeper).WithRuntimeCacheLimit(configuration.TenantCacheMaxEntries)
+	defer tenantRepo.Close()
+	if !verifyTenantsAtStartup(context.Background(), tenantRepo, mainLogger, configuration.StrictTenantSelfCheck) {
+		return 1
+	}
 	smtpIdentityRepo, smtpIdentityRepoErr := dependencies.newSMTPIdentityRepository(databaseInstance, configuration.MasterEncryptionKey)
 	if smtpIdentityRepoErr != nil {
 		mainLogger.Error("Failed to initialize SMTP identity repository", "error", smtpIdentityRepoErr)
@@ -531,22 +1409,30 @@ func runServer(args []string, dependencies serverDependencies) int {
 	}
 	smtpIdentityService := dependencies.newSMTPIdentityService(smtpIdentityRepo, smtpPublicSettings(configuration.SMTPSubmission))
 
-	notificationSvc := dependencies.newNotificationService(databaseInstance, mainLogger, configuration, tenantRepo)
+	notificationSvc := service.Chain(dependencies.newNotificationService(databaseInstance, mainLogger, configuration, tenantRepo), service.ProductionDecorators(mainLogger)...)
 
-	// Start the background retry worker.
+	// Start the background retry, daily report, integrity and webhook workers.
 	workerCtx, cancelWorker := context.WithCancel(context.Background())
 	defer cancelWorker()
 	go notificationSvc.StartRetryWorker(workerCtx)
+	go notificationSvc.StartDailyReportWorker(workerCtx)
+	go notificationSvc.StartIntegrityWorker(workerCtx)
+	go notificationSvc.StartWebhookWorker(workerCtx)
+	go notificationSvc.StartRetentionWorker(workerCtx)
+	go watchDrainSignal(workerCtx, notificationSvc, mainLogger)
 
+	var certificates map[string]httpapi.CertificateStatusReporter
 	if configuration.SMTPSubmission.Enabled {
 		var tlsConfig *tls.Config
 		if configuration.SMTPSubmission.TLSCertPath != "" && configuration.SMTPSubmission.TLSKeyPath != "" {
-			loadedTLSConfig, tlsErr := dependencies.loadTLSConfig(configuration.SMTPSubmission.TLSCertPath, configuration.SMTPSubmission.TLSKeyPath)
+			certificate, tlsErr := dependencies.loadCertificate(configuration.SMTPSubmission.TLSCertPath, configuration.SMTPSubmission.TLSKeyPath, mainLogger)
 			if tlsErr != nil {
 				mainLogger.Error("Failed to load SMTP submission TLS config", "error", tlsErr)
 				return 1
 			}
-			tlsConfig = loadedTLSConfig
+			go certificate.Watch(workerCtx, certificateReloadInterval)
+			certificates = map[string]httpapi.CertificateStatusReporter{smtpSubmissionCertificateName: certificate}
+			tlsConfig = certificate.TLSConfig()
 		}
 		smtpSubmissionServer, smtpServerErr := dependencies.newSMTPSubmissionServer(smtpsubmission.Config{
 			Hostname:          configuration.SMTPSubmission.Hostname,
@@ -595,6 +1481,50 @@ func runServer(args []string, dependencies serverDependencies) int {
 		startSMTPForwarding(smtpForwardingCtx, mainLogger, smtpForwardingServer, configuration, dependencies.exit)
 	}
 
+	var queueIntakeStats queueIntakeStatsReporter
+	if configuration.QueueIntake.Enabled {
+		intakeConfig := configuration.QueueIntake
+		queueIntakeConsumer, queueIntakeErr := dependencies.newQueueIntakeConsumer(queueintake.Config{
+			URL:               intakeConfig.URL,
+			Stream:            intakeConfig.Stream,
+			Subject:           intakeConfig.Subject,
+			Durable:           intakeConfig.Durable,
+			DeadLetterSubject: intakeConfig.DeadLetterSubject,
+			Concurrency:       intakeConfig.Concurrency,
+			RequeueDelay:      time.Duration(intakeConfig.RequeueDelaySec) * time.Second,
+			MaxDeliver:        intakeConfig.MaxDeliver,
+			AckWait:           time.Duration(intakeConfig.AckWaitSec) * time.Second,
+			Username:          intakeConfig.Username,
+			Password:          intakeConfig.Password,
+			Token:             intakeConfig.Token,
+			CredentialsFile:   intakeConfig.CredentialsFile,
+			Handler: newQueueIntakeHandler(
+				&notificationServiceServer{notificationService: notificationSvc, logger: mainLogger},
+				tenantRepo,
+				databaseInstance,
+				intakeConfig,
+				mainLogger,
+			),
+			Logger: mainLogger,
+		})
+		if queueIntakeErr != nil {
+			mainLogger.Error("Failed to initialize queue intake", "error", queueIntakeErr)
+			return 1
+		}
+		if startErr := queueIntakeConsumer.Start(context.Background()); startErr != nil {
+			mainLogger.Error("Failed to start queue intake", "error", startErr)
+			return 1
+		}
+		defer func() {
+			stopCtx, cancel := context.WithTimeout(context.Background(), queueIntakeStopTimeout)
+			defer cancel()
+			if err := queueIntakeConsumer.Stop(stopCtx); err != nil {
+				mainLogger.Error("Queue intake shutdown error", "error", err)
+			}
+		}()
+		queueIntakeStats = queueIntakeConsumer
+	}
+
 	if configuration.WebInterfaceEnabled {
 		sessionValidator, validatorErr := dependencies.newSessionValidator(sessionvalidator.Config{
 			SigningKey: []byte(configuration.TAuthSigningKey),
@@ -605,15 +1535,42 @@ func runServer(args []string, dependencies serverDependencies) int {
 			return 1
 		}
 
+		var openTracker httpapi.OpenTracker
+		if configuration.OpenTrackingBaseURL != "" {
+			openRecorder := opentracking.NewRecorder(databaseInstance, mainLogger)
+			recorderCtx, stopRecorder := context.WithCancel(context.Background())
+			recorderDone := make(chan struct{})
+			go func() {
+				defer close(recorderDone)
+				openRecorder.Run(recorderCtx)
+			}()
+			// Registered before the HTTP shutdown so it runs after it and flushes the last opens.
+			defer func() {
+				stopRecorder()
+				<-recorderDone
+			}()
+			openTracker = opentracking.NewTracker(opentracking.NewSigner(configuration.MasterEncryptionKey), tenantRepo, openRecorder)
+		}
+
 		httpServer, httpServerErr := dependencies.newHTTPServer(httpapi.Config{
-			ListenAddr:          configuration.HTTPListenAddr,
-			AllowedOrigins:      configuration.HTTPAllowedOrigins,
-			TrustedProxies:      configuration.HTTPTrustedProxies,
-			SessionValidator:    sessionValidator,
-			NotificationService: notificationSvc,
-			SMTPIdentityService: smtpIdentityService,
-			TenantRepository:    tenantRepo,
-			Logger:              mainLogger,
+			ListenAddr:                configuration.HTTPListenAddr,
+			AllowedOrigins:            configuration.HTTPAllowedOrigins,
+			TrustedProxies:            configuration.HTTPTrustedProxies,
+			ServiceAccountEmails:      configuration.HTTPServiceAccountEmails,
+			ReadTimeout:               time.Duration(configuration.HTTPReadTimeoutSec) * time.Second,
+			WriteTimeout:              time.Duration(configuration.HTTPWriteTimeoutSec) * time.Second,
+			IdleTimeout:               time.Duration(configuration.HTTPIdleTimeoutSec) * time.Second,
+			MaxRequestBytes:           configuration.HTTPMaxRequestBytes,
+			MaxAttachmentRequestBytes: configuration.HTTPMaxAttachmentRequestBytes,
+			CamelCaseJSON:             configuration.HTTPJSONFieldNaming == config.JSONFieldNamingCamelCase,
+			SessionValidator:          sessionValidator,
+			NotificationService:       notificationSvc,
+			SMTPIdentityService:       smtpIdentityService,
+			SavedFilterRepository:     savedfilter.NewRepository(databaseInstance),
+			TenantRepository:          tenantRepo,
+			Certificates:              certificates,
+			OpenTracker:               openTracker,
+			Logger:                    mainLogger,
 		})
 		if httpServerErr != nil {
 			mainLogger.Error("Failed to initialize HTTP server", "error", httpServerErr)
@@ -632,20 +1589,32 @@ func runServer(args []string, dependencies serverDependencies) int {
 		mainLogger.Info("Web interface disabled; HTTP server not started")
 	}
 
-	listener, listenErr := dependencies.listen("tcp", ":50051")
+	listener, listenErr := dependencies.listen(grpcEndpoint.Network(), grpcEndpoint.Address())
 	if listenErr != nil {
-		mainLogger.Error("Failed to listen on :50051", "error", listenErr)
+		mainLogger.Error("Failed to listen for gRPC", "listen_addr", grpcEndpoint.String(), "error", listenErr)
 		return 1
 	}
-	mainLogger.Info("service_ready", "event", grpcReadinessEvent)
+	logging.LogEvent(context.Background(), mainLogger, logging.EventGRPCReady, "service_ready")
 
-	if serveErr := dependencies.serveGRPC(listener, notificationSvc, tenantRepo, mainLogger, configuration.GRPCAuthToken); serveErr != nil {
+	if serveErr := dependencies.serveGRPC(listener, notificationSvc, queueIntakeStats, tenantRepo, mainLogger, grpcTokenGrants(configuration), configuration.GRPCKeepalive); serveErr != nil {
 		mainLogger.Error("gRPC server crashed", "error", serveErr)
 		return 1
 	}
 	return 0
 }
 
+// newConfiguredSecretKeeper builds the SecretKeeper with the cipher named by
+// server.secretCipher. A PKCS#11/HSM cipher is added here as another case that passes
+// tenant.WithCipher.
+func newConfiguredSecretKeeper(configuration config.Config) (*tenant.SecretKeeper, error) {
+	switch configuration.SecretCipher {
+	case "", config.SecretCipherAESGCM:
+		return tenant.NewSecretKeeper(configuration.MasterEncryptionKey)
+	default:
+		return nil, fmt.Errorf("unsupported secret cipher %q", configuration.SecretCipher)
+	}
+}
+
 func withServerDependencyDefaults(dependencies serverDependencies) serverDependencies {
 	production := productionServerDependencies()
 	if dependencies.loadConfig == nil {
@@ -666,6 +1635,9 @@ func withServerDependencyDefaults(dependencies serverDependencies) serverDepende
 	if dependencies.bootstrapTenantsFromFile == nil {
 		dependencies.bootstrapTenantsFromFile = production.bootstrapTenantsFromFile
 	}
+	if dependencies.seedDatabase == nil {
+		dependencies.seedDatabase = production.seedDatabase
+	}
 	if dependencies.newTenantRepository == nil {
 		dependencies.newTenantRepository = production.newTenantRepository
 	}
@@ -678,8 +1650,8 @@ func withServerDependencyDefaults(dependencies serverDependencies) serverDepende
 	if dependencies.newNotificationService == nil {
 		dependencies.newNotificationService = production.newNotificationService
 	}
-	if dependencies.loadTLSConfig == nil {
-		dependencies.loadTLSConfig = production.loadTLSConfig
+	if dependencies.loadCertificate == nil {
+		dependencies.loadCertificate = production.loadCertificate
 	}
 	if dependencies.newSMTPRelay == nil {
 		dependencies.newSMTPRelay = production.newSMTPRelay
@@ -699,6 +1671,9 @@ func withServerDependencyDefaults(dependencies serverDependencies) serverDepende
 	if dependencies.newHTTPServer == nil {
 		dependencies.newHTTPServer = production.newHTTPServer
 	}
+	if dependencies.newQueueIntakeConsumer == nil {
+		dependencies.newQueueIntakeConsumer = production.newQueueIntakeConsumer
+	}
 	if dependencies.listen == nil {
 		dependencies.listen = production.listen
 	}
@@ -759,19 +1734,88 @@ func startHTTPServer(logger *slog.Logger, server httpServerRunner, listenAddr st
 	}()
 }
 
-func serveGRPC(listener net.Listener, notificationSvc service.NotificationService, tenantRepo *tenant.Repository, logger *slog.Logger, requiredToken string) error {
-	grpcServer := grpc.NewServer(
+// verifyTenantsAtStartup resolves every active tenant so undecryptable credentials are
+// logged before traffic arrives. It reports false only when strict and a tenant failed.
+func verifyTenantsAtStartup(ctx context.Context, tenantRepo *tenant.Repository, logger *slog.Logger, strict bool) bool {
+	checked, failures, err := tenantRepo.VerifyActiveTenants(ctx)
+	if err != nil {
+		logger.Error("Failed to list tenants for self-check", "error", err)
+		return !strict
+	}
+	for _, failure := range failures {
+		logger.Error("Tenant self-check failed", "tenant_id", failure.TenantID, "error", failure.Err)
+	}
+	if len(failures) > 0 && strict {
+		logger.Error("Aborting startup: tenants failed the self-check", "failed", len(failures), "checked", checked)
+		return false
+	}
+	logger.Info("Tenant self-check completed", "checked", checked, "failed", len(failures))
+	return true
+}
+
+// watchDrainSignal starts a drain on SIGUSR1. Once the drain completes serveGRPC stops
+// and the server exits through its usual shutdown path.
+func watchDrainSignal(ctx context.Context, notificationSvc service.NotificationService, logger *slog.Logger) {
+	signalCtx, stopSignalWatch := drainSignalContext()
+	defer stopSignalWatch()
+	select {
+	case <-ctx.Done():
+		return
+	case <-signalCtx.Done():
+	}
+	logger.Info("Received SIGUSR1; draining")
+	if _, err := notificationSvc.Drain(ctx); err != nil {
+		logger.Error("Failed to start drain", "error", err)
+	}
+}
+
+// grpcKeepaliveOptions pings idle clients so intermediaries keep their connections open,
+// and tolerates client pings no more often than the configured interval.
+func grpcKeepaliveOptions(keepaliveConfig config.GRPCKeepaliveConfig) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    time.Duration(keepaliveConfig.TimeSec) * time.Second,
+			Timeout: time.Duration(keepaliveConfig.TimeoutSec) * time.Second,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             time.Duration(keepaliveConfig.MinClientPingIntervalSec) * time.Second,
+			PermitWithoutStream: keepaliveConfig.PermitPingsWithoutStream,
+		}),
+	}
+}
+
+func serveGRPC(listener net.Listener, notificationSvc service.NotificationService, queueIntake queueIntakeStatsReporter, tenantRepo *tenant.Repository, logger *slog.Logger, grpcTokens []config.GRPCTokenConfig, keepaliveConfig config.GRPCKeepaliveConfig) error {
+	grpcServer := grpc.NewServer(append(grpcKeepaliveOptions(keepaliveConfig),
 		grpc.MaxRecvMsgSize(grpcutil.MaxMessageSizeBytes),
 		grpc.MaxSendMsgSize(grpcutil.MaxMessageSizeBytes),
 		grpc.ChainUnaryInterceptor(
-			buildAuthInterceptor(logger, requiredToken),
+			buildAuthInterceptor(logger, grpcTokens),
+			buildDebugInterceptor(logger),
 			buildTenantInterceptor(logger, tenantRepo),
+			buildDatabaseBusyInterceptor(logger),
 		),
-	)
+		grpc.ChainStreamInterceptor(
+			buildAuthStreamInterceptor(logger, grpcTokens),
+			buildDebugStreamInterceptor(logger),
+			buildTenantStreamInterceptor(logger, tenantRepo),
+			buildDatabaseBusyStreamInterceptor(logger),
+		),
+	)...)
 	grpcapi.RegisterNotificationServiceServer(grpcServer, &notificationServiceServer{
 		notificationService: notificationSvc,
+		queueIntake:         queueIntake,
 		logger:              logger,
 	})
+	shutdownCtx, stopShutdownWatch := grpcShutdownContext()
+	defer stopShutdownWatch()
+	go func() {
+		select {
+		case <-shutdownCtx.Done():
+		case <-notificationSvc.Drained():
+			logger.Info("Drain complete; stopping gRPC server")
+		}
+		grpcServer.GracefulStop()
+	}()
 	return grpcServer.Serve(listener)
 }
 
@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/tls"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"math"
@@ -17,20 +18,29 @@ import (
 	"time"
 
 	"github.com/glebarez/sqlite"
+	"github.com/tyemirov/pinguin/internal/certreload"
 	"github.com/tyemirov/pinguin/internal/config"
+	"github.com/tyemirov/pinguin/internal/db"
 	"github.com/tyemirov/pinguin/internal/httpapi"
 	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/queueintake"
 	"github.com/tyemirov/pinguin/internal/service"
 	"github.com/tyemirov/pinguin/internal/smtpforwarding"
 	"github.com/tyemirov/pinguin/internal/smtpidentity"
 	"github.com/tyemirov/pinguin/internal/smtpsubmission"
 	"github.com/tyemirov/pinguin/internal/tenant"
+	"github.com/tyemirov/pinguin/pkg/client"
+	"github.com/tyemirov/pinguin/pkg/endpoint"
 	"github.com/tyemirov/pinguin/pkg/grpcapi"
 	sessionvalidator "github.com/tyemirov/tauth/pkg/sessionvalidator"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"gorm.io/gorm"
 )
@@ -43,20 +53,6 @@ const (
 	expectedHandlerNotCalledMessage    = "expected handler not to be called"
 )
 
-func TestDigestForLogging(t *testing.T) {
-	t.Helper()
-	value := digestForLogging("User@example.com ")
-	if value == "" {
-		t.Fatalf("expected digest")
-	}
-	if value != digestForLogging("user@example.com") {
-		t.Fatalf("expected normalized digest")
-	}
-	if digestForLogging("") != "" {
-		t.Fatalf("expected empty digest for empty input")
-	}
-}
-
 func TestMapGrpcAttachments(t *testing.T) {
 	t.Helper()
 	source := []*grpcapi.EmailAttachment{
@@ -70,12 +66,8 @@ func TestMapGrpcAttachments(t *testing.T) {
 	if len(result[0].Data) == 0 || len(source[0].Data) == 0 {
 		t.Fatalf("expected data in attachments")
 	}
-	if &result[0].Data[0] == &source[0].Data[0] {
-		t.Fatalf("expected data copy")
-	}
-	result[0].Data[0] = 'z'
-	if source[0].Data[0] == 'z' {
-		t.Fatalf("expected source data unchanged")
+	if &result[0].Data[0] != &source[0].Data[0] {
+		t.Fatalf("expected the decoded data to be handed over without a copy")
 	}
 	if result[0].Filename != "foo.txt" || result[0].ContentType != "text/plain" {
 		t.Fatalf("unexpected attachment contents %+v", result[0])
@@ -94,9 +86,8 @@ func TestMapModelAttachments(t *testing.T) {
 	if len(result) != 1 {
 		t.Fatalf("expected 1 attachment, got %d", len(result))
 	}
-	result[0].Data[0] = 'z'
-	if source[0].Data[0] == 'z' {
-		t.Fatalf("expected copy to avoid aliasing")
+	if &result[0].Data[0] != &source[0].Data[0] || result[0].Filename != "foo.txt" || result[0].ContentType != "text/plain" {
+		t.Fatalf("expected the attachment mapped without copying its data, got %+v", result[0])
 	}
 	if mapModelAttachments(nil) != nil {
 		t.Fatalf("expected nil for empty input")
@@ -118,8 +109,9 @@ func TestMapGrpcStatuses(t *testing.T) {
 	if statuses[0] != model.StatusQueued || statuses[2] != model.StatusCancelled || statuses[3] != model.StatusErrored {
 		t.Fatalf("unexpected status mapping %v", statuses)
 	}
-	if mapGrpcStatuses([]grpcapi.Status{grpcapi.Status(99)}) != nil {
-		t.Fatalf("expected nil for unsupported statuses")
+	unsupported := mapGrpcStatuses([]grpcapi.Status{grpcapi.Status(99)})
+	if len(unsupported) != 1 || model.CanonicalStatus(unsupported[0]) != "" {
+		t.Fatalf("expected unsupported status to be kept as an unknown value, got %v", unsupported)
 	}
 }
 
@@ -169,15 +161,32 @@ func TestMapModelToGrpcResponse(t *testing.T) {
 		t.Fatalf("unexpected attachments %+v", resp.Attachments)
 	}
 
+	expiresAt := now.Add(-time.Minute)
 	cancelled := mapModelToGrpcResponse(model.NotificationResponse{
 		NotificationType: model.NotificationEmail,
 		Status:           model.StatusCancelled,
+		ExpiresAt:        &expiresAt,
+		CancelReason:     model.CancelReasonExpired,
 		CreatedAt:        now,
 		UpdatedAt:        now,
 	})
+	if cancelled.Rendered != nil {
+		t.Fatalf("expected no rendered content unless requested")
+	}
+	rendered := mapModelToGrpcResponse(model.NotificationResponse{
+		NotificationType: model.NotificationEmail,
+		Status:           model.StatusSent,
+		Rendered:         &model.RenderedContent{Subject: "Final", Message: "Body", MIMEStructure: model.MIMEStructureMixed, SizeBytes: 2048, RenderedAt: now},
+	})
+	if rendered.Rendered.GetSubject() != "Final" || rendered.Rendered.GetMimeStructure() != model.MIMEStructureMixed || rendered.Rendered.GetSizeBytes() != 2048 || !rendered.Rendered.GetRenderedAt().AsTime().Equal(now) {
+		t.Fatalf("unexpected rendered content %+v", rendered.Rendered)
+	}
 	if cancelled.Status != grpcapi.Status_CANCELLED {
 		t.Fatalf("expected cancelled status, got %v", cancelled.Status)
 	}
+	if cancelled.ExpiresAt == nil || !cancelled.ExpiresAt.AsTime().Equal(expiresAt) || cancelled.CancelReason != model.CancelReasonExpired {
+		t.Fatalf("expected expiry fields to map, got %v/%q", cancelled.ExpiresAt, cancelled.CancelReason)
+	}
 	unknown := mapModelToGrpcResponse(model.NotificationResponse{
 		NotificationType: "push",
 		Status:           "mystery",
@@ -192,7 +201,7 @@ func TestMapModelToGrpcResponse(t *testing.T) {
 func TestBuildAuthInterceptor(t *testing.T) {
 	t.Helper()
 	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
-	interceptor := buildAuthInterceptor(logger, "token")
+	interceptor := buildAuthInterceptor(logger, []config.GRPCTokenConfig{{Token: "token", Scope: config.GRPCTokenScopeWrite}})
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return "ok", nil
 	}
@@ -288,7 +297,7 @@ func TestNotificationServiceServerHandlers(testHandle *testing.T) {
 		notificationService: service,
 		logger:              slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
 	}
-	ctx := context.Background()
+	ctx := fullAccessGRPCContext()
 
 	sendResponse, sendErr := server.SendNotification(ctx, &grpcapi.NotificationRequest{
 		NotificationType: grpcapi.NotificationType_EMAIL,
@@ -317,6 +326,9 @@ func TestNotificationServiceServerHandlers(testHandle *testing.T) {
 	if service.statusID != "notif-one" {
 		testHandle.Fatalf("expected status id recorded")
 	}
+	if _, renderedErr := server.GetNotificationStatus(ctx, &grpcapi.GetNotificationStatusRequest{NotificationId: "notif-one", IncludeRendered: true}); renderedErr != nil || service.renderedID != "notif-one" {
+		testHandle.Fatalf("expected include_rendered to load rendered content, err=%v", renderedErr)
+	}
 
 	listResponse, listErr := server.ListNotifications(ctx, &grpcapi.ListNotificationsRequest{Statuses: []grpcapi.Status{grpcapi.Status_QUEUED}})
 	if listErr != nil {
@@ -341,13 +353,16 @@ func TestNotificationServiceServerHandlers(testHandle *testing.T) {
 		testHandle.Fatalf("unexpected reschedule capture")
 	}
 
-	cancelResponse, cancelErr := server.CancelNotification(ctx, &grpcapi.CancelNotificationRequest{NotificationId: "notif-one"})
+	cancelResponse, cancelErr := server.CancelNotification(ctx, &grpcapi.CancelNotificationRequest{NotificationId: "notif-one", Reason: "duplicate"})
 	if cancelErr != nil || cancelResponse.GetNotificationId() != "notif-one" {
 		testHandle.Fatalf("cancel response=%+v err=%v", cancelResponse, cancelErr)
 	}
 	if service.cancelID != "notif-one" {
 		testHandle.Fatalf("expected cancel id recorded")
 	}
+	if service.cancelReason != "duplicate" || service.cancelActor != model.CancelActorGRPC {
+		testHandle.Fatalf("unexpected cancel reason %q or actor %q", service.cancelReason, service.cancelActor)
+	}
 }
 
 func TestNotificationServiceServerValidationAndServiceErrors(testHandle *testing.T) {
@@ -360,7 +375,7 @@ func TestNotificationServiceServerValidationAndServiceErrors(testHandle *testing
 		},
 		logger: slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
 	}
-	ctx := context.Background()
+	ctx := fullAccessGRPCContext()
 
 	testCases := []struct {
 		name string
@@ -381,6 +396,47 @@ func TestNotificationServiceServerValidationAndServiceErrors(testHandle *testing
 			})
 			return err
 		}, code: codes.InvalidArgument},
+		{name: "send subject header injection", call: func() error {
+			_, err := server.SendNotification(ctx, &grpcapi.NotificationRequest{
+				NotificationType: grpcapi.NotificationType_EMAIL,
+				Recipient:        "user@example.com",
+				Subject:          "Subject\r\nBcc: attacker@example.com",
+				Message:          "Body",
+			})
+			return err
+		}, code: codes.InvalidArgument},
+		{name: "send invalid calendar event", call: func() error {
+			_, err := server.SendNotification(ctx, &grpcapi.NotificationRequest{
+				NotificationType: grpcapi.NotificationType_EMAIL,
+				Recipient:        "user@example.com",
+				Subject:          "Invite",
+				Message:          "Body",
+				CalendarEvent:    &grpcapi.CalendarEvent{Ics: "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"},
+			})
+			return err
+		}, code: codes.InvalidArgument},
+		{name: "send invalid expiry timestamp", call: func() error {
+			_, err := server.SendNotification(ctx, &grpcapi.NotificationRequest{
+				NotificationType: grpcapi.NotificationType_EMAIL,
+				Recipient:        "user@example.com",
+				Subject:          "Subject",
+				Message:          "Body",
+				ExpiresAt:        &timestamppb.Timestamp{Seconds: math.MaxInt64},
+			})
+			return err
+		}, code: codes.InvalidArgument},
+		{name: "send expiry before schedule", call: func() error {
+			scheduledTime := time.Now().Add(time.Hour)
+			_, err := server.SendNotification(ctx, &grpcapi.NotificationRequest{
+				NotificationType: grpcapi.NotificationType_EMAIL,
+				Recipient:        "user@example.com",
+				Subject:          "Subject",
+				Message:          "Body",
+				ScheduledTime:    timestamppb.New(scheduledTime),
+				ExpiresAt:        timestamppb.New(scheduledTime.Add(-time.Minute)),
+			})
+			return err
+		}, code: codes.InvalidArgument},
 		{name: "send invalid model request", call: func() error {
 			_, err := server.SendNotification(ctx, &grpcapi.NotificationRequest{NotificationType: grpcapi.NotificationType_EMAIL})
 			return err
@@ -420,13 +476,6 @@ func TestNotificationServiceServerValidationAndServiceErrors(testHandle *testing
 			})
 			return err
 		}, code: codes.InvalidArgument},
-		{name: "reschedule past time", call: func() error {
-			_, err := server.RescheduleNotification(ctx, &grpcapi.RescheduleNotificationRequest{
-				NotificationId: "notif",
-				ScheduledTime:  timestamppb.New(time.Now().Add(-time.Hour)),
-			})
-			return err
-		}, code: codes.InvalidArgument},
 		{name: "reschedule service error", call: func() error {
 			_, err := server.RescheduleNotification(ctx, &grpcapi.RescheduleNotificationRequest{
 				NotificationId: "notif",
@@ -457,181 +506,844 @@ func TestNotificationServiceServerValidationAndServiceErrors(testHandle *testing
 	}
 }
 
-func TestSMTPPublicSettings(testHandle *testing.T) {
-	testHandle.Helper()
-	startTLS := smtpPublicSettings(configSMTPSubmission(":2525", ""))
-	if startTLS.Port != 2525 || startTLS.SecurityMode != "starttls" {
-		testHandle.Fatalf("unexpected starttls settings %+v", startTLS)
-	}
-	implicitTLS := smtpPublicSettings(configSMTPSubmission("", "127.0.0.1:2465"))
-	if implicitTLS.Port != 2465 || implicitTLS.SecurityMode != "ssl" {
-		testHandle.Fatalf("unexpected implicit tls settings %+v", implicitTLS)
-	}
-	caddyTerminated := configSMTPSubmission(":587", "")
-	caddyTerminated.PublicPort = 465
-	caddyTerminated.PublicSecurityMode = "ssl"
-	publicSettings := smtpPublicSettings(caddyTerminated)
-	if publicSettings.Port != 465 || publicSettings.SecurityMode != "ssl" {
-		testHandle.Fatalf("unexpected caddy-terminated public settings %+v", publicSettings)
-	}
-	defaultStartTLS := smtpPublicSettings(configSMTPSubmission("bad", ""))
-	if defaultStartTLS.Port != 587 {
-		testHandle.Fatalf("expected starttls fallback port, got %d", defaultStartTLS.Port)
-	}
-	defaultImplicitTLS := smtpPublicSettings(configSMTPSubmission("", "bad"))
-	if defaultImplicitTLS.Port != 465 {
-		testHandle.Fatalf("expected implicit tls fallback port, got %d", defaultImplicitTLS.Port)
+func TestNotificationServiceServerMapsDispatchCapacityToResourceExhausted(testHandle *testing.T) {
+	server := &notificationServiceServer{
+		notificationService: &recordingNotificationService{
+			err: fmt.Errorf("%w: limit 1 reached", service.ErrDispatchCapacityExhausted),
+		},
+		logger: slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
 	}
-	if smtpPortFromAddr(" ", 25) != 25 {
-		testHandle.Fatalf("expected blank address fallback")
+	_, err := server.SendNotification(fullAccessGRPCContext(), &grpcapi.NotificationRequest{
+		NotificationType: grpcapi.NotificationType_EMAIL,
+		Recipient:        "user@example.com",
+		Subject:          "Subject",
+		Message:          "Body",
+	})
+	if status.Code(err) != codes.ResourceExhausted {
+		testHandle.Fatalf("expected ResourceExhausted, got %v", err)
 	}
 }
 
-func TestRunServerSuccessWithInlineTenants(testHandle *testing.T) {
-	testHandle.Helper()
-	cfg := serverTestConfig()
-	state, dependencies := newServerTestDependencies(cfg)
-
-	if exitCode := runServer(nil, dependencies); exitCode != 0 {
-		testHandle.Fatalf("expected success exit code, got %d", exitCode)
+func TestNotificationServiceServerMapsScheduleInPastToInvalidArgument(testHandle *testing.T) {
+	notificationService := &recordingNotificationService{err: service.ErrScheduleInPast}
+	server := &notificationServiceServer{
+		notificationService: notificationService,
+		logger:              slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
 	}
-	if !state.bootstrapCalled || state.bootstrapFileCalled {
-		testHandle.Fatalf("expected inline bootstrap, state=%+v", state)
+	_, sendErr := server.SendNotification(fullAccessGRPCContext(), &grpcapi.NotificationRequest{
+		NotificationType: grpcapi.NotificationType_EMAIL,
+		Recipient:        "user@example.com",
+		Subject:          "Subject",
+		Message:          "Body",
+	})
+	_, rescheduleErr := server.RescheduleNotification(fullAccessGRPCContext(), &grpcapi.RescheduleNotificationRequest{
+		NotificationId: "notif",
+		ScheduledTime:  timestamppb.New(time.Now().Add(-time.Hour)),
+	})
+	for _, err := range []error{sendErr, rescheduleErr} {
+		if status.Code(err) != codes.InvalidArgument || status.Convert(err).Message() != scheduledTimeFutureMessage {
+			testHandle.Fatalf("expected InvalidArgument %q, got %v", scheduledTimeFutureMessage, err)
+		}
 	}
-	if !state.grpcServed {
-		testHandle.Fatalf("expected grpc server to be served")
+	if notificationService.rescheduleID != "notif" {
+		testHandle.Fatalf("expected the service to decide the past schedule, got reschedule id %q", notificationService.rescheduleID)
 	}
 }
 
-func TestRunServerPublishesGRPCReadyEventAfterListenerBind(testHandle *testing.T) {
-	testHandle.Helper()
-	cfg := serverTestConfig()
-	state, dependencies := newServerTestDependencies(cfg)
-	var logOutput bytes.Buffer
-	dependencies.newLogger = func(string) *slog.Logger {
-		return slog.New(slog.NewTextHandler(&logOutput, &slog.HandlerOptions{}))
-	}
-	dependencies.listen = func(string, string) (net.Listener, error) {
-		if strings.Contains(logOutput.String(), "event=pinguin.grpc.ready") {
-			testHandle.Fatal("gRPC readiness event was published before the listener bound")
-		}
-		return fakeListener{}, nil
+func TestNotificationServiceServerMapsReportingAndDrainErrors(testHandle *testing.T) {
+	server := &notificationServiceServer{
+		notificationService: &recordingNotificationService{err: service.ErrTenantInventoryUnavailable},
+		logger:              slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
 	}
-	dependencies.serveGRPC = func(net.Listener, service.NotificationService, *tenant.Repository, *slog.Logger, string) error {
-		if !strings.Contains(logOutput.String(), "event=pinguin.grpc.ready") {
-			testHandle.Fatalf("gRPC readiness event was not published after listener bind:\n%s", logOutput.String())
+	adminContext := withGRPCGrant(context.Background(), grpcGrant{scope: config.GRPCTokenScopeAdmin})
+	for name, call := range map[string]func() error{
+		"GetCostSummary": func() error {
+			_, err := server.GetCostSummary(fullAccessGRPCContext(), &grpcapi.CostSummaryRequest{StartTime: timestamppb.New(time.Now().Add(-time.Hour)), EndTime: timestamppb.Now()})
+			return err
+		},
+		"GetCapabilities": func() error {
+			_, err := server.GetCapabilities(fullAccessGRPCContext(), &grpcapi.GetCapabilitiesRequest{})
+			return err
+		},
+		"DrainInstance": func() error {
+			_, err := server.DrainInstance(adminContext, &grpcapi.DrainInstanceRequest{})
+			return err
+		},
+		"GetDrainStatus": func() error {
+			_, err := server.GetDrainStatus(adminContext, &grpcapi.GetDrainStatusRequest{})
+			return err
+		},
+	} {
+		if err := call(); status.Code(err) != codes.FailedPrecondition {
+			testHandle.Fatalf("%s: expected FailedPrecondition, got %v", name, err)
 		}
-		state.grpcServed = true
-		return nil
 	}
+}
 
-	if exitCode := runServer(nil, dependencies); exitCode != 0 {
-		testHandle.Fatalf("expected success exit code, got %d", exitCode)
+func TestNotificationServiceServerMapsImmediateDispatchFailureToUnavailable(testHandle *testing.T) {
+	notificationService := &recordingNotificationService{err: fmt.Errorf("%w: notification notif-1: %w", service.ErrImmediateDispatchFailed, errors.New("smtp down"))}
+	server := &notificationServiceServer{
+		notificationService: notificationService,
+		logger:              slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
 	}
-	if !state.grpcServed {
-		testHandle.Fatal("expected gRPC server to be served")
+	failOnImmediateError := true
+	_, err := server.SendNotification(fullAccessGRPCContext(), &grpcapi.NotificationRequest{
+		NotificationType:     grpcapi.NotificationType_EMAIL,
+		Recipient:            "user@example.com",
+		Subject:              "Subject",
+		Message:              "Body",
+		FailOnImmediateError: &failOnImmediateError,
+	})
+	if status.Code(err) != codes.Unavailable || !strings.Contains(status.Convert(err).Message(), "notif-1") {
+		testHandle.Fatalf("expected Unavailable naming the notification, got %v", err)
+	}
+	if fail, overridden := notificationService.sentRequest.FailOnImmediateError(); !overridden || !fail {
+		testHandle.Fatalf("expected the request override forwarded, got fail=%v overridden=%v", fail, overridden)
 	}
 }
 
-func TestRunServerDoesNotPublishGRPCReadyEventWhenListenerBindFails(testHandle *testing.T) {
-	testHandle.Helper()
-	cfg := serverTestConfig()
-	_, dependencies := newServerTestDependencies(cfg)
-	var logOutput bytes.Buffer
-	dependencies.newLogger = func(string) *slog.Logger {
-		return slog.New(slog.NewTextHandler(&logOutput, &slog.HandlerOptions{}))
+func TestNotificationServiceServerSendsNotificationGroup(testHandle *testing.T) {
+	notificationService := &recordingNotificationService{groupResponse: model.NotificationGroupResponse{
+		GroupID: "group-1",
+		Status:  model.StatusErrored,
+		Notifications: []model.NotificationResponse{
+			{NotificationID: "notif-email", GroupID: "group-1", NotificationType: model.NotificationEmail, Status: model.StatusErrored},
+			{NotificationID: "notif-sms", GroupID: "group-1", NotificationType: model.NotificationSMS, Status: model.StatusSent},
+		},
+	}}
+	server := &notificationServiceServer{
+		notificationService: notificationService,
+		logger:              slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
 	}
-	dependencies.listen = func(string, string) (net.Listener, error) {
-		return nil, errors.New("bind failed")
+	response, err := server.SendNotificationGroup(fullAccessGRPCContext(), &grpcapi.NotificationGroupRequest{
+		Subject: "Outage",
+		Message: "Database down",
+		Channels: []*grpcapi.NotificationChannel{
+			{NotificationType: grpcapi.NotificationType_EMAIL, Recipient: "user@example.com"},
+			{NotificationType: grpcapi.NotificationType_SMS, Recipient: "+15555550100", Message: "DB down"},
+		},
+	})
+	if err != nil {
+		testHandle.Fatalf("send group: %v", err)
 	}
-
-	if exitCode := runServer(nil, dependencies); exitCode != 1 {
-		testHandle.Fatalf("expected failure exit code, got %d", exitCode)
+	if response.GetGroupId() != "group-1" || response.GetStatus() != grpcapi.Status_ERRORED || len(response.GetNotifications()) != 2 {
+		testHandle.Fatalf("unexpected group response %+v", response)
 	}
-	if strings.Contains(logOutput.String(), "event=pinguin.grpc.ready") {
-		testHandle.Fatalf("gRPC readiness event was published after listener bind failed:\n%s", logOutput.String())
+	if response.GetNotifications()[1].GetGroupId() != "group-1" || response.GetNotifications()[1].GetStatus() != grpcapi.Status_SENT {
+		testHandle.Fatalf("expected per-channel ids and statuses, got %+v", response.GetNotifications()[1])
+	}
+	channels := notificationService.groupRequest.Channels()
+	if len(channels) != 2 || channels[0].Subject() != "Outage" || channels[0].Message() != "Database down" {
+		testHandle.Fatalf("expected the email channel to inherit the group content, got %+v", channels)
+	}
+	if channels[1].Subject() != "" || channels[1].Message() != "DB down" {
+		testHandle.Fatalf("expected the SMS channel to keep its own message and no subject, got %+v", channels[1])
 	}
 }
 
-func TestRunServerStartsWebAndSMTPSubmission(testHandle *testing.T) {
-	testHandle.Helper()
-	cfg := serverTestConfig()
-	cfg.TenantBootstrap = tenant.BootstrapConfig{}
-	cfg.TenantConfigPath = "tenants.yml"
-	cfg.WebInterfaceEnabled = true
-	cfg.HTTPListenAddr = "127.0.0.1:8080"
-	cfg.HTTPTrustedProxies = []string{"127.0.0.1"}
-	cfg.TAuthSigningKey = "signing-key"
-	cfg.TAuthCookieName = "app_session"
-	cfg.SMTPSubmission = config.SMTPSubmissionConfig{
-		Enabled:           true,
-		Hostname:          "smtp.example.com",
-		ListenAddr:        ":587",
-		TLSListenAddr:     ":465",
-		TLSCertPath:       "cert.pem",
-		TLSKeyPath:        "key.pem",
-		MaxMessageBytes:   1024,
-		MaxRecipients:     2,
-		AllowInsecureAuth: true,
+func TestNotificationServiceServerRejectsInvalidGroupChannels(testHandle *testing.T) {
+	server := &notificationServiceServer{
+		notificationService: &recordingNotificationService{},
+		logger:              slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
 	}
-	state, dependencies := newServerTestDependencies(cfg)
-	state.httpServer.shutdownErr = errors.New("shutdown failed")
-
-	if exitCode := runServer(nil, dependencies); exitCode != 0 {
-		testHandle.Fatalf("expected success exit code, got %d", exitCode)
+	testCases := []struct {
+		name     string
+		channels []*grpcapi.NotificationChannel
+		expected string
+	}{
+		{name: "no channels", expected: model.ErrNotificationGroupChannelsRequired.Error()},
+		{name: "email without subject", channels: []*grpcapi.NotificationChannel{
+			{NotificationType: grpcapi.NotificationType_SMS, Recipient: "+15555550100", Message: "Body"},
+			{NotificationType: grpcapi.NotificationType_EMAIL, Recipient: "user@example.com", Message: "Body"},
+		}, expected: "channel 2"},
+		{name: "sms with attachment", channels: []*grpcapi.NotificationChannel{
+			{NotificationType: grpcapi.NotificationType_SMS, Recipient: "+15555550100", Message: "Body", Attachments: []*grpcapi.EmailAttachment{{Filename: "a.txt", Data: []byte("a")}}},
+		}, expected: "channel 1: " + model.ErrNotificationAttachmentsNotAllowed.Error()},
 	}
-	waitForClosed(testHandle, state.smtpStarter.started)
-	waitForClosed(testHandle, state.httpServer.started)
-	if !state.bootstrapFileCalled || !state.tlsLoaded {
-		testHandle.Fatalf("expected file/bootstrap/tls setup, state=%+v", state)
+	for _, testCase := range testCases {
+		testHandle.Run(testCase.name, func(testHandle *testing.T) {
+			_, err := server.SendNotificationGroup(fullAccessGRPCContext(), &grpcapi.NotificationGroupRequest{Channels: testCase.channels})
+			if status.Code(err) != codes.InvalidArgument || !strings.Contains(status.Convert(err).Message(), testCase.expected) {
+				testHandle.Fatalf("expected InvalidArgument mentioning %q, got %v", testCase.expected, err)
+			}
+		})
 	}
-	if len(state.httpConfig.TrustedProxies) != 1 || state.httpConfig.TrustedProxies[0] != "127.0.0.1" {
-		testHandle.Fatalf("expected trusted proxy config to reach HTTP server, got %+v", state.httpConfig.TrustedProxies)
+}
+
+func TestNotificationServiceServerGetNotificationGroup(testHandle *testing.T) {
+	notificationService := &recordingNotificationService{err: fmt.Errorf("%w: group-x", model.ErrNotificationGroupNotFound)}
+	server := &notificationServiceServer{
+		notificationService: notificationService,
+		logger:              slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
 	}
-	if !state.httpServer.shutdownCalled {
-		testHandle.Fatalf("expected HTTP shutdown")
+	if _, err := server.GetNotificationGroup(fullAccessGRPCContext(), &grpcapi.GetNotificationGroupRequest{}); status.Code(err) != codes.InvalidArgument {
+		testHandle.Fatalf("expected InvalidArgument for a missing group id, got %v", err)
 	}
-	if state.smtpConfig.TLSConfig == nil || state.smtpConfig.Relay == nil {
-		testHandle.Fatalf("expected SMTP config to include TLS and relay")
+	if _, err := server.GetNotificationGroup(fullAccessGRPCContext(), &grpcapi.GetNotificationGroupRequest{GroupId: " group-x "}); status.Code(err) != codes.NotFound {
+		testHandle.Fatalf("expected NotFound, got %v", err)
 	}
-	if state.smtpConfig.CommandTimeout != 30*time.Second {
-		testHandle.Fatalf("expected SMTP command timeout from operation timeout, got %s", state.smtpConfig.CommandTimeout)
+	if notificationService.groupID != "group-x" {
+		testHandle.Fatalf("expected the trimmed group id forwarded, got %q", notificationService.groupID)
 	}
 }
 
-func TestRunServerStartsSMTPForwarding(testHandle *testing.T) {
-	testHandle.Helper()
-	cfg := serverTestConfig()
-	cfg.SMTPForwarding = config.SMTPForwardingConfig{
-		Enabled:         true,
-		Hostname:        "mx.pinguin.mprlab.com",
-		ListenAddr:      ":25",
-		MaxMessageBytes: 2048,
-		MaxRecipients:   3,
-		Relay: config.SMTPForwardingRelayConfig{
-			Host:     "relay.example.com",
-			Port:     587,
-			Username: "relay-user",
-			Password: "relay-pass",
+func TestNotificationServiceServerAttachesCalendarEvent(testHandle *testing.T) {
+	notificationService := &recordingNotificationService{response: model.NotificationResponse{NotificationID: "notif-invite"}}
+	server := &notificationServiceServer{
+		notificationService: notificationService,
+		logger:              slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
+	}
+	_, err := server.SendNotification(fullAccessGRPCContext(), &grpcapi.NotificationRequest{
+		NotificationType: grpcapi.NotificationType_EMAIL,
+		Recipient:        "user@example.com",
+		Subject:          "Planning cancelled",
+		Message:          "Body",
+		CalendarEvent: &grpcapi.CalendarEvent{
+			Ics:    "BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:meeting-1\nDTSTART:20260501T090000Z\nEND:VEVENT\nEND:VCALENDAR\n",
+			Method: "CANCEL",
 		},
+	})
+	if err != nil {
+		testHandle.Fatalf("send notification: %v", err)
 	}
-	state, dependencies := newServerTestDependencies(cfg)
+	attachments := notificationService.sentRequest.Attachments()
+	if len(attachments) != 1 || attachments[0].Filename != model.CalendarInviteFilename {
+		testHandle.Fatalf("expected the invite as the only attachment, got %+v", attachments)
+	}
+	if method, isInvite := model.CalendarInviteMethod(attachments[0].ContentType); !isInvite || method != model.CalendarMethodCancel {
+		testHandle.Fatalf("expected a CANCEL invite, got %q", attachments[0].ContentType)
+	}
+}
 
-	if exitCode := runServer(nil, dependencies); exitCode != 0 {
-		testHandle.Fatalf("expected success exit code, got %d", exitCode)
+func TestNotificationServiceServerPassesEncryptedContentThrough(testHandle *testing.T) {
+	notificationService := &recordingNotificationService{response: model.NotificationResponse{NotificationID: "notif-sealed", ContentEncrypted: true}}
+	server := &notificationServiceServer{
+		notificationService: notificationService,
+		logger:              slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
 	}
-	waitForClosed(testHandle, state.smtpForwardingStarter.started)
-	if state.smtpForwardingConfig.Hostname != "mx.pinguin.mprlab.com" {
-		testHandle.Fatalf("unexpected forwarding hostname %s", state.smtpForwardingConfig.Hostname)
+	entity := "Content-Type: application/pkcs7-mime; smime-type=enveloped-data\r\nContent-Transfer-Encoding: base64\r\n\r\nMIAGCSqG\r\n"
+	response, err := server.SendNotification(fullAccessGRPCContext(), &grpcapi.NotificationRequest{
+		NotificationType: grpcapi.NotificationType_EMAIL,
+		Recipient:        "user@example.com",
+		Subject:          "Sealed",
+		Message:          entity,
+		ContentEncrypted: true,
+	})
+	if err != nil {
+		testHandle.Fatalf("send notification: %v", err)
 	}
-	if state.smtpForwardingConfig.CommandTimeout != 30*time.Second {
-		testHandle.Fatalf("expected forwarding timeout from operation timeout, got %s", state.smtpForwardingConfig.CommandTimeout)
+	if !notificationService.sentRequest.ContentEncrypted() || notificationService.sentRequest.Message() != entity || !response.GetContentEncrypted() {
+		testHandle.Fatalf("expected the entity to reach the service unchanged and flagged")
 	}
-	if state.smtpForwardingConfig.Forwarder == nil {
-		testHandle.Fatalf("expected forwarding config to include forwarder")
+
+	incompatible := map[string]*grpcapi.NotificationRequest{
+		"plain body": {NotificationType: grpcapi.NotificationType_EMAIL, Recipient: "user@example.com", Subject: "Sealed", Message: "Body", ContentEncrypted: true},
+		"calendar event": {NotificationType: grpcapi.NotificationType_EMAIL, Recipient: "user@example.com", Subject: "Sealed", Message: entity, ContentEncrypted: true,
+			CalendarEvent: &grpcapi.CalendarEvent{Ics: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:meeting-1\nDTSTART:20260501T090000Z\nEND:VEVENT\nEND:VCALENDAR\n"}},
+		"sms truncation": {NotificationType: grpcapi.NotificationType_SMS, Recipient: "+15551234567", Message: "hQEMA", SmsOverflowPolicy: "truncate", ContentEncrypted: true},
 	}
-	if state.smtpForwardingConfig.RouteResolver == nil {
-		testHandle.Fatalf("expected forwarding config to include route resolver")
+	for name, request := range incompatible {
+		if _, err := server.SendNotification(fullAccessGRPCContext(), request); status.Code(err) != codes.InvalidArgument {
+			testHandle.Fatalf("%s: expected InvalidArgument, got %v", name, err)
+		}
+	}
+}
+
+func TestNotificationServiceServerPassesSenderOverridesThrough(testHandle *testing.T) {
+	notificationService := &recordingNotificationService{response: model.NotificationResponse{NotificationID: "notif-1", ReplyTo: "agent@support.example", FromDisplayName: "Ada"}}
+	server := &notificationServiceServer{
+		notificationService: notificationService,
+		logger:              slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
+	}
+	request := &grpcapi.NotificationRequest{
+		NotificationType: grpcapi.NotificationType_EMAIL,
+		Recipient:        "user@example.com",
+		Subject:          "Subject",
+		Message:          "Body",
+		ReplyTo:          "agent@support.example",
+		FromDisplayName:  "Ada",
+	}
+	response, err := server.SendNotification(fullAccessGRPCContext(), request)
+	if err != nil {
+		testHandle.Fatalf("send notification: %v", err)
+	}
+	if overrides := notificationService.sentRequest.SenderOverrides(); overrides.ReplyTo != "agent@support.example" || overrides.FromDisplayName != "Ada" {
+		testHandle.Fatalf("expected the overrides to reach the service, got %+v", overrides)
+	}
+	if response.GetReplyTo() != "agent@support.example" || response.GetFromDisplayName() != "Ada" {
+		testHandle.Fatalf("expected the response to carry the overrides, got %+v", response)
+	}
+
+	if _, err := server.SendNotification(fullAccessGRPCContext(), &grpcapi.NotificationRequest{NotificationType: grpcapi.NotificationType_SMS, Recipient: "+15551234567", Message: "Body", ReplyTo: "agent@support.example"}); status.Code(err) != codes.InvalidArgument {
+		testHandle.Fatalf("expected an SMS reply_to to be InvalidArgument, got %v", err)
+	}
+	notificationService.err = fmt.Errorf("%w: reply_to domain %q is not allowed for this tenant", model.ErrNotificationSenderOverrideForbidden, "support.example")
+	_, err = server.SendNotification(fullAccessGRPCContext(), request)
+	if status.Code(err) != codes.InvalidArgument || !strings.Contains(status.Convert(err).Message(), model.ErrNotificationSenderOverrideForbidden.Error()) {
+		testHandle.Fatalf("expected a policy violation to be InvalidArgument carrying its code, got %v", err)
+	}
+}
+
+func TestNotificationServiceServerMapsDrainingToUnavailableWithRetryHint(testHandle *testing.T) {
+	server := &notificationServiceServer{
+		notificationService: &recordingNotificationService{err: service.ErrDraining},
+		logger:              slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
+	}
+	_, err := server.SendNotification(fullAccessGRPCContext(), &grpcapi.NotificationRequest{
+		NotificationType: grpcapi.NotificationType_EMAIL,
+		Recipient:        "user@example.com",
+		Subject:          "Subject",
+		Message:          "Body",
+	})
+	if status.Code(err) != codes.Unavailable {
+		testHandle.Fatalf("expected Unavailable, got %v", err)
+	}
+	details := status.Convert(err).Details()
+	if len(details) != 1 {
+		testHandle.Fatalf("expected a retry hint, got %v", details)
+	}
+	if retryInfo, ok := details[0].(*errdetails.RetryInfo); !ok || retryInfo.GetRetryDelay().AsDuration() != drainingRetryDelay {
+		testHandle.Fatalf("expected RetryInfo of %s, got %v", drainingRetryDelay, details[0])
+	}
+}
+
+func TestDatabaseBusyInterceptorMapsToUnavailableWithRetryHint(testHandle *testing.T) {
+	interceptor := buildDatabaseBusyInterceptor(slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})))
+	busyErr := fmt.Errorf("list notifications: %w", model.ErrDatabaseBusy)
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, func(context.Context, interface{}) (interface{}, error) {
+		return nil, busyErr
+	})
+	if status.Code(err) != codes.Unavailable {
+		testHandle.Fatalf("expected Unavailable, got %v", err)
+	}
+	details := status.Convert(err).Details()
+	if len(details) != 1 {
+		testHandle.Fatalf("expected a retry hint, got %v", details)
+	}
+	if retryInfo, ok := details[0].(*errdetails.RetryInfo); !ok || retryInfo.GetRetryDelay().AsDuration() != databaseBusyRetryDelay {
+		testHandle.Fatalf("expected RetryInfo of %s, got %v", databaseBusyRetryDelay, details[0])
+	}
+
+	otherErr := status.Error(codes.NotFound, "missing")
+	if _, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, func(context.Context, interface{}) (interface{}, error) {
+		return nil, otherErr
+	}); err != otherErr {
+		testHandle.Fatalf("expected other errors to pass through, got %v", err)
+	}
+
+	streamInterceptor := buildDatabaseBusyStreamInterceptor(slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})))
+	if err := streamInterceptor(nil, nil, &grpc.StreamServerInfo{}, func(interface{}, grpc.ServerStream) error {
+		return busyErr
+	}); status.Code(err) != codes.Unavailable {
+		testHandle.Fatalf("expected Unavailable for a streaming call, got %v", err)
+	}
+	if err := streamInterceptor(nil, nil, &grpc.StreamServerInfo{}, func(interface{}, grpc.ServerStream) error {
+		return otherErr
+	}); err != otherErr {
+		testHandle.Fatalf("expected other streaming errors to pass through, got %v", err)
+	}
+}
+
+func TestDatabaseBusyInterceptorMapsQueryTimeoutToDeadlineExceeded(testHandle *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	timeoutErr := fmt.Errorf("list notifications: %w: %w", model.ErrQueryTimeout, context.DeadlineExceeded)
+	_, err := buildDatabaseBusyInterceptor(logger)(context.Background(), nil, &grpc.UnaryServerInfo{}, func(context.Context, interface{}) (interface{}, error) {
+		return nil, timeoutErr
+	})
+	if status.Code(err) != codes.DeadlineExceeded || strings.Contains(err.Error(), "list notifications") {
+		testHandle.Fatalf("expected DeadlineExceeded without the internal error, got %v", err)
+	}
+	if err := buildDatabaseBusyStreamInterceptor(logger)(nil, nil, &grpc.StreamServerInfo{}, func(interface{}, grpc.ServerStream) error {
+		return timeoutErr
+	}); status.Code(err) != codes.DeadlineExceeded {
+		testHandle.Fatalf("expected DeadlineExceeded for a streaming call, got %v", err)
+	}
+}
+
+func TestQueryTimeoutsUseDefaultsForZero(testHandle *testing.T) {
+	defaults := queryTimeouts(config.Config{})
+	if defaults.Point != time.Duration(config.DefaultQueryTimeoutSec)*time.Second || defaults.Aggregate != time.Duration(config.DefaultAggregateQueryTimeoutSec)*time.Second {
+		testHandle.Fatalf("unexpected default query timeouts %+v", defaults)
+	}
+	configured := queryTimeouts(config.Config{QueryTimeoutSec: 3, AggregateQueryTimeoutSec: 30})
+	if configured.Point != 3*time.Second || configured.Aggregate != 30*time.Second {
+		testHandle.Fatalf("unexpected configured query timeouts %+v", configured)
+	}
+}
+
+func TestNotificationServiceServerDrainInstanceRequiresAdminScope(testHandle *testing.T) {
+	startedAt := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	notificationService := &recordingNotificationService{drainStatus: service.DrainStatus{
+		Draining:        true,
+		StartedAt:       startedAt,
+		Deadline:        startedAt.Add(10 * time.Minute),
+		RemainingQueued: 7,
+	}}
+	server := &notificationServiceServer{
+		notificationService: notificationService,
+		logger:              slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
+	}
+	if _, err := server.DrainInstance(fullAccessGRPCContext(), &grpcapi.DrainInstanceRequest{}); status.Code(err) != codes.PermissionDenied || notificationService.drainCalls != 0 {
+		testHandle.Fatalf("expected a write token to be denied, got %v after %d drains", err, notificationService.drainCalls)
+	}
+	adminContext := withGRPCGrant(context.Background(), grpcGrant{scope: config.GRPCTokenScopeAdmin})
+	response, err := server.DrainInstance(adminContext, &grpcapi.DrainInstanceRequest{})
+	if err != nil || notificationService.drainCalls != 1 {
+		testHandle.Fatalf("DrainInstance error: %v after %d drains", err, notificationService.drainCalls)
+	}
+	if !response.GetDraining() || response.GetDrained() || response.GetRemainingQueued() != 7 || !response.GetDeadline().AsTime().Equal(startedAt.Add(10*time.Minute)) {
+		testHandle.Fatalf("unexpected drain status %+v", response)
+	}
+	progress, err := server.GetDrainStatus(adminContext, &grpcapi.GetDrainStatusRequest{})
+	if err != nil || progress.GetRemainingQueued() != 7 || notificationService.drainCalls != 1 {
+		testHandle.Fatalf("expected GetDrainStatus to report progress without draining again, got %+v (%v)", progress, err)
+	}
+
+	notificationService.drainStatus = service.DrainStatus{}
+	idle, err := server.GetDrainStatus(adminContext, &grpcapi.GetDrainStatusRequest{})
+	if err != nil || idle.GetDraining() || idle.GetStartedAt() != nil {
+		testHandle.Fatalf("expected an empty status before draining, got %+v (%v)", idle, err)
+	}
+}
+
+func TestWatchDrainSignalStartsDrain(testHandle *testing.T) {
+	signalCtx, deliverSignal := context.WithCancel(context.Background())
+	originalDrainSignalContext := drainSignalContext
+	drainSignalContext = func() (context.Context, context.CancelFunc) { return signalCtx, deliverSignal }
+	testHandle.Cleanup(func() { drainSignalContext = originalDrainSignalContext })
+
+	stubService := &recordingNotificationService{}
+	deliverSignal()
+	watchDrainSignal(context.Background(), stubService, slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})))
+	if stubService.drainCalls != 1 {
+		testHandle.Fatalf("expected SIGUSR1 to start one drain, got %d", stubService.drainCalls)
+	}
+}
+
+func TestVerifyTenantsAtStartupAbortsOnlyWhenStrict(testHandle *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	healthyRepo := newTestTenantRepository(testHandle, testTenantID)
+	if !verifyTenantsAtStartup(context.Background(), healthyRepo, logger, true) {
+		testHandle.Fatalf("expected a resolvable tenant to pass the strict self-check")
+	}
+
+	unmigratedDatabase, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		testHandle.Fatalf("open database: %v", err)
+	}
+	secretKeeper, err := tenant.NewSecretKeeper(strings.Repeat("a", 64))
+	if err != nil {
+		testHandle.Fatalf("init secret keeper: %v", err)
+	}
+	brokenRepo := tenant.NewRepository(unmigratedDatabase, secretKeeper)
+	if !verifyTenantsAtStartup(context.Background(), brokenRepo, logger, false) {
+		testHandle.Fatalf("expected a failed self-check to be logged without aborting")
+	}
+	if verifyTenantsAtStartup(context.Background(), brokenRepo, logger, true) {
+		testHandle.Fatalf("expected the strict self-check to abort startup")
+	}
+}
+
+func TestServeGRPCStopsOnceDrainCompletes(testHandle *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		testHandle.Fatalf("listen: %v", err)
+	}
+	drained := make(chan struct{})
+	stubService := &recordingNotificationService{drained: drained}
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- serveGRPC(listener, stubService, nil, newTestTenantRepository(testHandle, testTenantID), logger, nil, config.GRPCKeepaliveConfig{})
+	}()
+
+	close(drained)
+	select {
+	case err := <-serveErr:
+		if err != nil {
+			testHandle.Fatalf("expected a clean shutdown after the drain, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		testHandle.Fatalf("timed out waiting for gRPC shutdown after the drain")
+	}
+}
+
+func TestNotificationServiceServerGetCostSummary(testHandle *testing.T) {
+	notificationService := &recordingNotificationService{
+		costSummary: model.CostSummary{
+			Currency:  "USD",
+			TotalCost: 0.03,
+			Buckets: []model.CostSummaryBucket{
+				{Day: "2026-03-01", NotificationType: model.NotificationSMS, NotificationCount: 2, EstimatedCost: 0.03},
+			},
+		},
+	}
+	server := &notificationServiceServer{
+		notificationService: notificationService,
+		logger:              slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
+	}
+	startTime := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := server.GetCostSummary(fullAccessGRPCContext(), &grpcapi.CostSummaryRequest{StartTime: timestamppb.New(startTime)}); status.Code(err) != codes.InvalidArgument {
+		testHandle.Fatalf("expected InvalidArgument for missing end_time, got %v", err)
+	}
+	if _, err := server.GetCostSummary(fullAccessGRPCContext(), &grpcapi.CostSummaryRequest{
+		StartTime: timestamppb.New(startTime),
+		EndTime:   timestamppb.New(startTime.Add(-time.Hour)),
+	}); status.Code(err) != codes.InvalidArgument {
+		testHandle.Fatalf("expected InvalidArgument for inverted range, got %v", err)
+	}
+
+	response, err := server.GetCostSummary(fullAccessGRPCContext(), &grpcapi.CostSummaryRequest{
+		StartTime: timestamppb.New(startTime),
+		EndTime:   timestamppb.New(startTime.Add(24 * time.Hour)),
+	})
+	if err != nil {
+		testHandle.Fatalf("GetCostSummary error: %v", err)
+	}
+	if !notificationService.costSummaryRange.Start().Equal(startTime) {
+		testHandle.Fatalf("expected range start %v, got %v", startTime, notificationService.costSummaryRange.Start())
+	}
+	if response.GetCurrency() != "USD" || response.GetTotalCost() != 0.03 || len(response.GetBuckets()) != 1 {
+		testHandle.Fatalf("unexpected response %+v", response)
+	}
+	bucket := response.GetBuckets()[0]
+	if bucket.GetDay() != "2026-03-01" || bucket.GetNotificationType() != grpcapi.NotificationType_SMS || bucket.GetNotificationCount() != 2 {
+		testHandle.Fatalf("unexpected bucket %+v", bucket)
+	}
+}
+
+func TestNotificationServiceServerGetCapabilities(testHandle *testing.T) {
+	notificationService := &recordingNotificationService{
+		capabilities: service.Capabilities{
+			NotificationTypes:         []model.NotificationType{model.NotificationEmail},
+			MaxAttachmentCount:        10,
+			MaxAttachmentSizeBytes:    1024,
+			MaxAttachmentsTotalBytes:  4096,
+			AttachmentDataPersisted:   true,
+			MaxScheduleHorizonSeconds: 3600,
+		},
+	}
+	server := &notificationServiceServer{
+		notificationService: notificationService,
+		logger:              slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
+	}
+
+	response, err := server.GetCapabilities(fullAccessGRPCContext(), &grpcapi.GetCapabilitiesRequest{TenantId: "tenant-one"})
+	if err != nil {
+		testHandle.Fatalf("GetCapabilities error: %v", err)
+	}
+	if len(response.GetNotificationTypes()) != 1 || response.GetNotificationTypes()[0] != grpcapi.NotificationType_EMAIL {
+		testHandle.Fatalf("expected email-only capabilities, got %v", response.GetNotificationTypes())
+	}
+	if response.GetMaxAttachmentCount() != 10 || response.GetMaxAttachmentSizeBytes() != 1024 || response.GetMaxAttachmentsTotalBytes() != 4096 {
+		testHandle.Fatalf("unexpected attachment limits %+v", response)
+	}
+	if !response.GetAttachmentDataPersisted() || response.GetMaxScheduleHorizonSeconds() != 3600 {
+		testHandle.Fatalf("unexpected capabilities %+v", response)
+	}
+}
+
+func TestNotificationServiceServerEstimateNotificationSize(testHandle *testing.T) {
+	notificationService := &recordingNotificationService{
+		sizeEstimate: service.MessageSizeEstimate{MessageSizeBytes: 4096, AttachmentBytes: 2800},
+	}
+	server := &notificationServiceServer{
+		notificationService: notificationService,
+		logger:              slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
+	}
+	readOnlyCtx := withGRPCGrant(context.Background(), grpcGrant{scope: config.GRPCTokenScopeRead})
+
+	response, err := server.EstimateNotificationSize(readOnlyCtx, &grpcapi.NotificationRequest{
+		NotificationType: grpcapi.NotificationType_EMAIL,
+		Recipient:        "user@example.com",
+		Subject:          "Report",
+		Message:          "Attached.",
+		Attachments:      []*grpcapi.EmailAttachment{{Filename: "report.csv", ContentType: "text/csv", Data: []byte("a,b")}},
+	})
+	if err != nil {
+		testHandle.Fatalf("EstimateNotificationSize error: %v", err)
+	}
+	if response.GetMessageSizeBytes() != 4096 || response.GetAttachmentBytes() != 2800 {
+		testHandle.Fatalf("unexpected estimate %+v", response)
+	}
+	if notificationService.sentRequest.Recipient() != "user@example.com" || len(notificationService.sentRequest.SharedAttachments()) != 1 {
+		testHandle.Fatalf("expected the mapped request to reach the service, got %+v", notificationService.sentRequest)
+	}
+
+	if _, err := server.EstimateNotificationSize(readOnlyCtx, &grpcapi.NotificationRequest{NotificationType: grpcapi.NotificationType_EMAIL, Message: "Body"}); status.Code(err) != codes.InvalidArgument {
+		testHandle.Fatalf("expected an invalid request to fail with InvalidArgument, got %v", err)
+	}
+	notificationService.err = service.ErrScheduleBeyondHorizon
+	if _, err := server.EstimateNotificationSize(readOnlyCtx, &grpcapi.NotificationRequest{NotificationType: grpcapi.NotificationType_SMS, Recipient: "+15555550100", Message: "Body"}); status.Code(err) != codes.InvalidArgument {
+		testHandle.Fatalf("expected a send-check failure to map to InvalidArgument, got %v", err)
+	}
+	notificationService.err = service.ErrAttachmentDataNotPersisted
+	if _, err := server.EstimateNotificationSize(readOnlyCtx, &grpcapi.NotificationRequest{NotificationType: grpcapi.NotificationType_SMS, Recipient: "+15555550100", Message: "Body"}); status.Code(err) != codes.FailedPrecondition {
+		testHandle.Fatalf("expected FailedPrecondition, got %v", err)
+	}
+	if _, err := server.EstimateNotificationSize(context.Background(), &grpcapi.NotificationRequest{}); status.Code(err) != codes.PermissionDenied {
+		testHandle.Fatalf("expected a call without a grant to be denied, got %v", err)
+	}
+}
+
+func TestNotificationServiceServerTestTenantDelivery(testHandle *testing.T) {
+	notificationService := &recordingNotificationService{
+		deliveryCheck: service.DeliveryCheck{NotificationType: model.NotificationSMS, Error: "twilio API error: unauthorized"},
+	}
+	server := &notificationServiceServer{
+		notificationService: notificationService,
+		logger:              slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
+	}
+
+	response, err := server.TestTenantDelivery(fullAccessGRPCContext(), &grpcapi.TestTenantDeliveryRequest{TenantId: "tenant-one", NotificationType: grpcapi.NotificationType_SMS})
+	if err != nil {
+		testHandle.Fatalf("TestTenantDelivery error: %v", err)
+	}
+	if notificationService.deliveryType != model.NotificationSMS {
+		testHandle.Fatalf("expected an sms check, got %q", notificationService.deliveryType)
+	}
+	if response.GetSuccess() || response.GetError() != "twilio API error: unauthorized" || response.GetNotificationType() != grpcapi.NotificationType_SMS {
+		testHandle.Fatalf("unexpected response %+v", response)
+	}
+
+	readOnlyContext := withGRPCGrant(context.Background(), grpcGrant{scope: config.GRPCTokenScopeRead})
+	if _, err := server.TestTenantDelivery(readOnlyContext, &grpcapi.TestTenantDeliveryRequest{NotificationType: grpcapi.NotificationType_EMAIL}); status.Code(err) != codes.PermissionDenied {
+		testHandle.Fatalf("expected PERMISSION_DENIED for a read token, got %v", err)
+	}
+	notificationService.err = service.ErrDeliveryCheckUnsupported
+	if _, err := server.TestTenantDelivery(fullAccessGRPCContext(), &grpcapi.TestTenantDeliveryRequest{NotificationType: grpcapi.NotificationType_EMAIL}); status.Code(err) != codes.FailedPrecondition {
+		testHandle.Fatalf("expected FAILED_PRECONDITION for an unverifiable sender, got %v", err)
+	}
+}
+
+func TestNewConfiguredSecretKeeperSelectsCipher(testHandle *testing.T) {
+	key := strings.Repeat("a", 64)
+	for _, cipherName := range []string{"", config.SecretCipherAESGCM} {
+		keeper, err := newConfiguredSecretKeeper(config.Config{MasterEncryptionKey: key, SecretCipher: cipherName})
+		if err != nil {
+			testHandle.Fatalf("expected %q to build the AES-GCM keeper: %v", cipherName, err)
+		}
+		sealed, err := keeper.Encrypt("secret")
+		if err != nil {
+			testHandle.Fatalf("encrypt: %v", err)
+		}
+		if opened, err := keeper.Decrypt(sealed); err != nil || opened != "secret" {
+			testHandle.Fatalf("expected a round trip, got %q err=%v", opened, err)
+		}
+	}
+	if _, err := newConfiguredSecretKeeper(config.Config{MasterEncryptionKey: key, SecretCipher: "pkcs11"}); err == nil {
+		testHandle.Fatalf("expected an unknown cipher to be rejected")
+	}
+}
+
+func TestSMTPPublicSettings(testHandle *testing.T) {
+	testHandle.Helper()
+	startTLS := smtpPublicSettings(configSMTPSubmission(":2525", ""))
+	if startTLS.Port != 2525 || startTLS.SecurityMode != "starttls" {
+		testHandle.Fatalf("unexpected starttls settings %+v", startTLS)
+	}
+	implicitTLS := smtpPublicSettings(configSMTPSubmission("", "127.0.0.1:2465"))
+	if implicitTLS.Port != 2465 || implicitTLS.SecurityMode != "ssl" {
+		testHandle.Fatalf("unexpected implicit tls settings %+v", implicitTLS)
+	}
+	caddyTerminated := configSMTPSubmission(":587", "")
+	caddyTerminated.PublicPort = 465
+	caddyTerminated.PublicSecurityMode = "ssl"
+	publicSettings := smtpPublicSettings(caddyTerminated)
+	if publicSettings.Port != 465 || publicSettings.SecurityMode != "ssl" {
+		testHandle.Fatalf("unexpected caddy-terminated public settings %+v", publicSettings)
+	}
+	defaultStartTLS := smtpPublicSettings(configSMTPSubmission("bad", ""))
+	if defaultStartTLS.Port != 587 {
+		testHandle.Fatalf("expected starttls fallback port, got %d", defaultStartTLS.Port)
+	}
+	defaultImplicitTLS := smtpPublicSettings(configSMTPSubmission("", "bad"))
+	if defaultImplicitTLS.Port != 465 {
+		testHandle.Fatalf("expected implicit tls fallback port, got %d", defaultImplicitTLS.Port)
+	}
+	if smtpPortFromAddr(" ", 25) != 25 {
+		testHandle.Fatalf("expected blank address fallback")
+	}
+}
+
+func TestRunServerSuccessWithInlineTenants(testHandle *testing.T) {
+	testHandle.Helper()
+	cfg := serverTestConfig()
+	state, dependencies := newServerTestDependencies(cfg)
+
+	if exitCode := runServer(nil, dependencies); exitCode != 0 {
+		testHandle.Fatalf("expected success exit code, got %d", exitCode)
+	}
+	if !state.bootstrapCalled || state.bootstrapFileCalled {
+		testHandle.Fatalf("expected inline bootstrap, state=%+v", state)
+	}
+	if !state.grpcServed {
+		testHandle.Fatalf("expected grpc server to be served")
+	}
+}
+
+func TestRunServerPublishesGRPCReadyEventAfterListenerBind(testHandle *testing.T) {
+	testHandle.Helper()
+	cfg := serverTestConfig()
+	state, dependencies := newServerTestDependencies(cfg)
+	var logOutput bytes.Buffer
+	dependencies.newLogger = func(string) *slog.Logger {
+		return slog.New(slog.NewTextHandler(&logOutput, &slog.HandlerOptions{}))
+	}
+	dependencies.listen = func(string, string) (net.Listener, error) {
+		if strings.Contains(logOutput.String(), "event=pinguin.grpc.ready") {
+			testHandle.Fatal("gRPC readiness event was published before the listener bound")
+		}
+		return fakeListener{}, nil
+	}
+	dependencies.serveGRPC = func(net.Listener, service.NotificationService, queueIntakeStatsReporter, *tenant.Repository, *slog.Logger, []config.GRPCTokenConfig, config.GRPCKeepaliveConfig) error {
+		if !strings.Contains(logOutput.String(), "event=pinguin.grpc.ready") {
+			testHandle.Fatalf("gRPC readiness event was not published after listener bind:\n%s", logOutput.String())
+		}
+		state.grpcServed = true
+		return nil
+	}
+
+	if exitCode := runServer(nil, dependencies); exitCode != 0 {
+		testHandle.Fatalf("expected success exit code, got %d", exitCode)
+	}
+	if !state.grpcServed {
+		testHandle.Fatal("expected gRPC server to be served")
+	}
+}
+
+func TestRunServerDoesNotPublishGRPCReadyEventWhenListenerBindFails(testHandle *testing.T) {
+	testHandle.Helper()
+	cfg := serverTestConfig()
+	_, dependencies := newServerTestDependencies(cfg)
+	var logOutput bytes.Buffer
+	dependencies.newLogger = func(string) *slog.Logger {
+		return slog.New(slog.NewTextHandler(&logOutput, &slog.HandlerOptions{}))
+	}
+	dependencies.listen = func(string, string) (net.Listener, error) {
+		return nil, errors.New("bind failed")
+	}
+
+	if exitCode := runServer(nil, dependencies); exitCode != 1 {
+		testHandle.Fatalf("expected failure exit code, got %d", exitCode)
+	}
+	if strings.Contains(logOutput.String(), "event=pinguin.grpc.ready") {
+		testHandle.Fatalf("gRPC readiness event was published after listener bind failed:\n%s", logOutput.String())
+	}
+}
+
+func TestRunServerLogsEachConfigurationProblem(testHandle *testing.T) {
+	testHandle.Helper()
+	_, dependencies := newServerTestDependencies(serverTestConfig())
+	var logOutput bytes.Buffer
+	dependencies.newLogger = func(string) *slog.Logger {
+		return slog.New(slog.NewTextHandler(&logOutput, &slog.HandlerOptions{}))
+	}
+	dependencies.loadConfig = func() (config.Config, error) {
+		return config.Config{}, &config.ValidationError{Problems: []string{"missing server.databasePath", "missing web.listenAddr, or web.disabled"}}
+	}
+
+	if exitCode := runServer(nil, dependencies); exitCode != 1 {
+		testHandle.Fatalf("expected failure exit code, got %d", exitCode)
+	}
+	if count := strings.Count(logOutput.String(), "Configuration error"); count != 2 {
+		testHandle.Fatalf("expected one log line per problem, got %d:\n%s", count, logOutput.String())
+	}
+	if !strings.Contains(logOutput.String(), `detail="missing web.listenAddr, or web.disabled"`) {
+		testHandle.Fatalf("expected problems to be logged without splitting:\n%s", logOutput.String())
+	}
+}
+
+func TestRunServerStartsWebAndSMTPSubmission(testHandle *testing.T) {
+	testHandle.Helper()
+	cfg := serverTestConfig()
+	cfg.TenantBootstrap = tenant.BootstrapConfig{}
+	cfg.TenantConfigPath = "tenants.yml"
+	cfg.WebInterfaceEnabled = true
+	cfg.HTTPListenAddr = "127.0.0.1:8080"
+	cfg.HTTPTrustedProxies = []string{"127.0.0.1"}
+	cfg.HTTPServiceAccountEmails = []string{"monitor@example.com"}
+	cfg.TAuthSigningKey = "signing-key"
+	cfg.TAuthCookieName = "app_session"
+	cfg.SMTPSubmission = config.SMTPSubmissionConfig{
+		Enabled:           true,
+		Hostname:          "smtp.example.com",
+		ListenAddr:        ":587",
+		TLSListenAddr:     ":465",
+		TLSCertPath:       "cert.pem",
+		TLSKeyPath:        "key.pem",
+		MaxMessageBytes:   1024,
+		MaxRecipients:     2,
+		AllowInsecureAuth: true,
+	}
+	state, dependencies := newServerTestDependencies(cfg)
+	state.httpServer.shutdownErr = errors.New("shutdown failed")
+
+	if exitCode := runServer(nil, dependencies); exitCode != 0 {
+		testHandle.Fatalf("expected success exit code, got %d", exitCode)
+	}
+	waitForClosed(testHandle, state.smtpStarter.started)
+	waitForClosed(testHandle, state.httpServer.started)
+	if !state.bootstrapFileCalled || !state.tlsLoaded {
+		testHandle.Fatalf("expected file/bootstrap/tls setup, state=%+v", state)
+	}
+	if state.smtpConfig.TLSConfig == nil || state.smtpConfig.TLSConfig.GetCertificate == nil {
+		testHandle.Fatalf("expected SMTP submission to serve the reloadable certificate, got %+v", state.smtpConfig.TLSConfig)
+	}
+	if _, ok := state.httpConfig.Certificates[smtpSubmissionCertificateName]; !ok {
+		testHandle.Fatalf("expected SMTP submission certificate in healthz details, got %+v", state.httpConfig.Certificates)
+	}
+	if len(state.httpConfig.TrustedProxies) != 1 || state.httpConfig.TrustedProxies[0] != "127.0.0.1" {
+		testHandle.Fatalf("expected trusted proxy config to reach HTTP server, got %+v", state.httpConfig.TrustedProxies)
+	}
+	if len(state.httpConfig.ServiceAccountEmails) != 1 || state.httpConfig.ServiceAccountEmails[0] != "monitor@example.com" {
+		testHandle.Fatalf("expected service accounts to reach HTTP server, got %+v", state.httpConfig.ServiceAccountEmails)
+	}
+	if !state.httpServer.shutdownCalled {
+		testHandle.Fatalf("expected HTTP shutdown")
+	}
+	if state.smtpConfig.TLSConfig == nil || state.smtpConfig.Relay == nil {
+		testHandle.Fatalf("expected SMTP config to include TLS and relay")
+	}
+	if state.smtpConfig.CommandTimeout != 30*time.Second {
+		testHandle.Fatalf("expected SMTP command timeout from operation timeout, got %s", state.smtpConfig.CommandTimeout)
+	}
+}
+
+func TestRunServerStartsSMTPForwarding(testHandle *testing.T) {
+	testHandle.Helper()
+	cfg := serverTestConfig()
+	cfg.SMTPForwarding = config.SMTPForwardingConfig{
+		Enabled:         true,
+		Hostname:        "mx.pinguin.mprlab.com",
+		ListenAddr:      ":25",
+		MaxMessageBytes: 2048,
+		MaxRecipients:   3,
+		Relay: config.SMTPForwardingRelayConfig{
+			Host:     "relay.example.com",
+			Port:     587,
+			Username: "relay-user",
+			Password: "relay-pass",
+		},
+	}
+	state, dependencies := newServerTestDependencies(cfg)
+
+	if exitCode := runServer(nil, dependencies); exitCode != 0 {
+		testHandle.Fatalf("expected success exit code, got %d", exitCode)
+	}
+	waitForClosed(testHandle, state.smtpForwardingStarter.started)
+	if state.smtpForwardingConfig.Hostname != "mx.pinguin.mprlab.com" {
+		testHandle.Fatalf("unexpected forwarding hostname %s", state.smtpForwardingConfig.Hostname)
+	}
+	if state.smtpForwardingConfig.CommandTimeout != 30*time.Second {
+		testHandle.Fatalf("expected forwarding timeout from operation timeout, got %s", state.smtpForwardingConfig.CommandTimeout)
+	}
+	if state.smtpForwardingConfig.Forwarder == nil {
+		testHandle.Fatalf("expected forwarding config to include forwarder")
+	}
+	if state.smtpForwardingConfig.RouteResolver == nil {
+		testHandle.Fatalf("expected forwarding config to include route resolver")
 	}
 }
 
@@ -696,10 +1408,10 @@ func TestRunServerErrorPaths(testHandle *testing.T) {
 			deps.loadConfig = func() (config.Config, error) { return config.Config{}, expectedErr }
 		}},
 		{name: "database", config: serverTestConfig, mutate: func(deps *serverDependencies) {
-			deps.initDB = func(string, *slog.Logger) (*gorm.DB, error) { return nil, expectedErr }
+			deps.initDB = func(string, model.QueryTimeouts, *slog.Logger) (*gorm.DB, error) { return nil, expectedErr }
 		}},
 		{name: "secret keeper", config: serverTestConfig, mutate: func(deps *serverDependencies) {
-			deps.newSecretKeeper = func(string) (*tenant.SecretKeeper, error) { return nil, expectedErr }
+			deps.newSecretKeeper = func(config.Config) (*tenant.SecretKeeper, error) { return nil, expectedErr }
 		}},
 		{name: "inline bootstrap", config: serverTestConfig, mutate: func(deps *serverDependencies) {
 			deps.bootstrapTenants = func(context.Context, *gorm.DB, *tenant.SecretKeeper, tenant.BootstrapConfig) error {
@@ -731,7 +1443,7 @@ func TestRunServerErrorPaths(testHandle *testing.T) {
 			cfg.SMTPSubmission.TLSKeyPath = "key.pem"
 			return cfg
 		}, mutate: func(deps *serverDependencies) {
-			deps.loadTLSConfig = func(string, string) (*tls.Config, error) { return nil, expectedErr }
+			deps.loadCertificate = func(string, string, *slog.Logger) (certificateWatcher, error) { return nil, expectedErr }
 		}},
 		{name: "smtp server", config: func() config.Config {
 			cfg := serverTestConfig()
@@ -768,6 +1480,13 @@ func TestRunServerErrorPaths(testHandle *testing.T) {
 		}, mutate: func(deps *serverDependencies) {
 			deps.newSMTPForwardingServer = func(smtpforwarding.Config) (smtpForwardingStarter, error) { return nil, expectedErr }
 		}},
+		{name: "queue intake consumer", config: func() config.Config {
+			cfg := serverTestConfig()
+			cfg.QueueIntake.Enabled = true
+			return cfg
+		}, mutate: func(deps *serverDependencies) {
+			deps.newQueueIntakeConsumer = func(queueintake.Config) (queueIntakeRunner, error) { return nil, expectedErr }
+		}},
 		{name: "session validator", config: func() config.Config {
 			cfg := serverTestConfig()
 			cfg.WebInterfaceEnabled = true
@@ -786,7 +1505,7 @@ func TestRunServerErrorPaths(testHandle *testing.T) {
 			deps.listen = func(string, string) (net.Listener, error) { return nil, expectedErr }
 		}},
 		{name: "serve grpc", config: serverTestConfig, mutate: func(deps *serverDependencies) {
-			deps.serveGRPC = func(net.Listener, service.NotificationService, *tenant.Repository, *slog.Logger, string) error {
+			deps.serveGRPC = func(net.Listener, service.NotificationService, queueIntakeStatsReporter, *tenant.Repository, *slog.Logger, []config.GRPCTokenConfig, config.GRPCKeepaliveConfig) error {
 				return expectedErr
 			}
 		}},
@@ -938,48 +1657,353 @@ func TestServerDependencyDefaultsAndProductionWrappers(testHandle *testing.T) {
 	if _, ok := directRelay.(*smtpsubmission.DirectMXRelay); !ok {
 		testHandle.Fatalf("expected direct relay, got %T", directRelay)
 	}
-	if _, err := production.newSMTPSubmissionServer(smtpsubmission.Config{}); err == nil {
-		testHandle.Fatalf("expected invalid SMTP submission config error")
+	if _, err := production.newSMTPSubmissionServer(smtpsubmission.Config{}); err == nil {
+		testHandle.Fatalf("expected invalid SMTP submission config error")
+	}
+	forwarder, forwarderErr := production.newSMTPForwarder(logger, serverTestConfig())
+	if forwarderErr != nil {
+		testHandle.Fatalf("expected production forwarding forwarder, got %v", forwarderErr)
+	}
+	if forwarder == nil {
+		testHandle.Fatalf("expected production forwarding forwarder")
+	}
+	if _, err := production.newSMTPForwardingServer(smtpforwarding.Config{}); err == nil {
+		testHandle.Fatalf("expected invalid SMTP forwarding config error")
+	}
+	if _, err := production.newSessionValidator(sessionvalidator.Config{}); err == nil {
+		testHandle.Fatalf("expected invalid session validator error")
+	}
+	if _, err := production.newHTTPServer(httpapi.Config{}); err == nil {
+		testHandle.Fatalf("expected invalid HTTP server config error")
+	}
+	if _, err := production.loadCertificate("missing.pem", "missing.key", logger); err == nil {
+		testHandle.Fatalf("expected missing certificate error")
+	}
+}
+
+func TestServeGRPCBuildsServer(testHandle *testing.T) {
+	testHandle.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		testHandle.Fatalf("listen: %v", err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- serveGRPC(listener, &recordingNotificationService{}, nil, nil, logger, []config.GRPCTokenConfig{{Token: "token", Scope: config.GRPCTokenScopeWrite}}, config.GRPCKeepaliveConfig{})
+	}()
+	if err := listener.Close(); err != nil {
+		testHandle.Fatalf("close listener: %v", err)
+	}
+	select {
+	case err := <-errCh:
+		if err == nil {
+			testHandle.Fatalf("expected serve error after listener close")
+		}
+	case <-time.After(time.Second):
+		testHandle.Fatalf("timed out waiting for grpc serve")
+	}
+}
+
+func TestServeGRPCOverUnixSocketRemovesSocketOnShutdown(testHandle *testing.T) {
+	socketDirectory, err := os.MkdirTemp("", "pinguin")
+	if err != nil {
+		testHandle.Fatalf("temp dir: %v", err)
+	}
+	testHandle.Cleanup(func() { os.RemoveAll(socketDirectory) })
+	socketPath := filepath.Join(socketDirectory, "grpc.sock")
+	listenEndpoint, err := endpoint.Parse("unix://" + socketPath)
+	if err != nil {
+		testHandle.Fatalf("parse endpoint: %v", err)
+	}
+	listener, err := endpoint.Listen(listenEndpoint.Network(), listenEndpoint.Address())
+	if err != nil {
+		testHandle.Fatalf("listen: %v", err)
+	}
+	shutdownCtx, requestShutdown := context.WithCancel(context.Background())
+	originalShutdownContext := grpcShutdownContext
+	grpcShutdownContext = func() (context.Context, context.CancelFunc) { return shutdownCtx, requestShutdown }
+	testHandle.Cleanup(func() { grpcShutdownContext = originalShutdownContext })
+
+	stubService := &recordingNotificationService{response: model.NotificationResponse{NotificationID: "notif-unix", Status: model.StatusSent}}
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- serveGRPC(listener, stubService, nil, newTestTenantRepository(testHandle, testTenantID), logger, []config.GRPCTokenConfig{{Token: "token", Scope: config.GRPCTokenScopeWrite}}, config.GRPCKeepaliveConfig{})
+	}()
+
+	settings, err := client.NewSettings(listenEndpoint.String(), "token", testTenantID, 5, 5)
+	if err != nil {
+		testHandle.Fatalf("client settings: %v", err)
+	}
+	notificationClient, err := client.NewNotificationClient(logger, settings)
+	if err != nil {
+		testHandle.Fatalf("client: %v", err)
+	}
+	defer notificationClient.Close()
+	response, err := notificationClient.GetNotificationStatus("notif-unix")
+	if err != nil || response.GetNotificationId() != "notif-unix" || stubService.statusID != "notif-unix" {
+		testHandle.Fatalf("expected the status call to reach the stub service over the unix socket, got %v (%v)", response, err)
+	}
+
+	requestShutdown()
+	select {
+	case err := <-serveErr:
+		if err != nil {
+			testHandle.Fatalf("expected a clean shutdown, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		testHandle.Fatalf("timed out waiting for gRPC shutdown")
+	}
+	if _, err := os.Stat(socketPath); !errors.Is(err, os.ErrNotExist) {
+		testHandle.Fatalf("expected the socket file to be removed on shutdown, got %v", err)
+	}
+}
+
+func TestPingRequiresAValidTokenButNoTenant(testHandle *testing.T) {
+	socketDirectory, err := os.MkdirTemp("", "pinguin")
+	if err != nil {
+		testHandle.Fatalf("temp dir: %v", err)
+	}
+	testHandle.Cleanup(func() { os.RemoveAll(socketDirectory) })
+	listenEndpoint, err := endpoint.Parse("unix://" + filepath.Join(socketDirectory, "grpc.sock"))
+	if err != nil {
+		testHandle.Fatalf("parse endpoint: %v", err)
+	}
+	listener, err := endpoint.Listen(listenEndpoint.Network(), listenEndpoint.Address())
+	if err != nil {
+		testHandle.Fatalf("listen: %v", err)
+	}
+	shutdownCtx, requestShutdown := context.WithCancel(context.Background())
+	originalShutdownContext := grpcShutdownContext
+	grpcShutdownContext = func() (context.Context, context.CancelFunc) { return shutdownCtx, requestShutdown }
+	testHandle.Cleanup(func() { grpcShutdownContext = originalShutdownContext })
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	tokens := []config.GRPCTokenConfig{
+		{Token: "reader", Scope: config.GRPCTokenScopeRead},
+		{Token: "operator", Scope: config.GRPCTokenScopeAdmin},
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- serveGRPC(listener, &recordingNotificationService{}, nil, newTestTenantRepository(testHandle, testTenantID), logger, tokens, config.GRPCKeepaliveConfig{})
+	}()
+	defer func() {
+		requestShutdown()
+		<-serveErr
+	}()
+
+	before := time.Now().UTC().Add(-time.Second)
+	for _, token := range []string{"reader", "operator"} {
+		settings, settingsErr := client.NewAdminSettings(listenEndpoint.String(), token, 5, 5)
+		if settingsErr != nil {
+			testHandle.Fatalf("client settings: %v", settingsErr)
+		}
+		notificationClient, clientErr := client.NewNotificationClient(logger, settings)
+		if clientErr != nil {
+			testHandle.Fatalf("client: %v", clientErr)
+		}
+		response, pingErr := notificationClient.Ping()
+		notificationClient.Close()
+		if pingErr != nil {
+			testHandle.Fatalf("expected ping with the %s token to succeed, got %v", token, pingErr)
+		}
+		if response.GetServerTime().AsTime().Before(before) || response.GetVersion() == "" {
+			testHandle.Fatalf("expected server time and version, got %+v", response)
+		}
+	}
+
+	connection, err := grpc.NewClient(listenEndpoint.GRPCTarget(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		testHandle.Fatalf("dial: %v", err)
+	}
+	defer connection.Close()
+	rawClient := grpcapi.NewNotificationServiceClient(connection)
+	for name, ctx := range map[string]context.Context{
+		"missing token": context.Background(),
+		"unknown token": metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer other"),
+	} {
+		if _, pingErr := rawClient.Ping(ctx, &grpcapi.PingRequest{}); status.Code(pingErr) != codes.Unauthenticated {
+			testHandle.Fatalf("%s: expected Unauthenticated, got %v", name, pingErr)
+		}
+	}
+}
+
+func TestListNotificationsMapsUnknownStatusToInvalidArgument(testHandle *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	service := &recordingNotificationService{listErr: fmt.Errorf("%w: %q", model.ErrUnknownNotificationStatus, "99")}
+	server := &notificationServiceServer{notificationService: service, logger: logger}
+	request := &grpcapi.ListNotificationsRequest{Statuses: []grpcapi.Status{grpcapi.Status(99)}}
+
+	if _, err := server.ListNotifications(fullAccessGRPCContext(), request); status.Code(err) != codes.InvalidArgument {
+		testHandle.Fatalf("expected InvalidArgument from ListNotifications, got %v", err)
+	}
+	if len(service.listFilters.Statuses) != 1 || service.listFilters.Statuses[0] != "99" {
+		testHandle.Fatalf("expected the unknown status to reach the service, got %v", service.listFilters.Statuses)
 	}
-	forwarder, forwarderErr := production.newSMTPForwarder(logger, serverTestConfig())
-	if forwarderErr != nil {
-		testHandle.Fatalf("expected production forwarding forwarder, got %v", forwarderErr)
+	stream := &recordingListStream{ctx: fullAccessGRPCContext()}
+	if err := server.ListNotificationsStream(request, stream); status.Code(err) != codes.InvalidArgument {
+		testHandle.Fatalf("expected InvalidArgument from ListNotificationsStream, got %v", err)
 	}
-	if forwarder == nil {
-		testHandle.Fatalf("expected production forwarding forwarder")
+}
+
+func TestNotificationReadsProjectRequestedFields(testHandle *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	notification := model.NotificationResponse{NotificationID: "notif-1", Status: model.StatusSent, Recipient: "user@example.com", Message: "Body", RetryCount: 2}
+	service := &recordingNotificationService{response: notification, listResponses: []model.NotificationResponse{notification}}
+	server := &notificationServiceServer{notificationService: service, logger: logger}
+	fields := []string{"notification_id", "status"}
+
+	statusResponse, err := server.GetNotificationStatus(fullAccessGRPCContext(), &grpcapi.GetNotificationStatusRequest{NotificationId: "notif-1", Fields: fields})
+	if err != nil {
+		testHandle.Fatalf("GetNotificationStatus error: %v", err)
 	}
-	if _, err := production.newSMTPForwardingServer(smtpforwarding.Config{}); err == nil {
-		testHandle.Fatalf("expected invalid SMTP forwarding config error")
+	listResponse, err := server.ListNotifications(fullAccessGRPCContext(), &grpcapi.ListNotificationsRequest{Fields: fields})
+	if err != nil {
+		testHandle.Fatalf("ListNotifications error: %v", err)
 	}
-	if _, err := production.newSessionValidator(sessionvalidator.Config{}); err == nil {
-		testHandle.Fatalf("expected invalid session validator error")
+	stream := &recordingListStream{ctx: fullAccessGRPCContext()}
+	if err := server.ListNotificationsStream(&grpcapi.ListNotificationsRequest{Fields: fields}, stream); err != nil {
+		testHandle.Fatalf("ListNotificationsStream error: %v", err)
 	}
-	if _, err := production.newHTTPServer(httpapi.Config{}); err == nil {
-		testHandle.Fatalf("expected invalid HTTP server config error")
+	for _, projected := range append([]*grpcapi.NotificationResponse{statusResponse, listResponse.GetNotifications()[0]}, stream.sent...) {
+		expected := &grpcapi.NotificationResponse{NotificationId: "notif-1", Status: grpcapi.Status_SENT}
+		if !proto.Equal(projected, expected) {
+			testHandle.Fatalf("expected only the requested fields, got %v", projected)
+		}
+	}
+
+	unknown := []string{"notification_id", "password"}
+	if _, err := server.GetNotificationStatus(fullAccessGRPCContext(), &grpcapi.GetNotificationStatusRequest{NotificationId: "notif-1", Fields: unknown}); status.Code(err) != codes.InvalidArgument {
+		testHandle.Fatalf("expected InvalidArgument for an unknown field from GetNotificationStatus, got %v", err)
+	}
+	if _, err := server.ListNotifications(fullAccessGRPCContext(), &grpcapi.ListNotificationsRequest{Fields: unknown}); status.Code(err) != codes.InvalidArgument {
+		testHandle.Fatalf("expected InvalidArgument for an unknown field from ListNotifications, got %v", err)
+	}
+	if err := server.ListNotificationsStream(&grpcapi.ListNotificationsRequest{Fields: unknown}, &recordingListStream{ctx: fullAccessGRPCContext()}); status.Code(err) != codes.InvalidArgument {
+		testHandle.Fatalf("expected InvalidArgument for an unknown field from ListNotificationsStream, got %v", err)
 	}
 }
 
-func TestServeGRPCBuildsServer(testHandle *testing.T) {
-	testHandle.Helper()
+type recordingListStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*grpcapi.NotificationResponse
+}
+
+func (stream *recordingListStream) Context() context.Context {
+	return stream.ctx
+}
+
+func (stream *recordingListStream) Send(response *grpcapi.NotificationResponse) error {
+	stream.sent = append(stream.sent, response)
+	return nil
+}
+
+func TestListNotificationsStreamSendsLargeResultOldestFirst(testHandle *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	database, err := db.InitDB(filepath.Join(testHandle.TempDir(), "pinguin.db"), logger)
+	if err != nil {
+		testHandle.Fatalf("init db: %v", err)
+	}
+	tenantRepo := bootstrapQueueIntakeTenant(testHandle, database)
+	notificationSvc := service.NewNotificationServiceWithSenders(database, logger, config.Config{MaxRetries: 3, RetryIntervalSec: 30}, tenantRepo, &countingEmailSender{}, nil)
+	const rowCount = 50000
+	baseTime := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	records := make([]model.Notification, 0, rowCount)
+	for index := range rowCount {
+		notificationStatus := model.StatusSent
+		if index%5 == 0 {
+			notificationStatus = model.StatusQueued
+		}
+		records = append(records, model.Notification{
+			TenantID:         testTenantID,
+			NotificationID:   fmt.Sprintf("notif-%05d", index),
+			NotificationType: model.NotificationEmail,
+			Recipient:        "user@example.com",
+			Message:          "Body",
+			Status:           notificationStatus,
+			CreatedAt:        baseTime.Add(time.Duration(index/2) * time.Second),
+		})
+	}
+	if err := database.CreateInBatches(records, 50).Error; err != nil {
+		testHandle.Fatalf("seed notifications: %v", err)
+	}
+	records = nil
+
 	listener, err := net.Listen("tcp", "127.0.0.1:0")
 	if err != nil {
 		testHandle.Fatalf("listen: %v", err)
 	}
-	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
-	errCh := make(chan error, 1)
+	shutdownCtx, requestShutdown := context.WithCancel(context.Background())
+	originalShutdownContext := grpcShutdownContext
+	grpcShutdownContext = func() (context.Context, context.CancelFunc) { return shutdownCtx, requestShutdown }
+	testHandle.Cleanup(func() { grpcShutdownContext = originalShutdownContext })
+	tokens := []config.GRPCTokenConfig{
+		{Token: "reader", Scope: config.GRPCTokenScopeRead},
+		{Token: "other-reader", Scope: config.GRPCTokenScopeRead, TenantIDs: []string{"other-tenant"}},
+	}
+	serveErr := make(chan error, 1)
 	go func() {
-		errCh <- serveGRPC(listener, &recordingNotificationService{}, nil, logger, "token")
+		serveErr <- serveGRPC(listener, notificationSvc, nil, tenantRepo, logger, tokens, config.GRPCKeepaliveConfig{})
 	}()
-	if err := listener.Close(); err != nil {
-		testHandle.Fatalf("close listener: %v", err)
+	defer func() {
+		requestShutdown()
+		<-serveErr
+	}()
+
+	streamClient := func(token string, tenantID string) *client.NotificationClient {
+		settings, settingsErr := client.NewSettings(listener.Addr().String(), token, tenantID, 5, 5)
+		if settingsErr != nil {
+			testHandle.Fatalf("client settings: %v", settingsErr)
+		}
+		notificationClient, clientErr := client.NewNotificationClient(logger, settings)
+		if clientErr != nil {
+			testHandle.Fatalf("client: %v", clientErr)
+		}
+		testHandle.Cleanup(func() { notificationClient.Close() })
+		return notificationClient
+	}
+	reader := streamClient("reader", testTenantID)
+	received := 0
+	for response, streamErr := range reader.ListNotificationsStream(context.Background()) {
+		if streamErr != nil {
+			testHandle.Fatalf("stream: %v", streamErr)
+		}
+		if response.GetNotificationId() != fmt.Sprintf("notif-%05d", received) {
+			testHandle.Fatalf("expected oldest-first order, got %q at position %d", response.GetNotificationId(), received)
+		}
+		received++
 	}
-	select {
-	case err := <-errCh:
-		if err == nil {
-			testHandle.Fatalf("expected serve error after listener close")
+	if received != rowCount {
+		testHandle.Fatalf("expected %d notifications, got %d", rowCount, received)
+	}
+	queued := 0
+	for response, streamErr := range reader.ListNotificationsStream(context.Background(), grpcapi.Status_QUEUED) {
+		if streamErr != nil || response.GetStatus() != grpcapi.Status_QUEUED {
+			testHandle.Fatalf("expected only queued notifications, got %v (%v)", response, streamErr)
+		}
+		queued++
+	}
+	if queued != rowCount/5 {
+		testHandle.Fatalf("expected %d queued notifications, got %d", rowCount/5, queued)
+	}
+
+	for name, testCase := range map[string]struct {
+		client *client.NotificationClient
+		code   codes.Code
+	}{
+		"unknown token":          {client: streamClient("unknown", testTenantID), code: codes.Unauthenticated},
+		"unknown tenant":         {client: streamClient("reader", "missing-tenant"), code: codes.NotFound},
+		"token for other tenant": {client: streamClient("other-reader", testTenantID), code: codes.PermissionDenied},
+	} {
+		var streamErr error
+		for _, err := range testCase.client.ListNotificationsStream(context.Background()) {
+			streamErr = err
+		}
+		if status.Code(streamErr) != testCase.code {
+			testHandle.Fatalf("%s: expected %s, got %v", name, testCase.code, streamErr)
 		}
-	case <-time.After(time.Second):
-		testHandle.Fatalf("timed out waiting for grpc serve")
 	}
 }
 
@@ -1028,6 +2052,63 @@ func TestBuildTenantInterceptorUsesMetadata(testHandle *testing.T) {
 	}
 }
 
+func TestBuildTenantInterceptorResolvesHostMetadata(testHandle *testing.T) {
+	testHandle.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	repo := newTestTenantRepository(testHandle, testTenantID)
+	interceptor := buildTenantInterceptor(logger, repo)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		runtimeCfg, ok := tenant.RuntimeFromContext(ctx)
+		if !ok {
+			return nil, status.Error(codes.Internal, missingTenantRuntimeMessage)
+		}
+		return runtimeCfg.Tenant.ID, nil
+	}
+	testCases := []struct {
+		name         string
+		request      interface{}
+		metadata     metadata.MD
+		expectedCode codes.Code
+	}{
+		{
+			name:         "authority only",
+			request:      &grpcapi.GetNotificationStatusRequest{},
+			metadata:     metadata.Pairs(authorityMetadataKey, "test.localhost:50051"),
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "forwarded host wins over authority",
+			request:      &grpcapi.GetNotificationStatusRequest{},
+			metadata:     metadata.Pairs(authorityMetadataKey, "gateway.internal", forwardedHostMetadataKey, "Test.Localhost, gateway.internal"),
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "explicit tenant wins over host",
+			request:      &grpcapi.GetNotificationStatusRequest{TenantId: testTenantID},
+			metadata:     metadata.Pairs(authorityMetadataKey, "unknown.localhost"),
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "unknown host still requires tenant id",
+			request:      &grpcapi.GetNotificationStatusRequest{},
+			metadata:     metadata.Pairs(authorityMetadataKey, "unknown.localhost"),
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+	for _, testCase := range testCases {
+		testHandle.Run(testCase.name, func(testHandle *testing.T) {
+			metadataContext := metadata.NewIncomingContext(context.Background(), testCase.metadata)
+			response, err := interceptor(metadataContext, testCase.request, &grpc.UnaryServerInfo{}, handler)
+			if status.Code(err) != testCase.expectedCode {
+				testHandle.Fatalf("expected %v, got %v", testCase.expectedCode, err)
+			}
+			if testCase.expectedCode == codes.OK && response != testTenantID {
+				testHandle.Fatalf(expectedTenantIDTemplate, testTenantID, response)
+			}
+		})
+	}
+}
+
 func TestBuildTenantInterceptorRejectsMissingTenantID(testHandle *testing.T) {
 	testHandle.Helper()
 	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
@@ -1067,7 +2148,272 @@ func TestBuildTenantInterceptorRejectsUnknownTenant(testHandle *testing.T) {
 	}
 }
 
+func TestBuildTenantInterceptorReportsTenantLookupFailuresAsUnavailable(testHandle *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		testHandle.Fatalf("open database: %v", err)
+	}
+	secretKeeper, err := tenant.NewSecretKeeper(strings.Repeat("a", 64))
+	if err != nil {
+		testHandle.Fatalf("init secret keeper: %v", err)
+	}
+	sqlDatabase, err := database.DB()
+	if err != nil {
+		testHandle.Fatalf("sql database: %v", err)
+	}
+	if err := sqlDatabase.Close(); err != nil {
+		testHandle.Fatalf("close database: %v", err)
+	}
+	interceptor := buildTenantInterceptor(logger, tenant.NewRepository(database, secretKeeper))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		testHandle.Fatal(expectedHandlerNotCalledMessage)
+		return nil, nil
+	}
+
+	for name, callContext := range map[string]context.Context{
+		"tenant id": metadata.NewIncomingContext(context.Background(), metadata.Pairs(tenantMetadataKey, testTenantID)),
+		"host":      metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorityMetadataKey, "test.localhost:50051")),
+	} {
+		testHandle.Run(name, func(testHandle *testing.T) {
+			_, err := interceptor(callContext, &grpcapi.GetNotificationStatusRequest{}, &grpc.UnaryServerInfo{}, handler)
+			if status.Code(err) != codes.Unavailable || status.Convert(err).Message() != tenantLookupFailedMessage {
+				testHandle.Fatalf("expected unavailable for a database failure, got %v", err)
+			}
+		})
+	}
+}
+
+func TestBuildTenantInterceptorEnforcesCallerAllowlist(testHandle *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	allowlistedInterceptor := buildTenantInterceptor(logger, newTestTenantRepositoryWithCallerAllowlist(testHandle, testTenantID, []string{"10.20.0.0/16", "2001:db8:10::/48"}))
+	openInterceptor := buildTenantInterceptor(logger, newTestTenantRepository(testHandle, testTenantID))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	testCases := []struct {
+		name         string
+		interceptor  grpc.UnaryServerInterceptor
+		peerAddress  net.Addr
+		metadata     metadata.MD
+		expectedCode codes.Code
+	}{
+		{name: "ipv4 inside", interceptor: allowlistedInterceptor, peerAddress: &net.TCPAddr{IP: net.ParseIP("10.20.3.4"), Port: 40000}, expectedCode: codes.OK},
+		{name: "ipv4 outside", interceptor: allowlistedInterceptor, peerAddress: &net.TCPAddr{IP: net.ParseIP("10.21.3.4"), Port: 40000}, expectedCode: codes.PermissionDenied},
+		{name: "ipv6 inside", interceptor: allowlistedInterceptor, peerAddress: &net.TCPAddr{IP: net.ParseIP("2001:db8:10::7"), Port: 40000}, expectedCode: codes.OK},
+		{name: "ipv6 outside", interceptor: allowlistedInterceptor, peerAddress: &net.TCPAddr{IP: net.ParseIP("2001:db8:11::7"), Port: 40000}, expectedCode: codes.PermissionDenied},
+		{name: "unix socket peer", interceptor: allowlistedInterceptor, peerAddress: &net.UnixAddr{Name: "@", Net: "unix"}, expectedCode: codes.PermissionDenied},
+		{name: "host-resolved tenant", interceptor: allowlistedInterceptor, peerAddress: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 40000}, metadata: metadata.Pairs(authorityMetadataKey, "test.localhost"), expectedCode: codes.PermissionDenied},
+		{name: "empty allowlist", interceptor: openInterceptor, peerAddress: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 40000}, expectedCode: codes.OK},
+	}
+	for _, testCase := range testCases {
+		testHandle.Run(testCase.name, func(testHandle *testing.T) {
+			request := &grpcapi.GetNotificationStatusRequest{TenantId: testTenantID}
+			if testCase.metadata != nil {
+				request.TenantId = ""
+			}
+			callContext := peer.NewContext(metadata.NewIncomingContext(context.Background(), testCase.metadata), &peer.Peer{Addr: testCase.peerAddress})
+			_, err := testCase.interceptor(callContext, request, &grpc.UnaryServerInfo{}, handler)
+			if status.Code(err) != testCase.expectedCode {
+				testHandle.Fatalf("expected %v, got %v", testCase.expectedCode, err)
+			}
+		})
+	}
+}
+
+func TestBuildTenantInterceptorSkipsCrossTenantMethods(testHandle *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	interceptor := buildTenantInterceptor(logger, newTestTenantRepository(testHandle, testTenantID))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		if _, hasRuntime := tenant.RuntimeFromContext(ctx); hasRuntime {
+			return nil, errors.New("unexpected tenant runtime")
+		}
+		return "ok", nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: grpcapi.NotificationService_ListTenantsStatus_FullMethodName}
+	if _, err := interceptor(context.Background(), &grpcapi.ListTenantsStatusRequest{}, info, handler); err != nil {
+		testHandle.Fatalf("expected the cross-tenant method to skip tenant resolution, got %v", err)
+	}
+}
+
+func TestNotificationServiceServerListTenantsStatus(testHandle *testing.T) {
+	lastDispatchedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	notificationService := &recordingNotificationService{
+		tenantStatuses: []service.TenantStatus{{
+			TenantID:               "tenant-one",
+			DisplayName:            "Tenant One",
+			Status:                 "active",
+			DomainCount:            2,
+			EmailProfileConfigured: true,
+			EmailSenderCached:      true,
+			QueuedCount:            3,
+			ErroredCount:           1,
+			LastDispatchedAt:       &lastDispatchedAt,
+			OldestPendingAgeSec:    5400,
+			PendingAgeExceeded:     true,
+			ProviderLatencies:      []service.ProviderLatency{{Provider: model.NotificationSMS, Dispatches: 4, AverageMs: 120, MaxMs: 300, LastMs: 90}},
+			AttachmentSizes: &service.AttachmentSizeMetrics{
+				Sends:        1,
+				Attachments:  2,
+				TotalBytes:   3072,
+				MaxBytes:     2048,
+				SizeBuckets:  []service.HistogramBucket{{UpperBound: 16 << 10, Count: 2}},
+				CountBuckets: []service.HistogramBucket{{UpperBound: 2, Count: 1}},
+			},
+			Integrity: &service.TenantIntegrity{
+				CheckedAt:                 lastDispatchedAt,
+				AttachmentIntegrityCounts: model.AttachmentIntegrityCounts{OrphanedAttachments: 2, RepairedOrphans: 2},
+			},
+			ProviderBreakers: []service.ProviderBreakerState{{
+				Provider:       model.NotificationSMS,
+				Reason:         "provider account failure: account suspended",
+				OpenedAt:       lastDispatchedAt,
+				NextProbeAt:    lastDispatchedAt.Add(time.Minute),
+				AdminsNotified: true,
+			}},
+		}},
+	}
+	server := &notificationServiceServer{
+		notificationService: notificationService,
+		logger:              slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
+	}
+	adminContext := withGRPCGrant(context.Background(), grpcGrant{scope: config.GRPCTokenScopeAdmin})
+
+	response, err := server.ListTenantsStatus(adminContext, &grpcapi.ListTenantsStatusRequest{})
+	if err != nil {
+		testHandle.Fatalf("ListTenantsStatus error: %v", err)
+	}
+	if len(response.GetTenants()) != 1 {
+		testHandle.Fatalf("expected one tenant, got %+v", response.GetTenants())
+	}
+	tenantStatus := response.GetTenants()[0]
+	if tenantStatus.GetTenantId() != "tenant-one" || tenantStatus.GetDomainCount() != 2 || !tenantStatus.GetEmailProfileConfigured() || tenantStatus.GetSmsProfileConfigured() {
+		testHandle.Fatalf("unexpected tenant status %+v", tenantStatus)
+	}
+	if !tenantStatus.GetEmailSenderCached() || tenantStatus.GetQueuedCount() != 3 || tenantStatus.GetErroredCount() != 1 || !tenantStatus.GetLastDispatchedAt().AsTime().Equal(lastDispatchedAt) {
+		testHandle.Fatalf("unexpected tenant status %+v", tenantStatus)
+	}
+	if tenantStatus.GetOldestPendingAgeSeconds() != 5400 || !tenantStatus.GetPendingAgeExceeded() {
+		testHandle.Fatalf("unexpected pending age %+v", tenantStatus)
+	}
+	if latencies := tenantStatus.GetProviderLatencies(); len(latencies) != 1 || latencies[0].GetProvider() != grpcapi.NotificationType_SMS || latencies[0].GetAverageMs() != 120 {
+		testHandle.Fatalf("unexpected provider latencies %+v", latencies)
+	}
+	if sizes := tenantStatus.GetAttachmentSizes(); sizes.GetAttachments() != 2 || sizes.GetTotalBytes() != 3072 || len(sizes.GetSizeBuckets()) != 1 || sizes.GetSizeBuckets()[0].GetCount() != 2 || sizes.GetCountBuckets()[0].GetUpperBound() != 2 {
+		testHandle.Fatalf("unexpected attachment sizes %+v", sizes)
+	}
+	if integrity := tenantStatus.GetAttachmentIntegrity(); integrity.GetOrphanedAttachments() != 2 || integrity.GetRepairedOrphans() != 2 || !integrity.GetCheckedAt().AsTime().Equal(lastDispatchedAt) {
+		testHandle.Fatalf("unexpected attachment integrity %+v", integrity)
+	}
+	if breakers := tenantStatus.GetProviderBreakers(); len(breakers) != 1 || breakers[0].GetProvider() != grpcapi.NotificationType_SMS ||
+		breakers[0].GetLastProbeAt() != nil || !breakers[0].GetNextProbeAt().AsTime().Equal(lastDispatchedAt.Add(time.Minute)) || !breakers[0].GetAdminsNotified() {
+		testHandle.Fatalf("unexpected provider breakers %+v", breakers)
+	}
+
+	notificationService.err = service.ErrTenantInventoryUnavailable
+	if _, err := server.ListTenantsStatus(adminContext, &grpcapi.ListTenantsStatusRequest{}); status.Code(err) != codes.FailedPrecondition {
+		testHandle.Fatalf("expected failed precondition, got %v", err)
+	}
+}
+
+func TestNotificationServiceServerCacheRPCs(testHandle *testing.T) {
+	notificationService := &recordingNotificationService{
+		cacheStates: []service.CacheState{{Name: service.CacheEmailSender, Entries: 1, Hits: 9, Misses: 2}},
+	}
+	server := &notificationServiceServer{
+		notificationService: notificationService,
+		logger:              slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
+	}
+
+	writeContext := withGRPCGrant(context.Background(), grpcGrant{scope: config.GRPCTokenScopeWrite})
+	if _, err := server.FlushCaches(writeContext, &grpcapi.FlushCachesRequest{}); status.Code(err) != codes.PermissionDenied || notificationService.flushActor != "" {
+		testHandle.Fatalf("expected a write token to be refused, got %v (actor %q)", err, notificationService.flushActor)
+	}
+	if _, err := server.ListCaches(writeContext, &grpcapi.ListCachesRequest{}); status.Code(err) != codes.PermissionDenied {
+		testHandle.Fatalf("expected a write token to be refused, got %v", err)
+	}
+
+	adminContext := withGRPCGrant(context.Background(), grpcGrant{scope: config.GRPCTokenScopeAdmin})
+	listed, err := server.ListCaches(adminContext, &grpcapi.ListCachesRequest{})
+	if err != nil || len(listed.GetCaches()) != 1 || listed.GetCaches()[0].GetName() != service.CacheEmailSender || listed.GetCaches()[0].GetHits() != 9 {
+		testHandle.Fatalf("unexpected caches %+v (%v)", listed, err)
+	}
+	flushed, err := server.FlushCaches(adminContext, &grpcapi.FlushCachesRequest{})
+	if err != nil || len(flushed.GetCaches()) != 1 || notificationService.flushActor != model.CancelActorGRPC {
+		testHandle.Fatalf("expected a flush attributed to grpc, got %+v (%v, actor %q)", flushed, err, notificationService.flushActor)
+	}
+	for _, method := range []string{grpcapi.NotificationService_ListCaches_FullMethodName, grpcapi.NotificationService_FlushCaches_FullMethodName} {
+		if _, crossTenant := crossTenantGRPCMethods[method]; !crossTenant {
+			testHandle.Fatalf("expected %s to skip tenant resolution", method)
+		}
+	}
+}
+
+func TestNotificationServiceServerTransferNotifications(testHandle *testing.T) {
+	notificationService := &recordingNotificationService{
+		transferResult: service.NotificationTransferResult{
+			Transferred: 2,
+			Renamed:     []model.NotificationIDRename{{SourceNotificationID: "shared", DestinationNotificationID: "new-shared"}},
+			Skipped:     []service.SkippedTransfer{{NotificationID: "queued-1", Reason: "awaiting dispatch; set include_queued to move it"}},
+		},
+	}
+	server := &notificationServiceServer{
+		notificationService: notificationService,
+		logger:              slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
+	}
+	request := &grpcapi.TransferNotificationsRequest{
+		SourceTenantId:      "tenant-old",
+		DestinationTenantId: "tenant-new",
+		Statuses:            []grpcapi.Status{grpcapi.Status_SENT},
+		IncludeQueued:       true,
+	}
+	if _, err := server.TransferNotifications(fullAccessGRPCContext(), request); status.Code(err) != codes.PermissionDenied {
+		testHandle.Fatalf("expected permission denied for a write token, got %v", err)
+	}
+	if _, ok := crossTenantGRPCMethods[grpcapi.NotificationService_TransferNotifications_FullMethodName]; !ok {
+		testHandle.Fatalf("expected TransferNotifications to skip tenant resolution")
+	}
+	adminContext := withGRPCGrant(context.Background(), grpcGrant{scope: config.GRPCTokenScopeAdmin})
+
+	response, err := server.TransferNotifications(adminContext, request)
+	if err != nil {
+		testHandle.Fatalf("TransferNotifications error: %v", err)
+	}
+	recorded := notificationService.transferRequest
+	if recorded.SourceTenantID != "tenant-old" || recorded.DestinationTenantID != "tenant-new" || !recorded.IncludeQueued || recorded.Actor != model.CancelActorGRPC ||
+		len(recorded.Filters.Statuses) != 1 || recorded.Filters.Statuses[0] != model.StatusSent {
+		testHandle.Fatalf("unexpected transfer request %+v", recorded)
+	}
+	if response.GetTransferredCount() != 2 || len(response.GetRenamed()) != 1 || response.GetRenamed()[0].GetDestinationNotificationId() != "new-shared" ||
+		len(response.GetSkipped()) != 1 || response.GetSkipped()[0].GetNotificationId() != "queued-1" {
+		testHandle.Fatalf("unexpected transfer response %+v", response)
+	}
+
+	if _, err := server.TransferNotifications(adminContext, &grpcapi.TransferNotificationsRequest{SourceTenantId: "tenant-old"}); status.Code(err) != codes.InvalidArgument {
+		testHandle.Fatalf("expected invalid argument for a missing destination, got %v", err)
+	}
+	for expectedErr, expectedCode := range map[error]codes.Code{
+		service.ErrTransferSameTenant:                            codes.InvalidArgument,
+		fmt.Errorf("tenant runtime: %w", gorm.ErrRecordNotFound): codes.NotFound,
+		service.ErrTenantInventoryUnavailable:                    codes.FailedPrecondition,
+	} {
+		notificationService.err = expectedErr
+		if _, err := server.TransferNotifications(adminContext, request); status.Code(err) != expectedCode {
+			testHandle.Fatalf("expected %v for %v, got %v", expectedCode, expectedErr, err)
+		}
+	}
+}
+
+func fullAccessGRPCContext() context.Context {
+	return withGRPCGrant(context.Background(), grpcGrant{scope: config.GRPCTokenScopeWrite})
+}
+
 func newTestTenantRepository(testHandle *testing.T, tenantID string) *tenant.Repository {
+	testHandle.Helper()
+	return newTestTenantRepositoryWithCallerAllowlist(testHandle, tenantID, nil)
+}
+
+func newTestTenantRepositoryWithCallerAllowlist(testHandle *testing.T, tenantID string, callerAllowlist []string) *tenant.Repository {
 	testHandle.Helper()
 	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	if err != nil {
@@ -1101,6 +2447,7 @@ func newTestTenantRepository(testHandle *testing.T, tenantID string) *tenant.Rep
 					Password:    "smtp-pass",
 					FromAddress: "admin@example.com",
 				},
+				CallerAllowlist: callerAllowlist,
 			},
 		},
 	}
@@ -1111,16 +2458,36 @@ func newTestTenantRepository(testHandle *testing.T, tenantID string) *tenant.Rep
 }
 
 type recordingNotificationService struct {
-	response       model.NotificationResponse
-	listResponses  []model.NotificationResponse
-	err            error
-	listErr        error
-	sentRequest    model.NotificationRequest
-	statusID       string
-	listFilters    model.NotificationListFilters
-	rescheduleID   string
-	rescheduledFor time.Time
-	cancelID       string
+	response         model.NotificationResponse
+	listResponses    []model.NotificationResponse
+	err              error
+	listErr          error
+	sentRequest      model.NotificationRequest
+	groupRequest     model.NotificationGroupRequest
+	groupResponse    model.NotificationGroupResponse
+	groupID          string
+	statusID         string
+	renderedID       string
+	listFilters      model.NotificationListFilters
+	rescheduleID     string
+	rescheduledFor   time.Time
+	cancelID         string
+	cancelReason     string
+	cancelActor      string
+	costSummary      model.CostSummary
+	costSummaryRange model.CostSummaryRange
+	capabilities     service.Capabilities
+	tenantStatuses   []service.TenantStatus
+	deliveryCheck    service.DeliveryCheck
+	deliveryType     model.NotificationType
+	drainStatus      service.DrainStatus
+	drainCalls       int
+	drained          chan struct{}
+	sizeEstimate     service.MessageSizeEstimate
+	transferRequest  service.NotificationTransferRequest
+	transferResult   service.NotificationTransferResult
+	cacheStates      []service.CacheState
+	flushActor       string
 }
 
 func (service *recordingNotificationService) SendNotification(_ context.Context, request model.NotificationRequest) (model.NotificationResponse, error) {
@@ -1131,6 +2498,22 @@ func (service *recordingNotificationService) SendNotification(_ context.Context,
 	return service.response, nil
 }
 
+func (service *recordingNotificationService) SendNotificationGroup(_ context.Context, request model.NotificationGroupRequest) (model.NotificationGroupResponse, error) {
+	service.groupRequest = request
+	if service.err != nil {
+		return model.NotificationGroupResponse{}, service.err
+	}
+	return service.groupResponse, nil
+}
+
+func (service *recordingNotificationService) GetNotificationGroup(_ context.Context, groupID string) (model.NotificationGroupResponse, error) {
+	service.groupID = groupID
+	if service.err != nil {
+		return model.NotificationGroupResponse{}, service.err
+	}
+	return service.groupResponse, nil
+}
+
 func (service *recordingNotificationService) GetNotificationStatus(_ context.Context, notificationID string) (model.NotificationResponse, error) {
 	service.statusID = notificationID
 	if service.err != nil {
@@ -1139,6 +2522,14 @@ func (service *recordingNotificationService) GetNotificationStatus(_ context.Con
 	return service.response, nil
 }
 
+func (service *recordingNotificationService) GetRenderedNotification(_ context.Context, notificationID string) (model.NotificationResponse, error) {
+	service.renderedID = notificationID
+	if service.err != nil {
+		return model.NotificationResponse{}, service.err
+	}
+	return service.response, nil
+}
+
 func (service *recordingNotificationService) ListNotifications(_ context.Context, filters model.NotificationListFilters) ([]model.NotificationResponse, error) {
 	service.listFilters = filters
 	if service.listErr != nil {
@@ -1155,6 +2546,21 @@ func (service *recordingNotificationService) ListNotificationsPage(_ context.Con
 	return model.NotificationListResponsePage{Notifications: service.listResponses}, nil
 }
 
+func (service *recordingNotificationService) EstimateNotificationSize(_ context.Context, request model.NotificationRequest) (service.MessageSizeEstimate, error) {
+	service.sentRequest = request
+	return service.sizeEstimate, service.err
+}
+
+func (service *recordingNotificationService) StreamNotifications(_ context.Context, filters model.NotificationListFilters, visit func(model.NotificationResponse) error) error {
+	service.listFilters = filters
+	for _, response := range service.listResponses {
+		if err := visit(response); err != nil {
+			return err
+		}
+	}
+	return service.listErr
+}
+
 func (service *recordingNotificationService) ListNotificationsAll(_ context.Context, filters model.NotificationListFilters) ([]model.NotificationResponse, error) {
 	service.listFilters = filters
 	if service.listErr != nil {
@@ -1172,16 +2578,86 @@ func (service *recordingNotificationService) RescheduleNotification(_ context.Co
 	return service.response, nil
 }
 
-func (service *recordingNotificationService) CancelNotification(_ context.Context, notificationID string) (model.NotificationResponse, error) {
+func (service *recordingNotificationService) CancelNotification(_ context.Context, notificationID string, reason string, actor string) (model.NotificationResponse, error) {
 	service.cancelID = notificationID
+	service.cancelReason = reason
+	service.cancelActor = actor
 	if service.err != nil {
 		return model.NotificationResponse{}, service.err
 	}
 	return service.response, nil
 }
 
+func (service *recordingNotificationService) SetLegalHold(context.Context, string, bool, string) (model.NotificationResponse, error) {
+	return service.response, service.err
+}
+
+func (service *recordingNotificationService) GetCostSummary(_ context.Context, summaryRange model.CostSummaryRange) (model.CostSummary, error) {
+	service.costSummaryRange = summaryRange
+	if service.err != nil {
+		return model.CostSummary{}, service.err
+	}
+	return service.costSummary, nil
+}
+
 func (service *recordingNotificationService) StartRetryWorker(context.Context) {}
 
+func (service *recordingNotificationService) StartDailyReportWorker(context.Context) {}
+
+func (service *recordingNotificationService) StartIntegrityWorker(context.Context) {}
+
+func (service *recordingNotificationService) StartWebhookWorker(context.Context) {}
+
+func (service *recordingNotificationService) StartRetentionWorker(context.Context) {}
+
+func (service *recordingNotificationService) DispatchPacing(context.Context) ([]service.DispatchPacingState, error) {
+	return nil, nil
+}
+
+func (service *recordingNotificationService) GetCapabilities(context.Context) (service.Capabilities, error) {
+	return service.capabilities, service.err
+}
+
+func (service *recordingNotificationService) TestTenantDelivery(_ context.Context, notificationType model.NotificationType) (service.DeliveryCheck, error) {
+	service.deliveryType = notificationType
+	return service.deliveryCheck, service.err
+}
+
+func (service *recordingNotificationService) CacheStates(context.Context) []service.CacheState {
+	return service.cacheStates
+}
+
+func (service *recordingNotificationService) FlushCaches(_ context.Context, actor string) []service.CacheState {
+	service.flushActor = actor
+	return service.cacheStates
+}
+
+func (service *recordingNotificationService) ListTenantsStatus(context.Context) ([]service.TenantStatus, error) {
+	return service.tenantStatuses, service.err
+}
+
+func (service *recordingNotificationService) TransferNotifications(_ context.Context, request service.NotificationTransferRequest) (service.NotificationTransferResult, error) {
+	service.transferRequest = request
+	return service.transferResult, service.err
+}
+
+func (service *recordingNotificationService) Drain(context.Context) (service.DrainStatus, error) {
+	service.drainCalls++
+	return service.drainStatus, service.err
+}
+
+func (service *recordingNotificationService) GetDrainStatus(context.Context) (service.DrainStatus, error) {
+	return service.drainStatus, service.err
+}
+
+func (service *recordingNotificationService) Draining() bool {
+	return service.drainStatus.Draining
+}
+
+func (service *recordingNotificationService) Drained() <-chan struct{} {
+	return service.drained
+}
+
 func configSMTPSubmission(listenAddr string, tlsListenAddr string) config.SMTPSubmissionConfig {
 	return config.SMTPSubmissionConfig{
 		Hostname:      "smtp.example.com",
@@ -1251,10 +2727,10 @@ func newServerTestDependencies(cfg config.Config) (*serverTestState, serverDepen
 		newLogger: func(string) *slog.Logger {
 			return slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
 		},
-		initDB: func(string, *slog.Logger) (*gorm.DB, error) {
+		initDB: func(string, model.QueryTimeouts, *slog.Logger) (*gorm.DB, error) {
 			return nil, nil
 		},
-		newSecretKeeper: func(string) (*tenant.SecretKeeper, error) {
+		newSecretKeeper: func(config.Config) (*tenant.SecretKeeper, error) {
 			return &tenant.SecretKeeper{}, nil
 		},
 		bootstrapTenants: func(context.Context, *gorm.DB, *tenant.SecretKeeper, tenant.BootstrapConfig) error {
@@ -1277,9 +2753,9 @@ func newServerTestDependencies(cfg config.Config) (*serverTestState, serverDepen
 		newNotificationService: func(*gorm.DB, *slog.Logger, config.Config, *tenant.Repository) service.NotificationService {
 			return &recordingNotificationService{}
 		},
-		loadTLSConfig: func(string, string) (*tls.Config, error) {
+		loadCertificate: func(string, string, *slog.Logger) (certificateWatcher, error) {
 			state.tlsLoaded = true
-			return &tls.Config{MinVersion: tls.VersionTLS12}, nil
+			return fakeCertificateWatcher{}, nil
 		},
 		newSMTPRelay: func(*slog.Logger, config.Config) smtpsubmission.RawRelay {
 			return noopRawRelay{}
@@ -1305,12 +2781,12 @@ func newServerTestDependencies(cfg config.Config) (*serverTestState, serverDepen
 		listen: func(string, string) (net.Listener, error) {
 			return fakeListener{}, nil
 		},
-		serveGRPC: func(listener net.Listener, svc service.NotificationService, repo *tenant.Repository, logger *slog.Logger, token string) error {
+		serveGRPC: func(listener net.Listener, svc service.NotificationService, _ queueIntakeStatsReporter, repo *tenant.Repository, logger *slog.Logger, grpcTokens []config.GRPCTokenConfig, _ config.GRPCKeepaliveConfig) error {
 			_ = listener
 			_ = svc
 			_ = repo
 			_ = logger
-			if token != cfg.GRPCAuthToken {
+			if len(grpcTokens) != 1 || grpcTokens[0].Token != cfg.GRPCAuthToken || grpcTokens[0].Scope != config.GRPCTokenScopeWrite {
 				return errors.New("unexpected token")
 			}
 			state.grpcServed = true
@@ -1364,6 +2840,23 @@ func (noopRawRelay) Relay(context.Context, smtpsubmission.RawMessage) error {
 	return nil
 }
 
+type fakeCertificateWatcher struct{}
+
+func (fakeCertificateWatcher) TLSConfig() *tls.Config {
+	return &tls.Config{
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) { return &tls.Certificate{}, nil },
+		MinVersion:     tls.VersionTLS12,
+	}
+}
+
+func (fakeCertificateWatcher) Watch(ctx context.Context, _ time.Duration) {
+	<-ctx.Done()
+}
+
+func (fakeCertificateWatcher) Status() certreload.Status {
+	return certreload.Status{}
+}
+
 type noopForwarder struct{}
 
 func (noopForwarder) Forward(context.Context, smtpforwarding.Route, smtpforwarding.Message) error {
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/pkg/grpcapi"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// grpcNotificationFields selects NotificationResponse fields by their proto names, the
+// gRPC counterpart of model.NotificationFieldSet. Nil selects every field.
+type grpcNotificationFields map[protoreflect.Name]struct{}
+
+// newGRPCNotificationFields validates the fields option of a status or list request
+// against the NotificationResponse descriptor.
+func newGRPCNotificationFields(names []string) (grpcNotificationFields, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	descriptorFields := (&grpcapi.NotificationResponse{}).ProtoReflect().Descriptor().Fields()
+	fields := make(grpcNotificationFields, len(names))
+	for _, candidate := range names {
+		fieldName := protoreflect.Name(strings.TrimSpace(candidate))
+		if fieldName == "" {
+			return nil, fmt.Errorf("%w: empty field name", model.ErrInvalidNotificationFields)
+		}
+		if descriptorFields.ByName(fieldName) == nil {
+			return nil, fmt.Errorf("%w: unknown field %q", model.ErrInvalidNotificationFields, fieldName)
+		}
+		fields[fieldName] = struct{}{}
+	}
+	return fields, nil
+}
+
+// project clears every field of response the selection leaves out.
+func (fields grpcNotificationFields) project(response *grpcapi.NotificationResponse) *grpcapi.NotificationResponse {
+	if fields == nil {
+		return response
+	}
+	message := response.ProtoReflect()
+	descriptorFields := message.Descriptor().Fields()
+	for index := 0; index < descriptorFields.Len(); index++ {
+		if _, selected := fields[descriptorFields.Get(index).Name()]; !selected {
+			message.Clear(descriptorFields.Get(index))
+		}
+	}
+	return response
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/config"
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/queueintake"
+	"github.com/tyemirov/pinguin/internal/tenant"
+	"github.com/tyemirov/pinguin/pkg/grpcapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// queueIntakeStopTimeout bounds how long shutdown waits for in-flight queued requests.
+const queueIntakeStopTimeout = 10 * time.Second
+
+type queueIntakeRunner interface {
+	Start(context.Context) error
+	Stop(context.Context) error
+	Stats() queueintake.Stats
+}
+
+type queueIntakeStatsReporter interface {
+	Stats() queueintake.Stats
+}
+
+// permanentQueueIntakeCodes are SendNotification outcomes a redelivery cannot change.
+var permanentQueueIntakeCodes = map[codes.Code]struct{}{
+	codes.InvalidArgument:    {},
+	codes.NotFound:           {},
+	codes.FailedPrecondition: {},
+	codes.PermissionDenied:   {},
+	codes.OutOfRange:         {},
+	codes.Unauthenticated:    {},
+}
+
+// queueIntakeHandler sends queued requests through the gRPC SendNotification path, so
+// they get the same validation and limits as direct calls, and records each
+// idempotency key so a redelivered message does not send twice.
+type queueIntakeHandler struct {
+	server     *notificationServiceServer
+	tenantRepo *tenant.Repository
+	database   *gorm.DB
+	grant      grpcGrant
+	logger     *slog.Logger
+}
+
+func newQueueIntakeHandler(server *notificationServiceServer, tenantRepo *tenant.Repository, database *gorm.DB, intakeConfig config.QueueIntakeConfig, logger *slog.Logger) *queueIntakeHandler {
+	var tenantIDs map[string]struct{}
+	if len(intakeConfig.TenantIDs) > 0 {
+		tenantIDs = make(map[string]struct{}, len(intakeConfig.TenantIDs))
+		for _, tenantID := range intakeConfig.TenantIDs {
+			tenantIDs[tenantID] = struct{}{}
+		}
+	}
+	return &queueIntakeHandler{
+		server:     server,
+		tenantRepo: tenantRepo,
+		database:   database,
+		grant:      grpcGrant{scope: config.GRPCTokenScopeWrite, tenantIDs: tenantIDs},
+		logger:     logger,
+	}
+}
+
+func (handler *queueIntakeHandler) Handle(ctx context.Context, queued *grpcapi.QueuedNotificationRequest) (queueintake.Result, error) {
+	request := queued.GetRequest()
+	tenantID := strings.TrimSpace(request.GetTenantId())
+	runtimeCfg, err := handler.tenantRepo.ResolveByID(ctx, tenantID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, tenant.ErrInvalidTenantID) {
+			return queueintake.Result{}, fmt.Errorf("%w: %s: %v", queueintake.ErrPermanent, tenantNotFoundMessage, err)
+		}
+		return queueintake.Result{}, err
+	}
+	claimed, existing, err := model.ClaimQueueIntakeKey(ctx, handler.database, runtimeCfg.Tenant.ID, queued.GetIdempotencyKey(), time.Now().UTC())
+	if err != nil {
+		return queueintake.Result{}, err
+	}
+	if !claimed {
+		if existing.NotificationID == "" {
+			return queueintake.Result{}, errors.New("queue intake: idempotency key is being processed by another delivery")
+		}
+		return queueintake.Result{NotificationID: existing.NotificationID, Duplicate: true}, nil
+	}
+
+	// Immediate failures stay on the retry worker; requeueing here would send twice.
+	request.FailOnImmediateError = proto.Bool(false)
+	callCtx := withGRPCGrant(tenant.WithRuntime(ctx, runtimeCfg), handler.grant)
+	response, err := handler.server.SendNotification(callCtx, request)
+	if err != nil {
+		if releaseErr := model.ReleaseQueueIntakeKey(context.WithoutCancel(ctx), handler.database, runtimeCfg.Tenant.ID, queued.GetIdempotencyKey()); releaseErr != nil {
+			handler.logger.Error("queue_intake_release_failed", "tenant_id", runtimeCfg.Tenant.ID, "error", releaseErr)
+		}
+		if _, permanent := permanentQueueIntakeCodes[status.Code(err)]; permanent {
+			return queueintake.Result{}, fmt.Errorf("%w: %v", queueintake.ErrPermanent, err)
+		}
+		return queueintake.Result{}, err
+	}
+	if err := model.ConfirmQueueIntakeKey(context.WithoutCancel(ctx), handler.database, runtimeCfg.Tenant.ID, queued.GetIdempotencyKey(), response.GetNotificationId()); err != nil {
+		// The notification is stored. A republished duplicate finds the key unconfirmed and
+		// is requeued until it is dead-lettered, so it is never sent twice.
+		handler.logger.Error("queue_intake_confirm_failed", "tenant_id", runtimeCfg.Tenant.ID, "notification_id", response.GetNotificationId(), "error", err)
+	}
+	return queueintake.Result{NotificationID: response.GetNotificationId()}, nil
+}
+
+func mapQueueIntakeStats(reporter queueIntakeStatsReporter) *grpcapi.QueueIntakeStats {
+	if reporter == nil {
+		return nil
+	}
+	stats := reporter.Stats()
+	var lastReceivedAt *timestamppb.Timestamp
+	if !stats.LastReceivedAt.IsZero() {
+		lastReceivedAt = timestamppb.New(stats.LastReceivedAt)
+	}
+	return &grpcapi.QueueIntakeStats{
+		Received:       stats.Received,
+		Accepted:       stats.Accepted,
+		Duplicates:     stats.Duplicates,
+		DeadLettered:   stats.DeadLettered,
+		Requeued:       stats.Requeued,
+		LastReceivedAt: lastReceivedAt,
+	}
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/tyemirov/pinguin/internal/config"
+	"github.com/tyemirov/pinguin/internal/db"
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/queueintake"
+	"github.com/tyemirov/pinguin/internal/service"
+	"github.com/tyemirov/pinguin/internal/tenant"
+	"github.com/tyemirov/pinguin/pkg/grpcapi"
+	"google.golang.org/protobuf/encoding/protojson"
+	"gorm.io/gorm"
+)
+
+type countingEmailSender struct {
+	sends atomic.Int32
+}
+
+func (sender *countingEmailSender) SendEmail(context.Context, string, string, string, []model.EmailAttachment) error {
+	sender.sends.Add(1)
+	return nil
+}
+
+type stubQueueIntakeRunner struct {
+	startErr error
+	started  bool
+	stopped  bool
+	stats    queueintake.Stats
+}
+
+func (runner *stubQueueIntakeRunner) Start(context.Context) error {
+	runner.started = true
+	return runner.startErr
+}
+
+func (runner *stubQueueIntakeRunner) Stop(context.Context) error {
+	runner.stopped = true
+	return nil
+}
+
+func (runner *stubQueueIntakeRunner) Stats() queueintake.Stats {
+	return runner.stats
+}
+
+func TestQueueIntakeHandlerSendsOncePerIdempotencyKey(testHandle *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	database, err := db.InitDB(filepath.Join(testHandle.TempDir(), "pinguin.db"), logger)
+	if err != nil {
+		testHandle.Fatalf("init db: %v", err)
+	}
+	tenantRepo := bootstrapQueueIntakeTenant(testHandle, database)
+	sender := &countingEmailSender{}
+	notificationSvc := service.NewNotificationServiceWithSenders(database, logger, config.Config{MaxRetries: 3, RetryIntervalSec: 30}, tenantRepo, sender, nil)
+	handler := newQueueIntakeHandler(&notificationServiceServer{notificationService: notificationSvc, logger: logger}, tenantRepo, database, config.QueueIntakeConfig{}, logger)
+	ctx := context.Background()
+
+	first, err := handler.Handle(ctx, queuedEmailRequest("order-1", testTenantID, "user@example.com"))
+	if err != nil || first.Duplicate || first.NotificationID == "" {
+		testHandle.Fatalf("expected the first delivery to send, result=%+v err=%v", first, err)
+	}
+	notification, err := model.GetNotificationByID(ctx, database, testTenantID, first.NotificationID)
+	if err != nil || notification.Status != model.StatusSent {
+		testHandle.Fatalf("expected a sent notification, got %+v err=%v", notification, err)
+	}
+	second, err := handler.Handle(ctx, queuedEmailRequest("order-1", testTenantID, "user@example.com"))
+	if err != nil || !second.Duplicate || second.NotificationID != first.NotificationID {
+		testHandle.Fatalf("expected the redelivery to resolve to the first notification, result=%+v err=%v", second, err)
+	}
+	if sends := sender.sends.Load(); sends != 1 {
+		testHandle.Fatalf("expected one email, got %d", sends)
+	}
+
+	if _, err := handler.Handle(ctx, queuedEmailRequest("order-2", testTenantID, "")); !errors.Is(err, queueintake.ErrPermanent) {
+		testHandle.Fatalf("expected an invalid request to be permanent, got %v", err)
+	}
+	released, err := handler.Handle(ctx, queuedEmailRequest("order-2", testTenantID, "user@example.com"))
+	if err != nil || released.Duplicate {
+		testHandle.Fatalf("expected a rejected request to release its key, result=%+v err=%v", released, err)
+	}
+
+	if _, err := handler.Handle(ctx, queuedEmailRequest("order-3", "missing-tenant", "user@example.com")); !errors.Is(err, queueintake.ErrPermanent) {
+		testHandle.Fatalf("expected an unknown tenant to be permanent, got %v", err)
+	}
+	restricted := newQueueIntakeHandler(handler.server, tenantRepo, database, config.QueueIntakeConfig{TenantIDs: []string{"other-tenant"}}, logger)
+	if _, err := restricted.Handle(ctx, queuedEmailRequest("order-4", testTenantID, "user@example.com")); !errors.Is(err, queueintake.ErrPermanent) {
+		testHandle.Fatalf("expected a tenant outside queueIntake.tenants to be permanent, got %v", err)
+	}
+}
+
+// TestQueueIntakeSendsPublishedNotificationsOnce runs a message from an embedded
+// JetStream server through the consumer, the intake handler and the notification
+// service, then republishes it to check the idempotency key holds end to end.
+func TestQueueIntakeSendsPublishedNotificationsOnce(testHandle *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	database, err := db.InitDB(filepath.Join(testHandle.TempDir(), "pinguin.db"), logger)
+	if err != nil {
+		testHandle.Fatalf("init db: %v", err)
+	}
+	tenantRepo := bootstrapQueueIntakeTenant(testHandle, database)
+	sender := &countingEmailSender{}
+	notificationSvc := service.NewNotificationServiceWithSenders(database, logger, config.Config{MaxRetries: 3, RetryIntervalSec: 30}, tenantRepo, sender, nil)
+	handler := newQueueIntakeHandler(&notificationServiceServer{notificationService: notificationSvc, logger: logger}, tenantRepo, database, config.QueueIntakeConfig{}, logger)
+
+	url, stream := startQueueIntakeJetStream(testHandle, "PINGUIN", "pinguin.send")
+	consumer, err := queueintake.NewConsumer(queueintake.Config{
+		URL:               url,
+		Stream:            "PINGUIN",
+		Subject:           "pinguin.send",
+		Durable:           "pinguin",
+		DeadLetterSubject: "pinguin.dead",
+		Concurrency:       1,
+		RequeueDelay:      10 * time.Millisecond,
+		MaxDeliver:        3,
+		AckWait:           5 * time.Second,
+		Handler:           handler,
+		Logger:            logger,
+	})
+	if err != nil {
+		testHandle.Fatalf("new consumer: %v", err)
+	}
+	if err := consumer.Start(context.Background()); err != nil {
+		testHandle.Fatalf("start consumer: %v", err)
+	}
+	testHandle.Cleanup(func() {
+		stopCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = consumer.Stop(stopCtx)
+	})
+
+	payload, err := protojson.Marshal(queuedEmailRequest("order-1", testTenantID, "user@example.com"))
+	if err != nil {
+		testHandle.Fatalf("marshal: %v", err)
+	}
+	publishQueuedRequest(testHandle, stream, "pinguin.send", payload)
+	waitForQueueIntakeStats(testHandle, consumer, func(stats queueintake.Stats) bool { return stats.Accepted == 1 })
+	if sends := sender.sends.Load(); sends != 1 {
+		testHandle.Fatalf("expected one email, got %d", sends)
+	}
+	notifications, err := model.ListNotifications(context.Background(), database, testTenantID, model.NotificationListFilters{})
+	if err != nil || len(notifications) != 1 || notifications[0].Status != model.StatusSent {
+		testHandle.Fatalf("expected one sent notification, got %+v err=%v", notifications, err)
+	}
+
+	publishQueuedRequest(testHandle, stream, "pinguin.send", payload)
+	waitForQueueIntakeStats(testHandle, consumer, func(stats queueintake.Stats) bool { return stats.Duplicates == 1 })
+	if sends := sender.sends.Load(); sends != 1 {
+		testHandle.Fatalf("expected the republished message not to send again, got %d emails", sends)
+	}
+	if stats := consumer.Stats(); stats.Accepted != 1 || stats.DeadLettered != 0 || stats.Requeued != 0 {
+		testHandle.Fatalf("unexpected queue intake stats %+v", stats)
+	}
+}
+
+func TestRunServerStartsAndStopsQueueIntake(testHandle *testing.T) {
+	cfg := serverTestConfig()
+	cfg.QueueIntake = config.QueueIntakeConfig{Enabled: true, URL: "nats://127.0.0.1:4222", Stream: "PINGUIN", Subject: "pinguin.send", Durable: "pinguin", DeadLetterSubject: "pinguin.dead", RequeueDelaySec: 5, AckWaitSec: 30}
+	_, dependencies := newServerTestDependencies(cfg)
+	runner := &stubQueueIntakeRunner{}
+	var consumerConfig queueintake.Config
+	dependencies.newQueueIntakeConsumer = func(intakeConfig queueintake.Config) (queueIntakeRunner, error) {
+		consumerConfig = intakeConfig
+		return runner, nil
+	}
+	var servedReporter queueIntakeStatsReporter
+	dependencies.serveGRPC = func(_ net.Listener, _ service.NotificationService, reporter queueIntakeStatsReporter, _ *tenant.Repository, _ *slog.Logger, _ []config.GRPCTokenConfig, _ config.GRPCKeepaliveConfig) error {
+		servedReporter = reporter
+		if runner.stopped {
+			testHandle.Fatal("expected queue intake to run while gRPC serves")
+		}
+		return nil
+	}
+
+	if exitCode := runServer(nil, dependencies); exitCode != 0 {
+		testHandle.Fatalf("expected success exit code, got %d", exitCode)
+	}
+	if consumerConfig.Subject != "pinguin.send" || consumerConfig.RequeueDelay != 5*time.Second || consumerConfig.AckWait != 30*time.Second || consumerConfig.Handler == nil {
+		testHandle.Fatalf("unexpected consumer config %+v", consumerConfig)
+	}
+	if !runner.started || !runner.stopped || servedReporter != runner {
+		testHandle.Fatalf("expected queue intake to start, report stats and stop, runner=%+v", runner)
+	}
+
+	runner = &stubQueueIntakeRunner{startErr: errors.New("nats unavailable")}
+	if exitCode := runServer(nil, dependencies); exitCode != 1 {
+		testHandle.Fatalf("expected a failed queue intake start to exit 1, got %d", exitCode)
+	}
+}
+
+func TestListTenantsStatusIncludesQueueIntakeStats(testHandle *testing.T) {
+	lastReceivedAt := time.Date(2026, 7, 1, 9, 0, 0, 0, time.UTC)
+	server := &notificationServiceServer{
+		notificationService: &recordingNotificationService{},
+		queueIntake:         &stubQueueIntakeRunner{stats: queueintake.Stats{Received: 5, Accepted: 3, Duplicates: 1, DeadLettered: 1, Requeued: 2, LastReceivedAt: lastReceivedAt}},
+		logger:              slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
+	}
+	adminContext := withGRPCGrant(context.Background(), grpcGrant{scope: config.GRPCTokenScopeAdmin})
+	response, err := server.ListTenantsStatus(adminContext, &grpcapi.ListTenantsStatusRequest{})
+	if err != nil {
+		testHandle.Fatalf("ListTenantsStatus error: %v", err)
+	}
+	intake := response.GetQueueIntake()
+	if intake.GetReceived() != 5 || intake.GetAccepted() != 3 || intake.GetDuplicates() != 1 || intake.GetDeadLettered() != 1 || intake.GetRequeued() != 2 || !intake.GetLastReceivedAt().AsTime().Equal(lastReceivedAt) {
+		testHandle.Fatalf("unexpected queue intake stats %+v", intake)
+	}
+
+	server.queueIntake = nil
+	response, err = server.ListTenantsStatus(adminContext, &grpcapi.ListTenantsStatusRequest{})
+	if err != nil || response.GetQueueIntake() != nil {
+		testHandle.Fatalf("expected no queue intake stats when disabled, got %+v err=%v", response.GetQueueIntake(), err)
+	}
+}
+
+func queuedEmailRequest(idempotencyKey string, tenantID string, recipient string) *grpcapi.QueuedNotificationRequest {
+	return &grpcapi.QueuedNotificationRequest{
+		IdempotencyKey: idempotencyKey,
+		Request: &grpcapi.NotificationRequest{
+			TenantId:         tenantID,
+			NotificationType: grpcapi.NotificationType_EMAIL,
+			Recipient:        recipient,
+			Subject:          "Order shipped",
+			Message:          "Your order is on its way",
+		},
+	}
+}
+
+func bootstrapQueueIntakeTenant(testHandle *testing.T, database *gorm.DB) *tenant.Repository {
+	testHandle.Helper()
+	secretKeeper, err := tenant.NewSecretKeeper(strings.Repeat("a", 64))
+	if err != nil {
+		testHandle.Fatalf("init secret keeper: %v", err)
+	}
+	enabled := true
+	if err := tenant.Bootstrap(context.Background(), database, secretKeeper, tenant.BootstrapConfig{Tenants: []tenant.BootstrapTenant{{
+		ID:          testTenantID,
+		DisplayName: "Test Tenant",
+		Enabled:     &enabled,
+		Domains:     []string{"test.localhost"},
+		EmailProfile: tenant.BootstrapEmailProfile{
+			Host:        "smtp.localhost",
+			Port:        587,
+			Username:    "smtp-user",
+			Password:    "smtp-pass",
+			FromAddress: "admin@example.com",
+		},
+	}}}); err != nil {
+		testHandle.Fatalf("bootstrap tenants: %v", err)
+	}
+	return tenant.NewRepository(database, secretKeeper)
+}
+
+// startQueueIntakeJetStream starts an embedded JetStream server with a stream over
+// subject and returns its URL and a JetStream client.
+func startQueueIntakeJetStream(testHandle *testing.T, streamName string, subject string) (string, jetstream.JetStream) {
+	testHandle.Helper()
+	server, err := natsserver.NewServer(&natsserver.Options{Host: "127.0.0.1", Port: -1, JetStream: true, StoreDir: testHandle.TempDir(), NoLog: true, NoSigs: true})
+	if err != nil {
+		testHandle.Fatalf("nats server: %v", err)
+	}
+	go server.Start()
+	testHandle.Cleanup(server.Shutdown)
+	if !server.ReadyForConnections(5 * time.Second) {
+		testHandle.Fatalf("nats server not ready")
+	}
+	connection, err := nats.Connect(server.ClientURL())
+	if err != nil {
+		testHandle.Fatalf("connect: %v", err)
+	}
+	testHandle.Cleanup(connection.Close)
+	stream, err := jetstream.New(connection)
+	if err != nil {
+		testHandle.Fatalf("jetstream: %v", err)
+	}
+	if _, err := stream.CreateStream(context.Background(), jetstream.StreamConfig{Name: streamName, Subjects: []string{subject}}); err != nil {
+		testHandle.Fatalf("create stream: %v", err)
+	}
+	return server.ClientURL(), stream
+}
+
+func publishQueuedRequest(testHandle *testing.T, stream jetstream.JetStream, subject string, payload []byte) {
+	testHandle.Helper()
+	if _, err := stream.Publish(context.Background(), subject, payload); err != nil {
+		testHandle.Fatalf("publish: %v", err)
+	}
+}
+
+func waitForQueueIntakeStats(testHandle *testing.T, consumer *queueintake.Consumer, done func(queueintake.Stats) bool) {
+	testHandle.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !done(consumer.Stats()) {
+		if time.Now().After(deadline) {
+			testHandle.Fatalf("queue intake did not settle, stats %+v", consumer.Stats())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
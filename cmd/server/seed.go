@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/seed"
+)
+
+const seedCommandName = "seed"
+
+// seedOutput receives the sample tenant config printed by seed --print-tenants.
+var seedOutput io.Writer = os.Stdout
+
+// runSeed implements "pinguin-server seed", which fills the configured development
+// database with the sample tenants and generated notifications from internal/seed.
+func runSeed(args []string, dependencies serverDependencies) int {
+	flags := flag.NewFlagSet("pinguin-server seed", flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	seedValue := flags.Uint64("seed", 1, "seed for the generated data; equal seeds give equal rows")
+	count := flags.Int("count", seed.DefaultCount, "number of notifications to generate")
+	force := flags.Bool("force", false, "seed a database that already holds tenants or notifications")
+	baseTimeValue := flags.String("base-time", "", "RFC 3339 time the generated history ends at (default: today 00:00 UTC)")
+	printTenants := flags.Bool("print-tenants", false, "print the sample tenant config for tenantConfigPath and exit")
+	if parseErr := flags.Parse(args); parseErr != nil {
+		if errors.Is(parseErr, flag.ErrHelp) {
+			return 0
+		}
+		return 1
+	}
+	if *printTenants {
+		if _, err := seedOutput.Write(seed.TenantsYAML()); err != nil {
+			return 1
+		}
+		return 0
+	}
+	if *count <= 0 {
+		fmt.Fprintln(os.Stderr, "--count must be positive")
+		return 1
+	}
+	baseTime := time.Now().UTC().Truncate(24 * time.Hour)
+	if *baseTimeValue != "" {
+		parsed, err := time.Parse(time.RFC3339, *baseTimeValue)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "--base-time must be an RFC 3339 time: %v\n", err)
+			return 1
+		}
+		baseTime = parsed
+	}
+
+	configuration, configErr := dependencies.loadConfig()
+	if configErr != nil {
+		dependencies.newLogger("INFO").Error("Configuration error", "detail", configErr.Error())
+		return 1
+	}
+	logger := dependencies.newLogger(configuration.LogLevel)
+	databaseInstance, dbErr := dependencies.initDB(configuration.DatabasePath, queryTimeouts(configuration), logger)
+	if dbErr != nil {
+		logger.Error("Failed to initialize DB", "error", dbErr)
+		return 1
+	}
+	secretKeeper, keeperErr := dependencies.newSecretKeeper(configuration)
+	if keeperErr != nil {
+		logger.Error("Failed to initialize secret keeper", "error", keeperErr)
+		return 1
+	}
+	summary, seedErr := dependencies.seedDatabase(context.Background(), databaseInstance, secretKeeper, seed.Options{
+		Seed:           *seedValue,
+		Count:          *count,
+		BaseTime:       baseTime,
+		Force:          *force,
+		BatchChunkSize: configuration.NotificationBatchChunkSize,
+	})
+	if seedErr != nil {
+		logger.Error("Failed to seed database", "error", seedErr)
+		return 1
+	}
+	logger.Info("Seeded database",
+		"database_path", configuration.DatabasePath,
+		"tenants", summary.Tenants,
+		"notifications", summary.Notifications,
+		"attachments", summary.Attachments,
+		"skipped", summary.Skipped,
+	)
+	return 0
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/seed"
+	"github.com/tyemirov/pinguin/internal/tenant"
+	"gorm.io/gorm"
+)
+
+func TestRunServerSeedPassesFlagsToSeeder(testHandle *testing.T) {
+	state, dependencies := newServerTestDependencies(serverTestConfig())
+	var received seed.Options
+	dependencies.seedDatabase = func(_ context.Context, _ *gorm.DB, _ *tenant.SecretKeeper, options seed.Options) (seed.Summary, error) {
+		received = options
+		return seed.Summary{Tenants: 2, Notifications: options.Count}, nil
+	}
+
+	exitCode := runServer([]string{"seed", "--seed", "42", "--count", "25", "--force", "--base-time", "2026-03-02T00:00:00Z"}, dependencies)
+	if exitCode != 0 {
+		testHandle.Fatalf("expected seed success, got %d", exitCode)
+	}
+	expected := seed.Options{Seed: 42, Count: 25, BaseTime: time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC), Force: true}
+	if !received.BaseTime.Equal(expected.BaseTime) || received.Seed != expected.Seed || received.Count != expected.Count || !received.Force {
+		testHandle.Fatalf("unexpected seed options %+v", received)
+	}
+	if state.grpcServed || state.bootstrapCalled {
+		testHandle.Fatalf("seed must not start the server, state=%+v", state)
+	}
+}
+
+func TestRunServerSeedErrorPaths(testHandle *testing.T) {
+	testCases := []struct {
+		name string
+		args []string
+		err  error
+	}{
+		{name: "non-positive count", args: []string{"seed", "--count", "0"}},
+		{name: "malformed base time", args: []string{"seed", "--base-time", "yesterday"}},
+		{name: "unknown flag", args: []string{"seed", "--tenants", "3"}},
+		{name: "non-empty database", args: []string{"seed"}, err: seed.ErrDatabaseNotEmpty},
+	}
+	for _, testCase := range testCases {
+		testHandle.Run(testCase.name, func(testHandle *testing.T) {
+			_, dependencies := newServerTestDependencies(serverTestConfig())
+			called := false
+			dependencies.seedDatabase = func(context.Context, *gorm.DB, *tenant.SecretKeeper, seed.Options) (seed.Summary, error) {
+				called = true
+				return seed.Summary{}, testCase.err
+			}
+			if exitCode := runServer(testCase.args, dependencies); exitCode != 1 {
+				testHandle.Fatalf("expected exit code 1, got %d", exitCode)
+			}
+			if called != errors.Is(testCase.err, seed.ErrDatabaseNotEmpty) {
+				testHandle.Fatalf("unexpected seeder call=%v", called)
+			}
+		})
+	}
+}
+
+func TestRunServerSeedPrintsSampleTenants(testHandle *testing.T) {
+	_, dependencies := newServerTestDependencies(serverTestConfig())
+	var output bytes.Buffer
+	previousOutput := seedOutput
+	seedOutput = &output
+	testHandle.Cleanup(func() { seedOutput = previousOutput })
+
+	if exitCode := runServer([]string{"seed", "--print-tenants"}, dependencies); exitCode != 0 {
+		testHandle.Fatalf("expected success, got %d", exitCode)
+	}
+	if !strings.Contains(output.String(), "id: acme-dev") || !strings.Contains(output.String(), "id: globex-dev") {
+		testHandle.Fatalf("expected the sample tenants, got %q", output.String())
+	}
+}
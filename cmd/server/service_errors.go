@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// serviceStatusError translates the service and model sentinel errors into the gRPC
+// status every RPC reports them with. It matches with errors.Is only, so rewording or
+// wrapping an error never changes its code. Errors it does not recognize are returned
+// unchanged for the database interceptors to map.
+func serviceStatusError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, service.ErrMissingNotificationID):
+		return status.Error(codes.InvalidArgument, notificationIDRequiredMessage)
+	case errors.Is(err, service.ErrScheduleInPast):
+		return status.Error(codes.InvalidArgument, scheduledTimeFutureMessage)
+	case errors.Is(err, service.ErrScheduleAfterExpiry),
+		errors.Is(err, service.ErrScheduleBeyondHorizon),
+		errors.Is(err, service.ErrTransferSameTenant),
+		errors.Is(err, model.ErrNotificationSMSTooLong),
+		errors.Is(err, model.ErrNotificationSenderOverrideForbidden),
+		errors.Is(err, model.ErrCancelReasonInvalid),
+		errors.Is(err, model.ErrUnknownNotificationStatus),
+		errors.Is(err, model.ErrInvalidNotificationFields):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrNotificationNotEditable),
+		errors.Is(err, service.ErrAttachmentDataNotPersisted),
+		errors.Is(err, service.ErrSMSDisabled),
+		errors.Is(err, service.ErrDeliveryCheckUnsupported),
+		errors.Is(err, service.ErrEncryptedContentUnsupported),
+		errors.Is(err, service.ErrSenderOverridesUnsupported),
+		errors.Is(err, service.ErrTenantInventoryUnavailable):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, model.ErrNotificationNotFound),
+		errors.Is(err, model.ErrNotificationGroupNotFound),
+		errors.Is(err, gorm.ErrRecordNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrDispatchCapacityExhausted):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, service.ErrDraining):
+		return drainingStatusError(err)
+	case errors.Is(err, service.ErrImmediateDispatchFailed):
+		// The message carries the notification id; the record is already queued for
+		// retry, so callers must not resend.
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return err
+	}
+}
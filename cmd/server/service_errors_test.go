@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// relabeledError reports a sentinel under an unrelated message, as if its text had been reworded.
+type relabeledError struct {
+	message  string
+	sentinel error
+}
+
+func (relabeled relabeledError) Error() string { return relabeled.message }
+
+func (relabeled relabeledError) Unwrap() error { return relabeled.sentinel }
+
+func TestServiceStatusErrorMatchesSentinelsNotMessages(testHandle *testing.T) {
+	for sentinel, expectedCode := range map[error]codes.Code{
+		service.ErrMissingNotificationID:             codes.InvalidArgument,
+		service.ErrScheduleInPast:                    codes.InvalidArgument,
+		model.ErrCancelReasonInvalid:                 codes.InvalidArgument,
+		model.ErrNotificationSenderOverrideForbidden: codes.InvalidArgument,
+		model.ErrInvalidNotificationFields:           codes.InvalidArgument,
+		service.ErrSenderOverridesUnsupported:        codes.FailedPrecondition,
+		service.ErrNotificationNotEditable:           codes.FailedPrecondition,
+		service.ErrAttachmentDataNotPersisted:        codes.FailedPrecondition,
+		model.ErrNotificationNotFound:                codes.NotFound,
+		service.ErrDispatchCapacityExhausted:         codes.ResourceExhausted,
+		service.ErrDraining:                          codes.Unavailable,
+	} {
+		for _, message := range []string{sentinel.Error(), "missing notification ID", "something else entirely"} {
+			mapped := serviceStatusError(relabeledError{message: message, sentinel: sentinel})
+			if status.Code(mapped) != expectedCode {
+				testHandle.Fatalf("%v reworded as %q: expected %v, got %v", sentinel, message, expectedCode, mapped)
+			}
+		}
+	}
+
+	unknown := errors.New("missing notification_id")
+	if mapped := serviceStatusError(unknown); mapped != unknown {
+		testHandle.Fatalf("expected an unrecognized error to pass through, got %v", mapped)
+	}
+	busy := fmt.Errorf("load: %w", model.ErrDatabaseBusy)
+	if mapped := serviceStatusError(busy); mapped != busy {
+		testHandle.Fatalf("expected database errors to be left to the interceptors, got %v", mapped)
+	}
+}
@@ -0,0 +1,179 @@
+// Package certreload serves a TLS certificate pair from disk and swaps it in when the
+// files change, so rotated certificates take effect without a restart.
+package certreload
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Status describes the certificate being served and the outcome of the last reload.
+type Status struct {
+	CertPath string
+	NotAfter time.Time
+	// ReloadError is set when the files changed but the new pair was rejected; the
+	// previous certificate is still served.
+	ReloadError string
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// Reloader hands out the current certificate through tls.Config.GetCertificate.
+type Reloader struct {
+	certPath    string
+	keyPath     string
+	logger      *slog.Logger
+	certificate atomic.Pointer[tls.Certificate]
+
+	mutex     sync.Mutex
+	certStamp fileStamp
+	keyStamp  fileStamp
+	reloadErr error
+}
+
+// New loads the pair once; an unreadable or invalid pair is an error.
+func New(certPath string, keyPath string, logger *slog.Logger) (*Reloader, error) {
+	if logger == nil {
+		return nil, errors.New("certreload: logger is required")
+	}
+	reloader := &Reloader{certPath: certPath, keyPath: keyPath, logger: logger}
+	certStamp, keyStamp, err := reloader.stamps()
+	if err != nil {
+		return nil, err
+	}
+	certificate, err := loadCertificate(certPath, keyPath, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	reloader.certificate.Store(certificate)
+	reloader.certStamp = certStamp
+	reloader.keyStamp = keyStamp
+	return reloader, nil
+}
+
+// TLSConfig returns a server configuration that always presents the current certificate.
+func (reloader *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		GetCertificate: reloader.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+	}
+}
+
+// GetCertificate implements tls.Config.GetCertificate.
+func (reloader *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return reloader.certificate.Load(), nil
+}
+
+// Reload re-reads the pair when either file changed since the last attempt. The new
+// pair is validated before it replaces the current one; when it is rejected the
+// current certificate stays in place and the error is kept for Status.
+func (reloader *Reloader) Reload() error {
+	reloader.mutex.Lock()
+	defer reloader.mutex.Unlock()
+	certStamp, keyStamp, err := reloader.stamps()
+	if err != nil {
+		return reloader.rejectLocked(err)
+	}
+	if certStamp == reloader.certStamp && keyStamp == reloader.keyStamp {
+		return reloader.reloadErr
+	}
+	// Record the stamps before validating so a half-written pair is retried only once
+	// the other file changes too.
+	reloader.certStamp = certStamp
+	reloader.keyStamp = keyStamp
+	certificate, err := loadCertificate(reloader.certPath, reloader.keyPath, time.Now())
+	if err != nil {
+		return reloader.rejectLocked(err)
+	}
+	reloader.certificate.Store(certificate)
+	reloader.reloadErr = nil
+	reloader.logger.Info("tls_certificate_rotated", "cert_path", reloader.certPath, "not_after", certificate.Leaf.NotAfter.UTC())
+	return nil
+}
+
+// Watch polls the files every interval until ctx ends.
+func (reloader *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = reloader.Reload()
+		}
+	}
+}
+
+// Status reports the served certificate and any pending reload failure.
+func (reloader *Reloader) Status() Status {
+	reloader.mutex.Lock()
+	defer reloader.mutex.Unlock()
+	status := Status{CertPath: reloader.certPath, NotAfter: reloader.certificate.Load().Leaf.NotAfter.UTC()}
+	if reloader.reloadErr != nil {
+		status.ReloadError = reloader.reloadErr.Error()
+	}
+	return status
+}
+
+func (reloader *Reloader) rejectLocked(err error) error {
+	if reloader.reloadErr == nil || reloader.reloadErr.Error() != err.Error() {
+		reloader.logger.Warn("tls_certificate_reload_failed", "cert_path", reloader.certPath, "error", err)
+	}
+	reloader.reloadErr = err
+	return err
+}
+
+func (reloader *Reloader) stamps() (fileStamp, fileStamp, error) {
+	certStamp, err := statFile(reloader.certPath)
+	if err != nil {
+		return fileStamp{}, fileStamp{}, err
+	}
+	keyStamp, err := statFile(reloader.keyPath)
+	if err != nil {
+		return fileStamp{}, fileStamp{}, err
+	}
+	return certStamp, keyStamp, nil
+}
+
+func statFile(path string) (fileStamp, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}, fmt.Errorf("certreload: stat %s: %w", path, err)
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}, nil
+}
+
+// loadCertificate rejects pairs whose key does not match and certificates outside
+// their validity window.
+func loadCertificate(certPath string, keyPath string, currentTime time.Time) (*tls.Certificate, error) {
+	certificate, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("certreload: load certificate: %w", err)
+	}
+	if certificate.Leaf == nil {
+		leaf, parseErr := x509.ParseCertificate(certificate.Certificate[0])
+		if parseErr != nil {
+			return nil, fmt.Errorf("certreload: parse certificate: %w", parseErr)
+		}
+		certificate.Leaf = leaf
+	}
+	if currentTime.After(certificate.Leaf.NotAfter) {
+		return nil, fmt.Errorf("certreload: certificate expired at %s", certificate.Leaf.NotAfter.UTC().Format(time.RFC3339))
+	}
+	if currentTime.Before(certificate.Leaf.NotBefore) {
+		return nil, fmt.Errorf("certreload: certificate not valid before %s", certificate.Leaf.NotBefore.UTC().Format(time.RFC3339))
+	}
+	return &certificate, nil
+}
@@ -0,0 +1,245 @@
+package certreload
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReloadPresentsRotatedCertificateToNewConnections(t *testing.T) {
+	certPath, keyPath := writeCertificatePair(t, t.TempDir(), 1, time.Now().Add(time.Hour))
+	reloader, err := New(certPath, keyPath, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("new reloader: %v", err)
+	}
+	address := serveTLS(t, reloader.TLSConfig())
+	if serial := peerSerial(t, address); serial != 1 {
+		t.Fatalf("expected initial certificate, got serial %d", serial)
+	}
+
+	rotatedNotAfter := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+	writeCertificatePairAt(t, certPath, keyPath, 2, rotatedNotAfter)
+	if err := reloader.Reload(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if serial := peerSerial(t, address); serial != 2 {
+		t.Fatalf("expected rotated certificate, got serial %d", serial)
+	}
+	status := reloader.Status()
+	if !status.NotAfter.Equal(rotatedNotAfter) || status.ReloadError != "" {
+		t.Fatalf("unexpected status %+v", status)
+	}
+}
+
+func TestReloadKeepsCurrentCertificateWhenNewPairIsInvalid(t *testing.T) {
+	certPath, keyPath := writeCertificatePair(t, t.TempDir(), 1, time.Now().Add(time.Hour))
+	reloader, err := New(certPath, keyPath, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("new reloader: %v", err)
+	}
+	address := serveTLS(t, reloader.TLSConfig())
+
+	_, otherKeyPath := writeCertificatePair(t, t.TempDir(), 9, time.Now().Add(time.Hour))
+	mismatchedKey, readErr := os.ReadFile(otherKeyPath)
+	if readErr != nil {
+		t.Fatalf("read key: %v", readErr)
+	}
+	writeCertificatePairAt(t, certPath, filepath.Join(t.TempDir(), "unused.pem"), 2, time.Now().Add(time.Hour))
+	if err := os.WriteFile(keyPath, mismatchedKey, 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	if err := reloader.Reload(); err == nil {
+		t.Fatalf("expected mismatched pair to be rejected")
+	}
+	if serial := peerSerial(t, address); serial != 1 {
+		t.Fatalf("expected previous certificate after failed reload, got serial %d", serial)
+	}
+	if status := reloader.Status(); status.ReloadError == "" {
+		t.Fatalf("expected reload error in status, got %+v", status)
+	}
+
+	// Unchanged files are not re-read; the failure stays until the pair changes.
+	if err := reloader.Reload(); err == nil {
+		t.Fatalf("expected pending reload error")
+	}
+	writeCertificatePairAt(t, certPath, keyPath, 3, time.Now().Add(time.Hour))
+	if err := reloader.Reload(); err != nil {
+		t.Fatalf("reload fixed pair: %v", err)
+	}
+	if serial := peerSerial(t, address); serial != 3 {
+		t.Fatalf("expected fixed certificate, got serial %d", serial)
+	}
+	if status := reloader.Status(); status.ReloadError != "" {
+		t.Fatalf("expected reload error to clear, got %+v", status)
+	}
+}
+
+func TestReloadRejectsExpiredCertificate(t *testing.T) {
+	certPath, keyPath := writeCertificatePair(t, t.TempDir(), 1, time.Now().Add(time.Hour))
+	reloader, err := New(certPath, keyPath, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("new reloader: %v", err)
+	}
+	writeCertificatePairAt(t, certPath, keyPath, 2, time.Now().Add(-time.Minute))
+	if err := reloader.Reload(); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Fatalf("expected expired certificate error, got %v", err)
+	}
+	if serial := reloader.certificate.Load().Leaf.SerialNumber.Int64(); serial != 1 {
+		t.Fatalf("expected previous certificate, got serial %d", serial)
+	}
+}
+
+func TestReloadReportsMissingFiles(t *testing.T) {
+	certPath, keyPath := writeCertificatePair(t, t.TempDir(), 1, time.Now().Add(time.Hour))
+	reloader, err := New(certPath, keyPath, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("new reloader: %v", err)
+	}
+	if err := os.Remove(keyPath); err != nil {
+		t.Fatalf("remove key: %v", err)
+	}
+	if err := reloader.Reload(); err == nil {
+		t.Fatalf("expected missing key error")
+	}
+	if status := reloader.Status(); status.ReloadError == "" || status.NotAfter.IsZero() {
+		t.Fatalf("unexpected status %+v", status)
+	}
+}
+
+func TestWatchPicksUpRotation(t *testing.T) {
+	certPath, keyPath := writeCertificatePair(t, t.TempDir(), 1, time.Now().Add(time.Hour))
+	reloader, err := New(certPath, keyPath, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("new reloader: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	watchDone := make(chan struct{})
+	go func() {
+		reloader.Watch(ctx, 10*time.Millisecond)
+		close(watchDone)
+	}()
+	writeCertificatePairAt(t, certPath, keyPath, 2, time.Now().Add(time.Hour))
+	deadline := time.Now().Add(5 * time.Second)
+	for reloader.certificate.Load().Leaf.SerialNumber.Int64() != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("watcher did not load the rotated certificate")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-watchDone
+}
+
+func TestNewRejectsInvalidInput(t *testing.T) {
+	certPath, keyPath := writeCertificatePair(t, t.TempDir(), 1, time.Now().Add(time.Hour))
+	if _, err := New(certPath, keyPath, nil); err == nil {
+		t.Fatalf("expected logger error")
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	if _, err := New(filepath.Join(t.TempDir(), "missing.pem"), keyPath, logger); err == nil {
+		t.Fatalf("expected missing certificate error")
+	}
+	if _, err := New(keyPath, keyPath, logger); err == nil {
+		t.Fatalf("expected invalid certificate error")
+	}
+	reloader, err := New(certPath, keyPath, logger)
+	if err != nil {
+		t.Fatalf("new reloader: %v", err)
+	}
+	if tlsConfig := reloader.TLSConfig(); tlsConfig.MinVersion != tls.VersionTLS12 || tlsConfig.GetCertificate == nil {
+		t.Fatalf("unexpected tls config %+v", tlsConfig)
+	}
+}
+
+func serveTLS(t *testing.T, tlsConfig *tls.Config) string {
+	t.Helper()
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", tlsConfig)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			connection, acceptErr := listener.Accept()
+			if acceptErr != nil {
+				return
+			}
+			go func() {
+				defer connection.Close()
+				_ = connection.(*tls.Conn).Handshake()
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func peerSerial(t *testing.T, address string) int64 {
+	t.Helper()
+	connection, err := tls.DialWithDialer(&net.Dialer{Timeout: 5 * time.Second}, "tcp", address, &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatalf("dial tls: %v", err)
+	}
+	defer connection.Close()
+	return connection.ConnectionState().PeerCertificates[0].SerialNumber.Int64()
+}
+
+func writeCertificatePair(t *testing.T, dir string, serial int64, notAfter time.Time) (string, string) {
+	t.Helper()
+	certPath := filepath.Join(dir, "tls.crt")
+	keyPath := filepath.Join(dir, "tls.key")
+	writeCertificatePairAt(t, certPath, keyPath, serial, notAfter)
+	return certPath, keyPath
+}
+
+// writeCertificatePairAt writes a self-signed pair and moves the modification times
+// forward so a rewrite within the filesystem's timestamp resolution is still noticed.
+func writeCertificatePairAt(t *testing.T, certPath string, keyPath string, serial int64, notAfter time.Time) {
+	t.Helper()
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "pinguin.test"},
+		NotBefore:    time.Now().Add(-2 * time.Hour),
+		NotAfter:     notAfter,
+		DNSNames:     []string{"pinguin.test"},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	certificateBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	keyBytes, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	modTime := time.Now().Add(time.Duration(serial) * time.Minute)
+	for path, block := range map[string]*pem.Block{
+		certPath: {Type: "CERTIFICATE", Bytes: certificateBytes},
+		keyPath:  {Type: "EC PRIVATE KEY", Bytes: keyBytes},
+	} {
+		if err := os.WriteFile(path, pem.EncodeToMemory(block), 0o600); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("touch %s: %v", path, err)
+		}
+	}
+}
@@ -2,28 +2,159 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"sort"
 	"strings"
 
 	"github.com/tyemirov/pinguin/internal/tenant"
+	"github.com/tyemirov/pinguin/pkg/endpoint"
 	"gopkg.in/yaml.v3"
 )
 
 const defaultConfigPath = "configs/config.yml"
 
+// DefaultGRPCListenAddr is the gRPC listen address used when server.grpcListenAddr is unset.
+const DefaultGRPCListenAddr = ":50051"
+
+const (
+	// InFlightSendPolicyReject fails synchronous sends immediately when every in-flight slot is taken.
+	InFlightSendPolicyReject = "reject"
+	// InFlightSendPolicyWait queues synchronous sends until a slot frees up or the request context ends.
+	InFlightSendPolicyWait = "wait"
+)
+
+// RetryQueueSQL backs the retry worker with the notifications table; it is the default.
+const RetryQueueSQL = "sql"
+
+// SecretCipherAESGCM encrypts tenant secrets with AES-256-GCM under server.masterEncryptionKey; it is the default.
+const SecretCipherAESGCM = "aes-gcm"
+
+const (
+	// JSONFieldNamingSnakeCase serves /api JSON fields with the model's names, such as notification_id; it is the default.
+	JSONFieldNamingSnakeCase = "snake_case"
+	// JSONFieldNamingCamelCase serves /api JSON fields in camelCase, such as notificationId.
+	// The bundled dashboard reads snake_case fields and does not work with it.
+	JSONFieldNamingCamelCase = "camelCase"
+)
+
+const (
+	// DefaultGRPCKeepaliveTimeSec is how long a connection may sit idle before the server pings the client.
+	DefaultGRPCKeepaliveTimeSec = 120
+	// DefaultGRPCKeepaliveTimeoutSec is how long the server waits for a ping ack before closing the connection.
+	DefaultGRPCKeepaliveTimeoutSec = 20
+	// DefaultGRPCKeepaliveMinClientPingIntervalSec is the most often a client may ping; the Go
+	// client pings every 30 seconds by default.
+	DefaultGRPCKeepaliveMinClientPingIntervalSec = 20
+)
+
+const (
+	// DefaultDispatchPacingFailureThreshold is the transient-failure rate above which pacing starts.
+	DefaultDispatchPacingFailureThreshold = 0.25
+	// DefaultDispatchPacingMinDelayMs is the first delay inserted once pacing starts.
+	DefaultDispatchPacingMinDelayMs = 250
+	// DefaultDispatchPacingMaxDelayMs caps the delay between a paced tenant's dispatches.
+	DefaultDispatchPacingMaxDelayMs = 60000
+	// DefaultDispatchPacingRecoveryRate is the fraction of the delay removed per healthy dispatch.
+	DefaultDispatchPacingRecoveryRate = 0.1
+	// DefaultDrainTimeoutSec bounds how long a draining instance waits for its queues to empty.
+	DefaultDrainTimeoutSec = 600
+	// DefaultQueryTimeoutSec bounds a single-row statement; it exceeds SQLite's 10-second
+	// busy timeout so a lock wait still reports busy rather than a timeout.
+	DefaultQueryTimeoutSec = 15
+	// DefaultAggregateQueryTimeoutSec bounds an export, statistics or worker scan statement.
+	DefaultAggregateQueryTimeoutSec = 120
+	// DefaultSMTPPoolIdleTimeoutSec is how long a pooled SMTP connection stays open unused.
+	DefaultSMTPPoolIdleTimeoutSec = 60
+	// DefaultOpenTrackingTokenTTLHours is how long an open tracking pixel keeps recording opens.
+	DefaultOpenTrackingTokenTTLHours = 720
+)
+
+const (
+	// GRPCTokenScopeRead limits a gRPC token to status, list, summary, and capability RPCs.
+	GRPCTokenScopeRead = "read"
+	// GRPCTokenScopeWrite grants full gRPC access, including sends and mutations.
+	GRPCTokenScopeWrite = "write"
+	// GRPCTokenScopeAdmin is reserved for cross-tenant operator RPCs and grants no tenant access.
+	GRPCTokenScopeAdmin = "admin"
+)
+
 var defaultConfigPaths = []string{
 	defaultConfigPath,
 	"/config/config.yml",
 }
 
 type Config struct {
-	DatabasePath     string
-	GRPCAuthToken    string
+	DatabasePath  string
+	GRPCAuthToken string
+	// GRPCTokens lists additional scoped bearer tokens; GRPCAuthToken keeps full access.
+	GRPCTokens []GRPCTokenConfig
+	// GRPCListenAddr accepts host:port, tcp://, tcp4://, tcp6://, or unix:// addresses; empty uses DefaultGRPCListenAddr.
+	GRPCListenAddr   string
+	GRPCKeepalive    GRPCKeepaliveConfig
 	LogLevel         string
 	MaxRetries       int
 	RetryIntervalSec int
 
+	// MaxInFlightSends bounds concurrent synchronous dispatches per process; zero disables the guard.
+	MaxInFlightSends   int
+	InFlightSendPolicy string
+	DispatchPacing     DispatchPacingConfig
+	// MaxScheduleHorizonDays caps how far ahead a notification may be scheduled; zero means no limit.
+	MaxScheduleHorizonDays int
+	// StrictScheduling rejects sends scheduled in the past with ErrScheduleInPast instead of
+	// dispatching them immediately.
+	StrictScheduling bool
+	// StrictStatusFilters rejects list filters naming an unknown status with
+	// model.ErrUnknownNotificationStatus instead of ignoring the value.
+	StrictStatusFilters bool
+	// FailOnImmediateError makes SendNotification return the error of a failed immediate
+	// dispatch instead of only persisting the notification for retry; requests may override it.
+	FailOnImmediateError bool
+	// TenantCacheMaxEntries bounds the decrypted tenant configs kept in memory; zero uses the default.
+	TenantCacheMaxEntries int
+	// MaxConcurrentRetriesPerTenant caps one tenant's jobs per retry cycle; zero means no cap.
+	MaxConcurrentRetriesPerTenant int
+	// MaxRetryAgeSec stops retrying a notification this long after it was due, whatever
+	// retries remain; zero means no limit. A tenant's maxRetryAgeSec overrides it.
+	MaxRetryAgeSec int
+	// MaxPendingAgeSec flags a tenant in ListTenantsStatus once its oldest due queued
+	// notification has waited longer than this; zero disables the alert.
+	MaxPendingAgeSec int
+	// SMSCancelGraceSec lets a sent SMS be cancelled at the provider for this long after
+	// it was sent, while it may still be undelivered; zero disables it.
+	SMSCancelGraceSec int
+	// NotificationBatchChunkSize bounds the rows each bulk notification insert writes in
+	// one transaction; zero uses model.DefaultNotificationBatchChunkSize.
+	NotificationBatchChunkSize int
+	// IntegritySweepIntervalSec runs the attachment integrity sweep on this interval; zero disables it.
+	IntegritySweepIntervalSec int
+	// IntegritySweepRepairOrphans lets the sweep delete attachment rows whose notification is gone.
+	IntegritySweepRepairOrphans bool
+	// DrainTimeoutSec bounds how long a draining instance keeps dispatching before it exits; zero uses the default.
+	DrainTimeoutSec int
+	// QueryTimeoutSec bounds each single-row database statement; zero uses the default.
+	QueryTimeoutSec int
+	// AggregateQueryTimeoutSec bounds each export, statistics or worker scan statement; zero uses the default.
+	AggregateQueryTimeoutSec int
+	// SMTPPoolSize keeps up to this many SMTP connections open per tenant and caps the
+	// tenant's concurrent connections at it; zero dials a connection per message.
+	SMTPPoolSize int
+	// SMTPPoolIdleTimeoutSec closes pooled SMTP connections left unused this long; zero uses the default.
+	SMTPPoolIdleTimeoutSec int
+	// StrictTenantSelfCheck aborts startup when an active tenant's runtime config does not resolve.
+	StrictTenantSelfCheck bool
+	// RetryQueue names the queue that feeds the retry worker; empty uses RetryQueueSQL.
+	RetryQueue string
+	// SecretCipher names the cipher behind the tenant SecretKeeper; empty uses SecretCipherAESGCM.
+	SecretCipher string
+	// RetentionDays deletes finished notifications older than this many days unless held;
+	// zero keeps them. A tenant's retentionDays overrides it.
+	RetentionDays int
+	// WarnShortTenantRetention makes pinguin-doctor warn about tenants whose retentionDays
+	// is shorter than RetentionDays.
+	WarnShortTenantRetention bool
+
 	MasterEncryptionKey string
 	TenantConfigPath    string
 	TenantBootstrap     tenant.BootstrapConfig
@@ -32,8 +163,23 @@ type Config struct {
 	HTTPListenAddr      string
 	HTTPAllowedOrigins  []string
 	HTTPTrustedProxies  []string
-	SMTPSubmission      SMTPSubmissionConfig
-	SMTPForwarding      SMTPForwardingConfig
+	// HTTPServiceAccountEmails may read /api notifications across tenants but never change them.
+	HTTPServiceAccountEmails []string
+	// HTTP timeouts and body limits; zero selects the server defaults.
+	HTTPReadTimeoutSec            int
+	HTTPWriteTimeoutSec           int
+	HTTPIdleTimeoutSec            int
+	HTTPMaxRequestBytes           int64
+	HTTPMaxAttachmentRequestBytes int64
+	// HTTPJSONFieldNaming is the field naming of /api JSON bodies, snake_case or camelCase.
+	HTTPJSONFieldNaming string
+	// OpenTrackingBaseURL is the public origin pixel URLs point at; empty disables open tracking.
+	OpenTrackingBaseURL string
+	// OpenTrackingTokenTTLHours is how long a pixel records opens; zero uses the default.
+	OpenTrackingTokenTTLHours int
+	SMTPSubmission            SMTPSubmissionConfig
+	SMTPForwarding            SMTPForwardingConfig
+	QueueIntake               QueueIntakeConfig
 
 	TAuthSigningKey string
 	TAuthCookieName string
@@ -53,6 +199,31 @@ type Config struct {
 	OperationTimeoutSec  int
 }
 
+// GRPCTokenConfig grants a bearer token a scope and an optional tenant restriction.
+type GRPCTokenConfig struct {
+	Token     string
+	Scope     string
+	TenantIDs []string
+}
+
+// GRPCKeepaliveConfig keeps idle gRPC connections alive through NAT and load balancers
+// and bounds how often clients may ping.
+type GRPCKeepaliveConfig struct {
+	TimeSec                  int
+	TimeoutSec               int
+	MinClientPingIntervalSec int
+	PermitPingsWithoutStream bool
+}
+
+// DispatchPacingConfig slows a tenant's dispatches to a provider while its transient failure rate stays high.
+type DispatchPacingConfig struct {
+	Enabled          bool
+	FailureThreshold float64
+	MinDelayMs       int
+	MaxDelayMs       int
+	RecoveryRate     float64
+}
+
 // SMTPSubmissionConfig controls Gmail-facing SMTP submission listeners.
 type SMTPSubmissionConfig struct {
 	Enabled            bool
@@ -96,31 +267,120 @@ type SMTPForwardingRelayConfig struct {
 	Password string
 }
 
+// QueueIntakeConfig controls the NATS JetStream consumer that accepts send requests
+// from a queue. Zero counts and durations select the consumer defaults.
+type QueueIntakeConfig struct {
+	Enabled           bool
+	URL               string
+	Stream            string
+	Subject           string
+	Durable           string
+	DeadLetterSubject string
+	Concurrency       int
+	RequeueDelaySec   int
+	MaxDeliver        int
+	AckWaitSec        int
+	Username          string
+	Password          string
+	Token             string
+	CredentialsFile   string
+	// TenantIDs restricts which tenants queued requests may name; empty allows all.
+	TenantIDs []string
+}
+
+// ValidationError lists every field-level problem found while validating a Config.
+type ValidationError struct {
+	Problems []string
+}
+
+func (validationError *ValidationError) Error() string {
+	return "configuration errors: " + strings.Join(validationError.Problems, ", ")
+}
+
 type fileConfig struct {
 	Server         serverSection         `yaml:"server"`
 	Web            webSection            `yaml:"web"`
 	SMTPSubmission smtpSubmissionSection `yaml:"smtpSubmission"`
 	SMTPForwarding smtpForwardingSection `yaml:"smtpForwarding"`
+	QueueIntake    queueIntakeSection    `yaml:"queueIntake"`
 	Tenants        tenantConfig          `yaml:"tenants"`
 }
 
 type serverSection struct {
-	DatabasePath        string       `yaml:"databasePath"`
-	GRPCAuthToken       string       `yaml:"grpcAuthToken"`
-	LogLevel            string       `yaml:"logLevel"`
-	MaxRetries          int          `yaml:"maxRetries"`
-	RetryIntervalSec    int          `yaml:"retryIntervalSec"`
-	MasterEncryptionKey string       `yaml:"masterEncryptionKey"`
-	ConnectionTimeout   int          `yaml:"connectionTimeoutSec"`
-	OperationTimeout    int          `yaml:"operationTimeoutSec"`
-	TAuth               tauthSection `yaml:"tauth"`
+	DatabasePath        string                `yaml:"databasePath"`
+	GRPCAuthToken       string                `yaml:"grpcAuthToken"`
+	GRPCTokens          []grpcTokenSection    `yaml:"grpcTokens"`
+	GRPCListenAddr      string                `yaml:"grpcListenAddr"`
+	GRPCKeepalive       grpcKeepaliveSection  `yaml:"grpcKeepalive"`
+	LogLevel            string                `yaml:"logLevel"`
+	MaxRetries          int                   `yaml:"maxRetries"`
+	RetryIntervalSec    int                   `yaml:"retryIntervalSec"`
+	MaxInFlightSends    int                   `yaml:"maxInFlightSends"`
+	InFlightSendPolicy  string                `yaml:"inFlightSendPolicy"`
+	DispatchPacing      dispatchPacingSection `yaml:"dispatchPacing"`
+	MaxScheduleHorizon  int                   `yaml:"maxScheduleHorizonDays"`
+	StrictScheduling    bool                  `yaml:"strictScheduling"`
+	StrictStatusFilters bool                  `yaml:"strictStatusFilters"`
+	FailOnImmediate     bool                  `yaml:"failOnImmediateError"`
+	TenantCacheMax      int                   `yaml:"tenantCacheMaxEntries"`
+	MaxRetriesPerTenant int                   `yaml:"maxConcurrentRetriesPerTenant"`
+	MaxRetryAgeSec      int                   `yaml:"maxRetryAgeSec"`
+	MaxPendingAgeSec    int                   `yaml:"maxPendingAgeSec"`
+	SMSCancelGraceSec   int                   `yaml:"smsCancelGraceSec"`
+	BatchChunkSize      int                   `yaml:"notificationBatchChunkSize"`
+	IntegritySweepSec   int                   `yaml:"integritySweepIntervalSec"`
+	IntegrityRepair     bool                  `yaml:"integritySweepRepairOrphans"`
+	DrainTimeoutSec     int                   `yaml:"drainTimeoutSec"`
+	QueryTimeoutSec     int                   `yaml:"queryTimeoutSec"`
+	AggregateTimeoutSec int                   `yaml:"aggregateQueryTimeoutSec"`
+	SMTPPoolSize        int                   `yaml:"smtpPoolSize"`
+	SMTPPoolIdleSec     int                   `yaml:"smtpPoolIdleTimeoutSec"`
+	StrictTenantCheck   bool                  `yaml:"strictTenantSelfCheck"`
+	RetryQueue          string                `yaml:"retryQueue"`
+	SecretCipher        string                `yaml:"secretCipher"`
+	RetentionDays       int                   `yaml:"retentionDays"`
+	WarnShortRetention  *bool                 `yaml:"warnShortTenantRetention"`
+	MasterEncryptionKey string                `yaml:"masterEncryptionKey"`
+	ConnectionTimeout   int                   `yaml:"connectionTimeoutSec"`
+	OperationTimeout    int                   `yaml:"operationTimeoutSec"`
+	TAuth               tauthSection          `yaml:"tauth"`
+}
+
+type grpcKeepaliveSection struct {
+	TimeSec                  int   `yaml:"timeSec"`
+	TimeoutSec               int   `yaml:"timeoutSec"`
+	MinClientPingIntervalSec int   `yaml:"minClientPingIntervalSec"`
+	PermitWithoutStream      *bool `yaml:"permitWithoutStream"`
+}
+
+type dispatchPacingSection struct {
+	Enabled          bool    `yaml:"enabled"`
+	FailureThreshold float64 `yaml:"failureThreshold"`
+	MinDelayMs       int     `yaml:"minDelayMs"`
+	MaxDelayMs       int     `yaml:"maxDelayMs"`
+	RecoveryRate     float64 `yaml:"recoveryRate"`
+}
+
+type grpcTokenSection struct {
+	Token   string   `yaml:"token"`
+	Scope   string   `yaml:"scope"`
+	Tenants []string `yaml:"tenants"`
 }
 
 type webSection struct {
-	Enabled        *bool    `yaml:"enabled"`
-	ListenAddr     string   `yaml:"listenAddr"`
-	AllowedOrigins []string `yaml:"allowedOrigins"`
-	TrustedProxies []string `yaml:"trustedProxies"`
+	Enabled                   *bool    `yaml:"enabled"`
+	ListenAddr                string   `yaml:"listenAddr"`
+	AllowedOrigins            []string `yaml:"allowedOrigins"`
+	TrustedProxies            []string `yaml:"trustedProxies"`
+	ServiceAccounts           []string `yaml:"serviceAccounts"`
+	ReadTimeoutSec            int      `yaml:"readTimeoutSec"`
+	WriteTimeoutSec           int      `yaml:"writeTimeoutSec"`
+	IdleTimeoutSec            int      `yaml:"idleTimeoutSec"`
+	MaxRequestBytes           int64    `yaml:"maxRequestBytes"`
+	MaxAttachmentRequestBytes int64    `yaml:"maxAttachmentRequestBytes"`
+	JSONFieldNaming           string   `yaml:"jsonFieldNaming"`
+	OpenTrackingBaseURL       string   `yaml:"openTrackingBaseURL"`
+	OpenTrackingTokenTTLHours int      `yaml:"openTrackingTokenTTLHours"`
 }
 
 type tauthSection struct {
@@ -167,9 +427,28 @@ type smtpForwardingRelaySection struct {
 	Password string `yaml:"password"`
 }
 
+type queueIntakeSection struct {
+	Enabled           bool     `yaml:"enabled"`
+	URL               string   `yaml:"url"`
+	Stream            string   `yaml:"stream"`
+	Subject           string   `yaml:"subject"`
+	Durable           string   `yaml:"durable"`
+	DeadLetterSubject string   `yaml:"deadLetterSubject"`
+	Concurrency       int      `yaml:"concurrency"`
+	RequeueDelaySec   int      `yaml:"requeueDelaySec"`
+	MaxDeliver        int      `yaml:"maxDeliver"`
+	AckWaitSec        int      `yaml:"ackWaitSec"`
+	Username          string   `yaml:"username"`
+	Password          string   `yaml:"password"`
+	Token             string   `yaml:"token"`
+	CredentialsFile   string   `yaml:"credentialsFile"`
+	Tenants           []string `yaml:"tenants"`
+}
+
 type tenantConfig struct {
-	ConfigPath string
-	Tenants    []tenant.BootstrapTenant
+	ConfigPath           string
+	Tenants              []tenant.BootstrapTenant
+	ForceReassignDomains bool
 }
 
 func (cfg *tenantConfig) UnmarshalYAML(value *yaml.Node) error {
@@ -180,26 +459,32 @@ func (cfg *tenantConfig) UnmarshalYAML(value *yaml.Node) error {
 
 	switch value.Kind {
 	case yaml.SequenceNode:
-		var tenants []tenant.BootstrapTenant
-		if err := value.Decode(&tenants); err != nil {
+		tenants, err := tenant.DecodeBootstrapTenants(value)
+		if err != nil {
 			return fmt.Errorf("configuration: parse tenants: %w", err)
 		}
 		cfg.ConfigPath = ""
 		cfg.Tenants = tenants
 		return nil
 	case yaml.MappingNode:
-		if unknownKey := firstUnknownYAMLMappingKey(value, "configPath", "tenants"); unknownKey != "" {
+		if unknownKey := firstUnknownYAMLMappingKey(value, "configPath", "tenants", "forceReassignDomains"); unknownKey != "" {
 			return fmt.Errorf("configuration: tenants.%s is not supported", unknownKey)
 		}
 		var decoded struct {
-			ConfigPath string                   `yaml:"configPath"`
-			Tenants    []tenant.BootstrapTenant `yaml:"tenants"`
+			ConfigPath           string    `yaml:"configPath"`
+			Tenants              yaml.Node `yaml:"tenants"`
+			ForceReassignDomains bool      `yaml:"forceReassignDomains"`
 		}
 		if err := value.Decode(&decoded); err != nil {
 			return fmt.Errorf("configuration: parse tenants: %w", err)
 		}
+		tenants, err := tenant.DecodeBootstrapTenants(&decoded.Tenants)
+		if err != nil {
+			return fmt.Errorf("configuration: parse tenants: %w", err)
+		}
 		cfg.ConfigPath = strings.TrimSpace(decoded.ConfigPath)
-		cfg.Tenants = decoded.Tenants
+		cfg.Tenants = tenants
+		cfg.ForceReassignDomains = decoded.ForceReassignDomains
 		return nil
 	default:
 		return fmt.Errorf("configuration: tenants must be a list")
@@ -256,17 +541,54 @@ func loadConfigFromPath(configPath string) (Config, error) {
 		webEnabled = *fileCfg.Web.Enabled
 	}
 	configuration := Config{
-		DatabasePath:        strings.TrimSpace(fileCfg.Server.DatabasePath),
-		GRPCAuthToken:       strings.TrimSpace(fileCfg.Server.GRPCAuthToken),
-		LogLevel:            strings.TrimSpace(fileCfg.Server.LogLevel),
-		MaxRetries:          fileCfg.Server.MaxRetries,
-		RetryIntervalSec:    fileCfg.Server.RetryIntervalSec,
-		MasterEncryptionKey: strings.TrimSpace(fileCfg.Server.MasterEncryptionKey),
-		TenantConfigPath:    strings.TrimSpace(fileCfg.Tenants.ConfigPath),
-		WebInterfaceEnabled: webEnabled,
-		HTTPListenAddr:      strings.TrimSpace(fileCfg.Web.ListenAddr),
-		HTTPAllowedOrigins:  normalizeStrings(fileCfg.Web.AllowedOrigins),
-		HTTPTrustedProxies:  normalizeStrings(fileCfg.Web.TrustedProxies),
+		DatabasePath:                  strings.TrimSpace(fileCfg.Server.DatabasePath),
+		GRPCAuthToken:                 strings.TrimSpace(fileCfg.Server.GRPCAuthToken),
+		GRPCTokens:                    normalizeGRPCTokens(fileCfg.Server.GRPCTokens),
+		GRPCListenAddr:                strings.TrimSpace(fileCfg.Server.GRPCListenAddr),
+		GRPCKeepalive:                 normalizeGRPCKeepalive(fileCfg.Server.GRPCKeepalive),
+		LogLevel:                      strings.TrimSpace(fileCfg.Server.LogLevel),
+		MaxRetries:                    fileCfg.Server.MaxRetries,
+		RetryIntervalSec:              fileCfg.Server.RetryIntervalSec,
+		MaxInFlightSends:              fileCfg.Server.MaxInFlightSends,
+		InFlightSendPolicy:            normalizeInFlightSendPolicy(fileCfg.Server.InFlightSendPolicy),
+		DispatchPacing:                normalizeDispatchPacing(fileCfg.Server.DispatchPacing),
+		MaxScheduleHorizonDays:        fileCfg.Server.MaxScheduleHorizon,
+		StrictScheduling:              fileCfg.Server.StrictScheduling,
+		StrictStatusFilters:           fileCfg.Server.StrictStatusFilters,
+		FailOnImmediateError:          fileCfg.Server.FailOnImmediate,
+		TenantCacheMaxEntries:         fileCfg.Server.TenantCacheMax,
+		MaxConcurrentRetriesPerTenant: fileCfg.Server.MaxRetriesPerTenant,
+		MaxRetryAgeSec:                fileCfg.Server.MaxRetryAgeSec,
+		MaxPendingAgeSec:              fileCfg.Server.MaxPendingAgeSec,
+		SMSCancelGraceSec:             fileCfg.Server.SMSCancelGraceSec,
+		NotificationBatchChunkSize:    fileCfg.Server.BatchChunkSize,
+		IntegritySweepIntervalSec:     fileCfg.Server.IntegritySweepSec,
+		IntegritySweepRepairOrphans:   fileCfg.Server.IntegrityRepair,
+		DrainTimeoutSec:               fileCfg.Server.DrainTimeoutSec,
+		QueryTimeoutSec:               fileCfg.Server.QueryTimeoutSec,
+		AggregateQueryTimeoutSec:      fileCfg.Server.AggregateTimeoutSec,
+		SMTPPoolSize:                  fileCfg.Server.SMTPPoolSize,
+		SMTPPoolIdleTimeoutSec:        fileCfg.Server.SMTPPoolIdleSec,
+		StrictTenantSelfCheck:         fileCfg.Server.StrictTenantCheck,
+		RetryQueue:                    normalizeRetryQueue(fileCfg.Server.RetryQueue),
+		SecretCipher:                  normalizeSecretCipher(fileCfg.Server.SecretCipher),
+		RetentionDays:                 fileCfg.Server.RetentionDays,
+		WarnShortTenantRetention:      fileCfg.Server.WarnShortRetention == nil || *fileCfg.Server.WarnShortRetention,
+		MasterEncryptionKey:           strings.TrimSpace(fileCfg.Server.MasterEncryptionKey),
+		TenantConfigPath:              strings.TrimSpace(fileCfg.Tenants.ConfigPath),
+		WebInterfaceEnabled:           webEnabled,
+		HTTPListenAddr:                strings.TrimSpace(fileCfg.Web.ListenAddr),
+		HTTPAllowedOrigins:            normalizeStrings(fileCfg.Web.AllowedOrigins),
+		HTTPTrustedProxies:            normalizeStrings(fileCfg.Web.TrustedProxies),
+		HTTPServiceAccountEmails:      normalizeStrings(fileCfg.Web.ServiceAccounts),
+		HTTPReadTimeoutSec:            fileCfg.Web.ReadTimeoutSec,
+		HTTPWriteTimeoutSec:           fileCfg.Web.WriteTimeoutSec,
+		HTTPIdleTimeoutSec:            fileCfg.Web.IdleTimeoutSec,
+		HTTPMaxRequestBytes:           fileCfg.Web.MaxRequestBytes,
+		HTTPMaxAttachmentRequestBytes: fileCfg.Web.MaxAttachmentRequestBytes,
+		HTTPJSONFieldNaming:           normalizeJSONFieldNaming(fileCfg.Web.JSONFieldNaming),
+		OpenTrackingBaseURL:           strings.TrimSpace(fileCfg.Web.OpenTrackingBaseURL),
+		OpenTrackingTokenTTLHours:     fileCfg.Web.OpenTrackingTokenTTLHours,
 		SMTPSubmission: SMTPSubmissionConfig{
 			Enabled:            fileCfg.SMTPSubmission.Enabled,
 			Hostname:           strings.TrimSpace(fileCfg.SMTPSubmission.Hostname),
@@ -300,12 +622,30 @@ func loadConfigFromPath(configPath string) (Config, error) {
 				Password: strings.TrimSpace(fileCfg.SMTPForwarding.Relay.Password),
 			},
 		},
+		QueueIntake: QueueIntakeConfig{
+			Enabled:           fileCfg.QueueIntake.Enabled,
+			URL:               strings.TrimSpace(fileCfg.QueueIntake.URL),
+			Stream:            strings.TrimSpace(fileCfg.QueueIntake.Stream),
+			Subject:           strings.TrimSpace(fileCfg.QueueIntake.Subject),
+			Durable:           strings.TrimSpace(fileCfg.QueueIntake.Durable),
+			DeadLetterSubject: strings.TrimSpace(fileCfg.QueueIntake.DeadLetterSubject),
+			Concurrency:       fileCfg.QueueIntake.Concurrency,
+			RequeueDelaySec:   fileCfg.QueueIntake.RequeueDelaySec,
+			MaxDeliver:        fileCfg.QueueIntake.MaxDeliver,
+			AckWaitSec:        fileCfg.QueueIntake.AckWaitSec,
+			Username:          strings.TrimSpace(fileCfg.QueueIntake.Username),
+			Password:          strings.TrimSpace(fileCfg.QueueIntake.Password),
+			Token:             strings.TrimSpace(fileCfg.QueueIntake.Token),
+			CredentialsFile:   strings.TrimSpace(fileCfg.QueueIntake.CredentialsFile),
+			TenantIDs:         normalizeStrings(fileCfg.QueueIntake.Tenants),
+		},
 		TAuthSigningKey:      strings.TrimSpace(fileCfg.Server.TAuth.SigningKey),
 		TAuthCookieName:      strings.TrimSpace(fileCfg.Server.TAuth.CookieName),
 		ConnectionTimeoutSec: fileCfg.Server.ConnectionTimeout,
 		OperationTimeoutSec:  fileCfg.Server.OperationTimeout,
 		TenantBootstrap: tenant.BootstrapConfig{
-			Tenants: fileCfg.Tenants.Tenants,
+			Tenants:              fileCfg.Tenants.Tenants,
+			ForceReassignDomains: fileCfg.Tenants.ForceReassignDomains,
 		},
 	}
 
@@ -316,6 +656,8 @@ func loadConfigFromPath(configPath string) (Config, error) {
 	} else {
 		configuration.HTTPAllowedOrigins = nil
 		configuration.HTTPTrustedProxies = nil
+		configuration.HTTPServiceAccountEmails = nil
+		configuration.OpenTrackingBaseURL = ""
 		configuration.TAuthSigningKey = ""
 		configuration.TAuthCookieName = ""
 	}
@@ -331,6 +673,14 @@ func (configuration Config) TwilioConfigured() bool {
 	return configuration.TwilioAccountSID != "" && configuration.TwilioAuthToken != "" && configuration.TwilioFromNumber != ""
 }
 
+// GRPCListenAddress returns server.grpcListenAddr, falling back to DefaultGRPCListenAddr.
+func (configuration Config) GRPCListenAddress() string {
+	if strings.TrimSpace(configuration.GRPCListenAddr) == "" {
+		return DefaultGRPCListenAddr
+	}
+	return configuration.GRPCListenAddr
+}
+
 // ExpandConfigEnvironment expands shell-style placeholders and rejects absent variables.
 func ExpandConfigEnvironment(contents string) (string, error) {
 	var missing []string
@@ -368,20 +718,93 @@ func normalizeStrings(values []string) []string {
 func validateConfig(cfg Config) error {
 	var errors []string
 	requireString(cfg.DatabasePath, "server.databasePath", &errors)
-	requireString(cfg.GRPCAuthToken, "server.grpcAuthToken", &errors)
+	if len(cfg.GRPCTokens) == 0 {
+		requireString(cfg.GRPCAuthToken, "server.grpcAuthToken or server.grpcTokens", &errors)
+	}
+	validateGRPCTokens(cfg.GRPCAuthToken, cfg.GRPCTokens, &errors)
+	validateListenAddr(cfg.GRPCListenAddr, "server.grpcListenAddr", &errors)
 	requireString(cfg.LogLevel, "server.logLevel", &errors)
 	requirePositive(cfg.MaxRetries, "server.maxRetries", &errors)
 	requirePositive(cfg.RetryIntervalSec, "server.retryIntervalSec", &errors)
+	if cfg.MaxInFlightSends < 0 {
+		errors = append(errors, "server.maxInFlightSends must not be negative")
+	}
+	validateDispatchPacing(cfg.DispatchPacing, &errors)
+	validateGRPCKeepalive(cfg.GRPCKeepalive, &errors)
+	requireNonNegative(cfg.RetentionDays, "server.retentionDays", &errors)
+	requireNonNegative(cfg.MaxRetryAgeSec, "server.maxRetryAgeSec", &errors)
+	requireNonNegative(cfg.MaxPendingAgeSec, "server.maxPendingAgeSec", &errors)
+	requireNonNegative(cfg.SMSCancelGraceSec, "server.smsCancelGraceSec", &errors)
+	requireNonNegative(cfg.NotificationBatchChunkSize, "server.notificationBatchChunkSize", &errors)
+	if cfg.MaxScheduleHorizonDays < 0 {
+		errors = append(errors, "server.maxScheduleHorizonDays must not be negative")
+	}
+	if cfg.TenantCacheMaxEntries < 0 {
+		errors = append(errors, "server.tenantCacheMaxEntries must not be negative")
+	}
+	if cfg.MaxConcurrentRetriesPerTenant < 0 {
+		errors = append(errors, "server.maxConcurrentRetriesPerTenant must not be negative")
+	}
+	if cfg.IntegritySweepIntervalSec < 0 {
+		errors = append(errors, "server.integritySweepIntervalSec must not be negative")
+	}
+	if cfg.DrainTimeoutSec < 0 {
+		errors = append(errors, "server.drainTimeoutSec must not be negative")
+	}
+	if cfg.QueryTimeoutSec < 0 {
+		errors = append(errors, "server.queryTimeoutSec must not be negative")
+	}
+	if cfg.AggregateQueryTimeoutSec < 0 {
+		errors = append(errors, "server.aggregateQueryTimeoutSec must not be negative")
+	}
+	if cfg.SMTPPoolSize < 0 {
+		errors = append(errors, "server.smtpPoolSize must not be negative")
+	}
+	if cfg.SMTPPoolIdleTimeoutSec < 0 {
+		errors = append(errors, "server.smtpPoolIdleTimeoutSec must not be negative")
+	}
+	switch normalizeInFlightSendPolicy(cfg.InFlightSendPolicy) {
+	case InFlightSendPolicyReject, InFlightSendPolicyWait:
+	default:
+		errors = append(errors, "server.inFlightSendPolicy must be reject or wait")
+	}
+	if normalizeRetryQueue(cfg.RetryQueue) != RetryQueueSQL {
+		errors = append(errors, "server.retryQueue must be sql")
+	}
+	if normalizeSecretCipher(cfg.SecretCipher) != SecretCipherAESGCM {
+		errors = append(errors, "server.secretCipher must be aes-gcm")
+	}
 	requireString(cfg.MasterEncryptionKey, "server.masterEncryptionKey", &errors)
 	if len(cfg.TenantBootstrap.Tenants) == 0 {
 		requireString(cfg.TenantConfigPath, "tenants.configPath", &errors)
+		if cfg.TenantBootstrap.ForceReassignDomains {
+			errors = append(errors, "tenants.forceReassignDomains applies to inline tenants; set it in the tenants.configPath file instead")
+		}
 	}
 	requirePositive(cfg.ConnectionTimeoutSec, "server.connectionTimeoutSec", &errors)
 	requirePositive(cfg.OperationTimeoutSec, "server.operationTimeoutSec", &errors)
 
 	if cfg.WebInterfaceEnabled {
 		requireString(cfg.HTTPListenAddr, "web.listenAddr", &errors)
+		validateListenAddr(cfg.HTTPListenAddr, "web.listenAddr", &errors)
 		requireString(cfg.TAuthSigningKey, "server.tauth.signingKey", &errors)
+		requireNonNegative(cfg.HTTPReadTimeoutSec, "web.readTimeoutSec", &errors)
+		requireNonNegative(cfg.HTTPWriteTimeoutSec, "web.writeTimeoutSec", &errors)
+		requireNonNegative(cfg.HTTPIdleTimeoutSec, "web.idleTimeoutSec", &errors)
+		requireNonNegativeInt64(cfg.HTTPMaxRequestBytes, "web.maxRequestBytes", &errors)
+		requireNonNegativeInt64(cfg.HTTPMaxAttachmentRequestBytes, "web.maxAttachmentRequestBytes", &errors)
+		if naming := normalizeJSONFieldNaming(cfg.HTTPJSONFieldNaming); naming != JSONFieldNamingSnakeCase && naming != JSONFieldNamingCamelCase {
+			errors = append(errors, "web.jsonFieldNaming must be snake_case or camelCase")
+		}
+		if cfg.OpenTrackingBaseURL != "" && !isAbsoluteHTTPURL(cfg.OpenTrackingBaseURL) {
+			errors = append(errors, "web.openTrackingBaseURL must be an absolute http or https URL")
+		}
+		requireNonNegative(cfg.OpenTrackingTokenTTLHours, "web.openTrackingTokenTTLHours", &errors)
+		for _, email := range cfg.HTTPServiceAccountEmails {
+			if localPart, domain, found := strings.Cut(email, "@"); !found || localPart == "" || domain == "" {
+				errors = append(errors, fmt.Sprintf("web.serviceAccounts entry %q must be an email address", email))
+			}
+		}
 	}
 
 	if cfg.SMTPSubmission.Enabled {
@@ -425,6 +848,21 @@ func validateConfig(cfg Config) error {
 		requireString(cfg.SMTPForwarding.Relay.Password, "smtpForwarding.relay.password", &errors)
 	}
 
+	if cfg.QueueIntake.Enabled {
+		requireString(cfg.QueueIntake.URL, "queueIntake.url", &errors)
+		requireString(cfg.QueueIntake.Stream, "queueIntake.stream", &errors)
+		requireString(cfg.QueueIntake.Subject, "queueIntake.subject", &errors)
+		requireString(cfg.QueueIntake.Durable, "queueIntake.durable", &errors)
+		requireString(cfg.QueueIntake.DeadLetterSubject, "queueIntake.deadLetterSubject", &errors)
+		requireNonNegative(cfg.QueueIntake.Concurrency, "queueIntake.concurrency", &errors)
+		requireNonNegative(cfg.QueueIntake.RequeueDelaySec, "queueIntake.requeueDelaySec", &errors)
+		requireNonNegative(cfg.QueueIntake.MaxDeliver, "queueIntake.maxDeliver", &errors)
+		requireNonNegative(cfg.QueueIntake.AckWaitSec, "queueIntake.ackWaitSec", &errors)
+		if cfg.QueueIntake.Subject != "" && cfg.QueueIntake.Subject == cfg.QueueIntake.DeadLetterSubject {
+			errors = append(errors, "queueIntake.deadLetterSubject must differ from queueIntake.subject")
+		}
+	}
+
 	if len(cfg.TenantBootstrap.Tenants) > 0 {
 		for idx, tenantSpec := range cfg.TenantBootstrap.Tenants {
 			tenantPrefix := fmt.Sprintf("tenants[%d]", idx)
@@ -436,7 +874,7 @@ func validateConfig(cfg Config) error {
 	}
 
 	if len(errors) > 0 {
-		return fmt.Errorf("configuration errors: %s", strings.Join(errors, ", "))
+		return &ValidationError{Problems: errors}
 	}
 	return nil
 }
@@ -449,12 +887,166 @@ func normalizeSMTPDeliveryMode(value string) string {
 	return normalized
 }
 
+func normalizeInFlightSendPolicy(value string) string {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	if normalized == "" {
+		return InFlightSendPolicyReject
+	}
+	return normalized
+}
+
+func normalizeRetryQueue(value string) string {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	if normalized == "" {
+		return RetryQueueSQL
+	}
+	return normalized
+}
+
+// normalizeJSONFieldNaming matches the naming conventions case-insensitively and keeps
+// any other value for validation to reject.
+func normalizeJSONFieldNaming(value string) string {
+	trimmed := strings.TrimSpace(value)
+	switch strings.ToLower(trimmed) {
+	case "", strings.ToLower(JSONFieldNamingSnakeCase):
+		return JSONFieldNamingSnakeCase
+	case strings.ToLower(JSONFieldNamingCamelCase):
+		return JSONFieldNamingCamelCase
+	default:
+		return trimmed
+	}
+}
+
+func normalizeSecretCipher(value string) string {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	if normalized == "" {
+		return SecretCipherAESGCM
+	}
+	return normalized
+}
+
+func normalizeGRPCTokens(sections []grpcTokenSection) []GRPCTokenConfig {
+	if len(sections) == 0 {
+		return nil
+	}
+	tokens := make([]GRPCTokenConfig, 0, len(sections))
+	for _, section := range sections {
+		tokens = append(tokens, GRPCTokenConfig{
+			Token:     strings.TrimSpace(section.Token),
+			Scope:     strings.ToLower(strings.TrimSpace(section.Scope)),
+			TenantIDs: normalizeStrings(section.Tenants),
+		})
+	}
+	return tokens
+}
+
+func validateGRPCTokens(legacyToken string, tokens []GRPCTokenConfig, errors *[]string) {
+	seenTokens := make(map[string]struct{}, len(tokens)+1)
+	if trimmedLegacyToken := strings.TrimSpace(legacyToken); trimmedLegacyToken != "" {
+		seenTokens[trimmedLegacyToken] = struct{}{}
+	}
+	for index, token := range tokens {
+		fieldPrefix := fmt.Sprintf("server.grpcTokens[%d]", index)
+		requireString(token.Token, fieldPrefix+".token", errors)
+		switch token.Scope {
+		case GRPCTokenScopeRead, GRPCTokenScopeWrite:
+		case GRPCTokenScopeAdmin:
+			if len(token.TenantIDs) > 0 {
+				*errors = append(*errors, fieldPrefix+".tenants must be empty for admin tokens")
+			}
+		default:
+			*errors = append(*errors, fieldPrefix+".scope must be read, write, or admin")
+		}
+		if token.Token == "" {
+			continue
+		}
+		if _, duplicate := seenTokens[token.Token]; duplicate {
+			*errors = append(*errors, fieldPrefix+".token duplicates another gRPC token")
+		}
+		seenTokens[token.Token] = struct{}{}
+	}
+}
+
 func requireString(value string, name string, errors *[]string) {
 	if strings.TrimSpace(value) == "" {
 		*errors = append(*errors, fmt.Sprintf("missing %s", name))
 	}
 }
 
+// validateListenAddr checks the syntax of a non-empty listen address; requireString reports empty ones.
+func validateListenAddr(value string, name string, errors *[]string) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+	if _, err := endpoint.Parse(value); err != nil {
+		*errors = append(*errors, fmt.Sprintf("%s: %v", name, err))
+	}
+}
+
+// normalizeGRPCKeepalive fills unset intervals with defaults; pings without an active
+// stream are permitted unless disabled.
+func normalizeGRPCKeepalive(section grpcKeepaliveSection) GRPCKeepaliveConfig {
+	keepalive := GRPCKeepaliveConfig{
+		TimeSec:                  section.TimeSec,
+		TimeoutSec:               section.TimeoutSec,
+		MinClientPingIntervalSec: section.MinClientPingIntervalSec,
+		PermitPingsWithoutStream: section.PermitWithoutStream == nil || *section.PermitWithoutStream,
+	}
+	if keepalive.TimeSec == 0 {
+		keepalive.TimeSec = DefaultGRPCKeepaliveTimeSec
+	}
+	if keepalive.TimeoutSec == 0 {
+		keepalive.TimeoutSec = DefaultGRPCKeepaliveTimeoutSec
+	}
+	if keepalive.MinClientPingIntervalSec == 0 {
+		keepalive.MinClientPingIntervalSec = DefaultGRPCKeepaliveMinClientPingIntervalSec
+	}
+	return keepalive
+}
+
+func validateGRPCKeepalive(keepalive GRPCKeepaliveConfig, errors *[]string) {
+	requireNonNegative(keepalive.TimeSec, "server.grpcKeepalive.timeSec", errors)
+	requireNonNegative(keepalive.TimeoutSec, "server.grpcKeepalive.timeoutSec", errors)
+	requireNonNegative(keepalive.MinClientPingIntervalSec, "server.grpcKeepalive.minClientPingIntervalSec", errors)
+}
+
+// normalizeDispatchPacing fills unset bounds with defaults when pacing is enabled.
+func normalizeDispatchPacing(section dispatchPacingSection) DispatchPacingConfig {
+	if !section.Enabled {
+		return DispatchPacingConfig{}
+	}
+	pacing := DispatchPacingConfig(section)
+	if pacing.FailureThreshold == 0 {
+		pacing.FailureThreshold = DefaultDispatchPacingFailureThreshold
+	}
+	if pacing.MinDelayMs == 0 {
+		pacing.MinDelayMs = DefaultDispatchPacingMinDelayMs
+	}
+	if pacing.MaxDelayMs == 0 {
+		pacing.MaxDelayMs = DefaultDispatchPacingMaxDelayMs
+	}
+	if pacing.RecoveryRate == 0 {
+		pacing.RecoveryRate = DefaultDispatchPacingRecoveryRate
+	}
+	return pacing
+}
+
+func validateDispatchPacing(pacing DispatchPacingConfig, errors *[]string) {
+	if !pacing.Enabled {
+		return
+	}
+	if pacing.FailureThreshold <= 0 || pacing.FailureThreshold >= 1 {
+		*errors = append(*errors, "server.dispatchPacing.failureThreshold must be between 0 and 1")
+	}
+	requirePositive(pacing.MinDelayMs, "server.dispatchPacing.minDelayMs", errors)
+	if pacing.MaxDelayMs < pacing.MinDelayMs {
+		*errors = append(*errors, "server.dispatchPacing.maxDelayMs must not be less than minDelayMs")
+	}
+	if pacing.RecoveryRate <= 0 || pacing.RecoveryRate > 1 {
+		*errors = append(*errors, "server.dispatchPacing.recoveryRate must be greater than 0 and at most 1")
+	}
+}
+
 func requirePositive(value int, name string, errors *[]string) {
 	if value <= 0 {
 		*errors = append(*errors, fmt.Sprintf("missing %s", name))
@@ -467,6 +1059,24 @@ func requirePositiveInt64(value int64, name string, errors *[]string) {
 	}
 }
 
+func requireNonNegative(value int, name string, errors *[]string) {
+	if value < 0 {
+		*errors = append(*errors, fmt.Sprintf("%s must not be negative", name))
+	}
+}
+
+func requireNonNegativeInt64(value int64, name string, errors *[]string) {
+	if value < 0 {
+		*errors = append(*errors, fmt.Sprintf("%s must not be negative", name))
+	}
+}
+
+// isAbsoluteHTTPURL reports whether value is an http or https URL with a host.
+func isAbsoluteHTTPURL(value string) bool {
+	parsed, err := url.Parse(value)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
 func countNonEmptyStrings(values []string) int {
 	count := 0
 	for _, value := range values {
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -56,6 +58,8 @@ web:
   trustedProxies:
     - 198.51.100.10
     - "  2001:db8::/32  "
+  serviceAccounts:
+    - " monitor@ops.local "
 smtpSubmission:
   enabled: true
   hostname: smtp.one.test
@@ -90,12 +94,22 @@ smtpSubmission:
 	}
 
 	expected := Config{
-		DatabasePath:        "test.db",
-		GRPCAuthToken:       "unit-token",
-		LogLevel:            "INFO",
-		MaxRetries:          5,
-		RetryIntervalSec:    4,
-		MasterEncryptionKey: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		DatabasePath:  "test.db",
+		GRPCAuthToken: "unit-token",
+		GRPCKeepalive: GRPCKeepaliveConfig{
+			TimeSec:                  DefaultGRPCKeepaliveTimeSec,
+			TimeoutSec:               DefaultGRPCKeepaliveTimeoutSec,
+			MinClientPingIntervalSec: DefaultGRPCKeepaliveMinClientPingIntervalSec,
+			PermitPingsWithoutStream: true,
+		},
+		LogLevel:                 "INFO",
+		MaxRetries:               5,
+		RetryIntervalSec:         4,
+		InFlightSendPolicy:       InFlightSendPolicyReject,
+		RetryQueue:               RetryQueueSQL,
+		SecretCipher:             SecretCipherAESGCM,
+		WarnShortTenantRetention: true,
+		MasterEncryptionKey:      "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
 		TenantBootstrap: tenant.BootstrapConfig{
 			Tenants: []tenant.BootstrapTenant{
 				{
@@ -116,13 +130,16 @@ smtpSubmission:
 						AuthToken:  "auth",
 						FromNumber: "+10000000000",
 					},
+					SourceLine: 15,
 				},
 			},
 		},
-		WebInterfaceEnabled: true,
-		HTTPListenAddr:      ":8080",
-		HTTPAllowedOrigins:  []string{"https://app.local", "https://alt.local"},
-		HTTPTrustedProxies:  []string{"198.51.100.10", "2001:db8::/32"},
+		WebInterfaceEnabled:      true,
+		HTTPListenAddr:           ":8080",
+		HTTPAllowedOrigins:       []string{"https://app.local", "https://alt.local"},
+		HTTPTrustedProxies:       []string{"198.51.100.10", "2001:db8::/32"},
+		HTTPServiceAccountEmails: []string{"monitor@ops.local"},
+		HTTPJSONFieldNaming:      JSONFieldNamingSnakeCase,
 		SMTPSubmission: SMTPSubmissionConfig{
 			Enabled:           true,
 			Hostname:          "smtp.one.test",
@@ -483,6 +500,103 @@ func TestLoadConfigRejectsUnsupportedTenantFields(t *testing.T) {
 	}
 }
 
+func TestLoadConfigParsesQueueIntake(t *testing.T) {
+	configPath := writeConfigFile(t, `
+server:
+  databasePath: app.db
+  grpcAuthToken: token
+  logLevel: INFO
+  maxRetries: 3
+  retryIntervalSec: 30
+  masterEncryptionKey: ${MASTER_ENCRYPTION_KEY}
+  connectionTimeoutSec: 5
+  operationTimeoutSec: 10
+tenants:
+  configPath: tenants.yml
+web:
+  enabled: false
+queueIntake:
+  enabled: true
+  url: nats://nats.internal:4222
+  stream: PINGUIN
+  subject: pinguin.send
+  durable: pinguin-intake
+  deadLetterSubject: pinguin.send.dead
+  concurrency: 8
+  requeueDelaySec: 15
+  maxDeliver: 3
+  ackWaitSec: 45
+  token: ${NATS_TOKEN}
+  tenants: [" tenant-a ", ""]
+`)
+	t.Setenv("MASTER_ENCRYPTION_KEY", "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	t.Setenv("NATS_TOKEN", "nats-secret")
+
+	cfg, err := loadConfigFromPath(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig returned error: %v", err)
+	}
+	expected := QueueIntakeConfig{
+		Enabled:           true,
+		URL:               "nats://nats.internal:4222",
+		Stream:            "PINGUIN",
+		Subject:           "pinguin.send",
+		Durable:           "pinguin-intake",
+		DeadLetterSubject: "pinguin.send.dead",
+		Concurrency:       8,
+		RequeueDelaySec:   15,
+		MaxDeliver:        3,
+		AckWaitSec:        45,
+		Token:             "nats-secret",
+		TenantIDs:         []string{"tenant-a"},
+	}
+	if !reflect.DeepEqual(cfg.QueueIntake, expected) {
+		t.Fatalf("unexpected queue intake config:\n got: %#v\nwant: %#v", cfg.QueueIntake, expected)
+	}
+}
+
+func TestValidateConfigRejectsInvalidQueueIntake(t *testing.T) {
+	cfg := Config{
+		DatabasePath:         "app.db",
+		GRPCAuthToken:        "token",
+		LogLevel:             "INFO",
+		MaxRetries:           3,
+		RetryIntervalSec:     30,
+		MasterEncryptionKey:  "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		ConnectionTimeoutSec: 5,
+		OperationTimeoutSec:  10,
+		TenantConfigPath:     "tenants.yml",
+		QueueIntake: QueueIntakeConfig{
+			Enabled:           true,
+			Subject:           "pinguin.send",
+			DeadLetterSubject: "pinguin.send",
+			Concurrency:       -1,
+			AckWaitSec:        -1,
+		},
+	}
+	err := validateConfig(cfg)
+	if err == nil {
+		t.Fatalf("expected validation error")
+	}
+	for _, expected := range []string{
+		"missing queueIntake.url",
+		"missing queueIntake.stream",
+		"missing queueIntake.durable",
+		"queueIntake.concurrency",
+		"queueIntake.ackWaitSec",
+		"queueIntake.deadLetterSubject must differ from queueIntake.subject",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected error to contain %s, got %v", expected, err)
+		}
+	}
+
+	cfg.QueueIntake.Enabled = false
+	if err := validateConfig(cfg); err != nil {
+		t.Fatalf("expected a disabled queue intake to skip validation, got %v", err)
+	}
+}
+
 func TestValidateConfigRejectsInvalidSMTPForwarding(t *testing.T) {
 	cfg := Config{
 		DatabasePath:         "app.db",
@@ -578,6 +692,11 @@ func TestTenantConfigUnmarshalShapes(t *testing.T) {
 		t.Fatalf("unexpected sequence config %+v", sequence)
 	}
 
+	var forced tenantConfig
+	if err := yaml.Unmarshal([]byte("forceReassignDomains: true\ntenants:\n  - id: tenant-one\n"), &forced); err != nil || !forced.ForceReassignDomains {
+		t.Fatalf("expected forceReassignDomains to parse, got %+v (%v)", forced, err)
+	}
+
 	var unsupported tenantConfig
 	if err := yaml.Unmarshal([]byte("items:\n  - id: tenant-two\n    displayName: Two\nconfigPath: ignored.yml\n"), &unsupported); err == nil || !strings.Contains(err.Error(), "tenants.items is not supported") {
 		t.Fatalf("expected unsupported items error, got %v", err)
@@ -688,6 +807,39 @@ func TestValidateConfigAggregatesMissingFields(t *testing.T) {
 	}
 }
 
+func TestValidateConfigReturnsStructuredProblems(t *testing.T) {
+	err := validateConfig(Config{
+		WebInterfaceEnabled: true,
+		TenantBootstrap: tenant.BootstrapConfig{
+			Tenants: []tenant.BootstrapTenant{{ID: "tenant-one"}},
+		},
+	})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected ValidationError, got %T: %v", err, err)
+	}
+	if len(validationErr.Problems) < 2 {
+		t.Fatalf("expected several problems, got %v", validationErr.Problems)
+	}
+	for _, problem := range validationErr.Problems {
+		if strings.TrimSpace(problem) == "" || strings.Contains(problem, ", ") {
+			t.Fatalf("expected one field per problem, got %q", problem)
+		}
+	}
+	expectedMessage := "configuration errors: " + strings.Join(validationErr.Problems, ", ")
+	if err.Error() != expectedMessage {
+		t.Fatalf("expected joined message %q, got %q", expectedMessage, err.Error())
+	}
+	if !strings.Contains(validationErr.Problems[0], "server.databasePath") {
+		t.Fatalf("expected problems in validation order, got %v", validationErr.Problems)
+	}
+
+	wrapped := fmt.Errorf("load: %w", err)
+	if !errors.As(wrapped, &validationErr) {
+		t.Fatalf("expected ValidationError to survive wrapping")
+	}
+}
+
 func TestValidateConfigRejectsInvalidSMTPSubmissionModeAndPublicSettings(t *testing.T) {
 	cfg := Config{
 		DatabasePath:         "app.db",
@@ -727,6 +879,341 @@ func TestValidateConfigRejectsInvalidSMTPSubmissionModeAndPublicSettings(t *test
 	}
 }
 
+func TestLoadConfigParsesInFlightSendGuard(t *testing.T) {
+	t.Helper()
+	configPath := writeConfigFile(t, `
+server:
+  databasePath: app.db
+  grpcAuthToken: token
+  logLevel: INFO
+  maxRetries: 3
+  retryIntervalSec: 30
+  maxInFlightSends: 16
+  inFlightSendPolicy: " Wait "
+  masterEncryptionKey: aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa
+  connectionTimeoutSec: 5
+  operationTimeoutSec: 10
+tenants:
+  configPath: tenants.yml
+web:
+  enabled: false
+`)
+
+	cfg, err := loadConfigFromPath(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig returned error: %v", err)
+	}
+	if cfg.MaxInFlightSends != 16 {
+		t.Fatalf("expected maxInFlightSends 16, got %d", cfg.MaxInFlightSends)
+	}
+	if cfg.InFlightSendPolicy != InFlightSendPolicyWait {
+		t.Fatalf("expected wait policy, got %q", cfg.InFlightSendPolicy)
+	}
+}
+
+func TestValidateConfigRejectsInvalidInFlightSendGuard(t *testing.T) {
+	cfg := Config{
+		DatabasePath:                  "app.db",
+		GRPCAuthToken:                 "token",
+		LogLevel:                      "INFO",
+		MaxRetries:                    3,
+		RetryIntervalSec:              30,
+		MaxInFlightSends:              -1,
+		InFlightSendPolicy:            "drop",
+		MaxConcurrentRetriesPerTenant: -1,
+		IntegritySweepIntervalSec:     -1,
+		DrainTimeoutSec:               -1,
+		QueryTimeoutSec:               -1,
+		AggregateQueryTimeoutSec:      -1,
+		SMTPPoolSize:                  -1,
+		SMTPPoolIdleTimeoutSec:        -1,
+		RetryQueue:                    "redis",
+		SecretCipher:                  "pkcs11",
+		GRPCKeepalive:                 GRPCKeepaliveConfig{TimeSec: -1, MinClientPingIntervalSec: -1},
+		RetentionDays:                 -1,
+		MaxRetryAgeSec:                -1,
+		MaxPendingAgeSec:              -1,
+		SMSCancelGraceSec:             -1,
+		NotificationBatchChunkSize:    -1,
+		MasterEncryptionKey:           "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		ConnectionTimeoutSec:          5,
+		OperationTimeoutSec:           10,
+		TenantConfigPath:              "tenants.yml",
+	}
+	err := validateConfig(cfg)
+	if err == nil {
+		t.Fatalf("expected validation error")
+	}
+	for _, expected := range []string{
+		"server.maxInFlightSends",
+		"server.inFlightSendPolicy",
+		"server.maxConcurrentRetriesPerTenant",
+		"server.integritySweepIntervalSec",
+		"server.drainTimeoutSec",
+		"server.queryTimeoutSec",
+		"server.aggregateQueryTimeoutSec",
+		"server.smtpPoolSize",
+		"server.smtpPoolIdleTimeoutSec",
+		"server.retryQueue",
+		"server.secretCipher",
+		"server.grpcKeepalive.timeSec",
+		"server.grpcKeepalive.minClientPingIntervalSec",
+		"server.retentionDays",
+		"server.maxRetryAgeSec",
+		"server.maxPendingAgeSec",
+		"server.smsCancelGraceSec",
+		"server.notificationBatchChunkSize",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected error to contain %s, got %v", expected, err)
+		}
+	}
+}
+
+func TestLoadConfigParsesDispatchPacing(t *testing.T) {
+	configPath := writeConfigFile(t, `
+server:
+  databasePath: app.db
+  grpcAuthToken: token
+  logLevel: INFO
+  maxRetries: 3
+  retryIntervalSec: 30
+  dispatchPacing:
+    enabled: true
+    maxDelayMs: 10000
+  masterEncryptionKey: aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa
+  connectionTimeoutSec: 5
+  operationTimeoutSec: 10
+tenants:
+  configPath: tenants.yml
+web:
+  enabled: false
+`)
+
+	cfg, err := loadConfigFromPath(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig returned error: %v", err)
+	}
+	expected := DispatchPacingConfig{
+		Enabled:          true,
+		FailureThreshold: DefaultDispatchPacingFailureThreshold,
+		MinDelayMs:       DefaultDispatchPacingMinDelayMs,
+		MaxDelayMs:       10000,
+		RecoveryRate:     DefaultDispatchPacingRecoveryRate,
+	}
+	if cfg.DispatchPacing != expected {
+		t.Fatalf("expected %+v, got %+v", expected, cfg.DispatchPacing)
+	}
+
+	var problems []string
+	validateDispatchPacing(DispatchPacingConfig{Enabled: true, FailureThreshold: 1.5, MinDelayMs: 500, MaxDelayMs: 100, RecoveryRate: 2}, &problems)
+	for _, expectedProblem := range []string{"failureThreshold", "maxDelayMs", "recoveryRate"} {
+		if !strings.Contains(strings.Join(problems, ", "), "server.dispatchPacing."+expectedProblem) {
+			t.Fatalf("expected a %s problem, got %v", expectedProblem, problems)
+		}
+	}
+	problems = nil
+	validateDispatchPacing(DispatchPacingConfig{FailureThreshold: 5}, &problems)
+	if len(problems) != 0 {
+		t.Fatalf("expected disabled pacing to skip validation, got %v", problems)
+	}
+}
+
+func TestLoadConfigParsesGRPCKeepalive(t *testing.T) {
+	configPath := writeConfigFile(t, `
+server:
+  databasePath: app.db
+  grpcAuthToken: token
+  logLevel: INFO
+  maxRetries: 3
+  retryIntervalSec: 30
+  grpcKeepalive:
+    timeSec: 45
+    permitWithoutStream: false
+  masterEncryptionKey: aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa
+  connectionTimeoutSec: 5
+  operationTimeoutSec: 10
+tenants:
+  configPath: tenants.yml
+web:
+  enabled: false
+`)
+
+	cfg, err := loadConfigFromPath(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig returned error: %v", err)
+	}
+	expected := GRPCKeepaliveConfig{
+		TimeSec:                  45,
+		TimeoutSec:               DefaultGRPCKeepaliveTimeoutSec,
+		MinClientPingIntervalSec: DefaultGRPCKeepaliveMinClientPingIntervalSec,
+	}
+	if cfg.GRPCKeepalive != expected {
+		t.Fatalf("expected %+v, got %+v", expected, cfg.GRPCKeepalive)
+	}
+}
+
+func TestLoadConfigParsesScopedGRPCTokens(t *testing.T) {
+	t.Helper()
+	configPath := writeConfigFile(t, `
+server:
+  databasePath: app.db
+  grpcTokens:
+    - token: reader-token
+      scope: " Read "
+      tenants: [tenant-a, " "]
+    - token: writer-token
+      scope: write
+  logLevel: INFO
+  maxRetries: 3
+  retryIntervalSec: 30
+  masterEncryptionKey: aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa
+  connectionTimeoutSec: 5
+  operationTimeoutSec: 10
+tenants:
+  configPath: tenants.yml
+web:
+  enabled: false
+`)
+
+	cfg, err := loadConfigFromPath(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig returned error: %v", err)
+	}
+	expected := []GRPCTokenConfig{
+		{Token: "reader-token", Scope: GRPCTokenScopeRead, TenantIDs: []string{"tenant-a"}},
+		{Token: "writer-token", Scope: GRPCTokenScopeWrite},
+	}
+	if !reflect.DeepEqual(cfg.GRPCTokens, expected) {
+		t.Fatalf("unexpected gRPC tokens %+v", cfg.GRPCTokens)
+	}
+}
+
+func TestValidateConfigRejectsInvalidGRPCTokens(t *testing.T) {
+	cfg := Config{
+		DatabasePath:         "app.db",
+		GRPCAuthToken:        "shared",
+		GRPCTokens:           []GRPCTokenConfig{{Token: "", Scope: GRPCTokenScopeRead}, {Token: "shared", Scope: "owner"}, {Token: "operator", Scope: GRPCTokenScopeAdmin, TenantIDs: []string{"tenant-a"}}},
+		LogLevel:             "INFO",
+		MaxRetries:           3,
+		RetryIntervalSec:     30,
+		InFlightSendPolicy:   InFlightSendPolicyReject,
+		RetryQueue:           RetryQueueSQL,
+		MasterEncryptionKey:  "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		ConnectionTimeoutSec: 5,
+		OperationTimeoutSec:  10,
+		TenantConfigPath:     "tenants.yml",
+	}
+	err := validateConfig(cfg)
+	if err == nil {
+		t.Fatalf("expected validation error")
+	}
+	for _, expected := range []string{
+		"server.grpcTokens[0].token",
+		"server.grpcTokens[1].scope must be read, write, or admin",
+		"server.grpcTokens[1].token duplicates another gRPC token",
+		"server.grpcTokens[2].tenants must be empty for admin tokens",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected error to contain %s, got %v", expected, err)
+		}
+	}
+}
+
+func TestLoadConfigParsesHTTPLimits(t *testing.T) {
+	t.Helper()
+	configPath := writeConfigFile(t, `
+server:
+  databasePath: app.db
+  grpcAuthToken: token
+  logLevel: INFO
+  maxRetries: 3
+  retryIntervalSec: 30
+  masterEncryptionKey: aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa
+  connectionTimeoutSec: 5
+  operationTimeoutSec: 10
+  tauth:
+    signingKey: signing-key
+tenants:
+  configPath: tenants.yml
+web:
+  enabled: true
+  listenAddr: ":8080"
+  readTimeoutSec: 20
+  writeTimeoutSec: 40
+  idleTimeoutSec: 90
+  maxRequestBytes: 65536
+  maxAttachmentRequestBytes: 10485760
+  jsonFieldNaming: CamelCase
+  openTrackingBaseURL: https://mail.example.com/
+  openTrackingTokenTTLHours: 48
+`)
+
+	cfg, err := loadConfigFromPath(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig returned error: %v", err)
+	}
+	if cfg.HTTPReadTimeoutSec != 20 || cfg.HTTPWriteTimeoutSec != 40 || cfg.HTTPIdleTimeoutSec != 90 {
+		t.Fatalf("unexpected HTTP timeouts %d/%d/%d", cfg.HTTPReadTimeoutSec, cfg.HTTPWriteTimeoutSec, cfg.HTTPIdleTimeoutSec)
+	}
+	if cfg.HTTPMaxRequestBytes != 65536 || cfg.HTTPMaxAttachmentRequestBytes != 10485760 {
+		t.Fatalf("unexpected HTTP body limits %d/%d", cfg.HTTPMaxRequestBytes, cfg.HTTPMaxAttachmentRequestBytes)
+	}
+	if cfg.HTTPJSONFieldNaming != JSONFieldNamingCamelCase {
+		t.Fatalf("unexpected JSON field naming %q", cfg.HTTPJSONFieldNaming)
+	}
+	if cfg.OpenTrackingBaseURL != "https://mail.example.com/" || cfg.OpenTrackingTokenTTLHours != 48 {
+		t.Fatalf("unexpected open tracking settings %q/%d", cfg.OpenTrackingBaseURL, cfg.OpenTrackingTokenTTLHours)
+	}
+}
+
+func TestValidateConfigRejectsNegativeHTTPLimits(t *testing.T) {
+	cfg := Config{
+		DatabasePath:                  "app.db",
+		GRPCAuthToken:                 "token",
+		LogLevel:                      "INFO",
+		MaxRetries:                    3,
+		RetryIntervalSec:              30,
+		InFlightSendPolicy:            InFlightSendPolicyReject,
+		RetryQueue:                    RetryQueueSQL,
+		MasterEncryptionKey:           "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		ConnectionTimeoutSec:          5,
+		OperationTimeoutSec:           10,
+		TenantConfigPath:              "tenants.yml",
+		WebInterfaceEnabled:           true,
+		HTTPListenAddr:                ":8080",
+		TAuthSigningKey:               "signing-key",
+		HTTPReadTimeoutSec:            -1,
+		HTTPWriteTimeoutSec:           -1,
+		HTTPIdleTimeoutSec:            -1,
+		HTTPMaxRequestBytes:           -1,
+		HTTPMaxAttachmentRequestBytes: -1,
+		HTTPJSONFieldNaming:           "kebab-case",
+		OpenTrackingBaseURL:           "mail.example.com",
+		OpenTrackingTokenTTLHours:     -1,
+		HTTPServiceAccountEmails:      []string{"monitor"},
+	}
+	err := validateConfig(cfg)
+	if err == nil {
+		t.Fatalf("expected validation error")
+	}
+	for _, expected := range []string{
+		"web.readTimeoutSec",
+		"web.writeTimeoutSec",
+		"web.idleTimeoutSec",
+		"web.maxRequestBytes",
+		"web.maxAttachmentRequestBytes",
+		"web.jsonFieldNaming",
+		"web.openTrackingBaseURL",
+		"web.openTrackingTokenTTLHours",
+		"web.serviceAccounts",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected error to contain %s, got %v", expected, err)
+		}
+	}
+}
+
 func TestLoadConfigRejectsIncompleteSMTPSubmission(t *testing.T) {
 	t.Helper()
 	configPath := writeConfigFile(t, `
@@ -861,3 +1348,39 @@ web:
   enabled: false
 `
 }
+
+func TestValidateConfigRejectsMalformedListenAddresses(t *testing.T) {
+	cfg := Config{
+		DatabasePath:         "app.db",
+		GRPCAuthToken:        "token",
+		GRPCListenAddr:       "http://localhost:50051",
+		LogLevel:             "INFO",
+		MaxRetries:           3,
+		RetryIntervalSec:     30,
+		MasterEncryptionKey:  "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		ConnectionTimeoutSec: 5,
+		OperationTimeoutSec:  10,
+		TenantConfigPath:     "tenants.yml",
+		WebInterfaceEnabled:  true,
+		HTTPListenAddr:       "unix://",
+		TAuthSigningKey:      "signing-key",
+	}
+	err := validateConfig(cfg)
+	if err == nil {
+		t.Fatalf("expected validation error")
+	}
+	for _, expected := range []string{"server.grpcListenAddr", "web.listenAddr"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected error to contain %s, got %v", expected, err)
+		}
+	}
+
+	cfg.GRPCListenAddr = "unix:///var/run/pinguin.sock"
+	cfg.HTTPListenAddr = "tcp6://[::]:8080"
+	if err := validateConfig(cfg); err != nil {
+		t.Fatalf("expected scheme addresses to validate, got %v", err)
+	}
+	if (Config{}).GRPCListenAddress() != DefaultGRPCListenAddr || cfg.GRPCListenAddress() != "unix:///var/run/pinguin.sock" {
+		t.Fatalf("unexpected gRPC listen address fallback")
+	}
+}
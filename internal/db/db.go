@@ -11,6 +11,7 @@ import (
 
 	"github.com/glebarez/sqlite"
 	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/savedfilter"
 	"github.com/tyemirov/pinguin/internal/smtpidentity"
 	"github.com/tyemirov/pinguin/internal/tenant"
 	"gorm.io/gorm"
@@ -48,6 +49,21 @@ func InitDB(dbPath string, logger *slog.Logger) (*gorm.DB, error) {
 	return database, nil
 }
 
+// OpenExisting opens a database that must already exist, without creating or
+// migrating it, so inspection tools such as the doctor never change its schema.
+func OpenExisting(dbPath string, logger *slog.Logger) (*gorm.DB, error) {
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, fmt.Errorf("open sqlite failed: %w", err)
+	}
+	database, err := gorm.Open(sqlite.Open(sqliteDSN(dbPath)), &gorm.Config{
+		Logger: &slogGormLogger{logger: logger},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite failed: %w", err)
+	}
+	return database, nil
+}
+
 func sqliteDSN(dbPath string) string {
 	separator := "?"
 	if strings.Contains(dbPath, "?") {
@@ -68,6 +84,14 @@ var migrateDatabaseSchema = func(database *gorm.DB) error {
 	return database.AutoMigrate(
 		&model.Notification{},
 		&model.NotificationAttachment{},
+		&model.AttachmentBlob{},
+		&model.ReportRun{},
+		&model.WorkerCheckpoint{},
+		&model.WebhookDelivery{},
+		&model.RenderedContent{},
+		&model.NotificationOpen{},
+		&model.ProviderBreaker{},
+		&model.QueueIntakeKey{},
 		&tenant.Tenant{},
 		&tenant.TenantDomain{},
 		&tenant.TenantAdmin{},
@@ -76,6 +100,7 @@ var migrateDatabaseSchema = func(database *gorm.DB) error {
 		&smtpidentity.SenderDomain{},
 		&smtpidentity.Identity{},
 		&smtpidentity.ForwardRecipient{},
+		&savedfilter.SavedFilter{},
 	)
 }
 
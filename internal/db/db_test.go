@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -234,3 +236,60 @@ func TestSlogGormLoggerOmitsInterpolatedSQLValues(t *testing.T) {
 		t.Fatalf("expected log output to include row count, got %q", output)
 	}
 }
+
+func TestConcurrentWritersAndReadersSurfaceNoBusyErrors(t *testing.T) {
+	databasePath := filepath.Join(t.TempDir(), "pinguin.db")
+	database, initError := InitDB(databasePath, slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})))
+	if initError != nil {
+		t.Fatalf("init db error: %v", initError)
+	}
+	const writerCount, readerCount, notificationsPerWriter = 8, 4, 20
+	ctx := context.Background()
+	errorsSeen := make(chan error, writerCount*notificationsPerWriter*2+readerCount*notificationsPerWriter)
+	var waitGroup sync.WaitGroup
+	for writerIndex := 0; writerIndex < writerCount; writerIndex++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for notificationIndex := 0; notificationIndex < notificationsPerWriter; notificationIndex++ {
+				notification := model.Notification{
+					TenantID:         dbTestTenantID,
+					NotificationID:   fmt.Sprintf("stress-%d-%d", writerIndex, notificationIndex),
+					NotificationType: model.NotificationEmail,
+					Recipient:        "user@example.com",
+					Message:          "Body",
+					Status:           model.StatusQueued,
+					Attachments:      []model.NotificationAttachment{{TenantID: dbTestTenantID, Filename: "a.txt", ContentType: "text/plain", Data: []byte("a"), SizeBytes: 1}},
+				}
+				if err := model.CreateNotification(ctx, database, &notification); err != nil {
+					errorsSeen <- err
+					continue
+				}
+				notification.Status = model.StatusSent
+				if err := model.SaveNotification(ctx, database, &notification); err != nil {
+					errorsSeen <- err
+				}
+			}
+		}()
+	}
+	for readerIndex := 0; readerIndex < readerCount; readerIndex++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for attempt := 0; attempt < notificationsPerWriter; attempt++ {
+				if _, err := model.ListNotifications(ctx, database, dbTestTenantID, model.NotificationListFilters{}); err != nil {
+					errorsSeen <- err
+				}
+			}
+		}()
+	}
+	waitGroup.Wait()
+	close(errorsSeen)
+	for err := range errorsSeen {
+		t.Errorf("unexpected error under contention (busy=%v): %v", model.IsDatabaseBusy(err), err)
+	}
+	sent, err := model.ListNotifications(ctx, database, dbTestTenantID, model.NotificationListFilters{Statuses: []model.NotificationStatus{model.StatusSent}})
+	if err != nil || len(sent) != writerCount*notificationsPerWriter {
+		t.Fatalf("expected every notification to be saved as sent, got %d (%v)", len(sent), err)
+	}
+}
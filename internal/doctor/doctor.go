@@ -6,13 +6,19 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
 	"os"
 	"sort"
 	"strings"
 	"time"
 
 	runtimeconfig "github.com/tyemirov/pinguin/internal/config"
+	pinguindb "github.com/tyemirov/pinguin/internal/db"
+	"github.com/tyemirov/pinguin/internal/model"
 	"github.com/tyemirov/pinguin/internal/tenant"
+	"github.com/tyemirov/pinguin/pkg/endpoint"
 	"gopkg.in/yaml.v3"
 )
 
@@ -27,6 +33,8 @@ type DiagnosticResult struct {
 	Errors     []string `json:"errors,omitempty"`
 	Warnings   []string `json:"warnings,omitempty"`
 	TenantIDs  []string `json:"tenant_ids,omitempty"`
+	// Integrity holds the database integrity sweep when --database was requested.
+	Integrity *model.AttachmentIntegrityReport `json:"integrity,omitempty"`
 }
 
 // Report represents the complete doctor report for all validated configurations.
@@ -63,6 +71,10 @@ type Options struct {
 	ConfigPaths          []string
 	ValidateCrossConfigs bool
 	ExpandEnv            bool
+	// CheckDatabase opens each valid config's database and runs the attachment integrity sweep.
+	CheckDatabase bool
+	// RepairOrphans deletes orphaned attachment rows found by CheckDatabase.
+	RepairOrphans bool
 }
 
 // pinguinConfig mirrors the Pinguin configuration file structure for validation.
@@ -71,26 +83,85 @@ type pinguinConfig struct {
 	Web            pinguinWeb            `yaml:"web"`
 	SMTPSubmission pinguinSMTPSubmission `yaml:"smtpSubmission"`
 	SMTPForwarding pinguinSMTPForwarding `yaml:"smtpForwarding"`
+	QueueIntake    pinguinQueueIntake    `yaml:"queueIntake"`
 	Tenants        pinguinYAMLNode       `yaml:"tenants"`
 }
 
 type pinguinServer struct {
-	DatabasePath        string       `yaml:"databasePath"`
-	GRPCAuthToken       string       `yaml:"grpcAuthToken"`
-	LogLevel            string       `yaml:"logLevel"`
-	MaxRetries          int          `yaml:"maxRetries"`
-	RetryIntervalSec    int          `yaml:"retryIntervalSec"`
-	MasterEncryptionKey string       `yaml:"masterEncryptionKey"`
-	ConnectionTimeout   int          `yaml:"connectionTimeoutSec"`
-	OperationTimeout    int          `yaml:"operationTimeoutSec"`
-	TAuth               pinguinTAuth `yaml:"tauth"`
+	DatabasePath        string                `yaml:"databasePath"`
+	GRPCAuthToken       string                `yaml:"grpcAuthToken"`
+	GRPCTokens          []pinguinGRPCToken    `yaml:"grpcTokens"`
+	GRPCListenAddr      string                `yaml:"grpcListenAddr"`
+	GRPCKeepalive       pinguinGRPCKeepalive  `yaml:"grpcKeepalive"`
+	LogLevel            string                `yaml:"logLevel"`
+	MaxRetries          int                   `yaml:"maxRetries"`
+	RetryIntervalSec    int                   `yaml:"retryIntervalSec"`
+	MaxInFlightSends    int                   `yaml:"maxInFlightSends"`
+	InFlightSendPolicy  string                `yaml:"inFlightSendPolicy"`
+	DispatchPacing      pinguinDispatchPacing `yaml:"dispatchPacing"`
+	MaxScheduleHorizon  int                   `yaml:"maxScheduleHorizonDays"`
+	StrictScheduling    bool                  `yaml:"strictScheduling"`
+	StrictStatusFilters bool                  `yaml:"strictStatusFilters"`
+	FailOnImmediate     bool                  `yaml:"failOnImmediateError"`
+	TenantCacheMax      int                   `yaml:"tenantCacheMaxEntries"`
+	MaxRetriesPerTenant int                   `yaml:"maxConcurrentRetriesPerTenant"`
+	MaxRetryAgeSec      int                   `yaml:"maxRetryAgeSec"`
+	MaxPendingAgeSec    int                   `yaml:"maxPendingAgeSec"`
+	SMSCancelGraceSec   int                   `yaml:"smsCancelGraceSec"`
+	BatchChunkSize      int                   `yaml:"notificationBatchChunkSize"`
+	IntegritySweepSec   int                   `yaml:"integritySweepIntervalSec"`
+	IntegrityRepair     bool                  `yaml:"integritySweepRepairOrphans"`
+	DrainTimeoutSec     int                   `yaml:"drainTimeoutSec"`
+	QueryTimeoutSec     int                   `yaml:"queryTimeoutSec"`
+	AggregateTimeoutSec int                   `yaml:"aggregateQueryTimeoutSec"`
+	SMTPPoolSize        int                   `yaml:"smtpPoolSize"`
+	SMTPPoolIdleSec     int                   `yaml:"smtpPoolIdleTimeoutSec"`
+	StrictTenantCheck   bool                  `yaml:"strictTenantSelfCheck"`
+	RetryQueue          string                `yaml:"retryQueue"`
+	SecretCipher        string                `yaml:"secretCipher"`
+	RetentionDays       int                   `yaml:"retentionDays"`
+	WarnShortRetention  *bool                 `yaml:"warnShortTenantRetention"`
+	MasterEncryptionKey string                `yaml:"masterEncryptionKey"`
+	ConnectionTimeout   int                   `yaml:"connectionTimeoutSec"`
+	OperationTimeout    int                   `yaml:"operationTimeoutSec"`
+	TAuth               pinguinTAuth          `yaml:"tauth"`
+}
+
+type pinguinGRPCKeepalive struct {
+	TimeSec                  int   `yaml:"timeSec"`
+	TimeoutSec               int   `yaml:"timeoutSec"`
+	MinClientPingIntervalSec int   `yaml:"minClientPingIntervalSec"`
+	PermitWithoutStream      *bool `yaml:"permitWithoutStream"`
+}
+
+type pinguinDispatchPacing struct {
+	Enabled          bool    `yaml:"enabled"`
+	FailureThreshold float64 `yaml:"failureThreshold"`
+	MinDelayMs       int     `yaml:"minDelayMs"`
+	MaxDelayMs       int     `yaml:"maxDelayMs"`
+	RecoveryRate     float64 `yaml:"recoveryRate"`
+}
+
+type pinguinGRPCToken struct {
+	Token   string   `yaml:"token"`
+	Scope   string   `yaml:"scope"`
+	Tenants []string `yaml:"tenants"`
 }
 
 type pinguinWeb struct {
-	Enabled        *bool    `yaml:"enabled"`
-	ListenAddr     string   `yaml:"listenAddr"`
-	AllowedOrigins []string `yaml:"allowedOrigins"`
-	TrustedProxies []string `yaml:"trustedProxies"`
+	Enabled                   *bool    `yaml:"enabled"`
+	ListenAddr                string   `yaml:"listenAddr"`
+	AllowedOrigins            []string `yaml:"allowedOrigins"`
+	TrustedProxies            []string `yaml:"trustedProxies"`
+	ServiceAccounts           []string `yaml:"serviceAccounts"`
+	ReadTimeoutSec            int      `yaml:"readTimeoutSec"`
+	WriteTimeoutSec           int      `yaml:"writeTimeoutSec"`
+	IdleTimeoutSec            int      `yaml:"idleTimeoutSec"`
+	MaxRequestBytes           int64    `yaml:"maxRequestBytes"`
+	MaxAttachmentRequestBytes int64    `yaml:"maxAttachmentRequestBytes"`
+	JSONFieldNaming           string   `yaml:"jsonFieldNaming"`
+	OpenTrackingBaseURL       string   `yaml:"openTrackingBaseURL"`
+	OpenTrackingTokenTTLHours int      `yaml:"openTrackingTokenTTLHours"`
 }
 
 type pinguinTAuth struct {
@@ -130,39 +201,64 @@ type pinguinSMTPForwarding struct {
 	Relay           pinguinSMTPRelay `yaml:"relay"`
 }
 
+type pinguinQueueIntake struct {
+	Enabled           bool     `yaml:"enabled"`
+	URL               string   `yaml:"url"`
+	Stream            string   `yaml:"stream"`
+	Subject           string   `yaml:"subject"`
+	Durable           string   `yaml:"durable"`
+	DeadLetterSubject string   `yaml:"deadLetterSubject"`
+	Concurrency       int      `yaml:"concurrency"`
+	RequeueDelaySec   int      `yaml:"requeueDelaySec"`
+	MaxDeliver        int      `yaml:"maxDeliver"`
+	AckWaitSec        int      `yaml:"ackWaitSec"`
+	Username          string   `yaml:"username"`
+	Password          string   `yaml:"password"`
+	Token             string   `yaml:"token"`
+	CredentialsFile   string   `yaml:"credentialsFile"`
+	Tenants           []string `yaml:"tenants"`
+}
+
 type pinguinTenant = tenant.BootstrapTenant
 
 type pinguinYAMLNode struct {
-	ConfigPath string          `yaml:"configPath"`
-	Tenants    []pinguinTenant `yaml:"tenants"`
-	Raw        *yaml.Node      `yaml:"-"`
+	ConfigPath           string          `yaml:"configPath"`
+	Tenants              []pinguinTenant `yaml:"tenants"`
+	ForceReassignDomains bool            `yaml:"forceReassignDomains"`
+	Raw                  *yaml.Node      `yaml:"-"`
 }
 
 func (node *pinguinYAMLNode) UnmarshalYAML(value *yaml.Node) error {
 	node.Raw = value
 	switch value.Kind {
 	case yaml.SequenceNode:
-		var tenants []pinguinTenant
-		if decodeErr := value.Decode(&tenants); decodeErr != nil {
+		tenants, decodeErr := tenant.DecodeBootstrapTenants(value)
+		if decodeErr != nil {
 			return fmt.Errorf("configuration: parse tenants: %w", decodeErr)
 		}
 		node.ConfigPath = ""
 		node.Tenants = tenants
 		return nil
 	case yaml.MappingNode:
-		if unknownKey := firstUnsupportedTenantMappingKey(value, "configPath", "tenants"); unknownKey != "" {
+		if unknownKey := firstUnsupportedTenantMappingKey(value, "configPath", "tenants", "forceReassignDomains"); unknownKey != "" {
 			return fmt.Errorf("configuration: tenants.%s is not supported", unknownKey)
 		}
 		type decoded struct {
-			ConfigPath string          `yaml:"configPath"`
-			Tenants    []pinguinTenant `yaml:"tenants"`
+			ConfigPath           string    `yaml:"configPath"`
+			Tenants              yaml.Node `yaml:"tenants"`
+			ForceReassignDomains bool      `yaml:"forceReassignDomains"`
 		}
 		var decodedConfig decoded
 		if decodeErr := value.Decode(&decodedConfig); decodeErr != nil {
 			return fmt.Errorf("configuration: parse tenants: %w", decodeErr)
 		}
+		tenants, decodeErr := tenant.DecodeBootstrapTenants(&decodedConfig.Tenants)
+		if decodeErr != nil {
+			return fmt.Errorf("configuration: parse tenants: %w", decodeErr)
+		}
 		node.ConfigPath = strings.TrimSpace(decodedConfig.ConfigPath)
-		node.Tenants = decodedConfig.Tenants
+		node.Tenants = tenants
+		node.ForceReassignDomains = decodedConfig.ForceReassignDomains
 		return nil
 	default:
 		return fmt.Errorf("configuration: tenants must be a list")
@@ -188,7 +284,7 @@ func (node *pinguinYAMLNode) AllTenants() []pinguinTenant {
 }
 
 // Run executes the doctor validation for the specified configurations.
-func Run(_ context.Context, options Options) (*Report, error) {
+func Run(ctx context.Context, options Options) (*Report, error) {
 	if len(options.ConfigPaths) == 0 {
 		return nil, fmt.Errorf("%w: no config paths provided", errDoctor)
 	}
@@ -207,6 +303,9 @@ func Run(_ context.Context, options Options) (*Report, error) {
 
 	for _, configPath := range options.ConfigPaths {
 		diagnostic, config := validateConfig(configPath, options.ExpandEnv)
+		if options.CheckDatabase && diagnostic.Valid && config != nil {
+			checkDatabaseIntegrity(ctx, config.Server.DatabasePath, options.RepairOrphans, &diagnostic)
+		}
 		report.Diagnostics = append(report.Diagnostics, diagnostic)
 		if diagnostic.Valid && config != nil {
 			allConfigsByPath[configPath] = config
@@ -266,6 +365,7 @@ func validateConfig(configPath string, expandEnv bool) (DiagnosticResult, *pingu
 	}
 	validateSMTPSubmissionConfig(config.SMTPSubmission, &result)
 	validateSMTPForwardingConfig(config.SMTPForwarding, &result)
+	validateQueueIntakeConfig(config.QueueIntake, &result)
 
 	tenants := tenantsForValidation(config.Tenants, &result)
 	for _, tenant := range tenants {
@@ -275,6 +375,10 @@ func validateConfig(configPath string, expandEnv bool) (DiagnosticResult, *pingu
 		}
 		validateTenantConfig(tenant, webEnabled, &result)
 	}
+	if len(tenants) > 0 {
+		validateTenantBootstrap(tenants, &result)
+		warnShortTenantRetention(config.Server, tenants, &result)
+	}
 
 	sort.Strings(result.Errors)
 	sort.Strings(result.Warnings)
@@ -283,6 +387,37 @@ func validateConfig(configPath string, expandEnv bool) (DiagnosticResult, *pingu
 	return result, &config
 }
 
+// checkDatabaseIntegrity reports integrity findings as warnings; only a database
+// that cannot be opened or read fails the config.
+func checkDatabaseIntegrity(ctx context.Context, databasePath string, repairOrphans bool, result *DiagnosticResult) {
+	database, openErr := pinguindb.OpenExisting(strings.TrimSpace(databasePath), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if openErr != nil {
+		result.Valid = false
+		result.Errors = append(result.Errors, fmt.Sprintf("database: %v", openErr))
+		return
+	}
+	if sqlDB, sqlErr := database.DB(); sqlErr == nil {
+		defer sqlDB.Close()
+	}
+	integrity, checkErr := model.CheckAttachmentIntegrity(ctx, database, repairOrphans, time.Now().UTC())
+	if checkErr != nil {
+		result.Valid = false
+		result.Errors = append(result.Errors, fmt.Sprintf("database: %v", checkErr))
+		return
+	}
+	result.Integrity = &integrity
+	if unrepaired := integrity.OrphanedAttachments - integrity.RepairedOrphans; unrepaired > 0 {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("database: %d orphaned attachment rows (rerun with --repair-orphans to delete them)", unrepaired))
+	}
+	if integrity.AttachmentSizeMismatches > 0 {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("database: %d attachment rows whose size_bytes disagrees with the stored data", integrity.AttachmentSizeMismatches))
+	}
+	if integrity.DuplicateNotificationIDs > 0 {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("database: %d notification ids stored under more than one tenant", integrity.DuplicateNotificationIDs))
+	}
+	sort.Strings(result.Warnings)
+}
+
 func tenantsForValidation(config pinguinYAMLNode, result *DiagnosticResult) []pinguinTenant {
 	tenants := config.AllTenants()
 	if len(tenants) > 0 {
@@ -294,17 +429,15 @@ func tenantsForValidation(config pinguinYAMLNode, result *DiagnosticResult) []pi
 		result.Errors = append(result.Errors, "tenants.configPath is required when no inline tenants are configured")
 		return nil
 	}
-
-	rawContents, readErr := os.ReadFile(tenantConfigPath)
-	if readErr != nil {
+	if config.ForceReassignDomains {
 		result.Valid = false
-		result.Errors = append(result.Errors, fmt.Sprintf("tenants.configPath read %s: %v", tenantConfigPath, readErr))
-		return nil
+		result.Errors = append(result.Errors, "tenants.forceReassignDomains applies to inline tenants; set it in the tenants.configPath file instead")
 	}
-	var bootstrapConfig tenant.BootstrapConfig
-	if parseErr := yaml.Unmarshal(rawContents, &bootstrapConfig); parseErr != nil {
+
+	bootstrapConfig, loadErr := tenant.LoadBootstrapConfigFiles(tenantConfigPath)
+	if loadErr != nil {
 		result.Valid = false
-		result.Errors = append(result.Errors, fmt.Sprintf("tenants.configPath parse_yaml: %v", parseErr))
+		result.Errors = append(result.Errors, "tenants.configPath "+strings.TrimPrefix(loadErr.Error(), "tenant bootstrap: "))
 		return nil
 	}
 	if len(bootstrapConfig.Tenants) == 0 {
@@ -315,15 +448,40 @@ func tenantsForValidation(config pinguinYAMLNode, result *DiagnosticResult) []pi
 	return bootstrapConfig.Tenants
 }
 
+// validateDispatchPacing checks explicit bounds; zero values fall back to server defaults.
+func validateDispatchPacing(pacing pinguinDispatchPacing, result *DiagnosticResult) {
+	if !pacing.Enabled {
+		return
+	}
+	if pacing.FailureThreshold < 0 || pacing.FailureThreshold >= 1 {
+		result.Valid = false
+		result.Errors = append(result.Errors, "server.dispatchPacing.failureThreshold must be between 0 and 1")
+	}
+	if pacing.MinDelayMs < 0 || pacing.MaxDelayMs < 0 {
+		result.Valid = false
+		result.Errors = append(result.Errors, "server.dispatchPacing delays must not be negative")
+	}
+	if pacing.MinDelayMs > 0 && pacing.MaxDelayMs > 0 && pacing.MaxDelayMs < pacing.MinDelayMs {
+		result.Valid = false
+		result.Errors = append(result.Errors, "server.dispatchPacing.maxDelayMs must not be less than minDelayMs")
+	}
+	if pacing.RecoveryRate < 0 || pacing.RecoveryRate > 1 {
+		result.Valid = false
+		result.Errors = append(result.Errors, "server.dispatchPacing.recoveryRate must be greater than 0 and at most 1")
+	}
+}
+
 func validateServerConfig(server pinguinServer, webEnabled bool, result *DiagnosticResult) {
 	if strings.TrimSpace(server.DatabasePath) == "" {
 		result.Valid = false
 		result.Errors = append(result.Errors, "server.databasePath is required")
 	}
-	if strings.TrimSpace(server.GRPCAuthToken) == "" {
+	if strings.TrimSpace(server.GRPCAuthToken) == "" && len(server.GRPCTokens) == 0 {
 		result.Valid = false
-		result.Errors = append(result.Errors, "server.grpcAuthToken is required")
+		result.Errors = append(result.Errors, "server.grpcAuthToken or server.grpcTokens is required")
 	}
+	validateGRPCTokens(server, result)
+	validateListenAddr(server.GRPCListenAddr, "server.grpcListenAddr", result)
 	if strings.TrimSpace(server.LogLevel) == "" {
 		result.Valid = false
 		result.Errors = append(result.Errors, "server.logLevel is required")
@@ -336,6 +494,89 @@ func validateServerConfig(server pinguinServer, webEnabled bool, result *Diagnos
 		result.Valid = false
 		result.Errors = append(result.Errors, "server.retryIntervalSec must be positive")
 	}
+	if server.MaxInFlightSends < 0 {
+		result.Valid = false
+		result.Errors = append(result.Errors, "server.maxInFlightSends must not be negative")
+	}
+	switch strings.ToLower(strings.TrimSpace(server.InFlightSendPolicy)) {
+	case "", "reject", "wait":
+	default:
+		result.Valid = false
+		result.Errors = append(result.Errors, "server.inFlightSendPolicy must be reject or wait")
+	}
+	switch strings.ToLower(strings.TrimSpace(server.RetryQueue)) {
+	case "", "sql":
+	default:
+		result.Valid = false
+		result.Errors = append(result.Errors, "server.retryQueue must be sql")
+	}
+	switch strings.ToLower(strings.TrimSpace(server.SecretCipher)) {
+	case "", "aes-gcm":
+	default:
+		result.Valid = false
+		result.Errors = append(result.Errors, "server.secretCipher must be aes-gcm")
+	}
+	validateDispatchPacing(server.DispatchPacing, result)
+	if server.RetentionDays < 0 {
+		result.Valid = false
+		result.Errors = append(result.Errors, "server.retentionDays must not be negative")
+	}
+	if server.MaxRetryAgeSec < 0 {
+		result.Valid = false
+		result.Errors = append(result.Errors, "server.maxRetryAgeSec must not be negative")
+	}
+	if server.MaxPendingAgeSec < 0 {
+		result.Valid = false
+		result.Errors = append(result.Errors, "server.maxPendingAgeSec must not be negative")
+	}
+	if server.SMSCancelGraceSec < 0 {
+		result.Valid = false
+		result.Errors = append(result.Errors, "server.smsCancelGraceSec must not be negative")
+	}
+	if server.BatchChunkSize < 0 {
+		result.Valid = false
+		result.Errors = append(result.Errors, "server.notificationBatchChunkSize must not be negative")
+	}
+	if server.GRPCKeepalive.TimeSec < 0 || server.GRPCKeepalive.TimeoutSec < 0 || server.GRPCKeepalive.MinClientPingIntervalSec < 0 {
+		result.Valid = false
+		result.Errors = append(result.Errors, "server.grpcKeepalive intervals must not be negative")
+	}
+	if server.MaxScheduleHorizon < 0 {
+		result.Valid = false
+		result.Errors = append(result.Errors, "server.maxScheduleHorizonDays must not be negative")
+	}
+	if server.TenantCacheMax < 0 {
+		result.Valid = false
+		result.Errors = append(result.Errors, "server.tenantCacheMaxEntries must not be negative")
+	}
+	if server.MaxRetriesPerTenant < 0 {
+		result.Valid = false
+		result.Errors = append(result.Errors, "server.maxConcurrentRetriesPerTenant must not be negative")
+	}
+	if server.IntegritySweepSec < 0 {
+		result.Valid = false
+		result.Errors = append(result.Errors, "server.integritySweepIntervalSec must not be negative")
+	}
+	if server.DrainTimeoutSec < 0 {
+		result.Valid = false
+		result.Errors = append(result.Errors, "server.drainTimeoutSec must not be negative")
+	}
+	if server.QueryTimeoutSec < 0 {
+		result.Valid = false
+		result.Errors = append(result.Errors, "server.queryTimeoutSec must not be negative")
+	}
+	if server.AggregateTimeoutSec < 0 {
+		result.Valid = false
+		result.Errors = append(result.Errors, "server.aggregateQueryTimeoutSec must not be negative")
+	}
+	if server.SMTPPoolSize < 0 {
+		result.Valid = false
+		result.Errors = append(result.Errors, "server.smtpPoolSize must not be negative")
+	}
+	if server.SMTPPoolIdleSec < 0 {
+		result.Valid = false
+		result.Errors = append(result.Errors, "server.smtpPoolIdleTimeoutSec must not be negative")
+	}
 	if strings.TrimSpace(server.MasterEncryptionKey) == "" {
 		result.Valid = false
 		result.Errors = append(result.Errors, "server.masterEncryptionKey is required")
@@ -355,6 +596,35 @@ func validateServerConfig(server pinguinServer, webEnabled bool, result *Diagnos
 	}
 }
 
+func validateGRPCTokens(server pinguinServer, result *DiagnosticResult) {
+	hasLimitedToken := false
+	for index, token := range server.GRPCTokens {
+		if strings.TrimSpace(token.Token) == "" {
+			result.Valid = false
+			result.Errors = append(result.Errors, fmt.Sprintf("server.grpcTokens[%d].token is required", index))
+		}
+		switch strings.ToLower(strings.TrimSpace(token.Scope)) {
+		case "read":
+			hasLimitedToken = true
+		case "write":
+			if len(token.Tenants) > 0 {
+				hasLimitedToken = true
+			}
+		case "admin":
+			if len(token.Tenants) > 0 {
+				result.Valid = false
+				result.Errors = append(result.Errors, fmt.Sprintf("server.grpcTokens[%d].tenants must be empty for admin tokens", index))
+			}
+		default:
+			result.Valid = false
+			result.Errors = append(result.Errors, fmt.Sprintf("server.grpcTokens[%d].scope must be read, write, or admin", index))
+		}
+	}
+	if !hasLimitedToken && (strings.TrimSpace(server.GRPCAuthToken) != "" || len(server.GRPCTokens) > 0) {
+		result.Warnings = append(result.Warnings, "only full-access gRPC tokens are configured; add a read-scoped server.grpcTokens entry for monitoring clients")
+	}
+}
+
 func validateServerTAuthConfig(tauth pinguinTAuth, result *DiagnosticResult) {
 	if strings.TrimSpace(tauth.SigningKey) == "" {
 		result.Valid = false
@@ -362,11 +632,60 @@ func validateServerTAuthConfig(tauth pinguinTAuth, result *DiagnosticResult) {
 	}
 }
 
+// validateListenAddr checks the syntax of a non-empty listen address with the parser the servers use.
+func validateListenAddr(value string, name string, result *DiagnosticResult) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+	if _, err := endpoint.Parse(value); err != nil {
+		result.Valid = false
+		result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", name, err))
+	}
+}
+
 func validateWebConfig(web pinguinWeb, result *DiagnosticResult) {
 	if strings.TrimSpace(web.ListenAddr) == "" {
 		result.Valid = false
 		result.Errors = append(result.Errors, "web.listenAddr is required when web is enabled")
 	}
+	validateListenAddr(web.ListenAddr, "web.listenAddr", result)
+	for _, limit := range []struct {
+		name  string
+		value int64
+	}{
+		{name: "web.readTimeoutSec", value: int64(web.ReadTimeoutSec)},
+		{name: "web.writeTimeoutSec", value: int64(web.WriteTimeoutSec)},
+		{name: "web.idleTimeoutSec", value: int64(web.IdleTimeoutSec)},
+		{name: "web.maxRequestBytes", value: web.MaxRequestBytes},
+		{name: "web.maxAttachmentRequestBytes", value: web.MaxAttachmentRequestBytes},
+		{name: "web.openTrackingTokenTTLHours", value: int64(web.OpenTrackingTokenTTLHours)},
+	} {
+		if limit.value < 0 {
+			result.Valid = false
+			result.Errors = append(result.Errors, fmt.Sprintf("%s must not be negative", limit.name))
+		}
+	}
+	switch strings.ToLower(strings.TrimSpace(web.JSONFieldNaming)) {
+	case "", "snake_case":
+	case "camelcase":
+		result.Warnings = append(result.Warnings, "web.jsonFieldNaming is camelCase; the bundled dashboard reads snake_case fields and does not work with it")
+	default:
+		result.Valid = false
+		result.Errors = append(result.Errors, "web.jsonFieldNaming must be snake_case or camelCase")
+	}
+	validateOpenTrackingBaseURL(web, result)
+}
+
+func validateOpenTrackingBaseURL(web pinguinWeb, result *DiagnosticResult) {
+	baseURL := strings.TrimSpace(web.OpenTrackingBaseURL)
+	if baseURL == "" {
+		return
+	}
+	parsed, err := url.Parse(baseURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		result.Valid = false
+		result.Errors = append(result.Errors, "web.openTrackingBaseURL must be an absolute http or https URL")
+	}
 }
 
 func validateSMTPSubmissionConfig(submission pinguinSMTPSubmission, result *DiagnosticResult) {
@@ -474,6 +793,43 @@ func validateSMTPForwardingConfig(forwarding pinguinSMTPForwarding, result *Diag
 	}
 }
 
+func validateQueueIntakeConfig(intake pinguinQueueIntake, result *DiagnosticResult) {
+	if !intake.Enabled {
+		return
+	}
+	for _, field := range []struct{ name, value string }{
+		{"url", intake.URL},
+		{"stream", intake.Stream},
+		{"subject", intake.Subject},
+		{"durable", intake.Durable},
+		{"deadLetterSubject", intake.DeadLetterSubject},
+	} {
+		if strings.TrimSpace(field.value) == "" {
+			result.Valid = false
+			result.Errors = append(result.Errors, "queueIntake."+field.name+" is required when queue intake is enabled")
+		}
+	}
+	for _, field := range []struct {
+		name  string
+		value int
+	}{
+		{"concurrency", intake.Concurrency},
+		{"requeueDelaySec", intake.RequeueDelaySec},
+		{"maxDeliver", intake.MaxDeliver},
+		{"ackWaitSec", intake.AckWaitSec},
+	} {
+		if field.value < 0 {
+			result.Valid = false
+			result.Errors = append(result.Errors, "queueIntake."+field.name+" must not be negative")
+		}
+	}
+	subject := strings.TrimSpace(intake.Subject)
+	if subject != "" && subject == strings.TrimSpace(intake.DeadLetterSubject) {
+		result.Valid = false
+		result.Errors = append(result.Errors, "queueIntake.deadLetterSubject must differ from queueIntake.subject")
+	}
+}
+
 func normalizeSMTPDeliveryMode(value string) string {
 	normalized := strings.ToLower(strings.TrimSpace(value))
 	if normalized == "" {
@@ -494,17 +850,6 @@ func validateTenantConfig(tenant pinguinTenant, webEnabled bool, result *Diagnos
 		result.Errors = append(result.Errors, fmt.Sprintf("tenant[%s]: displayName is required", tenantLabel))
 	}
 
-	validDomains := 0
-	for _, domain := range tenant.Domains {
-		if strings.TrimSpace(domain) != "" {
-			validDomains++
-		}
-	}
-	if validDomains == 0 {
-		result.Valid = false
-		result.Errors = append(result.Errors, fmt.Sprintf("tenant[%s]: at least one domain is required", tenantLabel))
-	}
-
 	if webEnabled {
 		validAdmins := 0
 		for _, admin := range tenant.Admins {
@@ -519,6 +864,30 @@ func validateTenantConfig(tenant pinguinTenant, webEnabled bool, result *Diagnos
 	}
 }
 
+// warnShortTenantRetention flags tenants that purge notifications sooner than the server
+// default, which usually means a typo such as 30 for 300. Setting
+// server.warnShortTenantRetention to false silences it.
+func warnShortTenantRetention(server pinguinServer, tenants []pinguinTenant, result *DiagnosticResult) {
+	if server.WarnShortRetention != nil && !*server.WarnShortRetention {
+		return
+	}
+	for _, tenantSpec := range tenants {
+		if tenantSpec.RetentionDays > 0 && tenantSpec.RetentionDays < server.RetentionDays {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("tenant[%s]: retentionDays %d is shorter than server.retentionDays %d", strings.TrimSpace(tenantSpec.ID), tenantSpec.RetentionDays, server.RetentionDays))
+		}
+	}
+}
+
+// validateTenantBootstrap applies the checks tenant bootstrap runs before writing, so
+// the doctor and server startup reject the same tenant files with the same messages.
+func validateTenantBootstrap(tenants []pinguinTenant, result *DiagnosticResult) {
+	var validationErr *tenant.BootstrapValidationError
+	if errors.As(tenant.ValidateBootstrapConfig(tenant.BootstrapConfig{Tenants: tenants}), &validationErr) {
+		result.Valid = false
+		result.Errors = append(result.Errors, validationErr.Problems...)
+	}
+}
+
 func validateCrossConfigs(configsByPath map[string]*pinguinConfig) crossValidation {
 	validation := crossValidation{
 		Performed: true,
@@ -2,11 +2,15 @@ package doctor
 
 import (
 	"context"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	pinguindb "github.com/tyemirov/pinguin/internal/db"
+	"github.com/tyemirov/pinguin/internal/model"
 	"gopkg.in/yaml.v3"
 )
 
@@ -130,6 +134,8 @@ func TestRunValidatesTenantConfigPath(t *testing.T) {
 tenants:
   - id: mapped
     displayName: Mapped Tenant
+    emailProfile:
+      fromAddress: noreply@example.com
     domains:
       - mapped.example.com
 `)
@@ -157,6 +163,8 @@ func TestRunRejectsInvalidTenantConfigPath(t *testing.T) {
 tenants:
   - id: mapped
     displayName: Mapped Tenant
+    emailProfile:
+      fromAddress: noreply@example.com
     domains:
       - mapped.example.com
     status: active
@@ -178,6 +186,42 @@ tenants:
 	}
 }
 
+func TestRunReportsTenantBootstrapProblemsWithLines(t *testing.T) {
+	tempDir := t.TempDir()
+	tenantConfigPath := filepath.Join(tempDir, "tenants.yml")
+	writeTestConfig(t, tenantConfigPath, `tenants:
+  - id: first
+    displayName: First
+    domains: [shared.example.com]
+    emailProfile:
+      fromAddress: noreply@example.com
+    callerAllowlist: [10.0.0.0/8, 10.0.0/8]
+  - id: second
+    displayName: Second
+    domains: [shared.example.com]
+    emailProfile:
+      fromAddress: broken
+`)
+	configPath := filepath.Join(tempDir, "config.yml")
+	writeTestConfig(t, configPath, doctorConfigWithTenantConfigPath(tenantConfigPath))
+
+	report, err := Run(context.Background(), Options{
+		ConfigPaths: []string{configPath},
+	})
+	if err != nil {
+		t.Fatalf("expected no run error, got %v", err)
+	}
+	for _, expected := range []string{
+		`tenant first (tenants[0], line 2): callerAllowlist entry "10.0.0/8" is not a valid CIDR`,
+		"tenant second (tenants[1], line 8): tenant.bootstrap.address.invalid",
+		"tenant second (tenants[1], line 8): tenant.bootstrap.domain.duplicate: duplicate domain shared.example.com already claimed by tenant first (tenants[0], line 2)",
+	} {
+		if !containsDiagnosticError(report.Diagnostics[0].Errors, expected) {
+			t.Fatalf("expected diagnostic %q, got %v", expected, report.Diagnostics[0].Errors)
+		}
+	}
+}
+
 func TestRunRejectsUnreadableAndEmptyTenantConfigPath(t *testing.T) {
 	for _, testCase := range []struct {
 		name             string
@@ -418,6 +462,39 @@ func TestRunValidatesSMTPForwardingConfig(t *testing.T) {
 	}
 }
 
+func TestRunValidatesQueueIntakeConfig(t *testing.T) {
+	tempDir := t.TempDir()
+	validConfigPath := filepath.Join(tempDir, "valid-queue-intake.yml")
+	writeTestConfig(t, validConfigPath, strings.Replace(validSMTPForwardingConfigYAML, "smtpForwarding:\n  enabled: true", queueIntakeConfigYAML+"\nsmtpForwarding:\n  enabled: true", 1))
+	validReport, validErr := Run(context.Background(), Options{ConfigPaths: []string{validConfigPath}})
+	if validErr != nil {
+		t.Fatalf("expected no valid run error, got %v", validErr)
+	}
+	if validReport.Summary.ValidConfigs != 1 {
+		t.Fatalf("expected valid queue intake config, got %+v", validReport.Diagnostics)
+	}
+
+	invalidConfigPath := filepath.Join(tempDir, "invalid-queue-intake.yml")
+	invalidIntake := "queueIntake:\n  enabled: true\n  subject: pinguin.send\n  deadLetterSubject: pinguin.send\n  maxDeliver: -1\n"
+	writeTestConfig(t, invalidConfigPath, strings.Replace(validSMTPForwardingConfigYAML, "smtpForwarding:\n  enabled: true", invalidIntake+"\nsmtpForwarding:\n  enabled: true", 1))
+	invalidReport, invalidErr := Run(context.Background(), Options{ConfigPaths: []string{invalidConfigPath}})
+	if invalidErr != nil {
+		t.Fatalf("expected no invalid run error, got %v", invalidErr)
+	}
+	if invalidReport.Summary.ValidConfigs != 0 {
+		t.Fatalf("expected invalid queue intake config")
+	}
+	for _, expected := range []string{
+		"queueIntake.url is required",
+		"queueIntake.maxDeliver must not be negative",
+		"queueIntake.deadLetterSubject must differ",
+	} {
+		if !containsDiagnosticError(invalidReport.Diagnostics[0].Errors, expected) {
+			t.Fatalf("expected %s diagnostic, got %v", expected, invalidReport.Diagnostics[0].Errors)
+		}
+	}
+}
+
 func TestRunReturnsErrorWithNoConfigs(t *testing.T) {
 	_, err := Run(context.Background(), Options{
 		ConfigPaths: []string{},
@@ -563,6 +640,8 @@ configPath: " tenants.yml "
 tenants:
   - id: mapped
     displayName: Mapped Tenant
+    emailProfile:
+      fromAddress: noreply@example.com
     domains:
       - mapped.example.com
 `), &node); err != nil {
@@ -661,7 +740,6 @@ func TestDoctorValidationHelpersCoverErrorBranches(t *testing.T) {
 	validateTenantConfig(pinguinTenant{}, true, &tenantResult)
 	for _, expected := range []string{
 		"tenant[(unknown)]: displayName",
-		"tenant[(unknown)]: at least one domain",
 		"tenant[(unknown)]: at least one admin",
 	} {
 		if !containsDiagnosticError(tenantResult.Errors, expected) {
@@ -670,6 +748,94 @@ func TestDoctorValidationHelpersCoverErrorBranches(t *testing.T) {
 	}
 }
 
+func TestValidateServerConfigRejectsInvalidInFlightSendGuard(t *testing.T) {
+	serverResult := DiagnosticResult{Valid: true}
+	validateServerConfig(pinguinServer{
+		DatabasePath:        "app.db",
+		GRPCAuthToken:       "token",
+		LogLevel:            "INFO",
+		MaxRetries:          3,
+		RetryIntervalSec:    30,
+		MaxInFlightSends:    -1,
+		InFlightSendPolicy:  "drop",
+		MaxRetriesPerTenant: -1,
+		IntegritySweepSec:   -1,
+		DrainTimeoutSec:     -1,
+		QueryTimeoutSec:     -1,
+		AggregateTimeoutSec: -1,
+		SMTPPoolSize:        -1,
+		SMTPPoolIdleSec:     -1,
+		RetryQueue:          "redis",
+		SecretCipher:        "pkcs11",
+		GRPCKeepalive:       pinguinGRPCKeepalive{TimeoutSec: -1},
+		RetentionDays:       -1,
+		MaxPendingAgeSec:    -1,
+		SMSCancelGraceSec:   -1,
+		BatchChunkSize:      -1,
+		MasterEncryptionKey: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		ConnectionTimeout:   5,
+		OperationTimeout:    10,
+	}, false, &serverResult)
+	if serverResult.Valid {
+		t.Fatalf("expected invalid in-flight send guard to fail validation")
+	}
+	for _, expected := range []string{
+		"server.maxInFlightSends",
+		"server.inFlightSendPolicy",
+		"server.maxConcurrentRetriesPerTenant",
+		"server.integritySweepIntervalSec",
+		"server.drainTimeoutSec",
+		"server.queryTimeoutSec",
+		"server.aggregateQueryTimeoutSec",
+		"server.smtpPoolSize",
+		"server.smtpPoolIdleTimeoutSec",
+		"server.retryQueue",
+		"server.secretCipher",
+		"server.grpcKeepalive",
+		"server.retentionDays",
+		"server.maxPendingAgeSec",
+		"server.smsCancelGraceSec",
+		"server.notificationBatchChunkSize",
+	} {
+		if !containsDiagnosticError(serverResult.Errors, expected) {
+			t.Fatalf("expected server validation error %q in %v", expected, serverResult.Errors)
+		}
+	}
+}
+
+func TestValidateDispatchPacingRejectsInvalidBounds(t *testing.T) {
+	result := DiagnosticResult{Valid: true}
+	validateDispatchPacing(pinguinDispatchPacing{Enabled: true, FailureThreshold: 1, MinDelayMs: 500, MaxDelayMs: 100, RecoveryRate: -1}, &result)
+	if result.Valid {
+		t.Fatalf("expected invalid pacing bounds to fail validation")
+	}
+	for _, expected := range []string{"failureThreshold", "maxDelayMs", "recoveryRate"} {
+		if !containsDiagnosticError(result.Errors, "server.dispatchPacing."+expected) {
+			t.Fatalf("expected pacing error %q in %v", expected, result.Errors)
+		}
+	}
+	defaults := DiagnosticResult{Valid: true}
+	validateDispatchPacing(pinguinDispatchPacing{Enabled: true}, &defaults)
+	if !defaults.Valid {
+		t.Fatalf("expected enabled pacing with default bounds to pass, got %v", defaults.Errors)
+	}
+}
+
+func TestWarnShortTenantRetention(t *testing.T) {
+	tenants := []pinguinTenant{{ID: "tenant-short", RetentionDays: 30}, {ID: "tenant-long", RetentionDays: 400}, {ID: "tenant-default"}}
+	result := DiagnosticResult{Valid: true}
+	warnShortTenantRetention(pinguinServer{RetentionDays: 300}, tenants, &result)
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "tenant[tenant-short]: retentionDays 30") {
+		t.Fatalf("expected one short retention warning, got %v", result.Warnings)
+	}
+	silenced := DiagnosticResult{Valid: true}
+	disabled := false
+	warnShortTenantRetention(pinguinServer{RetentionDays: 300, WarnShortRetention: &disabled}, tenants, &silenced)
+	if len(silenced.Warnings) != 0 {
+		t.Fatalf("expected the warning to be silenced, got %v", silenced.Warnings)
+	}
+}
+
 func TestFormatSummaryIncludesCrossValidationErrors(t *testing.T) {
 	report := &Report{
 		Timestamp:   timeNowForDoctorTest,
@@ -786,6 +952,64 @@ func TestFormatSummaryProducesReadableOutput(t *testing.T) {
 	}
 }
 
+func TestRunChecksDatabaseIntegrityAndRepairsOrphans(t *testing.T) {
+	tempDir := t.TempDir()
+	databasePath := filepath.Join(tempDir, "pinguin.db")
+	database, err := pinguindb.InitDB(databasePath, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("init database: %v", err)
+	}
+	orphan := model.NotificationAttachment{TenantID: "tenant-one", NotificationID: "deleted-parent", Filename: "orphan.txt", SizeBytes: 6, Data: []byte("orphan")}
+	if err := database.Create(&orphan).Error; err != nil {
+		t.Fatalf("seed orphan: %v", err)
+	}
+	configPath := filepath.Join(tempDir, "config.yml")
+	writeTestConfig(t, configPath, strings.Replace(validConfigYAML, "/data/pinguin.db", databasePath, 1))
+
+	report, err := Run(context.Background(), Options{ConfigPaths: []string{configPath}, CheckDatabase: true})
+	if err != nil {
+		t.Fatalf("run doctor: %v", err)
+	}
+	diagnostic := report.Diagnostics[0]
+	if !diagnostic.Valid || diagnostic.Integrity == nil || diagnostic.Integrity.OrphanedAttachments != 1 || diagnostic.Integrity.RepairedOrphans != 0 {
+		t.Fatalf("expected one reported orphan, got %+v", diagnostic)
+	}
+	if !containsString(diagnostic.Warnings, "database: 1 orphaned attachment rows (rerun with --repair-orphans to delete them)") {
+		t.Fatalf("expected an orphan warning, got %v", diagnostic.Warnings)
+	}
+
+	report, err = Run(context.Background(), Options{ConfigPaths: []string{configPath}, CheckDatabase: true, RepairOrphans: true})
+	if err != nil {
+		t.Fatalf("run doctor repair: %v", err)
+	}
+	repaired := report.Diagnostics[0]
+	if repaired.Integrity.RepairedOrphans != 1 {
+		t.Fatalf("expected the orphan repaired, got %+v", repaired.Integrity)
+	}
+	for _, warning := range repaired.Warnings {
+		if strings.HasPrefix(warning, "database:") {
+			t.Fatalf("expected no database warnings after repair, got %v", repaired.Warnings)
+		}
+	}
+	var remaining int64
+	if err := database.Model(&model.NotificationAttachment{}).Count(&remaining).Error; err != nil || remaining != 0 {
+		t.Fatalf("expected no attachment rows after repair, got %d (%v)", remaining, err)
+	}
+
+	missingPath := filepath.Join(tempDir, "missing.yml")
+	writeTestConfig(t, missingPath, strings.Replace(validConfigYAML, "/data/pinguin.db", filepath.Join(tempDir, "absent.db"), 1))
+	report, err = Run(context.Background(), Options{ConfigPaths: []string{missingPath}, CheckDatabase: true})
+	if err != nil {
+		t.Fatalf("run doctor missing database: %v", err)
+	}
+	if missing := report.Diagnostics[0]; missing.Valid || len(missing.Errors) != 1 || !strings.HasPrefix(missing.Errors[0], "database: open sqlite failed") {
+		t.Fatalf("expected a missing database to fail the config, got %+v", missing)
+	}
+	if _, statErr := os.Stat(filepath.Join(tempDir, "absent.db")); !os.IsNotExist(statErr) {
+		t.Fatalf("expected the doctor not to create a database, got %v", statErr)
+	}
+}
+
 func writeTestConfig(t *testing.T, path string, content string) {
 	t.Helper()
 	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
@@ -811,6 +1035,8 @@ web:
 tenants:
   - id: demo
     displayName: Demo Tenant
+    emailProfile:
+      fromAddress: noreply@example.com
     domains:
       - demo.example.com` + tenantSnippet + `
 `
@@ -862,6 +1088,8 @@ web:
 tenants:
   - id: demo
     displayName: Demo Tenant
+    emailProfile:
+      fromAddress: noreply@example.com
     domains:
       - demo.example.com
     admins:
@@ -924,6 +1152,8 @@ web:
 tenants:
   - id: demo
     displayName: Demo Tenant
+    emailProfile:
+      fromAddress: noreply@example.com
     domains:
       - demo.example.com
     admins:
@@ -964,6 +1194,8 @@ tenants:
   items:
     - id: mapped
       displayName: Mapped Tenant
+      emailProfile:
+        fromAddress: noreply@example.com
       domains:
         - mapped.example.com
 `
@@ -991,6 +1223,8 @@ web:
 tenants:
   - id: other
     displayName: Other Tenant
+    emailProfile:
+      fromAddress: noreply@example.com
     domains:
       - other.example.com
     admins:
@@ -1020,6 +1254,8 @@ web:
 tenants:
   - id: conflicting
     displayName: Conflicting Tenant
+    emailProfile:
+      fromAddress: noreply@example.com
     domains:
       - demo.example.com
     admins:
@@ -1062,6 +1298,8 @@ smtpSubmission:
 tenants:
   - id: demo
     displayName: Demo Tenant
+    emailProfile:
+      fromAddress: noreply@example.com
     domains:
       - demo.example.com
 `
@@ -1094,6 +1332,8 @@ smtpSubmission:
 tenants:
   - id: demo
     displayName: Demo Tenant
+    emailProfile:
+      fromAddress: noreply@example.com
     domains:
       - demo.example.com
 `
@@ -1127,6 +1367,8 @@ smtpForwarding:
 tenants:
   - id: demo
     displayName: Demo Tenant
+    emailProfile:
+      fromAddress: noreply@example.com
     domains:
       - demo.example.com
 `
@@ -1162,6 +1404,8 @@ smtpSubmission:
 tenants:
   - id: demo
     displayName: Demo Tenant
+    emailProfile:
+      fromAddress: noreply@example.com
     domains:
       - demo.example.com
 `
@@ -1195,10 +1439,23 @@ smtpForwarding:
 tenants:
   - id: demo
     displayName: Demo Tenant
+    emailProfile:
+      fromAddress: noreply@example.com
     domains:
       - demo.example.com
 `
 
+const queueIntakeConfigYAML = `queueIntake:
+  enabled: true
+  url: nats://nats.internal:4222
+  stream: PINGUIN
+  subject: pinguin.send
+  durable: pinguin-intake
+  deadLetterSubject: pinguin.send.dead
+  tenants:
+    - demo
+`
+
 const invalidSMTPForwardingConfigYAML = `
 server:
   databasePath: /data/pinguin.db
@@ -1228,6 +1485,8 @@ smtpForwarding:
 tenants:
   - id: demo
     displayName: Demo Tenant
+    emailProfile:
+      fromAddress: noreply@example.com
     domains:
       - demo.example.com
 `
@@ -1255,8 +1514,113 @@ web:
 tenants:
   - id: test
     displayName: Test Tenant
+    emailProfile:
+      fromAddress: noreply@example.com
     domains:
       - ${TEST_TENANT_DOMAIN}
     admins:
       - ${TEST_ADMIN_EMAIL}
 `
+
+func TestValidateWebConfigRejectsNegativeLimits(t *testing.T) {
+	webResult := DiagnosticResult{Valid: true}
+	validateWebConfig(pinguinWeb{
+		ListenAddr:                ":8080",
+		ReadTimeoutSec:            -1,
+		MaxAttachmentRequestBytes: -1,
+		JSONFieldNaming:           "PascalCase",
+		OpenTrackingBaseURL:       "ftp://mail.example.com",
+		OpenTrackingTokenTTLHours: -1,
+	}, &webResult)
+	if webResult.Valid {
+		t.Fatalf("expected negative web limits to fail validation")
+	}
+	for _, expected := range []string{
+		"web.readTimeoutSec",
+		"web.maxAttachmentRequestBytes",
+		"web.jsonFieldNaming",
+		"web.openTrackingBaseURL",
+		"web.openTrackingTokenTTLHours",
+	} {
+		if !containsDiagnosticError(webResult.Errors, expected) {
+			t.Fatalf("expected web validation error %q in %v", expected, webResult.Errors)
+		}
+	}
+}
+
+func TestValidateWebConfigWarnsThatCamelCaseBreaksTheBundledDashboard(t *testing.T) {
+	for _, testCase := range []struct {
+		naming          string
+		expectedWarning bool
+	}{
+		{naming: ""},
+		{naming: "snake_case"},
+		{naming: "camelCase", expectedWarning: true},
+	} {
+		result := DiagnosticResult{Valid: true}
+		validateWebConfig(pinguinWeb{ListenAddr: ":8080", JSONFieldNaming: testCase.naming}, &result)
+		if !result.Valid {
+			t.Fatalf("expected %q to be valid, got %v", testCase.naming, result.Errors)
+		}
+		if hasWarning := containsDiagnosticError(result.Warnings, "bundled dashboard"); hasWarning != testCase.expectedWarning {
+			t.Fatalf("expected warning %v for %q, got %v", testCase.expectedWarning, testCase.naming, result.Warnings)
+		}
+	}
+}
+
+func TestValidateGRPCTokensWarnsWhenOnlyFullAccessConfigured(t *testing.T) {
+	testCases := []struct {
+		name            string
+		server          pinguinServer
+		expectedWarning bool
+		expectedError   string
+	}{
+		{name: "legacy token only", server: pinguinServer{GRPCAuthToken: "token"}, expectedWarning: true},
+		{name: "read token configured", server: pinguinServer{GRPCAuthToken: "token", GRPCTokens: []pinguinGRPCToken{{Token: "reader", Scope: "read"}}}},
+		{name: "invalid scope", server: pinguinServer{GRPCTokens: []pinguinGRPCToken{{Token: "reader", Scope: "owner"}}}, expectedWarning: true, expectedError: "server.grpcTokens[0].scope"},
+		{name: "admin token with tenants", server: pinguinServer{GRPCAuthToken: "token", GRPCTokens: []pinguinGRPCToken{{Token: "operator", Scope: "admin", Tenants: []string{"tenant-a"}}}}, expectedWarning: true, expectedError: "server.grpcTokens[0].tenants must be empty for admin tokens"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := DiagnosticResult{Valid: true}
+			validateGRPCTokens(testCase.server, &result)
+			if hasWarning := len(result.Warnings) > 0; hasWarning != testCase.expectedWarning {
+				t.Fatalf("expected warning %v, got %v", testCase.expectedWarning, result.Warnings)
+			}
+			if testCase.expectedError != "" && !containsDiagnosticError(result.Errors, testCase.expectedError) {
+				t.Fatalf("expected error %q in %v", testCase.expectedError, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateListenAddressesUseServerSyntax(t *testing.T) {
+	serverResult := DiagnosticResult{Valid: true}
+	validateServerConfig(pinguinServer{
+		DatabasePath:        "app.db",
+		GRPCAuthToken:       "token",
+		GRPCListenAddr:      "udp://:50051",
+		LogLevel:            "INFO",
+		MaxRetries:          3,
+		RetryIntervalSec:    30,
+		MasterEncryptionKey: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		ConnectionTimeout:   5,
+		OperationTimeout:    10,
+	}, false, &serverResult)
+	if serverResult.Valid || !containsDiagnosticError(serverResult.Errors, "server.grpcListenAddr") {
+		t.Fatalf("expected an unsupported scheme to fail validation, got %v", serverResult.Errors)
+	}
+
+	webResult := DiagnosticResult{Valid: true}
+	validateWebConfig(pinguinWeb{ListenAddr: "localhost"}, &webResult)
+	if webResult.Valid || !containsDiagnosticError(webResult.Errors, "web.listenAddr") {
+		t.Fatalf("expected a missing port to fail validation, got %v", webResult.Errors)
+	}
+	for _, listenAddr := range []string{":8080", "tcp4://0.0.0.0:8080", "unix:///run/pinguin/http.sock"} {
+		validResult := DiagnosticResult{Valid: true}
+		validateListenAddr(listenAddr, "web.listenAddr", &validResult)
+		if !validResult.Valid {
+			t.Fatalf("expected %s to validate, got %v", listenAddr, validResult.Errors)
+		}
+	}
+}
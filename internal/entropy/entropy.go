@@ -0,0 +1,72 @@
+// Package entropy is the seam through which Pinguin draws random bytes and unique
+// identifiers, so deployments that must source them from an approved module (FIPS,
+// PKCS#11/HSM) can supply their own RandomSource.
+package entropy
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RandomSource supplies random bytes and unique identifier suffixes. Implementations
+// must be safe for concurrent use.
+type RandomSource interface {
+	// Read fills p with random bytes. Secret nonces and webhook event ids are drawn from it.
+	io.Reader
+	// UniqueSuffix returns a decimal string that differs from every earlier call.
+	// Notification ids and MIME boundaries end with it.
+	UniqueSuffix() (string, error)
+}
+
+// System returns the default source: crypto/rand for bytes and the nanosecond clock
+// for suffixes, which is what Pinguin has always used.
+func System() RandomSource {
+	return systemSource{}
+}
+
+type systemSource struct{}
+
+func (systemSource) Read(p []byte) (int, error) {
+	return rand.Read(p)
+}
+
+func (systemSource) UniqueSuffix() (string, error) {
+	return strconv.FormatInt(time.Now().UnixNano(), 10), nil
+}
+
+// FromReader returns a source that draws everything from reader, suffixes included:
+// each suffix is 63 random bits in decimal. Wrap an approved module's reader with it.
+func FromReader(reader io.Reader) RandomSource {
+	return readerSource{reader: reader}
+}
+
+type readerSource struct {
+	reader io.Reader
+}
+
+func (source readerSource) Read(p []byte) (int, error) {
+	return source.reader.Read(p)
+}
+
+func (source readerSource) UniqueSuffix() (string, error) {
+	var buffer [8]byte
+	if _, err := io.ReadFull(source.reader, buffer[:]); err != nil {
+		return "", fmt.Errorf("entropy: unique suffix: %w", err)
+	}
+	return strconv.FormatUint(binary.BigEndian.Uint64(buffer[:])>>1, 10), nil
+}
+
+// NewUUID returns a random (version 4) UUID drawn from source.
+func NewUUID(source RandomSource) (string, error) {
+	id, err := uuid.NewRandomFromReader(source)
+	if err != nil {
+		return "", fmt.Errorf("entropy: uuid: %w", err)
+	}
+	return id.String(), nil
+}
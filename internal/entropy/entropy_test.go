@@ -0,0 +1,67 @@
+package entropy
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// checkRandomSource is the contract every RandomSource must meet.
+func checkRandomSource(t *testing.T, source RandomSource) {
+	t.Helper()
+	first := make([]byte, 32)
+	second := make([]byte, 32)
+	if _, err := io.ReadFull(source, first); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if _, err := io.ReadFull(source, second); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if bytes.Equal(first, second) {
+		t.Fatalf("expected successive reads to differ")
+	}
+	seen := make(map[string]bool)
+	for index := 0; index < 100; index++ {
+		suffix, err := source.UniqueSuffix()
+		if err != nil {
+			t.Fatalf("unique suffix: %v", err)
+		}
+		if _, parseErr := strconv.ParseUint(suffix, 10, 64); parseErr != nil {
+			t.Fatalf("expected a decimal suffix, got %q", suffix)
+		}
+		if seen[suffix] {
+			t.Fatalf("expected unique suffixes, got %q twice", suffix)
+		}
+		seen[suffix] = true
+	}
+	id, err := NewUUID(source)
+	if err != nil {
+		t.Fatalf("uuid: %v", err)
+	}
+	if parsed, parseErr := uuid.Parse(id); parseErr != nil || parsed.Version() != 4 {
+		t.Fatalf("expected a version 4 uuid, got %q", id)
+	}
+}
+
+func TestSystemSourceConformance(t *testing.T) {
+	checkRandomSource(t, System())
+}
+
+func TestReaderSourceConformance(t *testing.T) {
+	checkRandomSource(t, FromReader(rand.Reader))
+}
+
+func TestReaderSourceReportsReaderFailure(t *testing.T) {
+	source := FromReader(bytes.NewReader(nil))
+	if _, err := source.UniqueSuffix(); !errors.Is(err, io.EOF) {
+		t.Fatalf("expected the reader failure, got %v", err)
+	}
+	if _, err := NewUUID(source); err == nil {
+		t.Fatalf("expected a uuid error from an exhausted reader")
+	}
+}
@@ -0,0 +1,177 @@
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// camelCaseJSON serves /api JSON bodies with camelCase field names. Handlers and the
+// model keep their snake_case tags: request bodies are renamed to snake_case before a
+// handler binds them, and JSON responses are buffered and renamed before they are
+// written. The names listed in the fields parameter are renamed the same way; other
+// query parameters and multipart form fields keep their snake_case names.
+func camelCaseJSON() gin.HandlerFunc {
+	return func(contextGin *gin.Context) {
+		query := contextGin.Request.URL.Query()
+		if fields := query.Get(notificationFieldsParam); fields != "" {
+			names := strings.Split(fields, ",")
+			for index, name := range names {
+				names[index] = snakeCaseKey(strings.TrimSpace(name))
+			}
+			query.Set(notificationFieldsParam, strings.Join(names, ","))
+			contextGin.Request.URL.RawQuery = query.Encode()
+		}
+		if isJSONContentType(contextGin.Request.Header.Get("Content-Type")) && contextGin.Request.Body != nil && contextGin.Request.Body != http.NoBody {
+			body, readErr := io.ReadAll(contextGin.Request.Body)
+			if readErr != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(readErr, &maxBytesErr) {
+					contextGin.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": requestBodyTooLargeError})
+					return
+				}
+				contextGin.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": invalidPayloadError})
+				return
+			}
+			// A body that is not valid JSON is passed on unchanged for the handler to reject.
+			if renamed, renameErr := renameJSONKeys(body, snakeCaseKey); renameErr == nil {
+				body = renamed
+			}
+			contextGin.Request.Body = io.NopCloser(bytes.NewReader(body))
+			contextGin.Request.ContentLength = int64(len(body))
+		}
+
+		writer := &renamingResponseWriter{ResponseWriter: contextGin.Writer}
+		contextGin.Writer = writer
+		contextGin.Next()
+		writer.flush()
+	}
+}
+
+// renamingResponseWriter holds back the response body so JSON field names can be
+// renamed once the handler has finished writing.
+type renamingResponseWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (writer *renamingResponseWriter) Write(data []byte) (int, error) {
+	return writer.body.Write(data)
+}
+
+func (writer *renamingResponseWriter) WriteString(data string) (int, error) {
+	return writer.body.WriteString(data)
+}
+
+func (writer *renamingResponseWriter) flush() {
+	if writer.body.Len() == 0 {
+		return
+	}
+	body := writer.body.Bytes()
+	if isJSONContentType(writer.Header().Get("Content-Type")) {
+		if renamed, err := renameJSONKeys(body, camelCaseKey); err == nil {
+			body = renamed
+		}
+	}
+	_, _ = writer.ResponseWriter.Write(body)
+}
+
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/json"
+}
+
+// jsonContainer tracks an open object or array while renameJSONKeys copies tokens.
+type jsonContainer struct {
+	object        bool
+	items         int
+	awaitingValue bool
+}
+
+// renameJSONKeys rewrites every object key in data with rename and keeps values and
+// key order as they are.
+func renameJSONKeys(data []byte, rename func(string) string) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var output bytes.Buffer
+	var containers []jsonContainer
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if delimiter, isDelimiter := token.(json.Delim); isDelimiter && (delimiter == '}' || delimiter == ']') {
+			output.WriteByte(byte(delimiter))
+			containers = containers[:len(containers)-1]
+			continue
+		}
+		if len(containers) > 0 {
+			parent := &containers[len(containers)-1]
+			switch {
+			case parent.object && !parent.awaitingValue:
+				if parent.items > 0 {
+					output.WriteByte(',')
+				}
+				parent.items++
+				parent.awaitingValue = true
+				token = rename(token.(string))
+			case parent.object:
+				output.WriteByte(':')
+				parent.awaitingValue = false
+			default:
+				if parent.items > 0 {
+					output.WriteByte(',')
+				}
+				parent.items++
+			}
+		}
+		if delimiter, isDelimiter := token.(json.Delim); isDelimiter {
+			output.WriteByte(byte(delimiter))
+			containers = append(containers, jsonContainer{object: delimiter == '{'})
+			continue
+		}
+		encoded, err := json.Marshal(token)
+		if err != nil {
+			return nil, err
+		}
+		output.Write(encoded)
+	}
+	return output.Bytes(), nil
+}
+
+// camelCaseKey turns notification_id into notificationId.
+func camelCaseKey(key string) string {
+	parts := strings.Split(key, "_")
+	for index := 1; index < len(parts); index++ {
+		if parts[index] != "" {
+			parts[index] = strings.ToUpper(parts[index][:1]) + parts[index][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// snakeCaseKey turns notificationId into notification_id.
+func snakeCaseKey(key string) string {
+	var builder strings.Builder
+	for index, character := range key {
+		if unicode.IsUpper(character) {
+			if index > 0 {
+				builder.WriteByte('_')
+			}
+			builder.WriteRune(unicode.ToLower(character))
+			continue
+		}
+		builder.WriteRune(character)
+	}
+	return builder.String()
+}
@@ -0,0 +1,332 @@
+package httpapi
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/service"
+)
+
+const (
+	sendNotificationRoutePath    = "/api/notifications"
+	jsonContentType              = "application/json"
+	multipartFormContentType     = "multipart/form-data"
+	genericAttachmentContentType = "application/octet-stream"
+	maxMultipartFieldBytes       = 1 << 20
+	sendFieldNotificationType    = "notification_type"
+	sendFieldRecipient           = "recipient"
+	sendFieldSubject             = "subject"
+	sendFieldMessage             = "message"
+	sendFieldScheduledTime       = "scheduled_time"
+	sendFieldSMSOverflowPolicy   = "sms_overflow_policy"
+	sendFieldFailOnImmediate     = "fail_on_immediate_error"
+	sendFieldContentEncrypted    = "content_encrypted"
+	sendFieldReplyTo             = "reply_to"
+	sendFieldFromDisplayName     = "from_display_name"
+)
+
+var (
+	errMultipartInvalid         = errors.New("invalid multipart payload")
+	errMultipartFieldTooLarge   = errors.New("multipart field too large")
+	errMultipartFieldUnknown    = errors.New("unknown multipart field")
+	errMultipartAttachmentLimit = errors.New("attachment limit exceeded")
+	errAttachmentEncoding       = errors.New("invalid attachment encoding")
+)
+
+// sendNotificationPayload carries the fields shared by JSON and multipart send requests.
+type sendNotificationPayload struct {
+	NotificationType string `json:"notification_type"`
+	Recipient        string `json:"recipient"`
+	Subject          string `json:"subject"`
+	Message          string `json:"message"`
+	ScheduledTime    string `json:"scheduled_time"`
+	// SMSOverflowPolicy overrides the tenant's reject/truncate default for oversized SMS.
+	SMSOverflowPolicy string `json:"sms_overflow_policy"`
+	// FailOnImmediateError overrides server.failOnImmediateError when set.
+	FailOnImmediateError *bool `json:"fail_on_immediate_error"`
+	// ContentEncrypted marks Message as caller-encrypted content to send as is.
+	ContentEncrypted bool `json:"content_encrypted"`
+	// ReplyTo and FromDisplayName override the email's Reply-To and From display name
+	// when the tenant's policy allows it.
+	ReplyTo         string `json:"reply_to"`
+	FromDisplayName string `json:"from_display_name"`
+	// Attachments carries JSON bodies' email attachments; multipart bodies send file parts instead.
+	Attachments []sendAttachmentPayload `json:"attachments"`
+}
+
+// sendAttachmentPayload is one JSON attachment with its bytes in standard base64.
+type sendAttachmentPayload struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Data        string `json:"data"`
+}
+
+func (handler *notificationHandler) sendNotification(contextGin *gin.Context) {
+	requestContext, resolveErr := handler.resolveNotificationContext(contextGin)
+	if resolveErr != nil {
+		handler.writeTenantResolutionError(contextGin, resolveErr)
+		return
+	}
+	var payload sendNotificationPayload
+	var attachments []model.EmailAttachment
+	switch contextGin.ContentType() {
+	case jsonContentType:
+		if !bindJSONPayload(contextGin, &payload) {
+			return
+		}
+		var decodeErr error
+		attachments, decodeErr = decodeJSONAttachments(payload.Attachments)
+		if decodeErr != nil {
+			writeSendRequestError(contextGin, decodeErr)
+			return
+		}
+	case multipartFormContentType:
+		var parseErr error
+		payload, attachments, parseErr = readMultipartSendPayload(contextGin.Request)
+		if parseErr != nil {
+			writeMultipartSendError(contextGin, parseErr)
+			return
+		}
+	default:
+		contextGin.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "content type must be application/json or multipart/form-data"})
+		return
+	}
+
+	var scheduledFor *time.Time
+	if strings.TrimSpace(payload.ScheduledTime) != "" {
+		parsedTime, err := time.Parse(time.RFC3339, strings.TrimSpace(payload.ScheduledTime))
+		if err != nil {
+			contextGin.JSON(http.StatusBadRequest, gin.H{"error": "scheduled_time must be RFC3339"})
+			return
+		}
+		scheduledFor = &parsedTime
+	}
+	notificationType := model.NotificationType(strings.ToLower(strings.TrimSpace(payload.NotificationType)))
+	request, requestErr := model.NewNotificationRequest(notificationType, payload.Recipient, payload.Subject, payload.Message, scheduledFor, attachments)
+	if requestErr != nil {
+		writeSendRequestError(contextGin, requestErr)
+		return
+	}
+	request, requestErr = request.WithSMSOverflowPolicy(payload.SMSOverflowPolicy)
+	if requestErr != nil {
+		writeSendRequestError(contextGin, requestErr)
+		return
+	}
+	if payload.FailOnImmediateError != nil {
+		request = request.WithFailOnImmediateError(*payload.FailOnImmediateError)
+	}
+	if payload.ContentEncrypted {
+		request, requestErr = request.WithEncryptedContent()
+		if requestErr != nil {
+			writeSendRequestError(contextGin, requestErr)
+			return
+		}
+	}
+	request, requestErr = request.WithSenderOverrides(payload.ReplyTo, payload.FromDisplayName)
+	if requestErr != nil {
+		writeSendRequestError(contextGin, requestErr)
+		return
+	}
+	response, err := handler.service.SendNotification(requestContext, request)
+	if errors.Is(err, service.ErrImmediateDispatchFailed) {
+		// The notification is stored and queued for retry; return it so the caller
+		// does not resend.
+		contextGin.JSON(http.StatusBadGateway, gin.H{"error": err.Error(), "notification": recipientMaskerFor(contextGin).notification(response)})
+		return
+	}
+	if err != nil {
+		handler.writeError(contextGin, err)
+		return
+	}
+	contextGin.JSON(http.StatusOK, recipientMaskerFor(contextGin).notification(response))
+}
+
+// readMultipartSendPayload streams the form so per-file, total and count limits
+// are enforced while reading rather than after buffering the whole body.
+func readMultipartSendPayload(request *http.Request) (sendNotificationPayload, []model.EmailAttachment, error) {
+	var payload sendNotificationPayload
+	var failOnImmediateError, contentEncrypted string
+	reader, err := request.MultipartReader()
+	if err != nil {
+		return payload, nil, fmt.Errorf("%w: %v", errMultipartInvalid, err)
+	}
+	fields := map[string]*string{
+		sendFieldNotificationType:  &payload.NotificationType,
+		sendFieldRecipient:         &payload.Recipient,
+		sendFieldSubject:           &payload.Subject,
+		sendFieldMessage:           &payload.Message,
+		sendFieldScheduledTime:     &payload.ScheduledTime,
+		sendFieldSMSOverflowPolicy: &payload.SMSOverflowPolicy,
+		sendFieldFailOnImmediate:   &failOnImmediateError,
+		sendFieldContentEncrypted:  &contentEncrypted,
+		sendFieldReplyTo:           &payload.ReplyTo,
+		sendFieldFromDisplayName:   &payload.FromDisplayName,
+	}
+	var attachments []model.EmailAttachment
+	totalAttachmentBytes := 0
+	for {
+		part, nextErr := reader.NextPart()
+		if errors.Is(nextErr, io.EOF) {
+			if strings.TrimSpace(failOnImmediateError) != "" {
+				fail, parseErr := strconv.ParseBool(strings.TrimSpace(failOnImmediateError))
+				if parseErr != nil {
+					return payload, nil, fmt.Errorf("%w: %s must be true or false", errMultipartInvalid, sendFieldFailOnImmediate)
+				}
+				payload.FailOnImmediateError = &fail
+			}
+			if strings.TrimSpace(contentEncrypted) != "" {
+				encrypted, parseErr := strconv.ParseBool(strings.TrimSpace(contentEncrypted))
+				if parseErr != nil {
+					return payload, nil, fmt.Errorf("%w: %s must be true or false", errMultipartInvalid, sendFieldContentEncrypted)
+				}
+				payload.ContentEncrypted = encrypted
+			}
+			return payload, attachments, nil
+		}
+		if nextErr != nil {
+			return payload, nil, wrapMultipartReadError(nextErr)
+		}
+		if part.FileName() == "" {
+			target, known := fields[part.FormName()]
+			if !known {
+				part.Close()
+				return payload, nil, fmt.Errorf("%w: %s", errMultipartFieldUnknown, part.FormName())
+			}
+			value, readErr := readLimitedPart(part, maxMultipartFieldBytes)
+			part.Close()
+			if errors.Is(readErr, errMultipartAttachmentLimit) {
+				return payload, nil, fmt.Errorf("%w: %s", errMultipartFieldTooLarge, part.FormName())
+			}
+			if readErr != nil {
+				return payload, nil, readErr
+			}
+			*target = string(value)
+			continue
+		}
+		if len(attachments) == model.MaxNotificationAttachmentCount {
+			part.Close()
+			return payload, nil, fmt.Errorf("%w: %w", errMultipartAttachmentLimit, model.ErrNotificationAttachmentsTooMany)
+		}
+		data, readErr := readLimitedPart(part, model.MaxNotificationAttachmentSizeBytes)
+		part.Close()
+		if errors.Is(readErr, errMultipartAttachmentLimit) {
+			return payload, nil, fmt.Errorf("%w: %w", errMultipartAttachmentLimit, model.ErrNotificationAttachmentTooLarge)
+		}
+		if readErr != nil {
+			return payload, nil, readErr
+		}
+		totalAttachmentBytes += len(data)
+		if totalAttachmentBytes > model.MaxNotificationAttachmentsTotalBytes {
+			return payload, nil, fmt.Errorf("%w: %w", errMultipartAttachmentLimit, model.ErrNotificationAttachmentsTooLarge)
+		}
+		attachments = append(attachments, model.EmailAttachment{
+			Filename:    part.FileName(),
+			ContentType: attachmentContentType(part.Header.Get("Content-Type"), data),
+			Data:        data,
+		})
+	}
+}
+
+// decodeJSONAttachments decodes base64 attachments under the same count and size limits
+// multipart uploads get, rejecting an oversized one before decoding it. Filenames and
+// empty payloads are left to model.NewNotificationRequest.
+func decodeJSONAttachments(payloads []sendAttachmentPayload) ([]model.EmailAttachment, error) {
+	if len(payloads) == 0 {
+		return nil, nil
+	}
+	if len(payloads) > model.MaxNotificationAttachmentCount {
+		return nil, model.ErrNotificationAttachmentsTooMany
+	}
+	attachments := make([]model.EmailAttachment, 0, len(payloads))
+	totalAttachmentBytes := 0
+	for index, payload := range payloads {
+		encoded := strings.TrimSpace(payload.Data)
+		// DecodedLen rounds up to whole groups, so allow for the padding it counts.
+		if base64.StdEncoding.DecodedLen(len(encoded)) > model.MaxNotificationAttachmentSizeBytes+2 {
+			return nil, model.ErrNotificationAttachmentTooLarge
+		}
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("%w: attachments[%d].data must be standard base64", errAttachmentEncoding, index)
+		}
+		if len(data) > model.MaxNotificationAttachmentSizeBytes {
+			return nil, model.ErrNotificationAttachmentTooLarge
+		}
+		totalAttachmentBytes += len(data)
+		if totalAttachmentBytes > model.MaxNotificationAttachmentsTotalBytes {
+			return nil, model.ErrNotificationAttachmentsTooLarge
+		}
+		attachments = append(attachments, model.EmailAttachment{
+			Filename:    payload.Filename,
+			ContentType: attachmentContentType(payload.ContentType, data),
+			Data:        data,
+		})
+	}
+	return attachments, nil
+}
+
+// readLimitedPart reads at most limit bytes, reporting errMultipartAttachmentLimit
+// as soon as one more byte is available.
+func readLimitedPart(part *multipart.Part, limit int) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(part, int64(limit)+1))
+	if err != nil {
+		return nil, wrapMultipartReadError(err)
+	}
+	if len(data) > limit {
+		return nil, errMultipartAttachmentLimit
+	}
+	return data, nil
+}
+
+func wrapMultipartReadError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return err
+	}
+	return fmt.Errorf("%w: %v", errMultipartInvalid, err)
+}
+
+// attachmentContentType trusts the part header unless it is missing or generic,
+// in which case the payload is sniffed.
+func attachmentContentType(headerValue string, data []byte) string {
+	mediaType, _, err := mime.ParseMediaType(headerValue)
+	if err == nil && mediaType != genericAttachmentContentType {
+		return headerValue
+	}
+	return http.DetectContentType(data)
+}
+
+func writeMultipartSendError(contextGin *gin.Context, err error) {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		contextGin.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": requestBodyTooLargeError})
+	case errors.Is(err, errMultipartAttachmentLimit), errors.Is(err, errMultipartFieldTooLarge):
+		writeSendRequestError(contextGin, err)
+	case errors.Is(err, errMultipartFieldUnknown):
+		contextGin.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		contextGin.JSON(http.StatusBadRequest, gin.H{"error": invalidPayloadError})
+	}
+}
+
+func writeSendRequestError(contextGin *gin.Context, err error) {
+	switch {
+	case errors.Is(err, model.ErrNotificationAttachmentTooLarge),
+		errors.Is(err, model.ErrNotificationAttachmentsTooLarge),
+		errors.Is(err, errMultipartFieldTooLarge):
+		contextGin.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	default:
+		contextGin.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
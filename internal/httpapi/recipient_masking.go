@@ -0,0 +1,100 @@
+package httpapi
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tyemirov/pinguin/internal/model"
+	sessionvalidator "github.com/tyemirov/tauth/pkg/sessionvalidator"
+)
+
+const (
+	// sessionViewerRole marks read-only sessions; they see masked recipients unless
+	// the session also carries the admin role.
+	sessionViewerRole = "viewer"
+	recipientMaskRune = '*'
+	// recipientVisibleSuffix is how many trailing characters of a phone number stay visible.
+	recipientVisibleSuffix = 4
+)
+
+// recipientMasker rewrites recipients in responses for the calling session. The
+// service always returns full recipients; masking happens only at the HTTP boundary.
+type recipientMasker struct {
+	enabled bool
+}
+
+func recipientMaskerFor(contextGin *gin.Context) recipientMasker {
+	return recipientMasker{enabled: sessionIsServiceAccount(contextGin) || sessionMasksRecipients(claimsFromContextGin(contextGin))}
+}
+
+// sessionMasksRecipients reports whether the session holds the viewer role without the admin role.
+func sessionMasksRecipients(claims *sessionvalidator.Claims) bool {
+	if sessionHasAdminRole(claims) {
+		return false
+	}
+	for _, role := range claims.GetUserRoles() {
+		if strings.EqualFold(strings.TrimSpace(role), sessionViewerRole) {
+			return true
+		}
+	}
+	return false
+}
+
+// notification masks every address the response carries: the recipient, the Reply-To
+// address and a session email recorded as the canceller. Actors such as "grpc" and
+// "system" are not personal data and stay readable.
+func (masker recipientMasker) notification(response model.NotificationResponse) model.NotificationResponse {
+	if !masker.enabled {
+		return response
+	}
+	response.Recipient = maskRecipient(response.Recipient)
+	if response.ReplyTo != "" {
+		response.ReplyTo = maskRecipient(response.ReplyTo)
+	}
+	if strings.Contains(response.CancelledBy, "@") {
+		response.CancelledBy = maskRecipient(response.CancelledBy)
+	}
+	return response
+}
+
+func (masker recipientMasker) notifications(responses []model.NotificationResponse) []model.NotificationResponse {
+	if !masker.enabled {
+		return responses
+	}
+	masked := make([]model.NotificationResponse, 0, len(responses))
+	for _, response := range responses {
+		masked = append(masked, masker.notification(response))
+	}
+	return masked
+}
+
+func (masker recipientMasker) group(response model.NotificationGroupResponse) model.NotificationGroupResponse {
+	response.Notifications = masker.notifications(response.Notifications)
+	return response
+}
+
+// maskRecipient keeps the first character of an email's local part and its domain,
+// or the last digits of a phone number, and replaces the rest with asterisks.
+func maskRecipient(recipient string) string {
+	if localPart, domain, found := strings.Cut(recipient, "@"); found {
+		localRunes := []rune(localPart)
+		if len(localRunes) == 0 {
+			return recipient
+		}
+		return string(localRunes[0]) + strings.Repeat(string(recipientMaskRune), len(localRunes)-1) + "@" + domain
+	}
+	runes := []rune(recipient)
+	visible := recipientVisibleSuffix
+	if len(runes) <= visible {
+		visible = 0
+	}
+	masked := make([]rune, len(runes))
+	for index, character := range runes {
+		if index >= len(runes)-visible {
+			masked[index] = character
+			continue
+		}
+		masked[index] = recipientMaskRune
+	}
+	return string(masked)
+}
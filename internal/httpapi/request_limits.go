@@ -0,0 +1,64 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultReadTimeout               = 15 * time.Second
+	defaultWriteTimeout              = 30 * time.Second
+	defaultIdleTimeout               = 60 * time.Second
+	defaultMaxRequestBytes           = 1 << 20
+	defaultMaxAttachmentRequestBytes = 32 << 20
+	defaultMaxMultipartMemory        = 8 << 20
+	requestBodyTooLargeError         = "request body too large"
+	invalidPayloadError              = "invalid payload"
+)
+
+// attachmentRoutePaths lists route patterns that accept inline attachments and
+// therefore receive the larger attachment body limit.
+var attachmentRoutePaths = map[string]struct{}{
+	sendNotificationRoutePath: {},
+}
+
+// requestBodyLimiter caps every request body, granting the attachment limit
+// only to routes registered in attachmentRoutes.
+func requestBodyLimiter(defaultLimit int64, attachmentLimit int64, attachmentRoutes map[string]struct{}) gin.HandlerFunc {
+	return func(contextGin *gin.Context) {
+		if contextGin.Request.Body != nil && contextGin.Request.Body != http.NoBody {
+			limit := defaultLimit
+			if _, acceptsAttachments := attachmentRoutes[contextGin.FullPath()]; acceptsAttachments {
+				limit = attachmentLimit
+			}
+			contextGin.Request.Body = http.MaxBytesReader(contextGin.Writer, contextGin.Request.Body, limit)
+		}
+		contextGin.Next()
+	}
+}
+
+// bindJSONPayload decodes the request body, answering 413 when the body limit
+// was exceeded and 400 for any other decoding failure.
+func bindJSONPayload(contextGin *gin.Context, payload any) bool {
+	err := contextGin.ShouldBindJSON(payload)
+	if err == nil {
+		return true
+	}
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		contextGin.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": requestBodyTooLargeError})
+		return false
+	}
+	contextGin.JSON(http.StatusBadRequest, gin.H{"error": invalidPayloadError})
+	return false
+}
+
+func pickByteLimit(candidate int64, fallback int64) int64 {
+	if candidate <= 0 {
+		return fallback
+	}
+	return candidate
+}
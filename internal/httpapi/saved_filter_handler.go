@@ -0,0 +1,177 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/savedfilter"
+)
+
+const notificationFilterIDParam = "filter_id"
+
+type savedFilterPayload struct {
+	Name        string   `json:"name"`
+	Statuses    []string `json:"status"`
+	SearchQuery string   `json:"q"`
+	Shared      bool     `json:"shared"`
+}
+
+func (handler *notificationHandler) listSavedFilters(contextGin *gin.Context) {
+	scope, ok := handler.requireSavedFilterScope(contextGin)
+	if !ok {
+		return
+	}
+	filters, err := handler.savedFilters.List(contextGin.Request.Context(), scope)
+	if err != nil {
+		handler.writeSavedFilterError(contextGin, err)
+		return
+	}
+	contextGin.JSON(http.StatusOK, gin.H{"filters": filters})
+}
+
+func (handler *notificationHandler) createSavedFilter(contextGin *gin.Context) {
+	scope, ok := handler.requireSavedFilterScope(contextGin)
+	if !ok {
+		return
+	}
+	definition, ok := bindSavedFilterDefinition(contextGin)
+	if !ok {
+		return
+	}
+	filter, err := handler.savedFilters.Create(contextGin.Request.Context(), scope, definition)
+	if err != nil {
+		handler.writeSavedFilterError(contextGin, err)
+		return
+	}
+	contextGin.JSON(http.StatusCreated, filter)
+}
+
+func (handler *notificationHandler) updateSavedFilter(contextGin *gin.Context) {
+	scope, ok := handler.requireSavedFilterScope(contextGin)
+	if !ok {
+		return
+	}
+	filterID := strings.TrimSpace(contextGin.Param("id"))
+	if filterID == "" {
+		contextGin.JSON(http.StatusBadRequest, gin.H{"error": "filter_id is required"})
+		return
+	}
+	definition, ok := bindSavedFilterDefinition(contextGin)
+	if !ok {
+		return
+	}
+	filter, err := handler.savedFilters.Update(contextGin.Request.Context(), scope, filterID, definition)
+	if err != nil {
+		handler.writeSavedFilterError(contextGin, err)
+		return
+	}
+	contextGin.JSON(http.StatusOK, filter)
+}
+
+func (handler *notificationHandler) deleteSavedFilter(contextGin *gin.Context) {
+	scope, ok := handler.requireSavedFilterScope(contextGin)
+	if !ok {
+		return
+	}
+	filterID := strings.TrimSpace(contextGin.Param("id"))
+	if filterID == "" {
+		contextGin.JSON(http.StatusBadRequest, gin.H{"error": "filter_id is required"})
+		return
+	}
+	if err := handler.savedFilters.Delete(contextGin.Request.Context(), scope, filterID); err != nil {
+		handler.writeSavedFilterError(contextGin, err)
+		return
+	}
+	contextGin.Status(http.StatusNoContent)
+}
+
+// applySavedFilter replaces the list filters with the saved filter named by filter_id.
+// The saved filter owns status and q, so combining them with filter_id is rejected.
+func (handler *notificationHandler) applySavedFilter(contextGin *gin.Context, filters model.NotificationListFilters) (model.NotificationListFilters, bool) {
+	filterID := strings.TrimSpace(contextGin.Query(notificationFilterIDParam))
+	if filterID == "" {
+		return filters, true
+	}
+	if handler.savedFilters == nil {
+		contextGin.JSON(http.StatusBadRequest, gin.H{"error": "saved filters are not enabled"})
+		return model.NotificationListFilters{}, false
+	}
+	if len(filters.Statuses) > 0 || !filters.SearchQuery.IsZero() {
+		contextGin.JSON(http.StatusBadRequest, gin.H{"error": "filter_id cannot be combined with status or q"})
+		return model.NotificationListFilters{}, false
+	}
+	scope, ok := handler.requireSavedFilterScope(contextGin)
+	if !ok {
+		return model.NotificationListFilters{}, false
+	}
+	saved, err := handler.savedFilters.ListFilters(contextGin.Request.Context(), scope, filterID)
+	if err != nil {
+		handler.writeSavedFilterError(contextGin, err)
+		return model.NotificationListFilters{}, false
+	}
+	return saved, true
+}
+
+// requireSavedFilterScope authorizes the tenant named by tenant_id and scopes filters to
+// the session's email. Only an admin-role session may share filters with the tenant.
+func (handler *notificationHandler) requireSavedFilterScope(contextGin *gin.Context) (savedfilter.AccessScope, bool) {
+	if _, resolveErr := handler.resolveNotificationContext(contextGin); resolveErr != nil {
+		handler.writeTenantResolutionError(contextGin, resolveErr)
+		return savedfilter.AccessScope{}, false
+	}
+	claims := claimsFromContextGin(contextGin)
+	ownerEmail := strings.ToLower(strings.TrimSpace(claims.GetUserEmail()))
+	if ownerEmail == "" {
+		contextGin.JSON(http.StatusForbidden, gin.H{"error": "authenticated email is required"})
+		return savedfilter.AccessScope{}, false
+	}
+	return savedfilter.AccessScope{
+		TenantID:   strings.TrimSpace(contextGin.Query(tenantIDQueryParam)),
+		OwnerEmail: ownerEmail,
+		Admin:      sessionHasAdminRole(claims),
+	}, true
+}
+
+func bindSavedFilterDefinition(contextGin *gin.Context) (savedfilter.Definition, bool) {
+	var payload savedFilterPayload
+	if !bindJSONPayload(contextGin, &payload) {
+		return savedfilter.Definition{}, false
+	}
+	definition, err := savedfilter.NewDefinition(payload.Name, payload.Statuses, payload.SearchQuery, payload.Shared)
+	if err != nil {
+		contextGin.JSON(http.StatusBadRequest, gin.H{"error": savedFilterErrorDetail(err)})
+		return savedfilter.Definition{}, false
+	}
+	return definition, true
+}
+
+func (handler *notificationHandler) writeSavedFilterError(contextGin *gin.Context, err error) {
+	switch {
+	case errors.Is(err, savedfilter.ErrInvalidFilter):
+		contextGin.JSON(http.StatusBadRequest, gin.H{"error": savedFilterErrorDetail(err)})
+	case errors.Is(err, savedfilter.ErrFilterNotFound):
+		contextGin.JSON(http.StatusNotFound, gin.H{"error": "saved filter not found"})
+	case errors.Is(err, savedfilter.ErrFilterExists):
+		contextGin.JSON(http.StatusConflict, gin.H{"error": "a saved filter with this name already exists"})
+	case errors.Is(err, savedfilter.ErrFilterLimitReached):
+		contextGin.JSON(http.StatusConflict, gin.H{"error": savedFilterErrorDetail(err)})
+	case errors.Is(err, savedfilter.ErrFilterReadOnly):
+		contextGin.JSON(http.StatusForbidden, gin.H{"error": "shared filters can only be changed by their owner"})
+	case errors.Is(err, savedfilter.ErrSharingNotAllowed):
+		contextGin.JSON(http.StatusForbidden, gin.H{"error": "only admins can share filters"})
+	default:
+		handler.writeError(contextGin, err)
+	}
+}
+
+// savedFilterErrorDetail drops the error code prefix so clients see the reason only.
+func savedFilterErrorDetail(err error) string {
+	message := err.Error()
+	for _, code := range []error{savedfilter.ErrInvalidFilter, savedfilter.ErrFilterLimitReached} {
+		message = strings.TrimPrefix(message, code.Error()+": ")
+	}
+	return message
+}
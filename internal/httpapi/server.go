@@ -2,6 +2,7 @@ package httpapi
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -12,10 +13,16 @@ import (
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/tyemirov/pinguin/internal/certreload"
 	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/opentracking"
+	"github.com/tyemirov/pinguin/internal/savedfilter"
+	"github.com/tyemirov/pinguin/internal/schema"
 	"github.com/tyemirov/pinguin/internal/service"
 	"github.com/tyemirov/pinguin/internal/smtpidentity"
 	"github.com/tyemirov/pinguin/internal/tenant"
+	"github.com/tyemirov/pinguin/pkg/endpoint"
+	"github.com/tyemirov/pinguin/pkg/logging"
 	sessionvalidator "github.com/tyemirov/tauth/pkg/sessionvalidator"
 	"gorm.io/gorm"
 )
@@ -28,8 +35,14 @@ const (
 	notificationSearchParam  = "q"
 	notificationLimitParam   = "limit"
 	notificationCursorParam  = "cursor"
+	notificationFieldsParam  = "fields"
 	sessionAdminRole         = "admin"
+	adminRoutePrefix         = "/api/admin"
 	unknownSourceIP          = "unknown"
+	// drainingRetryAfterSeconds is the Retry-After hint sent while the instance drains.
+	drainingRetryAfterSeconds = "5"
+	// databaseBusyRetryAfterSeconds is the Retry-After hint sent when SQLite stayed locked.
+	databaseBusyRetryAfterSeconds = "1"
 )
 
 var (
@@ -42,25 +55,54 @@ type SessionValidator interface {
 	ValidateRequest(request *http.Request) (*sessionvalidator.Claims, error)
 }
 
+// CertificateStatusReporter reports a hot-reloaded TLS certificate for /healthz.
+type CertificateStatusReporter interface {
+	Status() certreload.Status
+}
+
+// OpenTracker records a load of an email's open tracking pixel.
+type OpenTracker interface {
+	Open(ctx context.Context, token string, userAgent string) error
+}
+
 // Config captures all inputs required to construct the HTTP server.
 type Config struct {
-	ListenAddr           string
-	AllowedOrigins       []string
-	TrustedProxies       []string
+	ListenAddr     string
+	AllowedOrigins []string
+	TrustedProxies []string
+	// ServiceAccountEmails lists session emails, such as monitoring accounts, that may read
+	// notifications and their status across every active tenant but never change them.
+	ServiceAccountEmails []string
 	SessionValidator     SessionValidator
 	NotificationService  service.NotificationService
 	SMTPIdentityService  *smtpidentity.Service
-	TenantRepository     *tenant.Repository
-	Logger               *slog.Logger
-	ReadHeaderTimeout    time.Duration
-	ShutdownGraceTimeout time.Duration
+	// SavedFilterRepository enables /api/filters and the filter_id list shortcut when set.
+	SavedFilterRepository *savedfilter.Repository
+	TenantRepository      *tenant.Repository
+	Logger                *slog.Logger
+	ReadHeaderTimeout     time.Duration
+	ReadTimeout           time.Duration
+	WriteTimeout          time.Duration
+	IdleTimeout           time.Duration
+	ShutdownGraceTimeout  time.Duration
+	// MaxRequestBytes caps request bodies; MaxAttachmentRequestBytes applies to attachment routes.
+	MaxRequestBytes           int64
+	MaxAttachmentRequestBytes int64
+	// Certificates lists the reloadable TLS certificates by listener name; /healthz
+	// reports their expiry and any failed reload.
+	Certificates map[string]CertificateStatusReporter
+	// OpenTracker serves the open tracking pixel at /t/:token when set.
+	OpenTracker OpenTracker
+	// CamelCaseJSON serves /api JSON bodies with camelCase field names instead of snake_case.
+	CamelCaseJSON bool
 }
 
 // Server hosts authenticated HTTP endpoints and static assets for the UI.
 type Server struct {
-	config     Config
-	httpServer *http.Server
-	logger     *slog.Logger
+	config        Config
+	listenNetwork string
+	httpServer    *http.Server
+	logger        *slog.Logger
 }
 
 // NewServer wires Gin, middleware, and handlers for the HTTP API.
@@ -68,6 +110,10 @@ func NewServer(cfg Config) (*Server, error) {
 	if strings.TrimSpace(cfg.ListenAddr) == "" {
 		return nil, errors.New("httpapi: listen address is required")
 	}
+	listenEndpoint, endpointErr := endpoint.Parse(cfg.ListenAddr)
+	if endpointErr != nil {
+		return nil, fmt.Errorf("httpapi: listen address: %w", endpointErr)
+	}
 	if cfg.SessionValidator == nil {
 		return nil, errors.New("httpapi: session validator is required")
 	}
@@ -83,6 +129,7 @@ func NewServer(cfg Config) (*Server, error) {
 
 	gin.SetMode(gin.ReleaseMode)
 	engine := gin.New()
+	engine.MaxMultipartMemory = defaultMaxMultipartMemory
 	if err := engine.SetTrustedProxies(normalizeTrustedProxies(cfg.TrustedProxies)); err != nil {
 		return nil, fmt.Errorf("httpapi: trusted proxies: %w", err)
 	}
@@ -90,19 +137,46 @@ func NewServer(cfg Config) (*Server, error) {
 	engine.Use(requestLogger(cfg.Logger))
 	engine.Use(tenantMiddleware(cfg.TenantRepository))
 	engine.Use(buildCORS(cfg.AllowedOrigins))
+	engine.Use(requestBodyLimiter(
+		pickByteLimit(cfg.MaxRequestBytes, defaultMaxRequestBytes),
+		pickByteLimit(cfg.MaxAttachmentRequestBytes, defaultMaxAttachmentRequestBytes),
+		attachmentRoutePaths,
+	))
 
 	engine.GET("/runtime-config", serveRuntimeConfig())
-	engine.GET("/healthz", func(contextGin *gin.Context) {
-		contextGin.JSON(http.StatusOK, gin.H{"status": "ok"})
-	})
+	engine.GET("/healthz", serveHealthz(cfg.NotificationService, cfg.Certificates))
+	engine.GET("/api/schema", serveSchema(cfg.Logger))
+	if cfg.OpenTracker != nil {
+		engine.GET(opentracking.PixelPathPrefix+":token", serveOpenPixel(cfg.OpenTracker))
+	}
 	protected := engine.Group("/api")
-	protected.Use(sessionMiddleware(cfg.SessionValidator))
+	protected.Use(sessionMiddleware(cfg.SessionValidator, newServiceAccounts(cfg.ServiceAccountEmails), cfg.Logger))
+	protected.Use(debugOverride(cfg.Logger))
+	if cfg.CamelCaseJSON {
+		protected.Use(camelCaseJSON())
+	}
 
 	handler := newNotificationHandler(cfg.NotificationService, cfg.TenantRepository, cfg.Logger)
+	handler.savedFilters = cfg.SavedFilterRepository
 	protected.GET("/tenants", handler.listTenants)
 	protected.GET("/notifications", handler.listNotifications)
+	protected.POST("/notifications", handler.sendNotification)
+	protected.GET("/notifications/:id", handler.getNotification)
 	protected.PATCH("/notifications/:id/schedule", handler.rescheduleNotification)
 	protected.POST("/notifications/:id/cancel", handler.cancelNotification)
+	protected.PATCH("/notifications/:id/legal-hold", handler.setLegalHold)
+	protected.POST("/notification-groups", handler.sendNotificationGroup)
+	protected.GET("/notification-groups/:id", handler.getNotificationGroup)
+	protected.GET("/dispatch-pacing", handler.dispatchPacing)
+	protected.GET("/capabilities", handler.capabilities)
+	protected.GET("/admin/caches", handler.listCaches)
+	protected.POST("/admin/caches/flush", handler.flushCaches)
+	if cfg.SavedFilterRepository != nil {
+		protected.GET("/filters", handler.listSavedFilters)
+		protected.POST("/filters", handler.createSavedFilter)
+		protected.PATCH("/filters/:id", handler.updateSavedFilter)
+		protected.DELETE("/filters/:id", handler.deleteSavedFilter)
+	}
 	if cfg.SMTPIdentityService != nil {
 		identityHandler := newSMTPIdentityHandler(cfg.SMTPIdentityService, cfg.TenantRepository, cfg.Logger)
 		protected.GET("/smtp-domains", identityHandler.listSenderDomains)
@@ -117,21 +191,30 @@ func NewServer(cfg Config) (*Server, error) {
 	}
 
 	httpServer := &http.Server{
-		Addr:              cfg.ListenAddr,
+		Addr:              listenEndpoint.Address(),
 		Handler:           engine,
 		ReadHeaderTimeout: pickDuration(cfg.ReadHeaderTimeout, defaultTimeout),
+		ReadTimeout:       pickDuration(cfg.ReadTimeout, defaultReadTimeout),
+		WriteTimeout:      pickDuration(cfg.WriteTimeout, defaultWriteTimeout),
+		IdleTimeout:       pickDuration(cfg.IdleTimeout, defaultIdleTimeout),
 	}
 
 	return &Server{
-		config:     cfg,
-		httpServer: httpServer,
-		logger:     cfg.Logger,
+		config:        cfg,
+		listenNetwork: listenEndpoint.Network(),
+		httpServer:    httpServer,
+		logger:        cfg.Logger,
 	}, nil
 }
 
-// Start begins serving HTTP traffic.
+// Start binds the listen address, which may be a Unix socket, and serves HTTP
+// traffic. Shutdown closes the listener, removing a Unix socket file.
 func (server *Server) Start() error {
-	err := server.httpServer.ListenAndServe()
+	listener, listenErr := endpoint.Listen(server.listenNetwork, server.httpServer.Addr)
+	if listenErr != nil {
+		return listenErr
+	}
+	err := server.httpServer.Serve(listener)
 	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return err
 	}
@@ -150,6 +233,10 @@ func requestLogger(logger *slog.Logger) gin.HandlerFunc {
 	return func(contextGin *gin.Context) {
 		started := time.Now()
 		contextGin.Next()
+		if strings.HasPrefix(contextGin.Request.URL.Path, opentracking.PixelPathPrefix) {
+			// Opens are recorded without the reader's address; a log line would add it back.
+			return
+		}
 		logger.Info(
 			"http_request_completed",
 			"method", contextGin.Request.Method,
@@ -163,6 +250,23 @@ func requestLogger(logger *slog.Logger) gin.HandlerFunc {
 	}
 }
 
+// debugOverride honors the X-Debug header of an authenticated request: its context logs
+// at DEBUG whatever the configured level, and its outcome and duration are logged when
+// it finishes. It runs after the session check, so anonymous callers cannot use it.
+func debugOverride(logger *slog.Logger) gin.HandlerFunc {
+	return func(contextGin *gin.Context) {
+		if !logging.DebugHeaderEnabled(contextGin.GetHeader(logging.DebugHeader)) {
+			contextGin.Next()
+			return
+		}
+		debugCtx := logging.WithDebug(contextGin.Request.Context())
+		contextGin.Request = contextGin.Request.WithContext(debugCtx)
+		startedAt := time.Now()
+		contextGin.Next()
+		logger.DebugContext(debugCtx, "http_request_debug", "method", contextGin.Request.Method, "path", contextGin.FullPath(), "status", contextGin.Writer.Status(), "duration_ms", time.Since(startedAt).Milliseconds())
+	}
+}
+
 func normalizeTrustedProxies(trustedProxies []string) []string {
 	normalizedTrustedProxies := make([]string, 0, len(trustedProxies))
 	for _, trustedProxy := range trustedProxies {
@@ -201,7 +305,7 @@ func buildCORS(allowedOrigins []string) gin.HandlerFunc {
 	if len(allowedOrigins) == 0 {
 		cfg := cors.Config{
 			AllowAllOrigins:  true,
-			AllowHeaders:     []string{"Content-Type", "X-Requested-With", "X-Client-Data", "X-Client"},
+			AllowHeaders:     []string{"Content-Type", "X-Requested-With", "X-Client-Data", "X-Client", logging.DebugHeader},
 			AllowMethods:     []string{http.MethodGet, http.MethodPost, http.MethodPatch, http.MethodDelete, http.MethodOptions},
 			AllowCredentials: false,
 		}
@@ -209,7 +313,7 @@ func buildCORS(allowedOrigins []string) gin.HandlerFunc {
 	}
 	cfg := cors.Config{
 		AllowOrigins:     allowedOrigins,
-		AllowHeaders:     []string{"Content-Type", "X-Requested-With", "X-Client-Data", "X-Client"},
+		AllowHeaders:     []string{"Content-Type", "X-Requested-With", "X-Client-Data", "X-Client", logging.DebugHeader},
 		AllowMethods:     []string{http.MethodGet, http.MethodPost, http.MethodPatch, http.MethodDelete, http.MethodOptions},
 		AllowCredentials: true,
 	}
@@ -235,31 +339,50 @@ func tenantMiddleware(repo *tenant.Repository) gin.HandlerFunc {
 
 func isTenantAgnosticPath(path string) bool {
 	return path == "/healthz" ||
+		strings.HasPrefix(path, opentracking.PixelPathPrefix) ||
+		path == "/api/schema" ||
 		path == "/api/tenants" ||
+		strings.HasPrefix(path, adminRoutePrefix+"/") ||
 		path == "/api/notifications" ||
 		strings.HasPrefix(path, "/api/notifications/") ||
+		path == notificationGroupRoutePrefix ||
+		strings.HasPrefix(path, notificationGroupRoutePrefix+"/") ||
+		path == "/api/filters" ||
+		strings.HasPrefix(path, "/api/filters/") ||
 		path == "/api/smtp-domains" ||
 		strings.HasPrefix(path, "/api/smtp-domains/") ||
 		path == "/api/smtp-identities" ||
 		strings.HasPrefix(path, "/api/smtp-identities/")
 }
 
-func sessionMiddleware(validator SessionValidator) gin.HandlerFunc {
+// sessionMiddleware authenticates /api requests. Service-account sessions are limited
+// to serviceAccountRoutes before any handler runs.
+func sessionMiddleware(validator SessionValidator, accounts serviceAccounts, logger *slog.Logger) gin.HandlerFunc {
 	return func(contextGin *gin.Context) {
 		claims, err := validator.ValidateRequest(contextGin.Request)
 		if err != nil {
+			logging.LogEvent(contextGin.Request.Context(), logger, logging.EventAuthRejected, "http_session_rejected", "transport", "http", "reason", "invalid_session", "path", contextGin.FullPath(), "error", err)
 			contextGin.AbortWithStatus(http.StatusUnauthorized)
 			return
 		}
+		if accounts.contains(claims.GetUserEmail()) {
+			if !serviceAccountRouteAllowed(contextGin) {
+				logging.LogEvent(contextGin.Request.Context(), logger, logging.EventAuthRejected, "http_session_rejected", "transport", "http", "reason", "service_account_read_only", "method", contextGin.Request.Method, "path", contextGin.FullPath())
+				contextGin.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "service accounts have read-only access"})
+				return
+			}
+			contextGin.Set(contextKeyServiceAccount, true)
+		}
 		contextGin.Set(contextKeyClaims, claims)
 		contextGin.Next()
 	}
 }
 
 type notificationHandler struct {
-	service    service.NotificationService
-	repository *tenant.Repository
-	logger     *slog.Logger
+	service      service.NotificationService
+	repository   *tenant.Repository
+	savedFilters *savedfilter.Repository
+	logger       *slog.Logger
 }
 
 func newNotificationHandler(svc service.NotificationService, repo *tenant.Repository, logger *slog.Logger) *notificationHandler {
@@ -293,13 +416,39 @@ func (handler *notificationHandler) listNotifications(contextGin *gin.Context) {
 		writeNotificationListRequestError(contextGin, parseErr)
 		return
 	}
+	filter, ok := handler.applySavedFilter(contextGin, filter)
+	if !ok {
+		return
+	}
+	fieldSet, fieldsErr := model.NewNotificationFieldSet(contextGin.Query(notificationFieldsParam))
+	if fieldsErr != nil {
+		writeNotificationListRequestError(contextGin, fieldsErr)
+		return
+	}
 	page, err := handler.service.ListNotificationsPage(requestContext, filter, pageRequest)
 	if err != nil {
 		handler.writeError(contextGin, err)
 		return
 	}
-	contextGin.JSON(http.StatusOK, notificationListPayload{
-		Notifications: page.Notifications,
+	masker := recipientMaskerFor(contextGin)
+	if fieldSet.SelectsAll() {
+		contextGin.JSON(http.StatusOK, notificationListPayload{
+			Notifications: masker.notifications(page.Notifications),
+			NextCursor:    page.NextCursor,
+		})
+		return
+	}
+	projectedNotifications := make([]map[string]json.RawMessage, 0, len(page.Notifications))
+	for _, notification := range masker.notifications(page.Notifications) {
+		projected, projectErr := fieldSet.Project(notification)
+		if projectErr != nil {
+			handler.writeError(contextGin, projectErr)
+			return
+		}
+		projectedNotifications = append(projectedNotifications, projected)
+	}
+	contextGin.JSON(http.StatusOK, projectedNotificationListPayload{
+		Notifications: projectedNotifications,
 		NextCursor:    page.NextCursor,
 	})
 }
@@ -313,8 +462,7 @@ func (handler *notificationHandler) rescheduleNotification(contextGin *gin.Conte
 	var payload struct {
 		ScheduledTime string `json:"scheduled_time"`
 	}
-	if err := contextGin.ShouldBindJSON(&payload); err != nil {
-		contextGin.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+	if !bindJSONPayload(contextGin, &payload) {
 		return
 	}
 	if strings.TrimSpace(payload.ScheduledTime) == "" {
@@ -327,10 +475,6 @@ func (handler *notificationHandler) rescheduleNotification(contextGin *gin.Conte
 		return
 	}
 	normalizedTime := parsedTime.UTC()
-	if normalizedTime.Before(time.Now().UTC()) {
-		contextGin.JSON(http.StatusBadRequest, gin.H{"error": scheduledTimeFutureError})
-		return
-	}
 	requestContext, resolveErr := handler.resolveNotificationContext(contextGin)
 	if resolveErr != nil {
 		handler.writeTenantResolutionError(contextGin, resolveErr)
@@ -341,34 +485,210 @@ func (handler *notificationHandler) rescheduleNotification(contextGin *gin.Conte
 		handler.writeError(contextGin, svcErr)
 		return
 	}
-	contextGin.JSON(http.StatusOK, response)
+	contextGin.JSON(http.StatusOK, recipientMaskerFor(contextGin).notification(response))
+}
+
+// getNotification returns one notification; include_rendered=true adds the content it
+// was dispatched with when the tenant records it, and fields limits the response like
+// it does for the list.
+func (handler *notificationHandler) getNotification(contextGin *gin.Context) {
+	notificationID := strings.TrimSpace(contextGin.Param("id"))
+	if notificationID == "" {
+		contextGin.JSON(http.StatusBadRequest, gin.H{"error": "notification_id is required"})
+		return
+	}
+	fieldSet, fieldsErr := model.NewNotificationFieldSet(contextGin.Query(notificationFieldsParam))
+	if fieldsErr != nil {
+		writeNotificationListRequestError(contextGin, fieldsErr)
+		return
+	}
+	includeRendered := false
+	if rawInclude := strings.TrimSpace(contextGin.Query("include_rendered")); rawInclude != "" {
+		parsed, parseErr := strconv.ParseBool(rawInclude)
+		if parseErr != nil {
+			contextGin.JSON(http.StatusBadRequest, gin.H{"error": "include_rendered must be true or false"})
+			return
+		}
+		includeRendered = parsed
+	}
+	requestContext, resolveErr := handler.resolveNotificationContext(contextGin)
+	if resolveErr != nil {
+		handler.writeTenantResolutionError(contextGin, resolveErr)
+		return
+	}
+	var response model.NotificationResponse
+	var err error
+	if includeRendered {
+		response, err = handler.service.GetRenderedNotification(requestContext, notificationID)
+	} else {
+		response, err = handler.service.GetNotificationStatus(requestContext, notificationID)
+	}
+	if err != nil {
+		handler.writeError(contextGin, err)
+		return
+	}
+	masked := recipientMaskerFor(contextGin).notification(response)
+	if fieldSet.SelectsAll() {
+		contextGin.JSON(http.StatusOK, masked)
+		return
+	}
+	projected, projectErr := fieldSet.Project(masked)
+	if projectErr != nil {
+		handler.writeError(contextGin, projectErr)
+		return
+	}
+	contextGin.JSON(http.StatusOK, projected)
 }
 
+// cancelNotification cancels a queued notification. The body is optional; its reason
+// is recorded with the session email as the actor.
 func (handler *notificationHandler) cancelNotification(contextGin *gin.Context) {
 	notificationID := strings.TrimSpace(contextGin.Param("id"))
 	if notificationID == "" {
 		contextGin.JSON(http.StatusBadRequest, gin.H{"error": "notification_id is required"})
 		return
 	}
+	var payload struct {
+		Reason string `json:"reason"`
+	}
+	if contextGin.Request.ContentLength != 0 && !bindJSONPayload(contextGin, &payload) {
+		return
+	}
 	requestContext, resolveErr := handler.resolveNotificationContext(contextGin)
 	if resolveErr != nil {
 		handler.writeTenantResolutionError(contextGin, resolveErr)
 		return
 	}
-	response, err := handler.service.CancelNotification(requestContext, notificationID)
+	response, err := handler.service.CancelNotification(requestContext, notificationID, payload.Reason, claimsFromContextGin(contextGin).GetUserEmail())
 	if err != nil {
 		handler.writeError(contextGin, err)
 		return
 	}
-	contextGin.JSON(http.StatusOK, response)
+	contextGin.JSON(http.StatusOK, recipientMaskerFor(contextGin).notification(response))
+}
+
+// setLegalHold places or lifts a legal hold. Only admins may change holds, since a held
+// notification outlives the tenant's retention.
+func (handler *notificationHandler) setLegalHold(contextGin *gin.Context) {
+	notificationID := strings.TrimSpace(contextGin.Param("id"))
+	if notificationID == "" {
+		contextGin.JSON(http.StatusBadRequest, gin.H{"error": "notification_id is required"})
+		return
+	}
+	var payload struct {
+		LegalHold *bool `json:"legal_hold"`
+	}
+	if !bindJSONPayload(contextGin, &payload) {
+		return
+	}
+	if payload.LegalHold == nil {
+		contextGin.JSON(http.StatusBadRequest, gin.H{"error": "legal_hold is required"})
+		return
+	}
+	claims := claimsFromContextGin(contextGin)
+	admin, adminErr := sessionHasAdminAccess(contextGin, handler.repository, claims)
+	if adminErr != nil {
+		handler.writeTenantResolutionError(contextGin, adminErr)
+		return
+	}
+	if !admin {
+		contextGin.JSON(http.StatusForbidden, gin.H{"error": "only admins may change legal holds"})
+		return
+	}
+	requestContext, resolveErr := handler.resolveNotificationContext(contextGin)
+	if resolveErr != nil {
+		handler.writeTenantResolutionError(contextGin, resolveErr)
+		return
+	}
+	response, err := handler.service.SetLegalHold(requestContext, notificationID, *payload.LegalHold, claims.GetUserEmail())
+	if err != nil {
+		handler.writeError(contextGin, err)
+		return
+	}
+	contextGin.JSON(http.StatusOK, recipientMaskerFor(contextGin).notification(response))
+}
+
+func (handler *notificationHandler) dispatchPacing(contextGin *gin.Context) {
+	requestContext, resolveErr := handler.resolveNotificationContext(contextGin)
+	if resolveErr != nil {
+		handler.writeTenantResolutionError(contextGin, resolveErr)
+		return
+	}
+	states, err := handler.service.DispatchPacing(requestContext)
+	if err != nil {
+		handler.writeError(contextGin, err)
+		return
+	}
+	if states == nil {
+		states = []service.DispatchPacingState{}
+	}
+	contextGin.JSON(http.StatusOK, gin.H{"pacing": states})
+}
+
+// listCaches reports the process's tenant and sender caches to super-admins.
+func (handler *notificationHandler) listCaches(contextGin *gin.Context) {
+	if !sessionHasAdminRole(claimsFromContextGin(contextGin)) {
+		contextGin.JSON(http.StatusForbidden, gin.H{"error": "only admins may inspect caches"})
+		return
+	}
+	contextGin.JSON(http.StatusOK, gin.H{"caches": handler.service.CacheStates(contextGin.Request.Context())})
+}
+
+// flushCaches empties the process's tenant and sender caches and reports what they held.
+func (handler *notificationHandler) flushCaches(contextGin *gin.Context) {
+	claims := claimsFromContextGin(contextGin)
+	if !sessionHasAdminRole(claims) {
+		contextGin.JSON(http.StatusForbidden, gin.H{"error": "only admins may flush caches"})
+		return
+	}
+	flushed := handler.service.FlushCaches(contextGin.Request.Context(), claims.GetUserEmail())
+	contextGin.JSON(http.StatusOK, gin.H{"caches": flushed})
+}
+
+func (handler *notificationHandler) capabilities(contextGin *gin.Context) {
+	requestContext, resolveErr := handler.resolveNotificationContext(contextGin)
+	if resolveErr != nil {
+		handler.writeTenantResolutionError(contextGin, resolveErr)
+		return
+	}
+	capabilities, err := handler.service.GetCapabilities(requestContext)
+	if err != nil {
+		handler.writeError(contextGin, err)
+		return
+	}
+	contextGin.JSON(http.StatusOK, capabilities)
 }
 
 func (handler *notificationHandler) writeError(contextGin *gin.Context, err error) {
 	switch {
-	case isMissingNotificationID(err):
+	case errors.Is(err, service.ErrMissingNotificationID):
 		contextGin.JSON(http.StatusBadRequest, gin.H{"error": "notification_id is required"})
+	case errors.Is(err, service.ErrScheduleAfterExpiry):
+		contextGin.JSON(http.StatusBadRequest, gin.H{"error": "scheduled_time must be before expires_at"})
+	case errors.Is(err, service.ErrScheduleInPast):
+		contextGin.JSON(http.StatusBadRequest, gin.H{"error": scheduledTimeFutureError})
+	case errors.Is(err, service.ErrScheduleBeyondHorizon), errors.Is(err, model.ErrNotificationSMSTooLong), errors.Is(err, model.ErrNotificationSenderOverrideForbidden), errors.Is(err, model.ErrCancelReasonInvalid), errors.Is(err, model.ErrUnknownNotificationStatus):
+		contextGin.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrAttachmentDataNotPersisted), errors.Is(err, service.ErrEncryptedContentUnsupported), errors.Is(err, service.ErrSenderOverridesUnsupported):
+		contextGin.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrSMSDisabled):
+		contextGin.JSON(http.StatusBadRequest, gin.H{"error": "sms delivery is disabled for this tenant"})
+	case errors.Is(err, service.ErrDispatchCapacityExhausted):
+		contextGin.JSON(http.StatusTooManyRequests, gin.H{"error": "too many sends in flight; retry later"})
+	case errors.Is(err, service.ErrDraining):
+		contextGin.Header("Retry-After", drainingRetryAfterSeconds)
+		contextGin.JSON(http.StatusServiceUnavailable, gin.H{"error": "instance is draining; retry later"})
+	case errors.Is(err, model.ErrDatabaseBusy):
+		handler.logger.Warn("http_database_busy", "error", err)
+		contextGin.Header("Retry-After", databaseBusyRetryAfterSeconds)
+		contextGin.JSON(http.StatusServiceUnavailable, gin.H{"error": "database is busy; retry later"})
+	case errors.Is(err, model.ErrQueryTimeout):
+		handler.logger.Warn("http_database_timeout", "error", err)
+		contextGin.JSON(http.StatusGatewayTimeout, gin.H{"error": "database query timed out"})
 	case errors.Is(err, service.ErrNotificationNotEditable):
 		contextGin.JSON(http.StatusConflict, gin.H{"error": "notification can only be edited while queued"})
+	case errors.Is(err, model.ErrNotificationGroupNotFound):
+		contextGin.JSON(http.StatusNotFound, gin.H{"error": "notification group not found"})
 	case errors.Is(err, model.ErrNotificationNotFound), errors.Is(err, gorm.ErrRecordNotFound):
 		contextGin.JSON(http.StatusNotFound, gin.H{"error": "notification not found"})
 	default:
@@ -383,16 +703,24 @@ func (handler *notificationHandler) resolveNotificationContext(contextGin *gin.C
 		return nil, errTenantIDRequired
 	}
 	if err := handler.authorizeNotificationTenant(contextGin, tenantID); err != nil {
+		if errors.Is(err, errTenantAccessDenied) {
+			logging.LogEvent(contextGin.Request.Context(), handler.logger, logging.EventAuthRejected, "http_tenant_access_denied", "transport", "http", "reason", "tenant_access_denied", "tenant_id", tenantID)
+		}
 		return nil, err
 	}
 	targetCfg, err := handler.repository.ResolveByID(contextGin.Request.Context(), tenantID)
 	if err != nil {
+		logging.LogEvent(contextGin.Request.Context(), handler.logger, logging.EventTenantResolutionFailed, "tenant_resolution_failed", "transport", "http", "tenant_id", tenantID, "error", err)
 		return nil, err
 	}
+	logging.LogEvent(contextGin.Request.Context(), handler.logger, logging.EventTenantResolved, "tenant_resolved", "transport", "http", "tenant_id", targetCfg.Tenant.ID, "source", "tenant_id")
 	return tenant.WithRuntime(contextGin.Request.Context(), targetCfg), nil
 }
 
 func (handler *notificationHandler) accessibleTenants(contextGin *gin.Context) ([]tenant.Tenant, error) {
+	if sessionIsServiceAccount(contextGin) {
+		return handler.repository.ListActiveTenants(contextGin.Request.Context())
+	}
 	claims := claimsFromContextGin(contextGin)
 	admin, adminErr := sessionHasAdminAccess(contextGin, handler.repository, claims)
 	if adminErr != nil {
@@ -409,6 +737,9 @@ func (handler *notificationHandler) accessibleTenants(contextGin *gin.Context) (
 }
 
 func (handler *notificationHandler) authorizeNotificationTenant(contextGin *gin.Context, tenantID string) error {
+	if sessionIsServiceAccount(contextGin) {
+		return nil
+	}
 	claims := claimsFromContextGin(contextGin)
 	admin, adminErr := sessionHasAdminAccess(contextGin, handler.repository, claims)
 	if adminErr != nil {
@@ -483,13 +814,6 @@ func (handler *notificationHandler) writeTenantResolutionError(contextGin *gin.C
 	}
 }
 
-func isMissingNotificationID(err error) bool {
-	if err == nil {
-		return false
-	}
-	return strings.Contains(err.Error(), "missing notification_id")
-}
-
 func parseStatusFilters(values []string) []model.NotificationStatus {
 	if len(values) == 0 {
 		return nil
@@ -516,6 +840,11 @@ type notificationListPayload struct {
 	NextCursor    string                       `json:"next_cursor,omitempty"`
 }
 
+type projectedNotificationListPayload struct {
+	Notifications []map[string]json.RawMessage `json:"notifications"`
+	NextCursor    string                       `json:"next_cursor,omitempty"`
+}
+
 func parseNotificationListRequest(contextGin *gin.Context) (model.NotificationListFilters, model.NotificationListPageRequest, error) {
 	searchQuery, searchErr := model.NewNotificationSearchQuery(contextGin.Query(notificationSearchParam))
 	if searchErr != nil {
@@ -560,6 +889,8 @@ func writeNotificationListRequestError(contextGin *gin.Context, err error) {
 		contextGin.JSON(http.StatusBadRequest, gin.H{"error": "cursor is invalid"})
 	case errors.Is(err, model.ErrInvalidNotificationLimit):
 		contextGin.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+	case errors.Is(err, model.ErrInvalidNotificationFields):
+		contextGin.JSON(http.StatusBadRequest, gin.H{"error": "fields must list known notification fields"})
 	default:
 		contextGin.JSON(http.StatusBadRequest, gin.H{"error": "invalid notification list request"})
 	}
@@ -584,6 +915,36 @@ type runtimeConfigTenant struct {
 	DisplayName string `json:"displayName"`
 }
 
+type certificateHealth struct {
+	NotAfter time.Time `json:"not_after"`
+	Warning  string    `json:"warning,omitempty"`
+}
+
+// serveHealthz reports draining as unavailable. A failed certificate reload is only a
+// warning in the details because the previous certificate is still served.
+func serveHealthz(notificationService service.NotificationService, certificates map[string]CertificateStatusReporter) gin.HandlerFunc {
+	return func(contextGin *gin.Context) {
+		if notificationService.Draining() {
+			contextGin.JSON(http.StatusServiceUnavailable, gin.H{"status": "draining"})
+			return
+		}
+		if len(certificates) == 0 {
+			contextGin.JSON(http.StatusOK, gin.H{"status": "ok"})
+			return
+		}
+		tlsDetails := make(map[string]certificateHealth, len(certificates))
+		for name, reporter := range certificates {
+			certificateStatus := reporter.Status()
+			health := certificateHealth{NotAfter: certificateStatus.NotAfter}
+			if certificateStatus.ReloadError != "" {
+				health.Warning = "certificate reload failed, serving the previous certificate: " + certificateStatus.ReloadError
+			}
+			tlsDetails[name] = health
+		}
+		contextGin.JSON(http.StatusOK, gin.H{"status": "ok", "details": gin.H{"tls_certificates": tlsDetails}})
+	}
+}
+
 func serveRuntimeConfig() gin.HandlerFunc {
 	return func(contextGin *gin.Context) {
 		runtimeCfg, ok := tenant.RuntimeFromContext(contextGin.Request.Context())
@@ -604,6 +965,35 @@ func serveRuntimeConfig() gin.HandlerFunc {
 	}
 }
 
+// serveOpenPixel answers pixel loads without a session or tenant host: the signed token
+// names the notification. Every token that is not recorded gets the same 404, so the
+// endpoint does not reveal whether a token was forged, expired or opted out.
+func serveOpenPixel(tracker OpenTracker) gin.HandlerFunc {
+	return func(contextGin *gin.Context) {
+		if err := tracker.Open(contextGin.Request.Context(), contextGin.Param("token"), contextGin.Request.UserAgent()); err != nil {
+			contextGin.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+		contextGin.Header("Cache-Control", "no-store, no-cache, must-revalidate, private")
+		contextGin.Header("Pragma", "no-cache")
+		contextGin.Data(http.StatusOK, "image/gif", opentracking.PixelGIF)
+	}
+}
+
+// serveSchema publishes the JSON Schema catalog of the notification and webhook payloads.
+// It needs neither a tenant nor a session so partners can validate against it in CI.
+func serveSchema(logger *slog.Logger) gin.HandlerFunc {
+	return func(contextGin *gin.Context) {
+		catalog, err := schema.Build()
+		if err != nil {
+			logger.Error("Failed to build payload schema", "error", err)
+			contextGin.JSON(http.StatusInternalServerError, gin.H{"error": "schema_unavailable"})
+			return
+		}
+		contextGin.JSON(http.StatusOK, catalog)
+	}
+}
+
 func buildAPIBaseURL(request *http.Request) string {
 	if request == nil {
 		return "/api"
@@ -3,12 +3,16 @@ package httpapi
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"mime/multipart"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/textproto"
 	"os"
 	"path/filepath"
 	"strings"
@@ -17,10 +21,15 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/glebarez/sqlite"
+	"github.com/tyemirov/pinguin/internal/certreload"
 	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/opentracking"
+	"github.com/tyemirov/pinguin/internal/savedfilter"
+	"github.com/tyemirov/pinguin/internal/schema"
 	"github.com/tyemirov/pinguin/internal/service"
 	"github.com/tyemirov/pinguin/internal/smtpidentity"
 	"github.com/tyemirov/pinguin/internal/tenant"
+	"github.com/tyemirov/pinguin/pkg/logging"
 	sessionvalidator "github.com/tyemirov/tauth/pkg/sessionvalidator"
 	"gopkg.in/yaml.v3"
 	"gorm.io/gorm"
@@ -74,6 +83,187 @@ func TestListNotificationsReturnsData(t *testing.T) {
 	}
 }
 
+func TestListNotificationsProjectsRequestedFields(t *testing.T) {
+	t.Helper()
+
+	createdAt := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	stubSvc := &stubNotificationService{
+		listResponse: []model.NotificationResponse{
+			{NotificationID: "queued", Status: model.StatusQueued, Recipient: "user@example.com", Message: "Body", CreatedAt: createdAt},
+		},
+	}
+	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/api/notifications?tenant_id=tenant-test&fields=notification_id,status,created_at", nil)
+	server.httpServer.Handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", recorder.Code)
+	}
+
+	var payload struct {
+		Notifications []map[string]any `json:"notifications"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("response decode error: %v", err)
+	}
+	if len(payload.Notifications) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(payload.Notifications))
+	}
+	projected := payload.Notifications[0]
+	if len(projected) != 3 || projected["notification_id"] != "queued" || projected["status"] != string(model.StatusQueued) || projected["created_at"] != "2026-03-01T10:00:00Z" {
+		t.Fatalf("unexpected projected notification %v", projected)
+	}
+}
+
+func TestGetNotificationProjectsRequestedFields(t *testing.T) {
+	stubSvc := &stubNotificationService{statusResponse: model.NotificationResponse{NotificationID: "notif-1", Status: model.StatusSent, Recipient: "user@example.com", Message: "Body"}}
+	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
+
+	recorder := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/notifications/notif-1?tenant_id=tenant-test&fields=notification_id,status", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", recorder.Code)
+	}
+	var projected map[string]any
+	if err := json.Unmarshal(recorder.Body.Bytes(), &projected); err != nil {
+		t.Fatalf("response decode error: %v", err)
+	}
+	if len(projected) != 2 || projected["notification_id"] != "notif-1" || projected["status"] != string(model.StatusSent) {
+		t.Fatalf("unexpected projected notification %v", projected)
+	}
+
+	recorder = httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/notifications/notif-1?tenant_id=tenant-test&fields=password", nil))
+	if recorder.Code != http.StatusBadRequest || !strings.Contains(recorder.Body.String(), "fields must list known notification fields") {
+		t.Fatalf("expected an unknown field to return 400, got %d %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestListNotificationsMasksRecipientsForViewers(t *testing.T) {
+	t.Helper()
+
+	testCases := []struct {
+		name              string
+		roles             []string
+		expectedRecipient []string
+	}{
+		{name: "viewer", roles: []string{"viewer"}, expectedRecipient: []string{"u***@example.com", "********4567"}},
+		{name: "admin", roles: []string{"admin"}, expectedRecipient: []string{"user@example.com", "+15551234567"}},
+		{name: "viewer and admin", roles: []string{"viewer", "admin"}, expectedRecipient: []string{"user@example.com", "+15551234567"}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			stubSvc := &stubNotificationService{
+				listResponse: []model.NotificationResponse{
+					{NotificationID: "email", Status: model.StatusQueued, Recipient: "user@example.com"},
+					{NotificationID: "sms", Status: model.StatusQueued, Recipient: "+15551234567"},
+				},
+			}
+			server := newTestHTTPServer(t, stubSvc, &stubValidator{roles: testCase.roles})
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/api/notifications?tenant_id=tenant-test", nil)
+			server.httpServer.Handler.ServeHTTP(recorder, request)
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d", recorder.Code)
+			}
+			var payload notificationListPayload
+			if err := json.Unmarshal(recorder.Body.Bytes(), &payload); err != nil {
+				t.Fatalf("response decode error: %v", err)
+			}
+			if len(payload.Notifications) != len(testCase.expectedRecipient) {
+				t.Fatalf("expected %d notifications, got %d", len(testCase.expectedRecipient), len(payload.Notifications))
+			}
+			for index, notification := range payload.Notifications {
+				if notification.Recipient != testCase.expectedRecipient[index] {
+					t.Fatalf("expected recipient %q, got %q", testCase.expectedRecipient[index], notification.Recipient)
+				}
+			}
+			if stubSvc.listResponse[0].Recipient != "user@example.com" {
+				t.Fatalf("masking changed the service response: %q", stubSvc.listResponse[0].Recipient)
+			}
+		})
+	}
+}
+
+func TestGetNotificationMasksRecipientForViewers(t *testing.T) {
+	t.Helper()
+
+	stubSvc := &stubNotificationService{
+		statusResponse: model.NotificationResponse{NotificationID: "notif-1", Status: model.StatusSent, Recipient: "user@example.com"},
+	}
+	viewerServer := newTestHTTPServer(t, stubSvc, &stubValidator{roles: []string{"viewer"}})
+	adminServer := newTestHTTPServer(t, stubSvc, &stubValidator{roles: []string{"admin"}})
+
+	for _, testCase := range []struct {
+		server            *Server
+		expectedRecipient string
+	}{
+		{server: viewerServer, expectedRecipient: "u***@example.com"},
+		{server: adminServer, expectedRecipient: "user@example.com"},
+	} {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/api/notifications/notif-1?tenant_id=tenant-test", nil)
+		testCase.server.httpServer.Handler.ServeHTTP(recorder, request)
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", recorder.Code)
+		}
+		var response model.NotificationResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("response decode error: %v", err)
+		}
+		if response.Recipient != testCase.expectedRecipient {
+			t.Fatalf("expected recipient %q, got %q", testCase.expectedRecipient, response.Recipient)
+		}
+	}
+}
+
+func TestViewerResponsesMaskReplyToAndCancellingSession(t *testing.T) {
+	notification := model.NotificationResponse{NotificationID: "notif-1", Status: model.StatusCancelled, Recipient: "user@example.com", ReplyTo: "support@example.com", CancelledBy: "operator@example.com"}
+	systemCancelled := model.NotificationResponse{NotificationID: "notif-2", Status: model.StatusCancelled, Recipient: "user@example.com", CancelledBy: model.CancelActorSystem}
+	stubSvc := &stubNotificationService{statusResponse: notification, listResponse: []model.NotificationResponse{notification, systemCancelled}}
+	server := newTestHTTPServer(t, stubSvc, &stubValidator{roles: []string{"viewer"}})
+
+	recorder := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/notifications/notif-1?tenant_id=tenant-test", nil))
+	var single model.NotificationResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &single); err != nil || recorder.Code != http.StatusOK {
+		t.Fatalf("expected the notification, got %d %s (%v)", recorder.Code, recorder.Body.String(), err)
+	}
+	recorder = httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/notifications?tenant_id=tenant-test", nil))
+	var list notificationListPayload
+	if err := json.Unmarshal(recorder.Body.Bytes(), &list); err != nil || recorder.Code != http.StatusOK || len(list.Notifications) != 2 {
+		t.Fatalf("expected the notification list, got %d %s (%v)", recorder.Code, recorder.Body.String(), err)
+	}
+	for _, masked := range []model.NotificationResponse{single, list.Notifications[0]} {
+		if masked.ReplyTo != "s******@example.com" || masked.CancelledBy != "o*******@example.com" {
+			t.Fatalf("expected reply_to and cancelled_by masked, got %q %q", masked.ReplyTo, masked.CancelledBy)
+		}
+	}
+	if list.Notifications[1].CancelledBy != model.CancelActorSystem || list.Notifications[1].ReplyTo != "" {
+		t.Fatalf("expected a non-email actor to stay readable, got %+v", list.Notifications[1])
+	}
+}
+
+func TestListNotificationsRejectsUnknownField(t *testing.T) {
+	t.Helper()
+
+	stubSvc := &stubNotificationService{}
+	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/api/notifications?tenant_id=tenant-test&fields=notification_id,password", nil)
+	server.httpServer.Handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", recorder.Code)
+	}
+	if !strings.Contains(recorder.Body.String(), "fields must list known notification fields") {
+		t.Fatalf("unexpected error body %s", recorder.Body.String())
+	}
+}
+
 func TestListNotificationsParsesSearchAndPagination(t *testing.T) {
 	t.Helper()
 
@@ -283,6 +473,87 @@ func TestListNotificationsRejectsTenantOutsideUserDomain(t *testing.T) {
 	}
 }
 
+func TestServiceAccountReadsAcrossTenantsButCannotMutate(t *testing.T) {
+	t.Helper()
+
+	notification := model.NotificationResponse{NotificationID: "notif-1", Status: model.StatusCancelled, Recipient: "user@example.com", ReplyTo: "support@example.com", CancelledBy: "operator@example.com"}
+	stubSvc := &stubNotificationService{
+		listResponse:   []model.NotificationResponse{notification},
+		statusResponse: notification,
+	}
+	newServer := func(serviceAccounts []string) *Server {
+		server, err := NewServer(Config{
+			ListenAddr:           ":0",
+			NotificationService:  stubSvc,
+			SessionValidator:     &stubValidator{email: "Monitor@ops.localhost", roles: []string{"user"}},
+			TenantRepository:     newMultiTenantRepository(t),
+			ServiceAccountEmails: serviceAccounts,
+			Logger:               slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
+		})
+		if err != nil {
+			t.Fatalf("server init error: %v", err)
+		}
+		return server
+	}
+	serve := func(server *Server, method string, path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		server.httpServer.Handler.ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+		return recorder
+	}
+
+	if recorder := serve(newServer(nil), http.MethodGet, "/api/notifications?tenant_id=tenant-bravo"); recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 before the email is a service account, got %d", recorder.Code)
+	}
+	server := newServer([]string{"monitor@ops.localhost"})
+
+	tenantsRecorder := serve(server, http.MethodGet, "/api/tenants")
+	var tenantsPayload struct {
+		Tenants []runtimeConfigTenant `json:"tenants"`
+	}
+	if err := json.Unmarshal(tenantsRecorder.Body.Bytes(), &tenantsPayload); err != nil || tenantsRecorder.Code != http.StatusOK {
+		t.Fatalf("expected the tenant list, got %d %s (%v)", tenantsRecorder.Code, tenantsRecorder.Body.String(), err)
+	}
+	if len(tenantsPayload.Tenants) != 2 {
+		t.Fatalf("expected every active tenant, got %+v", tenantsPayload.Tenants)
+	}
+
+	listRecorder := serve(server, http.MethodGet, "/api/notifications?tenant_id=tenant-bravo")
+	var listPayload notificationListPayload
+	if err := json.Unmarshal(listRecorder.Body.Bytes(), &listPayload); err != nil || listRecorder.Code != http.StatusOK {
+		t.Fatalf("expected the notification list, got %d %s (%v)", listRecorder.Code, listRecorder.Body.String(), err)
+	}
+	if stubSvc.lastTenantID != "tenant-bravo" || len(listPayload.Notifications) != 1 {
+		t.Fatalf("expected bravo notifications, got %q %+v", stubSvc.lastTenantID, listPayload.Notifications)
+	}
+	statusRecorder := serve(server, http.MethodGet, "/api/notifications/notif-1?tenant_id=tenant-bravo")
+	var statusPayload model.NotificationResponse
+	if err := json.Unmarshal(statusRecorder.Body.Bytes(), &statusPayload); err != nil || statusRecorder.Code != http.StatusOK {
+		t.Fatalf("expected the status read to succeed, got %d %s (%v)", statusRecorder.Code, statusRecorder.Body.String(), err)
+	}
+	for _, masked := range []model.NotificationResponse{listPayload.Notifications[0], statusPayload} {
+		if masked.Recipient != "u***@example.com" || masked.ReplyTo != "s******@example.com" || masked.CancelledBy != "o*******@example.com" {
+			t.Fatalf("expected every address masked for a service account, got %+v", masked)
+		}
+	}
+
+	for _, testCase := range []struct {
+		method string
+		path   string
+	}{
+		{method: http.MethodPost, path: "/api/notifications/notif-1/cancel?tenant_id=tenant-bravo"},
+		{method: http.MethodPatch, path: "/api/notifications/notif-1/schedule?tenant_id=tenant-bravo"},
+		{method: http.MethodPost, path: "/api/notifications?tenant_id=tenant-bravo"},
+		{method: http.MethodGet, path: "/api/admin/caches"},
+	} {
+		if recorder := serve(server, testCase.method, testCase.path); recorder.Code != http.StatusForbidden {
+			t.Fatalf("expected 403 for %s %s, got %d", testCase.method, testCase.path, recorder.Code)
+		}
+	}
+	if stubSvc.cancelCalls != 0 || stubSvc.rescheduleCalls != 0 || stubSvc.sendCalls != 0 {
+		t.Fatalf("service should not be called, got cancel=%d reschedule=%d send=%d", stubSvc.cancelCalls, stubSvc.rescheduleCalls, stubSvc.sendCalls)
+	}
+}
+
 func TestListNotificationsAllowsConfiguredAdminAcrossTenants(t *testing.T) {
 	t.Helper()
 
@@ -1015,146 +1286,468 @@ func TestHealthzBypassesTenantLookup(t *testing.T) {
 	}
 }
 
-func TestRescheduleValidation(t *testing.T) {
-	t.Helper()
-
-	server := newTestHTTPServer(t, &stubNotificationService{}, &stubValidator{})
+func TestHealthzReportsCertificateReloadWarning(t *testing.T) {
+	notAfter := time.Date(2027, 1, 2, 3, 4, 5, 0, time.UTC)
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	server, err := NewServer(Config{
+		ListenAddr:          ":0",
+		NotificationService: &stubNotificationService{},
+		SessionValidator:    &stubValidator{},
+		TenantRepository:    newTestTenantRepository(t),
+		Logger:              logger,
+		Certificates: map[string]CertificateStatusReporter{
+			"smtp_submission": stubCertificateReporter{status: certreload.Status{NotAfter: notAfter, ReloadError: "certreload: load certificate: bad pair"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("server init error: %v", err)
+	}
 
 	recorder := httptest.NewRecorder()
-	request := httptest.NewRequest(http.MethodPatch, "/api/notifications/notif-1/schedule", bytes.NewBufferString(`{}`))
-	request.Header.Set("Content-Type", "application/json")
-
-	server.httpServer.Handler.ServeHTTP(recorder, request)
-	if recorder.Code != http.StatusBadRequest {
-		t.Fatalf("expected 400, got %d", recorder.Code)
+	server.httpServer.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected a failed reload to keep healthz ok, got %d %s", recorder.Code, recorder.Body.String())
+	}
+	var payload struct {
+		Status  string `json:"status"`
+		Details struct {
+			TLSCertificates map[string]certificateHealth `json:"tls_certificates"`
+		} `json:"details"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode healthz: %v", err)
+	}
+	submission := payload.Details.TLSCertificates["smtp_submission"]
+	if payload.Status != "ok" || !submission.NotAfter.Equal(notAfter) || !strings.Contains(submission.Warning, "bad pair") {
+		t.Fatalf("unexpected healthz payload %s", recorder.Body.String())
 	}
 }
 
-func TestRescheduleNotificationRejectsEmptyID(t *testing.T) {
-	t.Helper()
-
-	stubSvc := &stubNotificationService{}
-	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
-
-	recorder := httptest.NewRecorder()
-	requestBody := `{"scheduled_time":"2024-01-02T15:04:05Z"}`
-	request := httptest.NewRequest(http.MethodPatch, "/api/notifications/%20/schedule", bytes.NewBufferString(requestBody))
-	request.Header.Set("Content-Type", "application/json")
-
-	server.httpServer.Handler.ServeHTTP(recorder, request)
+type stubOpenTracker struct {
+	validToken string
+	userAgents []string
+}
 
-	if recorder.Code != http.StatusBadRequest {
-		t.Fatalf("expected 400, got %d", recorder.Code)
-	}
-	if stubSvc.rescheduleCalls != 0 {
-		t.Fatalf("expected no service invocation, got %d", stubSvc.rescheduleCalls)
+func (tracker *stubOpenTracker) Open(_ context.Context, token string, userAgent string) error {
+	if token != tracker.validToken {
+		return opentracking.ErrNotTracked
 	}
+	tracker.userAgents = append(tracker.userAgents, userAgent)
+	return nil
 }
 
-func TestRescheduleNotificationRejectsPastSchedule(t *testing.T) {
-	t.Helper()
-
-	stubSvc := &stubNotificationService{}
-	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
+func TestOpenPixelServesGIFWithoutTenantOrSession(t *testing.T) {
+	tracker := &stubOpenTracker{validToken: "signed.token"}
+	server, err := NewServer(Config{
+		ListenAddr:          ":0",
+		NotificationService: &stubNotificationService{},
+		SessionValidator:    &stubValidator{err: errors.New("no session")},
+		TenantRepository:    newTestTenantRepository(t),
+		Logger:              slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
+		OpenTracker:         tracker,
+	})
+	if err != nil {
+		t.Fatalf("server init error: %v", err)
+	}
 
 	recorder := httptest.NewRecorder()
-	pastTime := time.Now().UTC().Add(-1 * time.Minute).Format(time.RFC3339)
-	requestBody := fmt.Sprintf(`{"scheduled_time":"%s"}`, pastTime)
-	request := httptest.NewRequest(http.MethodPatch, "/api/notifications/notif-1/schedule", bytes.NewBufferString(requestBody))
-	request.Header.Set("Content-Type", "application/json")
-
+	request := httptest.NewRequest(http.MethodGet, "/t/signed.token", nil)
+	request.Host = "unknown.localhost"
+	request.Header.Set("User-Agent", "Thunderbird/115.0")
 	server.httpServer.Handler.ServeHTTP(recorder, request)
-
-	if recorder.Code != http.StatusBadRequest {
-		t.Fatalf("expected 400, got %d", recorder.Code)
+	if recorder.Code != http.StatusOK || recorder.Header().Get("Content-Type") != "image/gif" || !bytes.Equal(recorder.Body.Bytes(), opentracking.PixelGIF) {
+		t.Fatalf("expected the pixel GIF, got %d %q", recorder.Code, recorder.Header().Get("Content-Type"))
 	}
-	if stubSvc.rescheduleCalls != 0 {
-		t.Fatalf("expected no service invocation, got %d", stubSvc.rescheduleCalls)
+	if !strings.Contains(recorder.Header().Get("Cache-Control"), "no-store") || len(tracker.userAgents) != 1 {
+		t.Fatalf("expected an uncached, recorded open, got %q and %v", recorder.Header().Get("Cache-Control"), tracker.userAgents)
 	}
-}
-
-func TestRescheduleNotificationRejectsInvalidPayloadAndTimestamp(t *testing.T) {
-	t.Helper()
-	server := newTestHTTPServer(t, &stubNotificationService{}, &stubValidator{})
 
-	testCases := []struct {
-		name string
-		body string
-	}{
-		{name: "invalid json", body: `{`},
-		{name: "invalid timestamp", body: `{"scheduled_time":"not-a-time"}`},
-	}
-	for _, testCase := range testCases {
-		testCase := testCase
-		t.Run(testCase.name, func(t *testing.T) {
-			recorder := httptest.NewRecorder()
-			request := httptest.NewRequest(http.MethodPatch, "/api/notifications/notif-1/schedule", strings.NewReader(testCase.body))
-			request.Header.Set("Content-Type", "application/json")
-			server.httpServer.Handler.ServeHTTP(recorder, request)
-			if recorder.Code != http.StatusBadRequest {
-				t.Fatalf("expected 400, got %d body=%s", recorder.Code, recorder.Body.String())
-			}
-		})
+	recorder = httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/t/guessed.token", nil))
+	if recorder.Code != http.StatusNotFound || len(tracker.userAgents) != 1 {
+		t.Fatalf("expected 404 for an untracked token, got %d", recorder.Code)
 	}
 }
 
-func TestRescheduleNotificationRequiresTenantID(t *testing.T) {
-	t.Helper()
-
-	stubSvc := &stubNotificationService{}
-	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
-
-	requestBody := fmt.Sprintf(`{"scheduled_time":"%s"}`, time.Now().UTC().Add(5*time.Minute).Format(time.RFC3339))
+func TestOpenPixelRouteAbsentWithoutTracker(t *testing.T) {
+	server := newTestHTTPServerWithRepo(t, &stubNotificationService{}, &stubValidator{}, newTestTenantRepository(t))
 	recorder := httptest.NewRecorder()
-	request := httptest.NewRequest(http.MethodPatch, "/api/notifications/notif-1/schedule", bytes.NewBufferString(requestBody))
-	request.Header.Set("Content-Type", "application/json")
-
+	request := httptest.NewRequest(http.MethodGet, "/t/signed.token", nil)
+	request.Host = "unknown.localhost"
 	server.httpServer.Handler.ServeHTTP(recorder, request)
-	if recorder.Code != http.StatusBadRequest {
-		t.Fatalf("expected 400, got %d", recorder.Code)
-	}
-	if stubSvc.rescheduleCalls != 0 {
-		t.Fatalf("expected no service invocation, got %d", stubSvc.rescheduleCalls)
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 when open tracking is off, got %d", recorder.Code)
 	}
 }
 
-func TestRescheduleNotificationUsesTenantID(t *testing.T) {
-	t.Helper()
-
-	repo := newMultiTenantRepository(t)
-	stubSvc := &stubNotificationService{}
-	server := newTestHTTPServerWithRepo(t, stubSvc, &stubValidator{}, repo)
+func TestSchemaEndpointServesCatalogWithoutTenantOrSession(t *testing.T) {
+	repo := newTestTenantRepository(t)
+	server := newTestHTTPServerWithRepo(t, &stubNotificationService{}, &stubValidator{err: errors.New("no session")}, repo)
 
-	requestBody := fmt.Sprintf(`{"scheduled_time":"%s"}`, time.Now().UTC().Add(5*time.Minute).Format(time.RFC3339))
 	recorder := httptest.NewRecorder()
-	request := httptest.NewRequest(http.MethodPatch, "/api/notifications/notif-1/schedule?tenant_id=tenant-bravo", bytes.NewBufferString(requestBody))
+	request := httptest.NewRequest(http.MethodGet, "/api/schema", nil)
 	request.Host = "unknown.localhost"
-	request.Header.Set("Content-Type", "application/json")
-
 	server.httpServer.Handler.ServeHTTP(recorder, request)
 	if recorder.Code != http.StatusOK {
-		t.Fatalf("expected 200, got %d", recorder.Code)
+		t.Fatalf("expected 200 for schema, got %d %s", recorder.Code, recorder.Body.String())
 	}
-	if stubSvc.lastTenantID != "tenant-bravo" {
-		t.Fatalf("expected tenant-bravo, got %s", stubSvc.lastTenantID)
+	var catalog schema.Catalog
+	if err := json.Unmarshal(recorder.Body.Bytes(), &catalog); err != nil {
+		t.Fatalf("decode schema: %v", err)
+	}
+	if catalog.Version != schema.Version || catalog.NotificationResponse.Properties["notification_id"] == nil || catalog.WebhookEvent.Properties["event_id"] == nil {
+		t.Fatalf("unexpected schema catalog: %s", recorder.Body.String())
 	}
 }
 
-func TestRescheduleNotificationMapsMissingIDErrorToBadRequest(t *testing.T) {
-	t.Helper()
-
-	stubSvc := &stubNotificationService{rescheduleErr: fmt.Errorf("missing notification_id")}
+func TestDrainingInstanceFailsHealthzAndSends(t *testing.T) {
+	stubSvc := &stubNotificationService{draining: true, sendErr: service.ErrDraining}
 	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
 
 	recorder := httptest.NewRecorder()
-	requestBody := `{"scheduled_time":"2024-01-02T15:04:05Z"}`
-	request := httptest.NewRequest(http.MethodPatch, "/api/notifications/notif-1/schedule?tenant_id=tenant-test", bytes.NewBufferString(requestBody))
-	request.Header.Set("Content-Type", "application/json")
+	server.httpServer.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if recorder.Code != http.StatusServiceUnavailable || !strings.Contains(recorder.Body.String(), "draining") {
+		t.Fatalf("expected healthz to report draining, got %d %s", recorder.Code, recorder.Body.String())
+	}
 
+	recorder = httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/api/notifications?tenant_id=tenant-test", strings.NewReader(`{"notification_type":"email","recipient":"a@example.com","message":"b"}`))
+	request.Header.Set("Content-Type", "application/json")
 	server.httpServer.Handler.ServeHTTP(recorder, request)
-
-	if recorder.Code != http.StatusBadRequest {
-		t.Fatalf("expected 400, got %d", recorder.Code)
+	if recorder.Code != http.StatusServiceUnavailable || recorder.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected 503 with Retry-After while draining, got %d %v", recorder.Code, recorder.Header())
+	}
+}
+
+func TestListNotificationsMapsDatabaseBusyToUnavailable(t *testing.T) {
+	stubSvc := &stubNotificationService{listErr: fmt.Errorf("list: %w", model.ErrDatabaseBusy)}
+	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
+
+	recorder := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/notifications?tenant_id=tenant-test", nil))
+	if recorder.Code != http.StatusServiceUnavailable || recorder.Header().Get("Retry-After") != databaseBusyRetryAfterSeconds {
+		t.Fatalf("expected 503 with Retry-After when the database is busy, got %d %v", recorder.Code, recorder.Header())
+	}
+	if strings.Contains(recorder.Body.String(), "SQLITE") {
+		t.Fatalf("expected no raw driver error in the response, got %s", recorder.Body.String())
+	}
+}
+
+func TestListNotificationsMapsQueryTimeoutToGatewayTimeout(t *testing.T) {
+	stubSvc := &stubNotificationService{listErr: fmt.Errorf("list: %w: %w", model.ErrQueryTimeout, context.DeadlineExceeded)}
+	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
+
+	recorder := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/notifications?tenant_id=tenant-test", nil))
+	if recorder.Code != http.StatusGatewayTimeout || !strings.Contains(recorder.Body.String(), "database query timed out") {
+		t.Fatalf("expected 504 when a query times out, got %d %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestListNotificationsRejectsUnknownStatusInStrictMode(t *testing.T) {
+	stubSvc := &stubNotificationService{listErr: fmt.Errorf("%w: %q", model.ErrUnknownNotificationStatus, "quued")}
+	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
+
+	recorder := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/notifications?tenant_id=tenant-test&status=Quued", nil))
+	if recorder.Code != http.StatusBadRequest || !strings.Contains(recorder.Body.String(), `unknown notification status: \"quued\"`) {
+		t.Fatalf("expected 400 naming the unknown status, got %d %s", recorder.Code, recorder.Body.String())
+	}
+	if len(stubSvc.lastListFilters.Statuses) != 1 || stubSvc.lastListFilters.Statuses[0] != "quued" {
+		t.Fatalf("expected the unknown status to reach the service, got %v", stubSvc.lastListFilters.Statuses)
+	}
+}
+
+func TestSavedFilterLifecycleAppliesFilterToList(t *testing.T) {
+	stubSvc := &stubNotificationService{}
+	server, _ := newTestHTTPServerWithSavedFilters(t, stubSvc, &stubValidator{})
+
+	createRecorder := serveSavedFilterRequest(server, http.MethodPost, "/api/filters?tenant_id=tenant-test", `{"name":"Errored SMS","status":["errored","queued"],"q":"sms"}`)
+	if createRecorder.Code != http.StatusCreated {
+		t.Fatalf("expected create 201, got %d body=%s", createRecorder.Code, createRecorder.Body.String())
+	}
+	var created savedfilter.PublicFilter
+	if err := json.Unmarshal(createRecorder.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode create payload: %v", err)
+	}
+	if created.ID == "" || created.Name != "Errored SMS" || strings.Join(created.Statuses, ",") != "errored,queued" || created.OwnerEmail != "user@example.com" {
+		t.Fatalf("unexpected created filter %+v", created)
+	}
+
+	listRecorder := serveSavedFilterRequest(server, http.MethodGet, "/api/filters?tenant_id=tenant-test", "")
+	if listRecorder.Code != http.StatusOK || !strings.Contains(listRecorder.Body.String(), created.ID) {
+		t.Fatalf("expected the filter in the list, got %d body=%s", listRecorder.Code, listRecorder.Body.String())
+	}
+
+	notificationsRecorder := serveSavedFilterRequest(server, http.MethodGet, "/api/notifications?tenant_id=tenant-test&limit=10&filter_id="+created.ID, "")
+	if notificationsRecorder.Code != http.StatusOK {
+		t.Fatalf("expected filtered list 200, got %d body=%s", notificationsRecorder.Code, notificationsRecorder.Body.String())
+	}
+	if len(stubSvc.lastListFilters.Statuses) != 2 || stubSvc.lastListFilters.Statuses[0] != model.StatusErrored || stubSvc.lastListFilters.SearchQuery.Value() != "sms" {
+		t.Fatalf("expected the saved filter applied, got %+v", stubSvc.lastListFilters)
+	}
+	if stubSvc.lastPageRequest.Limit() != 10 {
+		t.Fatalf("expected paging parameters to still apply, got %+v", stubSvc.lastPageRequest)
+	}
+
+	updateRecorder := serveSavedFilterRequest(server, http.MethodPatch, "/api/filters/"+created.ID+"?tenant_id=tenant-test", `{"name":"Cancelled","status":["cancelled"],"shared":true}`)
+	if updateRecorder.Code != http.StatusOK || !strings.Contains(updateRecorder.Body.String(), `"shared":true`) {
+		t.Fatalf("expected update 200, got %d body=%s", updateRecorder.Code, updateRecorder.Body.String())
+	}
+
+	deleteRecorder := serveSavedFilterRequest(server, http.MethodDelete, "/api/filters/"+created.ID+"?tenant_id=tenant-test", "")
+	if deleteRecorder.Code != http.StatusNoContent {
+		t.Fatalf("expected delete 204, got %d body=%s", deleteRecorder.Code, deleteRecorder.Body.String())
+	}
+	missingRecorder := serveSavedFilterRequest(server, http.MethodGet, "/api/notifications?tenant_id=tenant-test&filter_id="+created.ID, "")
+	if missingRecorder.Code != http.StatusNotFound {
+		t.Fatalf("expected a deleted filter_id to return 404, got %d", missingRecorder.Code)
+	}
+}
+
+func TestSavedFiltersShowOwnAndSharedFiltersOnly(t *testing.T) {
+	stubSvc := &stubNotificationService{}
+	validator := &stubValidator{}
+	server, _ := newTestHTTPServerWithSavedFilters(t, stubSvc, validator)
+
+	shared := createSavedFilterForTest(t, server, `{"name":"Team errors","status":["errored"],"shared":true}`)
+	private := createSavedFilterForTest(t, server, `{"name":"Admin only","status":["sent"]}`)
+
+	validator.email = "viewer@example.com"
+	validator.roles = []string{"viewer"}
+	if recorder := serveSavedFilterRequest(server, http.MethodPost, "/api/filters?tenant_id=tenant-test", `{"name":"Mine","shared":true}`); recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected sharing by a non-admin to return 403, got %d", recorder.Code)
+	}
+	own := createSavedFilterForTest(t, server, `{"name":"Mine","q":"invoice"}`)
+
+	listRecorder := serveSavedFilterRequest(server, http.MethodGet, "/api/filters?tenant_id=tenant-test", "")
+	var listPayload struct {
+		Filters []savedfilter.PublicFilter `json:"filters"`
+	}
+	if err := json.Unmarshal(listRecorder.Body.Bytes(), &listPayload); err != nil {
+		t.Fatalf("decode list payload: %v", err)
+	}
+	if len(listPayload.Filters) != 2 || listPayload.Filters[0].ID != own.ID || listPayload.Filters[1].ID != shared.ID {
+		t.Fatalf("expected own and shared filters only, got %+v", listPayload.Filters)
+	}
+
+	if recorder := serveSavedFilterRequest(server, http.MethodGet, "/api/notifications?tenant_id=tenant-test&filter_id="+shared.ID, ""); recorder.Code != http.StatusOK || stubSvc.lastListFilters.Statuses[0] != model.StatusErrored {
+		t.Fatalf("expected a shared filter to apply for a tenant user, got %d %+v", recorder.Code, stubSvc.lastListFilters)
+	}
+	if recorder := serveSavedFilterRequest(server, http.MethodGet, "/api/notifications?tenant_id=tenant-test&filter_id="+private.ID, ""); recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected another user's private filter to return 404, got %d", recorder.Code)
+	}
+	if recorder := serveSavedFilterRequest(server, http.MethodPatch, "/api/filters/"+shared.ID+"?tenant_id=tenant-test", `{"name":"Hijacked"}`); recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected shared filter update by a non-owner to return 403, got %d", recorder.Code)
+	}
+	if recorder := serveSavedFilterRequest(server, http.MethodDelete, "/api/filters/"+shared.ID+"?tenant_id=tenant-test", ""); recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected shared filter delete by a non-owner to return 403, got %d", recorder.Code)
+	}
+	if recorder := serveSavedFilterRequest(server, http.MethodDelete, "/api/filters/"+private.ID+"?tenant_id=tenant-test", ""); recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected another user's private filter delete to return 404, got %d", recorder.Code)
+	}
+
+	validator.email = "viewer@outside.example"
+	if recorder := serveSavedFilterRequest(server, http.MethodGet, "/api/filters?tenant_id=tenant-test", ""); recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected a tenant outside the user's domain to return 403, got %d", recorder.Code)
+	}
+}
+
+func TestSavedFilterValidationAndErrorMapping(t *testing.T) {
+	server, filterRepo := newTestHTTPServerWithSavedFilters(t, &stubNotificationService{}, &stubValidator{})
+	filterRepo.WithOwnerLimit(2)
+	existing := createSavedFilterForTest(t, server, `{"name":"Existing"}`)
+
+	testCases := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+		expectedError  string
+	}{
+		{name: "missing tenant", method: http.MethodGet, path: "/api/filters", expectedStatus: http.StatusBadRequest, expectedError: "tenant_id is required"},
+		{name: "unknown tenant", method: http.MethodGet, path: "/api/filters?tenant_id=tenant-missing", expectedStatus: http.StatusNotFound, expectedError: "tenant not found"},
+		{name: "invalid json", method: http.MethodPost, path: "/api/filters?tenant_id=tenant-test", body: `{`, expectedStatus: http.StatusBadRequest},
+		{name: "missing name", method: http.MethodPost, path: "/api/filters?tenant_id=tenant-test", body: `{"status":["queued"]}`, expectedStatus: http.StatusBadRequest, expectedError: "name is required"},
+		{name: "unknown status", method: http.MethodPost, path: "/api/filters?tenant_id=tenant-test", body: `{"name":"Bad","status":["delivered"]}`, expectedStatus: http.StatusBadRequest, expectedError: `unknown status \"delivered\"`},
+		{name: "search too long", method: http.MethodPost, path: "/api/filters?tenant_id=tenant-test", body: `{"name":"Bad","q":"` + strings.Repeat("q", 201) + `"}`, expectedStatus: http.StatusBadRequest, expectedError: "invalid notification search query"},
+		{name: "duplicate name", method: http.MethodPost, path: "/api/filters?tenant_id=tenant-test", body: `{"name":"Existing"}`, expectedStatus: http.StatusConflict, expectedError: "already exists"},
+		{name: "unknown filter update", method: http.MethodPatch, path: "/api/filters/missing?tenant_id=tenant-test", body: `{"name":"Other"}`, expectedStatus: http.StatusNotFound, expectedError: "saved filter not found"},
+		{name: "filter with status", method: http.MethodGet, path: "/api/notifications?tenant_id=tenant-test&status=sent&filter_id=" + existing.ID, expectedStatus: http.StatusBadRequest, expectedError: "cannot be combined"},
+		{name: "unknown filter id", method: http.MethodGet, path: "/api/notifications?tenant_id=tenant-test&filter_id=missing", expectedStatus: http.StatusNotFound, expectedError: "saved filter not found"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			recorder := serveSavedFilterRequest(server, testCase.method, testCase.path, testCase.body)
+			if recorder.Code != testCase.expectedStatus || !strings.Contains(recorder.Body.String(), testCase.expectedError) {
+				t.Fatalf("expected %d mentioning %q, got %d body=%s", testCase.expectedStatus, testCase.expectedError, recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+
+	createSavedFilterForTest(t, server, `{"name":"Second"}`)
+	limitRecorder := serveSavedFilterRequest(server, http.MethodPost, "/api/filters?tenant_id=tenant-test", `{"name":"Third"}`)
+	if limitRecorder.Code != http.StatusConflict || !strings.Contains(limitRecorder.Body.String(), "at most 2 filters per user") {
+		t.Fatalf("expected the per-user limit to return 409, got %d body=%s", limitRecorder.Code, limitRecorder.Body.String())
+	}
+}
+
+func TestSavedFiltersDisabledWithoutRepository(t *testing.T) {
+	stubSvc := &stubNotificationService{}
+	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
+
+	if recorder := serveSavedFilterRequest(server, http.MethodGet, "/api/filters?tenant_id=tenant-test", ""); recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected /api/filters to be unrouted without a repository, got %d", recorder.Code)
+	}
+	recorder := serveSavedFilterRequest(server, http.MethodGet, "/api/notifications?tenant_id=tenant-test&filter_id=any", "")
+	if recorder.Code != http.StatusBadRequest || stubSvc.listCalls != 0 {
+		t.Fatalf("expected filter_id to be rejected without a repository, got %d after %d list calls", recorder.Code, stubSvc.listCalls)
+	}
+}
+
+func TestRescheduleValidation(t *testing.T) {
+	t.Helper()
+
+	server := newTestHTTPServer(t, &stubNotificationService{}, &stubValidator{})
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPatch, "/api/notifications/notif-1/schedule", bytes.NewBufferString(`{}`))
+	request.Header.Set("Content-Type", "application/json")
+
+	server.httpServer.Handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", recorder.Code)
+	}
+}
+
+func TestRescheduleNotificationRejectsEmptyID(t *testing.T) {
+	t.Helper()
+
+	stubSvc := &stubNotificationService{}
+	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
+
+	recorder := httptest.NewRecorder()
+	requestBody := `{"scheduled_time":"2024-01-02T15:04:05Z"}`
+	request := httptest.NewRequest(http.MethodPatch, "/api/notifications/%20/schedule", bytes.NewBufferString(requestBody))
+	request.Header.Set("Content-Type", "application/json")
+
+	server.httpServer.Handler.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", recorder.Code)
+	}
+	if stubSvc.rescheduleCalls != 0 {
+		t.Fatalf("expected no service invocation, got %d", stubSvc.rescheduleCalls)
+	}
+}
+
+// TestRescheduleNotificationLeavesPastScheduleToService pins that a past time reaches the
+// service, whose clock decides what "past" means, and its sentinel maps to 400.
+func TestRescheduleNotificationLeavesPastScheduleToService(t *testing.T) {
+	t.Helper()
+
+	stubSvc := &stubNotificationService{rescheduleErr: service.ErrScheduleInPast}
+	server := newTestHTTPServerWithRepo(t, stubSvc, &stubValidator{}, newMultiTenantRepository(t))
+
+	recorder := httptest.NewRecorder()
+	pastTime := time.Now().UTC().Add(-1 * time.Minute).Format(time.RFC3339)
+	requestBody := fmt.Sprintf(`{"scheduled_time":"%s"}`, pastTime)
+	request := httptest.NewRequest(http.MethodPatch, "/api/notifications/notif-1/schedule?tenant_id=tenant-bravo", bytes.NewBufferString(requestBody))
+	request.Header.Set("Content-Type", "application/json")
+
+	server.httpServer.Handler.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusBadRequest || !strings.Contains(recorder.Body.String(), scheduledTimeFutureError) {
+		t.Fatalf("expected 400 %q, got %d %s", scheduledTimeFutureError, recorder.Code, recorder.Body.String())
+	}
+	if stubSvc.rescheduleCalls != 1 {
+		t.Fatalf("expected the service to decide the past schedule, got %d calls", stubSvc.rescheduleCalls)
+	}
+}
+
+func TestRescheduleNotificationRejectsInvalidPayloadAndTimestamp(t *testing.T) {
+	t.Helper()
+	server := newTestHTTPServer(t, &stubNotificationService{}, &stubValidator{})
+
+	testCases := []struct {
+		name string
+		body string
+	}{
+		{name: "invalid json", body: `{`},
+		{name: "invalid timestamp", body: `{"scheduled_time":"not-a-time"}`},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPatch, "/api/notifications/notif-1/schedule", strings.NewReader(testCase.body))
+			request.Header.Set("Content-Type", "application/json")
+			server.httpServer.Handler.ServeHTTP(recorder, request)
+			if recorder.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d body=%s", recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestRescheduleNotificationRequiresTenantID(t *testing.T) {
+	t.Helper()
+
+	stubSvc := &stubNotificationService{}
+	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
+
+	requestBody := fmt.Sprintf(`{"scheduled_time":"%s"}`, time.Now().UTC().Add(5*time.Minute).Format(time.RFC3339))
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPatch, "/api/notifications/notif-1/schedule", bytes.NewBufferString(requestBody))
+	request.Header.Set("Content-Type", "application/json")
+
+	server.httpServer.Handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", recorder.Code)
+	}
+	if stubSvc.rescheduleCalls != 0 {
+		t.Fatalf("expected no service invocation, got %d", stubSvc.rescheduleCalls)
+	}
+}
+
+func TestRescheduleNotificationUsesTenantID(t *testing.T) {
+	t.Helper()
+
+	repo := newMultiTenantRepository(t)
+	stubSvc := &stubNotificationService{}
+	server := newTestHTTPServerWithRepo(t, stubSvc, &stubValidator{}, repo)
+
+	requestBody := fmt.Sprintf(`{"scheduled_time":"%s"}`, time.Now().UTC().Add(5*time.Minute).Format(time.RFC3339))
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPatch, "/api/notifications/notif-1/schedule?tenant_id=tenant-bravo", bytes.NewBufferString(requestBody))
+	request.Host = "unknown.localhost"
+	request.Header.Set("Content-Type", "application/json")
+
+	server.httpServer.Handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", recorder.Code)
+	}
+	if stubSvc.lastTenantID != "tenant-bravo" {
+		t.Fatalf("expected tenant-bravo, got %s", stubSvc.lastTenantID)
+	}
+}
+
+func TestRescheduleNotificationMapsMissingIDErrorToBadRequest(t *testing.T) {
+	t.Helper()
+
+	stubSvc := &stubNotificationService{rescheduleErr: fmt.Errorf("reschedule: %w", service.ErrMissingNotificationID)}
+	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
+
+	recorder := httptest.NewRecorder()
+	requestBody := `{"scheduled_time":"2024-01-02T15:04:05Z"}`
+	request := httptest.NewRequest(http.MethodPatch, "/api/notifications/notif-1/schedule?tenant_id=tenant-test", bytes.NewBufferString(requestBody))
+	request.Header.Set("Content-Type", "application/json")
+
+	server.httpServer.Handler.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", recorder.Code)
 	}
 }
 
@@ -1166,6 +1759,10 @@ func TestRescheduleNotificationErrorMapping(t *testing.T) {
 		expectedCode int
 	}{
 		{name: "Conflict", err: service.ErrNotificationNotEditable, expectedCode: http.StatusConflict},
+		{name: "PastExpiry", err: service.ErrScheduleAfterExpiry, expectedCode: http.StatusBadRequest},
+		{name: "AttachmentDataNotPersisted", err: service.ErrAttachmentDataNotPersisted, expectedCode: http.StatusUnprocessableEntity},
+		{name: "ScheduleBeyondHorizon", err: service.ErrScheduleBeyondHorizon, expectedCode: http.StatusBadRequest},
+		{name: "ScheduleInPast", err: service.ErrScheduleInPast, expectedCode: http.StatusBadRequest},
 		{name: "NotFound", err: gorm.ErrRecordNotFound, expectedCode: http.StatusNotFound},
 		{name: "Internal", err: errors.New("boom"), expectedCode: http.StatusInternalServerError},
 	}
@@ -1187,6 +1784,47 @@ func TestRescheduleNotificationErrorMapping(t *testing.T) {
 	}
 }
 
+// relabeledError reports a sentinel under an unrelated message, as if its text had been reworded.
+type relabeledError struct {
+	message  string
+	sentinel error
+}
+
+func (relabeled relabeledError) Error() string { return relabeled.message }
+
+func (relabeled relabeledError) Unwrap() error { return relabeled.sentinel }
+
+func TestWriteErrorMatchesSentinelsNotMessages(t *testing.T) {
+	testCases := []struct {
+		sentinel     error
+		expectedCode int
+	}{
+		{sentinel: service.ErrMissingNotificationID, expectedCode: http.StatusBadRequest},
+		{sentinel: service.ErrNotificationNotEditable, expectedCode: http.StatusConflict},
+		{sentinel: service.ErrScheduleInPast, expectedCode: http.StatusBadRequest},
+		{sentinel: model.ErrNotificationNotFound, expectedCode: http.StatusNotFound},
+	}
+	for _, testCase := range testCases {
+		for _, message := range []string{testCase.sentinel.Error(), "Missing Notification ID", "something else entirely"} {
+			stubSvc := &stubNotificationService{cancelErr: relabeledError{message: message, sentinel: testCase.sentinel}}
+			server := newTestHTTPServer(t, stubSvc, &stubValidator{})
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/api/notifications/notif-1/cancel?tenant_id=tenant-test", nil)
+			server.httpServer.Handler.ServeHTTP(recorder, request)
+			if recorder.Code != testCase.expectedCode {
+				t.Fatalf("%v reworded as %q: expected %d, got %d", testCase.sentinel, message, testCase.expectedCode, recorder.Code)
+			}
+		}
+	}
+	stubSvc := &stubNotificationService{cancelErr: errors.New("missing notification_id")}
+	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
+	recorder := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/notifications/notif-1/cancel?tenant_id=tenant-test", nil))
+	if recorder.Code != http.StatusInternalServerError {
+		t.Fatalf("expected a bare message to no longer map to a client error, got %d", recorder.Code)
+	}
+}
+
 func TestCancelNotificationErrorMapping(t *testing.T) {
 	t.Helper()
 
@@ -1197,7 +1835,7 @@ func TestCancelNotificationErrorMapping(t *testing.T) {
 	}{
 		{
 			name:         "MissingNotificationID",
-			cancelError:  fmt.Errorf("missing notification_id"),
+			cancelError:  service.ErrMissingNotificationID,
 			expectedCode: http.StatusBadRequest,
 		},
 		{
@@ -1210,6 +1848,11 @@ func TestCancelNotificationErrorMapping(t *testing.T) {
 			cancelError:  model.ErrNotificationNotFound,
 			expectedCode: http.StatusNotFound,
 		},
+		{
+			name:         "InvalidReason",
+			cancelError:  fmt.Errorf("%w: reason contains control characters", model.ErrCancelReasonInvalid),
+			expectedCode: http.StatusBadRequest,
+		},
 		{
 			name:         "Internal",
 			cancelError:  errors.New("boom"),
@@ -1250,32 +1893,135 @@ func TestCancelNotificationSuccess(t *testing.T) {
 	}
 }
 
-func TestCancelNotificationRejectsEmptyID(t *testing.T) {
-	t.Helper()
-
+func TestCancelNotificationPassesReasonAndSessionActor(t *testing.T) {
 	stubSvc := &stubNotificationService{}
 	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
 
 	recorder := httptest.NewRecorder()
-	request := httptest.NewRequest(http.MethodPost, "/api/notifications/%20/cancel", nil)
-
+	request := httptest.NewRequest(http.MethodPost, "/api/notifications/notif-1/cancel?tenant_id=tenant-test", strings.NewReader(`{"reason":"duplicate"}`))
+	request.Header.Set("Content-Type", "application/json")
 	server.httpServer.Handler.ServeHTTP(recorder, request)
-
-	if recorder.Code != http.StatusBadRequest {
-		t.Fatalf("expected 400, got %d", recorder.Code)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", recorder.Code, recorder.Body.String())
 	}
-	if stubSvc.cancelCalls != 0 {
-		t.Fatalf("expected no service invocation, got %d", stubSvc.cancelCalls)
+	if stubSvc.lastCancelReason != "duplicate" || stubSvc.lastCancelActor != "user@example.com" {
+		t.Fatalf("unexpected reason %q or actor %q", stubSvc.lastCancelReason, stubSvc.lastCancelActor)
 	}
 }
 
-func TestCancelNotificationRequiresTenantID(t *testing.T) {
-	t.Helper()
+func TestSetLegalHoldRequiresAdmin(t *testing.T) {
 	stubSvc := &stubNotificationService{}
 	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
 
 	recorder := httptest.NewRecorder()
-	request := httptest.NewRequest(http.MethodPost, "/api/notifications/notif-1/cancel", nil)
+	request := httptest.NewRequest(http.MethodPatch, "/api/notifications/notif-1/legal-hold?tenant_id=tenant-test", strings.NewReader(`{"legal_hold":true}`))
+	request.Header.Set("Content-Type", "application/json")
+	server.httpServer.Handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK || stubSvc.legalHoldCalls != 1 || !stubSvc.lastLegalHold || stubSvc.lastLegalHoldActor != "user@example.com" {
+		t.Fatalf("expected an audited hold, got %d calls=%d held=%v actor=%q", recorder.Code, stubSvc.legalHoldCalls, stubSvc.lastLegalHold, stubSvc.lastLegalHoldActor)
+	}
+
+	recorder = httptest.NewRecorder()
+	request = httptest.NewRequest(http.MethodPatch, "/api/notifications/notif-1/legal-hold?tenant_id=tenant-test", strings.NewReader(`{}`))
+	request.Header.Set("Content-Type", "application/json")
+	server.httpServer.Handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without legal_hold, got %d", recorder.Code)
+	}
+
+	memberServer := newTestHTTPServerWithRepo(t, stubSvc, &stubValidator{email: "member@alpha.localhost", roles: []string{"user"}}, newMultiTenantRepository(t))
+	recorder = httptest.NewRecorder()
+	request = httptest.NewRequest(http.MethodPatch, "/api/notifications/notif-1/legal-hold?tenant_id=tenant-alpha", strings.NewReader(`{"legal_hold":false}`))
+	request.Header.Set("Content-Type", "application/json")
+	memberServer.httpServer.Handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusForbidden || stubSvc.legalHoldCalls != 1 {
+		t.Fatalf("expected members to be refused, got %d calls=%d", recorder.Code, stubSvc.legalHoldCalls)
+	}
+}
+
+func TestAdminCacheEndpointsRequireSuperAdmin(t *testing.T) {
+	stubSvc := &stubNotificationService{cacheStates: []service.CacheState{{Name: service.CacheTenantRuntime, Entries: 2, Hits: 5, Misses: 1}}}
+	memberServer := newTestHTTPServer(t, stubSvc, &stubValidator{roles: []string{"user"}})
+	for _, request := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/api/admin/caches", nil),
+		httptest.NewRequest(http.MethodPost, "/api/admin/caches/flush", nil),
+	} {
+		recorder := httptest.NewRecorder()
+		memberServer.httpServer.Handler.ServeHTTP(recorder, request)
+		if recorder.Code != http.StatusForbidden {
+			t.Fatalf("expected %s %s to refuse members, got %d", request.Method, request.URL.Path, recorder.Code)
+		}
+	}
+	if stubSvc.flushActor != "" {
+		t.Fatalf("expected no flush, got one by %q", stubSvc.flushActor)
+	}
+
+	server := newTestHTTPServer(t, stubSvc, &stubValidator{email: "ops@example.com"})
+	recorder := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/admin/caches", nil))
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), `{"name":"tenant_runtime","entries":2,"hits":5,"misses":1}`) {
+		t.Fatalf("expected cache states, got %d %s", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/admin/caches/flush", nil))
+	if recorder.Code != http.StatusOK || stubSvc.flushActor != "ops@example.com" {
+		t.Fatalf("expected a flush attributed to the session, got %d actor=%q", recorder.Code, stubSvc.flushActor)
+	}
+}
+
+func TestGetNotificationIncludesRenderedOnRequest(t *testing.T) {
+	stubSvc := &stubNotificationService{statusResponse: model.NotificationResponse{NotificationID: "notif-1", Rendered: &model.RenderedContent{Message: "Body", MIMEStructure: model.MIMEStructurePlain, SizeBytes: 120}}}
+	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
+
+	recorder := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/notifications/notif-1?tenant_id=tenant-test", nil))
+	if recorder.Code != http.StatusOK || stubSvc.lastStatusID != "notif-1" || stubSvc.renderedCalls != 0 {
+		t.Fatalf("expected a plain status lookup, got %d (%d rendered calls)", recorder.Code, stubSvc.renderedCalls)
+	}
+
+	recorder = httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/notifications/notif-1?tenant_id=tenant-test&include_rendered=true", nil))
+	if recorder.Code != http.StatusOK || stubSvc.renderedCalls != 1 {
+		t.Fatalf("expected a rendered lookup, got %d (%d rendered calls)", recorder.Code, stubSvc.renderedCalls)
+	}
+	if !strings.Contains(recorder.Body.String(), `"mime_structure":"plain"`) {
+		t.Fatalf("expected rendered content in body, got %s", recorder.Body.String())
+	}
+
+	recorder = httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/notifications/notif-1?tenant_id=tenant-test&include_rendered=maybe", nil))
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an invalid include_rendered, got %d", recorder.Code)
+	}
+}
+
+func TestCancelNotificationRejectsEmptyID(t *testing.T) {
+	t.Helper()
+
+	stubSvc := &stubNotificationService{}
+	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/api/notifications/%20/cancel", nil)
+
+	server.httpServer.Handler.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", recorder.Code)
+	}
+	if stubSvc.cancelCalls != 0 {
+		t.Fatalf("expected no service invocation, got %d", stubSvc.cancelCalls)
+	}
+}
+
+func TestCancelNotificationRequiresTenantID(t *testing.T) {
+	t.Helper()
+	stubSvc := &stubNotificationService{}
+	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/api/notifications/notif-1/cancel", nil)
 
 	server.httpServer.Handler.ServeHTTP(recorder, request)
 	if recorder.Code != http.StatusBadRequest {
@@ -1402,6 +2148,46 @@ func TestRequestLoggerStopsForwardedChainAtTrustBoundary(t *testing.T) {
 	}
 }
 
+func TestDebugOverrideLogsOnlyTheFlaggedRequest(t *testing.T) {
+	var logBuffer bytes.Buffer
+	logger := slog.New(logging.NewDebugOverrideHandler(slog.NewTextHandler(&logBuffer, &slog.HandlerOptions{Level: slog.LevelDebug}), slog.LevelInfo))
+	newServer := func(validator SessionValidator) *Server {
+		server, err := NewServer(Config{
+			ListenAddr:          ":0",
+			NotificationService: &stubNotificationService{sendResponse: model.NotificationResponse{NotificationID: "notif-1"}},
+			SessionValidator:    validator,
+			TenantRepository:    newTestTenantRepository(t),
+			Logger:              logger,
+		})
+		if err != nil {
+			t.Fatalf("server init error: %v", err)
+		}
+		return server
+	}
+	send := func(server *Server, debugHeader string) {
+		request := httptest.NewRequest(http.MethodPost, "/api/notifications?tenant_id=tenant-test",
+			strings.NewReader(`{"notification_type":"email","recipient":"a@example.com","message":"b"}`))
+		request.Header.Set("Content-Type", "application/json")
+		if debugHeader != "" {
+			request.Header.Set("X-Debug", debugHeader)
+		}
+		server.httpServer.Handler.ServeHTTP(httptest.NewRecorder(), request)
+	}
+
+	server := newServer(&stubValidator{})
+	send(server, "1")
+	send(server, "")
+	send(server, "yes")
+	send(newServer(&stubValidator{err: errors.New("no session")}), "1")
+	output := logBuffer.String()
+	if count := strings.Count(output, "msg=http_request_debug"); count != 1 {
+		t.Fatalf("expected one debug line for the flagged authenticated request, got %d: %q", count, output)
+	}
+	if !strings.Contains(output, "path=/api/notifications") || !strings.Contains(output, "status=200") {
+		t.Fatalf("expected the flagged request's path and status, got %q", output)
+	}
+}
+
 func TestSourceIPForContextFallsBackToUnknown(t *testing.T) {
 	t.Helper()
 
@@ -1487,6 +2273,45 @@ func TestBuildCORSEmitsCredentialsForExplicitAllowList(t *testing.T) {
 	}
 }
 
+// TestSavedFilterUpdatePassesCORSPreflight pins that a cross-origin dashboard may update
+// a saved filter: its method must be one buildCORS allows.
+func TestSavedFilterUpdatePassesCORSPreflight(t *testing.T) {
+	server, _ := newTestHTTPServerWithSavedFilters(t, &stubNotificationService{}, &stubValidator{})
+	assertCORSPreflightAllowed(t, server, http.MethodPatch, "/api/filters/filter-1?tenant_id=tenant-test")
+}
+
+func TestLegalHoldUpdatePassesCORSPreflight(t *testing.T) {
+	server := newTestHTTPServer(t, &stubNotificationService{}, &stubValidator{})
+	assertCORSPreflightAllowed(t, server, http.MethodPatch, "/api/notifications/notif-1/legal-hold?tenant_id=tenant-test")
+}
+
+func assertCORSPreflightAllowed(t *testing.T, server *Server, method string, path string) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodOptions, path, nil)
+	request.Header.Set("Origin", "https://dashboard.example")
+	request.Header.Set("Access-Control-Request-Method", method)
+	request.Header.Set("Access-Control-Request-Headers", "Content-Type")
+
+	server.httpServer.Handler.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusNoContent || !strings.Contains(recorder.Header().Get("Access-Control-Allow-Methods"), method) {
+		t.Fatalf("expected a %s preflight for %s to pass, got %d allow-methods %q", method, path, recorder.Code, recorder.Header().Get("Access-Control-Allow-Methods"))
+	}
+}
+
+func TestNewServerRefusesMissingTenantRepository(t *testing.T) {
+	server, err := NewServer(Config{
+		ListenAddr:          ":0",
+		NotificationService: &stubNotificationService{},
+		SessionValidator:    &stubValidator{},
+		Logger:              slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
+	})
+	if server != nil || err == nil || !strings.Contains(err.Error(), "tenant repository is required") {
+		t.Fatalf("expected construction to fail without a tenant repository, got %v (%v)", server, err)
+	}
+}
+
 func TestRuntimeConfigEndpointReturnsValues(t *testing.T) {
 	t.Helper()
 
@@ -1549,155 +2374,813 @@ func TestRuntimeConfigResolvesPerHost(t *testing.T) {
 		t.Fatalf("server init error: %v", err)
 	}
 
-	checkHost := func(host string, expectedID string) {
+	checkHost := func(host string, expectedID string) {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/runtime-config", nil)
+		request.Host = host
+		server.httpServer.Handler.ServeHTTP(recorder, request)
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("expected 200 for host %s, got %d", host, recorder.Code)
+		}
+		var payload struct {
+			Tenant struct {
+				ID string `json:"id"`
+			} `json:"tenant"`
+		}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if payload.Tenant.ID != expectedID {
+			t.Fatalf("host %s resolved id %s", host, payload.Tenant.ID)
+		}
+	}
+
+	checkHost("alpha.localhost", "tenant-alpha")
+	checkHost("bravo.localhost", "tenant-bravo")
+}
+
+func TestRuntimeConfigRejectsUnknownHost(t *testing.T) {
+	t.Helper()
+
+	repo := newMultiTenantRepository(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	server, err := NewServer(Config{
+		ListenAddr:          ":0",
+		NotificationService: &stubNotificationService{},
+		SessionValidator:    &stubValidator{},
+		TenantRepository:    repo,
+		Logger:              logger,
+	})
+	if err != nil {
+		t.Fatalf("server init error: %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/runtime-config", nil)
+	request.Host = "unknown.localhost"
+	server.httpServer.Handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown host, got %d", recorder.Code)
+	}
+}
+
+func TestRuntimeConfigMissingRuntimeReturnsInternalServerError(t *testing.T) {
+	t.Helper()
+	engine := gin.New()
+	engine.GET("/runtime-config", serveRuntimeConfig())
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/runtime-config", nil)
+	engine.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", recorder.Code)
+	}
+}
+
+func TestNewServerValidation(t *testing.T) {
+	t.Helper()
+	valid := func() Config {
+		return Config{
+			ListenAddr:          ":0",
+			NotificationService: &stubNotificationService{},
+			SessionValidator:    &stubValidator{},
+			TenantRepository:    newTestTenantRepository(t),
+			Logger:              slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
+		}
+	}
+	testCases := []struct {
+		name   string
+		mutate func(*Config)
+	}{
+		{name: "listen addr", mutate: func(cfg *Config) { cfg.ListenAddr = " " }},
+		{name: "validator", mutate: func(cfg *Config) { cfg.SessionValidator = nil }},
+		{name: "notification service", mutate: func(cfg *Config) { cfg.NotificationService = nil }},
+		{name: "tenant repository", mutate: func(cfg *Config) { cfg.TenantRepository = nil }},
+		{name: "logger", mutate: func(cfg *Config) { cfg.Logger = nil }},
+		{name: "trusted proxy", mutate: func(cfg *Config) { cfg.TrustedProxies = []string{"not-an-ip"} }},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			cfg := valid()
+			testCase.mutate(&cfg)
+			if _, err := NewServer(cfg); err == nil {
+				t.Fatalf("expected validation error")
+			}
+		})
+	}
+}
+
+func TestServerStartAndShutdown(t *testing.T) {
+	t.Helper()
+	server := newTestHTTPServer(t, &stubNotificationService{}, &stubValidator{})
+	server.httpServer.Addr = "127.0.0.1:0"
+	server.config.ShutdownGraceTimeout = time.Millisecond
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Start()
+	}()
+	time.Sleep(20 * time.Millisecond)
+	if err := server.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if err := <-errCh; err != nil {
+		t.Fatalf("start returned error: %v", err)
+	}
+
+	badServer := newTestHTTPServer(t, &stubNotificationService{}, &stubValidator{})
+	badServer.httpServer.Addr = "bad-address"
+	if err := badServer.Start(); err == nil {
+		t.Fatalf("expected listen error")
+	}
+}
+
+func TestRequestBodyLimitReturnsEntityTooLarge(t *testing.T) {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	stubSvc := &stubNotificationService{}
+	server, err := NewServer(Config{
+		ListenAddr:          ":0",
+		NotificationService: stubSvc,
+		SessionValidator:    &stubValidator{},
+		TenantRepository:    newTestTenantRepository(t),
+		Logger:              logger,
+		MaxRequestBytes:     64,
+	})
+	if err != nil {
+		t.Fatalf("server init error: %v", err)
+	}
+
+	oversizedBody := `{"scheduled_time":"` + strings.Repeat("9", 128) + `"}`
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPatch, "/api/notifications/notif-1/schedule", strings.NewReader(oversizedBody))
+	request.Header.Set("Content-Type", "application/json")
+	server.httpServer.Handler.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413, got %d", recorder.Code)
+	}
+	var payload map[string]string
+	if decodeErr := json.Unmarshal(recorder.Body.Bytes(), &payload); decodeErr != nil {
+		t.Fatalf("decode error body: %v", decodeErr)
+	}
+	if payload["error"] != requestBodyTooLargeError {
+		t.Fatalf("unexpected error body %v", payload)
+	}
+	if stubSvc.rescheduleCalls != 0 {
+		t.Fatalf("expected no service invocation, got %d", stubSvc.rescheduleCalls)
+	}
+
+	withinLimitBody := `{"scheduled_time":"` + time.Now().UTC().Add(time.Hour).Format(time.RFC3339) + `"}`
+	recorder = httptest.NewRecorder()
+	request = httptest.NewRequest(http.MethodPatch, "/api/notifications/notif-1/schedule?tenant_id=tenant-test", strings.NewReader(withinLimitBody))
+	request.Header.Set("Content-Type", "application/json")
+	server.httpServer.Handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK || stubSvc.rescheduleCalls != 1 {
+		t.Fatalf("expected a body within the limit to reach the service, got %d after %d calls", recorder.Code, stubSvc.rescheduleCalls)
+	}
+}
+
+func TestRequestBodyLimiterGrantsAttachmentRoutesLargerLimit(t *testing.T) {
+	t.Helper()
+	engine := gin.New()
+	engine.Use(requestBodyLimiter(8, 64, map[string]struct{}{"/attachments": {}}))
+	readBody := func(contextGin *gin.Context) {
+		if _, readErr := io.ReadAll(contextGin.Request.Body); readErr != nil {
+			contextGin.Status(http.StatusRequestEntityTooLarge)
+			return
+		}
+		contextGin.Status(http.StatusNoContent)
+	}
+	engine.POST("/attachments", readBody)
+	engine.POST("/plain", readBody)
+
+	testCases := []struct {
+		name         string
+		path         string
+		body         string
+		expectedCode int
+	}{
+		{name: "plain route within limit", path: "/plain", body: "12345678", expectedCode: http.StatusNoContent},
+		{name: "plain route over limit", path: "/plain", body: "123456789", expectedCode: http.StatusRequestEntityTooLarge},
+		{name: "attachment route uses larger limit", path: "/attachments", body: strings.Repeat("a", 64), expectedCode: http.StatusNoContent},
+		{name: "attachment route over larger limit", path: "/attachments", body: strings.Repeat("a", 65), expectedCode: http.StatusRequestEntityTooLarge},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, testCase.path, strings.NewReader(testCase.body))
+			engine.ServeHTTP(recorder, request)
+			if recorder.Code != testCase.expectedCode {
+				t.Fatalf("expected %d, got %d", testCase.expectedCode, recorder.Code)
+			}
+		})
+	}
+}
+
+func TestNewServerAppliesConfiguredAndDefaultTimeouts(t *testing.T) {
+	newServer := func(cfg Config) *Server {
+		t.Helper()
+		cfg.ListenAddr = "127.0.0.1:0"
+		cfg.NotificationService = &stubNotificationService{}
+		cfg.SessionValidator = &stubValidator{}
+		cfg.TenantRepository = newTestTenantRepository(t)
+		cfg.Logger = slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+		server, err := NewServer(cfg)
+		if err != nil {
+			t.Fatalf("server init error: %v", err)
+		}
+		return server
+	}
+
+	configured := newServer(Config{
+		ReadHeaderTimeout: 2 * time.Second,
+		ReadTimeout:       20 * time.Second,
+		WriteTimeout:      40 * time.Second,
+		IdleTimeout:       90 * time.Second,
+	}).httpServer
+	if configured.ReadHeaderTimeout != 2*time.Second || configured.ReadTimeout != 20*time.Second || configured.WriteTimeout != 40*time.Second || configured.IdleTimeout != 90*time.Second {
+		t.Fatalf("unexpected configured timeouts %v/%v/%v/%v", configured.ReadHeaderTimeout, configured.ReadTimeout, configured.WriteTimeout, configured.IdleTimeout)
+	}
+
+	defaults := newServer(Config{}).httpServer
+	if defaults.ReadHeaderTimeout != defaultTimeout || defaults.ReadTimeout != defaultReadTimeout || defaults.WriteTimeout != defaultWriteTimeout || defaults.IdleTimeout != defaultIdleTimeout {
+		t.Fatalf("unexpected default timeouts %v/%v/%v/%v", defaults.ReadHeaderTimeout, defaults.ReadTimeout, defaults.WriteTimeout, defaults.IdleTimeout)
+	}
+}
+
+func TestServerTerminatesSlowRequestBody(t *testing.T) {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	server, err := NewServer(Config{
+		ListenAddr:          "127.0.0.1:0",
+		NotificationService: &stubNotificationService{},
+		SessionValidator:    &stubValidator{},
+		TenantRepository:    newTestTenantRepository(t),
+		Logger:              logger,
+		ReadTimeout:         200 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("server init error: %v", err)
+	}
+	listener, listenErr := net.Listen("tcp", "127.0.0.1:0")
+	if listenErr != nil {
+		t.Fatalf("listen: %v", listenErr)
+	}
+	go func() {
+		_ = server.httpServer.Serve(listener)
+	}()
+	t.Cleanup(func() {
+		_ = server.httpServer.Close()
+	})
+
+	connection, dialErr := net.Dial("tcp", listener.Addr().String())
+	if dialErr != nil {
+		t.Fatalf("dial: %v", dialErr)
+	}
+	defer connection.Close()
+	partialRequest := "PATCH /api/notifications/notif-1/schedule HTTP/1.1\r\n" +
+		"Host: localhost\r\n" +
+		"Content-Type: application/json\r\n" +
+		"Content-Length: 1024\r\n\r\n" +
+		`{"scheduled_time":`
+	if _, writeErr := connection.Write([]byte(partialRequest)); writeErr != nil {
+		t.Fatalf("write partial request: %v", writeErr)
+	}
+
+	if deadlineErr := connection.SetReadDeadline(time.Now().Add(5 * time.Second)); deadlineErr != nil {
+		t.Fatalf("set deadline: %v", deadlineErr)
+	}
+	started := time.Now()
+	if _, readErr := io.ReadAll(connection); readErr != nil {
+		t.Fatalf("expected server to close the stalled connection, got %v", readErr)
+	}
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Fatalf("expected stalled body to be cut off near the read timeout, took %s", elapsed)
+	}
+}
+
+func TestSmallHelpers(t *testing.T) {
+	t.Helper()
+	if pickDuration(3*time.Second, time.Second) != 3*time.Second {
+		t.Fatalf("expected explicit duration")
+	}
+	statuses := parseStatusFilters([]string{" queued ", "queued", "", "ERRORED"})
+	if len(statuses) != 2 || statuses[0] != model.StatusQueued || statuses[1] != model.StatusErrored {
+		t.Fatalf("unexpected statuses %v", statuses)
+	}
+	if base := buildAPIBaseURL(nil); base != "/api" {
+		t.Fatalf("unexpected nil request base %q", base)
+	}
+	tlsRequest := httptest.NewRequest(http.MethodGet, "https://api.example/runtime-config", nil)
+	if base := buildAPIBaseURL(tlsRequest); base != "https://api.example/api" {
+		t.Fatalf("unexpected TLS base %q", base)
+	}
+	forwardedRequest := httptest.NewRequest(http.MethodGet, "/runtime-config", nil)
+	forwardedRequest.Host = ""
+	forwardedRequest.Header.Set("X-Forwarded-Proto", "https")
+	if base := buildAPIBaseURL(forwardedRequest); base != "https://localhost/api" {
+		t.Fatalf("unexpected forwarded base %q", base)
+	}
+}
+
+func TestSendNotificationMultipartUploadsAttachments(t *testing.T) {
+	t.Helper()
+	stubSvc := &stubNotificationService{sendResponse: model.NotificationResponse{NotificationID: "notif-upload", Status: model.StatusSent}}
+	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
+
+	pngData := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0x01}, 32)...)
+	textData := []byte("quarterly numbers\n")
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, value := range map[string]string{
+		"notification_type": "EMAIL",
+		"recipient":         "someone@example.com",
+		"subject":           "Report",
+		"message":           "See attached.",
+	} {
+		if err := writer.WriteField(name, value); err != nil {
+			t.Fatalf("write field: %v", err)
+		}
+	}
+	textHeader := textproto.MIMEHeader{}
+	textHeader.Set("Content-Disposition", `form-data; name="attachments"; filename="notes.txt"`)
+	textHeader.Set("Content-Type", "text/plain; charset=utf-8")
+	textPart, err := writer.CreatePart(textHeader)
+	if err != nil {
+		t.Fatalf("create text part: %v", err)
+	}
+	textPart.Write(textData)
+	imagePart, err := writer.CreateFormFile("attachments", "chart.png")
+	if err != nil {
+		t.Fatalf("create image part: %v", err)
+	}
+	imagePart.Write(pngData)
+	writer.Close()
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/api/notifications?tenant_id=tenant-test", &body)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	server.httpServer.Handler.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if stubSvc.sendCalls != 1 || stubSvc.lastTenantID != "tenant-test" {
+		t.Fatalf("expected one send for tenant-test, got %d calls for %q", stubSvc.sendCalls, stubSvc.lastTenantID)
+	}
+	sent := stubSvc.lastSendRequest
+	if sent.NotificationType() != model.NotificationEmail || sent.Recipient() != "someone@example.com" || sent.Subject() != "Report" || sent.Message() != "See attached." {
+		t.Fatalf("unexpected form fields %s %s %s %s", sent.NotificationType(), sent.Recipient(), sent.Subject(), sent.Message())
+	}
+	attachments := sent.Attachments()
+	if len(attachments) != 2 {
+		t.Fatalf("expected two attachments, got %d", len(attachments))
+	}
+	if attachments[0].Filename != "notes.txt" || attachments[0].ContentType != "text/plain; charset=utf-8" || !bytes.Equal(attachments[0].Data, textData) {
+		t.Fatalf("unexpected text attachment %s %s %q", attachments[0].Filename, attachments[0].ContentType, attachments[0].Data)
+	}
+	if attachments[1].Filename != "chart.png" || attachments[1].ContentType != "image/png" || !bytes.Equal(attachments[1].Data, pngData) {
+		t.Fatalf("expected sniffed png attachment, got %s %s", attachments[1].Filename, attachments[1].ContentType)
+	}
+}
+
+func TestSendNotificationAcceptsJSON(t *testing.T) {
+	t.Helper()
+	stubSvc := &stubNotificationService{sendResponse: model.NotificationResponse{NotificationID: "notif-json", Status: model.StatusQueued}}
+	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
+
+	recorder := httptest.NewRecorder()
+	requestBody := `{"notification_type":"sms","recipient":"+15555550100","message":"Hi","scheduled_time":"2030-01-02T15:04:05Z"}`
+	request := httptest.NewRequest(http.MethodPost, "/api/notifications?tenant_id=tenant-test", strings.NewReader(requestBody))
+	request.Header.Set("Content-Type", "application/json; charset=utf-8")
+	server.httpServer.Handler.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	sent := stubSvc.lastSendRequest
+	if sent.NotificationType() != model.NotificationSMS || sent.ScheduledFor() == nil || sent.ScheduledFor().Year() != 2030 {
+		t.Fatalf("unexpected json request %s %v", sent.NotificationType(), sent.ScheduledFor())
+	}
+	var response model.NotificationResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil || response.NotificationID != "notif-json" {
+		t.Fatalf("unexpected response %s (%v)", recorder.Body.String(), err)
+	}
+}
+
+func TestCamelCaseJSONRenamesAPIFields(t *testing.T) {
+	scheduledFor := time.Date(2030, time.January, 2, 15, 4, 5, 0, time.UTC)
+	notification := model.NotificationResponse{NotificationID: "notif-1", NotificationType: model.NotificationEmail, Status: model.StatusQueued, ScheduledFor: &scheduledFor, RetryCount: 2}
+	stubSvc := &stubNotificationService{
+		listResponse:   []model.NotificationResponse{notification},
+		nextCursor:     "cursor-2",
+		statusResponse: notification,
+		sendResponse:   notification,
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	camelServer, err := NewServer(Config{
+		ListenAddr:          ":0",
+		NotificationService: stubSvc,
+		SessionValidator:    &stubValidator{},
+		TenantRepository:    newTestTenantRepository(t),
+		Logger:              logger,
+		CamelCaseJSON:       true,
+	})
+	if err != nil {
+		t.Fatalf("server init error: %v", err)
+	}
+	snakeServer := newTestHTTPServer(t, stubSvc, &stubValidator{})
+
+	for _, testCase := range []struct {
+		name    string
+		server  *Server
+		path    string
+		present []string
+		absent  []string
+	}{
+		{name: "camelCase list", server: camelServer, path: "/api/notifications?tenant_id=tenant-test", present: []string{`"notifications":[{`, `"notificationId":"notif-1"`, `"scheduledFor":`, `"retryCount":2`, `"nextCursor":"cursor-2"`}, absent: []string{"notification_id", "next_cursor"}},
+		{name: "camelCase status", server: camelServer, path: "/api/notifications/notif-1?tenant_id=tenant-test", present: []string{`"notificationId":"notif-1"`, `"notificationType":"email"`, `"createdAt":`}, absent: []string{"notification_id", "created_at"}},
+		{name: "camelCase projected list", server: camelServer, path: "/api/notifications?tenant_id=tenant-test&fields=notificationId,retryCount", present: []string{`{"notificationId":"notif-1","retryCount":2}`}, absent: []string{"status"}},
+		{name: "snake_case list", server: snakeServer, path: "/api/notifications?tenant_id=tenant-test", present: []string{`"notification_id":"notif-1"`, `"next_cursor":"cursor-2"`}, absent: []string{"notificationId"}},
+		{name: "snake_case status", server: snakeServer, path: "/api/notifications/notif-1?tenant_id=tenant-test", present: []string{`"notification_id":"notif-1"`, `"created_at":`}, absent: []string{"notificationId"}},
+	} {
+		recorder := httptest.NewRecorder()
+		testCase.server.httpServer.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, testCase.path, nil))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d: %s", testCase.name, recorder.Code, recorder.Body.String())
+		}
+		body := recorder.Body.String()
+		for _, expected := range testCase.present {
+			if !strings.Contains(body, expected) {
+				t.Fatalf("%s: expected %s in %s", testCase.name, expected, body)
+			}
+		}
+		for _, unexpected := range testCase.absent {
+			if strings.Contains(body, unexpected) {
+				t.Fatalf("%s: unexpected %s in %s", testCase.name, unexpected, body)
+			}
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/api/notifications?tenant_id=tenant-test", strings.NewReader(`{"notificationType":"sms","recipient":"+15555550100","message":"Hi","scheduledTime":"2030-01-02T15:04:05Z"}`))
+	request.Header.Set("Content-Type", "application/json")
+	camelServer.httpServer.Handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), `"notificationId":"notif-1"`) {
+		t.Fatalf("expected a camelCase send to succeed, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if sent := stubSvc.lastSendRequest; sent.NotificationType() != model.NotificationSMS || sent.ScheduledFor() == nil {
+		t.Fatalf("expected camelCase request fields to bind, got %s %v", sent.NotificationType(), sent.ScheduledFor())
+	}
+}
+
+func TestRenameJSONKeysKeepsValuesAndOrder(t *testing.T) {
+	renamed, err := renameJSONKeys([]byte(`{"next_cursor":"a_b","items":[{"retry_count":9007199254740993,"body":"<p>x</p>"},null,[true]],"empty":{}}`), camelCaseKey)
+	if err != nil {
+		t.Fatalf("rename error: %v", err)
+	}
+	if want := `{"nextCursor":"a_b","items":[{"retryCount":9007199254740993,"body":"\u003cp\u003ex\u003c/p\u003e"},null,[true]],"empty":{}}`; string(renamed) != want {
+		t.Fatalf("expected %s, got %s", want, renamed)
+	}
+	if snakeCaseKey("smsOverflowPolicy") != "sms_overflow_policy" || camelCaseKey("sms_overflow_policy") != "smsOverflowPolicy" {
+		t.Fatalf("unexpected key conversion")
+	}
+}
+
+func TestSendNotificationDecodesBase64Attachments(t *testing.T) {
+	stubSvc := &stubNotificationService{sendResponse: model.NotificationResponse{NotificationID: "notif-base64", Status: model.StatusSent}}
+	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
+
+	pngData := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0x01}, 32)...)
+	textData := []byte("quarterly numbers\n")
+	requestBody, err := json.Marshal(map[string]any{
+		"notification_type": "email",
+		"recipient":         "someone@example.com",
+		"subject":           "Report",
+		"message":           "See attached.",
+		"attachments": []map[string]string{
+			{"filename": "notes.txt", "content_type": "text/plain; charset=utf-8", "data": base64.StdEncoding.EncodeToString(textData)},
+			{"filename": "chart.png", "data": base64.StdEncoding.EncodeToString(pngData)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("encode request: %v", err)
+	}
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/api/notifications?tenant_id=tenant-test", bytes.NewReader(requestBody))
+	request.Header.Set("Content-Type", "application/json")
+	server.httpServer.Handler.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	attachments := stubSvc.lastSendRequest.Attachments()
+	if len(attachments) != 2 {
+		t.Fatalf("expected two attachments, got %d", len(attachments))
+	}
+	if attachments[0].Filename != "notes.txt" || attachments[0].ContentType != "text/plain; charset=utf-8" || !bytes.Equal(attachments[0].Data, textData) {
+		t.Fatalf("unexpected text attachment %s %s %q", attachments[0].Filename, attachments[0].ContentType, attachments[0].Data)
+	}
+	if attachments[1].Filename != "chart.png" || attachments[1].ContentType != "image/png" || !bytes.Equal(attachments[1].Data, pngData) {
+		t.Fatalf("expected sniffed png attachment, got %s %s", attachments[1].Filename, attachments[1].ContentType)
+	}
+
+	recorder = httptest.NewRecorder()
+	request = httptest.NewRequest(http.MethodPost, "/api/notifications?tenant_id=tenant-test",
+		strings.NewReader(`{"notification_type":"email","recipient":"a@example.com","message":"b","attachments":[{"filename":"a.txt","data":"bm90IGJhc2U2NA=="},{"filename":"b.txt","data":"%%%"}]}`))
+	request.Header.Set("Content-Type", "application/json")
+	server.httpServer.Handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusBadRequest || !strings.Contains(recorder.Body.String(), "attachments[1].data must be standard base64") {
+		t.Fatalf("expected a 400 naming the malformed attachment, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestSendNotificationRejectsInvalidRequests(t *testing.T) {
+	t.Helper()
+	rawBody := func(contentType string, body string) func() (string, io.Reader) {
+		return func() (string, io.Reader) { return contentType, strings.NewReader(body) }
+	}
+	multipartBody := func(fields map[string]string, files map[string][]byte) func() (string, io.Reader) {
+		return func() (string, io.Reader) {
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			for name, value := range fields {
+				writer.WriteField(name, value)
+			}
+			for filename, data := range files {
+				part, _ := writer.CreateFormFile("attachments", filename)
+				part.Write(data)
+			}
+			writer.Close()
+			return writer.FormDataContentType(), &body
+		}
+	}
+	validFields := map[string]string{"notification_type": "email", "recipient": "someone@example.com", "message": "Body"}
+	jsonAttachments := func(notificationType string, attachments ...string) func() (string, io.Reader) {
+		entries := make([]map[string]string, 0, len(attachments))
+		for index, data := range attachments {
+			entries = append(entries, map[string]string{"filename": fmt.Sprintf("file-%d.bin", index), "data": data})
+		}
+		encoded, _ := json.Marshal(map[string]any{"notification_type": notificationType, "recipient": "someone@example.com", "message": "Body", "attachments": entries})
+		return rawBody("application/json", string(encoded))
+	}
+	oversizedAttachment := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte("x"), model.MaxNotificationAttachmentSizeBytes+1))
+	tooManyFiles := make(map[string][]byte, model.MaxNotificationAttachmentCount+1)
+	for fileIndex := 0; fileIndex <= model.MaxNotificationAttachmentCount; fileIndex++ {
+		tooManyFiles[fmt.Sprintf("file-%d.txt", fileIndex)] = []byte("x")
+	}
+	testCases := []struct {
+		name         string
+		buildBody    func() (string, io.Reader)
+		sendErr      error
+		expectedCode int
+	}{
+		{name: "unsupported content type", buildBody: rawBody("text/plain", "hello"), expectedCode: http.StatusUnsupportedMediaType},
+		{name: "invalid json", buildBody: rawBody("application/json", "{"), expectedCode: http.StatusBadRequest},
+		{name: "unsupported type", buildBody: rawBody("application/json", `{"notification_type":"push","recipient":"a","message":"b"}`), expectedCode: http.StatusBadRequest},
+		{name: "invalid scheduled time", buildBody: rawBody("application/json", `{"notification_type":"email","recipient":"a","message":"b","scheduled_time":"tomorrow"}`), expectedCode: http.StatusBadRequest},
+		{name: "subject header injection", buildBody: rawBody("application/json", `{"notification_type":"email","recipient":"a@example.com","subject":"Hi\r\nBcc: attacker@example.com","message":"b"}`), expectedCode: http.StatusBadRequest},
+		{name: "recipient header injection", buildBody: rawBody("application/json", `{"notification_type":"email","recipient":"a@example.com\nBcc: attacker@example.com","message":"b"}`), expectedCode: http.StatusBadRequest},
+		{name: "missing boundary", buildBody: rawBody("multipart/form-data", "--x--"), expectedCode: http.StatusBadRequest},
+		{name: "unknown field", buildBody: multipartBody(map[string]string{"notification_type": "email", "priority": "high"}, nil), expectedCode: http.StatusBadRequest},
+		{name: "oversized file", buildBody: multipartBody(validFields, map[string][]byte{"big.bin": bytes.Repeat([]byte("x"), model.MaxNotificationAttachmentSizeBytes+1)}), expectedCode: http.StatusRequestEntityTooLarge},
+		{name: "too many files", buildBody: multipartBody(validFields, tooManyFiles), expectedCode: http.StatusBadRequest},
+		{name: "malformed base64 attachment", buildBody: jsonAttachments("email", "not base64!"), expectedCode: http.StatusBadRequest},
+		{name: "oversized base64 attachment", buildBody: jsonAttachments("email", oversizedAttachment), expectedCode: http.StatusRequestEntityTooLarge},
+		{name: "empty base64 attachment", buildBody: jsonAttachments("email", ""), expectedCode: http.StatusBadRequest},
+		{name: "base64 attachment on sms", buildBody: jsonAttachments("sms", "aGVsbG8="), expectedCode: http.StatusBadRequest},
+		{name: "invalid fail_on_immediate_error", buildBody: multipartBody(map[string]string{"notification_type": "email", "recipient": "a@example.com", "message": "b", "fail_on_immediate_error": "maybe"}, nil), expectedCode: http.StatusBadRequest},
+		{name: "sms disabled", buildBody: rawBody("application/json", `{"notification_type":"sms","recipient":"+15555550100","message":"b"}`), sendErr: service.ErrSMSDisabled, expectedCode: http.StatusBadRequest},
+		{name: "capacity exhausted", buildBody: rawBody("application/json", `{"notification_type":"email","recipient":"a@example.com","message":"b"}`), sendErr: service.ErrDispatchCapacityExhausted, expectedCode: http.StatusTooManyRequests},
+		{name: "schedule in past", buildBody: rawBody("application/json", `{"notification_type":"email","recipient":"a@example.com","message":"b"}`), sendErr: service.ErrScheduleInPast, expectedCode: http.StatusBadRequest},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			stubSvc := &stubNotificationService{sendErr: testCase.sendErr}
+			server := newTestHTTPServer(t, stubSvc, &stubValidator{})
+			contentType, body := testCase.buildBody()
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/api/notifications?tenant_id=tenant-test", body)
+			request.Header.Set("Content-Type", contentType)
+			server.httpServer.Handler.ServeHTTP(recorder, request)
+			if recorder.Code != testCase.expectedCode {
+				t.Fatalf("expected %d, got %d: %s", testCase.expectedCode, recorder.Code, recorder.Body.String())
+			}
+			if testCase.sendErr == nil && stubSvc.sendCalls != 0 {
+				t.Fatalf("expected no service call, got %d", stubSvc.sendCalls)
+			}
+		})
+	}
+}
+
+func TestSendNotificationReportsImmediateDispatchFailure(t *testing.T) {
+	stubSvc := &stubNotificationService{
+		sendResponse: model.NotificationResponse{NotificationID: "notif-1", Status: model.StatusErrored},
+		sendErr:      fmt.Errorf("%w: notification notif-1: %w", service.ErrImmediateDispatchFailed, errors.New("smtp down")),
+	}
+	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/api/notifications?tenant_id=tenant-test",
+		strings.NewReader(`{"notification_type":"email","recipient":"a@example.com","message":"b","fail_on_immediate_error":true}`))
+	request.Header.Set("Content-Type", "application/json")
+	server.httpServer.Handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusBadGateway {
+		t.Fatalf("expected 502, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var body struct {
+		Error        string                     `json:"error"`
+		Notification model.NotificationResponse `json:"notification"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Notification.NotificationID != "notif-1" || body.Error == "" {
+		t.Fatalf("expected the stored notification alongside the error, got %+v", body)
+	}
+	if fail, overridden := stubSvc.lastSendRequest.FailOnImmediateError(); !overridden || !fail {
+		t.Fatalf("expected the request override forwarded, got fail=%v overridden=%v", fail, overridden)
+	}
+}
+
+func TestSendNotificationAcceptsEncryptedContent(t *testing.T) {
+	stubSvc := &stubNotificationService{sendResponse: model.NotificationResponse{NotificationID: "notif-1", ContentEncrypted: true}}
+	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
+	send := func(body string) *httptest.ResponseRecorder {
 		recorder := httptest.NewRecorder()
-		request := httptest.NewRequest(http.MethodGet, "/runtime-config", nil)
-		request.Host = host
+		request := httptest.NewRequest(http.MethodPost, "/api/notifications?tenant_id=tenant-test", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
 		server.httpServer.Handler.ServeHTTP(recorder, request)
-		if recorder.Code != http.StatusOK {
-			t.Fatalf("expected 200 for host %s, got %d", host, recorder.Code)
-		}
-		var payload struct {
-			Tenant struct {
-				ID string `json:"id"`
-			} `json:"tenant"`
-		}
-		if err := json.Unmarshal(recorder.Body.Bytes(), &payload); err != nil {
-			t.Fatalf("decode error: %v", err)
-		}
-		if payload.Tenant.ID != expectedID {
-			t.Fatalf("host %s resolved id %s", host, payload.Tenant.ID)
-		}
+		return recorder
 	}
 
-	checkHost("alpha.localhost", "tenant-alpha")
-	checkHost("bravo.localhost", "tenant-bravo")
+	entity := `Content-Type: application/pkcs7-mime; smime-type=enveloped-data\r\n\r\nMIAGCSqG`
+	if recorder := send(`{"notification_type":"email","recipient":"a@example.com","message":"` + entity + `","content_encrypted":true}`); recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if !stubSvc.lastSendRequest.ContentEncrypted() {
+		t.Fatalf("expected the encrypted flag forwarded")
+	}
+	if recorder := send(`{"notification_type":"sms","recipient":"+15555550100","message":"hQEMA","sms_overflow_policy":"truncate","content_encrypted":true}`); recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected truncation of encrypted SMS to be rejected, got %d: %s", recorder.Code, recorder.Body.String())
+	}
 }
 
-func TestRuntimeConfigRejectsUnknownHost(t *testing.T) {
-	t.Helper()
+func TestSendNotificationForwardsSenderOverrides(t *testing.T) {
+	stubSvc := &stubNotificationService{sendResponse: model.NotificationResponse{NotificationID: "notif-1", ReplyTo: "agent@support.example"}}
+	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
+	send := func(body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, "/api/notifications?tenant_id=tenant-test", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		server.httpServer.Handler.ServeHTTP(recorder, request)
+		return recorder
+	}
 
-	repo := newMultiTenantRepository(t)
-	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
-	server, err := NewServer(Config{
-		ListenAddr:          ":0",
-		NotificationService: &stubNotificationService{},
-		SessionValidator:    &stubValidator{},
-		TenantRepository:    repo,
-		Logger:              logger,
-	})
-	if err != nil {
-		t.Fatalf("server init error: %v", err)
+	recorder := send(`{"notification_type":"email","recipient":"a@example.com","subject":"Hi","message":"Body","reply_to":"agent@support.example","from_display_name":"Ada"}`)
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), `"reply_to":"agent@support.example"`) {
+		t.Fatalf("expected 200 with the reply_to, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if overrides := stubSvc.lastSendRequest.SenderOverrides(); overrides.ReplyTo != "agent@support.example" || overrides.FromDisplayName != "Ada" {
+		t.Fatalf("expected the overrides forwarded, got %+v", overrides)
+	}
+	if recorder := send(`{"notification_type":"email","recipient":"a@example.com","subject":"Hi","message":"Body","reply_to":"Agent <agent@support.example>"}`); recorder.Code != http.StatusBadRequest || !strings.Contains(recorder.Body.String(), model.ErrNotificationReplyToInvalid.Error()) {
+		t.Fatalf("expected an invalid reply_to to be rejected, got %d: %s", recorder.Code, recorder.Body.String())
 	}
 
-	recorder := httptest.NewRecorder()
-	request := httptest.NewRequest(http.MethodGet, "/runtime-config", nil)
-	request.Host = "unknown.localhost"
-	server.httpServer.Handler.ServeHTTP(recorder, request)
-	if recorder.Code != http.StatusNotFound {
-		t.Fatalf("expected 404 for unknown host, got %d", recorder.Code)
+	stubSvc.sendErr = fmt.Errorf("%w: this tenant does not allow reply_to or from_display_name", model.ErrNotificationSenderOverrideForbidden)
+	if recorder := send(`{"notification_type":"email","recipient":"a@example.com","subject":"Hi","message":"Body","from_display_name":"Ada"}`); recorder.Code != http.StatusBadRequest || !strings.Contains(recorder.Body.String(), model.ErrNotificationSenderOverrideForbidden.Error()) {
+		t.Fatalf("expected a policy violation to be a 400 carrying its code, got %d: %s", recorder.Code, recorder.Body.String())
 	}
 }
 
-func TestRuntimeConfigMissingRuntimeReturnsInternalServerError(t *testing.T) {
-	t.Helper()
-	engine := gin.New()
-	engine.GET("/runtime-config", serveRuntimeConfig())
+func TestSendNotificationGroupReturnsCombinedState(t *testing.T) {
+	stubSvc := &stubNotificationService{groupResponse: model.NotificationGroupResponse{
+		GroupID: "group-1",
+		Status:  model.StatusSent,
+		Notifications: []model.NotificationResponse{
+			{NotificationID: "notif-email", GroupID: "group-1", Status: model.StatusSent},
+			{NotificationID: "notif-sms", GroupID: "group-1", Status: model.StatusSent},
+		},
+	}}
+	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
 
 	recorder := httptest.NewRecorder()
-	request := httptest.NewRequest(http.MethodGet, "/runtime-config", nil)
-	engine.ServeHTTP(recorder, request)
-	if recorder.Code != http.StatusInternalServerError {
-		t.Fatalf("expected 500, got %d", recorder.Code)
+	request := httptest.NewRequest(http.MethodPost, "/api/notification-groups?tenant_id=tenant-test",
+		strings.NewReader(`{"subject":"Outage","message":"Database down","channels":[{"notification_type":"email","recipient":"a@example.com"},{"notification_type":"sms","recipient":"+15555550100","message":"DB down"}]}`))
+	request.Header.Set("Content-Type", "application/json")
+	server.httpServer.Handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var body model.NotificationGroupResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.GroupID != "group-1" || len(body.Notifications) != 2 {
+		t.Fatalf("unexpected group response %+v", body)
+	}
+	channels := stubSvc.lastGroupRequest.Channels()
+	if len(channels) != 2 || channels[0].Subject() != "Outage" || channels[0].Message() != "Database down" || channels[1].Message() != "DB down" {
+		t.Fatalf("expected group content to fill empty channel fields, got %+v", channels)
 	}
 }
 
-func TestNewServerValidation(t *testing.T) {
-	t.Helper()
-	valid := func() Config {
-		return Config{
-			ListenAddr:          ":0",
-			NotificationService: &stubNotificationService{},
-			SessionValidator:    &stubValidator{},
-			TenantRepository:    newTestTenantRepository(t),
-			Logger:              slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
-		}
-	}
+func TestSendNotificationGroupRejectsInvalidChannels(t *testing.T) {
 	testCases := []struct {
-		name   string
-		mutate func(*Config)
+		name     string
+		body     string
+		expected string
 	}{
-		{name: "listen addr", mutate: func(cfg *Config) { cfg.ListenAddr = " " }},
-		{name: "validator", mutate: func(cfg *Config) { cfg.SessionValidator = nil }},
-		{name: "notification service", mutate: func(cfg *Config) { cfg.NotificationService = nil }},
-		{name: "tenant repository", mutate: func(cfg *Config) { cfg.TenantRepository = nil }},
-		{name: "logger", mutate: func(cfg *Config) { cfg.Logger = nil }},
-		{name: "trusted proxy", mutate: func(cfg *Config) { cfg.TrustedProxies = []string{"not-an-ip"} }},
+		{name: "no channels", body: `{"message":"b","channels":[]}`, expected: model.ErrNotificationGroupChannelsRequired.Error()},
+		{name: "missing recipient", body: `{"message":"b","channels":[{"notification_type":"sms","recipient":"+15555550100"},{"notification_type":"email","recipient":" ","subject":"s"}]}`, expected: "channel 2"},
+		{name: "email without subject", body: `{"message":"b","channels":[{"notification_type":"email","recipient":"a@example.com"}]}`, expected: model.ErrNotificationSubjectRequired.Error()},
 	}
 	for _, testCase := range testCases {
-		testCase := testCase
 		t.Run(testCase.name, func(t *testing.T) {
-			cfg := valid()
-			testCase.mutate(&cfg)
-			if _, err := NewServer(cfg); err == nil {
-				t.Fatalf("expected validation error")
+			server := newTestHTTPServer(t, &stubNotificationService{}, &stubValidator{})
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/api/notification-groups?tenant_id=tenant-test", strings.NewReader(testCase.body))
+			request.Header.Set("Content-Type", "application/json")
+			server.httpServer.Handler.ServeHTTP(recorder, request)
+			if recorder.Code != http.StatusBadRequest || !strings.Contains(recorder.Body.String(), testCase.expected) {
+				t.Fatalf("expected 400 mentioning %q, got %d: %s", testCase.expected, recorder.Code, recorder.Body.String())
 			}
 		})
 	}
 }
 
-func TestServerStartAndShutdown(t *testing.T) {
-	t.Helper()
-	server := newTestHTTPServer(t, &stubNotificationService{}, &stubValidator{})
-	server.httpServer.Addr = "127.0.0.1:0"
-	server.config.ShutdownGraceTimeout = time.Millisecond
+func TestGetNotificationGroupReportsMissingGroup(t *testing.T) {
+	stubSvc := &stubNotificationService{groupErr: fmt.Errorf("%w: group-x", model.ErrNotificationGroupNotFound)}
+	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
 
-	errCh := make(chan error, 1)
-	go func() {
-		errCh <- server.Start()
-	}()
-	time.Sleep(20 * time.Millisecond)
-	if err := server.Shutdown(context.Background()); err != nil {
-		t.Fatalf("shutdown: %v", err)
-	}
-	if err := <-errCh; err != nil {
-		t.Fatalf("start returned error: %v", err)
+	recorder := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/notification-groups/group-x?tenant_id=tenant-test", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", recorder.Code, recorder.Body.String())
 	}
-
-	badServer := newTestHTTPServer(t, &stubNotificationService{}, &stubValidator{})
-	badServer.httpServer.Addr = "bad-address"
-	if err := badServer.Start(); err == nil {
-		t.Fatalf("expected listen error")
+	if stubSvc.lastGroupID != "group-x" {
+		t.Fatalf("expected the group id forwarded, got %q", stubSvc.lastGroupID)
 	}
 }
 
-func TestSmallHelpers(t *testing.T) {
-	t.Helper()
-	if pickDuration(3*time.Second, time.Second) != 3*time.Second {
-		t.Fatalf("expected explicit duration")
+func TestDispatchPacingReturnsTenantState(t *testing.T) {
+	stubSvc := &stubNotificationService{pacingStates: []service.DispatchPacingState{
+		{Provider: model.NotificationEmail, FailureRate: 0.5, DelayMs: 400, Attempts: 6, TransientFailures: 3},
+	}}
+	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
+
+	recorder := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/dispatch-pacing?tenant_id=tenant-test", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", recorder.Code, recorder.Body.String())
 	}
-	if isMissingNotificationID(nil) {
-		t.Fatalf("nil error should not look like missing id")
+	var body struct {
+		Pacing []service.DispatchPacingState `json:"pacing"`
 	}
-	statuses := parseStatusFilters([]string{" queued ", "queued", "", "ERRORED"})
-	if len(statuses) != 2 || statuses[0] != model.StatusQueued || statuses[1] != model.StatusErrored {
-		t.Fatalf("unexpected statuses %v", statuses)
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
 	}
-	if base := buildAPIBaseURL(nil); base != "/api" {
-		t.Fatalf("unexpected nil request base %q", base)
+	if len(body.Pacing) != 1 || body.Pacing[0].DelayMs != 400 || stubSvc.lastTenantID != "tenant-test" {
+		t.Fatalf("unexpected pacing response %+v for tenant %q", body.Pacing, stubSvc.lastTenantID)
 	}
-	tlsRequest := httptest.NewRequest(http.MethodGet, "https://api.example/runtime-config", nil)
-	if base := buildAPIBaseURL(tlsRequest); base != "https://api.example/api" {
-		t.Fatalf("unexpected TLS base %q", base)
+
+	stubSvc.pacingStates = nil
+	recorder = httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/dispatch-pacing?tenant_id=tenant-test", nil))
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), `"pacing":[]`) {
+		t.Fatalf("expected an empty pacing list, got %d: %s", recorder.Code, recorder.Body.String())
 	}
-	forwardedRequest := httptest.NewRequest(http.MethodGet, "/runtime-config", nil)
-	forwardedRequest.Host = ""
-	forwardedRequest.Header.Set("X-Forwarded-Proto", "https")
-	if base := buildAPIBaseURL(forwardedRequest); base != "https://localhost/api" {
-		t.Fatalf("unexpected forwarded base %q", base)
+}
+
+func TestCapabilitiesReturnsTenantCapabilities(t *testing.T) {
+	stubSvc := &stubNotificationService{capabilities: service.Capabilities{
+		NotificationTypes:      []model.NotificationType{model.NotificationEmail},
+		MaxAttachmentCount:     10,
+		MaxAttachmentSizeBytes: 1024,
+	}}
+	server := newTestHTTPServer(t, stubSvc, &stubValidator{})
+
+	recorder := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/capabilities?tenant_id=tenant-test", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if !strings.Contains(recorder.Body.String(), `"notification_types":["email"]`) || stubSvc.lastTenantID != "tenant-test" {
+		t.Fatalf("unexpected capabilities response %s for tenant %q", recorder.Body.String(), stubSvc.lastTenantID)
 	}
 }
 
+type stubCertificateReporter struct {
+	status certreload.Status
+}
+
+func (reporter stubCertificateReporter) Status() certreload.Status {
+	return reporter.status
+}
+
 func newTestHTTPServer(t *testing.T, svc service.NotificationService, validator SessionValidator) *Server {
 	t.Helper()
 	repo := newTestTenantRepository(t)
@@ -1721,6 +3204,56 @@ func newTestHTTPServerWithRepo(t *testing.T, svc service.NotificationService, va
 	return server
 }
 
+func newTestHTTPServerWithSavedFilters(t *testing.T, svc service.NotificationService, validator SessionValidator) (*Server, *savedfilter.Repository) {
+	t.Helper()
+	dbInstance, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "filters.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := dbInstance.AutoMigrate(&savedfilter.SavedFilter{}); err != nil {
+		t.Fatalf("migrate sqlite: %v", err)
+	}
+	filterRepo := savedfilter.NewRepository(dbInstance)
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	server, err := NewServer(Config{
+		ListenAddr:            ":0",
+		NotificationService:   svc,
+		SavedFilterRepository: filterRepo,
+		SessionValidator:      validator,
+		TenantRepository:      newTestTenantRepository(t),
+		Logger:                logger,
+	})
+	if err != nil {
+		t.Fatalf("server init error: %v", err)
+	}
+	return server, filterRepo
+}
+
+// serveSavedFilterRequest uses a host no tenant claims, so the routes must bypass host resolution.
+func serveSavedFilterRequest(server *Server, method string, path string, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	request.Host = "dashboard.unknown.test"
+	if body != "" {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	server.httpServer.Handler.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func createSavedFilterForTest(t *testing.T, server *Server, body string) savedfilter.PublicFilter {
+	t.Helper()
+	recorder := serveSavedFilterRequest(server, http.MethodPost, "/api/filters?tenant_id=tenant-test", body)
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected create 201, got %d body=%s", recorder.Code, recorder.Body.String())
+	}
+	var filter savedfilter.PublicFilter
+	if err := json.Unmarshal(recorder.Body.Bytes(), &filter); err != nil {
+		t.Fatalf("decode saved filter: %v", err)
+	}
+	return filter
+}
+
 func newTestHTTPServerWithSMTPIdentities(t *testing.T) (*Server, *smtpidentity.Repository) {
 	t.Helper()
 	return newTestHTTPServerWithSMTPIdentitiesAndValidator(t, &stubValidator{})
@@ -2048,21 +3581,65 @@ type stubNotificationService struct {
 	cancelResponse     model.NotificationResponse
 	cancelErr          error
 	cancelCalls        int
+	legalHoldCalls     int
+	lastLegalHold      bool
+	lastLegalHoldActor string
 	lastCancelID       string
+	lastCancelReason   string
+	lastCancelActor    string
 	lastTenantID       string
 	listCalls          int
 	listAllCalls       int
 	lastListFilters    model.NotificationListFilters
 	lastPageRequest    model.NotificationListPageRequest
 	nextCursor         string
+	sendResponse       model.NotificationResponse
+	sendErr            error
+	sendCalls          int
+	lastSendRequest    model.NotificationRequest
+	groupResponse      model.NotificationGroupResponse
+	groupErr           error
+	lastGroupRequest   model.NotificationGroupRequest
+	lastGroupID        string
+	pacingStates       []service.DispatchPacingState
+	capabilities       service.Capabilities
+	draining           bool
+	statusResponse     model.NotificationResponse
+	statusErr          error
+	lastStatusID       string
+	renderedCalls      int
+	cacheStates        []service.CacheState
+	flushActor         string
+}
+
+func (stub *stubNotificationService) SendNotification(ctx context.Context, request model.NotificationRequest) (model.NotificationResponse, error) {
+	stub.sendCalls++
+	stub.lastSendRequest = request
+	if runtimeCfg, ok := tenant.RuntimeFromContext(ctx); ok {
+		stub.lastTenantID = runtimeCfg.Tenant.ID
+	}
+	return stub.sendResponse, stub.sendErr
+}
+
+func (stub *stubNotificationService) SendNotificationGroup(_ context.Context, request model.NotificationGroupRequest) (model.NotificationGroupResponse, error) {
+	stub.lastGroupRequest = request
+	return stub.groupResponse, stub.groupErr
+}
+
+func (stub *stubNotificationService) GetNotificationGroup(_ context.Context, groupID string) (model.NotificationGroupResponse, error) {
+	stub.lastGroupID = groupID
+	return stub.groupResponse, stub.groupErr
 }
 
-func (stub *stubNotificationService) SendNotification(context.Context, model.NotificationRequest) (model.NotificationResponse, error) {
-	return model.NotificationResponse{}, errors.New("not implemented")
+func (stub *stubNotificationService) GetNotificationStatus(_ context.Context, notificationID string) (model.NotificationResponse, error) {
+	stub.lastStatusID = notificationID
+	return stub.statusResponse, stub.statusErr
 }
 
-func (stub *stubNotificationService) GetNotificationStatus(context.Context, string) (model.NotificationResponse, error) {
-	return model.NotificationResponse{}, errors.New("not implemented")
+func (stub *stubNotificationService) GetRenderedNotification(_ context.Context, notificationID string) (model.NotificationResponse, error) {
+	stub.renderedCalls++
+	stub.lastStatusID = notificationID
+	return stub.statusResponse, stub.statusErr
 }
 
 func (stub *stubNotificationService) ListNotifications(ctx context.Context, _ model.NotificationListFilters) ([]model.NotificationResponse, error) {
@@ -2086,6 +3663,14 @@ func (stub *stubNotificationService) ListNotificationsPage(ctx context.Context,
 	}, stub.listErr
 }
 
+func (stub *stubNotificationService) EstimateNotificationSize(context.Context, model.NotificationRequest) (service.MessageSizeEstimate, error) {
+	return service.MessageSizeEstimate{}, nil
+}
+
+func (stub *stubNotificationService) StreamNotifications(_ context.Context, _ model.NotificationListFilters, _ func(model.NotificationResponse) error) error {
+	return stub.listErr
+}
+
 func (stub *stubNotificationService) ListNotificationsAll(_ context.Context, _ model.NotificationListFilters) ([]model.NotificationResponse, error) {
 	stub.listCalls++
 	stub.listAllCalls++
@@ -2105,9 +3690,11 @@ func (stub *stubNotificationService) RescheduleNotification(requestContext conte
 	return stub.rescheduleResponse, nil
 }
 
-func (stub *stubNotificationService) CancelNotification(requestContext context.Context, notificationID string) (model.NotificationResponse, error) {
+func (stub *stubNotificationService) CancelNotification(requestContext context.Context, notificationID string, reason string, actor string) (model.NotificationResponse, error) {
 	stub.cancelCalls++
 	stub.lastCancelID = notificationID
+	stub.lastCancelReason = reason
+	stub.lastCancelActor = actor
 	if runtimeCfg, ok := tenant.RuntimeFromContext(requestContext); ok {
 		stub.lastTenantID = runtimeCfg.Tenant.ID
 	}
@@ -2117,4 +3704,127 @@ func (stub *stubNotificationService) CancelNotification(requestContext context.C
 	return stub.cancelResponse, nil
 }
 
+func (stub *stubNotificationService) SetLegalHold(_ context.Context, notificationID string, held bool, actor string) (model.NotificationResponse, error) {
+	stub.legalHoldCalls++
+	stub.lastLegalHold = held
+	stub.lastLegalHoldActor = actor
+	return model.NotificationResponse{NotificationID: notificationID, LegalHold: held}, nil
+}
+
+func (stub *stubNotificationService) GetCostSummary(context.Context, model.CostSummaryRange) (model.CostSummary, error) {
+	return model.CostSummary{}, nil
+}
+
 func (stub *stubNotificationService) StartRetryWorker(context.Context) {}
+
+func (stub *stubNotificationService) StartDailyReportWorker(context.Context) {}
+
+func (stub *stubNotificationService) StartIntegrityWorker(context.Context) {}
+
+func (stub *stubNotificationService) StartWebhookWorker(context.Context) {}
+
+func (stub *stubNotificationService) StartRetentionWorker(context.Context) {}
+
+func (stub *stubNotificationService) DispatchPacing(ctx context.Context) ([]service.DispatchPacingState, error) {
+	if runtimeCfg, ok := tenant.RuntimeFromContext(ctx); ok {
+		stub.lastTenantID = runtimeCfg.Tenant.ID
+	}
+	return stub.pacingStates, nil
+}
+
+func (stub *stubNotificationService) GetCapabilities(ctx context.Context) (service.Capabilities, error) {
+	if runtimeCfg, ok := tenant.RuntimeFromContext(ctx); ok {
+		stub.lastTenantID = runtimeCfg.Tenant.ID
+	}
+	return stub.capabilities, nil
+}
+
+func (stub *stubNotificationService) TestTenantDelivery(context.Context, model.NotificationType) (service.DeliveryCheck, error) {
+	return service.DeliveryCheck{}, errors.New("not implemented")
+}
+
+func (stub *stubNotificationService) ListTenantsStatus(context.Context) ([]service.TenantStatus, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (stub *stubNotificationService) TransferNotifications(context.Context, service.NotificationTransferRequest) (service.NotificationTransferResult, error) {
+	return service.NotificationTransferResult{}, errors.New("not implemented")
+}
+
+func (stub *stubNotificationService) Drain(context.Context) (service.DrainStatus, error) {
+	return service.DrainStatus{}, errors.New("not implemented")
+}
+
+func (stub *stubNotificationService) CacheStates(context.Context) []service.CacheState {
+	return stub.cacheStates
+}
+
+func (stub *stubNotificationService) FlushCaches(_ context.Context, actor string) []service.CacheState {
+	stub.flushActor = actor
+	return stub.cacheStates
+}
+
+func (stub *stubNotificationService) GetDrainStatus(context.Context) (service.DrainStatus, error) {
+	return service.DrainStatus{Draining: stub.draining}, nil
+}
+
+func (stub *stubNotificationService) Draining() bool {
+	return stub.draining
+}
+
+func (stub *stubNotificationService) Drained() <-chan struct{} {
+	return nil
+}
+
+func TestServerServesOverUnixSocketAndRemovesItOnShutdown(t *testing.T) {
+	socketDirectory, err := os.MkdirTemp("", "pinguin")
+	if err != nil {
+		t.Fatalf("temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(socketDirectory) })
+	socketPath := filepath.Join(socketDirectory, "http.sock")
+	server, err := NewServer(Config{
+		ListenAddr:          "unix://" + socketPath,
+		NotificationService: &stubNotificationService{},
+		SessionValidator:    &stubValidator{},
+		TenantRepository:    newTestTenantRepository(t),
+		Logger:              slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
+	})
+	if err != nil {
+		t.Fatalf("server init error: %v", err)
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Start()
+	}()
+
+	httpClient := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _ string, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socketPath)
+		},
+	}}
+	var response *http.Response
+	for attempt := 0; attempt < 50; attempt++ {
+		if response, err = httpClient.Get("http://pinguin/healthz"); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("healthz over unix socket: %v", err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", response.StatusCode)
+	}
+
+	if err := server.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if err := <-errCh; err != nil {
+		t.Fatalf("start returned error: %v", err)
+	}
+	if _, err := os.Stat(socketPath); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected the socket file to be removed on shutdown, got %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package httpapi
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const contextKeyServiceAccount = "service_account"
+
+// serviceAccountRoutes are the /api routes a service-account session may call: the
+// listing and status reads monitoring needs. Everything else, every mutation included,
+// is refused with 403.
+var serviceAccountRoutes = map[string]struct{}{
+	http.MethodGet + " /api/tenants":                 {},
+	http.MethodGet + " /api/notifications":           {},
+	http.MethodGet + " /api/notifications/:id":       {},
+	http.MethodGet + " /api/notification-groups/:id": {},
+	http.MethodGet + " /api/dispatch-pacing":         {},
+	http.MethodGet + " /api/capabilities":            {},
+}
+
+// serviceAccounts is the set of session emails that get read-only access to every
+// active tenant without being admins. Emails match case-insensitively.
+type serviceAccounts map[string]struct{}
+
+func newServiceAccounts(emails []string) serviceAccounts {
+	accounts := make(serviceAccounts, len(emails))
+	for _, email := range emails {
+		if normalized := strings.ToLower(strings.TrimSpace(email)); normalized != "" {
+			accounts[normalized] = struct{}{}
+		}
+	}
+	return accounts
+}
+
+func (accounts serviceAccounts) contains(email string) bool {
+	_, found := accounts[strings.ToLower(strings.TrimSpace(email))]
+	return found
+}
+
+func serviceAccountRouteAllowed(contextGin *gin.Context) bool {
+	_, allowed := serviceAccountRoutes[contextGin.Request.Method+" "+contextGin.FullPath()]
+	return allowed
+}
+
+// sessionIsServiceAccount reports whether sessionMiddleware admitted the session as a service account.
+func sessionIsServiceAccount(contextGin *gin.Context) bool {
+	return contextGin.GetBool(contextKeyServiceAccount)
+}
@@ -45,8 +45,7 @@ func (handler *smtpIdentityHandler) createIdentity(contextGin *gin.Context) {
 		EmailAddress string   `json:"email_address"`
 		ForwardTo    []string `json:"forward_to"`
 	}
-	if err := contextGin.ShouldBindJSON(&payload); err != nil {
-		contextGin.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+	if !bindJSONPayload(contextGin, &payload) {
 		return
 	}
 	address, addressErr := smtpidentity.NewAddress(payload.EmailAddress)
@@ -80,8 +79,7 @@ func (handler *smtpIdentityHandler) updateForwarding(contextGin *gin.Context) {
 	var payload struct {
 		ForwardTo []string `json:"forward_to"`
 	}
-	if err := contextGin.ShouldBindJSON(&payload); err != nil {
-		contextGin.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+	if !bindJSONPayload(contextGin, &payload) {
 		return
 	}
 	forwardTo, forwardToErr := parseForwardRecipients(payload.ForwardTo)
@@ -171,8 +169,7 @@ func (handler *smtpIdentityHandler) createSenderDomain(contextGin *gin.Context)
 	var payload struct {
 		Domain string `json:"domain"`
 	}
-	if err := contextGin.ShouldBindJSON(&payload); err != nil {
-		contextGin.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+	if !bindJSONPayload(contextGin, &payload) {
 		return
 	}
 	domain, err := handler.service.CreateSenderDomain(contextGin.Request.Context(), scope, payload.Domain)
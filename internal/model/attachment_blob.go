@@ -0,0 +1,200 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	attachmentBlobTenantIDColumn    = "tenant_id"
+	attachmentBlobContentHashColumn = "content_hash"
+	attachmentBlobRefCountColumn    = "ref_count"
+	attachmentBlobUpdatedAtColumn   = "updated_at"
+)
+
+// AttachmentBlob stores the bytes of an attachment once per tenant and SHA-256, so a
+// file sent to many recipients is kept once. RefCount is the number of attachment rows
+// pointing at the blob; the blob is deleted when the last of them is.
+type AttachmentBlob struct {
+	TenantID    string    `gorm:"primaryKey"`
+	ContentHash string    `gorm:"primaryKey;size:64"`
+	Data        []byte    `gorm:"type:blob"`
+	SizeBytes   int       `gorm:"not null"`
+	RefCount    int       `gorm:"not null;default:0"`
+	CreatedAt   time.Time `gorm:"not null"`
+	UpdatedAt   time.Time `gorm:"not null"`
+}
+
+// attachmentBlobRef is the part of an attachment row needed to release its blob.
+type attachmentBlobRef struct {
+	ID          uint
+	TenantID    string
+	ContentHash string
+}
+
+type attachmentBlobKey struct {
+	tenantID    string
+	contentHash string
+}
+
+// AttachmentContentHash returns the hex SHA-256 that keys an attachment's blob.
+func AttachmentContentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// createNotificationWithBlobs inserts n and its attachment rows inside tx. Attachment
+// bytes go to shared blobs and the rows keep only the content hash; n's attachments
+// still hold their bytes when it returns.
+func createNotificationWithBlobs(tx *gorm.DB, n *Notification) error {
+	restore, err := storeAttachmentBlobs(tx, n.Attachments)
+	defer restore()
+	if err != nil {
+		return err
+	}
+	return tx.Create(n).Error
+}
+
+// storeAttachmentBlobs takes a reference on the blob of every attachment carrying
+// bytes and clears the bytes from the row. The returned func puts the bytes back.
+func storeAttachmentBlobs(tx *gorm.DB, attachments []NotificationAttachment) (func(), error) {
+	payloads := make([][]byte, len(attachments))
+	restore := func() {
+		for index, payload := range payloads {
+			if payload != nil {
+				attachments[index].Data = payload
+			}
+		}
+	}
+	for index := range attachments {
+		attachment := &attachments[index]
+		if attachment.DataDiscarded || attachment.Data == nil {
+			continue
+		}
+		contentHash := AttachmentContentHash(attachment.Data)
+		if err := retainAttachmentBlob(tx, attachment.TenantID, contentHash, attachment.Data); err != nil {
+			return restore, fmt.Errorf("store_attachment_blob: %w", err)
+		}
+		payloads[index] = attachment.Data
+		attachment.ContentHash = contentHash
+		attachment.Data = nil
+	}
+	return restore, nil
+}
+
+func retainAttachmentBlob(tx *gorm.DB, tenantID string, contentHash string, data []byte) error {
+	blob := AttachmentBlob{TenantID: tenantID, ContentHash: contentHash, Data: data, SizeBytes: len(data)}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&blob).Error; err != nil {
+		return err
+	}
+	return adjustAttachmentBlobRefCount(tx, attachmentBlobKey{tenantID: tenantID, contentHash: contentHash}, 1)
+}
+
+// adjustAttachmentBlobRefCount applies delta to the blob's reference count and deletes
+// the blob once nothing references it.
+func adjustAttachmentBlobRefCount(tx *gorm.DB, key attachmentBlobKey, delta int) error {
+	condition := &AttachmentBlob{TenantID: key.tenantID, ContentHash: key.contentHash}
+	var blob attachmentBlobRefCount
+	err := tx.Model(&AttachmentBlob{}).
+		Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where(condition).
+		Take(&blob).Error
+	if err != nil {
+		return err
+	}
+	refCount := blob.RefCount + delta
+	if refCount <= 0 {
+		return tx.Where(condition).Delete(&AttachmentBlob{}).Error
+	}
+	return tx.Model(&AttachmentBlob{}).
+		Where(condition).
+		Updates(map[string]interface{}{
+			attachmentBlobRefCountColumn:  refCount,
+			attachmentBlobUpdatedAtColumn: time.Now().UTC(),
+		}).Error
+}
+
+type attachmentBlobRefCount struct {
+	RefCount int
+}
+
+// deleteAttachmentRows deletes the attachment rows matching condition and releases
+// their blobs, returning how many rows were deleted.
+func deleteAttachmentRows(tx *gorm.DB, condition clause.Expression) (int64, error) {
+	var refs []attachmentBlobRef
+	if err := tx.Model(&NotificationAttachment{}).Where(condition).Find(&refs).Error; err != nil {
+		return 0, err
+	}
+	releases := make(map[attachmentBlobKey]int)
+	for _, ref := range refs {
+		if ref.ContentHash != "" {
+			releases[attachmentBlobKey{tenantID: ref.TenantID, contentHash: ref.ContentHash}]++
+		}
+	}
+	for key, count := range releases {
+		if err := adjustAttachmentBlobRefCount(tx, key, -count); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, err
+		}
+	}
+	result := tx.Where(condition).Delete(&NotificationAttachment{})
+	return result.RowsAffected, result.Error
+}
+
+// hydrateAttachmentData loads the blob bytes of every deduplicated attachment on the
+// notifications. Rows written before deduplication keep their bytes inline.
+func hydrateAttachmentData(db *gorm.DB, notifications ...*Notification) error {
+	hashesByTenant := make(map[string][]interface{})
+	seen := make(map[attachmentBlobKey]bool)
+	for _, notification := range notifications {
+		for _, attachment := range notification.Attachments {
+			key := attachmentBlobKey{tenantID: attachment.TenantID, contentHash: attachment.ContentHash}
+			if attachment.ContentHash == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			hashesByTenant[key.tenantID] = append(hashesByTenant[key.tenantID], key.contentHash)
+		}
+	}
+	if len(hashesByTenant) == 0 {
+		return nil
+	}
+	blobData := make(map[attachmentBlobKey][]byte, len(seen))
+	for tenantID, hashes := range hashesByTenant {
+		var blobs []AttachmentBlob
+		err := db.Where(clause.And(
+			clause.Eq{Column: clause.Column{Name: attachmentBlobTenantIDColumn}, Value: tenantID},
+			clause.IN{Column: clause.Column{Name: attachmentBlobContentHashColumn}, Values: hashes},
+		)).Find(&blobs).Error
+		if err != nil {
+			return fmt.Errorf("load_attachment_blobs: %w", err)
+		}
+		for _, blob := range blobs {
+			blobData[attachmentBlobKey{tenantID: blob.TenantID, contentHash: blob.ContentHash}] = blob.Data
+		}
+	}
+	for _, notification := range notifications {
+		for index := range notification.Attachments {
+			attachment := &notification.Attachments[index]
+			if attachment.ContentHash == "" {
+				continue
+			}
+			attachment.Data = blobData[attachmentBlobKey{tenantID: attachment.TenantID, contentHash: attachment.ContentHash}]
+		}
+	}
+	return nil
+}
+
+// hydrateNotificationList is hydrateAttachmentData for a loaded slice.
+func hydrateNotificationList(db *gorm.DB, notifications []Notification) error {
+	pointers := make([]*Notification, len(notifications))
+	for index := range notifications {
+		pointers[index] = &notifications[index]
+	}
+	return hydrateAttachmentData(db, pointers...)
+}
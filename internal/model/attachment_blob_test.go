@@ -0,0 +1,93 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestIdenticalAttachmentsShareOneBlob(t *testing.T) {
+	db := openModelTestDatabase(t)
+	if err := db.AutoMigrate(&RenderedContent{}, &QueueIntakeKey{}, &NotificationOpen{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	ctx := context.Background()
+	statement := []byte("%PDF-1.7 monthly statement")
+	createdAt := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	for _, notificationID := range []string{"statement-1", "statement-2"} {
+		notification := Notification{
+			TenantID:         "tenant-a",
+			NotificationID:   notificationID,
+			NotificationType: NotificationEmail,
+			Recipient:        notificationID + "@example.com",
+			Status:           StatusSent,
+			CreatedAt:        createdAt,
+			Attachments:      convertEmailAttachments("tenant-a", notificationID, []EmailAttachment{{Filename: "statement.pdf", ContentType: "application/pdf", Data: statement}}),
+		}
+		if err := CreateNotification(ctx, db, &notification); err != nil {
+			t.Fatalf("create %s: %v", notificationID, err)
+		}
+		if string(notification.Attachments[0].Data) != string(statement) {
+			t.Fatalf("expected the caller's attachment to keep its bytes")
+		}
+		createdAt = createdAt.Add(time.Hour)
+	}
+
+	var blobs []AttachmentBlob
+	if err := db.Find(&blobs).Error; err != nil || len(blobs) != 1 {
+		t.Fatalf("expected one shared blob, got %d err=%v", len(blobs), err)
+	}
+	if blobs[0].RefCount != 2 || blobs[0].ContentHash != AttachmentContentHash(statement) {
+		t.Fatalf("unexpected blob %+v", blobs[0])
+	}
+	notifications, err := ListNotifications(ctx, db, "tenant-a", NotificationListFilters{})
+	if err != nil || len(notifications) != 2 {
+		t.Fatalf("expected both notifications, got %d err=%v", len(notifications), err)
+	}
+	for _, notification := range notifications {
+		if len(notification.Attachments) != 1 || string(notification.Attachments[0].Data) != string(statement) {
+			t.Fatalf("expected %s to load the shared bytes, got %+v", notification.NotificationID, notification.Attachments)
+		}
+	}
+
+	if purged, err := PurgeNotificationsBefore(ctx, db, "tenant-a", time.Date(2026, 5, 1, 9, 30, 0, 0, time.UTC)); err != nil || purged != 1 {
+		t.Fatalf("expected to purge the first statement, got %d err=%v", purged, err)
+	}
+	if err := db.Find(&blobs).Error; err != nil || len(blobs) != 1 || blobs[0].RefCount != 1 {
+		t.Fatalf("expected the blob to survive with one reference, got %+v err=%v", blobs, err)
+	}
+	remaining, err := GetNotificationByID(ctx, db, "tenant-a", "statement-2")
+	if err != nil || string(remaining.Attachments[0].Data) != string(statement) {
+		t.Fatalf("expected the remaining notification to keep its bytes, err=%v", err)
+	}
+
+	if purged, err := PurgeNotificationsBefore(ctx, db, "tenant-a", time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)); err != nil || purged != 1 {
+		t.Fatalf("expected to purge the second statement, got %d err=%v", purged, err)
+	}
+	var blobCount int64
+	if err := db.Model(&AttachmentBlob{}).Count(&blobCount).Error; err != nil || blobCount != 0 {
+		t.Fatalf("expected the unreferenced blob to be deleted, got %d err=%v", blobCount, err)
+	}
+}
+
+func TestAttachmentBlobsAreScopedPerTenant(t *testing.T) {
+	db := openModelTestDatabase(t)
+	ctx := context.Background()
+	for _, tenantID := range []string{"tenant-a", "tenant-b"} {
+		notification := Notification{
+			TenantID:         tenantID,
+			NotificationID:   tenantID + "-file",
+			NotificationType: NotificationEmail,
+			Recipient:        "user@example.com",
+			Status:           StatusQueued,
+			Attachments:      convertEmailAttachments(tenantID, tenantID+"-file", []EmailAttachment{{Filename: "a.txt", ContentType: "text/plain", Data: []byte("same bytes")}}),
+		}
+		if err := CreateNotification(ctx, db, &notification); err != nil {
+			t.Fatalf("create for %s: %v", tenantID, err)
+		}
+	}
+	var blobCount int64
+	if err := db.Model(&AttachmentBlob{}).Count(&blobCount).Error; err != nil || blobCount != 2 {
+		t.Fatalf("expected one blob per tenant, got %d err=%v", blobCount, err)
+	}
+}
@@ -0,0 +1,174 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	attachmentIntegrityIDColumn              = "id"
+	notificationIntegrityBatchSize           = 1000
+	attachmentIntegrityBatchSize             = 25
+	attachmentIntegrityRepairDeleteBatchSize = 500
+)
+
+// AttachmentIntegrityCounts tallies the problems one integrity sweep found, for the
+// whole database or for a single tenant.
+type AttachmentIntegrityCounts struct {
+	// OrphanedAttachments counts attachment rows whose (notification_id, tenant_id)
+	// parent no longer exists.
+	OrphanedAttachments int64 `json:"orphaned_attachments"`
+	// AttachmentSizeMismatches counts attachment rows whose recorded size_bytes
+	// disagrees with the bytes actually stored inline. Metadata-only rows and rows
+	// whose bytes live in a shared blob are skipped.
+	AttachmentSizeMismatches int64 `json:"attachment_size_mismatches"`
+	// DuplicateNotificationIDs counts notification ids stored under more than one tenant.
+	DuplicateNotificationIDs int64 `json:"duplicate_notification_ids"`
+	// RepairedOrphans counts orphaned attachment rows the sweep deleted.
+	RepairedOrphans int64 `json:"repaired_orphans"`
+}
+
+// AttachmentIntegrityReport is the outcome of CheckAttachmentIntegrity. Tenants holds
+// the per-tenant breakdown and only lists tenants with at least one finding.
+type AttachmentIntegrityReport struct {
+	CheckedAt time.Time `json:"checked_at"`
+	AttachmentIntegrityCounts
+	Tenants map[string]AttachmentIntegrityCounts `json:"tenants,omitempty"`
+}
+
+type notificationIntegrityKey struct {
+	ID             uint
+	TenantID       string
+	NotificationID string
+}
+
+// CheckAttachmentIntegrity sweeps notifications and attachments in id order, batch by
+// batch, because attachments reference their notification by a string pair that not
+// every driver enforces as a foreign key. With repairOrphans it deletes orphaned
+// attachment rows; every other finding is reported only.
+func CheckAttachmentIntegrity(ctx context.Context, db *gorm.DB, repairOrphans bool, now time.Time) (AttachmentIntegrityReport, error) {
+	ctx = aggregateQueries(ctx)
+	database := db.WithContext(ctx)
+	report := AttachmentIntegrityReport{CheckedAt: now.UTC(), Tenants: make(map[string]AttachmentIntegrityCounts)}
+
+	tenantsByNotificationID, err := loadNotificationTenants(database)
+	if err != nil {
+		return AttachmentIntegrityReport{}, err
+	}
+	for _, tenantIDs := range tenantsByNotificationID {
+		if len(tenantIDs) < 2 {
+			continue
+		}
+		report.DuplicateNotificationIDs++
+		for _, tenantID := range tenantIDs {
+			report.updateTenant(tenantID, func(counts *AttachmentIntegrityCounts) { counts.DuplicateNotificationIDs++ })
+		}
+	}
+
+	var orphanIDs []uint
+	var lastID uint
+	for {
+		var attachments []NotificationAttachment
+		if err := database.
+			Where(clause.Gt{Column: clause.Column{Name: attachmentIntegrityIDColumn}, Value: lastID}).
+			Order(clause.OrderByColumn{Column: clause.Column{Name: attachmentIntegrityIDColumn}}).
+			Limit(attachmentIntegrityBatchSize).
+			Find(&attachments).Error; err != nil {
+			return AttachmentIntegrityReport{}, fmt.Errorf("check_attachment_integrity: attachments: %w", err)
+		}
+		for _, attachment := range attachments {
+			if !containsString(tenantsByNotificationID[attachment.NotificationID], attachment.TenantID) {
+				orphanIDs = append(orphanIDs, attachment.ID)
+				report.OrphanedAttachments++
+				report.updateTenant(attachment.TenantID, func(counts *AttachmentIntegrityCounts) { counts.OrphanedAttachments++ })
+			}
+			if !attachment.DataDiscarded && attachment.ContentHash == "" && attachment.SizeBytes != len(attachment.Data) {
+				report.AttachmentSizeMismatches++
+				report.updateTenant(attachment.TenantID, func(counts *AttachmentIntegrityCounts) { counts.AttachmentSizeMismatches++ })
+			}
+		}
+		if len(attachments) < attachmentIntegrityBatchSize {
+			break
+		}
+		lastID = attachments[len(attachments)-1].ID
+	}
+
+	if repairOrphans && len(orphanIDs) > 0 {
+		repaired, err := deleteAttachmentsByID(database, orphanIDs)
+		if err != nil {
+			return AttachmentIntegrityReport{}, err
+		}
+		report.RepairedOrphans = repaired
+		for tenantID, counts := range report.Tenants {
+			counts.RepairedOrphans = counts.OrphanedAttachments
+			report.Tenants[tenantID] = counts
+		}
+	}
+	return report, nil
+}
+
+// loadNotificationTenants maps every stored notification id to the tenants holding it.
+func loadNotificationTenants(database *gorm.DB) (map[string][]string, error) {
+	tenantsByNotificationID := make(map[string][]string)
+	var lastID uint
+	for {
+		var keys []notificationIntegrityKey
+		if err := database.Model(&Notification{}).
+			Where(clause.Gt{Column: clause.Column{Name: notificationIDColumn}, Value: lastID}).
+			Order(clause.OrderByColumn{Column: clause.Column{Name: notificationIDColumn}}).
+			Limit(notificationIntegrityBatchSize).
+			Find(&keys).Error; err != nil {
+			return nil, fmt.Errorf("check_attachment_integrity: notifications: %w", err)
+		}
+		for _, key := range keys {
+			if !containsString(tenantsByNotificationID[key.NotificationID], key.TenantID) {
+				tenantsByNotificationID[key.NotificationID] = append(tenantsByNotificationID[key.NotificationID], key.TenantID)
+			}
+		}
+		if len(keys) < notificationIntegrityBatchSize {
+			return tenantsByNotificationID, nil
+		}
+		lastID = keys[len(keys)-1].ID
+	}
+}
+
+func deleteAttachmentsByID(database *gorm.DB, attachmentIDs []uint) (int64, error) {
+	var deleted int64
+	for start := 0; start < len(attachmentIDs); start += attachmentIntegrityRepairDeleteBatchSize {
+		end := min(start+attachmentIntegrityRepairDeleteBatchSize, len(attachmentIDs))
+		values := make([]any, 0, end-start)
+		for _, attachmentID := range attachmentIDs[start:end] {
+			values = append(values, attachmentID)
+		}
+		var batchDeleted int64
+		err := database.Transaction(func(tx *gorm.DB) error {
+			var deleteErr error
+			batchDeleted, deleteErr = deleteAttachmentRows(tx, clause.IN{Column: clause.Column{Name: attachmentIntegrityIDColumn}, Values: values})
+			return deleteErr
+		})
+		if err != nil {
+			return deleted, fmt.Errorf("check_attachment_integrity: delete orphans: %w", err)
+		}
+		deleted += batchDeleted
+	}
+	return deleted, nil
+}
+
+func (report *AttachmentIntegrityReport) updateTenant(tenantID string, update func(*AttachmentIntegrityCounts)) {
+	counts := report.Tenants[tenantID]
+	update(&counts)
+	report.Tenants[tenantID] = counts
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
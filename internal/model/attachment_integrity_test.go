@@ -0,0 +1,101 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCheckAttachmentIntegrityDetectsAndRepairsOrphans(t *testing.T) {
+	database := openModelTestDatabase(t)
+	ctx := context.Background()
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	for _, tenantID := range []string{"tenant-a", "tenant-b"} {
+		record := Notification{
+			TenantID:         tenantID,
+			NotificationID:   tenantID + "-kept",
+			NotificationType: NotificationEmail,
+			Recipient:        "user@example.com",
+			Message:          "Body",
+			Status:           StatusSent,
+			Attachments:      []NotificationAttachment{{Filename: "kept.txt", ContentType: "text/plain", SizeBytes: 4, Data: []byte("kept")}},
+		}
+		if err := CreateNotification(ctx, database, &record); err != nil {
+			t.Fatalf("seed notification: %v", err)
+		}
+	}
+	seededAttachments := []NotificationAttachment{
+		{TenantID: "tenant-a", NotificationID: "deleted-parent", Filename: "orphan.txt", SizeBytes: 6, Data: []byte("orphan")},
+		{TenantID: "tenant-a", NotificationID: "tenant-b-kept", Filename: "wrong-tenant.txt", SizeBytes: 5, Data: []byte("wrong")},
+		{TenantID: "tenant-b", NotificationID: "tenant-b-kept", Filename: "short.txt", SizeBytes: 99, Data: []byte("short")},
+		{TenantID: "tenant-b", NotificationID: "tenant-b-kept", Filename: "metadata.txt", SizeBytes: 1024, DataDiscarded: true},
+	}
+	if err := database.Create(&seededAttachments).Error; err != nil {
+		t.Fatalf("seed attachments: %v", err)
+	}
+
+	report, err := CheckAttachmentIntegrity(ctx, database, false, now)
+	if err != nil {
+		t.Fatalf("check integrity: %v", err)
+	}
+	if report.OrphanedAttachments != 2 || report.AttachmentSizeMismatches != 1 || report.DuplicateNotificationIDs != 0 || report.RepairedOrphans != 0 {
+		t.Fatalf("unexpected totals %+v", report.AttachmentIntegrityCounts)
+	}
+	if report.Tenants["tenant-a"].OrphanedAttachments != 2 || report.Tenants["tenant-b"].AttachmentSizeMismatches != 1 || !report.CheckedAt.Equal(now) {
+		t.Fatalf("unexpected per-tenant breakdown %+v", report.Tenants)
+	}
+	var attachmentRows int64
+	if err := database.Model(&NotificationAttachment{}).Count(&attachmentRows).Error; err != nil || attachmentRows != 6 {
+		t.Fatalf("expected a report-only sweep to keep every row, got %d (%v)", attachmentRows, err)
+	}
+
+	repaired, err := CheckAttachmentIntegrity(ctx, database, true, now)
+	if err != nil {
+		t.Fatalf("repair integrity: %v", err)
+	}
+	if repaired.RepairedOrphans != 2 || repaired.Tenants["tenant-a"].RepairedOrphans != 2 {
+		t.Fatalf("expected both orphans repaired, got %+v", repaired)
+	}
+	for _, tenantID := range []string{"tenant-a", "tenant-b"} {
+		stored, err := GetNotificationByID(ctx, database, tenantID, tenantID+"-kept")
+		if err != nil {
+			t.Fatalf("load %s notification: %v", tenantID, err)
+		}
+		if len(stored.Attachments) == 0 || stored.Attachments[0].Filename != "kept.txt" {
+			t.Fatalf("expected repair to keep %s attachments, got %+v", tenantID, stored.Attachments)
+		}
+	}
+	after, err := CheckAttachmentIntegrity(ctx, database, false, now)
+	if err != nil || after.OrphanedAttachments != 0 || after.AttachmentSizeMismatches != 1 {
+		t.Fatalf("expected only the unrepaired size mismatch to remain, got %+v (%v)", after.AttachmentIntegrityCounts, err)
+	}
+}
+
+func TestCheckAttachmentIntegrityCountsCrossTenantDuplicateIDs(t *testing.T) {
+	database := openModelTestDatabase(t)
+	ctx := context.Background()
+	if err := database.Migrator().DropIndex(&Notification{}, "idx_tenant_notification"); err != nil {
+		t.Fatalf("drop unique index: %v", err)
+	}
+	for _, tenantID := range []string{"tenant-a", "tenant-b", "tenant-c"} {
+		notificationID := "shared-id"
+		if tenantID == "tenant-c" {
+			notificationID = "unique-id"
+		}
+		record := Notification{TenantID: tenantID, NotificationID: notificationID, NotificationType: NotificationSMS, Recipient: "+15555550100", Message: "Body", Status: StatusSent}
+		if err := database.Create(&record).Error; err != nil {
+			t.Fatalf("seed notification: %v", err)
+		}
+	}
+
+	report, err := CheckAttachmentIntegrity(ctx, database, false, time.Now())
+	if err != nil {
+		t.Fatalf("check integrity: %v", err)
+	}
+	if report.DuplicateNotificationIDs != 1 || report.Tenants["tenant-a"].DuplicateNotificationIDs != 1 || report.Tenants["tenant-b"].DuplicateNotificationIDs != 1 {
+		t.Fatalf("expected one duplicated id across two tenants, got %+v", report)
+	}
+	if _, listed := report.Tenants["tenant-c"]; listed {
+		t.Fatalf("expected clean tenants to be omitted, got %+v", report.Tenants)
+	}
+}
@@ -0,0 +1,147 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+const (
+	// CalendarMethodRequest sends a new or updated meeting invitation.
+	CalendarMethodRequest = "REQUEST"
+	// CalendarMethodCancel withdraws a previously sent invitation.
+	CalendarMethodCancel = "CANCEL"
+	// CalendarInviteFilename names the .ics attachment carried next to the inline invite.
+	CalendarInviteFilename = "invite.ics"
+
+	calendarMediaType       = "text/calendar"
+	calendarMethodParameter = "method"
+	calendarContentTemplate = "text/calendar; charset=\"utf-8\"; method=%s"
+	calendarMethodProperty  = "METHOD"
+	calendarBeginCalendar   = "BEGIN:VCALENDAR"
+	calendarEndCalendar     = "END:VCALENDAR"
+	calendarBeginEvent      = "BEGIN:VEVENT"
+	calendarEndEvent        = "END:VEVENT"
+	calendarUIDProperty     = "UID"
+	calendarDTStartProperty = "DTSTART"
+)
+
+// ErrNotificationCalendarEventInvalid indicates a calendar event that mail clients would not render as an invite.
+var ErrNotificationCalendarEventInvalid = errors.New("notification.request.invalid_calendar_event")
+
+// WithCalendarEvent returns a copy of an email request that carries an iCalendar invite.
+// The ICS text must hold one VCALENDAR with at least one VEVENT that has a UID and a
+// DTSTART. An empty method means REQUEST; a METHOD property already in the text must
+// agree with it and is added when missing. The invite travels as an attachment whose
+// content type carries the method, which is how buildEmailMessage recognises it.
+func (request NotificationRequest) WithCalendarEvent(ics string, method string) (NotificationRequest, error) {
+	if request.notificationType != NotificationEmail {
+		return NotificationRequest{}, fmt.Errorf("%w: calendar events require email notifications", ErrNotificationCalendarEventInvalid)
+	}
+	if request.contentEncrypted {
+		return NotificationRequest{}, fmt.Errorf("%w: calendar events must be inside the encrypted MIME entity", ErrNotificationEncryptedContentIncompatible)
+	}
+	for _, attachment := range request.attachments {
+		if _, isInvite := CalendarInviteMethod(attachment.ContentType); isInvite {
+			return NotificationRequest{}, fmt.Errorf("%w: only one calendar event is allowed", ErrNotificationCalendarEventInvalid)
+		}
+	}
+	normalizedMethod := strings.ToUpper(strings.TrimSpace(method))
+	if normalizedMethod == "" {
+		normalizedMethod = CalendarMethodRequest
+	}
+	if normalizedMethod != CalendarMethodRequest && normalizedMethod != CalendarMethodCancel {
+		return NotificationRequest{}, fmt.Errorf("%w: method must be REQUEST or CANCEL", ErrNotificationCalendarEventInvalid)
+	}
+	normalizedICS, err := normalizeCalendarEvent(ics, normalizedMethod)
+	if err != nil {
+		return NotificationRequest{}, err
+	}
+	attachments, err := normalizeNotificationAttachments(request.notificationType, append(append([]EmailAttachment(nil), request.attachments...), EmailAttachment{
+		Filename:    CalendarInviteFilename,
+		ContentType: fmt.Sprintf(calendarContentTemplate, normalizedMethod),
+		Data:        []byte(normalizedICS),
+	}))
+	if err != nil {
+		return NotificationRequest{}, err
+	}
+	request.attachments = attachments
+	return request, nil
+}
+
+// CalendarInviteMethod reports the iTIP method of a text/calendar content type that
+// carries one, which marks an attachment as an invite rather than a plain file.
+func CalendarInviteMethod(contentType string) (string, bool) {
+	mediaType, parameters, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != calendarMediaType {
+		return "", false
+	}
+	method := strings.ToUpper(strings.TrimSpace(parameters[calendarMethodParameter]))
+	return method, method != ""
+}
+
+// normalizeCalendarEvent checks the structure mail clients need and rewrites line
+// endings to the CRLF that RFC 5545 requires.
+func normalizeCalendarEvent(ics string, method string) (string, error) {
+	rawLines := strings.Split(strings.ReplaceAll(strings.TrimSpace(ics), "\r\n", "\n"), "\n")
+	lines := make([]string, 0, len(rawLines)+1)
+	calendarCount, calendarDepth, eventCount := 0, 0, 0
+	inEvent, eventHasUID, eventHasStart, hasMethod := false, false, false, false
+	for _, line := range rawLines {
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			continue
+		}
+		lines = append(lines, line)
+		if line[0] == ' ' || line[0] == '\t' {
+			// Folded continuation of the previous content line.
+			continue
+		}
+		name := calendarPropertyName(line)
+		switch {
+		case strings.EqualFold(line, calendarBeginCalendar):
+			calendarCount++
+			calendarDepth++
+		case strings.EqualFold(line, calendarEndCalendar):
+			calendarDepth--
+		case strings.EqualFold(line, calendarBeginEvent):
+			inEvent, eventHasUID, eventHasStart = true, false, false
+		case strings.EqualFold(line, calendarEndEvent):
+			if !inEvent || !eventHasUID || !eventHasStart {
+				return "", fmt.Errorf("%w: every VEVENT needs UID and DTSTART", ErrNotificationCalendarEventInvalid)
+			}
+			inEvent = false
+			eventCount++
+		case name == calendarMethodProperty && !inEvent:
+			hasMethod = true
+			_, value, _ := strings.Cut(line, ":")
+			if value = strings.ToUpper(strings.TrimSpace(value)); value != method {
+				return "", fmt.Errorf("%w: METHOD %s does not match method %s", ErrNotificationCalendarEventInvalid, value, method)
+			}
+		case name == calendarUIDProperty && inEvent:
+			eventHasUID = true
+		case name == calendarDTStartProperty && inEvent:
+			eventHasStart = true
+		}
+	}
+	if len(lines) < 2 || !strings.EqualFold(lines[0], calendarBeginCalendar) || !strings.EqualFold(lines[len(lines)-1], calendarEndCalendar) || calendarCount != 1 || calendarDepth != 0 {
+		return "", fmt.Errorf("%w: expected a single VCALENDAR", ErrNotificationCalendarEventInvalid)
+	}
+	if inEvent || eventCount == 0 {
+		return "", fmt.Errorf("%w: expected at least one complete VEVENT", ErrNotificationCalendarEventInvalid)
+	}
+	if !hasMethod {
+		lines = append([]string{lines[0], calendarMethodProperty + ":" + method}, lines[1:]...)
+	}
+	return strings.Join(lines, "\r\n") + "\r\n", nil
+}
+
+// calendarPropertyName returns the upper-cased name of a content line, without parameters.
+func calendarPropertyName(line string) string {
+	end := strings.IndexAny(line, ";:")
+	if end < 0 {
+		return ""
+	}
+	return strings.ToUpper(line[:end])
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const sampleCalendarEvent = "BEGIN:VCALENDAR\nVERSION:2.0\nPRODID:-//Example//Scheduler//EN\nBEGIN:VEVENT\nUID:meeting-1@example.com\nDTSTART:20260501T090000Z\nSUMMARY:Planning\n with folded text\nEND:VEVENT\nEND:VCALENDAR\n"
+
+func TestNotificationRequestWithCalendarEvent(t *testing.T) {
+	emailRequest, err := NewNotificationRequest(NotificationEmail, sampleRecipient, "Invite", sampleMessage, nil, []EmailAttachment{{Filename: sampleFilename, ContentType: sampleContentType, Data: []byte("agenda")}})
+	if err != nil {
+		t.Fatalf("email request: %v", err)
+	}
+	smsRequest, err := NewNotificationRequest(NotificationSMS, "+15555550100", "", sampleMessage, nil, nil)
+	if err != nil {
+		t.Fatalf("sms request: %v", err)
+	}
+	testCases := []struct {
+		name                string
+		request             NotificationRequest
+		ics                 string
+		method              string
+		expectedContentType string
+		expectedError       string
+	}{
+		{name: "request by default", request: emailRequest, ics: sampleCalendarEvent, expectedContentType: `text/calendar; charset="utf-8"; method=REQUEST`},
+		{name: "cancel", request: emailRequest, ics: strings.Replace(sampleCalendarEvent, "VERSION:2.0", "METHOD:CANCEL\nVERSION:2.0", 1), method: "cancel", expectedContentType: `text/calendar; charset="utf-8"; method=CANCEL`},
+		{name: "sms", request: smsRequest, ics: sampleCalendarEvent, expectedError: "require email"},
+		{name: "unsupported method", request: emailRequest, ics: sampleCalendarEvent, method: "PUBLISH", expectedError: "REQUEST or CANCEL"},
+		{name: "method mismatch", request: emailRequest, ics: strings.Replace(sampleCalendarEvent, "VERSION:2.0", "METHOD:REQUEST\nVERSION:2.0", 1), method: CalendarMethodCancel, expectedError: "does not match"},
+		{name: "not a calendar", request: emailRequest, ics: "hello", expectedError: "single VCALENDAR"},
+		{name: "two calendars", request: emailRequest, ics: sampleCalendarEvent + sampleCalendarEvent, expectedError: "single VCALENDAR"},
+		{name: "no event", request: emailRequest, ics: "BEGIN:VCALENDAR\nVERSION:2.0\nEND:VCALENDAR", expectedError: "complete VEVENT"},
+		{name: "event without uid", request: emailRequest, ics: strings.Replace(sampleCalendarEvent, "UID:meeting-1@example.com\n", "", 1), expectedError: "UID and DTSTART"},
+		{name: "unterminated event", request: emailRequest, ics: strings.Replace(sampleCalendarEvent, "END:VEVENT\n", "", 1), expectedError: "complete VEVENT"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			updated, err := testCase.request.WithCalendarEvent(testCase.ics, testCase.method)
+			if testCase.expectedError != "" {
+				if !errors.Is(err, ErrNotificationCalendarEventInvalid) || !strings.Contains(err.Error(), testCase.expectedError) {
+					t.Fatalf("expected invalid calendar event mentioning %q, got %v", testCase.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			attachments := updated.Attachments()
+			if len(attachments) != 2 || len(testCase.request.Attachments()) != 1 {
+				t.Fatalf("expected the invite appended to a copy, got %+v", attachments)
+			}
+			invite := attachments[1]
+			if invite.Filename != CalendarInviteFilename || invite.ContentType != testCase.expectedContentType {
+				t.Fatalf("unexpected invite attachment %s %q", invite.Filename, invite.ContentType)
+			}
+			icsText := string(invite.Data)
+			method, _ := CalendarInviteMethod(invite.ContentType)
+			if strings.Count(icsText, "METHOD:") != 1 || !strings.Contains(icsText, "\r\nMETHOD:"+method+"\r\n") {
+				t.Fatalf("expected exactly one METHOD property, got %q", icsText)
+			}
+			if strings.Contains(strings.ReplaceAll(icsText, "\r\n", ""), "\n") || !strings.Contains(icsText, "\r\n with folded text\r\n") {
+				t.Fatalf("expected CRLF line endings with folding preserved, got %q", icsText)
+			}
+			if _, again := updated.WithCalendarEvent(testCase.ics, testCase.method); again == nil || !strings.Contains(again.Error(), "only one") {
+				t.Fatalf("expected a second calendar event to be rejected, got %v", again)
+			}
+		})
+	}
+}
+
+func TestCalendarInviteMethod(t *testing.T) {
+	for contentType, expected := range map[string]string{
+		`text/calendar; charset="utf-8"; method=REQUEST`: CalendarMethodRequest,
+		"text/calendar; method=cancel":                   CalendarMethodCancel,
+		"text/calendar":                                  "",
+		"application/ics; method=REQUEST":                "",
+		"not a media type;;":                             "",
+	} {
+		method, isInvite := CalendarInviteMethod(contentType)
+		if method != expected || isInvite != (expected != "") {
+			t.Fatalf("CalendarInviteMethod(%q) = %q, %v; want %q", contentType, method, isInvite, expected)
+		}
+	}
+}
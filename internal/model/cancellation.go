@@ -0,0 +1,42 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxCancelReasonLength bounds the characters of a caller-supplied cancellation reason.
+const MaxCancelReasonLength = 200
+
+const (
+	// CancelActorSystem marks notifications Pinguin cancelled itself, e.g. on expiry.
+	CancelActorSystem = "system"
+	// CancelActorGRPC marks notifications cancelled through the gRPC API. Tokens carry
+	// no identity, so the channel is the most specific actor available.
+	CancelActorGRPC = "grpc"
+)
+
+// ErrCancelReasonInvalid reports a cancellation reason that is too long or carries control characters.
+var ErrCancelReasonInvalid = errors.New("notification.cancel.invalid_reason")
+
+// NormalizeCancelReason trims a caller-supplied cancellation reason and checks its
+// length and characters. An empty reason is allowed.
+func NormalizeCancelReason(reason string) (string, error) {
+	normalized := strings.TrimSpace(reason)
+	if utf8.RuneCountInString(normalized) > MaxCancelReasonLength {
+		return "", fmt.Errorf("%w: reason must be at most %d characters", ErrCancelReasonInvalid, MaxCancelReasonLength)
+	}
+	if strings.IndexFunc(normalized, unicode.IsControl) >= 0 {
+		return "", fmt.Errorf("%w: reason contains control characters", ErrCancelReasonInvalid)
+	}
+	return normalized, nil
+}
+
+// IsSystemCancelReason reports whether reason is one Pinguin records when it cancels
+// a notification itself.
+func IsSystemCancelReason(reason string) bool {
+	return reason == CancelReasonExpired || reason == CancelReasonAttachmentDataUnavailable
+}
@@ -0,0 +1,223 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// DailyReportDateLayout formats the tenant-local calendar day a report covers.
+	DailyReportDateLayout = "2006-01-02"
+
+	notificationUpdatedAtColumn = "updated_at"
+	reportRunTenantIDColumn     = "tenant_id"
+	reportRunReportDateColumn   = "report_date"
+)
+
+// ReportRunStatus tracks how far a daily report run progressed.
+type ReportRunStatus string
+
+const (
+	// ReportRunClaimed means a worker owns the (tenant, date) slot and is sending the report.
+	ReportRunClaimed ReportRunStatus = "claimed"
+	// ReportRunSent means every report notification was accepted.
+	ReportRunSent ReportRunStatus = "sent"
+	// ReportRunFailed means the run stopped early; it is not retried to avoid double sends.
+	ReportRunFailed ReportRunStatus = "failed"
+)
+
+// ErrInvalidDailyReportDay reports a report day without a location.
+var ErrInvalidDailyReportDay = errors.New("notification.daily_report.invalid_day")
+
+// ReportRun records that the daily report for a tenant and local date was claimed.
+type ReportRun struct {
+	ID                uint            `gorm:"primaryKey"`
+	TenantID          string          `gorm:"index:idx_report_run_tenant_date,unique"`
+	ReportDate        string          `gorm:"index:idx_report_run_tenant_date,unique"`
+	Status            ReportRunStatus `gorm:"index"`
+	NotificationCount int
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// ClaimReportRun inserts the (tenant, date) run; claimed is false when another run already owns it.
+func ClaimReportRun(ctx context.Context, db *gorm.DB, tenantID string, reportDate string, now time.Time) (bool, error) {
+	run := ReportRun{TenantID: tenantID, ReportDate: reportDate, Status: ReportRunClaimed, CreatedAt: now, UpdatedAt: now}
+	result := db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: reportRunTenantIDColumn}, {Name: reportRunReportDateColumn}},
+			DoNothing: true,
+		}).
+		Create(&run)
+	if result.Error != nil {
+		return false, fmt.Errorf("claim_report_run: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// FinishReportRun stores the final status of a claimed run.
+func FinishReportRun(ctx context.Context, db *gorm.DB, tenantID string, reportDate string, status ReportRunStatus, notificationCount int, now time.Time) error {
+	err := db.WithContext(ctx).
+		Model(&ReportRun{}).
+		Where(&ReportRun{TenantID: tenantID, ReportDate: reportDate}).
+		Updates(&ReportRun{Status: status, NotificationCount: notificationCount, UpdatedAt: now}).Error
+	if err != nil {
+		return fmt.Errorf("finish_report_run: %w", err)
+	}
+	return nil
+}
+
+// DailyReportDay is one tenant-local calendar day, stored as a half-open UTC window.
+type DailyReportDay struct {
+	date  string
+	start time.Time
+	end   time.Time
+}
+
+// NewDailyReportDay returns the local calendar day before the one containing now.
+// The window follows the location's midnights, so DST days span 23 or 25 hours.
+func NewDailyReportDay(now time.Time, location *time.Location) (DailyReportDay, error) {
+	if location == nil || now.IsZero() {
+		return DailyReportDay{}, fmt.Errorf("%w: time and location are required", ErrInvalidDailyReportDay)
+	}
+	localNow := now.In(location)
+	end := time.Date(localNow.Year(), localNow.Month(), localNow.Day(), 0, 0, 0, 0, location)
+	start := time.Date(localNow.Year(), localNow.Month(), localNow.Day()-1, 0, 0, 0, 0, location)
+	return DailyReportDay{date: start.Format(DailyReportDateLayout), start: start.UTC(), end: end.UTC()}, nil
+}
+
+// Date returns the local calendar date in YYYY-MM-DD form.
+func (day DailyReportDay) Date() string {
+	return day.date
+}
+
+// Start returns the inclusive UTC window start.
+func (day DailyReportDay) Start() time.Time {
+	return day.start
+}
+
+// End returns the exclusive UTC window end.
+func (day DailyReportDay) End() time.Time {
+	return day.end
+}
+
+// ReportCount pairs a label with the number of notifications carrying it.
+type ReportCount struct {
+	Label string
+	Count int
+}
+
+// DailyActivitySummary aggregates the notifications a tenant touched during one report day.
+type DailyActivitySummary struct {
+	TenantID           string
+	Day                DailyReportDay
+	SentCount          int
+	ErroredCount       int
+	CancelledCount     int
+	QueuedCount        int
+	AverageRetryCount  float64
+	ErroredByChannel   []ReportCount
+	CancelledByReason  []ReportCount
+	StuckCount         int
+	OldestStuckDueTime *time.Time
+}
+
+// SummarizeDailyActivity groups notifications last updated during the day and counts queued
+// notifications that were due before the day ended but have still not left the queue.
+func SummarizeDailyActivity(ctx context.Context, db *gorm.DB, tenantID string, day DailyReportDay) (DailyActivitySummary, error) {
+	ctx = aggregateQueries(ctx)
+	var updatedNotifications []Notification
+	updatedAtColumn := clause.Column{Name: notificationUpdatedAtColumn}
+	err := db.WithContext(ctx).
+		Where(&Notification{TenantID: tenantID}).
+		Where(clause.And(
+			clause.Gte{Column: updatedAtColumn, Value: day.Start()},
+			clause.Lt{Column: updatedAtColumn, Value: day.End()},
+		)).
+		Find(&updatedNotifications).Error
+	if err != nil {
+		return DailyActivitySummary{}, fmt.Errorf("summarize_daily_activity: %w", err)
+	}
+
+	var stuckNotifications []Notification
+	scheduledForColumn := clause.Column{Name: notificationScheduledForColumn}
+	err = db.WithContext(ctx).
+		Where(&Notification{TenantID: tenantID, Status: StatusQueued}).
+		Where(clause.Or(
+			clause.Lt{Column: scheduledForColumn, Value: day.End()},
+			clause.And(
+				clause.Eq{Column: scheduledForColumn, Value: nil},
+				clause.Lt{Column: clause.Column{Name: notificationCreatedAtColumn}, Value: day.End()},
+			),
+		)).
+		Find(&stuckNotifications).Error
+	if err != nil {
+		return DailyActivitySummary{}, fmt.Errorf("summarize_daily_activity: stuck: %w", err)
+	}
+	return buildDailyActivitySummary(tenantID, day, updatedNotifications, stuckNotifications), nil
+}
+
+func buildDailyActivitySummary(tenantID string, day DailyReportDay, updatedNotifications []Notification, stuckNotifications []Notification) DailyActivitySummary {
+	summary := DailyActivitySummary{TenantID: tenantID, Day: day}
+	erroredByChannel := make(map[string]int)
+	cancelledByReason := make(map[string]int)
+	totalRetries := 0
+	for _, notification := range updatedNotifications {
+		totalRetries += notification.RetryCount
+		switch notification.Status {
+		case StatusSent:
+			summary.SentCount++
+		case StatusErrored:
+			summary.ErroredCount++
+			erroredByChannel[string(notification.NotificationType)]++
+		case StatusCancelled:
+			summary.CancelledCount++
+			// Reasons callers give are free text, so only system reasons get their own row.
+			reason := notification.CancelReason
+			if !IsSystemCancelReason(reason) {
+				reason = "manual"
+			}
+			cancelledByReason[reason]++
+		case StatusQueued:
+			summary.QueuedCount++
+		}
+	}
+	if len(updatedNotifications) > 0 {
+		summary.AverageRetryCount = float64(totalRetries) / float64(len(updatedNotifications))
+	}
+	summary.ErroredByChannel = sortedReportCounts(erroredByChannel)
+	summary.CancelledByReason = sortedReportCounts(cancelledByReason)
+
+	summary.StuckCount = len(stuckNotifications)
+	for _, notification := range stuckNotifications {
+		dueTime := notification.CreatedAt.UTC()
+		if notification.ScheduledFor != nil {
+			dueTime = notification.ScheduledFor.UTC()
+		}
+		if summary.OldestStuckDueTime == nil || dueTime.Before(*summary.OldestStuckDueTime) {
+			oldest := dueTime
+			summary.OldestStuckDueTime = &oldest
+		}
+	}
+	return summary
+}
+
+func sortedReportCounts(countsByLabel map[string]int) []ReportCount {
+	counts := make([]ReportCount, 0, len(countsByLabel))
+	for label, count := range countsByLabel {
+		counts = append(counts, ReportCount{Label: label, Count: count})
+	}
+	sort.Slice(counts, func(leftIndex int, rightIndex int) bool {
+		if counts[leftIndex].Count != counts[rightIndex].Count {
+			return counts[leftIndex].Count > counts[rightIndex].Count
+		}
+		return counts[leftIndex].Label < counts[rightIndex].Label
+	})
+	return counts
+}
@@ -0,0 +1,142 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewDailyReportDayFollowsLocalMidnights(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+	testCases := []struct {
+		name          string
+		now           time.Time
+		location      *time.Location
+		expectedDate  string
+		expectedStart time.Time
+		expectedEnd   time.Time
+	}{
+		{
+			name:          "utc day",
+			now:           time.Date(2026, 4, 2, 8, 0, 0, 0, time.UTC),
+			location:      time.UTC,
+			expectedDate:  "2026-04-01",
+			expectedStart: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2026, 4, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "local day lags the utc date",
+			now:           time.Date(2026, 4, 2, 2, 0, 0, 0, time.UTC),
+			location:      newYork,
+			expectedDate:  "2026-03-31",
+			expectedStart: time.Date(2026, 3, 31, 4, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2026, 4, 1, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "spring forward day spans 23 hours",
+			now:           time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC),
+			location:      newYork,
+			expectedDate:  "2026-03-08",
+			expectedStart: time.Date(2026, 3, 8, 5, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2026, 3, 9, 4, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			day, err := NewDailyReportDay(testCase.now, testCase.location)
+			if err != nil {
+				t.Fatalf("report day: %v", err)
+			}
+			if day.Date() != testCase.expectedDate || !day.Start().Equal(testCase.expectedStart) || !day.End().Equal(testCase.expectedEnd) {
+				t.Fatalf("expected %s [%s, %s), got %s [%s, %s)", testCase.expectedDate, testCase.expectedStart, testCase.expectedEnd, day.Date(), day.Start(), day.End())
+			}
+		})
+	}
+	if _, err := NewDailyReportDay(time.Now(), nil); !errors.Is(err, ErrInvalidDailyReportDay) {
+		t.Fatalf("expected invalid day error, got %v", err)
+	}
+}
+
+func TestClaimReportRunIsIdempotentPerTenantAndDate(t *testing.T) {
+	database := openModelTestDatabase(t)
+	now := time.Date(2026, 4, 2, 8, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	claimed, err := ClaimReportRun(ctx, database, "tenant-a", "2026-04-01", now)
+	if err != nil || !claimed {
+		t.Fatalf("expected first claim, got claimed=%v err=%v", claimed, err)
+	}
+	claimed, err = ClaimReportRun(ctx, database, "tenant-a", "2026-04-01", now.Add(time.Minute))
+	if err != nil || claimed {
+		t.Fatalf("expected duplicate claim to be refused, got claimed=%v err=%v", claimed, err)
+	}
+	for _, other := range [][2]string{{"tenant-b", "2026-04-01"}, {"tenant-a", "2026-04-02"}} {
+		if claimed, err := ClaimReportRun(ctx, database, other[0], other[1], now); err != nil || !claimed {
+			t.Fatalf("expected independent claim for %v, got claimed=%v err=%v", other, claimed, err)
+		}
+	}
+
+	if err := FinishReportRun(ctx, database, "tenant-a", "2026-04-01", ReportRunSent, 2, now); err != nil {
+		t.Fatalf("finish run: %v", err)
+	}
+	var stored ReportRun
+	if err := database.Where(&ReportRun{TenantID: "tenant-a", ReportDate: "2026-04-01"}).First(&stored).Error; err != nil {
+		t.Fatalf("fetch run: %v", err)
+	}
+	if stored.Status != ReportRunSent || stored.NotificationCount != 2 {
+		t.Fatalf("unexpected run %+v", stored)
+	}
+}
+
+func TestSummarizeDailyActivity(t *testing.T) {
+	database := openModelTestDatabase(t)
+	day, err := NewDailyReportDay(time.Date(2026, 4, 2, 8, 0, 0, 0, time.UTC), time.UTC)
+	if err != nil {
+		t.Fatalf("report day: %v", err)
+	}
+	inside := day.Start().Add(6 * time.Hour)
+	beforeDay := day.Start().Add(-time.Hour)
+	afterDay := day.End().Add(time.Hour)
+	records := []Notification{
+		{NotificationID: "sent", TenantID: "tenant-a", NotificationType: NotificationEmail, Status: StatusSent, RetryCount: 1, CreatedAt: inside, UpdatedAt: inside},
+		{NotificationID: "errored-email", TenantID: "tenant-a", NotificationType: NotificationEmail, Status: StatusErrored, RetryCount: 3, CreatedAt: inside, UpdatedAt: inside},
+		{NotificationID: "errored-sms", TenantID: "tenant-a", NotificationType: NotificationSMS, Status: StatusErrored, RetryCount: 2, CreatedAt: inside, UpdatedAt: inside},
+		{NotificationID: "errored-sms-2", TenantID: "tenant-a", NotificationType: NotificationSMS, Status: StatusErrored, RetryCount: 2, CreatedAt: inside, UpdatedAt: inside},
+		{NotificationID: "expired", TenantID: "tenant-a", NotificationType: NotificationEmail, Status: StatusCancelled, CancelReason: CancelReasonExpired, CreatedAt: inside, UpdatedAt: inside},
+		{NotificationID: "cancelled", TenantID: "tenant-a", NotificationType: NotificationEmail, Status: StatusCancelled, CreatedAt: inside, UpdatedAt: inside},
+		{NotificationID: "stuck-old", TenantID: "tenant-a", NotificationType: NotificationEmail, Status: StatusQueued, ScheduledFor: &beforeDay, CreatedAt: beforeDay, UpdatedAt: beforeDay},
+		{NotificationID: "stuck-unscheduled", TenantID: "tenant-a", NotificationType: NotificationEmail, Status: StatusQueued, CreatedAt: inside, UpdatedAt: inside},
+		{NotificationID: "future", TenantID: "tenant-a", NotificationType: NotificationEmail, Status: StatusQueued, ScheduledFor: &afterDay, CreatedAt: inside, UpdatedAt: inside},
+		{NotificationID: "next-day", TenantID: "tenant-a", NotificationType: NotificationEmail, Status: StatusSent, CreatedAt: afterDay, UpdatedAt: afterDay},
+		{NotificationID: "other-tenant", TenantID: "tenant-b", NotificationType: NotificationEmail, Status: StatusSent, CreatedAt: inside, UpdatedAt: inside},
+	}
+	for index := range records {
+		if err := CreateNotification(context.Background(), database, &records[index]); err != nil {
+			t.Fatalf("insert %s: %v", records[index].NotificationID, err)
+		}
+	}
+
+	summary, err := SummarizeDailyActivity(context.Background(), database, "tenant-a", day)
+	if err != nil {
+		t.Fatalf("summarize: %v", err)
+	}
+	if summary.SentCount != 1 || summary.ErroredCount != 3 || summary.CancelledCount != 2 || summary.QueuedCount != 2 {
+		t.Fatalf("unexpected status counts %+v", summary)
+	}
+	if summary.AverageRetryCount != 1 {
+		t.Fatalf("expected average retry count 1, got %v", summary.AverageRetryCount)
+	}
+	if len(summary.ErroredByChannel) != 2 || summary.ErroredByChannel[0] != (ReportCount{Label: "sms", Count: 2}) {
+		t.Fatalf("unexpected errored breakdown %+v", summary.ErroredByChannel)
+	}
+	if len(summary.CancelledByReason) != 2 || summary.CancelledByReason[0].Label != CancelReasonExpired {
+		t.Fatalf("unexpected cancelled breakdown %+v", summary.CancelledByReason)
+	}
+	if summary.StuckCount != 2 || summary.OldestStuckDueTime == nil || !summary.OldestStuckDueTime.Equal(beforeDay) {
+		t.Fatalf("unexpected stuck summary %d %v", summary.StuckCount, summary.OldestStuckDueTime)
+	}
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+// ErrDatabaseBusy reports that SQLite stayed locked through every retry; callers should
+// back off and try again rather than treat it as an internal failure.
+var ErrDatabaseBusy = errors.New("notification.storage.busy")
+
+const (
+	sqliteResultBusy   = 5
+	sqliteResultLocked = 6
+
+	busyRetryInitialDelay = 20 * time.Millisecond
+	busyRetryMaxDelay     = 250 * time.Millisecond
+	// busyRetryTotalDelay bounds the time spent sleeping between attempts; each attempt
+	// may additionally wait up to the connection's busy_timeout.
+	busyRetryTotalDelay = 2 * time.Second
+)
+
+// sqliteCodedError matches the driver's error without importing it.
+type sqliteCodedError interface {
+	Code() int
+}
+
+// busyRetrySleep waits between attempts; tests replace it to avoid real sleeps.
+var busyRetrySleep = func(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// IsDatabaseBusy reports whether err is SQLite's SQLITE_BUSY or SQLITE_LOCKED, including
+// their extended result codes.
+func IsDatabaseBusy(err error) bool {
+	if err == nil {
+		return false
+	}
+	var codedErr sqliteCodedError
+	if errors.As(err, &codedErr) {
+		primaryCode := codedErr.Code() & 0xff
+		return primaryCode == sqliteResultBusy || primaryCode == sqliteResultLocked
+	}
+	message := err.Error()
+	return strings.Contains(message, "SQLITE_BUSY") || strings.Contains(message, "SQLITE_LOCKED") || strings.Contains(message, "database is locked")
+}
+
+// retryOnBusy runs operation again while it fails with a busy or locked database, with
+// jittered exponential backoff capped by busyRetryTotalDelay. Exhausted retries return
+// ErrDatabaseBusy wrapping the last driver error; other errors pass through unchanged.
+func retryOnBusy(ctx context.Context, operation func() error) error {
+	delay := busyRetryInitialDelay
+	var slept time.Duration
+	for {
+		err := operation()
+		if !IsDatabaseBusy(err) {
+			return err
+		}
+		if slept >= busyRetryTotalDelay {
+			return fmt.Errorf("%w: %w", ErrDatabaseBusy, err)
+		}
+		jittered := delay/2 + rand.N(delay/2+1)
+		jittered = min(jittered, busyRetryTotalDelay-slept)
+		if sleepErr := busyRetrySleep(ctx, jittered); sleepErr != nil {
+			return fmt.Errorf("%w: %w", ErrDatabaseBusy, err)
+		}
+		slept += jittered
+		delay = min(delay*2, busyRetryMaxDelay)
+	}
+}
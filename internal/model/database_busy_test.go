@@ -0,0 +1,102 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type codedSQLiteError struct {
+	code int
+}
+
+func (err codedSQLiteError) Error() string { return fmt.Sprintf("sqlite error (%d)", err.code) }
+
+func (err codedSQLiteError) Code() int { return err.code }
+
+func stubBusyRetrySleep(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var delays []time.Duration
+	originalSleep := busyRetrySleep
+	busyRetrySleep = func(ctx context.Context, delay time.Duration) error {
+		delays = append(delays, delay)
+		return ctx.Err()
+	}
+	t.Cleanup(func() { busyRetrySleep = originalSleep })
+	return &delays
+}
+
+func TestIsDatabaseBusy(t *testing.T) {
+	for _, testCase := range []struct {
+		err  error
+		busy bool
+	}{
+		{err: codedSQLiteError{code: 5}, busy: true},
+		{err: codedSQLiteError{code: 6}, busy: true},
+		{err: fmt.Errorf("save: %w", codedSQLiteError{code: 517}), busy: true},
+		{err: codedSQLiteError{code: 19}, busy: false},
+		{err: errors.New("database is locked (5) (SQLITE_BUSY)"), busy: true},
+		{err: errors.New("constraint failed"), busy: false},
+		{err: nil, busy: false},
+	} {
+		if busy := IsDatabaseBusy(testCase.err); busy != testCase.busy {
+			t.Fatalf("IsDatabaseBusy(%v) = %v, want %v", testCase.err, busy, testCase.busy)
+		}
+	}
+}
+
+func TestRetryOnBusyRecoversAfterTransientLocks(t *testing.T) {
+	delays := stubBusyRetrySleep(t)
+	attempts := 0
+	err := retryOnBusy(context.Background(), func() error {
+		attempts++
+		if attempts < 3 {
+			return codedSQLiteError{code: 5}
+		}
+		return nil
+	})
+	if err != nil || attempts != 3 || len(*delays) != 2 {
+		t.Fatalf("expected success on the third attempt after two waits, got %v after %d attempts and %v", err, attempts, *delays)
+	}
+	if (*delays)[0] < busyRetryInitialDelay/2 || (*delays)[0] > busyRetryInitialDelay {
+		t.Fatalf("expected the first wait to be jittered within the initial delay, got %s", (*delays)[0])
+	}
+}
+
+func TestRetryOnBusyGivesUpWithErrDatabaseBusy(t *testing.T) {
+	delays := stubBusyRetrySleep(t)
+	driverErr := codedSQLiteError{code: 5}
+	err := retryOnBusy(context.Background(), func() error { return driverErr })
+	if !errors.Is(err, ErrDatabaseBusy) || !errors.Is(err, driverErr) {
+		t.Fatalf("expected ErrDatabaseBusy wrapping the driver error, got %v", err)
+	}
+	var total time.Duration
+	for _, delay := range *delays {
+		if delay > busyRetryMaxDelay {
+			t.Fatalf("expected every wait to stay under %s, got %s", busyRetryMaxDelay, delay)
+		}
+		total += delay
+	}
+	if total != busyRetryTotalDelay {
+		t.Fatalf("expected the waits to add up to %s, got %s", busyRetryTotalDelay, total)
+	}
+
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	attempts := 0
+	err = retryOnBusy(cancelledCtx, func() error {
+		attempts++
+		return driverErr
+	})
+	if !errors.Is(err, ErrDatabaseBusy) || attempts != 1 {
+		t.Fatalf("expected a cancelled context to stop retrying, got %v after %d attempts", err, attempts)
+	}
+
+	otherErr := errors.New("constraint failed")
+	attempts = 0
+	if err := retryOnBusy(context.Background(), func() error { attempts++; return otherErr }); err != otherErr || attempts != 1 {
+		t.Fatalf("expected other errors to pass through untouched, got %v after %d attempts", err, attempts)
+	}
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"mime"
+	"net/textproto"
+	"strings"
+)
+
+const contentHeaderPrefix = "Content-"
+
+var (
+	// ErrNotificationEncryptedContentInvalid indicates caller-encrypted email content
+	// that is not an encrypted MIME entity.
+	ErrNotificationEncryptedContentInvalid = errors.New("notification.request.invalid_encrypted_content")
+	// ErrNotificationEncryptedContentIncompatible indicates caller-encrypted content
+	// combined with a feature that needs to read or change the content.
+	ErrNotificationEncryptedContentIncompatible = errors.New("notification.request.encrypted_content_incompatible")
+)
+
+// encryptedMIMETypes are the top-level media types of PGP/MIME and S/MIME encrypted entities.
+var encryptedMIMETypes = map[string]bool{
+	"multipart/encrypted":      true,
+	"application/pkcs7-mime":   true,
+	"application/x-pkcs7-mime": true,
+}
+
+// WithEncryptedContent returns a copy of the request whose message is ciphertext the
+// caller encrypted, which Pinguin stores and sends without reading or changing it. For
+// email the message is a complete MIME entity, Content-* headers, a blank line and the
+// body, whose Content-Type is multipart/encrypted (PGP/MIME) or application/pkcs7-mime
+// (S/MIME); the sender adds only From, To, Subject and MIME-Version. For SMS it is the
+// ciphertext text. Attachments, calendar events and SMS truncation are refused, since
+// each would have to change the content.
+func (request NotificationRequest) WithEncryptedContent() (NotificationRequest, error) {
+	if len(request.attachments) > 0 {
+		return NotificationRequest{}, fmt.Errorf("%w: attachments and calendar events must be inside the encrypted MIME entity", ErrNotificationEncryptedContentIncompatible)
+	}
+	if request.smsOverflowPolicy == SMSOverflowTruncate {
+		return NotificationRequest{}, fmt.Errorf("%w: encrypted SMS cannot be truncated", ErrNotificationEncryptedContentIncompatible)
+	}
+	if request.notificationType == NotificationEmail {
+		if err := validateEncryptedMIMEEntity(request.message); err != nil {
+			return NotificationRequest{}, err
+		}
+	}
+	request.contentEncrypted = true
+	return request, nil
+}
+
+// ContentEncrypted reports whether the request's message is caller-encrypted ciphertext.
+func (request NotificationRequest) ContentEncrypted() bool {
+	return request.contentEncrypted
+}
+
+// validateEncryptedMIMEEntity checks only the entity's headers; the body is opaque.
+func validateEncryptedMIMEEntity(entity string) error {
+	header, err := textproto.NewReader(bufio.NewReader(strings.NewReader(entity))).ReadMIMEHeader()
+	if err != nil {
+		return fmt.Errorf("%w: the message must be a MIME entity: headers, a blank line, then the body", ErrNotificationEncryptedContentInvalid)
+	}
+	for name := range header {
+		if !strings.HasPrefix(name, contentHeaderPrefix) {
+			return fmt.Errorf("%w: the MIME entity may only carry Content-* headers, not %s", ErrNotificationEncryptedContentInvalid, name)
+		}
+	}
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil || !encryptedMIMETypes[mediaType] {
+		return fmt.Errorf("%w: Content-Type must be multipart/encrypted or application/pkcs7-mime", ErrNotificationEncryptedContentInvalid)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+const testPGPMIMEEntity = "Content-Type: multipart/encrypted; protocol=\"application/pgp-encrypted\"; boundary=\"b1\"\r\n" +
+	"\r\n" +
+	"--b1\r\nContent-Type: application/pgp-encrypted\r\n\r\nVersion: 1\r\n" +
+	"--b1\r\nContent-Type: application/octet-stream\r\n\r\n-----BEGIN PGP MESSAGE-----\r\nhQEMA\r\n-----END PGP MESSAGE-----\r\n" +
+	"--b1--\r\n"
+
+func TestWithEncryptedContent(t *testing.T) {
+	emailRequest, err := NewNotificationRequest(NotificationEmail, "user@example.com", "Subject", testPGPMIMEEntity, nil, nil)
+	if err != nil {
+		t.Fatalf("NewNotificationRequest error: %v", err)
+	}
+	encrypted, err := emailRequest.WithEncryptedContent()
+	if err != nil || !encrypted.ContentEncrypted() {
+		t.Fatalf("expected a PGP/MIME entity to be accepted, got %v", err)
+	}
+	notification := NewNotification("notif-1", "tenant-1", encrypted, time.Now())
+	if !notification.ContentEncrypted || notification.Message != testPGPMIMEEntity {
+		t.Fatalf("expected the entity to be stored unchanged and flagged, got %+v", notification)
+	}
+	if response := NewNotificationResponse(notification); !response.ContentEncrypted {
+		t.Fatalf("expected the response to report encrypted content")
+	}
+
+	smime := "Content-Type: application/pkcs7-mime; smime-type=enveloped-data; name=smime.p7m\r\nContent-Transfer-Encoding: base64\r\n\r\nMIAGCSqG\r\n"
+	smimeRequest, err := NewNotificationRequest(NotificationEmail, "user@example.com", "Subject", smime, nil, nil)
+	if err != nil {
+		t.Fatalf("NewNotificationRequest error: %v", err)
+	}
+	if _, err := smimeRequest.WithEncryptedContent(); err != nil {
+		t.Fatalf("expected an S/MIME entity to be accepted, got %v", err)
+	}
+
+	smsRequest, err := NewNotificationRequest(NotificationSMS, "+15555550100", "", "hQEMA-ciphertext", nil, nil)
+	if err != nil {
+		t.Fatalf("NewNotificationRequest error: %v", err)
+	}
+	if encryptedSMS, err := smsRequest.WithEncryptedContent(); err != nil || !encryptedSMS.ContentEncrypted() {
+		t.Fatalf("expected SMS ciphertext to be accepted, got %v", err)
+	}
+}
+
+func TestWithEncryptedContentRejectsInvalidEntities(t *testing.T) {
+	testCases := map[string]string{
+		"plain text":           "Hello there",
+		"plain mime type":      "Content-Type: text/plain\r\n\r\nHello",
+		"non content header":   "Content-Type: multipart/encrypted; boundary=b1\r\nSubject: leaked\r\n\r\n--b1--\r\n",
+		"missing content type": "Content-Transfer-Encoding: base64\r\n\r\nMIAGCSqG\r\n",
+	}
+	for name, message := range testCases {
+		t.Run(name, func(t *testing.T) {
+			request, err := NewNotificationRequest(NotificationEmail, "user@example.com", "Subject", message, nil, nil)
+			if err != nil {
+				t.Fatalf("NewNotificationRequest error: %v", err)
+			}
+			if _, err := request.WithEncryptedContent(); !errors.Is(err, ErrNotificationEncryptedContentInvalid) {
+				t.Fatalf("expected the entity to be rejected, got %v", err)
+			}
+		})
+	}
+}
+
+func TestWithEncryptedContentRejectsIncompatibleFeatures(t *testing.T) {
+	attachments := []EmailAttachment{{Filename: "report.txt", ContentType: "text/plain", Data: []byte("numbers")}}
+	withAttachment, err := NewNotificationRequest(NotificationEmail, "user@example.com", "Subject", testPGPMIMEEntity, nil, attachments)
+	if err != nil {
+		t.Fatalf("NewNotificationRequest error: %v", err)
+	}
+	if _, err := withAttachment.WithEncryptedContent(); !errors.Is(err, ErrNotificationEncryptedContentIncompatible) {
+		t.Fatalf("expected attachments to be rejected, got %v", err)
+	}
+
+	emailRequest, err := NewNotificationRequest(NotificationEmail, "user@example.com", "Subject", testPGPMIMEEntity, nil, nil)
+	if err != nil {
+		t.Fatalf("NewNotificationRequest error: %v", err)
+	}
+	encrypted, err := emailRequest.WithEncryptedContent()
+	if err != nil {
+		t.Fatalf("WithEncryptedContent error: %v", err)
+	}
+	if _, err := encrypted.WithCalendarEvent("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n", ""); !errors.Is(err, ErrNotificationEncryptedContentIncompatible) {
+		t.Fatalf("expected a calendar event to be rejected, got %v", err)
+	}
+
+	smsRequest, err := NewNotificationRequest(NotificationSMS, "+15555550100", "", "hQEMA-ciphertext", nil, nil)
+	if err != nil {
+		t.Fatalf("NewNotificationRequest error: %v", err)
+	}
+	truncating, err := smsRequest.WithSMSOverflowPolicy("truncate")
+	if err != nil {
+		t.Fatalf("WithSMSOverflowPolicy error: %v", err)
+	}
+	if _, err := truncating.WithEncryptedContent(); !errors.Is(err, ErrNotificationEncryptedContentIncompatible) {
+		t.Fatalf("expected truncation to be rejected, got %v", err)
+	}
+	encryptedSMS, err := smsRequest.WithEncryptedContent()
+	if err != nil {
+		t.Fatalf("WithEncryptedContent error: %v", err)
+	}
+	if _, err := encryptedSMS.WithSMSOverflowPolicy("truncate"); !errors.Is(err, ErrNotificationEncryptedContentIncompatible) {
+		t.Fatalf("expected truncation after encryption to be rejected, got %v", err)
+	}
+}
@@ -25,9 +25,9 @@ const (
 
 // EmailAttachment carries attachment metadata used across domain layers.
 type EmailAttachment struct {
-	Filename    string `json:"filename"`
-	ContentType string `json:"content_type"`
-	Data        []byte `json:"data"`
+	Filename    string `json:"filename" description:"Attachment file name."`
+	ContentType string `json:"content_type" description:"MIME type of the attachment."`
+	Data        []byte `json:"data" description:"Attachment bytes, base64-encoded in JSON."`
 }
 
 // Status constants used for the Notification model.
@@ -39,6 +39,31 @@ const (
 	StatusUnknown   NotificationStatus = "unknown"
 )
 
+// CancelReasonExpired marks notifications cancelled because their expiry passed before dispatch.
+const CancelReasonExpired = "expired"
+
+// CancelReasonAttachmentDataUnavailable marks retries abandoned because the tenant
+// keeps attachment metadata only, so the original bytes cannot be resent.
+const CancelReasonAttachmentDataUnavailable = "attachment_data_unavailable"
+
+// LastErrorMaxAgeExceeded marks notifications the retry worker gave up on because they
+// stayed undelivered longer than the max retry age.
+const LastErrorMaxAgeExceeded = "max age exceeded"
+
+// NotificationStreamBatchSize is how many rows StreamNotifications reads per query.
+const NotificationStreamBatchSize = 1000
+
+// NotificationSource tags notifications Pinguin creates on its own behalf.
+type NotificationSource string
+
+const (
+	// NotificationSourceSystemReport marks the scheduled tenant activity digest.
+	NotificationSourceSystemReport NotificationSource = "system-report"
+	// NotificationSourceSystemAlert marks notices to tenant admins about their delivery,
+	// such as a channel paused by its provider breaker.
+	NotificationSourceSystemAlert NotificationSource = "system-alert"
+)
+
 const (
 	notificationTenantIDColumn       = "tenant_id"
 	notificationIDColumn             = "id"
@@ -50,6 +75,8 @@ const (
 	notificationStatusColumn         = "status"
 	notificationRetryCountColumn     = "retry_count"
 	notificationScheduledForColumn   = "scheduled_for"
+	notificationNextAttemptAtColumn  = "next_attempt_at"
+	notificationLastErrorColumn      = "last_error"
 	notificationCreatedAtColumn      = "created_at"
 	defaultNotificationListLimit     = 50
 	maxNotificationListLimit         = 100
@@ -61,6 +88,8 @@ var (
 	ErrInvalidNotificationCursor = errors.New("invalid notification list cursor")
 	ErrInvalidNotificationLimit  = errors.New("invalid notification list limit")
 	ErrInvalidNotificationSearch = errors.New("invalid notification search query")
+	// ErrUnknownNotificationStatus rejects a status filter value that names no status.
+	ErrUnknownNotificationStatus = errors.New("unknown notification status")
 )
 
 func CanonicalStatus(status NotificationStatus) NotificationStatus {
@@ -237,69 +266,140 @@ func (filters NotificationListFilters) NormalizedStatuses() []NotificationStatus
 	return normalized
 }
 
+// ValidateStatuses reports the first status that CanonicalStatus does not recognize.
+// NormalizedStatuses drops such values, so callers that want them rejected check first.
+func (filters NotificationListFilters) ValidateStatuses() error {
+	for _, status := range filters.Statuses {
+		if CanonicalStatus(status) == "" {
+			return fmt.Errorf("%w: %q", ErrUnknownNotificationStatus, string(status))
+		}
+	}
+	return nil
+}
+
 // Notification is our main model in the DB, with GORM & JSON tags.
 // You can return this directly via JSON or create a separate struct if you like.
 type Notification struct {
-	ID                uint                     `json:"-" gorm:"primaryKey"`
-	TenantID          string                   `json:"tenant_id" gorm:"index"`
-	NotificationID    string                   `json:"notification_id" gorm:"index:idx_tenant_notification,unique"`
-	NotificationType  NotificationType         `json:"notification_type"`
-	Recipient         string                   `json:"recipient"`
-	Subject           string                   `json:"subject,omitempty"`
-	Message           string                   `json:"message"`
-	ProviderMessageID string                   `json:"provider_message_id"`
-	Status            NotificationStatus       `json:"status"`
-	RetryCount        int                      `json:"retry_count"`
-	LastAttemptedAt   time.Time                `json:"last_attempted_at"`
-	ScheduledFor      *time.Time               `json:"scheduled_for"`
-	CreatedAt         time.Time                `json:"created_at"`
-	UpdatedAt         time.Time                `json:"updated_at"`
-	Attachments       []NotificationAttachment `json:"attachments,omitempty" gorm:"foreignKey:NotificationID,TenantID;references:NotificationID,TenantID;constraint:OnDelete:CASCADE"`
+	ID                uint               `json:"-" gorm:"primaryKey"`
+	TenantID          string             `json:"tenant_id" gorm:"index;index:idx_notifications_tenant_created,priority:1"`
+	NotificationID    string             `json:"notification_id" gorm:"index:idx_tenant_notification,unique"`
+	NotificationType  NotificationType   `json:"notification_type"`
+	Recipient         string             `json:"recipient"`
+	Subject           string             `json:"subject,omitempty"`
+	Message           string             `json:"message"`
+	ProviderMessageID string             `json:"provider_message_id"`
+	Status            NotificationStatus `json:"status"`
+	RetryCount        int                `json:"retry_count"`
+	LastAttemptedAt   time.Time          `json:"last_attempted_at"`
+	ScheduledFor      *time.Time         `json:"scheduled_for"`
+	NextAttemptAt     *time.Time         `json:"next_attempt_at,omitempty" gorm:"index"`
+	ExpiresAt         *time.Time         `json:"expires_at"`
+	CancelReason      string             `json:"cancel_reason,omitempty"`
+	// CancelledBy names who cancelled the notification: a session email, CancelActorGRPC or CancelActorSystem.
+	CancelledBy string     `json:"cancelled_by,omitempty"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+	// LastError records why retries stopped before maxRetries; empty otherwise.
+	LastError             string             `json:"last_error,omitempty"`
+	Source                NotificationSource `json:"source,omitempty"`
+	EstimatedCost         float64            `json:"estimated_cost"`
+	CostCurrency          string             `json:"cost_currency,omitempty"`
+	SMSTruncated          bool               `json:"sms_truncated,omitempty"`
+	OriginalMessageLength int                `json:"original_message_length,omitempty"`
+	// GroupID links the notifications of one multi-channel send; empty otherwise.
+	GroupID string `json:"group_id,omitempty" gorm:"index"`
+	// LegalHold exempts the notification from the retention sweep.
+	LegalHold bool `json:"legal_hold,omitempty" gorm:"not null;default:false"`
+	// ContentEncrypted marks a Message the caller encrypted: an email's MIME entity or
+	// an SMS's ciphertext, stored and sent exactly as received.
+	ContentEncrypted bool `json:"content_encrypted,omitempty" gorm:"not null;default:false"`
+	// ReplyTo and FromDisplayName are the per-message email headers the tenant's policy
+	// allowed; empty values mean the defaults.
+	ReplyTo         string `json:"reply_to,omitempty"`
+	FromDisplayName string `json:"from_display_name,omitempty"`
+	// CreatedAt is indexed with TenantID so StreamNotifications seeks each batch instead of sorting.
+	CreatedAt   time.Time                `json:"created_at" gorm:"index:idx_notifications_tenant_created,priority:2"`
+	UpdatedAt   time.Time                `json:"updated_at"`
+	Attachments []NotificationAttachment `json:"attachments,omitempty" gorm:"foreignKey:NotificationID,TenantID;references:NotificationID,TenantID;constraint:OnDelete:CASCADE"`
 }
 
 // NotificationAttachment persists attachment payloads per notification.
+// DataDiscarded rows keep filename, content type and size but no Data.
+// Rows with a ContentHash keep their bytes in the shared AttachmentBlob; Data is
+// filled from it on load. Rows stored before deduplication keep Data inline.
 type NotificationAttachment struct {
 	ID             uint      `json:"-" gorm:"primaryKey"`
 	TenantID       string    `json:"tenant_id" gorm:"index"`
 	NotificationID string    `json:"notification_id" gorm:"index"`
 	Filename       string    `json:"filename"`
 	ContentType    string    `json:"content_type"`
+	SizeBytes      int       `json:"size_bytes"`
 	Data           []byte    `json:"data" gorm:"type:blob"`
+	ContentHash    string    `json:"-" gorm:"index"`
+	DataDiscarded  bool      `json:"data_discarded,omitempty"`
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
 }
 
 // NotificationRequest represents a validated request payload.
 type NotificationRequest struct {
-	notificationType NotificationType
-	recipient        string
-	subject          string
-	message          string
-	scheduledFor     *time.Time
-	attachments      []EmailAttachment
+	notificationType  NotificationType
+	recipient         string
+	subject           string
+	message           string
+	scheduledFor      *time.Time
+	expiresAt         *time.Time
+	source            NotificationSource
+	smsOverflowPolicy SMSOverflowPolicy
+	failOnImmediate   *bool
+	attachments       []EmailAttachment
+	contentEncrypted  bool
+	senderOverrides   SenderOverrides
 }
 
 // NotificationResponse is what you'll return to the client.
 // You could also return the Notification itself, but some prefer a separate shape.
 type NotificationResponse struct {
-	NotificationID    string             `json:"notification_id"`
-	TenantID          string             `json:"tenant_id"`
-	NotificationType  NotificationType   `json:"notification_type"`
-	Recipient         string             `json:"recipient"`
-	Subject           string             `json:"subject,omitempty"`
-	Message           string             `json:"message"`
-	Status            NotificationStatus `json:"status"`
-	ProviderMessageID string             `json:"provider_message_id"`
-	RetryCount        int                `json:"retry_count"`
-	ScheduledFor      *time.Time         `json:"scheduled_for,omitempty"`
-	CreatedAt         time.Time          `json:"created_at"`
-	UpdatedAt         time.Time          `json:"updated_at"`
-	Attachments       []EmailAttachment  `json:"attachments,omitempty"`
-}
-
-// NewNotification constructs a ready-to-insert DB Notification from a request, defaulting status=queued.
-func NewNotification(notificationID string, tenantID string, req NotificationRequest) Notification {
-	now := time.Now().UTC()
+	NotificationID        string             `json:"notification_id" description:"Identifier of the notification."`
+	TenantID              string             `json:"tenant_id" description:"Tenant that owns the notification."`
+	NotificationType      NotificationType   `json:"notification_type" description:"Delivery channel: email or sms."`
+	Recipient             string             `json:"recipient" description:"Email address or phone number the notification is sent to."`
+	Subject               string             `json:"subject,omitempty" description:"Email subject; empty for SMS."`
+	Message               string             `json:"message" description:"Message body as submitted."`
+	Status                NotificationStatus `json:"status" description:"Delivery status: queued, sent, errored or cancelled."`
+	ProviderMessageID     string             `json:"provider_message_id" description:"Message id reported by the email or SMS provider once sent."`
+	RetryCount            int                `json:"retry_count" description:"Number of delivery attempts that failed."`
+	ScheduledFor          *time.Time         `json:"scheduled_for,omitempty" description:"Time the notification is due, or null to send immediately."`
+	ExpiresAt             *time.Time         `json:"expires_at,omitempty" description:"Time after which the notification must not be sent."`
+	CancelReason          string             `json:"cancel_reason,omitempty" description:"Why the notification was cancelled: a system reason such as expired, or the reason the canceller gave."`
+	CancelledBy           string             `json:"cancelled_by,omitempty" description:"Who cancelled the notification: the session email, grpc, or system."`
+	CancelledAt           *time.Time         `json:"cancelled_at,omitempty" description:"Time the notification was cancelled."`
+	LastError             string             `json:"last_error,omitempty" description:"Why retries stopped before the retry limit, e.g. max age exceeded."`
+	Source                NotificationSource `json:"source,omitempty" description:"System component that created the notification, when not an API caller."`
+	EstimatedCost         float64            `json:"estimated_cost" description:"Estimated delivery cost in cost_currency."`
+	CostCurrency          string             `json:"cost_currency,omitempty" description:"Currency of estimated_cost."`
+	Truncated             bool               `json:"truncated,omitempty" description:"Whether the SMS body was truncated to the tenant segment limit."`
+	OriginalMessageLength int                `json:"original_message_length,omitempty" description:"Length of the SMS body before truncation."`
+	Warnings              []string           `json:"warnings,omitempty" description:"Non-fatal issues found while accepting the notification."`
+	LegalHold             bool               `json:"legal_hold,omitempty" description:"Whether the notification is exempt from retention."`
+	ContentEncrypted      bool               `json:"content_encrypted,omitempty" description:"Whether message is ciphertext the caller encrypted, stored and sent as received."`
+	GroupID               string             `json:"group_id,omitempty" description:"Identifier shared by the notifications of one multi-channel send."`
+	ReplyTo               string             `json:"reply_to,omitempty" description:"Reply-To address the email is sent with, when the request set one."`
+	FromDisplayName       string             `json:"from_display_name,omitempty" description:"From display name the email is sent with, when the request set one."`
+	// PendingReason is only set on single-notification reads.
+	PendingReason PendingReason `json:"pending_reason,omitempty" description:"Why a notification still to be sent has not gone out: scheduled, circuit_open, awaiting_retry, rate_limited or due. Omitted once no further attempt will be made."`
+	// Rendered is only set when the caller asked for the content as it was dispatched.
+	Rendered *RenderedContent `json:"rendered,omitempty" description:"Content as it was dispatched; only set when requested."`
+	// Opens is only set on single-notification reads for tenants with open tracking.
+	Opens       *NotificationOpenSummary `json:"opens,omitempty" description:"Open tracking state; only set for tenants with open tracking."`
+	CreatedAt   time.Time                `json:"created_at" description:"Time the notification was accepted."`
+	UpdatedAt   time.Time                `json:"updated_at" description:"Time the notification last changed."`
+	Attachments []EmailAttachment        `json:"attachments,omitempty" description:"Email attachments."`
+}
+
+// NewNotification constructs a ready-to-insert DB Notification from a request, defaulting
+// status=queued and stamping createdAt as both its creation and update time.
+func NewNotification(notificationID string, tenantID string, req NotificationRequest, createdAt time.Time) Notification {
+	now := createdAt.UTC()
 	var scheduledFor *time.Time
 	if req.scheduledFor != nil {
 		normalizedScheduled := req.scheduledFor.UTC()
@@ -314,6 +414,11 @@ func NewNotification(notificationID string, tenantID string, req NotificationReq
 		Message:          req.message,
 		Status:           StatusQueued,
 		ScheduledFor:     scheduledFor,
+		ExpiresAt:        req.ExpiresAt(),
+		Source:           req.source,
+		ContentEncrypted: req.contentEncrypted,
+		ReplyTo:          req.senderOverrides.ReplyTo,
+		FromDisplayName:  req.senderOverrides.FromDisplayName,
 		CreatedAt:        now,
 		UpdatedAt:        now,
 		Attachments:      convertEmailAttachments(tenantID, notificationID, req.attachments),
@@ -331,35 +436,182 @@ func NewNotificationResponse(n Notification) NotificationResponse {
 	if status == "" {
 		status = StatusUnknown
 	}
+	var warnings []string
+	if n.SMSTruncated {
+		warnings = append(warnings, SMSTruncatedWarning)
+	}
 	return NotificationResponse{
-		NotificationID:    n.NotificationID,
-		TenantID:          n.TenantID,
-		NotificationType:  n.NotificationType,
-		Recipient:         n.Recipient,
-		Subject:           n.Subject,
-		Message:           n.Message,
-		Status:            status,
-		ProviderMessageID: n.ProviderMessageID,
-		RetryCount:        n.RetryCount,
-		ScheduledFor:      scheduledFor,
-		CreatedAt:         n.CreatedAt,
-		UpdatedAt:         n.UpdatedAt,
-		Attachments:       ToEmailAttachments(n.Attachments),
+		NotificationID:        n.NotificationID,
+		TenantID:              n.TenantID,
+		NotificationType:      n.NotificationType,
+		Recipient:             n.Recipient,
+		Subject:               n.Subject,
+		Message:               n.Message,
+		Status:                status,
+		ProviderMessageID:     n.ProviderMessageID,
+		RetryCount:            n.RetryCount,
+		ScheduledFor:          scheduledFor,
+		ExpiresAt:             utcTimePointer(n.ExpiresAt),
+		CancelReason:          n.CancelReason,
+		CancelledBy:           n.CancelledBy,
+		CancelledAt:           utcTimePointer(n.CancelledAt),
+		LastError:             n.LastError,
+		Source:                n.Source,
+		EstimatedCost:         n.EstimatedCost,
+		CostCurrency:          n.CostCurrency,
+		Truncated:             n.SMSTruncated,
+		OriginalMessageLength: n.OriginalMessageLength,
+		Warnings:              warnings,
+		LegalHold:             n.LegalHold,
+		ContentEncrypted:      n.ContentEncrypted,
+		GroupID:               n.GroupID,
+		ReplyTo:               n.ReplyTo,
+		FromDisplayName:       n.FromDisplayName,
+		CreatedAt:             n.CreatedAt,
+		UpdatedAt:             n.UpdatedAt,
+		Attachments:           SharedEmailAttachments(n.Attachments),
+	}
+}
+
+// IsExpired reports whether the notification passed its do-not-send-after time.
+func (n Notification) IsExpired(currentTime time.Time) bool {
+	return n.ExpiresAt != nil && currentTime.After(*n.ExpiresAt)
+}
+
+// MaxRetryAgeDeadline returns when the notification stops being retried under maxAge,
+// counted from when it was due: its schedule, or its creation when unscheduled. The
+// second result is false when maxAge is not positive.
+func (n Notification) MaxRetryAgeDeadline(maxAge time.Duration) (time.Time, bool) {
+	if maxAge <= 0 {
+		return time.Time{}, false
+	}
+	dueAt := n.CreatedAt
+	if n.ScheduledFor != nil {
+		dueAt = *n.ScheduledFor
+	}
+	return dueAt.Add(maxAge), true
+}
+
+// ExceedsMaxRetryAge reports whether currentTime passed the max retry age deadline
+// while that deadline is earlier than the expiry. When the expiry comes first, or both
+// fall at once, IsExpired decides instead.
+func (n Notification) ExceedsMaxRetryAge(maxAge time.Duration, currentTime time.Time) bool {
+	deadline, limited := n.MaxRetryAgeDeadline(maxAge)
+	if !limited || !currentTime.After(deadline) {
+		return false
+	}
+	return n.ExpiresAt == nil || deadline.Before(*n.ExpiresAt)
+}
+
+// MarkMaxRetryAgeExceeded fails the notification for good because it stayed undelivered
+// past the max retry age, whatever retries remain.
+func (n *Notification) MarkMaxRetryAgeExceeded(currentTime time.Time) {
+	n.Status = StatusErrored
+	n.LastError = LastErrorMaxAgeExceeded
+	n.NextAttemptAt = nil
+	n.UpdatedAt = currentTime
+}
+
+// RetriesAbandoned reports whether the system stopped retrying the notification before
+// maxRetries, as recorded in LastError.
+func (n Notification) RetriesAbandoned() bool {
+	return n.LastError != ""
+}
+
+// MarkCancelled cancels the notification on request, clearing any schedule and
+// recording the reason given and who cancelled it.
+func (n *Notification) MarkCancelled(currentTime time.Time, reason string, actor string) {
+	n.Status = StatusCancelled
+	n.ScheduledFor = nil
+	n.markCancelledBy(currentTime, reason, actor)
+}
+
+// MarkExpired cancels the notification because its expiry passed before dispatch.
+func (n *Notification) MarkExpired(currentTime time.Time) {
+	n.Status = StatusCancelled
+	n.markCancelledBy(currentTime, CancelReasonExpired, CancelActorSystem)
+}
+
+func (n *Notification) markCancelledBy(currentTime time.Time, reason string, actor string) {
+	cancelledAt := currentTime.UTC()
+	n.CancelReason = reason
+	n.CancelledBy = actor
+	n.CancelledAt = &cancelledAt
+	n.UpdatedAt = currentTime
+}
+
+// MarkSMSTruncated replaces the body with its truncated form and records the original
+// length in characters.
+func (n *Notification) MarkSMSTruncated(truncatedMessage string) {
+	n.OriginalMessageLength = utf8.RuneCountInString(n.Message)
+	n.Message = truncatedMessage
+	n.SMSTruncated = true
+}
+
+// DiscardAttachmentData drops attachment bytes before persistence, keeping metadata only.
+func (n *Notification) DiscardAttachmentData() {
+	for index := range n.Attachments {
+		n.Attachments[index].Data = nil
+		n.Attachments[index].DataDiscarded = true
+	}
+}
+
+// HasDiscardedAttachmentData reports whether any attachment was stored without its bytes.
+func (n Notification) HasDiscardedAttachmentData() bool {
+	for _, attachment := range n.Attachments {
+		if attachment.DataDiscarded {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkAttachmentDataUnavailable cancels a retry that cannot be sent without the discarded attachment bytes.
+func (n *Notification) MarkAttachmentDataUnavailable(currentTime time.Time) {
+	n.Status = StatusCancelled
+	n.markCancelledBy(currentTime, CancelReasonAttachmentDataUnavailable, CancelActorSystem)
+}
+
+func utcTimePointer(value *time.Time) *time.Time {
+	if value == nil {
+		return nil
 	}
+	normalized := value.UTC()
+	return &normalized
 }
 
 // ====================== DB CRUD METHODS ====================== //
 
 func CreateNotification(ctx context.Context, db *gorm.DB, n *Notification) error {
-	return db.WithContext(ctx).Create(n).Error
+	// A failed insert is rolled back but may leave generated ids behind; each retry
+	// starts from the caller's ids so it never reuses a key another writer took.
+	notificationID := n.ID
+	attachmentIDs := make([]uint, len(n.Attachments))
+	for attachmentIndex, attachment := range n.Attachments {
+		attachmentIDs[attachmentIndex] = attachment.ID
+	}
+	return retryOnBusy(ctx, func() error {
+		n.ID = notificationID
+		for attachmentIndex := range n.Attachments {
+			n.Attachments[attachmentIndex].ID = attachmentIDs[attachmentIndex]
+		}
+		return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return createNotificationWithBlobs(tx, n)
+		})
+	})
 }
 
 func GetNotificationByID(ctx context.Context, db *gorm.DB, tenantID string, notificationID string) (*Notification, error) {
 	var notif Notification
-	err := db.WithContext(ctx).
-		Preload("Attachments").
-		Where(&Notification{TenantID: tenantID, NotificationID: notificationID}).
-		First(&notif).Error
+	err := retryOnBusy(ctx, func() error {
+		if err := db.WithContext(ctx).
+			Preload("Attachments").
+			Where(&Notification{TenantID: tenantID, NotificationID: notificationID}).
+			First(&notif).Error; err != nil {
+			return err
+		}
+		return hydrateAttachmentData(db.WithContext(ctx), &notif)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -367,28 +619,68 @@ func GetNotificationByID(ctx context.Context, db *gorm.DB, tenantID string, noti
 }
 
 func SaveNotification(ctx context.Context, db *gorm.DB, n *Notification) error {
-	return db.WithContext(ctx).Save(n).Error
+	return retryOnBusy(ctx, func() error {
+		return db.WithContext(ctx).Save(n).Error
+	})
+}
+
+// CountUnattemptedQueuedNotifications counts the tenant's queued notifications without
+// a schedule that were created before createdBefore and never attempted. Such rows were
+// meant for immediate dispatch, so they are stranded until the retry worker reaches them.
+func CountUnattemptedQueuedNotifications(ctx context.Context, db *gorm.DB, tenantID string, createdBefore time.Time) (int64, error) {
+	var count int64
+	err := retryOnBusy(ctx, func() error {
+		return db.WithContext(ctx).Model(&Notification{}).
+			Where(clause.And(
+				clause.Eq{Column: clause.Column{Name: notificationTenantIDColumn}, Value: tenantID},
+				clause.Eq{Column: clause.Column{Name: notificationStatusColumn}, Value: StatusQueued},
+				clause.Eq{Column: clause.Column{Name: notificationScheduledForColumn}, Value: nil},
+				clause.Eq{Column: clause.Column{Name: notificationRetryCountColumn}, Value: 0},
+				clause.Lt{Column: clause.Column{Name: notificationCreatedAtColumn}, Value: createdBefore},
+			)).
+			Count(&count).Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("count_unattempted_queued_notifications: %w", err)
+	}
+	return count, nil
 }
 
 func GetPendingRetryNotifications(ctx context.Context, db *gorm.DB, tenantID string, maxRetries int, currentTime time.Time) ([]Notification, error) {
+	ctx = aggregateQueries(ctx)
 	var notifications []Notification
 	tenantIDColumn := clause.Column{Name: notificationTenantIDColumn}
 	statusColumn := clause.Column{Name: notificationStatusColumn}
 	retryCountColumn := clause.Column{Name: notificationRetryCountColumn}
 	scheduledForColumn := clause.Column{Name: notificationScheduledForColumn}
+	nextAttemptAtColumn := clause.Column{Name: notificationNextAttemptAtColumn}
+	lastErrorColumn := clause.Column{Name: notificationLastErrorColumn}
 	statusValues := []interface{}{StatusQueued, StatusErrored}
-	err := db.WithContext(ctx).
-		Preload("Attachments").
-		Where(clause.And(
-			clause.Eq{Column: tenantIDColumn, Value: tenantID},
-			clause.IN{Column: statusColumn, Values: statusValues},
-			clause.Lt{Column: retryCountColumn, Value: maxRetries},
-			clause.Or(
-				clause.Eq{Column: scheduledForColumn, Value: nil},
-				clause.Lte{Column: scheduledForColumn, Value: currentTime},
-			),
-		)).
-		Find(&notifications).Error
+	err := retryOnBusy(ctx, func() error {
+		if err := db.WithContext(ctx).
+			Preload("Attachments").
+			Where(clause.And(
+				clause.Eq{Column: tenantIDColumn, Value: tenantID},
+				clause.IN{Column: statusColumn, Values: statusValues},
+				clause.Lt{Column: retryCountColumn, Value: maxRetries},
+				clause.Or(
+					clause.Eq{Column: scheduledForColumn, Value: nil},
+					clause.Lte{Column: scheduledForColumn, Value: currentTime},
+				),
+				clause.Or(
+					clause.Eq{Column: nextAttemptAtColumn, Value: nil},
+					clause.Lte{Column: nextAttemptAtColumn, Value: currentTime},
+				),
+				clause.Or(
+					clause.Eq{Column: lastErrorColumn, Value: nil},
+					clause.Eq{Column: lastErrorColumn, Value: ""},
+				),
+			)).
+			Find(&notifications).Error; err != nil {
+			return err
+		}
+		return hydrateNotificationList(db.WithContext(ctx), notifications)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -396,42 +688,103 @@ func GetPendingRetryNotifications(ctx context.Context, db *gorm.DB, tenantID str
 }
 
 func ListNotifications(ctx context.Context, db *gorm.DB, tenantID string, filters NotificationListFilters) ([]Notification, error) {
-	query := notificationListQuery(ctx, db, filters).
-		Where(&Notification{TenantID: tenantID})
+	ctx = aggregateQueries(ctx)
 	var notifications []Notification
-	if err := query.Find(&notifications).Error; err != nil {
+	err := retryOnBusy(ctx, func() error {
+		if err := notificationListQuery(ctx, db, filters).
+			Where(&Notification{TenantID: tenantID}).
+			Find(&notifications).Error; err != nil {
+			return err
+		}
+		return hydrateNotificationList(db.WithContext(ctx), notifications)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return notifications, nil
 }
 
 func ListNotificationsPage(ctx context.Context, db *gorm.DB, tenantID string, filters NotificationListFilters, pageRequest NotificationListPageRequest) (NotificationListPage, error) {
-	query := notificationListQuery(ctx, db, filters).
-		Where(&Notification{TenantID: tenantID})
-	if cursor := pageRequest.Cursor(); cursor != nil {
-		query = query.Where(notificationCursorCondition(*cursor))
-	}
 	var notifications []Notification
-	if err := query.Limit(pageRequest.Limit() + 1).Find(&notifications).Error; err != nil {
+	err := retryOnBusy(ctx, func() error {
+		query := notificationListQuery(ctx, db, filters).
+			Where(&Notification{TenantID: tenantID})
+		if cursor := pageRequest.Cursor(); cursor != nil {
+			query = query.Where(notificationCursorCondition(*cursor))
+		}
+		if err := query.Limit(pageRequest.Limit() + 1).Find(&notifications).Error; err != nil {
+			return err
+		}
+		return hydrateNotificationList(db.WithContext(ctx), notifications)
+	})
+	if err != nil {
 		return NotificationListPage{}, err
 	}
 	return notificationPageFromRecords(notifications, pageRequest.Limit())
 }
 
 func ListNotificationsAll(ctx context.Context, db *gorm.DB, filters NotificationListFilters) ([]Notification, error) {
-	query := notificationListQuery(ctx, db, filters)
+	ctx = aggregateQueries(ctx)
 	var notifications []Notification
-	if err := query.Find(&notifications).Error; err != nil {
+	err := retryOnBusy(ctx, func() error {
+		if err := notificationListQuery(ctx, db, filters).Find(&notifications).Error; err != nil {
+			return err
+		}
+		return hydrateNotificationList(db.WithContext(ctx), notifications)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return notifications, nil
 }
 
+// StreamNotifications visits a tenant's notifications oldest first, batchSize rows per
+// query, so the caller never holds more than one batch. Attachments are not loaded.
+// Iteration stops at the first error visit returns.
+func StreamNotifications(ctx context.Context, db *gorm.DB, tenantID string, filters NotificationListFilters, batchSize int, visit func([]Notification) error) error {
+	ctx = aggregateQueries(ctx)
+	if batchSize <= 0 {
+		batchSize = NotificationStreamBatchSize
+	}
+	var lastRecord *Notification
+	for {
+		var notifications []Notification
+		err := retryOnBusy(ctx, func() error {
+			query := applyNotificationListFilters(db.WithContext(ctx), filters).
+				Where(&Notification{TenantID: tenantID}).
+				Order(clause.OrderByColumn{Column: clause.Column{Name: notificationCreatedAtColumn}}).
+				Order(clause.OrderByColumn{Column: clause.Column{Name: notificationIDColumn}})
+			if lastRecord != nil {
+				query = query.Where(notificationStreamCursorCondition(lastRecord.CreatedAt, lastRecord.ID))
+			}
+			return query.Limit(batchSize).Find(&notifications).Error
+		})
+		if err != nil {
+			return err
+		}
+		if len(notifications) == 0 {
+			return nil
+		}
+		if err := visit(notifications); err != nil {
+			return err
+		}
+		if len(notifications) < batchSize {
+			return nil
+		}
+		lastNotification := notifications[len(notifications)-1]
+		lastRecord = &lastNotification
+	}
+}
+
 func notificationListQuery(ctx context.Context, db *gorm.DB, filters NotificationListFilters) *gorm.DB {
 	query := db.WithContext(ctx).
 		Preload("Attachments").
 		Order(clause.OrderByColumn{Column: clause.Column{Name: notificationCreatedAtColumn}, Desc: true}).
 		Order(clause.OrderByColumn{Column: clause.Column{Name: notificationIDColumn}, Desc: true})
+	return applyNotificationListFilters(query, filters)
+}
+
+func applyNotificationListFilters(query *gorm.DB, filters NotificationListFilters) *gorm.DB {
 	statuses := filters.NormalizedStatuses()
 	if len(statuses) > 0 {
 		statusValues := make([]interface{}, 0, len(statuses))
@@ -477,6 +830,23 @@ func notificationCursorCondition(cursor NotificationListCursor) clause.Expressio
 	)
 }
 
+// notificationStreamCursorCondition selects rows after the given one in ascending order.
+// The leading bound lets the tenant/created_at index seek to the cursor.
+func notificationStreamCursorCondition(createdAt time.Time, id uint) clause.Expression {
+	createdAtColumn := clause.Column{Name: notificationCreatedAtColumn}
+	idColumn := clause.Column{Name: notificationIDColumn}
+	return clause.And(
+		clause.Gte{Column: createdAtColumn, Value: createdAt},
+		clause.Or(
+			clause.Gt{Column: createdAtColumn, Value: createdAt},
+			clause.And(
+				clause.Eq{Column: createdAtColumn, Value: createdAt},
+				clause.Gt{Column: idColumn, Value: id},
+			),
+		),
+	)
+}
+
 func notificationPageFromRecords(records []Notification, limit int) (NotificationListPage, error) {
 	if len(records) <= limit {
 		return NotificationListPage{Notifications: records}, nil
@@ -510,14 +880,14 @@ func convertEmailAttachments(tenantID string, notificationID string, attachments
 	}
 	converted := make([]NotificationAttachment, 0, len(attachments))
 	for _, att := range attachments {
-		clonedData := make([]byte, len(att.Data))
-		copy(clonedData, att.Data)
+		// Request attachment data is immutable after validation, so the row shares it.
 		converted = append(converted, NotificationAttachment{
 			TenantID:       tenantID,
 			NotificationID: notificationID,
 			Filename:       att.Filename,
 			ContentType:    att.ContentType,
-			Data:           clonedData,
+			SizeBytes:      len(att.Data),
+			Data:           att.Data,
 		})
 	}
 	return converted
@@ -540,3 +910,20 @@ func ToEmailAttachments(stored []NotificationAttachment) []EmailAttachment {
 	}
 	return result
 }
+
+// SharedEmailAttachments translates stored attachments to the domain shape without
+// copying their data. Callers must not modify the data.
+func SharedEmailAttachments(stored []NotificationAttachment) []EmailAttachment {
+	if len(stored) == 0 {
+		return nil
+	}
+	result := make([]EmailAttachment, 0, len(stored))
+	for _, att := range stored {
+		result = append(result, EmailAttachment{
+			Filename:    att.Filename,
+			ContentType: att.ContentType,
+			Data:        att.Data,
+		})
+	}
+	return result
+}
@@ -3,6 +3,7 @@ package model
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -167,10 +168,14 @@ func TestNewNotificationConstructsQueuedRecord(t *testing.T) {
 				t.Fatalf("notification request error: %v", requestErr)
 			}
 
-			record := NewNotification("notif-1", modelTestTenantID, request)
+			createdAt := time.Date(2040, time.January, 2, 3, 4, 5, 0, time.UTC)
+			record := NewNotification("notif-1", modelTestTenantID, request, createdAt)
 			if record.Status != StatusQueued {
 				t.Fatalf("expected queued status, got %s", record.Status)
 			}
+			if !record.CreatedAt.Equal(createdAt) || !record.UpdatedAt.Equal(createdAt) {
+				t.Fatalf("expected timestamps %s, got created %s updated %s", createdAt, record.CreatedAt, record.UpdatedAt)
+			}
 			if record.NotificationType != NotificationEmail {
 				t.Fatalf("unexpected type %s", record.NotificationType)
 			}
@@ -211,7 +216,7 @@ func TestNewNotificationCopiesAttachments(t *testing.T) {
 		t.Fatalf("notification request error: %v", requestErr)
 	}
 
-	record := NewNotification("notif-attachments", modelTestTenantID, request)
+	record := NewNotification("notif-attachments", modelTestTenantID, request, time.Now())
 	if len(record.Attachments) != 1 {
 		t.Fatalf("expected attachment to be copied")
 	}
@@ -465,6 +470,84 @@ func TestListNotificationsPageSearchesAndPaginates(t *testing.T) {
 	}
 }
 
+func TestStreamNotificationsVisitsLargeResultOldestFirstInBatches(t *testing.T) {
+	database := openModelTestDatabase(t)
+	ctx := context.Background()
+	const rowCount = 50000
+	baseTime := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	records := make([]Notification, 0, rowCount)
+	for index := range rowCount {
+		status := StatusSent
+		if index%10 == 0 {
+			status = StatusErrored
+		}
+		records = append(records, Notification{
+			TenantID:         modelTestTenantID,
+			NotificationID:   fmt.Sprintf("notif-%05d", index),
+			NotificationType: NotificationEmail,
+			Recipient:        "user@example.com",
+			Message:          "Body",
+			Status:           status,
+			// Rows are inserted out of time order and share timestamps, so the id tie-break is exercised.
+			CreatedAt: baseTime.Add(time.Duration((index*7919)%rowCount/3) * time.Second),
+		})
+	}
+	records = append(records, Notification{TenantID: "other-tenant", NotificationID: "other", Status: StatusSent, CreatedAt: baseTime})
+	if err := database.CreateInBatches(records, 50).Error; err != nil {
+		t.Fatalf("seed notifications: %v", err)
+	}
+	records = nil
+
+	visited := 0
+	batches := 0
+	var previous Notification
+	err := StreamNotifications(ctx, database, modelTestTenantID, NotificationListFilters{}, NotificationStreamBatchSize, func(batch []Notification) error {
+		batches++
+		if len(batch) > NotificationStreamBatchSize {
+			t.Fatalf("expected at most %d rows per batch, got %d", NotificationStreamBatchSize, len(batch))
+		}
+		for _, record := range batch {
+			if record.TenantID != modelTestTenantID {
+				t.Fatalf("expected only the tenant's rows, got %q", record.TenantID)
+			}
+			if visited > 0 && (record.CreatedAt.Before(previous.CreatedAt) || record.CreatedAt.Equal(previous.CreatedAt) && record.ID <= previous.ID) {
+				t.Fatalf("expected ascending order, got %s/%d after %s/%d", record.CreatedAt, record.ID, previous.CreatedAt, previous.ID)
+			}
+			if record.Attachments != nil {
+				t.Fatalf("expected attachments not to be loaded")
+			}
+			previous = record
+			visited++
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("stream notifications: %v", err)
+	}
+	if visited != rowCount || batches != rowCount/NotificationStreamBatchSize {
+		t.Fatalf("expected %d rows in %d batches, got %d rows in %d batches", rowCount, rowCount/NotificationStreamBatchSize, visited, batches)
+	}
+
+	errored := 0
+	err = StreamNotifications(ctx, database, modelTestTenantID, NotificationListFilters{Statuses: []NotificationStatus{StatusErrored}}, 0, func(batch []Notification) error {
+		errored += len(batch)
+		return nil
+	})
+	if err != nil || errored != rowCount/10 {
+		t.Fatalf("expected %d errored rows, got %d (%v)", rowCount/10, errored, err)
+	}
+
+	stopErr := errors.New("stop")
+	calls := 0
+	err = StreamNotifications(ctx, database, modelTestTenantID, NotificationListFilters{}, 100, func([]Notification) error {
+		calls++
+		return stopErr
+	})
+	if !errors.Is(err, stopErr) || calls != 1 {
+		t.Fatalf("expected the visitor error to stop iteration after one batch, got %v after %d calls", err, calls)
+	}
+}
+
 func TestNotificationPageFromRecordsRejectsInvalidCursorRecord(t *testing.T) {
 	_, err := notificationPageFromRecords([]Notification{
 		{ID: 0, CreatedAt: time.Now().UTC()},
@@ -501,12 +584,15 @@ func TestDatabaseHelpersReturnStorageErrors(t *testing.T) {
 	if _, err := ListNotificationsAll(ctx, database, NotificationListFilters{}); err == nil {
 		t.Fatalf("expected list all storage error")
 	}
+	if err := StreamNotifications(ctx, database, modelTestTenantID, NotificationListFilters{}, 0, func([]Notification) error { return nil }); err == nil {
+		t.Fatalf("expected stream storage error")
+	}
 	if _, err := MustGetNotificationByID(ctx, database, modelTestTenantID, "notif"); err == nil || errors.Is(err, ErrNotificationNotFound) {
 		t.Fatalf("expected wrapped storage error, got %v", err)
 	}
 }
 
-func openModelTestDatabase(t *testing.T) *gorm.DB {
+func openModelTestDatabase(t testing.TB) *gorm.DB {
 	t.Helper()
 
 	databaseName := time.Now().UTC().Format("20060102150405.000000000")
@@ -514,7 +600,7 @@ func openModelTestDatabase(t *testing.T) *gorm.DB {
 	if openError != nil {
 		t.Fatalf("open database error: %v", openError)
 	}
-	if migrateError := database.AutoMigrate(&Notification{}, &NotificationAttachment{}); migrateError != nil {
+	if migrateError := database.AutoMigrate(&Notification{}, &NotificationAttachment{}, &AttachmentBlob{}, &ReportRun{}, &WorkerCheckpoint{}); migrateError != nil {
 		t.Fatalf("migration error: %v", migrateError)
 	}
 	return database
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// DefaultNotificationBatchChunkSize bounds rows per transaction when no chunk size is configured.
+// It keeps each multi-row INSERT well below SQLite's bound-parameter limit.
+const DefaultNotificationBatchChunkSize = 250
+
+// NotificationBatchErrors maps indexes in the submitted slice to their insert failure.
+type NotificationBatchErrors map[int]error
+
+// CreateNotificationsBatch inserts notifications and their attachments in one
+// transaction per chunk. When a chunk fails it is rolled back and replayed row
+// by row so only the offending rows are reported; every other row is persisted.
+func CreateNotificationsBatch(ctx context.Context, db *gorm.DB, notifications []Notification, chunkSize int) NotificationBatchErrors {
+	if chunkSize <= 0 {
+		chunkSize = DefaultNotificationBatchChunkSize
+	}
+	batchErrors := make(NotificationBatchErrors)
+	for chunkStart := 0; chunkStart < len(notifications); chunkStart += chunkSize {
+		chunkEnd := min(chunkStart+chunkSize, len(notifications))
+		chunk := notifications[chunkStart:chunkEnd]
+		chunkErr := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			for offset := range chunk {
+				restore, err := storeAttachmentBlobs(tx, chunk[offset].Attachments)
+				defer restore()
+				if err != nil {
+					return err
+				}
+			}
+			return tx.CreateInBatches(chunk, len(chunk)).Error
+		})
+		if chunkErr == nil {
+			continue
+		}
+		for offset := range chunk {
+			resetNotificationKeys(&chunk[offset])
+			if rowErr := CreateNotification(ctx, db, &chunk[offset]); rowErr != nil {
+				resetNotificationKeys(&chunk[offset])
+				batchErrors[chunkStart+offset] = rowErr
+			}
+		}
+	}
+	return batchErrors
+}
+
+// resetNotificationKeys clears primary keys assigned by a rolled-back insert.
+func resetNotificationKeys(notification *Notification) {
+	notification.ID = 0
+	for index := range notification.Attachments {
+		notification.Attachments[index].ID = 0
+	}
+}
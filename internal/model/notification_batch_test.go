@@ -0,0 +1,104 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func newBatchTestNotifications(prefix string, count int) []Notification {
+	notifications := make([]Notification, 0, count)
+	for index := 0; index < count; index++ {
+		notificationID := fmt.Sprintf("%s-%d", prefix, index)
+		notifications = append(notifications, Notification{
+			TenantID:         "tenant-batch",
+			NotificationID:   notificationID,
+			NotificationType: NotificationEmail,
+			Recipient:        "user@example.com",
+			Subject:          "Subject",
+			Message:          "Body",
+			Status:           StatusQueued,
+			Attachments: []NotificationAttachment{
+				{TenantID: "tenant-batch", NotificationID: notificationID, Filename: "note.txt", ContentType: "text/plain", Data: []byte("hello")},
+			},
+		})
+	}
+	return notifications
+}
+
+func TestCreateNotificationsBatchPersistsRowsAndAttachments(t *testing.T) {
+	database := openModelTestDatabase(t)
+	notifications := newBatchTestNotifications("ok", 7)
+
+	batchErrors := CreateNotificationsBatch(context.Background(), database, notifications, 3)
+	if len(batchErrors) != 0 {
+		t.Fatalf("expected no batch errors, got %v", batchErrors)
+	}
+	var notificationCount, attachmentCount int64
+	database.Model(&Notification{}).Count(&notificationCount)
+	database.Model(&NotificationAttachment{}).Count(&attachmentCount)
+	if notificationCount != 7 || attachmentCount != 7 {
+		t.Fatalf("expected 7 notifications and attachments, got %d and %d", notificationCount, attachmentCount)
+	}
+	for index, notification := range notifications {
+		if notification.ID == 0 {
+			t.Fatalf("expected notification %d to receive a primary key", index)
+		}
+	}
+}
+
+func TestCreateNotificationsBatchIsolatesConstraintViolation(t *testing.T) {
+	database := openModelTestDatabase(t)
+	existing := newBatchTestNotifications("dup", 1)
+	if err := CreateNotification(context.Background(), database, &existing[0]); err != nil {
+		t.Fatalf("seed existing notification: %v", err)
+	}
+	notifications := newBatchTestNotifications("row", 6)
+	notifications[4].NotificationID = "dup-0"
+	notifications[4].Attachments[0].NotificationID = "dup-0"
+
+	batchErrors := CreateNotificationsBatch(context.Background(), database, notifications, 3)
+	if len(batchErrors) != 1 || batchErrors[4] == nil {
+		t.Fatalf("expected only index 4 to fail, got %v", batchErrors)
+	}
+	if notifications[4].ID != 0 {
+		t.Fatalf("expected failed row to keep no primary key, got %d", notifications[4].ID)
+	}
+	var notificationCount, attachmentCount int64
+	database.Model(&Notification{}).Count(&notificationCount)
+	database.Model(&NotificationAttachment{}).Count(&attachmentCount)
+	if notificationCount != 6 {
+		t.Fatalf("expected seed plus five inserted notifications, got %d", notificationCount)
+	}
+	if attachmentCount != 6 {
+		t.Fatalf("expected rolled-back chunk attachments not to duplicate, got %d", attachmentCount)
+	}
+}
+
+const benchmarkNotificationCount = 10000
+
+func BenchmarkCreateNotificationSingleInserts(b *testing.B) {
+	for iteration := 0; iteration < b.N; iteration++ {
+		b.StopTimer()
+		database := openModelTestDatabase(b)
+		notifications := newBatchTestNotifications(fmt.Sprintf("single-%d", iteration), benchmarkNotificationCount)
+		b.StartTimer()
+		for index := range notifications {
+			if err := CreateNotification(context.Background(), database, &notifications[index]); err != nil {
+				b.Fatalf("insert %d: %v", index, err)
+			}
+		}
+	}
+}
+
+func BenchmarkCreateNotificationsBatch(b *testing.B) {
+	for iteration := 0; iteration < b.N; iteration++ {
+		b.StopTimer()
+		database := openModelTestDatabase(b)
+		notifications := newBatchTestNotifications(fmt.Sprintf("batch-%d", iteration), benchmarkNotificationCount)
+		b.StartTimer()
+		if batchErrors := CreateNotificationsBatch(context.Background(), database, notifications, DefaultNotificationBatchChunkSize); len(batchErrors) != 0 {
+			b.Fatalf("batch errors: %v", batchErrors)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	gsm7SingleSegmentLimit = 160
+	gsm7MultiSegmentLimit  = 153
+	ucs2SingleSegmentLimit = 70
+	ucs2MultiSegmentLimit  = 67
+
+	costSummaryDayLayout            = "2006-01-02"
+	notificationLastAttemptedColumn = "last_attempted_at"
+)
+
+// ErrInvalidCostSummaryRange reports an empty or inverted cost summary window.
+var ErrInvalidCostSummaryRange = errors.New("notification.cost_summary.invalid_range")
+
+// gsm7BasicCharacters lists the GSM 03.38 default alphabet.
+const gsm7BasicCharacters = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+
+// gsm7ExtendedCharacters require an escape septet and count as two characters.
+const gsm7ExtendedCharacters = "^{}\\[~]|€\f"
+
+// SMSSegmentCount returns the number of billable SMS segments for the message body.
+// GSM-7 bodies fit 160 characters in one segment (153 when concatenated); any
+// character outside GSM-7 switches the whole body to UCS-2 (70, or 67 when concatenated).
+func SMSSegmentCount(message string) int {
+	if message == "" {
+		return 1
+	}
+	septetCount, isGSM7 := gsm7SeptetCount(message)
+	if isGSM7 {
+		return segmentsForLength(septetCount, gsm7SingleSegmentLimit, gsm7MultiSegmentLimit)
+	}
+	return segmentsForLength(ucs2CodeUnitCount(message), ucs2SingleSegmentLimit, ucs2MultiSegmentLimit)
+}
+
+func gsm7SeptetCount(message string) (int, bool) {
+	septetCount := 0
+	for _, character := range message {
+		switch {
+		case containsRune(gsm7BasicCharacters, character):
+			septetCount++
+		case containsRune(gsm7ExtendedCharacters, character):
+			septetCount += 2
+		default:
+			return 0, false
+		}
+	}
+	return septetCount, true
+}
+
+func containsRune(alphabet string, character rune) bool {
+	for _, candidate := range alphabet {
+		if candidate == character {
+			return true
+		}
+	}
+	return false
+}
+
+func ucs2CodeUnitCount(message string) int {
+	codeUnits := 0
+	for _, character := range message {
+		if utf8.RuneLen(character) == 4 {
+			codeUnits += 2
+			continue
+		}
+		codeUnits++
+	}
+	return codeUnits
+}
+
+func segmentsForLength(length int, singleLimit int, multiLimit int) int {
+	if length <= singleLimit {
+		return 1
+	}
+	return (length + multiLimit - 1) / multiLimit
+}
+
+// CostSummaryRange is a validated half-open [start, end) window for cost aggregation.
+type CostSummaryRange struct {
+	start time.Time
+	end   time.Time
+}
+
+// NewCostSummaryRange validates that the window is non-empty.
+func NewCostSummaryRange(start time.Time, end time.Time) (CostSummaryRange, error) {
+	if start.IsZero() || end.IsZero() || !end.After(start) {
+		return CostSummaryRange{}, fmt.Errorf("%w: end must be after start", ErrInvalidCostSummaryRange)
+	}
+	return CostSummaryRange{start: start.UTC(), end: end.UTC()}, nil
+}
+
+// Start returns the inclusive window start.
+func (summaryRange CostSummaryRange) Start() time.Time {
+	return summaryRange.start
+}
+
+// End returns the exclusive window end.
+func (summaryRange CostSummaryRange) End() time.Time {
+	return summaryRange.end
+}
+
+// CostSummaryBucket aggregates sent notifications for one UTC day and channel.
+type CostSummaryBucket struct {
+	Day               string           `json:"day"`
+	NotificationType  NotificationType `json:"notification_type"`
+	NotificationCount int              `json:"notification_count"`
+	EstimatedCost     float64          `json:"estimated_cost"`
+}
+
+// CostSummary aggregates estimated spend for a tenant over a range.
+type CostSummary struct {
+	TenantID  string              `json:"tenant_id"`
+	Currency  string              `json:"currency"`
+	TotalCost float64             `json:"total_cost"`
+	Buckets   []CostSummaryBucket `json:"buckets"`
+}
+
+// SummarizeNotificationCosts groups sent notifications by dispatch day and channel.
+func SummarizeNotificationCosts(ctx context.Context, db *gorm.DB, tenantID string, summaryRange CostSummaryRange) (CostSummary, error) {
+	ctx = aggregateQueries(ctx)
+	var notifications []Notification
+	lastAttemptedColumn := clause.Column{Name: notificationLastAttemptedColumn}
+	err := db.WithContext(ctx).
+		Where(&Notification{TenantID: tenantID, Status: StatusSent}).
+		Where(clause.And(
+			clause.Gte{Column: lastAttemptedColumn, Value: summaryRange.Start()},
+			clause.Lt{Column: lastAttemptedColumn, Value: summaryRange.End()},
+		)).
+		Find(&notifications).Error
+	if err != nil {
+		return CostSummary{}, fmt.Errorf("summarize_notification_costs: %w", err)
+	}
+	return buildCostSummary(tenantID, notifications), nil
+}
+
+func buildCostSummary(tenantID string, notifications []Notification) CostSummary {
+	type bucketKey struct {
+		day              string
+		notificationType NotificationType
+	}
+	summary := CostSummary{TenantID: tenantID, Buckets: []CostSummaryBucket{}}
+	bucketIndexes := make(map[bucketKey]int)
+	for _, notification := range notifications {
+		if summary.Currency == "" {
+			summary.Currency = notification.CostCurrency
+		}
+		key := bucketKey{
+			day:              notification.LastAttemptedAt.UTC().Format(costSummaryDayLayout),
+			notificationType: notification.NotificationType,
+		}
+		bucketIndex, exists := bucketIndexes[key]
+		if !exists {
+			bucketIndex = len(summary.Buckets)
+			bucketIndexes[key] = bucketIndex
+			summary.Buckets = append(summary.Buckets, CostSummaryBucket{Day: key.day, NotificationType: key.notificationType})
+		}
+		summary.Buckets[bucketIndex].NotificationCount++
+		summary.Buckets[bucketIndex].EstimatedCost += notification.EstimatedCost
+		summary.TotalCost += notification.EstimatedCost
+	}
+	sort.Slice(summary.Buckets, func(leftIndex int, rightIndex int) bool {
+		left := summary.Buckets[leftIndex]
+		right := summary.Buckets[rightIndex]
+		if left.Day != right.Day {
+			return left.Day < right.Day
+		}
+		return left.NotificationType < right.NotificationType
+	})
+	return summary
+}
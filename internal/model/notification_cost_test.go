@@ -0,0 +1,98 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSMSSegmentCount(t *testing.T) {
+	testCases := []struct {
+		name     string
+		message  string
+		expected int
+	}{
+		{name: "empty body bills one segment", message: "", expected: 1},
+		{name: "gsm7 single segment", message: strings.Repeat("a", 160), expected: 1},
+		{name: "gsm7 concatenated", message: strings.Repeat("a", 161), expected: 2},
+		{name: "gsm7 three segments", message: strings.Repeat("a", 307), expected: 3},
+		{name: "gsm7 extended characters count double", message: strings.Repeat("€", 80), expected: 1},
+		{name: "gsm7 extended overflow", message: strings.Repeat("€", 81), expected: 2},
+		{name: "ucs2 single segment", message: strings.Repeat("ж", 70), expected: 1},
+		{name: "ucs2 concatenated", message: strings.Repeat("ж", 71), expected: 2},
+		{name: "single non gsm character switches encoding", message: strings.Repeat("a", 69) + "ж", expected: 1},
+		{name: "surrogate pairs count two units", message: strings.Repeat("😀", 36), expected: 2},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if segments := SMSSegmentCount(testCase.message); segments != testCase.expected {
+				t.Fatalf("expected %d segments, got %d", testCase.expected, segments)
+			}
+		})
+	}
+}
+
+func TestNewCostSummaryRangeRejectsEmptyWindow(t *testing.T) {
+	now := time.Now()
+	for _, window := range [][2]time.Time{
+		{now, now},
+		{now, now.Add(-time.Hour)},
+		{time.Time{}, now},
+	} {
+		if _, err := NewCostSummaryRange(window[0], window[1]); !errors.Is(err, ErrInvalidCostSummaryRange) {
+			t.Fatalf("expected invalid range error for %v, got %v", window, err)
+		}
+	}
+}
+
+func TestSummarizeNotificationCostsGroupsByDayAndChannel(t *testing.T) {
+	database := openModelTestDatabase(t)
+	dayOne := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	dayTwo := dayOne.Add(24 * time.Hour)
+	records := []Notification{
+		{NotificationID: "email-1", TenantID: "tenant-a", NotificationType: NotificationEmail, Status: StatusSent, LastAttemptedAt: dayOne, EstimatedCost: 0.001, CostCurrency: "USD"},
+		{NotificationID: "email-2", TenantID: "tenant-a", NotificationType: NotificationEmail, Status: StatusSent, LastAttemptedAt: dayOne.Add(time.Hour), EstimatedCost: 0.001, CostCurrency: "USD"},
+		{NotificationID: "sms-1", TenantID: "tenant-a", NotificationType: NotificationSMS, Status: StatusSent, LastAttemptedAt: dayOne, EstimatedCost: 0.015, CostCurrency: "USD"},
+		{NotificationID: "sms-2", TenantID: "tenant-a", NotificationType: NotificationSMS, Status: StatusSent, LastAttemptedAt: dayTwo, EstimatedCost: 0.0225, CostCurrency: "USD"},
+		{NotificationID: "errored", TenantID: "tenant-a", NotificationType: NotificationSMS, Status: StatusErrored, LastAttemptedAt: dayTwo, EstimatedCost: 9, CostCurrency: "USD"},
+		{NotificationID: "other-tenant", TenantID: "tenant-b", NotificationType: NotificationEmail, Status: StatusSent, LastAttemptedAt: dayOne, EstimatedCost: 9, CostCurrency: "USD"},
+		{NotificationID: "outside-range", TenantID: "tenant-a", NotificationType: NotificationEmail, Status: StatusSent, LastAttemptedAt: dayTwo.Add(48 * time.Hour), EstimatedCost: 9, CostCurrency: "USD"},
+	}
+	for index := range records {
+		if err := CreateNotification(context.Background(), database, &records[index]); err != nil {
+			t.Fatalf("insert %s: %v", records[index].NotificationID, err)
+		}
+	}
+	summaryRange, rangeErr := NewCostSummaryRange(dayOne.Truncate(24*time.Hour), dayTwo.Truncate(24*time.Hour).Add(24*time.Hour))
+	if rangeErr != nil {
+		t.Fatalf("range: %v", rangeErr)
+	}
+
+	summary, err := SummarizeNotificationCosts(context.Background(), database, "tenant-a", summaryRange)
+	if err != nil {
+		t.Fatalf("summarize: %v", err)
+	}
+	if summary.Currency != "USD" {
+		t.Fatalf("expected USD currency, got %q", summary.Currency)
+	}
+	if math.Abs(summary.TotalCost-0.0395) > 1e-9 {
+		t.Fatalf("expected total 0.0395, got %v", summary.TotalCost)
+	}
+	expectedBuckets := []CostSummaryBucket{
+		{Day: "2026-03-01", NotificationType: NotificationEmail, NotificationCount: 2, EstimatedCost: 0.002},
+		{Day: "2026-03-01", NotificationType: NotificationSMS, NotificationCount: 1, EstimatedCost: 0.015},
+		{Day: "2026-03-02", NotificationType: NotificationSMS, NotificationCount: 1, EstimatedCost: 0.0225},
+	}
+	if len(summary.Buckets) != len(expectedBuckets) {
+		t.Fatalf("expected %d buckets, got %+v", len(expectedBuckets), summary.Buckets)
+	}
+	for index, expected := range expectedBuckets {
+		actual := summary.Buckets[index]
+		if actual.Day != expected.Day || actual.NotificationType != expected.NotificationType || actual.NotificationCount != expected.NotificationCount || math.Abs(actual.EstimatedCost-expected.EstimatedCost) > 1e-9 {
+			t.Fatalf("bucket %d: expected %+v, got %+v", index, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrInvalidNotificationFields reports an empty or unknown sparse fieldset entry.
+var ErrInvalidNotificationFields = errors.New("invalid notification fields")
+
+// notificationResponseFieldNames lists the JSON field names a fieldset may select.
+var notificationResponseFieldNames = jsonFieldNames(reflect.TypeOf(NotificationResponse{}))
+
+// NotificationFieldSet selects which NotificationResponse JSON fields to return.
+// The zero value selects every field.
+type NotificationFieldSet struct {
+	fields map[string]struct{}
+}
+
+// NewNotificationFieldSet parses a comma-separated list of NotificationResponse JSON field names.
+func NewNotificationFieldSet(rawValue string) (NotificationFieldSet, error) {
+	normalized := strings.TrimSpace(rawValue)
+	if normalized == "" {
+		return NotificationFieldSet{}, nil
+	}
+	fields := make(map[string]struct{})
+	for _, candidate := range strings.Split(normalized, ",") {
+		fieldName := strings.TrimSpace(candidate)
+		if fieldName == "" {
+			return NotificationFieldSet{}, fmt.Errorf("%w: empty field name", ErrInvalidNotificationFields)
+		}
+		if _, known := notificationResponseFieldNames[fieldName]; !known {
+			return NotificationFieldSet{}, fmt.Errorf("%w: unknown field %q", ErrInvalidNotificationFields, fieldName)
+		}
+		fields[fieldName] = struct{}{}
+	}
+	return NotificationFieldSet{fields: fields}, nil
+}
+
+// SelectsAll reports whether the fieldset returns the full response.
+func (fieldSet NotificationFieldSet) SelectsAll() bool {
+	return len(fieldSet.fields) == 0
+}
+
+// Project returns only the selected JSON fields of the response.
+func (fieldSet NotificationFieldSet) Project(response NotificationResponse) (map[string]json.RawMessage, error) {
+	encoded, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("project notification fields: %w", err)
+	}
+	var projected map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &projected); err != nil {
+		return nil, fmt.Errorf("project notification fields: %w", err)
+	}
+	if fieldSet.SelectsAll() {
+		return projected, nil
+	}
+	for fieldName := range projected {
+		if _, selected := fieldSet.fields[fieldName]; !selected {
+			delete(projected, fieldName)
+		}
+	}
+	return projected, nil
+}
+
+func jsonFieldNames(structType reflect.Type) map[string]struct{} {
+	fieldNames := make(map[string]struct{}, structType.NumField())
+	for index := 0; index < structType.NumField(); index++ {
+		tagName, _, _ := strings.Cut(structType.Field(index).Tag.Get("json"), ",")
+		if tagName == "" || tagName == "-" {
+			continue
+		}
+		fieldNames[tagName] = struct{}{}
+	}
+	return fieldNames
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewNotificationFieldSet(t *testing.T) {
+	testCases := []struct {
+		name        string
+		rawValue    string
+		expectAll   bool
+		expectedErr error
+	}{
+		{name: "empty selects all", rawValue: "  ", expectAll: true},
+		{name: "known fields", rawValue: "notification_id, status,created_at"},
+		{name: "unknown field", rawValue: "notification_id,secret", expectedErr: ErrInvalidNotificationFields},
+		{name: "empty entry", rawValue: "notification_id,,status", expectedErr: ErrInvalidNotificationFields},
+		{name: "go field names are not json names", rawValue: "NotificationID", expectedErr: ErrInvalidNotificationFields},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			fieldSet, err := NewNotificationFieldSet(testCase.rawValue)
+			if !errors.Is(err, testCase.expectedErr) {
+				t.Fatalf("expected error %v, got %v", testCase.expectedErr, err)
+			}
+			if err == nil && fieldSet.SelectsAll() != testCase.expectAll {
+				t.Fatalf("expected SelectsAll %v", testCase.expectAll)
+			}
+		})
+	}
+}
+
+func TestNotificationFieldSetProjectKeepsOnlySelectedFields(t *testing.T) {
+	fieldSet, err := NewNotificationFieldSet("notification_id,status,scheduled_for")
+	if err != nil {
+		t.Fatalf("field set: %v", err)
+	}
+	scheduledFor := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	projected, projectErr := fieldSet.Project(NotificationResponse{
+		NotificationID: "notif-1",
+		Status:         StatusQueued,
+		Recipient:      "user@example.com",
+		ScheduledFor:   &scheduledFor,
+	})
+	if projectErr != nil {
+		t.Fatalf("project: %v", projectErr)
+	}
+	if len(projected) != 3 {
+		t.Fatalf("expected three fields, got %v", projected)
+	}
+	if string(projected["notification_id"]) != `"notif-1"` || string(projected["status"]) != `"queued"` || string(projected["scheduled_for"]) != `"2026-03-01T10:00:00Z"` {
+		t.Fatalf("unexpected projection %v", projected)
+	}
+}
@@ -0,0 +1,127 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MaxNotificationGroupChannels caps the channels of one multi-channel send.
+const MaxNotificationGroupChannels = 10
+
+const notificationGroupIDColumn = "group_id"
+
+var (
+	// ErrNotificationGroupChannelsRequired indicates a group request without channels.
+	ErrNotificationGroupChannelsRequired = errors.New("notification.request.channels_required")
+	// ErrNotificationGroupTooManyChannels indicates a group request over MaxNotificationGroupChannels.
+	ErrNotificationGroupTooManyChannels = errors.New("notification.request.channels_count_exceeded")
+	// ErrNotificationSubjectRequired indicates an email channel of a group without a subject.
+	ErrNotificationSubjectRequired = errors.New("notification.request.subject_required")
+	// ErrNotificationGroupNotFound indicates no notification of the tenant carries the group id.
+	ErrNotificationGroupNotFound = errors.New("notification group not found")
+)
+
+// NotificationGroupRequest is a validated multi-channel send. Each channel becomes its
+// own notification, dispatched independently and linked to the others by a group id.
+type NotificationGroupRequest struct {
+	channels []NotificationRequest
+}
+
+// NewNotificationGroupRequest validates the channels of a multi-channel send. Each
+// channel is a request built by NewNotificationRequest; email channels must also carry
+// a subject. Errors name the 1-based channel they concern.
+func NewNotificationGroupRequest(channels []NotificationRequest) (NotificationGroupRequest, error) {
+	if len(channels) == 0 {
+		return NotificationGroupRequest{}, ErrNotificationGroupChannelsRequired
+	}
+	if len(channels) > MaxNotificationGroupChannels {
+		return NotificationGroupRequest{}, fmt.Errorf(wrapWithMaxTemplate, ErrNotificationGroupTooManyChannels, MaxNotificationGroupChannels)
+	}
+	for channelIndex, channel := range channels {
+		if channel.notificationType == NotificationEmail && strings.TrimSpace(channel.subject) == "" {
+			return NotificationGroupRequest{}, fmt.Errorf("%w: channel %d", ErrNotificationSubjectRequired, channelIndex+1)
+		}
+	}
+	return NotificationGroupRequest{channels: append([]NotificationRequest(nil), channels...)}, nil
+}
+
+// Channels returns the per-channel requests in submission order.
+func (request NotificationGroupRequest) Channels() []NotificationRequest {
+	return append([]NotificationRequest(nil), request.channels...)
+}
+
+// NotificationGroupResponse is the combined state of a multi-channel send.
+type NotificationGroupResponse struct {
+	GroupID string `json:"group_id"`
+	// Status summarizes the channels; see NotificationGroupStatus.
+	Status        NotificationStatus     `json:"status"`
+	Notifications []NotificationResponse `json:"notifications"`
+}
+
+// NewNotificationGroupResponse translates the group's stored notifications to a response.
+func NewNotificationGroupResponse(groupID string, notifications []Notification) NotificationGroupResponse {
+	responses := make([]NotificationResponse, 0, len(notifications))
+	statuses := make([]NotificationStatus, 0, len(notifications))
+	for _, notification := range notifications {
+		response := NewNotificationResponse(notification)
+		responses = append(responses, response)
+		statuses = append(statuses, response.Status)
+	}
+	return NotificationGroupResponse{
+		GroupID:       groupID,
+		Status:        NotificationGroupStatus(statuses),
+		Notifications: responses,
+	}
+}
+
+// NotificationGroupStatus combines channel statuses: queued while any channel awaits
+// dispatch, errored while any channel awaits a retry, sent once every channel finished
+// and at least one was sent, and cancelled when every channel was cancelled.
+func NotificationGroupStatus(statuses []NotificationStatus) NotificationStatus {
+	if len(statuses) == 0 {
+		return StatusUnknown
+	}
+	combined := StatusCancelled
+	for _, status := range statuses {
+		switch {
+		case status == StatusQueued:
+			return StatusQueued
+		case status == StatusErrored:
+			combined = StatusErrored
+		case status == StatusSent && combined == StatusCancelled:
+			combined = StatusSent
+		}
+	}
+	return combined
+}
+
+// ListNotificationGroup returns the tenant's notifications sharing groupID in the order
+// they were created.
+func ListNotificationGroup(ctx context.Context, db *gorm.DB, tenantID string, groupID string) ([]Notification, error) {
+	var notifications []Notification
+	err := retryOnBusy(ctx, func() error {
+		if err := db.WithContext(ctx).
+			Preload("Attachments").
+			Where(clause.And(
+				clause.Eq{Column: clause.Column{Name: notificationTenantIDColumn}, Value: tenantID},
+				clause.Eq{Column: clause.Column{Name: notificationGroupIDColumn}, Value: groupID},
+			)).
+			Order(clause.OrderByColumn{Column: clause.Column{Name: notificationIDColumn}}).
+			Find(&notifications).Error; err != nil {
+			return err
+		}
+		return hydrateNotificationList(db.WithContext(ctx), notifications)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list_notification_group: %w", err)
+	}
+	if len(notifications) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotificationGroupNotFound, groupID)
+	}
+	return notifications, nil
+}
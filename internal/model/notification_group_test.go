@@ -0,0 +1,62 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewNotificationGroupRequestValidatesChannels(t *testing.T) {
+	email, err := NewNotificationRequest(NotificationEmail, "user@example.com", "", "Body", nil, nil)
+	if err != nil {
+		t.Fatalf("email request: %v", err)
+	}
+	sms, err := NewNotificationRequest(NotificationSMS, "+15555550100", "", "Body", nil, nil)
+	if err != nil {
+		t.Fatalf("sms request: %v", err)
+	}
+	tooMany := make([]NotificationRequest, MaxNotificationGroupChannels+1)
+	for index := range tooMany {
+		tooMany[index] = sms
+	}
+	testCases := []struct {
+		name          string
+		channels      []NotificationRequest
+		expectedErr   error
+		expectedInErr string
+	}{
+		{name: "no channels", expectedErr: ErrNotificationGroupChannelsRequired},
+		{name: "too many channels", channels: tooMany, expectedErr: ErrNotificationGroupTooManyChannels},
+		{name: "email without subject", channels: []NotificationRequest{sms, email}, expectedErr: ErrNotificationSubjectRequired, expectedInErr: "channel 2"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewNotificationGroupRequest(testCase.channels)
+			if !errors.Is(err, testCase.expectedErr) || !strings.Contains(err.Error(), testCase.expectedInErr) {
+				t.Fatalf("expected %v mentioning %q, got %v", testCase.expectedErr, testCase.expectedInErr, err)
+			}
+		})
+	}
+}
+
+func TestNotificationGroupStatus(t *testing.T) {
+	testCases := []struct {
+		name     string
+		statuses []NotificationStatus
+		expected NotificationStatus
+	}{
+		{name: "any queued", statuses: []NotificationStatus{StatusSent, StatusErrored, StatusQueued}, expected: StatusQueued},
+		{name: "errored awaiting retry", statuses: []NotificationStatus{StatusSent, StatusErrored}, expected: StatusErrored},
+		{name: "all sent", statuses: []NotificationStatus{StatusSent, StatusSent}, expected: StatusSent},
+		{name: "sent and cancelled", statuses: []NotificationStatus{StatusCancelled, StatusSent}, expected: StatusSent},
+		{name: "all cancelled", statuses: []NotificationStatus{StatusCancelled, StatusCancelled}, expected: StatusCancelled},
+		{name: "no channels", expected: StatusUnknown},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if combined := NotificationGroupStatus(testCase.statuses); combined != testCase.expected {
+				t.Fatalf("expected %s, got %s", testCase.expected, combined)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	notificationOpenOpenedAtColumn       = "opened_at"
+	notificationOpenNotificationIDColumn = "notification_id"
+	notificationOpenSaveBatchSize        = 500
+)
+
+// NotificationOpen records one load of an email's open tracking pixel. Only the time and
+// a coarse user-agent family are kept; client addresses are never stored.
+type NotificationOpen struct {
+	ID              uint      `gorm:"primaryKey"`
+	TenantID        string    `gorm:"index:idx_notification_open_notification;not null"`
+	NotificationID  string    `gorm:"index:idx_notification_open_notification;not null"`
+	OpenedAt        time.Time `gorm:"not null"`
+	UserAgentFamily string
+	CreatedAt       time.Time
+}
+
+// NotificationOpenSummary is the open state of one tracked email.
+type NotificationOpenSummary struct {
+	OpenCount     int64      `json:"open_count" description:"Number of times the tracking pixel was loaded."`
+	FirstOpenedAt *time.Time `json:"first_opened_at,omitempty" description:"Time the email was first opened."`
+	LastOpenedAt  *time.Time `json:"last_opened_at,omitempty" description:"Time the email was last opened."`
+}
+
+// SaveNotificationOpens inserts recorded opens in batches.
+func SaveNotificationOpens(ctx context.Context, db *gorm.DB, opens []NotificationOpen) error {
+	if len(opens) == 0 {
+		return nil
+	}
+	err := retryOnBusy(ctx, func() error {
+		return db.WithContext(ctx).CreateInBatches(opens, notificationOpenSaveBatchSize).Error
+	})
+	if err != nil {
+		return fmt.Errorf("save_notification_opens: %w", err)
+	}
+	return nil
+}
+
+// SummarizeNotificationOpens counts a notification's opens and finds the first and last.
+func SummarizeNotificationOpens(ctx context.Context, db *gorm.DB, tenantID string, notificationID string) (NotificationOpenSummary, error) {
+	var summary NotificationOpenSummary
+	database := db.WithContext(ctx)
+	byNotification := &NotificationOpen{TenantID: tenantID, NotificationID: notificationID}
+	if err := database.Model(&NotificationOpen{}).Where(byNotification).Count(&summary.OpenCount).Error; err != nil {
+		return NotificationOpenSummary{}, fmt.Errorf("summarize_notification_opens: count: %w", err)
+	}
+	if summary.OpenCount == 0 {
+		return summary, nil
+	}
+	for _, bound := range []struct {
+		desc   bool
+		target **time.Time
+	}{
+		{desc: false, target: &summary.FirstOpenedAt},
+		{desc: true, target: &summary.LastOpenedAt},
+	} {
+		var openedAt []time.Time
+		if err := database.Model(&NotificationOpen{}).
+			Where(byNotification).
+			Order(clause.OrderByColumn{Column: clause.Column{Name: notificationOpenOpenedAtColumn}, Desc: bound.desc}).
+			Limit(1).
+			Pluck(notificationOpenOpenedAtColumn, &openedAt).Error; err != nil {
+			return NotificationOpenSummary{}, fmt.Errorf("summarize_notification_opens: bounds: %w", err)
+		}
+		if len(openedAt) > 0 {
+			*bound.target = utcTimePointer(&openedAt[0])
+		}
+	}
+	return summary, nil
+}
+
+// CountOpenedNotifications returns how many of a tenant's notifications were opened at least once.
+func CountOpenedNotifications(ctx context.Context, db *gorm.DB, tenantID string) (int64, error) {
+	var opened int64
+	if err := db.WithContext(aggregateQueries(ctx)).Model(&NotificationOpen{}).
+		Where(&NotificationOpen{TenantID: tenantID}).
+		Distinct(notificationOpenNotificationIDColumn).
+		Count(&opened).Error; err != nil {
+		return 0, fmt.Errorf("count_opened_notifications: %w", err)
+	}
+	return opened, nil
+}
@@ -5,19 +5,28 @@ import (
 	"fmt"
 	"strings"
 	"time"
+	"unicode"
+
+	attachmenttypes "github.com/tyemirov/pinguin/pkg/attachments"
 )
 
 const (
-	maxNotificationAttachmentCount       = 10
-	maxNotificationAttachmentSizeBytes   = 5 * 1024 * 1024
-	maxNotificationAttachmentsTotalBytes = 25 * 1024 * 1024
-	defaultAttachmentContentType         = "application/octet-stream"
-	attachmentIndexTemplate              = "attachment %d"
-	attachmentFilenameTemplate           = "attachment %q"
-	attachmentMaxTemplate                = "max %d"
-	wrapWithIndexTemplate                = "%w: " + attachmentIndexTemplate
-	wrapWithFilenameTemplate             = "%w: " + attachmentFilenameTemplate
-	wrapWithMaxTemplate                  = "%w: " + attachmentMaxTemplate
+	// MaxNotificationAttachmentCount caps attachments per email notification.
+	MaxNotificationAttachmentCount = 10
+	// MaxNotificationAttachmentSizeBytes caps a single attachment payload.
+	MaxNotificationAttachmentSizeBytes = 5 * 1024 * 1024
+	// MaxNotificationAttachmentsTotalBytes caps the combined attachment payload.
+	MaxNotificationAttachmentsTotalBytes = 25 * 1024 * 1024
+)
+
+const (
+	defaultAttachmentContentType = "application/octet-stream"
+	attachmentIndexTemplate      = "attachment %d"
+	attachmentFilenameTemplate   = "attachment %q"
+	attachmentMaxTemplate        = "max %d"
+	wrapWithIndexTemplate        = "%w: " + attachmentIndexTemplate
+	wrapWithFilenameTemplate     = "%w: " + attachmentFilenameTemplate
+	wrapWithMaxTemplate          = "%w: " + attachmentMaxTemplate
 )
 
 var (
@@ -39,14 +48,28 @@ var (
 	ErrNotificationAttachmentTooLarge = errors.New("notification.request.attachment_size_exceeded")
 	// ErrNotificationAttachmentsTooLarge indicates attachments exceed the total size limit.
 	ErrNotificationAttachmentsTooLarge = errors.New("notification.request.attachments_total_size_exceeded")
+	// ErrNotificationExpiryInvalid indicates the expiry is missing or not after the scheduled time.
+	ErrNotificationExpiryInvalid = errors.New("notification.request.invalid_expiry")
+	// ErrNotificationHeaderValueInvalid indicates a value bound for a message header
+	// carries CR, LF or another control character.
+	ErrNotificationHeaderValueInvalid = errors.New("notification.request.invalid_header_value")
 )
 
 // NewNotificationRequest validates and normalizes a notification request payload.
+// The request takes ownership of the attachment data without copying it: callers must
+// not modify the byte slices afterwards, and the model never does.
 func NewNotificationRequest(notificationType NotificationType, recipient string, subject string, message string, scheduledFor *time.Time, attachments []EmailAttachment) (NotificationRequest, error) {
 	normalizedRecipient := strings.TrimSpace(recipient)
 	if normalizedRecipient == "" {
 		return NotificationRequest{}, ErrNotificationRecipientRequired
 	}
+	if err := ValidateHeaderValue("recipient", normalizedRecipient); err != nil {
+		return NotificationRequest{}, err
+	}
+	normalizedSubject := strings.TrimSpace(subject)
+	if err := ValidateHeaderValue("subject", normalizedSubject); err != nil {
+		return NotificationRequest{}, err
+	}
 	normalizedMessage := strings.TrimSpace(message)
 	if normalizedMessage == "" {
 		return NotificationRequest{}, ErrNotificationMessageRequired
@@ -66,13 +89,49 @@ func NewNotificationRequest(notificationType NotificationType, recipient string,
 	return NotificationRequest{
 		notificationType: notificationType,
 		recipient:        normalizedRecipient,
-		subject:          strings.TrimSpace(subject),
+		subject:          normalizedSubject,
 		message:          message,
 		scheduledFor:     normalizedSchedule,
 		attachments:      normalizedAttachments,
 	}, nil
 }
 
+// WithExpiresAt returns a copy of the request that must not be sent after expiresAt.
+// The expiry must be after the scheduled time when one is set.
+func (request NotificationRequest) WithExpiresAt(expiresAt time.Time) (NotificationRequest, error) {
+	if expiresAt.IsZero() {
+		return NotificationRequest{}, fmt.Errorf("%w: expires_at is required", ErrNotificationExpiryInvalid)
+	}
+	normalizedExpiry := expiresAt.UTC()
+	if request.scheduledFor != nil && !normalizedExpiry.After(*request.scheduledFor) {
+		return NotificationRequest{}, fmt.Errorf("%w: expires_at must be after scheduled_time", ErrNotificationExpiryInvalid)
+	}
+	request.expiresAt = &normalizedExpiry
+	return request, nil
+}
+
+// WithSource returns a copy of the request tagged with the system component that created it.
+func (request NotificationRequest) WithSource(source NotificationSource) NotificationRequest {
+	request.source = source
+	return request
+}
+
+// WithFailOnImmediateError returns a copy of the request that overrides the server's
+// failOnImmediateError setting: when fail is true, a failed immediate dispatch is
+// reported to the caller even though the notification stays queued for retry.
+func (request NotificationRequest) WithFailOnImmediateError(fail bool) NotificationRequest {
+	request.failOnImmediate = &fail
+	return request
+}
+
+// FailOnImmediateError returns the request's override and whether one was set.
+func (request NotificationRequest) FailOnImmediateError() (bool, bool) {
+	if request.failOnImmediate == nil {
+		return false, false
+	}
+	return *request.failOnImmediate, true
+}
+
 // NotificationType returns the request notification type.
 func (request NotificationRequest) NotificationType() NotificationType {
 	return request.notificationType
@@ -102,11 +161,29 @@ func (request NotificationRequest) ScheduledFor() *time.Time {
 	return &scheduleCopy
 }
 
+// ExpiresAt returns the do-not-send-after time in UTC, when present.
+func (request NotificationRequest) ExpiresAt() *time.Time {
+	if request.expiresAt == nil {
+		return nil
+	}
+	expiryCopy := request.expiresAt.UTC()
+	return &expiryCopy
+}
+
 // Attachments returns a copy of the normalized attachments.
 func (request NotificationRequest) Attachments() []EmailAttachment {
 	return cloneEmailAttachments(request.attachments)
 }
 
+// SharedAttachments returns the normalized attachments without copying their data,
+// for the dispatch path that only reads them. Callers must not modify the data.
+func (request NotificationRequest) SharedAttachments() []EmailAttachment {
+	if len(request.attachments) == 0 {
+		return nil
+	}
+	return append([]EmailAttachment(nil), request.attachments...)
+}
+
 func isSupportedNotificationType(notificationType NotificationType) bool {
 	switch notificationType {
 	case NotificationEmail, NotificationSMS:
@@ -123,8 +200,8 @@ func normalizeNotificationAttachments(notificationType NotificationType, attachm
 	if notificationType != NotificationEmail {
 		return nil, ErrNotificationAttachmentsNotAllowed
 	}
-	if len(attachments) > maxNotificationAttachmentCount {
-		return nil, fmt.Errorf(wrapWithMaxTemplate, ErrNotificationAttachmentsTooMany, maxNotificationAttachmentCount)
+	if len(attachments) > MaxNotificationAttachmentCount {
+		return nil, fmt.Errorf(wrapWithMaxTemplate, ErrNotificationAttachmentsTooMany, MaxNotificationAttachmentCount)
 	}
 
 	totalSize := 0
@@ -134,33 +211,57 @@ func normalizeNotificationAttachments(notificationType NotificationType, attachm
 		if filename == "" {
 			return nil, fmt.Errorf(wrapWithIndexTemplate, ErrNotificationAttachmentFilenameRequired, attachmentIndex+1)
 		}
-		dataCopy := append([]byte(nil), attachment.Data...)
-		payloadSize := len(dataCopy)
+		payloadSize := len(attachment.Data)
 		if payloadSize == 0 {
 			return nil, fmt.Errorf(wrapWithFilenameTemplate, ErrNotificationAttachmentDataRequired, filename)
 		}
-		if payloadSize > maxNotificationAttachmentSizeBytes {
+		if payloadSize > MaxNotificationAttachmentSizeBytes {
 			return nil, fmt.Errorf(wrapWithFilenameTemplate, ErrNotificationAttachmentTooLarge, filename)
 		}
 		totalSize += payloadSize
 
 		contentType := strings.TrimSpace(attachment.ContentType)
+		if contentType == "" {
+			contentType = attachmenttypes.TypeByExtension(filename)
+		}
 		if contentType == "" {
 			contentType = defaultAttachmentContentType
 		}
+		if err := ValidateHeaderValue(fmt.Sprintf("attachment %d content_type", attachmentIndex+1), contentType); err != nil {
+			return nil, err
+		}
 		normalized = append(normalized, EmailAttachment{
 			Filename:    filename,
 			ContentType: contentType,
-			Data:        dataCopy,
+			Data:        attachment.Data,
 		})
 	}
 
-	if totalSize > maxNotificationAttachmentsTotalBytes {
-		return nil, fmt.Errorf(wrapWithMaxTemplate, ErrNotificationAttachmentsTooLarge, maxNotificationAttachmentsTotalBytes)
+	if totalSize > MaxNotificationAttachmentsTotalBytes {
+		return nil, fmt.Errorf(wrapWithMaxTemplate, ErrNotificationAttachmentsTooLarge, MaxNotificationAttachmentsTotalBytes)
 	}
 	return normalized, nil
 }
 
+// ValidateHeaderValue rejects values that would let a caller end a header line early
+// and inject headers or SMTP commands. Horizontal tabs are allowed; every other
+// control character, CR and LF included, is not. The error names the field.
+func ValidateHeaderValue(field string, value string) error {
+	for _, character := range value {
+		if character != '\t' && unicode.IsControl(character) {
+			return fmt.Errorf("%w: %s contains control characters", ErrNotificationHeaderValueInvalid, field)
+		}
+	}
+	return nil
+}
+
+// IsHTMLMessage reports whether an email body is an HTML document, which is sent as
+// text/html: after leading whitespace it starts with a doctype or an <html> tag.
+func IsHTMLMessage(message string) bool {
+	lowered := strings.ToLower(strings.TrimSpace(message))
+	return strings.HasPrefix(lowered, "<!doctype html") || strings.HasPrefix(lowered, "<html")
+}
+
 func cloneEmailAttachments(attachments []EmailAttachment) []EmailAttachment {
 	if len(attachments) == 0 {
 		return nil
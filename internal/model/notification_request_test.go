@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"mime"
+	"strings"
 	"testing"
 	"time"
 )
@@ -82,6 +84,43 @@ func TestNewNotificationRequestValidation(t *testing.T) {
 	}
 }
 
+func TestNewNotificationRequestRejectsHeaderInjection(t *testing.T) {
+	testCases := []struct {
+		name          string
+		recipient     string
+		subject       string
+		contentType   string
+		expectedField string
+	}{
+		{name: "SubjectBccInjection", recipient: sampleRecipient, subject: "Hello\r\nBcc: attacker@example.com", expectedField: "subject"},
+		{name: "SubjectBareLineFeed", recipient: sampleRecipient, subject: "Hello\nX-Injected: 1", expectedField: "subject"},
+		{name: "SubjectNullByte", recipient: sampleRecipient, subject: "Hello\x00", expectedField: "subject"},
+		{name: "RecipientRcptInjection", recipient: "user@example.com\r\nRCPT TO:<attacker@example.com>", expectedField: "recipient"},
+		{name: "RecipientEmbeddedCarriageReturn", recipient: "user@exa\rmple.com", expectedField: "recipient"},
+		{name: "AttachmentContentTypeInjection", recipient: sampleRecipient, contentType: "text/plain\r\nBcc: attacker@example.com", expectedField: "attachment 1 content_type"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var attachments []EmailAttachment
+			if testCase.contentType != "" {
+				attachments = []EmailAttachment{{Filename: sampleFilename, ContentType: testCase.contentType, Data: []byte("data")}}
+			}
+			_, requestErr := NewNotificationRequest(NotificationEmail, testCase.recipient, testCase.subject, sampleMessage, nil, attachments)
+			if !errors.Is(requestErr, ErrNotificationHeaderValueInvalid) {
+				t.Fatalf("expected header value error, got %v", requestErr)
+			}
+			if !strings.Contains(requestErr.Error(), testCase.expectedField) {
+				t.Fatalf("expected the error to name %q, got %v", testCase.expectedField, requestErr)
+			}
+		})
+	}
+
+	request, requestErr := NewNotificationRequest(NotificationEmail, sampleRecipient, "Tabbed\tsubject", "Line one\r\nLine two", nil, nil)
+	if requestErr != nil || request.Subject() != "Tabbed\tsubject" {
+		t.Fatalf("expected tabs in the subject and line breaks in the body to be accepted, got %v", requestErr)
+	}
+}
+
 func TestNewNotificationRequestNormalizesAttachments(t *testing.T) {
 	t.Helper()
 
@@ -103,16 +142,19 @@ func TestNewNotificationRequestNormalizesAttachments(t *testing.T) {
 		t.Fatalf("notification request error: %v", requestErr)
 	}
 
-	originalData[0] = 0x03
+	shared := request.SharedAttachments()
+	if len(shared) != 1 || &shared[0].Data[0] != &originalData[0] {
+		t.Fatalf("expected the request to take the attachment data over without a copy")
+	}
 	attachments := request.Attachments()
 	if len(attachments) != 1 {
 		t.Fatalf("expected 1 attachment, got %d", len(attachments))
 	}
-	if attachments[0].ContentType != defaultAttachmentContentType {
-		t.Fatalf("expected default content type, got %s", attachments[0].ContentType)
+	if !strings.HasPrefix(attachments[0].ContentType, "text/plain") {
+		t.Fatalf("expected a text/plain content type from the extension, got %s", attachments[0].ContentType)
 	}
-	if attachments[0].Data[0] == originalData[0] {
-		t.Fatalf("expected attachment data to be copied")
+	if &attachments[0].Data[0] == &originalData[0] {
+		t.Fatalf("expected Attachments to copy the data")
 	}
 
 	attachments[0].Data[0] = 0x04
@@ -160,6 +202,50 @@ func TestNotificationRequestAccessorsReturnNormalizedCopies(t *testing.T) {
 	}
 }
 
+func TestNotificationRequestWithExpiresAt(t *testing.T) {
+	scheduledTime := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	scheduledRequest, scheduledErr := NewNotificationRequest(NotificationEmail, sampleRecipient, "Subject", sampleMessage, &scheduledTime, nil)
+	if scheduledErr != nil {
+		t.Fatalf("scheduled request: %v", scheduledErr)
+	}
+	immediateRequest, immediateErr := NewNotificationRequest(NotificationEmail, sampleRecipient, "Subject", sampleMessage, nil, nil)
+	if immediateErr != nil {
+		t.Fatalf("immediate request: %v", immediateErr)
+	}
+	testCases := []struct {
+		name        string
+		request     NotificationRequest
+		expiresAt   time.Time
+		expectError bool
+	}{
+		{name: "zero expiry", request: immediateRequest, expectError: true},
+		{name: "expiry before schedule", request: scheduledRequest, expiresAt: scheduledTime.Add(-time.Minute), expectError: true},
+		{name: "expiry equal to schedule", request: scheduledRequest, expiresAt: scheduledTime, expectError: true},
+		{name: "expiry after schedule", request: scheduledRequest, expiresAt: scheduledTime.Add(time.Hour)},
+		{name: "expiry without schedule", request: immediateRequest, expiresAt: scheduledTime},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			updated, err := testCase.request.WithExpiresAt(testCase.expiresAt.In(time.FixedZone("offset", 3600)))
+			if testCase.expectError {
+				if !errors.Is(err, ErrNotificationExpiryInvalid) {
+					t.Fatalf("expected invalid expiry error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if updated.ExpiresAt() == nil || !updated.ExpiresAt().Equal(testCase.expiresAt) || updated.ExpiresAt().Location() != time.UTC {
+				t.Fatalf("unexpected expiry %v", updated.ExpiresAt())
+			}
+			if testCase.request.ExpiresAt() != nil {
+				t.Fatalf("expected original request to stay unchanged")
+			}
+		})
+	}
+}
+
 func TestNewNotificationRequestAttachmentValidation(t *testing.T) {
 	t.Helper()
 
@@ -181,8 +267,8 @@ func TestNewNotificationRequestAttachmentValidation(t *testing.T) {
 		{
 			name: "TooManyAttachments",
 			attachments: func() []EmailAttachment {
-				result := make([]EmailAttachment, 0, maxNotificationAttachmentCount+1)
-				for attachmentIndex := 0; attachmentIndex < maxNotificationAttachmentCount+1; attachmentIndex++ {
+				result := make([]EmailAttachment, 0, MaxNotificationAttachmentCount+1)
+				for attachmentIndex := 0; attachmentIndex < MaxNotificationAttachmentCount+1; attachmentIndex++ {
 					result = append(result, EmailAttachment{
 						Filename:    sampleFilename,
 						ContentType: sampleContentType,
@@ -198,7 +284,7 @@ func TestNewNotificationRequestAttachmentValidation(t *testing.T) {
 			attachments: []EmailAttachment{
 				{
 					Filename: "big.bin",
-					Data:     bytes.Repeat([]byte("x"), maxNotificationAttachmentSizeBytes+1),
+					Data:     bytes.Repeat([]byte("x"), MaxNotificationAttachmentSizeBytes+1),
 				},
 			},
 			expectedError: ErrNotificationAttachmentTooLarge,
@@ -206,9 +292,9 @@ func TestNewNotificationRequestAttachmentValidation(t *testing.T) {
 		{
 			name: "AggregateTooLarge",
 			attachments: func() []EmailAttachment {
-				chunkSize := (maxNotificationAttachmentsTotalBytes / 5) + 1
-				if chunkSize >= maxNotificationAttachmentSizeBytes {
-					chunkSize = maxNotificationAttachmentSizeBytes - 10
+				chunkSize := (MaxNotificationAttachmentsTotalBytes / 5) + 1
+				if chunkSize >= MaxNotificationAttachmentSizeBytes {
+					chunkSize = MaxNotificationAttachmentSizeBytes - 10
 				}
 				result := make([]EmailAttachment, 0, 6)
 				for attachmentIndex := 0; attachmentIndex < 6; attachmentIndex++ {
@@ -242,3 +328,29 @@ func TestNewNotificationRequestAttachmentValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeNotificationAttachmentsInfersContentTypeFromExtension(t *testing.T) {
+	testCases := []struct {
+		filename    string
+		contentType string
+		expected    string
+	}{
+		{filename: "notes.txt", expected: "text/plain"},
+		{filename: "export.CSV", expected: "text/csv"},
+		{filename: "payload.unknownext", expected: defaultAttachmentContentType},
+		{filename: "no-extension", expected: defaultAttachmentContentType},
+		{filename: "export.csv", contentType: "application/vnd.ms-excel", expected: "application/vnd.ms-excel"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.filename, func(t *testing.T) {
+			normalized, err := normalizeNotificationAttachments(NotificationEmail, []EmailAttachment{{Filename: testCase.filename, ContentType: testCase.contentType, Data: []byte("a,b")}})
+			if err != nil {
+				t.Fatalf("normalize: %v", err)
+			}
+			mediaType, _, parseErr := mime.ParseMediaType(normalized[0].ContentType)
+			if parseErr != nil || mediaType != testCase.expected {
+				t.Fatalf("expected %s, got %q", testCase.expected, normalized[0].ContentType)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// NotificationSnapshotVersion is the encoding version every NotificationSnapshot carries.
+// Renaming or removing a field, or changing how one is redacted or formatted, raises it;
+// adding an optional field does not.
+const NotificationSnapshotVersion = 1
+
+// SnapshotRedaction selects how much of a notification a snapshot reveals.
+type SnapshotRedaction int
+
+const (
+	// SnapshotRedacted replaces the recipient with its digest and drops the provider's
+	// error text, which can quote the recipient. It is the default.
+	SnapshotRedacted SnapshotRedaction = iota
+	// SnapshotFull keeps the recipient and the error text, for consumers already
+	// entitled to the notification itself.
+	SnapshotFull
+)
+
+// NotificationSnapshot is the canonical JSON view of a notification's state shared by
+// webhook payloads and audit entries, so each consumer encodes the same field names and
+// timestamps. Timestamps are UTC RFC 3339; unset ones are omitted. Subject, message and
+// attachments are never included.
+type NotificationSnapshot struct {
+	Version          int                `json:"version" description:"Encoding version of the snapshot; raised on incompatible changes."`
+	TenantID         string             `json:"tenant_id" description:"Tenant that owns the notification."`
+	NotificationID   string             `json:"notification_id" description:"Identifier of the notification."`
+	NotificationType NotificationType   `json:"notification_type" description:"Channel of the notification: email or sms."`
+	Status           NotificationStatus `json:"status" description:"Status of the notification when the snapshot was taken."`
+	Recipient        string             `json:"recipient,omitempty" description:"Recipient address or number; omitted unless the consumer receives full snapshots."`
+	RecipientDigest  string             `json:"recipient_digest" description:"Short case-insensitive SHA-256 digest of the recipient, for correlation without the address."`
+	RetryCount       int                `json:"retry_count" description:"Dispatch attempts that failed so far."`
+	LastError        string             `json:"last_error,omitempty" description:"Why retries stopped early; omitted unless the consumer receives full snapshots."`
+	Source           NotificationSource `json:"source,omitempty" description:"Intake that accepted the notification."`
+	GroupID          string             `json:"group_id,omitempty" description:"Group of a multi-channel send."`
+	CancelReason     string             `json:"cancel_reason,omitempty" description:"Why the notification was cancelled."`
+	CancelledBy      string             `json:"cancelled_by,omitempty" description:"Who cancelled the notification: the session email, grpc, or system."`
+	ScheduledFor     *time.Time         `json:"scheduled_for,omitempty" description:"Time the notification was scheduled for."`
+	ExpiresAt        *time.Time         `json:"expires_at,omitempty" description:"Time after which the notification is no longer sent."`
+	LastAttemptedAt  *time.Time         `json:"last_attempted_at,omitempty" description:"Time of the latest dispatch attempt."`
+	CancelledAt      *time.Time         `json:"cancelled_at,omitempty" description:"Time the notification was cancelled."`
+	CreatedAt        time.Time          `json:"created_at" description:"Time the notification was accepted."`
+	UpdatedAt        time.Time          `json:"updated_at" description:"Time the notification last changed."`
+}
+
+// NewNotificationSnapshot captures record's current state under redaction.
+func NewNotificationSnapshot(record Notification, redaction SnapshotRedaction) NotificationSnapshot {
+	snapshot := NotificationSnapshot{
+		Version:          NotificationSnapshotVersion,
+		TenantID:         record.TenantID,
+		NotificationID:   record.NotificationID,
+		NotificationType: record.NotificationType,
+		Status:           record.Status,
+		RecipientDigest:  DigestRecipient(record.Recipient),
+		RetryCount:       record.RetryCount,
+		Source:           record.Source,
+		GroupID:          record.GroupID,
+		CancelReason:     record.CancelReason,
+		CancelledBy:      record.CancelledBy,
+		ScheduledFor:     utcTimePointer(record.ScheduledFor),
+		ExpiresAt:        utcTimePointer(record.ExpiresAt),
+		CancelledAt:      utcTimePointer(record.CancelledAt),
+		CreatedAt:        record.CreatedAt.UTC(),
+		UpdatedAt:        record.UpdatedAt.UTC(),
+	}
+	if !record.LastAttemptedAt.IsZero() {
+		snapshot.LastAttemptedAt = utcTimePointer(&record.LastAttemptedAt)
+	}
+	if redaction == SnapshotFull {
+		snapshot.Recipient = record.Recipient
+		snapshot.LastError = record.LastError
+	}
+	return snapshot
+}
+
+// Encode returns the snapshot's canonical JSON encoding.
+func (snapshot NotificationSnapshot) Encode() ([]byte, error) {
+	encoded, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("encode notification snapshot: %w", err)
+	}
+	return encoded, nil
+}
+
+// DigestRecipient returns a short, case-insensitive digest so a recipient can be
+// correlated across snapshots and log lines without being written out.
+func DigestRecipient(recipient string) string {
+	trimmed := strings.TrimSpace(strings.ToLower(recipient))
+	if trimmed == "" {
+		return ""
+	}
+	digest := sha256.Sum256([]byte(trimmed))
+	return hex.EncodeToString(digest[:8])
+}
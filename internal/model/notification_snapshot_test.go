@@ -0,0 +1,90 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// snapshotGoldenFixture fills every field a snapshot reads, in a non-UTC zone, so the
+// golden files also pin timestamp normalization.
+func snapshotGoldenFixture() Notification {
+	zone := time.FixedZone("UTC+2", 2*60*60)
+	scheduledFor := time.Date(2026, 4, 2, 11, 0, 0, 0, zone)
+	expiresAt := time.Date(2026, 4, 3, 11, 0, 0, 0, zone)
+	cancelledAt := time.Date(2026, 4, 2, 12, 30, 15, 250_000_000, zone)
+	return Notification{
+		TenantID:          "tenant-snapshot",
+		NotificationID:    "notif-1",
+		NotificationType:  NotificationEmail,
+		Recipient:         "User@Example.com",
+		Subject:           "Quarterly statement",
+		Message:           "Private body",
+		ProviderMessageID: "provider-123",
+		Status:            StatusCancelled,
+		RetryCount:        2,
+		LastAttemptedAt:   time.Date(2026, 4, 2, 12, 0, 0, 0, zone),
+		ScheduledFor:      &scheduledFor,
+		ExpiresAt:         &expiresAt,
+		CancelReason:      "duplicate",
+		CancelledBy:       CancelActorGRPC,
+		CancelledAt:       &cancelledAt,
+		LastError:         "550 mailbox user@example.com unavailable",
+		Source:            NotificationSourceSystemAlert,
+		GroupID:           "group-1",
+		CreatedAt:         time.Date(2026, 4, 2, 10, 0, 0, 0, zone),
+		UpdatedAt:         time.Date(2026, 4, 2, 12, 30, 15, 250_000_000, zone),
+	}
+}
+
+// TestNotificationSnapshotMatchesGoldenEncoding locks the version 1 encoding. A failure
+// means a field, its name or its redaction changed: keep the old encoding, or raise
+// NotificationSnapshotVersion and add golden files for the new version.
+func TestNotificationSnapshotMatchesGoldenEncoding(t *testing.T) {
+	for _, testCase := range []struct {
+		golden    string
+		redaction SnapshotRedaction
+	}{
+		{golden: "notification_snapshot_v1_redacted.json", redaction: SnapshotRedacted},
+		{golden: "notification_snapshot_v1_full.json", redaction: SnapshotFull},
+	} {
+		encoded, err := NewNotificationSnapshot(snapshotGoldenFixture(), testCase.redaction).Encode()
+		if err != nil {
+			t.Fatalf("encode %s: %v", testCase.golden, err)
+		}
+		expected, err := os.ReadFile(filepath.Join("testdata", testCase.golden))
+		if err != nil {
+			t.Fatalf("read golden file: %v", err)
+		}
+		var compacted bytes.Buffer
+		if err := json.Compact(&compacted, expected); err != nil {
+			t.Fatalf("golden file %s is not JSON: %v", testCase.golden, err)
+		}
+		if !bytes.Equal(encoded, compacted.Bytes()) {
+			t.Fatalf("snapshot encoding changed for %s:\n got: %s\nwant: %s", testCase.golden, encoded, compacted.Bytes())
+		}
+	}
+}
+
+func TestNotificationSnapshotOmitsUnsetTimes(t *testing.T) {
+	record := Notification{TenantID: "tenant-snapshot", NotificationID: "notif-2", NotificationType: NotificationSMS, Recipient: "+15555550100", Status: StatusQueued}
+	encoded, err := NewNotificationSnapshot(record, SnapshotRedacted).Encode()
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	for _, omitted := range []string{"recipient", "last_attempted_at", "scheduled_for", "cancelled_at", "last_error"} {
+		if _, present := fields[omitted]; present {
+			t.Fatalf("expected %s to be omitted, got %s", omitted, encoded)
+		}
+	}
+	if fields["version"] != float64(NotificationSnapshotVersion) || fields["recipient_digest"] != DigestRecipient("+15555550100") {
+		t.Fatalf("unexpected snapshot %s", encoded)
+	}
+}
@@ -0,0 +1,236 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// NotificationTransferBatchSize is how many notifications one transfer transaction moves.
+	NotificationTransferBatchSize = 500
+	// maxTransferRenameAttempts bounds how often a colliding id is regenerated.
+	maxTransferRenameAttempts = 5
+)
+
+// ErrTransferRenameExhausted reports that no free notification id was found in the destination.
+var ErrTransferRenameExhausted = errors.New("no free notification id in the destination tenant")
+
+// NotificationIDRename records a notification that took a new id in the destination
+// tenant because its id was already used there.
+type NotificationIDRename struct {
+	SourceNotificationID      string
+	DestinationNotificationID string
+}
+
+// ListNotificationTransferBatch returns up to limit notifications of tenantID with a
+// primary key above afterID, in key order and without attachments. Rows that move
+// leave the tenant, so callers continue from the last key they saw.
+func ListNotificationTransferBatch(ctx context.Context, db *gorm.DB, tenantID string, filters NotificationListFilters, afterID uint, limit int) ([]Notification, error) {
+	var notifications []Notification
+	err := retryOnBusy(ctx, func() error {
+		query := db.WithContext(ctx).
+			Where(clause.And(
+				clause.Eq{Column: clause.Column{Name: notificationTenantIDColumn}, Value: tenantID},
+				clause.Gt{Column: clause.Column{Name: notificationIDColumn}, Value: afterID},
+			))
+		return applyNotificationListFilters(query, filters).
+			Order(clause.OrderByColumn{Column: clause.Column{Name: notificationIDColumn}}).
+			Limit(limit).
+			Find(&notifications).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list_notification_transfer_batch: %w", err)
+	}
+	return notifications, nil
+}
+
+// MoveNotifications reassigns the notifications from sourceTenantID to
+// destinationTenantID in one transaction, together with their attachment rows, the
+// blob references those rows hold and their rendered content. A notification whose id
+// already exists in the destination takes an id from newID; the renames are returned.
+// Webhook deliveries and queue intake keys stay with the source tenant.
+func MoveNotifications(ctx context.Context, db *gorm.DB, sourceTenantID string, destinationTenantID string, notifications []Notification, newID func() (string, error)) ([]NotificationIDRename, error) {
+	if len(notifications) == 0 {
+		return nil, nil
+	}
+	var renames []NotificationIDRename
+	err := retryOnBusy(ctx, func() error {
+		renames = nil
+		return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			destinationIDs, err := destinationNotificationIDs(tx, destinationTenantID, notifications, newID)
+			if err != nil {
+				return err
+			}
+			if err := moveAttachmentBlobs(tx, sourceTenantID, destinationTenantID, notifications); err != nil {
+				return err
+			}
+			now := time.Now().UTC()
+			for _, notification := range notifications {
+				destinationID := destinationIDs[notification.NotificationID]
+				if destinationID != notification.NotificationID {
+					renames = append(renames, NotificationIDRename{SourceNotificationID: notification.NotificationID, DestinationNotificationID: destinationID})
+				}
+				if err := moveNotificationRows(tx, sourceTenantID, destinationTenantID, notification, destinationID, now); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("move_notifications: %w", err)
+	}
+	return renames, nil
+}
+
+// destinationNotificationIDs maps each notification id to the id it gets in the
+// destination: itself, or a fresh id when the destination already uses it.
+func destinationNotificationIDs(tx *gorm.DB, destinationTenantID string, notifications []Notification, newID func() (string, error)) (map[string]string, error) {
+	sourceIDs := make([]interface{}, 0, len(notifications))
+	for _, notification := range notifications {
+		sourceIDs = append(sourceIDs, notification.NotificationID)
+	}
+	taken, err := takenNotificationIDs(tx, destinationTenantID, sourceIDs)
+	if err != nil {
+		return nil, err
+	}
+	// reserved holds the ids this batch will use, so a fresh id cannot repeat one of them.
+	reserved := make(map[string]struct{}, len(notifications))
+	for _, notification := range notifications {
+		reserved[notification.NotificationID] = struct{}{}
+	}
+	destinationIDs := make(map[string]string, len(notifications))
+	for _, notification := range notifications {
+		destinationIDs[notification.NotificationID] = notification.NotificationID
+		if _, collides := taken[notification.NotificationID]; !collides {
+			continue
+		}
+		renamed, renameErr := freeNotificationID(tx, destinationTenantID, reserved, newID)
+		if renameErr != nil {
+			return nil, renameErr
+		}
+		reserved[renamed] = struct{}{}
+		destinationIDs[notification.NotificationID] = renamed
+	}
+	return destinationIDs, nil
+}
+
+func freeNotificationID(tx *gorm.DB, tenantID string, reserved map[string]struct{}, newID func() (string, error)) (string, error) {
+	for range maxTransferRenameAttempts {
+		candidate, err := newID()
+		if err != nil {
+			return "", err
+		}
+		if _, used := reserved[candidate]; used {
+			continue
+		}
+		taken, err := takenNotificationIDs(tx, tenantID, []interface{}{candidate})
+		if err != nil {
+			return "", err
+		}
+		if len(taken) == 0 {
+			return candidate, nil
+		}
+	}
+	return "", ErrTransferRenameExhausted
+}
+
+func takenNotificationIDs(tx *gorm.DB, tenantID string, notificationIDs []interface{}) (map[string]struct{}, error) {
+	var existing []string
+	if err := tx.Model(&Notification{}).
+		Where(clause.And(
+			clause.Eq{Column: clause.Column{Name: notificationTenantIDColumn}, Value: tenantID},
+			clause.IN{Column: clause.Column{Name: notificationNotificationIDColumn}, Values: notificationIDs},
+		)).
+		Pluck(notificationNotificationIDColumn, &existing).Error; err != nil {
+		return nil, err
+	}
+	taken := make(map[string]struct{}, len(existing))
+	for _, notificationID := range existing {
+		taken[notificationID] = struct{}{}
+	}
+	return taken, nil
+}
+
+// moveAttachmentBlobs takes references on destination blobs for every deduplicated
+// attachment of the notifications and releases the source references, copying the
+// bytes when the destination has no blob for that hash yet.
+func moveAttachmentBlobs(tx *gorm.DB, sourceTenantID string, destinationTenantID string, notifications []Notification) error {
+	notificationIDs := make([]interface{}, 0, len(notifications))
+	for _, notification := range notifications {
+		notificationIDs = append(notificationIDs, notification.NotificationID)
+	}
+	var refs []attachmentBlobRef
+	if err := tx.Model(&NotificationAttachment{}).
+		Where(clause.And(
+			clause.Eq{Column: clause.Column{Name: notificationTenantIDColumn}, Value: sourceTenantID},
+			clause.IN{Column: clause.Column{Name: notificationNotificationIDColumn}, Values: notificationIDs},
+			clause.Neq{Column: clause.Column{Name: attachmentBlobContentHashColumn}, Value: ""},
+		)).
+		Find(&refs).Error; err != nil {
+		return err
+	}
+	counts := make(map[string]int)
+	for _, ref := range refs {
+		counts[ref.ContentHash]++
+	}
+	for contentHash, count := range counts {
+		var blob AttachmentBlob
+		if err := tx.Where(&AttachmentBlob{TenantID: sourceTenantID, ContentHash: contentHash}).Take(&blob).Error; err != nil {
+			return fmt.Errorf("load attachment blob: %w", err)
+		}
+		copied := AttachmentBlob{TenantID: destinationTenantID, ContentHash: contentHash, Data: blob.Data, SizeBytes: blob.SizeBytes}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&copied).Error; err != nil {
+			return err
+		}
+		if err := adjustAttachmentBlobRefCount(tx, attachmentBlobKey{tenantID: destinationTenantID, contentHash: contentHash}, count); err != nil {
+			return err
+		}
+		if err := adjustAttachmentBlobRefCount(tx, attachmentBlobKey{tenantID: sourceTenantID, contentHash: contentHash}, -count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func moveNotificationRows(tx *gorm.DB, sourceTenantID string, destinationTenantID string, notification Notification, destinationID string, now time.Time) error {
+	reassigned := map[string]interface{}{
+		notificationTenantIDColumn:       destinationTenantID,
+		notificationNotificationIDColumn: destinationID,
+	}
+	sourceRows := clause.And(
+		clause.Eq{Column: clause.Column{Name: notificationTenantIDColumn}, Value: sourceTenantID},
+		clause.Eq{Column: clause.Column{Name: notificationNotificationIDColumn}, Value: notification.NotificationID},
+	)
+	if err := tx.Model(&NotificationAttachment{}).Where(sourceRows).Updates(reassigned).Error; err != nil {
+		return fmt.Errorf("move attachments: %w", err)
+	}
+	if err := tx.Model(&RenderedContent{}).Where(sourceRows).Updates(reassigned).Error; err != nil {
+		return fmt.Errorf("move rendered content: %w", err)
+	}
+	if err := tx.Model(&NotificationOpen{}).Where(sourceRows).Updates(reassigned).Error; err != nil {
+		return fmt.Errorf("move opens: %w", err)
+	}
+	result := tx.Model(&Notification{}).
+		Where(clause.And(
+			clause.Eq{Column: clause.Column{Name: notificationIDColumn}, Value: notification.ID},
+			clause.Eq{Column: clause.Column{Name: notificationTenantIDColumn}, Value: sourceTenantID},
+		)).
+		Updates(map[string]interface{}{
+			notificationTenantIDColumn:       destinationTenantID,
+			notificationNotificationIDColumn: destinationID,
+			notificationUpdatedAtColumn:      now,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("move notification: %w", result.Error)
+	}
+	if result.RowsAffected != 1 {
+		return fmt.Errorf("move notification %s: %w", notification.NotificationID, ErrNotificationNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+func TestMoveNotificationsRenamesCollisionsAndMovesAttachments(t *testing.T) {
+	db := openModelTestDatabase(t)
+	if err := db.AutoMigrate(&RenderedContent{}, &NotificationOpen{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	// Databases created before the unique index may hold one id under several tenants.
+	if err := db.Migrator().DropIndex(&Notification{}, "idx_tenant_notification"); err != nil {
+		t.Fatalf("drop unique index: %v", err)
+	}
+	ctx := context.Background()
+	statement := []byte("%PDF-1.7 statement")
+	createdAt := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	create := func(tenantID string, notificationID string, status NotificationStatus, attachments []EmailAttachment) Notification {
+		t.Helper()
+		notification := Notification{
+			TenantID:         tenantID,
+			NotificationID:   notificationID,
+			NotificationType: NotificationEmail,
+			Recipient:        notificationID + "@example.com",
+			Message:          "Body",
+			Status:           status,
+			CreatedAt:        createdAt,
+			Attachments:      convertEmailAttachments(tenantID, notificationID, attachments),
+		}
+		createdAt = createdAt.Add(time.Minute)
+		if err := CreateNotification(ctx, db, &notification); err != nil {
+			t.Fatalf("create %s/%s: %v", tenantID, notificationID, err)
+		}
+		return notification
+	}
+	create("tenant-old", "shared", StatusSent, []EmailAttachment{{Filename: "statement.pdf", ContentType: "application/pdf", Data: statement}})
+	create("tenant-old", "only-old", StatusSent, []EmailAttachment{{Filename: "statement.pdf", ContentType: "application/pdf", Data: statement}})
+	create("tenant-old", "stays", StatusSent, []EmailAttachment{{Filename: "statement.pdf", ContentType: "application/pdf", Data: statement}})
+	create("tenant-new", "shared", StatusSent, nil)
+	rendered := RenderedContent{TenantID: "tenant-old", NotificationID: "shared", SameAsInput: true, RenderedAt: createdAt}
+	if err := SaveRenderedContent(ctx, db, &rendered); err != nil {
+		t.Fatalf("save rendered content: %v", err)
+	}
+
+	batch, err := ListNotificationTransferBatch(ctx, db, "tenant-old", NotificationListFilters{}, 0, 2)
+	if err != nil || len(batch) != 2 || batch[0].NotificationID != "shared" || batch[1].NotificationID != "only-old" {
+		t.Fatalf("expected the first two notifications in key order, got %+v err=%v", batch, err)
+	}
+	generated := 0
+	renames, err := MoveNotifications(ctx, db, "tenant-old", "tenant-new", batch, func() (string, error) {
+		generated++
+		if generated == 1 {
+			return "shared", nil
+		}
+		return fmt.Sprintf("renamed-%d", generated), nil
+	})
+	if err != nil {
+		t.Fatalf("move notifications: %v", err)
+	}
+	if len(renames) != 1 || renames[0] != (NotificationIDRename{SourceNotificationID: "shared", DestinationNotificationID: "renamed-2"}) {
+		t.Fatalf("expected the colliding id to be regenerated past a taken candidate, got %+v", renames)
+	}
+
+	moved, err := GetNotificationByID(ctx, db, "tenant-new", "renamed-2")
+	if err != nil || len(moved.Attachments) != 1 || string(moved.Attachments[0].Data) != string(statement) {
+		t.Fatalf("expected the renamed notification with its attachment bytes, got %+v err=%v", moved, err)
+	}
+	if existing, err := GetNotificationByID(ctx, db, "tenant-new", "shared"); err != nil || len(existing.Attachments) != 0 {
+		t.Fatalf("expected the destination's own notification untouched, got %+v err=%v", existing, err)
+	}
+	if _, found, err := GetRenderedContent(ctx, db, "tenant-new", "renamed-2"); err != nil || !found {
+		t.Fatalf("expected rendered content to follow the renamed notification, found=%v err=%v", found, err)
+	}
+	for _, notificationID := range []string{"shared", "only-old"} {
+		if _, err := GetNotificationByID(ctx, db, "tenant-old", notificationID); err == nil {
+			t.Fatalf("expected %s to have left the source tenant", notificationID)
+		}
+	}
+	sourceNotifications, err := ListNotifications(ctx, db, "tenant-old", NotificationListFilters{})
+	if err != nil || len(sourceNotifications) != 1 || sourceNotifications[0].NotificationID != "stays" || string(sourceNotifications[0].Attachments[0].Data) != string(statement) {
+		t.Fatalf("expected only the unmoved notification in the source, got %+v err=%v", sourceNotifications, err)
+	}
+	destinationNotifications, err := ListNotifications(ctx, db, "tenant-new", NotificationListFilters{})
+	if err != nil || len(destinationNotifications) != 3 {
+		t.Fatalf("expected three notifications in the destination, got %d err=%v", len(destinationNotifications), err)
+	}
+
+	var blobs []AttachmentBlob
+	if err := db.Order(clause.OrderByColumn{Column: clause.Column{Name: notificationTenantIDColumn}}).Find(&blobs).Error; err != nil || len(blobs) != 2 {
+		t.Fatalf("expected one blob per tenant, got %+v err=%v", blobs, err)
+	}
+	if blobs[0].TenantID != "tenant-new" || blobs[0].RefCount != 2 || blobs[1].TenantID != "tenant-old" || blobs[1].RefCount != 1 {
+		t.Fatalf("expected blob references to follow the attachments, got %+v", blobs)
+	}
+}
+
+func TestMoveNotificationsFailsWhenNoFreeIDIsFound(t *testing.T) {
+	db := openModelTestDatabase(t)
+	if err := db.AutoMigrate(&RenderedContent{}, &NotificationOpen{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := db.Migrator().DropIndex(&Notification{}, "idx_tenant_notification"); err != nil {
+		t.Fatalf("drop unique index: %v", err)
+	}
+	ctx := context.Background()
+	for _, tenantID := range []string{"tenant-old", "tenant-new"} {
+		notification := Notification{TenantID: tenantID, NotificationID: "shared", NotificationType: NotificationSMS, Recipient: "+15555550100", Message: "Body", Status: StatusSent}
+		if err := CreateNotification(ctx, db, &notification); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+	batch, err := ListNotificationTransferBatch(ctx, db, "tenant-old", NotificationListFilters{Statuses: []NotificationStatus{StatusSent}}, 0, NotificationTransferBatchSize)
+	if err != nil || len(batch) != 1 {
+		t.Fatalf("list batch: %+v err=%v", batch, err)
+	}
+	_, err = MoveNotifications(ctx, db, "tenant-old", "tenant-new", batch, func() (string, error) { return "shared", nil })
+	if !errors.Is(err, ErrTransferRenameExhausted) {
+		t.Fatalf("expected rename exhaustion, got %v", err)
+	}
+	if _, err := GetNotificationByID(ctx, db, "tenant-old", "shared"); err != nil {
+		t.Fatalf("expected the failed batch to roll back, got %v", err)
+	}
+	if renames, err := MoveNotifications(ctx, db, "tenant-old", "tenant-new", nil, nil); err != nil || renames != nil {
+		t.Fatalf("expected an empty batch to do nothing, got %+v err=%v", renames, err)
+	}
+}
@@ -0,0 +1,18 @@
+package model
+
+// PendingReason explains why a notification the retry worker will still send has not
+// gone out yet. Notifications in a terminal state have none.
+type PendingReason string
+
+const (
+	// PendingReasonScheduled waits for the notification's scheduled time.
+	PendingReasonScheduled PendingReason = "scheduled"
+	// PendingReasonCircuitOpen waits for the tenant's provider breaker for the channel to close.
+	PendingReasonCircuitOpen PendingReason = "circuit_open"
+	// PendingReasonAwaitingRetry waits out the backoff after a failed attempt.
+	PendingReasonAwaitingRetry PendingReason = "awaiting_retry"
+	// PendingReasonRateLimited waits while dispatch pacing slows the tenant's provider.
+	PendingReasonRateLimited PendingReason = "rate_limited"
+	// PendingReasonDue is ready and waits for the next retry worker pass.
+	PendingReasonDue PendingReason = "due"
+)
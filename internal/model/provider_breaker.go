@@ -0,0 +1,103 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	providerBreakerTenantIDColumn = "tenant_id"
+	providerBreakerChannelColumn  = "channel"
+)
+
+// ProviderBreaker is an open circuit breaker: dispatch for the tenant's channel is paused
+// because its provider rejected the tenant's account. A row exists only while the breaker
+// is open; closing it deletes the row.
+type ProviderBreaker struct {
+	TenantID string           `gorm:"primaryKey"`
+	Channel  NotificationType `gorm:"primaryKey"`
+	// Reason is the provider error that opened the breaker.
+	Reason   string    `gorm:"not null"`
+	OpenedAt time.Time `gorm:"not null"`
+	// LastProbeAt is when the latest canary dispatch was admitted; nil until the first one.
+	LastProbeAt      *time.Time
+	AdminsNotifiedAt *time.Time
+	UpdatedAt        time.Time
+}
+
+// NextProbeAt is when the breaker next admits a canary dispatch.
+func (breaker ProviderBreaker) NextProbeAt(interval time.Duration) time.Time {
+	if breaker.LastProbeAt == nil {
+		return breaker.OpenedAt.Add(interval)
+	}
+	return breaker.LastProbeAt.Add(interval)
+}
+
+// OpenProviderBreaker stores breaker unless one is already open for its tenant and
+// channel; opened is false in that case, so only the first opener acts on it.
+func OpenProviderBreaker(ctx context.Context, db *gorm.DB, breaker *ProviderBreaker) (bool, error) {
+	var opened bool
+	err := retryOnBusy(ctx, func() error {
+		result := db.WithContext(ctx).
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(breaker)
+		opened = result.RowsAffected > 0
+		return result.Error
+	})
+	if err != nil {
+		return false, fmt.Errorf("open_provider_breaker: %w", err)
+	}
+	return opened, nil
+}
+
+// SaveProviderBreaker updates an open breaker's probe and notification timestamps.
+func SaveProviderBreaker(ctx context.Context, db *gorm.DB, breaker *ProviderBreaker) error {
+	err := retryOnBusy(ctx, func() error {
+		return db.WithContext(ctx).
+			Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: providerBreakerTenantIDColumn}, {Name: providerBreakerChannelColumn}},
+				UpdateAll: true,
+			}).
+			Create(breaker).Error
+	})
+	if err != nil {
+		return fmt.Errorf("save_provider_breaker: %w", err)
+	}
+	return nil
+}
+
+// CloseProviderBreaker deletes the tenant's breaker for channel; closing a breaker that
+// is not open is a no-op.
+func CloseProviderBreaker(ctx context.Context, db *gorm.DB, tenantID string, channel NotificationType) error {
+	if tenantID == "" || channel == "" {
+		return errors.New("close_provider_breaker: tenant and channel are required")
+	}
+	err := retryOnBusy(ctx, func() error {
+		return db.WithContext(ctx).
+			Where(&ProviderBreaker{TenantID: tenantID, Channel: channel}).
+			Delete(&ProviderBreaker{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("close_provider_breaker: %w", err)
+	}
+	return nil
+}
+
+// ListProviderBreakers returns every open breaker.
+func ListProviderBreakers(ctx context.Context, db *gorm.DB) ([]ProviderBreaker, error) {
+	var breakers []ProviderBreaker
+	err := retryOnBusy(ctx, func() error {
+		return db.WithContext(ctx).
+			Order(clause.OrderByColumn{Column: clause.Column{Name: providerBreakerTenantIDColumn}}).
+			Find(&breakers).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list_provider_breakers: %w", err)
+	}
+	return breakers, nil
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestProviderBreakerOpensOnceAndCloses(t *testing.T) {
+	db := openModelTestDatabase(t)
+	if err := db.AutoMigrate(&ProviderBreaker{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	ctx := context.Background()
+	openedAt := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	opened, err := OpenProviderBreaker(ctx, db, &ProviderBreaker{TenantID: "tenant-a", Channel: NotificationSMS, Reason: "account suspended", OpenedAt: openedAt})
+	if err != nil || !opened {
+		t.Fatalf("expected the first open to store the breaker, opened=%v err=%v", opened, err)
+	}
+	opened, err = OpenProviderBreaker(ctx, db, &ProviderBreaker{TenantID: "tenant-a", Channel: NotificationSMS, Reason: "again", OpenedAt: openedAt.Add(time.Minute)})
+	if err != nil || opened {
+		t.Fatalf("expected an open breaker not to be reopened, opened=%v err=%v", opened, err)
+	}
+
+	probeAt := openedAt.Add(5 * time.Minute)
+	if err := SaveProviderBreaker(ctx, db, &ProviderBreaker{TenantID: "tenant-a", Channel: NotificationSMS, Reason: "account suspended", OpenedAt: openedAt, LastProbeAt: &probeAt}); err != nil {
+		t.Fatalf("save probe: %v", err)
+	}
+	breakers, err := ListProviderBreakers(ctx, db)
+	if err != nil || len(breakers) != 1 {
+		t.Fatalf("expected one open breaker, got %+v err=%v", breakers, err)
+	}
+	if breakers[0].Reason != "account suspended" || !breakers[0].NextProbeAt(time.Minute).Equal(probeAt.Add(time.Minute)) {
+		t.Fatalf("unexpected stored breaker %+v", breakers[0])
+	}
+
+	if err := CloseProviderBreaker(ctx, db, "tenant-a", NotificationEmail); err != nil {
+		t.Fatalf("close other channel: %v", err)
+	}
+	if err := CloseProviderBreaker(ctx, db, "tenant-a", NotificationSMS); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if breakers, err := ListProviderBreakers(ctx, db); err != nil || len(breakers) != 0 {
+		t.Fatalf("expected no open breakers after closing, got %+v err=%v", breakers, err)
+	}
+}
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrQueryTimeout reports a statement stopped by its query deadline or the caller's;
+// callers should map it to a deadline error rather than an internal failure.
+var ErrQueryTimeout = errors.New("notification.storage.timeout")
+
+// QueryClass selects which bound RegisterQueryTimeouts puts on a statement.
+type QueryClass int
+
+const (
+	// QueryClassPoint covers single-row reads and writes; it is the default.
+	QueryClassPoint QueryClass = iota
+	// QueryClassAggregate covers exports, statistics and worker scans over many rows.
+	QueryClassAggregate
+)
+
+// QueryTimeouts bounds each statement by its class; zero leaves that class bounded only
+// by the caller's own deadline.
+type QueryTimeouts struct {
+	Point     time.Duration
+	Aggregate time.Duration
+}
+
+func (timeouts QueryTimeouts) forClass(class QueryClass) time.Duration {
+	if class == QueryClassAggregate {
+		return timeouts.Aggregate
+	}
+	return timeouts.Point
+}
+
+type queryClassContextKey struct{}
+
+const (
+	queryTimeoutStartCallback  = "pinguin:query_timeout_start"
+	queryTimeoutFinishCallback = "pinguin:query_timeout_finish"
+	queryTimeoutStateKey       = "pinguin:query_timeout_state"
+)
+
+// queryTimeoutState carries a statement's bounded context from the start callback to
+// the finish callback.
+type queryTimeoutState struct {
+	parent context.Context
+	cancel context.CancelFunc
+}
+
+// WithQueryClass marks the statements run with ctx as class.
+func WithQueryClass(ctx context.Context, class QueryClass) context.Context {
+	return context.WithValue(ctx, queryClassContextKey{}, class)
+}
+
+func aggregateQueries(ctx context.Context) context.Context {
+	return WithQueryClass(ctx, QueryClassAggregate)
+}
+
+func queryClassFromContext(ctx context.Context) QueryClass {
+	class, _ := ctx.Value(queryClassContextKey{}).(QueryClass)
+	return class
+}
+
+// RegisterQueryTimeouts bounds every create, query, update and delete statement of db by
+// the timeout of its class, and wraps a statement error caused by a passed deadline in
+// ErrQueryTimeout. Row and Rows are left alone because their callers read the result after
+// the callbacks return; the repository never issues raw statements.
+//
+// The SQLite driver interrupts writes and other executed statements once the deadline
+// passes, but checks a read's deadline only before stepping its rows, so a slow read
+// still runs to the end before it reports ErrQueryTimeout.
+func RegisterQueryTimeouts(db *gorm.DB, timeouts QueryTimeouts) error {
+	start := func(tx *gorm.DB) { startQueryTimeout(tx, timeouts) }
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().Before("*").Register(queryTimeoutStartCallback, start),
+		callbacks.Create().After("*").Register(queryTimeoutFinishCallback, finishQueryTimeout),
+		callbacks.Query().Before("*").Register(queryTimeoutStartCallback, start),
+		callbacks.Query().After("*").Register(queryTimeoutFinishCallback, finishQueryTimeout),
+		callbacks.Update().Before("*").Register(queryTimeoutStartCallback, start),
+		callbacks.Update().After("*").Register(queryTimeoutFinishCallback, finishQueryTimeout),
+		callbacks.Delete().Before("*").Register(queryTimeoutStartCallback, start),
+		callbacks.Delete().After("*").Register(queryTimeoutFinishCallback, finishQueryTimeout),
+	} {
+		if err != nil {
+			return fmt.Errorf("register query timeouts: %w", err)
+		}
+	}
+	return nil
+}
+
+func startQueryTimeout(tx *gorm.DB, timeouts QueryTimeouts) {
+	parent := tx.Statement.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+	state := &queryTimeoutState{parent: parent, cancel: func() {}}
+	if timeout := timeouts.forClass(queryClassFromContext(parent)); timeout > 0 {
+		tx.Statement.Context, state.cancel = context.WithTimeout(parent, timeout)
+	}
+	tx.InstanceSet(queryTimeoutStateKey, state)
+}
+
+func finishQueryTimeout(tx *gorm.DB) {
+	value, found := tx.InstanceGet(queryTimeoutStateKey)
+	if !found {
+		return
+	}
+	state := value.(*queryTimeoutState)
+	if tx.Error != nil && errors.Is(tx.Statement.Context.Err(), context.DeadlineExceeded) && !errors.Is(tx.Error, ErrQueryTimeout) {
+		tx.Error = fmt.Errorf("%w: %w", ErrQueryTimeout, tx.Error)
+	}
+	state.cancel()
+	tx.Statement.Context = state.parent
+}
@@ -0,0 +1,101 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func seedQueryTimeoutNotifications(t *testing.T, database *gorm.DB, count int) {
+	t.Helper()
+	records := make([]Notification, 0, count)
+	for index := 0; index < count; index++ {
+		records = append(records, Notification{
+			TenantID:         modelTestTenantID,
+			NotificationID:   fmt.Sprintf("notif-%04d", index),
+			NotificationType: NotificationEmail,
+			Recipient:        fmt.Sprintf("user-%04d@example.com", index),
+			Message:          "Body",
+			Status:           StatusSent,
+		})
+	}
+	if err := database.CreateInBatches(records, 100).Error; err != nil {
+		t.Fatalf("seed notifications: %v", err)
+	}
+}
+
+// slowRecipientUpdate rewrites one recipient chosen by sorting a three-way cross join of
+// the notifications table, which takes far longer than the test timeouts to evaluate.
+func slowRecipientUpdate(ctx context.Context, database *gorm.DB) error {
+	crossJoin := database.Session(&gorm.Session{NewDB: true}).Model(&Notification{}).Clauses(
+		clause.Select{Columns: []clause.Column{{Table: "c", Name: "recipient"}}},
+		clause.From{Tables: []clause.Table{
+			{Name: "notifications", Alias: "a"},
+			{Name: "notifications", Alias: "b"},
+			{Name: "notifications", Alias: "c"},
+		}},
+		clause.OrderBy{Columns: []clause.OrderByColumn{
+			{Column: clause.Column{Table: "a", Name: "recipient"}, Desc: true},
+			{Column: clause.Column{Table: "b", Name: "recipient"}},
+			{Column: clause.Column{Table: "c", Name: "recipient"}},
+		}},
+		clause.Limit{Limit: ptrInt(1)},
+	)
+	return database.WithContext(ctx).Model(&Notification{}).
+		Clauses(clause.Where{Exprs: []clause.Expression{clause.Eq{Column: clause.Column{Name: "recipient"}, Value: []interface{}{crossJoin}}}}).
+		Update("recipient", "chosen@example.com").Error
+}
+
+func ptrInt(value int) *int {
+	return &value
+}
+
+func TestRegisterQueryTimeoutsStopsSlowWrites(t *testing.T) {
+	database := openModelTestDatabase(t)
+	seedQueryTimeoutNotifications(t, database, 300)
+	if err := RegisterQueryTimeouts(database, QueryTimeouts{Point: 50 * time.Millisecond}); err != nil {
+		t.Fatalf("register query timeouts: %v", err)
+	}
+
+	startedAt := time.Now()
+	err := slowRecipientUpdate(context.Background(), database)
+	if !errors.Is(err, ErrQueryTimeout) {
+		t.Fatalf("expected ErrQueryTimeout, got %v", err)
+	}
+	if elapsed := time.Since(startedAt); elapsed > 5*time.Second {
+		t.Fatalf("expected the statement to stop near its deadline, took %s", elapsed)
+	}
+
+	// Point reads and aggregate reads without a bound are unaffected.
+	ctx := context.Background()
+	if _, err := GetNotificationByID(ctx, database, modelTestTenantID, "notif-0001"); err != nil {
+		t.Fatalf("point read after timeout: %v", err)
+	}
+	listed, err := ListNotifications(ctx, database, modelTestTenantID, NotificationListFilters{})
+	if err != nil || len(listed) != 300 {
+		t.Fatalf("expected the aggregate read to list every notification, got %d err=%v", len(listed), err)
+	}
+}
+
+func TestRegisterQueryTimeoutsUsesClassFromContext(t *testing.T) {
+	database := openModelTestDatabase(t)
+	seedQueryTimeoutNotifications(t, database, 300)
+	if err := RegisterQueryTimeouts(database, QueryTimeouts{Point: time.Minute, Aggregate: 50 * time.Millisecond}); err != nil {
+		t.Fatalf("register query timeouts: %v", err)
+	}
+
+	err := slowRecipientUpdate(WithQueryClass(context.Background(), QueryClassAggregate), database)
+	if !errors.Is(err, ErrQueryTimeout) {
+		t.Fatalf("expected the aggregate bound to stop the statement, got %v", err)
+	}
+	if err := database.WithContext(context.Background()).Model(&Notification{}).
+		Clauses(clause.Where{Exprs: []clause.Expression{clause.Eq{Column: clause.Column{Name: "notification_id"}, Value: "notif-0002"}}}).
+		Update("recipient", "point@example.com").Error; err != nil {
+		t.Fatalf("point write: %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	queueIntakeTenantIDColumn       = "tenant_id"
+	queueIntakeIdempotencyKeyColumn = "idempotency_key"
+	queueIntakeNotificationIDColumn = "notification_id"
+)
+
+// QueueIntakeKey records that a queued send request with this idempotency key was taken
+// on. NotificationID stays empty while the send is in progress and holds the created
+// notification once it was stored.
+type QueueIntakeKey struct {
+	TenantID       string `gorm:"primaryKey"`
+	IdempotencyKey string `gorm:"primaryKey"`
+	NotificationID string
+	CreatedAt      time.Time
+}
+
+// ClaimQueueIntakeKey inserts an unconfirmed key. When the key already exists, claimed is
+// false and existing holds the stored row.
+func ClaimQueueIntakeKey(ctx context.Context, db *gorm.DB, tenantID string, idempotencyKey string, currentTime time.Time) (claimed bool, existing QueueIntakeKey, err error) {
+	err = retryOnBusy(ctx, func() error {
+		result := db.WithContext(ctx).
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&QueueIntakeKey{TenantID: tenantID, IdempotencyKey: idempotencyKey, CreatedAt: currentTime.UTC()})
+		if result.Error != nil {
+			return result.Error
+		}
+		claimed = result.RowsAffected == 1
+		if claimed {
+			return nil
+		}
+		return db.WithContext(ctx).
+			Where(&QueueIntakeKey{TenantID: tenantID, IdempotencyKey: idempotencyKey}).
+			Take(&existing).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, QueueIntakeKey{}, fmt.Errorf("claim_queue_intake_key: key released concurrently: %w", err)
+	}
+	if err != nil {
+		return false, QueueIntakeKey{}, fmt.Errorf("claim_queue_intake_key: %w", err)
+	}
+	return claimed, existing, nil
+}
+
+// ConfirmQueueIntakeKey stores the notification a claimed key created.
+func ConfirmQueueIntakeKey(ctx context.Context, db *gorm.DB, tenantID string, idempotencyKey string, notificationID string) error {
+	err := retryOnBusy(ctx, func() error {
+		return db.WithContext(ctx).
+			Model(&QueueIntakeKey{}).
+			Where(queueIntakeKeyClause(tenantID, idempotencyKey)).
+			Update(queueIntakeNotificationIDColumn, notificationID).Error
+	})
+	if err != nil {
+		return fmt.Errorf("confirm_queue_intake_key: %w", err)
+	}
+	return nil
+}
+
+// ReleaseQueueIntakeKey deletes an unconfirmed key so a later delivery may claim it again.
+func ReleaseQueueIntakeKey(ctx context.Context, db *gorm.DB, tenantID string, idempotencyKey string) error {
+	err := retryOnBusy(ctx, func() error {
+		return db.WithContext(ctx).
+			Where(clause.And(
+				queueIntakeKeyClause(tenantID, idempotencyKey),
+				clause.Eq{Column: clause.Column{Name: queueIntakeNotificationIDColumn}, Value: ""},
+			)).
+			Delete(&QueueIntakeKey{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("release_queue_intake_key: %w", err)
+	}
+	return nil
+}
+
+func queueIntakeKeyClause(tenantID string, idempotencyKey string) clause.Expression {
+	return clause.And(
+		clause.Eq{Column: clause.Column{Name: queueIntakeTenantIDColumn}, Value: tenantID},
+		clause.Eq{Column: clause.Column{Name: queueIntakeIdempotencyKeyColumn}, Value: idempotencyKey},
+	)
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestQueueIntakeKeyClaimConfirmRelease(t *testing.T) {
+	db := openModelTestDatabase(t)
+	if err := db.AutoMigrate(&QueueIntakeKey{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	ctx := context.Background()
+	claimedAt := time.Date(2026, 7, 1, 9, 0, 0, 0, time.UTC)
+
+	claimed, _, err := ClaimQueueIntakeKey(ctx, db, "tenant-a", "order-1", claimedAt)
+	if err != nil || !claimed {
+		t.Fatalf("expected the first claim to succeed, claimed=%v err=%v", claimed, err)
+	}
+	claimed, existing, err := ClaimQueueIntakeKey(ctx, db, "tenant-a", "order-1", claimedAt)
+	if err != nil || claimed || existing.NotificationID != "" {
+		t.Fatalf("expected an unconfirmed existing key, claimed=%v existing=%+v err=%v", claimed, existing, err)
+	}
+	claimed, _, err = ClaimQueueIntakeKey(ctx, db, "tenant-b", "order-1", claimedAt)
+	if err != nil || !claimed {
+		t.Fatalf("expected keys to be scoped per tenant, claimed=%v err=%v", claimed, err)
+	}
+
+	if err := ReleaseQueueIntakeKey(ctx, db, "tenant-a", "order-1"); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	claimed, _, err = ClaimQueueIntakeKey(ctx, db, "tenant-a", "order-1", claimedAt)
+	if err != nil || !claimed {
+		t.Fatalf("expected a released key to be claimable again, claimed=%v err=%v", claimed, err)
+	}
+
+	if err := ConfirmQueueIntakeKey(ctx, db, "tenant-a", "order-1", "notif-1"); err != nil {
+		t.Fatalf("confirm: %v", err)
+	}
+	if err := ReleaseQueueIntakeKey(ctx, db, "tenant-a", "order-1"); err != nil {
+		t.Fatalf("release confirmed: %v", err)
+	}
+	claimed, existing, err = ClaimQueueIntakeKey(ctx, db, "tenant-a", "order-1", claimedAt)
+	if err != nil || claimed || existing.NotificationID != "notif-1" {
+		t.Fatalf("expected the confirmed key to survive release, claimed=%v existing=%+v err=%v", claimed, existing, err)
+	}
+}
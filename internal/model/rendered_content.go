@@ -0,0 +1,108 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MIME structures recorded for dispatched email.
+const (
+	// MIMEStructurePlain is a single text/plain body.
+	MIMEStructurePlain = "plain"
+	// MIMEStructureMixed is a multipart/mixed body followed by attachments.
+	MIMEStructureMixed = "mixed"
+	// MIMEStructureAlternative is a multipart/mixed message whose body is a
+	// multipart/alternative pairing the text with a calendar invite.
+	MIMEStructureAlternative = "alternative"
+)
+
+const (
+	renderedContentTenantIDColumn       = "tenant_id"
+	renderedContentNotificationIDColumn = "notification_id"
+)
+
+// RenderedContent records what a notification looked like when it was last dispatched.
+// The subject and body are copied only when they differ from the notification's own,
+// which keeps the table small while dispatch leaves content untouched.
+type RenderedContent struct {
+	ID             uint   `json:"-" gorm:"primaryKey"`
+	TenantID       string `json:"-" gorm:"uniqueIndex:idx_rendered_content_notification;not null"`
+	NotificationID string `json:"-" gorm:"uniqueIndex:idx_rendered_content_notification;not null"`
+	// SameAsInput reports that the stored notification's subject and body went out unchanged.
+	SameAsInput bool   `json:"same_as_input" description:"Whether the subject and body went out unchanged."`
+	Subject     string `json:"subject,omitempty" description:"Subject as dispatched, when it differs from the notification."`
+	Message     string `json:"message" description:"Body as dispatched, when it differs from the notification."`
+	// MIMEStructure is one of the MIMEStructure* values for email and empty for SMS.
+	MIMEStructure string `json:"mime_structure,omitempty" description:"MIME layout of the email: plain, mixed or alternative; empty for SMS."`
+	// SizeBytes is the size of the raw email message or of the SMS body.
+	SizeBytes  int       `json:"size_bytes" description:"Size of the raw email message or of the SMS body."`
+	RenderedAt time.Time `json:"rendered_at" description:"Time the content was rendered for dispatch."`
+	CreatedAt  time.Time `json:"-"`
+	UpdatedAt  time.Time `json:"-"`
+}
+
+// NewRenderedContent describes the dispatch of n with the given final subject and body.
+func NewRenderedContent(n Notification, subject string, message string, mimeStructure string, sizeBytes int, renderedAt time.Time) RenderedContent {
+	content := RenderedContent{
+		TenantID:       n.TenantID,
+		NotificationID: n.NotificationID,
+		SameAsInput:    subject == n.Subject && message == n.Message,
+		MIMEStructure:  mimeStructure,
+		SizeBytes:      sizeBytes,
+		RenderedAt:     renderedAt.UTC(),
+	}
+	if !content.SameAsInput {
+		content.Subject = subject
+		content.Message = message
+	}
+	return content
+}
+
+// WithInput fills the subject and body from n when they were not copied at dispatch.
+func (content RenderedContent) WithInput(n Notification) RenderedContent {
+	if content.SameAsInput {
+		content.Subject = n.Subject
+		content.Message = n.Message
+	}
+	return content
+}
+
+// SaveRenderedContent inserts or replaces the notification's rendered content, so a
+// retry overwrites what an earlier failed attempt recorded.
+func SaveRenderedContent(ctx context.Context, db *gorm.DB, content *RenderedContent) error {
+	err := retryOnBusy(ctx, func() error {
+		return db.WithContext(ctx).
+			Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: renderedContentTenantIDColumn}, {Name: renderedContentNotificationIDColumn}},
+				UpdateAll: true,
+			}).
+			Create(content).Error
+	})
+	if err != nil {
+		return fmt.Errorf("save_rendered_content: %w", err)
+	}
+	return nil
+}
+
+// GetRenderedContent loads a notification's rendered content; found is false when
+// none was recorded.
+func GetRenderedContent(ctx context.Context, db *gorm.DB, tenantID string, notificationID string) (RenderedContent, bool, error) {
+	var content RenderedContent
+	err := retryOnBusy(ctx, func() error {
+		return db.WithContext(ctx).
+			Where(&RenderedContent{TenantID: tenantID, NotificationID: notificationID}).
+			Take(&content).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return RenderedContent{}, false, nil
+	}
+	if err != nil {
+		return RenderedContent{}, false, fmt.Errorf("get_rendered_content: %w", err)
+	}
+	return content, true, nil
+}
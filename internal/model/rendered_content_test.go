@@ -0,0 +1,52 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRenderedContentCopiesOnlyChangedContent(t *testing.T) {
+	notification := Notification{TenantID: "tenant-a", NotificationID: "notif-1", Subject: "Hello", Message: "Body"}
+	renderedAt := time.Now()
+
+	unchanged := NewRenderedContent(notification, "Hello", "Body", MIMEStructurePlain, 100, renderedAt)
+	if !unchanged.SameAsInput || unchanged.Subject != "" || unchanged.Message != "" {
+		t.Fatalf("expected unchanged content not to be copied, got %+v", unchanged)
+	}
+	if resolved := unchanged.WithInput(notification); resolved.Subject != "Hello" || resolved.Message != "Body" {
+		t.Fatalf("expected WithInput to fill the stored content, got %+v", resolved)
+	}
+
+	changed := NewRenderedContent(notification, "Hello", "Body\n-- Acme", MIMEStructurePlain, 110, renderedAt)
+	if changed.SameAsInput || changed.Message != "Body\n-- Acme" {
+		t.Fatalf("expected changed content to be copied, got %+v", changed)
+	}
+	if resolved := changed.WithInput(notification); resolved.Message != "Body\n-- Acme" {
+		t.Fatalf("expected WithInput to keep rendered content, got %+v", resolved)
+	}
+}
+
+func TestSaveRenderedContentReplacesEarlierAttempt(t *testing.T) {
+	db := openModelTestDatabase(t)
+	if err := db.AutoMigrate(&RenderedContent{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	ctx := context.Background()
+	notification := Notification{TenantID: "tenant-a", NotificationID: "notif-1", Message: "Body"}
+	first := NewRenderedContent(notification, "", "First", "", 5, time.Now())
+	if err := SaveRenderedContent(ctx, db, &first); err != nil {
+		t.Fatalf("save first: %v", err)
+	}
+	second := NewRenderedContent(notification, "", "Second", "", 6, time.Now())
+	if err := SaveRenderedContent(ctx, db, &second); err != nil {
+		t.Fatalf("save second: %v", err)
+	}
+	stored, found, err := GetRenderedContent(ctx, db, "tenant-a", "notif-1")
+	if err != nil || !found || stored.Message != "Second" || stored.SizeBytes != 6 {
+		t.Fatalf("expected the second attempt to win, got %+v found=%v err=%v", stored, found, err)
+	}
+	if _, found, err := GetRenderedContent(ctx, db, "tenant-b", "notif-1"); err != nil || found {
+		t.Fatalf("expected rendered content to be tenant scoped, found=%v err=%v", found, err)
+	}
+}
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	notificationLegalHoldColumn = "legal_hold"
+	// retentionPurgeBatchSize bounds the notifications deleted per transaction.
+	retentionPurgeBatchSize = 500
+)
+
+// SetNotificationLegalHold places or lifts a legal hold on the tenant's notification and
+// returns the updated record. Held notifications are never purged by retention.
+func SetNotificationLegalHold(ctx context.Context, db *gorm.DB, tenantID string, notificationID string, held bool, currentTime time.Time) (*Notification, error) {
+	var rowsAffected int64
+	err := retryOnBusy(ctx, func() error {
+		result := db.WithContext(ctx).
+			Model(&Notification{}).
+			Where(&Notification{TenantID: tenantID, NotificationID: notificationID}).
+			Updates(map[string]interface{}{
+				notificationLegalHoldColumn: held,
+				notificationUpdatedAtColumn: currentTime.UTC(),
+			})
+		rowsAffected = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("set_notification_legal_hold: %w", err)
+	}
+	if rowsAffected == 0 {
+		return nil, ErrNotificationNotFound
+	}
+	return GetNotificationByID(ctx, db, tenantID, notificationID)
+}
+
+// PurgeNotificationsBefore deletes the tenant's finished notifications created before
+// cutoff, along with their attachments, rendered content and queue idempotency keys.
+// Queued notifications and those under legal hold are kept. It returns how many notifications were deleted.
+func PurgeNotificationsBefore(ctx context.Context, db *gorm.DB, tenantID string, cutoff time.Time) (int64, error) {
+	ctx = aggregateQueries(ctx)
+	var purged int64
+	for {
+		var batch []Notification
+		err := retryOnBusy(ctx, func() error {
+			return db.WithContext(ctx).
+				Where(clause.And(
+					clause.Eq{Column: clause.Column{Name: notificationTenantIDColumn}, Value: tenantID},
+					clause.Eq{Column: clause.Column{Name: notificationLegalHoldColumn}, Value: false},
+					clause.IN{Column: clause.Column{Name: notificationStatusColumn}, Values: []interface{}{StatusSent, StatusErrored, StatusCancelled}},
+					clause.Lt{Column: clause.Column{Name: notificationCreatedAtColumn}, Value: cutoff.UTC()},
+				)).
+				Order(clause.OrderByColumn{Column: clause.Column{Name: notificationIDColumn}}).
+				Limit(retentionPurgeBatchSize).
+				Find(&batch).Error
+		})
+		if err != nil {
+			return purged, fmt.Errorf("purge_notifications: %w", err)
+		}
+		if len(batch) == 0 {
+			return purged, nil
+		}
+		rowIDs := make([]interface{}, len(batch))
+		notificationIDs := make([]interface{}, len(batch))
+		for index, notification := range batch {
+			rowIDs[index] = notification.ID
+			notificationIDs[index] = notification.NotificationID
+		}
+		err = retryOnBusy(ctx, func() error {
+			return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+				byNotification := clause.And(
+					clause.Eq{Column: clause.Column{Name: notificationTenantIDColumn}, Value: tenantID},
+					clause.IN{Column: clause.Column{Name: notificationNotificationIDColumn}, Values: notificationIDs},
+				)
+				if _, err := deleteAttachmentRows(tx, byNotification); err != nil {
+					return err
+				}
+				if err := tx.Where(byNotification).Delete(&RenderedContent{}).Error; err != nil {
+					return err
+				}
+				if err := tx.Where(byNotification).Delete(&NotificationOpen{}).Error; err != nil {
+					return err
+				}
+				if err := tx.Where(byNotification).Delete(&QueueIntakeKey{}).Error; err != nil {
+					return err
+				}
+				return tx.Where(clause.IN{Column: clause.Column{Name: notificationIDColumn}, Values: rowIDs}).Delete(&Notification{}).Error
+			})
+		})
+		if err != nil {
+			return purged, fmt.Errorf("purge_notifications: %w", err)
+		}
+		purged += int64(len(batch))
+		if len(batch) < retentionPurgeBatchSize {
+			return purged, nil
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPurgeNotificationsBeforeRemovesDependentRows(t *testing.T) {
+	db := openModelTestDatabase(t)
+	if err := db.AutoMigrate(&RenderedContent{}, &QueueIntakeKey{}, &NotificationOpen{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	ctx := context.Background()
+	cutoff := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	records := []Notification{
+		{TenantID: "tenant-a", NotificationID: "expired", Status: StatusSent, CreatedAt: cutoff.Add(-time.Hour), Attachments: []NotificationAttachment{{TenantID: "tenant-a", Filename: "a.txt", ContentType: "text/plain", Data: []byte("a")}}},
+		{TenantID: "tenant-a", NotificationID: "held", Status: StatusSent, CreatedAt: cutoff.Add(-time.Hour)},
+		{TenantID: "tenant-a", NotificationID: "fresh", Status: StatusSent, CreatedAt: cutoff.Add(time.Hour)},
+		{TenantID: "tenant-b", NotificationID: "other-tenant", Status: StatusSent, CreatedAt: cutoff.Add(-time.Hour)},
+	}
+	for index := range records {
+		records[index].NotificationType = NotificationEmail
+		records[index].Recipient = "user@example.com"
+		if err := CreateNotification(ctx, db, &records[index]); err != nil {
+			t.Fatalf("create %s: %v", records[index].NotificationID, err)
+		}
+	}
+	if _, err := SetNotificationLegalHold(ctx, db, "tenant-a", "held", true, cutoff); err != nil {
+		t.Fatalf("hold: %v", err)
+	}
+	if _, err := SetNotificationLegalHold(ctx, db, "tenant-b", "held", true, cutoff); err != ErrNotificationNotFound {
+		t.Fatalf("expected a hold on another tenant's notification to be rejected, got %v", err)
+	}
+
+	if _, _, err := ClaimQueueIntakeKey(ctx, db, "tenant-a", "key-expired", cutoff); err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	if err := ConfirmQueueIntakeKey(ctx, db, "tenant-a", "key-expired", "expired"); err != nil {
+		t.Fatalf("confirm: %v", err)
+	}
+
+	purged, err := PurgeNotificationsBefore(ctx, db, "tenant-a", cutoff)
+	if err != nil || purged != 1 {
+		t.Fatalf("expected one purged notification, got %d err=%v", purged, err)
+	}
+	if _, err := GetNotificationByID(ctx, db, "tenant-a", "expired"); err == nil {
+		t.Fatalf("expected the expired notification to be deleted")
+	}
+	var attachmentCount int64
+	if err := db.Model(&NotificationAttachment{}).Count(&attachmentCount).Error; err != nil || attachmentCount != 0 {
+		t.Fatalf("expected attachments to be purged, got %d err=%v", attachmentCount, err)
+	}
+	var keyCount int64
+	if err := db.Model(&QueueIntakeKey{}).Count(&keyCount).Error; err != nil || keyCount != 0 {
+		t.Fatalf("expected queue intake keys to be purged, got %d err=%v", keyCount, err)
+	}
+	for _, kept := range []struct{ tenantID, notificationID string }{{"tenant-a", "held"}, {"tenant-a", "fresh"}, {"tenant-b", "other-tenant"}} {
+		if _, err := GetNotificationByID(ctx, db, kept.tenantID, kept.notificationID); err != nil {
+			t.Fatalf("expected %s to survive: %v", kept.notificationID, err)
+		}
+	}
+}
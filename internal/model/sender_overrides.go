@@ -0,0 +1,82 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxFromDisplayNameLength caps the characters of a per-message From display name.
+const MaxFromDisplayNameLength = 100
+
+var (
+	// ErrNotificationReplyToInvalid indicates a reply_to that is not a single bare email address.
+	ErrNotificationReplyToInvalid = errors.New("notification.request.invalid_reply_to")
+	// ErrNotificationFromDisplayNameInvalid indicates a from_display_name that is too long or carries control characters.
+	ErrNotificationFromDisplayNameInvalid = errors.New("notification.request.invalid_from_display_name")
+	// ErrNotificationSenderOverrideForbidden indicates a reply_to or from_display_name the tenant's policy does not allow.
+	ErrNotificationSenderOverrideForbidden = errors.New("notification.request.sender_override_forbidden")
+)
+
+// SenderOverrides are the per-message Reply-To address and From display name of an
+// email. The From address itself always stays the tenant's.
+type SenderOverrides struct {
+	ReplyTo         string
+	FromDisplayName string
+}
+
+// IsZero reports whether neither header is overridden.
+func (overrides SenderOverrides) IsZero() bool {
+	return overrides.ReplyTo == "" && overrides.FromDisplayName == ""
+}
+
+// ReplyToDomain returns the lowercased domain of the Reply-To address, or "" when unset.
+func (overrides SenderOverrides) ReplyToDomain() string {
+	separator := strings.LastIndex(overrides.ReplyTo, "@")
+	if separator < 0 {
+		return ""
+	}
+	return strings.ToLower(overrides.ReplyTo[separator+1:])
+}
+
+// WithSenderOverrides returns a copy of an email request that sets its Reply-To and
+// From display name; empty values keep the defaults. replyTo must be one bare address,
+// without a display name, and fromDisplayName at most MaxFromDisplayNameLength
+// characters of text without control characters. Whether the tenant allows either is
+// decided when the request is sent.
+func (request NotificationRequest) WithSenderOverrides(replyTo string, fromDisplayName string) (NotificationRequest, error) {
+	replyTo = strings.TrimSpace(replyTo)
+	fromDisplayName = strings.TrimSpace(fromDisplayName)
+	if replyTo == "" && fromDisplayName == "" {
+		return request, nil
+	}
+	if request.notificationType != NotificationEmail {
+		return NotificationRequest{}, fmt.Errorf("%w: reply_to and from_display_name apply to email notifications only", ErrNotificationSenderOverrideForbidden)
+	}
+	if replyTo != "" {
+		address, err := mail.ParseAddress(replyTo)
+		if err != nil || address.Name != "" || address.Address != replyTo {
+			return NotificationRequest{}, fmt.Errorf("%w: %q", ErrNotificationReplyToInvalid, replyTo)
+		}
+	}
+	if utf8.RuneCountInString(fromDisplayName) > MaxFromDisplayNameLength {
+		return NotificationRequest{}, fmt.Errorf("%w: max %d characters", ErrNotificationFromDisplayNameInvalid, MaxFromDisplayNameLength)
+	}
+	if err := ValidateHeaderValue("from_display_name", fromDisplayName); err != nil {
+		return NotificationRequest{}, fmt.Errorf("%w: %w", ErrNotificationFromDisplayNameInvalid, err)
+	}
+	request.senderOverrides = SenderOverrides{ReplyTo: replyTo, FromDisplayName: fromDisplayName}
+	return request, nil
+}
+
+// SenderOverrides returns the request's Reply-To and From display name overrides.
+func (request NotificationRequest) SenderOverrides() SenderOverrides {
+	return request.senderOverrides
+}
+
+// SenderOverrides returns the Reply-To and From display name the notification is sent with.
+func (n Notification) SenderOverrides() SenderOverrides {
+	return SenderOverrides{ReplyTo: n.ReplyTo, FromDisplayName: n.FromDisplayName}
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithSenderOverrides(t *testing.T) {
+	emailRequest, err := NewNotificationRequest(NotificationEmail, "user@example.com", "Subject", "Body", nil, nil)
+	if err != nil {
+		t.Fatalf("NewNotificationRequest error: %v", err)
+	}
+	overridden, err := emailRequest.WithSenderOverrides(" agent@support.Example.com ", " Ada from Support ")
+	if err != nil {
+		t.Fatalf("expected valid overrides to be accepted, got %v", err)
+	}
+	expected := SenderOverrides{ReplyTo: "agent@support.Example.com", FromDisplayName: "Ada from Support"}
+	if overridden.SenderOverrides() != expected || expected.ReplyToDomain() != "support.example.com" {
+		t.Fatalf("unexpected overrides %+v", overridden.SenderOverrides())
+	}
+	notification := NewNotification("notif-1", "tenant-1", overridden, time.Now())
+	if notification.SenderOverrides() != expected {
+		t.Fatalf("expected the notification to record the overrides, got %+v", notification)
+	}
+	if response := NewNotificationResponse(notification); response.ReplyTo != expected.ReplyTo || response.FromDisplayName != expected.FromDisplayName {
+		t.Fatalf("expected the response to report the overrides, got %+v", response)
+	}
+	if unchanged, err := emailRequest.WithSenderOverrides(" ", ""); err != nil || !unchanged.SenderOverrides().IsZero() {
+		t.Fatalf("expected blank overrides to keep the defaults, got %+v (%v)", unchanged.SenderOverrides(), err)
+	}
+
+	for _, testCase := range []struct {
+		name            string
+		replyTo         string
+		fromDisplayName string
+		expected        error
+	}{
+		{name: "display name in reply_to", replyTo: "Agent <agent@example.com>", expected: ErrNotificationReplyToInvalid},
+		{name: "several reply_to addresses", replyTo: "a@example.com, b@example.com", expected: ErrNotificationReplyToInvalid},
+		{name: "malformed reply_to", replyTo: "agent@", expected: ErrNotificationReplyToInvalid},
+		{name: "header injection in reply_to", replyTo: "agent@example.com\r\nBcc: x@example.com", expected: ErrNotificationReplyToInvalid},
+		{name: "header injection in display name", fromDisplayName: "Support\r\nBcc: x@example.com", expected: ErrNotificationFromDisplayNameInvalid},
+		{name: "display name too long", fromDisplayName: strings.Repeat("a", MaxFromDisplayNameLength+1), expected: ErrNotificationFromDisplayNameInvalid},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if _, err := emailRequest.WithSenderOverrides(testCase.replyTo, testCase.fromDisplayName); !errors.Is(err, testCase.expected) {
+				t.Fatalf("expected %v, got %v", testCase.expected, err)
+			}
+		})
+	}
+
+	smsRequest, err := NewNotificationRequest(NotificationSMS, "+15555550100", "", "Body", nil, nil)
+	if err != nil {
+		t.Fatalf("NewNotificationRequest error: %v", err)
+	}
+	if _, err := smsRequest.WithSenderOverrides("agent@example.com", ""); !errors.Is(err, ErrNotificationSenderOverrideForbidden) {
+		t.Fatalf("expected SMS overrides to be refused, got %v", err)
+	}
+}
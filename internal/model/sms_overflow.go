@@ -0,0 +1,149 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SMSOverflowPolicy decides what happens to an SMS body longer than its tenant's segment limit.
+type SMSOverflowPolicy string
+
+const (
+	// SMSOverflowReject refuses oversized SMS bodies; it is the default.
+	SMSOverflowReject SMSOverflowPolicy = "reject"
+	// SMSOverflowTruncate shortens oversized SMS bodies to the limit and appends a suffix.
+	SMSOverflowTruncate SMSOverflowPolicy = "truncate"
+
+	// DefaultSMSTruncationSuffix marks a truncated SMS body; it is GSM-7 so it never
+	// forces a body into UCS-2 on its own.
+	DefaultSMSTruncationSuffix = "..."
+
+	// SMSTruncatedWarning is the response warning attached to a truncated SMS.
+	SMSTruncatedWarning = "sms.truncated"
+
+	zeroWidthJoiner           = '\u200D'
+	emojiModifierFirst        = '\U0001F3FB'
+	emojiModifierLast         = '\U0001F3FF'
+	regionalIndicatorFirst    = '\U0001F1E6'
+	regionalIndicatorLast     = '\U0001F1FF'
+	emojiTagFirst             = '\U000E0020'
+	emojiTagLast              = '\U000E007F'
+	smsOverflowPolicyTemplate = "%w: %q"
+)
+
+var (
+	// ErrNotificationSMSTooLong indicates an SMS body exceeds the tenant's segment limit under the reject policy.
+	ErrNotificationSMSTooLong = errors.New("notification.request.sms_too_long")
+	// ErrNotificationSMSOverflowPolicyInvalid indicates an unknown overflow policy or one set on a non-SMS request.
+	ErrNotificationSMSOverflowPolicyInvalid = errors.New("notification.request.invalid_sms_overflow_policy")
+)
+
+// ParseSMSOverflowPolicy normalizes a policy name; an empty name yields "" so callers
+// can fall back to the tenant default.
+func ParseSMSOverflowPolicy(raw string) (SMSOverflowPolicy, error) {
+	switch policy := SMSOverflowPolicy(strings.ToLower(strings.TrimSpace(raw))); policy {
+	case "", SMSOverflowReject, SMSOverflowTruncate:
+		return policy, nil
+	default:
+		return "", fmt.Errorf(smsOverflowPolicyTemplate, ErrNotificationSMSOverflowPolicyInvalid, raw)
+	}
+}
+
+// WithSMSOverflowPolicy returns a copy of an SMS request that overrides the tenant's
+// overflow policy. An empty policy keeps the tenant default.
+func (request NotificationRequest) WithSMSOverflowPolicy(raw string) (NotificationRequest, error) {
+	policy, err := ParseSMSOverflowPolicy(raw)
+	if err != nil {
+		return NotificationRequest{}, err
+	}
+	if policy != "" && request.notificationType != NotificationSMS {
+		return NotificationRequest{}, fmt.Errorf("%w: overflow policies apply to SMS notifications only", ErrNotificationSMSOverflowPolicyInvalid)
+	}
+	if policy == SMSOverflowTruncate && request.contentEncrypted {
+		return NotificationRequest{}, fmt.Errorf("%w: encrypted SMS cannot be truncated", ErrNotificationEncryptedContentIncompatible)
+	}
+	request.smsOverflowPolicy = policy
+	return request, nil
+}
+
+// SMSOverflowPolicy returns the request's overflow policy, or "" for the tenant default.
+func (request NotificationRequest) SMSOverflowPolicy() SMSOverflowPolicy {
+	return request.smsOverflowPolicy
+}
+
+// TruncateSMS shortens message so that it plus suffix fits in maxSegments segments as
+// counted by SMSSegmentCount, so dropping the last non-GSM-7 character lets the result
+// use the larger GSM-7 limits. The cut falls between grapheme clusters, never inside a
+// character sequence such as a letter and its combining marks or a joined emoji, and
+// trailing whitespace before the suffix is dropped. The second result is false when
+// not even the suffix alone fits.
+func TruncateSMS(message string, maxSegments int, suffix string) (string, bool) {
+	fits := func(prefixLength int) bool {
+		return SMSSegmentCount(strings.TrimRightFunc(message[:prefixLength], unicode.IsSpace)+suffix) <= maxSegments
+	}
+	if maxSegments <= 0 || !fits(0) {
+		return "", false
+	}
+	boundaries := graphemeBoundaries(message)
+	// Boundaries only grow the candidate prefix, and segment counts never shrink as a
+	// body grows, so the longest fitting prefix is a binary search away.
+	longest := sort.Search(len(boundaries), func(index int) bool {
+		return !fits(boundaries[index])
+	}) - 1
+	prefixLength := 0
+	if longest >= 0 {
+		prefixLength = boundaries[longest]
+	}
+	return strings.TrimRightFunc(message[:prefixLength], unicode.IsSpace) + suffix, true
+}
+
+// graphemeBoundaries returns the byte offsets, ascending and ending at len(message), where
+// message can be cut without splitting a grapheme cluster. It covers the cases SMS bodies
+// meet in practice: combining marks, variation selectors, emoji modifiers, tags and
+// zero-width joiner sequences, regional indicator flag pairs, and CRLF.
+func graphemeBoundaries(message string) []int {
+	boundaries := make([]int, 0, utf8.RuneCountInString(message))
+	previous := rune(-1)
+	regionalIndicatorRun := 0
+	for offset, character := range message {
+		if previous >= 0 && !extendsGrapheme(previous, character, regionalIndicatorRun) {
+			boundaries = append(boundaries, offset)
+		}
+		if isRegionalIndicator(character) {
+			regionalIndicatorRun++
+		} else {
+			regionalIndicatorRun = 0
+		}
+		previous = character
+	}
+	return append(boundaries, len(message))
+}
+
+// extendsGrapheme reports whether character belongs to the cluster ending in previous;
+// regionalIndicatorRun counts the regional indicators that end at previous.
+func extendsGrapheme(previous rune, character rune, regionalIndicatorRun int) bool {
+	switch {
+	case previous == '\r' && character == '\n':
+		return true
+	case previous == zeroWidthJoiner, character == zeroWidthJoiner:
+		return true
+	case unicode.In(character, unicode.Mn, unicode.Me, unicode.Mc, unicode.Variation_Selector):
+		return true
+	case character >= emojiModifierFirst && character <= emojiModifierLast:
+		return true
+	case character >= emojiTagFirst && character <= emojiTagLast:
+		return true
+	case isRegionalIndicator(character):
+		return regionalIndicatorRun%2 == 1
+	default:
+		return false
+	}
+}
+
+func isRegionalIndicator(character rune) bool {
+	return character >= regionalIndicatorFirst && character <= regionalIndicatorLast
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateSMS(t *testing.T) {
+	testCases := []struct {
+		name        string
+		message     string
+		maxSegments int
+		expected    string
+	}{
+		{name: "gsm7 exact single segment boundary", message: strings.Repeat("a", 161), maxSegments: 1, expected: strings.Repeat("a", 157) + "..."},
+		{name: "gsm7 concatenated limit", message: strings.Repeat("a", 400), maxSegments: 2, expected: strings.Repeat("a", 303) + "..."},
+		{name: "gsm7 extended characters count double", message: strings.Repeat("€", 100), maxSegments: 1, expected: strings.Repeat("€", 78) + "..."},
+		{name: "ucs2 exact single segment boundary", message: strings.Repeat("ж", 71), maxSegments: 1, expected: strings.Repeat("ж", 67) + "..."},
+		{name: "ucs2 concatenated limit", message: strings.Repeat("ж", 200), maxSegments: 2, expected: strings.Repeat("ж", 131) + "..."},
+		{name: "dropping the only ucs2 character restores gsm7 limits", message: strings.Repeat("a", 200) + "ж", maxSegments: 2, expected: strings.Repeat("a", 200) + "..."},
+		{name: "surrogate pairs are never split", message: strings.Repeat("😀", 50), maxSegments: 1, expected: strings.Repeat("😀", 33) + "..."},
+		{name: "combining marks stay with their base", message: strings.Repeat("e\u0301", 50), maxSegments: 1, expected: strings.Repeat("e\u0301", 33) + "..."},
+		{name: "flags keep both regional indicators", message: strings.Repeat("🇺🇸", 40), maxSegments: 1, expected: strings.Repeat("🇺🇸", 16) + "..."},
+		{name: "trailing whitespace is dropped before the suffix", message: strings.Repeat("a", 156) + " bcdef", maxSegments: 1, expected: strings.Repeat("a", 156) + "..."},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			truncated, ok := TruncateSMS(testCase.message, testCase.maxSegments, DefaultSMSTruncationSuffix)
+			if !ok {
+				t.Fatalf("expected the message to be truncatable")
+			}
+			if truncated != testCase.expected {
+				t.Fatalf("expected %q (%d runes), got %q (%d runes)", testCase.expected, utf8.RuneCountInString(testCase.expected), truncated, utf8.RuneCountInString(truncated))
+			}
+			if !utf8.ValidString(truncated) {
+				t.Fatalf("truncated body is not valid UTF-8")
+			}
+			if segments := SMSSegmentCount(truncated); segments > testCase.maxSegments {
+				t.Fatalf("truncated body needs %d segments, limit %d", segments, testCase.maxSegments)
+			}
+		})
+	}
+}
+
+func TestTruncateSMSReportsSuffixThatCannotFit(t *testing.T) {
+	if _, ok := TruncateSMS(strings.Repeat("a", 200), 1, strings.Repeat("ж", 71)); ok {
+		t.Fatalf("expected a suffix longer than the limit to be rejected")
+	}
+	if _, ok := TruncateSMS(strings.Repeat("a", 200), 0, DefaultSMSTruncationSuffix); ok {
+		t.Fatalf("expected a zero segment limit to be rejected")
+	}
+}
+
+func TestWithSMSOverflowPolicy(t *testing.T) {
+	smsRequest, err := NewNotificationRequest(NotificationSMS, "+15555550100", "", "Body", nil, nil)
+	if err != nil {
+		t.Fatalf("NewNotificationRequest error: %v", err)
+	}
+	truncating, err := smsRequest.WithSMSOverflowPolicy(" Truncate ")
+	if err != nil || truncating.SMSOverflowPolicy() != SMSOverflowTruncate {
+		t.Fatalf("expected truncate policy, got %q (%v)", truncating.SMSOverflowPolicy(), err)
+	}
+	if _, err := smsRequest.WithSMSOverflowPolicy("shorten"); !errors.Is(err, ErrNotificationSMSOverflowPolicyInvalid) {
+		t.Fatalf("expected unknown policy to be rejected, got %v", err)
+	}
+	emailRequest, err := NewNotificationRequest(NotificationEmail, "user@example.com", "Subject", "Body", nil, nil)
+	if err != nil {
+		t.Fatalf("NewNotificationRequest error: %v", err)
+	}
+	if _, err := emailRequest.WithSMSOverflowPolicy("truncate"); !errors.Is(err, ErrNotificationSMSOverflowPolicyInvalid) {
+		t.Fatalf("expected email request to reject an overflow policy, got %v", err)
+	}
+	if unchanged, err := emailRequest.WithSMSOverflowPolicy(""); err != nil || unchanged.SMSOverflowPolicy() != "" {
+		t.Fatalf("expected an empty policy to be accepted on any request, got %v", err)
+	}
+}
+
+func TestNotificationResponseWarnsAboutTruncatedSMS(t *testing.T) {
+	record := Notification{NotificationType: NotificationSMS, Message: "résumé of a long text"}
+	record.MarkSMSTruncated("résumé...")
+	response := NewNotificationResponse(record)
+	if !response.Truncated || response.OriginalMessageLength != 21 || response.Message != "résumé..." {
+		t.Fatalf("unexpected truncation fields %+v", response)
+	}
+	if len(response.Warnings) != 1 || response.Warnings[0] != SMSTruncatedWarning {
+		t.Fatalf("expected a truncation warning, got %v", response.Warnings)
+	}
+}
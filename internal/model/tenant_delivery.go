@@ -0,0 +1,87 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const notificationLastAttemptedAtColumn = "last_attempted_at"
+
+// TenantDeliverySummary counts a tenant's backlog and records its most recent successful dispatch.
+type TenantDeliverySummary struct {
+	QueuedCount      int64
+	ErroredCount     int64
+	LastDispatchedAt *time.Time
+	// OldestPendingSince is when the longest-waiting due queued notification became due:
+	// its schedule when it has one, else its creation. Nil when nothing queued is due.
+	OldestPendingSince *time.Time
+}
+
+// SummarizeTenantDelivery runs count and single-row queries only, so it stays cheap
+// regardless of how many notifications a tenant has stored. Queued notifications
+// scheduled after now are not yet pending.
+func SummarizeTenantDelivery(ctx context.Context, db *gorm.DB, tenantID string, now time.Time) (TenantDeliverySummary, error) {
+	ctx = aggregateQueries(ctx)
+	var summary TenantDeliverySummary
+	database := db.WithContext(ctx)
+	if err := database.Model(&Notification{}).
+		Where(&Notification{TenantID: tenantID, Status: StatusQueued}).
+		Count(&summary.QueuedCount).Error; err != nil {
+		return TenantDeliverySummary{}, fmt.Errorf("summarize_tenant_delivery: queued: %w", err)
+	}
+	if err := database.Model(&Notification{}).
+		Where(&Notification{TenantID: tenantID, Status: StatusErrored}).
+		Count(&summary.ErroredCount).Error; err != nil {
+		return TenantDeliverySummary{}, fmt.Errorf("summarize_tenant_delivery: errored: %w", err)
+	}
+	var dispatchTimes []time.Time
+	if err := database.Model(&Notification{}).
+		Where(&Notification{TenantID: tenantID, Status: StatusSent}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: notificationLastAttemptedAtColumn}, Desc: true}).
+		Limit(1).
+		Pluck(notificationLastAttemptedAtColumn, &dispatchTimes).Error; err != nil {
+		return TenantDeliverySummary{}, fmt.Errorf("summarize_tenant_delivery: last dispatch: %w", err)
+	}
+	if len(dispatchTimes) > 0 {
+		summary.LastDispatchedAt = utcTimePointer(&dispatchTimes[0])
+	}
+	oldestPending, err := oldestPendingSince(database, tenantID, now)
+	if err != nil {
+		return TenantDeliverySummary{}, fmt.Errorf("summarize_tenant_delivery: oldest pending: %w", err)
+	}
+	summary.OldestPendingSince = oldestPending
+	return summary, nil
+}
+
+// oldestPendingSince returns the earliest due time among the tenant's queued
+// notifications, taking the oldest unscheduled creation and the oldest passed schedule.
+func oldestPendingSince(database *gorm.DB, tenantID string, now time.Time) (*time.Time, error) {
+	scheduledFor := clause.Column{Name: notificationScheduledForColumn}
+	candidates := []struct {
+		column    string
+		condition clause.Expression
+	}{
+		{column: notificationCreatedAtColumn, condition: clause.Eq{Column: scheduledFor, Value: nil}},
+		{column: notificationScheduledForColumn, condition: clause.Lte{Column: scheduledFor, Value: now.UTC()}},
+	}
+	var oldest *time.Time
+	for _, candidate := range candidates {
+		var dueTimes []time.Time
+		if err := database.Model(&Notification{}).
+			Where(&Notification{TenantID: tenantID, Status: StatusQueued}).
+			Clauses(clause.Where{Exprs: []clause.Expression{candidate.condition}}).
+			Order(clause.OrderByColumn{Column: clause.Column{Name: candidate.column}}).
+			Limit(1).
+			Pluck(candidate.column, &dueTimes).Error; err != nil {
+			return nil, err
+		}
+		if len(dueTimes) > 0 && (oldest == nil || dueTimes[0].Before(*oldest)) {
+			oldest = utcTimePointer(&dueTimes[0])
+		}
+	}
+	return oldest, nil
+}
@@ -0,0 +1,94 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WebhookDeliveryStatus tracks a webhook event through delivery.
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending events are waiting for their first or next attempt.
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliveryDelivered events were accepted with a 2xx response.
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookDeliveryFailed events were rejected permanently or ran out of retries.
+	WebhookDeliveryFailed WebhookDeliveryStatus = "failed"
+)
+
+const (
+	webhookDeliveryIDColumn         = "id"
+	webhookDeliveryStatusColumn     = "status"
+	webhookDeliveryRetryCountColumn = "retry_count"
+)
+
+// WebhookDelivery is one notification state event queued for a tenant's webhook. The
+// row carries only what the signed payload needs, never the recipient; its snapshot is
+// redacted.
+type WebhookDelivery struct {
+	ID                 uint   `gorm:"primaryKey"`
+	EventID            string `gorm:"uniqueIndex;not null"`
+	TenantID           string `gorm:"index;not null"`
+	NotificationID     string `gorm:"not null"`
+	Event              string `gorm:"not null"`
+	NotificationStatus NotificationStatus
+	OccurredAt         time.Time
+	// CancelReason, CancelledBy and CancelledAt are copied from cancelled notifications.
+	CancelReason string
+	CancelledBy  string
+	CancelledAt  *time.Time
+	// Snapshot is the notification as the event saw it; nil on events queued before snapshots.
+	Snapshot        *NotificationSnapshot `gorm:"serializer:json"`
+	Status          WebhookDeliveryStatus `gorm:"index"`
+	RetryCount      int
+	LastAttemptedAt time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// CreateWebhookDelivery queues a webhook event.
+func CreateWebhookDelivery(ctx context.Context, db *gorm.DB, delivery *WebhookDelivery) error {
+	err := retryOnBusy(ctx, func() error {
+		return db.WithContext(ctx).Create(delivery).Error
+	})
+	if err != nil {
+		return fmt.Errorf("create_webhook_delivery: %w", err)
+	}
+	return nil
+}
+
+// ListPendingWebhookDeliveries returns up to limit pending events still under the retry
+// limit, oldest first.
+func ListPendingWebhookDeliveries(ctx context.Context, db *gorm.DB, maxRetries int, limit int) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+	err := retryOnBusy(ctx, func() error {
+		return db.WithContext(ctx).
+			Where(clause.And(
+				clause.Eq{Column: clause.Column{Name: webhookDeliveryStatusColumn}, Value: WebhookDeliveryPending},
+				clause.Lt{Column: clause.Column{Name: webhookDeliveryRetryCountColumn}, Value: maxRetries},
+			)).
+			Order(clause.OrderByColumn{Column: clause.Column{Name: webhookDeliveryIDColumn}}).
+			Limit(limit).
+			Find(&deliveries).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list_pending_webhook_deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// SaveWebhookDelivery persists the outcome of a delivery attempt.
+func SaveWebhookDelivery(ctx context.Context, db *gorm.DB, delivery *WebhookDelivery) error {
+	err := retryOnBusy(ctx, func() error {
+		return db.WithContext(ctx).Save(delivery).Error
+	})
+	if err != nil {
+		return fmt.Errorf("save_webhook_delivery: %w", err)
+	}
+	return nil
+}
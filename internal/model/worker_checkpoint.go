@@ -0,0 +1,56 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const workerCheckpointWorkerNameColumn = "worker_name"
+
+// RetryWorkerCheckpointName identifies the notification retry worker's checkpoint row.
+const RetryWorkerCheckpointName = "notification_retry"
+
+// WorkerCheckpoint records where a background worker stopped so a restart resumes
+// instead of rescanning from zero. TenantCursors maps a tenant id to the row id of
+// the last notification the worker attempted for that tenant.
+type WorkerCheckpoint struct {
+	WorkerName    string          `gorm:"primaryKey"`
+	LastTickAt    time.Time       `gorm:"not null"`
+	TenantCursors map[string]uint `gorm:"serializer:json"`
+	UpdatedAt     time.Time
+}
+
+// LoadWorkerCheckpoint returns the named worker's checkpoint; found is false when the
+// worker has never completed a tick.
+func LoadWorkerCheckpoint(ctx context.Context, db *gorm.DB, workerName string) (WorkerCheckpoint, bool, error) {
+	var checkpoint WorkerCheckpoint
+	err := db.WithContext(ctx).
+		Where(&WorkerCheckpoint{WorkerName: workerName}).
+		Take(&checkpoint).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return WorkerCheckpoint{}, false, nil
+	}
+	if err != nil {
+		return WorkerCheckpoint{}, false, fmt.Errorf("load_worker_checkpoint: %w", err)
+	}
+	return checkpoint, true, nil
+}
+
+// SaveWorkerCheckpoint inserts or replaces the worker's checkpoint row.
+func SaveWorkerCheckpoint(ctx context.Context, db *gorm.DB, checkpoint WorkerCheckpoint) error {
+	err := db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: workerCheckpointWorkerNameColumn}},
+			UpdateAll: true,
+		}).
+		Create(&checkpoint).Error
+	if err != nil {
+		return fmt.Errorf("save_worker_checkpoint: %w", err)
+	}
+	return nil
+}
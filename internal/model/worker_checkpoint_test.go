@@ -0,0 +1,43 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWorkerCheckpointRoundTripsAndReplaces(t *testing.T) {
+	database := openModelTestDatabase(t)
+	ctx := context.Background()
+
+	if _, found, err := LoadWorkerCheckpoint(ctx, database, RetryWorkerCheckpointName); err != nil || found {
+		t.Fatalf("expected no checkpoint yet, got found=%v err=%v", found, err)
+	}
+	firstTick := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	if err := SaveWorkerCheckpoint(ctx, database, WorkerCheckpoint{
+		WorkerName:    RetryWorkerCheckpointName,
+		LastTickAt:    firstTick,
+		TenantCursors: map[string]uint{"tenant-a": 4},
+	}); err != nil {
+		t.Fatalf("save checkpoint: %v", err)
+	}
+	if err := SaveWorkerCheckpoint(ctx, database, WorkerCheckpoint{
+		WorkerName:    RetryWorkerCheckpointName,
+		LastTickAt:    firstTick.Add(time.Minute),
+		TenantCursors: map[string]uint{"tenant-a": 7, "tenant-b": 2},
+	}); err != nil {
+		t.Fatalf("replace checkpoint: %v", err)
+	}
+
+	checkpoint, found, err := LoadWorkerCheckpoint(ctx, database, RetryWorkerCheckpointName)
+	if err != nil || !found {
+		t.Fatalf("expected a stored checkpoint, got found=%v err=%v", found, err)
+	}
+	if !checkpoint.LastTickAt.Equal(firstTick.Add(time.Minute)) || checkpoint.TenantCursors["tenant-a"] != 7 || checkpoint.TenantCursors["tenant-b"] != 2 {
+		t.Fatalf("unexpected checkpoint %+v", checkpoint)
+	}
+	var rows int64
+	if err := database.Model(&WorkerCheckpoint{}).Count(&rows).Error; err != nil || rows != 1 {
+		t.Fatalf("expected one checkpoint row, got %d (%v)", rows, err)
+	}
+}
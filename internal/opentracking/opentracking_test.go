@@ -0,0 +1,186 @@
+package opentracking
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+	"gorm.io/gorm"
+)
+
+func TestSignerTokenLifecycle(t *testing.T) {
+	signer := NewSigner("master-key")
+	issuedAt := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	claims := Claims{TenantID: "tenant-a", NotificationID: "notification-1", ExpiresAt: issuedAt.Add(time.Hour)}
+	token := signer.Sign(claims)
+
+	verified, err := signer.Verify(token, issuedAt)
+	if err != nil {
+		t.Fatalf("verify fresh token: %v", err)
+	}
+	if verified != claims {
+		t.Fatalf("expected %+v, got %+v", claims, verified)
+	}
+	if _, err := signer.Verify(token, claims.ExpiresAt); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected an expired token to be rejected, got %v", err)
+	}
+
+	payload, mac, _ := strings.Cut(token, ".")
+	forgedPayload := NewSigner("master-key").Sign(Claims{TenantID: "tenant-b", NotificationID: "notification-1", ExpiresAt: claims.ExpiresAt})
+	forgedPayload, _, _ = strings.Cut(forgedPayload, ".")
+	for name, tampered := range map[string]string{
+		"swapped payload": forgedPayload + "." + mac,
+		"truncated mac":   payload + "." + mac[:len(mac)-2],
+		"missing mac":     payload,
+		"other secret":    NewSigner("other-key").Sign(claims),
+		"empty":           "",
+	} {
+		if _, err := signer.Verify(tampered, issuedAt); !errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("%s: expected rejection, got %v", name, err)
+		}
+	}
+}
+
+func TestInjectPlacesPixelBeforeClosingBody(t *testing.T) {
+	pixelURL := "https://mail.example.com/t/a.b"
+	injected := Inject("<html><body><p>Hi</p></BODY></html>", pixelURL)
+	if want := `<p>Hi</p><img src="https://mail.example.com/t/a.b" width="1" height="1" alt="" style="border:0"></BODY></html>`; !strings.HasSuffix(injected, want) {
+		t.Fatalf("expected the pixel before </BODY>, got %q", injected)
+	}
+	if appended := Inject("<html><p>Hi</p>", pixelURL); !strings.HasSuffix(appended, `style="border:0">`) {
+		t.Fatalf("expected the pixel appended to a body without </body>, got %q", appended)
+	}
+}
+
+func TestPixelsURLCarriesVerifiableToken(t *testing.T) {
+	signer := NewSigner("master-key")
+	sentAt := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	pixelURL := NewPixels(signer, "https://mail.example.com/", 48*time.Hour).URL("tenant-a", "notification-1", sentAt)
+	token, found := strings.CutPrefix(pixelURL, "https://mail.example.com"+PixelPathPrefix)
+	if !found {
+		t.Fatalf("unexpected pixel URL %q", pixelURL)
+	}
+	claims, err := signer.Verify(token, sentAt.Add(47*time.Hour))
+	if err != nil || claims.NotificationID != "notification-1" {
+		t.Fatalf("expected the token to verify within its TTL, got %+v (%v)", claims, err)
+	}
+	if _, err := signer.Verify(token, sentAt.Add(48*time.Hour)); err == nil {
+		t.Fatalf("expected the token to expire after its TTL")
+	}
+}
+
+func TestUserAgentFamily(t *testing.T) {
+	for userAgent, want := range map[string]string{
+		"Mozilla/5.0 (Windows NT 5.1; rv:11.0) Gecko Firefox/11.0 (via ggpht.com GoogleImageProxy)": "gmail",
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko)":  "apple",
+		"Microsoft Office/16.0 (Windows NT 10.0; Microsoft Outlook 16.0.17029)":                     "outlook",
+		"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 Chrome/120.0 Safari/537.36 Edg/120.0":   "edge",
+		"curl/8.4.0": "other",
+		"":           "unknown",
+	} {
+		if got := UserAgentFamily(userAgent); got != want {
+			t.Fatalf("UserAgentFamily(%q) = %q, want %q", userAgent, got, want)
+		}
+	}
+}
+
+type stubTenantResolver map[string]tenant.RuntimeConfig
+
+func (resolver stubTenantResolver) ResolveByID(_ context.Context, tenantID string) (tenant.RuntimeConfig, error) {
+	runtimeCfg, found := resolver[tenantID]
+	if !found {
+		return tenant.RuntimeConfig{}, tenant.ErrInvalidTenantID
+	}
+	return runtimeCfg, nil
+}
+
+func openTestDatabase(t *testing.T) *gorm.DB {
+	t.Helper()
+	database, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "opens.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	if err := database.AutoMigrate(&model.NotificationOpen{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return database
+}
+
+func TestTrackerRecordsOnlyOptedInTenants(t *testing.T) {
+	database := openTestDatabase(t)
+	signer := NewSigner("master-key")
+	recorder := newRecorder(database, slog.New(slog.NewTextHandler(io.Discard, nil)), 16, 16, time.Hour)
+	tracker := NewTracker(signer, stubTenantResolver{
+		"tenant-tracked": {Tenant: tenant.Tenant{ID: "tenant-tracked", OpenTracking: true}},
+		"tenant-default": {Tenant: tenant.Tenant{ID: "tenant-default"}},
+	}, recorder)
+	openedAt := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return openedAt }
+	tokenFor := func(tenantID string) string {
+		return signer.Sign(Claims{TenantID: tenantID, NotificationID: "notification-1", ExpiresAt: openedAt.Add(time.Hour)})
+	}
+
+	if err := tracker.Open(context.Background(), tokenFor("tenant-tracked"), "Thunderbird/115.0"); err != nil {
+		t.Fatalf("expected the opted-in tenant's open to be recorded, got %v", err)
+	}
+	for name, token := range map[string]string{
+		"tenant without opt-in": tokenFor("tenant-default"),
+		"unknown tenant":        tokenFor("tenant-gone"),
+		"guessed token":         "bm90aGluZw.bm90aGluZw",
+	} {
+		if err := tracker.Open(context.Background(), token, "Thunderbird/115.0"); !errors.Is(err, ErrNotTracked) {
+			t.Fatalf("%s: expected ErrNotTracked, got %v", name, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	recorder.Run(ctx)
+	var opens []model.NotificationOpen
+	if err := database.Find(&opens).Error; err != nil {
+		t.Fatalf("load opens: %v", err)
+	}
+	if len(opens) != 1 || opens[0].TenantID != "tenant-tracked" || opens[0].UserAgentFamily != "thunderbird" || !opens[0].OpenedAt.Equal(openedAt) {
+		t.Fatalf("expected one recorded open, got %+v", opens)
+	}
+}
+
+func TestRecorderWritesInBatchesAndDropsWhenFull(t *testing.T) {
+	database := openTestDatabase(t)
+	recorder := newRecorder(database, slog.New(slog.NewTextHandler(io.Discard, nil)), 4, 2, time.Hour)
+	for index := 0; index < 6; index++ {
+		recorder.Record(model.NotificationOpen{TenantID: "tenant-a", NotificationID: "notification-1", OpenedAt: time.Now().UTC()})
+	}
+	if dropped := recorder.Dropped(); dropped != 2 {
+		t.Fatalf("expected opens beyond the buffer to be dropped, got %d", dropped)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		recorder.Run(ctx)
+	}()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		var count int64
+		database.Model(&model.NotificationOpen{}).Count(&count)
+		if count == 4 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected full batches to be written before the flush interval, got %d", count)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+}
@@ -0,0 +1,74 @@
+package opentracking
+
+import (
+	"html"
+	"strings"
+	"time"
+)
+
+// PixelPathPrefix is the HTTP path the pixel is served under; the token follows it.
+const PixelPathPrefix = "/t/"
+
+// PixelGIF is the transparent 1x1 GIF the pixel endpoint returns.
+var PixelGIF = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+// Pixels builds the tracking pixel for outgoing HTML emails.
+type Pixels struct {
+	signer   *Signer
+	baseURL  string
+	tokenTTL time.Duration
+}
+
+// NewPixels issues tokens valid for tokenTTL in pixel URLs under baseURL, the public
+// origin of the HTTP server.
+func NewPixels(signer *Signer, baseURL string, tokenTTL time.Duration) *Pixels {
+	return &Pixels{signer: signer, baseURL: strings.TrimRight(baseURL, "/"), tokenTTL: tokenTTL}
+}
+
+// URL returns the pixel URL for a notification sent at now.
+func (pixels *Pixels) URL(tenantID string, notificationID string, now time.Time) string {
+	token := pixels.signer.Sign(Claims{TenantID: tenantID, NotificationID: notificationID, ExpiresAt: now.Add(pixels.tokenTTL)})
+	return pixels.baseURL + PixelPathPrefix + token
+}
+
+// Inject adds an <img> loading pixelURL to an HTML body, before its closing </body> tag
+// when it has one and at the end otherwise.
+func Inject(body string, pixelURL string) string {
+	image := `<img src="` + html.EscapeString(pixelURL) + `" width="1" height="1" alt="" style="border:0">`
+	if closing := strings.LastIndex(strings.ToLower(body), "</body>"); closing >= 0 {
+		return body[:closing] + image + body[closing:]
+	}
+	return body + image
+}
+
+// UserAgentFamily reduces a User-Agent header to a coarse mail client or browser family,
+// so opens can be told apart by client without storing the full header.
+func UserAgentFamily(userAgent string) string {
+	lowered := strings.ToLower(userAgent)
+	for _, family := range []struct {
+		marker string
+		name   string
+	}{
+		{marker: "googleimageproxy", name: "gmail"},
+		{marker: "yahoomailproxy", name: "yahoo"},
+		{marker: "outlook", name: "outlook"},
+		{marker: "microsoft office", name: "outlook"},
+		{marker: "thunderbird", name: "thunderbird"},
+		{marker: "edg/", name: "edge"},
+		{marker: "firefox", name: "firefox"},
+		{marker: "chrome", name: "chrome"},
+		{marker: "applewebkit", name: "apple"},
+	} {
+		if strings.Contains(lowered, family.marker) {
+			return family.name
+		}
+	}
+	if lowered == "" {
+		return "unknown"
+	}
+	return "other"
+}
@@ -0,0 +1,99 @@
+package opentracking
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultBufferSize is how many opens can wait for a flush before new ones are dropped.
+	DefaultBufferSize = 4096
+	// DefaultBatchSize is how many opens are written per insert.
+	DefaultBatchSize = 200
+	// DefaultFlushInterval bounds how long an open waits in the buffer.
+	DefaultFlushInterval = 2 * time.Second
+)
+
+// Recorder buffers opens and writes them in batches so a burst of pixel loads, such as
+// a large campaign opened at once, costs a handful of inserts instead of one per load.
+type Recorder struct {
+	db            *gorm.DB
+	logger        *slog.Logger
+	opens         chan model.NotificationOpen
+	batchSize     int
+	flushInterval time.Duration
+	dropped       atomic.Int64
+}
+
+// NewRecorder returns a recorder using the default buffer, batch size and flush interval.
+func NewRecorder(db *gorm.DB, logger *slog.Logger) *Recorder {
+	return newRecorder(db, logger, DefaultBufferSize, DefaultBatchSize, DefaultFlushInterval)
+}
+
+func newRecorder(db *gorm.DB, logger *slog.Logger, bufferSize int, batchSize int, flushInterval time.Duration) *Recorder {
+	return &Recorder{
+		db:            db,
+		logger:        logger,
+		opens:         make(chan model.NotificationOpen, bufferSize),
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+	}
+}
+
+// Record queues an open without blocking. When the buffer is full the open is dropped
+// and counted: losing an open is better than slowing the pixel response.
+func (recorder *Recorder) Record(open model.NotificationOpen) {
+	select {
+	case recorder.opens <- open:
+	default:
+		recorder.dropped.Add(1)
+	}
+}
+
+// Dropped reports how many opens were discarded because the buffer was full.
+func (recorder *Recorder) Dropped() int64 {
+	return recorder.dropped.Load()
+}
+
+// Run writes queued opens until ctx is cancelled, then writes whatever is still queued.
+func (recorder *Recorder) Run(ctx context.Context) {
+	ticker := time.NewTicker(recorder.flushInterval)
+	defer ticker.Stop()
+	batch := make([]model.NotificationOpen, 0, recorder.batchSize)
+	for {
+		select {
+		case open := <-recorder.opens:
+			batch = append(batch, open)
+			if len(batch) >= recorder.batchSize {
+				batch = recorder.flush(context.Background(), batch)
+			}
+		case <-ticker.C:
+			batch = recorder.flush(context.Background(), batch)
+		case <-ctx.Done():
+			for {
+				select {
+				case open := <-recorder.opens:
+					batch = append(batch, open)
+				default:
+					recorder.flush(context.Background(), batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+func (recorder *Recorder) flush(ctx context.Context, batch []model.NotificationOpen) []model.NotificationOpen {
+	if len(batch) == 0 {
+		return batch
+	}
+	if err := model.SaveNotificationOpens(ctx, recorder.db, batch); err != nil {
+		recorder.logger.Error("open_tracking_flush_failed", "opens", len(batch), "error", err)
+	}
+	return batch[:0]
+}
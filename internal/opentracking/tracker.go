@@ -0,0 +1,53 @@
+package opentracking
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+)
+
+// ErrNotTracked reports a pixel load that is not recorded: the token is invalid or
+// expired, or the tenant has not opted in to open tracking.
+var ErrNotTracked = errors.New("opentracking: open not tracked")
+
+// TenantResolver looks up a tenant's runtime configuration.
+type TenantResolver interface {
+	ResolveByID(ctx context.Context, tenantID string) (tenant.RuntimeConfig, error)
+}
+
+// Tracker turns a pixel load into a recorded open.
+type Tracker struct {
+	signer   *Signer
+	tenants  TenantResolver
+	recorder *Recorder
+	now      func() time.Time
+}
+
+// NewTracker verifies pixel tokens with signer and queues opens on recorder.
+func NewTracker(signer *Signer, tenants TenantResolver, recorder *Recorder) *Tracker {
+	return &Tracker{signer: signer, tenants: tenants, recorder: recorder, now: time.Now}
+}
+
+// Open records a load of the pixel identified by token. Opens are checked against the
+// tenant's current setting, so a tenant that opts out stops recording immediately.
+func (tracker *Tracker) Open(ctx context.Context, token string, userAgent string) error {
+	now := tracker.now().UTC()
+	claims, err := tracker.signer.Verify(token, now)
+	if err != nil {
+		return ErrNotTracked
+	}
+	runtimeCfg, err := tracker.tenants.ResolveByID(ctx, claims.TenantID)
+	if err != nil || !runtimeCfg.Tenant.OpenTracking {
+		return ErrNotTracked
+	}
+	tracker.recorder.Record(model.NotificationOpen{
+		TenantID:        claims.TenantID,
+		NotificationID:  claims.NotificationID,
+		OpenedAt:        now,
+		UserAgentFamily: UserAgentFamily(userAgent),
+	})
+	return nil
+}
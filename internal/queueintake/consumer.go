@@ -0,0 +1,323 @@
+package queueintake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/tyemirov/pinguin/pkg/grpcapi"
+)
+
+const (
+	defaultConcurrency  = 4
+	defaultRequeueDelay = 30 * time.Second
+	defaultMaxDeliver   = 5
+	defaultAckWait      = time.Minute
+
+	// ContentTypeHeader selects how a message body is decoded; see DecodeRequest.
+	ContentTypeHeader = "Content-Type"
+	// ErrorHeader carries the reason a message was dead-lettered.
+	ErrorHeader = "Pinguin-Error"
+	// OriginalSubjectHeader carries the subject a dead-lettered message was consumed from.
+	OriginalSubjectHeader = "Pinguin-Original-Subject"
+)
+
+// ErrPermanent marks a handler failure that no redelivery can fix; the message goes
+// straight to the dead-letter subject.
+var ErrPermanent = errors.New("queue_intake.permanent")
+
+// Result reports how the handler disposed of an accepted message.
+type Result struct {
+	NotificationID string
+	Duplicate      bool
+}
+
+// Handler turns a decoded queued request into a notification.
+type Handler interface {
+	Handle(ctx context.Context, request *grpcapi.QueuedNotificationRequest) (Result, error)
+}
+
+// Config defines the JetStream consumer and its dependencies. The stream must exist;
+// the durable consumer is created or updated on Start.
+type Config struct {
+	URL               string
+	Stream            string
+	Subject           string
+	Durable           string
+	DeadLetterSubject string
+	Concurrency       int
+	RequeueDelay      time.Duration
+	MaxDeliver        int
+	AckWait           time.Duration
+	Username          string
+	Password          string
+	Token             string
+	CredentialsFile   string
+	Handler           Handler
+	Logger            *slog.Logger
+}
+
+// Stats counts the messages the consumer has seen since it started.
+type Stats struct {
+	Received       int64
+	Accepted       int64
+	Duplicates     int64
+	DeadLettered   int64
+	Requeued       int64
+	LastReceivedAt time.Time
+}
+
+// Consumer pulls send requests from a JetStream durable consumer and hands them to
+// the Handler.
+type Consumer struct {
+	config       Config
+	logger       *slog.Logger
+	connection   *nats.Conn
+	stream       jetstream.JetStream
+	messages     jetstream.MessagesContext
+	cancel       context.CancelFunc
+	workers      sync.WaitGroup
+	received     atomic.Int64
+	accepted     atomic.Int64
+	duplicates   atomic.Int64
+	deadLettered atomic.Int64
+	requeued     atomic.Int64
+	lastReceived atomic.Int64
+}
+
+// NewConsumer validates the configuration and applies defaults.
+func NewConsumer(cfg Config) (*Consumer, error) {
+	if strings.TrimSpace(cfg.URL) == "" {
+		return nil, errors.New("queue intake: url is required")
+	}
+	if strings.TrimSpace(cfg.Stream) == "" {
+		return nil, errors.New("queue intake: stream is required")
+	}
+	if strings.TrimSpace(cfg.Subject) == "" {
+		return nil, errors.New("queue intake: subject is required")
+	}
+	if strings.TrimSpace(cfg.Durable) == "" {
+		return nil, errors.New("queue intake: durable is required")
+	}
+	if strings.TrimSpace(cfg.DeadLetterSubject) == "" {
+		return nil, errors.New("queue intake: dead letter subject is required")
+	}
+	if cfg.Handler == nil {
+		return nil, errors.New("queue intake: handler is required")
+	}
+	if cfg.Logger == nil {
+		return nil, errors.New("queue intake: logger is required")
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = defaultConcurrency
+	}
+	if cfg.RequeueDelay <= 0 {
+		cfg.RequeueDelay = defaultRequeueDelay
+	}
+	if cfg.MaxDeliver <= 0 {
+		cfg.MaxDeliver = defaultMaxDeliver
+	}
+	if cfg.AckWait <= 0 {
+		cfg.AckWait = defaultAckWait
+	}
+	return &Consumer{config: cfg, logger: cfg.Logger}, nil
+}
+
+// Start connects, binds the durable consumer and starts the workers. It returns once
+// consumption has begun; Stop ends it.
+func (consumer *Consumer) Start(ctx context.Context) error {
+	connection, err := nats.Connect(consumer.config.URL, consumer.connectOptions()...)
+	if err != nil {
+		return fmt.Errorf("queue intake: connect: %w", err)
+	}
+	stream, err := jetstream.New(connection)
+	if err != nil {
+		connection.Close()
+		return fmt.Errorf("queue intake: jetstream: %w", err)
+	}
+	boundStream, err := stream.Stream(ctx, consumer.config.Stream)
+	if err != nil {
+		connection.Close()
+		return fmt.Errorf("queue intake: stream %q: %w", consumer.config.Stream, err)
+	}
+	durable, err := boundStream.CreateOrUpdateConsumer(ctx, jetstream.ConsumerConfig{
+		Durable:       consumer.config.Durable,
+		FilterSubject: consumer.config.Subject,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		MaxDeliver:    consumer.config.MaxDeliver,
+		AckWait:       consumer.config.AckWait,
+	})
+	if err != nil {
+		connection.Close()
+		return fmt.Errorf("queue intake: consumer %q: %w", consumer.config.Durable, err)
+	}
+	messages, err := durable.Messages(jetstream.PullMaxMessages(consumer.config.Concurrency))
+	if err != nil {
+		connection.Close()
+		return fmt.Errorf("queue intake: messages: %w", err)
+	}
+	consumer.connection = connection
+	consumer.stream = stream
+	consumer.messages = messages
+	workerCtx, cancel := context.WithCancel(context.Background())
+	consumer.cancel = cancel
+	for range consumer.config.Concurrency {
+		consumer.workers.Add(1)
+		go consumer.work(workerCtx)
+	}
+	consumer.logger.Info("queue_intake_started", "stream", consumer.config.Stream, "subject", consumer.config.Subject, "durable", consumer.config.Durable, "concurrency", consumer.config.Concurrency)
+	return nil
+}
+
+// Stop stops pulling, lets in-flight messages finish until ctx ends, then closes the
+// connection. Messages still in flight at that point are redelivered after AckWait.
+func (consumer *Consumer) Stop(ctx context.Context) error {
+	if consumer.messages == nil {
+		return nil
+	}
+	consumer.messages.Drain()
+	finished := make(chan struct{})
+	go func() {
+		consumer.workers.Wait()
+		close(finished)
+	}()
+	var err error
+	select {
+	case <-finished:
+	case <-ctx.Done():
+		err = fmt.Errorf("queue intake: stop: %w", ctx.Err())
+	}
+	consumer.cancel()
+	consumer.connection.Close()
+	stats := consumer.Stats()
+	consumer.logger.Info("queue_intake_stopped", "received", stats.Received, "accepted", stats.Accepted, "duplicates", stats.Duplicates, "dead_lettered", stats.DeadLettered, "requeued", stats.Requeued)
+	return err
+}
+
+// Stats returns the counters since Start.
+func (consumer *Consumer) Stats() Stats {
+	stats := Stats{
+		Received:     consumer.received.Load(),
+		Accepted:     consumer.accepted.Load(),
+		Duplicates:   consumer.duplicates.Load(),
+		DeadLettered: consumer.deadLettered.Load(),
+		Requeued:     consumer.requeued.Load(),
+	}
+	if lastReceived := consumer.lastReceived.Load(); lastReceived > 0 {
+		stats.LastReceivedAt = time.Unix(0, lastReceived).UTC()
+	}
+	return stats
+}
+
+func (consumer *Consumer) connectOptions() []nats.Option {
+	options := []nats.Option{nats.Name("pinguin-queue-intake"), nats.MaxReconnects(-1)}
+	if consumer.config.Username != "" {
+		options = append(options, nats.UserInfo(consumer.config.Username, consumer.config.Password))
+	}
+	if consumer.config.Token != "" {
+		options = append(options, nats.Token(consumer.config.Token))
+	}
+	if consumer.config.CredentialsFile != "" {
+		options = append(options, nats.UserCredentials(consumer.config.CredentialsFile))
+	}
+	return options
+}
+
+func (consumer *Consumer) work(ctx context.Context) {
+	defer consumer.workers.Done()
+	for {
+		message, err := consumer.messages.Next()
+		if err != nil {
+			if errors.Is(err, jetstream.ErrMsgIteratorClosed) {
+				return
+			}
+			consumer.logger.Warn("queue_intake_fetch_failed", "error", err)
+			continue
+		}
+		consumer.process(ctx, message)
+	}
+}
+
+func (consumer *Consumer) process(ctx context.Context, message jetstream.Msg) {
+	consumer.received.Add(1)
+	consumer.lastReceived.Store(time.Now().UnixNano())
+	request, err := DecodeRequest(message.Data(), message.Headers().Get(ContentTypeHeader))
+	if err == nil {
+		handleCtx, cancel := context.WithTimeout(ctx, consumer.config.AckWait)
+		var result Result
+		result, err = consumer.config.Handler.Handle(handleCtx, request)
+		cancel()
+		if err == nil {
+			consumer.acknowledge(message, request, result)
+			return
+		}
+	}
+	if errors.Is(err, ErrPermanent) || consumer.lastDelivery(message) {
+		consumer.deadLetter(ctx, message, err)
+		return
+	}
+	consumer.logger.Warn("queue_intake_requeued", "subject", message.Subject(), "error", err)
+	consumer.requeued.Add(1)
+	if nakErr := message.NakWithDelay(consumer.config.RequeueDelay); nakErr != nil {
+		consumer.logger.Error("queue_intake_nak_failed", "error", nakErr)
+	}
+}
+
+func (consumer *Consumer) acknowledge(message jetstream.Msg, request *grpcapi.QueuedNotificationRequest, result Result) {
+	if result.Duplicate {
+		consumer.duplicates.Add(1)
+	} else {
+		consumer.accepted.Add(1)
+	}
+	consumer.logger.Info("queue_intake_accepted", "tenant_id", request.GetRequest().GetTenantId(), "notification_id", result.NotificationID, "duplicate", result.Duplicate)
+	if err := message.Ack(); err != nil {
+		// The notification is stored; a redelivery resolves to the same key and is acked as a duplicate.
+		consumer.logger.Error("queue_intake_ack_failed", "notification_id", result.NotificationID, "error", err)
+	}
+}
+
+func (consumer *Consumer) lastDelivery(message jetstream.Msg) bool {
+	metadata, err := message.Metadata()
+	if err != nil {
+		return false
+	}
+	return metadata.NumDelivered >= uint64(consumer.config.MaxDeliver)
+}
+
+// deadLetter copies the message to the dead-letter subject and terminates it. When the
+// copy fails the message is requeued instead, so nothing is dropped.
+func (consumer *Consumer) deadLetter(ctx context.Context, message jetstream.Msg, cause error) {
+	deadLetterMessage := nats.NewMsg(consumer.config.DeadLetterSubject)
+	deadLetterMessage.Data = message.Data()
+	for key, values := range message.Headers() {
+		if strings.HasPrefix(key, "Nats-") {
+			// Server-assigned headers such as Nats-Msg-Id would deduplicate or misroute the copy.
+			continue
+		}
+		for _, value := range values {
+			deadLetterMessage.Header.Add(key, value)
+		}
+	}
+	deadLetterMessage.Header.Set(ErrorHeader, cause.Error())
+	deadLetterMessage.Header.Set(OriginalSubjectHeader, message.Subject())
+	if _, err := consumer.stream.PublishMsg(ctx, deadLetterMessage); err != nil {
+		consumer.logger.Error("queue_intake_dead_letter_failed", "subject", message.Subject(), "error", err)
+		consumer.requeued.Add(1)
+		if nakErr := message.NakWithDelay(consumer.config.RequeueDelay); nakErr != nil {
+			consumer.logger.Error("queue_intake_nak_failed", "error", nakErr)
+		}
+		return
+	}
+	consumer.deadLettered.Add(1)
+	consumer.logger.Warn("queue_intake_dead_lettered", "subject", message.Subject(), "error", cause)
+	if err := message.Term(); err != nil {
+		consumer.logger.Error("queue_intake_term_failed", "error", err)
+	}
+}
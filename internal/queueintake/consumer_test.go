@@ -0,0 +1,207 @@
+package queueintake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/tyemirov/pinguin/pkg/grpcapi"
+)
+
+const (
+	testStream            = "PINGUIN"
+	testSubject           = "pinguin.send"
+	testDeadLetterSubject = "pinguin.dead"
+)
+
+type scriptedHandler struct {
+	mutex     sync.Mutex
+	outcomes  map[string][]error
+	calls     map[string]int
+	successes map[string]int
+}
+
+func (handler *scriptedHandler) Handle(_ context.Context, request *grpcapi.QueuedNotificationRequest) (Result, error) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+	key := request.GetIdempotencyKey()
+	handler.calls[key]++
+	if outcomes := handler.outcomes[key]; len(outcomes) > 0 {
+		handler.outcomes[key] = outcomes[1:]
+		if outcomes[0] != nil {
+			return Result{}, outcomes[0]
+		}
+	}
+	handler.successes[key]++
+	return Result{NotificationID: "notif-" + key, Duplicate: handler.successes[key] > 1}, nil
+}
+
+func TestConsumerAcksRequeuesAndDeadLetters(t *testing.T) {
+	url := startJetStreamServer(t)
+	connection, err := nats.Connect(url)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer connection.Close()
+	deadLetters, err := connection.SubscribeSync(testDeadLetterSubject)
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	handler := &scriptedHandler{
+		outcomes: map[string][]error{
+			"transient-once": {errors.New("database busy")},
+			"always-failing": {errors.New("database busy"), errors.New("database busy")},
+			"rejected":       {fmt.Errorf("%w: invalid recipient", ErrPermanent)},
+		},
+		calls:     map[string]int{},
+		successes: map[string]int{},
+	}
+	consumer, err := NewConsumer(Config{
+		URL:               url,
+		Stream:            testStream,
+		Subject:           testSubject,
+		Durable:           "pinguin-test",
+		DeadLetterSubject: testDeadLetterSubject,
+		Concurrency:       2,
+		RequeueDelay:      10 * time.Millisecond,
+		MaxDeliver:        2,
+		AckWait:           5 * time.Second,
+		Handler:           handler,
+		Logger:            slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	if err != nil {
+		t.Fatalf("new consumer: %v", err)
+	}
+	if err := consumer.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	for _, payload := range []string{
+		`{"idempotencyKey":"ok","request":{"tenantId":"tenant-a"}}`,
+		`{"idempotencyKey":"ok","request":{"tenantId":"tenant-a"}}`,
+		`{"idempotencyKey":"transient-once","request":{"tenantId":"tenant-a"}}`,
+		`{"idempotencyKey":"always-failing","request":{"tenantId":"tenant-a"}}`,
+		`{"idempotencyKey":"rejected","request":{"tenantId":"tenant-a"}}`,
+		`not json`,
+	} {
+		if err := connection.Publish(testSubject, []byte(payload)); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+
+	deadLettered := map[string]string{}
+	for range 3 {
+		message, err := deadLetters.NextMsg(5 * time.Second)
+		if err != nil {
+			t.Fatalf("expected a dead-lettered message: %v", err)
+		}
+		if message.Header.Get(OriginalSubjectHeader) != testSubject || message.Header.Get(ErrorHeader) == "" {
+			t.Fatalf("unexpected dead-letter headers %v", message.Header)
+		}
+		deadLettered[string(message.Data)] = message.Header.Get(ErrorHeader)
+	}
+	for _, payload := range []string{
+		`{"idempotencyKey":"always-failing","request":{"tenantId":"tenant-a"}}`,
+		`{"idempotencyKey":"rejected","request":{"tenantId":"tenant-a"}}`,
+		`not json`,
+	} {
+		if _, found := deadLettered[payload]; !found {
+			t.Fatalf("expected %s to be dead-lettered, got %v", payload, deadLettered)
+		}
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := consumer.Stop(stopCtx); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	stats := consumer.Stats()
+	if stats.Received != 8 || stats.Accepted != 2 || stats.Duplicates != 1 || stats.DeadLettered != 3 || stats.Requeued != 2 || stats.LastReceivedAt.IsZero() {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+	if handler.calls["transient-once"] != 2 || handler.calls["always-failing"] != 2 || handler.calls["rejected"] != 1 {
+		t.Fatalf("unexpected handler calls %v", handler.calls)
+	}
+}
+
+func TestConsumerStartFailsWithoutStream(t *testing.T) {
+	url := startJetStreamServer(t)
+	consumer, err := NewConsumer(Config{
+		URL:               url,
+		Stream:            "MISSING",
+		Subject:           testSubject,
+		Durable:           "pinguin-test",
+		DeadLetterSubject: testDeadLetterSubject,
+		Handler:           &scriptedHandler{},
+		Logger:            slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	if err != nil {
+		t.Fatalf("new consumer: %v", err)
+	}
+	if err := consumer.Start(context.Background()); !errors.Is(err, jetstream.ErrStreamNotFound) {
+		t.Fatalf("expected a missing stream error, got %v", err)
+	}
+	if err := consumer.Stop(context.Background()); err != nil {
+		t.Fatalf("expected stopping an unstarted consumer to succeed, got %v", err)
+	}
+}
+
+func TestNewConsumerRequiresConfiguration(t *testing.T) {
+	if _, err := NewConsumer(Config{}); err == nil {
+		t.Fatalf("expected missing url to be rejected")
+	}
+	consumer, err := NewConsumer(Config{
+		URL:               "nats://127.0.0.1:4222",
+		Stream:            testStream,
+		Subject:           testSubject,
+		Durable:           "pinguin-test",
+		DeadLetterSubject: testDeadLetterSubject,
+		Handler:           &scriptedHandler{},
+		Logger:            slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	if err != nil {
+		t.Fatalf("new consumer: %v", err)
+	}
+	if consumer.config.Concurrency != defaultConcurrency || consumer.config.MaxDeliver != defaultMaxDeliver || consumer.config.AckWait != defaultAckWait || consumer.config.RequeueDelay != defaultRequeueDelay {
+		t.Fatalf("expected defaults, got %+v", consumer.config)
+	}
+}
+
+// startJetStreamServer runs an in-process NATS server with a stream that captures both
+// the intake and dead-letter subjects.
+func startJetStreamServer(t *testing.T) string {
+	t.Helper()
+	server, err := natsserver.NewServer(&natsserver.Options{Host: "127.0.0.1", Port: -1, JetStream: true, StoreDir: t.TempDir(), NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatalf("nats server: %v", err)
+	}
+	go server.Start()
+	t.Cleanup(server.Shutdown)
+	if !server.ReadyForConnections(5 * time.Second) {
+		t.Fatalf("nats server not ready")
+	}
+	connection, err := nats.Connect(server.ClientURL())
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer connection.Close()
+	stream, err := jetstream.New(connection)
+	if err != nil {
+		t.Fatalf("jetstream: %v", err)
+	}
+	if _, err := stream.CreateStream(context.Background(), jetstream.StreamConfig{Name: testStream, Subjects: []string{testSubject, testDeadLetterSubject}}); err != nil {
+		t.Fatalf("create stream: %v", err)
+	}
+	return server.ClientURL()
+}
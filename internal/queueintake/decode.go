@@ -0,0 +1,49 @@
+package queueintake
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tyemirov/pinguin/pkg/grpcapi"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// ContentTypeProtobuf selects the binary protobuf encoding; any other content type is
+	// decoded as protobuf JSON.
+	ContentTypeProtobuf = "application/x-protobuf"
+	// MaxIdempotencyKeyLength bounds the characters of a message's idempotency key.
+	MaxIdempotencyKeyLength = 200
+)
+
+// DecodeRequest parses a queued send request and checks the envelope fields. Every
+// error it returns wraps ErrPermanent, since redelivering the same bytes cannot help.
+func DecodeRequest(data []byte, contentType string) (*grpcapi.QueuedNotificationRequest, error) {
+	request := &grpcapi.QueuedNotificationRequest{}
+	var err error
+	if strings.EqualFold(strings.TrimSpace(contentType), ContentTypeProtobuf) {
+		err = proto.Unmarshal(data, request)
+	} else {
+		err = protojson.Unmarshal(data, request)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: decode: %v", ErrPermanent, err)
+	}
+	idempotencyKey := strings.TrimSpace(request.GetIdempotencyKey())
+	if idempotencyKey == "" {
+		return nil, fmt.Errorf("%w: idempotency_key is required", ErrPermanent)
+	}
+	if utf8.RuneCountInString(idempotencyKey) > MaxIdempotencyKeyLength {
+		return nil, fmt.Errorf("%w: idempotency_key must be at most %d characters", ErrPermanent, MaxIdempotencyKeyLength)
+	}
+	request.IdempotencyKey = idempotencyKey
+	if request.GetRequest() == nil {
+		return nil, fmt.Errorf("%w: request is required", ErrPermanent)
+	}
+	if strings.TrimSpace(request.GetRequest().GetTenantId()) == "" {
+		return nil, fmt.Errorf("%w: request.tenant_id is required", ErrPermanent)
+	}
+	return request, nil
+}
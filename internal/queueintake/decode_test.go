@@ -0,0 +1,48 @@
+package queueintake
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tyemirov/pinguin/pkg/grpcapi"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDecodeRequestAcceptsJSONAndProtobuf(t *testing.T) {
+	request, err := DecodeRequest([]byte(`{"idempotencyKey":" order-1 ","request":{"tenantId":"tenant-a","notificationType":"EMAIL","recipient":"user@example.com","subject":"Hi","message":"Hello"}}`), "")
+	if err != nil {
+		t.Fatalf("decode json: %v", err)
+	}
+	if request.GetIdempotencyKey() != "order-1" || request.GetRequest().GetTenantId() != "tenant-a" || request.GetRequest().GetNotificationType() != grpcapi.NotificationType_EMAIL {
+		t.Fatalf("unexpected decoded request %+v", request)
+	}
+
+	encoded, err := proto.Marshal(request)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	decoded, err := DecodeRequest(encoded, "Application/X-Protobuf")
+	if err != nil {
+		t.Fatalf("decode protobuf: %v", err)
+	}
+	if !proto.Equal(decoded, request) {
+		t.Fatalf("expected the protobuf round trip to match, got %+v", decoded)
+	}
+}
+
+func TestDecodeRequestRejectsInvalidEnvelopesAsPermanent(t *testing.T) {
+	for name, payload := range map[string]string{
+		"malformed":        `{"idempotencyKey":`,
+		"missing key":      `{"request":{"tenantId":"tenant-a"}}`,
+		"long key":         `{"idempotencyKey":"` + strings.Repeat("k", MaxIdempotencyKeyLength+1) + `","request":{"tenantId":"tenant-a"}}`,
+		"missing request":  `{"idempotencyKey":"order-1"}`,
+		"missing tenant":   `{"idempotencyKey":"order-1","request":{"recipient":"user@example.com"}}`,
+		"unknown field":    `{"idempotencyKey":"order-1","unexpected":true}`,
+		"protobuf as json": "\x0a\x07order-1",
+	} {
+		if _, err := DecodeRequest([]byte(payload), ""); !errors.Is(err, ErrPermanent) {
+			t.Fatalf("%s: expected a permanent error, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package savedfilter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+)
+
+const (
+	// DefaultMaxFiltersPerOwner caps how many filters one user may own within a tenant.
+	DefaultMaxFiltersPerOwner = 50
+	maxFilterNameLength       = 100
+	statusSeparator           = ","
+)
+
+var (
+	// ErrInvalidFilter indicates a filter name or list parameters the list endpoint would reject.
+	ErrInvalidFilter = errors.New("saved_filter.invalid")
+	// ErrFilterNotFound indicates the filter does not exist or is not visible to the caller.
+	ErrFilterNotFound = errors.New("saved_filter.not_found")
+	// ErrFilterExists indicates the owner already has a filter with the same name.
+	ErrFilterExists = errors.New("saved_filter.exists")
+	// ErrFilterLimitReached indicates the owner already holds the maximum number of filters.
+	ErrFilterLimitReached = errors.New("saved_filter.limit_reached")
+	// ErrFilterReadOnly indicates a shared filter the caller can use but does not own.
+	ErrFilterReadOnly = errors.New("saved_filter.read_only")
+	// ErrSharingNotAllowed indicates a non-admin tried to share a filter with the tenant.
+	ErrSharingNotAllowed = errors.New("saved_filter.sharing_not_allowed")
+)
+
+// SavedFilter stores a named notification list filter owned by one user within a tenant.
+type SavedFilter struct {
+	ID          string `gorm:"primaryKey"`
+	TenantID    string `gorm:"index:idx_saved_filter_owner,priority:1;not null"`
+	OwnerEmail  string `gorm:"index:idx_saved_filter_owner,priority:2;not null"`
+	Name        string `gorm:"not null"`
+	Statuses    string
+	SearchQuery string
+	Shared      bool `gorm:"index"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// PublicFilter is the saved filter shape exposed to HTTP callers.
+type PublicFilter struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Statuses    []string  `json:"status"`
+	SearchQuery string    `json:"q"`
+	Shared      bool      `json:"shared"`
+	OwnerEmail  string    `json:"owner_email"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// AccessScope identifies the caller: filters are visible to their owner and, when
+// shared, to everyone in the tenant. Admin marks an admin-role session, which may share.
+type AccessScope struct {
+	TenantID   string
+	OwnerEmail string
+	Admin      bool
+}
+
+// Definition is a validated filter name plus the list parameters it applies.
+type Definition struct {
+	name        string
+	statuses    []model.NotificationStatus
+	searchQuery model.NotificationSearchQuery
+	shared      bool
+}
+
+// NewDefinition validates a filter against the list endpoint's parameters: status values
+// must be known notification statuses and q must be a valid search query.
+func NewDefinition(name string, statuses []string, searchQuery string, shared bool) (Definition, error) {
+	normalizedName := strings.TrimSpace(name)
+	if normalizedName == "" {
+		return Definition{}, fmt.Errorf("%w: name is required", ErrInvalidFilter)
+	}
+	if len([]rune(normalizedName)) > maxFilterNameLength {
+		return Definition{}, fmt.Errorf("%w: name must be %d characters or fewer", ErrInvalidFilter, maxFilterNameLength)
+	}
+	parsedStatuses, statusErr := parseStatuses(statuses)
+	if statusErr != nil {
+		return Definition{}, statusErr
+	}
+	parsedQuery, queryErr := model.NewNotificationSearchQuery(searchQuery)
+	if queryErr != nil {
+		return Definition{}, fmt.Errorf("%w: %w", ErrInvalidFilter, queryErr)
+	}
+	return Definition{name: normalizedName, statuses: parsedStatuses, searchQuery: parsedQuery, shared: shared}, nil
+}
+
+// ListFilters returns the stored parameters as list endpoint filters, re-validating them
+// so a row edited outside the API cannot widen a query.
+func (filter SavedFilter) ListFilters() (model.NotificationListFilters, error) {
+	statuses, statusErr := parseStatuses(splitStatuses(filter.Statuses))
+	if statusErr != nil {
+		return model.NotificationListFilters{}, statusErr
+	}
+	searchQuery, queryErr := model.NewNotificationSearchQuery(filter.SearchQuery)
+	if queryErr != nil {
+		return model.NotificationListFilters{}, fmt.Errorf("%w: %w", ErrInvalidFilter, queryErr)
+	}
+	return model.NotificationListFilters{Statuses: statuses, SearchQuery: searchQuery}, nil
+}
+
+func parseStatuses(values []string) ([]model.NotificationStatus, error) {
+	var statuses []model.NotificationStatus
+	seen := make(map[model.NotificationStatus]struct{}, len(values))
+	for _, raw := range values {
+		trimmed := strings.TrimSpace(raw)
+		if trimmed == "" {
+			continue
+		}
+		status := model.CanonicalStatus(model.NotificationStatus(strings.ToLower(trimmed)))
+		if status == "" {
+			return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidFilter, trimmed)
+		}
+		if _, duplicate := seen[status]; duplicate {
+			continue
+		}
+		seen[status] = struct{}{}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func joinStatuses(statuses []model.NotificationStatus) string {
+	values := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		values = append(values, string(status))
+	}
+	return strings.Join(values, statusSeparator)
+}
+
+func splitStatuses(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, statusSeparator)
+}
+
+func publicFilter(record SavedFilter) PublicFilter {
+	statuses := splitStatuses(record.Statuses)
+	if statuses == nil {
+		statuses = []string{}
+	}
+	return PublicFilter{
+		ID:          record.ID,
+		Name:        record.Name,
+		Statuses:    statuses,
+		SearchQuery: record.SearchQuery,
+		Shared:      record.Shared,
+		OwnerEmail:  record.OwnerEmail,
+		CreatedAt:   record.CreatedAt,
+		UpdatedAt:   record.UpdatedAt,
+	}
+}
+
+func normalizeOwnerEmail(ownerEmail string) string {
+	return strings.ToLower(strings.TrimSpace(ownerEmail))
+}
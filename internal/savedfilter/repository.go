@@ -0,0 +1,220 @@
+package savedfilter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyemirov/pinguin/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	filterIDColumn         = "id"
+	filterNameColumn       = "name"
+	filterOwnerEmailColumn = "owner_email"
+	filterSharedColumn     = "shared"
+	filterTenantIDColumn   = "tenant_id"
+)
+
+// Repository stores saved notification filters.
+type Repository struct {
+	db          *gorm.DB
+	maxPerOwner int
+	clockFunc   func() time.Time
+}
+
+// NewRepository constructs a saved filter repository with the default per-owner limit.
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		db:          db,
+		maxPerOwner: DefaultMaxFiltersPerOwner,
+		clockFunc:   func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// WithOwnerLimit overrides how many filters one user may own per tenant; values
+// below one keep the default.
+func (repository *Repository) WithOwnerLimit(limit int) *Repository {
+	if limit > 0 {
+		repository.maxPerOwner = limit
+	}
+	return repository
+}
+
+// List returns the caller's own filters plus filters shared within the tenant, by name.
+func (repository *Repository) List(ctx context.Context, scope AccessScope) ([]PublicFilter, error) {
+	if scopeErr := requireScope(scope); scopeErr != nil {
+		return nil, scopeErr
+	}
+	var records []SavedFilter
+	if err := repository.db.WithContext(ctx).
+		Where(visibleToScope(scope)).
+		Order(clause.OrderBy{Columns: []clause.OrderByColumn{
+			{Column: clause.Column{Name: filterNameColumn}},
+			{Column: clause.Column{Name: filterIDColumn}},
+		}}).
+		Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("saved filter list: %w", err)
+	}
+	result := make([]PublicFilter, 0, len(records))
+	for _, record := range records {
+		result = append(result, publicFilter(record))
+	}
+	return result, nil
+}
+
+// Create stores a new filter owned by the caller. Only admin-role callers may share it.
+func (repository *Repository) Create(ctx context.Context, scope AccessScope, definition Definition) (PublicFilter, error) {
+	if scopeErr := requireScope(scope); scopeErr != nil {
+		return PublicFilter{}, scopeErr
+	}
+	if definition.shared && !scope.Admin {
+		return PublicFilter{}, ErrSharingNotAllowed
+	}
+	ownerEmail := normalizeOwnerEmail(scope.OwnerEmail)
+	var owned int64
+	if err := repository.db.WithContext(ctx).
+		Model(&SavedFilter{}).
+		Where(&SavedFilter{TenantID: scope.TenantID, OwnerEmail: ownerEmail}).
+		Count(&owned).Error; err != nil {
+		return PublicFilter{}, fmt.Errorf("saved filter create: count: %w", err)
+	}
+	if owned >= int64(repository.maxPerOwner) {
+		return PublicFilter{}, fmt.Errorf("%w: at most %d filters per user", ErrFilterLimitReached, repository.maxPerOwner)
+	}
+	if existsErr := repository.requireUniqueName(ctx, scope, definition.name, ""); existsErr != nil {
+		return PublicFilter{}, existsErr
+	}
+	now := repository.clockFunc()
+	record := SavedFilter{
+		ID:          uuid.NewString(),
+		TenantID:    scope.TenantID,
+		OwnerEmail:  ownerEmail,
+		Name:        definition.name,
+		Statuses:    joinStatuses(definition.statuses),
+		SearchQuery: definition.searchQuery.Value(),
+		Shared:      definition.shared,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := repository.db.WithContext(ctx).Create(&record).Error; err != nil {
+		return PublicFilter{}, fmt.Errorf("saved filter create: %w", err)
+	}
+	return publicFilter(record), nil
+}
+
+// Update replaces the name and parameters of a filter the caller owns. Shared filters
+// owned by someone else are read-only.
+func (repository *Repository) Update(ctx context.Context, scope AccessScope, filterID string, definition Definition) (PublicFilter, error) {
+	if definition.shared && !scope.Admin {
+		return PublicFilter{}, ErrSharingNotAllowed
+	}
+	record, fetchErr := repository.requireOwnedFilter(ctx, scope, filterID)
+	if fetchErr != nil {
+		return PublicFilter{}, fetchErr
+	}
+	if existsErr := repository.requireUniqueName(ctx, scope, definition.name, record.ID); existsErr != nil {
+		return PublicFilter{}, existsErr
+	}
+	record.Name = definition.name
+	record.Statuses = joinStatuses(definition.statuses)
+	record.SearchQuery = definition.searchQuery.Value()
+	record.Shared = definition.shared
+	record.UpdatedAt = repository.clockFunc()
+	if err := repository.db.WithContext(ctx).Save(&record).Error; err != nil {
+		return PublicFilter{}, fmt.Errorf("saved filter update: %w", err)
+	}
+	return publicFilter(record), nil
+}
+
+// Delete removes a filter the caller owns.
+func (repository *Repository) Delete(ctx context.Context, scope AccessScope, filterID string) error {
+	record, fetchErr := repository.requireOwnedFilter(ctx, scope, filterID)
+	if fetchErr != nil {
+		return fetchErr
+	}
+	if err := repository.db.WithContext(ctx).Delete(&record).Error; err != nil {
+		return fmt.Errorf("saved filter delete: %w", err)
+	}
+	return nil
+}
+
+// ListFilters loads a filter visible to the caller and returns it as list endpoint filters.
+func (repository *Repository) ListFilters(ctx context.Context, scope AccessScope, filterID string) (model.NotificationListFilters, error) {
+	record, fetchErr := repository.requireVisibleFilter(ctx, scope, filterID)
+	if fetchErr != nil {
+		return model.NotificationListFilters{}, fetchErr
+	}
+	return record.ListFilters()
+}
+
+func (repository *Repository) requireVisibleFilter(ctx context.Context, scope AccessScope, filterID string) (SavedFilter, error) {
+	if scopeErr := requireScope(scope); scopeErr != nil {
+		return SavedFilter{}, scopeErr
+	}
+	if filterID == "" {
+		return SavedFilter{}, ErrFilterNotFound
+	}
+	var record SavedFilter
+	err := repository.db.WithContext(ctx).
+		Where(&SavedFilter{ID: filterID}).
+		Where(visibleToScope(scope)).
+		First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return SavedFilter{}, ErrFilterNotFound
+	}
+	if err != nil {
+		return SavedFilter{}, fmt.Errorf("saved filter fetch: %w", err)
+	}
+	return record, nil
+}
+
+func (repository *Repository) requireOwnedFilter(ctx context.Context, scope AccessScope, filterID string) (SavedFilter, error) {
+	record, fetchErr := repository.requireVisibleFilter(ctx, scope, filterID)
+	if fetchErr != nil {
+		return SavedFilter{}, fetchErr
+	}
+	if record.OwnerEmail != normalizeOwnerEmail(scope.OwnerEmail) {
+		return SavedFilter{}, ErrFilterReadOnly
+	}
+	return record, nil
+}
+
+func (repository *Repository) requireUniqueName(ctx context.Context, scope AccessScope, name string, exceptID string) error {
+	var existing SavedFilter
+	err := repository.db.WithContext(ctx).
+		Where(&SavedFilter{TenantID: scope.TenantID, OwnerEmail: normalizeOwnerEmail(scope.OwnerEmail), Name: name}).
+		First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("saved filter name check: %w", err)
+	}
+	if existing.ID == exceptID {
+		return nil
+	}
+	return ErrFilterExists
+}
+
+// visibleToScope matches the tenant's filters the caller owns or that are shared.
+func visibleToScope(scope AccessScope) clause.Expression {
+	return clause.And(
+		clause.Eq{Column: clause.Column{Name: filterTenantIDColumn}, Value: scope.TenantID},
+		clause.Or(
+			clause.Eq{Column: clause.Column{Name: filterOwnerEmailColumn}, Value: normalizeOwnerEmail(scope.OwnerEmail)},
+			clause.Eq{Column: clause.Column{Name: filterSharedColumn}, Value: true},
+		),
+	)
+}
+
+func requireScope(scope AccessScope) error {
+	if scope.TenantID == "" || normalizeOwnerEmail(scope.OwnerEmail) == "" {
+		return errors.New("saved filter: tenant and owner are required")
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package savedfilter
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/tyemirov/pinguin/internal/model"
+	"gorm.io/gorm"
+)
+
+const testTenantID = "tenant-test"
+
+var (
+	ownerScope = AccessScope{TenantID: testTenantID, OwnerEmail: "Owner@Example.com"}
+	adminScope = AccessScope{TenantID: testTenantID, OwnerEmail: "admin@example.com", Admin: true}
+	otherScope = AccessScope{TenantID: testTenantID, OwnerEmail: "other@example.com"}
+)
+
+func newFilterRepository(t *testing.T) (*Repository, *gorm.DB) {
+	t.Helper()
+	database, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "filters.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := database.AutoMigrate(&SavedFilter{}); err != nil {
+		t.Fatalf("migrate sqlite: %v", err)
+	}
+	return NewRepository(database), database
+}
+
+func mustDefinition(t *testing.T, name string, statuses []string, searchQuery string, shared bool) Definition {
+	t.Helper()
+	definition, err := NewDefinition(name, statuses, searchQuery, shared)
+	if err != nil {
+		t.Fatalf("definition: %v", err)
+	}
+	return definition
+}
+
+func TestNewDefinitionValidatesListParameters(t *testing.T) {
+	definition, err := NewDefinition("  Errored SMS ", []string{"Errored", "errored", " ", "queued"}, " sms ", false)
+	if err != nil {
+		t.Fatalf("definition: %v", err)
+	}
+	if definition.name != "Errored SMS" || joinStatuses(definition.statuses) != "errored,queued" || definition.searchQuery.Value() != "sms" {
+		t.Fatalf("unexpected normalized definition %+v", definition)
+	}
+	for name, testCase := range map[string]struct {
+		name     string
+		statuses []string
+		query    string
+	}{
+		"missing name":   {name: " "},
+		"long name":      {name: strings.Repeat("a", maxFilterNameLength+1)},
+		"unknown status": {name: "bad", statuses: []string{"delivered"}},
+		"long query":     {name: "bad", query: strings.Repeat("q", 201)},
+	} {
+		if _, err := NewDefinition(testCase.name, testCase.statuses, testCase.query, false); !errors.Is(err, ErrInvalidFilter) {
+			t.Fatalf("%s: expected ErrInvalidFilter, got %v", name, err)
+		}
+	}
+}
+
+func TestRepositoryScopesFiltersToOwnerAndSharedTenantFilters(t *testing.T) {
+	repository, _ := newFilterRepository(t)
+	ctx := context.Background()
+
+	own, err := repository.Create(ctx, ownerScope, mustDefinition(t, "Mine", []string{"errored"}, "", false))
+	if err != nil {
+		t.Fatalf("create own filter: %v", err)
+	}
+	if own.OwnerEmail != "owner@example.com" || own.Shared {
+		t.Fatalf("unexpected own filter %+v", own)
+	}
+	shared, err := repository.Create(ctx, adminScope, mustDefinition(t, "Team", []string{"sent"}, "weekly", true))
+	if err != nil {
+		t.Fatalf("create shared filter: %v", err)
+	}
+	if _, err := repository.Create(ctx, otherScope, mustDefinition(t, "Private", nil, "", false)); err != nil {
+		t.Fatalf("create other filter: %v", err)
+	}
+	if _, err := repository.Create(ctx, AccessScope{TenantID: "tenant-other", OwnerEmail: "admin@example.com", Admin: true}, mustDefinition(t, "Elsewhere", nil, "", true)); err != nil {
+		t.Fatalf("create other tenant filter: %v", err)
+	}
+
+	visible, err := repository.List(ctx, ownerScope)
+	if err != nil {
+		t.Fatalf("list filters: %v", err)
+	}
+	if len(visible) != 2 || visible[0].ID != own.ID || visible[1].ID != shared.ID {
+		t.Fatalf("expected own and shared filters by name, got %+v", visible)
+	}
+
+	filters, err := repository.ListFilters(ctx, ownerScope, shared.ID)
+	if err != nil {
+		t.Fatalf("resolve shared filter: %v", err)
+	}
+	if len(filters.Statuses) != 1 || filters.Statuses[0] != model.StatusSent || filters.SearchQuery.Value() != "weekly" {
+		t.Fatalf("unexpected list filters %+v", filters)
+	}
+	if _, err := repository.Update(ctx, ownerScope, shared.ID, mustDefinition(t, "Renamed", nil, "", false)); !errors.Is(err, ErrFilterReadOnly) {
+		t.Fatalf("expected shared filter to be read-only for a non-owner, got %v", err)
+	}
+	if err := repository.Delete(ctx, ownerScope, shared.ID); !errors.Is(err, ErrFilterReadOnly) {
+		t.Fatalf("expected shared filter delete to be refused, got %v", err)
+	}
+	if _, err := repository.ListFilters(ctx, otherScope, own.ID); !errors.Is(err, ErrFilterNotFound) {
+		t.Fatalf("expected another user's private filter to be hidden, got %v", err)
+	}
+}
+
+func TestRepositoryRestrictsSharingToAdmins(t *testing.T) {
+	repository, _ := newFilterRepository(t)
+	ctx := context.Background()
+
+	if _, err := repository.Create(ctx, ownerScope, mustDefinition(t, "Team", nil, "", true)); !errors.Is(err, ErrSharingNotAllowed) {
+		t.Fatalf("expected sharing to require admin, got %v", err)
+	}
+	own, err := repository.Create(ctx, ownerScope, mustDefinition(t, "Mine", nil, "", false))
+	if err != nil {
+		t.Fatalf("create filter: %v", err)
+	}
+	if _, err := repository.Update(ctx, ownerScope, own.ID, mustDefinition(t, "Mine", nil, "", true)); !errors.Is(err, ErrSharingNotAllowed) {
+		t.Fatalf("expected sharing on update to require admin, got %v", err)
+	}
+}
+
+func TestRepositoryUpdateAndDeleteOwnedFilter(t *testing.T) {
+	repository, _ := newFilterRepository(t)
+	ctx := context.Background()
+
+	first, err := repository.Create(ctx, ownerScope, mustDefinition(t, "First", nil, "", false))
+	if err != nil {
+		t.Fatalf("create first: %v", err)
+	}
+	if _, err := repository.Create(ctx, ownerScope, mustDefinition(t, "Second", nil, "", false)); err != nil {
+		t.Fatalf("create second: %v", err)
+	}
+	if _, err := repository.Create(ctx, ownerScope, mustDefinition(t, "First", nil, "", false)); !errors.Is(err, ErrFilterExists) {
+		t.Fatalf("expected duplicate name to be rejected, got %v", err)
+	}
+	if _, err := repository.Update(ctx, ownerScope, first.ID, mustDefinition(t, "Second", nil, "", false)); !errors.Is(err, ErrFilterExists) {
+		t.Fatalf("expected rename onto an existing name to be rejected, got %v", err)
+	}
+	updated, err := repository.Update(ctx, ownerScope, first.ID, mustDefinition(t, "First", []string{"cancelled"}, "renewal", false))
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if updated.ID != first.ID || len(updated.Statuses) != 1 || updated.Statuses[0] != "cancelled" || updated.SearchQuery != "renewal" {
+		t.Fatalf("unexpected updated filter %+v", updated)
+	}
+	if err := repository.Delete(ctx, ownerScope, first.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := repository.Delete(ctx, ownerScope, first.ID); !errors.Is(err, ErrFilterNotFound) {
+		t.Fatalf("expected deleted filter to be gone, got %v", err)
+	}
+}
+
+func TestRepositoryEnforcesOwnerLimit(t *testing.T) {
+	repository, _ := newFilterRepository(t)
+	repository.WithOwnerLimit(2)
+	ctx := context.Background()
+
+	for _, name := range []string{"One", "Two"} {
+		if _, err := repository.Create(ctx, ownerScope, mustDefinition(t, name, nil, "", false)); err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+	}
+	if _, err := repository.Create(ctx, ownerScope, mustDefinition(t, "Three", nil, "", false)); !errors.Is(err, ErrFilterLimitReached) {
+		t.Fatalf("expected the owner limit to apply, got %v", err)
+	}
+	if _, err := repository.Create(ctx, otherScope, mustDefinition(t, "Three", nil, "", false)); err != nil {
+		t.Fatalf("expected the limit to be per owner, got %v", err)
+	}
+}
+
+func TestListFiltersRejectsTamperedRows(t *testing.T) {
+	repository, database := newFilterRepository(t)
+	ctx := context.Background()
+
+	own, err := repository.Create(ctx, ownerScope, mustDefinition(t, "Mine", nil, "", false))
+	if err != nil {
+		t.Fatalf("create filter: %v", err)
+	}
+	if err := database.Model(&SavedFilter{}).Where(&SavedFilter{ID: own.ID}).Update("statuses", "bogus").Error; err != nil {
+		t.Fatalf("tamper filter: %v", err)
+	}
+	if _, err := repository.ListFilters(ctx, ownerScope, own.ID); !errors.Is(err, ErrInvalidFilter) {
+		t.Fatalf("expected a stored unknown status to be rejected, got %v", err)
+	}
+}
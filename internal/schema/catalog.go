@@ -0,0 +1,36 @@
+package schema
+
+import (
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/service"
+)
+
+// Version identifies the shape of the catalog's documents. Bump it whenever a field is
+// added, removed, renamed or changes type; the package tests fail until it is bumped.
+const Version = "9"
+
+// Catalog is what /api/schema serves and `pinguin-doctor schema` prints.
+type Catalog struct {
+	Version              string  `json:"version"`
+	NotificationResponse *Schema `json:"notification_response"`
+	WebhookEvent         *Schema `json:"webhook_event"`
+}
+
+// Build generates the catalog from the current Go structs.
+func Build() (Catalog, error) {
+	notificationResponse, err := Generate("NotificationResponse",
+		"A notification as returned by the HTTP API.", model.NotificationResponse{})
+	if err != nil {
+		return Catalog{}, err
+	}
+	webhookEvent, err := Generate("WebhookEvent",
+		"The JSON body POSTed to a tenant's webhook.", service.WebhookEvent{})
+	if err != nil {
+		return Catalog{}, err
+	}
+	return Catalog{
+		Version:              Version,
+		NotificationResponse: notificationResponse,
+		WebhookEvent:         webhookEvent,
+	}, nil
+}
@@ -0,0 +1,179 @@
+// Package schema generates JSON Schema documents for Pinguin's public payloads from
+// the Go structs that produce them. Every JSON field must carry a description struct
+// tag, so a field cannot reach partners undocumented.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// DescriptionTag is the struct tag holding a field's description.
+const DescriptionTag = "description"
+
+// Dialect is the JSON Schema draft the documents follow.
+const Dialect = "https://json-schema.org/draft/2020-12/schema"
+
+const (
+	typeString  = "string"
+	typeInteger = "integer"
+	typeNumber  = "number"
+	typeBoolean = "boolean"
+	typeArray   = "array"
+	typeObject  = "object"
+	typeNull    = "null"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Schema is the subset of JSON Schema the generator emits.
+type Schema struct {
+	Dialect         string             `json:"$schema,omitempty"`
+	Title           string             `json:"title,omitempty"`
+	Description     string             `json:"description,omitempty"`
+	Type            Types              `json:"type,omitempty"`
+	Format          string             `json:"format,omitempty"`
+	ContentEncoding string             `json:"contentEncoding,omitempty"`
+	Properties      map[string]*Schema `json:"properties,omitempty"`
+	Required        []string           `json:"required,omitempty"`
+	Items           *Schema            `json:"items,omitempty"`
+}
+
+// Types is a schema's type keyword. A single type encodes as a string, and a nullable
+// one as an array such as ["string", "null"].
+type Types []string
+
+// MarshalJSON encodes a single type as a bare string.
+func (types Types) MarshalJSON() ([]byte, error) {
+	if len(types) == 1 {
+		return json.Marshal(types[0])
+	}
+	return json.Marshal([]string(types))
+}
+
+// UnmarshalJSON accepts either encoding MarshalJSON produces.
+func (types *Types) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*types = Types{single}
+		return nil
+	}
+	var several []string
+	if err := json.Unmarshal(data, &several); err != nil {
+		return fmt.Errorf("schema: type: %w", err)
+	}
+	*types = several
+	return nil
+}
+
+// Generate describes the JSON encoding of value, which must be a struct or a pointer to
+// one. Fields are named and made optional by their json tags; a field without omitempty
+// is required. It fails on a JSON field without a description tag or of a kind the
+// generator cannot describe.
+func Generate(title string, description string, value interface{}) (*Schema, error) {
+	valueType := reflect.TypeOf(value)
+	for valueType != nil && valueType.Kind() == reflect.Pointer {
+		valueType = valueType.Elem()
+	}
+	if valueType == nil || valueType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("schema: %s: expected a struct, got %v", title, valueType)
+	}
+	document, err := describeType(valueType, valueType.Name())
+	if err != nil {
+		return nil, err
+	}
+	document.Dialect = Dialect
+	document.Title = title
+	document.Description = description
+	return document, nil
+}
+
+func describeType(valueType reflect.Type, path string) (*Schema, error) {
+	if valueType == timeType {
+		return &Schema{Type: Types{typeString}, Format: "date-time"}, nil
+	}
+	switch valueType.Kind() {
+	case reflect.Pointer:
+		described, err := describeType(valueType.Elem(), path)
+		if err != nil {
+			return nil, err
+		}
+		described.Type = append(described.Type, typeNull)
+		return described, nil
+	case reflect.String:
+		return &Schema{Type: Types{typeString}}, nil
+	case reflect.Bool:
+		return &Schema{Type: Types{typeBoolean}}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: Types{typeInteger}}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: Types{typeNumber}}, nil
+	case reflect.Slice:
+		if valueType.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: Types{typeString}, ContentEncoding: "base64"}, nil
+		}
+		items, err := describeType(valueType.Elem(), path+"[]")
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: Types{typeArray}, Items: items}, nil
+	case reflect.Struct:
+		return describeStruct(valueType, path)
+	default:
+		return nil, fmt.Errorf("schema: %s: unsupported kind %s", path, valueType.Kind())
+	}
+}
+
+func describeStruct(structType reflect.Type, path string) (*Schema, error) {
+	described := &Schema{Type: Types{typeObject}, Properties: make(map[string]*Schema)}
+	for index := 0; index < structType.NumField(); index++ {
+		field := structType.Field(index)
+		if !field.IsExported() {
+			continue
+		}
+		name, omitEmpty, skip := jsonFieldName(field)
+		if skip {
+			continue
+		}
+		fieldPath := path + "." + name
+		if field.Anonymous {
+			return nil, fmt.Errorf("schema: %s: embedded fields are not supported", fieldPath)
+		}
+		description := field.Tag.Get(DescriptionTag)
+		if description == "" {
+			return nil, fmt.Errorf("schema: %s has no %s tag", fieldPath, DescriptionTag)
+		}
+		property, err := describeType(field.Type, fieldPath)
+		if err != nil {
+			return nil, err
+		}
+		property.Description = description
+		described.Properties[name] = property
+		if !omitEmpty {
+			described.Required = append(described.Required, name)
+		}
+	}
+	return described, nil
+}
+
+// jsonFieldName reads the field's json tag the way encoding/json does.
+func jsonFieldName(field reflect.StructField) (name string, omitEmpty bool, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	name, options, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	for _, option := range strings.Split(options, ",") {
+		if option == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty, false
+}
@@ -0,0 +1,117 @@
+package schema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// versionShapes pins the shape of the catalog for each Version. When a field changes,
+// bump Version and add its fingerprint here; never edit an existing entry.
+var versionShapes = map[string]string{
+	"1": "ef0748a018c3f9e77ec8c2cb48d1f940887121a4f7307a7e9d11af8ef303383f",
+	"2": "a673ef82cfaadf8108fb42b733cfc8d22def719f85973e69e7ba48499fa7fadc",
+	"3": "11ded850309e5394b745360556e7a51f9cc7dae4ceed9912794b1909b0a426a4",
+	"4": "f92ed2b2befbbea1fcd4f51819e61e152e535c5f1f0fc35029a94b98ae206276",
+	"5": "684e42542e4bf34aa35619a467d9a912892c624e492b62ba186e961aa9be8d21",
+	"6": "f308551d0b068a155bd2a56350af410e30d390d0bfd30f8f69eb5d58d826d05e",
+	"7": "6e7a5b534e5d4e2ef3fcdbb087245727c937bd9666bc84c90cbec11896bacc3b",
+	"8": "c8c2009a7f8dc7b3563d654e37412988d2834dcba35523c69bd1036fb8099cfb",
+	"9": "2e726b9df4d2b48d1d01629e9a040f521d75c75107ff5d039fcb65084d46e9a8",
+}
+
+func TestBuildDescribesEveryField(t *testing.T) {
+	catalog, err := Build()
+	if err != nil {
+		t.Fatalf("every JSON field of a published payload needs a %s tag: %v", DescriptionTag, err)
+	}
+	if catalog.Version != Version {
+		t.Fatalf("expected version %s, got %s", Version, catalog.Version)
+	}
+}
+
+func TestVersionMatchesShape(t *testing.T) {
+	catalog, err := Build()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	var lines []string
+	collectShape(&lines, "notification_response", catalog.NotificationResponse, true)
+	collectShape(&lines, "webhook_event", catalog.WebhookEvent, true)
+	sort.Strings(lines)
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	fingerprint := hex.EncodeToString(sum[:])
+	if versionShapes[Version] != fingerprint {
+		t.Fatalf("schema fields changed without a version bump: bump schema.Version and record %q for it in versionShapes\n%s", fingerprint, strings.Join(lines, "\n"))
+	}
+}
+
+// collectShape lists every property with its types, format and whether it is required,
+// leaving out descriptions so rewording one does not need a version bump.
+func collectShape(lines *[]string, path string, document *Schema, required bool) {
+	*lines = append(*lines, strings.Join([]string{path, strings.Join(document.Type, "|"), document.Format, document.ContentEncoding, boolWord(required)}, " "))
+	if document.Items != nil {
+		collectShape(lines, path+"[]", document.Items, true)
+	}
+	requiredNames := make(map[string]bool, len(document.Required))
+	for _, name := range document.Required {
+		requiredNames[name] = true
+	}
+	for name, property := range document.Properties {
+		collectShape(lines, path+"."+name, property, requiredNames[name])
+	}
+}
+
+func boolWord(value bool) string {
+	if value {
+		return "required"
+	}
+	return "optional"
+}
+
+type undocumentedPayload struct {
+	ID    string `json:"id" description:"Identifier."`
+	Added string `json:"added"`
+}
+
+func TestGenerateRejectsUndocumentedField(t *testing.T) {
+	_, err := Generate("Undocumented", "", undocumentedPayload{})
+	if err == nil || !strings.Contains(err.Error(), "undocumentedPayload.added") {
+		t.Fatalf("expected the undocumented field to be named, got %v", err)
+	}
+}
+
+type samplePayload struct {
+	Name      string     `json:"name" description:"Name."`
+	Count     int        `json:"count,omitempty" description:"Count."`
+	DueAt     *time.Time `json:"due_at" description:"Due time."`
+	Data      []byte     `json:"data,omitempty" description:"Bytes."`
+	Tags      []string   `json:"tags,omitempty" description:"Tags."`
+	Internal  string     `json:"-"`
+	unexposed string
+}
+
+func TestGenerateMapsJSONEncoding(t *testing.T) {
+	document, err := Generate("Sample", "A sample.", samplePayload{})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	encoded, err := json.Marshal(document)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	expected := `{"$schema":"https://json-schema.org/draft/2020-12/schema","title":"Sample","description":"A sample.","type":"object",` +
+		`"properties":{"count":{"description":"Count.","type":"integer"},` +
+		`"data":{"description":"Bytes.","type":"string","contentEncoding":"base64"},` +
+		`"due_at":{"description":"Due time.","type":["string","null"],"format":"date-time"},` +
+		`"name":{"description":"Name.","type":"string"},` +
+		`"tags":{"description":"Tags.","type":"array","items":{"type":"string"}}},` +
+		`"required":["name","due_at"]}`
+	if string(encoded) != expected {
+		t.Fatalf("unexpected schema:\n%s", encoded)
+	}
+}
@@ -0,0 +1,308 @@
+// Package seed fills a development database with two sample tenants and a few hundred
+// generated notifications, so contributors and UI work start from realistic data. The
+// same seed and base time always produce the same rows.
+package seed
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultCount is how many notifications a seed run generates when Options.Count is zero.
+	DefaultCount = 300
+	// LegacyStatusFailed is the status rows stored before "errored" existed still carry;
+	// responses canonicalize it to "unknown".
+	LegacyStatusFailed model.NotificationStatus = "failed"
+
+	seedMaxRetries  = 3
+	seedHistoryDays = 14
+)
+
+// ErrDatabaseNotEmpty reports a database that already holds tenants or notifications.
+var ErrDatabaseNotEmpty = errors.New("database is not empty")
+
+//go:embed tenants.yml
+var tenantsYAML []byte
+
+// Options controls a seed run.
+type Options struct {
+	// Seed picks the generated data; equal seeds and base times give equal rows.
+	Seed uint64
+	// Count is the number of notifications to generate; zero uses DefaultCount.
+	Count int
+	// BaseTime anchors every generated timestamp; history lies before it and schedules after.
+	BaseTime time.Time
+	// Force seeds a database that already holds data. The sample tenants replace the
+	// configured tenant set, and notifications whose ids already exist are skipped.
+	Force bool
+	// BatchChunkSize bounds the notifications stored per transaction; zero uses
+	// model.DefaultNotificationBatchChunkSize.
+	BatchChunkSize int
+}
+
+// Summary counts what a seed run wrote.
+type Summary struct {
+	Tenants       int
+	Notifications int
+	Attachments   int
+	Skipped       int
+}
+
+// TenantsYAML returns the embedded sample tenant config, so a development server can
+// load the same tenants through tenantConfigPath.
+func TenantsYAML() []byte {
+	return append([]byte(nil), tenantsYAML...)
+}
+
+// Tenants returns the sample tenant config.
+func Tenants() (tenant.BootstrapConfig, error) {
+	var cfg tenant.BootstrapConfig
+	if err := yaml.Unmarshal(tenantsYAML, &cfg); err != nil {
+		return tenant.BootstrapConfig{}, fmt.Errorf("seed: parse sample tenants: %w", err)
+	}
+	return cfg, nil
+}
+
+// Run bootstraps the sample tenants into db and stores the generated notifications.
+// Without Options.Force it refuses, with ErrDatabaseNotEmpty, a database that already
+// holds tenants or notifications.
+func Run(ctx context.Context, db *gorm.DB, keeper *tenant.SecretKeeper, options Options) (Summary, error) {
+	if !options.Force {
+		if err := requireEmpty(ctx, db); err != nil {
+			return Summary{}, err
+		}
+	}
+	tenantsCfg, err := Tenants()
+	if err != nil {
+		return Summary{}, err
+	}
+	if err := tenant.Bootstrap(ctx, db, keeper, tenantsCfg); err != nil {
+		return Summary{}, fmt.Errorf("seed: bootstrap sample tenants: %w", err)
+	}
+	summary := Summary{Tenants: len(tenantsCfg.Tenants)}
+	generated := Notifications(options)
+	existing, err := existingNotificationIDs(ctx, db, generated, options.BatchChunkSize)
+	if err != nil {
+		return summary, err
+	}
+	pending := make([]model.Notification, 0, len(generated))
+	for _, notification := range generated {
+		if _, found := existing[notificationKey{tenantID: notification.TenantID, notificationID: notification.NotificationID}]; found {
+			summary.Skipped++
+			continue
+		}
+		pending = append(pending, notification)
+	}
+	batchErrors := model.CreateNotificationsBatch(ctx, db, pending, options.BatchChunkSize)
+	for index, notification := range pending {
+		if _, failed := batchErrors[index]; failed {
+			continue
+		}
+		summary.Notifications++
+		summary.Attachments += len(notification.Attachments)
+	}
+	for index, notification := range pending {
+		if storeErr, failed := batchErrors[index]; failed {
+			return summary, fmt.Errorf("seed: store %s: %w", notification.NotificationID, storeErr)
+		}
+	}
+	return summary, nil
+}
+
+func requireEmpty(ctx context.Context, db *gorm.DB) error {
+	for _, table := range []any{&tenant.Tenant{}, &model.Notification{}} {
+		var count int64
+		if err := db.WithContext(ctx).Model(table).Count(&count).Error; err != nil {
+			return fmt.Errorf("seed: check database: %w", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("seed: %w; pass --force to seed it anyway", ErrDatabaseNotEmpty)
+		}
+	}
+	return nil
+}
+
+// notificationKey identifies a notification across tenants.
+type notificationKey struct {
+	tenantID       string
+	notificationID string
+}
+
+// existingNotificationIDs reports which generated notifications are already stored,
+// querying chunkSize ids at a time.
+func existingNotificationIDs(ctx context.Context, db *gorm.DB, notifications []model.Notification, chunkSize int) (map[notificationKey]struct{}, error) {
+	if chunkSize <= 0 {
+		chunkSize = model.DefaultNotificationBatchChunkSize
+	}
+	existing := make(map[notificationKey]struct{})
+	for chunkStart := 0; chunkStart < len(notifications); chunkStart += chunkSize {
+		chunk := notifications[chunkStart:min(chunkStart+chunkSize, len(notifications))]
+		ids := make([]string, 0, len(chunk))
+		for _, notification := range chunk {
+			ids = append(ids, notification.NotificationID)
+		}
+		var stored []model.Notification
+		if err := db.WithContext(ctx).Model(&model.Notification{}).
+			Select("tenant_id", "notification_id").
+			Where("notification_id IN ?", ids).
+			Find(&stored).Error; err != nil {
+			return nil, fmt.Errorf("seed: check existing notifications: %w", err)
+		}
+		for _, notification := range stored {
+			existing[notificationKey{tenantID: notification.TenantID, notificationID: notification.NotificationID}] = struct{}{}
+		}
+	}
+	return existing, nil
+}
+
+// sampleTenant is what the generator needs to know about one sample tenant.
+type sampleTenant struct {
+	id     string
+	domain string
+	sms    bool
+}
+
+var (
+	sampleTenants = []sampleTenant{
+		{id: "acme-dev", domain: "acme.localhost", sms: true},
+		{id: "globex-dev", domain: "globex.localhost"},
+	}
+	sampleNames    = []string{"ada.lovelace", "grace.hopper", "alan.turing", "katherine.johnson", "edsger.dijkstra", "barbara.liskov", "donald.knuth", "margaret.hamilton"}
+	sampleSubjects = []string{"Your order has shipped", "Password reset requested", "Invoice %s is ready", "Welcome aboard", "Weekly activity summary", "Appointment reminder", "Your export is ready"}
+	sampleMessages = []string{
+		"Hi %s, thanks for your order. It is on its way and should arrive within three business days.",
+		"Hi %s, we received a request to reset your password. The link expires in one hour.",
+		"Hi %s, your monthly invoice is attached. Reply to this message with any questions.",
+		"Hi %s, here is a summary of what happened in your workspace this week.",
+	}
+	sampleSMS          = []string{"Your verification code is %s.", "Reminder: your appointment is tomorrow at 10:00. Reply C to cancel.", "Your delivery %s is out for delivery today."}
+	sampleErrors       = []string{"550 5.1.1 mailbox unavailable", "421 4.7.0 try again later", "Twilio error 21610: recipient unsubscribed"}
+	sampleCancelReason = []string{"duplicate", "customer request", model.CancelReasonExpired}
+)
+
+// Notifications generates the seed run's notifications without touching a database,
+// so tests can reuse the same rows. Statuses cover queued, scheduled, sent, errored,
+// cancelled and the legacy "failed"; some emails carry small generated attachments.
+func Notifications(options Options) []model.Notification {
+	count := options.Count
+	if count <= 0 {
+		count = DefaultCount
+	}
+	baseTime := options.BaseTime.UTC()
+	random := rand.New(rand.NewPCG(options.Seed, 0x70696e6775696e))
+	notifications := make([]model.Notification, 0, count)
+	for index := 0; index < count; index++ {
+		notifications = append(notifications, generateNotification(random, index, baseTime))
+	}
+	return notifications
+}
+
+func generateNotification(random *rand.Rand, index int, baseTime time.Time) model.Notification {
+	owner := sampleTenants[random.IntN(len(sampleTenants))]
+	name := sampleNames[random.IntN(len(sampleNames))]
+	firstName, _, _ := strings.Cut(name, ".")
+	displayName := strings.ToUpper(firstName[:1]) + firstName[1:]
+	createdAt := baseTime.Add(-time.Duration(random.Int64N(int64(seedHistoryDays * 24 * time.Hour)))).Truncate(time.Second)
+	notification := model.Notification{
+		TenantID:       owner.id,
+		NotificationID: fmt.Sprintf("seed-%04d", index+1),
+		CreatedAt:      createdAt,
+		UpdatedAt:      createdAt,
+	}
+	if owner.sms && random.IntN(10) < 3 {
+		notification.NotificationType = model.NotificationSMS
+		notification.Recipient = fmt.Sprintf("+1555555%04d", random.IntN(10000))
+		notification.Message = fmt.Sprintf(sampleSMS[random.IntN(len(sampleSMS))], fmt.Sprintf("%06d", random.IntN(1000000)))
+	} else {
+		notification.NotificationType = model.NotificationEmail
+		notification.Recipient = name + "@" + owner.domain
+		subject := sampleSubjects[random.IntN(len(sampleSubjects))]
+		if strings.Contains(subject, "%s") {
+			subject = fmt.Sprintf(subject, fmt.Sprintf("INV-%05d", index+1))
+		}
+		notification.Subject = subject
+		notification.Message = fmt.Sprintf(sampleMessages[random.IntN(len(sampleMessages))], displayName)
+		if random.IntN(100) < 15 {
+			notification.Attachments = generateAttachments(random, owner.id, notification.NotificationID, createdAt)
+		}
+	}
+	applyStatus(random, &notification, baseTime)
+	return notification
+}
+
+// applyStatus picks a status with roughly production proportions and fills the
+// attempt, schedule and cancellation fields that status implies.
+func applyStatus(random *rand.Rand, notification *model.Notification, baseTime time.Time) {
+	attemptedAt := notification.CreatedAt.Add(time.Duration(1+random.IntN(300)) * time.Second)
+	switch roll := random.IntN(100); {
+	case roll < 55:
+		notification.Status = model.StatusSent
+		notification.RetryCount = random.IntN(2)
+		notification.ProviderMessageID = fmt.Sprintf("provider-%08x", random.Uint32())
+		notification.LastAttemptedAt = attemptedAt
+	case roll < 65:
+		notification.Status = model.StatusQueued
+		scheduledFor := baseTime.Add(time.Duration(1+random.IntN(72)) * time.Hour)
+		notification.ScheduledFor = &scheduledFor
+	case roll < 72:
+		notification.Status = model.StatusQueued
+	case roll < 85:
+		notification.Status = model.StatusErrored
+		notification.RetryCount = 1 + random.IntN(seedMaxRetries)
+		notification.LastAttemptedAt = attemptedAt.Add(time.Duration(notification.RetryCount) * time.Minute)
+		if notification.RetryCount == seedMaxRetries {
+			notification.LastError = sampleErrors[random.IntN(len(sampleErrors))]
+		}
+	case roll < 95:
+		reason := sampleCancelReason[random.IntN(len(sampleCancelReason))]
+		actor := model.CancelActorGRPC
+		if reason == model.CancelReasonExpired {
+			actor = model.CancelActorSystem
+		}
+		notification.MarkCancelled(attemptedAt, reason, actor)
+	default:
+		notification.Status = LegacyStatusFailed
+		notification.RetryCount = seedMaxRetries
+		notification.LastAttemptedAt = attemptedAt
+	}
+	if !notification.LastAttemptedAt.IsZero() {
+		notification.UpdatedAt = notification.LastAttemptedAt
+	}
+	if notification.CancelledAt != nil {
+		notification.UpdatedAt = *notification.CancelledAt
+	}
+}
+
+func generateAttachments(random *rand.Rand, tenantID string, notificationID string, createdAt time.Time) []model.NotificationAttachment {
+	attachments := make([]model.NotificationAttachment, 0, 2)
+	for fileIndex := 0; fileIndex < 1+random.IntN(2); fileIndex++ {
+		var rows strings.Builder
+		rows.WriteString("item,quantity,amount\n")
+		for row := 0; row < 3+random.IntN(8); row++ {
+			fmt.Fprintf(&rows, "item-%d,%d,%d.%02d\n", row+1, 1+random.IntN(9), random.IntN(500), random.IntN(100))
+		}
+		data := []byte(rows.String())
+		attachments = append(attachments, model.NotificationAttachment{
+			TenantID:       tenantID,
+			NotificationID: notificationID,
+			Filename:       fmt.Sprintf("%s-%d.csv", notificationID, fileIndex+1),
+			ContentType:    "text/csv",
+			SizeBytes:      len(data),
+			Data:           data,
+			CreatedAt:      createdAt,
+			UpdatedAt:      createdAt,
+		})
+	}
+	return attachments
+}
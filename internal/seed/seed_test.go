@@ -0,0 +1,119 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/db"
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+	"gorm.io/gorm"
+)
+
+var seedTestBaseTime = time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC)
+
+func openSeedTestDatabase(t *testing.T) *gorm.DB {
+	t.Helper()
+	database, err := db.InitDB(filepath.Join(t.TempDir(), "seed.db"), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	return database
+}
+
+func TestNotificationsAreDeterministic(t *testing.T) {
+	options := Options{Seed: 7, BaseTime: seedTestBaseTime}
+	first := Notifications(options)
+	second := Notifications(options)
+	if len(first) != DefaultCount {
+		t.Fatalf("expected %d notifications, got %d", DefaultCount, len(first))
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("expected equal seeds to generate equal notifications")
+	}
+	other := Notifications(Options{Seed: 8, BaseTime: seedTestBaseTime})
+	if reflect.DeepEqual(first, other) {
+		t.Fatalf("expected different seeds to generate different notifications")
+	}
+}
+
+func TestNotificationsCoverStatusesAndTypes(t *testing.T) {
+	statuses := map[model.NotificationStatus]int{}
+	types := map[model.NotificationType]int{}
+	var scheduled, attachments, retried int
+	for _, notification := range Notifications(Options{Seed: 1, BaseTime: seedTestBaseTime}) {
+		statuses[notification.Status]++
+		types[notification.NotificationType]++
+		if notification.ScheduledFor != nil {
+			scheduled++
+			if !notification.ScheduledFor.After(seedTestBaseTime) {
+				t.Fatalf("expected %s to be scheduled after the base time", notification.NotificationID)
+			}
+		}
+		if notification.CreatedAt.After(seedTestBaseTime) {
+			t.Fatalf("expected %s to be created before the base time", notification.NotificationID)
+		}
+		if notification.NotificationType == model.NotificationSMS && notification.TenantID != "acme-dev" {
+			t.Fatalf("expected sms only for the tenant with an sms profile, got %s", notification.TenantID)
+		}
+		if notification.RetryCount > 0 {
+			retried++
+		}
+		attachments += len(notification.Attachments)
+	}
+	for _, status := range []model.NotificationStatus{model.StatusQueued, model.StatusSent, model.StatusErrored, model.StatusCancelled, LegacyStatusFailed} {
+		if statuses[status] == 0 {
+			t.Fatalf("expected at least one %q notification, got %v", status, statuses)
+		}
+	}
+	if types[model.NotificationEmail] == 0 || types[model.NotificationSMS] == 0 {
+		t.Fatalf("expected email and sms notifications, got %v", types)
+	}
+	if scheduled == 0 || attachments == 0 || retried == 0 {
+		t.Fatalf("expected scheduled, attached and retried notifications, got %d/%d/%d", scheduled, attachments, retried)
+	}
+}
+
+func TestRunSeedsEmptyDatabase(t *testing.T) {
+	database := openSeedTestDatabase(t)
+	keeper, err := tenant.NewSecretKeeper(strings.Repeat("a", 64))
+	if err != nil {
+		t.Fatalf("secret keeper: %v", err)
+	}
+	// A chunk size that does not divide the count exercises a partial last chunk.
+	summary, err := Run(context.Background(), database, keeper, Options{Seed: 3, Count: 40, BaseTime: seedTestBaseTime, BatchChunkSize: 7})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if summary.Tenants != 2 || summary.Notifications != 40 || summary.Skipped != 0 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+	var stored int64
+	if err := database.Model(&model.Notification{}).Count(&stored).Error; err != nil || stored != 40 {
+		t.Fatalf("expected 40 stored notifications, got %d err=%v", stored, err)
+	}
+	var attachments int64
+	if err := database.Model(&model.NotificationAttachment{}).Count(&attachments).Error; err != nil || int(attachments) != summary.Attachments {
+		t.Fatalf("expected %d stored attachments, got %d err=%v", summary.Attachments, attachments, err)
+	}
+
+	_, err = Run(context.Background(), database, keeper, Options{Seed: 3, Count: 40, BaseTime: seedTestBaseTime})
+	if !errors.Is(err, ErrDatabaseNotEmpty) {
+		t.Fatalf("expected ErrDatabaseNotEmpty, got %v", err)
+	}
+
+	forced, err := Run(context.Background(), database, keeper, Options{Seed: 3, Count: 50, BaseTime: seedTestBaseTime, Force: true, BatchChunkSize: 7})
+	if err != nil {
+		t.Fatalf("forced run: %v", err)
+	}
+	if forced.Notifications != 10 || forced.Skipped != 40 {
+		t.Fatalf("expected the forced run to skip existing ids, got %+v", forced)
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/tyemirov/pinguin/internal/config"
+	"github.com/tyemirov/pinguin/internal/entropy"
+	"github.com/tyemirov/pinguin/internal/model"
+)
+
+// mimeBuildingEmailSender renders the message like SMTPEmailSender without dialing,
+// so the benchmark covers the MIME encoding as well.
+type mimeBuildingEmailSender struct{}
+
+func (mimeBuildingEmailSender) SendEmail(_ context.Context, recipient string, subject string, message string, attachments []model.EmailAttachment) error {
+	_, err := buildEmailMessage(entropy.System(), "from@example.com", recipient, subject, message, attachments, model.SenderOverrides{})
+	return err
+}
+
+// BenchmarkSendNotificationLargeAttachments measures one send carrying three 5 MiB
+// attachments, from request validation through the MIME message and the insert.
+func BenchmarkSendNotificationLargeAttachments(b *testing.B) {
+	decoded := make([]model.EmailAttachment, 0, 3)
+	for index := 0; index < 3; index++ {
+		decoded = append(decoded, model.EmailAttachment{
+			Filename:    fmt.Sprintf("report-%d.pdf", index),
+			ContentType: "application/pdf",
+			Data:        bytes.Repeat([]byte{byte('a' + index)}, model.MaxNotificationAttachmentSizeBytes),
+		})
+	}
+	configuration := config.Config{MaxRetries: 3, RetryIntervalSec: 1}
+	serviceInstance := NewNotificationServiceWithSenders(openIsolatedDatabase(b), newDiscardLogger(), configuration, nil, mimeBuildingEmailSender{}, &stubSmsSender{})
+	ctx := tenantContext()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for iteration := 0; iteration < b.N; iteration++ {
+		request, err := model.NewNotificationRequest(model.NotificationEmail, "user@example.com", "Reports", "Attached.", nil, decoded)
+		if err != nil {
+			b.Fatalf("request: %v", err)
+		}
+		response, err := serviceInstance.SendNotification(ctx, request)
+		if err != nil {
+			b.Fatalf("send: %v", err)
+		}
+		if response.Status != model.StatusSent {
+			b.Fatalf("expected sent, got %s", response.Status)
+		}
+	}
+}
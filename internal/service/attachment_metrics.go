@@ -0,0 +1,128 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/tyemirov/pinguin/internal/model"
+)
+
+// attachmentSizeBucketBounds are the upper bounds, in bytes, of the attachment size
+// histogram. The last bound is the per-attachment limit, so every attachment fits.
+var attachmentSizeBucketBounds = []int64{16 << 10, 256 << 10, 1 << 20, model.MaxNotificationAttachmentSizeBytes}
+
+// attachmentCountBucketBounds are the upper bounds of the attachments-per-send histogram.
+var attachmentCountBucketBounds = []int64{1, 2, 5, model.MaxNotificationAttachmentCount}
+
+// HistogramBucket counts the observations at or below UpperBound, like a Prometheus
+// cumulative bucket.
+type HistogramBucket struct {
+	UpperBound int64 `json:"upper_bound"`
+	Count      int64 `json:"count"`
+}
+
+// AttachmentSizeMetrics summarizes the attachments a tenant's sends carried. Only
+// counts and byte sizes are kept; filenames, content types and data never are.
+type AttachmentSizeMetrics struct {
+	// Sends counts the stored notifications that carried at least one attachment.
+	Sends       int64 `json:"sends"`
+	Attachments int64 `json:"attachments"`
+	TotalBytes  int64 `json:"total_bytes"`
+	MaxBytes    int64 `json:"max_bytes"`
+	// SizeBuckets is the histogram of single attachment sizes in bytes.
+	SizeBuckets []HistogramBucket `json:"size_buckets"`
+	// CountBuckets is the histogram of attachments per send.
+	CountBuckets []HistogramBucket `json:"count_buckets"`
+}
+
+type attachmentSizeEntry struct {
+	sends        int64
+	attachments  int64
+	totalBytes   int64
+	maxBytes     int64
+	sizeCounts   []int64
+	countsBySend []int64
+}
+
+// attachmentSizeRecorder keeps per-tenant attachment histograms for the operator views.
+type attachmentSizeRecorder struct {
+	mutex   sync.Mutex
+	entries map[string]*attachmentSizeEntry
+}
+
+func newAttachmentSizeRecorder() *attachmentSizeRecorder {
+	return &attachmentSizeRecorder{entries: make(map[string]*attachmentSizeEntry)}
+}
+
+// observe records one stored send. Sends without attachments are not recorded.
+func (recorder *attachmentSizeRecorder) observe(tenantID string, attachments []model.EmailAttachment) {
+	if recorder == nil || len(attachments) == 0 {
+		return
+	}
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	entry, exists := recorder.entries[tenantID]
+	if !exists {
+		entry = &attachmentSizeEntry{
+			sizeCounts:   make([]int64, len(attachmentSizeBucketBounds)),
+			countsBySend: make([]int64, len(attachmentCountBucketBounds)),
+		}
+		recorder.entries[tenantID] = entry
+	}
+	entry.sends++
+	observeBuckets(entry.countsBySend, attachmentCountBucketBounds, int64(len(attachments)))
+	for _, attachment := range attachments {
+		size := int64(len(attachment.Data))
+		entry.attachments++
+		entry.totalBytes += size
+		if size > entry.maxBytes {
+			entry.maxBytes = size
+		}
+		observeBuckets(entry.sizeCounts, attachmentSizeBucketBounds, size)
+	}
+}
+
+func (recorder *attachmentSizeRecorder) snapshot(tenantID string) *AttachmentSizeMetrics {
+	if recorder == nil {
+		return nil
+	}
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	entry, exists := recorder.entries[tenantID]
+	if !exists {
+		return nil
+	}
+	return &AttachmentSizeMetrics{
+		Sends:        entry.sends,
+		Attachments:  entry.attachments,
+		TotalBytes:   entry.totalBytes,
+		MaxBytes:     entry.maxBytes,
+		SizeBuckets:  histogramBuckets(attachmentSizeBucketBounds, entry.sizeCounts),
+		CountBuckets: histogramBuckets(attachmentCountBucketBounds, entry.countsBySend),
+	}
+}
+
+// observeBuckets increments every cumulative bucket whose bound covers value.
+func observeBuckets(counts []int64, bounds []int64, value int64) {
+	for index, bound := range bounds {
+		if value <= bound {
+			counts[index]++
+		}
+	}
+}
+
+func histogramBuckets(bounds []int64, counts []int64) []HistogramBucket {
+	buckets := make([]HistogramBucket, 0, len(bounds))
+	for index, bound := range bounds {
+		buckets = append(buckets, HistogramBucket{UpperBound: bound, Count: counts[index]})
+	}
+	return buckets
+}
+
+// attachmentBytes totals the attachment data of one send.
+func attachmentBytes(attachments []model.EmailAttachment) int {
+	total := 0
+	for _, attachment := range attachments {
+		total += len(attachment.Data)
+	}
+	return total
+}
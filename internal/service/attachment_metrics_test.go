@@ -0,0 +1,79 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/tyemirov/pinguin/internal/model"
+)
+
+func TestSendNotificationObservesAttachmentSizes(t *testing.T) {
+	database := openIsolatedDatabase(t)
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(database, &stubEmailSender{}, &stubSmsSender{})
+
+	attachments := []model.EmailAttachment{
+		{Filename: "small.txt", ContentType: "text/plain", Data: bytes.Repeat([]byte("a"), 1000)},
+		{Filename: "medium.pdf", ContentType: "application/pdf", Data: bytes.Repeat([]byte("b"), 100<<10)},
+		{Filename: "large.bin", ContentType: "application/octet-stream", Data: bytes.Repeat([]byte("c"), 2<<20)},
+	}
+	request := mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", nil, attachments)
+	if _, err := serviceInstance.SendNotification(tenantContext(), request); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	plainRequest := mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", nil, nil)
+	if _, err := serviceInstance.SendNotification(tenantContext(), plainRequest); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+
+	metrics := serviceInstance.attachmentSizes.snapshot(testTenantID)
+	if metrics == nil {
+		t.Fatalf("expected attachment metrics for the tenant")
+	}
+	if metrics.Sends != 1 || metrics.Attachments != 3 || metrics.TotalBytes != 1000+100<<10+2<<20 || metrics.MaxBytes != 2<<20 {
+		t.Fatalf("unexpected attachment totals %+v", metrics)
+	}
+	expectedSizes := []HistogramBucket{
+		{UpperBound: 16 << 10, Count: 1},
+		{UpperBound: 256 << 10, Count: 2},
+		{UpperBound: 1 << 20, Count: 2},
+		{UpperBound: model.MaxNotificationAttachmentSizeBytes, Count: 3},
+	}
+	if len(metrics.SizeBuckets) != len(expectedSizes) {
+		t.Fatalf("unexpected size buckets %+v", metrics.SizeBuckets)
+	}
+	for index, expected := range expectedSizes {
+		if metrics.SizeBuckets[index] != expected {
+			t.Fatalf("size bucket %d: expected %+v, got %+v", index, expected, metrics.SizeBuckets[index])
+		}
+	}
+	expectedCounts := []int64{0, 0, 1, 1}
+	for index, expected := range expectedCounts {
+		if metrics.CountBuckets[index].Count != expected {
+			t.Fatalf("count bucket %d: expected %d, got %+v", index, expected, metrics.CountBuckets)
+		}
+	}
+	if serviceInstance.attachmentSizes.snapshot("tenant-other") != nil {
+		t.Fatalf("expected no metrics for a tenant without sends")
+	}
+}
+
+func TestAttachmentSizeRecorderKeepsNoAttachmentDetails(t *testing.T) {
+	recorder := newAttachmentSizeRecorder()
+	recorder.observe("tenant-a", []model.EmailAttachment{{Filename: "payroll-jane-doe.pdf", ContentType: "application/pdf", Data: []byte("secret")}})
+	metrics := recorder.snapshot("tenant-a")
+	if metrics == nil || metrics.TotalBytes != 6 {
+		t.Fatalf("unexpected metrics %+v", metrics)
+	}
+	encoded, err := json.Marshal(metrics)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if strings.Contains(string(encoded), "payroll") || strings.Contains(string(encoded), "secret") || strings.Contains(string(encoded), "pdf") {
+		t.Fatalf("expected no attachment names, types or data in metrics, got %s", encoded)
+	}
+	if (*attachmentSizeRecorder)(nil).snapshot("tenant-a") != nil {
+		t.Fatalf("expected a nil recorder to report nothing")
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+)
+
+func metadataOnlyTenantContext() context.Context {
+	cfg := baseRuntimeConfig()
+	cfg.Tenant.AttachmentMetadataOnly = true
+	return tenant.WithRuntime(context.Background(), cfg)
+}
+
+func TestSendNotificationStoresAttachmentMetadataOnly(t *testing.T) {
+	database := openIsolatedDatabase(t)
+	emailSender := &stubEmailSender{}
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(database, emailSender, &stubSmsSender{})
+
+	request := mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", nil, []model.EmailAttachment{
+		{Filename: "invoice.pdf", ContentType: "application/pdf", Data: []byte("%PDF-1.7")},
+	})
+	response, sendErr := serviceInstance.SendNotification(metadataOnlyTenantContext(), request)
+	if sendErr != nil {
+		t.Fatalf("send error: %v", sendErr)
+	}
+	if len(emailSender.receivedAttachments) != 1 || string(emailSender.receivedAttachments[0][0].Data) != "%PDF-1.7" {
+		t.Fatalf("expected the immediate send to carry the attachment bytes, got %+v", emailSender.receivedAttachments)
+	}
+
+	var stored []model.NotificationAttachment
+	if err := database.Where(&model.NotificationAttachment{NotificationID: response.NotificationID}).Find(&stored).Error; err != nil {
+		t.Fatalf("load attachments: %v", err)
+	}
+	if len(stored) != 1 {
+		t.Fatalf("expected attachment metadata row, got %d", len(stored))
+	}
+	if stored[0].Filename != "invoice.pdf" || stored[0].ContentType != "application/pdf" || stored[0].SizeBytes != len("%PDF-1.7") {
+		t.Fatalf("unexpected attachment metadata %+v", stored[0])
+	}
+	if len(stored[0].Data) != 0 || !stored[0].DataDiscarded {
+		t.Fatalf("expected attachment bytes to be discarded, got %d bytes (discarded=%v)", len(stored[0].Data), stored[0].DataDiscarded)
+	}
+}
+
+func TestSendNotificationPersistsAttachmentDataByDefault(t *testing.T) {
+	database := openIsolatedDatabase(t)
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(database, &stubEmailSender{}, &stubSmsSender{})
+
+	request := mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", nil, []model.EmailAttachment{
+		{Filename: "invoice.pdf", ContentType: "application/pdf", Data: []byte("%PDF-1.7")},
+	})
+	response, sendErr := serviceInstance.SendNotification(tenantContext(), request)
+	if sendErr != nil {
+		t.Fatalf("send error: %v", sendErr)
+	}
+	stored, err := model.GetNotificationByID(tenantContext(), database, testTenantID, response.NotificationID)
+	if err != nil {
+		t.Fatalf("load notification: %v", err)
+	}
+	if len(stored.Attachments) != 1 || string(stored.Attachments[0].Data) != "%PDF-1.7" || stored.Attachments[0].DataDiscarded {
+		t.Fatalf("expected attachment bytes to be stored, got %+v", stored.Attachments)
+	}
+}
+
+func TestSendNotificationRejectsScheduledAttachmentsWithoutPersistence(t *testing.T) {
+	database := openIsolatedDatabase(t)
+	emailSender := &stubEmailSender{}
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(database, emailSender, &stubSmsSender{})
+
+	scheduledFor := time.Now().UTC().Add(time.Hour)
+	request := mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", &scheduledFor, []model.EmailAttachment{
+		{Filename: "invoice.pdf", ContentType: "application/pdf", Data: []byte("%PDF-1.7")},
+	})
+	if _, sendErr := serviceInstance.SendNotification(metadataOnlyTenantContext(), request); !errors.Is(sendErr, ErrAttachmentDataNotPersisted) {
+		t.Fatalf("expected ErrAttachmentDataNotPersisted, got %v", sendErr)
+	}
+	var count int64
+	if err := database.Model(&model.Notification{}).Count(&count).Error; err != nil || count != 0 {
+		t.Fatalf("expected nothing to be stored, got %d (%v)", count, err)
+	}
+}
+
+func TestRetryWorkerCancelsNotificationWithDiscardedAttachmentData(t *testing.T) {
+	database := openIsolatedDatabase(t)
+	emailSender := &stubEmailSender{err: errors.New("smtp unavailable")}
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(database, emailSender, &stubSmsSender{})
+
+	request := mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", nil, []model.EmailAttachment{
+		{Filename: "invoice.pdf", ContentType: "application/pdf", Data: []byte("%PDF-1.7")},
+	})
+	response, sendErr := serviceInstance.SendNotification(metadataOnlyTenantContext(), request)
+	if sendErr != nil {
+		t.Fatalf("send error: %v", sendErr)
+	}
+	if response.Status != model.StatusErrored {
+		t.Fatalf("expected failed immediate send, got %s", response.Status)
+	}
+
+	emailSender.err = nil
+	worker := newRetryWorkerForTest(t, serviceInstance, &adjustableClock{now: time.Now().UTC().Add(time.Hour)})
+	worker.RunOnce(tenantContext())
+	if emailSender.callCount != 1 {
+		t.Fatalf("expected the retry to fail fast without sending, got %d sends", emailSender.callCount)
+	}
+	stored, fetchErr := model.GetNotificationByID(tenantContext(), database, testTenantID, response.NotificationID)
+	if fetchErr != nil {
+		t.Fatalf("fetch notification error: %v", fetchErr)
+	}
+	if stored.Status != model.StatusCancelled || stored.CancelReason != model.CancelReasonAttachmentDataUnavailable {
+		t.Fatalf("expected cancelled/%s, got %s/%q", model.CancelReasonAttachmentDataUnavailable, stored.Status, stored.CancelReason)
+	}
+}
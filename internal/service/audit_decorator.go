@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+)
+
+// WithAudit audit-logs every send and group send with its outcome. Recipients are
+// digested and content is never logged. Cancellations, legal holds, transfers and cache
+// flushes are audited by the core service, which holds the records they change.
+func WithAudit(logger *slog.Logger) Decorator {
+	return func(inner NotificationService) NotificationService {
+		return &auditService{NotificationService: inner, logger: logger}
+	}
+}
+
+type auditService struct {
+	NotificationService
+	logger *slog.Logger
+}
+
+func (service *auditService) SendNotification(ctx context.Context, request model.NotificationRequest) (model.NotificationResponse, error) {
+	response, err := service.NotificationService.SendNotification(ctx, request)
+	attributes := []any{
+		"tenant_id", auditTenantID(ctx),
+		"notification_type", request.NotificationType(),
+		"recipient_digest", DigestForLogging(request.Recipient()),
+		"accepted", response.NotificationID != "",
+	}
+	if response.NotificationID != "" {
+		attributes = append(attributes, "notification_id", response.NotificationID, "status", response.Status)
+	}
+	if err != nil {
+		attributes = append(attributes, "error", err)
+	}
+	service.logger.InfoContext(ctx, "audit_notification_send", attributes...)
+	return response, err
+}
+
+func (service *auditService) SendNotificationGroup(ctx context.Context, request model.NotificationGroupRequest) (model.NotificationGroupResponse, error) {
+	response, err := service.NotificationService.SendNotificationGroup(ctx, request)
+	attributes := []any{
+		"tenant_id", auditTenantID(ctx),
+		"channels", len(request.Channels()),
+		"accepted", response.GroupID != "",
+	}
+	if response.GroupID != "" {
+		attributes = append(attributes, "group_id", response.GroupID, "status", response.Status)
+	}
+	if err != nil {
+		attributes = append(attributes, "error", err)
+	}
+	service.logger.InfoContext(ctx, "audit_notification_group_send", attributes...)
+	return response, err
+}
+
+// auditTenantID names the tenant a call was made for, or is empty when the context has none.
+func auditTenantID(ctx context.Context) string {
+	runtimeCfg, ok := tenant.RuntimeFromContext(ctx)
+	if !ok {
+		return ""
+	}
+	return runtimeCfg.Tenant.ID
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/tyemirov/pinguin/internal/model"
+)
+
+func TestWithAuditLogsSendOutcomes(t *testing.T) {
+	var calls []string
+	var auditLog bytes.Buffer
+	inner := &recordingInnerService{calls: &calls, response: model.NotificationResponse{NotificationID: "notif-1", Status: model.StatusErrored}, err: ErrImmediateDispatchFailed}
+	audited := WithAudit(slog.New(slog.NewJSONHandler(&auditLog, nil)))(inner)
+
+	response, err := audited.SendNotification(tenantContext(), mustNotificationRequest(t, model.NotificationEmail, "User@Example.com", "Subject", "Secret body", nil, nil))
+	if !errors.Is(err, ErrImmediateDispatchFailed) || response.NotificationID != "notif-1" {
+		t.Fatalf("expected the inner result unchanged, got %+v (%v)", response, err)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal(auditLog.Bytes(), &entry); err != nil {
+		t.Fatalf("decode audit entry %q: %v", auditLog.String(), err)
+	}
+	if entry["msg"] != "audit_notification_send" || entry["tenant_id"] != testTenantID || entry["notification_id"] != "notif-1" ||
+		entry["status"] != string(model.StatusErrored) || entry["accepted"] != true || entry["error"] == nil {
+		t.Fatalf("unexpected audit entry %+v", entry)
+	}
+	if entry["recipient_digest"] != DigestForLogging("user@example.com") || strings.Contains(auditLog.String(), "xample.com") || strings.Contains(auditLog.String(), "Secret body") {
+		t.Fatalf("expected no recipient or content in the audit entry, got %s", auditLog.String())
+	}
+
+	auditLog.Reset()
+	groupRequest, err := model.NewNotificationGroupRequest([]model.NotificationRequest{
+		mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", nil, nil),
+		mustNotificationRequest(t, model.NotificationSMS, "+15555550100", "", "Body", nil, nil),
+	})
+	if err != nil {
+		t.Fatalf("group request: %v", err)
+	}
+	if _, err := audited.SendNotificationGroup(tenantContext(), groupRequest); err != nil {
+		t.Fatalf("group send: %v", err)
+	}
+	if output := auditLog.String(); !strings.Contains(output, `"msg":"audit_notification_group_send"`) || !strings.Contains(output, `"group_id":"group-1"`) || !strings.Contains(output, `"channels":2`) {
+		t.Fatalf("expected a group audit entry, got %s", output)
+	}
+}
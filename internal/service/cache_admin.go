@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Cache names reported by CacheStates.
+const (
+	CacheTenantRuntime = "tenant_runtime"
+	CacheTenantDomain  = "tenant_domain"
+	CacheEmailSender   = "email_sender"
+	CacheSMSSender     = "sms_sender"
+)
+
+// CacheState is the size and effectiveness of one in-process cache since the process
+// started. Flushing a cache empties it but keeps its counters.
+type CacheState struct {
+	Name    string `json:"name"`
+	Entries int    `json:"entries"`
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+}
+
+// senderCacheCounters counts lookups of the tenant-specific sender caches. A sender
+// rebuilt after its credentials rotated counts as a miss.
+type senderCacheCounters struct {
+	emailHits   atomic.Uint64
+	emailMisses atomic.Uint64
+	smsHits     atomic.Uint64
+	smsMisses   atomic.Uint64
+}
+
+func (serviceInstance *notificationServiceImpl) CacheStates(ctx context.Context) []CacheState {
+	var states []CacheState
+	if serviceInstance.tenantRepo != nil {
+		repoStats := serviceInstance.tenantRepo.CacheStats()
+		states = append(states,
+			CacheState{Name: CacheTenantRuntime, Entries: repoStats.RuntimeEntries, Hits: repoStats.Hits, Misses: repoStats.Misses},
+			CacheState{Name: CacheTenantDomain, Entries: repoStats.DomainEntries, Hits: repoStats.DomainHits, Misses: repoStats.DomainMisses},
+		)
+	}
+	serviceInstance.senderMutex.RLock()
+	emailEntries, smsEntries := len(serviceInstance.emailSenders), len(serviceInstance.smsSenders)
+	serviceInstance.senderMutex.RUnlock()
+	counters := &serviceInstance.senderCacheStats
+	return append(states,
+		CacheState{Name: CacheEmailSender, Entries: emailEntries, Hits: counters.emailHits.Load(), Misses: counters.emailMisses.Load()},
+		CacheState{Name: CacheSMSSender, Entries: smsEntries, Hits: counters.smsHits.Load(), Misses: counters.smsMisses.Load()},
+	)
+}
+
+func (serviceInstance *notificationServiceImpl) FlushCaches(ctx context.Context, actor string) []CacheState {
+	flushed := serviceInstance.CacheStates(ctx)
+	serviceInstance.tenantRepo.FlushCaches()
+	serviceInstance.senderMutex.Lock()
+	for _, sender := range serviceInstance.emailSenders {
+		// Pooled connections are still authenticated with the credentials the sender
+		// was built from; sends already in progress finish on theirs.
+		if smtpSender, isSMTP := sender.(*SMTPEmailSender); isSMTP {
+			smtpSender.Close()
+		}
+	}
+	serviceInstance.emailSenders = make(map[string]EmailSender)
+	serviceInstance.smsSenders = make(map[string]SmsSender)
+	serviceInstance.senderMutex.Unlock()
+
+	attributes := []any{"actor", actor}
+	for _, state := range flushed {
+		attributes = append(attributes, state.Name+"_entries", state.Entries)
+	}
+	serviceInstance.logger.Info("audit_caches_flushed", attributes...)
+	return flushed
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestFlushCachesForcesFreshTenantAndSenderLookups(t *testing.T) {
+	serviceInstance, _, _ := newDailyReportTestService(t, nil, nil)
+	serviceInstance.defaultEmailSender = nil
+	var auditLog bytes.Buffer
+	serviceInstance.logger = slog.New(slog.NewTextHandler(&auditLog, &slog.HandlerOptions{}))
+	ctx := context.Background()
+
+	resolveAndBuildSender := func() EmailSender {
+		t.Helper()
+		runtimeCfg, err := serviceInstance.tenantRepo.ResolveByID(ctx, "tenant-report")
+		if err != nil {
+			t.Fatalf("resolve tenant: %v", err)
+		}
+		sender, err := serviceInstance.emailSenderForTenant(runtimeCfg)
+		if err != nil {
+			t.Fatalf("email sender: %v", err)
+		}
+		return sender
+	}
+	first := resolveAndBuildSender()
+	if resolveAndBuildSender() != first {
+		t.Fatalf("expected the cached sender to be reused")
+	}
+	before := cacheStatesByName(serviceInstance.CacheStates(ctx))
+	if runtime := before[CacheTenantRuntime]; runtime.Entries != 1 || runtime.Hits != 1 || runtime.Misses != 1 {
+		t.Fatalf("unexpected runtime cache state %+v", runtime)
+	}
+	if email := before[CacheEmailSender]; email.Entries != 1 || email.Hits != 1 || email.Misses != 1 {
+		t.Fatalf("unexpected email sender cache state %+v", email)
+	}
+
+	flushed := cacheStatesByName(serviceInstance.FlushCaches(ctx, "ops@report.example"))
+	if flushed[CacheEmailSender].Entries != 1 || flushed[CacheTenantRuntime].Entries != 1 {
+		t.Fatalf("expected the flush to report what the caches held, got %+v", flushed)
+	}
+	emptied := cacheStatesByName(serviceInstance.CacheStates(ctx))
+	if emptied[CacheTenantRuntime].Entries != 0 || emptied[CacheEmailSender].Entries != 0 || emptied[CacheEmailSender].Hits != 1 {
+		t.Fatalf("expected empty caches with their counters kept, got %+v", emptied)
+	}
+	if !strings.Contains(auditLog.String(), "audit_caches_flushed") || !strings.Contains(auditLog.String(), "actor=ops@report.example") {
+		t.Fatalf("expected an audited flush, got %q", auditLog.String())
+	}
+
+	if resolveAndBuildSender() == first {
+		t.Fatalf("expected a new sender after the flush")
+	}
+	after := cacheStatesByName(serviceInstance.CacheStates(ctx))
+	if runtime := after[CacheTenantRuntime]; runtime.Misses != 2 || runtime.Hits != 1 {
+		t.Fatalf("expected the resolve after the flush to miss, got %+v", runtime)
+	}
+	if email := after[CacheEmailSender]; email.Misses != 2 || email.Hits != 1 {
+		t.Fatalf("expected the sender lookup after the flush to miss, got %+v", email)
+	}
+}
+
+func cacheStatesByName(states []CacheState) map[string]CacheState {
+	byName := make(map[string]CacheState, len(states))
+	for _, state := range states {
+		byName[state.Name] = state
+	}
+	return byName
+}
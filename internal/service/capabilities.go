@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+)
+
+// Capabilities describes what a tenant can send. A zero MaxScheduleHorizonSeconds means
+// notifications may be scheduled arbitrarily far ahead.
+type Capabilities struct {
+	NotificationTypes         []model.NotificationType `json:"notification_types"`
+	MaxAttachmentCount        int                      `json:"max_attachment_count"`
+	MaxAttachmentSizeBytes    int                      `json:"max_attachment_size_bytes"`
+	MaxAttachmentsTotalBytes  int                      `json:"max_attachments_total_bytes"`
+	AttachmentDataPersisted   bool                     `json:"attachment_data_persisted"`
+	MaxScheduleHorizonSeconds int64                    `json:"max_schedule_horizon_seconds"`
+}
+
+func (serviceInstance *notificationServiceImpl) GetCapabilities(ctx context.Context) (Capabilities, error) {
+	runtimeCfg, err := serviceInstance.requireTenant(ctx)
+	if err != nil {
+		return Capabilities{}, err
+	}
+	return Capabilities{
+		NotificationTypes:         serviceInstance.enabledNotificationTypes(runtimeCfg),
+		MaxAttachmentCount:        model.MaxNotificationAttachmentCount,
+		MaxAttachmentSizeBytes:    model.MaxNotificationAttachmentSizeBytes,
+		MaxAttachmentsTotalBytes:  model.MaxNotificationAttachmentsTotalBytes,
+		AttachmentDataPersisted:   !runtimeCfg.Tenant.AttachmentMetadataOnly,
+		MaxScheduleHorizonSeconds: int64(serviceInstance.scheduleHorizon() / time.Second),
+	}, nil
+}
+
+// enabledNotificationTypes lists the channels whose sender resolves for the tenant,
+// either from tenant credentials or from a process-wide default sender.
+func (serviceInstance *notificationServiceImpl) enabledNotificationTypes(runtimeCfg tenant.RuntimeConfig) []model.NotificationType {
+	notificationTypes := make([]model.NotificationType, 0, 2)
+	if _, err := serviceInstance.emailSenderForTenant(runtimeCfg); err == nil {
+		notificationTypes = append(notificationTypes, model.NotificationEmail)
+	}
+	if _, err := serviceInstance.smsSenderForTenant(runtimeCfg); err == nil {
+		notificationTypes = append(notificationTypes, model.NotificationSMS)
+	}
+	return notificationTypes
+}
+
+func (serviceInstance *notificationServiceImpl) scheduleHorizon() time.Duration {
+	return time.Duration(serviceInstance.config.MaxScheduleHorizonDays) * 24 * time.Hour
+}
+
+func (serviceInstance *notificationServiceImpl) beyondScheduleHorizon(scheduledFor *time.Time, now time.Time) bool {
+	horizon := serviceInstance.scheduleHorizon()
+	return horizon > 0 && scheduledFor != nil && scheduledFor.After(now.Add(horizon))
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+)
+
+func TestGetCapabilitiesReportsEmailOnlyWithoutSMSCredentials(t *testing.T) {
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), nil, nil)
+
+	capabilities, err := serviceInstance.GetCapabilities(tenantContextWithoutSMS())
+	if err != nil {
+		t.Fatalf("capabilities error: %v", err)
+	}
+	if !reflect.DeepEqual(capabilities.NotificationTypes, []model.NotificationType{model.NotificationEmail}) {
+		t.Fatalf("expected email-only capabilities, got %v", capabilities.NotificationTypes)
+	}
+	if capabilities.MaxAttachmentCount != model.MaxNotificationAttachmentCount ||
+		capabilities.MaxAttachmentSizeBytes != model.MaxNotificationAttachmentSizeBytes ||
+		capabilities.MaxAttachmentsTotalBytes != model.MaxNotificationAttachmentsTotalBytes {
+		t.Fatalf("unexpected attachment limits %+v", capabilities)
+	}
+	if !capabilities.AttachmentDataPersisted || capabilities.MaxScheduleHorizonSeconds != 0 {
+		t.Fatalf("unexpected defaults %+v", capabilities)
+	}
+
+	withSMS, err := serviceInstance.GetCapabilities(tenantContext())
+	if err != nil {
+		t.Fatalf("capabilities error: %v", err)
+	}
+	if !reflect.DeepEqual(withSMS.NotificationTypes, []model.NotificationType{model.NotificationEmail, model.NotificationSMS}) {
+		t.Fatalf("expected email and sms capabilities, got %v", withSMS.NotificationTypes)
+	}
+}
+
+func TestGetCapabilitiesReflectsTenantAndServerSettings(t *testing.T) {
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), nil, nil)
+	serviceInstance.config.MaxScheduleHorizonDays = 30
+
+	capabilities, err := serviceInstance.GetCapabilities(metadataOnlyTenantContext())
+	if err != nil {
+		t.Fatalf("capabilities error: %v", err)
+	}
+	if capabilities.AttachmentDataPersisted || capabilities.MaxScheduleHorizonSeconds != int64((30*24*time.Hour)/time.Second) {
+		t.Fatalf("unexpected capabilities %+v", capabilities)
+	}
+	if _, err := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), nil, nil).GetCapabilities(context.Background()); !errors.Is(err, ErrMissingTenantContext) {
+		t.Fatalf("expected missing tenant error, got %v", err)
+	}
+}
+
+func TestScheduleHorizonRejectsDistantSchedules(t *testing.T) {
+	database := openIsolatedDatabase(t)
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(database, &stubEmailSender{}, &stubSmsSender{})
+	serviceInstance.config.MaxScheduleHorizonDays = 7
+
+	distant := time.Now().UTC().Add(8 * 24 * time.Hour)
+	if _, err := serviceInstance.SendNotification(tenantContext(), mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", &distant, nil)); !errors.Is(err, ErrScheduleBeyondHorizon) {
+		t.Fatalf("expected ErrScheduleBeyondHorizon, got %v", err)
+	}
+
+	nearby := time.Now().UTC().Add(24 * time.Hour)
+	response, err := serviceInstance.SendNotification(tenantContext(), mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", &nearby, nil))
+	if err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	if _, err := serviceInstance.RescheduleNotification(tenantContext(), response.NotificationID, distant); !errors.Is(err, ErrScheduleBeyondHorizon) {
+		t.Fatalf("expected reschedule beyond the horizon to fail, got %v", err)
+	}
+}
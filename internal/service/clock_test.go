@@ -0,0 +1,107 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/config"
+	"github.com/tyemirov/pinguin/internal/model"
+)
+
+// clockTestNow is years ahead of the wall clock, so a decision that read time.Now
+// instead of the injected clock would come out differently.
+var clockTestNow = time.Date(2040, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func newClockTestService(t *testing.T, clock *adjustableClock, emailSender *stubEmailSender) *notificationServiceImpl {
+	t.Helper()
+	configuration := config.Config{MaxRetries: 3, RetryIntervalSec: 1, MaxScheduleHorizonDays: 1}
+	return NewNotificationServiceWithSenders(openIsolatedDatabase(t), newDiscardLogger(), configuration, nil, emailSender, &stubSmsSender{}, WithClock(clock)).(*notificationServiceImpl)
+}
+
+func TestInjectedClockDecidesScheduleAndHorizon(t *testing.T) {
+	emailSender := &stubEmailSender{}
+	serviceInstance := newClockTestService(t, &adjustableClock{now: clockTestNow}, emailSender)
+
+	due := clockTestNow.Add(-time.Minute)
+	response, err := serviceInstance.SendNotification(tenantContext(), mustNotificationRequest(t, model.NotificationEmail, "due@example.com", "Subject", "Body", &due, nil))
+	if err != nil {
+		t.Fatalf("send due: %v", err)
+	}
+	if response.Status != model.StatusSent || emailSender.callCount != 1 {
+		t.Fatalf("expected a schedule before the injected now to send immediately, status=%s calls=%d", response.Status, emailSender.callCount)
+	}
+
+	later := clockTestNow.Add(time.Hour)
+	response, err = serviceInstance.SendNotification(tenantContext(), mustNotificationRequest(t, model.NotificationEmail, "later@example.com", "Subject", "Body", &later, nil))
+	if err != nil {
+		t.Fatalf("send later: %v", err)
+	}
+	if response.Status != model.StatusQueued || emailSender.callCount != 1 {
+		t.Fatalf("expected a schedule after the injected now to stay queued, status=%s calls=%d", response.Status, emailSender.callCount)
+	}
+	stored, err := serviceInstance.GetNotificationStatus(tenantContext(), response.NotificationID)
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if !response.CreatedAt.Equal(clockTestNow) || !stored.CreatedAt.Equal(clockTestNow) {
+		t.Fatalf("expected the insert stamped at the injected now, got response %s stored %s", response.CreatedAt, stored.CreatedAt)
+	}
+
+	beyond := clockTestNow.Add(48 * time.Hour)
+	if _, err := serviceInstance.SendNotification(tenantContext(), mustNotificationRequest(t, model.NotificationEmail, "beyond@example.com", "Subject", "Body", &beyond, nil)); !errors.Is(err, ErrScheduleBeyondHorizon) {
+		t.Fatalf("expected the horizon to be measured from the injected now, got %v", err)
+	}
+	if _, err := serviceInstance.RescheduleNotification(tenantContext(), response.NotificationID, beyond); !errors.Is(err, ErrScheduleBeyondHorizon) {
+		t.Fatalf("expected reschedule to measure the horizon from the injected now, got %v", err)
+	}
+}
+
+func TestInjectedClockDecidesExpiry(t *testing.T) {
+	emailSender := &stubEmailSender{}
+	serviceInstance := newClockTestService(t, &adjustableClock{now: clockTestNow}, emailSender)
+
+	request, err := mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", nil, nil).WithExpiresAt(clockTestNow.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("expiry: %v", err)
+	}
+	response, err := serviceInstance.SendNotification(tenantContext(), request)
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if response.Status != model.StatusCancelled || response.CancelReason != model.CancelReasonExpired || emailSender.callCount != 0 {
+		t.Fatalf("expected the injected now to expire the notification, status=%s reason=%s calls=%d", response.Status, response.CancelReason, emailSender.callCount)
+	}
+	if !response.UpdatedAt.Equal(clockTestNow) {
+		t.Fatalf("expected the expiry stamped at the injected now, got %s", response.UpdatedAt)
+	}
+}
+
+func TestInjectedClockStampsRescheduleAndCancel(t *testing.T) {
+	clock := &adjustableClock{now: clockTestNow}
+	serviceInstance := newClockTestService(t, clock, &stubEmailSender{})
+
+	scheduledFor := clockTestNow.Add(time.Hour)
+	created, err := serviceInstance.SendNotification(tenantContext(), mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", &scheduledFor, nil))
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	clock.now = clockTestNow.Add(10 * time.Minute)
+	rescheduled, err := serviceInstance.RescheduleNotification(tenantContext(), created.NotificationID, clockTestNow.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("reschedule: %v", err)
+	}
+	if !rescheduled.UpdatedAt.Equal(clock.now) {
+		t.Fatalf("expected reschedule stamped at %s, got %s", clock.now, rescheduled.UpdatedAt)
+	}
+
+	clock.now = clockTestNow.Add(20 * time.Minute)
+	cancelled, err := serviceInstance.CancelNotification(tenantContext(), created.NotificationID, "", "")
+	if err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if !cancelled.UpdatedAt.Equal(clock.now) {
+		t.Fatalf("expected cancel stamped at %s, got %s", clock.now, cancelled.UpdatedAt)
+	}
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+	"github.com/tyemirov/utils/scheduler"
+)
+
+const (
+	dailyReportCheckInterval = time.Minute
+	dailyReportSubjectFormat = "Pinguin daily report for %s (%s)"
+)
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now().UTC()
+}
+
+// dailyReportJob emails each opted-in tenant's admins a digest of the previous local day.
+// Runs are claimed per (tenant, date) in report_runs, so restarts never send a day twice.
+type dailyReportJob struct {
+	serviceInstance *notificationServiceImpl
+	clock           scheduler.Clock
+}
+
+func newDailyReportJob(serviceInstance *notificationServiceImpl, clock scheduler.Clock) *dailyReportJob {
+	return &dailyReportJob{serviceInstance: serviceInstance, clock: clock}
+}
+
+// StartDailyReportWorker checks every minute for tenants whose daily report is due.
+func (serviceInstance *notificationServiceImpl) StartDailyReportWorker(ctx context.Context) {
+	if serviceInstance.tenantRepo == nil {
+		serviceInstance.logger.Warn("Daily report worker disabled: tenant repository unavailable")
+		return
+	}
+	job := newDailyReportJob(serviceInstance, serviceInstance.timeSource())
+	ticker := time.NewTicker(dailyReportCheckInterval)
+	defer ticker.Stop()
+	for {
+		job.RunOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce sends every due report that has not been claimed yet.
+func (job *dailyReportJob) RunOnce(ctx context.Context) {
+	logger := job.serviceInstance.logger
+	tenants, err := job.serviceInstance.tenantRepo.ListActiveTenants(ctx)
+	if err != nil {
+		logger.Error("Failed to list tenants for daily report", "error", err)
+		return
+	}
+	for _, tenantModel := range tenants {
+		if runErr := job.runForTenant(ctx, tenantModel); runErr != nil {
+			logger.Error("Daily report failed", "tenant_id", tenantModel.ID, "error", runErr)
+		}
+	}
+}
+
+func (job *dailyReportJob) runForTenant(ctx context.Context, tenantModel tenant.Tenant) error {
+	if job.serviceInstance.Draining() {
+		return nil
+	}
+	schedule, enabled, err := tenantModel.DailyReportSchedule()
+	if err != nil || !enabled {
+		return err
+	}
+	now := job.clock.Now()
+	if now.Before(schedule.SendTimeOn(now)) {
+		return nil
+	}
+	reportDay, err := model.NewDailyReportDay(now, schedule.Location)
+	if err != nil {
+		return err
+	}
+	recipients, err := job.serviceInstance.adminRecipients(ctx, tenantModel)
+	if err != nil {
+		return err
+	}
+	if len(recipients) == 0 {
+		job.serviceInstance.logger.Warn("Daily report skipped: tenant has no admins or support email", "tenant_id", tenantModel.ID)
+		return nil
+	}
+	runtimeCfg, err := job.serviceInstance.tenantRepo.ResolveByID(ctx, tenantModel.ID)
+	if err != nil {
+		return err
+	}
+	summary, err := model.SummarizeDailyActivity(ctx, job.serviceInstance.database, tenantModel.ID, reportDay)
+	if err != nil {
+		return err
+	}
+
+	claimed, err := model.ClaimReportRun(ctx, job.serviceInstance.database, tenantModel.ID, reportDay.Date(), now)
+	if err != nil || !claimed {
+		return err
+	}
+	subject := fmt.Sprintf(dailyReportSubjectFormat, tenantModel.DisplayName, reportDay.Date())
+	body := renderDailyReport(tenantModel, schedule.Location, summary)
+	tenantContext := tenant.WithRuntime(ctx, runtimeCfg)
+	runStatus := model.ReportRunSent
+	sentCount := 0
+	var sendErr error
+	for _, recipient := range recipients {
+		request, requestErr := model.NewNotificationRequest(model.NotificationEmail, recipient, subject, body, nil, nil)
+		if requestErr != nil {
+			sendErr = requestErr
+			break
+		}
+		if _, sendErr = job.serviceInstance.SendNotification(tenantContext, request.WithSource(model.NotificationSourceSystemReport)); sendErr != nil {
+			break
+		}
+		sentCount++
+	}
+	if sendErr != nil {
+		runStatus = model.ReportRunFailed
+	}
+	job.serviceInstance.logger.Info("Daily report processed", "tenant_id", tenantModel.ID, "report_date", reportDay.Date(), "status", runStatus, "notifications", sentCount)
+	if finishErr := model.FinishReportRun(ctx, job.serviceInstance.database, tenantModel.ID, reportDay.Date(), runStatus, sentCount, job.clock.Now()); finishErr != nil {
+		return finishErr
+	}
+	return sendErr
+}
+
+// adminRecipients prefers tenant admins and falls back to the support address.
+func (serviceInstance *notificationServiceImpl) adminRecipients(ctx context.Context, tenantModel tenant.Tenant) ([]string, error) {
+	adminEmails, err := serviceInstance.tenantRepo.ListTenantAdminEmails(ctx, tenantModel.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(adminEmails) > 0 {
+		return adminEmails, nil
+	}
+	if supportEmail := strings.TrimSpace(tenantModel.SupportEmail); supportEmail != "" {
+		return []string{supportEmail}, nil
+	}
+	return nil, nil
+}
+
+func renderDailyReport(tenantModel tenant.Tenant, location *time.Location, summary model.DailyActivitySummary) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "Daily activity report for %s\n", tenantModel.DisplayName)
+	fmt.Fprintf(&builder, "Day: %s (%s)\n\n", summary.Day.Date(), location.String())
+	fmt.Fprintf(&builder, "Sent: %d\n", summary.SentCount)
+	fmt.Fprintf(&builder, "Errored: %d\n", summary.ErroredCount)
+	fmt.Fprintf(&builder, "Cancelled: %d\n", summary.CancelledCount)
+	fmt.Fprintf(&builder, "Still queued: %d\n", summary.QueuedCount)
+	fmt.Fprintf(&builder, "Average retry count: %.2f\n", summary.AverageRetryCount)
+	writeReportCounts(&builder, "Errors by channel", summary.ErroredByChannel)
+	writeReportCounts(&builder, "Cancellations by reason", summary.CancelledByReason)
+	fmt.Fprintf(&builder, "\nStuck (due before the day ended, still queued): %d\n", summary.StuckCount)
+	if summary.OldestStuckDueTime != nil {
+		fmt.Fprintf(&builder, "Oldest stuck due time: %s\n", summary.OldestStuckDueTime.In(location).Format(time.RFC3339))
+	}
+	return builder.String()
+}
+
+func writeReportCounts(builder *strings.Builder, heading string, counts []model.ReportCount) {
+	if len(counts) == 0 {
+		return
+	}
+	fmt.Fprintf(builder, "\n%s:\n", heading)
+	for _, count := range counts {
+		fmt.Fprintf(builder, "  %s: %d\n", count.Label, count.Count)
+	}
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+	"gorm.io/gorm"
+)
+
+type recordingReportEmailSender struct {
+	recipients []string
+	subjects   []string
+	messages   []string
+}
+
+func (sender *recordingReportEmailSender) SendEmail(_ context.Context, recipient string, subject string, message string, _ []model.EmailAttachment) error {
+	sender.recipients = append(sender.recipients, recipient)
+	sender.subjects = append(sender.subjects, subject)
+	sender.messages = append(sender.messages, message)
+	return nil
+}
+
+func newDailyReportTestService(t *testing.T, dailyReport *tenant.BootstrapDailyReport, admins []string) (*notificationServiceImpl, *recordingReportEmailSender, *gorm.DB) {
+	t.Helper()
+	database := openIsolatedDatabase(t)
+	if err := database.AutoMigrate(&tenant.Tenant{}, &tenant.TenantDomain{}, &tenant.TenantAdmin{}, &tenant.EmailProfile{}, &tenant.SMSProfile{}); err != nil {
+		t.Fatalf("tenant migration: %v", err)
+	}
+	keeper, err := tenant.NewSecretKeeper(strings.Repeat("a", 64))
+	if err != nil {
+		t.Fatalf("secret keeper: %v", err)
+	}
+	if err := tenant.Bootstrap(context.Background(), database, keeper, tenant.BootstrapConfig{
+		Tenants: []tenant.BootstrapTenant{
+			{
+				ID:           "tenant-report",
+				DisplayName:  "Report Tenant",
+				SupportEmail: "support@report.example",
+				Enabled:      ptrBool(true),
+				Domains:      []string{"report.example"},
+				Admins:       admins,
+				EmailProfile: tenant.BootstrapEmailProfile{
+					Host:        "smtp.report.example",
+					Port:        587,
+					Username:    "smtp-user",
+					Password:    "smtp-pass",
+					FromAddress: "noreply@report.example",
+				},
+				DailyReport: dailyReport,
+			},
+		},
+	}); err != nil {
+		t.Fatalf("bootstrap: %v", err)
+	}
+	emailSender := &recordingReportEmailSender{}
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(database, emailSender, &stubSmsSender{})
+	serviceInstance.tenantRepo = tenant.NewRepository(database, keeper)
+	return serviceInstance, emailSender, database
+}
+
+func TestDailyReportJobSendsOncePerTenantAndDate(t *testing.T) {
+	serviceInstance, emailSender, database := newDailyReportTestService(t,
+		&tenant.BootstrapDailyReport{Enabled: true, SendAt: "07:00", Timezone: "UTC"},
+		[]string{"ops@report.example", "lead@report.example"},
+	)
+	clock := &adjustableClock{now: time.Date(2026, 4, 2, 7, 5, 0, 0, time.UTC)}
+
+	newDailyReportJob(serviceInstance, clock).RunOnce(context.Background())
+	if len(emailSender.recipients) != 2 {
+		t.Fatalf("expected one report per admin, got %v", emailSender.recipients)
+	}
+
+	clock.now = clock.now.Add(time.Hour)
+	newDailyReportJob(serviceInstance, clock).RunOnce(context.Background())
+	if len(emailSender.recipients) != 2 {
+		t.Fatalf("expected restarted job not to resend the same day, got %v", emailSender.recipients)
+	}
+
+	clock.now = time.Date(2026, 4, 3, 7, 0, 0, 0, time.UTC)
+	newDailyReportJob(serviceInstance, clock).RunOnce(context.Background())
+	if len(emailSender.recipients) != 4 {
+		t.Fatalf("expected the next day to be reported, got %v", emailSender.recipients)
+	}
+
+	reportNotifications, err := model.ListNotifications(context.Background(), database, "tenant-report", model.NotificationListFilters{})
+	if err != nil {
+		t.Fatalf("list notifications: %v", err)
+	}
+	if len(reportNotifications) != 4 {
+		t.Fatalf("expected report notifications in the tenant list, got %d", len(reportNotifications))
+	}
+	for _, notification := range reportNotifications {
+		if notification.Source != model.NotificationSourceSystemReport || notification.Status != model.StatusSent {
+			t.Fatalf("unexpected report notification %s/%s", notification.Source, notification.Status)
+		}
+	}
+	var runs []model.ReportRun
+	if err := database.Find(&runs).Error; err != nil {
+		t.Fatalf("list runs: %v", err)
+	}
+	if len(runs) != 2 || runs[0].Status != model.ReportRunSent || runs[0].NotificationCount != 2 {
+		t.Fatalf("unexpected report runs %+v", runs)
+	}
+}
+
+func TestDailyReportJobUsesTenantTimezone(t *testing.T) {
+	serviceInstance, emailSender, database := newDailyReportTestService(t,
+		&tenant.BootstrapDailyReport{Enabled: true, SendAt: "07:00", Timezone: "America/New_York"},
+		nil,
+	)
+	lateLocalEvening := time.Date(2026, 3, 9, 3, 30, 0, 0, time.UTC)
+	nextLocalDay := time.Date(2026, 3, 9, 4, 30, 0, 0, time.UTC)
+	for _, record := range []model.Notification{
+		{TenantID: "tenant-report", NotificationID: "in-window", NotificationType: model.NotificationEmail, Recipient: "user@example.com", Status: model.StatusSent, CreatedAt: lateLocalEvening, UpdatedAt: lateLocalEvening},
+		{TenantID: "tenant-report", NotificationID: "next-day", NotificationType: model.NotificationEmail, Recipient: "user@example.com", Status: model.StatusSent, CreatedAt: nextLocalDay, UpdatedAt: nextLocalDay},
+	} {
+		if err := model.CreateNotification(context.Background(), database, &record); err != nil {
+			t.Fatalf("seed notification: %v", err)
+		}
+	}
+
+	clock := &adjustableClock{now: time.Date(2026, 3, 9, 10, 30, 0, 0, time.UTC)}
+	job := newDailyReportJob(serviceInstance, clock)
+	job.RunOnce(context.Background())
+	if len(emailSender.recipients) != 0 {
+		t.Fatalf("expected no report before 07:00 New York time, got %v", emailSender.recipients)
+	}
+
+	clock.now = time.Date(2026, 3, 9, 11, 0, 0, 0, time.UTC)
+	job.RunOnce(context.Background())
+	if len(emailSender.recipients) != 1 || emailSender.recipients[0] != "support@report.example" {
+		t.Fatalf("expected one report to the support address, got %v", emailSender.recipients)
+	}
+	if !strings.Contains(emailSender.subjects[0], "2026-03-08") {
+		t.Fatalf("expected the local previous day in the subject, got %q", emailSender.subjects[0])
+	}
+	if !strings.Contains(emailSender.messages[0], "Sent: 1\n") || !strings.Contains(emailSender.messages[0], "America/New_York") {
+		t.Fatalf("expected only the local-day notification to be counted, got:\n%s", emailSender.messages[0])
+	}
+}
+
+func TestDailyReportJobSkipsDisabledTenants(t *testing.T) {
+	serviceInstance, emailSender, database := newDailyReportTestService(t, nil, []string{"ops@report.example"})
+	newDailyReportJob(serviceInstance, &adjustableClock{now: time.Date(2026, 4, 2, 12, 0, 0, 0, time.UTC)}).RunOnce(context.Background())
+	if len(emailSender.recipients) != 0 {
+		t.Fatalf("expected reports to stay off by default, got %v", emailSender.recipients)
+	}
+	var runCount int64
+	if err := database.Model(&model.ReportRun{}).Count(&runCount).Error; err != nil || runCount != 0 {
+		t.Fatalf("expected no report runs, got %d (%v)", runCount, err)
+	}
+}
+
+func TestDailyReportJobWaitsWhileDraining(t *testing.T) {
+	serviceInstance, emailSender, database := newDailyReportTestService(t,
+		&tenant.BootstrapDailyReport{Enabled: true, SendAt: "07:00", Timezone: "UTC"},
+		[]string{"ops@report.example"},
+	)
+	serviceInstance.drain.draining = true
+	newDailyReportJob(serviceInstance, &adjustableClock{now: time.Date(2026, 4, 2, 7, 5, 0, 0, time.UTC)}).RunOnce(context.Background())
+	if len(emailSender.recipients) != 0 {
+		t.Fatalf("expected no reports while draining, got %v", emailSender.recipients)
+	}
+	var runCount int64
+	if err := database.Model(&model.ReportRun{}).Count(&runCount).Error; err != nil || runCount != 0 {
+		t.Fatalf("expected the run to stay unclaimed for another instance, got %d (%v)", runCount, err)
+	}
+}
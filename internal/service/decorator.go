@@ -0,0 +1,33 @@
+package service
+
+import "log/slog"
+
+// Decorator wraps a NotificationService with one cross-cutting concern. A decorator
+// embeds the service it wraps and overrides only the methods its concern touches, so
+// every other call passes straight through.
+type Decorator func(NotificationService) NotificationService
+
+// Chain wraps core in decorators, the first one outermost: a call passes through the
+// decorators in the order listed before it reaches core.
+func Chain(core NotificationService, decorators ...Decorator) NotificationService {
+	wrapped := core
+	for index := len(decorators) - 1; index >= 0; index-- {
+		wrapped = decorators[index](wrapped)
+	}
+	return wrapped
+}
+
+// ProductionDecorators lists the chain the server assembles around the core service,
+// outermost first:
+//
+//  1. WithAudit, so every send is audited, including those a later decorator refuses.
+//  2. WithDrainGate, which refuses new sends while the instance drains.
+//
+// Request validation, persistence and dispatch stay in the core service. New admission
+// concerns such as quotas or suppression belong between the drain gate and the core.
+func ProductionDecorators(logger *slog.Logger) []Decorator {
+	return []Decorator{
+		WithAudit(logger),
+		WithDrainGate(),
+	}
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/tyemirov/pinguin/internal/model"
+)
+
+// recordingInnerService stands in for the core service under a decorator chain. Calls
+// to methods it does not override panic through the nil embedded interface.
+type recordingInnerService struct {
+	NotificationService
+	calls    *[]string
+	draining bool
+	response model.NotificationResponse
+	err      error
+}
+
+func (inner *recordingInnerService) SendNotification(context.Context, model.NotificationRequest) (model.NotificationResponse, error) {
+	*inner.calls = append(*inner.calls, "core")
+	return inner.response, inner.err
+}
+
+func (inner *recordingInnerService) SendNotificationGroup(context.Context, model.NotificationGroupRequest) (model.NotificationGroupResponse, error) {
+	*inner.calls = append(*inner.calls, "core")
+	return model.NotificationGroupResponse{GroupID: "group-1", Status: model.StatusSent}, nil
+}
+
+func (inner *recordingInnerService) Draining() bool {
+	return inner.draining
+}
+
+// tracingDecorator records when a call passes through it.
+func tracingDecorator(name string, calls *[]string) Decorator {
+	return func(inner NotificationService) NotificationService {
+		return &tracingService{NotificationService: inner, name: name, calls: calls}
+	}
+}
+
+type tracingService struct {
+	NotificationService
+	name  string
+	calls *[]string
+}
+
+func (service *tracingService) SendNotification(ctx context.Context, request model.NotificationRequest) (model.NotificationResponse, error) {
+	*service.calls = append(*service.calls, service.name)
+	return service.NotificationService.SendNotification(ctx, request)
+}
+
+func TestChainRunsDecoratorsOutermostFirst(t *testing.T) {
+	var calls []string
+	chain := Chain(&recordingInnerService{calls: &calls}, tracingDecorator("first", &calls), tracingDecorator("second", &calls))
+	if _, err := chain.SendNotification(tenantContext(), mustNotificationRequest(t, model.NotificationSMS, "+15555550100", "", "Body", nil, nil)); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if strings.Join(calls, ",") != "first,second,core" {
+		t.Fatalf("expected the first decorator outermost, got %v", calls)
+	}
+	if Chain(&recordingInnerService{calls: &calls}).Draining() {
+		t.Fatalf("expected an empty chain to pass calls through")
+	}
+}
+
+func TestProductionChainAuditsSendsTheDrainGateRefuses(t *testing.T) {
+	var calls []string
+	var auditLog bytes.Buffer
+	inner := &recordingInnerService{calls: &calls, response: model.NotificationResponse{NotificationID: "notif-1", Status: model.StatusSent}}
+	chain := Chain(inner, ProductionDecorators(slog.New(slog.NewTextHandler(&auditLog, nil)))...)
+	request := mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", nil, nil)
+
+	if _, err := chain.SendNotification(tenantContext(), request); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	inner.draining = true
+	if _, err := chain.SendNotification(tenantContext(), request); !errors.Is(err, ErrDraining) {
+		t.Fatalf("expected the drain gate to refuse the send, got %v", err)
+	}
+	if len(calls) != 1 {
+		t.Fatalf("expected only the first send to reach the core, got %v", calls)
+	}
+	lines := strings.Split(strings.TrimSpace(auditLog.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "accepted=true") || !strings.Contains(lines[1], "accepted=false") || !strings.Contains(lines[1], ErrDraining.Error()) {
+		t.Fatalf("expected the audit, outermost, to record both sends, got %q", auditLog.String())
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+)
+
+// ErrDeliveryCheckUnsupported reports a sender that cannot verify credentials without sending.
+var ErrDeliveryCheckUnsupported = errors.New("delivery check unsupported by the configured sender")
+
+// CredentialVerifier is implemented by senders that can confirm their provider
+// credentials with a handshake that delivers nothing.
+type CredentialVerifier interface {
+	VerifyCredentials(ctx context.Context) error
+}
+
+// DeliveryCheck is the outcome of a provider credential check. Error explains a
+// failed check.
+type DeliveryCheck struct {
+	NotificationType model.NotificationType `json:"notification_type"`
+	Success          bool                   `json:"success"`
+	Error            string                 `json:"error,omitempty"`
+}
+
+// TestTenantDelivery checks the tenant's provider credentials for one channel. It
+// neither delivers a message nor stores a notification. Missing or rejected
+// credentials are reported in the result rather than as an error.
+func (serviceInstance *notificationServiceImpl) TestTenantDelivery(ctx context.Context, notificationType model.NotificationType) (DeliveryCheck, error) {
+	runtimeCfg, err := serviceInstance.requireTenant(ctx)
+	if err != nil {
+		return DeliveryCheck{}, err
+	}
+	var sender any
+	var senderErr error
+	operationTimeoutSec := serviceInstance.config.OperationTimeoutSec
+	switch notificationType {
+	case model.NotificationEmail:
+		sender, senderErr = serviceInstance.emailSenderForTenant(runtimeCfg)
+		operationTimeoutSec = serviceInstance.smtpTimeoutsForTenant(runtimeCfg.Email).OperationTimeoutSec
+	case model.NotificationSMS:
+		sender, senderErr = serviceInstance.smsSenderForTenant(runtimeCfg)
+	default:
+		return DeliveryCheck{}, model.ErrNotificationTypeUnsupported
+	}
+	check := DeliveryCheck{NotificationType: notificationType}
+	if senderErr != nil {
+		check.Error = senderErr.Error()
+		return check, nil
+	}
+	verifier, ok := sender.(CredentialVerifier)
+	if !ok {
+		return DeliveryCheck{}, ErrDeliveryCheckUnsupported
+	}
+
+	if operationTimeout := time.Duration(operationTimeoutSec) * time.Second; operationTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, operationTimeout)
+		defer cancel()
+	}
+	if verifyErr := verifier.VerifyCredentials(ctx); verifyErr != nil {
+		check.Error = verifyErr.Error()
+	} else {
+		check.Success = true
+	}
+	serviceInstance.logger.Info("tenant_delivery_test",
+		"tenant_id", runtimeCfg.Tenant.ID,
+		"notification_type", notificationType,
+		"success", check.Success,
+		"error", check.Error,
+	)
+	return check, nil
+}
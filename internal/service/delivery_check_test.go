@@ -0,0 +1,238 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/tyemirov/pinguin/internal/config"
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+)
+
+// fakeSMTPServer accepts AUTH PLAIN for one username/password pair and records every
+// command it receives so tests can prove no mail transaction was started.
+type fakeSMTPServer struct {
+	listener net.Listener
+	username string
+	password string
+	mutex    sync.Mutex
+	commands []string
+}
+
+func startFakeSMTPServer(t *testing.T, username string, password string) *fakeSMTPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := &fakeSMTPServer{listener: listener, username: username, password: password}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			connection, acceptErr := listener.Accept()
+			if acceptErr != nil {
+				return
+			}
+			go server.serve(connection)
+		}
+	}()
+	return server
+}
+
+func (server *fakeSMTPServer) port() int {
+	return server.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (server *fakeSMTPServer) receivedCommands() []string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return append([]string(nil), server.commands...)
+}
+
+func (server *fakeSMTPServer) serve(connection net.Conn) {
+	defer connection.Close()
+	reader := bufio.NewReader(connection)
+	reply := func(line string) { fmt.Fprintf(connection, "%s\r\n", line) }
+	reply("220 localhost ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb, argument, _ := strings.Cut(line, " ")
+		verb = strings.ToUpper(verb)
+		server.mutex.Lock()
+		server.commands = append(server.commands, verb)
+		server.mutex.Unlock()
+		switch verb {
+		case "EHLO":
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			_, encoded, _ := strings.Cut(argument, " ")
+			decoded, _ := base64.StdEncoding.DecodeString(encoded)
+			if string(decoded) == "\x00"+server.username+"\x00"+server.password {
+				reply("235 2.7.0 Authentication successful")
+			} else {
+				reply("535 5.7.8 Authentication credentials invalid")
+			}
+		case "NOOP":
+			reply("250 2.0.0 OK")
+		case "QUIT":
+			reply("221 2.0.0 Bye")
+			return
+		default:
+			reply("502 5.5.2 Command not implemented")
+		}
+	}
+}
+
+// fakeTwilioAccounts serves the Twilio account resource for one SID/token pair.
+func fakeTwilioAccounts(accountSID string, authToken string) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodGet || request.URL.Path != "/2010-04-01/Accounts/"+accountSID+".json" {
+			http.Error(writer, `{"message":"unexpected request"}`, http.StatusNotFound)
+			return
+		}
+		username, password, _ := request.BasicAuth()
+		if username != accountSID || password != authToken {
+			http.Error(writer, `{"code":20003,"message":"Authenticate"}`, http.StatusUnauthorized)
+			return
+		}
+		writer.Write([]byte(`{"sid":"` + accountSID + `","status":"active"}`))
+	})
+}
+
+func newDeliveryCheckService() *notificationServiceImpl {
+	return &notificationServiceImpl{
+		logger:       newDiscardLogger(),
+		config:       config.Config{ConnectionTimeoutSec: 5, OperationTimeoutSec: 5},
+		emailSenders: make(map[string]EmailSender),
+		smsSenders:   make(map[string]SmsSender),
+	}
+}
+
+func deliveryCheckContext(smtpPort int, smtpPassword string) context.Context {
+	runtimeCfg := baseRuntimeConfig()
+	runtimeCfg.Email.Host = "127.0.0.1"
+	runtimeCfg.Email.Port = smtpPort
+	runtimeCfg.Email.Password = smtpPassword
+	return tenant.WithRuntime(context.Background(), runtimeCfg)
+}
+
+func TestTestTenantDeliveryVerifiesSMTPCredentials(t *testing.T) {
+	testCases := []struct {
+		name          string
+		password      string
+		expectSuccess bool
+		expectedError string
+	}{
+		{name: "valid credentials", password: "smtp-pass", expectSuccess: true},
+		{name: "rejected credentials", password: "wrong-pass", expectedError: "failed to authenticate"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			smtpServer := startFakeSMTPServer(t, "smtp-user", "smtp-pass")
+			serviceInstance := newDeliveryCheckService()
+
+			check, err := serviceInstance.TestTenantDelivery(deliveryCheckContext(smtpServer.port(), testCase.password), model.NotificationEmail)
+			if err != nil {
+				t.Fatalf("TestTenantDelivery error: %v", err)
+			}
+			if check.Success != testCase.expectSuccess || !strings.Contains(check.Error, testCase.expectedError) {
+				t.Fatalf("unexpected check %+v", check)
+			}
+			sawNoop := false
+			for _, command := range smtpServer.receivedCommands() {
+				if command == "MAIL" || command == "RCPT" || command == "DATA" {
+					t.Fatalf("expected no mail transaction, got %v", smtpServer.receivedCommands())
+				}
+				sawNoop = sawNoop || command == "NOOP"
+			}
+			if sawNoop != testCase.expectSuccess {
+				t.Fatalf("expected NOOP only after a successful AUTH, got %v", smtpServer.receivedCommands())
+			}
+		})
+	}
+}
+
+func TestTestTenantDeliveryVerifiesTwilioCredentials(t *testing.T) {
+	testCases := []struct {
+		name          string
+		authToken     string
+		expectSuccess bool
+		expectedError string
+	}{
+		{name: "valid credentials", authToken: "sms-secret", expectSuccess: true},
+		{name: "rejected credentials", authToken: "wrong-secret", expectedError: "Authenticate"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			twilioEndpoint := fakeTwilioAccounts("AC123", "sms-secret")
+			serviceInstance := newDeliveryCheckService()
+			serviceInstance.smsSenders[testTenantID] = &TwilioSmsSender{
+				AccountSID: "AC123",
+				AuthToken:  testCase.authToken,
+				FromNumber: "+15550000000",
+				HTTPClient: &http.Client{Transport: roundTripFunc(func(request *http.Request) (*http.Response, error) {
+					recorder := httptest.NewRecorder()
+					twilioEndpoint.ServeHTTP(recorder, request)
+					return recorder.Result(), nil
+				})},
+				Logger: newDiscardLogger(),
+			}
+
+			check, err := serviceInstance.TestTenantDelivery(tenantContext(), model.NotificationSMS)
+			if err != nil {
+				t.Fatalf("TestTenantDelivery error: %v", err)
+			}
+			if check.Success != testCase.expectSuccess || !strings.Contains(check.Error, testCase.expectedError) {
+				t.Fatalf("unexpected check %+v", check)
+			}
+		})
+	}
+}
+
+func TestTestTenantDeliveryReportsMissingCredentialsAndUnsupportedSenders(t *testing.T) {
+	serviceInstance := newDeliveryCheckService()
+	check, err := serviceInstance.TestTenantDelivery(tenantContextWithoutSMS(), model.NotificationSMS)
+	if err != nil || check.Success || check.Error != ErrSMSDisabled.Error() {
+		t.Fatalf("expected a failed check for missing Twilio credentials, got %+v (%v)", check, err)
+	}
+	if _, err := serviceInstance.TestTenantDelivery(tenantContext(), model.NotificationType("push")); !errors.Is(err, model.ErrNotificationTypeUnsupported) {
+		t.Fatalf("expected unsupported type error, got %v", err)
+	}
+	if _, err := serviceInstance.TestTenantDelivery(context.Background(), model.NotificationEmail); !errors.Is(err, ErrMissingTenantContext) {
+		t.Fatalf("expected missing tenant error, got %v", err)
+	}
+
+	stubbed := newDeliveryCheckService()
+	stubbed.defaultEmailSender = &stubEmailSender{}
+	if _, err := stubbed.TestTenantDelivery(tenantContext(), model.NotificationEmail); !errors.Is(err, ErrDeliveryCheckUnsupported) {
+		t.Fatalf("expected unsupported sender error, got %v", err)
+	}
+}
+
+func TestSMTPVerifyCredentialsReportsDialFailure(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	port := strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+	listener.Close()
+	sender := NewSMTPEmailSender(SMTPConfig{Host: "127.0.0.1", Port: port, Timeouts: config.Config{ConnectionTimeoutSec: 1}}, newDiscardLogger())
+	if err := sender.VerifyCredentials(context.Background()); err == nil || !strings.Contains(err.Error(), "failed to dial") {
+		t.Fatalf("expected dial failure, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/pkg/logging"
+)
+
+// ProviderLatency summarizes how long a tenant's SendEmail or SendSms calls have taken.
+type ProviderLatency struct {
+	Provider   model.NotificationType `json:"provider"`
+	Dispatches int64                  `json:"dispatches"`
+	AverageMs  int64                  `json:"average_ms"`
+	MaxMs      int64                  `json:"max_ms"`
+	LastMs     int64                  `json:"last_ms"`
+}
+
+type dispatchLatencyKey struct {
+	tenantID string
+	provider model.NotificationType
+}
+
+type dispatchLatencyEntry struct {
+	dispatches int64
+	total      time.Duration
+	max        time.Duration
+	last       time.Duration
+}
+
+// dispatchLatencyRecorder keeps per-tenant, per-provider call latency for the operator views.
+type dispatchLatencyRecorder struct {
+	mutex   sync.Mutex
+	entries map[dispatchLatencyKey]*dispatchLatencyEntry
+}
+
+func newDispatchLatencyRecorder() *dispatchLatencyRecorder {
+	return &dispatchLatencyRecorder{entries: make(map[dispatchLatencyKey]*dispatchLatencyEntry)}
+}
+
+func (recorder *dispatchLatencyRecorder) observe(tenantID string, provider model.NotificationType, latency time.Duration) {
+	if recorder == nil {
+		return
+	}
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	key := dispatchLatencyKey{tenantID: tenantID, provider: provider}
+	entry, exists := recorder.entries[key]
+	if !exists {
+		entry = &dispatchLatencyEntry{}
+		recorder.entries[key] = entry
+	}
+	entry.dispatches++
+	entry.total += latency
+	entry.last = latency
+	if latency > entry.max {
+		entry.max = latency
+	}
+}
+
+func (recorder *dispatchLatencyRecorder) snapshot(tenantID string) []ProviderLatency {
+	if recorder == nil {
+		return nil
+	}
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	var latencies []ProviderLatency
+	for key, entry := range recorder.entries {
+		if key.tenantID != tenantID {
+			continue
+		}
+		latencies = append(latencies, ProviderLatency{
+			Provider:   key.provider,
+			Dispatches: entry.dispatches,
+			AverageMs:  (entry.total / time.Duration(entry.dispatches)).Milliseconds(),
+			MaxMs:      entry.max.Milliseconds(),
+			LastMs:     entry.last.Milliseconds(),
+		})
+	}
+	sort.Slice(latencies, func(left, right int) bool {
+		return latencies[left].Provider < latencies[right].Provider
+	})
+	return latencies
+}
+
+// callProvider times one SendEmail or SendSms call. It logs provider_latency_ms with
+// the recipient digested, as a dispatch_failed event unless the call succeeded or its
+// caller cancelled it, and feeds the latency and outcome to the metrics, pacer and
+// provider breakers.
+func (serviceInstance *notificationServiceImpl) callProvider(ctx context.Context, tenantID string, notificationID string, notificationType model.NotificationType, recipient string, call func() error) error {
+	startedAt := time.Now()
+	callErr := call()
+	latency := time.Since(startedAt)
+	serviceInstance.dispatchLatency.observe(tenantID, notificationType, latency)
+	// A call cancelled by its caller says nothing about the provider's health.
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		serviceInstance.dispatchPacer.record(tenantID, notificationType, callErr)
+		if breaker, opened := serviceInstance.providerBreakers.record(ctx, tenantID, notificationType, callErr, serviceInstance.currentTime()); opened {
+			serviceInstance.notifyBreakerOpened(ctx, breaker)
+		}
+	}
+	attributes := []any{
+		"tenant_id", tenantID,
+		"notification_id", notificationID,
+		"notification_type", notificationType,
+		"recipient_digest", DigestForLogging(recipient),
+		"provider_latency_ms", latency.Milliseconds(),
+		"success", callErr == nil,
+	}
+	event := logging.EventNotificationDispatchAttempted
+	if callErr != nil && !errors.Is(ctx.Err(), context.Canceled) {
+		event = logging.EventNotificationDispatchFailed
+		attributes = append(attributes, "error", callErr)
+	}
+	logging.LogEvent(ctx, serviceInstance.logger, event, "provider_dispatch", attributes...)
+	return callErr
+}
+
+// DigestForLogging returns a short, case-insensitive digest so recipients and subjects
+// can be correlated across log lines without being written out.
+// It matches the recipient_digest of notification snapshots.
+func DigestForLogging(value string) string {
+	return model.DigestRecipient(value)
+}
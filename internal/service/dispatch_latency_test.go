@@ -0,0 +1,111 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/pkg/logging"
+)
+
+func TestSendNotificationLogsProviderLatency(t *testing.T) {
+	database := openIsolatedDatabase(t)
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(database, &stubEmailSender{}, &stubSmsSender{})
+	var logOutput bytes.Buffer
+	serviceInstance.logger = slog.New(slog.NewJSONHandler(&logOutput, nil))
+
+	request := mustNotificationRequest(t, model.NotificationEmail, "User@Example.com", "Subject", "Body", nil, nil)
+	response, err := serviceInstance.SendNotification(tenantContext(), request)
+	if err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+
+	var dispatchEntry map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(logOutput.String()), "\n") {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("decode log line %q: %v", line, err)
+		}
+		if entry["msg"] == "provider_dispatch" {
+			dispatchEntry = entry
+		}
+	}
+	if dispatchEntry == nil {
+		t.Fatalf("expected a provider_dispatch log entry, got %s", logOutput.String())
+	}
+	if _, hasLatency := dispatchEntry["provider_latency_ms"].(float64); !hasLatency {
+		t.Fatalf("expected numeric provider_latency_ms, got %+v", dispatchEntry)
+	}
+	if dispatchEntry["tenant_id"] != testTenantID || dispatchEntry["notification_type"] != string(model.NotificationEmail) || dispatchEntry["notification_id"] != response.NotificationID {
+		t.Fatalf("unexpected dispatch log fields %+v", dispatchEntry)
+	}
+	if dispatchEntry["recipient_digest"] != DigestForLogging("user@example.com") || strings.Contains(logOutput.String(), "xample.com") {
+		t.Fatalf("expected only the recipient digest in logs, got %s", logOutput.String())
+	}
+	if latencies := serviceInstance.dispatchLatency.snapshot(testTenantID); len(latencies) != 1 || latencies[0].Dispatches != 1 {
+		t.Fatalf("expected the send to be recorded in latency metrics, got %+v", latencies)
+	}
+}
+
+func TestSendNotificationLogsDispatchTimingForDebugRequestsOnly(t *testing.T) {
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), &stubEmailSender{}, &stubSmsSender{})
+	var logOutput bytes.Buffer
+	serviceInstance.logger = slog.New(logging.NewDebugOverrideHandler(slog.NewTextHandler(&logOutput, &slog.HandlerOptions{Level: slog.LevelDebug}), slog.LevelInfo))
+
+	if _, err := serviceInstance.SendNotification(tenantContext(), mustNotificationRequest(t, model.NotificationEmail, "plain@example.com", "Subject", "Body", nil, nil)); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	if strings.Contains(logOutput.String(), "level=DEBUG") {
+		t.Fatalf("expected no debug lines without the override, got %s", logOutput.String())
+	}
+	response, err := serviceInstance.SendNotification(logging.WithDebug(tenantContext()), mustNotificationRequest(t, model.NotificationEmail, "Debug@Example.com", "Subject", "Body", nil, nil))
+	if err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	output := logOutput.String()
+	for _, fragment := range []string{"msg=send_prepared", "msg=send_dispatch_timing", "dispatch_ms=", "notification_id=" + response.NotificationID, "recipient_digest=" + DigestForLogging("debug@example.com")} {
+		if !strings.Contains(output, fragment) {
+			t.Fatalf("expected %q in the debug output, got %s", fragment, output)
+		}
+	}
+	if strings.Contains(output, "xample.com") {
+		t.Fatalf("expected only recipient digests in logs, got %s", output)
+	}
+}
+
+func TestDispatchLatencyRecorderSummarizesPerProvider(t *testing.T) {
+	recorder := newDispatchLatencyRecorder()
+	recorder.observe("tenant-a", model.NotificationSMS, 30*time.Millisecond)
+	recorder.observe("tenant-a", model.NotificationEmail, 100*time.Millisecond)
+	recorder.observe("tenant-a", model.NotificationEmail, 300*time.Millisecond)
+	recorder.observe("tenant-b", model.NotificationEmail, time.Second)
+
+	latencies := recorder.snapshot("tenant-a")
+	if len(latencies) != 2 || latencies[0].Provider != model.NotificationEmail {
+		t.Fatalf("expected email and sms latencies, got %+v", latencies)
+	}
+	email := latencies[0]
+	if email.Dispatches != 2 || email.AverageMs != 200 || email.MaxMs != 300 || email.LastMs != 300 {
+		t.Fatalf("unexpected email latency %+v", email)
+	}
+	if (*dispatchLatencyRecorder)(nil).snapshot("tenant-a") != nil {
+		t.Fatalf("expected a nil recorder to report nothing")
+	}
+}
+
+func TestDigestForLogging(t *testing.T) {
+	value := DigestForLogging("User@example.com ")
+	if value == "" {
+		t.Fatalf("expected digest")
+	}
+	if value != DigestForLogging("user@example.com") {
+		t.Fatalf("expected normalized digest")
+	}
+	if DigestForLogging("") != "" {
+		t.Fatalf("expected empty digest for empty input")
+	}
+}
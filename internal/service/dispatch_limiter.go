@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tyemirov/pinguin/internal/config"
+)
+
+// ErrDispatchCapacityExhausted reports that every in-flight synchronous send slot is taken.
+var ErrDispatchCapacityExhausted = errors.New("notification.dispatch.capacity_exhausted")
+
+// dispatchLimiter bounds the number of synchronous provider dispatches running at once.
+// It only guards SendNotification; the retry worker keeps its own pacing.
+type dispatchLimiter struct {
+	slots      chan struct{}
+	waitOnFull bool
+}
+
+func newDispatchLimiter(maxInFlightSends int, policy string) *dispatchLimiter {
+	if maxInFlightSends <= 0 {
+		return nil
+	}
+	return &dispatchLimiter{
+		slots:      make(chan struct{}, maxInFlightSends),
+		waitOnFull: policy == config.InFlightSendPolicyWait,
+	}
+}
+
+// acquire reserves a dispatch slot and returns the function that releases it.
+func (limiter *dispatchLimiter) acquire(ctx context.Context) (func(), error) {
+	if limiter == nil {
+		return func() {}, nil
+	}
+	release := func() { <-limiter.slots }
+	if !limiter.waitOnFull {
+		select {
+		case limiter.slots <- struct{}{}:
+			return release, nil
+		default:
+			return nil, fmt.Errorf("%w: limit %d reached", ErrDispatchCapacityExhausted, cap(limiter.slots))
+		}
+	}
+	select {
+	case limiter.slots <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%w: waiting for slot: %v", ErrDispatchCapacityExhausted, ctx.Err())
+	}
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/config"
+	"github.com/tyemirov/pinguin/internal/model"
+)
+
+type blockingEmailSender struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func newBlockingEmailSender() *blockingEmailSender {
+	return &blockingEmailSender{
+		started: make(chan struct{}, 8),
+		release: make(chan struct{}),
+	}
+}
+
+func (sender *blockingEmailSender) SendEmail(context.Context, string, string, string, []model.EmailAttachment) error {
+	sender.started <- struct{}{}
+	<-sender.release
+	return nil
+}
+
+func TestSendNotificationInFlightGuard(t *testing.T) {
+	testCases := []struct {
+		name         string
+		policy       string
+		waitDeadline time.Duration
+	}{
+		{name: "reject policy fails fast", policy: config.InFlightSendPolicyReject},
+		{name: "wait policy blocks until context ends", policy: config.InFlightSendPolicyWait, waitDeadline: 50 * time.Millisecond},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			database := openIsolatedDatabase(t)
+			emailSender := newBlockingEmailSender()
+			serviceInstance := NewNotificationServiceWithSenders(
+				database,
+				slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
+				config.Config{MaxRetries: 3, RetryIntervalSec: 1, MaxInFlightSends: 1, InFlightSendPolicy: testCase.policy},
+				nil,
+				emailSender,
+				nil,
+			)
+			request := mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", nil, nil)
+
+			firstResult := make(chan error, 1)
+			go func() {
+				_, sendErr := serviceInstance.SendNotification(tenantContext(), request)
+				firstResult <- sendErr
+			}()
+			<-emailSender.started
+
+			secondContext := tenantContext()
+			if testCase.waitDeadline > 0 {
+				var cancel context.CancelFunc
+				secondContext, cancel = context.WithTimeout(secondContext, testCase.waitDeadline)
+				defer cancel()
+			}
+			_, secondErr := serviceInstance.SendNotification(secondContext, request)
+			if !errors.Is(secondErr, ErrDispatchCapacityExhausted) {
+				t.Fatalf("expected capacity exhausted error, got %v", secondErr)
+			}
+
+			close(emailSender.release)
+			if firstErr := <-firstResult; firstErr != nil {
+				t.Fatalf("first send failed: %v", firstErr)
+			}
+			response, thirdErr := serviceInstance.SendNotification(tenantContext(), request)
+			if thirdErr != nil {
+				t.Fatalf("expected released slot to admit send, got %v", thirdErr)
+			}
+			if response.Status != model.StatusSent {
+				t.Fatalf("expected sent status, got %s", response.Status)
+			}
+		})
+	}
+}
+
+func TestSendNotificationWaitPolicyAdmitsQueuedSend(t *testing.T) {
+	database := openIsolatedDatabase(t)
+	emailSender := newBlockingEmailSender()
+	serviceInstance := NewNotificationServiceWithSenders(
+		database,
+		slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
+		config.Config{MaxRetries: 3, RetryIntervalSec: 1, MaxInFlightSends: 1, InFlightSendPolicy: config.InFlightSendPolicyWait},
+		nil,
+		emailSender,
+		nil,
+	)
+	request := mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", nil, nil)
+
+	results := make(chan error, 2)
+	for range 2 {
+		go func() {
+			_, sendErr := serviceInstance.SendNotification(tenantContext(), request)
+			results <- sendErr
+		}()
+	}
+	<-emailSender.started
+	select {
+	case <-emailSender.started:
+		t.Fatalf("expected second send to wait for a free slot")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(emailSender.release)
+	for range 2 {
+		if sendErr := <-results; sendErr != nil {
+			t.Fatalf("expected queued send to succeed, got %v", sendErr)
+		}
+	}
+}
+
+func TestSendNotificationScheduledSendsBypassInFlightGuard(t *testing.T) {
+	database := openIsolatedDatabase(t)
+	emailSender := newBlockingEmailSender()
+	serviceInstance := NewNotificationServiceWithSenders(
+		database,
+		slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
+		config.Config{MaxRetries: 3, RetryIntervalSec: 1, MaxInFlightSends: 1, InFlightSendPolicy: config.InFlightSendPolicyReject},
+		nil,
+		emailSender,
+		nil,
+	)
+	immediateRequest := mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", nil, nil)
+	firstResult := make(chan error, 1)
+	go func() {
+		_, sendErr := serviceInstance.SendNotification(tenantContext(), immediateRequest)
+		firstResult <- sendErr
+	}()
+	<-emailSender.started
+
+	scheduledFor := time.Now().UTC().Add(time.Hour)
+	scheduledRequest := mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", &scheduledFor, nil)
+	response, scheduleErr := serviceInstance.SendNotification(tenantContext(), scheduledRequest)
+	if scheduleErr != nil {
+		t.Fatalf("expected scheduled send to bypass the guard, got %v", scheduleErr)
+	}
+	if response.Status != model.StatusQueued {
+		t.Fatalf("expected queued status, got %s", response.Status)
+	}
+	close(emailSender.release)
+	if firstErr := <-firstResult; firstErr != nil {
+		t.Fatalf("first send failed: %v", firstErr)
+	}
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/textproto"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/config"
+	"github.com/tyemirov/pinguin/internal/model"
+)
+
+// dispatchPacingSmoothing weights the newest outcome in the moving failure rate.
+const dispatchPacingSmoothing = 0.2
+
+// DispatchPacingState reports how one tenant's dispatches to a provider are being paced.
+type DispatchPacingState struct {
+	Provider          model.NotificationType `json:"provider"`
+	FailureRate       float64                `json:"failure_rate"`
+	DelayMs           int64                  `json:"delay_ms"`
+	NextDispatchAt    *time.Time             `json:"next_dispatch_at,omitempty"`
+	Attempts          int                    `json:"attempts"`
+	TransientFailures int                    `json:"transient_failures"`
+}
+
+type dispatchPacingKey struct {
+	tenantID string
+	provider model.NotificationType
+}
+
+type dispatchPacingEntry struct {
+	failureRate       float64
+	delay             time.Duration
+	lastAdmittedAt    time.Time
+	attempts          int
+	transientFailures int
+}
+
+// dispatchPacer slows a tenant's dispatches to a provider while that provider keeps
+// answering with transient failures. It never blocks: paced work is deferred to a
+// later worker pass, so other tenants keep their full share of each batch.
+type dispatchPacer struct {
+	mutex            sync.Mutex
+	entries          map[dispatchPacingKey]*dispatchPacingEntry
+	failureThreshold float64
+	minDelay         time.Duration
+	maxDelay         time.Duration
+	recoveryRate     float64
+	logger           *slog.Logger
+}
+
+func newDispatchPacer(cfg config.DispatchPacingConfig, logger *slog.Logger) *dispatchPacer {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &dispatchPacer{
+		entries:          make(map[dispatchPacingKey]*dispatchPacingEntry),
+		failureThreshold: cfg.FailureThreshold,
+		minDelay:         time.Duration(cfg.MinDelayMs) * time.Millisecond,
+		maxDelay:         time.Duration(cfg.MaxDelayMs) * time.Millisecond,
+		recoveryRate:     cfg.RecoveryRate,
+		logger:           logger,
+	}
+}
+
+// admit reports whether a dispatch may start now and, if so, starts the next delay window.
+func (pacer *dispatchPacer) admit(tenantID string, provider model.NotificationType, now time.Time) bool {
+	if pacer == nil {
+		return true
+	}
+	pacer.mutex.Lock()
+	defer pacer.mutex.Unlock()
+	entry := pacer.entry(dispatchPacingKey{tenantID: tenantID, provider: provider})
+	if entry.delay > 0 && now.Before(entry.lastAdmittedAt.Add(entry.delay)) {
+		return false
+	}
+	entry.lastAdmittedAt = now
+	return true
+}
+
+// holding reports whether pacing would turn away a dispatch to the provider at now,
+// without starting a delay window the way admit does.
+func (pacer *dispatchPacer) holding(tenantID string, provider model.NotificationType, now time.Time) bool {
+	if pacer == nil {
+		return false
+	}
+	pacer.mutex.Lock()
+	defer pacer.mutex.Unlock()
+	entry, exists := pacer.entries[dispatchPacingKey{tenantID: tenantID, provider: provider}]
+	return exists && entry.delay > 0 && now.Before(entry.lastAdmittedAt.Add(entry.delay))
+}
+
+// record folds a dispatch outcome into the failure rate. Transient failures above the
+// threshold double the delay up to maxDelay; healthy dispatches below it shrink the
+// delay by recoveryRate until it drops under minDelay and pacing stops.
+func (pacer *dispatchPacer) record(tenantID string, provider model.NotificationType, dispatchErr error) {
+	if pacer == nil {
+		return
+	}
+	transient := isTransientDispatchError(dispatchErr)
+	pacer.mutex.Lock()
+	defer pacer.mutex.Unlock()
+	entry := pacer.entry(dispatchPacingKey{tenantID: tenantID, provider: provider})
+	entry.attempts++
+	sample := 0.0
+	if transient {
+		sample = 1
+		entry.transientFailures++
+	}
+	entry.failureRate += dispatchPacingSmoothing * (sample - entry.failureRate)
+
+	previousDelay := entry.delay
+	switch {
+	case transient && entry.failureRate > pacer.failureThreshold:
+		entry.delay = min(max(entry.delay*2, pacer.minDelay), pacer.maxDelay)
+	case !transient && entry.delay > 0 && entry.failureRate <= pacer.failureThreshold:
+		entry.delay -= time.Duration(float64(entry.delay) * pacer.recoveryRate)
+		if entry.delay < pacer.minDelay {
+			entry.delay = 0
+		}
+	}
+	if (previousDelay == 0) != (entry.delay == 0) {
+		pacer.logger.Info(
+			"dispatch_pacing_changed",
+			"tenant_id", tenantID,
+			"provider", provider,
+			"paced", entry.delay > 0,
+			"delay_ms", entry.delay.Milliseconds(),
+			"failure_rate", entry.failureRate,
+		)
+	}
+}
+
+// snapshot returns the pacing state of every provider the tenant has dispatched to.
+func (pacer *dispatchPacer) snapshot(tenantID string) []DispatchPacingState {
+	if pacer == nil {
+		return nil
+	}
+	pacer.mutex.Lock()
+	defer pacer.mutex.Unlock()
+	var states []DispatchPacingState
+	for key, entry := range pacer.entries {
+		if key.tenantID != tenantID {
+			continue
+		}
+		state := DispatchPacingState{
+			Provider:          key.provider,
+			FailureRate:       entry.failureRate,
+			DelayMs:           entry.delay.Milliseconds(),
+			Attempts:          entry.attempts,
+			TransientFailures: entry.transientFailures,
+		}
+		if entry.delay > 0 {
+			nextDispatchAt := entry.lastAdmittedAt.Add(entry.delay).UTC()
+			state.NextDispatchAt = &nextDispatchAt
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(left, right int) bool {
+		return states[left].Provider < states[right].Provider
+	})
+	return states
+}
+
+func (pacer *dispatchPacer) entry(key dispatchPacingKey) *dispatchPacingEntry {
+	entry, exists := pacer.entries[key]
+	if !exists {
+		entry = &dispatchPacingEntry{}
+		pacer.entries[key] = entry
+	}
+	return entry
+}
+
+// isTransientDispatchError recognizes provider back-pressure: SMTP 4xx replies, Twilio 429/5xx
+// responses, network errors and timeouts.
+func isTransientDispatchError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var smtpError *textproto.Error
+	if errors.As(err, &smtpError) {
+		return smtpError.Code >= 400 && smtpError.Code < 500
+	}
+	var twilioError *TwilioAPIError
+	if errors.As(err, &twilioError) {
+		return twilioError.StatusCode == http.StatusTooManyRequests || twilioError.StatusCode >= http.StatusInternalServerError
+	}
+	var networkError net.Error
+	return errors.As(err, &networkError) || errors.Is(err, context.DeadlineExceeded)
+}
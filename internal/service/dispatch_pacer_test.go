@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/textproto"
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/config"
+	"github.com/tyemirov/pinguin/internal/model"
+)
+
+func newTestDispatchPacer() *dispatchPacer {
+	return newDispatchPacer(config.DispatchPacingConfig{
+		Enabled:          true,
+		FailureThreshold: config.DefaultDispatchPacingFailureThreshold,
+		MinDelayMs:       50,
+		MaxDelayMs:       5000,
+		RecoveryRate:     config.DefaultDispatchPacingRecoveryRate,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+// rateLimitedRelay answers 421 once more than limit dispatches land within one second.
+type rateLimitedRelay struct {
+	limit      int
+	dispatches []time.Time
+}
+
+func (relay *rateLimitedRelay) send(now time.Time) error {
+	windowStart := now.Add(-time.Second)
+	recent := relay.dispatches[:0]
+	for _, dispatchedAt := range relay.dispatches {
+		if dispatchedAt.After(windowStart) {
+			recent = append(recent, dispatchedAt)
+		}
+	}
+	relay.dispatches = append(recent, now)
+	if len(relay.dispatches) > relay.limit {
+		return fmt.Errorf("smtp send failed: %w", &textproto.Error{Code: 421, Msg: "too many connections"})
+	}
+	return nil
+}
+
+func TestDispatchPacerConvergesBelowProviderRateLimit(t *testing.T) {
+	pacer := newTestDispatchPacer()
+	relay := &rateLimitedRelay{limit: 5}
+	start := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	const (
+		step            = 10 * time.Millisecond
+		simulated       = 3 * time.Minute
+		measuredSeconds = 60
+	)
+	measureFrom := start.Add(simulated - measuredSeconds*time.Second)
+	measuredAttempts, measuredFailures, otherTenantAdmits := 0, 0, 0
+	for now := start; now.Before(start.Add(simulated)); now = now.Add(step) {
+		if pacer.admit("tenant-paced", model.NotificationEmail, now) {
+			sendErr := relay.send(now)
+			pacer.record("tenant-paced", model.NotificationEmail, sendErr)
+			if !now.Before(measureFrom) {
+				measuredAttempts++
+				if sendErr != nil {
+					measuredFailures++
+				}
+			}
+		}
+		if pacer.admit("tenant-healthy", model.NotificationEmail, now) {
+			pacer.record("tenant-healthy", model.NotificationEmail, nil)
+			otherTenantAdmits++
+		}
+	}
+
+	attemptRate := float64(measuredAttempts) / measuredSeconds
+	if attemptRate > float64(relay.limit) {
+		t.Fatalf("expected the paced attempt rate to converge below %d/s, got %.2f/s", relay.limit, attemptRate)
+	}
+	if attemptRate < float64(relay.limit)/4 {
+		t.Fatalf("expected pacing to keep useful throughput, got %.2f/s", attemptRate)
+	}
+	if failureRatio := float64(measuredFailures) / float64(measuredAttempts); failureRatio > config.DefaultDispatchPacingFailureThreshold {
+		t.Fatalf("expected transient failures to stay under the threshold, got %.2f", failureRatio)
+	}
+	if expected := int(simulated / step); otherTenantAdmits != expected {
+		t.Fatalf("expected the healthy tenant to be admitted on every tick (%d), got %d", expected, otherTenantAdmits)
+	}
+}
+
+func TestDispatchPacerRecoversAndReportsState(t *testing.T) {
+	pacer := newTestDispatchPacer()
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	transientErr := &textproto.Error{Code: 450, Msg: "mailbox busy"}
+	for attempt := 0; attempt < 4; attempt++ {
+		pacer.record("tenant-a", model.NotificationEmail, transientErr)
+	}
+	if !pacer.admit("tenant-a", model.NotificationEmail, now) {
+		t.Fatalf("expected the first paced dispatch to be admitted")
+	}
+	if pacer.admit("tenant-a", model.NotificationEmail, now.Add(time.Millisecond)) {
+		t.Fatalf("expected the next dispatch to wait for the delay")
+	}
+	if !pacer.admit("tenant-a", model.NotificationSMS, now) {
+		t.Fatalf("expected another provider of the same tenant to stay unpaced")
+	}
+
+	states := pacer.snapshot("tenant-a")
+	if len(states) != 2 || states[0].Provider != model.NotificationEmail || states[0].DelayMs <= 0 || states[0].NextDispatchAt == nil {
+		t.Fatalf("expected paced email state, got %+v", states)
+	}
+	if states[0].TransientFailures != 4 || states[1].DelayMs != 0 {
+		t.Fatalf("unexpected pacing state %+v", states)
+	}
+	if other := pacer.snapshot("tenant-b"); len(other) != 0 {
+		t.Fatalf("expected no state for an idle tenant, got %+v", other)
+	}
+
+	for attempt := 0; attempt < 100; attempt++ {
+		pacer.record("tenant-a", model.NotificationEmail, nil)
+	}
+	recovered := pacer.snapshot("tenant-a")[0]
+	if recovered.DelayMs != 0 || recovered.NextDispatchAt != nil || recovered.FailureRate > config.DefaultDispatchPacingFailureThreshold {
+		t.Fatalf("expected pacing to stop after recovery, got %+v", recovered)
+	}
+}
+
+func TestDisabledDispatchPacerAdmitsEverything(t *testing.T) {
+	pacer := newDispatchPacer(config.DispatchPacingConfig{}, nil)
+	pacer.record("tenant-a", model.NotificationEmail, &textproto.Error{Code: 421})
+	if !pacer.admit("tenant-a", model.NotificationEmail, time.Now()) || pacer.snapshot("tenant-a") != nil {
+		t.Fatalf("expected a disabled pacer to be a no-op")
+	}
+}
+
+func TestIsTransientDispatchError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "nil", err: nil},
+		{name: "smtp 421", err: fmt.Errorf("wrapped: %w", &textproto.Error{Code: 421}), expected: true},
+		{name: "smtp 550", err: &textproto.Error{Code: 550}},
+		{name: "twilio 429", err: &TwilioAPIError{StatusCode: 429}, expected: true},
+		{name: "twilio 503", err: &TwilioAPIError{StatusCode: 503}, expected: true},
+		{name: "twilio 400", err: &TwilioAPIError{StatusCode: 400}},
+		{name: "deadline", err: fmt.Errorf("send: %w", context.DeadlineExceeded), expected: true},
+		{name: "plain", err: errors.New("boom")},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if actual := isTransientDispatchError(testCase.err); actual != testCase.expected {
+				t.Fatalf("expected %v, got %v", testCase.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRetryStoreLimitsPacedTenantShareOfBatch(t *testing.T) {
+	database := openIsolatedDatabase(t)
+	now := time.Now().UTC()
+	for index := 0; index < 3; index++ {
+		for _, tenantID := range []string{"tenant-paced", "tenant-healthy"} {
+			record := model.Notification{
+				TenantID:         tenantID,
+				NotificationID:   fmt.Sprintf("%s-%d", tenantID, index),
+				NotificationType: model.NotificationEmail,
+				Recipient:        "user@example.com",
+				Message:          "Body",
+				Status:           model.StatusQueued,
+				CreatedAt:        now,
+				UpdatedAt:        now,
+			}
+			if err := model.CreateNotification(context.Background(), database, &record); err != nil {
+				t.Fatalf("seed notification: %v", err)
+			}
+		}
+	}
+	pacer := newTestDispatchPacer()
+	for attempt := 0; attempt < 4; attempt++ {
+		pacer.record("tenant-paced", model.NotificationEmail, &textproto.Error{Code: 421})
+	}
+	store := newNotificationRetryStore(database, nil).withPacer(pacer)
+
+	jobs, err := store.PendingJobs(context.Background(), 5, now)
+	if err != nil {
+		t.Fatalf("pending jobs: %v", err)
+	}
+	perTenant := map[string]int{}
+	for _, job := range jobs {
+		perTenant[job.Payload.(*model.Notification).TenantID]++
+	}
+	if perTenant["tenant-paced"] != 1 || perTenant["tenant-healthy"] != 3 {
+		t.Fatalf("expected one paced job and every healthy job, got %v", perTenant)
+	}
+}
+
+func TestSendNotificationDefersImmediateDispatchWhilePaced(t *testing.T) {
+	database := openIsolatedDatabase(t)
+	emailSender := &stubEmailSender{}
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(database, emailSender, &stubSmsSender{})
+	serviceInstance.dispatchPacer = newTestDispatchPacer()
+	for attempt := 0; attempt < 4; attempt++ {
+		serviceInstance.dispatchPacer.record(testTenantID, model.NotificationEmail, &textproto.Error{Code: 421})
+	}
+	serviceInstance.dispatchPacer.admit(testTenantID, model.NotificationEmail, time.Now().UTC())
+
+	response, err := serviceInstance.SendNotification(tenantContext(), mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", nil, nil))
+	if err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	if emailSender.callCount != 0 || response.Status != model.StatusQueued {
+		t.Fatalf("expected a deferred queued notification, got %s after %d sends", response.Status, emailSender.callCount)
+	}
+	states, err := serviceInstance.DispatchPacing(tenantContext())
+	if err != nil || len(states) != 1 || states[0].DelayMs == 0 {
+		t.Fatalf("expected paced state for the tenant, got %+v (%v)", states, err)
+	}
+}
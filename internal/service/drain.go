@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/config"
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+	"gorm.io/gorm/clause"
+)
+
+// ErrDraining rejects new sends once the instance has started draining; callers should
+// retry against another instance.
+var ErrDraining = errors.New("notification.dispatch.draining")
+
+// DrainStatus reports the progress of a drain started by Drain. It is the zero value
+// while the instance accepts traffic.
+type DrainStatus struct {
+	Draining  bool
+	StartedAt time.Time
+	Deadline  time.Time
+	// Drained is set once the queues emptied or the deadline passed; the process may exit.
+	Drained bool
+	// RemainingQueued counts due queued and errored notifications still awaiting the retry worker.
+	RemainingQueued int64
+}
+
+// instanceDrain is the service's drain state. done is created lazily so services built
+// without a constructor still drain.
+type instanceDrain struct {
+	mutex     sync.Mutex
+	draining  bool
+	drained   bool
+	startedAt time.Time
+	deadline  time.Time
+	done      chan struct{}
+}
+
+// doneChannel must be called with mutex held.
+func (drain *instanceDrain) doneChannel() chan struct{} {
+	if drain.done == nil {
+		drain.done = make(chan struct{})
+	}
+	return drain.done
+}
+
+// Drain makes WithDrainGate refuse new sends while the retry worker keeps
+// dispatching. Drained closes once no due notification is pending or
+// server.drainTimeoutSec has passed. Repeated calls keep the first deadline.
+func (serviceInstance *notificationServiceImpl) Drain(ctx context.Context) (DrainStatus, error) {
+	currentTime := serviceInstance.currentTime()
+	serviceInstance.drain.mutex.Lock()
+	started := !serviceInstance.drain.draining
+	if started {
+		serviceInstance.drain.draining = true
+		serviceInstance.drain.startedAt = currentTime
+		serviceInstance.drain.deadline = currentTime.Add(serviceInstance.drainTimeout())
+	}
+	deadline := serviceInstance.drain.deadline
+	serviceInstance.drain.mutex.Unlock()
+	if started {
+		serviceInstance.logger.Info("drain_started", "deadline", deadline)
+		go serviceInstance.watchDrain(context.WithoutCancel(ctx))
+	}
+	return serviceInstance.GetDrainStatus(ctx)
+}
+
+// GetDrainStatus reports drain progress and completes the drain when the queues are
+// empty or the deadline has passed.
+func (serviceInstance *notificationServiceImpl) GetDrainStatus(ctx context.Context) (DrainStatus, error) {
+	currentTime := serviceInstance.currentTime()
+	serviceInstance.drain.mutex.Lock()
+	status := DrainStatus{
+		Draining:  serviceInstance.drain.draining,
+		Drained:   serviceInstance.drain.drained,
+		StartedAt: serviceInstance.drain.startedAt,
+		Deadline:  serviceInstance.drain.deadline,
+	}
+	serviceInstance.drain.mutex.Unlock()
+	if !status.Draining {
+		return DrainStatus{}, nil
+	}
+	remaining, err := serviceInstance.countDrainRemaining(ctx, currentTime)
+	if err != nil {
+		return DrainStatus{}, err
+	}
+	status.RemainingQueued = remaining
+	if !status.Drained && (remaining == 0 || !currentTime.Before(status.Deadline)) {
+		serviceInstance.finishDrain(remaining)
+		status.Drained = true
+	}
+	return status, nil
+}
+
+// Draining reports whether Drain has been called; it never touches the database.
+func (serviceInstance *notificationServiceImpl) Draining() bool {
+	serviceInstance.drain.mutex.Lock()
+	defer serviceInstance.drain.mutex.Unlock()
+	return serviceInstance.drain.draining
+}
+
+// Drained closes when a drain completes.
+func (serviceInstance *notificationServiceImpl) Drained() <-chan struct{} {
+	serviceInstance.drain.mutex.Lock()
+	defer serviceInstance.drain.mutex.Unlock()
+	return serviceInstance.drain.doneChannel()
+}
+
+// WithDrainGate refuses new sends and group sends with ErrDraining once the wrapped
+// service is draining.
+func WithDrainGate() Decorator {
+	return func(inner NotificationService) NotificationService {
+		return &drainGateService{NotificationService: inner}
+	}
+}
+
+type drainGateService struct {
+	NotificationService
+}
+
+func (service *drainGateService) SendNotification(ctx context.Context, request model.NotificationRequest) (model.NotificationResponse, error) {
+	if service.Draining() {
+		return model.NotificationResponse{}, ErrDraining
+	}
+	return service.NotificationService.SendNotification(ctx, request)
+}
+
+func (service *drainGateService) SendNotificationGroup(ctx context.Context, request model.NotificationGroupRequest) (model.NotificationGroupResponse, error) {
+	if service.Draining() {
+		return model.NotificationGroupResponse{}, ErrDraining
+	}
+	return service.NotificationService.SendNotificationGroup(ctx, request)
+}
+
+func (serviceInstance *notificationServiceImpl) finishDrain(remaining int64) {
+	serviceInstance.drain.mutex.Lock()
+	defer serviceInstance.drain.mutex.Unlock()
+	if serviceInstance.drain.drained {
+		return
+	}
+	serviceInstance.drain.drained = true
+	close(serviceInstance.drain.doneChannel())
+	serviceInstance.logger.Info("drain_completed", "remaining_queued", remaining, "deadline_exceeded", remaining > 0)
+}
+
+// watchDrain polls progress on the retry interval until the drain completes.
+func (serviceInstance *notificationServiceImpl) watchDrain(ctx context.Context) {
+	interval := time.Duration(serviceInstance.retryIntervalSec) * time.Second
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		status, err := serviceInstance.GetDrainStatus(ctx)
+		if err != nil {
+			serviceInstance.logger.Error("Failed to check drain progress", "error", err)
+		} else if status.Drained {
+			return
+		} else {
+			serviceInstance.logger.Info("drain_progress", "remaining_queued", status.RemainingQueued)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// countDrainRemaining counts the notifications the retry worker still owes before the
+// instance may exit: due, under the retry limit, and owned by an active tenant. Backoff
+// windows are ignored because the worker will still retry them; notifications scheduled
+// for later are left to the instances that stay up.
+func (serviceInstance *notificationServiceImpl) countDrainRemaining(ctx context.Context, currentTime time.Time) (int64, error) {
+	query := serviceInstance.database.WithContext(ctx).Model(&model.Notification{})
+	if serviceInstance.tenantRepo != nil {
+		query = query.Clauses(activeTenantJoinClause()).Where(clause.Eq{
+			Column: clause.Column{Table: pendingJobsTenantsTable, Name: pendingJobsTenantStatusColumn},
+			Value:  tenant.TenantStatusActive,
+		})
+	}
+	var remaining int64
+	err := query.Where(clause.And(
+		clause.IN{
+			Column: clause.Column{Table: pendingJobsNotificationsTable, Name: pendingJobsStatusColumn},
+			Values: []interface{}{model.StatusQueued, model.StatusErrored},
+		},
+		clause.Lt{Column: clause.Column{Table: pendingJobsNotificationsTable, Name: pendingJobsRetryCountColumn}, Value: serviceInstance.maxRetries},
+		clause.Or(
+			clause.Eq{Column: clause.Column{Table: pendingJobsNotificationsTable, Name: pendingJobsScheduledForColumn}, Value: nil},
+			clause.Lte{Column: clause.Column{Table: pendingJobsNotificationsTable, Name: pendingJobsScheduledForColumn}, Value: currentTime},
+		),
+		retriesNotAbandonedFilter(),
+	)).Count(&remaining).Error
+	return remaining, err
+}
+
+func (serviceInstance *notificationServiceImpl) drainTimeout() time.Duration {
+	if serviceInstance.config.DrainTimeoutSec > 0 {
+		return time.Duration(serviceInstance.config.DrainTimeoutSec) * time.Second
+	}
+	return time.Duration(config.DefaultDrainTimeoutSec) * time.Second
+}
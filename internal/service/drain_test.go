@@ -0,0 +1,120 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+)
+
+// drainTestClock is shared with the drain watcher goroutine, so reads are locked.
+type drainTestClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func (clock *drainTestClock) Now() time.Time {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	return clock.now
+}
+
+func (clock *drainTestClock) advance(duration time.Duration) {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	clock.now = clock.now.Add(duration)
+}
+
+func seedDrainNotification(t *testing.T, serviceInstance *notificationServiceImpl, notificationID string, scheduledFor *time.Time) {
+	t.Helper()
+	record := model.Notification{
+		TenantID:         testTenantID,
+		NotificationID:   notificationID,
+		NotificationType: model.NotificationEmail,
+		Recipient:        "user@example.com",
+		Message:          "Body",
+		Status:           model.StatusQueued,
+		ScheduledFor:     scheduledFor,
+	}
+	if err := model.CreateNotification(tenantContext(), serviceInstance.database, &record); err != nil {
+		t.Fatalf("seed notification: %v", err)
+	}
+}
+
+func TestDrainRejectsSendsAndCompletesOnceQueueEmpties(t *testing.T) {
+	emailSender := &stubEmailSender{}
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), emailSender, &stubSmsSender{})
+	clock := &drainTestClock{now: time.Now().UTC()}
+	serviceInstance.clock = clock
+	later := clock.Now().Add(24 * time.Hour)
+	seedDrainNotification(t, serviceInstance, "notif-due", nil)
+	seedDrainNotification(t, serviceInstance, "notif-tomorrow", &later)
+
+	if status, err := serviceInstance.GetDrainStatus(tenantContext()); err != nil || status.Draining {
+		t.Fatalf("expected no drain before Drain, got %+v (%v)", status, err)
+	}
+	status, err := serviceInstance.Drain(tenantContext())
+	if err != nil {
+		t.Fatalf("drain: %v", err)
+	}
+	if !status.Draining || status.Drained || status.RemainingQueued != 1 || !status.Deadline.After(status.StartedAt) {
+		t.Fatalf("expected one due notification left to drain, got %+v", status)
+	}
+	if !serviceInstance.Draining() {
+		t.Fatalf("expected Draining to report the drain")
+	}
+
+	_, sendErr := Chain(serviceInstance, WithDrainGate()).SendNotification(tenantContext(), mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", nil, nil))
+	if !errors.Is(sendErr, ErrDraining) || emailSender.callCount != 0 {
+		t.Fatalf("expected new sends to be refused while draining, got %v after %d sends", sendErr, emailSender.callCount)
+	}
+
+	clock.advance(time.Minute)
+	newRetryWorkerForTest(t, serviceInstance, clock).RunOnce(tenantContext())
+	if emailSender.callCount != 1 {
+		t.Fatalf("expected the retry worker to keep dispatching while draining, got %d sends", emailSender.callCount)
+	}
+	status, err = serviceInstance.GetDrainStatus(tenantContext())
+	if err != nil || !status.Drained || status.RemainingQueued != 0 {
+		t.Fatalf("expected the drain to complete once the due queue is empty, got %+v (%v)", status, err)
+	}
+	select {
+	case <-serviceInstance.Drained():
+	default:
+		t.Fatalf("expected Drained to be closed")
+	}
+
+	repeated, err := serviceInstance.Drain(tenantContext())
+	if err != nil || !repeated.StartedAt.Equal(status.StartedAt) || !repeated.Deadline.Equal(status.Deadline) {
+		t.Fatalf("expected a repeated Drain to keep the original window, got %+v (%v)", repeated, err)
+	}
+}
+
+func TestDrainCompletesAtDeadlineWithWorkRemaining(t *testing.T) {
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), &stubEmailSender{}, &stubSmsSender{})
+	serviceInstance.config.DrainTimeoutSec = 60
+	clock := &drainTestClock{now: time.Now().UTC()}
+	serviceInstance.clock = clock
+	seedDrainNotification(t, serviceInstance, "notif-stuck", nil)
+
+	status, err := serviceInstance.Drain(tenantContext())
+	if err != nil || status.Drained || !status.Deadline.Equal(status.StartedAt.Add(time.Minute)) {
+		t.Fatalf("expected a one minute drain window, got %+v (%v)", status, err)
+	}
+	clock.advance(59 * time.Second)
+	if status, err = serviceInstance.GetDrainStatus(tenantContext()); err != nil || status.Drained {
+		t.Fatalf("expected the drain to wait until the deadline, got %+v (%v)", status, err)
+	}
+	clock.advance(time.Second)
+	status, err = serviceInstance.GetDrainStatus(tenantContext())
+	if err != nil || !status.Drained || status.RemainingQueued != 1 {
+		t.Fatalf("expected the deadline to end the drain with one notification left, got %+v (%v)", status, err)
+	}
+	select {
+	case <-serviceInstance.Drained():
+	default:
+		t.Fatalf("expected Drained to be closed at the deadline")
+	}
+}
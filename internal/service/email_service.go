@@ -1,21 +1,34 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"net"
+	"net/mail"
 	"net/smtp"
+	"net/textproto"
 	"strings"
 	"time"
 
 	"github.com/tyemirov/pinguin/internal/config"
+	"github.com/tyemirov/pinguin/internal/entropy"
 	"github.com/tyemirov/pinguin/internal/model"
 	"log/slog"
 )
 
+const (
+	// calendarFileContentType labels the .ics copy of an invite the way Outlook sends it.
+	calendarFileContentType = "application/ics"
+
+	smtpCodeAuthMechanismTooWeak   = 534
+	smtpCodeAuthCredentialsInvalid = 535
+)
+
 type SMTPConfig struct {
 	Host        string
 	Port        string
@@ -23,15 +36,35 @@ type SMTPConfig struct {
 	Password    string
 	FromAddress string
 	Timeouts    config.Config
+	// Random supplies MIME boundaries; nil uses entropy.System.
+	Random entropy.RandomSource
+	// PoolSize keeps up to this many connections open between messages and caps the
+	// sender's concurrent connections at it; zero dials a connection per message.
+	PoolSize int
+	// PoolIdleTimeout closes pooled connections left unused this long.
+	PoolIdleTimeout time.Duration
 }
 
 type EmailSender interface {
 	SendEmail(ctx context.Context, recipient string, subject string, message string, attachments []model.EmailAttachment) error
 }
 
+// EncryptedEmailSender is implemented by email senders that can relay a MIME entity the
+// caller encrypted without reading or rebuilding it.
+type EncryptedEmailSender interface {
+	SendEncryptedEmail(ctx context.Context, recipient string, subject string, mimeEntity string, overrides model.SenderOverrides) error
+}
+
+// SenderOverrideEmailSender is implemented by email senders that can send a message
+// under a per-message Reply-To address and From display name.
+type SenderOverrideEmailSender interface {
+	SendEmailWithOverrides(ctx context.Context, recipient string, subject string, message string, attachments []model.EmailAttachment, overrides model.SenderOverrides) error
+}
+
 var (
-	dialTLSFunc = func(dialer *net.Dialer, network string, addr string, config *tls.Config) (net.Conn, error) {
-		return tls.DialWithDialer(dialer, network, addr, config)
+	dialTLSFunc = func(ctx context.Context, dialer *net.Dialer, network string, addr string, config *tls.Config) (net.Conn, error) {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: config}
+		return tlsDialer.DialContext(ctx, network, addr)
 	}
 	newSMTPClient = func(conn net.Conn, host string) (smtpClient, error) {
 		client, err := smtp.NewClient(conn, host)
@@ -40,7 +73,7 @@ var (
 		}
 		return smtpClientWrapper{client: client}, nil
 	}
-	sendMailFunc = smtp.SendMail
+	sendMailFunc = sendMailContext
 )
 
 type smtpClient interface {
@@ -78,22 +111,72 @@ func (wrapper smtpClientWrapper) Quit() error {
 type SMTPEmailSender struct {
 	Config SMTPConfig
 	Logger *slog.Logger
+	pool   *smtpPool
 }
 
 func NewSMTPEmailSender(configuration SMTPConfig, logger *slog.Logger) *SMTPEmailSender {
-	return &SMTPEmailSender{
+	senderInstance := &SMTPEmailSender{
 		Config: configuration,
 		Logger: logger,
 	}
+	if configuration.PoolSize > 0 {
+		senderInstance.pool = newSMTPPool(configuration.PoolSize, configuration.PoolIdleTimeout, senderInstance.dialPooledConnection)
+	}
+	return senderInstance
+}
+
+// Close closes the sender's idle pooled connections; connections still sending close
+// once their message is done. A sender without a pool has nothing to close.
+func (senderInstance *SMTPEmailSender) Close() {
+	if senderInstance.pool != nil {
+		senderInstance.pool.close()
+	}
 }
 
 func (senderInstance *SMTPEmailSender) SendEmail(ctx context.Context, recipient string, subject string, message string, attachments []model.EmailAttachment) error {
-	emailMessage := buildEmailMessage(senderInstance.Config.FromAddress, recipient, subject, message, attachments)
-	return senderInstance.SendRawEmail(ctx, senderInstance.Config.FromAddress, []string{recipient}, []byte(emailMessage))
+	return senderInstance.SendEmailWithOverrides(ctx, recipient, subject, message, attachments, model.SenderOverrides{})
+}
+
+// SendEmailWithOverrides sends like SendEmail, with the Reply-To header and the From
+// display name the overrides set.
+func (senderInstance *SMTPEmailSender) SendEmailWithOverrides(ctx context.Context, recipient string, subject string, message string, attachments []model.EmailAttachment, overrides model.SenderOverrides) error {
+	random := senderInstance.Config.Random
+	if random == nil {
+		random = entropy.System()
+	}
+	emailMessage, err := buildEmailMessage(random, senderInstance.Config.FromAddress, recipient, subject, message, attachments, overrides)
+	if err != nil {
+		return err
+	}
+	return senderInstance.SendRawEmail(ctx, senderInstance.Config.FromAddress, []string{recipient}, emailMessage)
 }
 
-// SendRawEmail relays a prebuilt RFC 5322 message through the configured upstream SMTP provider.
+// SendEncryptedEmail sends mimeEntity, unchanged, under From, To, Subject and
+// MIME-Version headers, plus Reply-To when the overrides set one.
+func (senderInstance *SMTPEmailSender) SendEncryptedEmail(ctx context.Context, recipient string, subject string, mimeEntity string, overrides model.SenderOverrides) error {
+	emailMessage, err := buildEncryptedEmailMessage(senderInstance.Config.FromAddress, recipient, subject, mimeEntity, overrides)
+	if err != nil {
+		return err
+	}
+	return senderInstance.SendRawEmail(ctx, senderInstance.Config.FromAddress, []string{recipient}, emailMessage)
+}
+
+// SendRawEmail relays a prebuilt RFC 5322 message through the configured upstream SMTP
+// provider, over a pooled connection when PoolSize is set. The whole exchange, including
+// the wait for a pooled connection, is bounded by the operation timeout.
 func (senderInstance *SMTPEmailSender) SendRawEmail(ctx context.Context, fromAddress string, recipients []string, rawMessage []byte) error {
+	if operationTimeout := time.Duration(senderInstance.Config.Timeouts.OperationTimeoutSec) * time.Second; operationTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, operationTimeout)
+		defer cancel()
+	}
+	if senderInstance.pool != nil {
+		if sendError := senderInstance.pool.send(ctx, fromAddress, recipients, rawMessage); sendError != nil {
+			return classifySMTPError(fmt.Errorf("smtp send failed: %w", sendError))
+		}
+		return nil
+	}
+	connectionTimeout := time.Duration(senderInstance.Config.Timeouts.ConnectionTimeoutSec) * time.Second
 	if senderInstance.Config.Port == "465" {
 		serverAddr := net.JoinHostPort(senderInstance.Config.Host, senderInstance.Config.Port)
 		tlsConfig := &tls.Config{
@@ -102,121 +185,367 @@ func (senderInstance *SMTPEmailSender) SendRawEmail(ctx context.Context, fromAdd
 		}
 
 		dialer := &net.Dialer{
-			Timeout: time.Duration(senderInstance.Config.Timeouts.ConnectionTimeoutSec) * time.Second,
+			Timeout: connectionTimeout,
 		}
 
-		tlsConnection, dialError := dialTLSFunc(dialer, "tcp", serverAddr, tlsConfig)
+		tlsConnection, dialError := dialTLSFunc(ctx, dialer, "tcp", serverAddr, tlsConfig)
 		if dialError != nil {
-			return fmt.Errorf("failed to dial TLS: %w", dialError)
+			return abortedByContext(ctx, fmt.Errorf("failed to dial TLS: %w", dialError))
 		}
 		defer tlsConnection.Close()
-
-		if ctx.Err() != nil {
-			return ctx.Err()
-		}
+		defer closeOnDone(ctx, tlsConnection)()
 
 		smtpClient, clientError := newSMTPClient(tlsConnection, senderInstance.Config.Host)
 		if clientError != nil {
-			return fmt.Errorf("failed to create SMTP client: %w", clientError)
+			return abortedByContext(ctx, fmt.Errorf("failed to create SMTP client: %w", clientError))
 		}
 		defer smtpClient.Quit()
 
 		smtpAuth := smtp.PlainAuth("", senderInstance.Config.Username, senderInstance.Config.Password, senderInstance.Config.Host)
 		if authError := smtpClient.Auth(smtpAuth); authError != nil {
-			return fmt.Errorf("failed to authenticate: %w", authError)
+			return classifySMTPError(abortedByContext(ctx, fmt.Errorf("failed to authenticate: %w", authError)))
 		}
+		return abortedByContext(ctx, transmitMessage(smtpClient, fromAddress, recipients, rawMessage))
+	}
 
-		if mailError := smtpClient.Mail(fromAddress); mailError != nil {
-			return fmt.Errorf("failed to set sender: %w", mailError)
-		}
-		for _, recipient := range recipients {
-			if rcptError := smtpClient.Rcpt(recipient); rcptError != nil {
-				return fmt.Errorf("failed to set recipient: %w", rcptError)
-			}
-		}
+	smtpAddress := net.JoinHostPort(senderInstance.Config.Host, senderInstance.Config.Port)
+	smtpAuth := smtp.PlainAuth("", senderInstance.Config.Username, senderInstance.Config.Password, senderInstance.Config.Host)
+	sendError := sendMailFunc(ctx, connectionTimeout, smtpAddress, smtpAuth, fromAddress, recipients, rawMessage)
+	if sendError != nil {
+		return classifySMTPError(fmt.Errorf("smtp send failed: %w", sendError))
+	}
+	return nil
+}
 
-		dataWriter, dataError := smtpClient.Data()
-		if dataError != nil {
-			return fmt.Errorf("failed to get data writer: %w", dataError)
+// sendMailContext is smtp.SendMail with ctx and dialTimeout bounding the dial and ctx
+// aborting every later step, including a DATA write in progress, by closing the connection.
+func sendMailContext(ctx context.Context, dialTimeout time.Duration, addr string, auth smtp.Auth, fromAddress string, recipients []string, rawMessage []byte) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	connection, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+	defer closeOnDone(ctx, connection)()
+
+	client, err := smtp.NewClient(connection, host)
+	if err != nil {
+		return abortedByContext(ctx, err)
+	}
+	if supported, _ := client.Extension("STARTTLS"); supported {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return abortedByContext(ctx, err)
 		}
-		_, writeError := dataWriter.Write(rawMessage)
-		if writeError != nil {
-			dataWriter.Close()
-			return fmt.Errorf("failed to write email message: %w", writeError)
+	}
+	if supported, _ := client.Extension("AUTH"); supported && auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return abortedByContext(ctx, err)
 		}
-		if closeDataError := dataWriter.Close(); closeDataError != nil {
-			return fmt.Errorf("failed to close data writer: %w", closeDataError)
+	}
+	if err := transmitMessage(smtpClientWrapper{client: client}, fromAddress, recipients, rawMessage); err != nil {
+		return abortedByContext(ctx, err)
+	}
+	// The message was accepted with DATA; a QUIT cut short by cancellation does not undo it.
+	if quitErr := client.Quit(); quitErr != nil && ctx.Err() == nil {
+		return quitErr
+	}
+	return nil
+}
+
+// transmitMessage runs the MAIL, RCPT and DATA steps of one message. The provider has
+// accepted the message only once it returns nil.
+func transmitMessage(client smtpClient, fromAddress string, recipients []string, rawMessage []byte) error {
+	if mailError := client.Mail(fromAddress); mailError != nil {
+		return fmt.Errorf("failed to set sender: %w", mailError)
+	}
+	for _, recipient := range recipients {
+		if rcptError := client.Rcpt(recipient); rcptError != nil {
+			return fmt.Errorf("failed to set recipient: %w", rcptError)
 		}
+	}
+	dataWriter, dataError := client.Data()
+	if dataError != nil {
+		return fmt.Errorf("failed to get data writer: %w", dataError)
+	}
+	if _, writeError := dataWriter.Write(rawMessage); writeError != nil {
+		dataWriter.Close()
+		return fmt.Errorf("failed to write email message: %w", writeError)
+	}
+	if closeDataError := dataWriter.Close(); closeDataError != nil {
+		return fmt.Errorf("failed to close data writer: %w", closeDataError)
+	}
+	return nil
+}
 
-		return nil
+// closeOnDone closes connection when ctx ends, which unblocks any read or write in
+// progress; the returned function stops the watch.
+func closeOnDone(ctx context.Context, connection net.Conn) func() bool {
+	return context.AfterFunc(ctx, func() {
+		connection.Close()
+	})
+}
+
+// abortedByContext reports err as ctx's error once ctx has ended, since the failure was
+// then caused by closing the connection rather than by the provider.
+func abortedByContext(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() == nil {
+		return err
 	}
+	return fmt.Errorf("%w: %v", ctx.Err(), err)
+}
 
-	smtpAddress := net.JoinHostPort(senderInstance.Config.Host, senderInstance.Config.Port)
+// VerifyCredentials connects to the SMTP server, upgrades to TLS when offered,
+// authenticates and issues NOOP without starting a mail transaction.
+func (senderInstance *SMTPEmailSender) VerifyCredentials(ctx context.Context) error {
+	serverAddr := net.JoinHostPort(senderInstance.Config.Host, senderInstance.Config.Port)
+	dialer := &net.Dialer{
+		Timeout: time.Duration(senderInstance.Config.Timeouts.ConnectionTimeoutSec) * time.Second,
+	}
+	implicitTLS := senderInstance.Config.Port == "465"
+	var connection net.Conn
+	var dialError error
+	if implicitTLS {
+		connection, dialError = dialTLSFunc(ctx, dialer, "tcp", serverAddr, &tls.Config{
+			InsecureSkipVerify: true, // Matches SendRawEmail.
+			ServerName:         senderInstance.Config.Host,
+		})
+	} else {
+		connection, dialError = dialer.DialContext(ctx, "tcp", serverAddr)
+	}
+	if dialError != nil {
+		return fmt.Errorf("failed to dial: %w", dialError)
+	}
+	if deadline, hasDeadline := ctx.Deadline(); hasDeadline {
+		connection.SetDeadline(deadline)
+	}
+
+	client, clientError := smtp.NewClient(connection, senderInstance.Config.Host)
+	if clientError != nil {
+		connection.Close()
+		return fmt.Errorf("failed to create SMTP client: %w", clientError)
+	}
+	defer client.Close()
+
+	if !implicitTLS {
+		if supported, _ := client.Extension("STARTTLS"); supported {
+			if tlsError := client.StartTLS(&tls.Config{ServerName: senderInstance.Config.Host}); tlsError != nil {
+				return fmt.Errorf("failed to start TLS: %w", tlsError)
+			}
+		}
+	}
 	smtpAuth := smtp.PlainAuth("", senderInstance.Config.Username, senderInstance.Config.Password, senderInstance.Config.Host)
-	sendError := sendMailFunc(smtpAddress, smtpAuth, fromAddress, recipients, rawMessage)
-	if sendError != nil {
-		return fmt.Errorf("smtp send failed: %w", sendError)
+	if authError := client.Auth(smtpAuth); authError != nil {
+		return fmt.Errorf("failed to authenticate: %w", authError)
 	}
-	return nil
+	if noopError := client.Noop(); noopError != nil {
+		return fmt.Errorf("smtp noop failed: %w", noopError)
+	}
+	return client.Quit()
 }
 
-func buildEmailMessage(fromAddress string, toAddress string, subject string, body string, attachments []model.EmailAttachment) string {
-	var builder strings.Builder
-	builder.WriteString(fmt.Sprintf("From: %s\r\n", fromAddress))
-	builder.WriteString(fmt.Sprintf("To: %s\r\n", toAddress))
-	builder.WriteString(fmt.Sprintf("Subject: %s\r\n", subject))
-	builder.WriteString("MIME-Version: 1.0\r\n")
-	if len(attachments) == 0 {
-		builder.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
-		builder.WriteString("\r\n")
-		builder.WriteString(body)
-		return builder.String()
+// classifySMTPError marks replies rejecting the configured credentials as a
+// ProviderAccountError: 534 (the server requires a different mechanism or an app
+// password) and 535 (authentication credentials invalid).
+func classifySMTPError(err error) error {
+	var smtpError *textproto.Error
+	if errors.As(err, &smtpError) && (smtpError.Code == smtpCodeAuthMechanismTooWeak || smtpError.Code == smtpCodeAuthCredentialsInvalid) {
+		return &ProviderAccountError{Err: err}
 	}
+	return err
+}
 
-	boundary := fmt.Sprintf("PinguinBoundary-%d", time.Now().UnixNano())
-	builder.WriteString(fmt.Sprintf("Content-Type: multipart/mixed; boundary=\"%s\"\r\n", boundary))
-	builder.WriteString("\r\n")
+// buildEmailMessage refuses header values carrying CR, LF or other control characters
+// even though requests are validated upstream, so no caller can inject headers. The
+// buffer is sized up front and attachments are base64-encoded straight into it, so a
+// message costs one allocation of about its final size.
+func buildEmailMessage(random entropy.RandomSource, fromAddress string, toAddress string, subject string, body string, attachments []model.EmailAttachment, overrides model.SenderOverrides) ([]byte, error) {
+	headerValues := envelopeHeaderValues(fromAddress, toAddress, subject, overrides)
+	for attachmentIndex, attachment := range attachments {
+		headerValues = append(headerValues, [2]string{fmt.Sprintf("attachment %d content_type", attachmentIndex+1), attachment.ContentType})
+	}
+	for _, headerValue := range headerValues {
+		if err := model.ValidateHeaderValue(headerValue[0], headerValue[1]); err != nil {
+			return nil, err
+		}
+	}
+
+	var buffer bytes.Buffer
+	buffer.Grow(estimateEmailMessageSize(fromAddress, toAddress, subject, body, attachments))
+	if err := writeEnvelopeHeaders(&buffer, fromAddress, toAddress, subject, overrides); err != nil {
+		return nil, err
+	}
+	if len(attachments) == 0 {
+		buffer.WriteString("Content-Type: " + bodyContentType(body) + "\r\n")
+		buffer.WriteString("\r\n")
+		buffer.WriteString(body)
+		return buffer.Bytes(), nil
+	}
 
-	builder.WriteString(fmt.Sprintf("--%s\r\n", boundary))
-	builder.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
-	builder.WriteString("Content-Transfer-Encoding: 7bit\r\n\r\n")
-	builder.WriteString(body)
-	builder.WriteString("\r\n")
+	boundarySuffix, err := random.UniqueSuffix()
+	if err != nil {
+		return nil, fmt.Errorf("mime boundary: %w", err)
+	}
+	boundary := "PinguinBoundary-" + boundarySuffix
+	fmt.Fprintf(&buffer, "Content-Type: multipart/mixed; boundary=\"%s\"\r\n", boundary)
+	buffer.WriteString("\r\n")
+
+	fmt.Fprintf(&buffer, "--%s\r\n", boundary)
+	invite, attachments := splitCalendarInvite(attachments)
+	if invite != nil {
+		// Mail clients render an invite from a text/calendar alternative to the body and
+		// also expect the same data as an .ics file for calendars that import attachments.
+		alternativeBoundary := boundary + "-alt"
+		fmt.Fprintf(&buffer, "Content-Type: multipart/alternative; boundary=\"%s\"\r\n\r\n", alternativeBoundary)
+		fmt.Fprintf(&buffer, "--%s\r\n", alternativeBoundary)
+		writeTextPart(&buffer, body)
+		fmt.Fprintf(&buffer, "--%s\r\n", alternativeBoundary)
+		fmt.Fprintf(&buffer, "Content-Type: %s\r\n", invite.ContentType)
+		buffer.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+		writeBase64Chunked(&buffer, invite.Data)
+		fmt.Fprintf(&buffer, "--%s--\r\n", alternativeBoundary)
+		attachments = append([]model.EmailAttachment{{Filename: invite.Filename, ContentType: calendarFileContentType, Data: invite.Data}}, attachments...)
+	} else {
+		writeTextPart(&buffer, body)
+	}
 
 	for _, attachment := range attachments {
-		builder.WriteString(fmt.Sprintf("--%s\r\n", boundary))
+		fmt.Fprintf(&buffer, "--%s\r\n", boundary)
 		contentType := attachment.ContentType
 		if contentType == "" {
 			contentType = "application/octet-stream"
 		}
-		builder.WriteString(fmt.Sprintf("Content-Type: %s\r\n", contentType))
-		builder.WriteString("Content-Transfer-Encoding: base64\r\n")
-		builder.WriteString(fmt.Sprintf("Content-Disposition: attachment; filename=\"%s\"\r\n", sanitizeFilename(attachment.Filename)))
-		builder.WriteString("\r\n")
-		builder.WriteString(encodeBase64Chunked(attachment.Data))
-		builder.WriteString("\r\n")
+		fmt.Fprintf(&buffer, "Content-Type: %s\r\n", contentType)
+		buffer.WriteString("Content-Transfer-Encoding: base64\r\n")
+		fmt.Fprintf(&buffer, "Content-Disposition: attachment; filename=\"%s\"\r\n", sanitizeFilename(attachment.Filename))
+		buffer.WriteString("\r\n")
+		writeBase64Chunked(&buffer, attachment.Data)
+		buffer.WriteString("\r\n")
 	}
 
-	builder.WriteString(fmt.Sprintf("--%s--\r\n", boundary))
-	return builder.String()
+	fmt.Fprintf(&buffer, "--%s--\r\n", boundary)
+	return buffer.Bytes(), nil
 }
 
-func encodeBase64Chunked(data []byte) string {
-	if len(data) == 0 {
-		return ""
+// buildEncryptedEmailMessage prepends the envelope headers to a caller-encrypted MIME
+// entity, whose own Content-* headers end the header block, and copies it byte for byte.
+func buildEncryptedEmailMessage(fromAddress string, toAddress string, subject string, mimeEntity string, overrides model.SenderOverrides) ([]byte, error) {
+	for _, headerValue := range envelopeHeaderValues(fromAddress, toAddress, subject, overrides) {
+		if err := model.ValidateHeaderValue(headerValue[0], headerValue[1]); err != nil {
+			return nil, err
+		}
 	}
-	encoded := base64.StdEncoding.EncodeToString(data)
-	const lineLength = 76
-	var builder strings.Builder
-	for start := 0; start < len(encoded); start += lineLength {
-		end := start + lineLength
-		if end > len(encoded) {
-			end = len(encoded)
+	var buffer bytes.Buffer
+	buffer.Grow(emailMessageOverheadBytes + len(fromAddress) + len(toAddress) + len(subject) + len(mimeEntity))
+	if err := writeEnvelopeHeaders(&buffer, fromAddress, toAddress, subject, overrides); err != nil {
+		return nil, err
+	}
+	buffer.WriteString(mimeEntity)
+	return buffer.Bytes(), nil
+}
+
+// envelopeHeaderValues names the values writeEnvelopeHeaders puts in headers, for
+// validation before anything is written.
+func envelopeHeaderValues(fromAddress string, toAddress string, subject string, overrides model.SenderOverrides) [][2]string {
+	return [][2]string{{"from", fromAddress}, {"recipient", toAddress}, {"subject", subject}, {"reply_to", overrides.ReplyTo}, {"from_display_name", overrides.FromDisplayName}}
+}
+
+// writeEnvelopeHeaders writes From, Reply-To, To, Subject and MIME-Version. A From
+// display name override replaces the one the tenant's address carries, if any, and is
+// quoted or RFC 2047 encoded as needed; the address itself always stays the tenant's.
+func writeEnvelopeHeaders(buffer *bytes.Buffer, fromAddress string, toAddress string, subject string, overrides model.SenderOverrides) error {
+	fromHeader := fromAddress
+	if overrides.FromDisplayName != "" {
+		parsedFrom, err := mail.ParseAddress(fromAddress)
+		if err != nil {
+			return fmt.Errorf("from address %q: %w", fromAddress, err)
 		}
-		builder.WriteString(encoded[start:end])
-		builder.WriteString("\r\n")
+		fromHeader = (&mail.Address{Name: overrides.FromDisplayName, Address: parsedFrom.Address}).String()
+	}
+	fmt.Fprintf(buffer, "From: %s\r\n", fromHeader)
+	if overrides.ReplyTo != "" {
+		fmt.Fprintf(buffer, "Reply-To: %s\r\n", overrides.ReplyTo)
+	}
+	fmt.Fprintf(buffer, "To: %s\r\n", toAddress)
+	fmt.Fprintf(buffer, "Subject: %s\r\n", subject)
+	buffer.WriteString("MIME-Version: 1.0\r\n")
+	return nil
+}
+
+// emailMessageOverheadBytes covers the headers, boundaries and part headers around
+// the caller-supplied values; a low guess only costs one more buffer growth.
+const emailMessageOverheadBytes = 1024
+
+// estimateEmailMessageSize returns the size buildEmailMessage's output will have,
+// give or take the fixed headers.
+func estimateEmailMessageSize(fromAddress string, toAddress string, subject string, body string, attachments []model.EmailAttachment) int {
+	size := emailMessageOverheadBytes + len(fromAddress) + len(toAddress) + len(subject) + 2*len(body)
+	for _, attachment := range attachments {
+		size += emailMessageOverheadBytes + len(attachment.Filename) + len(attachment.ContentType)
+		encodedSize := base64ChunkedSize(len(attachment.Data))
+		if _, isInvite := model.CalendarInviteMethod(attachment.ContentType); isInvite {
+			// The invite goes out twice: as the calendar alternative and as an .ics file.
+			encodedSize *= 2
+		}
+		size += encodedSize
+	}
+	return size
+}
+
+func writeTextPart(buffer *bytes.Buffer, body string) {
+	buffer.WriteString("Content-Type: " + bodyContentType(body) + "\r\n")
+	buffer.WriteString("Content-Transfer-Encoding: 7bit\r\n\r\n")
+	buffer.WriteString(body)
+	buffer.WriteString("\r\n")
+}
+
+// bodyContentType sends HTML documents as text/html and everything else as plain text.
+func bodyContentType(body string) string {
+	if model.IsHTMLMessage(body) {
+		return "text/html; charset=\"utf-8\""
+	}
+	return "text/plain; charset=\"utf-8\""
+}
+
+// splitCalendarInvite separates the first text/calendar attachment that names an iTIP
+// method from the rest; later ones stay ordinary attachments.
+func splitCalendarInvite(attachments []model.EmailAttachment) (*model.EmailAttachment, []model.EmailAttachment) {
+	for index, attachment := range attachments {
+		if _, isInvite := model.CalendarInviteMethod(attachment.ContentType); isInvite {
+			remaining := append(append([]model.EmailAttachment(nil), attachments[:index]...), attachments[index+1:]...)
+			return &attachment, remaining
+		}
+	}
+	return nil, attachments
+}
+
+const (
+	base64LineLength = 76
+	// base64LineInputBytes is how many input bytes fill one encoded line.
+	base64LineInputBytes = base64LineLength / 4 * 3
+)
+
+// base64ChunkedSize returns the size of data of the given length once encoded into CRLF-terminated lines.
+func base64ChunkedSize(dataLength int) int {
+	encodedLength := base64.StdEncoding.EncodedLen(dataLength)
+	lines := (encodedLength + base64LineLength - 1) / base64LineLength
+	return encodedLength + 2*lines
+}
+
+// writeBase64Chunked encodes data into CRLF-terminated lines of 76 characters, one line
+// at a time, so no encoded copy of the whole attachment is built.
+func writeBase64Chunked(buffer *bytes.Buffer, data []byte) {
+	var line [base64LineLength]byte
+	for start := 0; start < len(data); start += base64LineInputBytes {
+		end := min(start+base64LineInputBytes, len(data))
+		encodedLength := base64.StdEncoding.EncodedLen(end - start)
+		base64.StdEncoding.Encode(line[:encodedLength], data[start:end])
+		buffer.Write(line[:encodedLength])
+		buffer.WriteString("\r\n")
 	}
-	return builder.String()
 }
 
 func sanitizeFilename(filename string) string {
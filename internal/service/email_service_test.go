@@ -4,14 +4,21 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"encoding/base64"
 	"errors"
 	"io"
+	"mime"
+	"mime/multipart"
 	"net"
+	"net/mail"
 	"net/smtp"
+	"net/textproto"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/tyemirov/pinguin/internal/config"
+	"github.com/tyemirov/pinguin/internal/entropy"
 	"github.com/tyemirov/pinguin/internal/model"
 	"log/slog"
 )
@@ -33,7 +40,7 @@ func TestSendEmailPlain(t *testing.T) {
 		body string
 	}
 
-	sendMailFunc = func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+	sendMailFunc = func(_ context.Context, _ time.Duration, addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
 		captured.addr = addr
 		captured.from = from
 		captured.to = append([]string(nil), to...)
@@ -66,6 +73,40 @@ func TestSendEmailPlain(t *testing.T) {
 	}
 }
 
+func TestSendRawEmailAppliesConnectionAndOperationTimeouts(t *testing.T) {
+	originalSendMail := sendMailFunc
+	defer func() {
+		sendMailFunc = originalSendMail
+	}()
+
+	var capturedDialTimeout time.Duration
+	var capturedDeadline time.Time
+	var hasDeadline bool
+	sendMailFunc = func(ctx context.Context, dialTimeout time.Duration, _ string, _ smtp.Auth, _ string, _ []string, _ []byte) error {
+		capturedDialTimeout = dialTimeout
+		capturedDeadline, hasDeadline = ctx.Deadline()
+		return nil
+	}
+
+	sender := NewSMTPEmailSender(SMTPConfig{
+		Host:        "relay.example.com",
+		Port:        "25",
+		FromAddress: "from@example.com",
+		Timeouts:    config.Config{ConnectionTimeoutSec: 30, OperationTimeoutSec: 120},
+	}, newDiscardLogger())
+
+	started := time.Now()
+	if err := sender.SendRawEmail(context.Background(), "from@example.com", []string{"to@example.com"}, []byte("message")); err != nil {
+		t.Fatalf("SendRawEmail returned error: %v", err)
+	}
+	if capturedDialTimeout != 30*time.Second {
+		t.Fatalf("expected a 30s dial timeout, got %s", capturedDialTimeout)
+	}
+	if !hasDeadline || capturedDeadline.Before(started.Add(120*time.Second)) || capturedDeadline.After(time.Now().Add(120*time.Second)) {
+		t.Fatalf("expected a 120s operation deadline, got %v (set=%t)", capturedDeadline, hasDeadline)
+	}
+}
+
 type stubConn struct{}
 
 func (stubConn) Read([]byte) (int, error)         { return 0, io.EOF }
@@ -141,7 +182,7 @@ func TestSendEmailTLS(t *testing.T) {
 		newSMTPClient = originalClient
 	}()
 
-	dialTLSFunc = func(*net.Dialer, string, string, *tls.Config) (net.Conn, error) {
+	dialTLSFunc = func(context.Context, *net.Dialer, string, string, *tls.Config) (net.Conn, error) {
 		return stubConn{}, nil
 	}
 
@@ -188,7 +229,7 @@ func TestSendRawEmailPlainReportsSendMailError(t *testing.T) {
 	defer func() {
 		sendMailFunc = originalSendMail
 	}()
-	sendMailFunc = func(string, smtp.Auth, string, []string, []byte) error {
+	sendMailFunc = func(context.Context, time.Duration, string, smtp.Auth, string, []string, []byte) error {
 		return errors.New("smtp unavailable")
 	}
 
@@ -206,6 +247,105 @@ func TestSendRawEmailPlainReportsSendMailError(t *testing.T) {
 	}
 }
 
+func TestSendRawEmailClassifiesCredentialRejection(t *testing.T) {
+	originalSendMail := sendMailFunc
+	defer func() {
+		sendMailFunc = originalSendMail
+	}()
+	sender := NewSMTPEmailSender(SMTPConfig{
+		Host:        "smtp.example.com",
+		Port:        "587",
+		Username:    "user",
+		Password:    "pass",
+		FromAddress: "from@example.com",
+	}, newDiscardLogger())
+
+	testCases := []struct {
+		name           string
+		sendErr        error
+		accountFailure bool
+	}{
+		{name: "invalid credentials", sendErr: &textproto.Error{Code: 535, Msg: "5.7.8 authentication credentials invalid"}, accountFailure: true},
+		{name: "app password required", sendErr: &textproto.Error{Code: 534, Msg: "5.7.9 application-specific password required"}, accountFailure: true},
+		{name: "mailbox unavailable", sendErr: &textproto.Error{Code: 550, Msg: "5.1.1 user unknown"}},
+		{name: "temporary failure", sendErr: &textproto.Error{Code: 421, Msg: "4.7.0 try again later"}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			sendMailFunc = func(context.Context, time.Duration, string, smtp.Auth, string, []string, []byte) error {
+				return testCase.sendErr
+			}
+			err := sender.SendRawEmail(context.Background(), "from@example.com", []string{"to@example.com"}, []byte("hello"))
+			var accountError *ProviderAccountError
+			if errors.As(err, &accountError) != testCase.accountFailure {
+				t.Fatalf("expected account failure %v, got %v", testCase.accountFailure, err)
+			}
+			if !errors.Is(err, testCase.sendErr) {
+				t.Fatalf("expected the SMTP reply to stay wrapped, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSendRawEmailStopsWhenContextIsCancelled(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+	dataReceived := make(chan struct{})
+	go func() {
+		connection, acceptErr := listener.Accept()
+		if acceptErr != nil {
+			return
+		}
+		defer connection.Close()
+		reader := textproto.NewConn(connection)
+		reader.PrintfLine("220 localhost ESMTP")
+		for {
+			line, readErr := reader.ReadLine()
+			if readErr != nil {
+				return
+			}
+			switch {
+			case strings.HasPrefix(line, "EHLO"):
+				reader.PrintfLine("250 localhost")
+			case line == "DATA":
+				reader.PrintfLine("354 go ahead")
+				if _, readErr := reader.ReadDotLines(); readErr != nil {
+					return
+				}
+				// Stall instead of accepting the message.
+				close(dataReceived)
+				io.Copy(io.Discard, connection)
+				return
+			default:
+				reader.PrintfLine("250 ok")
+			}
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	sender := NewSMTPEmailSender(SMTPConfig{Host: host, Port: port, FromAddress: "from@example.com"}, newDiscardLogger())
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-dataReceived
+		cancel()
+	}()
+	sendDone := make(chan error, 1)
+	go func() {
+		sendDone <- sender.SendRawEmail(ctx, "from@example.com", []string{"to@example.com"}, []byte("hello\r\n"))
+	}()
+	select {
+	case err := <-sendDone:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected the send to report the cancellation, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected cancellation to abort the stalled send")
+	}
+}
+
 func TestSendRawEmailTLSErrorPaths(t *testing.T) {
 	originalDial := dialTLSFunc
 	originalClient := newSMTPClient
@@ -223,7 +363,7 @@ func TestSendRawEmailTLSErrorPaths(t *testing.T) {
 	}, newDiscardLogger())
 
 	t.Run("dial error", func(t *testing.T) {
-		dialTLSFunc = func(*net.Dialer, string, string, *tls.Config) (net.Conn, error) {
+		dialTLSFunc = func(context.Context, *net.Dialer, string, string, *tls.Config) (net.Conn, error) {
 			return nil, errors.New("dial failed")
 		}
 		err := sender.SendRawEmail(context.Background(), "from@example.com", []string{"to@example.com"}, []byte("hello"))
@@ -233,7 +373,7 @@ func TestSendRawEmailTLSErrorPaths(t *testing.T) {
 	})
 
 	t.Run("context canceled after dial", func(t *testing.T) {
-		dialTLSFunc = func(*net.Dialer, string, string, *tls.Config) (net.Conn, error) {
+		dialTLSFunc = func(context.Context, *net.Dialer, string, string, *tls.Config) (net.Conn, error) {
 			return stubConn{}, nil
 		}
 		ctx, cancel := context.WithCancel(context.Background())
@@ -245,7 +385,7 @@ func TestSendRawEmailTLSErrorPaths(t *testing.T) {
 	})
 
 	t.Run("client creation error", func(t *testing.T) {
-		dialTLSFunc = func(*net.Dialer, string, string, *tls.Config) (net.Conn, error) {
+		dialTLSFunc = func(context.Context, *net.Dialer, string, string, *tls.Config) (net.Conn, error) {
 			return stubConn{}, nil
 		}
 		newSMTPClient = func(net.Conn, string) (smtpClient, error) {
@@ -271,7 +411,7 @@ func TestSendRawEmailTLSErrorPaths(t *testing.T) {
 	} {
 		testCase := testCase
 		t.Run(testCase.name, func(t *testing.T) {
-			dialTLSFunc = func(*net.Dialer, string, string, *tls.Config) (net.Conn, error) {
+			dialTLSFunc = func(context.Context, *net.Dialer, string, string, *tls.Config) (net.Conn, error) {
 				return stubConn{}, nil
 			}
 			newSMTPClient = func(net.Conn, string) (smtpClient, error) {
@@ -287,7 +427,7 @@ func TestSendRawEmailTLSErrorPaths(t *testing.T) {
 
 func TestDefaultSMTPDialAndClientConstructors(t *testing.T) {
 	dialer := &net.Dialer{Timeout: time.Millisecond}
-	if _, err := dialTLSFunc(dialer, "tcp", "127.0.0.1:1", &tls.Config{}); err == nil {
+	if _, err := dialTLSFunc(context.Background(), dialer, "tcp", "127.0.0.1:1", &tls.Config{}); err == nil {
 		t.Fatalf("expected default TLS dial to report connection failure")
 	}
 	if _, err := newSMTPClient(stubConn{}, "localhost"); err == nil {
@@ -403,8 +543,10 @@ func TestSMTPClientWrapperUsesUnderlyingClient(t *testing.T) {
 }
 
 func TestEmailMessageHelpers(t *testing.T) {
-	if encoded := encodeBase64Chunked(nil); encoded != "" {
-		t.Fatalf("expected empty encoding, got %q", encoded)
+	var encoded bytes.Buffer
+	writeBase64Chunked(&encoded, nil)
+	if encoded.Len() != 0 {
+		t.Fatalf("expected empty encoding, got %q", encoded.String())
 	}
 	if filename := sanitizeFilename(" \x00\"\\\\ "); filename != "attachment" {
 		t.Fatalf("expected fallback attachment filename, got %q", filename)
@@ -412,13 +554,213 @@ func TestEmailMessageHelpers(t *testing.T) {
 	if filename := sanitizeFilename("   "); filename != "attachment" {
 		t.Fatalf("expected blank filename fallback, got %q", filename)
 	}
-	message := buildEmailMessage("from@example.com", "to@example.com", "Subject", "Body", []model.EmailAttachment{
+	message, err := buildEmailMessage(entropy.System(), "from@example.com", "to@example.com", "Subject", "Body", []model.EmailAttachment{
 		{Filename: " \x00report\".txt ", Data: []byte("hello")},
-	})
-	if !strings.Contains(message, "application/octet-stream") {
+	}, model.SenderOverrides{})
+	if err != nil {
+		t.Fatalf("build message: %v", err)
+	}
+	if !strings.Contains(string(message), "application/octet-stream") {
 		t.Fatalf("expected default attachment content type, got %q", message)
 	}
-	if strings.Contains(message, "\"report\"") {
+	if strings.Contains(string(message), "\"report\"") {
 		t.Fatalf("expected sanitized filename, got %q", message)
 	}
 }
+
+func TestBuildEmailMessageEncodesAttachmentsInLinesWithinTheEstimate(t *testing.T) {
+	data := make([]byte, 3*base64LineInputBytes+10)
+	for index := range data {
+		data[index] = byte(index * 7)
+	}
+	var encoded bytes.Buffer
+	writeBase64Chunked(&encoded, data)
+	lines := strings.Split(strings.TrimSuffix(encoded.String(), "\r\n"), "\r\n")
+	if len(lines) != 4 || len(lines[0]) != base64LineLength || len(lines[3]) > base64LineLength {
+		t.Fatalf("expected three full lines and a short one, got %q", lines)
+	}
+	if strings.Join(lines, "") != base64.StdEncoding.EncodeToString(data) {
+		t.Fatalf("expected the lines to join into the standard encoding")
+	}
+	if encoded.Len() != base64ChunkedSize(len(data)) {
+		t.Fatalf("expected %d encoded bytes, got %d", base64ChunkedSize(len(data)), encoded.Len())
+	}
+
+	attachments := []model.EmailAttachment{
+		{Filename: "large.bin", ContentType: "application/octet-stream", Data: bytes.Repeat(data, 100)},
+		{Filename: "invite.ics", ContentType: "text/calendar; method=REQUEST", Data: []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n")},
+	}
+	message, err := buildEmailMessage(entropy.System(), "from@example.com", "to@example.com", "Subject", "Body", attachments, model.SenderOverrides{})
+	if err != nil {
+		t.Fatalf("build message: %v", err)
+	}
+	if estimate := estimateEmailMessageSize("from@example.com", "to@example.com", "Subject", "Body", attachments); len(message) > estimate {
+		t.Fatalf("expected the message to fit the %d byte estimate, got %d bytes", estimate, len(message))
+	}
+}
+
+func TestBuildEmailMessageSendsHTMLDocumentsAsHTML(t *testing.T) {
+	attachments := []model.EmailAttachment{{Filename: "notes.txt", ContentType: "text/plain", Data: []byte("notes")}}
+	for _, testCase := range []struct {
+		body        string
+		attachments []model.EmailAttachment
+		expected    string
+	}{
+		{body: "Hello <b>there</b>", expected: `Content-Type: text/plain; charset="utf-8"`},
+		{body: "  <!DOCTYPE html><html><body>Hi</body></html>", expected: `Content-Type: text/html; charset="utf-8"`},
+		{body: "<HTML><body>Hi</body></HTML>", attachments: attachments, expected: `Content-Type: text/html; charset="utf-8"`},
+	} {
+		message, err := buildEmailMessage(entropy.System(), "from@example.com", "to@example.com", "Subject", testCase.body, testCase.attachments, model.SenderOverrides{})
+		if err != nil {
+			t.Fatalf("build message: %v", err)
+		}
+		if !strings.Contains(string(message), testCase.expected) {
+			t.Fatalf("expected %q for body %q, got:\n%s", testCase.expected, testCase.body, message)
+		}
+	}
+}
+
+func TestBuildEmailMessageRejectsHeaderInjection(t *testing.T) {
+	testCases := []struct {
+		name          string
+		fromAddress   string
+		toAddress     string
+		subject       string
+		attachments   []model.EmailAttachment
+		expectedField string
+	}{
+		{name: "subject", fromAddress: "from@example.com", toAddress: "to@example.com", subject: "Hi\r\nBcc: attacker@example.com", expectedField: "subject"},
+		{name: "recipient", fromAddress: "from@example.com", toAddress: "to@example.com\nBcc: attacker@example.com", subject: "Hi", expectedField: "recipient"},
+		{name: "from", fromAddress: "from@example.com\r\nReply-To: attacker@example.com", toAddress: "to@example.com", subject: "Hi", expectedField: "from"},
+		{
+			name:          "attachment content type",
+			fromAddress:   "from@example.com",
+			toAddress:     "to@example.com",
+			subject:       "Hi",
+			attachments:   []model.EmailAttachment{{Filename: "a.txt", ContentType: "text/plain\r\nBcc: attacker@example.com", Data: []byte("a")}},
+			expectedField: "attachment 1 content_type",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			message, err := buildEmailMessage(entropy.System(), testCase.fromAddress, testCase.toAddress, testCase.subject, "Body", testCase.attachments, model.SenderOverrides{})
+			if !errors.Is(err, model.ErrNotificationHeaderValueInvalid) || !strings.Contains(err.Error(), testCase.expectedField) {
+				t.Fatalf("expected a header value error naming %q, got %v", testCase.expectedField, err)
+			}
+			if message != nil {
+				t.Fatalf("expected no message to be built, got %q", message)
+			}
+		})
+	}
+}
+
+func TestSendEmailRefusesInjectedHeadersBeforeDialing(t *testing.T) {
+	originalSendMail := sendMailFunc
+	defer func() {
+		sendMailFunc = originalSendMail
+	}()
+	sendMailFunc = func(context.Context, time.Duration, string, smtp.Auth, string, []string, []byte) error {
+		t.Fatalf("expected no SMTP transaction for an injected subject")
+		return nil
+	}
+	sender := NewSMTPEmailSender(SMTPConfig{Host: "smtp.example.com", Port: "587", FromAddress: "from@example.com"}, newDiscardLogger())
+	err := sender.SendEmail(context.Background(), "to@example.com", "Hi\r\nBcc: attacker@example.com", "Body", nil)
+	if !errors.Is(err, model.ErrNotificationHeaderValueInvalid) {
+		t.Fatalf("expected header value error, got %v", err)
+	}
+}
+
+// mimeTree flattens a message into one "depth:media type" entry per part, with a
+// filename when the part is an attachment and the method when it is an invite.
+func mimeTree(t *testing.T, rawMessage string) []string {
+	t.Helper()
+	parsed, err := mail.ReadMessage(strings.NewReader(rawMessage))
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	var tree []string
+	var walk func(depth int, header map[string][]string, body io.Reader)
+	walk = func(depth int, header map[string][]string, body io.Reader) {
+		contentType := "text/plain"
+		if values := header["Content-Type"]; len(values) > 0 {
+			contentType = values[0]
+		}
+		mediaType, parameters, parseErr := mime.ParseMediaType(contentType)
+		if parseErr != nil {
+			t.Fatalf("parse content type %q: %v", contentType, parseErr)
+		}
+		entry := strings.Repeat(" ", depth) + mediaType
+		if method := parameters["method"]; method != "" {
+			entry += " method=" + method
+		}
+		if values := header["Content-Disposition"]; len(values) > 0 {
+			if _, dispositionParameters, dispositionErr := mime.ParseMediaType(values[0]); dispositionErr == nil {
+				entry += " " + dispositionParameters["filename"]
+			}
+		}
+		tree = append(tree, entry)
+		if !strings.HasPrefix(mediaType, "multipart/") {
+			return
+		}
+		reader := multipart.NewReader(body, parameters["boundary"])
+		for {
+			part, partErr := reader.NextRawPart()
+			if errors.Is(partErr, io.EOF) {
+				return
+			}
+			if partErr != nil {
+				t.Fatalf("read part: %v", partErr)
+			}
+			walk(depth+1, part.Header, part)
+		}
+	}
+	walk(0, parsed.Header, parsed.Body)
+	return tree
+}
+
+func TestBuildEmailMessageEmitsCalendarInviteAlternative(t *testing.T) {
+	invite := "BEGIN:VCALENDAR\r\nMETHOD:CANCEL\r\nBEGIN:VEVENT\r\nUID:meeting-1\r\nDTSTART:20260501T090000Z\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	testCases := []struct {
+		name        string
+		attachments []model.EmailAttachment
+		expected    []string
+	}{
+		{name: "plain", expected: []string{"text/plain"}},
+		{
+			name:        "attachment only",
+			attachments: []model.EmailAttachment{{Filename: "notes.txt", ContentType: "text/plain", Data: []byte("notes")}},
+			expected:    []string{"multipart/mixed", " text/plain", " text/plain notes.txt"},
+		},
+		{
+			name:        "calendar file without method",
+			attachments: []model.EmailAttachment{{Filename: "event.ics", ContentType: "text/calendar", Data: []byte(invite)}},
+			expected:    []string{"multipart/mixed", " text/plain", " text/calendar event.ics"},
+		},
+		{
+			name: "invite",
+			attachments: []model.EmailAttachment{
+				{Filename: "notes.txt", ContentType: "text/plain", Data: []byte("notes")},
+				{Filename: model.CalendarInviteFilename, ContentType: `text/calendar; charset="utf-8"; method=CANCEL`, Data: []byte(invite)},
+			},
+			expected: []string{
+				"multipart/mixed",
+				" multipart/alternative",
+				"  text/plain",
+				"  text/calendar method=CANCEL",
+				" application/ics invite.ics",
+				" text/plain notes.txt",
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			message, err := buildEmailMessage(entropy.System(), "from@example.com", "to@example.com", "Subject", "Body", testCase.attachments, model.SenderOverrides{})
+			if err != nil {
+				t.Fatalf("build message: %v", err)
+			}
+			if tree := mimeTree(t, string(message)); strings.Join(tree, "\n") != strings.Join(testCase.expected, "\n") {
+				t.Fatalf("unexpected MIME tree:\n%s\nwant:\n%s", strings.Join(tree, "\n"), strings.Join(testCase.expected, "\n"))
+			}
+		})
+	}
+}
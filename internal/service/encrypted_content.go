@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+)
+
+// ErrEncryptedContentUnsupported rejects caller-encrypted email for a tenant whose email
+// sender cannot relay a MIME entity unchanged.
+var ErrEncryptedContentUnsupported = errors.New("encrypted content unsupported by the configured email sender")
+
+// requireEncryptedEmailSender refuses caller-encrypted email up front when the tenant's
+// sender cannot relay it. A sender that cannot be built yet is left to the dispatch.
+func (serviceInstance *notificationServiceImpl) requireEncryptedEmailSender(runtimeCfg tenant.RuntimeConfig, notification model.Notification) error {
+	if !notification.ContentEncrypted || notification.NotificationType != model.NotificationEmail {
+		return nil
+	}
+	emailSender, err := serviceInstance.emailSenderForTenant(runtimeCfg)
+	if err != nil {
+		return nil
+	}
+	if _, ok := emailSender.(EncryptedEmailSender); !ok {
+		return ErrEncryptedContentUnsupported
+	}
+	return nil
+}
+
+// deliverEmail sends a notification's email through emailSender: caller-encrypted content
+// as its MIME entity, anything else as body with attachments, in both cases under the
+// record's Reply-To and From display name overrides.
+func deliverEmail(ctx context.Context, emailSender EmailSender, record *model.Notification, body string, attachments []model.EmailAttachment) error {
+	overrides := record.SenderOverrides()
+	if record.ContentEncrypted {
+		encryptedSender, ok := emailSender.(EncryptedEmailSender)
+		if !ok {
+			return ErrEncryptedContentUnsupported
+		}
+		return encryptedSender.SendEncryptedEmail(ctx, record.Recipient, record.Subject, record.Message, overrides)
+	}
+	if overrides.IsZero() {
+		return emailSender.SendEmail(ctx, record.Recipient, record.Subject, body, attachments)
+	}
+	overrideSender, ok := emailSender.(SenderOverrideEmailSender)
+	if !ok {
+		return ErrSenderOverridesUnsupported
+	}
+	return overrideSender.SendEmailWithOverrides(ctx, record.Recipient, record.Subject, body, attachments, overrides)
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/config"
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+)
+
+const testEncryptedEntity = "Content-Type: multipart/encrypted; protocol=\"application/pgp-encrypted\"; boundary=\"b1\"\r\n" +
+	"\r\n" +
+	"--b1\r\nContent-Type: application/pgp-encrypted\r\n\r\nVersion: 1\r\n" +
+	"--b1\r\nContent-Type: application/octet-stream\r\n\r\n-----BEGIN PGP MESSAGE-----\r\nhQEMA <html> \r\n-----END PGP MESSAGE-----\r\n" +
+	"--b1--\r\n"
+
+func mustEncryptedRequest(t *testing.T, notificationType model.NotificationType, recipient string, message string) model.NotificationRequest {
+	t.Helper()
+	request, err := mustNotificationRequest(t, notificationType, recipient, "Subject", message, nil, nil).WithEncryptedContent()
+	if err != nil {
+		t.Fatalf("WithEncryptedContent error: %v", err)
+	}
+	return request
+}
+
+func TestSendNotificationRelaysEncryptedEmailVerbatim(t *testing.T) {
+	originalSendMail := sendMailFunc
+	defer func() { sendMailFunc = originalSendMail }()
+	var transmitted []byte
+	sendMailFunc = func(_ context.Context, _ time.Duration, _ string, _ smtp.Auth, _ string, _ []string, msg []byte) error {
+		transmitted = append([]byte(nil), msg...)
+		return nil
+	}
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), nil, &stubSmsSender{})
+	serviceInstance.openTrackingPixels = newOpenTrackingPixels(config.Config{OpenTrackingBaseURL: "https://mail.example.com", MasterEncryptionKey: strings.Repeat("a", 64)})
+	runtimeCfg := baseRuntimeConfig()
+	runtimeCfg.Tenant.OpenTracking = true
+	runtimeCfg.Tenant.StoreRenderedContent = true
+	ctx := tenant.WithRuntime(context.Background(), runtimeCfg)
+
+	response, err := serviceInstance.SendNotification(ctx, mustEncryptedRequest(t, model.NotificationEmail, "user@example.com", testEncryptedEntity))
+	if err != nil {
+		t.Fatalf("SendNotification error: %v", err)
+	}
+	if response.Status != model.StatusSent || !response.ContentEncrypted {
+		t.Fatalf("expected a sent encrypted notification, got %+v", response)
+	}
+	expected := "From: noreply@test\r\nTo: user@example.com\r\nSubject: Subject\r\nMIME-Version: 1.0\r\n" + testEncryptedEntity
+	if string(transmitted) != expected {
+		t.Fatalf("expected the entity to be transmitted unchanged:\n%q\ngot\n%q", expected, transmitted)
+	}
+	stored, err := model.GetNotificationByID(ctx, serviceInstance.database, testTenantID, response.NotificationID)
+	if err != nil {
+		t.Fatalf("GetNotificationByID error: %v", err)
+	}
+	if stored.Message != testEncryptedEntity || !stored.ContentEncrypted {
+		t.Fatalf("expected the entity to be stored unchanged, got %q", stored.Message)
+	}
+	if _, found, err := model.GetRenderedContent(ctx, serviceInstance.database, testTenantID, response.NotificationID); err != nil || found {
+		t.Fatalf("expected no rendered copy of encrypted content, found=%v (%v)", found, err)
+	}
+
+	estimate, err := serviceInstance.EstimateNotificationSize(ctx, mustEncryptedRequest(t, model.NotificationEmail, "user@example.com", testEncryptedEntity))
+	if err != nil || estimate.MessageSizeBytes != len(expected) {
+		t.Fatalf("expected the estimate to match the transmitted size %d, got %+v (%v)", len(expected), estimate, err)
+	}
+}
+
+func TestSendNotificationRejectsEncryptedEmailForPlainSender(t *testing.T) {
+	emailSender := &stubEmailSender{}
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), emailSender, &stubSmsSender{})
+
+	_, err := serviceInstance.SendNotification(tenantContext(), mustEncryptedRequest(t, model.NotificationEmail, "user@example.com", testEncryptedEntity))
+	if !errors.Is(err, ErrEncryptedContentUnsupported) {
+		t.Fatalf("expected ErrEncryptedContentUnsupported, got %v", err)
+	}
+	if emailSender.callCount != 0 {
+		t.Fatalf("expected nothing to be sent")
+	}
+}
+
+func TestSendNotificationSendsEncryptedSMSUntruncated(t *testing.T) {
+	smsSender := &stubSmsSender{}
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), &stubEmailSender{}, smsSender)
+	runtimeCfg := baseRuntimeConfig()
+	runtimeCfg.Tenant.SMSMaxSegments = 1
+	runtimeCfg.Tenant.SMSOverflowPolicy = string(model.SMSOverflowTruncate)
+	ctx := tenant.WithRuntime(context.Background(), runtimeCfg)
+
+	ciphertext := "hQEMA8x9/ciphertext=="
+	if _, err := serviceInstance.SendNotification(ctx, mustEncryptedRequest(t, model.NotificationSMS, "+15555550100", ciphertext)); err != nil {
+		t.Fatalf("SendNotification error: %v", err)
+	}
+	if smsSender.lastMessage != ciphertext {
+		t.Fatalf("expected the ciphertext to be sent as is, got %q", smsSender.lastMessage)
+	}
+	_, err := serviceInstance.SendNotification(ctx, mustEncryptedRequest(t, model.NotificationSMS, "+15555550100", strings.Repeat("A", 200)))
+	if !errors.Is(err, model.ErrNotificationSMSTooLong) {
+		t.Fatalf("expected oversized ciphertext to be rejected despite the tenant's truncate policy, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+)
+
+// errDispatchCancelled is the cancellation cause of a dispatch stopped by CancelNotification.
+var errDispatchCancelled = errors.New("notification cancelled during dispatch")
+
+// dispatchCancellation is the cause CancelNotification cancels a dispatch with; it
+// carries the reason and actor the cancelled notification records.
+type dispatchCancellation struct {
+	reason string
+	actor  string
+}
+
+func (cancellation *dispatchCancellation) Error() string {
+	return errDispatchCancelled.Error()
+}
+
+func (cancellation *dispatchCancellation) Is(target error) bool {
+	return target == errDispatchCancelled
+}
+
+type inFlightKey struct {
+	tenantID       string
+	notificationID string
+}
+
+type inFlightDispatch struct {
+	cancel context.CancelCauseFunc
+	done   chan struct{}
+	// outcome is the notification as its dispatch left it; it is set before done closes.
+	outcome model.Notification
+}
+
+// inFlightDispatches tracks the provider sends in progress so CancelNotification can
+// abort them. The zero value is ready to use.
+type inFlightDispatches struct {
+	mutex   sync.Mutex
+	entries map[inFlightKey]*inFlightDispatch
+}
+
+// begin registers a dispatch and returns the context its provider call must use. The
+// returned finish records how the dispatch left the notification and must be called
+// once, after the outcome is final.
+func (dispatches *inFlightDispatches) begin(ctx context.Context, tenantID string, notificationID string) (context.Context, func(model.Notification)) {
+	dispatchCtx, cancel := context.WithCancelCause(ctx)
+	entry := &inFlightDispatch{cancel: cancel, done: make(chan struct{})}
+	key := inFlightKey{tenantID: tenantID, notificationID: notificationID}
+	dispatches.mutex.Lock()
+	if dispatches.entries == nil {
+		dispatches.entries = make(map[inFlightKey]*inFlightDispatch)
+	}
+	dispatches.entries[key] = entry
+	dispatches.mutex.Unlock()
+	return dispatchCtx, func(outcome model.Notification) {
+		dispatches.mutex.Lock()
+		if dispatches.entries[key] == entry {
+			delete(dispatches.entries, key)
+		}
+		dispatches.mutex.Unlock()
+		entry.outcome = outcome
+		close(entry.done)
+		cancel(nil)
+	}
+}
+
+// cancel aborts the notification's dispatch if one is in progress and returns it, so
+// the caller can wait for its outcome. The dispatch records reason and actor when the
+// abort cancels the notification.
+func (dispatches *inFlightDispatches) cancel(tenantID string, notificationID string, reason string, actor string) (*inFlightDispatch, bool) {
+	dispatches.mutex.Lock()
+	defer dispatches.mutex.Unlock()
+	entry, inFlight := dispatches.entries[inFlightKey{tenantID: tenantID, notificationID: notificationID}]
+	if inFlight {
+		entry.cancel(&dispatchCancellation{reason: reason, actor: actor})
+	}
+	return entry, inFlight
+}
+
+// dispatchCancelled reports whether CancelNotification stopped the dispatch using ctx.
+func dispatchCancelled(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), errDispatchCancelled)
+}
+
+// markDispatchCancelled cancels record with the reason and actor CancelNotification
+// stopped the dispatch using ctx with.
+func markDispatchCancelled(ctx context.Context, record *model.Notification, currentTime time.Time) {
+	var cancellation *dispatchCancellation
+	if errors.As(context.Cause(ctx), &cancellation) {
+		record.MarkCancelled(currentTime, cancellation.reason, cancellation.actor)
+		return
+	}
+	record.MarkCancelled(currentTime, "", "")
+}
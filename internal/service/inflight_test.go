@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/utils/scheduler"
+)
+
+// blockingSender holds every send until its context ends. With acceptDespiteCancel it
+// then reports the message as accepted, as a provider that finished DATA would.
+type blockingSender struct {
+	started             chan struct{}
+	acceptDespiteCancel bool
+}
+
+func newBlockingSender() *blockingSender {
+	return &blockingSender{started: make(chan struct{}, 1)}
+}
+
+func (sender *blockingSender) block(ctx context.Context) error {
+	sender.started <- struct{}{}
+	<-ctx.Done()
+	if sender.acceptDespiteCancel {
+		return nil
+	}
+	return ctx.Err()
+}
+
+func (sender *blockingSender) SendEmail(ctx context.Context, _ string, _ string, _ string, _ []model.EmailAttachment) error {
+	return sender.block(ctx)
+}
+
+func (sender *blockingSender) SendSms(ctx context.Context, _ string, _ string) (string, error) {
+	if err := sender.block(ctx); err != nil {
+		return "", err
+	}
+	return "SM-accepted", nil
+}
+
+func inFlightNotificationID(t *testing.T, serviceInstance *notificationServiceImpl) string {
+	t.Helper()
+	serviceInstance.inFlight.mutex.Lock()
+	defer serviceInstance.inFlight.mutex.Unlock()
+	for key := range serviceInstance.inFlight.entries {
+		return key.notificationID
+	}
+	t.Fatalf("expected a dispatch in flight")
+	return ""
+}
+
+func TestCancelNotificationAbortsImmediateSend(t *testing.T) {
+	sender := newBlockingSender()
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), sender, sender)
+	ctx := tenantContext()
+
+	sendDone := make(chan model.NotificationResponse, 1)
+	go func() {
+		response, err := serviceInstance.SendNotification(ctx, mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", nil, nil))
+		if err != nil {
+			t.Errorf("SendNotification error: %v", err)
+		}
+		sendDone <- response
+	}()
+	<-sender.started
+
+	cancelled, err := serviceInstance.CancelNotification(ctx, inFlightNotificationID(t, serviceInstance), "", "")
+	if err != nil {
+		t.Fatalf("CancelNotification error: %v", err)
+	}
+	if cancelled.Status != model.StatusCancelled {
+		t.Fatalf("expected the aborted send to be cancelled, got %s", cancelled.Status)
+	}
+	sent := <-sendDone
+	if sent.Status != model.StatusCancelled {
+		t.Fatalf("expected the send to report the cancellation, got %s", sent.Status)
+	}
+	stored, err := model.GetNotificationByID(ctx, serviceInstance.database, testTenantID, sent.NotificationID)
+	if err != nil {
+		t.Fatalf("fetch error: %v", err)
+	}
+	if stored.Status != model.StatusCancelled || stored.RetryCount != 0 {
+		t.Fatalf("expected a cancelled record without retries, got %s retries=%d", stored.Status, stored.RetryCount)
+	}
+}
+
+func TestCancelNotificationReportsSendAcceptedBeforeAbort(t *testing.T) {
+	sender := newBlockingSender()
+	sender.acceptDespiteCancel = true
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), sender, sender)
+	ctx := tenantContext()
+
+	sendDone := make(chan model.NotificationResponse, 1)
+	go func() {
+		response, _ := serviceInstance.SendNotification(ctx, mustNotificationRequest(t, model.NotificationSMS, "+15555550100", "", "Body", nil, nil))
+		sendDone <- response
+	}()
+	<-sender.started
+
+	cancelled, err := serviceInstance.CancelNotification(ctx, inFlightNotificationID(t, serviceInstance), "", "")
+	if err != nil {
+		t.Fatalf("CancelNotification error: %v", err)
+	}
+	if cancelled.Status != model.StatusSent || cancelled.ProviderMessageID != "SM-accepted" {
+		t.Fatalf("expected the accepted send to stay sent, got %s/%q", cancelled.Status, cancelled.ProviderMessageID)
+	}
+	if sent := <-sendDone; sent.Status != model.StatusSent {
+		t.Fatalf("expected the send to report delivery, got %s", sent.Status)
+	}
+}
+
+func TestCancelNotificationAbortsRetryAttempt(t *testing.T) {
+	sender := newBlockingSender()
+	database := openIsolatedDatabase(t)
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(database, sender, sender)
+	insertNotificationRecord(t, database, model.Notification{
+		NotificationID:   "notif-retry",
+		NotificationType: model.NotificationEmail,
+		Recipient:        "user@example.com",
+		Subject:          "Subject",
+		Message:          "Body",
+		Status:           model.StatusErrored,
+		RetryCount:       1,
+	})
+	store := newNotificationRetryStore(database, nil)
+	jobs, err := store.PendingJobs(tenantContext(), 5, time.Now().Add(time.Hour))
+	if err != nil || len(jobs) != 1 {
+		t.Fatalf("expected one pending job, got %d err=%v", len(jobs), err)
+	}
+
+	type attemptReturn struct {
+		result scheduler.DispatchResult
+		err    error
+	}
+	attemptDone := make(chan attemptReturn, 1)
+	go func() {
+		result, err := newNotificationDispatcher(serviceInstance).Attempt(tenantContext(), jobs[0])
+		attemptDone <- attemptReturn{result: result, err: err}
+	}()
+	<-sender.started
+
+	cancelled, err := serviceInstance.CancelNotification(tenantContext(), "notif-retry", "", "")
+	if err != nil || cancelled.Status != model.StatusCancelled {
+		t.Fatalf("expected the retry attempt to be cancelled, got %s err=%v", cancelled.Status, err)
+	}
+	outcome := <-attemptDone
+	if outcome.err != nil || outcome.result.Status != string(model.StatusCancelled) {
+		t.Fatalf("expected a cancelled attempt result, got %+v err=%v", outcome.result, outcome.err)
+	}
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+)
+
+// StartIntegrityWorker sweeps attachment rows once at startup and then every
+// server.integritySweepIntervalSec, keeping the latest report for ListTenantsStatus.
+// Orphans are deleted only when server.integritySweepRepairOrphans is set.
+func (serviceInstance *notificationServiceImpl) StartIntegrityWorker(ctx context.Context) {
+	interval := time.Duration(serviceInstance.config.IntegritySweepIntervalSec) * time.Second
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		serviceInstance.runIntegritySweep(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (serviceInstance *notificationServiceImpl) runIntegritySweep(ctx context.Context) {
+	report, err := model.CheckAttachmentIntegrity(ctx, serviceInstance.database, serviceInstance.config.IntegritySweepRepairOrphans, serviceInstance.currentTime())
+	if err != nil {
+		serviceInstance.logger.Error("Attachment integrity sweep failed", "error", err)
+		return
+	}
+	serviceInstance.integrityMutex.Lock()
+	serviceInstance.lastIntegrity = &report
+	serviceInstance.integrityMutex.Unlock()
+	serviceInstance.logger.Info("attachment_integrity_sweep",
+		"orphaned_attachments", report.OrphanedAttachments,
+		"attachment_size_mismatches", report.AttachmentSizeMismatches,
+		"duplicate_notification_ids", report.DuplicateNotificationIDs,
+		"repaired_orphans", report.RepairedOrphans,
+	)
+}
+
+// tenantIntegrity returns the tenant's share of the latest sweep, or nil before the first sweep.
+func (serviceInstance *notificationServiceImpl) tenantIntegrity(tenantID string) *TenantIntegrity {
+	serviceInstance.integrityMutex.RLock()
+	defer serviceInstance.integrityMutex.RUnlock()
+	if serviceInstance.lastIntegrity == nil {
+		return nil
+	}
+	return &TenantIntegrity{
+		CheckedAt:                 serviceInstance.lastIntegrity.CheckedAt,
+		AttachmentIntegrityCounts: serviceInstance.lastIntegrity.Tenants[tenantID],
+	}
+}
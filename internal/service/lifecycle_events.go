@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/pkg/logging"
+)
+
+// logLifecycleEvent logs where a saved notification now stands: terminal once it is
+// sent, cancelled or dead-lettered, and retry_scheduled while a failed send awaits the
+// retry worker. Queued notifications have nothing to report yet.
+func (serviceInstance *notificationServiceImpl) logLifecycleEvent(ctx context.Context, record *model.Notification) {
+	attributes := []any{
+		"notification_id", record.NotificationID,
+		"tenant_id", record.TenantID,
+		"notification_type", record.NotificationType,
+		"status", record.Status,
+		"retry_count", record.RetryCount,
+	}
+	switch record.Status {
+	case model.StatusSent, model.StatusCancelled:
+		logging.LogEvent(ctx, serviceInstance.logger, logging.EventNotificationTerminal, "notification_terminal", attributes...)
+	case model.StatusErrored:
+		if deadLettered(record, serviceInstance.maxRetries) {
+			logging.LogEvent(ctx, serviceInstance.logger, logging.EventNotificationTerminal, "notification_terminal", append(attributes, "dead_lettered", true)...)
+			return
+		}
+		if record.NextAttemptAt != nil {
+			attributes = append(attributes, "next_attempt_at", record.NextAttemptAt.UTC())
+		}
+		logging.LogEvent(ctx, serviceInstance.logger, logging.EventNotificationRetryScheduled, "notification_retry_scheduled", attributes...)
+	}
+}
+
+// deadLettered reports whether an errored notification will not be retried again.
+func deadLettered(record *model.Notification, maxRetries int) bool {
+	return record.RetriesAbandoned() || (maxRetries > 0 && record.RetryCount >= maxRetries)
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/pkg/logging"
+)
+
+func TestSendNotificationLogsLifecycleEvents(t *testing.T) {
+	for _, testCase := range []struct {
+		name           string
+		sendErr        error
+		expectedEvents []logging.Event
+	}{
+		{
+			name:           "sent",
+			expectedEvents: []logging.Event{logging.EventNotificationReceived, logging.EventNotificationDispatchAttempted, logging.EventNotificationPersisted, logging.EventNotificationTerminal},
+		},
+		{
+			name:           "failed",
+			sendErr:        errors.New("smtp unavailable"),
+			expectedEvents: []logging.Event{logging.EventNotificationReceived, logging.EventNotificationDispatchFailed, logging.EventNotificationPersisted, logging.EventNotificationRetryScheduled},
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), &stubEmailSender{err: testCase.sendErr}, &stubSmsSender{})
+			var logOutput bytes.Buffer
+			serviceInstance.logger = slog.New(slog.NewJSONHandler(&logOutput, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+			response, _ := serviceInstance.SendNotification(tenantContext(), mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", nil, nil))
+
+			var events []logging.Event
+			for _, line := range strings.Split(strings.TrimSpace(logOutput.String()), "\n") {
+				var entry map[string]interface{}
+				if err := json.Unmarshal([]byte(line), &entry); err != nil {
+					t.Fatalf("decode log line %q: %v", line, err)
+				}
+				name, hasEvent := entry[logging.EventKey].(string)
+				if !hasEvent {
+					continue
+				}
+				event := logging.Event(name)
+				events = append(events, event)
+				if entry["level"] != event.Level().String() {
+					t.Fatalf("expected %s at %s, got %v", event, event.Level(), entry["level"])
+				}
+				if event != logging.EventNotificationReceived && entry["notification_id"] != response.NotificationID {
+					t.Fatalf("expected %s to name the notification, got %+v", event, entry)
+				}
+			}
+			if !slices.Equal(events, testCase.expectedEvents) {
+				t.Fatalf("expected events %v, got %v", testCase.expectedEvents, events)
+			}
+		})
+	}
+}
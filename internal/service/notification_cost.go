@@ -0,0 +1,52 @@
+package service
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+)
+
+// twilioPriceFields captures the billing fields Twilio includes in message resources.
+type twilioPriceFields struct {
+	Price     *string `json:"price"`
+	PriceUnit string  `json:"price_unit"`
+}
+
+// applyDispatchCost stamps the estimated cost on a successfully dispatched notification.
+// Twilio's reported price wins over the configured estimate when it is present and in the tenant currency.
+func applyDispatchCost(tenantModel tenant.Tenant, notificationRecord *model.Notification, providerResponse string) {
+	notificationRecord.CostCurrency = tenantModel.CostCurrency
+	switch notificationRecord.NotificationType {
+	case model.NotificationEmail:
+		notificationRecord.EstimatedCost = tenantModel.EmailUnitCost
+	case model.NotificationSMS:
+		if reportedPrice, reportedCurrency, ok := twilioReportedPrice(providerResponse); ok &&
+			(tenantModel.CostCurrency == "" || strings.EqualFold(reportedCurrency, tenantModel.CostCurrency)) {
+			notificationRecord.EstimatedCost = reportedPrice
+			notificationRecord.CostCurrency = strings.ToUpper(reportedCurrency)
+			return
+		}
+		segmentCount := model.SMSSegmentCount(notificationRecord.Message)
+		notificationRecord.EstimatedCost = float64(segmentCount) * tenantModel.SMSSegmentUnitCost
+	}
+}
+
+// twilioReportedPrice extracts the absolute price from a Twilio message resource body.
+func twilioReportedPrice(providerResponse string) (float64, string, bool) {
+	var fields twilioPriceFields
+	if err := json.Unmarshal([]byte(providerResponse), &fields); err != nil {
+		return 0, "", false
+	}
+	if fields.Price == nil || strings.TrimSpace(fields.PriceUnit) == "" {
+		return 0, "", false
+	}
+	parsedPrice, parseErr := strconv.ParseFloat(strings.TrimSpace(*fields.Price), 64)
+	if parseErr != nil {
+		return 0, "", false
+	}
+	return math.Abs(parsedPrice), strings.TrimSpace(fields.PriceUnit), true
+}
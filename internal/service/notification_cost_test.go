@@ -0,0 +1,112 @@
+package service
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+)
+
+func TestApplyDispatchCost(t *testing.T) {
+	costedTenant := tenant.Tenant{ID: "tenant-cost", CostCurrency: "USD", EmailUnitCost: 0.0004, SMSSegmentUnitCost: 0.0079}
+	testCases := []struct {
+		name             string
+		tenantModel      tenant.Tenant
+		record           model.Notification
+		providerResponse string
+		expectedCost     float64
+		expectedCurrency string
+	}{
+		{
+			name:             "email uses configured unit cost",
+			tenantModel:      costedTenant,
+			record:           model.Notification{NotificationType: model.NotificationEmail, Message: "hello"},
+			expectedCost:     0.0004,
+			expectedCurrency: "USD",
+		},
+		{
+			name:             "sms multiplies segments by unit cost",
+			tenantModel:      costedTenant,
+			record:           model.Notification{NotificationType: model.NotificationSMS, Message: strings.Repeat("a", 200)},
+			providerResponse: `{"sid":"SM1","price":null,"price_unit":"USD"}`,
+			expectedCost:     2 * 0.0079,
+			expectedCurrency: "USD",
+		},
+		{
+			name:             "sms prefers twilio reported price",
+			tenantModel:      costedTenant,
+			record:           model.Notification{NotificationType: model.NotificationSMS, Message: strings.Repeat("a", 200)},
+			providerResponse: `{"sid":"SM1","price":"-0.02370","price_unit":"usd"}`,
+			expectedCost:     0.0237,
+			expectedCurrency: "USD",
+		},
+		{
+			name:             "sms ignores reported price in another currency",
+			tenantModel:      costedTenant,
+			record:           model.Notification{NotificationType: model.NotificationSMS, Message: "short"},
+			providerResponse: `{"sid":"SM1","price":"-0.05","price_unit":"EUR"}`,
+			expectedCost:     0.0079,
+			expectedCurrency: "USD",
+		},
+		{
+			name:             "tenant without cost model records nothing",
+			tenantModel:      tenant.Tenant{ID: "tenant-free"},
+			record:           model.Notification{NotificationType: model.NotificationEmail, Message: "hello"},
+			expectedCost:     0,
+			expectedCurrency: "",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			record := testCase.record
+			applyDispatchCost(testCase.tenantModel, &record, testCase.providerResponse)
+			if math.Abs(record.EstimatedCost-testCase.expectedCost) > 1e-9 {
+				t.Fatalf("expected cost %v, got %v", testCase.expectedCost, record.EstimatedCost)
+			}
+			if record.CostCurrency != testCase.expectedCurrency {
+				t.Fatalf("expected currency %q, got %q", testCase.expectedCurrency, record.CostCurrency)
+			}
+		})
+	}
+}
+
+func TestSendNotificationStampsEstimatedCostAndSummarizes(t *testing.T) {
+	database := openIsolatedDatabase(t)
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(database, &stubEmailSender{}, &stubSmsSender{})
+	runtimeCfg := baseRuntimeConfig()
+	runtimeCfg.Tenant.CostCurrency = "USD"
+	runtimeCfg.Tenant.EmailUnitCost = 0.001
+	runtimeCfg.Tenant.SMSSegmentUnitCost = 0.01
+	ctx := tenant.WithRuntime(t.Context(), runtimeCfg)
+
+	emailResponse, emailErr := serviceInstance.SendNotification(ctx, mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", nil, nil))
+	if emailErr != nil {
+		t.Fatalf("send email: %v", emailErr)
+	}
+	if emailResponse.EstimatedCost != 0.001 || emailResponse.CostCurrency != "USD" {
+		t.Fatalf("unexpected email cost %v %s", emailResponse.EstimatedCost, emailResponse.CostCurrency)
+	}
+	smsResponse, smsErr := serviceInstance.SendNotification(ctx, mustNotificationRequest(t, model.NotificationSMS, "+15555550100", "", strings.Repeat("ж", 71), nil, nil))
+	if smsErr != nil {
+		t.Fatalf("send sms: %v", smsErr)
+	}
+	if math.Abs(smsResponse.EstimatedCost-0.02) > 1e-9 {
+		t.Fatalf("expected two-segment sms cost, got %v", smsResponse.EstimatedCost)
+	}
+
+	now := time.Now().UTC()
+	summaryRange, rangeErr := model.NewCostSummaryRange(now.Add(-time.Hour), now.Add(time.Hour))
+	if rangeErr != nil {
+		t.Fatalf("range: %v", rangeErr)
+	}
+	summary, summaryErr := serviceInstance.GetCostSummary(ctx, summaryRange)
+	if summaryErr != nil {
+		t.Fatalf("summary: %v", summaryErr)
+	}
+	if summary.Currency != "USD" || math.Abs(summary.TotalCost-0.021) > 1e-9 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+}
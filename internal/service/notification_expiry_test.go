@@ -0,0 +1,112 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+)
+
+func TestRetryWorkerSkipsExpiredQueuedNotification(t *testing.T) {
+	database := openIsolatedDatabase(t)
+	emailSender := &stubEmailSender{}
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(database, emailSender, &stubSmsSender{})
+
+	now := time.Now().UTC()
+	scheduledFor := now.Add(-10 * time.Minute)
+	expiresAt := now.Add(-time.Minute)
+	expiredNotification := model.Notification{
+		TenantID:         testTenantID,
+		NotificationID:   "notif-expired",
+		NotificationType: model.NotificationEmail,
+		Recipient:        "user@example.com",
+		Message:          "Body",
+		Status:           model.StatusQueued,
+		ScheduledFor:     &scheduledFor,
+		ExpiresAt:        &expiresAt,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if createErr := model.CreateNotification(tenantContext(), database, &expiredNotification); createErr != nil {
+		t.Fatalf("create notification error: %v", createErr)
+	}
+
+	worker := newRetryWorkerForTest(t, serviceInstance, &adjustableClock{now: now})
+	worker.RunOnce(tenantContext())
+	if emailSender.callCount != 0 {
+		t.Fatalf("expected expired notification not to be dispatched")
+	}
+
+	stored, fetchErr := model.GetNotificationByID(tenantContext(), database, testTenantID, "notif-expired")
+	if fetchErr != nil {
+		t.Fatalf("fetch notification error: %v", fetchErr)
+	}
+	if stored.Status != model.StatusCancelled || stored.CancelReason != model.CancelReasonExpired {
+		t.Fatalf("expected cancelled/expired, got %s/%q", stored.Status, stored.CancelReason)
+	}
+
+	worker.RunOnce(tenantContext())
+	if emailSender.callCount != 0 {
+		t.Fatalf("expected cancelled notification to stay undispatched")
+	}
+}
+
+func TestSendNotificationSkipsAlreadyExpiredImmediateSend(t *testing.T) {
+	database := openIsolatedDatabase(t)
+	emailSender := &stubEmailSender{}
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(database, emailSender, &stubSmsSender{})
+
+	request, expiryErr := mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", nil, nil).
+		WithExpiresAt(time.Now().UTC().Add(-time.Second))
+	if expiryErr != nil {
+		t.Fatalf("expiry error: %v", expiryErr)
+	}
+	response, sendErr := serviceInstance.SendNotification(tenantContext(), request)
+	if sendErr != nil {
+		t.Fatalf("send error: %v", sendErr)
+	}
+	if emailSender.callCount != 0 {
+		t.Fatalf("expected expired send to be skipped")
+	}
+	if response.Status != model.StatusCancelled || response.CancelReason != model.CancelReasonExpired {
+		t.Fatalf("expected cancelled/expired response, got %s/%q", response.Status, response.CancelReason)
+	}
+}
+
+func TestRescheduleNotificationRejectsScheduleAfterExpiry(t *testing.T) {
+	database := openIsolatedDatabase(t)
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(database, &stubEmailSender{}, &stubSmsSender{})
+
+	scheduledFor := time.Now().UTC().Add(time.Hour)
+	request, expiryErr := mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", &scheduledFor, nil).
+		WithExpiresAt(scheduledFor.Add(time.Hour))
+	if expiryErr != nil {
+		t.Fatalf("expiry error: %v", expiryErr)
+	}
+	response, sendErr := serviceInstance.SendNotification(tenantContext(), request)
+	if sendErr != nil {
+		t.Fatalf("send error: %v", sendErr)
+	}
+
+	testCases := []struct {
+		name          string
+		scheduledFor  time.Time
+		expectedError error
+	}{
+		{name: "before expiry", scheduledFor: scheduledFor.Add(30 * time.Minute)},
+		{name: "at expiry", scheduledFor: scheduledFor.Add(time.Hour), expectedError: ErrScheduleAfterExpiry},
+		{name: "after expiry", scheduledFor: scheduledFor.Add(2 * time.Hour), expectedError: ErrScheduleAfterExpiry},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, rescheduleErr := serviceInstance.RescheduleNotification(tenantContext(), response.NotificationID, testCase.scheduledFor)
+			if testCase.expectedError == nil && rescheduleErr != nil {
+				t.Fatalf("unexpected reschedule error: %v", rescheduleErr)
+			}
+			if testCase.expectedError != nil && !errors.Is(rescheduleErr, testCase.expectedError) {
+				t.Fatalf("expected %v, got %v", testCase.expectedError, rescheduleErr)
+			}
+		})
+	}
+}
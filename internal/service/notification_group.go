@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+	"github.com/tyemirov/pinguin/pkg/logging"
+)
+
+// SendNotificationGroup stores one notification per channel under a shared group id and
+// attempts each immediate dispatch on its own, so a failing channel never holds back
+// the others. Every channel is checked before any is stored: a rejected channel fails
+// the whole request with an error naming it. Failed dispatches stay errored for the
+// retry worker, and sends refused by the in-flight limit stay queued for it.
+func (serviceInstance *notificationServiceImpl) SendNotificationGroup(ctx context.Context, request model.NotificationGroupRequest) (model.NotificationGroupResponse, error) {
+	runtimeCfg, err := serviceInstance.requireTenant(ctx)
+	if err != nil {
+		return model.NotificationGroupResponse{}, err
+	}
+	groupID, err := serviceInstance.newNotificationGroupID()
+	if err != nil {
+		return model.NotificationGroupResponse{}, err
+	}
+	currentTime := serviceInstance.currentTime()
+	channels := request.Channels()
+	logging.LogEvent(ctx, serviceInstance.logger, logging.EventNotificationReceived, "notification_group_received", "group_id", groupID, "tenant_id", runtimeCfg.Tenant.ID, "channels", len(channels))
+	pendingSends := make([]pendingSend, 0, len(channels))
+	for channelIndex, channel := range channels {
+		if err := serviceInstance.requireSender(runtimeCfg, channel.NotificationType()); err != nil {
+			return model.NotificationGroupResponse{}, fmt.Errorf("channel %d: %w", channelIndex+1, err)
+		}
+		pending, err := serviceInstance.prepareSend(runtimeCfg, channel, groupID, currentTime)
+		if err != nil {
+			logging.LogEvent(ctx, serviceInstance.logger, logging.EventNotificationRejected, "send_rejected", "group_id", groupID, "tenant_id", runtimeCfg.Tenant.ID, "channel", channelIndex+1, "error", err)
+			return model.NotificationGroupResponse{}, fmt.Errorf("channel %d: %w", channelIndex+1, err)
+		}
+		pendingSends = append(pendingSends, pending)
+	}
+	notifications := make([]model.Notification, 0, len(pendingSends))
+	for channelIndex := range pendingSends {
+		if err := serviceInstance.dispatchAndStore(ctx, runtimeCfg, &pendingSends[channelIndex], currentTime, true); err != nil {
+			return model.NotificationGroupResponse{}, fmt.Errorf("channel %d: %w", channelIndex+1, err)
+		}
+		notifications = append(notifications, pendingSends[channelIndex].notification)
+	}
+	response := model.NewNotificationGroupResponse(groupID, notifications)
+	serviceInstance.logger.Info("notification_group_persisted", "group_id", groupID, "tenant_id", runtimeCfg.Tenant.ID, "channels", len(notifications), "status", response.Status)
+	return response, nil
+}
+
+// GetNotificationGroup returns the combined state of the tenant's notifications sharing groupID.
+func (serviceInstance *notificationServiceImpl) GetNotificationGroup(ctx context.Context, groupID string) (model.NotificationGroupResponse, error) {
+	runtimeCfg, err := serviceInstance.requireTenant(ctx)
+	if err != nil {
+		return model.NotificationGroupResponse{}, err
+	}
+	notifications, err := model.ListNotificationGroup(ctx, serviceInstance.database, runtimeCfg.Tenant.ID, groupID)
+	if err != nil {
+		serviceInstance.logger.Error("Failed to retrieve notification group", "group_id", groupID, "error", err)
+		return model.NotificationGroupResponse{}, err
+	}
+	return model.NewNotificationGroupResponse(groupID, notifications), nil
+}
+
+// requireSender reports why the tenant cannot dispatch notificationType, if it cannot.
+func (serviceInstance *notificationServiceImpl) requireSender(runtimeCfg tenant.RuntimeConfig, notificationType model.NotificationType) error {
+	if notificationType == model.NotificationSMS {
+		_, err := serviceInstance.smsSenderForTenant(runtimeCfg)
+		return err
+	}
+	_, err := serviceInstance.emailSenderForTenant(runtimeCfg)
+	return err
+}
+
+// newNotificationGroupID mints a group id from the random source.
+func (serviceInstance *notificationServiceImpl) newNotificationGroupID() (string, error) {
+	suffix, err := serviceInstance.randomSource().UniqueSuffix()
+	if err != nil {
+		return "", fmt.Errorf("notification group id: %w", err)
+	}
+	return "group-" + suffix, nil
+}
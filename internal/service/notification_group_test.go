@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tyemirov/pinguin/internal/config"
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+)
+
+func TestSendNotificationGroupDispatchesChannelsIndependently(t *testing.T) {
+	database := openIsolatedDatabase(t)
+	emailSender := &stubEmailSender{err: errors.New("smtp unavailable")}
+	smsSender := &stubSmsSender{providerID: "SM123"}
+	serviceInstance := NewNotificationServiceWithSenders(database, newDiscardLogger(), config.Config{MaxRetries: 3, RetryIntervalSec: 1}, nil, emailSender, smsSender)
+
+	groupRequest, err := model.NewNotificationGroupRequest([]model.NotificationRequest{
+		mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Outage", "Database down", nil, nil),
+		mustNotificationRequest(t, model.NotificationSMS, "+15555550100", "", "Database down", nil, nil),
+	})
+	if err != nil {
+		t.Fatalf("group request: %v", err)
+	}
+	response, err := serviceInstance.SendNotificationGroup(tenantContext(), groupRequest)
+	if err != nil {
+		t.Fatalf("send group: %v", err)
+	}
+	if response.GroupID == "" || len(response.Notifications) != 2 {
+		t.Fatalf("expected a group id and two channels, got %+v", response)
+	}
+	if response.Notifications[0].Status != model.StatusErrored || response.Notifications[1].Status != model.StatusSent {
+		t.Fatalf("expected email errored and SMS sent, got %s and %s", response.Notifications[0].Status, response.Notifications[1].Status)
+	}
+	if response.Status != model.StatusErrored {
+		t.Fatalf("expected the combined status errored, got %s", response.Status)
+	}
+	if emailSender.callCount != 1 || smsSender.callCount != 1 {
+		t.Fatalf("expected one dispatch per channel, got email=%d sms=%d", emailSender.callCount, smsSender.callCount)
+	}
+
+	stored, err := serviceInstance.GetNotificationGroup(tenantContext(), response.GroupID)
+	if err != nil {
+		t.Fatalf("get group: %v", err)
+	}
+	if len(stored.Notifications) != 2 || stored.Status != model.StatusErrored {
+		t.Fatalf("expected both stored channels with combined status errored, got %+v", stored)
+	}
+	for index, notification := range stored.Notifications {
+		if notification.GroupID != response.GroupID || notification.NotificationID != response.Notifications[index].NotificationID {
+			t.Fatalf("expected channel %d linked to the group in request order, got %+v", index+1, notification)
+		}
+	}
+}
+
+func TestSendNotificationGroupRejectsChannelsBeforeStoring(t *testing.T) {
+	database := openIsolatedDatabase(t)
+	emailSender := &stubEmailSender{}
+	serviceInstance := NewNotificationServiceWithSenders(database, newDiscardLogger(), config.Config{MaxRetries: 3, RetryIntervalSec: 1}, nil, emailSender, &stubSmsSender{})
+
+	groupRequest, err := model.NewNotificationGroupRequest([]model.NotificationRequest{
+		mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Outage", "Database down", nil, nil),
+		mustNotificationRequest(t, model.NotificationSMS, "+15555550100", "", strings.Repeat("x", 400), nil, nil),
+	})
+	if err != nil {
+		t.Fatalf("group request: %v", err)
+	}
+	runtimeCfg := baseRuntimeConfig()
+	runtimeCfg.Tenant.SMSMaxSegments = 1
+	_, err = serviceInstance.SendNotificationGroup(tenant.WithRuntime(context.Background(), runtimeCfg), groupRequest)
+	if !errors.Is(err, model.ErrNotificationSMSTooLong) || !strings.Contains(err.Error(), "channel 2") {
+		t.Fatalf("expected the oversized SMS channel named, got %v", err)
+	}
+	if emailSender.callCount != 0 {
+		t.Fatalf("expected no dispatch before every channel validated, got %d", emailSender.callCount)
+	}
+	var stored int64
+	if err := database.Model(&model.Notification{}).Count(&stored).Error; err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if stored != 0 {
+		t.Fatalf("expected nothing stored, got %d notifications", stored)
+	}
+}
+
+func TestGetNotificationGroupReportsUnknownGroup(t *testing.T) {
+	database := openIsolatedDatabase(t)
+	serviceInstance := NewNotificationServiceWithSenders(database, newDiscardLogger(), config.Config{MaxRetries: 3, RetryIntervalSec: 1}, nil, &stubEmailSender{}, &stubSmsSender{})
+
+	_, err := serviceInstance.GetNotificationGroup(tenantContext(), "group-missing")
+	if !errors.Is(err, model.ErrNotificationGroupNotFound) {
+		t.Fatalf("expected ErrNotificationGroupNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+)
+
+func prefixedTenantContext(tenantID string, prefix string) context.Context {
+	cfg := baseRuntimeConfig()
+	cfg.Tenant.ID = tenantID
+	cfg.Tenant.NotificationIDPrefix = prefix
+	return tenant.WithRuntime(context.Background(), cfg)
+}
+
+func TestSendNotificationUsesTenantNotificationIDPrefix(t *testing.T) {
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), &stubEmailSender{}, &stubSmsSender{})
+	scheduledFor := time.Now().UTC().Add(time.Hour)
+	testCases := []struct {
+		ctx            context.Context
+		expectedPrefix string
+	}{
+		{ctx: prefixedTenantContext("tenant-acme", "acme"), expectedPrefix: "acme-"},
+		{ctx: prefixedTenantContext("tenant-beta", "beta_eu"), expectedPrefix: "beta_eu-"},
+		{ctx: tenantContext(), expectedPrefix: tenant.DefaultNotificationIDPrefix + "-"},
+	}
+	seen := make(map[string]struct{})
+	for _, testCase := range testCases {
+		for attempt := 0; attempt < 2; attempt++ {
+			response, err := serviceInstance.SendNotification(testCase.ctx, mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", &scheduledFor, nil))
+			if err != nil {
+				t.Fatalf("send error: %v", err)
+			}
+			if !strings.HasPrefix(response.NotificationID, testCase.expectedPrefix) || len(response.NotificationID) == len(testCase.expectedPrefix) {
+				t.Fatalf("expected an id starting with %q, got %q", testCase.expectedPrefix, response.NotificationID)
+			}
+			if _, duplicate := seen[response.NotificationID]; duplicate {
+				t.Fatalf("expected unique notification ids, got %q twice", response.NotificationID)
+			}
+			seen[response.NotificationID] = struct{}{}
+			if _, err := serviceInstance.GetNotificationStatus(testCase.ctx, response.NotificationID); err != nil {
+				t.Fatalf("expected the owning tenant to find %q, got %v", response.NotificationID, err)
+			}
+		}
+	}
+}
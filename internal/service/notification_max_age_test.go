@@ -0,0 +1,146 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/config"
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/utils/scheduler"
+)
+
+func TestRetryWorkerEnforcesMaxRetryAge(t *testing.T) {
+	now := time.Date(2040, 6, 1, 12, 0, 0, 0, time.UTC)
+	createdAt := now.Add(-2 * time.Hour)
+	expiresAfterMaxAge := now.Add(-10 * time.Minute)
+	expiresBeforeMaxAge := now.Add(-90 * time.Minute)
+	testCases := []struct {
+		name             string
+		expiresAt        *time.Time
+		maxRetryAgeSec   int
+		expectedStatus   model.NotificationStatus
+		expectedReason   string
+		expectedLastErr  string
+		expectedAttempts int
+	}{
+		{
+			name:            "max age before expiry",
+			expiresAt:       &expiresAfterMaxAge,
+			maxRetryAgeSec:  3600,
+			expectedStatus:  model.StatusErrored,
+			expectedLastErr: model.LastErrorMaxAgeExceeded,
+		},
+		{
+			name:           "expiry before max age",
+			expiresAt:      &expiresBeforeMaxAge,
+			maxRetryAgeSec: 3600,
+			expectedStatus: model.StatusCancelled,
+			expectedReason: model.CancelReasonExpired,
+		},
+		{
+			name:            "max age without expiry",
+			maxRetryAgeSec:  3600,
+			expectedStatus:  model.StatusErrored,
+			expectedLastErr: model.LastErrorMaxAgeExceeded,
+		},
+		{
+			name:             "within max age",
+			maxRetryAgeSec:   3 * 3600,
+			expectedStatus:   model.StatusSent,
+			expectedAttempts: 1,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			database := openIsolatedDatabase(t)
+			emailSender := &stubEmailSender{}
+			clock := &adjustableClock{now: now}
+			configuration := config.Config{MaxRetries: 5, RetryIntervalSec: 1, MaxRetryAgeSec: testCase.maxRetryAgeSec}
+			serviceInstance := NewNotificationServiceWithSenders(database, newDiscardLogger(), configuration, nil, emailSender, &stubSmsSender{}, WithClock(clock)).(*notificationServiceImpl)
+			record := model.Notification{
+				TenantID:         testTenantID,
+				NotificationID:   "notif-aged",
+				NotificationType: model.NotificationEmail,
+				Recipient:        "user@example.com",
+				Subject:          "Subject",
+				Message:          "Body",
+				Status:           model.StatusErrored,
+				RetryCount:       1,
+				LastAttemptedAt:  now.Add(-time.Hour),
+				ExpiresAt:        testCase.expiresAt,
+				CreatedAt:        createdAt,
+				UpdatedAt:        createdAt,
+			}
+			if err := model.CreateNotification(tenantContext(), database, &record); err != nil {
+				t.Fatalf("create notification: %v", err)
+			}
+
+			store := newNotificationRetryStore(database, nil).withMaxRetryAge(time.Duration(testCase.maxRetryAgeSec) * time.Second)
+			worker := newRetryWorkerWithStoreForTest(t, serviceInstance, store, clock)
+			worker.RunOnce(tenantContext())
+			worker.RunOnce(tenantContext())
+
+			if emailSender.callCount != testCase.expectedAttempts {
+				t.Fatalf("expected %d dispatches, got %d", testCase.expectedAttempts, emailSender.callCount)
+			}
+			stored, err := model.GetNotificationByID(tenantContext(), database, testTenantID, "notif-aged")
+			if err != nil {
+				t.Fatalf("fetch notification: %v", err)
+			}
+			if stored.Status != testCase.expectedStatus || stored.CancelReason != testCase.expectedReason || stored.LastError != testCase.expectedLastErr {
+				t.Fatalf("expected %s/%q/%q, got %s/%q/%q", testCase.expectedStatus, testCase.expectedReason, testCase.expectedLastErr, stored.Status, stored.CancelReason, stored.LastError)
+			}
+			if testCase.expectedLastErr != "" && (stored.RetryCount != 1 || stored.NextAttemptAt != nil) {
+				t.Fatalf("expected the aged notification to leave the queue with retries unused, got retry_count=%d next_attempt_at=%v", stored.RetryCount, stored.NextAttemptAt)
+			}
+		})
+	}
+}
+
+func TestRetryDispatcherFailsClaimedJobPastMaxRetryAge(t *testing.T) {
+	now := time.Date(2040, 6, 1, 12, 0, 0, 0, time.UTC)
+	database := openIsolatedDatabase(t)
+	emailSender := &stubEmailSender{}
+	configuration := config.Config{MaxRetries: 5, RetryIntervalSec: 1, MaxRetryAgeSec: 60}
+	serviceInstance := NewNotificationServiceWithSenders(database, newDiscardLogger(), configuration, nil, emailSender, &stubSmsSender{}, WithClock(&adjustableClock{now: now})).(*notificationServiceImpl)
+	scheduledFor := now.Add(-2 * time.Minute)
+	record := &model.Notification{
+		TenantID:         testTenantID,
+		NotificationID:   "notif-claimed",
+		NotificationType: model.NotificationEmail,
+		Recipient:        "user@example.com",
+		Message:          "Body",
+		Status:           model.StatusQueued,
+		ScheduledFor:     &scheduledFor,
+		CreatedAt:        now.Add(-time.Hour),
+	}
+
+	result, err := newNotificationDispatcher(serviceInstance).Attempt(tenantContext(), scheduler.Job{ID: record.NotificationID, Payload: record})
+	if err != nil {
+		t.Fatalf("attempt: %v", err)
+	}
+	if result.Status != string(model.StatusErrored) || record.LastError != model.LastErrorMaxAgeExceeded || emailSender.callCount != 0 {
+		t.Fatalf("expected an undispatched max-age failure, got %+v with %+v", result, record)
+	}
+	if webhookEventForTransition(record, model.StatusQueued, 5) != WebhookEventDeadLettered {
+		t.Fatalf("expected a max-age failure to be reported as dead-lettered")
+	}
+}
+
+func newRetryWorkerWithStoreForTest(t *testing.T, serviceInstance *notificationServiceImpl, store *notificationRetryStore, clock scheduler.Clock) *scheduler.Worker {
+	t.Helper()
+	worker, err := scheduler.NewWorker(scheduler.Config{
+		Repository:    store,
+		Dispatcher:    newNotificationDispatcher(serviceInstance),
+		Logger:        serviceInstance.logger,
+		Interval:      time.Duration(serviceInstance.retryIntervalSec) * time.Second,
+		MaxRetries:    serviceInstance.maxRetries,
+		SuccessStatus: string(model.StatusSent),
+		FailureStatus: string(model.StatusErrored),
+		Clock:         clock,
+	})
+	if err != nil {
+		t.Fatalf("worker init error: %v", err)
+	}
+	return worker
+}
@@ -4,20 +4,36 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"sort"
 	"time"
 
 	"github.com/tyemirov/pinguin/internal/model"
 	"github.com/tyemirov/pinguin/internal/tenant"
+	"github.com/tyemirov/pinguin/pkg/logging"
 	"github.com/tyemirov/utils/scheduler"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
+// notificationRetryStore is used by a single scheduler goroutine, so its cursors need
+// no locking.
 type notificationRetryStore struct {
-	database   *gorm.DB
-	tenantRepo *tenant.Repository
+	database      *gorm.DB
+	tenantRepo    *tenant.Repository
+	pacer         *dispatchPacer
+	breakers      *providerBreakers
+	tenantCap     int
+	maxRetryAge   time.Duration
+	retryInterval time.Duration
+	tenantCursors map[string]uint
+	onStateChange stateChangeHook
+	claims        *retryClaims
 }
 
+// stateChangeHook observes a notification after an attempt's outcome is saved.
+type stateChangeHook func(ctx context.Context, record *model.Notification, previous model.NotificationStatus)
+
 const (
 	pendingJobsNotificationsTable = "notifications"
 	pendingJobsTenantsTable       = "tenants"
@@ -27,10 +43,59 @@ const (
 	pendingJobsStatusColumn       = "status"
 	pendingJobsRetryCountColumn   = "retry_count"
 	pendingJobsScheduledForColumn = "scheduled_for"
+	pendingJobsNextAttemptColumn  = "next_attempt_at"
+	pendingJobsLastErrorColumn    = "last_error"
+	pendingJobsRowIDColumn        = "id"
+	// maxRetryBackoffShift matches the scheduler's cap on exponential backoff doublings.
+	maxRetryBackoffShift = 20
+	// retryCheckpointMaxAge discards checkpoints from a worker that has been down long
+	// enough that its cursors no longer describe the backlog.
+	retryCheckpointMaxAge = 24 * time.Hour
 )
 
 func newNotificationRetryStore(database *gorm.DB, tenantRepo *tenant.Repository) *notificationRetryStore {
-	return &notificationRetryStore{database: database, tenantRepo: tenantRepo}
+	return &notificationRetryStore{
+		database:      database,
+		tenantRepo:    tenantRepo,
+		tenantCursors: make(map[string]uint),
+		claims:        newRetryClaims(defaultRetryClaimLease),
+	}
+}
+
+// withPacer limits each paced tenant+provider to the jobs its current delay admits.
+func (store *notificationRetryStore) withPacer(pacer *dispatchPacer) *notificationRetryStore {
+	store.pacer = pacer
+	return store
+}
+
+// withBreakers skips jobs for tenant channels paused by a provider breaker, except the
+// breaker's periodic canary.
+func (store *notificationRetryStore) withBreakers(breakers *providerBreakers) *notificationRetryStore {
+	store.breakers = breakers
+	return store
+}
+
+// withTenantCap bounds how many jobs one tenant contributes to a cycle so a large backlog
+// cannot hold the worker while other tenants wait. A tenant's MaxConcurrentRetries
+// overrides defaultCap; zero on both means no cap.
+func (store *notificationRetryStore) withTenantCap(defaultCap int) *notificationRetryStore {
+	store.tenantCap = defaultCap
+	return store
+}
+
+// withMaxRetryAge fails jobs for good once they stayed undelivered longer than their
+// tenant's MaxRetryAgeSec, or defaultAge when the tenant has none; zero on both means
+// no limit.
+func (store *notificationRetryStore) withMaxRetryAge(defaultAge time.Duration) *notificationRetryStore {
+	store.maxRetryAge = defaultAge
+	return store
+}
+
+// withBackoff persists each failed attempt's next_attempt_at so pending-job queries skip
+// notifications still inside their backoff window, including after a restart.
+func (store *notificationRetryStore) withBackoff(interval time.Duration) *notificationRetryStore {
+	store.retryInterval = interval
+	return store
 }
 
 func (store *notificationRetryStore) PendingJobs(ctx context.Context, maxRetries int, now time.Time) ([]scheduler.Job, error) {
@@ -42,7 +107,7 @@ func (store *notificationRetryStore) PendingJobs(ctx context.Context, maxRetries
 
 func (store *notificationRetryStore) pendingJobsForActiveTenants(ctx context.Context, maxRetries int, now time.Time) ([]scheduler.Job, error) {
 	var notifications []model.Notification
-	err := store.database.WithContext(ctx).
+	err := store.database.WithContext(model.WithQueryClass(ctx, model.QueryClassAggregate)).
 		Preload("Attachments").
 		Clauses(activeTenantJoinClause()).
 		Where(clause.Eq{
@@ -50,30 +115,58 @@ func (store *notificationRetryStore) pendingJobsForActiveTenants(ctx context.Con
 			Value:  tenant.TenantStatusActive,
 		}).
 		Where(pendingJobsFilter(maxRetries, now)).
+		Order(pendingJobsOrder()).
 		Find(&notifications).Error
 	if err != nil {
 		return nil, err
 	}
-	return store.jobsFromNotifications(notifications), nil
+	return store.jobsFromNotifications(ctx, notifications, now), nil
 }
 
 func (store *notificationRetryStore) pendingJobsAll(ctx context.Context, maxRetries int, now time.Time) ([]scheduler.Job, error) {
 	var notifications []model.Notification
-	err := store.database.WithContext(ctx).
+	err := store.database.WithContext(model.WithQueryClass(ctx, model.QueryClassAggregate)).
 		Preload("Attachments").
 		Where(pendingJobsFilter(maxRetries, now)).
+		Order(pendingJobsOrder()).
 		Find(&notifications).Error
 	if err != nil {
 		return nil, err
 	}
-	return store.jobsFromNotifications(notifications), nil
+	return store.jobsFromNotifications(ctx, notifications, now), nil
 }
 
-func (store *notificationRetryStore) jobsFromNotifications(records []model.Notification) []scheduler.Job {
-	jobs := make([]scheduler.Job, 0, len(records))
+// jobsFromNotifications applies the pacer, provider breakers and tenant caps, then interleaves tenants so
+// every tenant with pending work gets a job near the front of the cycle.
+func (store *notificationRetryStore) jobsFromNotifications(ctx context.Context, records []model.Notification, now time.Time) []scheduler.Job {
+	tenantCaps := make(map[string]int)
+	tenantMaxAges := make(map[string]time.Duration)
+	tenantJobs := make(map[string][]scheduler.Job)
+	var tenantOrder []string
+	records = store.rotatePastCursors(records)
 	for index := range records {
 		record := records[index]
-		jobs = append(jobs, scheduler.Job{
+		tenantCap, known := tenantCaps[record.TenantID]
+		if !known {
+			tenantCap = store.capForTenant(ctx, record.TenantID)
+			tenantCaps[record.TenantID] = tenantCap
+			tenantMaxAges[record.TenantID] = store.maxRetryAgeForTenant(ctx, record.TenantID)
+			tenantOrder = append(tenantOrder, record.TenantID)
+		}
+		if record.ExceedsMaxRetryAge(tenantMaxAges[record.TenantID], now) {
+			store.abandonAgedRecord(ctx, &records[index], now)
+			continue
+		}
+		if tenantCap > 0 && len(tenantJobs[record.TenantID]) >= tenantCap {
+			continue
+		}
+		if !store.pacer.admit(record.TenantID, record.NotificationType, now) {
+			continue
+		}
+		if !store.breakers.admit(ctx, record.TenantID, record.NotificationType, now) {
+			continue
+		}
+		tenantJobs[record.TenantID] = append(tenantJobs[record.TenantID], scheduler.Job{
 			ID:              record.NotificationID,
 			ScheduledFor:    record.ScheduledFor,
 			RetryCount:      record.RetryCount,
@@ -81,7 +174,77 @@ func (store *notificationRetryStore) jobsFromNotifications(records []model.Notif
 			Payload:         &records[index],
 		})
 	}
-	return jobs
+	return interleaveTenantJobs(tenantOrder, tenantJobs)
+}
+
+// capForTenant resolves the tenant's own cap, falling back to the server default when
+// the tenant has none or its runtime cannot be loaded.
+func (store *notificationRetryStore) capForTenant(ctx context.Context, tenantID string) int {
+	if store.tenantRepo == nil {
+		return store.tenantCap
+	}
+	runtimeCfg, err := store.tenantRepo.ResolveByID(ctx, tenantID)
+	if err != nil || runtimeCfg.Tenant.MaxConcurrentRetries <= 0 {
+		return store.tenantCap
+	}
+	return runtimeCfg.Tenant.MaxConcurrentRetries
+}
+
+// maxRetryAgeForTenant resolves the tenant's own max retry age, falling back to the
+// server default when the tenant has none or its runtime cannot be loaded.
+func (store *notificationRetryStore) maxRetryAgeForTenant(ctx context.Context, tenantID string) time.Duration {
+	if store.tenantRepo == nil {
+		return store.maxRetryAge
+	}
+	runtimeCfg, err := store.tenantRepo.ResolveByID(ctx, tenantID)
+	if err != nil || runtimeCfg.Tenant.MaxRetryAgeSec <= 0 {
+		return store.maxRetryAge
+	}
+	return time.Duration(runtimeCfg.Tenant.MaxRetryAgeSec) * time.Second
+}
+
+// abandonAgedRecord fails a notification past its max retry age without attempting it.
+// A failed save leaves it pending, so the next cycle tries again.
+func (store *notificationRetryStore) abandonAgedRecord(ctx context.Context, record *model.Notification, now time.Time) {
+	previousStatus := record.Status
+	record.MarkMaxRetryAgeExceeded(now)
+	if err := model.SaveNotification(ctx, store.database, record); err != nil {
+		return
+	}
+	if store.onStateChange != nil {
+		store.onStateChange(ctx, record, previousStatus)
+	}
+}
+
+// rotatePastCursors moves each tenant's notifications after its cursor ahead of those at
+// or before it, so a capped tenant continues through its backlog instead of restarting
+// from its oldest notification every cycle. Records must be ordered by row id.
+func (store *notificationRetryStore) rotatePastCursors(records []model.Notification) []model.Notification {
+	if len(store.tenantCursors) == 0 {
+		return records
+	}
+	rotated := append([]model.Notification(nil), records...)
+	sort.SliceStable(rotated, func(left, right int) bool {
+		return rotated[left].ID > store.tenantCursors[rotated[left].TenantID] &&
+			rotated[right].ID <= store.tenantCursors[rotated[right].TenantID]
+	})
+	return rotated
+}
+
+func interleaveTenantJobs(tenantOrder []string, tenantJobs map[string][]scheduler.Job) []scheduler.Job {
+	total := 0
+	for _, jobs := range tenantJobs {
+		total += len(jobs)
+	}
+	interleaved := make([]scheduler.Job, 0, total)
+	for round := 0; len(interleaved) < total; round++ {
+		for _, tenantID := range tenantOrder {
+			if jobs := tenantJobs[tenantID]; round < len(jobs) {
+				interleaved = append(interleaved, jobs[round])
+			}
+		}
+	}
+	return interleaved
 }
 
 func activeTenantJoinClause() clause.From {
@@ -112,10 +275,79 @@ func pendingJobsFilter(maxRetries int, currentTime time.Time) clause.Expression
 			clause.Eq{Column: clause.Column{Table: pendingJobsNotificationsTable, Name: pendingJobsScheduledForColumn}, Value: nil},
 			clause.Lte{Column: clause.Column{Table: pendingJobsNotificationsTable, Name: pendingJobsScheduledForColumn}, Value: currentTime},
 		),
+		clause.Or(
+			clause.Eq{Column: clause.Column{Table: pendingJobsNotificationsTable, Name: pendingJobsNextAttemptColumn}, Value: nil},
+			clause.Lte{Column: clause.Column{Table: pendingJobsNotificationsTable, Name: pendingJobsNextAttemptColumn}, Value: currentTime},
+		),
+		retriesNotAbandonedFilter(),
+	)
+}
+
+// retriesNotAbandonedFilter excludes notifications the worker gave up on before
+// maxRetries, such as those past their max retry age.
+func retriesNotAbandonedFilter() clause.Expression {
+	return clause.Or(
+		clause.Eq{Column: clause.Column{Table: pendingJobsNotificationsTable, Name: pendingJobsLastErrorColumn}, Value: nil},
+		clause.Eq{Column: clause.Column{Table: pendingJobsNotificationsTable, Name: pendingJobsLastErrorColumn}, Value: ""},
 	)
 }
 
+// withStateChangeHook reports every saved attempt outcome with the status it replaced.
+func (store *notificationRetryStore) withStateChangeHook(hook stateChangeHook) *notificationRetryStore {
+	store.onStateChange = hook
+	return store
+}
+
+func pendingJobsOrder() clause.OrderByColumn {
+	return clause.OrderByColumn{Column: clause.Column{Table: pendingJobsNotificationsTable, Name: pendingJobsRowIDColumn}}
+}
+
+// Claim is the RetryQueue view of PendingJobs. Only jobs the scheduler's backoff admits
+// at now are leased; the rest stay unclaimed for a later cycle.
+func (store *notificationRetryStore) Claim(ctx context.Context, maxRetries int, now time.Time) ([]scheduler.Job, error) {
+	jobs, err := store.PendingJobs(ctx, maxRetries, now)
+	if err != nil {
+		return nil, err
+	}
+	store.claims.expire(now)
+	claimed := make([]scheduler.Job, 0, len(jobs))
+	for _, job := range jobs {
+		if store.retryInterval > 0 && job.RetryCount > 0 && !job.LastAttemptedAt.IsZero() &&
+			now.Before(retryBackoffAt(job.LastAttemptedAt, job.RetryCount, store.retryInterval)) {
+			continue
+		}
+		if store.claims.claim(job.ID, now) {
+			claimed = append(claimed, job)
+		}
+	}
+	return claimed, nil
+}
+
+// Ack saves a final attempt outcome and releases the claim.
+func (store *notificationRetryStore) Ack(ctx context.Context, job scheduler.Job, update scheduler.AttemptUpdate) error {
+	if err := store.saveAttempt(ctx, job, update, nil); err != nil {
+		return err
+	}
+	store.claims.release(job.ID)
+	return nil
+}
+
+// Nack saves a failed attempt with next_attempt_at set to retryAt and releases the claim.
+func (store *notificationRetryStore) Nack(ctx context.Context, job scheduler.Job, update scheduler.AttemptUpdate, retryAt time.Time) error {
+	if err := store.saveAttempt(ctx, job, update, &retryAt); err != nil {
+		return err
+	}
+	store.claims.release(job.ID)
+	return nil
+}
+
 func (store *notificationRetryStore) ApplyAttemptResult(ctx context.Context, job scheduler.Job, update scheduler.AttemptUpdate) error {
+	return store.saveAttempt(ctx, job, update, nil)
+}
+
+// saveAttempt persists an attempt outcome; a nil retryAt derives next_attempt_at from
+// the configured backoff.
+func (store *notificationRetryStore) saveAttempt(ctx context.Context, job scheduler.Job, update scheduler.AttemptUpdate, retryAt *time.Time) error {
 	record, err := store.notificationFromJob(job)
 	if err != nil {
 		return err
@@ -124,12 +356,70 @@ func (store *notificationRetryStore) ApplyAttemptResult(ctx context.Context, job
 	if canonicalStatus == "" {
 		canonicalStatus = model.StatusErrored
 	}
+	previousStatus := record.Status
 	record.Status = canonicalStatus
 	record.ProviderMessageID = update.ProviderMessageID
 	record.RetryCount = update.RetryCount
 	record.LastAttemptedAt = update.LastAttemptedAt
 	record.UpdatedAt = update.LastAttemptedAt
-	return model.SaveNotification(ctx, store.database, record)
+	record.NextAttemptAt = store.nextAttemptAt(record)
+	if retryAt != nil && !record.RetriesAbandoned() && (record.Status == model.StatusQueued || record.Status == model.StatusErrored) {
+		nextAttempt := retryAt.UTC()
+		record.NextAttemptAt = &nextAttempt
+	}
+	if err := model.SaveNotification(ctx, store.database, record); err != nil {
+		return err
+	}
+	store.tenantCursors[record.TenantID] = record.ID
+	if store.onStateChange != nil {
+		store.onStateChange(ctx, record, previousStatus)
+	}
+	return nil
+}
+
+// nextAttemptAt mirrors the scheduler's exponential backoff so the stored time is when
+// the worker would next retry; it is nil once the notification leaves the retry queue.
+func (store *notificationRetryStore) nextAttemptAt(record *model.Notification) *time.Time {
+	if store.retryInterval <= 0 || record.RetriesAbandoned() || (record.Status != model.StatusQueued && record.Status != model.StatusErrored) {
+		return nil
+	}
+	nextAttempt := retryBackoffAt(record.LastAttemptedAt, record.RetryCount, store.retryInterval)
+	return &nextAttempt
+}
+
+// resumeFromCheckpoint restores the tenant cursors of the last completed tick unless the
+// checkpoint is older than retryCheckpointMaxAge.
+func (store *notificationRetryStore) resumeFromCheckpoint(ctx context.Context, logger *slog.Logger, now time.Time) {
+	checkpoint, found, err := model.LoadWorkerCheckpoint(ctx, store.database, model.RetryWorkerCheckpointName)
+	if err != nil {
+		logger.Error("Failed to load retry worker checkpoint", "error", err)
+		return
+	}
+	if !found {
+		return
+	}
+	if age := now.Sub(checkpoint.LastTickAt); age > retryCheckpointMaxAge {
+		logger.Info("Discarding stale retry worker checkpoint", "last_tick_at", checkpoint.LastTickAt, "age", age.String())
+		return
+	}
+	for tenantID, cursor := range checkpoint.TenantCursors {
+		store.tenantCursors[tenantID] = cursor
+	}
+	logger.Info("Resuming retry worker from checkpoint", "last_tick_at", checkpoint.LastTickAt, "tenant_cursors", len(checkpoint.TenantCursors))
+}
+
+// saveCheckpoint records a completed tick and the current tenant cursors.
+func (store *notificationRetryStore) saveCheckpoint(ctx context.Context, tickAt time.Time) error {
+	cursors := make(map[string]uint, len(store.tenantCursors))
+	for tenantID, cursor := range store.tenantCursors {
+		cursors[tenantID] = cursor
+	}
+	return model.SaveWorkerCheckpoint(ctx, store.database, model.WorkerCheckpoint{
+		WorkerName:    model.RetryWorkerCheckpointName,
+		LastTickAt:    tickAt.UTC(),
+		TenantCursors: cursors,
+		UpdatedAt:     tickAt.UTC(),
+	})
 }
 
 func (store *notificationRetryStore) notificationFromJob(job scheduler.Job) (*model.Notification, error) {
@@ -151,16 +441,31 @@ func newNotificationDispatcher(serviceInstance *notificationServiceImpl) *notifi
 	return &notificationDispatcher{serviceInstance: serviceInstance}
 }
 
-func (dispatcher *notificationDispatcher) Attempt(ctx context.Context, job scheduler.Job) (scheduler.DispatchResult, error) {
+func (dispatcher *notificationDispatcher) Attempt(ctx context.Context, job scheduler.Job) (result scheduler.DispatchResult, err error) {
 	notificationRecord, err := dispatcher.recordFromJob(job)
 	if err != nil {
 		return scheduler.DispatchResult{}, err
 	}
+	currentTime := dispatcher.serviceInstance.currentTime()
+	if notificationRecord.ExceedsMaxRetryAge(dispatcher.serviceInstance.maxRetryAge(ctx, notificationRecord.TenantID), currentTime) {
+		dispatcher.serviceInstance.logger.Info("Abandoning retry: max age exceeded", "notification_id", notificationRecord.NotificationID, "tenant_id", notificationRecord.TenantID)
+		notificationRecord.MarkMaxRetryAgeExceeded(currentTime)
+		return scheduler.DispatchResult{Status: string(model.StatusErrored)}, nil
+	}
+	if notificationRecord.IsExpired(currentTime) {
+		dispatcher.serviceInstance.logger.Info("Skipping expired notification", "notification_id", notificationRecord.NotificationID, "tenant_id", notificationRecord.TenantID)
+		notificationRecord.MarkExpired(currentTime)
+		return scheduler.DispatchResult{Status: string(model.StatusCancelled)}, nil
+	}
 	runtimeCfg, runtimeErr := dispatcher.serviceInstance.runtimeForTenantID(ctx, notificationRecord.TenantID)
 	if runtimeErr != nil {
-		dispatcher.serviceInstance.logger.Error("Failed to resolve tenant runtime for retry", "tenant_id", notificationRecord.TenantID, "error", runtimeErr)
+		logging.LogEvent(ctx, dispatcher.serviceInstance.logger, logging.EventTenantResolutionFailed, "Failed to resolve tenant runtime for retry", "notification_id", notificationRecord.NotificationID, "tenant_id", notificationRecord.TenantID, "error", runtimeErr)
 		return scheduler.DispatchResult{Status: string(model.StatusErrored)}, runtimeErr
 	}
+	dispatchCtx, finishDispatch := dispatcher.serviceInstance.inFlight.begin(ctx, notificationRecord.TenantID, notificationRecord.NotificationID)
+	defer func() {
+		finishDispatch(attemptOutcome(*notificationRecord, result, err))
+	}()
 
 	switch notificationRecord.NotificationType {
 	case model.NotificationEmail:
@@ -168,11 +473,21 @@ func (dispatcher *notificationDispatcher) Attempt(ctx context.Context, job sched
 		if senderErr != nil {
 			return scheduler.DispatchResult{Status: string(model.StatusErrored)}, senderErr
 		}
-		emailAttachments := model.ToEmailAttachments(notificationRecord.Attachments)
-		sendErr := emailSender.SendEmail(ctx, notificationRecord.Recipient, notificationRecord.Subject, notificationRecord.Message, emailAttachments)
+		if notificationRecord.HasDiscardedAttachmentData() {
+			dispatcher.serviceInstance.logger.Error("Abandoning retry: attachment data was not persisted", "notification_id", notificationRecord.NotificationID, "tenant_id", notificationRecord.TenantID)
+			notificationRecord.MarkAttachmentDataUnavailable(dispatcher.serviceInstance.currentTime())
+			return scheduler.DispatchResult{Status: string(model.StatusCancelled)}, nil
+		}
+		emailAttachments := model.SharedEmailAttachments(notificationRecord.Attachments)
+		emailBody := dispatcher.serviceInstance.trackedEmailBody(runtimeCfg, notificationRecord)
+		sendErr := dispatcher.serviceInstance.callProvider(dispatchCtx, notificationRecord.TenantID, notificationRecord.NotificationID, model.NotificationEmail, notificationRecord.Recipient, func() error {
+			return deliverEmail(dispatchCtx, emailSender, notificationRecord, emailBody, emailAttachments)
+		})
 		if sendErr != nil {
-			return scheduler.DispatchResult{}, sendErr
+			return dispatcher.failedSend(dispatchCtx, notificationRecord, sendErr)
 		}
+		applyDispatchCost(runtimeCfg.Tenant, notificationRecord, "")
+		dispatcher.serviceInstance.recordRenderedContent(ctx, runtimeCfg, notificationRecord, notificationRecord.Subject, emailBody, emailAttachments)
 		return scheduler.DispatchResult{Status: string(model.StatusSent)}, nil
 	case model.NotificationSMS:
 		smsSender, senderErr := dispatcher.serviceInstance.smsSenderForTenant(runtimeCfg)
@@ -180,10 +495,17 @@ func (dispatcher *notificationDispatcher) Attempt(ctx context.Context, job sched
 			dispatcher.serviceInstance.logger.Warn("Skipping SMS retry because delivery is disabled", "notification_id", notificationRecord.NotificationID)
 			return scheduler.DispatchResult{Status: string(model.StatusErrored)}, senderErr
 		}
-		providerMessageID, sendErr := smsSender.SendSms(ctx, notificationRecord.Recipient, notificationRecord.Message)
+		var providerMessageID string
+		sendErr := dispatcher.serviceInstance.callProvider(dispatchCtx, notificationRecord.TenantID, notificationRecord.NotificationID, model.NotificationSMS, notificationRecord.Recipient, func() error {
+			var smsErr error
+			providerMessageID, smsErr = smsSender.SendSms(dispatchCtx, notificationRecord.Recipient, notificationRecord.Message)
+			return smsErr
+		})
 		if sendErr != nil {
-			return scheduler.DispatchResult{}, sendErr
+			return dispatcher.failedSend(dispatchCtx, notificationRecord, sendErr)
 		}
+		applyDispatchCost(runtimeCfg.Tenant, notificationRecord, providerMessageID)
+		dispatcher.serviceInstance.recordRenderedContent(ctx, runtimeCfg, notificationRecord, notificationRecord.Subject, notificationRecord.Message, nil)
 		return scheduler.DispatchResult{
 			Status:            string(model.StatusSent),
 			ProviderMessageID: providerMessageID,
@@ -194,6 +516,29 @@ func (dispatcher *notificationDispatcher) Attempt(ctx context.Context, job sched
 	}
 }
 
+// failedSend reports a provider call that did not deliver. A send aborted by
+// CancelNotification cancels the notification instead of scheduling another attempt.
+func (dispatcher *notificationDispatcher) failedSend(dispatchCtx context.Context, notificationRecord *model.Notification, sendErr error) (scheduler.DispatchResult, error) {
+	if !dispatchCancelled(dispatchCtx) {
+		return scheduler.DispatchResult{}, sendErr
+	}
+	dispatcher.serviceInstance.logger.Info("Retry dispatch cancelled in flight", "notification_id", notificationRecord.NotificationID, "tenant_id", notificationRecord.TenantID)
+	markDispatchCancelled(dispatchCtx, notificationRecord, dispatcher.serviceInstance.currentTime())
+	return scheduler.DispatchResult{Status: string(model.StatusCancelled)}, nil
+}
+
+// attemptOutcome is the notification as the retry worker saves it after an attempt.
+func attemptOutcome(record model.Notification, result scheduler.DispatchResult, attemptErr error) model.Notification {
+	record.Status = model.CanonicalStatus(model.NotificationStatus(result.Status))
+	if attemptErr != nil || record.Status == "" {
+		record.Status = model.StatusErrored
+	}
+	if result.ProviderMessageID != "" {
+		record.ProviderMessageID = result.ProviderMessageID
+	}
+	return record
+}
+
 func (dispatcher *notificationDispatcher) recordFromJob(job scheduler.Job) (*model.Notification, error) {
 	notificationRecord, ok := job.Payload.(*model.Notification)
 	if !ok || notificationRecord == nil {
@@ -6,12 +6,14 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/tyemirov/pinguin/internal/model"
 	"github.com/tyemirov/pinguin/internal/tenant"
 	"github.com/tyemirov/utils/scheduler"
+	"gorm.io/gorm"
 )
 
 type testEmailSender struct {
@@ -379,3 +381,238 @@ func TestNotificationDispatcherReportsPayloadRuntimeAndSendFailures(t *testing.T
 		t.Fatalf("expected unsupported notification error, got result=%+v err=%v", unsupportedResult, unsupportedErr)
 	}
 }
+
+func seedQueuedNotifications(t *testing.T, database *gorm.DB, tenantID string, count int, now time.Time) {
+	t.Helper()
+	for index := 0; index < count; index++ {
+		record := model.Notification{
+			TenantID:         tenantID,
+			NotificationID:   fmt.Sprintf("%s-%d", tenantID, index),
+			NotificationType: model.NotificationEmail,
+			Recipient:        "user@example.com",
+			Message:          "Body",
+			Status:           model.StatusQueued,
+			CreatedAt:        now,
+			UpdatedAt:        now,
+		}
+		if err := model.CreateNotification(context.Background(), database, &record); err != nil {
+			t.Fatalf("seed notification: %v", err)
+		}
+	}
+}
+
+func TestRetryStoreCapsAndInterleavesTenantJobs(t *testing.T) {
+	database := openIsolatedDatabase(t)
+	now := time.Now().UTC()
+	seedQueuedNotifications(t, database, "tenant-backlog", 10, now)
+	seedQueuedNotifications(t, database, "tenant-quiet", 1, now)
+	store := newNotificationRetryStore(database, nil).withTenantCap(2)
+
+	jobs, err := store.PendingJobs(context.Background(), 5, now)
+	if err != nil {
+		t.Fatalf("pending jobs: %v", err)
+	}
+	var tenantOrder []string
+	for _, job := range jobs {
+		tenantOrder = append(tenantOrder, job.Payload.(*model.Notification).TenantID)
+	}
+	expected := []string{"tenant-backlog", "tenant-quiet", "tenant-backlog"}
+	if fmt.Sprint(tenantOrder) != fmt.Sprint(expected) {
+		t.Fatalf("expected capped, interleaved jobs %v, got %v", expected, tenantOrder)
+	}
+}
+
+func TestRetryWorkerBacklogDoesNotBlockOtherTenants(t *testing.T) {
+	database := openIsolatedDatabase(t)
+	emailSender := &stubEmailSender{}
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(database, emailSender, &stubSmsSender{})
+	now := time.Now().UTC()
+	seedQueuedNotifications(t, database, "tenant-backlog", 50, now)
+	seedQueuedNotifications(t, database, "tenant-quiet", 1, now)
+	worker, err := scheduler.NewWorker(scheduler.Config{
+		Repository:    newNotificationRetryStore(database, nil).withTenantCap(5),
+		Dispatcher:    newNotificationDispatcher(serviceInstance),
+		Logger:        serviceInstance.logger,
+		Interval:      time.Second,
+		MaxRetries:    serviceInstance.maxRetries,
+		SuccessStatus: string(model.StatusSent),
+		FailureStatus: string(model.StatusErrored),
+		Clock:         &adjustableClock{now: now},
+	})
+	if err != nil {
+		t.Fatalf("worker init error: %v", err)
+	}
+
+	worker.RunOnce(context.Background())
+	if emailSender.callCount != 6 {
+		t.Fatalf("expected five backlog jobs and the quiet tenant's job, got %d sends", emailSender.callCount)
+	}
+	quiet, fetchErr := model.GetNotificationByID(context.Background(), database, "tenant-quiet", "tenant-quiet-0")
+	if fetchErr != nil {
+		t.Fatalf("fetch notification: %v", fetchErr)
+	}
+	if quiet.Status != model.StatusSent {
+		t.Fatalf("expected the quiet tenant's job to be sent in the first cycle, got %s", quiet.Status)
+	}
+}
+
+func TestRetryStoreUsesTenantRetryCapOverride(t *testing.T) {
+	database := openIsolatedDatabase(t)
+	if err := database.AutoMigrate(&tenant.Tenant{}, &tenant.TenantDomain{}, &tenant.TenantAdmin{}, &tenant.EmailProfile{}, &tenant.SMSProfile{}); err != nil {
+		t.Fatalf("tenant migration: %v", err)
+	}
+	keeper, err := tenant.NewSecretKeeper(strings.Repeat("a", 64))
+	if err != nil {
+		t.Fatalf("secret keeper: %v", err)
+	}
+	bootstrapTenant := func(tenantID string, maxConcurrentRetries int) tenant.BootstrapTenant {
+		return tenant.BootstrapTenant{
+			ID:          tenantID,
+			DisplayName: tenantID,
+			Enabled:     ptrBool(true),
+			Domains:     []string{tenantID + ".example"},
+			EmailProfile: tenant.BootstrapEmailProfile{
+				Host:        "smtp.example",
+				Port:        587,
+				Username:    "smtp-user",
+				Password:    "smtp-pass",
+				FromAddress: "noreply@" + tenantID + ".example",
+			},
+			MaxConcurrentRetries: maxConcurrentRetries,
+		}
+	}
+	if err := tenant.Bootstrap(context.Background(), database, keeper, tenant.BootstrapConfig{
+		Tenants: []tenant.BootstrapTenant{bootstrapTenant("tenant-bulk", 3), bootstrapTenant("tenant-default", 0)},
+	}); err != nil {
+		t.Fatalf("bootstrap: %v", err)
+	}
+	now := time.Now().UTC()
+	seedQueuedNotifications(t, database, "tenant-bulk", 5, now)
+	seedQueuedNotifications(t, database, "tenant-default", 5, now)
+	store := newNotificationRetryStore(database, tenant.NewRepository(database, keeper)).withTenantCap(1)
+
+	jobs, err := store.PendingJobs(context.Background(), 5, now)
+	if err != nil {
+		t.Fatalf("pending jobs: %v", err)
+	}
+	perTenant := map[string]int{}
+	for _, job := range jobs {
+		perTenant[job.Payload.(*model.Notification).TenantID]++
+	}
+	if perTenant["tenant-bulk"] != 3 || perTenant["tenant-default"] != 1 {
+		t.Fatalf("expected the tenant override and the server default, got %v", perTenant)
+	}
+}
+
+// newCheckpointedRetryWorkerForTest builds the worker the way StartRetryWorker does,
+// resuming from any stored checkpoint, to stand in for one server process.
+func newCheckpointedRetryWorkerForTest(t *testing.T, serviceInstance *notificationServiceImpl, clock *adjustableClock) (*scheduler.Worker, *notificationRetryStore) {
+	t.Helper()
+	interval := time.Duration(serviceInstance.retryIntervalSec) * time.Second
+	store := newNotificationRetryStore(serviceInstance.database, nil).withBackoff(interval)
+	store.resumeFromCheckpoint(context.Background(), serviceInstance.logger, clock.now)
+	worker, err := scheduler.NewWorker(scheduler.Config{
+		Repository:    store,
+		Dispatcher:    newNotificationDispatcher(serviceInstance),
+		Logger:        serviceInstance.logger,
+		Interval:      interval,
+		MaxRetries:    serviceInstance.maxRetries,
+		SuccessStatus: string(model.StatusSent),
+		FailureStatus: string(model.StatusErrored),
+		Clock:         clock,
+	})
+	if err != nil {
+		t.Fatalf("worker init error: %v", err)
+	}
+	return worker, store
+}
+
+func TestRetryWorkerRestartHonorsPersistedBackoff(t *testing.T) {
+	database := openIsolatedDatabase(t)
+	start := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	seedQueuedNotifications(t, database, "tenant-incident", 3, start)
+	emailSender := &stubEmailSender{err: errors.New("provider unavailable")}
+	clock := &adjustableClock{now: start}
+
+	firstService := newNotificationServiceWithSendersForSchedulerTests(database, emailSender, &stubSmsSender{})
+	firstService.retryIntervalSec = 60
+	firstWorker, firstStore := newCheckpointedRetryWorkerForTest(t, firstService, clock)
+	firstWorker.RunOnce(context.Background())
+	if err := firstStore.saveCheckpoint(context.Background(), clock.now); err != nil {
+		t.Fatalf("save checkpoint: %v", err)
+	}
+	if emailSender.callCount != 3 {
+		t.Fatalf("expected every notification to be attempted once, got %d", emailSender.callCount)
+	}
+	stored, err := model.GetNotificationByID(context.Background(), database, "tenant-incident", "tenant-incident-0")
+	if err != nil {
+		t.Fatalf("fetch notification: %v", err)
+	}
+	if stored.NextAttemptAt == nil || !stored.NextAttemptAt.Equal(start.Add(2*time.Minute)) {
+		t.Fatalf("expected next_attempt_at two intervals after the attempt, got %v", stored.NextAttemptAt)
+	}
+
+	// Restart: a fresh service and worker, with no in-memory state, mid backoff window.
+	clock.now = start.Add(90 * time.Second)
+	restartedService := newNotificationServiceWithSendersForSchedulerTests(database, emailSender, &stubSmsSender{})
+	restartedService.retryIntervalSec = 60
+	restartedWorker, restartedStore := newCheckpointedRetryWorkerForTest(t, restartedService, clock)
+	if jobs, err := restartedStore.PendingJobs(context.Background(), restartedService.maxRetries, clock.now); err != nil || len(jobs) != 0 {
+		t.Fatalf("expected no pending jobs inside the backoff window, got %d (%v)", len(jobs), err)
+	}
+	restartedWorker.RunOnce(context.Background())
+	if emailSender.callCount != 3 {
+		t.Fatalf("expected no attempt inside the backoff window after a restart, got %d", emailSender.callCount)
+	}
+
+	clock.now = start.Add(2 * time.Minute)
+	restartedWorker.RunOnce(context.Background())
+	if emailSender.callCount != 6 {
+		t.Fatalf("expected one more attempt each once the backoff elapsed, got %d", emailSender.callCount)
+	}
+}
+
+func TestRetryStoreResumesTenantCursorFromCheckpoint(t *testing.T) {
+	database := openIsolatedDatabase(t)
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	seedQueuedNotifications(t, database, "tenant-backlog", 3, now)
+	first, err := model.GetNotificationByID(context.Background(), database, "tenant-backlog", "tenant-backlog-0")
+	if err != nil {
+		t.Fatalf("fetch notification: %v", err)
+	}
+	saveCheckpoint := func(lastTickAt time.Time) {
+		t.Helper()
+		checkpoint := model.WorkerCheckpoint{
+			WorkerName:    model.RetryWorkerCheckpointName,
+			LastTickAt:    lastTickAt,
+			TenantCursors: map[string]uint{"tenant-backlog": first.ID},
+		}
+		if err := model.SaveWorkerCheckpoint(context.Background(), database, checkpoint); err != nil {
+			t.Fatalf("save checkpoint: %v", err)
+		}
+	}
+	firstPendingJob := func(logger *slog.Logger) string {
+		t.Helper()
+		store := newNotificationRetryStore(database, nil).withTenantCap(1)
+		store.resumeFromCheckpoint(context.Background(), logger, now)
+		jobs, err := store.PendingJobs(context.Background(), 5, now)
+		if err != nil || len(jobs) != 1 {
+			t.Fatalf("expected one capped job, got %d (%v)", len(jobs), err)
+		}
+		return jobs[0].ID
+	}
+
+	saveCheckpoint(now.Add(-time.Minute))
+	if jobID := firstPendingJob(slog.New(slog.NewTextHandler(io.Discard, nil))); jobID != "tenant-backlog-1" {
+		t.Fatalf("expected the cycle to continue after the cursor, got %s", jobID)
+	}
+
+	saveCheckpoint(now.Add(-retryCheckpointMaxAge - time.Minute))
+	var logOutput strings.Builder
+	if jobID := firstPendingJob(slog.New(slog.NewTextHandler(&logOutput, nil))); jobID != "tenant-backlog-0" {
+		t.Fatalf("expected a stale checkpoint to restart from the oldest notification, got %s", jobID)
+	}
+	if !strings.Contains(logOutput.String(), "Discarding stale retry worker checkpoint") {
+		t.Fatalf("expected the stale checkpoint to be logged, got %q", logOutput.String())
+	}
+}
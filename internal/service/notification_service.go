@@ -5,41 +5,112 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/tyemirov/pinguin/internal/config"
+	"github.com/tyemirov/pinguin/internal/entropy"
 	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/opentracking"
 	"github.com/tyemirov/pinguin/internal/tenant"
+	"github.com/tyemirov/pinguin/pkg/logging"
 	"github.com/tyemirov/utils/scheduler"
 	"gorm.io/gorm"
 )
 
 // NotificationService defines the external interface for processing notifications.
 type NotificationService interface {
-	// SendNotification immediately dispatches the notification and stores it.
+	// SendNotification immediately dispatches the notification and stores it. When the
+	// immediate dispatch fails and failOnImmediateError is in effect, it returns the stored
+	// response together with an error wrapping ErrImmediateDispatchFailed.
 	SendNotification(ctx context.Context, request model.NotificationRequest) (model.NotificationResponse, error)
+	// SendNotificationGroup sends one notification per channel, linked by a shared group id
+	// and dispatched independently.
+	SendNotificationGroup(ctx context.Context, request model.NotificationGroupRequest) (model.NotificationGroupResponse, error)
+	// GetNotificationGroup retrieves the combined state of a multi-channel send.
+	GetNotificationGroup(ctx context.Context, groupID string) (model.NotificationGroupResponse, error)
+	// EstimateNotificationSize validates request as SendNotification would and reports the
+	// size of the message its provider would receive, without sending or storing it.
+	EstimateNotificationSize(ctx context.Context, request model.NotificationRequest) (MessageSizeEstimate, error)
 	// GetNotificationStatus retrieves the stored notification status.
 	GetNotificationStatus(ctx context.Context, notificationID string) (model.NotificationResponse, error)
+	// GetRenderedNotification retrieves the stored notification with the content it was dispatched with.
+	GetRenderedNotification(ctx context.Context, notificationID string) (model.NotificationResponse, error)
 	// ListNotifications returns stored notifications honoring the provided filters.
 	ListNotifications(ctx context.Context, filters model.NotificationListFilters) ([]model.NotificationResponse, error)
 	// ListNotificationsPage returns a paginated set of stored notifications.
 	ListNotificationsPage(ctx context.Context, filters model.NotificationListFilters, pageRequest model.NotificationListPageRequest) (model.NotificationListResponsePage, error)
+	// StreamNotifications visits the tenant's notifications oldest first, without
+	// attachments, reading model.NotificationStreamBatchSize rows at a time. Iteration
+	// stops at the first error visit returns.
+	StreamNotifications(ctx context.Context, filters model.NotificationListFilters, visit func(model.NotificationResponse) error) error
 	// ListNotificationsAll returns notifications across all tenants.
 	ListNotificationsAll(ctx context.Context, filters model.NotificationListFilters) ([]model.NotificationResponse, error)
 	// RescheduleNotification updates the scheduled send time for a queued notification.
 	RescheduleNotification(ctx context.Context, notificationID string, scheduledFor time.Time) (model.NotificationResponse, error)
 	// CancelNotification transitions a queued notification to cancelled so workers skip it.
-	CancelNotification(ctx context.Context, notificationID string) (model.NotificationResponse, error)
+	// A notification mid-dispatch is aborted and ends cancelled, or sent when the provider
+	// had already accepted it. The optional reason, limited to model.MaxCancelReasonLength
+	// characters, and actor are recorded on the notification and in the audit log.
+	CancelNotification(ctx context.Context, notificationID string, reason string, actor string) (model.NotificationResponse, error)
+	// SetLegalHold places or lifts a legal hold, which exempts a notification from retention.
+	// actor identifies the operator for the audit log.
+	SetLegalHold(ctx context.Context, notificationID string, held bool, actor string) (model.NotificationResponse, error)
+	// GetCostSummary aggregates estimated spend for sent notifications by day and channel.
+	GetCostSummary(ctx context.Context, summaryRange model.CostSummaryRange) (model.CostSummary, error)
 	// StartRetryWorker begins a background worker that processes retries with exponential backoff.
 	StartRetryWorker(ctx context.Context)
+	// StartDailyReportWorker emails opted-in tenants' admins a digest of the previous local day.
+	StartDailyReportWorker(ctx context.Context)
+	// StartIntegrityWorker periodically sweeps attachment rows for integrity problems.
+	StartIntegrityWorker(ctx context.Context)
+	// StartWebhookWorker delivers queued notification state events to tenant webhooks.
+	StartWebhookWorker(ctx context.Context)
+	// StartRetentionWorker periodically deletes finished notifications past their tenant's retention.
+	StartRetentionWorker(ctx context.Context)
+	// DispatchPacing reports the adaptive pacing state of the tenant's providers.
+	DispatchPacing(ctx context.Context) ([]DispatchPacingState, error)
+	// GetCapabilities reports the channels and limits available to the tenant.
+	GetCapabilities(ctx context.Context) (Capabilities, error)
+	// TestTenantDelivery checks the tenant's provider credentials for a channel without sending.
+	TestTenantDelivery(ctx context.Context, notificationType model.NotificationType) (DeliveryCheck, error)
+	// ListTenantsStatus reports every tenant's operational status; callers must restrict it to operators.
+	ListTenantsStatus(ctx context.Context) ([]TenantStatus, error)
+	// TransferNotifications moves notifications between tenants; callers must restrict it to operators.
+	TransferNotifications(ctx context.Context, request NotificationTransferRequest) (NotificationTransferResult, error)
+	// CacheStates reports the tenant and sender caches of this process; callers must restrict it to operators.
+	CacheStates(ctx context.Context) []CacheState
+	// FlushCaches empties the tenant and sender caches, audit-logs actor, and returns the states before the flush.
+	FlushCaches(ctx context.Context, actor string) []CacheState
+	// Drain stops accepting sends so the instance can finish its queues and exit.
+	Drain(ctx context.Context) (DrainStatus, error)
+	// GetDrainStatus reports drain progress; it is the zero value until Drain is called.
+	GetDrainStatus(ctx context.Context) (DrainStatus, error)
+	// Draining reports whether Drain has been called.
+	Draining() bool
+	// Drained closes once a drain has completed.
+	Drained() <-chan struct{}
 }
 
 var (
+	// ErrMissingNotificationID rejects a lookup or change addressed by an empty notification id.
+	ErrMissingNotificationID   = errors.New("notification_id is required")
 	ErrSMSDisabled             = errors.New("sms delivery disabled: missing Twilio credentials")
 	ErrNotificationNotEditable = errors.New("notification must be queued before editing")
 	ErrMissingTenantContext    = errors.New("tenant context missing")
+	ErrScheduleAfterExpiry     = errors.New("scheduled time must be before the notification expiry")
+	ErrScheduleBeyondHorizon   = errors.New("scheduled time is beyond the maximum schedule horizon")
+	// ErrScheduleInPast rejects a reschedule to a time already passed, and a send scheduled
+	// in the past when server.strictScheduling is set; otherwise such a send goes out now.
+	ErrScheduleInPast = errors.New("scheduled time must be in the future")
+	// ErrImmediateDispatchFailed reports a failed immediate dispatch to callers that asked
+	// for it with failOnImmediateError; the notification is stored and will be retried.
+	ErrImmediateDispatchFailed = errors.New("immediate dispatch failed; notification queued for retry")
+	// ErrAttachmentDataNotPersisted rejects deferred sends whose attachment bytes the tenant does not store.
+	ErrAttachmentDataNotPersisted = errors.New("attachments cannot be scheduled: tenant does not persist attachment data")
 )
 
 type notificationServiceImpl struct {
@@ -54,6 +125,52 @@ type notificationServiceImpl struct {
 	senderMutex        sync.RWMutex
 	emailSenders       map[string]EmailSender
 	smsSenders         map[string]SmsSender
+	senderCacheStats   senderCacheCounters
+	dispatchLimiter    *dispatchLimiter
+	dispatchPacer      *dispatchPacer
+	providerBreakers   *providerBreakers
+	dispatchLatency    *dispatchLatencyRecorder
+	attachmentSizes    *attachmentSizeRecorder
+	inFlight           inFlightDispatches
+	integrityMutex     sync.RWMutex
+	lastIntegrity      *model.AttachmentIntegrityReport
+	drain              instanceDrain
+	// clock is the service's time source for schedules, expiry, drains and its workers;
+	// nil uses the system clock.
+	clock scheduler.Clock
+	// webhookClient posts webhook events; nil uses http.DefaultClient.
+	webhookClient *http.Client
+	// retryQueue feeds the retry worker; nil builds the queue named by server.retryQueue.
+	retryQueue RetryQueue
+	// random mints notification ids, MIME boundaries and webhook event ids; nil uses entropy.System.
+	random entropy.RandomSource
+	// openTrackingPixels adds open tracking pixels to opted-in tenants' HTML email; nil
+	// when web.openTrackingBaseURL is unset.
+	openTrackingPixels *opentracking.Pixels
+}
+
+// Option customizes a NotificationService at construction.
+type Option func(*notificationServiceImpl)
+
+// WithClock makes the service and its workers read the time from clock instead of the
+// system clock. The timestamps GORM fills in on the service's writes follow it too.
+func WithClock(clock scheduler.Clock) Option {
+	return func(serviceInstance *notificationServiceImpl) {
+		serviceInstance.clock = clock
+		if serviceInstance.database != nil && clock != nil {
+			serviceInstance.database = serviceInstance.database.Session(&gorm.Session{
+				NowFunc: func() time.Time { return clock.Now().UTC() },
+			})
+		}
+	}
+}
+
+// WithRandomSource draws notification ids, MIME boundaries and webhook event ids from
+// source instead of entropy.System.
+func WithRandomSource(source entropy.RandomSource) Option {
+	return func(serviceInstance *notificationServiceImpl) {
+		serviceInstance.random = source
+	}
 }
 
 // NewNotificationService creates a NotificationService backed by SMTP/Twilio senders.
@@ -69,6 +186,7 @@ func NewNotificationServiceWithSenders(
 	tenantRepo *tenant.Repository,
 	emailSender EmailSender,
 	smsSender SmsSender,
+	options ...Option,
 ) NotificationService {
 	var defaultEmailSender EmailSender
 	var defaultSmsSender SmsSender
@@ -96,7 +214,7 @@ func NewNotificationServiceWithSenders(
 		logger.Warn("SMS notifications disabled: missing Twilio credentials")
 	}
 
-	return &notificationServiceImpl{
+	serviceInstance := &notificationServiceImpl{
 		database:           db,
 		logger:             logger,
 		tenantRepo:         tenantRepo,
@@ -107,7 +225,17 @@ func NewNotificationServiceWithSenders(
 		retryIntervalSec:   cfg.RetryIntervalSec,
 		emailSenders:       make(map[string]EmailSender),
 		smsSenders:         make(map[string]SmsSender),
+		dispatchLimiter:    newDispatchLimiter(cfg.MaxInFlightSends, cfg.InFlightSendPolicy),
+		dispatchPacer:      newDispatchPacer(cfg.DispatchPacing, logger),
+		providerBreakers:   newProviderBreakers(db, time.Duration(cfg.RetryIntervalSec)*time.Second, logger),
+		dispatchLatency:    newDispatchLatencyRecorder(),
+		attachmentSizes:    newAttachmentSizeRecorder(),
+		openTrackingPixels: newOpenTrackingPixels(cfg),
+	}
+	for _, option := range options {
+		option(serviceInstance)
 	}
+	return serviceInstance
 }
 
 func (serviceInstance *notificationServiceImpl) SendNotification(ctx context.Context, request model.NotificationRequest) (model.NotificationResponse, error) {
@@ -115,71 +243,233 @@ func (serviceInstance *notificationServiceImpl) SendNotification(ctx context.Con
 	if err != nil {
 		return model.NotificationResponse{}, err
 	}
-	recipient := request.Recipient()
-	subject := request.Subject()
-	message := request.Message()
-	attachments := request.Attachments()
-	scheduledFor := request.ScheduledFor()
+	logging.LogEvent(ctx, serviceInstance.logger, logging.EventNotificationReceived, "notification_received",
+		"tenant_id", runtimeCfg.Tenant.ID,
+		"notification_type", request.NotificationType(),
+		"recipient_digest", DigestForLogging(request.Recipient()),
+		"subject_digest", DigestForLogging(request.Subject()),
+		"scheduled", request.ScheduledFor() != nil,
+		"attachment_count", len(request.SharedAttachments()),
+	)
+	currentTime := serviceInstance.currentTime()
+	pending, err := serviceInstance.prepareSend(runtimeCfg, request, "", currentTime)
+	if err != nil {
+		logging.LogEvent(ctx, serviceInstance.logger, logging.EventNotificationRejected, "send_rejected", "tenant_id", runtimeCfg.Tenant.ID, "notification_type", request.NotificationType(), "recipient_digest", DigestForLogging(request.Recipient()), "error", err)
+		return model.NotificationResponse{}, err
+	}
+	serviceInstance.logger.DebugContext(ctx, "send_prepared",
+		"tenant_id", runtimeCfg.Tenant.ID,
+		"notification_id", pending.notification.NotificationID,
+		"notification_type", pending.notification.NotificationType,
+		"recipient_digest", DigestForLogging(pending.notification.Recipient),
+		"attachment_count", len(pending.attachments),
+		"immediate", pending.immediate,
+	)
+	if err := serviceInstance.dispatchAndStore(ctx, runtimeCfg, &pending, currentTime, false); err != nil {
+		return model.NotificationResponse{}, err
+	}
+	if pending.notification.Status == model.StatusErrored && serviceInstance.failOnImmediateError(request) {
+		return model.NewNotificationResponse(pending.notification), fmt.Errorf("%w: notification %s: %w", ErrImmediateDispatchFailed, pending.notification.NotificationID, pending.dispatchError)
+	}
+	return model.NewNotificationResponse(pending.notification), nil
+}
 
-	notificationID := fmt.Sprintf("notif-%d", time.Now().UnixNano())
-	newNotification := model.NewNotification(notificationID, runtimeCfg.Tenant.ID, request)
+// pendingSend is a validated notification awaiting its immediate dispatch attempt and insert.
+type pendingSend struct {
+	notification model.Notification
+	attachments  []model.EmailAttachment
+	// immediate is false when the notification is scheduled for later.
+	immediate bool
+	// dispatchError is the provider error of a failed immediate dispatch.
+	dispatchError error
+}
 
-	currentTime := time.Now().UTC()
+// prepareSend builds the notification for request and applies every check that can
+// reject it, without dispatching or storing anything.
+func (serviceInstance *notificationServiceImpl) prepareSend(runtimeCfg tenant.RuntimeConfig, request model.NotificationRequest, groupID string, currentTime time.Time) (pendingSend, error) {
+	attachments := request.SharedAttachments()
+	scheduledFor := request.ScheduledFor()
 
+	notificationID, err := serviceInstance.newNotificationID(runtimeCfg)
+	if err != nil {
+		return pendingSend{}, err
+	}
+	newNotification := model.NewNotification(notificationID, runtimeCfg.Tenant.ID, request, currentTime)
+	newNotification.GroupID = groupID
+	if err := applySMSLimits(runtimeCfg, request.SMSOverflowPolicy(), &newNotification); err != nil {
+		return pendingSend{}, err
+	}
+	if newNotification.SMSTruncated {
+		serviceInstance.logger.Info("Truncated SMS to the tenant segment limit", "notification_id", notificationID, "tenant_id", runtimeCfg.Tenant.ID, "original_length", newNotification.OriginalMessageLength)
+	}
+	if err := serviceInstance.requireEncryptedEmailSender(runtimeCfg, newNotification); err != nil {
+		return pendingSend{}, err
+	}
+	if err := serviceInstance.requireSenderOverridePolicy(runtimeCfg, newNotification); err != nil {
+		return pendingSend{}, err
+	}
+
+	if serviceInstance.beyondScheduleHorizon(scheduledFor, currentTime) {
+		return pendingSend{}, ErrScheduleBeyondHorizon
+	}
+	if serviceInstance.config.StrictScheduling && scheduledFor != nil && scheduledFor.Before(currentTime) {
+		return pendingSend{}, ErrScheduleInPast
+	}
 	shouldAttemptImmediateSend := true
 	if scheduledFor != nil && scheduledFor.After(currentTime) {
 		shouldAttemptImmediateSend = false
 	}
+	if runtimeCfg.Tenant.AttachmentMetadataOnly && len(attachments) > 0 {
+		if !shouldAttemptImmediateSend {
+			return pendingSend{}, ErrAttachmentDataNotPersisted
+		}
+		newNotification.DiscardAttachmentData()
+	}
+	return pendingSend{notification: newNotification, attachments: attachments, immediate: shouldAttemptImmediateSend}, nil
+}
+
+// dispatchAndStore attempts the immediate dispatch of a prepared notification, stores
+// it and records a failed dispatch's provider error on pending. When deferWhenBusy is
+// set, a send refused by the in-flight limit is stored queued for the retry worker
+// instead of failing.
+func (serviceInstance *notificationServiceImpl) dispatchAndStore(ctx context.Context, runtimeCfg tenant.RuntimeConfig, pending *pendingSend, currentTime time.Time, deferWhenBusy bool) error {
+	dispatchStartedAt := time.Now()
+	newNotification := &pending.notification
+	notificationID := newNotification.NotificationID
+	recipient := newNotification.Recipient
+	subject := newNotification.Subject
+	attachments := pending.attachments
+	shouldAttemptImmediateSend := pending.immediate
+	if shouldAttemptImmediateSend && !newNotification.HasDiscardedAttachmentData() &&
+		!serviceInstance.dispatchPacer.admit(runtimeCfg.Tenant.ID, newNotification.NotificationType, currentTime) {
+		logging.LogEvent(ctx, serviceInstance.logger, logging.EventNotificationDeferred, "Immediate dispatch deferred by pacing", "notification_id", notificationID, "tenant_id", runtimeCfg.Tenant.ID, "reason", "pacing")
+		shouldAttemptImmediateSend = false
+	}
+	if shouldAttemptImmediateSend && !newNotification.HasDiscardedAttachmentData() &&
+		!serviceInstance.providerBreakers.admit(ctx, runtimeCfg.Tenant.ID, newNotification.NotificationType, currentTime) {
+		logging.LogEvent(ctx, serviceInstance.logger, logging.EventNotificationDeferred, "Immediate dispatch deferred by provider breaker", "notification_id", notificationID, "tenant_id", runtimeCfg.Tenant.ID, "reason", "provider_breaker")
+		shouldAttemptImmediateSend = false
+	}
+
+	if shouldAttemptImmediateSend && newNotification.IsExpired(currentTime) {
+		serviceInstance.logger.Info("Skipping expired notification", "notification_id", notificationID, "tenant_id", runtimeCfg.Tenant.ID)
+		newNotification.MarkExpired(currentTime)
+		shouldAttemptImmediateSend = false
+	}
+
+	if shouldAttemptImmediateSend {
+		releaseDispatchSlot, acquireErr := serviceInstance.dispatchLimiter.acquire(ctx)
+		switch {
+		case acquireErr != nil && !deferWhenBusy:
+			serviceInstance.logger.Warn("Immediate dispatch rejected", "tenant_id", runtimeCfg.Tenant.ID, "error", acquireErr)
+			return acquireErr
+		case acquireErr != nil:
+			logging.LogEvent(ctx, serviceInstance.logger, logging.EventNotificationDeferred, "Immediate dispatch deferred by the in-flight limit", "notification_id", notificationID, "tenant_id", runtimeCfg.Tenant.ID, "reason", "in_flight_limit")
+			shouldAttemptImmediateSend = false
+		default:
+			defer releaseDispatchSlot()
+		}
+	}
 
 	var dispatchError error
+	emailBody := newNotification.Message
 	if shouldAttemptImmediateSend {
+		dispatchCtx, finishDispatch := serviceInstance.inFlight.begin(ctx, runtimeCfg.Tenant.ID, notificationID)
+		defer func() {
+			finishDispatch(*newNotification)
+		}()
 		switch newNotification.NotificationType {
 		case model.NotificationEmail:
-			var emailSender EmailSender
-			emailSender, err = serviceInstance.emailSenderForTenant(runtimeCfg)
+			emailSender, err := serviceInstance.emailSenderForTenant(runtimeCfg)
 			if err != nil {
 				serviceInstance.logger.Error("Email sender unavailable", "tenant_id", runtimeCfg.Tenant.ID, "error", err)
-				return model.NotificationResponse{}, err
+				return err
 			}
-			dispatchError = emailSender.SendEmail(ctx, recipient, subject, message, attachments)
+			emailBody = serviceInstance.trackedEmailBody(runtimeCfg, newNotification)
+			dispatchError = serviceInstance.callProvider(dispatchCtx, runtimeCfg.Tenant.ID, notificationID, model.NotificationEmail, recipient, func() error {
+				return deliverEmail(dispatchCtx, emailSender, newNotification, emailBody, attachments)
+			})
 			if dispatchError == nil {
 				newNotification.Status = model.StatusSent
 				newNotification.LastAttemptedAt = currentTime
 				// When using SMTP no provider message ID is returned.
+				applyDispatchCost(runtimeCfg.Tenant, newNotification, "")
 			}
 		case model.NotificationSMS:
-			var smsSender SmsSender
-			smsSender, err = serviceInstance.smsSenderForTenant(runtimeCfg)
+			smsSender, err := serviceInstance.smsSenderForTenant(runtimeCfg)
 			if err != nil {
 				serviceInstance.logger.Warn("SMS sender unavailable", "tenant_id", runtimeCfg.Tenant.ID, "error", err)
-				return model.NotificationResponse{}, err
+				return err
 			}
 			var providerMessageID string
-			providerMessageID, dispatchError = smsSender.SendSms(ctx, recipient, message)
+			dispatchError = serviceInstance.callProvider(dispatchCtx, runtimeCfg.Tenant.ID, notificationID, model.NotificationSMS, recipient, func() error {
+				var sendErr error
+				providerMessageID, sendErr = smsSender.SendSms(dispatchCtx, recipient, newNotification.Message)
+				return sendErr
+			})
 			if dispatchError == nil {
 				newNotification.Status = model.StatusSent
 				newNotification.ProviderMessageID = providerMessageID
 				newNotification.LastAttemptedAt = currentTime
+				applyDispatchCost(runtimeCfg.Tenant, newNotification, providerMessageID)
 			}
 		}
-		if dispatchError != nil {
-			serviceInstance.logger.Error("Immediate dispatch failed", "error", dispatchError)
+		switch {
+		case dispatchError != nil && dispatchCancelled(dispatchCtx):
+			serviceInstance.logger.Info("Immediate dispatch cancelled in flight", "notification_id", notificationID, "tenant_id", runtimeCfg.Tenant.ID)
+			markDispatchCancelled(dispatchCtx, newNotification, currentTime)
+			newNotification.LastAttemptedAt = currentTime
+		case dispatchError != nil:
 			newNotification.Status = model.StatusErrored
 			newNotification.LastAttemptedAt = currentTime
 		}
 	}
+	pending.dispatchError = dispatchError
 
-	if err := model.CreateNotification(ctx, serviceInstance.database, &newNotification); err != nil {
+	if err := model.CreateNotification(ctx, serviceInstance.database, newNotification); err != nil {
 		serviceInstance.logger.Error("Failed to store notification", "error", err)
-		return model.NotificationResponse{}, err
+		return err
 	}
-	serviceInstance.logger.Info(
-		"notification_persisted",
+	logging.LogEvent(ctx, serviceInstance.logger, logging.EventNotificationPersisted, "notification_persisted",
 		"notification_id", newNotification.NotificationID,
+		"tenant_id", runtimeCfg.Tenant.ID,
 		"notification_type", newNotification.NotificationType,
 		"status", newNotification.Status,
+		"attachment_count", len(attachments),
+		"attachment_bytes", attachmentBytes(attachments),
+	)
+	serviceInstance.attachmentSizes.observe(runtimeCfg.Tenant.ID, attachments)
+	serviceInstance.recordStateChange(ctx, newNotification, model.StatusQueued)
+	if newNotification.Status == model.StatusSent {
+		serviceInstance.recordRenderedContent(ctx, runtimeCfg, newNotification, subject, emailBody, attachments)
+	}
+	serviceInstance.logger.DebugContext(ctx, "send_dispatch_timing",
+		"tenant_id", runtimeCfg.Tenant.ID,
+		"notification_id", notificationID,
+		"recipient_digest", DigestForLogging(recipient),
+		"dispatch_attempted", shouldAttemptImmediateSend,
+		"status", newNotification.Status,
+		"dispatch_ms", time.Since(dispatchStartedAt).Milliseconds(),
 	)
-	return model.NewNotificationResponse(newNotification), nil
+	return nil
+}
+
+// maxRetryAge resolves the tenant's MaxRetryAgeSec, falling back to server.maxRetryAgeSec
+// when the tenant has none or its runtime cannot be loaded.
+func (serviceInstance *notificationServiceImpl) maxRetryAge(ctx context.Context, tenantID string) time.Duration {
+	maxAgeSec := serviceInstance.config.MaxRetryAgeSec
+	if runtimeCfg, err := serviceInstance.runtimeForTenantID(ctx, tenantID); err == nil && runtimeCfg.Tenant.MaxRetryAgeSec > 0 {
+		maxAgeSec = runtimeCfg.Tenant.MaxRetryAgeSec
+	}
+	return time.Duration(maxAgeSec) * time.Second
+}
+
+// failOnImmediateError resolves the request's override against server.failOnImmediateError.
+func (serviceInstance *notificationServiceImpl) failOnImmediateError(request model.NotificationRequest) bool {
+	if fail, overridden := request.FailOnImmediateError(); overridden {
+		return fail
+	}
+	return serviceInstance.config.FailOnImmediateError
 }
 
 func (serviceInstance *notificationServiceImpl) GetNotificationStatus(ctx context.Context, notificationID string) (model.NotificationResponse, error) {
@@ -187,12 +477,17 @@ func (serviceInstance *notificationServiceImpl) GetNotificationStatus(ctx contex
 	if err != nil {
 		return model.NotificationResponse{}, err
 	}
+	if strings.TrimSpace(notificationID) == "" {
+		return model.NotificationResponse{}, ErrMissingNotificationID
+	}
 	notificationRecord, retrievalError := model.MustGetNotificationByID(ctx, serviceInstance.database, runtimeCfg.Tenant.ID, notificationID)
 	if retrievalError != nil {
 		serviceInstance.logger.Error("Failed to retrieve notification", "error", retrievalError)
 		return model.NotificationResponse{}, retrievalError
 	}
-	return model.NewNotificationResponse(*notificationRecord), nil
+	response := model.NewNotificationResponse(*notificationRecord)
+	response.PendingReason = serviceInstance.pendingReason(ctx, *notificationRecord, serviceInstance.currentTime())
+	return response, nil
 }
 
 func (serviceInstance *notificationServiceImpl) ListNotifications(ctx context.Context, filters model.NotificationListFilters) ([]model.NotificationResponse, error) {
@@ -200,6 +495,9 @@ func (serviceInstance *notificationServiceImpl) ListNotifications(ctx context.Co
 	if err != nil {
 		return nil, err
 	}
+	if err := serviceInstance.checkStatusFilters(filters); err != nil {
+		return nil, err
+	}
 	records, err := model.ListNotifications(ctx, serviceInstance.database, runtimeCfg.Tenant.ID, filters)
 	if err != nil {
 		serviceInstance.logger.Error("Failed to list notifications", "error", err)
@@ -217,6 +515,9 @@ func (serviceInstance *notificationServiceImpl) ListNotificationsPage(ctx contex
 	if err != nil {
 		return model.NotificationListResponsePage{}, err
 	}
+	if err := serviceInstance.checkStatusFilters(filters); err != nil {
+		return model.NotificationListResponsePage{}, err
+	}
 	page, err := model.ListNotificationsPage(ctx, serviceInstance.database, runtimeCfg.Tenant.ID, filters, pageRequest)
 	if err != nil {
 		serviceInstance.logger.Error("Failed to list notifications", "error", err)
@@ -232,7 +533,32 @@ func (serviceInstance *notificationServiceImpl) ListNotificationsPage(ctx contex
 	}, nil
 }
 
+func (serviceInstance *notificationServiceImpl) StreamNotifications(ctx context.Context, filters model.NotificationListFilters, visit func(model.NotificationResponse) error) error {
+	runtimeCfg, err := serviceInstance.requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+	if err := serviceInstance.checkStatusFilters(filters); err != nil {
+		return err
+	}
+	err = model.StreamNotifications(ctx, serviceInstance.database, runtimeCfg.Tenant.ID, filters, model.NotificationStreamBatchSize, func(records []model.Notification) error {
+		for _, record := range records {
+			if visitErr := visit(model.NewNotificationResponse(record)); visitErr != nil {
+				return visitErr
+			}
+		}
+		return nil
+	})
+	if err != nil && ctx.Err() == nil {
+		serviceInstance.logger.Error("Failed to stream notifications", "error", err)
+	}
+	return err
+}
+
 func (serviceInstance *notificationServiceImpl) ListNotificationsAll(ctx context.Context, filters model.NotificationListFilters) ([]model.NotificationResponse, error) {
+	if err := serviceInstance.checkStatusFilters(filters); err != nil {
+		return nil, err
+	}
 	records, err := model.ListNotificationsAll(ctx, serviceInstance.database, filters)
 	if err != nil {
 		serviceInstance.logger.Error("Failed to list notifications", "error", err)
@@ -245,12 +571,31 @@ func (serviceInstance *notificationServiceImpl) ListNotificationsAll(ctx context
 	return responses, nil
 }
 
+// checkStatusFilters rejects unknown status filters when server.strictStatusFilters is set;
+// otherwise the list ignores them.
+func (serviceInstance *notificationServiceImpl) checkStatusFilters(filters model.NotificationListFilters) error {
+	if !serviceInstance.config.StrictStatusFilters {
+		return nil
+	}
+	return filters.ValidateStatuses()
+}
+
 func (serviceInstance *notificationServiceImpl) RescheduleNotification(ctx context.Context, notificationID string, scheduledFor time.Time) (model.NotificationResponse, error) {
 	runtimeCfg, err := serviceInstance.requireTenant(ctx)
 	if err != nil {
 		return model.NotificationResponse{}, err
 	}
+	if strings.TrimSpace(notificationID) == "" {
+		return model.NotificationResponse{}, ErrMissingNotificationID
+	}
 	normalizedSchedule := scheduledFor.UTC()
+	currentTime := serviceInstance.currentTime()
+	if normalizedSchedule.Before(currentTime) {
+		return model.NotificationResponse{}, ErrScheduleInPast
+	}
+	if serviceInstance.beyondScheduleHorizon(&normalizedSchedule, currentTime) {
+		return model.NotificationResponse{}, ErrScheduleBeyondHorizon
+	}
 	existingNotification, fetchErr := model.MustGetNotificationByID(ctx, serviceInstance.database, runtimeCfg.Tenant.ID, notificationID)
 	if fetchErr != nil {
 		serviceInstance.logger.Error("Failed to fetch notification for reschedule", "notification_id", notificationID, "error", fetchErr)
@@ -260,9 +605,13 @@ func (serviceInstance *notificationServiceImpl) RescheduleNotification(ctx conte
 		serviceInstance.logger.Warn("Rejecting reschedule because notification is not queued", "notification_id", notificationID, "status", existingNotification.Status)
 		return model.NotificationResponse{}, ErrNotificationNotEditable
 	}
+	if existingNotification.ExpiresAt != nil && !existingNotification.ExpiresAt.After(normalizedSchedule) {
+		serviceInstance.logger.Warn("Rejecting reschedule past notification expiry", "notification_id", notificationID, "expires_at", existingNotification.ExpiresAt)
+		return model.NotificationResponse{}, ErrScheduleAfterExpiry
+	}
 	scheduleCopy := normalizedSchedule
 	existingNotification.ScheduledFor = &scheduleCopy
-	existingNotification.UpdatedAt = time.Now().UTC()
+	existingNotification.UpdatedAt = serviceInstance.currentTime()
 	if saveErr := model.SaveNotification(ctx, serviceInstance.database, existingNotification); saveErr != nil {
 		serviceInstance.logger.Error("Failed to reschedule notification", "notification_id", notificationID, "error", saveErr)
 		return model.NotificationResponse{}, saveErr
@@ -270,36 +619,95 @@ func (serviceInstance *notificationServiceImpl) RescheduleNotification(ctx conte
 	return model.NewNotificationResponse(*existingNotification), nil
 }
 
-func (serviceInstance *notificationServiceImpl) CancelNotification(ctx context.Context, notificationID string) (model.NotificationResponse, error) {
+func (serviceInstance *notificationServiceImpl) CancelNotification(ctx context.Context, notificationID string, reason string, actor string) (model.NotificationResponse, error) {
 	runtimeCfg, err := serviceInstance.requireTenant(ctx)
 	if err != nil {
 		return model.NotificationResponse{}, err
 	}
+	if strings.TrimSpace(notificationID) == "" {
+		return model.NotificationResponse{}, ErrMissingNotificationID
+	}
+	normalizedReason, err := model.NormalizeCancelReason(reason)
+	if err != nil {
+		return model.NotificationResponse{}, err
+	}
+	if dispatch, inFlight := serviceInstance.inFlight.cancel(runtimeCfg.Tenant.ID, notificationID, normalizedReason, actor); inFlight {
+		select {
+		case <-dispatch.done:
+		case <-ctx.Done():
+			return model.NotificationResponse{}, ctx.Err()
+		}
+		serviceInstance.logger.Info("Cancelled in-flight dispatch", "notification_id", notificationID, "tenant_id", runtimeCfg.Tenant.ID, "status", dispatch.outcome.Status)
+		if dispatch.outcome.Status == model.StatusCancelled {
+			serviceInstance.auditCancellation(dispatch.outcome)
+		}
+		return model.NewNotificationResponse(dispatch.outcome), nil
+	}
 	existingNotification, fetchErr := model.MustGetNotificationByID(ctx, serviceInstance.database, runtimeCfg.Tenant.ID, notificationID)
 	if fetchErr != nil {
 		serviceInstance.logger.Error("Failed to fetch notification for cancellation", "notification_id", notificationID, "error", fetchErr)
 		return model.NotificationResponse{}, fetchErr
 	}
+	if existingNotification.Status == model.StatusSent && existingNotification.NotificationType == model.NotificationSMS {
+		return serviceInstance.cancelSentSms(ctx, runtimeCfg, existingNotification, normalizedReason, actor)
+	}
 	if existingNotification.Status != model.StatusQueued {
 		serviceInstance.logger.Warn("Rejecting cancellation because notification is not queued", "notification_id", notificationID, "status", existingNotification.Status)
 		return model.NotificationResponse{}, ErrNotificationNotEditable
 	}
-	existingNotification.Status = model.StatusCancelled
-	existingNotification.ScheduledFor = nil
-	existingNotification.UpdatedAt = time.Now().UTC()
+	existingNotification.MarkCancelled(serviceInstance.currentTime(), normalizedReason, actor)
 	if saveErr := model.SaveNotification(ctx, serviceInstance.database, existingNotification); saveErr != nil {
 		serviceInstance.logger.Error("Failed to cancel notification", "notification_id", notificationID, "error", saveErr)
 		return model.NotificationResponse{}, saveErr
 	}
+	serviceInstance.auditCancellation(*existingNotification)
+	serviceInstance.recordStateChange(ctx, existingNotification, model.StatusQueued)
 	return model.NewNotificationResponse(*existingNotification), nil
 }
 
+// auditCancellation writes the audit log entry of a requested cancellation with the
+// fields its notification.cancelled webhook event carries, and the encoded snapshot.
+func (serviceInstance *notificationServiceImpl) auditCancellation(record model.Notification) {
+	attributes := []any{"tenant_id", record.TenantID, "notification_id", record.NotificationID, "cancel_reason", record.CancelReason, "cancelled_by", record.CancelledBy, "cancelled_at", record.CancelledAt}
+	if encoded, err := model.NewNotificationSnapshot(record, model.SnapshotRedacted).Encode(); err == nil {
+		attributes = append(attributes, "notification", string(encoded))
+	}
+	serviceInstance.logger.Info("audit_notification_cancelled", attributes...)
+}
+
+func (serviceInstance *notificationServiceImpl) GetCostSummary(ctx context.Context, summaryRange model.CostSummaryRange) (model.CostSummary, error) {
+	runtimeCfg, err := serviceInstance.requireTenant(ctx)
+	if err != nil {
+		return model.CostSummary{}, err
+	}
+	summary, summaryErr := model.SummarizeNotificationCosts(ctx, serviceInstance.database, runtimeCfg.Tenant.ID, summaryRange)
+	if summaryErr != nil {
+		serviceInstance.logger.Error("Failed to summarize notification costs", "tenant_id", runtimeCfg.Tenant.ID, "error", summaryErr)
+		return model.CostSummary{}, summaryErr
+	}
+	if summary.Currency == "" {
+		summary.Currency = runtimeCfg.Tenant.CostCurrency
+	}
+	return summary, nil
+}
+
 func (serviceInstance *notificationServiceImpl) StartRetryWorker(ctx context.Context) {
+	retryInterval := time.Duration(serviceInstance.retryIntervalSec) * time.Second
+	retryQueue := serviceInstance.retryQueue
+	if retryQueue == nil {
+		var queueErr error
+		retryQueue, queueErr = serviceInstance.newRetryQueue(retryInterval)
+		if queueErr != nil {
+			serviceInstance.logger.Error("Failed to initialize retry queue", "error", queueErr)
+			return
+		}
+	}
 	worker, workerErr := scheduler.NewWorker(scheduler.Config{
-		Repository:    newNotificationRetryStore(serviceInstance.database, serviceInstance.tenantRepo),
+		Repository:    newRetryQueueRepository(retryQueue, serviceInstance.maxRetries, retryInterval),
 		Dispatcher:    newNotificationDispatcher(serviceInstance),
 		Logger:        serviceInstance.logger,
-		Interval:      time.Duration(serviceInstance.retryIntervalSec) * time.Second,
+		Clock:         serviceInstance.timeSource(),
+		Interval:      retryInterval,
 		MaxRetries:    serviceInstance.maxRetries,
 		SuccessStatus: string(model.StatusSent),
 		FailureStatus: string(model.StatusErrored),
@@ -308,7 +716,47 @@ func (serviceInstance *notificationServiceImpl) StartRetryWorker(ctx context.Con
 		serviceInstance.logger.Error("Failed to initialize retry worker", "error", workerErr)
 		return
 	}
-	worker.Run(ctx)
+	checkpointer, _ := retryQueue.(retryCheckpointer)
+	if checkpointer != nil {
+		checkpointer.resumeFromCheckpoint(ctx, serviceInstance.logger, serviceInstance.currentTime())
+	}
+	if ctx.Err() == nil && serviceInstance.reconcileUnattemptedQueued(ctx, serviceInstance.currentTime()) > 0 {
+		worker.RunOnce(ctx)
+	}
+	runCheckpointedRetryWorker(ctx, worker, checkpointer, retryInterval, serviceInstance.timeSource(), serviceInstance.logger)
+}
+
+// runCheckpointedRetryWorker drives the scheduler tick by tick and records a checkpoint
+// after each completed tick so a restart resumes where the worker stopped. A nil
+// checkpointer runs the ticks without checkpoints.
+func runCheckpointedRetryWorker(ctx context.Context, worker *scheduler.Worker, checkpointer retryCheckpointer, interval time.Duration, clock scheduler.Clock, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	logger.Info("retry_worker_started", "interval", interval)
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("retry_worker_stopped")
+			return
+		case <-ticker.C:
+			worker.RunOnce(ctx)
+			if ctx.Err() != nil || checkpointer == nil {
+				continue
+			}
+			if err := checkpointer.saveCheckpoint(ctx, clock.Now().UTC()); err != nil {
+				logger.Error("Failed to save retry worker checkpoint", "error", err)
+			}
+		}
+	}
+}
+
+// DispatchPacing reports the adaptive pacing state of the tenant in ctx.
+func (serviceInstance *notificationServiceImpl) DispatchPacing(ctx context.Context) ([]DispatchPacingState, error) {
+	runtimeCfg, err := serviceInstance.requireTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return serviceInstance.dispatchPacer.snapshot(runtimeCfg.Tenant.ID), nil
 }
 
 func (serviceInstance *notificationServiceImpl) requireTenant(ctx context.Context) (tenant.RuntimeConfig, error) {
@@ -319,6 +767,35 @@ func (serviceInstance *notificationServiceImpl) requireTenant(ctx context.Contex
 	return runtimeCfg, nil
 }
 
+// timeSource returns the injected clock, falling back to the system clock.
+func (serviceInstance *notificationServiceImpl) timeSource() scheduler.Clock {
+	if serviceInstance.clock == nil {
+		return systemClock{}
+	}
+	return serviceInstance.clock
+}
+
+// currentTime reads the service clock in UTC.
+func (serviceInstance *notificationServiceImpl) currentTime() time.Time {
+	return serviceInstance.timeSource().Now().UTC()
+}
+
+func (serviceInstance *notificationServiceImpl) randomSource() entropy.RandomSource {
+	if serviceInstance.random == nil {
+		return entropy.System()
+	}
+	return serviceInstance.random
+}
+
+// newNotificationID joins the tenant's prefix and a unique suffix from the random source.
+func (serviceInstance *notificationServiceImpl) newNotificationID(runtimeCfg tenant.RuntimeConfig) (string, error) {
+	suffix, err := serviceInstance.randomSource().UniqueSuffix()
+	if err != nil {
+		return "", fmt.Errorf("notification id: %w", err)
+	}
+	return runtimeCfg.NotificationIDPrefix() + "-" + suffix, nil
+}
+
 func (serviceInstance *notificationServiceImpl) emailSenderForTenant(runtimeCfg tenant.RuntimeConfig) (EmailSender, error) {
 	if serviceInstance.defaultEmailSender != nil {
 		return serviceInstance.defaultEmailSender, nil
@@ -326,26 +803,92 @@ func (serviceInstance *notificationServiceImpl) emailSenderForTenant(runtimeCfg
 	if runtimeCfg.Email.Host == "" || runtimeCfg.Email.Username == "" || runtimeCfg.Email.Password == "" || runtimeCfg.Email.FromAddress == "" {
 		return nil, fmt.Errorf("email credentials unavailable for tenant %s", runtimeCfg.Tenant.ID)
 	}
+	poolSize, poolIdleTimeout := serviceInstance.smtpPoolForTenant(runtimeCfg.Email)
+	smtpConfig := SMTPConfig{
+		Host:            runtimeCfg.Email.Host,
+		Port:            strconv.Itoa(runtimeCfg.Email.Port),
+		Username:        runtimeCfg.Email.Username,
+		Password:        runtimeCfg.Email.Password,
+		FromAddress:     runtimeCfg.Email.FromAddress,
+		Timeouts:        serviceInstance.smtpTimeoutsForTenant(runtimeCfg.Email),
+		Random:          serviceInstance.randomSource(),
+		PoolSize:        poolSize,
+		PoolIdleTimeout: poolIdleTimeout,
+	}
 	serviceInstance.senderMutex.RLock()
 	cached := serviceInstance.emailSenders[runtimeCfg.Tenant.ID]
 	serviceInstance.senderMutex.RUnlock()
-	if cached != nil {
+	if cached != nil && !smtpSenderOutdated(cached, smtpConfig) {
+		serviceInstance.senderCacheStats.emailHits.Add(1)
 		return cached, nil
 	}
-	smtpSender := NewSMTPEmailSender(SMTPConfig{
-		Host:        runtimeCfg.Email.Host,
-		Port:        strconv.Itoa(runtimeCfg.Email.Port),
-		Username:    runtimeCfg.Email.Username,
-		Password:    runtimeCfg.Email.Password,
-		FromAddress: runtimeCfg.Email.FromAddress,
-		Timeouts:    serviceInstance.config,
-	}, serviceInstance.logger)
 	serviceInstance.senderMutex.Lock()
 	defer serviceInstance.senderMutex.Unlock()
+	cached = serviceInstance.emailSenders[runtimeCfg.Tenant.ID]
+	if cached != nil && !smtpSenderOutdated(cached, smtpConfig) {
+		serviceInstance.senderCacheStats.emailHits.Add(1)
+		return cached, nil
+	}
+	serviceInstance.senderCacheStats.emailMisses.Add(1)
+	// Rotated credentials or pool settings replace the sender, and its pooled
+	// connections, still authenticated with the old account, are closed.
+	if outdated, isSMTP := cached.(*SMTPEmailSender); isSMTP {
+		outdated.Close()
+	}
+	smtpSender := NewSMTPEmailSender(smtpConfig, serviceInstance.logger)
 	serviceInstance.emailSenders[runtimeCfg.Tenant.ID] = smtpSender
 	return smtpSender, nil
 }
 
+// smtpSenderOutdated reports whether cached is an SMTP sender built from settings other
+// than configuration. Other senders are never replaced.
+func smtpSenderOutdated(cached EmailSender, configuration SMTPConfig) bool {
+	smtpSender, isSMTP := cached.(*SMTPEmailSender)
+	if !isSMTP {
+		return false
+	}
+	current := smtpSender.Config
+	return current.Host != configuration.Host ||
+		current.Port != configuration.Port ||
+		current.Username != configuration.Username ||
+		current.Password != configuration.Password ||
+		current.FromAddress != configuration.FromAddress ||
+		current.Timeouts.ConnectionTimeoutSec != configuration.Timeouts.ConnectionTimeoutSec ||
+		current.Timeouts.OperationTimeoutSec != configuration.Timeouts.OperationTimeoutSec ||
+		current.PoolSize != configuration.PoolSize ||
+		current.PoolIdleTimeout != configuration.PoolIdleTimeout
+}
+
+// smtpPoolForTenant returns server.smtpPoolSize and server.smtpPoolIdleTimeoutSec with
+// the email profile's overrides applied.
+func (serviceInstance *notificationServiceImpl) smtpPoolForTenant(credentials tenant.EmailCredentials) (int, time.Duration) {
+	poolSize := serviceInstance.config.SMTPPoolSize
+	if credentials.PoolSize > 0 {
+		poolSize = credentials.PoolSize
+	}
+	idleTimeoutSec := serviceInstance.config.SMTPPoolIdleTimeoutSec
+	if credentials.PoolIdleTimeoutSec > 0 {
+		idleTimeoutSec = credentials.PoolIdleTimeoutSec
+	}
+	if idleTimeoutSec <= 0 {
+		idleTimeoutSec = config.DefaultSMTPPoolIdleTimeoutSec
+	}
+	return poolSize, time.Duration(idleTimeoutSec) * time.Second
+}
+
+// smtpTimeoutsForTenant returns the server configuration with the email profile's
+// connection and operation timeout overrides applied.
+func (serviceInstance *notificationServiceImpl) smtpTimeoutsForTenant(credentials tenant.EmailCredentials) config.Config {
+	timeouts := serviceInstance.config
+	if credentials.ConnectionTimeoutSec > 0 {
+		timeouts.ConnectionTimeoutSec = credentials.ConnectionTimeoutSec
+	}
+	if credentials.OperationTimeoutSec > 0 {
+		timeouts.OperationTimeoutSec = credentials.OperationTimeoutSec
+	}
+	return timeouts
+}
+
 func (serviceInstance *notificationServiceImpl) smsSenderForTenant(runtimeCfg tenant.RuntimeConfig) (SmsSender, error) {
 	if serviceInstance.defaultSmsSender != nil {
 		return serviceInstance.defaultSmsSender, nil
@@ -357,8 +900,10 @@ func (serviceInstance *notificationServiceImpl) smsSenderForTenant(runtimeCfg te
 	cached := serviceInstance.smsSenders[runtimeCfg.Tenant.ID]
 	serviceInstance.senderMutex.RUnlock()
 	if cached != nil {
+		serviceInstance.senderCacheStats.smsHits.Add(1)
 		return cached, nil
 	}
+	serviceInstance.senderCacheStats.smsMisses.Add(1)
 	smsSender := NewTwilioSmsSender(runtimeCfg.SMS.AccountSID, runtimeCfg.SMS.AuthToken, runtimeCfg.SMS.FromNumber, serviceInstance.logger, serviceInstance.config)
 	serviceInstance.senderMutex.Lock()
 	defer serviceInstance.senderMutex.Unlock()
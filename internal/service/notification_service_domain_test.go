@@ -73,6 +73,52 @@ func TestListNotificationsFiltersByStatus(t *testing.T) {
 	}
 }
 
+func TestListNotificationsUnknownStatusFilter(t *testing.T) {
+	database := openIsolatedDatabase(t)
+	serviceInstance := newNotificationServiceForDomainTests(database)
+	now := time.Now().UTC()
+	for index, status := range []model.NotificationStatus{model.StatusQueued, model.StatusSent} {
+		insertNotificationRecord(t, database, model.Notification{
+			NotificationID:   fmt.Sprintf("notif-%s", status),
+			NotificationType: model.NotificationEmail,
+			Recipient:        "user@example.com",
+			Message:          "hello",
+			Status:           status,
+			CreatedAt:        now.Add(time.Duration(index) * time.Second),
+			UpdatedAt:        now.Add(time.Duration(index) * time.Second),
+		})
+	}
+	typo := model.NotificationListFilters{Statuses: []model.NotificationStatus{"quued"}}
+
+	lenient, err := serviceInstance.ListNotifications(tenantContext(), typo)
+	if err != nil || len(lenient) != 2 {
+		t.Fatalf("expected the unknown status to be ignored by default, got %d notifications, err=%v", len(lenient), err)
+	}
+
+	serviceInstance.config.StrictStatusFilters = true
+	if _, err := serviceInstance.ListNotifications(tenantContext(), typo); !errors.Is(err, model.ErrUnknownNotificationStatus) || !strings.Contains(err.Error(), `"quued"`) {
+		t.Fatalf("expected ListNotifications to reject the unknown status, got %v", err)
+	}
+	if _, err := serviceInstance.ListNotificationsPage(tenantContext(), typo, model.DefaultNotificationListPageRequest()); !errors.Is(err, model.ErrUnknownNotificationStatus) {
+		t.Fatalf("expected ListNotificationsPage to reject the unknown status, got %v", err)
+	}
+	visited := 0
+	streamErr := serviceInstance.StreamNotifications(tenantContext(), typo, func(model.NotificationResponse) error {
+		visited++
+		return nil
+	})
+	if !errors.Is(streamErr, model.ErrUnknownNotificationStatus) || visited != 0 {
+		t.Fatalf("expected StreamNotifications to reject the unknown status, got %v after %d notifications", streamErr, visited)
+	}
+	if _, err := serviceInstance.ListNotificationsAll(context.Background(), typo); !errors.Is(err, model.ErrUnknownNotificationStatus) {
+		t.Fatalf("expected ListNotificationsAll to reject the unknown status, got %v", err)
+	}
+	strict, err := serviceInstance.ListNotifications(tenantContext(), model.NotificationListFilters{Statuses: []model.NotificationStatus{model.StatusSent}})
+	if err != nil || len(strict) != 1 {
+		t.Fatalf("expected known statuses to filter in strict mode, got %d notifications, err=%v", len(strict), err)
+	}
+}
+
 func TestListNotificationsPageSearchesMessageAndPaginates(t *testing.T) {
 	t.Helper()
 
@@ -211,6 +257,38 @@ func TestGetNotificationStatusReturnsStoredRecord(t *testing.T) {
 	}
 }
 
+func TestNotificationLookupsRejectBlankIDs(t *testing.T) {
+	serviceInstance := newNotificationServiceForDomainTests(openIsolatedDatabase(t))
+	ctx := tenantContext()
+	lookups := map[string]func() error{
+		"status": func() error {
+			_, err := serviceInstance.GetNotificationStatus(ctx, " ")
+			return err
+		},
+		"rendered": func() error {
+			_, err := serviceInstance.GetRenderedNotification(ctx, "")
+			return err
+		},
+		"reschedule": func() error {
+			_, err := serviceInstance.RescheduleNotification(ctx, "", time.Now().Add(time.Hour))
+			return err
+		},
+		"cancel": func() error {
+			_, err := serviceInstance.CancelNotification(ctx, "", "", model.CancelActorGRPC)
+			return err
+		},
+		"legal hold": func() error {
+			_, err := serviceInstance.SetLegalHold(ctx, "", true, "counsel@example.com")
+			return err
+		},
+	}
+	for name, lookup := range lookups {
+		if err := lookup(); !errors.Is(err, ErrMissingNotificationID) {
+			t.Fatalf("%s: expected ErrMissingNotificationID, got %v", name, err)
+		}
+	}
+}
+
 func TestListNotificationsAllReturnsRecordsAcrossTenants(t *testing.T) {
 	database := openIsolatedDatabase(t)
 	serviceInstance := newNotificationServiceForDomainTests(database)
@@ -265,7 +343,7 @@ func TestNotificationServiceRequiresTenantContext(t *testing.T) {
 	if _, err := serviceInstance.RescheduleNotification(context.Background(), "notif", time.Now()); !errors.Is(err, ErrMissingTenantContext) {
 		t.Fatalf("expected missing tenant on reschedule, got %v", err)
 	}
-	if _, err := serviceInstance.CancelNotification(context.Background(), "notif"); !errors.Is(err, ErrMissingTenantContext) {
+	if _, err := serviceInstance.CancelNotification(context.Background(), "notif", "", ""); !errors.Is(err, ErrMissingTenantContext) {
 		t.Fatalf("expected missing tenant on cancel, got %v", err)
 	}
 }
@@ -294,7 +372,7 @@ func TestNotificationServicePropagatesStorageErrors(t *testing.T) {
 	if _, err := serviceInstance.RescheduleNotification(tenantContext(), "missing", time.Now()); err == nil {
 		t.Fatalf("expected reschedule storage error")
 	}
-	if _, err := serviceInstance.CancelNotification(tenantContext(), "missing"); err == nil {
+	if _, err := serviceInstance.CancelNotification(tenantContext(), "missing", "", ""); err == nil {
 		t.Fatalf("expected cancel storage error")
 	}
 }
@@ -393,7 +471,7 @@ func TestCancelNotificationTransitionsStatus(t *testing.T) {
 		UpdatedAt:        now,
 	})
 
-	response, err := serviceInstance.CancelNotification(tenantContext(), "notif-cancel")
+	response, err := serviceInstance.CancelNotification(tenantContext(), "notif-cancel", "", "")
 	if err != nil {
 		t.Fatalf("cancel error: %v", err)
 	}
@@ -416,6 +494,45 @@ func TestCancelNotificationTransitionsStatus(t *testing.T) {
 	}
 }
 
+func TestCancelNotificationRecordsReasonAndActor(t *testing.T) {
+	database := openIsolatedDatabase(t)
+	serviceInstance := newNotificationServiceForDomainTests(database)
+
+	now := time.Now().UTC()
+	insertNotificationRecord(t, database, model.Notification{
+		NotificationID:   "notif-cancel-reason",
+		NotificationType: model.NotificationEmail,
+		Recipient:        "user@example.com",
+		Message:          "queued",
+		Status:           model.StatusQueued,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	})
+
+	if _, err := serviceInstance.CancelNotification(tenantContext(), "notif-cancel-reason", strings.Repeat("x", model.MaxCancelReasonLength+1), "ops@example.com"); !errors.Is(err, model.ErrCancelReasonInvalid) {
+		t.Fatalf("expected ErrCancelReasonInvalid for an overlong reason, got %v", err)
+	}
+	if _, err := serviceInstance.CancelNotification(tenantContext(), "notif-cancel-reason", "dup\nreason", "ops@example.com"); !errors.Is(err, model.ErrCancelReasonInvalid) {
+		t.Fatalf("expected ErrCancelReasonInvalid for control characters, got %v", err)
+	}
+
+	response, err := serviceInstance.CancelNotification(tenantContext(), "notif-cancel-reason", "  duplicate  ", "ops@example.com")
+	if err != nil {
+		t.Fatalf("cancel error: %v", err)
+	}
+	if response.CancelReason != "duplicate" || response.CancelledBy != "ops@example.com" || response.CancelledAt == nil {
+		t.Fatalf("unexpected cancellation fields %q %q %v", response.CancelReason, response.CancelledBy, response.CancelledAt)
+	}
+
+	stored, fetchErr := model.GetNotificationByID(tenantContext(), database, testTenantID, "notif-cancel-reason")
+	if fetchErr != nil {
+		t.Fatalf("fetch error: %v", fetchErr)
+	}
+	if stored.CancelReason != "duplicate" || stored.CancelledBy != "ops@example.com" || stored.CancelledAt == nil {
+		t.Fatalf("unexpected stored cancellation fields %q %q %v", stored.CancelReason, stored.CancelledBy, stored.CancelledAt)
+	}
+}
+
 func TestCancelNotificationRejectsNonQueued(t *testing.T) {
 	t.Helper()
 
@@ -433,7 +550,7 @@ func TestCancelNotificationRejectsNonQueued(t *testing.T) {
 		UpdatedAt:        now,
 	})
 
-	if _, err := serviceInstance.CancelNotification(tenantContext(), "notif-sent"); !errors.Is(err, ErrNotificationNotEditable) {
+	if _, err := serviceInstance.CancelNotification(tenantContext(), "notif-sent", "", ""); !errors.Is(err, ErrNotificationNotEditable) {
 		t.Fatalf("expected ErrNotificationNotEditable, got %v", err)
 	}
 }
@@ -453,7 +570,7 @@ func TestRescheduleAndCancelPropagateSaveErrors(t *testing.T) {
 		{
 			name: "cancel",
 			call: func(serviceInstance *notificationServiceImpl, now time.Time) error {
-				_, err := serviceInstance.CancelNotification(tenantContext(), "notif-edit")
+				_, err := serviceInstance.CancelNotification(tenantContext(), "notif-edit", "", "")
 				return err
 			},
 		},
@@ -537,6 +654,66 @@ func TestEmailSenderForTenantUsesRuntimeCredentials(t *testing.T) {
 	if otherSender == sender {
 		t.Fatalf("expected distinct sender instances for different tenants")
 	}
+	if timeouts := smtpSender.Config.Timeouts; timeouts.ConnectionTimeoutSec != 5 || timeouts.OperationTimeoutSec != 5 {
+		t.Fatalf("expected server timeouts without overrides, got %d/%d", timeouts.ConnectionTimeoutSec, timeouts.OperationTimeoutSec)
+	}
+}
+
+func TestEmailSenderForTenantAppliesTimeoutOverrides(t *testing.T) {
+	serviceInstance := &notificationServiceImpl{
+		logger:       slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
+		config:       config.Config{ConnectionTimeoutSec: 5, OperationTimeoutSec: 5},
+		emailSenders: make(map[string]EmailSender),
+		smsSenders:   make(map[string]SmsSender),
+	}
+	slowRelay := tenant.RuntimeConfig{
+		Tenant: tenant.Tenant{ID: "tenant-slow"},
+		Email: tenant.EmailCredentials{
+			Host:                 "relay.slow.example",
+			Port:                 25,
+			Username:             "slow-user",
+			Password:             "slow-pass",
+			FromAddress:          "noreply@slow.example",
+			ConnectionTimeoutSec: 30,
+			OperationTimeoutSec:  120,
+		},
+	}
+	partialOverride := tenant.RuntimeConfig{
+		Tenant: tenant.Tenant{ID: "tenant-partial"},
+		Email: tenant.EmailCredentials{
+			Host:                "smtp.partial.example",
+			Port:                587,
+			Username:            "partial-user",
+			Password:            "partial-pass",
+			FromAddress:         "noreply@partial.example",
+			OperationTimeoutSec: 60,
+		},
+	}
+
+	testCases := []struct {
+		name               string
+		runtime            tenant.RuntimeConfig
+		expectedConnection int
+		expectedOperation  int
+	}{
+		{name: "BothOverridden", runtime: slowRelay, expectedConnection: 30, expectedOperation: 120},
+		{name: "OperationOnly", runtime: partialOverride, expectedConnection: 5, expectedOperation: 60},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			sender, err := serviceInstance.emailSenderForTenant(testCase.runtime)
+			if err != nil {
+				t.Fatalf("email sender resolve error: %v", err)
+			}
+			timeouts := sender.(*SMTPEmailSender).Config.Timeouts
+			if timeouts.ConnectionTimeoutSec != testCase.expectedConnection || timeouts.OperationTimeoutSec != testCase.expectedOperation {
+				t.Fatalf("expected %d/%d, got %d/%d", testCase.expectedConnection, testCase.expectedOperation, timeouts.ConnectionTimeoutSec, timeouts.OperationTimeoutSec)
+			}
+		})
+	}
+	if serviceInstance.config.ConnectionTimeoutSec != 5 || serviceInstance.config.OperationTimeoutSec != 5 {
+		t.Fatalf("tenant overrides leaked into the server config: %+v", serviceInstance.config)
+	}
 }
 
 func TestSmsSenderForTenantUsesRuntimeCredentials(t *testing.T) {
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/glebarez/sqlite"
+	"github.com/tyemirov/pinguin/internal/config"
 	"github.com/tyemirov/pinguin/internal/model"
 	"github.com/tyemirov/utils/scheduler"
 	"gorm.io/gorm"
@@ -98,6 +99,81 @@ func TestSendNotificationRespectsSchedule(t *testing.T) {
 	}
 }
 
+func TestStrictSchedulingRejectsPastTimesOnSendAndReschedule(t *testing.T) {
+	now := time.Date(2040, 6, 1, 12, 0, 0, 0, time.UTC)
+	emailSender := &stubEmailSender{}
+	configuration := config.Config{MaxRetries: 3, RetryIntervalSec: 1, StrictScheduling: true}
+	serviceInstance := NewNotificationServiceWithSenders(openIsolatedDatabase(t), newDiscardLogger(), configuration, nil, emailSender, &stubSmsSender{}, WithClock(&adjustableClock{now: now}))
+
+	past := now.Add(-time.Minute)
+	_, sendErr := serviceInstance.SendNotification(tenantContext(), mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", &past, nil))
+	if !errors.Is(sendErr, ErrScheduleInPast) {
+		t.Fatalf("expected ErrScheduleInPast from send, got %v", sendErr)
+	}
+	if emailSender.callCount != 0 {
+		t.Fatalf("expected no dispatch for a rejected send")
+	}
+
+	future := now.Add(time.Hour)
+	created, err := serviceInstance.SendNotification(tenantContext(), mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", &future, nil))
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	_, rescheduleErr := serviceInstance.RescheduleNotification(tenantContext(), created.NotificationID, past)
+	if !errors.Is(rescheduleErr, ErrScheduleInPast) {
+		t.Fatalf("expected ErrScheduleInPast from reschedule, got %v", rescheduleErr)
+	}
+}
+
+func TestFailOnImmediateErrorReturnsDispatchErrorAndKeepsRecord(t *testing.T) {
+	providerErr := errors.New("smtp unavailable")
+	testCases := []struct {
+		name       string
+		serverFlag bool
+		override   *bool
+		expectErr  bool
+	}{
+		{name: "default persists and returns", expectErr: false},
+		{name: "server flag fails", serverFlag: true, expectErr: true},
+		{name: "request enables", override: boolPointer(true), expectErr: true},
+		{name: "request disables server flag", serverFlag: true, override: boolPointer(false), expectErr: false},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			database := openIsolatedDatabase(t)
+			configuration := config.Config{MaxRetries: 3, RetryIntervalSec: 1, FailOnImmediateError: testCase.serverFlag}
+			serviceInstance := NewNotificationServiceWithSenders(database, newDiscardLogger(), configuration, nil, &stubEmailSender{err: providerErr}, &stubSmsSender{})
+
+			request := mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", nil, nil)
+			if testCase.override != nil {
+				request = request.WithFailOnImmediateError(*testCase.override)
+			}
+			response, err := serviceInstance.SendNotification(tenantContext(), request)
+			if testCase.expectErr {
+				if !errors.Is(err, ErrImmediateDispatchFailed) || !errors.Is(err, providerErr) {
+					t.Fatalf("expected ErrImmediateDispatchFailed wrapping the provider error, got %v", err)
+				}
+			} else if err != nil {
+				t.Fatalf("expected the failure to be absorbed, got %v", err)
+			}
+			if response.NotificationID == "" || response.Status != model.StatusErrored {
+				t.Fatalf("expected the errored notification in the response, got %+v", response)
+			}
+			stored, err := model.MustGetNotificationByID(context.Background(), database, testTenantID, response.NotificationID)
+			if err != nil {
+				t.Fatalf("expected the notification persisted for retry: %v", err)
+			}
+			if stored.Status != model.StatusErrored {
+				t.Fatalf("expected the stored notification errored, got %s", stored.Status)
+			}
+		})
+	}
+}
+
+func boolPointer(value bool) *bool {
+	return &value
+}
+
 func TestRetryWorkerRespectsSchedule(t *testing.T) {
 	t.Helper()
 
@@ -461,13 +537,15 @@ func (sender *stubEmailSender) SendEmail(_ context.Context, _ string, _ string,
 }
 
 type stubSmsSender struct {
-	callCount  int
-	providerID string
-	err        error
+	callCount   int
+	providerID  string
+	err         error
+	lastMessage string
 }
 
-func (sender *stubSmsSender) SendSms(_ context.Context, _ string, _ string) (string, error) {
+func (sender *stubSmsSender) SendSms(_ context.Context, _ string, message string) (string, error) {
 	sender.callCount++
+	sender.lastMessage = message
 	if sender.err != nil {
 		return "", sender.err
 	}
@@ -502,7 +580,7 @@ func mustNotificationRequest(
 	return request
 }
 
-func openIsolatedDatabase(t *testing.T) *gorm.DB {
+func openIsolatedDatabase(t testing.TB) *gorm.DB {
 	t.Helper()
 
 	databaseName := time.Now().UTC().Format("20060102150405.000000000")
@@ -510,7 +588,7 @@ func openIsolatedDatabase(t *testing.T) *gorm.DB {
 	if openError != nil {
 		t.Fatalf("sqlite open error: %v", openError)
 	}
-	if migrateError := database.AutoMigrate(&model.Notification{}, &model.NotificationAttachment{}); migrateError != nil {
+	if migrateError := database.AutoMigrate(&model.Notification{}, &model.NotificationAttachment{}, &model.AttachmentBlob{}, &model.ReportRun{}, &model.WorkerCheckpoint{}, &model.WebhookDelivery{}, &model.RenderedContent{}, &model.ProviderBreaker{}, &model.QueueIntakeKey{}, &model.NotificationOpen{}); migrateError != nil {
 		t.Fatalf("migration error: %v", migrateError)
 	}
 	return database
@@ -530,5 +608,7 @@ func newNotificationServiceWithSendersForSchedulerTests(database *gorm.DB, email
 		retryIntervalSec:   1,
 		emailSenders:       make(map[string]EmailSender),
 		smsSenders:         make(map[string]SmsSender),
+		dispatchLatency:    newDispatchLatencyRecorder(),
+		attachmentSizes:    newAttachmentSizeRecorder(),
 	}
 }
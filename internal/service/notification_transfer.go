@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+)
+
+const (
+	transferDirectionOut = "out"
+	transferDirectionIn  = "in"
+)
+
+// ErrTransferSameTenant rejects a transfer whose source and destination are one tenant.
+var ErrTransferSameTenant = errors.New("source and destination tenant must differ")
+
+// NotificationTransferRequest selects the notifications to move from one tenant to another.
+type NotificationTransferRequest struct {
+	SourceTenantID      string
+	DestinationTenantID string
+	Filters             model.NotificationListFilters
+	// IncludeQueued also moves notifications still awaiting dispatch, once the destination
+	// tenant is found able to send them. They would otherwise go out with the destination's
+	// credentials without anyone checking first.
+	IncludeQueued bool
+	// Actor names who asked for the transfer in the audit log.
+	Actor string
+}
+
+// SkippedTransfer names a matching notification that stayed in the source tenant.
+type SkippedTransfer struct {
+	NotificationID string
+	Reason         string
+}
+
+// NotificationTransferResult reports what a transfer moved. Renamed lists the
+// notifications that took a new id because the destination already used theirs.
+type NotificationTransferResult struct {
+	Transferred int64
+	Renamed     []model.NotificationIDRename
+	Skipped     []SkippedTransfer
+}
+
+// TransferNotifications moves the source tenant's matching notifications, with their
+// attachments and rendered content, to the destination tenant in batched transactions.
+// When a batch fails the batches before it stay moved and are reported with the error.
+// Both tenants get an audit log entry either way.
+func (serviceInstance *notificationServiceImpl) TransferNotifications(ctx context.Context, request NotificationTransferRequest) (NotificationTransferResult, error) {
+	if serviceInstance.tenantRepo == nil {
+		return NotificationTransferResult{}, ErrTenantInventoryUnavailable
+	}
+	if err := serviceInstance.checkStatusFilters(request.Filters); err != nil {
+		return NotificationTransferResult{}, err
+	}
+	source, err := serviceInstance.tenantRepo.ResolveByID(ctx, request.SourceTenantID)
+	if err != nil {
+		return NotificationTransferResult{}, err
+	}
+	destination, err := serviceInstance.tenantRepo.ResolveByID(ctx, request.DestinationTenantID)
+	if err != nil {
+		return NotificationTransferResult{}, err
+	}
+	if source.Tenant.ID == destination.Tenant.ID {
+		return NotificationTransferResult{}, ErrTransferSameTenant
+	}
+
+	result, err := serviceInstance.transferBatches(ctx, request, source, destination)
+	serviceInstance.auditTransfer(request, source.Tenant.ID, destination.Tenant.ID, result)
+	if err != nil {
+		serviceInstance.logger.Error("Failed to transfer notifications", "source_tenant_id", source.Tenant.ID, "destination_tenant_id", destination.Tenant.ID, "transferred", result.Transferred, "error", err)
+		return result, err
+	}
+	return result, nil
+}
+
+func (serviceInstance *notificationServiceImpl) transferBatches(ctx context.Context, request NotificationTransferRequest, source tenant.RuntimeConfig, destination tenant.RuntimeConfig) (NotificationTransferResult, error) {
+	var result NotificationTransferResult
+	newID := func() (string, error) {
+		return serviceInstance.newNotificationID(destination)
+	}
+	var afterID uint
+	for {
+		batch, err := model.ListNotificationTransferBatch(ctx, serviceInstance.database, source.Tenant.ID, request.Filters, afterID, model.NotificationTransferBatchSize)
+		if err != nil {
+			return result, err
+		}
+		if len(batch) == 0 {
+			return result, nil
+		}
+		afterID = batch[len(batch)-1].ID
+		movable := make([]model.Notification, 0, len(batch))
+		for _, record := range batch {
+			if reason := serviceInstance.transferBlocker(record, destination, request.IncludeQueued); reason != "" {
+				result.Skipped = append(result.Skipped, SkippedTransfer{NotificationID: record.NotificationID, Reason: reason})
+				continue
+			}
+			movable = append(movable, record)
+		}
+		renames, err := model.MoveNotifications(ctx, serviceInstance.database, source.Tenant.ID, destination.Tenant.ID, movable, newID)
+		if err != nil {
+			return result, err
+		}
+		result.Transferred += int64(len(movable))
+		result.Renamed = append(result.Renamed, renames...)
+		if len(batch) < model.NotificationTransferBatchSize {
+			return result, nil
+		}
+	}
+}
+
+// transferBlocker explains why record must stay in the source tenant, or returns "".
+// A notification the retry worker would still dispatch moves only with includeQueued,
+// and only when the destination tenant has a sender for its channel.
+func (serviceInstance *notificationServiceImpl) transferBlocker(record model.Notification, destination tenant.RuntimeConfig, includeQueued bool) string {
+	if !serviceInstance.awaitsDispatch(record) {
+		return ""
+	}
+	if !includeQueued {
+		return "awaiting dispatch; set include_queued to move it"
+	}
+	var senderErr error
+	switch record.NotificationType {
+	case model.NotificationEmail:
+		_, senderErr = serviceInstance.emailSenderForTenant(destination)
+	case model.NotificationSMS:
+		_, senderErr = serviceInstance.smsSenderForTenant(destination)
+	default:
+		senderErr = model.ErrNotificationTypeUnsupported
+	}
+	if senderErr != nil {
+		return "destination tenant cannot send it: " + senderErr.Error()
+	}
+	return ""
+}
+
+// awaitsDispatch reports whether the retry worker may still send record: it is queued,
+// or errored with retries left and not given up on.
+func (serviceInstance *notificationServiceImpl) awaitsDispatch(record model.Notification) bool {
+	switch record.Status {
+	case model.StatusQueued:
+		return true
+	case model.StatusErrored:
+		return record.LastError == "" && record.RetryCount < serviceInstance.maxRetries
+	default:
+		return false
+	}
+}
+
+func (serviceInstance *notificationServiceImpl) auditTransfer(request NotificationTransferRequest, sourceTenantID string, destinationTenantID string, result NotificationTransferResult) {
+	for _, entry := range []struct {
+		tenantID      string
+		counterpartID string
+		direction     string
+	}{
+		{tenantID: sourceTenantID, counterpartID: destinationTenantID, direction: transferDirectionOut},
+		{tenantID: destinationTenantID, counterpartID: sourceTenantID, direction: transferDirectionIn},
+	} {
+		serviceInstance.logger.Info("audit_notifications_transferred",
+			"tenant_id", entry.tenantID,
+			"direction", entry.direction,
+			"counterpart_tenant_id", entry.counterpartID,
+			"transferred", result.Transferred,
+			"renamed", len(result.Renamed),
+			"skipped", len(result.Skipped),
+			"include_queued", request.IncludeQueued,
+			"actor", request.Actor,
+		)
+	}
+	for _, rename := range result.Renamed {
+		serviceInstance.logger.Info("audit_notification_id_remapped",
+			"tenant_id", destinationTenantID,
+			"notification_id", rename.DestinationNotificationID,
+			"source_tenant_id", sourceTenantID,
+			"source_notification_id", rename.SourceNotificationID,
+			"actor", request.Actor,
+		)
+	}
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+	"gorm.io/gorm"
+)
+
+func newTransferTestService(t *testing.T) (*notificationServiceImpl, *gorm.DB, *bytes.Buffer) {
+	t.Helper()
+	database := openIsolatedDatabase(t)
+	if err := database.AutoMigrate(&tenant.Tenant{}, &tenant.TenantDomain{}, &tenant.TenantAdmin{}, &tenant.EmailProfile{}, &tenant.SMSProfile{}); err != nil {
+		t.Fatalf("tenant migration: %v", err)
+	}
+	// Databases created before the unique index may hold one id under several tenants.
+	if err := database.Migrator().DropIndex(&model.Notification{}, "idx_tenant_notification"); err != nil {
+		t.Fatalf("drop unique index: %v", err)
+	}
+	keeper, err := tenant.NewSecretKeeper(strings.Repeat("a", 64))
+	if err != nil {
+		t.Fatalf("secret keeper: %v", err)
+	}
+	emailProfile := func(host string) tenant.BootstrapEmailProfile {
+		return tenant.BootstrapEmailProfile{Host: host, Port: 587, Username: "smtp-user", Password: "smtp-pass", FromAddress: "noreply@" + host}
+	}
+	if err := tenant.Bootstrap(context.Background(), database, keeper, tenant.BootstrapConfig{
+		Tenants: []tenant.BootstrapTenant{
+			{
+				ID:           "tenant-old",
+				DisplayName:  "Old Org",
+				SupportEmail: "support@old.example",
+				Enabled:      ptrBool(true),
+				Domains:      []string{"old.example"},
+				EmailProfile: emailProfile("smtp.old.example"),
+				SMSProfile:   &tenant.BootstrapSMSProfile{AccountSID: "AC123", AuthToken: "token", FromNumber: "+15555550100"},
+			},
+			{
+				ID:           "tenant-new",
+				DisplayName:  "New Org",
+				SupportEmail: "support@new.example",
+				Enabled:      ptrBool(true),
+				Domains:      []string{"new.example"},
+				EmailProfile: emailProfile("smtp.new.example"),
+			},
+		},
+	}); err != nil {
+		t.Fatalf("bootstrap: %v", err)
+	}
+	// No default senders, so queued notifications are checked against the destination's profiles.
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(database, nil, nil)
+	serviceInstance.tenantRepo = tenant.NewRepository(database, keeper)
+	var logs bytes.Buffer
+	serviceInstance.logger = slog.New(slog.NewTextHandler(&logs, nil))
+	return serviceInstance, database, &logs
+}
+
+func TestTransferNotificationsMovesFinishedNotificationsAndSkipsQueued(t *testing.T) {
+	serviceInstance, database, logs := newTransferTestService(t)
+	ctx := context.Background()
+	for _, record := range []model.Notification{
+		{TenantID: "tenant-old", NotificationID: "shared", NotificationType: model.NotificationEmail, Recipient: "a@example.com", Message: "Body", Status: model.StatusSent},
+		{TenantID: "tenant-old", NotificationID: "queued-email", NotificationType: model.NotificationEmail, Recipient: "b@example.com", Message: "Body", Status: model.StatusQueued},
+		{TenantID: "tenant-old", NotificationID: "queued-sms", NotificationType: model.NotificationSMS, Recipient: "+15555550101", Message: "Body", Status: model.StatusQueued},
+		{TenantID: "tenant-old", NotificationID: "retrying", NotificationType: model.NotificationEmail, Recipient: "c@example.com", Message: "Body", Status: model.StatusErrored, RetryCount: 1},
+		{TenantID: "tenant-old", NotificationID: "given-up", NotificationType: model.NotificationEmail, Recipient: "d@example.com", Message: "Body", Status: model.StatusErrored, RetryCount: 5, LastError: "mailbox unavailable"},
+		{TenantID: "tenant-old", NotificationID: "cancelled", NotificationType: model.NotificationSMS, Recipient: "+15555550102", Message: "Body", Status: model.StatusCancelled},
+		{TenantID: "tenant-new", NotificationID: "shared", NotificationType: model.NotificationEmail, Recipient: "e@example.com", Message: "Body", Status: model.StatusSent},
+	} {
+		insertNotificationRecord(t, database, record)
+	}
+
+	result, err := serviceInstance.TransferNotifications(ctx, NotificationTransferRequest{SourceTenantID: "tenant-old", DestinationTenantID: "tenant-new", Actor: "grpc"})
+	if err != nil {
+		t.Fatalf("transfer: %v", err)
+	}
+	if result.Transferred != 3 || len(result.Skipped) != 3 {
+		t.Fatalf("expected three moved and three skipped, got %+v", result)
+	}
+	for _, skipped := range result.Skipped {
+		if !strings.Contains(skipped.Reason, "include_queued") {
+			t.Fatalf("expected pending notifications to need include_queued, got %+v", skipped)
+		}
+	}
+	if len(result.Renamed) != 1 || result.Renamed[0].SourceNotificationID != "shared" || result.Renamed[0].DestinationNotificationID == "shared" {
+		t.Fatalf("expected the colliding id to be renamed, got %+v", result.Renamed)
+	}
+
+	oldContext := runtimeContext(t, serviceInstance, "tenant-old")
+	newContext := runtimeContext(t, serviceInstance, "tenant-new")
+	if _, err := serviceInstance.GetNotificationStatus(oldContext, "cancelled"); err == nil {
+		t.Fatalf("expected the moved notification to be gone from the source tenant")
+	}
+	if moved, err := serviceInstance.GetNotificationStatus(newContext, result.Renamed[0].DestinationNotificationID); err != nil || moved.Recipient != "a@example.com" {
+		t.Fatalf("expected the renamed notification in the destination, got %+v err=%v", moved, err)
+	}
+	if own, err := serviceInstance.GetNotificationStatus(newContext, "shared"); err != nil || own.Recipient != "e@example.com" {
+		t.Fatalf("expected the destination's own notification untouched, got %+v err=%v", own, err)
+	}
+	if remaining, err := serviceInstance.ListNotifications(oldContext, model.NotificationListFilters{}); err != nil || len(remaining) != 3 {
+		t.Fatalf("expected the pending notifications to stay in the source, got %d err=%v", len(remaining), err)
+	}
+
+	result, err = serviceInstance.TransferNotifications(ctx, NotificationTransferRequest{SourceTenantID: "tenant-old", DestinationTenantID: "tenant-new", IncludeQueued: true, Actor: "grpc"})
+	if err != nil {
+		t.Fatalf("transfer including queued: %v", err)
+	}
+	if result.Transferred != 2 || len(result.Skipped) != 1 || result.Skipped[0].NotificationID != "queued-sms" || !strings.Contains(result.Skipped[0].Reason, "cannot send") {
+		t.Fatalf("expected queued email to move and queued sms to stay, got %+v", result)
+	}
+	if remaining, err := serviceInstance.ListNotifications(oldContext, model.NotificationListFilters{}); err != nil || len(remaining) != 1 || remaining[0].NotificationID != "queued-sms" {
+		t.Fatalf("expected only the undeliverable sms in the source, got %+v err=%v", remaining, err)
+	}
+	if moved, err := serviceInstance.ListNotifications(newContext, model.NotificationListFilters{}); err != nil || len(moved) != 6 {
+		t.Fatalf("expected six notifications in the destination, got %d err=%v", len(moved), err)
+	}
+
+	auditLog := logs.String()
+	for _, expected := range []string{
+		"audit_notifications_transferred tenant_id=tenant-old direction=out counterpart_tenant_id=tenant-new transferred=3",
+		"audit_notifications_transferred tenant_id=tenant-new direction=in counterpart_tenant_id=tenant-old transferred=3",
+		"audit_notification_id_remapped tenant_id=tenant-new",
+	} {
+		if !strings.Contains(auditLog, expected) {
+			t.Fatalf("expected %q in audit log:\n%s", expected, auditLog)
+		}
+	}
+}
+
+func TestTransferNotificationsRejectsInvalidRequests(t *testing.T) {
+	serviceInstance, _, _ := newTransferTestService(t)
+	ctx := context.Background()
+	if _, err := serviceInstance.TransferNotifications(ctx, NotificationTransferRequest{SourceTenantID: "tenant-old", DestinationTenantID: "tenant-old"}); !errors.Is(err, ErrTransferSameTenant) {
+		t.Fatalf("expected same tenant error, got %v", err)
+	}
+	if _, err := serviceInstance.TransferNotifications(ctx, NotificationTransferRequest{SourceTenantID: "tenant-old", DestinationTenantID: "tenant-missing"}); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected unknown tenant error, got %v", err)
+	}
+	serviceInstance.config.StrictStatusFilters = true
+	if _, err := serviceInstance.TransferNotifications(ctx, NotificationTransferRequest{SourceTenantID: "tenant-old", DestinationTenantID: "tenant-new", Filters: model.NotificationListFilters{Statuses: []model.NotificationStatus{"delivered"}}}); !errors.Is(err, model.ErrUnknownNotificationStatus) {
+		t.Fatalf("expected unknown status error, got %v", err)
+	}
+	serviceInstance.tenantRepo = nil
+	if _, err := serviceInstance.TransferNotifications(ctx, NotificationTransferRequest{SourceTenantID: "tenant-old", DestinationTenantID: "tenant-new"}); !errors.Is(err, ErrTenantInventoryUnavailable) {
+		t.Fatalf("expected inventory error, got %v", err)
+	}
+}
+
+func runtimeContext(t *testing.T, serviceInstance *notificationServiceImpl, tenantID string) context.Context {
+	t.Helper()
+	runtimeCfg, err := serviceInstance.tenantRepo.ResolveByID(context.Background(), tenantID)
+	if err != nil {
+		t.Fatalf("resolve %s: %v", tenantID, err)
+	}
+	return tenant.WithRuntime(context.Background(), runtimeCfg)
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/config"
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/opentracking"
+	"github.com/tyemirov/pinguin/internal/tenant"
+)
+
+// newOpenTrackingPixels builds the pixel issuer when web.openTrackingBaseURL is set;
+// without it no email carries a pixel, whatever the tenants opted in to.
+func newOpenTrackingPixels(cfg config.Config) *opentracking.Pixels {
+	if cfg.OpenTrackingBaseURL == "" {
+		return nil
+	}
+	ttlHours := cfg.OpenTrackingTokenTTLHours
+	if ttlHours <= 0 {
+		ttlHours = config.DefaultOpenTrackingTokenTTLHours
+	}
+	return opentracking.NewPixels(opentracking.NewSigner(cfg.MasterEncryptionKey), cfg.OpenTrackingBaseURL, time.Duration(ttlHours)*time.Hour)
+}
+
+// trackedEmailBody returns the body an email goes out with: the stored message, plus an
+// open tracking pixel when the tenant opted in and the message is an HTML document.
+// The stored message itself never carries the pixel, and neither does encrypted content.
+func (serviceInstance *notificationServiceImpl) trackedEmailBody(runtimeCfg tenant.RuntimeConfig, record *model.Notification) string {
+	notificationID, message := record.NotificationID, record.Message
+	if record.ContentEncrypted || serviceInstance.openTrackingPixels == nil || !runtimeCfg.Tenant.OpenTracking || !model.IsHTMLMessage(message) {
+		return message
+	}
+	pixelURL := serviceInstance.openTrackingPixels.URL(runtimeCfg.Tenant.ID, notificationID, serviceInstance.currentTime())
+	return opentracking.Inject(message, pixelURL)
+}
+
+// attachOpens adds the open summary to a detail response for tenants that track opens.
+func (serviceInstance *notificationServiceImpl) attachOpens(ctx context.Context, runtimeCfg tenant.RuntimeConfig, response *model.NotificationResponse) error {
+	if !runtimeCfg.Tenant.OpenTracking || response.NotificationType != model.NotificationEmail {
+		return nil
+	}
+	summary, err := model.SummarizeNotificationOpens(ctx, serviceInstance.database, runtimeCfg.Tenant.ID, response.NotificationID)
+	if err != nil {
+		return err
+	}
+	response.Opens = &summary
+	return nil
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/config"
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+)
+
+// bodyRecordingEmailSender keeps the body of every email it is handed.
+type bodyRecordingEmailSender struct {
+	bodies []string
+}
+
+func (sender *bodyRecordingEmailSender) SendEmail(_ context.Context, _ string, _ string, body string, _ []model.EmailAttachment) error {
+	sender.bodies = append(sender.bodies, body)
+	return nil
+}
+
+const openTrackingHTMLBody = "<!DOCTYPE html><html><body><p>Hello</p></body></html>"
+
+func newOpenTrackingTestService(t *testing.T, baseURL string) (*notificationServiceImpl, *bodyRecordingEmailSender) {
+	t.Helper()
+	emailSender := &bodyRecordingEmailSender{}
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), emailSender, &stubSmsSender{})
+	serviceInstance.openTrackingPixels = newOpenTrackingPixels(config.Config{OpenTrackingBaseURL: baseURL, MasterEncryptionKey: strings.Repeat("a", 64)})
+	return serviceInstance, emailSender
+}
+
+func openTrackingContext(enabled bool) context.Context {
+	runtimeCfg := baseRuntimeConfig()
+	runtimeCfg.Tenant.OpenTracking = enabled
+	return tenant.WithRuntime(context.Background(), runtimeCfg)
+}
+
+func TestSendNotificationAddsPixelForOptedInHTMLEmail(t *testing.T) {
+	serviceInstance, emailSender := newOpenTrackingTestService(t, "https://mail.example.com")
+	ctx := openTrackingContext(true)
+
+	response, err := serviceInstance.SendNotification(ctx, mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", openTrackingHTMLBody, nil, nil))
+	if err != nil {
+		t.Fatalf("SendNotification error: %v", err)
+	}
+	if len(emailSender.bodies) != 1 || !strings.Contains(emailSender.bodies[0], `<img src="https://mail.example.com/t/`) {
+		t.Fatalf("expected the sent body to carry the pixel, got %q", emailSender.bodies)
+	}
+	if response.Message != openTrackingHTMLBody {
+		t.Fatalf("expected the stored message to stay without the pixel, got %q", response.Message)
+	}
+
+	detailed, err := serviceInstance.GetRenderedNotification(ctx, response.NotificationID)
+	if err != nil {
+		t.Fatalf("GetRenderedNotification error: %v", err)
+	}
+	if detailed.Opens == nil || detailed.Opens.OpenCount != 0 || detailed.Opens.FirstOpenedAt != nil {
+		t.Fatalf("expected an empty open summary before any open, got %+v", detailed.Opens)
+	}
+	firstOpen := time.Date(2026, time.March, 1, 9, 0, 0, 0, time.UTC)
+	if err := model.SaveNotificationOpens(ctx, serviceInstance.database, []model.NotificationOpen{
+		{TenantID: testTenantID, NotificationID: response.NotificationID, OpenedAt: firstOpen.Add(time.Hour)},
+		{TenantID: testTenantID, NotificationID: response.NotificationID, OpenedAt: firstOpen},
+	}); err != nil {
+		t.Fatalf("save opens: %v", err)
+	}
+	detailed, err = serviceInstance.GetRenderedNotification(ctx, response.NotificationID)
+	if err != nil {
+		t.Fatalf("GetRenderedNotification error: %v", err)
+	}
+	opens := detailed.Opens
+	if opens == nil || opens.OpenCount != 2 || !opens.FirstOpenedAt.Equal(firstOpen) || !opens.LastOpenedAt.Equal(firstOpen.Add(time.Hour)) {
+		t.Fatalf("unexpected open summary %+v", opens)
+	}
+}
+
+func TestSendNotificationLeavesEmailUntrackedByDefault(t *testing.T) {
+	testCases := []struct {
+		name    string
+		baseURL string
+		optIn   bool
+		body    string
+	}{
+		{name: "tenant not opted in", baseURL: "https://mail.example.com", body: openTrackingHTMLBody},
+		{name: "plain text body", baseURL: "https://mail.example.com", optIn: true, body: "Hello"},
+		{name: "no base URL", optIn: true, body: openTrackingHTMLBody},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			serviceInstance, emailSender := newOpenTrackingTestService(t, testCase.baseURL)
+			ctx := openTrackingContext(testCase.optIn)
+			response, err := serviceInstance.SendNotification(ctx, mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", testCase.body, nil, nil))
+			if err != nil {
+				t.Fatalf("SendNotification error: %v", err)
+			}
+			if len(emailSender.bodies) != 1 || emailSender.bodies[0] != testCase.body {
+				t.Fatalf("expected the body to go out unchanged, got %q", emailSender.bodies)
+			}
+			if testCase.optIn {
+				return
+			}
+			detailed, err := serviceInstance.GetRenderedNotification(ctx, response.NotificationID)
+			if err != nil || detailed.Opens != nil {
+				t.Fatalf("expected no open summary without opt-in, got %+v (%v)", detailed.Opens, err)
+			}
+		})
+	}
+}
+
+func TestListTenantsStatusCountsOpenedNotifications(t *testing.T) {
+	serviceInstance, _, database := newDailyReportTestService(t, nil, nil)
+	opens := []model.NotificationOpen{
+		{TenantID: "tenant-report", NotificationID: "notif-1", OpenedAt: time.Now().UTC()},
+		{TenantID: "tenant-report", NotificationID: "notif-1", OpenedAt: time.Now().UTC()},
+		{TenantID: "tenant-report", NotificationID: "notif-2", OpenedAt: time.Now().UTC()},
+	}
+	if err := model.SaveNotificationOpens(context.Background(), database, opens); err != nil {
+		t.Fatalf("save opens: %v", err)
+	}
+
+	statuses, err := serviceInstance.ListTenantsStatus(context.Background())
+	if err != nil || len(statuses) != 1 || statuses[0].OpenedCount != 0 {
+		t.Fatalf("expected no opened count without opt-in, got %+v (%v)", statuses, err)
+	}
+
+	if err := database.Model(&tenant.Tenant{}).Where(&tenant.Tenant{ID: "tenant-report"}).Update("open_tracking", true).Error; err != nil {
+		t.Fatalf("enable open tracking: %v", err)
+	}
+	statuses, err = serviceInstance.ListTenantsStatus(context.Background())
+	if err != nil || len(statuses) != 1 || statuses[0].OpenedCount != 2 {
+		t.Fatalf("expected two opened notifications, got %+v (%v)", statuses, err)
+	}
+}
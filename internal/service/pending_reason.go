@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+)
+
+// pendingReason explains why a notification the retry worker will still send has not
+// gone out at now. It is empty once no further attempt will be made. The checks follow
+// the order the worker applies them: the schedule, the provider breaker, the retry
+// backoff, then dispatch pacing.
+func (serviceInstance *notificationServiceImpl) pendingReason(ctx context.Context, record model.Notification, now time.Time) model.PendingReason {
+	canonical := record
+	canonical.Status = model.CanonicalStatus(record.Status)
+	if !serviceInstance.awaitsDispatch(canonical) {
+		return ""
+	}
+	switch {
+	case record.ScheduledFor != nil && now.Before(*record.ScheduledFor):
+		return model.PendingReasonScheduled
+	case serviceInstance.providerBreakers.isOpen(ctx, record.TenantID, record.NotificationType):
+		return model.PendingReasonCircuitOpen
+	case serviceInstance.retryPendingAt(canonical).After(now):
+		return model.PendingReasonAwaitingRetry
+	case serviceInstance.dispatchPacer.holding(record.TenantID, record.NotificationType, now):
+		return model.PendingReasonRateLimited
+	default:
+		return model.PendingReasonDue
+	}
+}
+
+// retryPendingAt is when a failed notification's backoff ends: the stored next attempt
+// when the retry worker recorded one, otherwise the backoff after its last attempt.
+// It is the zero time for notifications that never failed.
+func (serviceInstance *notificationServiceImpl) retryPendingAt(record model.Notification) time.Time {
+	if record.NextAttemptAt != nil {
+		return *record.NextAttemptAt
+	}
+	if record.Status != model.StatusErrored || record.LastAttemptedAt.IsZero() {
+		return time.Time{}
+	}
+	return retryBackoffAt(record.LastAttemptedAt, record.RetryCount, time.Duration(serviceInstance.retryIntervalSec)*time.Second)
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+)
+
+func TestGetNotificationStatusReportsPendingReason(t *testing.T) {
+	now := time.Now().UTC()
+	future := now.Add(time.Hour)
+	recentAttempt := now.Add(-100 * time.Millisecond)
+	testCases := []struct {
+		name   string
+		record model.Notification
+		want   model.PendingReason
+	}{
+		{
+			name:   "scheduled for later",
+			record: model.Notification{Status: model.StatusQueued, ScheduledFor: &future},
+			want:   model.PendingReasonScheduled,
+		},
+		{
+			name:   "failed send waiting out its backoff",
+			record: model.Notification{Status: model.StatusErrored, RetryCount: 1, LastAttemptedAt: recentAttempt},
+			want:   model.PendingReasonAwaitingRetry,
+		},
+		{
+			name:   "retry worker recorded the next attempt",
+			record: model.Notification{Status: model.StatusErrored, RetryCount: 1, NextAttemptAt: &future},
+			want:   model.PendingReasonAwaitingRetry,
+		},
+		{
+			name:   "queued and due",
+			record: model.Notification{Status: model.StatusQueued},
+			want:   model.PendingReasonDue,
+		},
+		{
+			name:   "sent",
+			record: model.Notification{Status: model.StatusSent},
+		},
+		{
+			name:   "retries exhausted",
+			record: model.Notification{Status: model.StatusErrored, RetryCount: 3, LastAttemptedAt: recentAttempt},
+		},
+		{
+			name:   "permanent failure",
+			record: model.Notification{Status: model.StatusErrored, RetryCount: 1, LastError: "invalid recipient"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			database := openIsolatedDatabase(t)
+			serviceInstance := newNotificationServiceForDomainTests(database)
+			serviceInstance.retryIntervalSec = 60
+			record := testCase.record
+			record.NotificationID = "notif-pending"
+			record.NotificationType = model.NotificationEmail
+			record.Recipient = "user@example.com"
+			insertNotificationRecord(t, database, record)
+
+			response, err := serviceInstance.GetNotificationStatus(tenantContext(), "notif-pending")
+			if err != nil {
+				t.Fatalf("GetNotificationStatus error: %v", err)
+			}
+			if response.PendingReason != testCase.want {
+				t.Fatalf("expected pending reason %q, got %q", testCase.want, response.PendingReason)
+			}
+		})
+	}
+}
+
+func TestGetNotificationStatusReportsProviderHolds(t *testing.T) {
+	database := openIsolatedDatabase(t)
+	serviceInstance := newNotificationServiceForDomainTests(database)
+	insertNotificationRecord(t, database, model.Notification{NotificationID: "notif-email", NotificationType: model.NotificationEmail, Recipient: "user@example.com", Status: model.StatusQueued})
+	insertNotificationRecord(t, database, model.Notification{NotificationID: "notif-sms", NotificationType: model.NotificationSMS, Recipient: "+15555550100", Status: model.StatusQueued})
+	ctx := tenantContext()
+	now := time.Now().UTC()
+
+	serviceInstance.providerBreakers = newProviderBreakers(database, time.Minute, serviceInstance.logger)
+	serviceInstance.providerBreakers.record(ctx, testTenantID, model.NotificationSMS, &ProviderAccountError{Err: &TwilioAPIError{StatusCode: 401, Code: twilioCodeAuthenticationFailed}}, now)
+	serviceInstance.dispatchPacer = newTestDispatchPacer()
+	serviceInstance.dispatchPacer.entry(dispatchPacingKey{tenantID: testTenantID, provider: model.NotificationEmail}).delay = time.Hour
+	serviceInstance.dispatchPacer.admit(testTenantID, model.NotificationEmail, now)
+
+	for notificationID, want := range map[string]model.PendingReason{
+		"notif-sms":   model.PendingReasonCircuitOpen,
+		"notif-email": model.PendingReasonRateLimited,
+	} {
+		response, err := serviceInstance.GetNotificationStatus(ctx, notificationID)
+		if err != nil {
+			t.Fatalf("GetNotificationStatus(%s) error: %v", notificationID, err)
+		}
+		if response.PendingReason != want {
+			t.Fatalf("expected %s to report %q, got %q", notificationID, want, response.PendingReason)
+		}
+	}
+}
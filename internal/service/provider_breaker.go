@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"gorm.io/gorm"
+)
+
+const (
+	providerBreakerNoticeSubjectFormat = "Pinguin paused %s delivery for %s"
+	providerBreakerNoticeBodyFormat    = "Pinguin stopped sending %s notifications for %s because the provider rejected the account:\n\n  %s\n\n" +
+		"Queued notifications are kept. Pinguin retries one of them every %s and resumes delivery as soon as the provider accepts it.\n"
+)
+
+// ProviderAccountError reports that a provider refused the tenant's account itself,
+// such as rejected credentials or a suspended account. Retrying other messages cannot
+// succeed until the account is fixed, so it opens the tenant's breaker for the channel.
+type ProviderAccountError struct {
+	Err error
+}
+
+func (accountError *ProviderAccountError) Error() string {
+	return fmt.Sprintf("provider account failure: %v", accountError.Err)
+}
+
+func (accountError *ProviderAccountError) Unwrap() error {
+	return accountError.Err
+}
+
+// ProviderBreakerState reports a tenant channel whose dispatch is paused.
+type ProviderBreakerState struct {
+	Provider       model.NotificationType `json:"provider"`
+	Reason         string                 `json:"reason"`
+	OpenedAt       time.Time              `json:"opened_at"`
+	LastProbeAt    *time.Time             `json:"last_probe_at,omitempty"`
+	NextProbeAt    time.Time              `json:"next_probe_at"`
+	AdminsNotified bool                   `json:"admins_notified"`
+}
+
+type providerBreakerKey struct {
+	tenantID string
+	channel  model.NotificationType
+}
+
+// providerBreakers pauses a tenant's channel after its provider reports an account-level
+// failure. While a breaker is open every dispatch for the channel is skipped except one
+// canary per probe interval; the canary's success closes the breaker. Open breakers are
+// stored in provider_breakers so a restart keeps them, and are cached once loaded.
+type providerBreakers struct {
+	mutex         sync.Mutex
+	database      *gorm.DB
+	probeInterval time.Duration
+	logger        *slog.Logger
+	loaded        bool
+	open          map[providerBreakerKey]*model.ProviderBreaker
+}
+
+func newProviderBreakers(database *gorm.DB, probeInterval time.Duration, logger *slog.Logger) *providerBreakers {
+	return &providerBreakers{
+		database:      database,
+		probeInterval: probeInterval,
+		logger:        logger,
+		open:          make(map[providerBreakerKey]*model.ProviderBreaker),
+	}
+}
+
+// admit reports whether a dispatch to the tenant's channel may start now. An open
+// breaker admits only the first dispatch of each probe interval, as a canary.
+func (breakers *providerBreakers) admit(ctx context.Context, tenantID string, channel model.NotificationType, now time.Time) bool {
+	if breakers == nil {
+		return true
+	}
+	breakers.mutex.Lock()
+	defer breakers.mutex.Unlock()
+	breakers.ensureLoaded(ctx)
+	breaker, isOpen := breakers.open[providerBreakerKey{tenantID: tenantID, channel: channel}]
+	if !isOpen {
+		return true
+	}
+	if now.Before(breaker.NextProbeAt(breakers.probeInterval)) {
+		return false
+	}
+	probeAt := now.UTC()
+	breaker.LastProbeAt = &probeAt
+	breaker.UpdatedAt = probeAt
+	if err := model.SaveProviderBreaker(ctx, breakers.database, breaker); err != nil {
+		breakers.logger.Error("Failed to save provider breaker probe", "tenant_id", tenantID, "provider", channel, "error", err)
+	}
+	breakers.logger.Info("provider_breaker_probe", "tenant_id", tenantID, "provider", channel)
+	return true
+}
+
+// record folds a dispatch outcome into the tenant's breaker. An account-level failure
+// opens it; opened is true only for the call that opened it, so its admins are told
+// once. Any other outcome except a transient failure proves the account works again
+// and closes an open breaker.
+func (breakers *providerBreakers) record(ctx context.Context, tenantID string, channel model.NotificationType, dispatchErr error, now time.Time) (model.ProviderBreaker, bool) {
+	if breakers == nil {
+		return model.ProviderBreaker{}, false
+	}
+	var accountError *ProviderAccountError
+	isAccountFailure := errors.As(dispatchErr, &accountError)
+	breakers.mutex.Lock()
+	defer breakers.mutex.Unlock()
+	breakers.ensureLoaded(ctx)
+	key := providerBreakerKey{tenantID: tenantID, channel: channel}
+	breaker, isOpen := breakers.open[key]
+	switch {
+	case isAccountFailure && !isOpen:
+		breaker = &model.ProviderBreaker{
+			TenantID:  tenantID,
+			Channel:   channel,
+			Reason:    accountError.Error(),
+			OpenedAt:  now.UTC(),
+			UpdatedAt: now.UTC(),
+		}
+		breakers.open[key] = breaker
+		opened, err := model.OpenProviderBreaker(ctx, breakers.database, breaker)
+		if err != nil {
+			breakers.logger.Error("Failed to save provider breaker", "tenant_id", tenantID, "provider", channel, "error", err)
+		}
+		breakers.logger.Error("provider_breaker_opened", "tenant_id", tenantID, "provider", channel, "reason", breaker.Reason)
+		return *breaker, opened
+	case isOpen && !isAccountFailure && !isTransientDispatchError(dispatchErr):
+		delete(breakers.open, key)
+		if err := model.CloseProviderBreaker(ctx, breakers.database, tenantID, channel); err != nil {
+			breakers.logger.Error("Failed to close provider breaker", "tenant_id", tenantID, "provider", channel, "error", err)
+		}
+		breakers.logger.Info("provider_breaker_closed", "tenant_id", tenantID, "provider", channel, "paused_for", now.Sub(breaker.OpenedAt).String())
+	}
+	return model.ProviderBreaker{}, false
+}
+
+// markNotified records that the breaker's notice reached the tenant's admins.
+func (breakers *providerBreakers) markNotified(ctx context.Context, tenantID string, channel model.NotificationType, now time.Time) {
+	breakers.mutex.Lock()
+	defer breakers.mutex.Unlock()
+	breaker, isOpen := breakers.open[providerBreakerKey{tenantID: tenantID, channel: channel}]
+	if !isOpen {
+		return
+	}
+	notifiedAt := now.UTC()
+	breaker.AdminsNotifiedAt = &notifiedAt
+	breaker.UpdatedAt = notifiedAt
+	if err := model.SaveProviderBreaker(ctx, breakers.database, breaker); err != nil {
+		breakers.logger.Error("Failed to save provider breaker notice", "tenant_id", tenantID, "provider", channel, "error", err)
+	}
+}
+
+// isOpen reports whether the tenant's channel is paused by an open breaker.
+func (breakers *providerBreakers) isOpen(ctx context.Context, tenantID string, channel model.NotificationType) bool {
+	if breakers == nil {
+		return false
+	}
+	breakers.mutex.Lock()
+	defer breakers.mutex.Unlock()
+	breakers.ensureLoaded(ctx)
+	_, isOpen := breakers.open[providerBreakerKey{tenantID: tenantID, channel: channel}]
+	return isOpen
+}
+
+// snapshot returns the tenant's open breakers.
+func (breakers *providerBreakers) snapshot(ctx context.Context, tenantID string) []ProviderBreakerState {
+	if breakers == nil {
+		return nil
+	}
+	breakers.mutex.Lock()
+	defer breakers.mutex.Unlock()
+	breakers.ensureLoaded(ctx)
+	var states []ProviderBreakerState
+	for key, breaker := range breakers.open {
+		if key.tenantID != tenantID {
+			continue
+		}
+		states = append(states, ProviderBreakerState{
+			Provider:       key.channel,
+			Reason:         breaker.Reason,
+			OpenedAt:       breaker.OpenedAt,
+			LastProbeAt:    breaker.LastProbeAt,
+			NextProbeAt:    breaker.NextProbeAt(breakers.probeInterval).UTC(),
+			AdminsNotified: breaker.AdminsNotifiedAt != nil,
+		})
+	}
+	sort.Slice(states, func(left, right int) bool {
+		return states[left].Provider < states[right].Provider
+	})
+	return states
+}
+
+// ensureLoaded reads the stored breakers on first use. A failed read leaves every channel
+// dispatching and is retried on the next call. The caller holds the mutex.
+func (breakers *providerBreakers) ensureLoaded(ctx context.Context) {
+	if breakers.loaded {
+		return
+	}
+	stored, err := model.ListProviderBreakers(ctx, breakers.database)
+	if err != nil {
+		breakers.logger.Error("Failed to load provider breakers", "error", err)
+		return
+	}
+	for index := range stored {
+		breaker := stored[index]
+		breakers.open[providerBreakerKey{tenantID: breaker.TenantID, channel: breaker.Channel}] = &breaker
+	}
+	breakers.loaded = true
+}
+
+// notifyBreakerOpened queues a notice to the tenant's admins. It goes through the retry
+// queue rather than a direct send, so a notice about a paused email channel is held
+// with the rest of the tenant's email and delivered when the channel resumes.
+func (serviceInstance *notificationServiceImpl) notifyBreakerOpened(ctx context.Context, breaker model.ProviderBreaker) {
+	if serviceInstance.tenantRepo == nil {
+		return
+	}
+	runtimeCfg, err := serviceInstance.tenantRepo.ResolveByID(ctx, breaker.TenantID)
+	if err != nil {
+		serviceInstance.logger.Error("Provider breaker notice skipped: tenant unavailable", "tenant_id", breaker.TenantID, "error", err)
+		return
+	}
+	recipients, err := serviceInstance.adminRecipients(ctx, runtimeCfg.Tenant)
+	if err != nil {
+		serviceInstance.logger.Error("Provider breaker notice skipped: admins unavailable", "tenant_id", breaker.TenantID, "error", err)
+		return
+	}
+	if len(recipients) == 0 {
+		serviceInstance.logger.Warn("Provider breaker notice skipped: tenant has no admins or support email", "tenant_id", breaker.TenantID)
+		return
+	}
+	channelName := strings.ToUpper(string(breaker.Channel))
+	subject := fmt.Sprintf(providerBreakerNoticeSubjectFormat, channelName, runtimeCfg.Tenant.DisplayName)
+	body := fmt.Sprintf(providerBreakerNoticeBodyFormat, channelName, runtimeCfg.Tenant.DisplayName, breaker.Reason, serviceInstance.providerBreakers.probeInterval)
+	notices := make([]model.Notification, 0, len(recipients))
+	for _, recipient := range recipients {
+		request, requestErr := model.NewNotificationRequest(model.NotificationEmail, recipient, subject, body, nil, nil)
+		if requestErr != nil {
+			serviceInstance.logger.Error("Provider breaker notice skipped", "tenant_id", breaker.TenantID, "error", requestErr)
+			return
+		}
+		notificationID, idErr := serviceInstance.newNotificationID(runtimeCfg)
+		if idErr != nil {
+			serviceInstance.logger.Error("Provider breaker notice skipped", "tenant_id", breaker.TenantID, "error", idErr)
+			return
+		}
+		notices = append(notices, model.NewNotification(notificationID, breaker.TenantID, request.WithSource(model.NotificationSourceSystemAlert), serviceInstance.currentTime()))
+	}
+	if batchErrors := model.CreateNotificationsBatch(ctx, serviceInstance.database, notices, serviceInstance.config.NotificationBatchChunkSize); len(batchErrors) > 0 {
+		for index, createErr := range batchErrors {
+			serviceInstance.logger.Error("Failed to queue provider breaker notice", "tenant_id", breaker.TenantID, "notification_id", notices[index].NotificationID, "error", createErr)
+		}
+		return
+	}
+	serviceInstance.providerBreakers.markNotified(ctx, breaker.TenantID, breaker.Channel, serviceInstance.currentTime())
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"net/textproto"
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+	"github.com/tyemirov/utils/scheduler"
+)
+
+func smsJobs(t *testing.T, store *notificationRetryStore, ctx context.Context, now time.Time) []scheduler.Job {
+	t.Helper()
+	jobs, err := store.PendingJobs(ctx, 5, now)
+	if err != nil {
+		t.Fatalf("pending jobs: %v", err)
+	}
+	var smsJobs []scheduler.Job
+	for _, job := range jobs {
+		if job.Payload.(*model.Notification).NotificationType == model.NotificationSMS {
+			smsJobs = append(smsJobs, job)
+		}
+	}
+	return smsJobs
+}
+
+func TestProviderBreakerPausesAndResumesTenantChannel(t *testing.T) {
+	serviceInstance, _, database := newDailyReportTestService(t, nil, []string{"ops@report.example", "lead@report.example"})
+	smsSender := &stubSmsSender{err: &ProviderAccountError{Err: &TwilioAPIError{StatusCode: 401, Code: twilioCodeAuthenticationFailed}}}
+	serviceInstance.defaultSmsSender = smsSender
+	serviceInstance.providerBreakers = newProviderBreakers(database, time.Minute, serviceInstance.logger)
+	runtimeCfg, err := serviceInstance.tenantRepo.ResolveByID(context.Background(), "tenant-report")
+	if err != nil {
+		t.Fatalf("resolve tenant: %v", err)
+	}
+	ctx := tenant.WithRuntime(context.Background(), runtimeCfg)
+
+	first, err := serviceInstance.SendNotification(ctx, mustNotificationRequest(t, model.NotificationSMS, "+15555550100", "", "First", nil, nil))
+	if err != nil || first.Status != model.StatusErrored || smsSender.callCount != 1 {
+		t.Fatalf("expected the auth failure to reach the provider once, got %s calls=%d err=%v", first.Status, smsSender.callCount, err)
+	}
+	for _, body := range []string{"Second", "Third"} {
+		response, err := serviceInstance.SendNotification(ctx, mustNotificationRequest(t, model.NotificationSMS, "+15555550100", "", body, nil, nil))
+		if err != nil || response.Status != model.StatusQueued {
+			t.Fatalf("expected a paused send to stay queued, got %s err=%v", response.Status, err)
+		}
+	}
+	if smsSender.callCount != 1 {
+		t.Fatalf("expected paused sends to skip the provider, got %d calls", smsSender.callCount)
+	}
+
+	notices, err := model.ListNotifications(context.Background(), database, "tenant-report", model.NotificationListFilters{})
+	if err != nil {
+		t.Fatalf("list notifications: %v", err)
+	}
+	noticeCount := 0
+	for _, notification := range notices {
+		if notification.Source == model.NotificationSourceSystemAlert {
+			noticeCount++
+		}
+	}
+	if noticeCount != 2 {
+		t.Fatalf("expected one notice per admin, got %d", noticeCount)
+	}
+
+	store := newNotificationRetryStore(database, serviceInstance.tenantRepo).withBreakers(serviceInstance.providerBreakers)
+	if jobs := smsJobs(t, store, ctx, time.Now()); len(jobs) != 0 {
+		t.Fatalf("expected the retry worker to skip the paused channel, got %d jobs", len(jobs))
+	}
+
+	restarted := newProviderBreakers(database, time.Minute, serviceInstance.logger)
+	states := restarted.snapshot(ctx, "tenant-report")
+	if len(states) != 1 || states[0].Provider != model.NotificationSMS || !states[0].AdminsNotified {
+		t.Fatalf("expected the breaker to survive a restart, got %+v", states)
+	}
+	serviceInstance.providerBreakers = restarted
+	store.withBreakers(restarted)
+	if _, opened := restarted.record(ctx, "tenant-report", model.NotificationSMS, smsSender.err, time.Now()); opened {
+		t.Fatalf("expected a failure on an open breaker not to notify admins again")
+	}
+
+	smsSender.err = nil
+	probeTime := time.Now().Add(2 * time.Minute)
+	canaries := smsJobs(t, store, ctx, probeTime)
+	if len(canaries) != 1 {
+		t.Fatalf("expected one canary per probe interval, got %d", len(canaries))
+	}
+	result, err := newNotificationDispatcher(serviceInstance).Attempt(ctx, canaries[0])
+	if err != nil || result.Status != string(model.StatusSent) || smsSender.callCount != 2 {
+		t.Fatalf("expected the canary to reach the recovered provider, got %+v calls=%d err=%v", result, smsSender.callCount, err)
+	}
+	if err := store.Ack(ctx, canaries[0], scheduler.AttemptUpdate{Status: result.Status, LastAttemptedAt: probeTime}); err != nil {
+		t.Fatalf("ack canary: %v", err)
+	}
+	if states := restarted.snapshot(ctx, "tenant-report"); len(states) != 0 {
+		t.Fatalf("expected the breaker to close, got %+v", states)
+	}
+	if stored, err := model.ListProviderBreakers(ctx, database); err != nil || len(stored) != 0 {
+		t.Fatalf("expected the stored breaker to be removed, got %+v err=%v", stored, err)
+	}
+	if jobs := smsJobs(t, store, ctx, probeTime); len(jobs) != 2 {
+		t.Fatalf("expected dispatch to resume for the remaining SMS, got %d jobs", len(jobs))
+	}
+}
+
+func TestProviderBreakerIgnoresOrdinaryFailures(t *testing.T) {
+	breakers := newProviderBreakers(openIsolatedDatabase(t), time.Minute, newDiscardLogger())
+	ctx := context.Background()
+	now := time.Now()
+	breakers.record(ctx, testTenantID, model.NotificationSMS, &TwilioAPIError{StatusCode: 400, Code: 21211}, now)
+	if !breakers.admit(ctx, testTenantID, model.NotificationSMS, now) {
+		t.Fatalf("expected a message-level failure to leave the channel open")
+	}
+	breakers.record(ctx, testTenantID, model.NotificationEmail, &ProviderAccountError{Err: &textproto.Error{Code: 535, Msg: "authentication failed"}}, now)
+	if !breakers.admit(ctx, testTenantID, model.NotificationSMS, now) {
+		t.Fatalf("expected an email breaker to leave SMS dispatching")
+	}
+	if breakers.admit(ctx, testTenantID, model.NotificationEmail, now.Add(30*time.Second)) {
+		t.Fatalf("expected the email channel to be paused before its first probe")
+	}
+	breakers.record(ctx, testTenantID, model.NotificationEmail, &textproto.Error{Code: 421, Msg: "try again later"}, now)
+	if states := breakers.snapshot(ctx, testTenantID); len(states) != 1 {
+		t.Fatalf("expected a transient failure to keep the breaker open, got %+v", states)
+	}
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"crypto/rand"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/tyemirov/pinguin/internal/config"
+	"github.com/tyemirov/pinguin/internal/entropy"
+	"github.com/tyemirov/pinguin/internal/model"
+)
+
+// sequenceSource hands out increasing suffixes so tests can see where each one went.
+type sequenceSource struct {
+	next int
+	err  error
+}
+
+func (source *sequenceSource) Read(p []byte) (int, error) {
+	return rand.Read(p)
+}
+
+func (source *sequenceSource) UniqueSuffix() (string, error) {
+	if source.err != nil {
+		return "", source.err
+	}
+	source.next++
+	return strconv.Itoa(source.next), nil
+}
+
+func TestWithRandomSourceMintsNotificationIDsAndBoundaries(t *testing.T) {
+	source := &sequenceSource{next: 1000}
+	emailSender := &stubEmailSender{}
+	serviceInstance := NewNotificationServiceWithSenders(openIsolatedDatabase(t), newDiscardLogger(), config.Config{MaxRetries: 3, RetryIntervalSec: 1}, nil, emailSender, &stubSmsSender{}, WithRandomSource(source)).(*notificationServiceImpl)
+
+	response, err := serviceInstance.SendNotification(tenantContext(), mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", nil, nil))
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if response.NotificationID != "notif-1001" {
+		t.Fatalf("expected the id suffix from the injected source, got %s", response.NotificationID)
+	}
+
+	message, err := buildEmailMessage(serviceInstance.randomSource(), "from@example.com", "to@example.com", "Subject", "Body", []model.EmailAttachment{{Filename: "a.txt", ContentType: "text/plain", Data: []byte("a")}}, model.SenderOverrides{})
+	if err != nil || !strings.Contains(string(message), `boundary="PinguinBoundary-1002"`) {
+		t.Fatalf("expected the boundary suffix from the injected source, err=%v", err)
+	}
+
+	source.err = errors.New("hsm unavailable")
+	if _, err := serviceInstance.SendNotification(tenantContext(), mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", nil, nil)); err == nil || !strings.Contains(err.Error(), "hsm unavailable") {
+		t.Fatalf("expected the source failure to reject the send, got %v", err)
+	}
+}
+
+func TestDefaultRandomSourceKeepsNotificationIDFormat(t *testing.T) {
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), &stubEmailSender{}, &stubSmsSender{})
+	notificationID, err := serviceInstance.newNotificationID(baseRuntimeConfig())
+	if err != nil {
+		t.Fatalf("notification id: %v", err)
+	}
+	suffix, found := strings.CutPrefix(notificationID, "notif-")
+	if _, parseErr := strconv.ParseInt(suffix, 10, 64); !found || parseErr != nil {
+		t.Fatalf("expected notif-<unix nanos>, got %s", notificationID)
+	}
+	if serviceInstance.randomSource() != entropy.System() {
+		t.Fatalf("expected the system source by default")
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+)
+
+// GetRenderedNotification returns the stored notification together with the content it
+// was last dispatched with. Rendered stays nil when nothing was recorded, either because
+// the tenant does not store rendered content or because no dispatch has succeeded yet.
+// Opens is set only for tenants that track opens.
+func (serviceInstance *notificationServiceImpl) GetRenderedNotification(ctx context.Context, notificationID string) (model.NotificationResponse, error) {
+	runtimeCfg, err := serviceInstance.requireTenant(ctx)
+	if err != nil {
+		return model.NotificationResponse{}, err
+	}
+	if strings.TrimSpace(notificationID) == "" {
+		return model.NotificationResponse{}, ErrMissingNotificationID
+	}
+	notificationRecord, err := model.MustGetNotificationByID(ctx, serviceInstance.database, runtimeCfg.Tenant.ID, notificationID)
+	if err != nil {
+		serviceInstance.logger.Error("Failed to retrieve notification", "error", err)
+		return model.NotificationResponse{}, err
+	}
+	response := model.NewNotificationResponse(*notificationRecord)
+	response.PendingReason = serviceInstance.pendingReason(ctx, *notificationRecord, serviceInstance.currentTime())
+	content, found, err := model.GetRenderedContent(ctx, serviceInstance.database, runtimeCfg.Tenant.ID, notificationID)
+	if err != nil {
+		serviceInstance.logger.Error("Failed to retrieve rendered content", "notification_id", notificationID, "error", err)
+		return model.NotificationResponse{}, err
+	}
+	if found {
+		rendered := content.WithInput(*notificationRecord)
+		response.Rendered = &rendered
+	}
+	if err := serviceInstance.attachOpens(ctx, runtimeCfg, &response); err != nil {
+		serviceInstance.logger.Error("Failed to summarize notification opens", "notification_id", notificationID, "error", err)
+		return model.NotificationResponse{}, err
+	}
+	return response, nil
+}
+
+// MessageSizeEstimate reports how large a notification would be when handed to its provider.
+type MessageSizeEstimate struct {
+	// MessageSizeBytes is the full MIME message for email, with attachments base64-encoded,
+	// or the message body for SMS.
+	MessageSizeBytes int
+	// AttachmentBytes is the attachments' size before encoding.
+	AttachmentBytes int
+}
+
+// EstimateNotificationSize applies the checks SendNotification would and builds the
+// message the provider would receive, without sending or storing anything.
+func (serviceInstance *notificationServiceImpl) EstimateNotificationSize(ctx context.Context, request model.NotificationRequest) (MessageSizeEstimate, error) {
+	runtimeCfg, err := serviceInstance.requireTenant(ctx)
+	if err != nil {
+		return MessageSizeEstimate{}, err
+	}
+	pending, err := serviceInstance.prepareSend(runtimeCfg, request, "", serviceInstance.currentTime())
+	if err != nil {
+		return MessageSizeEstimate{}, err
+	}
+	estimate := MessageSizeEstimate{
+		MessageSizeBytes: len(pending.notification.Message),
+		AttachmentBytes:  attachmentBytes(pending.attachments),
+	}
+	if pending.notification.NotificationType == model.NotificationEmail && pending.notification.ContentEncrypted {
+		rawMessage, err := buildEncryptedEmailMessage(serviceInstance.emailFromAddress(runtimeCfg), pending.notification.Recipient, pending.notification.Subject, pending.notification.Message, pending.notification.SenderOverrides())
+		if err != nil {
+			return MessageSizeEstimate{}, err
+		}
+		estimate.MessageSizeBytes = len(rawMessage)
+	} else if pending.notification.NotificationType == model.NotificationEmail {
+		rawMessage, err := buildEmailMessage(serviceInstance.randomSource(), serviceInstance.emailFromAddress(runtimeCfg), pending.notification.Recipient, pending.notification.Subject, pending.notification.Message, pending.attachments, pending.notification.SenderOverrides())
+		if err != nil {
+			return MessageSizeEstimate{}, err
+		}
+		estimate.MessageSizeBytes = len(rawMessage)
+	}
+	return estimate, nil
+}
+
+// recordRenderedContent stores the final subject and body a notification was dispatched
+// with when its tenant opted in. Caller-encrypted content is stored as is already, so it
+// gets no copy. Failures are logged; they never fail the send.
+func (serviceInstance *notificationServiceImpl) recordRenderedContent(ctx context.Context, runtimeCfg tenant.RuntimeConfig, record *model.Notification, subject string, message string, attachments []model.EmailAttachment) {
+	if !runtimeCfg.Tenant.StoreRenderedContent || record.ContentEncrypted {
+		return
+	}
+	mimeStructure := ""
+	sizeBytes := len(message)
+	if record.NotificationType == model.NotificationEmail {
+		rawMessage, err := buildEmailMessage(serviceInstance.randomSource(), serviceInstance.emailFromAddress(runtimeCfg), record.Recipient, subject, message, attachments, record.SenderOverrides())
+		if err != nil {
+			serviceInstance.logger.Warn("Skipping rendered content: message does not build", "notification_id", record.NotificationID, "tenant_id", record.TenantID, "error", err)
+			return
+		}
+		mimeStructure = emailMIMEStructure(attachments)
+		sizeBytes = len(rawMessage)
+	}
+	content := model.NewRenderedContent(*record, subject, message, mimeStructure, sizeBytes, serviceInstance.currentTime())
+	if err := model.SaveRenderedContent(ctx, serviceInstance.database, &content); err != nil {
+		serviceInstance.logger.Error("Failed to store rendered content", "notification_id", record.NotificationID, "tenant_id", record.TenantID, "error", err)
+	}
+}
+
+// emailFromAddress is the From address the tenant's email goes out with.
+func (serviceInstance *notificationServiceImpl) emailFromAddress(runtimeCfg tenant.RuntimeConfig) string {
+	if runtimeCfg.Email.FromAddress != "" {
+		return runtimeCfg.Email.FromAddress
+	}
+	return serviceInstance.config.FromEmail
+}
+
+// emailMIMEStructure names the structure buildEmailMessage produces for attachments.
+func emailMIMEStructure(attachments []model.EmailAttachment) string {
+	if len(attachments) == 0 {
+		return model.MIMEStructurePlain
+	}
+	if invite, _ := splitCalendarInvite(attachments); invite != nil {
+		return model.MIMEStructureAlternative
+	}
+	return model.MIMEStructureMixed
+}
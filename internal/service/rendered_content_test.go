@@ -0,0 +1,136 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/smtp"
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+)
+
+func renderedContentContext(store bool) context.Context {
+	runtimeCfg := baseRuntimeConfig()
+	runtimeCfg.Tenant.StoreRenderedContent = store
+	return tenant.WithRuntime(context.Background(), runtimeCfg)
+}
+
+func TestGetRenderedNotificationReportsDispatchedEmail(t *testing.T) {
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), &stubEmailSender{}, &stubSmsSender{})
+	ctx := renderedContentContext(true)
+	attachments := []model.EmailAttachment{{Filename: "report.txt", ContentType: "text/plain", Data: []byte("numbers")}}
+
+	response, err := serviceInstance.SendNotification(ctx, mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", nil, attachments))
+	if err != nil {
+		t.Fatalf("SendNotification error: %v", err)
+	}
+	if response.Rendered != nil {
+		t.Fatalf("expected send responses to leave rendered content out")
+	}
+	stored, found, err := model.GetRenderedContent(ctx, serviceInstance.database, testTenantID, response.NotificationID)
+	if err != nil || !found {
+		t.Fatalf("expected rendered content to be stored, found=%v err=%v", found, err)
+	}
+	if !stored.SameAsInput || stored.Message != "" || stored.Subject != "" {
+		t.Fatalf("expected unchanged content not to be copied, got %+v", stored)
+	}
+
+	detailed, err := serviceInstance.GetRenderedNotification(ctx, response.NotificationID)
+	if err != nil {
+		t.Fatalf("GetRenderedNotification error: %v", err)
+	}
+	rendered := detailed.Rendered
+	if rendered == nil || rendered.Subject != "Subject" || rendered.Message != "Body" || rendered.MIMEStructure != model.MIMEStructureMixed {
+		t.Fatalf("unexpected rendered content %+v", rendered)
+	}
+	rawMessage, err := buildEmailMessage(serviceInstance.randomSource(), serviceInstance.emailFromAddress(baseRuntimeConfig()), "user@example.com", "Subject", "Body", attachments, model.SenderOverrides{})
+	if err != nil {
+		t.Fatalf("build message: %v", err)
+	}
+	if rendered.SizeBytes != len(rawMessage) || rendered.RenderedAt.IsZero() {
+		t.Fatalf("expected the raw message size and time, got %d/%v want %d", rendered.SizeBytes, rendered.RenderedAt, len(rawMessage))
+	}
+}
+
+func TestGetRenderedNotificationWithoutOptIn(t *testing.T) {
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), &stubEmailSender{}, &stubSmsSender{})
+	ctx := renderedContentContext(false)
+
+	response, err := serviceInstance.SendNotification(ctx, mustNotificationRequest(t, model.NotificationSMS, "+15555550100", "", "Body", nil, nil))
+	if err != nil {
+		t.Fatalf("SendNotification error: %v", err)
+	}
+	detailed, err := serviceInstance.GetRenderedNotification(ctx, response.NotificationID)
+	if err != nil || detailed.Rendered != nil || detailed.NotificationID != response.NotificationID {
+		t.Fatalf("expected no rendered content without opt-in, got %+v (%v)", detailed.Rendered, err)
+	}
+}
+
+func TestEstimateNotificationSizeMatchesTheSentMessage(t *testing.T) {
+	originalSendMail := sendMailFunc
+	defer func() { sendMailFunc = originalSendMail }()
+	sentSize := 0
+	sendMailFunc = func(_ context.Context, _ time.Duration, _ string, _ smtp.Auth, _ string, _ []string, msg []byte) error {
+		sentSize = len(msg)
+		return nil
+	}
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), nil, &stubSmsSender{})
+	ctx := renderedContentContext(false)
+	report := model.EmailAttachment{Filename: "report.csv", ContentType: "text/csv", Data: bytes.Repeat([]byte("0123456789,"), 40000)}
+	invite := model.EmailAttachment{Filename: model.CalendarInviteFilename, ContentType: "text/calendar; method=REQUEST", Data: []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n")}
+	for name, attachments := range map[string][]model.EmailAttachment{
+		"plain":      nil,
+		"attachment": {report},
+		"invite":     {report, invite},
+	} {
+		request := mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Quarterly numbers", "See attached.", nil, attachments)
+		estimate, err := serviceInstance.EstimateNotificationSize(ctx, request)
+		if err != nil {
+			t.Fatalf("%s: EstimateNotificationSize error: %v", name, err)
+		}
+		if _, err := serviceInstance.SendNotification(ctx, request); err != nil {
+			t.Fatalf("%s: SendNotification error: %v", name, err)
+		}
+		if estimate.MessageSizeBytes != sentSize {
+			t.Fatalf("%s: expected the estimate to match the sent message, got %d want %d", name, estimate.MessageSizeBytes, sentSize)
+		}
+		if estimate.AttachmentBytes != attachmentBytes(attachments) {
+			t.Fatalf("%s: expected %d attachment bytes, got %d", name, attachmentBytes(attachments), estimate.AttachmentBytes)
+		}
+	}
+	if estimate, _ := serviceInstance.EstimateNotificationSize(ctx, mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Numbers", "Body", nil, []model.EmailAttachment{report})); estimate.MessageSizeBytes < len(report.Data)*4/3 {
+		t.Fatalf("expected the estimate to include the base64 growth, got %d for %d bytes", estimate.MessageSizeBytes, len(report.Data))
+	}
+
+	smsEstimate, err := serviceInstance.EstimateNotificationSize(ctx, mustNotificationRequest(t, model.NotificationSMS, "+15555550100", "", "Short text", nil, nil))
+	if err != nil || smsEstimate.MessageSizeBytes != len("Short text") || smsEstimate.AttachmentBytes != 0 {
+		t.Fatalf("expected the SMS body size, got %+v (%v)", smsEstimate, err)
+	}
+
+	serviceInstance.config.StrictScheduling = true
+	past := time.Now().UTC().Add(-time.Hour)
+	if _, err := serviceInstance.EstimateNotificationSize(ctx, mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Late", "Body", &past, nil)); !errors.Is(err, ErrScheduleInPast) {
+		t.Fatalf("expected the send checks to apply, got %v", err)
+	}
+	var stored int64
+	if err := serviceInstance.database.Model(&model.Notification{}).Count(&stored).Error; err != nil || stored != 3 {
+		t.Fatalf("expected only the three sends to be stored, got %d (%v)", stored, err)
+	}
+}
+
+func TestEmailMIMEStructure(t *testing.T) {
+	invite := model.EmailAttachment{Filename: model.CalendarInviteFilename, ContentType: "text/calendar; method=REQUEST", Data: []byte("BEGIN:VCALENDAR")}
+	file := model.EmailAttachment{Filename: "a.txt", ContentType: "text/plain", Data: []byte("a")}
+	for expected, attachments := range map[string][]model.EmailAttachment{
+		model.MIMEStructurePlain:       nil,
+		model.MIMEStructureMixed:       {file},
+		model.MIMEStructureAlternative: {file, invite},
+	} {
+		if structure := emailMIMEStructure(attachments); structure != expected {
+			t.Fatalf("expected %s, got %s", expected, structure)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+)
+
+// retentionSweepInterval is how often StartRetentionWorker purges expired notifications.
+const retentionSweepInterval = time.Hour
+
+// RetentionReport counts the notifications one retention sweep deleted, by tenant.
+// Tenants that keep notifications forever are absent.
+type RetentionReport struct {
+	SweptAt time.Time
+	Deleted map[string]int64
+}
+
+// StartRetentionWorker purges expired notifications once at startup and then hourly.
+// Each tenant keeps finished notifications for its retentionDays, or server.retentionDays
+// when unset; notifications under legal hold are never purged.
+func (serviceInstance *notificationServiceImpl) StartRetentionWorker(ctx context.Context) {
+	if serviceInstance.tenantRepo == nil {
+		return
+	}
+	ticker := time.NewTicker(retentionSweepInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := serviceInstance.runRetentionSweep(ctx, serviceInstance.currentTime()); err != nil {
+			serviceInstance.logger.Error("Retention sweep failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runRetentionSweep applies every tenant's retention cutoff, suspended tenants included.
+// A tenant whose purge fails is logged and skipped so the others are still swept.
+func (serviceInstance *notificationServiceImpl) runRetentionSweep(ctx context.Context, now time.Time) (RetentionReport, error) {
+	report := RetentionReport{SweptAt: now, Deleted: make(map[string]int64)}
+	inventory, err := serviceInstance.tenantRepo.ListTenantInventory(ctx)
+	if err != nil {
+		return report, err
+	}
+	for _, entry := range inventory {
+		retentionDays := entry.Tenant.RetentionDays
+		if retentionDays <= 0 {
+			retentionDays = serviceInstance.config.RetentionDays
+		}
+		if retentionDays <= 0 {
+			continue
+		}
+		cutoff := now.AddDate(0, 0, -retentionDays)
+		deleted, purgeErr := model.PurgeNotificationsBefore(ctx, serviceInstance.database, entry.Tenant.ID, cutoff)
+		if purgeErr != nil {
+			serviceInstance.logger.Error("Retention sweep failed for tenant", "tenant_id", entry.Tenant.ID, "deleted", deleted, "error", purgeErr)
+		}
+		report.Deleted[entry.Tenant.ID] = deleted
+		serviceInstance.logger.Info("retention_sweep", "tenant_id", entry.Tenant.ID, "retention_days", retentionDays, "cutoff", cutoff, "deleted", deleted)
+	}
+	return report, nil
+}
+
+// SetLegalHold places or lifts a legal hold on one of the tenant's notifications. The
+// change is written to the audit log with the actor who requested it.
+func (serviceInstance *notificationServiceImpl) SetLegalHold(ctx context.Context, notificationID string, held bool, actor string) (model.NotificationResponse, error) {
+	runtimeCfg, err := serviceInstance.requireTenant(ctx)
+	if err != nil {
+		return model.NotificationResponse{}, err
+	}
+	if strings.TrimSpace(notificationID) == "" {
+		return model.NotificationResponse{}, ErrMissingNotificationID
+	}
+	updated, err := model.SetNotificationLegalHold(ctx, serviceInstance.database, runtimeCfg.Tenant.ID, notificationID, held, serviceInstance.currentTime())
+	if err != nil {
+		serviceInstance.logger.Error("Failed to update legal hold", "notification_id", notificationID, "tenant_id", runtimeCfg.Tenant.ID, "error", err)
+		return model.NotificationResponse{}, err
+	}
+	serviceInstance.logger.Info("audit_legal_hold", "tenant_id", runtimeCfg.Tenant.ID, "notification_id", notificationID, "legal_hold", held, "actor", actor)
+	return model.NewNotificationResponse(*updated), nil
+}
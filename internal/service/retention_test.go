@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+	"gorm.io/gorm"
+)
+
+func retentionTestTenant(id string, retentionDays int) tenant.BootstrapTenant {
+	return tenant.BootstrapTenant{
+		ID:            id,
+		DisplayName:   id,
+		SupportEmail:  "support@" + id + ".example",
+		Enabled:       ptrBool(true),
+		Domains:       []string{id + ".example"},
+		RetentionDays: retentionDays,
+		EmailProfile: tenant.BootstrapEmailProfile{
+			Host:        "smtp." + id + ".example",
+			Port:        587,
+			Username:    "smtp-user",
+			Password:    "smtp-pass",
+			FromAddress: "noreply@" + id + ".example",
+		},
+	}
+}
+
+func newRetentionTestService(t *testing.T, tenants ...tenant.BootstrapTenant) (*notificationServiceImpl, *gorm.DB) {
+	t.Helper()
+	database := openIsolatedDatabase(t)
+	if err := database.AutoMigrate(&tenant.Tenant{}, &tenant.TenantDomain{}, &tenant.TenantAdmin{}, &tenant.EmailProfile{}, &tenant.SMSProfile{}); err != nil {
+		t.Fatalf("tenant migration: %v", err)
+	}
+	keeper, err := tenant.NewSecretKeeper(strings.Repeat("a", 64))
+	if err != nil {
+		t.Fatalf("secret keeper: %v", err)
+	}
+	if err := tenant.Bootstrap(context.Background(), database, keeper, tenant.BootstrapConfig{Tenants: tenants}); err != nil {
+		t.Fatalf("bootstrap: %v", err)
+	}
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(database, &stubEmailSender{}, &stubSmsSender{})
+	serviceInstance.tenantRepo = tenant.NewRepository(database, keeper)
+	return serviceInstance, database
+}
+
+func insertAgedNotification(t *testing.T, database *gorm.DB, tenantID string, notificationID string, status model.NotificationStatus, createdAt time.Time) {
+	t.Helper()
+	insertNotificationRecord(t, database, model.Notification{
+		TenantID:         tenantID,
+		NotificationID:   notificationID,
+		NotificationType: model.NotificationEmail,
+		Recipient:        "user@example.com",
+		Subject:          "Subject",
+		Message:          "Body",
+		Status:           status,
+		CreatedAt:        createdAt,
+	})
+}
+
+func remainingNotificationIDs(t *testing.T, database *gorm.DB, tenantID string) map[string]bool {
+	t.Helper()
+	notifications, err := model.ListNotifications(context.Background(), database, tenantID, model.NotificationListFilters{})
+	if err != nil {
+		t.Fatalf("list notifications: %v", err)
+	}
+	remaining := make(map[string]bool, len(notifications))
+	for _, notification := range notifications {
+		remaining[notification.NotificationID] = true
+	}
+	return remaining
+}
+
+func TestRetentionSweepAppliesEachTenantCutoff(t *testing.T) {
+	serviceInstance, database := newRetentionTestService(t,
+		retentionTestTenant("tenant-short", 7),
+		retentionTestTenant("tenant-default", 0),
+	)
+	serviceInstance.config.RetentionDays = 30
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	insertAgedNotification(t, database, "tenant-short", "short-old", model.StatusSent, now.AddDate(0, 0, -10))
+	insertAgedNotification(t, database, "tenant-short", "short-new", model.StatusSent, now.AddDate(0, 0, -3))
+	insertAgedNotification(t, database, "tenant-short", "short-queued", model.StatusQueued, now.AddDate(0, 0, -10))
+	insertAgedNotification(t, database, "tenant-default", "default-mid", model.StatusErrored, now.AddDate(0, 0, -10))
+	insertAgedNotification(t, database, "tenant-default", "default-old", model.StatusCancelled, now.AddDate(0, 0, -40))
+
+	report, err := serviceInstance.runRetentionSweep(context.Background(), now)
+	if err != nil {
+		t.Fatalf("retention sweep: %v", err)
+	}
+	if report.Deleted["tenant-short"] != 1 || report.Deleted["tenant-default"] != 1 {
+		t.Fatalf("expected one deletion per tenant, got %+v", report.Deleted)
+	}
+	short := remainingNotificationIDs(t, database, "tenant-short")
+	if short["short-old"] || !short["short-new"] || !short["short-queued"] {
+		t.Fatalf("unexpected tenant-short survivors %v", short)
+	}
+	defaults := remainingNotificationIDs(t, database, "tenant-default")
+	if defaults["default-old"] || !defaults["default-mid"] {
+		t.Fatalf("expected the server default to apply, got survivors %v", defaults)
+	}
+}
+
+func TestRetentionSweepKeepsHeldNotifications(t *testing.T) {
+	serviceInstance, database := newRetentionTestService(t, retentionTestTenant("tenant-held", 7))
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	insertAgedNotification(t, database, "tenant-held", "held", model.StatusSent, now.AddDate(0, 0, -30))
+	insertAgedNotification(t, database, "tenant-held", "released", model.StatusSent, now.AddDate(0, 0, -30))
+
+	runtimeCfg, err := serviceInstance.tenantRepo.ResolveByID(context.Background(), "tenant-held")
+	if err != nil {
+		t.Fatalf("resolve tenant: %v", err)
+	}
+	ctx := tenant.WithRuntime(context.Background(), runtimeCfg)
+	for _, notificationID := range []string{"held", "released"} {
+		response, err := serviceInstance.SetLegalHold(ctx, notificationID, true, "counsel@held.example")
+		if err != nil || !response.LegalHold {
+			t.Fatalf("expected %s to be held, got %+v err=%v", notificationID, response, err)
+		}
+	}
+
+	for sweep := 0; sweep < 2; sweep++ {
+		report, err := serviceInstance.runRetentionSweep(context.Background(), now.AddDate(0, 0, sweep*30))
+		if err != nil || report.Deleted["tenant-held"] != 0 {
+			t.Fatalf("expected sweep %d to keep held notifications, got %+v err=%v", sweep, report.Deleted, err)
+		}
+	}
+
+	if _, err := serviceInstance.SetLegalHold(ctx, "released", false, "counsel@held.example"); err != nil {
+		t.Fatalf("release hold: %v", err)
+	}
+	report, err := serviceInstance.runRetentionSweep(context.Background(), now)
+	if err != nil || report.Deleted["tenant-held"] != 1 {
+		t.Fatalf("expected the released notification to be purged, got %+v err=%v", report.Deleted, err)
+	}
+	if remaining := remainingNotificationIDs(t, database, "tenant-held"); !remaining["held"] || remaining["released"] {
+		t.Fatalf("unexpected survivors %v", remaining)
+	}
+	if _, err := serviceInstance.SetLegalHold(ctx, "missing", true, "counsel@held.example"); err != model.ErrNotificationNotFound {
+		t.Fatalf("expected ErrNotificationNotFound, got %v", err)
+	}
+}
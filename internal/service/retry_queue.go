@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/config"
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/utils/scheduler"
+)
+
+// RetryQueue hands due notifications to the retry worker and records what happened to
+// them. The notifications table stays the source of truth for status; a queue only
+// decides which notification is attempted next and when.
+//
+// Delivery is at least once, outcomes are recorded exactly once:
+//   - Claim returns due jobs, oldest first, whose RetryCount is below maxRetries. A
+//     claimed job is not returned by another Claim until it is acked, nacked, or its
+//     claim lease expires; an expired claim is redelivered, so a worker that stops
+//     mid-attempt can cause one duplicate dispatch.
+//   - Ack records a final outcome (sent, cancelled, or errored with no retries left).
+//     The job is never claimed again.
+//   - Nack records a failed attempt that will be retried. The job is claimable again
+//     from retryAt with the update's RetryCount and LastAttemptedAt.
+//
+// Ack and Nack on a job the caller did not claim must not fail, since a redelivered
+// claim can race the original worker's outcome.
+type RetryQueue interface {
+	Claim(ctx context.Context, maxRetries int, now time.Time) ([]scheduler.Job, error)
+	Ack(ctx context.Context, job scheduler.Job, update scheduler.AttemptUpdate) error
+	Nack(ctx context.Context, job scheduler.Job, update scheduler.AttemptUpdate, retryAt time.Time) error
+}
+
+// retryCheckpointer is implemented by queues that can resume their position after a
+// restart; the retry worker saves a checkpoint after every completed tick.
+type retryCheckpointer interface {
+	resumeFromCheckpoint(ctx context.Context, logger *slog.Logger, now time.Time)
+	saveCheckpoint(ctx context.Context, tickAt time.Time) error
+}
+
+// defaultRetryClaimLease bounds how long a claimed job stays hidden from later claims.
+const defaultRetryClaimLease = 5 * time.Minute
+
+// newRetryQueue builds the queue named by server.retryQueue.
+func (serviceInstance *notificationServiceImpl) newRetryQueue(retryInterval time.Duration) (RetryQueue, error) {
+	switch queueName := serviceInstance.config.RetryQueue; queueName {
+	case "", config.RetryQueueSQL:
+		return newNotificationRetryStore(serviceInstance.database, serviceInstance.tenantRepo).
+			withPacer(serviceInstance.dispatchPacer).
+			withBreakers(serviceInstance.providerBreakers).
+			withTenantCap(serviceInstance.config.MaxConcurrentRetriesPerTenant).
+			withMaxRetryAge(time.Duration(serviceInstance.config.MaxRetryAgeSec) * time.Second).
+			withBackoff(retryInterval).
+			withStateChangeHook(serviceInstance.recordStateChange), nil
+	default:
+		return nil, fmt.Errorf("unsupported retry queue %q", queueName)
+	}
+}
+
+// retryQueueRepository adapts a RetryQueue to the scheduler: failures with retries left
+// are nacked until the scheduler's own backoff admits them again, everything else is acked.
+type retryQueueRepository struct {
+	queue         RetryQueue
+	maxRetries    int
+	retryInterval time.Duration
+}
+
+func newRetryQueueRepository(queue RetryQueue, maxRetries int, retryInterval time.Duration) *retryQueueRepository {
+	return &retryQueueRepository{queue: queue, maxRetries: maxRetries, retryInterval: retryInterval}
+}
+
+func (repository *retryQueueRepository) PendingJobs(ctx context.Context, maxRetries int, now time.Time) ([]scheduler.Job, error) {
+	return repository.queue.Claim(ctx, maxRetries, now)
+}
+
+func (repository *retryQueueRepository) ApplyAttemptResult(ctx context.Context, job scheduler.Job, update scheduler.AttemptUpdate) error {
+	status := model.CanonicalStatus(model.NotificationStatus(update.Status))
+	if (status == "" || status == model.StatusErrored) && update.RetryCount < repository.maxRetries {
+		return repository.queue.Nack(ctx, job, update, retryBackoffAt(update.LastAttemptedAt, update.RetryCount, repository.retryInterval))
+	}
+	return repository.queue.Ack(ctx, job, update)
+}
+
+// retryBackoffAt mirrors the scheduler's exponential backoff: the attempt after
+// retryCount failures is due retryInterval*2^retryCount after the last one.
+func retryBackoffAt(lastAttemptedAt time.Time, retryCount int, retryInterval time.Duration) time.Time {
+	shift := retryCount
+	if shift > maxRetryBackoffShift {
+		shift = maxRetryBackoffShift
+	}
+	if shift < 0 {
+		shift = 0
+	}
+	return lastAttemptedAt.UTC().Add(retryInterval * time.Duration(1<<uint(shift)))
+}
+
+// retryClaims hides claimed job ids until they are released or their lease expires.
+type retryClaims struct {
+	lease       time.Duration
+	leasedUntil map[string]time.Time
+}
+
+func newRetryClaims(lease time.Duration) *retryClaims {
+	return &retryClaims{lease: lease, leasedUntil: make(map[string]time.Time)}
+}
+
+// claim leases jobID from now and reports whether it was free to claim.
+func (claims *retryClaims) claim(jobID string, now time.Time) bool {
+	if leasedUntil, claimed := claims.leasedUntil[jobID]; claimed && now.Before(leasedUntil) {
+		return false
+	}
+	claims.leasedUntil[jobID] = now.Add(claims.lease)
+	return true
+}
+
+// expire forgets leases that ended before now so abandoned claims do not accumulate.
+func (claims *retryClaims) expire(now time.Time) {
+	for jobID, leasedUntil := range claims.leasedUntil {
+		if !now.Before(leasedUntil) {
+			delete(claims.leasedUntil, jobID)
+		}
+	}
+}
+
+func (claims *retryClaims) release(jobID string) {
+	delete(claims.leasedUntil, jobID)
+}
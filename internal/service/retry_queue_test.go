@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/utils/scheduler"
+)
+
+// memoryRetryQueue is an in-memory RetryQueue for tests. It keeps outcomes on the job
+// payloads instead of persisting them.
+type memoryRetryQueue struct {
+	mutex   sync.Mutex
+	entries []*memoryRetryEntry
+	claims  *retryClaims
+}
+
+type memoryRetryEntry struct {
+	job     scheduler.Job
+	retryAt time.Time
+}
+
+func newMemoryRetryQueue() *memoryRetryQueue {
+	return &memoryRetryQueue{claims: newRetryClaims(defaultRetryClaimLease)}
+}
+
+func (queue *memoryRetryQueue) enqueue(record model.Notification) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	entry := &memoryRetryEntry{job: scheduler.Job{
+		ID:              record.NotificationID,
+		ScheduledFor:    record.ScheduledFor,
+		RetryCount:      record.RetryCount,
+		LastAttemptedAt: record.LastAttemptedAt,
+		Payload:         &record,
+	}}
+	if record.NextAttemptAt != nil {
+		entry.retryAt = *record.NextAttemptAt
+	}
+	queue.entries = append(queue.entries, entry)
+}
+
+func (queue *memoryRetryQueue) Claim(_ context.Context, maxRetries int, now time.Time) ([]scheduler.Job, error) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	queue.claims.expire(now)
+	var jobs []scheduler.Job
+	for _, entry := range queue.entries {
+		if entry.job.RetryCount >= maxRetries || now.Before(entry.retryAt) {
+			continue
+		}
+		if entry.job.ScheduledFor != nil && now.Before(*entry.job.ScheduledFor) {
+			continue
+		}
+		if queue.claims.claim(entry.job.ID, now) {
+			jobs = append(jobs, entry.job)
+		}
+	}
+	return jobs, nil
+}
+
+func (queue *memoryRetryQueue) Ack(_ context.Context, job scheduler.Job, update scheduler.AttemptUpdate) error {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	for index, entry := range queue.entries {
+		if entry.job.ID == job.ID {
+			applyMemoryAttempt(entry, update)
+			queue.entries = append(queue.entries[:index], queue.entries[index+1:]...)
+			break
+		}
+	}
+	queue.claims.release(job.ID)
+	return nil
+}
+
+func (queue *memoryRetryQueue) Nack(_ context.Context, job scheduler.Job, update scheduler.AttemptUpdate, retryAt time.Time) error {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	for _, entry := range queue.entries {
+		if entry.job.ID == job.ID {
+			applyMemoryAttempt(entry, update)
+			entry.retryAt = retryAt
+			break
+		}
+	}
+	queue.claims.release(job.ID)
+	return nil
+}
+
+func (queue *memoryRetryQueue) size() int {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	return len(queue.entries)
+}
+
+func applyMemoryAttempt(entry *memoryRetryEntry, update scheduler.AttemptUpdate) {
+	entry.job.RetryCount = update.RetryCount
+	entry.job.LastAttemptedAt = update.LastAttemptedAt
+	if record, ok := entry.job.Payload.(*model.Notification); ok {
+		record.Status = model.NotificationStatus(update.Status)
+		record.RetryCount = update.RetryCount
+		record.LastAttemptedAt = update.LastAttemptedAt
+	}
+}
+
+type retryQueueHarness struct {
+	queue RetryQueue
+	seed  func(record model.Notification)
+}
+
+func TestSQLRetryQueueConformance(t *testing.T) {
+	runRetryQueueConformance(t, func(t *testing.T) retryQueueHarness {
+		database := openIsolatedDatabase(t)
+		return retryQueueHarness{
+			queue: newNotificationRetryStore(database, nil).withBackoff(time.Second),
+			seed: func(record model.Notification) {
+				if err := database.Create(&record).Error; err != nil {
+					t.Fatalf("seed notification: %v", err)
+				}
+			},
+		}
+	})
+}
+
+func TestMemoryRetryQueueConformance(t *testing.T) {
+	runRetryQueueConformance(t, func(*testing.T) retryQueueHarness {
+		queue := newMemoryRetryQueue()
+		return retryQueueHarness{queue: queue, seed: queue.enqueue}
+	})
+}
+
+// runRetryQueueConformance checks the claim, ack, and nack contract documented on RetryQueue.
+func runRetryQueueConformance(t *testing.T, newHarness func(t *testing.T) retryQueueHarness) {
+	t.Helper()
+	const maxRetries = 3
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	queuedNotification := func(notificationID string) model.Notification {
+		return model.Notification{
+			NotificationID:   notificationID,
+			TenantID:         testTenantID,
+			NotificationType: model.NotificationEmail,
+			Recipient:        "user@example.com",
+			Subject:          "Subject",
+			Message:          "Body",
+			Status:           model.StatusQueued,
+			CreatedAt:        now.Add(-time.Hour),
+			UpdatedAt:        now.Add(-time.Hour),
+		}
+	}
+	claimIDs := func(t *testing.T, queue RetryQueue, at time.Time) []string {
+		t.Helper()
+		jobs, err := queue.Claim(ctx, maxRetries, at)
+		if err != nil {
+			t.Fatalf("claim: %v", err)
+		}
+		ids := make([]string, 0, len(jobs))
+		for _, job := range jobs {
+			ids = append(ids, job.ID)
+		}
+		return ids
+	}
+	claimOne := func(t *testing.T, queue RetryQueue, at time.Time) scheduler.Job {
+		t.Helper()
+		jobs, err := queue.Claim(ctx, maxRetries, at)
+		if err != nil || len(jobs) != 1 {
+			t.Fatalf("expected one claimed job, got %d (%v)", len(jobs), err)
+		}
+		return jobs[0]
+	}
+
+	t.Run("ClaimReturnsDueJobsOldestFirst", func(t *testing.T) {
+		harness := newHarness(t)
+		harness.seed(queuedNotification("notif-due-first"))
+		erroredNotification := queuedNotification("notif-due-retry")
+		erroredNotification.Status = model.StatusErrored
+		erroredNotification.RetryCount = 1
+		erroredNotification.LastAttemptedAt = now.Add(-time.Hour)
+		harness.seed(erroredNotification)
+		scheduledNotification := queuedNotification("notif-scheduled")
+		scheduledFor := now.Add(time.Hour)
+		scheduledNotification.ScheduledFor = &scheduledFor
+		harness.seed(scheduledNotification)
+		exhaustedNotification := queuedNotification("notif-exhausted")
+		exhaustedNotification.Status = model.StatusErrored
+		exhaustedNotification.RetryCount = maxRetries
+		harness.seed(exhaustedNotification)
+
+		ids := claimIDs(t, harness.queue, now)
+		if len(ids) != 2 || ids[0] != "notif-due-first" || ids[1] != "notif-due-retry" {
+			t.Fatalf("expected the two due jobs oldest first, got %v", ids)
+		}
+	})
+
+	t.Run("ClaimedJobsStayHiddenUntilTheLeaseExpires", func(t *testing.T) {
+		harness := newHarness(t)
+		harness.seed(queuedNotification("notif-leased"))
+		claimOne(t, harness.queue, now)
+		if ids := claimIDs(t, harness.queue, now.Add(time.Second)); len(ids) != 0 {
+			t.Fatalf("expected a claimed job to stay hidden, got %v", ids)
+		}
+		if ids := claimIDs(t, harness.queue, now.Add(defaultRetryClaimLease)); len(ids) != 1 {
+			t.Fatalf("expected an abandoned claim to be redelivered, got %v", ids)
+		}
+	})
+
+	t.Run("AckedJobsAreNeverClaimedAgain", func(t *testing.T) {
+		harness := newHarness(t)
+		harness.seed(queuedNotification("notif-acked"))
+		job := claimOne(t, harness.queue, now)
+		update := scheduler.AttemptUpdate{Status: string(model.StatusSent), RetryCount: 1, LastAttemptedAt: now}
+		if err := harness.queue.Ack(ctx, job, update); err != nil {
+			t.Fatalf("ack: %v", err)
+		}
+		if ids := claimIDs(t, harness.queue, now.Add(defaultRetryClaimLease+time.Hour)); len(ids) != 0 {
+			t.Fatalf("expected an acked job to be gone, got %v", ids)
+		}
+		if err := harness.queue.Ack(ctx, job, update); err != nil {
+			t.Fatalf("expected a repeated ack not to fail, got %v", err)
+		}
+	})
+
+	t.Run("NackedJobsReturnAtRetryAt", func(t *testing.T) {
+		harness := newHarness(t)
+		harness.seed(queuedNotification("notif-nacked"))
+		job := claimOne(t, harness.queue, now)
+		retryAt := now.Add(time.Minute)
+		update := scheduler.AttemptUpdate{Status: string(model.StatusErrored), RetryCount: 1, LastAttemptedAt: now}
+		if err := harness.queue.Nack(ctx, job, update, retryAt); err != nil {
+			t.Fatalf("nack: %v", err)
+		}
+		if ids := claimIDs(t, harness.queue, retryAt.Add(-time.Second)); len(ids) != 0 {
+			t.Fatalf("expected a nacked job to wait for retryAt, got %v", ids)
+		}
+		retried := claimOne(t, harness.queue, retryAt)
+		if retried.ID != job.ID || retried.RetryCount != 1 || !retried.LastAttemptedAt.Equal(now) {
+			t.Fatalf("expected the nacked attempt to be recorded, got %+v", retried)
+		}
+	})
+}
+
+func TestRetryWorkerNacksFailuresAndAcksDeliveryThroughInjectedQueue(t *testing.T) {
+	emailSender := &stubEmailSender{err: errors.New("smtp unavailable")}
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), emailSender, &stubSmsSender{})
+	queue := newMemoryRetryQueue()
+	queue.enqueue(model.Notification{
+		NotificationID:   "notif-injected",
+		TenantID:         testTenantID,
+		NotificationType: model.NotificationEmail,
+		Recipient:        "user@example.com",
+		Subject:          "Subject",
+		Message:          "Body",
+		Status:           model.StatusQueued,
+	})
+	clock := &drainTestClock{now: time.Now().UTC()}
+	retryInterval := time.Duration(serviceInstance.retryIntervalSec) * time.Second
+	worker, err := scheduler.NewWorker(scheduler.Config{
+		Repository:    newRetryQueueRepository(queue, serviceInstance.maxRetries, retryInterval),
+		Dispatcher:    newNotificationDispatcher(serviceInstance),
+		Logger:        serviceInstance.logger,
+		Interval:      retryInterval,
+		MaxRetries:    serviceInstance.maxRetries,
+		SuccessStatus: string(model.StatusSent),
+		FailureStatus: string(model.StatusErrored),
+		Clock:         clock,
+	})
+	if err != nil {
+		t.Fatalf("worker init error: %v", err)
+	}
+
+	worker.RunOnce(tenantContext())
+	if emailSender.callCount != 1 || queue.size() != 1 || queue.entries[0].job.RetryCount != 1 {
+		t.Fatalf("expected the failed attempt to be nacked, got %d calls and %d queued", emailSender.callCount, queue.size())
+	}
+	if !queue.entries[0].retryAt.Equal(clock.Now().Add(2 * retryInterval)) {
+		t.Fatalf("expected the retry at the scheduler's backoff, got %v", queue.entries[0].retryAt)
+	}
+
+	emailSender.err = nil
+	clock.advance(retryInterval)
+	worker.RunOnce(tenantContext())
+	if emailSender.callCount != 1 {
+		t.Fatalf("expected the backoff to hold the retry, got %d calls", emailSender.callCount)
+	}
+	clock.advance(retryInterval)
+	worker.RunOnce(tenantContext())
+	if emailSender.callCount != 2 || queue.size() != 0 {
+		t.Fatalf("expected the delivered job to be acked, got %d calls and %d queued", emailSender.callCount, queue.size())
+	}
+}
+
+func TestStartRetryWorkerRejectsUnknownRetryQueue(t *testing.T) {
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), &stubEmailSender{}, &stubSmsSender{})
+	serviceInstance.config.RetryQueue = "redis"
+	if _, err := serviceInstance.newRetryQueue(time.Second); err == nil {
+		t.Fatalf("expected an unknown retry queue to be rejected")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	serviceInstance.StartRetryWorker(ctx)
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+)
+
+// ErrSenderOverridesUnsupported rejects a Reply-To or From display name override for a
+// tenant whose email sender cannot set them.
+var ErrSenderOverridesUnsupported = errors.New("sender overrides unsupported by the configured email sender")
+
+// requireSenderOverridePolicy enforces the tenant's sender override policy on a new email
+// notification and refuses overrides its sender could not apply. A sender that cannot
+// be built yet is left to the dispatch.
+func (serviceInstance *notificationServiceImpl) requireSenderOverridePolicy(runtimeCfg tenant.RuntimeConfig, notification model.Notification) error {
+	overrides := notification.SenderOverrides()
+	if overrides.IsZero() {
+		return nil
+	}
+	if !runtimeCfg.Tenant.AllowsSenderOverride(overrides.ReplyToDomain()) {
+		if runtimeCfg.Tenant.SenderOverridePolicy == tenant.SenderOverridesAllowlist {
+			return fmt.Errorf("%w: reply_to domain %q is not allowed for this tenant", model.ErrNotificationSenderOverrideForbidden, overrides.ReplyToDomain())
+		}
+		return fmt.Errorf("%w: this tenant does not allow reply_to or from_display_name", model.ErrNotificationSenderOverrideForbidden)
+	}
+	if notification.ContentEncrypted {
+		return nil
+	}
+	emailSender, err := serviceInstance.emailSenderForTenant(runtimeCfg)
+	if err != nil {
+		return nil
+	}
+	if _, ok := emailSender.(SenderOverrideEmailSender); !ok {
+		return ErrSenderOverridesUnsupported
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+)
+
+func mustSenderOverrideRequest(t *testing.T, request model.NotificationRequest, replyTo string, fromDisplayName string) model.NotificationRequest {
+	t.Helper()
+	overridden, err := request.WithSenderOverrides(replyTo, fromDisplayName)
+	if err != nil {
+		t.Fatalf("WithSenderOverrides error: %v", err)
+	}
+	return overridden
+}
+
+func TestSendNotificationAppliesSenderOverridePolicy(t *testing.T) {
+	originalSendMail := sendMailFunc
+	defer func() { sendMailFunc = originalSendMail }()
+	var transmitted []string
+	sendMailFunc = func(_ context.Context, _ time.Duration, _ string, _ smtp.Auth, _ string, _ []string, msg []byte) error {
+		transmitted = append(transmitted, string(msg))
+		return nil
+	}
+
+	for _, testCase := range []struct {
+		name            string
+		policy          string
+		domains         string
+		replyTo         string
+		fromDisplayName string
+		expectedHeaders string
+		expectedErr     error
+	}{
+		{name: "no overrides under deny", policy: tenant.SenderOverridesDeny, expectedHeaders: "From: noreply@test\r\nTo: user@example.com\r\n"},
+		{name: "deny refuses a reply_to", policy: tenant.SenderOverridesDeny, replyTo: "agent@support.example", expectedErr: model.ErrNotificationSenderOverrideForbidden},
+		{name: "unset policy refuses a display name", fromDisplayName: "Ada", expectedErr: model.ErrNotificationSenderOverrideForbidden},
+		{
+			name: "allow applies both", policy: tenant.SenderOverridesAllow, replyTo: "agent@elsewhere.example", fromDisplayName: "Ada from Support",
+			expectedHeaders: "From: \"Ada from Support\" <noreply@test>\r\nReply-To: agent@elsewhere.example\r\nTo: user@example.com\r\n",
+		},
+		{
+			name: "allowlist accepts a listed domain", policy: tenant.SenderOverridesAllowlist, domains: "support.example", replyTo: "agent@Support.example",
+			expectedHeaders: "From: noreply@test\r\nReply-To: agent@Support.example\r\nTo: user@example.com\r\n",
+		},
+		{name: "allowlist refuses another domain", policy: tenant.SenderOverridesAllowlist, domains: "support.example", replyTo: "agent@elsewhere.example", expectedErr: model.ErrNotificationSenderOverrideForbidden},
+		{
+			name: "allowlist accepts an encoded display name", policy: tenant.SenderOverridesAllowlist, domains: "support.example", fromDisplayName: "Zoë",
+			expectedHeaders: "From: =?utf-8?q?Zo=C3=AB?= <noreply@test>\r\nTo: user@example.com\r\n",
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			transmitted = nil
+			serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), nil, &stubSmsSender{})
+			runtimeCfg := baseRuntimeConfig()
+			runtimeCfg.Tenant.SenderOverridePolicy = testCase.policy
+			runtimeCfg.Tenant.SenderOverrideDomains = testCase.domains
+			ctx := tenant.WithRuntime(context.Background(), runtimeCfg)
+			request := mustSenderOverrideRequest(t, mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", nil, nil), testCase.replyTo, testCase.fromDisplayName)
+
+			response, err := serviceInstance.SendNotification(ctx, request)
+			if testCase.expectedErr != nil {
+				if !errors.Is(err, testCase.expectedErr) || len(transmitted) != 0 {
+					t.Fatalf("expected %v and nothing sent, got %v after %d sends", testCase.expectedErr, err, len(transmitted))
+				}
+				return
+			}
+			if err != nil || response.Status != model.StatusSent {
+				t.Fatalf("expected a sent notification, got %+v (%v)", response, err)
+			}
+			if len(transmitted) != 1 || !strings.HasPrefix(transmitted[0], testCase.expectedHeaders) {
+				t.Fatalf("expected the message to start with\n%q\ngot\n%q", testCase.expectedHeaders, transmitted)
+			}
+			stored, err := model.GetNotificationByID(ctx, serviceInstance.database, testTenantID, response.NotificationID)
+			if err != nil {
+				t.Fatalf("GetNotificationByID error: %v", err)
+			}
+			if stored.ReplyTo != testCase.replyTo || stored.FromDisplayName != testCase.fromDisplayName {
+				t.Fatalf("expected the effective overrides to be stored, got %q/%q", stored.ReplyTo, stored.FromDisplayName)
+			}
+		})
+	}
+}
+
+func TestSendNotificationAppliesReplyToAroundEncryptedContent(t *testing.T) {
+	originalSendMail := sendMailFunc
+	defer func() { sendMailFunc = originalSendMail }()
+	var transmitted []byte
+	sendMailFunc = func(_ context.Context, _ time.Duration, _ string, _ smtp.Auth, _ string, _ []string, msg []byte) error {
+		transmitted = append([]byte(nil), msg...)
+		return nil
+	}
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), nil, &stubSmsSender{})
+	runtimeCfg := baseRuntimeConfig()
+	runtimeCfg.Tenant.SenderOverridePolicy = tenant.SenderOverridesAllow
+	ctx := tenant.WithRuntime(context.Background(), runtimeCfg)
+
+	request := mustSenderOverrideRequest(t, mustEncryptedRequest(t, model.NotificationEmail, "user@example.com", testEncryptedEntity), "agent@support.example", "")
+	if _, err := serviceInstance.SendNotification(ctx, request); err != nil {
+		t.Fatalf("SendNotification error: %v", err)
+	}
+	expected := "From: noreply@test\r\nReply-To: agent@support.example\r\nTo: user@example.com\r\nSubject: Subject\r\nMIME-Version: 1.0\r\n" + testEncryptedEntity
+	if string(transmitted) != expected {
+		t.Fatalf("expected the Reply-To outside the untouched entity:\n%q\ngot\n%q", expected, transmitted)
+	}
+}
+
+func TestSendNotificationRejectsSenderOverridesForPlainSender(t *testing.T) {
+	emailSender := &stubEmailSender{}
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), emailSender, &stubSmsSender{})
+	runtimeCfg := baseRuntimeConfig()
+	runtimeCfg.Tenant.SenderOverridePolicy = tenant.SenderOverridesAllow
+	request := mustSenderOverrideRequest(t, mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", nil, nil), "agent@support.example", "")
+
+	_, err := serviceInstance.SendNotification(tenant.WithRuntime(context.Background(), runtimeCfg), request)
+	if !errors.Is(err, ErrSenderOverridesUnsupported) || emailSender.callCount != 0 {
+		t.Fatalf("expected ErrSenderOverridesUnsupported before any send, got %v after %d sends", err, emailSender.callCount)
+	}
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+)
+
+// cancelSentSms withdraws an SMS the provider accepted within server.smsCancelGraceSec
+// of its send. Outside the window, without a provider message id, with a sender that
+// cannot cancel, or when the provider refuses because the message already left its
+// queue, it returns ErrNotificationNotEditable.
+func (serviceInstance *notificationServiceImpl) cancelSentSms(ctx context.Context, runtimeCfg tenant.RuntimeConfig, record *model.Notification, reason string, actor string) (model.NotificationResponse, error) {
+	graceWindow := time.Duration(serviceInstance.config.SMSCancelGraceSec) * time.Second
+	currentTime := serviceInstance.currentTime()
+	if graceWindow <= 0 || record.ProviderMessageID == "" || currentTime.Sub(record.LastAttemptedAt) > graceWindow {
+		serviceInstance.logger.Warn("Rejecting cancellation because the SMS is outside its cancel grace window", "notification_id", record.NotificationID, "sent_at", record.LastAttemptedAt)
+		return model.NotificationResponse{}, ErrNotificationNotEditable
+	}
+	smsSender, senderErr := serviceInstance.smsSenderForTenant(runtimeCfg)
+	canceller, canCancel := smsSender.(SmsCanceller)
+	if senderErr != nil || !canCancel {
+		serviceInstance.logger.Warn("Rejecting cancellation because the SMS sender cannot cancel", "notification_id", record.NotificationID, "error", senderErr)
+		return model.NotificationResponse{}, ErrNotificationNotEditable
+	}
+
+	cancelCtx := ctx
+	if operationTimeout := time.Duration(serviceInstance.config.OperationTimeoutSec) * time.Second; operationTimeout > 0 {
+		var cancel context.CancelFunc
+		cancelCtx, cancel = context.WithTimeout(ctx, operationTimeout)
+		defer cancel()
+	}
+	if cancelErr := canceller.CancelSms(cancelCtx, record.ProviderMessageID); cancelErr != nil {
+		var apiError *TwilioAPIError
+		if errors.As(cancelErr, &apiError) && apiError.StatusCode < 500 {
+			serviceInstance.logger.Warn("Provider refused to cancel sent SMS", "notification_id", record.NotificationID, "error", cancelErr)
+			return model.NotificationResponse{}, fmt.Errorf("%w: the provider refused to cancel the sent SMS", ErrNotificationNotEditable)
+		}
+		serviceInstance.logger.Error("Failed to cancel sent SMS at the provider", "notification_id", record.NotificationID, "error", cancelErr)
+		return model.NotificationResponse{}, cancelErr
+	}
+
+	record.MarkCancelled(currentTime, reason, actor)
+	if saveErr := model.SaveNotification(ctx, serviceInstance.database, record); saveErr != nil {
+		serviceInstance.logger.Error("Failed to save SMS cancelled at the provider", "notification_id", record.NotificationID, "error", saveErr)
+		return model.NotificationResponse{}, saveErr
+	}
+	serviceInstance.auditCancellation(*record)
+	serviceInstance.recordStateChange(ctx, record, model.StatusSent)
+	return model.NewNotificationResponse(*record), nil
+}
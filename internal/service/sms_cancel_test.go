@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+)
+
+// cancellingSmsSender records provider cancellations and fails them with err.
+type cancellingSmsSender struct {
+	stubSmsSender
+	cancelled []string
+	err       error
+}
+
+func (sender *cancellingSmsSender) CancelSms(_ context.Context, providerMessageID string) error {
+	sender.cancelled = append(sender.cancelled, providerMessageID)
+	return sender.err
+}
+
+func newSMSCancelTestService(t *testing.T, smsSender SmsSender, sentAt time.Time) *notificationServiceImpl {
+	t.Helper()
+	database := openIsolatedDatabase(t)
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(database, &stubEmailSender{}, smsSender)
+	serviceInstance.config.SMSCancelGraceSec = 30
+	serviceInstance.clock = &adjustableClock{now: sentAt}
+	insertNotificationRecord(t, database, model.Notification{
+		NotificationID:    "notif-sms",
+		NotificationType:  model.NotificationSMS,
+		Recipient:         "+15550100",
+		Message:           "Your code is 1234",
+		Status:            model.StatusSent,
+		ProviderMessageID: `{"sid":"SM123","status":"queued"}`,
+		LastAttemptedAt:   sentAt,
+		CreatedAt:         sentAt,
+		UpdatedAt:         sentAt,
+	})
+	return serviceInstance
+}
+
+func TestCancelNotificationCancelsSentSMSWithinGraceWindow(t *testing.T) {
+	sentAt := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	smsSender := &cancellingSmsSender{}
+	serviceInstance := newSMSCancelTestService(t, smsSender, sentAt)
+	serviceInstance.clock = &adjustableClock{now: sentAt.Add(10 * time.Second)}
+
+	response, err := serviceInstance.CancelNotification(tenantContext(), "notif-sms", "duplicate", "ops@example.com")
+	if err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if len(smsSender.cancelled) != 1 || smsSender.cancelled[0] != `{"sid":"SM123","status":"queued"}` {
+		t.Fatalf("expected one provider cancellation, got %v", smsSender.cancelled)
+	}
+	if response.Status != model.StatusCancelled || response.CancelledBy != "ops@example.com" {
+		t.Fatalf("unexpected response %+v", response)
+	}
+	stored, err := model.MustGetNotificationByID(context.Background(), serviceInstance.database, testTenantID, "notif-sms")
+	if err != nil || stored.Status != model.StatusCancelled || stored.CancelReason != "duplicate" {
+		t.Fatalf("expected a stored cancellation, got %+v (%v)", stored, err)
+	}
+}
+
+func TestCancelNotificationRejectsSentSMSOutsideGraceWindow(t *testing.T) {
+	sentAt := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	smsSender := &cancellingSmsSender{}
+	serviceInstance := newSMSCancelTestService(t, smsSender, sentAt)
+	serviceInstance.clock = &adjustableClock{now: sentAt.Add(31 * time.Second)}
+
+	if _, err := serviceInstance.CancelNotification(tenantContext(), "notif-sms", "", ""); !errors.Is(err, ErrNotificationNotEditable) {
+		t.Fatalf("expected ErrNotificationNotEditable, got %v", err)
+	}
+	serviceInstance.clock = &adjustableClock{now: sentAt.Add(time.Second)}
+	serviceInstance.config.SMSCancelGraceSec = 0
+	if _, err := serviceInstance.CancelNotification(tenantContext(), "notif-sms", "", ""); !errors.Is(err, ErrNotificationNotEditable) {
+		t.Fatalf("expected ErrNotificationNotEditable without a grace window, got %v", err)
+	}
+	if len(smsSender.cancelled) != 0 {
+		t.Fatalf("expected no provider cancellation, got %v", smsSender.cancelled)
+	}
+}
+
+func TestCancelNotificationRejectsSentSMSTheProviderCannotCancel(t *testing.T) {
+	sentAt := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	unsupported := newSMSCancelTestService(t, &stubSmsSender{}, sentAt)
+	if _, err := unsupported.CancelNotification(tenantContext(), "notif-sms", "", ""); !errors.Is(err, ErrNotificationNotEditable) {
+		t.Fatalf("expected ErrNotificationNotEditable for a sender without cancel support, got %v", err)
+	}
+
+	refusing := &cancellingSmsSender{err: &TwilioAPIError{StatusCode: 400, Code: 30409, Body: `{"code":30409}`}}
+	serviceInstance := newSMSCancelTestService(t, refusing, sentAt)
+	if _, err := serviceInstance.CancelNotification(tenantContext(), "notif-sms", "", ""); !errors.Is(err, ErrNotificationNotEditable) {
+		t.Fatalf("expected ErrNotificationNotEditable after a provider refusal, got %v", err)
+	}
+	stored, err := model.MustGetNotificationByID(context.Background(), serviceInstance.database, testTenantID, "notif-sms")
+	if err != nil || stored.Status != model.StatusSent {
+		t.Fatalf("expected the notification to stay sent, got %+v (%v)", stored, err)
+	}
+}
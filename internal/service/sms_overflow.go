@@ -0,0 +1,38 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+)
+
+// applySMSLimits enforces the tenant's SMS segment cap on a new notification. Bodies over
+// the cap are rejected unless the request, or failing that the tenant, chose truncation.
+// Caller-encrypted bodies are never truncated.
+func applySMSLimits(runtimeCfg tenant.RuntimeConfig, policy model.SMSOverflowPolicy, notification *model.Notification) error {
+	maxSegments := runtimeCfg.Tenant.SMSMaxSegments
+	if notification.NotificationType != model.NotificationSMS || maxSegments <= 0 {
+		return nil
+	}
+	segments := model.SMSSegmentCount(notification.Message)
+	if segments <= maxSegments {
+		return nil
+	}
+	if policy == "" {
+		policy = model.SMSOverflowPolicy(runtimeCfg.Tenant.SMSOverflowPolicy)
+	}
+	if policy != model.SMSOverflowTruncate || notification.ContentEncrypted {
+		return fmt.Errorf("%w: %d segments exceed the limit of %d", model.ErrNotificationSMSTooLong, segments, maxSegments)
+	}
+	suffix := runtimeCfg.Tenant.SMSTruncationSuffix
+	if suffix == "" {
+		suffix = model.DefaultSMSTruncationSuffix
+	}
+	truncated, ok := model.TruncateSMS(notification.Message, maxSegments, suffix)
+	if !ok {
+		return fmt.Errorf("%w: the truncation suffix does not fit in %d segments", model.ErrNotificationSMSTooLong, maxSegments)
+	}
+	notification.MarkSMSTruncated(truncated)
+	return nil
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+)
+
+func smsLimitsContext(maxSegments int, overflowPolicy string) context.Context {
+	runtimeCfg := baseRuntimeConfig()
+	runtimeCfg.Tenant.SMSMaxSegments = maxSegments
+	runtimeCfg.Tenant.SMSOverflowPolicy = overflowPolicy
+	return tenant.WithRuntime(context.Background(), runtimeCfg)
+}
+
+func TestSendNotificationRejectsOversizedSMSByDefault(t *testing.T) {
+	smsSender := &stubSmsSender{}
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), &stubEmailSender{}, smsSender)
+
+	_, err := serviceInstance.SendNotification(smsLimitsContext(1, ""), mustNotificationRequest(t, model.NotificationSMS, "+15555550100", "", strings.Repeat("a", 161), nil, nil))
+	if !errors.Is(err, model.ErrNotificationSMSTooLong) {
+		t.Fatalf("expected oversized SMS to be rejected, got %v", err)
+	}
+	if smsSender.callCount != 0 {
+		t.Fatalf("expected no dispatch for a rejected SMS")
+	}
+
+	response, err := serviceInstance.SendNotification(smsLimitsContext(1, ""), mustNotificationRequest(t, model.NotificationSMS, "+15555550100", "", strings.Repeat("a", 160), nil, nil))
+	if err != nil || response.Truncated || len(response.Warnings) != 0 {
+		t.Fatalf("expected a body at the exact limit to pass untouched, got %+v (%v)", response, err)
+	}
+}
+
+func TestSendNotificationTruncatesOversizedSMS(t *testing.T) {
+	smsSender := &stubSmsSender{}
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), &stubEmailSender{}, smsSender)
+	message := strings.Repeat("ж", 100)
+
+	response, err := serviceInstance.SendNotification(smsLimitsContext(1, tenant.SMSOverflowTruncate), mustNotificationRequest(t, model.NotificationSMS, "+15555550100", "", message, nil, nil))
+	if err != nil {
+		t.Fatalf("SendNotification error: %v", err)
+	}
+	expected := strings.Repeat("ж", 67) + model.DefaultSMSTruncationSuffix
+	if response.Message != expected || !response.Truncated || response.OriginalMessageLength != 100 {
+		t.Fatalf("unexpected truncated response %+v", response)
+	}
+	if len(response.Warnings) != 1 || response.Warnings[0] != model.SMSTruncatedWarning {
+		t.Fatalf("expected a truncation warning, got %v", response.Warnings)
+	}
+	if smsSender.lastMessage != expected {
+		t.Fatalf("expected the provider to receive the truncated body, got %q", smsSender.lastMessage)
+	}
+	stored, err := serviceInstance.GetNotificationStatus(smsLimitsContext(1, tenant.SMSOverflowTruncate), response.NotificationID)
+	if err != nil || stored.Message != expected || !stored.Truncated || stored.OriginalMessageLength != 100 {
+		t.Fatalf("expected the truncation to be persisted, got %+v (%v)", stored, err)
+	}
+}
+
+func TestSendNotificationRequestPolicyOverridesTenantDefault(t *testing.T) {
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), &stubEmailSender{}, &stubSmsSender{})
+	request, err := mustNotificationRequest(t, model.NotificationSMS, "+15555550100", "", strings.Repeat("a", 200), nil, nil).WithSMSOverflowPolicy(string(model.SMSOverflowReject))
+	if err != nil {
+		t.Fatalf("WithSMSOverflowPolicy error: %v", err)
+	}
+	if _, err := serviceInstance.SendNotification(smsLimitsContext(1, tenant.SMSOverflowTruncate), request); !errors.Is(err, model.ErrNotificationSMSTooLong) {
+		t.Fatalf("expected the request's reject policy to win, got %v", err)
+	}
+}
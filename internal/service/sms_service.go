@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,10 +14,50 @@ import (
 	"log/slog"
 )
 
+const twilioAPIBaseURL = "https://api.twilio.com/2010-04-01"
+
 type SmsSender interface {
 	SendSms(ctx context.Context, recipient string, message string) (string, error)
 }
 
+// Twilio error codes that describe the account rather than the message.
+const (
+	twilioCodeAuthenticationFailed = 20003
+	twilioCodeAccountNotActive     = 20005
+	twilioCodeAccountSuspended     = 30002
+)
+
+// TwilioAPIError reports a non-success HTTP response from the Twilio API. Code is the
+// Twilio error code from the response body, or zero when the body carried none.
+type TwilioAPIError struct {
+	StatusCode int
+	Code       int
+	Body       string
+}
+
+// newTwilioAPIError describes a failed response and marks rejected credentials and
+// suspended or inactive accounts as a ProviderAccountError.
+func newTwilioAPIError(statusCode int, body []byte) error {
+	var payload struct {
+		Code int `json:"code"`
+	}
+	_ = json.Unmarshal(body, &payload)
+	apiError := &TwilioAPIError{StatusCode: statusCode, Code: payload.Code, Body: string(body)}
+	switch {
+	case statusCode == http.StatusUnauthorized,
+		payload.Code == twilioCodeAuthenticationFailed,
+		payload.Code == twilioCodeAccountNotActive,
+		payload.Code == twilioCodeAccountSuspended:
+		return &ProviderAccountError{Err: apiError}
+	default:
+		return apiError
+	}
+}
+
+func (apiError *TwilioAPIError) Error() string {
+	return fmt.Sprintf("twilio API error: %s", apiError.Body)
+}
+
 type TwilioSmsSender struct {
 	AccountSID string
 	AuthToken  string
@@ -41,7 +82,7 @@ func (senderInstance *TwilioSmsSender) SendSms(ctx context.Context, recipient st
 	formData.Set("From", senderInstance.FromNumber)
 	formData.Set("Body", message)
 
-	apiEndpoint := fmt.Sprintf("https://api.twilio.com/2010-04-01/Accounts/%s/Messages.json", senderInstance.AccountSID)
+	apiEndpoint := fmt.Sprintf("%s/Accounts/%s/Messages.json", twilioAPIBaseURL, senderInstance.AccountSID)
 	requestInstance, requestError := http.NewRequestWithContext(ctx, http.MethodPost, apiEndpoint, strings.NewReader(formData.Encode()))
 	if requestError != nil {
 		senderInstance.Logger.Error("Failed to create Twilio request", "error", requestError)
@@ -60,8 +101,80 @@ func (senderInstance *TwilioSmsSender) SendSms(ctx context.Context, recipient st
 	responseBody, _ := io.ReadAll(responseInstance.Body)
 	if responseInstance.StatusCode >= 300 {
 		senderInstance.Logger.Error("Twilio API returned error", "status", responseInstance.StatusCode, "body", string(responseBody))
-		return "", fmt.Errorf("twilio API error: %s", string(responseBody))
+		return "", newTwilioAPIError(responseInstance.StatusCode, responseBody)
 	}
 
 	return string(responseBody), nil
 }
+
+// SmsCanceller is implemented by SMS senders that can withdraw a message the provider
+// accepted but has not delivered yet.
+type SmsCanceller interface {
+	CancelSms(ctx context.Context, providerMessageID string) error
+}
+
+// CancelSms sets the Twilio message's status to canceled. Twilio refuses with a 4xx
+// TwilioAPIError once the message has left its queue.
+func (senderInstance *TwilioSmsSender) CancelSms(ctx context.Context, providerMessageID string) error {
+	messageSID := twilioMessageSID(providerMessageID)
+	if messageSID == "" {
+		return fmt.Errorf("twilio cancel: no message sid")
+	}
+	formData := url.Values{}
+	formData.Set("Status", "canceled")
+
+	apiEndpoint := fmt.Sprintf("%s/Accounts/%s/Messages/%s.json", twilioAPIBaseURL, url.PathEscape(senderInstance.AccountSID), url.PathEscape(messageSID))
+	requestInstance, requestError := http.NewRequestWithContext(ctx, http.MethodPost, apiEndpoint, strings.NewReader(formData.Encode()))
+	if requestError != nil {
+		return requestError
+	}
+	requestInstance.SetBasicAuth(senderInstance.AccountSID, senderInstance.AuthToken)
+	requestInstance.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	responseInstance, responseError := senderInstance.HTTPClient.Do(requestInstance)
+	if responseError != nil {
+		return responseError
+	}
+	defer responseInstance.Body.Close()
+
+	responseBody, _ := io.ReadAll(responseInstance.Body)
+	if responseInstance.StatusCode >= 300 {
+		return newTwilioAPIError(responseInstance.StatusCode, responseBody)
+	}
+	return nil
+}
+
+// twilioMessageSID returns the sid of a stored provider message id, which holds the
+// message resource Twilio returned on send, or the sid itself.
+func twilioMessageSID(providerMessageID string) string {
+	var resource struct {
+		SID string `json:"sid"`
+	}
+	if err := json.Unmarshal([]byte(providerMessageID), &resource); err == nil {
+		return strings.TrimSpace(resource.SID)
+	}
+	return strings.TrimSpace(providerMessageID)
+}
+
+// VerifyCredentials fetches the Twilio account resource, which succeeds only for a
+// valid account SID and auth token and sends nothing.
+func (senderInstance *TwilioSmsSender) VerifyCredentials(ctx context.Context) error {
+	apiEndpoint := fmt.Sprintf("%s/Accounts/%s.json", twilioAPIBaseURL, url.PathEscape(senderInstance.AccountSID))
+	requestInstance, requestError := http.NewRequestWithContext(ctx, http.MethodGet, apiEndpoint, nil)
+	if requestError != nil {
+		return requestError
+	}
+	requestInstance.SetBasicAuth(senderInstance.AccountSID, senderInstance.AuthToken)
+
+	responseInstance, responseError := senderInstance.HTTPClient.Do(requestInstance)
+	if responseError != nil {
+		return responseError
+	}
+	defer responseInstance.Body.Close()
+
+	responseBody, _ := io.ReadAll(responseInstance.Body)
+	if responseInstance.StatusCode >= 300 {
+		return &TwilioAPIError{StatusCode: responseInstance.StatusCode, Body: string(responseBody)}
+	}
+	return nil
+}
@@ -91,6 +91,50 @@ func TestTwilioSmsSenderErrorStatus(t *testing.T) {
 	}
 }
 
+func TestTwilioSmsSenderClassifiesAccountFailures(t *testing.T) {
+	testCases := []struct {
+		name           string
+		statusCode     int
+		body           string
+		accountFailure bool
+		expectedCode   int
+	}{
+		{name: "rejected credentials", statusCode: 401, body: `{"code": 20003, "message": "Authenticate"}`, accountFailure: true, expectedCode: 20003},
+		{name: "inactive account", statusCode: 403, body: `{"code": 20005, "message": "Account not active"}`, accountFailure: true, expectedCode: 20005},
+		{name: "suspended account", statusCode: 400, body: `{"code": 30002, "message": "Account suspended"}`, accountFailure: true, expectedCode: 30002},
+		{name: "invalid recipient", statusCode: 400, body: `{"code": 21211, "message": "Invalid 'To' Phone Number"}`, expectedCode: 21211},
+		{name: "unparsable body", statusCode: 500, body: "fail"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			sender := &TwilioSmsSender{
+				AccountSID: "sid",
+				AuthToken:  "token",
+				FromNumber: "+1000",
+				HTTPClient: &http.Client{
+					Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+						return &http.Response{
+							StatusCode: testCase.statusCode,
+							Body:       io.NopCloser(bytes.NewBufferString(testCase.body)),
+							Header:     make(http.Header),
+						}, nil
+					}),
+				},
+				Logger: newDiscardLogger(),
+			}
+			_, err := sender.SendSms(context.Background(), "+1222", "Hello")
+			var accountError *ProviderAccountError
+			if errors.As(err, &accountError) != testCase.accountFailure {
+				t.Fatalf("expected account failure %v, got %v", testCase.accountFailure, err)
+			}
+			var apiError *TwilioAPIError
+			if !errors.As(err, &apiError) || apiError.StatusCode != testCase.statusCode || apiError.Code != testCase.expectedCode {
+				t.Fatalf("expected the Twilio error to stay wrapped, got %v", err)
+			}
+		})
+	}
+}
+
 func TestTwilioSmsSenderConstructorAndRequestFailures(t *testing.T) {
 	constructed := NewTwilioSmsSender("sid", "token", "+1000", newDiscardLogger(), config.Config{ConnectionTimeoutSec: 3})
 	if constructed.AccountSID != "sid" || constructed.HTTPClient == nil {
@@ -122,3 +166,36 @@ func TestTwilioSmsSenderConstructorAndRequestFailures(t *testing.T) {
 		t.Fatalf("expected HTTP client error, got %v", err)
 	}
 }
+
+func TestTwilioSmsSenderCancelSmsUpdatesMessageStatus(t *testing.T) {
+	var capturedURL, capturedBody string
+	sender := &TwilioSmsSender{
+		AccountSID: "AC1",
+		AuthToken:  "token",
+		HTTPClient: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			capturedURL = req.URL.String()
+			body, _ := io.ReadAll(req.Body)
+			capturedBody = string(body)
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewBufferString(`{"status":"canceled"}`)), Header: make(http.Header)}, nil
+		})},
+		Logger: newDiscardLogger(),
+	}
+
+	if err := sender.CancelSms(context.Background(), `{"sid":"SM123","status":"queued"}`); err != nil {
+		t.Fatalf("CancelSms returned error: %v", err)
+	}
+	if capturedURL != twilioAPIBaseURL+"/Accounts/AC1/Messages/SM123.json" || capturedBody != "Status=canceled" {
+		t.Fatalf("unexpected cancel request %s %q", capturedURL, capturedBody)
+	}
+	if err := sender.CancelSms(context.Background(), ""); err == nil {
+		t.Fatalf("expected an error without a message sid")
+	}
+
+	sender.HTTPClient = &http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: 400, Body: io.NopCloser(bytes.NewBufferString(`{"code":30409}`)), Header: make(http.Header)}, nil
+	})}
+	var apiError *TwilioAPIError
+	if err := sender.CancelSms(context.Background(), "SM123"); !errors.As(err, &apiError) || apiError.Code != 30409 {
+		t.Fatalf("expected a TwilioAPIError, got %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"sync"
+	"time"
+)
+
+// smtpPoolQuitTimeout bounds the QUIT sent to a pooled connection being closed.
+const smtpPoolQuitTimeout = 2 * time.Second
+
+// smtpPool keeps authenticated SMTP connections to one provider account open between
+// messages. At most size connections are open at once, busy or idle; a send waits for
+// one to free up. Connections left idle for idleTimeout are closed.
+type smtpPool struct {
+	size        int
+	idleTimeout time.Duration
+	dial        func(context.Context) (*pooledSMTPConnection, error)
+	slots       chan struct{}
+
+	mutex  sync.Mutex
+	idle   []*pooledSMTPConnection
+	reaper *time.Timer
+	closed bool
+}
+
+// pooledSMTPConnection is an authenticated SMTP session that can carry several messages.
+type pooledSMTPConnection struct {
+	connection net.Conn
+	client     *smtp.Client
+	idleSince  time.Time
+}
+
+func newSMTPPool(size int, idleTimeout time.Duration, dial func(context.Context) (*pooledSMTPConnection, error)) *smtpPool {
+	return &smtpPool{
+		size:        size,
+		idleTimeout: idleTimeout,
+		dial:        dial,
+		slots:       make(chan struct{}, size),
+	}
+}
+
+// send delivers one message over an idle connection, or a new one when none is idle.
+// The connection returns to the pool only when the provider accepted the message.
+func (pool *smtpPool) send(ctx context.Context, fromAddress string, recipients []string, rawMessage []byte) error {
+	select {
+	case pool.slots <- struct{}{}:
+	case <-ctx.Done():
+		return fmt.Errorf("wait for an SMTP connection: %w", ctx.Err())
+	}
+	defer func() { <-pool.slots }()
+
+	pooled, err := pool.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	stopWatch := closeOnDone(ctx, pooled.connection)
+	transmitErr := transmitMessage(smtpClientWrapper{client: pooled.client}, fromAddress, recipients, rawMessage)
+	if !stopWatch() || transmitErr != nil {
+		pooled.close()
+		return abortedByContext(ctx, transmitErr)
+	}
+	pool.release(pooled)
+	return nil
+}
+
+// acquire returns the most recently used idle connection that still answers NOOP, or
+// dials a new one.
+func (pool *smtpPool) acquire(ctx context.Context) (*pooledSMTPConnection, error) {
+	for {
+		pooled := pool.popIdle()
+		if pooled == nil {
+			return pool.dial(ctx)
+		}
+		stopWatch := closeOnDone(ctx, pooled.connection)
+		noopErr := pooled.client.Noop()
+		if stopWatch() && noopErr == nil {
+			return pooled, nil
+		}
+		pooled.close()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (pool *smtpPool) popIdle() *pooledSMTPConnection {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	if len(pool.idle) == 0 {
+		return nil
+	}
+	pooled := pool.idle[len(pool.idle)-1]
+	pool.idle = pool.idle[:len(pool.idle)-1]
+	return pooled
+}
+
+func (pool *smtpPool) release(pooled *pooledSMTPConnection) {
+	pool.mutex.Lock()
+	if pool.closed || pool.idleTimeout <= 0 {
+		pool.mutex.Unlock()
+		pooled.close()
+		return
+	}
+	pooled.idleSince = time.Now()
+	pool.idle = append(pool.idle, pooled)
+	if pool.reaper == nil {
+		pool.reaper = time.AfterFunc(pool.idleTimeout, pool.reapIdle)
+	}
+	pool.mutex.Unlock()
+}
+
+// reapIdle closes connections idle for idleTimeout and rearms itself for the oldest
+// connection still idle.
+func (pool *smtpPool) reapIdle() {
+	pool.mutex.Lock()
+	now := time.Now()
+	var expired []*pooledSMTPConnection
+	kept := pool.idle[:0]
+	for _, pooled := range pool.idle {
+		if now.Sub(pooled.idleSince) >= pool.idleTimeout {
+			expired = append(expired, pooled)
+			continue
+		}
+		kept = append(kept, pooled)
+	}
+	pool.idle = kept
+	pool.reaper = nil
+	if len(kept) > 0 && !pool.closed {
+		pool.reaper = time.AfterFunc(pool.idleTimeout-now.Sub(kept[0].idleSince), pool.reapIdle)
+	}
+	pool.mutex.Unlock()
+	for _, pooled := range expired {
+		pooled.close()
+	}
+}
+
+// idleCount reports how many connections are open and unused.
+func (pool *smtpPool) idleCount() int {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	return len(pool.idle)
+}
+
+// close closes every idle connection; connections in use close when their send ends.
+func (pool *smtpPool) close() {
+	pool.mutex.Lock()
+	idle := pool.idle
+	pool.idle = nil
+	pool.closed = true
+	if pool.reaper != nil {
+		pool.reaper.Stop()
+		pool.reaper = nil
+	}
+	pool.mutex.Unlock()
+	for _, pooled := range idle {
+		pooled.close()
+	}
+}
+
+func (pooled *pooledSMTPConnection) close() {
+	pooled.connection.SetDeadline(time.Now().Add(smtpPoolQuitTimeout))
+	pooled.client.Quit()
+	pooled.connection.Close()
+}
+
+// dialPooledConnection opens and authenticates a connection the way SendRawEmail does
+// for a single message: implicit TLS on port 465, STARTTLS elsewhere when offered.
+func (senderInstance *SMTPEmailSender) dialPooledConnection(ctx context.Context) (*pooledSMTPConnection, error) {
+	host := senderInstance.Config.Host
+	serverAddr := net.JoinHostPort(host, senderInstance.Config.Port)
+	dialer := &net.Dialer{Timeout: time.Duration(senderInstance.Config.Timeouts.ConnectionTimeoutSec) * time.Second}
+	implicitTLS := senderInstance.Config.Port == "465"
+	var connection net.Conn
+	var dialErr error
+	if implicitTLS {
+		connection, dialErr = dialTLSFunc(ctx, dialer, "tcp", serverAddr, &tls.Config{
+			InsecureSkipVerify: true, // Matches SendRawEmail.
+			ServerName:         host,
+		})
+	} else {
+		connection, dialErr = dialer.DialContext(ctx, "tcp", serverAddr)
+	}
+	if dialErr != nil {
+		return nil, abortedByContext(ctx, fmt.Errorf("failed to dial: %w", dialErr))
+	}
+	defer closeOnDone(ctx, connection)()
+
+	client, clientErr := smtp.NewClient(connection, host)
+	if clientErr != nil {
+		connection.Close()
+		return nil, abortedByContext(ctx, fmt.Errorf("failed to create SMTP client: %w", clientErr))
+	}
+	pooled := &pooledSMTPConnection{connection: connection, client: client}
+	if !implicitTLS {
+		if supported, _ := client.Extension("STARTTLS"); supported {
+			if tlsErr := client.StartTLS(&tls.Config{ServerName: host}); tlsErr != nil {
+				pooled.close()
+				return nil, abortedByContext(ctx, fmt.Errorf("failed to start TLS: %w", tlsErr))
+			}
+		}
+	}
+	if supported, _ := client.Extension("AUTH"); supported || implicitTLS {
+		smtpAuth := smtp.PlainAuth("", senderInstance.Config.Username, senderInstance.Config.Password, host)
+		if authErr := client.Auth(smtpAuth); authErr != nil {
+			pooled.close()
+			return nil, abortedByContext(ctx, fmt.Errorf("failed to authenticate: %w", authErr))
+		}
+	}
+	return pooled, nil
+}
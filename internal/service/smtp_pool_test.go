@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/config"
+	"github.com/tyemirov/pinguin/internal/tenant"
+)
+
+// countingSMTPServer accepts any message after holding DATA for dataDelay and records
+// how many connections it has seen and how many were open at once.
+type countingSMTPServer struct {
+	listener  net.Listener
+	dataDelay time.Duration
+
+	mutex    sync.Mutex
+	open     int
+	maxOpen  int
+	accepted int
+	messages int
+}
+
+func startCountingSMTPServer(t *testing.T, dataDelay time.Duration) *countingSMTPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := &countingSMTPServer{listener: listener, dataDelay: dataDelay}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			connection, acceptErr := listener.Accept()
+			if acceptErr != nil {
+				return
+			}
+			go server.serve(connection)
+		}
+	}()
+	return server
+}
+
+func (server *countingSMTPServer) serve(connection net.Conn) {
+	server.mutex.Lock()
+	server.accepted++
+	server.open++
+	server.maxOpen = max(server.maxOpen, server.open)
+	server.mutex.Unlock()
+	defer func() {
+		server.mutex.Lock()
+		server.open--
+		server.mutex.Unlock()
+		connection.Close()
+	}()
+	reader := textproto.NewConn(connection)
+	reader.PrintfLine("220 localhost ESMTP")
+	for {
+		line, readErr := reader.ReadLine()
+		if readErr != nil {
+			return
+		}
+		switch {
+		case strings.HasPrefix(line, "EHLO"):
+			reader.PrintfLine("250 localhost")
+		case line == "DATA":
+			reader.PrintfLine("354 go ahead")
+			if _, readErr := reader.ReadDotLines(); readErr != nil {
+				return
+			}
+			time.Sleep(server.dataDelay)
+			server.mutex.Lock()
+			server.messages++
+			server.mutex.Unlock()
+			reader.PrintfLine("250 accepted")
+		case line == "QUIT":
+			reader.PrintfLine("221 bye")
+			return
+		default:
+			reader.PrintfLine("250 ok")
+		}
+	}
+}
+
+func (server *countingSMTPServer) counts() (open int, maxOpen int, accepted int, messages int) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return server.open, server.maxOpen, server.accepted, server.messages
+}
+
+func (server *countingSMTPServer) email(username string, poolSize int) tenant.EmailCredentials {
+	host, port, _ := net.SplitHostPort(server.listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	return tenant.EmailCredentials{
+		Host:        host,
+		Port:        portNumber,
+		Username:    username,
+		Password:    "secret",
+		FromAddress: "noreply@example.com",
+		PoolSize:    poolSize,
+	}
+}
+
+func newSMTPPoolTestService(cfg config.Config) *notificationServiceImpl {
+	return &notificationServiceImpl{
+		logger:       slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})),
+		config:       cfg,
+		emailSenders: make(map[string]EmailSender),
+		smsSenders:   make(map[string]SmsSender),
+	}
+}
+
+func TestEmailSenderForTenantOpensConnectionsUpToPoolSize(t *testing.T) {
+	serviceInstance := newSMTPPoolTestService(config.Config{SMTPPoolSize: 1})
+	smallServer := startCountingSMTPServer(t, 50*time.Millisecond)
+	largeServer := startCountingSMTPServer(t, 50*time.Millisecond)
+	tenants := []struct {
+		runtime tenant.RuntimeConfig
+		server  *countingSMTPServer
+		want    int
+	}{
+		// The small tenant inherits server.smtpPoolSize; the heavy sender overrides it.
+		{runtime: tenant.RuntimeConfig{Tenant: tenant.Tenant{ID: "tenant-small"}, Email: smallServer.email("small", 0)}, server: smallServer, want: 1},
+		{runtime: tenant.RuntimeConfig{Tenant: tenant.Tenant{ID: "tenant-heavy"}, Email: largeServer.email("heavy", 4)}, server: largeServer, want: 4},
+	}
+
+	const sendsPerTenant = 8
+	var group sync.WaitGroup
+	errs := make(chan error, 2*sendsPerTenant)
+	for _, tenantCase := range tenants {
+		sender, err := serviceInstance.emailSenderForTenant(tenantCase.runtime)
+		if err != nil {
+			t.Fatalf("resolve sender for %s: %v", tenantCase.runtime.Tenant.ID, err)
+		}
+		t.Cleanup(sender.(*SMTPEmailSender).Close)
+		for index := 0; index < sendsPerTenant; index++ {
+			group.Add(1)
+			go func() {
+				defer group.Done()
+				errs <- sender.SendEmail(context.Background(), fmt.Sprintf("user-%d@example.com", index), "Subject", "Body", nil)
+			}()
+		}
+	}
+	group.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("send: %v", err)
+		}
+	}
+	for _, tenantCase := range tenants {
+		open, maxOpen, accepted, messages := tenantCase.server.counts()
+		if maxOpen != tenantCase.want || accepted != tenantCase.want {
+			t.Fatalf("%s: expected %d concurrent connections, got max %d of %d dialed", tenantCase.runtime.Tenant.ID, tenantCase.want, maxOpen, accepted)
+		}
+		if messages != sendsPerTenant || open != tenantCase.want {
+			t.Fatalf("%s: expected %d messages over %d kept connections, got %d over %d", tenantCase.runtime.Tenant.ID, sendsPerTenant, tenantCase.want, messages, open)
+		}
+	}
+}
+
+func TestSMTPPoolReapsIdleConnections(t *testing.T) {
+	server := startCountingSMTPServer(t, 0)
+	credentials := server.email("user", 2)
+	sender := NewSMTPEmailSender(SMTPConfig{
+		Host:            credentials.Host,
+		Port:            strconv.Itoa(credentials.Port),
+		Username:        credentials.Username,
+		Password:        credentials.Password,
+		FromAddress:     credentials.FromAddress,
+		PoolSize:        credentials.PoolSize,
+		PoolIdleTimeout: 50 * time.Millisecond,
+	}, newDiscardLogger())
+	defer sender.Close()
+
+	for index := 0; index < 3; index++ {
+		if err := sender.SendEmail(context.Background(), "user@example.com", "Subject", "Body", nil); err != nil {
+			t.Fatalf("send %d: %v", index, err)
+		}
+	}
+	if _, _, accepted, _ := server.counts(); accepted != 1 {
+		t.Fatalf("expected sequential sends to reuse one connection, got %d", accepted)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for sender.pool.idleCount() > 0 || openConnections(server) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the idle connection to be closed, idle=%d open=%d", sender.pool.idleCount(), openConnections(server))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestEmailSenderForTenantReplacesSenderOnCredentialRotation(t *testing.T) {
+	server := startCountingSMTPServer(t, 0)
+	serviceInstance := newSMTPPoolTestService(config.Config{SMTPPoolSize: 2, SMTPPoolIdleTimeoutSec: 60})
+	runtimeCfg := tenant.RuntimeConfig{Tenant: tenant.Tenant{ID: "tenant-rotating"}, Email: server.email("user", 0)}
+
+	first, err := serviceInstance.emailSenderForTenant(runtimeCfg)
+	if err != nil {
+		t.Fatalf("resolve sender: %v", err)
+	}
+	if err := first.SendEmail(context.Background(), "user@example.com", "Subject", "Body", nil); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if again, _ := serviceInstance.emailSenderForTenant(runtimeCfg); again != first {
+		t.Fatalf("expected unchanged credentials to reuse the sender")
+	}
+
+	runtimeCfg.Email.Password = "rotated"
+	rotated, err := serviceInstance.emailSenderForTenant(runtimeCfg)
+	if err != nil {
+		t.Fatalf("resolve rotated sender: %v", err)
+	}
+	defer rotated.(*SMTPEmailSender).Close()
+	if rotated == first {
+		t.Fatalf("expected rotated credentials to replace the sender")
+	}
+	if idle := first.(*SMTPEmailSender).pool.idleCount(); idle != 0 {
+		t.Fatalf("expected the outdated sender's connections to be closed, %d still idle", idle)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for openConnections(server) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the server to see the outdated connection close")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func openConnections(server *countingSMTPServer) int {
+	open, _, _, _ := server.counts()
+	return open
+}
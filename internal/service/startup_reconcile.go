@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+)
+
+// reconcileUnattemptedQueued counts, per active tenant, the queued notifications without
+// a schedule that no dispatch attempted before startedAt, logs each non-zero count, and
+// returns the total. Such rows were deferred or interrupted before their immediate send;
+// StartRetryWorker runs a pass right away when any exist instead of leaving them until
+// the first tick. Without a tenant inventory nothing is counted.
+func (serviceInstance *notificationServiceImpl) reconcileUnattemptedQueued(ctx context.Context, startedAt time.Time) int64 {
+	if serviceInstance.tenantRepo == nil {
+		return 0
+	}
+	logger := serviceInstance.logger
+	tenants, err := serviceInstance.tenantRepo.ListActiveTenants(ctx)
+	if err != nil {
+		logger.Error("Failed to list tenants for startup reconciliation", "error", err)
+		return 0
+	}
+	var recovered int64
+	for _, tenantModel := range tenants {
+		count, countErr := model.CountUnattemptedQueuedNotifications(ctx, serviceInstance.database, tenantModel.ID, startedAt)
+		if countErr != nil {
+			logger.Error("Startup reconciliation failed", "tenant_id", tenantModel.ID, "error", countErr)
+			continue
+		}
+		if count == 0 {
+			continue
+		}
+		logger.Info("startup_reconciliation_recovered", "tenant_id", tenantModel.ID, "recovered", count)
+		recovered += count
+	}
+	return recovered
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+)
+
+func TestStartRetryWorkerDispatchesUnattemptedQueuedOnStartup(t *testing.T) {
+	serviceInstance, emailSender, database := newDailyReportTestService(t, nil, nil)
+	// An hour-long interval leaves the startup reconciliation as the only pass in the test.
+	serviceInstance.retryIntervalSec = 3600
+	var logs bytes.Buffer
+	serviceInstance.logger = slog.New(slog.NewTextHandler(&logs, nil))
+	createdAt := time.Now().UTC().Add(-time.Minute)
+	scheduledFor := time.Now().UTC().Add(time.Hour)
+	insertNotificationRecord(t, database, model.Notification{
+		TenantID: "tenant-report", NotificationID: "stranded", NotificationType: model.NotificationEmail,
+		Recipient: "stranded@report.example", Subject: "Hello", Message: "Body", Status: model.StatusQueued, CreatedAt: createdAt,
+	})
+	insertNotificationRecord(t, database, model.Notification{
+		TenantID: "tenant-report", NotificationID: "later", NotificationType: model.NotificationEmail,
+		Recipient: "later@report.example", Subject: "Hello", Message: "Body", Status: model.StatusQueued, CreatedAt: createdAt, ScheduledFor: &scheduledFor,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	workerDone := make(chan struct{})
+	go func() {
+		serviceInstance.StartRetryWorker(ctx)
+		close(workerDone)
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		stranded, err := model.GetNotificationByID(context.Background(), database, "tenant-report", "stranded")
+		if err == nil && stranded.Status == model.StatusSent {
+			break
+		}
+		if time.Now().After(deadline) {
+			cancel()
+			<-workerDone
+			t.Fatalf("expected the stranded notification to be dispatched on startup, got %+v err=%v", stranded, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-workerDone
+
+	if len(emailSender.recipients) != 1 || emailSender.recipients[0] != "stranded@report.example" {
+		t.Fatalf("expected only the stranded notification to be sent, got %v", emailSender.recipients)
+	}
+	if later, err := model.GetNotificationByID(context.Background(), database, "tenant-report", "later"); err != nil || later.Status != model.StatusQueued {
+		t.Fatalf("expected the scheduled notification to stay queued, got %+v err=%v", later, err)
+	}
+	if !strings.Contains(logs.String(), "startup_reconciliation_recovered tenant_id=tenant-report recovered=1") {
+		t.Fatalf("expected the recovered count in the log:\n%s", logs.String())
+	}
+}
+
+func TestReconcileUnattemptedQueuedSkipsAttemptedAndSuspendedTenants(t *testing.T) {
+	serviceInstance, _, database := newDailyReportTestService(t, nil, nil)
+	startedAt := time.Now().UTC()
+	for _, record := range []model.Notification{
+		{TenantID: "tenant-report", NotificationID: "retried", NotificationType: model.NotificationEmail, Recipient: "a@report.example", Message: "Body", Status: model.StatusQueued, RetryCount: 1},
+		{TenantID: "tenant-report", NotificationID: "new", NotificationType: model.NotificationEmail, Recipient: "b@report.example", Message: "Body", Status: model.StatusQueued, CreatedAt: startedAt.Add(time.Second)},
+		{TenantID: "tenant-gone", NotificationID: "orphan", NotificationType: model.NotificationEmail, Recipient: "c@report.example", Message: "Body", Status: model.StatusQueued},
+	} {
+		if record.CreatedAt.IsZero() {
+			record.CreatedAt = startedAt.Add(-time.Minute)
+		}
+		insertNotificationRecord(t, database, record)
+	}
+	if recovered := serviceInstance.reconcileUnattemptedQueued(context.Background(), startedAt); recovered != 0 {
+		t.Fatalf("expected nothing to reconcile, got %d", recovered)
+	}
+	serviceInstance.tenantRepo = nil
+	if recovered := serviceInstance.reconcileUnattemptedQueued(context.Background(), startedAt); recovered != 0 {
+		t.Fatalf("expected no reconciliation without a tenant inventory, got %d", recovered)
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+)
+
+// ErrTenantInventoryUnavailable reports a service built without a tenant repository.
+var ErrTenantInventoryUnavailable = errors.New("tenant status unavailable: no tenant repository")
+
+// TenantStatus is the operator view of one tenant. Credentials are reported as
+// configured or not; no secret value is ever included.
+type TenantStatus struct {
+	TenantID               string     `json:"tenant_id"`
+	DisplayName            string     `json:"display_name"`
+	Status                 string     `json:"status"`
+	DomainCount            int        `json:"domain_count"`
+	EmailProfileConfigured bool       `json:"email_profile_configured"`
+	SMSProfileConfigured   bool       `json:"sms_profile_configured"`
+	EmailSenderCached      bool       `json:"email_sender_cached"`
+	SMSSenderCached        bool       `json:"sms_sender_cached"`
+	QueuedCount            int64      `json:"queued_count"`
+	ErroredCount           int64      `json:"errored_count"`
+	LastDispatchedAt       *time.Time `json:"last_dispatched_at,omitempty"`
+	// OldestPendingAgeSec is how long the tenant's longest-waiting due queued notification
+	// has waited, in whole seconds; zero when nothing queued is due.
+	OldestPendingAgeSec int64 `json:"oldest_pending_age_sec"`
+	// PendingAgeExceeded reports OldestPendingAgeSec above server.maxPendingAgeSec.
+	PendingAgeExceeded bool `json:"pending_age_exceeded"`
+	// OpenedCount is how many notifications were opened at least once; zero for tenants
+	// that do not track opens.
+	OpenedCount int64 `json:"opened_count"`
+	// ProviderLatencies covers dispatches made by this process since it started.
+	ProviderLatencies []ProviderLatency `json:"provider_latencies,omitempty"`
+	// AttachmentSizes covers sends stored by this process since it started; nil until one carried attachments.
+	AttachmentSizes *AttachmentSizeMetrics `json:"attachment_sizes,omitempty"`
+	// ProviderBreakers lists the tenant's channels paused after account-level provider failures.
+	ProviderBreakers []ProviderBreakerState `json:"provider_breakers,omitempty"`
+	// Integrity is the tenant's share of the latest attachment integrity sweep; nil until one ran.
+	Integrity *TenantIntegrity `json:"integrity,omitempty"`
+}
+
+// TenantIntegrity carries one tenant's attachment integrity findings and when they were checked.
+type TenantIntegrity struct {
+	CheckedAt time.Time `json:"checked_at"`
+	model.AttachmentIntegrityCounts
+}
+
+func (serviceInstance *notificationServiceImpl) ListTenantsStatus(ctx context.Context) ([]TenantStatus, error) {
+	if serviceInstance.tenantRepo == nil {
+		return nil, ErrTenantInventoryUnavailable
+	}
+	inventory, err := serviceInstance.tenantRepo.ListTenantInventory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	currentTime := serviceInstance.currentTime()
+	statuses := make([]TenantStatus, 0, len(inventory))
+	for _, entry := range inventory {
+		delivery, summaryErr := model.SummarizeTenantDelivery(ctx, serviceInstance.database, entry.Tenant.ID, currentTime)
+		if summaryErr != nil {
+			return nil, summaryErr
+		}
+		emailSenderCached, smsSenderCached := serviceInstance.cachedSenders(entry.Tenant.ID)
+		oldestPendingAgeSec := pendingAgeSeconds(delivery.OldestPendingSince, currentTime)
+		var openedCount int64
+		if entry.Tenant.OpenTracking {
+			if openedCount, err = model.CountOpenedNotifications(ctx, serviceInstance.database, entry.Tenant.ID); err != nil {
+				return nil, err
+			}
+		}
+		statuses = append(statuses, TenantStatus{
+			TenantID:               entry.Tenant.ID,
+			DisplayName:            entry.Tenant.DisplayName,
+			Status:                 string(entry.Tenant.Status),
+			DomainCount:            entry.DomainCount,
+			EmailProfileConfigured: entry.EmailProfileConfigured,
+			SMSProfileConfigured:   entry.SMSProfileConfigured,
+			EmailSenderCached:      emailSenderCached,
+			SMSSenderCached:        smsSenderCached,
+			QueuedCount:            delivery.QueuedCount,
+			ErroredCount:           delivery.ErroredCount,
+			LastDispatchedAt:       delivery.LastDispatchedAt,
+			OldestPendingAgeSec:    oldestPendingAgeSec,
+			PendingAgeExceeded:     serviceInstance.config.MaxPendingAgeSec > 0 && oldestPendingAgeSec > int64(serviceInstance.config.MaxPendingAgeSec),
+			OpenedCount:            openedCount,
+			ProviderLatencies:      serviceInstance.dispatchLatency.snapshot(entry.Tenant.ID),
+			AttachmentSizes:        serviceInstance.attachmentSizes.snapshot(entry.Tenant.ID),
+			ProviderBreakers:       serviceInstance.providerBreakers.snapshot(ctx, entry.Tenant.ID),
+			Integrity:              serviceInstance.tenantIntegrity(entry.Tenant.ID),
+		})
+	}
+	return statuses, nil
+}
+
+// pendingAgeSeconds returns how many whole seconds passed from since to currentTime,
+// or zero when since is nil or in the future.
+func pendingAgeSeconds(since *time.Time, currentTime time.Time) int64 {
+	if since == nil || !currentTime.After(*since) {
+		return 0
+	}
+	return int64(currentTime.Sub(*since) / time.Second)
+}
+
+// cachedSenders reports whether tenant-specific senders were already built for the tenant.
+func (serviceInstance *notificationServiceImpl) cachedSenders(tenantID string) (bool, bool) {
+	serviceInstance.senderMutex.RLock()
+	defer serviceInstance.senderMutex.RUnlock()
+	_, emailCached := serviceInstance.emailSenders[tenantID]
+	_, smsCached := serviceInstance.smsSenders[tenantID]
+	return emailCached, smsCached
+}
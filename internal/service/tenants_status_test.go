@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+)
+
+func TestListTenantsStatusReportsCountsAndLastDispatch(t *testing.T) {
+	serviceInstance, _, database := newDailyReportTestService(t, nil, []string{"ops@report.example"})
+	lastDispatchedAt := time.Date(2026, 4, 2, 9, 30, 0, 0, time.UTC)
+	seeds := []struct {
+		status          model.NotificationStatus
+		lastAttemptedAt time.Time
+	}{
+		{status: model.StatusQueued},
+		{status: model.StatusQueued},
+		{status: model.StatusErrored, lastAttemptedAt: lastDispatchedAt.Add(time.Hour)},
+		{status: model.StatusSent, lastAttemptedAt: lastDispatchedAt.Add(-time.Hour)},
+		{status: model.StatusSent, lastAttemptedAt: lastDispatchedAt},
+	}
+	for index, seed := range seeds {
+		record := model.Notification{
+			TenantID:         "tenant-report",
+			NotificationID:   fmt.Sprintf("notif-%d", index),
+			NotificationType: model.NotificationEmail,
+			Recipient:        "user@example.com",
+			Message:          "Body",
+			Status:           seed.status,
+			LastAttemptedAt:  seed.lastAttemptedAt,
+		}
+		if err := model.CreateNotification(context.Background(), database, &record); err != nil {
+			t.Fatalf("seed notification: %v", err)
+		}
+	}
+	serviceInstance.emailSenders["tenant-report"] = &stubEmailSender{}
+
+	statuses, err := serviceInstance.ListTenantsStatus(context.Background())
+	if err != nil {
+		t.Fatalf("list tenants status: %v", err)
+	}
+	if len(statuses) != 1 {
+		t.Fatalf("expected one tenant, got %+v", statuses)
+	}
+	tenantStatus := statuses[0]
+	if tenantStatus.TenantID != "tenant-report" || tenantStatus.Status != "active" || tenantStatus.DomainCount != 1 {
+		t.Fatalf("unexpected tenant identity %+v", tenantStatus)
+	}
+	if !tenantStatus.EmailProfileConfigured || tenantStatus.SMSProfileConfigured || !tenantStatus.EmailSenderCached || tenantStatus.SMSSenderCached {
+		t.Fatalf("unexpected profile and sender flags %+v", tenantStatus)
+	}
+	if tenantStatus.QueuedCount != 2 || tenantStatus.ErroredCount != 1 {
+		t.Fatalf("unexpected counts %+v", tenantStatus)
+	}
+	if tenantStatus.LastDispatchedAt == nil || !tenantStatus.LastDispatchedAt.Equal(lastDispatchedAt) {
+		t.Fatalf("expected last dispatch %v, got %v", lastDispatchedAt, tenantStatus.LastDispatchedAt)
+	}
+	encoded, err := json.Marshal(statuses)
+	if err != nil {
+		t.Fatalf("encode statuses: %v", err)
+	}
+	for _, secret := range []string{"smtp-user", "smtp-pass"} {
+		if strings.Contains(string(encoded), secret) {
+			t.Fatalf("expected no credentials in the status view, got %s", encoded)
+		}
+	}
+}
+
+func TestListTenantsStatusReportsOldestPendingAge(t *testing.T) {
+	serviceInstance, _, database := newDailyReportTestService(t, nil, nil)
+	now := time.Date(2026, 4, 2, 12, 0, 0, 0, time.UTC)
+	serviceInstance.clock = &adjustableClock{now: now}
+	dueSoonAfterCreation := now.Add(-30 * time.Minute)
+	notDueYet := now.Add(time.Hour)
+	for _, record := range []model.Notification{
+		{NotificationID: "waiting", Status: model.StatusQueued, CreatedAt: now.Add(-2 * time.Hour)},
+		{NotificationID: "scheduled-due", Status: model.StatusQueued, CreatedAt: now.Add(-5 * time.Hour), ScheduledFor: &dueSoonAfterCreation},
+		{NotificationID: "scheduled-later", Status: model.StatusQueued, CreatedAt: now.Add(-10 * time.Hour), ScheduledFor: &notDueYet},
+		{NotificationID: "failed", Status: model.StatusErrored, CreatedAt: now.Add(-20 * time.Hour)},
+	} {
+		record.TenantID = "tenant-report"
+		record.NotificationType = model.NotificationEmail
+		record.Recipient = "user@example.com"
+		record.Message = "Body"
+		insertNotificationRecord(t, database, record)
+	}
+
+	serviceInstance.config.MaxPendingAgeSec = 3600
+	statuses, err := serviceInstance.ListTenantsStatus(context.Background())
+	if err != nil {
+		t.Fatalf("list tenants status: %v", err)
+	}
+	if statuses[0].OldestPendingAgeSec != 7200 || !statuses[0].PendingAgeExceeded {
+		t.Fatalf("expected a two hour wait above the alert, got %+v", statuses[0])
+	}
+
+	serviceInstance.config.MaxPendingAgeSec = 0
+	serviceInstance.clock = &adjustableClock{now: now.Add(time.Hour)}
+	statuses, err = serviceInstance.ListTenantsStatus(context.Background())
+	if err != nil {
+		t.Fatalf("list tenants status: %v", err)
+	}
+	if statuses[0].OldestPendingAgeSec != 3*3600 || statuses[0].PendingAgeExceeded {
+		t.Fatalf("expected the age to follow the clock without an alert, got %+v", statuses[0])
+	}
+}
+
+func TestListTenantsStatusRequiresTenantRepository(t *testing.T) {
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), &stubEmailSender{}, &stubSmsSender{})
+	if _, err := serviceInstance.ListTenantsStatus(context.Background()); !errors.Is(err, ErrTenantInventoryUnavailable) {
+		t.Fatalf("expected ErrTenantInventoryUnavailable, got %v", err)
+	}
+}
+
+func TestIntegrityWorkerReportsAndRepairsOrphansInTenantStatus(t *testing.T) {
+	serviceInstance, _, database := newDailyReportTestService(t, nil, []string{"ops@report.example"})
+	orphan := model.NotificationAttachment{TenantID: "tenant-report", NotificationID: "deleted-parent", Filename: "orphan.txt", SizeBytes: 6, Data: []byte("orphan")}
+	if err := database.Create(&orphan).Error; err != nil {
+		t.Fatalf("seed orphan: %v", err)
+	}
+	statuses, err := serviceInstance.ListTenantsStatus(context.Background())
+	if err != nil || statuses[0].Integrity != nil {
+		t.Fatalf("expected no integrity view before the first sweep, got %+v (%v)", statuses, err)
+	}
+
+	serviceInstance.config.IntegritySweepIntervalSec = 3600
+	serviceInstance.config.IntegritySweepRepairOrphans = true
+	ctx, cancel := context.WithCancel(context.Background())
+	workerDone := make(chan struct{})
+	go func() {
+		serviceInstance.StartIntegrityWorker(ctx)
+		close(workerDone)
+	}()
+	var integrity *TenantIntegrity
+	for deadline := time.Now().Add(5 * time.Second); integrity == nil && time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		integrity = serviceInstance.tenantIntegrity("tenant-report")
+	}
+	cancel()
+	<-workerDone
+	if integrity == nil || integrity.OrphanedAttachments != 1 || integrity.RepairedOrphans != 1 || integrity.CheckedAt.IsZero() {
+		t.Fatalf("expected the sweep to report and repair one orphan, got %+v", integrity)
+	}
+	var remaining int64
+	if err := database.Model(&model.NotificationAttachment{}).Count(&remaining).Error; err != nil || remaining != 0 {
+		t.Fatalf("expected the orphan to be deleted, got %d rows (%v)", remaining, err)
+	}
+}
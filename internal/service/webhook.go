@@ -0,0 +1,286 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/entropy"
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+	"github.com/tyemirov/utils/scheduler"
+	"gorm.io/gorm"
+)
+
+const (
+	// WebhookEventSent fires when a notification is delivered to its provider.
+	WebhookEventSent = "notification.sent"
+	// WebhookEventErrored fires when a notification's dispatch first fails; retries follow.
+	WebhookEventErrored = "notification.errored"
+	// WebhookEventDeadLettered fires when a notification fails its last allowed attempt.
+	WebhookEventDeadLettered = "notification.dead_lettered"
+	// WebhookEventCancelled fires when a notification is cancelled, on request or by Pinguin itself.
+	WebhookEventCancelled = "notification.cancelled"
+
+	// WebhookSignatureHeader carries "sha256=" and the hex HMAC-SHA256 of the timestamp
+	// header, a '.', and the raw body, keyed with the tenant's webhook secret.
+	WebhookSignatureHeader = "X-Pinguin-Signature"
+	// WebhookTimestampHeader carries the Unix time the request was signed.
+	WebhookTimestampHeader = "X-Pinguin-Timestamp"
+	// WebhookEventHeader repeats the payload's event name for routing without parsing.
+	WebhookEventHeader = "X-Pinguin-Event"
+
+	webhookSignaturePrefix    = "sha256="
+	webhookRequestTimeout     = 10 * time.Second
+	webhookDeliveriesPerCycle = 100
+	// webhookResponseDrainBytes bounds how much of a response body is read before closing.
+	webhookResponseDrainBytes = 4096
+)
+
+// errWebhookRejected marks a 4xx response, which is not retried.
+var errWebhookRejected = errors.New("webhook endpoint rejected the event")
+
+// WebhookEvent is the JSON body POSTed to a tenant's webhook. EventID stays the same
+// across retries so receivers can drop duplicates.
+type WebhookEvent struct {
+	EventID        string    `json:"event_id" description:"Identifier of the event; unchanged across retries."`
+	Event          string    `json:"event" description:"Event name, e.g. notification.sent."`
+	TenantID       string    `json:"tenant_id" description:"Tenant that owns the notification."`
+	NotificationID string    `json:"notification_id" description:"Identifier of the notification."`
+	Status         string    `json:"status" description:"Notification status after the event."`
+	OccurredAt     time.Time `json:"occurred_at" description:"Time the event happened."`
+	// The cancellation fields are set on notification.cancelled events only.
+	CancelReason string     `json:"cancel_reason,omitempty" description:"Why the notification was cancelled; set on notification.cancelled."`
+	CancelledBy  string     `json:"cancelled_by,omitempty" description:"Who cancelled the notification: the session email, grpc, or system; set on notification.cancelled."`
+	CancelledAt  *time.Time `json:"cancelled_at,omitempty" description:"Time the notification was cancelled; set on notification.cancelled."`
+	// Notification is the redacted snapshot taken when the event happened.
+	Notification *model.NotificationSnapshot `json:"notification,omitempty" description:"Redacted snapshot of the notification when the event happened; absent on events queued before snapshots existed."`
+}
+
+// SignWebhookPayload returns the WebhookSignatureHeader value for body signed at timestamp.
+func SignWebhookPayload(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return webhookSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookEventForTransition names the event a status change emits, or "" for none.
+// Errored fires once per notification; a failure on the last allowed attempt, or one
+// whose retries were abandoned, is reported as dead-lettered instead.
+func webhookEventForTransition(record *model.Notification, previous model.NotificationStatus, maxRetries int) string {
+	switch record.Status {
+	case model.StatusSent:
+		if previous != model.StatusSent {
+			return WebhookEventSent
+		}
+	case model.StatusErrored:
+		if deadLettered(record, maxRetries) {
+			return WebhookEventDeadLettered
+		}
+		if previous != model.StatusErrored {
+			return WebhookEventErrored
+		}
+	case model.StatusCancelled:
+		if previous != model.StatusCancelled {
+			return WebhookEventCancelled
+		}
+	}
+	return ""
+}
+
+// recordStateChange logs the notification's lifecycle event and queues a webhook event
+// when the tenant has a webhook and the transition is one subscribers care about.
+// Failures are logged; they never fail the send.
+func (serviceInstance *notificationServiceImpl) recordStateChange(ctx context.Context, record *model.Notification, previous model.NotificationStatus) {
+	serviceInstance.logLifecycleEvent(ctx, record)
+	event := webhookEventForTransition(record, previous, serviceInstance.maxRetries)
+	if event == "" {
+		return
+	}
+	runtimeCfg, err := serviceInstance.runtimeForTenantID(ctx, record.TenantID)
+	if err != nil {
+		serviceInstance.logger.Warn("Skipping webhook event: tenant runtime unavailable", "notification_id", record.NotificationID, "tenant_id", record.TenantID, "error", err)
+		return
+	}
+	if runtimeCfg.Webhook == nil {
+		return
+	}
+	eventID, err := entropy.NewUUID(serviceInstance.randomSource())
+	if err != nil {
+		serviceInstance.logger.Error("Failed to queue webhook event", "notification_id", record.NotificationID, "tenant_id", record.TenantID, "webhook_event", event, "error", err)
+		return
+	}
+	snapshot := model.NewNotificationSnapshot(*record, model.SnapshotRedacted)
+	delivery := model.WebhookDelivery{
+		EventID:            eventID,
+		TenantID:           record.TenantID,
+		NotificationID:     record.NotificationID,
+		Event:              event,
+		NotificationStatus: record.Status,
+		OccurredAt:         serviceInstance.currentTime(),
+		Snapshot:           &snapshot,
+		Status:             model.WebhookDeliveryPending,
+	}
+	if event == WebhookEventCancelled {
+		delivery.CancelReason = record.CancelReason
+		delivery.CancelledBy = record.CancelledBy
+		delivery.CancelledAt = record.CancelledAt
+	}
+	if err := model.CreateWebhookDelivery(ctx, serviceInstance.database, &delivery); err != nil {
+		serviceInstance.logger.Error("Failed to queue webhook event", "notification_id", record.NotificationID, "tenant_id", record.TenantID, "webhook_event", event, "error", err)
+	}
+}
+
+// StartWebhookWorker delivers queued webhook events on the retry interval, backing off
+// exponentially between attempts like notification retries do.
+func (serviceInstance *notificationServiceImpl) StartWebhookWorker(ctx context.Context) {
+	worker, err := serviceInstance.newWebhookWorker(serviceInstance.timeSource())
+	if err != nil {
+		serviceInstance.logger.Error("Failed to initialize webhook worker", "error", err)
+		return
+	}
+	worker.Run(ctx)
+}
+
+func (serviceInstance *notificationServiceImpl) newWebhookWorker(clock scheduler.Clock) (*scheduler.Worker, error) {
+	return scheduler.NewWorker(scheduler.Config{
+		Repository:    newWebhookDeliveryStore(serviceInstance.database, serviceInstance.maxRetries),
+		Dispatcher:    newWebhookDispatcher(serviceInstance),
+		Logger:        serviceInstance.logger,
+		Interval:      time.Duration(serviceInstance.retryIntervalSec) * time.Second,
+		MaxRetries:    serviceInstance.maxRetries,
+		SuccessStatus: string(model.WebhookDeliveryDelivered),
+		FailureStatus: string(model.WebhookDeliveryPending),
+		Clock:         clock,
+	})
+}
+
+type webhookDeliveryStore struct {
+	database   *gorm.DB
+	maxRetries int
+}
+
+func newWebhookDeliveryStore(database *gorm.DB, maxRetries int) *webhookDeliveryStore {
+	return &webhookDeliveryStore{database: database, maxRetries: maxRetries}
+}
+
+func (store *webhookDeliveryStore) PendingJobs(ctx context.Context, maxRetries int, _ time.Time) ([]scheduler.Job, error) {
+	deliveries, err := model.ListPendingWebhookDeliveries(ctx, store.database, maxRetries, webhookDeliveriesPerCycle)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]scheduler.Job, 0, len(deliveries))
+	for index := range deliveries {
+		jobs = append(jobs, scheduler.Job{
+			ID:              deliveries[index].EventID,
+			RetryCount:      deliveries[index].RetryCount,
+			LastAttemptedAt: deliveries[index].LastAttemptedAt,
+			Payload:         &deliveries[index],
+		})
+	}
+	return jobs, nil
+}
+
+// ApplyAttemptResult marks an event failed once it has used its last attempt.
+func (store *webhookDeliveryStore) ApplyAttemptResult(ctx context.Context, job scheduler.Job, update scheduler.AttemptUpdate) error {
+	delivery, ok := job.Payload.(*model.WebhookDelivery)
+	if !ok || delivery == nil {
+		return fmt.Errorf("missing webhook delivery payload for job %s", job.ID)
+	}
+	delivery.Status = model.WebhookDeliveryStatus(update.Status)
+	delivery.RetryCount = update.RetryCount
+	delivery.LastAttemptedAt = update.LastAttemptedAt
+	if delivery.Status == model.WebhookDeliveryPending && store.maxRetries > 0 && delivery.RetryCount >= store.maxRetries {
+		delivery.Status = model.WebhookDeliveryFailed
+	}
+	return model.SaveWebhookDelivery(ctx, store.database, delivery)
+}
+
+type webhookDispatcher struct {
+	serviceInstance *notificationServiceImpl
+}
+
+func newWebhookDispatcher(serviceInstance *notificationServiceImpl) *webhookDispatcher {
+	return &webhookDispatcher{serviceInstance: serviceInstance}
+}
+
+// Attempt POSTs the signed event. Network errors and 5xx/429 responses are retried;
+// other 4xx responses and a removed webhook fail the event immediately.
+func (dispatcher *webhookDispatcher) Attempt(ctx context.Context, job scheduler.Job) (scheduler.DispatchResult, error) {
+	delivery, ok := job.Payload.(*model.WebhookDelivery)
+	if !ok || delivery == nil {
+		return scheduler.DispatchResult{Status: string(model.WebhookDeliveryFailed)}, errors.New("webhook delivery payload missing from job")
+	}
+	runtimeCfg, err := dispatcher.serviceInstance.runtimeForTenantID(ctx, delivery.TenantID)
+	if err != nil {
+		return scheduler.DispatchResult{}, err
+	}
+	if runtimeCfg.Webhook == nil {
+		return scheduler.DispatchResult{Status: string(model.WebhookDeliveryFailed)}, fmt.Errorf("tenant %s no longer has a webhook", delivery.TenantID)
+	}
+	statusCode, err := dispatcher.post(ctx, *runtimeCfg.Webhook, delivery)
+	switch {
+	case err != nil:
+		return scheduler.DispatchResult{}, err
+	case statusCode >= 200 && statusCode < 300:
+		return scheduler.DispatchResult{}, nil
+	case statusCode >= 500 || statusCode == http.StatusTooManyRequests:
+		return scheduler.DispatchResult{}, fmt.Errorf("webhook endpoint returned %d", statusCode)
+	default:
+		return scheduler.DispatchResult{Status: string(model.WebhookDeliveryFailed)}, fmt.Errorf("%w: status %d", errWebhookRejected, statusCode)
+	}
+}
+
+func (dispatcher *webhookDispatcher) post(ctx context.Context, webhook tenant.WebhookCredentials, delivery *model.WebhookDelivery) (int, error) {
+	body, err := json.Marshal(WebhookEvent{
+		EventID:        delivery.EventID,
+		Event:          delivery.Event,
+		TenantID:       delivery.TenantID,
+		NotificationID: delivery.NotificationID,
+		Status:         string(delivery.NotificationStatus),
+		OccurredAt:     delivery.OccurredAt.UTC(),
+		CancelReason:   delivery.CancelReason,
+		CancelledBy:    delivery.CancelledBy,
+		CancelledAt:    delivery.CancelledAt,
+		Notification:   delivery.Snapshot,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("encode webhook event: %w", err)
+	}
+	requestCtx, cancel := context.WithTimeout(ctx, webhookRequestTimeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(requestCtx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("build webhook request: %w", err)
+	}
+	timestamp := strconv.FormatInt(dispatcher.serviceInstance.currentTime().Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(WebhookEventHeader, delivery.Event)
+	request.Header.Set(WebhookTimestampHeader, timestamp)
+	request.Header.Set(WebhookSignatureHeader, SignWebhookPayload(webhook.Secret, timestamp, body))
+	response, err := dispatcher.serviceInstance.webhookHTTPClient().Do(request)
+	if err != nil {
+		return 0, fmt.Errorf("post webhook: %w", err)
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, webhookResponseDrainBytes))
+	return response.StatusCode, nil
+}
+
+func (serviceInstance *notificationServiceImpl) webhookHTTPClient() *http.Client {
+	if serviceInstance.webhookClient != nil {
+		return serviceInstance.webhookClient
+	}
+	return http.DefaultClient
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/tenant"
+	"github.com/tyemirov/utils/scheduler"
+)
+
+const testWebhookSecret = "whsec-test"
+
+type webhookCapture struct {
+	mutex    sync.Mutex
+	statuses []int
+	requests []capturedWebhook
+}
+
+type capturedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+// newWebhookServer answers with statuses in order and repeats the last one afterwards.
+func newWebhookServer(t *testing.T, statuses ...int) (*httptest.Server, *webhookCapture) {
+	t.Helper()
+	capture := &webhookCapture{statuses: statuses}
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+		capture.mutex.Lock()
+		capture.requests = append(capture.requests, capturedWebhook{header: request.Header.Clone(), body: body})
+		status := capture.statuses[len(capture.statuses)-1]
+		if len(capture.requests) <= len(capture.statuses) {
+			status = capture.statuses[len(capture.requests)-1]
+		}
+		capture.mutex.Unlock()
+		writer.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, capture
+}
+
+func (capture *webhookCapture) received() []capturedWebhook {
+	capture.mutex.Lock()
+	defer capture.mutex.Unlock()
+	return append([]capturedWebhook(nil), capture.requests...)
+}
+
+func webhookTenantContext(webhookURL string) context.Context {
+	runtimeCfg := baseRuntimeConfig()
+	runtimeCfg.Webhook = &tenant.WebhookCredentials{URL: webhookURL, Secret: testWebhookSecret}
+	return tenant.WithRuntime(context.Background(), runtimeCfg)
+}
+
+func loadWebhookDeliveries(t *testing.T, serviceInstance *notificationServiceImpl) []model.WebhookDelivery {
+	t.Helper()
+	var deliveries []model.WebhookDelivery
+	if err := serviceInstance.database.Find(&deliveries).Error; err != nil {
+		t.Fatalf("load webhook deliveries: %v", err)
+	}
+	return deliveries
+}
+
+func TestSentNotificationDeliversSignedWebhookAndRetriesOnServerError(t *testing.T) {
+	server, capture := newWebhookServer(t, http.StatusServiceUnavailable, http.StatusOK)
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), &stubEmailSender{}, &stubSmsSender{})
+	ctx := webhookTenantContext(server.URL)
+
+	response, err := serviceInstance.SendNotification(ctx, mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", nil, nil))
+	if err != nil {
+		t.Fatalf("SendNotification error: %v", err)
+	}
+	deliveries := loadWebhookDeliveries(t, serviceInstance)
+	if len(deliveries) != 1 || deliveries[0].Event != WebhookEventSent || deliveries[0].Status != model.WebhookDeliveryPending {
+		t.Fatalf("expected one pending sent event, got %+v", deliveries)
+	}
+
+	clock := &drainTestClock{now: time.Now().UTC()}
+	worker, err := serviceInstance.newWebhookWorker(clock)
+	if err != nil {
+		t.Fatalf("webhook worker: %v", err)
+	}
+	worker.RunOnce(ctx)
+	deliveries = loadWebhookDeliveries(t, serviceInstance)
+	if deliveries[0].Status != model.WebhookDeliveryPending || deliveries[0].RetryCount != 1 {
+		t.Fatalf("expected a 503 to leave the event pending for retry, got %+v", deliveries[0])
+	}
+
+	clock.advance(2 * time.Second)
+	worker.RunOnce(ctx)
+	deliveries = loadWebhookDeliveries(t, serviceInstance)
+	if deliveries[0].Status != model.WebhookDeliveryDelivered {
+		t.Fatalf("expected the retry to deliver the event, got %+v", deliveries[0])
+	}
+
+	requests := capture.received()
+	if len(requests) != 2 {
+		t.Fatalf("expected two webhook attempts, got %d", len(requests))
+	}
+	final := requests[1]
+	timestamp := final.header.Get(WebhookTimestampHeader)
+	if signature := final.header.Get(WebhookSignatureHeader); signature != SignWebhookPayload(testWebhookSecret, timestamp, final.body) {
+		t.Fatalf("signature %q does not verify", signature)
+	}
+	if final.header.Get(WebhookEventHeader) != WebhookEventSent {
+		t.Fatalf("unexpected event header %q", final.header.Get(WebhookEventHeader))
+	}
+	if strings.Contains(string(final.body), "user@example.com") {
+		t.Fatalf("webhook payload leaked the recipient: %s", final.body)
+	}
+	var event WebhookEvent
+	if err := json.Unmarshal(final.body, &event); err != nil {
+		t.Fatalf("decode webhook payload: %v", err)
+	}
+	if event.NotificationID != response.NotificationID || event.Status != string(model.StatusSent) || event.TenantID != testTenantID || event.OccurredAt.IsZero() {
+		t.Fatalf("unexpected webhook payload %+v", event)
+	}
+	if snapshot := event.Notification; snapshot == nil || snapshot.Version != model.NotificationSnapshotVersion || snapshot.Status != model.StatusSent ||
+		snapshot.Recipient != "" || snapshot.RecipientDigest != DigestForLogging("user@example.com") {
+		t.Fatalf("expected a redacted snapshot of the sent notification, got %+v", event.Notification)
+	}
+	if event.EventID != deliveries[0].EventID || string(requests[0].body) != string(final.body) {
+		t.Fatalf("expected retries to resend the same event")
+	}
+}
+
+func TestWebhookClientErrorFailsEventWithoutRetry(t *testing.T) {
+	server, capture := newWebhookServer(t, http.StatusBadRequest)
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), &stubEmailSender{err: errors.New("smtp down")}, &stubSmsSender{})
+	ctx := webhookTenantContext(server.URL)
+
+	if _, err := serviceInstance.SendNotification(ctx, mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", nil, nil)); err != nil {
+		t.Fatalf("SendNotification error: %v", err)
+	}
+	worker, err := serviceInstance.newWebhookWorker(nil)
+	if err != nil {
+		t.Fatalf("webhook worker: %v", err)
+	}
+	worker.RunOnce(ctx)
+	worker.RunOnce(ctx)
+
+	deliveries := loadWebhookDeliveries(t, serviceInstance)
+	if len(deliveries) != 1 || deliveries[0].Event != WebhookEventErrored || deliveries[0].Status != model.WebhookDeliveryFailed {
+		t.Fatalf("expected one failed errored event, got %+v", deliveries)
+	}
+	if len(capture.received()) != 1 {
+		t.Fatalf("expected a 4xx response not to be retried, got %d attempts", len(capture.received()))
+	}
+}
+
+func TestRetryStoreQueuesDeadLetterEventOnLastAttempt(t *testing.T) {
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), &stubEmailSender{}, &stubSmsSender{})
+	serviceInstance.maxRetries = 2
+	ctx := webhookTenantContext("https://hooks.example.com/pinguin")
+	record := model.Notification{
+		NotificationID:   "notif-dead-letter",
+		TenantID:         testTenantID,
+		NotificationType: model.NotificationEmail,
+		Recipient:        "user@example.com",
+		Status:           model.StatusErrored,
+		RetryCount:       1,
+	}
+	if err := serviceInstance.database.Create(&record).Error; err != nil {
+		t.Fatalf("create notification: %v", err)
+	}
+	store := newNotificationRetryStore(serviceInstance.database, nil).withStateChangeHook(serviceInstance.recordStateChange)
+	jobs, err := store.PendingJobs(ctx, serviceInstance.maxRetries, time.Now().UTC())
+	if err != nil || len(jobs) != 1 {
+		t.Fatalf("expected one pending job, got %d (%v)", len(jobs), err)
+	}
+	if err := store.ApplyAttemptResult(ctx, jobs[0], scheduler.AttemptUpdate{Status: string(model.StatusErrored), RetryCount: 2, LastAttemptedAt: time.Now().UTC()}); err != nil {
+		t.Fatalf("apply attempt: %v", err)
+	}
+
+	deliveries := loadWebhookDeliveries(t, serviceInstance)
+	if len(deliveries) != 1 || deliveries[0].Event != WebhookEventDeadLettered || deliveries[0].NotificationID != record.NotificationID {
+		t.Fatalf("expected one dead-lettered event, got %+v", deliveries)
+	}
+}
+
+func TestCancelNotificationQueuesCancelledWebhookWithReasonAndActor(t *testing.T) {
+	server, capture := newWebhookServer(t, http.StatusOK)
+	serviceInstance := newNotificationServiceWithSendersForSchedulerTests(openIsolatedDatabase(t), &stubEmailSender{}, &stubSmsSender{})
+	ctx := webhookTenantContext(server.URL)
+
+	scheduledFor := time.Now().UTC().Add(time.Hour)
+	response, err := serviceInstance.SendNotification(ctx, mustNotificationRequest(t, model.NotificationEmail, "user@example.com", "Subject", "Body", &scheduledFor, nil))
+	if err != nil {
+		t.Fatalf("SendNotification error: %v", err)
+	}
+	var logs bytes.Buffer
+	serviceInstance.logger = slog.New(slog.NewJSONHandler(&logs, nil))
+	if _, err := serviceInstance.CancelNotification(ctx, response.NotificationID, "duplicate", "ops@example.com"); err != nil {
+		t.Fatalf("CancelNotification error: %v", err)
+	}
+	if auditLog := logs.String(); !strings.Contains(auditLog, `"msg":"audit_notification_cancelled"`) ||
+		!strings.Contains(auditLog, `\"recipient_digest\":\"`+DigestForLogging("user@example.com")) || strings.Contains(auditLog, "user@example.com") {
+		t.Fatalf("expected the audit entry to carry a redacted snapshot:\n%s", auditLog)
+	}
+	deliveries := loadWebhookDeliveries(t, serviceInstance)
+	if len(deliveries) != 1 || deliveries[0].Event != WebhookEventCancelled {
+		t.Fatalf("expected one cancelled event, got %+v", deliveries)
+	}
+
+	worker, err := serviceInstance.newWebhookWorker(&drainTestClock{now: time.Now().UTC()})
+	if err != nil {
+		t.Fatalf("webhook worker: %v", err)
+	}
+	worker.RunOnce(ctx)
+	requests := capture.received()
+	if len(requests) != 1 {
+		t.Fatalf("expected one webhook attempt, got %d", len(requests))
+	}
+	var event WebhookEvent
+	if err := json.Unmarshal(requests[0].body, &event); err != nil {
+		t.Fatalf("decode webhook payload: %v", err)
+	}
+	if event.Status != string(model.StatusCancelled) || event.CancelReason != "duplicate" || event.CancelledBy != "ops@example.com" || event.CancelledAt == nil {
+		t.Fatalf("unexpected webhook payload %+v", event)
+	}
+	if snapshot := event.Notification; snapshot == nil || snapshot.CancelReason != "duplicate" || snapshot.CancelledAt == nil || !snapshot.CancelledAt.Equal(*event.CancelledAt) {
+		t.Fatalf("expected the snapshot to carry the cancellation, got %+v", event.Notification)
+	}
+}
+
+func TestWebhookEventForTransition(t *testing.T) {
+	testCases := []struct {
+		name       string
+		previous   model.NotificationStatus
+		status     model.NotificationStatus
+		retryCount int
+		expected   string
+	}{
+		{name: "QueuedToSent", previous: model.StatusQueued, status: model.StatusSent, expected: WebhookEventSent},
+		{name: "ErroredToSent", previous: model.StatusErrored, status: model.StatusSent, expected: WebhookEventSent},
+		{name: "FirstFailure", previous: model.StatusQueued, status: model.StatusErrored, retryCount: 1, expected: WebhookEventErrored},
+		{name: "RepeatedFailure", previous: model.StatusErrored, status: model.StatusErrored, retryCount: 2},
+		{name: "LastFailure", previous: model.StatusErrored, status: model.StatusErrored, retryCount: 3, expected: WebhookEventDeadLettered},
+		{name: "StillQueued", previous: model.StatusQueued, status: model.StatusQueued},
+		{name: "Cancelled", previous: model.StatusQueued, status: model.StatusCancelled, expected: WebhookEventCancelled},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			record := &model.Notification{Status: testCase.status, RetryCount: testCase.retryCount}
+			if event := webhookEventForTransition(record, testCase.previous, 3); event != testCase.expected {
+				t.Fatalf("expected %q, got %q", testCase.expected, event)
+			}
+		})
+	}
+}
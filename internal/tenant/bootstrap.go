@@ -3,8 +3,8 @@ package tenant
 import (
 	"context"
 	"fmt"
-	"os"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"gopkg.in/yaml.v3"
@@ -13,8 +13,11 @@ import (
 )
 
 // BootstrapConfig defines the YAML layout for tenant provisioning.
+// ForceReassignDomains allows a domain to move from one configured tenant to another;
+// without it such a move is rejected so two tenants never silently compete for a host.
 type BootstrapConfig struct {
-	Tenants []BootstrapTenant `json:"tenants" yaml:"tenants"`
+	Tenants              []BootstrapTenant `json:"tenants" yaml:"tenants"`
+	ForceReassignDomains bool              `json:"forceReassignDomains,omitempty" yaml:"forceReassignDomains,omitempty"`
 }
 
 func (cfg *BootstrapConfig) UnmarshalYAML(value *yaml.Node) error {
@@ -25,15 +28,21 @@ func (cfg *BootstrapConfig) UnmarshalYAML(value *yaml.Node) error {
 	if value.Kind != yaml.MappingNode {
 		return fmt.Errorf("tenant bootstrap: config must be a mapping")
 	}
-	if unsupportedKey := firstUnsupportedBootstrapYAMLMappingKey(value, "tenants"); unsupportedKey != "" {
+	if unsupportedKey := firstUnsupportedBootstrapYAMLMappingKey(value, "tenants", "forceReassignDomains"); unsupportedKey != "" {
 		return fmt.Errorf("tenant bootstrap: %s is not supported", unsupportedKey)
 	}
-	type rawBootstrapConfig BootstrapConfig
-	var decoded rawBootstrapConfig
+	var decoded struct {
+		Tenants              yaml.Node `yaml:"tenants"`
+		ForceReassignDomains bool      `yaml:"forceReassignDomains"`
+	}
 	if err := value.Decode(&decoded); err != nil {
 		return err
 	}
-	*cfg = BootstrapConfig(decoded)
+	tenants, err := DecodeBootstrapTenants(&decoded.Tenants)
+	if err != nil {
+		return err
+	}
+	*cfg = BootstrapConfig{Tenants: tenants, ForceReassignDomains: decoded.ForceReassignDomains}
 	return nil
 }
 
@@ -48,6 +57,34 @@ type BootstrapTenant struct {
 	Admins       []string              `json:"admins" yaml:"admins"`
 	EmailProfile BootstrapEmailProfile `json:"emailProfile" yaml:"emailProfile"`
 	SMSProfile   *BootstrapSMSProfile  `json:"smsProfile" yaml:"smsProfile"`
+	CostModel    *BootstrapCostModel   `json:"costModel,omitempty" yaml:"costModel,omitempty"`
+	DailyReport  *BootstrapDailyReport `json:"dailyReport,omitempty" yaml:"dailyReport,omitempty"`
+	// PersistAttachmentData defaults to true; false keeps attachment metadata only.
+	PersistAttachmentData *bool `json:"persistAttachmentData,omitempty" yaml:"persistAttachmentData,omitempty"`
+	// MaxConcurrentRetries overrides server.maxConcurrentRetriesPerTenant when positive.
+	MaxConcurrentRetries int `json:"maxConcurrentRetries,omitempty" yaml:"maxConcurrentRetries,omitempty"`
+	// CallerAllowlist limits gRPC calls for the tenant to peers inside these CIDRs.
+	CallerAllowlist []string `json:"callerAllowlist,omitempty" yaml:"callerAllowlist,omitempty"`
+	// NotificationIDPrefix replaces the default "notif" prefix of the tenant's notification ids.
+	NotificationIDPrefix string `json:"notificationIdPrefix,omitempty" yaml:"notificationIdPrefix,omitempty"`
+	// Webhook receives signed events when the tenant's notifications are sent or fail.
+	Webhook *BootstrapWebhook `json:"webhook,omitempty" yaml:"webhook,omitempty"`
+	// SMSLimits caps SMS body size and picks the default overflow policy.
+	SMSLimits *BootstrapSMSLimits `json:"smsLimits,omitempty" yaml:"smsLimits,omitempty"`
+	// StoreRenderedContent records what each notification was dispatched with.
+	StoreRenderedContent bool `json:"storeRenderedContent,omitempty" yaml:"storeRenderedContent,omitempty"`
+	// RetentionDays overrides server.retentionDays when positive.
+	RetentionDays int `json:"retentionDays,omitempty" yaml:"retentionDays,omitempty"`
+	// MaxRetryAgeSec overrides server.maxRetryAgeSec when positive.
+	MaxRetryAgeSec int `json:"maxRetryAgeSec,omitempty" yaml:"maxRetryAgeSec,omitempty"`
+	// OpenTracking adds an open tracking pixel to the tenant's HTML emails; off by default.
+	OpenTracking bool `json:"openTracking,omitempty" yaml:"openTracking,omitempty"`
+	// SenderOverrides lets requests set their own Reply-To and From display name; denied by default.
+	SenderOverrides *BootstrapSenderOverrides `json:"senderOverrides,omitempty" yaml:"senderOverrides,omitempty"`
+	// SourceLine is the YAML line the tenant was declared on, zero when built in code.
+	SourceLine int `json:"-" yaml:"-"`
+	// SourceFile is the tenant config file the tenant came from when several were merged.
+	SourceFile string `json:"-" yaml:"-"`
 }
 
 func (spec *BootstrapTenant) UnmarshalYAML(value *yaml.Node) error {
@@ -61,7 +98,7 @@ func (spec *BootstrapTenant) UnmarshalYAML(value *yaml.Node) error {
 	if yamlMappingHasKey(value, "status") {
 		return fmt.Errorf("tenant bootstrap: tenants[].status is no longer supported; use tenants[].enabled (true|false)")
 	}
-	if unsupportedKey := firstUnsupportedBootstrapYAMLMappingKey(value, "id", "displayName", "supportEmail", "enabled", "domains", "admins", "emailProfile", "smsProfile"); unsupportedKey != "" {
+	if unsupportedKey := firstUnsupportedBootstrapYAMLMappingKey(value, "id", "displayName", "supportEmail", "enabled", "domains", "admins", "emailProfile", "smsProfile", "costModel", "dailyReport", "persistAttachmentData", "maxConcurrentRetries", "callerAllowlist", "notificationIdPrefix", "webhook", "smsLimits", "storeRenderedContent", "retentionDays", "maxRetryAgeSec", "openTracking", "senderOverrides"); unsupportedKey != "" {
 		return fmt.Errorf("tenant bootstrap: tenants[].%s is not supported", unsupportedKey)
 	}
 	type rawBootstrapTenant BootstrapTenant
@@ -70,6 +107,7 @@ func (spec *BootstrapTenant) UnmarshalYAML(value *yaml.Node) error {
 		return err
 	}
 	*spec = BootstrapTenant(decoded)
+	spec.SourceLine = value.Line
 	return nil
 }
 
@@ -80,6 +118,14 @@ type BootstrapEmailProfile struct {
 	Username    string `json:"username" yaml:"username"`
 	Password    string `json:"password" yaml:"password"`
 	FromAddress string `json:"fromAddress" yaml:"fromAddress"`
+	// ConnectionTimeoutSec overrides server.connectionTimeoutSec for the tenant's SMTP sender when positive.
+	ConnectionTimeoutSec int `json:"connectionTimeoutSec,omitempty" yaml:"connectionTimeoutSec,omitempty"`
+	// OperationTimeoutSec overrides server.operationTimeoutSec for the tenant's SMTP sender when positive.
+	OperationTimeoutSec int `json:"operationTimeoutSec,omitempty" yaml:"operationTimeoutSec,omitempty"`
+	// PoolSize overrides server.smtpPoolSize for the tenant's SMTP sender when positive.
+	PoolSize int `json:"poolSize,omitempty" yaml:"poolSize,omitempty"`
+	// PoolIdleTimeoutSec overrides server.smtpPoolIdleTimeoutSec for the tenant's SMTP sender when positive.
+	PoolIdleTimeoutSec int `json:"poolIdleTimeoutSec,omitempty" yaml:"poolIdleTimeoutSec,omitempty"`
 }
 
 func (profile *BootstrapEmailProfile) UnmarshalYAML(value *yaml.Node) error {
@@ -90,7 +136,7 @@ func (profile *BootstrapEmailProfile) UnmarshalYAML(value *yaml.Node) error {
 	if value.Kind != yaml.MappingNode {
 		return fmt.Errorf("tenant bootstrap: tenants[].emailProfile must be a mapping")
 	}
-	if unsupportedKey := firstUnsupportedBootstrapYAMLMappingKey(value, "host", "port", "username", "password", "fromAddress"); unsupportedKey != "" {
+	if unsupportedKey := firstUnsupportedBootstrapYAMLMappingKey(value, "host", "port", "username", "password", "fromAddress", "connectionTimeoutSec", "operationTimeoutSec", "poolSize", "poolIdleTimeoutSec"); unsupportedKey != "" {
 		return fmt.Errorf("tenant bootstrap: tenants[].emailProfile.%s is not supported", unsupportedKey)
 	}
 	type rawBootstrapEmailProfile BootstrapEmailProfile
@@ -129,6 +175,81 @@ func (profile *BootstrapSMSProfile) UnmarshalYAML(value *yaml.Node) error {
 	return nil
 }
 
+// BootstrapCostModel defines per-tenant unit costs used for spend estimates.
+type BootstrapCostModel struct {
+	Currency           string  `json:"currency" yaml:"currency"`
+	EmailUnitCost      float64 `json:"emailUnitCost" yaml:"emailUnitCost"`
+	SMSSegmentUnitCost float64 `json:"smsSegmentUnitCost" yaml:"smsSegmentUnitCost"`
+}
+
+func (costModel *BootstrapCostModel) UnmarshalYAML(value *yaml.Node) error {
+	if value == nil {
+		*costModel = BootstrapCostModel{}
+		return nil
+	}
+	if value.Kind != yaml.MappingNode {
+		return fmt.Errorf("tenant bootstrap: tenants[].costModel must be a mapping")
+	}
+	if unsupportedKey := firstUnsupportedBootstrapYAMLMappingKey(value, "currency", "emailUnitCost", "smsSegmentUnitCost"); unsupportedKey != "" {
+		return fmt.Errorf("tenant bootstrap: tenants[].costModel.%s is not supported", unsupportedKey)
+	}
+	type rawBootstrapCostModel BootstrapCostModel
+	var decoded rawBootstrapCostModel
+	if err := value.Decode(&decoded); err != nil {
+		return err
+	}
+	decoded.Currency = strings.ToUpper(strings.TrimSpace(decoded.Currency))
+	if decoded.Currency == "" {
+		return fmt.Errorf("tenant bootstrap: tenants[].costModel.currency is required")
+	}
+	if decoded.EmailUnitCost < 0 || decoded.SMSSegmentUnitCost < 0 {
+		return fmt.Errorf("tenant bootstrap: tenants[].costModel unit costs must not be negative")
+	}
+	*costModel = BootstrapCostModel(decoded)
+	return nil
+}
+
+// BootstrapDailyReport schedules the per-tenant activity digest emailed to tenant admins.
+type BootstrapDailyReport struct {
+	Enabled  bool   `json:"enabled" yaml:"enabled"`
+	SendAt   string `json:"sendAt" yaml:"sendAt"`
+	Timezone string `json:"timezone" yaml:"timezone"`
+}
+
+func (dailyReport *BootstrapDailyReport) UnmarshalYAML(value *yaml.Node) error {
+	if value == nil {
+		*dailyReport = BootstrapDailyReport{}
+		return nil
+	}
+	if value.Kind != yaml.MappingNode {
+		return fmt.Errorf("tenant bootstrap: tenants[].dailyReport must be a mapping")
+	}
+	if unsupportedKey := firstUnsupportedBootstrapYAMLMappingKey(value, "enabled", "sendAt", "timezone"); unsupportedKey != "" {
+		return fmt.Errorf("tenant bootstrap: tenants[].dailyReport.%s is not supported", unsupportedKey)
+	}
+	type rawBootstrapDailyReport BootstrapDailyReport
+	var decoded rawBootstrapDailyReport
+	if err := value.Decode(&decoded); err != nil {
+		return err
+	}
+	decoded.SendAt = strings.TrimSpace(decoded.SendAt)
+	if decoded.SendAt == "" {
+		decoded.SendAt = DefaultDailyReportSendAt
+	}
+	if _, _, err := ParseDailyReportSendAt(decoded.SendAt); err != nil {
+		return fmt.Errorf("tenant bootstrap: tenants[].dailyReport.sendAt must use HH:MM: %w", err)
+	}
+	decoded.Timezone = strings.TrimSpace(decoded.Timezone)
+	if decoded.Timezone == "" {
+		decoded.Timezone = DefaultDailyReportTimezone
+	}
+	if _, err := time.LoadLocation(decoded.Timezone); err != nil {
+		return fmt.Errorf("tenant bootstrap: tenants[].dailyReport.timezone is not a known IANA zone: %w", err)
+	}
+	*dailyReport = BootstrapDailyReport(decoded)
+	return nil
+}
+
 func firstUnsupportedBootstrapYAMLMappingKey(value *yaml.Node, allowedKeys ...string) string {
 	allowed := make(map[string]struct{}, len(allowedKeys))
 	for _, allowedKey := range allowedKeys {
@@ -155,40 +276,27 @@ func yamlMappingHasKey(value *yaml.Node, key string) bool {
 	return false
 }
 
-// BootstrapFromFile loads tenants from a YAML file and upserts them.
+// BootstrapFromFile loads tenants from a YAML file, a directory of them, or a glob,
+// merges them, and upserts the result.
 func BootstrapFromFile(ctx context.Context, db *gorm.DB, keeper *SecretKeeper, path string) error {
-	contents, err := os.ReadFile(path)
+	cfg, err := LoadBootstrapConfigFiles(path)
 	if err != nil {
-		return fmt.Errorf("tenant bootstrap: read file: %w", err)
-	}
-	var cfg BootstrapConfig
-	if err := yaml.Unmarshal(contents, &cfg); err != nil {
-		return fmt.Errorf("tenant bootstrap: parse yaml: %w", err)
+		return err
 	}
 	return Bootstrap(ctx, db, keeper, cfg)
 }
 
 // Bootstrap loads tenants from an in-memory config and upserts them.
 func Bootstrap(ctx context.Context, db *gorm.DB, keeper *SecretKeeper, cfg BootstrapConfig) error {
-	if len(cfg.Tenants) == 0 {
-		return fmt.Errorf("tenant bootstrap: no tenants configured")
-	}
-	tenantSpecs := prepareBootstrapTenants(cfg.Tenants)
-	enabledCount := 0
-	for _, tenantSpec := range tenantSpecs {
-		if tenantSpec.Enabled != nil && !*tenantSpec.Enabled {
-			continue
-		}
-		enabledCount++
-	}
-	if enabledCount == 0 {
-		return fmt.Errorf("tenant bootstrap: no enabled tenants configured")
-	}
-	if err := validateBootstrapDomains(tenantSpecs); err != nil {
+	if err := ValidateBootstrapConfig(cfg); err != nil {
 		return err
 	}
+	tenantSpecs := prepareBootstrapTenants(cfg.Tenants)
 	configuredTenantIDs := bootstrapTenantIDs(tenantSpecs)
 	transactionErr := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := validateDomainReassignments(tx, tenantSpecs, configuredTenantIDs, cfg.ForceReassignDomains); err != nil {
+			return err
+		}
 		if err := resetTenantDomains(tx); err != nil {
 			return err
 		}
@@ -204,9 +312,9 @@ func Bootstrap(ctx context.Context, db *gorm.DB, keeper *SecretKeeper, cfg Boots
 		if err := removeStaleTenants(tx, configuredTenantIDs); err != nil {
 			return err
 		}
-		for _, tenantSpec := range tenantSpecs {
+		for tenantIndex, tenantSpec := range tenantSpecs {
 			if err := upsertTenant(ctx, tx, keeper, tenantSpec); err != nil {
-				return err
+				return fmt.Errorf("tenant bootstrap: %s: %w", bootstrapTenantLabel(tenantIndex, tenantSpec.ID, tenantSpec.SourceFile, tenantSpec.SourceLine), err)
 			}
 		}
 		return nil
@@ -218,31 +326,73 @@ func Bootstrap(ctx context.Context, db *gorm.DB, keeper *SecretKeeper, cfg Boots
 	return nil
 }
 
+// upsertTenant writes one validated tenant; Bootstrap prefixes its errors with the tenant label.
 func upsertTenant(ctx context.Context, tx *gorm.DB, keeper *SecretKeeper, spec BootstrapTenant) error {
-	if strings.TrimSpace(spec.Status) != "" {
-		return fmt.Errorf("tenant bootstrap: tenants[].status is no longer supported; use tenants[].enabled (true|false)")
-	}
 	status := string(TenantStatusActive)
 	if spec.Enabled != nil && !*spec.Enabled {
 		status = string(TenantStatusSuspended)
 	}
 	tenantModel := Tenant{
-		ID:           spec.ID,
-		DisplayName:  spec.DisplayName,
-		SupportEmail: spec.SupportEmail,
-		Status:       TenantStatus(status),
+		ID:                   spec.ID,
+		DisplayName:          spec.DisplayName,
+		SupportEmail:         spec.SupportEmail,
+		Status:               TenantStatus(status),
+		MaxConcurrentRetries: spec.MaxConcurrentRetries,
+		NotificationIDPrefix: strings.TrimSpace(spec.NotificationIDPrefix),
+		StoreRenderedContent: spec.StoreRenderedContent,
+		RetentionDays:        spec.RetentionDays,
+		MaxRetryAgeSec:       spec.MaxRetryAgeSec,
+		OpenTracking:         spec.OpenTracking,
+	}
+	if len(spec.CallerAllowlist) > 0 {
+		callerAllowlist, err := ParseCallerAllowlist(spec.CallerAllowlist)
+		if err != nil {
+			return err
+		}
+		tenantModel.CallerAllowlist = formatCallerAllowlist(callerAllowlist)
+	}
+	if spec.PersistAttachmentData != nil && !*spec.PersistAttachmentData {
+		tenantModel.AttachmentMetadataOnly = true
+	}
+	if spec.Webhook != nil {
+		secretCipher, err := keeper.Encrypt(spec.Webhook.Secret)
+		if err != nil {
+			return err
+		}
+		tenantModel.WebhookURL = strings.TrimSpace(spec.Webhook.URL)
+		tenantModel.WebhookSecretCipher = secretCipher
+	}
+	if spec.SMSLimits != nil {
+		tenantModel.SMSMaxSegments = spec.SMSLimits.MaxSegments
+		tenantModel.SMSOverflowPolicy = strings.ToLower(strings.TrimSpace(spec.SMSLimits.OverflowPolicy))
+		tenantModel.SMSTruncationSuffix = spec.SMSLimits.TruncationSuffix
+	}
+	if spec.SenderOverrides != nil {
+		tenantModel.SenderOverridePolicy = strings.ToLower(strings.TrimSpace(spec.SenderOverrides.Policy))
+		tenantModel.SenderOverrideDomains = strings.Join(normalizeSenderOverrideDomains(spec.SenderOverrides.AllowedDomains), senderOverrideDomainSeparator)
+	}
+	if spec.CostModel != nil {
+		tenantModel.CostCurrency = spec.CostModel.Currency
+		tenantModel.EmailUnitCost = spec.CostModel.EmailUnitCost
+		tenantModel.SMSSegmentUnitCost = spec.CostModel.SMSSegmentUnitCost
+	}
+	if spec.DailyReport != nil {
+		tenantModel.DailyReportEnabled = spec.DailyReport.Enabled
+		tenantModel.DailyReportSendAt = spec.DailyReport.SendAt
+		tenantModel.DailyReportTimezone = spec.DailyReport.Timezone
 	}
 	if err := tx.WithContext(ctx).Clauses(clauseOnConflictUpdateAll()).
 		Create(&tenantModel).Error; err != nil {
-		return fmt.Errorf("tenant bootstrap: upsert tenant %s: %w", spec.ID, err)
+		return fmt.Errorf("upsert tenant %s: %w", spec.ID, err)
 	}
 
 	normalizedDomains := normalizeDomainHosts(spec.Domains)
 	for domainIndex, host := range normalizedDomains {
 		domain := TenantDomain{
-			TenantID:  spec.ID,
-			Host:      host,
-			IsDefault: domainIndex == 0,
+			TenantID:       spec.ID,
+			Host:           host,
+			WildcardSuffix: wildcardSuffix(host),
+			IsDefault:      domainIndex == 0,
 		}
 		createResult := tx.Clauses(clause.OnConflict{
 			Columns:   []clause.Column{{Name: "host"}},
@@ -256,7 +406,7 @@ func upsertTenant(ctx context.Context, tx *gorm.DB, keeper *SecretKeeper, spec B
 			return fmt.Errorf(bootstrapDomainErrorFormat, host, err)
 		}
 		if existingDomain.TenantID != spec.ID || existingDomain.IsDefault != domain.IsDefault {
-			return fmt.Errorf("tenant bootstrap: %s: domain %s already assigned to tenant %s", bootstrapDomainConflictCode, host, existingDomain.TenantID)
+			return fmt.Errorf("%s: domain %s already assigned to tenant %s", bootstrapDomainConflictCode, host, existingDomain.TenantID)
 		}
 	}
 
@@ -273,17 +423,21 @@ func upsertTenant(ctx context.Context, tx *gorm.DB, keeper *SecretKeeper, spec B
 		return err
 	}
 	emailProfile := EmailProfile{
-		ID:             uuid.NewString(),
-		TenantID:       spec.ID,
-		Host:           spec.EmailProfile.Host,
-		Port:           spec.EmailProfile.Port,
-		UsernameCipher: usernameCipher,
-		PasswordCipher: passwordCipher,
-		FromAddress:    spec.EmailProfile.FromAddress,
-		IsDefault:      true,
+		ID:                   uuid.NewString(),
+		TenantID:             spec.ID,
+		Host:                 spec.EmailProfile.Host,
+		Port:                 spec.EmailProfile.Port,
+		UsernameCipher:       usernameCipher,
+		PasswordCipher:       passwordCipher,
+		FromAddress:          spec.EmailProfile.FromAddress,
+		ConnectionTimeoutSec: spec.EmailProfile.ConnectionTimeoutSec,
+		OperationTimeoutSec:  spec.EmailProfile.OperationTimeoutSec,
+		PoolSize:             spec.EmailProfile.PoolSize,
+		PoolIdleTimeoutSec:   spec.EmailProfile.PoolIdleTimeoutSec,
+		IsDefault:            true,
 	}
 	if err := tx.Create(&emailProfile).Error; err != nil {
-		return fmt.Errorf("tenant bootstrap: email profile: %w", err)
+		return fmt.Errorf("email profile: %w", err)
 	}
 
 	if spec.SMSProfile != nil {
@@ -304,7 +458,7 @@ func upsertTenant(ctx context.Context, tx *gorm.DB, keeper *SecretKeeper, spec B
 			IsDefault:        true,
 		}
 		if err := tx.Create(&smsProfile).Error; err != nil {
-			return fmt.Errorf("tenant bootstrap: sms profile: %w", err)
+			return fmt.Errorf("sms profile: %w", err)
 		}
 	}
 
@@ -314,6 +468,7 @@ func upsertTenant(ctx context.Context, tx *gorm.DB, keeper *SecretKeeper, spec B
 const (
 	bootstrapDuplicateDomainCode   = "tenant.bootstrap.domain.duplicate"
 	bootstrapMissingDomainCode     = "tenant.bootstrap.domain.missing"
+	bootstrapInvalidDomainCode     = "tenant.bootstrap.domain.invalid"
 	bootstrapDomainResetCode       = "tenant.bootstrap.domain.reset_failed"
 	bootstrapDomainConflictCode    = "tenant.bootstrap.domain.conflict"
 	bootstrapAdminResetCode        = "tenant.bootstrap.admin.reset_failed"
@@ -321,7 +476,8 @@ const (
 	bootstrapEmailProfileResetCode = "tenant.bootstrap.email_profile.reset_failed"
 	bootstrapSMSProfileResetCode   = "tenant.bootstrap.sms_profile.reset_failed"
 	bootstrapTenantCleanupCode     = "tenant.bootstrap.tenant.cleanup_failed"
-	bootstrapDomainErrorFormat     = "tenant bootstrap: domain %s: %w"
+	bootstrapInvalidAddressCode    = "tenant.bootstrap.address.invalid"
+	bootstrapDomainErrorFormat     = "domain %s: %w"
 )
 
 func upsertTenantAdmins(db *gorm.DB, tenantID string, admins []string) error {
@@ -331,7 +487,7 @@ func upsertTenantAdmins(db *gorm.DB, tenantID string, admins []string) error {
 			Email:    email,
 		}
 		if err := db.Create(&admin).Error; err != nil {
-			return fmt.Errorf("tenant bootstrap: %s: create tenant admin: %w", bootstrapAdminCreateCode, err)
+			return fmt.Errorf("%s: create tenant admin: %w", bootstrapAdminCreateCode, err)
 		}
 	}
 	return nil
@@ -357,25 +513,34 @@ func bootstrapTenantIDs(tenantSpecs []BootstrapTenant) []string {
 	return tenantIDs
 }
 
-func validateBootstrapDomains(tenantSpecs []BootstrapTenant) error {
-	normalizedHosts := make(map[string]int, len(tenantSpecs))
+// validateDomainReassignments rejects hosts that an existing, still-configured tenant
+// already owns unless force is set. Claims held by tenants being removed are released
+// with them. The whole check runs before any row is written and reports every conflict.
+func validateDomainReassignments(db *gorm.DB, tenantSpecs []BootstrapTenant, configuredTenantIDs []string, force bool) error {
+	configuredTenants := make(map[string]struct{}, len(configuredTenantIDs))
+	for _, tenantID := range configuredTenantIDs {
+		configuredTenants[tenantID] = struct{}{}
+	}
+	var problems []string
 	for tenantIndex, tenantSpec := range tenantSpecs {
-		domainCount := 0
-		for _, host := range tenantSpec.Domains {
-			normalizedHost := normalizeDomainHost(host)
-			if normalizedHost == "" {
+		label := bootstrapTenantLabel(tenantIndex, tenantSpec.ID, tenantSpec.SourceFile, tenantSpec.SourceLine)
+		for _, host := range normalizeDomainHosts(tenantSpec.Domains) {
+			var existingDomains []TenantDomain
+			if err := db.Where(&TenantDomain{Host: host}).Limit(1).Find(&existingDomains).Error; err != nil {
+				return fmt.Errorf("tenant bootstrap: %s: "+bootstrapDomainErrorFormat, label, host, err)
+			}
+			if len(existingDomains) == 0 || existingDomains[0].TenantID == tenantSpec.ID {
 				continue
 			}
-			domainCount++
-			if existingIndex, exists := normalizedHosts[normalizedHost]; exists {
-				return fmt.Errorf("tenant bootstrap: %s: duplicate domain %s between tenants[%d] and tenants[%d]", bootstrapDuplicateDomainCode, normalizedHost, existingIndex, tenantIndex)
+			if _, stillConfigured := configuredTenants[existingDomains[0].TenantID]; !stillConfigured || force {
+				continue
 			}
-			normalizedHosts[normalizedHost] = tenantIndex
-		}
-		if domainCount == 0 {
-			return fmt.Errorf("tenant bootstrap: %s: tenants[%d] has no domains", bootstrapMissingDomainCode, tenantIndex)
+			problems = append(problems, fmt.Sprintf("%s: %s: domain %s is assigned to tenant %s and cannot be claimed by tenant %s; set forceReassignDomains to move it", label, bootstrapDomainConflictCode, host, existingDomains[0].TenantID, tenantSpec.ID))
 		}
 	}
+	if len(problems) > 0 {
+		return &BootstrapValidationError{Problems: problems}
+	}
 	return nil
 }
 
@@ -441,8 +606,10 @@ func normalizeDomainHosts(domains []string) []string {
 	return normalizedDomains
 }
 
+// normalizeDomainHost normalizes a registered domain like a Host header, so "Acme.example:443"
+// and "acme.example" are the same registration.
 func normalizeDomainHost(host string) string {
-	return strings.ToLower(strings.TrimSpace(host))
+	return normalizeHost(host)
 }
 
 func normalizeAdminEmails(emails []string) []string {
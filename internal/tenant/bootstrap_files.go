@@ -0,0 +1,95 @@
+package tenant
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const bootstrapGlobMetaCharacters = "*?["
+
+// BootstrapConfigFiles expands tenants.configPath into the files it names: the path
+// itself for a file, every .yml and .yaml file directly inside a directory, or every
+// match of a glob pattern. Files are returned in lexical order.
+func BootstrapConfigFiles(path string) ([]string, error) {
+	trimmedPath := strings.TrimSpace(path)
+	if strings.ContainsAny(trimmedPath, bootstrapGlobMetaCharacters) {
+		matches, err := filepath.Glob(trimmedPath)
+		if err != nil {
+			return nil, fmt.Errorf("tenant bootstrap: glob %s: %w", trimmedPath, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("tenant bootstrap: glob %s matched no files", trimmedPath)
+		}
+		sort.Strings(matches)
+		return matches, nil
+	}
+	info, err := os.Stat(trimmedPath)
+	if err != nil {
+		return nil, fmt.Errorf("tenant bootstrap: read %s: %w", trimmedPath, err)
+	}
+	if !info.IsDir() {
+		return []string{trimmedPath}, nil
+	}
+	entries, err := os.ReadDir(trimmedPath)
+	if err != nil {
+		return nil, fmt.Errorf("tenant bootstrap: read %s: %w", trimmedPath, err)
+	}
+	var files []string
+	for _, entry := range entries {
+		extension := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (extension != ".yml" && extension != ".yaml") {
+			continue
+		}
+		files = append(files, filepath.Join(trimmedPath, entry.Name()))
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("tenant bootstrap: directory %s holds no .yml or .yaml files", trimmedPath)
+	}
+	return files, nil
+}
+
+// LoadBootstrapConfigFiles reads and merges every tenant config file named by path.
+// Each tenant remembers its file so validation problems point at it, and a tenant id
+// declared in two files is rejected naming both. forceReassignDomains applies to the
+// merged config when any file sets it.
+func LoadBootstrapConfigFiles(path string) (BootstrapConfig, error) {
+	files, err := BootstrapConfigFiles(path)
+	if err != nil {
+		return BootstrapConfig{}, err
+	}
+	var merged BootstrapConfig
+	declaredIn := make(map[string]string)
+	var duplicates []string
+	for _, file := range files {
+		contents, readErr := os.ReadFile(file)
+		if readErr != nil {
+			return BootstrapConfig{}, fmt.Errorf("tenant bootstrap: read %s: %w", file, readErr)
+		}
+		var fileCfg BootstrapConfig
+		if parseErr := yaml.Unmarshal(contents, &fileCfg); parseErr != nil {
+			return BootstrapConfig{}, fmt.Errorf("tenant bootstrap: parse yaml %s: %w", file, parseErr)
+		}
+		merged.ForceReassignDomains = merged.ForceReassignDomains || fileCfg.ForceReassignDomains
+		for _, spec := range fileCfg.Tenants {
+			if len(files) > 1 {
+				spec.SourceFile = file
+			}
+			tenantID := strings.TrimSpace(spec.ID)
+			if firstFile, seen := declaredIn[tenantID]; seen && tenantID != "" && firstFile != file {
+				duplicates = append(duplicates, fmt.Sprintf("tenant %s is declared in both %s and %s", tenantID, firstFile, file))
+			} else if !seen {
+				declaredIn[tenantID] = file
+			}
+			merged.Tenants = append(merged.Tenants, spec)
+		}
+	}
+	if len(duplicates) > 0 {
+		return BootstrapConfig{}, &BootstrapValidationError{Problems: duplicates}
+	}
+	return merged, nil
+}
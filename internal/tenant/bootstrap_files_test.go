@@ -0,0 +1,118 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTenantFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	directory := t.TempDir()
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(directory, name), []byte(contents), 0o600); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	return directory
+}
+
+func tenantFileYAML(tenantID string, domain string) string {
+	return `tenants:
+  - id: ` + tenantID + `
+    displayName: ` + tenantID + `
+    domains: [` + domain + `]
+    emailProfile:
+      fromAddress: noreply@` + domain + `
+`
+}
+
+func TestBootstrapFromFileMergesDirectoryAndGlob(t *testing.T) {
+	directory := writeTenantFiles(t, map[string]string{
+		"billing.yml":  tenantFileYAML("billing", "billing.example"),
+		"support.yaml": tenantFileYAML("support", "support.example"),
+		"README.md":    "not a tenant file",
+	})
+	for _, testCase := range []struct {
+		name        string
+		path        string
+		expectedIDs []string
+	}{
+		{name: "directory", path: directory, expectedIDs: []string{"billing", "support"}},
+		{name: "glob", path: filepath.Join(directory, "*.yml"), expectedIDs: []string{"billing"}},
+		{name: "single file", path: filepath.Join(directory, "support.yaml"), expectedIDs: []string{"support"}},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			dbInstance := newTestDatabase(t)
+			keeper := newTestSecretKeeper(t)
+			if err := BootstrapFromFile(context.Background(), dbInstance, keeper, testCase.path); err != nil {
+				t.Fatalf("bootstrap error: %v", err)
+			}
+			// Display names equal the ids, so the listing order is the id order.
+			tenants, err := NewRepository(dbInstance, keeper).ListActiveTenants(context.Background())
+			if err != nil {
+				t.Fatalf("list tenants: %v", err)
+			}
+			if len(tenants) != len(testCase.expectedIDs) {
+				t.Fatalf("expected tenants %v, got %+v", testCase.expectedIDs, tenants)
+			}
+			for tenantIndex, expectedID := range testCase.expectedIDs {
+				if tenants[tenantIndex].ID != expectedID {
+					t.Fatalf("expected tenants %v, got %+v", testCase.expectedIDs, tenants)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadBootstrapConfigFilesRejectsDuplicatesAcrossFiles(t *testing.T) {
+	directory := writeTenantFiles(t, map[string]string{
+		"a.yml": tenantFileYAML("billing", "billing.example"),
+		"b.yml": tenantFileYAML("billing", "billing-two.example"),
+	})
+	_, err := LoadBootstrapConfigFiles(directory)
+	var validationErr *BootstrapValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	expected := "tenant billing is declared in both " + filepath.Join(directory, "a.yml") + " and " + filepath.Join(directory, "b.yml")
+	if len(validationErr.Problems) != 1 || validationErr.Problems[0] != expected {
+		t.Fatalf("expected %q, got %q", expected, validationErr.Problems)
+	}
+}
+
+func TestLoadBootstrapConfigFilesLabelsProblemsWithTheirFile(t *testing.T) {
+	directory := writeTenantFiles(t, map[string]string{
+		"a.yml": tenantFileYAML("billing", "shared.example"),
+		"b.yml": tenantFileYAML("support", "shared.example"),
+	})
+	cfg, err := LoadBootstrapConfigFiles(directory)
+	if err != nil {
+		t.Fatalf("load files: %v", err)
+	}
+	err = ValidateBootstrapConfig(cfg)
+	expected := "tenant support (" + filepath.Join(directory, "b.yml") + ", line 2): " + bootstrapDuplicateDomainCode + ": duplicate domain shared.example already claimed by tenant billing (" + filepath.Join(directory, "a.yml") + ", line 2)"
+	if err == nil || !strings.Contains(err.Error(), expected) {
+		t.Fatalf("expected %q, got %v", expected, err)
+	}
+}
+
+func TestBootstrapConfigFilesRejectsEmptySources(t *testing.T) {
+	directory := writeTenantFiles(t, map[string]string{"notes.txt": "nothing"})
+	for _, testCase := range []struct {
+		path     string
+		fragment string
+	}{
+		{path: directory, fragment: "holds no .yml or .yaml files"},
+		{path: filepath.Join(directory, "*.yml"), fragment: "matched no files"},
+		{path: filepath.Join(directory, "missing.yml"), fragment: "tenant bootstrap: read"},
+		{path: filepath.Join(directory, "[.yml"), fragment: "tenant bootstrap: glob"},
+	} {
+		if _, err := BootstrapConfigFiles(testCase.path); err == nil || !strings.Contains(err.Error(), testCase.fragment) {
+			t.Fatalf("BootstrapConfigFiles(%q): expected %q, got %v", testCase.path, testCase.fragment, err)
+		}
+	}
+}
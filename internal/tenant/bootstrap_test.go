@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"gopkg.in/yaml.v3"
 	"gorm.io/gorm"
@@ -231,6 +232,102 @@ func TestBootstrapRejectsDuplicateDomains(t *testing.T) {
 	if err == nil {
 		t.Fatalf("expected duplicate domain error")
 	}
+	for _, expected := range []string{bootstrapDuplicateDomainCode, domainHost, "tenant-one", "tenant-two"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected duplicate domain error to mention %q, got %v", expected, err)
+		}
+	}
+}
+
+func TestBootstrapRejectsAmbiguousAndMalformedDomains(t *testing.T) {
+	cfg := BootstrapConfig{
+		Tenants: []BootstrapTenant{
+			bootstrapTenantSpec("tenant-one", []string{"acme.example", "*.acme.example"}),
+			bootstrapTenantSpec("tenant-two", []string{"ACME.example:443", "*.ACME.example."}),
+			bootstrapTenantSpec("tenant-three", []string{"*", "*.example", "shop.*.acme.example", "a*.acme.example", "a..example"}),
+		},
+	}
+
+	err := Bootstrap(context.Background(), newTestDatabase(t), newTestSecretKeeper(t), cfg)
+	var validationErr *BootstrapValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	problems := strings.Join(validationErr.Problems, "\n")
+	for _, expected := range []string{
+		bootstrapDuplicateDomainCode + ": duplicate domain acme.example",
+		bootstrapDuplicateDomainCode + ": duplicate domain *.acme.example",
+		bootstrapInvalidDomainCode + ": domain * ",
+		bootstrapInvalidDomainCode + ": domain *.example ",
+		bootstrapInvalidDomainCode + ": domain shop.*.acme.example ",
+		bootstrapInvalidDomainCode + ": domain a*.acme.example ",
+		bootstrapInvalidDomainCode + ": domain a..example ",
+	} {
+		if !strings.Contains(problems, expected) {
+			t.Fatalf("expected problem %q, got:\n%s", expected, problems)
+		}
+	}
+}
+
+func TestBootstrapRejectsDomainMovesBetweenConfiguredTenants(t *testing.T) {
+	dbInstance := newTestDatabase(t)
+	keeper := newTestSecretKeeper(t)
+	firstRun := BootstrapConfig{Tenants: []BootstrapTenant{
+		bootstrapTenantSpec("tenant-one", []string{"shared.example"}),
+		bootstrapTenantSpec("tenant-two", []string{"two.example"}),
+	}}
+	if err := Bootstrap(context.Background(), dbInstance, keeper, firstRun); err != nil {
+		t.Fatalf("first bootstrap: %v", err)
+	}
+	repo := NewRepository(dbInstance, keeper)
+	if runtimeCfg, err := repo.ResolveByHost(context.Background(), "shared.example"); err != nil || runtimeCfg.Tenant.ID != "tenant-one" {
+		t.Fatalf("expected tenant-one to own the domain, got %+v (%v)", runtimeCfg.Tenant, err)
+	}
+
+	secondRun := BootstrapConfig{Tenants: []BootstrapTenant{
+		bootstrapTenantSpec("tenant-one", []string{"one.example"}),
+		bootstrapTenantSpec("tenant-two", []string{"two.example", "shared.example"}),
+	}}
+	err := Bootstrap(context.Background(), dbInstance, keeper, secondRun)
+	for _, expected := range []string{bootstrapDomainConflictCode, "shared.example", "tenant-one", "tenant-two"} {
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected conflict error mentioning %q, got %v", expected, err)
+		}
+	}
+	var sharedDomain TenantDomain
+	if err := dbInstance.Where(&TenantDomain{Host: "shared.example"}).Take(&sharedDomain).Error; err != nil || sharedDomain.TenantID != "tenant-one" {
+		t.Fatalf("expected rejected bootstrap to leave the claim untouched, got %+v (%v)", sharedDomain, err)
+	}
+
+	secondRun.ForceReassignDomains = true
+	if err := Bootstrap(context.Background(), dbInstance, keeper, secondRun); err != nil {
+		t.Fatalf("forced bootstrap: %v", err)
+	}
+	var sharedClaims []TenantDomain
+	if err := dbInstance.Where(&TenantDomain{Host: "shared.example"}).Find(&sharedClaims).Error; err != nil {
+		t.Fatalf("list claims: %v", err)
+	}
+	if len(sharedClaims) != 1 || sharedClaims[0].TenantID != "tenant-two" {
+		t.Fatalf("expected a single claim owned by tenant-two, got %+v", sharedClaims)
+	}
+	if runtimeCfg, err := repo.ResolveByHost(context.Background(), "shared.example"); err != nil || runtimeCfg.Tenant.ID != "tenant-two" {
+		t.Fatalf("expected cached host lookup to follow the move, got %+v (%v)", runtimeCfg.Tenant, err)
+	}
+}
+
+func TestBootstrapReleasesDomainsOfRemovedTenants(t *testing.T) {
+	dbInstance := newTestDatabase(t)
+	keeper := newTestSecretKeeper(t)
+	if err := Bootstrap(context.Background(), dbInstance, keeper, BootstrapConfig{Tenants: []BootstrapTenant{
+		bootstrapTenantSpec("tenant-old", []string{"shared.example"}),
+	}}); err != nil {
+		t.Fatalf("first bootstrap: %v", err)
+	}
+	if err := Bootstrap(context.Background(), dbInstance, keeper, BootstrapConfig{Tenants: []BootstrapTenant{
+		bootstrapTenantSpec("tenant-new", []string{"shared.example"}),
+	}}); err != nil {
+		t.Fatalf("expected a removed tenant's domain to be claimable, got %v", err)
+	}
 }
 
 func TestBootstrapFromFileReportsReadAndParseErrors(t *testing.T) {
@@ -262,6 +359,10 @@ func TestBootstrapYAMLStrictSchemaEdges(t *testing.T) {
 	if err := yaml.Unmarshal([]byte("items: []\n"), &config); err == nil || !strings.Contains(err.Error(), "items is not supported") {
 		t.Fatalf("expected unsupported root key error, got %v", err)
 	}
+	var forced BootstrapConfig
+	if err := yaml.Unmarshal([]byte("forceReassignDomains: true\ntenants: []\n"), &forced); err != nil || !forced.ForceReassignDomains {
+		t.Fatalf("expected forceReassignDomains to parse, got %+v (%v)", forced, err)
+	}
 
 	var tenantSpec BootstrapTenant
 	if err := tenantSpec.UnmarshalYAML(nil); err != nil {
@@ -319,6 +420,60 @@ func TestBootstrapYAMLStrictSchemaEdges(t *testing.T) {
 	}
 }
 
+func TestBootstrapPersistsTenantCostModel(t *testing.T) {
+	dbInstance := newTestDatabase(t)
+	keeper := newTestSecretKeeper(t)
+	var cfg BootstrapConfig
+	rawConfig := `
+tenants:
+  - id: tenant-one
+    displayName: Alpha Corp
+    domains: [alpha.example]
+    emailProfile:
+      host: smtp.alpha.example
+      port: 587
+      username: smtp-user
+      password: smtp-pass
+      fromAddress: noreply@alpha.example
+    costModel:
+      currency: " usd "
+      emailUnitCost: 0.0004
+      smsSegmentUnitCost: 0.0079
+`
+	if err := yaml.Unmarshal([]byte(rawConfig), &cfg); err != nil {
+		t.Fatalf("parse cost model: %v", err)
+	}
+	if err := Bootstrap(context.Background(), dbInstance, keeper, cfg); err != nil {
+		t.Fatalf("bootstrap error: %v", err)
+	}
+	var tenantModel Tenant
+	if err := dbInstance.Where(&Tenant{ID: "tenant-one"}).First(&tenantModel).Error; err != nil {
+		t.Fatalf("fetch tenant: %v", err)
+	}
+	if tenantModel.CostCurrency != "USD" || tenantModel.EmailUnitCost != 0.0004 || tenantModel.SMSSegmentUnitCost != 0.0079 {
+		t.Fatalf("unexpected persisted cost model %+v", tenantModel)
+	}
+
+	for _, testCase := range []struct {
+		name     string
+		snippet  string
+		expected string
+	}{
+		{name: "not a mapping", snippet: "costModel: broken", expected: "costModel must be a mapping"},
+		{name: "unsupported key", snippet: "costModel:\n      currency: USD\n      perCall: 1", expected: "costModel.perCall is not supported"},
+		{name: "missing currency", snippet: "costModel:\n      emailUnitCost: 1", expected: "costModel.currency is required"},
+		{name: "negative cost", snippet: "costModel:\n      currency: USD\n      smsSegmentUnitCost: -1", expected: "must not be negative"},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			var invalid BootstrapConfig
+			err := yaml.Unmarshal([]byte("tenants:\n  - "+testCase.snippet+"\n"), &invalid)
+			if err == nil || !strings.Contains(err.Error(), testCase.expected) {
+				t.Fatalf("expected error containing %q, got %v", testCase.expected, err)
+			}
+		})
+	}
+}
+
 func TestBootstrapRejectsDisabledStatusAndMissingDomains(t *testing.T) {
 	t.Helper()
 	dbInstance := newTestDatabase(t)
@@ -393,6 +548,8 @@ func TestBootstrapGeneratesTenantIDAndRemovesSMSProfile(t *testing.T) {
 	}
 
 	anonymous := bootstrapTenantSpec("", []string{"generated.example"})
+	anonymous.SupportEmail = "support@generated.example"
+	anonymous.EmailProfile.FromAddress = "noreply@generated.example"
 	if err := Bootstrap(context.Background(), dbInstance, keeper, BootstrapConfig{Tenants: []BootstrapTenant{anonymous}}); err != nil {
 		t.Fatalf("bootstrap anonymous tenant: %v", err)
 	}
@@ -660,3 +817,588 @@ func bootstrapTenantSpec(tenantID string, domainHosts []string) BootstrapTenant
 		},
 	}
 }
+
+func TestBootstrapPersistsTenantDailyReport(t *testing.T) {
+	dbInstance := newTestDatabase(t)
+	keeper := newTestSecretKeeper(t)
+	var cfg BootstrapConfig
+	rawConfig := `
+tenants:
+  - id: tenant-one
+    displayName: Alpha Corp
+    domains: [alpha.example]
+    admins: [Ops@Alpha.example, lead@alpha.example]
+    emailProfile:
+      host: smtp.alpha.example
+      port: 587
+      username: smtp-user
+      password: smtp-pass
+      fromAddress: noreply@alpha.example
+    dailyReport:
+      enabled: true
+      timezone: America/New_York
+`
+	if err := yaml.Unmarshal([]byte(rawConfig), &cfg); err != nil {
+		t.Fatalf("parse daily report: %v", err)
+	}
+	if err := Bootstrap(context.Background(), dbInstance, keeper, cfg); err != nil {
+		t.Fatalf("bootstrap error: %v", err)
+	}
+	var tenantModel Tenant
+	if err := dbInstance.Where(&Tenant{ID: "tenant-one"}).First(&tenantModel).Error; err != nil {
+		t.Fatalf("fetch tenant: %v", err)
+	}
+	schedule, enabled, err := tenantModel.DailyReportSchedule()
+	if err != nil || !enabled {
+		t.Fatalf("expected enabled schedule, got enabled=%v err=%v", enabled, err)
+	}
+	if schedule.Hour != 7 || schedule.Minute != 0 || schedule.Location.String() != "America/New_York" {
+		t.Fatalf("unexpected schedule %+v", schedule)
+	}
+	adminEmails, err := NewRepository(dbInstance, keeper).ListTenantAdminEmails(context.Background(), "tenant-one")
+	if err != nil {
+		t.Fatalf("list admins: %v", err)
+	}
+	if strings.Join(adminEmails, ",") != "lead@alpha.example,ops@alpha.example" {
+		t.Fatalf("unexpected admin emails %v", adminEmails)
+	}
+
+	for _, testCase := range []struct {
+		name     string
+		snippet  string
+		expected string
+	}{
+		{name: "not a mapping", snippet: "dailyReport: daily", expected: "dailyReport must be a mapping"},
+		{name: "unsupported key", snippet: "dailyReport:\n      cron: '0 7 * * *'", expected: "dailyReport.cron is not supported"},
+		{name: "invalid send time", snippet: "dailyReport:\n      sendAt: '25:00'", expected: "dailyReport.sendAt must use HH:MM"},
+		{name: "unknown timezone", snippet: "dailyReport:\n      timezone: Mars/Olympus", expected: "dailyReport.timezone is not a known IANA zone"},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			var invalid BootstrapConfig
+			err := yaml.Unmarshal([]byte("tenants:\n  - "+testCase.snippet+"\n"), &invalid)
+			if err == nil || !strings.Contains(err.Error(), testCase.expected) {
+				t.Fatalf("expected error containing %q, got %v", testCase.expected, err)
+			}
+		})
+	}
+}
+
+func TestTenantDailyReportSchedule(t *testing.T) {
+	disabled, enabled, err := Tenant{ID: "tenant-off"}.DailyReportSchedule()
+	if err != nil || enabled || disabled.Location != nil {
+		t.Fatalf("expected disabled schedule, got %+v enabled=%v err=%v", disabled, enabled, err)
+	}
+	if _, _, err := (Tenant{ID: "tenant-bad", DailyReportEnabled: true, DailyReportTimezone: "Nowhere/Else"}).DailyReportSchedule(); !errors.Is(err, ErrDailyReportScheduleInvalid) {
+		t.Fatalf("expected invalid schedule error, got %v", err)
+	}
+	schedule, enabled, err := Tenant{ID: "tenant-berlin", DailyReportEnabled: true, DailyReportSendAt: "06:30", DailyReportTimezone: "Europe/Berlin"}.DailyReportSchedule()
+	if err != nil || !enabled {
+		t.Fatalf("expected schedule, got enabled=%v err=%v", enabled, err)
+	}
+	sendTime := schedule.SendTimeOn(time.Date(2026, 3, 29, 23, 30, 0, 0, time.UTC))
+	if !sendTime.Equal(time.Date(2026, 3, 30, 4, 30, 0, 0, time.UTC)) {
+		t.Fatalf("expected local 06:30 on the Berlin day after the DST switch, got %s", sendTime.UTC())
+	}
+}
+
+func TestBootstrapPersistsAttachmentDataPreference(t *testing.T) {
+	dbInstance := newTestDatabase(t)
+	keeper := newTestSecretKeeper(t)
+	metadataOnly := bootstrapTenantSpec("tenant-metadata", []string{"metadata.example"})
+	metadataOnly.PersistAttachmentData = ptrBool(false)
+	cfg := BootstrapConfig{Tenants: []BootstrapTenant{
+		metadataOnly,
+		bootstrapTenantSpec("tenant-default", []string{"default.example"}),
+	}}
+	if err := Bootstrap(context.Background(), dbInstance, keeper, cfg); err != nil {
+		t.Fatalf("bootstrap error: %v", err)
+	}
+	for tenantID, expected := range map[string]bool{"tenant-metadata": true, "tenant-default": false} {
+		var tenantModel Tenant
+		if err := dbInstance.Where(&Tenant{ID: tenantID}).First(&tenantModel).Error; err != nil {
+			t.Fatalf("fetch tenant %s: %v", tenantID, err)
+		}
+		if tenantModel.AttachmentMetadataOnly != expected {
+			t.Fatalf("expected %s AttachmentMetadataOnly=%v, got %v", tenantID, expected, tenantModel.AttachmentMetadataOnly)
+		}
+	}
+}
+
+func TestBootstrapValidatesTenantAddresses(t *testing.T) {
+	testCases := []struct {
+		name          string
+		supportEmail  string
+		fromAddress   string
+		expectedField string
+	}{
+		{name: "plain addresses", supportEmail: "support@alpha.example", fromAddress: "noreply@alpha.example"},
+		{name: "display name from", supportEmail: "", fromAddress: "Alpha Corp <noreply@alpha.example>"},
+		{name: "malformed from", supportEmail: "support@alpha.example", fromAddress: "noreply-at-alpha.example", expectedField: "emailProfile.fromAddress"},
+		{name: "missing from", supportEmail: "support@alpha.example", fromAddress: " ", expectedField: "emailProfile.fromAddress"},
+		{name: "malformed support", supportEmail: "support@", fromAddress: "noreply@alpha.example", expectedField: "supportEmail"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			dbInstance := newTestDatabase(t)
+			keeper := newTestSecretKeeper(t)
+			spec := bootstrapTenantSpec("tenant-alpha", []string{"alpha.example"})
+			spec.SupportEmail = testCase.supportEmail
+			spec.EmailProfile.FromAddress = testCase.fromAddress
+
+			err := Bootstrap(context.Background(), dbInstance, keeper, BootstrapConfig{Tenants: []BootstrapTenant{spec}})
+			if testCase.expectedField == "" {
+				if err != nil {
+					t.Fatalf("unexpected bootstrap error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected bootstrap to reject %s", testCase.expectedField)
+			}
+			for _, fragment := range []string{bootstrapInvalidAddressCode, "tenant tenant-alpha", testCase.expectedField} {
+				if !strings.Contains(err.Error(), fragment) {
+					t.Fatalf("expected %q in error, got %v", fragment, err)
+				}
+			}
+			var tenantCount int64
+			if countErr := dbInstance.Model(&Tenant{}).Count(&tenantCount).Error; countErr != nil || tenantCount != 0 {
+				t.Fatalf("expected the failed bootstrap to roll back, got %d tenants (%v)", tenantCount, countErr)
+			}
+		})
+	}
+}
+
+func TestBootstrapFromFileReportsEveryProblemWithTenantAndLine(t *testing.T) {
+	dbInstance := newTestDatabase(t)
+	keeper := newTestSecretKeeper(t)
+	tenantFile := `tenants:
+  - id: tenant-one
+    displayName: One
+    domains: [one.example]
+    emailProfile:
+      fromAddress: noreply@one.example
+  - id: tenant-two
+    displayName: Two
+    domains: [two.example]
+    emailProfile:
+      fromAddress: not-an-address
+  - id: tenant-three
+    displayName: Three
+    domains: []
+    emailProfile:
+      fromAddress: noreply@three.example
+`
+	path := filepath.Join(t.TempDir(), "tenants.yml")
+	if err := os.WriteFile(path, []byte(tenantFile), 0o600); err != nil {
+		t.Fatalf("write tenant file: %v", err)
+	}
+
+	err := BootstrapFromFile(context.Background(), dbInstance, keeper, path)
+	var validationErr *BootstrapValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected a bootstrap validation error, got %v", err)
+	}
+	if len(validationErr.Problems) != 2 {
+		t.Fatalf("expected both problems to be reported, got %q", validationErr.Problems)
+	}
+	if !strings.HasPrefix(validationErr.Problems[0], "tenant tenant-two (tenants[1], line 7): "+bootstrapInvalidAddressCode) {
+		t.Fatalf("unexpected first problem %q", validationErr.Problems[0])
+	}
+	if !strings.HasPrefix(validationErr.Problems[1], "tenant tenant-three (tenants[2], line 12): "+bootstrapMissingDomainCode) {
+		t.Fatalf("unexpected second problem %q", validationErr.Problems[1])
+	}
+	var tenantCount int64
+	if countErr := dbInstance.Model(&Tenant{}).Count(&tenantCount).Error; countErr != nil || tenantCount != 0 {
+		t.Fatalf("expected nothing to be written, got %d tenants (%v)", tenantCount, countErr)
+	}
+}
+
+func TestBootstrapYAMLCollectsDecodeProblemsPerTenant(t *testing.T) {
+	var cfg BootstrapConfig
+	err := yaml.Unmarshal([]byte(`tenants:
+  - id: tenant-one
+    displayName: One
+    colour: blue
+  - id: tenant-two
+    displayName: Two
+  - displayName: Anonymous
+    emailProfile:
+      fromAdress: noreply@example.com
+`), &cfg)
+	var validationErr *BootstrapValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected a bootstrap validation error, got %v", err)
+	}
+	expected := []string{
+		"tenant tenant-one (tenants[0], line 2): tenants[].colour is not supported",
+		"tenant (tenants[2], line 7): tenants[].emailProfile.fromAdress is not supported",
+	}
+	if len(validationErr.Problems) != len(expected) {
+		t.Fatalf("expected %d problems, got %q", len(expected), validationErr.Problems)
+	}
+	for problemIndex, problem := range validationErr.Problems {
+		if problem != expected[problemIndex] {
+			t.Fatalf("expected %q, got %q", expected[problemIndex], problem)
+		}
+	}
+}
+
+func TestBootstrapPersistsTenantRetryCap(t *testing.T) {
+	dbInstance := newTestDatabase(t)
+	keeper := newTestSecretKeeper(t)
+	var cfg BootstrapConfig
+	rawConfig := `
+tenants:
+  - id: tenant-one
+    displayName: Alpha Corp
+    domains: [alpha.example]
+    emailProfile:
+      fromAddress: noreply@alpha.example
+    maxConcurrentRetries: 4
+`
+	if err := yaml.Unmarshal([]byte(rawConfig), &cfg); err != nil {
+		t.Fatalf("parse retry cap: %v", err)
+	}
+	if err := Bootstrap(context.Background(), dbInstance, keeper, cfg); err != nil {
+		t.Fatalf("bootstrap error: %v", err)
+	}
+	var tenantModel Tenant
+	if err := dbInstance.Where(&Tenant{ID: "tenant-one"}).First(&tenantModel).Error; err != nil {
+		t.Fatalf("fetch tenant: %v", err)
+	}
+	if tenantModel.MaxConcurrentRetries != 4 {
+		t.Fatalf("expected retry cap 4, got %d", tenantModel.MaxConcurrentRetries)
+	}
+
+	cfg.Tenants[0].MaxConcurrentRetries = -1
+	err := ValidateBootstrapConfig(cfg)
+	if err == nil || !strings.Contains(err.Error(), "tenant tenant-one (tenants[0], line 3): maxConcurrentRetries must not be negative") {
+		t.Fatalf("expected a negative retry cap to be rejected, got %v", err)
+	}
+}
+
+func TestBootstrapPersistsNotificationIDPrefix(t *testing.T) {
+	dbInstance := newTestDatabase(t)
+	keeper := newTestSecretKeeper(t)
+	cfg := sampleBootstrapConfig()
+	cfg.Tenants[0].NotificationIDPrefix = " acme "
+	if err := Bootstrap(context.Background(), dbInstance, keeper, cfg); err != nil {
+		t.Fatalf("bootstrap error: %v", err)
+	}
+	runtimeCfg, err := NewRepository(dbInstance, keeper).ResolveByID(context.Background(), "tenant-one")
+	if err != nil {
+		t.Fatalf("resolve tenant: %v", err)
+	}
+	if runtimeCfg.NotificationIDPrefix() != "acme" {
+		t.Fatalf("expected prefix acme, got %q", runtimeCfg.NotificationIDPrefix())
+	}
+	if (RuntimeConfig{}).NotificationIDPrefix() != DefaultNotificationIDPrefix {
+		t.Fatalf("expected tenants without a prefix to use %q", DefaultNotificationIDPrefix)
+	}
+
+	for _, invalidPrefix := range []string{"acme-", "acme corp", "acme/", strings.Repeat("a", 33)} {
+		cfg.Tenants[0].NotificationIDPrefix = invalidPrefix
+		if err := ValidateBootstrapConfig(cfg); err == nil || !strings.Contains(err.Error(), "notificationIdPrefix") {
+			t.Fatalf("expected prefix %q to be rejected, got %v", invalidPrefix, err)
+		}
+	}
+}
+
+func TestBootstrapPersistsEncryptedWebhook(t *testing.T) {
+	dbInstance := newTestDatabase(t)
+	keeper := newTestSecretKeeper(t)
+	cfg := sampleBootstrapConfig()
+	cfg.Tenants[0].Webhook = &BootstrapWebhook{URL: "https://hooks.example.com/pinguin", Secret: "whsec-one"}
+	if err := Bootstrap(context.Background(), dbInstance, keeper, cfg); err != nil {
+		t.Fatalf("bootstrap error: %v", err)
+	}
+	var tenantModel Tenant
+	if err := dbInstance.Where(&Tenant{ID: "tenant-one"}).First(&tenantModel).Error; err != nil {
+		t.Fatalf("load tenant: %v", err)
+	}
+	if len(tenantModel.WebhookSecretCipher) == 0 || strings.Contains(string(tenantModel.WebhookSecretCipher), "whsec-one") {
+		t.Fatalf("expected the webhook secret to be stored encrypted")
+	}
+	runtimeCfg, err := NewRepository(dbInstance, keeper).ResolveByID(context.Background(), "tenant-one")
+	if err != nil {
+		t.Fatalf("resolve tenant: %v", err)
+	}
+	if runtimeCfg.Webhook == nil || runtimeCfg.Webhook.URL != "https://hooks.example.com/pinguin" || runtimeCfg.Webhook.Secret != "whsec-one" {
+		t.Fatalf("unexpected webhook runtime %+v", runtimeCfg.Webhook)
+	}
+
+	cfg.Tenants[0].Webhook = &BootstrapWebhook{URL: "ftp://hooks.example.com", Secret: ""}
+	err = ValidateBootstrapConfig(cfg)
+	if err == nil || !strings.Contains(err.Error(), "webhook.url") || !strings.Contains(err.Error(), "webhook.secret") {
+		t.Fatalf("expected an invalid webhook to be rejected, got %v", err)
+	}
+
+	var parsed BootstrapConfig
+	if err := yaml.Unmarshal([]byte("tenants:\n  - webhook:\n      url: https://hooks.example.com\n      events: [sent]\n"), &parsed); err == nil || !strings.Contains(err.Error(), "tenants[].webhook.events is not supported") {
+		t.Fatalf("expected unknown webhook keys to be rejected, got %v", err)
+	}
+}
+
+func TestBootstrapPersistsSMSLimits(t *testing.T) {
+	dbInstance := newTestDatabase(t)
+	keeper := newTestSecretKeeper(t)
+	var parsed BootstrapConfig
+	if err := yaml.Unmarshal([]byte("tenants:\n  - id: tenant-one\n    smsLimits:\n      maxSegments: 2\n      overflowPolicy: Truncate\n      truncationSuffix: \" [more]\"\n"), &parsed); err != nil {
+		t.Fatalf("parse smsLimits: %v", err)
+	}
+	cfg := sampleBootstrapConfig()
+	cfg.Tenants[0].SMSLimits = parsed.Tenants[0].SMSLimits
+	if err := Bootstrap(context.Background(), dbInstance, keeper, cfg); err != nil {
+		t.Fatalf("bootstrap error: %v", err)
+	}
+	runtimeCfg, err := NewRepository(dbInstance, keeper).ResolveByID(context.Background(), "tenant-one")
+	if err != nil {
+		t.Fatalf("resolve tenant: %v", err)
+	}
+	if runtimeCfg.Tenant.SMSMaxSegments != 2 || runtimeCfg.Tenant.SMSOverflowPolicy != SMSOverflowTruncate || runtimeCfg.Tenant.SMSTruncationSuffix != " [more]" {
+		t.Fatalf("unexpected sms limits %+v", runtimeCfg.Tenant)
+	}
+
+	cfg.Tenants[0].SMSLimits = &BootstrapSMSLimits{OverflowPolicy: "shorten", TruncationSuffix: "\n"}
+	err = ValidateBootstrapConfig(cfg)
+	if err == nil || !strings.Contains(err.Error(), "smsLimits.overflowPolicy") || !strings.Contains(err.Error(), "smsLimits.truncationSuffix") {
+		t.Fatalf("expected invalid sms limits to be rejected, got %v", err)
+	}
+	cfg.Tenants[0].SMSLimits = &BootstrapSMSLimits{OverflowPolicy: SMSOverflowTruncate}
+	if err := ValidateBootstrapConfig(cfg); err == nil || !strings.Contains(err.Error(), "requires smsLimits.maxSegments") {
+		t.Fatalf("expected truncation without a segment cap to be rejected, got %v", err)
+	}
+}
+
+func TestBootstrapPersistsSenderOverridePolicy(t *testing.T) {
+	dbInstance := newTestDatabase(t)
+	keeper := newTestSecretKeeper(t)
+	var parsed BootstrapConfig
+	if err := yaml.Unmarshal([]byte("tenants:\n  - id: tenant-one\n    senderOverrides:\n      policy: AllowList\n      allowedDomains: [Support.Acme.example, agents.acme.example]\n"), &parsed); err != nil {
+		t.Fatalf("parse senderOverrides: %v", err)
+	}
+	cfg := sampleBootstrapConfig()
+	cfg.Tenants[0].SenderOverrides = parsed.Tenants[0].SenderOverrides
+	if err := Bootstrap(context.Background(), dbInstance, keeper, cfg); err != nil {
+		t.Fatalf("bootstrap error: %v", err)
+	}
+	runtimeCfg, err := NewRepository(dbInstance, keeper).ResolveByID(context.Background(), "tenant-one")
+	if err != nil {
+		t.Fatalf("resolve tenant: %v", err)
+	}
+	if runtimeCfg.Tenant.SenderOverridePolicy != SenderOverridesAllowlist || runtimeCfg.Tenant.SenderOverrideDomains != "support.acme.example,agents.acme.example" {
+		t.Fatalf("unexpected sender override policy %+v", runtimeCfg.Tenant)
+	}
+
+	cfg.Tenants[0].SenderOverrides = &BootstrapSenderOverrides{Policy: "sometimes", AllowedDomains: []string{"*.acme.example"}}
+	err = ValidateBootstrapConfig(cfg)
+	if err == nil || !strings.Contains(err.Error(), "senderOverrides.policy") || !strings.Contains(err.Error(), "senderOverrides.allowedDomains entry") {
+		t.Fatalf("expected an invalid sender override policy to be rejected, got %v", err)
+	}
+	cfg.Tenants[0].SenderOverrides = &BootstrapSenderOverrides{Policy: SenderOverridesAllowlist}
+	if err := ValidateBootstrapConfig(cfg); err == nil || !strings.Contains(err.Error(), "requires senderOverrides.allowedDomains") {
+		t.Fatalf("expected an allowlist without domains to be rejected, got %v", err)
+	}
+	cfg.Tenants[0].SenderOverrides = &BootstrapSenderOverrides{Policy: SenderOverridesAllow, AllowedDomains: []string{"acme.example"}}
+	if err := ValidateBootstrapConfig(cfg); err == nil || !strings.Contains(err.Error(), "requires senderOverrides.policy allowlist") {
+		t.Fatalf("expected domains without the allowlist policy to be rejected, got %v", err)
+	}
+	if err := yaml.Unmarshal([]byte("tenants:\n  - senderOverrides:\n      fromAddress: a@acme.example\n"), &parsed); err == nil || !strings.Contains(err.Error(), "tenants[].senderOverrides.fromAddress is not supported") {
+		t.Fatalf("expected unknown senderOverrides keys to be rejected, got %v", err)
+	}
+}
+
+func TestTenantAllowsSenderOverride(t *testing.T) {
+	for _, testCase := range []struct {
+		name          string
+		tenantModel   Tenant
+		replyToDomain string
+		allowed       bool
+	}{
+		{name: "unset policy denies", tenantModel: Tenant{}, replyToDomain: "acme.example", allowed: false},
+		{name: "deny denies display names", tenantModel: Tenant{SenderOverridePolicy: SenderOverridesDeny}, allowed: false},
+		{name: "allow accepts any domain", tenantModel: Tenant{SenderOverridePolicy: SenderOverridesAllow}, replyToDomain: "elsewhere.example", allowed: true},
+		{name: "allowlist accepts a listed domain", tenantModel: Tenant{SenderOverridePolicy: SenderOverridesAllowlist, SenderOverrideDomains: "support.acme.example,acme.example"}, replyToDomain: "ACME.example", allowed: true},
+		{name: "allowlist refuses subdomains", tenantModel: Tenant{SenderOverridePolicy: SenderOverridesAllowlist, SenderOverrideDomains: "acme.example"}, replyToDomain: "evil.acme.example", allowed: false},
+		{name: "allowlist accepts display names alone", tenantModel: Tenant{SenderOverridePolicy: SenderOverridesAllowlist, SenderOverrideDomains: "acme.example"}, allowed: true},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if allowed := testCase.tenantModel.AllowsSenderOverride(testCase.replyToDomain); allowed != testCase.allowed {
+				t.Fatalf("expected allowed=%v, got %v", testCase.allowed, allowed)
+			}
+		})
+	}
+}
+
+func TestBootstrapPersistsStoreRenderedContent(t *testing.T) {
+	dbInstance := newTestDatabase(t)
+	keeper := newTestSecretKeeper(t)
+	cfg := sampleBootstrapConfig()
+	cfg.Tenants[0].StoreRenderedContent = true
+	if err := Bootstrap(context.Background(), dbInstance, keeper, cfg); err != nil {
+		t.Fatalf("bootstrap error: %v", err)
+	}
+	runtimeCfg, err := NewRepository(dbInstance, keeper).ResolveByID(context.Background(), "tenant-one")
+	if err != nil {
+		t.Fatalf("resolve tenant: %v", err)
+	}
+	if !runtimeCfg.Tenant.StoreRenderedContent {
+		t.Fatalf("expected storeRenderedContent to persist")
+	}
+}
+
+func TestBootstrapPersistsRetentionDays(t *testing.T) {
+	dbInstance := newTestDatabase(t)
+	keeper := newTestSecretKeeper(t)
+	var cfg BootstrapConfig
+	rawConfig := `
+tenants:
+  - id: tenant-one
+    displayName: Alpha Corp
+    domains: [alpha.example]
+    emailProfile:
+      fromAddress: noreply@alpha.example
+    retentionDays: 90
+    maxRetryAgeSec: 86400
+`
+	if err := yaml.Unmarshal([]byte(rawConfig), &cfg); err != nil {
+		t.Fatalf("parse retention: %v", err)
+	}
+	if err := Bootstrap(context.Background(), dbInstance, keeper, cfg); err != nil {
+		t.Fatalf("bootstrap error: %v", err)
+	}
+	var tenantModel Tenant
+	if err := dbInstance.Where(&Tenant{ID: "tenant-one"}).First(&tenantModel).Error; err != nil {
+		t.Fatalf("fetch tenant: %v", err)
+	}
+	if tenantModel.RetentionDays != 90 || tenantModel.MaxRetryAgeSec != 86400 {
+		t.Fatalf("expected retention 90 and max retry age 86400, got %d and %d", tenantModel.RetentionDays, tenantModel.MaxRetryAgeSec)
+	}
+
+	cfg.Tenants[0].RetentionDays = -1
+	err := ValidateBootstrapConfig(cfg)
+	if err == nil || !strings.Contains(err.Error(), "tenant tenant-one (tenants[0], line 3): retentionDays must not be negative") {
+		t.Fatalf("expected a negative retention to be rejected, got %v", err)
+	}
+
+	cfg.Tenants[0].RetentionDays = 90
+	cfg.Tenants[0].MaxRetryAgeSec = -1
+	err = ValidateBootstrapConfig(cfg)
+	if err == nil || !strings.Contains(err.Error(), "maxRetryAgeSec must not be negative") {
+		t.Fatalf("expected a negative max retry age to be rejected, got %v", err)
+	}
+}
+
+func TestBootstrapPersistsEmailProfileTimeouts(t *testing.T) {
+	dbInstance := newTestDatabase(t)
+	keeper := newTestSecretKeeper(t)
+	var cfg BootstrapConfig
+	rawConfig := `
+tenants:
+  - id: tenant-one
+    displayName: Alpha Corp
+    domains: [alpha.example]
+    emailProfile:
+      host: relay.alpha.internal
+      port: 25
+      fromAddress: noreply@alpha.example
+      connectionTimeoutSec: 30
+      operationTimeoutSec: 120
+`
+	if err := yaml.Unmarshal([]byte(rawConfig), &cfg); err != nil {
+		t.Fatalf("parse email profile timeouts: %v", err)
+	}
+	if err := Bootstrap(context.Background(), dbInstance, keeper, cfg); err != nil {
+		t.Fatalf("bootstrap error: %v", err)
+	}
+	runtimeCfg, err := NewRepository(dbInstance, keeper).ResolveByID(context.Background(), "tenant-one")
+	if err != nil {
+		t.Fatalf("resolve tenant: %v", err)
+	}
+	if runtimeCfg.Email.ConnectionTimeoutSec != 30 || runtimeCfg.Email.OperationTimeoutSec != 120 {
+		t.Fatalf("expected timeouts 30 and 120, got %d and %d", runtimeCfg.Email.ConnectionTimeoutSec, runtimeCfg.Email.OperationTimeoutSec)
+	}
+
+	cfg.Tenants[0].EmailProfile.OperationTimeoutSec = -1
+	err = ValidateBootstrapConfig(cfg)
+	if err == nil || !strings.Contains(err.Error(), "emailProfile.operationTimeoutSec must not be negative") {
+		t.Fatalf("expected a negative operation timeout to be rejected, got %v", err)
+	}
+}
+
+func TestBootstrapPersistsEmailProfilePoolSettings(t *testing.T) {
+	dbInstance := newTestDatabase(t)
+	keeper := newTestSecretKeeper(t)
+	var cfg BootstrapConfig
+	rawConfig := `
+tenants:
+  - id: tenant-one
+    displayName: Alpha Corp
+    domains: [alpha.example]
+    emailProfile:
+      host: relay.alpha.internal
+      port: 25
+      fromAddress: noreply@alpha.example
+      poolSize: 8
+      poolIdleTimeoutSec: 30
+`
+	if err := yaml.Unmarshal([]byte(rawConfig), &cfg); err != nil {
+		t.Fatalf("parse email profile pool settings: %v", err)
+	}
+	if err := Bootstrap(context.Background(), dbInstance, keeper, cfg); err != nil {
+		t.Fatalf("bootstrap error: %v", err)
+	}
+	runtimeCfg, err := NewRepository(dbInstance, keeper).ResolveByID(context.Background(), "tenant-one")
+	if err != nil {
+		t.Fatalf("resolve tenant: %v", err)
+	}
+	if runtimeCfg.Email.PoolSize != 8 || runtimeCfg.Email.PoolIdleTimeoutSec != 30 {
+		t.Fatalf("expected pool size 8 and idle timeout 30, got %d and %d", runtimeCfg.Email.PoolSize, runtimeCfg.Email.PoolIdleTimeoutSec)
+	}
+
+	cfg.Tenants[0].EmailProfile.PoolSize = -1
+	err = ValidateBootstrapConfig(cfg)
+	if err == nil || !strings.Contains(err.Error(), "emailProfile.poolSize must not be negative") {
+		t.Fatalf("expected a negative pool size to be rejected, got %v", err)
+	}
+}
+
+func TestBootstrapPersistsOpenTrackingOptIn(t *testing.T) {
+	dbInstance := newTestDatabase(t)
+	keeper := newTestSecretKeeper(t)
+	var cfg BootstrapConfig
+	rawConfig := `
+tenants:
+  - id: tenant-tracked
+    displayName: Tracked Corp
+    domains: [tracked.example]
+    openTracking: true
+    emailProfile:
+      host: relay.tracked.internal
+      port: 25
+      fromAddress: noreply@tracked.example
+  - id: tenant-default
+    displayName: Default Corp
+    domains: [default.example]
+    emailProfile:
+      host: relay.default.internal
+      port: 25
+      fromAddress: noreply@default.example
+`
+	if err := yaml.Unmarshal([]byte(rawConfig), &cfg); err != nil {
+		t.Fatalf("parse open tracking setting: %v", err)
+	}
+	if err := Bootstrap(context.Background(), dbInstance, keeper, cfg); err != nil {
+		t.Fatalf("bootstrap error: %v", err)
+	}
+	repo := NewRepository(dbInstance, keeper)
+	for tenantID, expected := range map[string]bool{"tenant-tracked": true, "tenant-default": false} {
+		runtimeCfg, err := repo.ResolveByID(context.Background(), tenantID)
+		if err != nil {
+			t.Fatalf("resolve %s: %v", tenantID, err)
+		}
+		if runtimeCfg.Tenant.OpenTracking != expected {
+			t.Fatalf("%s: expected open tracking %v, got %v", tenantID, expected, runtimeCfg.Tenant.OpenTracking)
+		}
+	}
+}
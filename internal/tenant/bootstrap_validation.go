@@ -0,0 +1,176 @@
+package tenant
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BootstrapValidationError lists every problem found in a bootstrap config so a file
+// with many tenants can be fixed in one pass. Per-tenant problems name the tenant id,
+// its index, and its YAML line when the config was decoded from a file.
+type BootstrapValidationError struct {
+	Problems []string
+}
+
+func (validationErr *BootstrapValidationError) Error() string {
+	return "tenant bootstrap: " + strings.Join(validationErr.Problems, "; ")
+}
+
+// DecodeBootstrapTenants decodes a tenants sequence entry by entry, keeping each
+// tenant's source line and collecting the decode problems of every entry instead of
+// stopping at the first.
+func DecodeBootstrapTenants(value *yaml.Node) ([]BootstrapTenant, error) {
+	if value == nil || value.Kind == 0 {
+		return nil, nil
+	}
+	if value.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("tenant bootstrap: tenants must be a list")
+	}
+	tenants := make([]BootstrapTenant, 0, len(value.Content))
+	var problems []string
+	for tenantIndex, tenantNode := range value.Content {
+		var spec BootstrapTenant
+		if err := tenantNode.Decode(&spec); err != nil {
+			label := bootstrapTenantLabel(tenantIndex, yamlMappingScalar(tenantNode, "id"), "", tenantNode.Line)
+			problems = append(problems, fmt.Sprintf("%s: %s", label, strings.TrimPrefix(err.Error(), "tenant bootstrap: ")))
+			continue
+		}
+		tenants = append(tenants, spec)
+	}
+	if len(problems) > 0 {
+		return nil, &BootstrapValidationError{Problems: problems}
+	}
+	return tenants, nil
+}
+
+// ValidateBootstrapConfig checks the whole config without touching the database.
+// Bootstrap runs it before writing anything, and the doctor runs it so file-mode and
+// runtime-mode report the same problems.
+func ValidateBootstrapConfig(cfg BootstrapConfig) error {
+	if len(cfg.Tenants) == 0 {
+		return &BootstrapValidationError{Problems: []string{"no tenants configured"}}
+	}
+	var problems []string
+	enabledCount := 0
+	claimedHosts := make(map[string]int, len(cfg.Tenants))
+	for tenantIndex, spec := range cfg.Tenants {
+		label := bootstrapTenantLabel(tenantIndex, spec.ID, spec.SourceFile, spec.SourceLine)
+		if spec.Enabled == nil || *spec.Enabled {
+			enabledCount++
+		}
+		if strings.TrimSpace(spec.Status) != "" {
+			problems = append(problems, fmt.Sprintf("%s: tenants[].status is no longer supported; use tenants[].enabled (true|false)", label))
+		}
+		if spec.MaxConcurrentRetries < 0 {
+			problems = append(problems, fmt.Sprintf("%s: maxConcurrentRetries must not be negative", label))
+		}
+		if spec.RetentionDays < 0 {
+			problems = append(problems, fmt.Sprintf("%s: retentionDays must not be negative", label))
+		}
+		if spec.MaxRetryAgeSec < 0 {
+			problems = append(problems, fmt.Sprintf("%s: maxRetryAgeSec must not be negative", label))
+		}
+		if spec.EmailProfile.ConnectionTimeoutSec < 0 {
+			problems = append(problems, fmt.Sprintf("%s: emailProfile.connectionTimeoutSec must not be negative", label))
+		}
+		if spec.EmailProfile.OperationTimeoutSec < 0 {
+			problems = append(problems, fmt.Sprintf("%s: emailProfile.operationTimeoutSec must not be negative", label))
+		}
+		if spec.EmailProfile.PoolSize < 0 {
+			problems = append(problems, fmt.Sprintf("%s: emailProfile.poolSize must not be negative", label))
+		}
+		if spec.EmailProfile.PoolIdleTimeoutSec < 0 {
+			problems = append(problems, fmt.Sprintf("%s: emailProfile.poolIdleTimeoutSec must not be negative", label))
+		}
+		if _, err := ParseCallerAllowlist(spec.CallerAllowlist); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", label, err))
+		}
+		if !validNotificationIDPrefix(spec.NotificationIDPrefix) {
+			problems = append(problems, fmt.Sprintf("%s: notificationIdPrefix %q must be 1-%d letters, digits, '_' or '-' and must not end with '-'", label, strings.TrimSpace(spec.NotificationIDPrefix), maxNotificationIDPrefixLength))
+		}
+		for _, webhookProblem := range webhookProblems(spec.Webhook) {
+			problems = append(problems, fmt.Sprintf("%s: %s", label, webhookProblem))
+		}
+		for _, smsLimitsProblem := range smsLimitsProblems(spec.SMSLimits) {
+			problems = append(problems, fmt.Sprintf("%s: %s", label, smsLimitsProblem))
+		}
+		for _, senderOverridesProblem := range senderOverridesProblems(spec.SenderOverrides) {
+			problems = append(problems, fmt.Sprintf("%s: %s", label, senderOverridesProblem))
+		}
+		for _, addressProblem := range tenantAddressProblems(spec) {
+			problems = append(problems, fmt.Sprintf("%s: %s", label, addressProblem))
+		}
+		domainCount := 0
+		for _, host := range normalizeDomainHosts(spec.Domains) {
+			domainCount++
+			if !validDomainPattern(host) {
+				problems = append(problems, fmt.Sprintf("%s: %s: domain %s must be a host name or a wildcard such as *.acme.example", label, bootstrapInvalidDomainCode, host))
+				continue
+			}
+			if existingIndex, claimed := claimedHosts[host]; claimed {
+				existing := cfg.Tenants[existingIndex]
+				problems = append(problems, fmt.Sprintf("%s: %s: duplicate domain %s already claimed by %s", label, bootstrapDuplicateDomainCode, host, bootstrapTenantLabel(existingIndex, existing.ID, existing.SourceFile, existing.SourceLine)))
+				continue
+			}
+			claimedHosts[host] = tenantIndex
+		}
+		if domainCount == 0 {
+			problems = append(problems, fmt.Sprintf("%s: %s: tenant has no domains", label, bootstrapMissingDomainCode))
+		}
+	}
+	if enabledCount == 0 {
+		problems = append(problems, "no enabled tenants configured")
+	}
+	if len(problems) > 0 {
+		return &BootstrapValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// tenantAddressProblems rejects From and support addresses that would produce
+// invalid email headers. The support address is optional.
+func tenantAddressProblems(spec BootstrapTenant) []string {
+	var problems []string
+	if _, err := mail.ParseAddress(spec.EmailProfile.FromAddress); err != nil {
+		problems = append(problems, fmt.Sprintf("%s: emailProfile.fromAddress %q is not a valid email address: %v", bootstrapInvalidAddressCode, spec.EmailProfile.FromAddress, err))
+	}
+	if strings.TrimSpace(spec.SupportEmail) == "" {
+		return problems
+	}
+	if _, err := mail.ParseAddress(spec.SupportEmail); err != nil {
+		problems = append(problems, fmt.Sprintf("%s: supportEmail %q is not a valid email address: %v", bootstrapInvalidAddressCode, spec.SupportEmail, err))
+	}
+	return problems
+}
+
+// bootstrapTenantLabel names a tenant by id and position. Tenants merged from several
+// files are located by file and line, since the merged index matches no single file.
+func bootstrapTenantLabel(tenantIndex int, tenantID string, sourceFile string, line int) string {
+	location := fmt.Sprintf("tenants[%d]", tenantIndex)
+	if sourceFile != "" {
+		location = sourceFile
+	}
+	if line > 0 {
+		location = fmt.Sprintf("%s, line %d", location, line)
+	}
+	tenantID = strings.TrimSpace(tenantID)
+	if tenantID == "" {
+		return fmt.Sprintf("tenant (%s)", location)
+	}
+	return fmt.Sprintf("tenant %s (%s)", tenantID, location)
+}
+
+func yamlMappingScalar(value *yaml.Node, key string) string {
+	if value == nil || value.Kind != yaml.MappingNode {
+		return ""
+	}
+	for contentIndex := 0; contentIndex+1 < len(value.Content); contentIndex += 2 {
+		if strings.TrimSpace(value.Content[contentIndex].Value) == key && value.Content[contentIndex+1].Kind == yaml.ScalarNode {
+			return value.Content[contentIndex+1].Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,67 @@
+package tenant
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+const callerAllowlistSeparator = ","
+
+// ParseCallerAllowlist parses callerAllowlist entries. Each entry is a CIDR; a bare
+// address is taken as a single host. Blank entries are ignored, so an empty list means
+// the tenant accepts any caller.
+func ParseCallerAllowlist(entries []string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, entry := range entries {
+		trimmed := strings.TrimSpace(entry)
+		if trimmed == "" {
+			continue
+		}
+		prefix, err := netip.ParsePrefix(trimmed)
+		if err != nil {
+			address, addressErr := netip.ParseAddr(trimmed)
+			if addressErr != nil || address.Zone() != "" {
+				return nil, fmt.Errorf("callerAllowlist entry %q is not a valid CIDR", trimmed)
+			}
+			prefix = netip.PrefixFrom(address.Unmap(), address.Unmap().BitLen())
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// AllowsCaller reports whether a gRPC peer address may act for the tenant. Every
+// address passes when no allowlist is configured; otherwise an invalid address, such as
+// a Unix socket peer, is refused. IPv4-mapped IPv6 peers from dual-stack listeners
+// match IPv4 entries.
+func (cfg RuntimeConfig) AllowsCaller(address netip.Addr) bool {
+	if len(cfg.CallerAllowlist) == 0 {
+		return true
+	}
+	if !address.IsValid() {
+		return false
+	}
+	address = address.Unmap().WithZone("")
+	for _, prefix := range cfg.CallerAllowlist {
+		if prefix.Contains(address) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatCallerAllowlist(prefixes []netip.Prefix) string {
+	formatted := make([]string, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		formatted = append(formatted, prefix.String())
+	}
+	return strings.Join(formatted, callerAllowlistSeparator)
+}
+
+func splitCallerAllowlist(stored string) []string {
+	if stored == "" {
+		return nil
+	}
+	return strings.Split(stored, callerAllowlistSeparator)
+}
@@ -0,0 +1,90 @@
+package tenant
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestRuntimeConfigAllowsCaller(t *testing.T) {
+	testCases := []struct {
+		name      string
+		allowlist []string
+		address   string
+		allowed   bool
+	}{
+		{name: "empty allowlist allows anyone", allowlist: nil, address: "203.0.113.9", allowed: true},
+		{name: "empty allowlist allows unix peers", allowlist: []string{" "}, address: "", allowed: true},
+		{name: "ipv4 inside", allowlist: []string{"10.20.0.0/16"}, address: "10.20.3.4", allowed: true},
+		{name: "ipv4 outside", allowlist: []string{"10.20.0.0/16"}, address: "10.21.0.1", allowed: false},
+		{name: "ipv4-mapped peer", allowlist: []string{"10.20.0.0/16"}, address: "::ffff:10.20.3.4", allowed: true},
+		{name: "bare ipv4 address", allowlist: []string{"192.0.2.7"}, address: "192.0.2.7", allowed: true},
+		{name: "ipv6 inside", allowlist: []string{"10.20.0.0/16", "2001:db8:10::/48"}, address: "2001:db8:10:ffff::1", allowed: true},
+		{name: "ipv6 outside", allowlist: []string{"2001:db8:10::/48"}, address: "2001:db8:11::1", allowed: false},
+		{name: "ipv6 zone ignored", allowlist: []string{"fe80::/10"}, address: "fe80::1%eth0", allowed: true},
+		{name: "unix peer refused", allowlist: []string{"10.20.0.0/16"}, address: "", allowed: false},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			prefixes, err := ParseCallerAllowlist(testCase.allowlist)
+			if err != nil {
+				t.Fatalf("parse allowlist: %v", err)
+			}
+			var address netip.Addr
+			if testCase.address != "" {
+				address = netip.MustParseAddr(testCase.address)
+			}
+			if allowed := (RuntimeConfig{CallerAllowlist: prefixes}).AllowsCaller(address); allowed != testCase.allowed {
+				t.Fatalf("AllowsCaller(%q) = %v, want %v", testCase.address, allowed, testCase.allowed)
+			}
+		})
+	}
+}
+
+func TestParseCallerAllowlistRejectsInvalidEntries(t *testing.T) {
+	for _, entry := range []string{"10.0.0/8", "10.0.0.0/33", "2001:db8::/129", "fe80::1%eth0", "internal.example"} {
+		if _, err := ParseCallerAllowlist([]string{"10.0.0.0/8", entry}); err == nil || !strings.Contains(err.Error(), "not a valid CIDR") {
+			t.Fatalf("expected %q to be rejected, got %v", entry, err)
+		}
+	}
+}
+
+func TestBootstrapPersistsCallerAllowlist(t *testing.T) {
+	dbInstance := newTestDatabase(t)
+	keeper := newTestSecretKeeper(t)
+	var cfg BootstrapConfig
+	rawConfig := `
+tenants:
+  - id: tenant-one
+    displayName: Alpha Corp
+    domains: [alpha.example]
+    emailProfile:
+      fromAddress: noreply@alpha.example
+    callerAllowlist: [10.20.3.4/16, "2001:db8:10::/48", 192.0.2.7]
+`
+	if err := yaml.Unmarshal([]byte(rawConfig), &cfg); err != nil {
+		t.Fatalf("parse allowlist: %v", err)
+	}
+	if err := Bootstrap(context.Background(), dbInstance, keeper, cfg); err != nil {
+		t.Fatalf("bootstrap error: %v", err)
+	}
+	runtimeCfg, err := NewRepository(dbInstance, keeper).ResolveByID(context.Background(), "tenant-one")
+	if err != nil {
+		t.Fatalf("resolve tenant: %v", err)
+	}
+	if runtimeCfg.Tenant.CallerAllowlist != "10.20.0.0/16,2001:db8:10::/48,192.0.2.7/32" {
+		t.Fatalf("expected canonical CIDRs to be stored, got %q", runtimeCfg.Tenant.CallerAllowlist)
+	}
+	if len(runtimeCfg.CallerAllowlist) != 3 || !runtimeCfg.AllowsCaller(netip.MustParseAddr("10.20.9.9")) || runtimeCfg.AllowsCaller(netip.MustParseAddr("198.51.100.1")) {
+		t.Fatalf("expected the resolved runtime config to carry the parsed allowlist, got %v", runtimeCfg.CallerAllowlist)
+	}
+
+	cfg.Tenants[0].CallerAllowlist = []string{"10.20.0.0/16", "not-a-cidr"}
+	err = ValidateBootstrapConfig(cfg)
+	if err == nil || !strings.Contains(err.Error(), `tenant tenant-one (tenants[0], line 3): callerAllowlist entry "not-a-cidr" is not a valid CIDR`) {
+		t.Fatalf("expected an invalid CIDR to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package tenant
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultDailyReportSendAt is the local time the digest goes out when sendAt is omitted.
+	DefaultDailyReportSendAt = "07:00"
+	// DefaultDailyReportTimezone is used when a tenant does not name an IANA zone.
+	DefaultDailyReportTimezone = "UTC"
+
+	dailyReportSendAtLayout = "15:04"
+)
+
+// ErrDailyReportScheduleInvalid reports a stored daily report schedule that cannot be parsed.
+var ErrDailyReportScheduleInvalid = errors.New("tenant: invalid daily report schedule")
+
+// DailyReportSchedule is the parsed daily digest schedule for one tenant.
+type DailyReportSchedule struct {
+	Hour     int
+	Minute   int
+	Location *time.Location
+}
+
+// ParseDailyReportSendAt splits an HH:MM value into its local hour and minute.
+func ParseDailyReportSendAt(sendAt string) (int, int, error) {
+	parsed, err := time.Parse(dailyReportSendAtLayout, sendAt)
+	if err != nil {
+		return 0, 0, err
+	}
+	return parsed.Hour(), parsed.Minute(), nil
+}
+
+// SendTimeOn returns the scheduled send time on the local day containing now.
+func (schedule DailyReportSchedule) SendTimeOn(now time.Time) time.Time {
+	localNow := now.In(schedule.Location)
+	return time.Date(localNow.Year(), localNow.Month(), localNow.Day(), schedule.Hour, schedule.Minute, 0, 0, schedule.Location)
+}
+
+// DailyReportSchedule returns the tenant digest schedule; ok is false when reports are off.
+func (tenantModel Tenant) DailyReportSchedule() (DailyReportSchedule, bool, error) {
+	if !tenantModel.DailyReportEnabled {
+		return DailyReportSchedule{}, false, nil
+	}
+	sendAt := tenantModel.DailyReportSendAt
+	if sendAt == "" {
+		sendAt = DefaultDailyReportSendAt
+	}
+	hour, minute, err := ParseDailyReportSendAt(sendAt)
+	if err != nil {
+		return DailyReportSchedule{}, false, fmt.Errorf("%w: tenant %s sendAt %q", ErrDailyReportScheduleInvalid, tenantModel.ID, sendAt)
+	}
+	timezone := tenantModel.DailyReportTimezone
+	if timezone == "" {
+		timezone = DefaultDailyReportTimezone
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return DailyReportSchedule{}, false, fmt.Errorf("%w: tenant %s timezone %q", ErrDailyReportScheduleInvalid, tenantModel.ID, timezone)
+	}
+	return DailyReportSchedule{Hour: hour, Minute: minute, Location: location}, true, nil
+}
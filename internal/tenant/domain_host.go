@@ -0,0 +1,68 @@
+package tenant
+
+import (
+	"net"
+	"strings"
+)
+
+// wildcardHostPrefix starts a domain that matches every subdomain of the rest,
+// e.g. "*.acme.example" matches "eu.acme.example" and "a.eu.acme.example" but not
+// "acme.example" itself.
+const wildcardHostPrefix = "*."
+
+// normalizeHost reduces a Host header or a registered domain to its lower-case host
+// name. Ports, the brackets around IPv6 literals and a trailing root dot are removed.
+func normalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if host == "" {
+		return ""
+	}
+	if splitHost, _, err := net.SplitHostPort(host); err == nil {
+		host = splitHost
+	} else {
+		host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	}
+	return strings.TrimSuffix(host, ".")
+}
+
+// wildcardSuffix returns the suffix a wildcard domain matches, ".acme.example" for
+// "*.acme.example", or "" when host is an exact domain.
+func wildcardSuffix(host string) string {
+	if !strings.HasPrefix(host, wildcardHostPrefix) {
+		return ""
+	}
+	return host[len(wildcardHostPrefix)-1:]
+}
+
+// validDomainPattern accepts exact hosts and wildcards whose "*" is the whole first
+// label. A wildcard needs two labels after it, so no tenant can claim a whole TLD.
+func validDomainPattern(host string) bool {
+	labels := strings.Split(host, ".")
+	for labelIndex, label := range labels {
+		if label == "" {
+			return false
+		}
+		if strings.Contains(label, "*") && (labelIndex != 0 || label != "*") {
+			return false
+		}
+	}
+	return labels[0] != "*" || len(labels) >= 3
+}
+
+// hostWildcardSuffixes lists the wildcard suffixes that could match host, longest
+// first: ".b.example" and ".example" for "a.b.example". IP literals have none.
+func hostWildcardSuffixes(host string) []string {
+	if net.ParseIP(host) != nil {
+		return nil
+	}
+	var suffixes []string
+	for index := strings.Index(host, "."); index >= 0 && index < len(host)-1; {
+		suffixes = append(suffixes, host[index:])
+		next := strings.Index(host[index+1:], ".")
+		if next < 0 {
+			break
+		}
+		index += next + 1
+	}
+	return suffixes
+}
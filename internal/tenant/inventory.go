@@ -0,0 +1,66 @@
+package tenant
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const tenantOwnerColumnTenantID = "tenant_id"
+
+// TenantInventory summarizes one tenant for operator status views. Profiles are
+// reported as present or absent only; their credentials are never loaded.
+type TenantInventory struct {
+	Tenant                 Tenant
+	DomainCount            int
+	EmailProfileConfigured bool
+	SMSProfileConfigured   bool
+}
+
+// ListTenantInventory returns every tenant, suspended ones included, ordered by display name.
+func (repo *Repository) ListTenantInventory(ctx context.Context) ([]TenantInventory, error) {
+	database := repo.db.WithContext(ctx)
+	var tenants []Tenant
+	if err := database.
+		Order(clause.OrderByColumn{Column: clause.Column{Name: tenantColumnDisplayName}}).
+		Find(&tenants).Error; err != nil {
+		return nil, fmt.Errorf("tenant inventory: %w", err)
+	}
+	domainCounts, err := countRowsByTenant(database, &TenantDomain{})
+	if err != nil {
+		return nil, fmt.Errorf("tenant inventory: domains: %w", err)
+	}
+	emailProfileCounts, err := countRowsByTenant(database, &EmailProfile{})
+	if err != nil {
+		return nil, fmt.Errorf("tenant inventory: email profiles: %w", err)
+	}
+	smsProfileCounts, err := countRowsByTenant(database, &SMSProfile{})
+	if err != nil {
+		return nil, fmt.Errorf("tenant inventory: sms profiles: %w", err)
+	}
+	inventory := make([]TenantInventory, 0, len(tenants))
+	for _, tenantRow := range tenants {
+		inventory = append(inventory, TenantInventory{
+			Tenant:                 tenantRow,
+			DomainCount:            domainCounts[tenantRow.ID],
+			EmailProfileConfigured: emailProfileCounts[tenantRow.ID] > 0,
+			SMSProfileConfigured:   smsProfileCounts[tenantRow.ID] > 0,
+		})
+	}
+	return inventory, nil
+}
+
+// countRowsByTenant plucks only the owning tenant id so secret columns are never read.
+func countRowsByTenant(database *gorm.DB, rowModel interface{}) (map[string]int, error) {
+	var tenantIDs []string
+	if err := database.Model(rowModel).Pluck(tenantOwnerColumnTenantID, &tenantIDs).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int, len(tenantIDs))
+	for _, tenantID := range tenantIDs {
+		counts[tenantID]++
+	}
+	return counts, nil
+}
@@ -20,18 +20,66 @@ type Tenant struct {
 	DisplayName  string
 	SupportEmail string
 	Status       TenantStatus `gorm:"index"`
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	// CostCurrency labels the unit costs below; Pinguin never converts between currencies.
+	CostCurrency       string
+	EmailUnitCost      float64
+	SMSSegmentUnitCost float64
+	// DailyReport* schedule the activity digest; SendAt is HH:MM in DailyReportTimezone.
+	DailyReportEnabled  bool
+	DailyReportSendAt   string
+	DailyReportTimezone string
+	// AttachmentMetadataOnly stores attachment filename, type and size but never the bytes.
+	AttachmentMetadataOnly bool
+	// MaxConcurrentRetries caps the tenant's jobs per retry cycle; zero uses the server default.
+	MaxConcurrentRetries int
+	// CallerAllowlist holds the comma-separated CIDRs allowed to call the gRPC API for
+	// the tenant; empty allows any caller.
+	CallerAllowlist string
+	// NotificationIDPrefix starts the tenant's notification ids; empty uses DefaultNotificationIDPrefix.
+	NotificationIDPrefix string
+	// WebhookURL receives signed notification state events; empty disables them.
+	WebhookURL          string
+	WebhookSecretCipher []byte
+	// SMSMaxSegments caps billable segments per SMS; zero leaves bodies unlimited.
+	SMSMaxSegments int
+	// SMSOverflowPolicy applies to oversized SMS bodies whose request names no policy;
+	// empty means SMSOverflowReject.
+	SMSOverflowPolicy string
+	// SMSTruncationSuffix ends truncated SMS bodies; empty uses the default suffix.
+	SMSTruncationSuffix string
+	// StoreRenderedContent keeps the subject, body, MIME structure and size each
+	// notification was dispatched with.
+	StoreRenderedContent bool
+	// RetentionDays is how long the tenant's finished notifications are kept; zero uses
+	// server.retentionDays.
+	RetentionDays int
+	// MaxRetryAgeSec stops retrying the tenant's notifications this long after they were
+	// due; zero uses server.maxRetryAgeSec.
+	MaxRetryAgeSec int
+	// OpenTracking adds an open tracking pixel to the tenant's HTML emails and serves
+	// /t/:token for them; tenants without it never get a pixel.
+	OpenTracking bool
+	// SenderOverridePolicy decides whether requests may set their own Reply-To and From
+	// display name: SenderOverridesAllow, SenderOverridesAllowlist, or empty to deny.
+	SenderOverridePolicy string
+	// SenderOverrideDomains holds the comma-separated Reply-To domains the allowlist
+	// policy accepts.
+	SenderOverrideDomains string
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
 }
 
 // TenantDomain links hostnames to a tenant for HTTP routing.
 type TenantDomain struct {
-	ID        uint   `gorm:"primaryKey"`
-	TenantID  string `gorm:"index"`
-	Host      string `gorm:"uniqueIndex"`
-	IsDefault bool
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID       uint   `gorm:"primaryKey"`
+	TenantID string `gorm:"index"`
+	Host     string `gorm:"uniqueIndex"`
+	// WildcardSuffix is ".acme.example" when Host is the wildcard "*.acme.example" and
+	// empty for exact hosts.
+	WildcardSuffix string `gorm:"index"`
+	IsDefault      bool
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 }
 
 // TenantAdmin links browser workspace administrator emails to tenants.
@@ -52,9 +100,17 @@ type EmailProfile struct {
 	UsernameCipher []byte
 	PasswordCipher []byte
 	FromAddress    string
-	IsDefault      bool
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
+	// ConnectionTimeoutSec and OperationTimeoutSec override server.connectionTimeoutSec
+	// and server.operationTimeoutSec for this profile's SMTP sender when positive.
+	ConnectionTimeoutSec int
+	OperationTimeoutSec  int
+	// PoolSize and PoolIdleTimeoutSec override server.smtpPoolSize and
+	// server.smtpPoolIdleTimeoutSec for this profile's SMTP sender when positive.
+	PoolSize           int
+	PoolIdleTimeoutSec int
+	IsDefault          bool
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
 }
 
 // SMSProfile stores Twilio credentials per tenant.
@@ -0,0 +1,39 @@
+package tenant
+
+import "strings"
+
+const (
+	// DefaultNotificationIDPrefix starts notification ids of tenants without their own prefix.
+	DefaultNotificationIDPrefix = "notif"
+
+	maxNotificationIDPrefixLength = 32
+)
+
+// NotificationIDPrefix returns the prefix for the tenant's new notification ids; the
+// id itself is the prefix, a hyphen, and a unique suffix.
+func (cfg RuntimeConfig) NotificationIDPrefix() string {
+	if prefix := strings.TrimSpace(cfg.Tenant.NotificationIDPrefix); prefix != "" {
+		return prefix
+	}
+	return DefaultNotificationIDPrefix
+}
+
+// validNotificationIDPrefix accepts an empty prefix, which means the default, or one
+// that keeps ids safe in URLs and log lines.
+func validNotificationIDPrefix(prefix string) bool {
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		return true
+	}
+	if len(prefix) > maxNotificationIDPrefixLength || strings.HasSuffix(prefix, "-") {
+		return false
+	}
+	for _, character := range prefix {
+		isLetter := (character >= 'a' && character <= 'z') || (character >= 'A' && character <= 'Z')
+		isDigit := character >= '0' && character <= '9'
+		if !isLetter && !isDigit && character != '_' && character != '-' {
+			return false
+		}
+	}
+	return true
+}
@@ -1,9 +1,12 @@
 package tenant
 
 import (
+	"container/list"
 	"context"
 	"errors"
 	"fmt"
+	"net/netip"
+	"slices"
 	"strings"
 	"sync"
 
@@ -16,6 +19,10 @@ type RuntimeConfig struct {
 	Tenant Tenant
 	Email  EmailCredentials
 	SMS    *SMSCredentials
+	// CallerAllowlist is Tenant.CallerAllowlist parsed once per cache load.
+	CallerAllowlist []netip.Prefix
+	// Webhook is nil unless the tenant configured a webhook.
+	Webhook *WebhookCredentials
 }
 
 // EmailCredentials exposes decrypted SMTP settings.
@@ -25,6 +32,14 @@ type EmailCredentials struct {
 	Username    string
 	Password    string
 	FromAddress string
+	// ConnectionTimeoutSec and OperationTimeoutSec are the profile's overrides; zero
+	// means the server setting applies.
+	ConnectionTimeoutSec int
+	OperationTimeoutSec  int
+	// PoolSize and PoolIdleTimeoutSec are the profile's SMTP pool overrides; zero means
+	// the server setting applies.
+	PoolSize           int
+	PoolIdleTimeoutSec int
 }
 
 // SMSCredentials exposes decrypted Twilio settings.
@@ -45,18 +60,54 @@ const (
 	tenantDomainTableName      = "tenant_domains"
 	tenantDomainColumnTenantID = "tenant_id"
 	tenantDomainColumnHost     = "host"
-	tenantAdminTableName       = "tenant_admins"
-	tenantAdminColumnTenantID  = "tenant_id"
-	tenantAdminColumnEmail     = "email"
+	// tenantDomainColumnWildcardSuffix is indexed so wildcard lookups stay cheap.
+	tenantDomainColumnWildcardSuffix = "wildcard_suffix"
+	tenantAdminTableName             = "tenant_admins"
+	tenantAdminColumnTenantID        = "tenant_id"
+	tenantAdminColumnEmail           = "email"
 )
 
-// Repository exposes tenant lookups.
+// DefaultRuntimeCacheMaxEntries bounds the decrypted runtime configs a repository keeps.
+const DefaultRuntimeCacheMaxEntries = 1000
+
+// maxCachedHosts bounds the host-to-tenant cache, which grows by one entry per concrete
+// host a wildcard domain matches. A full cache is cleared rather than grown.
+const maxCachedHosts = 10000
+
+// Repository exposes tenant lookups. Call Close when a repository is discarded so it
+// leaves the bootstrap invalidation registry.
 type Repository struct {
 	db                *gorm.DB
 	keeper            *SecretKeeper
 	cacheMutex        sync.RWMutex
-	runtimeCache      map[string]RuntimeConfig
+	runtimeCache      map[string]*list.Element
+	runtimeOrder      *list.List
+	maxRuntimeEntries int
 	domainTenantCache map[string]string
+	cacheHits         uint64
+	cacheMisses       uint64
+	cacheEvictions    uint64
+	domainHits        uint64
+	domainMisses      uint64
+	closed            bool
+}
+
+// RepositoryCacheStats reports the size and effectiveness of a repository's caches.
+type RepositoryCacheStats struct {
+	RuntimeEntries    int     `json:"runtime_entries"`
+	MaxRuntimeEntries int     `json:"max_runtime_entries"`
+	DomainEntries     int     `json:"domain_entries"`
+	DomainHits        uint64  `json:"domain_hits"`
+	DomainMisses      uint64  `json:"domain_misses"`
+	Hits              uint64  `json:"hits"`
+	Misses            uint64  `json:"misses"`
+	Evictions         uint64  `json:"evictions"`
+	HitRate           float64 `json:"hit_rate"`
+}
+
+type runtimeCacheEntry struct {
+	tenantID string
+	cfg      RuntimeConfig
 }
 
 var repositoryRegistry = struct {
@@ -71,7 +122,9 @@ func NewRepository(db *gorm.DB, keeper *SecretKeeper) *Repository {
 	repo := &Repository{
 		db:                db,
 		keeper:            keeper,
-		runtimeCache:      make(map[string]RuntimeConfig),
+		runtimeCache:      make(map[string]*list.Element),
+		runtimeOrder:      list.New(),
+		maxRuntimeEntries: DefaultRuntimeCacheMaxEntries,
 		domainTenantCache: make(map[string]string),
 	}
 	repositoryRegistry.Lock()
@@ -80,6 +133,66 @@ func NewRepository(db *gorm.DB, keeper *SecretKeeper) *Repository {
 	return repo
 }
 
+// WithRuntimeCacheLimit caps the runtime cache, evicting the least recently used
+// tenant once full. A non-positive limit keeps DefaultRuntimeCacheMaxEntries.
+func (repo *Repository) WithRuntimeCacheLimit(maxEntries int) *Repository {
+	if repo == nil {
+		return nil
+	}
+	if maxEntries <= 0 {
+		maxEntries = DefaultRuntimeCacheMaxEntries
+	}
+	repo.cacheMutex.Lock()
+	repo.maxRuntimeEntries = maxEntries
+	repo.evictRuntimeEntriesLocked()
+	repo.cacheMutex.Unlock()
+	return repo
+}
+
+// Close removes the repository from the invalidation registry and drops its caches.
+// Lookups keep working after Close but are no longer cached. Close is idempotent.
+func (repo *Repository) Close() {
+	if repo == nil {
+		return
+	}
+	repositoryRegistry.Lock()
+	delete(repositoryRegistry.repos, repo)
+	repositoryRegistry.Unlock()
+	repo.cacheMutex.Lock()
+	repo.closed = true
+	repo.resetCachesLocked()
+	repo.cacheMutex.Unlock()
+}
+
+// FlushCaches drops the cached runtime configs and host lookups, so the next lookup of
+// every tenant reads the database. The hit and miss counters are kept.
+func (repo *Repository) FlushCaches() {
+	if repo == nil {
+		return
+	}
+	repo.clearCaches()
+}
+
+// CacheStats reports cache sizes, hit and miss counts, and the runtime cache hit rate.
+func (repo *Repository) CacheStats() RepositoryCacheStats {
+	repo.cacheMutex.RLock()
+	defer repo.cacheMutex.RUnlock()
+	stats := RepositoryCacheStats{
+		RuntimeEntries:    len(repo.runtimeCache),
+		MaxRuntimeEntries: repo.maxRuntimeEntries,
+		DomainEntries:     len(repo.domainTenantCache),
+		DomainHits:        repo.domainHits,
+		DomainMisses:      repo.domainMisses,
+		Hits:              repo.cacheHits,
+		Misses:            repo.cacheMisses,
+		Evictions:         repo.cacheEvictions,
+	}
+	if lookups := repo.cacheHits + repo.cacheMisses; lookups > 0 {
+		stats.HitRate = float64(repo.cacheHits) / float64(lookups)
+	}
+	return stats
+}
+
 // ResolveByHost returns the tenant associated with the provided host.
 func (repo *Repository) ResolveByHost(ctx context.Context, host string) (RuntimeConfig, error) {
 	normalized := normalizeHost(host)
@@ -89,8 +202,8 @@ func (repo *Repository) ResolveByHost(ctx context.Context, host string) (Runtime
 	if cachedTenantID, ok := repo.cachedTenantID(normalized); ok {
 		return repo.runtimeConfig(ctx, cachedTenantID)
 	}
-	var domain TenantDomain
-	if err := repo.db.WithContext(ctx).Where(&TenantDomain{Host: normalized}).First(&domain).Error; err != nil {
+	domain, err := repo.lookupDomain(ctx, normalized)
+	if err != nil {
 		return RuntimeConfig{}, fmt.Errorf("tenant resolve: domain %s: %w", normalized, err)
 	}
 	runtimeCfg, err := repo.runtimeConfig(ctx, domain.TenantID)
@@ -101,6 +214,42 @@ func (repo *Repository) ResolveByHost(ctx context.Context, host string) (Runtime
 	return runtimeCfg, nil
 }
 
+// lookupDomain finds the registration for a concrete host: the exact domain when one is
+// registered, otherwise the wildcard with the longest matching suffix.
+func (repo *Repository) lookupDomain(ctx context.Context, host string) (TenantDomain, error) {
+	var exact []TenantDomain
+	if err := repo.db.WithContext(ctx).Where(&TenantDomain{Host: host}).Limit(1).Find(&exact).Error; err != nil {
+		return TenantDomain{}, err
+	}
+	if len(exact) > 0 {
+		return exact[0], nil
+	}
+	suffixes := hostWildcardSuffixes(host)
+	if len(suffixes) == 0 {
+		return TenantDomain{}, gorm.ErrRecordNotFound
+	}
+	suffixValues := make([]interface{}, len(suffixes))
+	for index, suffix := range suffixes {
+		suffixValues[index] = suffix
+	}
+	var wildcards []TenantDomain
+	if err := repo.db.WithContext(ctx).
+		Where(clause.IN{Column: clause.Column{Name: tenantDomainColumnWildcardSuffix}, Values: suffixValues}).
+		Find(&wildcards).Error; err != nil {
+		return TenantDomain{}, err
+	}
+	if len(wildcards) == 0 {
+		return TenantDomain{}, gorm.ErrRecordNotFound
+	}
+	longest := wildcards[0]
+	for _, wildcard := range wildcards[1:] {
+		if len(wildcard.WildcardSuffix) > len(longest.WildcardSuffix) {
+			longest = wildcard
+		}
+	}
+	return longest, nil
+}
+
 // ResolveByID fetches tenant runtime config by id.
 func (repo *Repository) ResolveByID(ctx context.Context, tenantID string) (RuntimeConfig, error) {
 	normalized := strings.TrimSpace(tenantID)
@@ -160,6 +309,22 @@ func (repo *Repository) IsActiveTenantAdmin(ctx context.Context, email string) (
 	return matchingTenants > 0, nil
 }
 
+// ListTenantAdminEmails returns the normalized admin emails configured for a tenant.
+func (repo *Repository) ListTenantAdminEmails(ctx context.Context, tenantID string) ([]string, error) {
+	var admins []TenantAdmin
+	if err := repo.db.WithContext(ctx).
+		Where(&TenantAdmin{TenantID: strings.TrimSpace(tenantID)}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: tenantAdminColumnEmail}}).
+		Find(&admins).Error; err != nil {
+		return nil, fmt.Errorf("tenant admin list: %w", err)
+	}
+	emails := make([]string, 0, len(admins))
+	for _, admin := range admins {
+		emails = append(emails, admin.Email)
+	}
+	return emails, nil
+}
+
 func (repo *Repository) runtimeConfig(ctx context.Context, tenantID string) (RuntimeConfig, error) {
 	if cachedCfg, ok := repo.cachedRuntimeConfig(tenantID); ok {
 		return cachedCfg, nil
@@ -212,27 +377,48 @@ func (repo *Repository) loadRuntimeConfig(ctx context.Context, tenantID string)
 	if err != nil {
 		return RuntimeConfig{}, err
 	}
+	callerAllowlist, err := ParseCallerAllowlist(splitCallerAllowlist(tenantModel.CallerAllowlist))
+	if err != nil {
+		return RuntimeConfig{}, fmt.Errorf("tenant runtime: %w", err)
+	}
+	var webhook *WebhookCredentials
+	if tenantModel.WebhookURL != "" {
+		webhookSecret, err := repo.keeper.Decrypt(tenantModel.WebhookSecretCipher)
+		if err != nil {
+			return RuntimeConfig{}, err
+		}
+		webhook = &WebhookCredentials{URL: tenantModel.WebhookURL, Secret: webhookSecret}
+	}
 	return RuntimeConfig{
 		Tenant: tenantModel,
 		Email: EmailCredentials{
-			Host:        emailProfile.Host,
-			Port:        emailProfile.Port,
-			Username:    username,
-			Password:    password,
-			FromAddress: emailProfile.FromAddress,
+			Host:                 emailProfile.Host,
+			Port:                 emailProfile.Port,
+			Username:             username,
+			Password:             password,
+			FromAddress:          emailProfile.FromAddress,
+			ConnectionTimeoutSec: emailProfile.ConnectionTimeoutSec,
+			OperationTimeoutSec:  emailProfile.OperationTimeoutSec,
+			PoolSize:             emailProfile.PoolSize,
+			PoolIdleTimeoutSec:   emailProfile.PoolIdleTimeoutSec,
 		},
-		SMS: smsPtr,
+		SMS:             smsPtr,
+		CallerAllowlist: callerAllowlist,
+		Webhook:         webhook,
 	}, nil
 }
 
 func (repo *Repository) cachedRuntimeConfig(tenantID string) (RuntimeConfig, bool) {
-	repo.cacheMutex.RLock()
-	cachedCfg, ok := repo.runtimeCache[tenantID]
-	repo.cacheMutex.RUnlock()
+	repo.cacheMutex.Lock()
+	defer repo.cacheMutex.Unlock()
+	element, ok := repo.runtimeCache[tenantID]
 	if !ok {
+		repo.cacheMisses++
 		return RuntimeConfig{}, false
 	}
-	return cloneRuntimeConfig(cachedCfg), true
+	repo.cacheHits++
+	repo.runtimeOrder.MoveToFront(element)
+	return cloneRuntimeConfig(element.Value.(*runtimeCacheEntry).cfg), true
 }
 
 func (repo *Repository) cacheRuntimeConfig(tenantID string, cfg RuntimeConfig) {
@@ -240,21 +426,49 @@ func (repo *Repository) cacheRuntimeConfig(tenantID string, cfg RuntimeConfig) {
 		return
 	}
 	repo.cacheMutex.Lock()
-	repo.runtimeCache[tenantID] = cfg
-	repo.cacheMutex.Unlock()
+	defer repo.cacheMutex.Unlock()
+	if repo.closed {
+		return
+	}
+	if element, ok := repo.runtimeCache[tenantID]; ok {
+		element.Value.(*runtimeCacheEntry).cfg = cfg
+		repo.runtimeOrder.MoveToFront(element)
+		return
+	}
+	repo.runtimeCache[tenantID] = repo.runtimeOrder.PushFront(&runtimeCacheEntry{tenantID: tenantID, cfg: cfg})
+	repo.evictRuntimeEntriesLocked()
+}
+
+func (repo *Repository) evictRuntimeEntriesLocked() {
+	for len(repo.runtimeCache) > repo.maxRuntimeEntries {
+		oldest := repo.runtimeOrder.Back()
+		repo.runtimeOrder.Remove(oldest)
+		delete(repo.runtimeCache, oldest.Value.(*runtimeCacheEntry).tenantID)
+		repo.cacheEvictions++
+	}
 }
 
 func (repo *Repository) clearCaches() {
 	repo.cacheMutex.Lock()
-	repo.runtimeCache = make(map[string]RuntimeConfig)
-	repo.domainTenantCache = make(map[string]string)
+	repo.resetCachesLocked()
 	repo.cacheMutex.Unlock()
 }
 
+func (repo *Repository) resetCachesLocked() {
+	repo.runtimeCache = make(map[string]*list.Element)
+	repo.runtimeOrder.Init()
+	repo.domainTenantCache = make(map[string]string)
+}
+
 func (repo *Repository) cachedTenantID(host string) (string, bool) {
-	repo.cacheMutex.RLock()
+	repo.cacheMutex.Lock()
+	defer repo.cacheMutex.Unlock()
 	tenantID, ok := repo.domainTenantCache[host]
-	repo.cacheMutex.RUnlock()
+	if ok {
+		repo.domainHits++
+	} else {
+		repo.domainMisses++
+	}
 	return tenantID, ok
 }
 
@@ -263,7 +477,12 @@ func (repo *Repository) cacheTenantID(host string, tenantID string) {
 		return
 	}
 	repo.cacheMutex.Lock()
-	repo.domainTenantCache[host] = tenantID
+	if !repo.closed {
+		if len(repo.domainTenantCache) >= maxCachedHosts {
+			repo.domainTenantCache = make(map[string]string)
+		}
+		repo.domainTenantCache[host] = tenantID
+	}
 	repo.cacheMutex.Unlock()
 }
 
@@ -273,17 +492,34 @@ func cloneRuntimeConfig(cfg RuntimeConfig) RuntimeConfig {
 		smsCopy := *cfg.SMS
 		clonedCfg.SMS = &smsCopy
 	}
+	clonedCfg.CallerAllowlist = slices.Clone(cfg.CallerAllowlist)
+	if cfg.Webhook != nil {
+		webhookCopy := *cfg.Webhook
+		clonedCfg.Webhook = &webhookCopy
+	}
 	return clonedCfg
 }
 
+// invalidateRegisteredRepositories clears every open repository's caches. The registry
+// lock is released before clearing so a concurrent Close never waits on the walk.
 func invalidateRegisteredRepositories() {
 	repositoryRegistry.Lock()
-	defer repositoryRegistry.Unlock()
+	repos := make([]*Repository, 0, len(repositoryRegistry.repos))
 	for repo := range repositoryRegistry.repos {
+		repos = append(repos, repo)
+	}
+	repositoryRegistry.Unlock()
+	for _, repo := range repos {
 		repo.clearCaches()
 	}
 }
 
+func registeredRepositoryCount() int {
+	repositoryRegistry.Lock()
+	defer repositoryRegistry.Unlock()
+	return len(repositoryRegistry.repos)
+}
+
 func activeTenantDomainJoinClause() clause.From {
 	return clause.From{
 		Joins: []clause.Join{
@@ -317,15 +553,3 @@ func activeTenantAdminJoinClause() clause.From {
 		},
 	}
 }
-
-func normalizeHost(host string) string {
-	host = strings.ToLower(strings.TrimSpace(host))
-	if host == "" {
-		return ""
-	}
-	if strings.Contains(host, ":") {
-		parts := strings.Split(host, ":")
-		return parts[0]
-	}
-	return host
-}
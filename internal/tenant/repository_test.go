@@ -75,6 +75,47 @@ func TestRepositoryResolveByHost(t *testing.T) {
 	}
 }
 
+func TestRepositoryResolveByHostMatchesWildcardsPortsAndIPLiterals(t *testing.T) {
+	dbInstance := newTestDatabase(t)
+	keeper := newTestSecretKeeper(t)
+	cfg := sampleBootstrapConfig()
+	cfg.Tenants[0].Domains = append(cfg.Tenants[0].Domains, "*.alpha.example")
+	cfg.Tenants = append(cfg.Tenants, bootstrapTenantSpec("tenant-two", []string{"*.eu.alpha.example", "[::1]", "eu.alpha.example"}))
+	if err := Bootstrap(context.Background(), dbInstance, keeper, cfg); err != nil {
+		t.Fatalf("bootstrap error: %v", err)
+	}
+
+	repo := NewRepository(dbInstance, keeper)
+	testCases := map[string]string{
+		"PORTAL.Alpha.Example:8443": "tenant-one",
+		"alpha.example":             "tenant-one",
+		"shop.alpha.example":        "tenant-one",
+		"eu.alpha.example":          "tenant-two",
+		"shop.eu.alpha.example":     "tenant-two",
+		"a.b.eu.alpha.example":      "tenant-two",
+		"portal.alpha.example.":     "tenant-one",
+		"[::1]:8080":                "tenant-two",
+		"[::1]":                     "tenant-two",
+	}
+	for host, expectedTenantID := range testCases {
+		runtimeCfg, err := repo.ResolveByHost(context.Background(), host)
+		if err != nil {
+			t.Fatalf("resolve %q: %v", host, err)
+		}
+		if runtimeCfg.Tenant.ID != expectedTenantID {
+			t.Fatalf("resolve %q: expected %s, got %s", host, expectedTenantID, runtimeCfg.Tenant.ID)
+		}
+	}
+	for _, host := range []string{"alpha.example.org", "example", "[::2]:8080"} {
+		if _, err := repo.ResolveByHost(context.Background(), host); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("resolve %q: expected not found, got %v", host, err)
+		}
+	}
+	if tenantID, cached := repo.cachedTenantID("shop.eu.alpha.example"); !cached || tenantID != "tenant-two" {
+		t.Fatalf("expected the concrete wildcard host to be cached")
+	}
+}
+
 func TestRepositoryResolveByHostValidationAndLookupErrors(t *testing.T) {
 	t.Helper()
 	dbInstance := newTestDatabase(t)
@@ -151,6 +192,128 @@ func TestRepositoryResolveByIDCachesRuntimeConfig(t *testing.T) {
 	}
 }
 
+func TestRepositoryFlushCachesForcesDatabaseReads(t *testing.T) {
+	counter := newQueryCounter()
+	dbInstance := newTestDatabaseWithLogger(t, counter)
+	keeper := newTestSecretKeeper(t)
+	configPath := writeBootstrapFile(t, sampleBootstrapConfig())
+	if err := BootstrapFromFile(context.Background(), dbInstance, keeper, configPath); err != nil {
+		t.Fatalf("bootstrap error: %v", err)
+	}
+	repo := NewRepository(dbInstance, keeper)
+	defer repo.Close()
+
+	resolve := func() int {
+		t.Helper()
+		counter.Reset()
+		if _, err := repo.ResolveByHost(context.Background(), "portal.alpha.example"); err != nil {
+			t.Fatalf("resolve host error: %v", err)
+		}
+		return counter.Count()
+	}
+	if queries := resolve(); queries == 0 {
+		t.Fatalf("expected database queries during first resolve")
+	}
+	if queries := resolve(); queries != 0 {
+		t.Fatalf("expected cached resolve without database queries, got %d", queries)
+	}
+	repo.FlushCaches()
+	if flushed := repo.CacheStats(); flushed.RuntimeEntries != 0 || flushed.DomainEntries != 0 {
+		t.Fatalf("expected empty caches after a flush, got %+v", flushed)
+	}
+	if queries := resolve(); queries == 0 {
+		t.Fatalf("expected the resolve after a flush to read the database")
+	}
+	if queries := resolve(); queries != 0 {
+		t.Fatalf("expected the flushed entry to be cached again, got %d queries", queries)
+	}
+
+	stats := repo.CacheStats()
+	if stats.DomainHits != 2 || stats.DomainMisses != 2 || stats.Hits != 2 || stats.Misses != 2 {
+		t.Fatalf("expected counters to survive the flush, got %+v", stats)
+	}
+}
+
+func TestRepositoryCloseKeepsRegistryBounded(t *testing.T) {
+	dbInstance := newTestDatabase(t)
+	keeper := newTestSecretKeeper(t)
+	baseline := registeredRepositoryCount()
+	for iteration := 0; iteration < 500; iteration++ {
+		repo := NewRepository(dbInstance, keeper)
+		repo.Close()
+		repo.Close()
+	}
+	if count := registeredRepositoryCount(); count != baseline {
+		t.Fatalf("expected the registry to stay at %d repositories, got %d", baseline, count)
+	}
+}
+
+func TestRepositoryCloseToleratesConcurrentInvalidation(t *testing.T) {
+	dbInstance := newTestDatabase(t)
+	keeper := newTestSecretKeeper(t)
+	baseline := registeredRepositoryCount()
+	var waitGroup sync.WaitGroup
+	for worker := 0; worker < 8; worker++ {
+		waitGroup.Add(2)
+		go func() {
+			defer waitGroup.Done()
+			for iteration := 0; iteration < 50; iteration++ {
+				NewRepository(dbInstance, keeper).Close()
+			}
+		}()
+		go func() {
+			defer waitGroup.Done()
+			for iteration := 0; iteration < 50; iteration++ {
+				invalidateRegisteredRepositories()
+			}
+		}()
+	}
+	waitGroup.Wait()
+	if count := registeredRepositoryCount(); count != baseline {
+		t.Fatalf("expected the registry to stay at %d repositories, got %d", baseline, count)
+	}
+}
+
+func TestRepositoryRuntimeCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	dbInstance := newTestDatabase(t)
+	keeper := newTestSecretKeeper(t)
+	cfg := BootstrapConfig{Tenants: []BootstrapTenant{
+		bootstrapTenantSpec("tenant-a", []string{"a.example"}),
+		bootstrapTenantSpec("tenant-b", []string{"b.example"}),
+		bootstrapTenantSpec("tenant-c", []string{"c.example"}),
+	}}
+	if err := Bootstrap(context.Background(), dbInstance, keeper, cfg); err != nil {
+		t.Fatalf("bootstrap tenants: %v", err)
+	}
+	repo := NewRepository(dbInstance, keeper).WithRuntimeCacheLimit(2)
+	defer repo.Close()
+
+	for _, tenantID := range []string{"tenant-a", "tenant-b", "tenant-a", "tenant-c", "tenant-a", "tenant-b"} {
+		if _, err := repo.ResolveByID(context.Background(), tenantID); err != nil {
+			t.Fatalf("resolve %s: %v", tenantID, err)
+		}
+	}
+	stats := repo.CacheStats()
+	if stats.RuntimeEntries != 2 || stats.MaxRuntimeEntries != 2 {
+		t.Fatalf("expected a bounded runtime cache, got %+v", stats)
+	}
+	// tenant-b was evicted when tenant-c arrived because tenant-a had been used more recently.
+	if stats.Hits != 2 || stats.Misses != 4 || stats.Evictions != 2 {
+		t.Fatalf("unexpected cache counters %+v", stats)
+	}
+	if stats.HitRate < 0.33 || stats.HitRate > 0.34 {
+		t.Fatalf("unexpected hit rate %v", stats.HitRate)
+	}
+
+	repo.Close()
+	if _, err := repo.ResolveByID(context.Background(), "tenant-c"); err != nil {
+		t.Fatalf("resolve after close: %v", err)
+	}
+	if closedStats := repo.CacheStats(); closedStats.RuntimeEntries != 0 || closedStats.DomainEntries != 0 {
+		t.Fatalf("expected a closed repository to stop caching, got %+v", closedStats)
+	}
+}
+
 func TestRepositoryResolveByIDRejectsEmpty(t *testing.T) {
 	t.Helper()
 	counter := newQueryCounter()
@@ -269,6 +432,34 @@ func TestRepositoryListActiveTenants(t *testing.T) {
 	}
 }
 
+func TestRepositoryListTenantInventoryIncludesSuspendedTenants(t *testing.T) {
+	dbInstance := newTestDatabase(t)
+	keeper := newTestSecretKeeper(t)
+	cfg := sampleBootstrapConfig()
+	suspended := bootstrapTenantSpec("tenant-two", []string{"beta.example"})
+	suspended.DisplayName = "Beta"
+	suspended.Enabled = ptrBool(false)
+	cfg.Tenants = append(cfg.Tenants, suspended)
+	if err := Bootstrap(context.Background(), dbInstance, keeper, cfg); err != nil {
+		t.Fatalf("bootstrap tenants: %v", err)
+	}
+
+	inventory, err := NewRepository(dbInstance, keeper).ListTenantInventory(context.Background())
+	if err != nil {
+		t.Fatalf("list tenant inventory: %v", err)
+	}
+	if len(inventory) != 2 {
+		t.Fatalf("expected both tenants, got %+v", inventory)
+	}
+	alpha, beta := inventory[0], inventory[1]
+	if alpha.Tenant.ID != "tenant-one" || alpha.DomainCount != 2 || !alpha.EmailProfileConfigured || !alpha.SMSProfileConfigured {
+		t.Fatalf("unexpected active tenant inventory %+v", alpha)
+	}
+	if beta.Tenant.ID != "tenant-two" || beta.Tenant.Status != TenantStatusSuspended || beta.DomainCount != 1 || beta.SMSProfileConfigured {
+		t.Fatalf("unexpected suspended tenant inventory %+v", beta)
+	}
+}
+
 func TestRepositoryListActiveTenantsOrdersByDisplayName(t *testing.T) {
 	t.Helper()
 	dbInstance := newTestDatabase(t)
@@ -618,6 +809,11 @@ func TestNormalizeHost(t *testing.T) {
 		"example.com:8080":    "example.com",
 		" ":                   "",
 		"sub.example.com:443": "sub.example.com",
+		"example.com.":        "example.com",
+		"[::1]:8080":          "::1",
+		"[2001:DB8::1]":       "2001:db8::1",
+		"2001:db8::1":         "2001:db8::1",
+		"127.0.0.1:8080":      "127.0.0.1",
 	}
 	for input, expected := range testCases {
 		if actual := normalizeHost(input); actual != expected {
@@ -5,18 +5,53 @@ import (
 	"crypto/cipher"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 )
 
-// SecretKeeper encrypts and decrypts sensitive strings at rest.
+// Cipher seals and opens the secrets a SecretKeeper stores. The default is AES-256-GCM;
+// a PKCS#11/HSM-backed implementation can replace it with WithCipher.
+type Cipher interface {
+	Seal(plaintext []byte) ([]byte, error)
+	Open(ciphertext []byte) ([]byte, error)
+}
+
+// SecretKeeper encrypts and decrypts sensitive strings at rest. Without WithCipher it
+// uses AES-GCM with key, drawing nonces from random.
 type SecretKeeper struct {
 	key    []byte
 	random io.Reader
+	cipher Cipher
+}
+
+// SecretKeeperOption customizes a SecretKeeper at construction.
+type SecretKeeperOption func(*SecretKeeper)
+
+// WithCipher replaces the AES-GCM cipher. The raw key passed to NewSecretKeeper is then
+// not used and may be empty.
+func WithCipher(secretCipher Cipher) SecretKeeperOption {
+	return func(keeper *SecretKeeper) {
+		keeper.cipher = secretCipher
+	}
+}
+
+// WithRandomSource draws the AES-GCM nonces from random instead of crypto/rand.
+func WithRandomSource(random io.Reader) SecretKeeperOption {
+	return func(keeper *SecretKeeper) {
+		keeper.random = random
+	}
 }
 
 // NewSecretKeeper builds a keeper from a raw key. The key must be 32 bytes.
-func NewSecretKeeper(rawKey string) (*SecretKeeper, error) {
+func NewSecretKeeper(rawKey string, options ...SecretKeeperOption) (*SecretKeeper, error) {
+	keeper := &SecretKeeper{random: rand.Reader}
+	for _, option := range options {
+		option(keeper)
+	}
+	if keeper.cipher != nil {
+		return keeper, nil
+	}
 	keyBytes, err := hex.DecodeString(rawKey)
 	if err != nil {
 		return nil, fmt.Errorf("tenant: invalid encryption key: %w", err)
@@ -24,40 +59,79 @@ func NewSecretKeeper(rawKey string) (*SecretKeeper, error) {
 	if len(keyBytes) != 32 {
 		return nil, fmt.Errorf("tenant: encryption key must decode to 32 bytes")
 	}
-	return &SecretKeeper{key: keyBytes, random: rand.Reader}, nil
+	keeper.key = keyBytes
+	return keeper, nil
 }
 
-// Encrypt converts plaintext into ciphertext bytes using AES-GCM.
+// Encrypt converts plaintext into ciphertext bytes.
 func (keeper *SecretKeeper) Encrypt(plaintext string) ([]byte, error) {
-	block, err := aes.NewCipher(keeper.key)
+	return keeper.activeCipher().Seal([]byte(plaintext))
+}
+
+// Decrypt reverses Encrypt.
+func (keeper *SecretKeeper) Decrypt(ciphertext []byte) (string, error) {
+	plaintext, err := keeper.activeCipher().Open(ciphertext)
 	if err != nil {
-		return nil, fmt.Errorf("tenant: init cipher: %w", err)
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func (keeper *SecretKeeper) activeCipher() Cipher {
+	if keeper.cipher != nil {
+		return keeper.cipher
+	}
+	return aesGCMCipher{key: keeper.key, random: keeper.random}
+}
+
+// NewAESGCMCipher returns the default cipher for a 32-byte key, drawing nonces from
+// random. Its ciphertexts are the nonce followed by the GCM ciphertext and tag.
+func NewAESGCMCipher(key []byte, random io.Reader) (Cipher, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("tenant: encryption key must decode to 32 bytes")
+	}
+	return aesGCMCipher{key: key, random: random}, nil
+}
+
+// aesGCMCipher stores the nonce followed by the GCM ciphertext and tag.
+type aesGCMCipher struct {
+	key    []byte
+	random io.Reader
+}
+
+func (gcmCipher aesGCMCipher) Seal(plaintext []byte) ([]byte, error) {
+	gcm, err := gcmCipher.aead()
+	if err != nil {
+		return nil, err
 	}
-	gcm, _ := cipher.NewGCM(block)
 	nonce := make([]byte, gcm.NonceSize())
-	if _, err := io.ReadFull(keeper.random, nonce); err != nil {
+	if _, err := io.ReadFull(gcmCipher.random, nonce); err != nil {
 		return nil, fmt.Errorf("tenant: nonce: %w", err)
 	}
-	ciphertext := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
-	return ciphertext, nil
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
 }
 
-// Decrypt reverses Encrypt.
-func (keeper *SecretKeeper) Decrypt(ciphertext []byte) (string, error) {
-	block, err := aes.NewCipher(keeper.key)
+func (gcmCipher aesGCMCipher) Open(ciphertext []byte) ([]byte, error) {
+	gcm, err := gcmCipher.aead()
 	if err != nil {
-		return "", fmt.Errorf("tenant: init cipher: %w", err)
+		return nil, err
 	}
-	gcm, _ := cipher.NewGCM(block)
 	nonceSize := gcm.NonceSize()
 	if len(ciphertext) < nonceSize {
-		return "", fmt.Errorf("tenant: ciphertext too short")
+		return nil, errors.New("tenant: ciphertext too short")
 	}
-	nonce := ciphertext[:nonceSize]
-	payload := ciphertext[nonceSize:]
-	plaintext, err := gcm.Open(nil, nonce, payload, nil)
+	plaintext, err := gcm.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], nil)
 	if err != nil {
-		return "", fmt.Errorf("tenant: decrypt: %w", err)
+		return nil, fmt.Errorf("tenant: decrypt: %w", err)
 	}
-	return string(plaintext), nil
+	return plaintext, nil
+}
+
+func (gcmCipher aesGCMCipher) aead() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(gcmCipher.key)
+	if err != nil {
+		return nil, fmt.Errorf("tenant: init cipher: %w", err)
+	}
+	gcm, _ := cipher.NewGCM(block)
+	return gcm, nil
 }
@@ -1,6 +1,9 @@
 package tenant
 
 import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"io"
 	"strings"
@@ -78,6 +81,97 @@ func TestSecretKeeperReportsNonceSourceFailure(t *testing.T) {
 	}
 }
 
+// goldenSecretCiphertext is "smtp-password" sealed with the test key and the nonce
+// "pinguin-nonc" by the original SecretKeeper format: nonce, then GCM ciphertext and tag.
+const goldenSecretCiphertext = "70696e6775696e2d6e6f6e63c466eed9663020fbd7602e4a7176e82af8b8c331067ae0cd83ec0e3d1b"
+
+// checkCipher is the contract every Cipher must meet.
+func checkCipher(t *testing.T, secretCipher Cipher) {
+	t.Helper()
+	for _, plaintext := range []string{"", "super-secret-value", strings.Repeat("x", 4096)} {
+		sealed, err := secretCipher.Seal([]byte(plaintext))
+		if err != nil {
+			t.Fatalf("seal: %v", err)
+		}
+		if plaintext != "" && bytes.Contains(sealed, []byte(plaintext)) {
+			t.Fatalf("expected ciphertext not to contain the plaintext")
+		}
+		opened, err := secretCipher.Open(sealed)
+		if err != nil || string(opened) != plaintext {
+			t.Fatalf("expected %q to round-trip, got %q err=%v", plaintext, opened, err)
+		}
+	}
+	first, _ := secretCipher.Seal([]byte("payload"))
+	second, _ := secretCipher.Seal([]byte("payload"))
+	if bytes.Equal(first, second) {
+		t.Fatalf("expected repeated seals to differ")
+	}
+	first[len(first)-1] ^= 0xff
+	if _, err := secretCipher.Open(first); err == nil {
+		t.Fatalf("expected tampered ciphertext to be rejected")
+	}
+	if _, err := secretCipher.Open(nil); err == nil {
+		t.Fatalf("expected empty ciphertext to be rejected")
+	}
+}
+
+func TestAESGCMCipherConformance(t *testing.T) {
+	key, _ := hex.DecodeString(strings.Repeat("a", 64))
+	secretCipher, err := NewAESGCMCipher(key, rand.Reader)
+	if err != nil {
+		t.Fatalf("cipher: %v", err)
+	}
+	checkCipher(t, secretCipher)
+	if _, err := NewAESGCMCipher(key[:16], rand.Reader); err == nil {
+		t.Fatalf("expected a short key to be rejected")
+	}
+}
+
+func TestSecretKeeperDecryptsExistingCiphertexts(t *testing.T) {
+	golden, _ := hex.DecodeString(goldenSecretCiphertext)
+	plaintext, err := newTestSecretKeeper(t).Decrypt(golden)
+	if err != nil || plaintext != "smtp-password" {
+		t.Fatalf("expected the stored secret to decrypt, got %q err=%v", plaintext, err)
+	}
+
+	keeper, err := NewSecretKeeper(strings.Repeat("a", 64), WithRandomSource(strings.NewReader("pinguin-nonc")))
+	if err != nil {
+		t.Fatalf("secret keeper: %v", err)
+	}
+	sealed, err := keeper.Encrypt("smtp-password")
+	if err != nil || hex.EncodeToString(sealed) != goldenSecretCiphertext {
+		t.Fatalf("expected the default cipher to keep its byte format, got %x err=%v", sealed, err)
+	}
+}
+
+func TestSecretKeeperUsesInjectedCipher(t *testing.T) {
+	injected := &recordingCipher{}
+	keeper, err := NewSecretKeeper("", WithCipher(injected))
+	if err != nil {
+		t.Fatalf("secret keeper: %v", err)
+	}
+	sealed, err := keeper.Encrypt("payload")
+	if err != nil || string(sealed) != "sealed:payload" {
+		t.Fatalf("expected the injected cipher to seal, got %q err=%v", sealed, err)
+	}
+	if opened, err := keeper.Decrypt(sealed); err != nil || opened != "payload" || injected.opens != 1 {
+		t.Fatalf("expected the injected cipher to open, got %q opens=%d err=%v", opened, injected.opens, err)
+	}
+}
+
+type recordingCipher struct {
+	opens int
+}
+
+func (recorder *recordingCipher) Seal(plaintext []byte) ([]byte, error) {
+	return append([]byte("sealed:"), plaintext...), nil
+}
+
+func (recorder *recordingCipher) Open(ciphertext []byte) ([]byte, error) {
+	recorder.opens++
+	return bytes.TrimPrefix(ciphertext, []byte("sealed:")), nil
+}
+
 type failingReader struct {
 	err error
 }
@@ -0,0 +1,34 @@
+package tenant
+
+import (
+	"context"
+	"fmt"
+)
+
+// TenantResolutionFailure names an active tenant whose runtime config does not load,
+// for example because a credential no longer decrypts with the master key.
+type TenantResolutionFailure struct {
+	TenantID string
+	Err      error
+}
+
+// VerifyActiveTenants resolves every active tenant's runtime config, decrypting its
+// credentials, so corrupt rows surface at startup instead of at the first send. The
+// error covers listing the tenants only; per-tenant problems are returned as failures.
+// Tenants that resolve are left in the runtime cache.
+func (repo *Repository) VerifyActiveTenants(ctx context.Context) (int, []TenantResolutionFailure, error) {
+	if repo == nil {
+		return 0, nil, nil
+	}
+	tenants, err := repo.ListActiveTenants(ctx)
+	if err != nil {
+		return 0, nil, fmt.Errorf("tenant self-check: %w", err)
+	}
+	var failures []TenantResolutionFailure
+	for _, tenantRow := range tenants {
+		if _, resolveErr := repo.ResolveByID(ctx, tenantRow.ID); resolveErr != nil {
+			failures = append(failures, TenantResolutionFailure{TenantID: tenantRow.ID, Err: resolveErr})
+		}
+	}
+	return len(tenants), failures, nil
+}
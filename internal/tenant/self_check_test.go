@@ -0,0 +1,57 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRepositoryVerifyActiveTenantsReportsUndecryptableCredentials(t *testing.T) {
+	dbInstance := newTestDatabase(t)
+	keeper := newTestSecretKeeper(t)
+	cfg := sampleBootstrapConfig()
+	cfg.Tenants = append(cfg.Tenants, BootstrapTenant{
+		ID:           "tenant-corrupt",
+		DisplayName:  "Corrupt",
+		SupportEmail: "support@corrupt.example",
+		Domains:      []string{"corrupt.example"},
+		EmailProfile: BootstrapEmailProfile{
+			Host:        "smtp.corrupt.example",
+			Port:        587,
+			Username:    "corrupt-user",
+			Password:    "corrupt-pass",
+			FromAddress: "noreply@corrupt.example",
+		},
+	})
+	if err := BootstrapFromFile(context.Background(), dbInstance, keeper, writeBootstrapFile(t, cfg)); err != nil {
+		t.Fatalf("bootstrap error: %v", err)
+	}
+	repo := NewRepository(dbInstance, keeper)
+
+	checked, failures, err := repo.VerifyActiveTenants(context.Background())
+	if err != nil || checked != 2 || len(failures) != 0 {
+		t.Fatalf("expected both tenants to resolve, got %d checked, %+v (%v)", checked, failures, err)
+	}
+
+	if err := dbInstance.Model(&EmailProfile{}).
+		Where(&EmailProfile{TenantID: "tenant-corrupt"}).
+		Update("password_cipher", []byte("not-a-ciphertext")).Error; err != nil {
+		t.Fatalf("corrupt password: %v", err)
+	}
+	repo.clearCaches()
+	checked, failures, err = repo.VerifyActiveTenants(context.Background())
+	if err != nil || checked != 2 {
+		t.Fatalf("expected both tenants to be checked, got %d (%v)", checked, err)
+	}
+	if len(failures) != 1 || failures[0].TenantID != "tenant-corrupt" || failures[0].Err == nil {
+		t.Fatalf("expected only the corrupt tenant to fail, got %+v", failures)
+	}
+}
+
+func TestRepositoryVerifyActiveTenantsReportsStorageFailure(t *testing.T) {
+	dbInstance := newTestDatabase(t)
+	repo := NewRepository(dbInstance, newTestSecretKeeper(t))
+	closeTenantDatabase(t, dbInstance)
+	if _, _, err := repo.VerifyActiveTenants(context.Background()); err == nil {
+		t.Fatalf("expected a listing error")
+	}
+}
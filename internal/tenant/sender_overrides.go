@@ -0,0 +1,109 @@
+package tenant
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// SenderOverridesDeny refuses per-message Reply-To and From display names; it is the default.
+	SenderOverridesDeny = "deny"
+	// SenderOverridesAllow accepts any per-message Reply-To and From display name.
+	SenderOverridesAllow = "allow"
+	// SenderOverridesAllowlist accepts From display names and Reply-To addresses in the allowed domains.
+	SenderOverridesAllowlist = "allowlist"
+
+	senderOverrideDomainSeparator = ","
+)
+
+// BootstrapSenderOverrides decides whether the tenant's email requests may set their
+// own Reply-To address and From display name. The From address is never overridable.
+type BootstrapSenderOverrides struct {
+	// Policy is deny (the default), allow or allowlist.
+	Policy string `json:"policy" yaml:"policy"`
+	// AllowedDomains lists the Reply-To domains the allowlist policy accepts, matched exactly.
+	AllowedDomains []string `json:"allowedDomains,omitempty" yaml:"allowedDomains,omitempty"`
+}
+
+func (overrides *BootstrapSenderOverrides) UnmarshalYAML(value *yaml.Node) error {
+	if value == nil {
+		*overrides = BootstrapSenderOverrides{}
+		return nil
+	}
+	if value.Kind != yaml.MappingNode {
+		return fmt.Errorf("tenant bootstrap: tenants[].senderOverrides must be a mapping")
+	}
+	if unsupportedKey := firstUnsupportedBootstrapYAMLMappingKey(value, "policy", "allowedDomains"); unsupportedKey != "" {
+		return fmt.Errorf("tenant bootstrap: tenants[].senderOverrides.%s is not supported", unsupportedKey)
+	}
+	type rawBootstrapSenderOverrides BootstrapSenderOverrides
+	var decoded rawBootstrapSenderOverrides
+	if err := value.Decode(&decoded); err != nil {
+		return err
+	}
+	decoded.Policy = strings.ToLower(strings.TrimSpace(decoded.Policy))
+	*overrides = BootstrapSenderOverrides(decoded)
+	return nil
+}
+
+// senderOverridesProblems reports why a sender override policy cannot be applied: the
+// policy must be known, and allowed domains are required by, and only valid for, the
+// allowlist policy. Domains are exact host names; wildcards are refused.
+func senderOverridesProblems(overrides *BootstrapSenderOverrides) []string {
+	if overrides == nil {
+		return nil
+	}
+	var problems []string
+	allowedDomains := normalizeSenderOverrideDomains(overrides.AllowedDomains)
+	switch strings.ToLower(strings.TrimSpace(overrides.Policy)) {
+	case "", SenderOverridesDeny, SenderOverridesAllow:
+		if len(allowedDomains) > 0 {
+			problems = append(problems, "senderOverrides.allowedDomains requires senderOverrides.policy allowlist")
+		}
+	case SenderOverridesAllowlist:
+		if len(allowedDomains) == 0 {
+			problems = append(problems, "senderOverrides.policy allowlist requires senderOverrides.allowedDomains")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("senderOverrides.policy %q must be deny, allow or allowlist", overrides.Policy))
+	}
+	for _, domain := range allowedDomains {
+		if strings.Contains(domain, "*") || !validDomainPattern(domain) {
+			problems = append(problems, fmt.Sprintf("senderOverrides.allowedDomains entry %q must be a domain name such as support.acme.example", domain))
+		}
+	}
+	return problems
+}
+
+// AllowsSenderOverride reports whether the tenant lets a request set its own From
+// display name and, when replyToDomain is not empty, a Reply-To address in that domain.
+func (tenantModel Tenant) AllowsSenderOverride(replyToDomain string) bool {
+	switch tenantModel.SenderOverridePolicy {
+	case SenderOverridesAllow:
+		return true
+	case SenderOverridesAllowlist:
+		return replyToDomain == "" || slices.Contains(splitSenderOverrideDomains(tenantModel.SenderOverrideDomains), strings.ToLower(replyToDomain))
+	default:
+		return false
+	}
+}
+
+func normalizeSenderOverrideDomains(domains []string) []string {
+	normalized := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		if trimmed := strings.ToLower(strings.TrimSpace(domain)); trimmed != "" {
+			normalized = append(normalized, trimmed)
+		}
+	}
+	return normalized
+}
+
+func splitSenderOverrideDomains(stored string) []string {
+	if stored == "" {
+		return nil
+	}
+	return strings.Split(stored, senderOverrideDomainSeparator)
+}
@@ -0,0 +1,76 @@
+package tenant
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// SMSOverflowReject refuses SMS bodies longer than the tenant's segment limit.
+	SMSOverflowReject = "reject"
+	// SMSOverflowTruncate shortens SMS bodies to the tenant's segment limit.
+	SMSOverflowTruncate = "truncate"
+
+	maxSMSTruncationSuffixLength = 20
+)
+
+// BootstrapSMSLimits caps the size of the tenant's SMS bodies and decides what
+// happens to longer ones when a request does not choose an overflow policy itself.
+type BootstrapSMSLimits struct {
+	// MaxSegments caps billable segments per SMS; zero leaves bodies unlimited.
+	MaxSegments int `json:"maxSegments" yaml:"maxSegments"`
+	// OverflowPolicy is reject (the default) or truncate.
+	OverflowPolicy string `json:"overflowPolicy,omitempty" yaml:"overflowPolicy,omitempty"`
+	// TruncationSuffix ends truncated bodies; empty uses "...".
+	TruncationSuffix string `json:"truncationSuffix,omitempty" yaml:"truncationSuffix,omitempty"`
+}
+
+func (limits *BootstrapSMSLimits) UnmarshalYAML(value *yaml.Node) error {
+	if value == nil {
+		*limits = BootstrapSMSLimits{}
+		return nil
+	}
+	if value.Kind != yaml.MappingNode {
+		return fmt.Errorf("tenant bootstrap: tenants[].smsLimits must be a mapping")
+	}
+	if unsupportedKey := firstUnsupportedBootstrapYAMLMappingKey(value, "maxSegments", "overflowPolicy", "truncationSuffix"); unsupportedKey != "" {
+		return fmt.Errorf("tenant bootstrap: tenants[].smsLimits.%s is not supported", unsupportedKey)
+	}
+	type rawBootstrapSMSLimits BootstrapSMSLimits
+	var decoded rawBootstrapSMSLimits
+	if err := value.Decode(&decoded); err != nil {
+		return err
+	}
+	decoded.OverflowPolicy = strings.ToLower(strings.TrimSpace(decoded.OverflowPolicy))
+	*limits = BootstrapSMSLimits(decoded)
+	return nil
+}
+
+// smsLimitsProblems reports why SMS limits cannot be applied: the segment cap must not
+// be negative, truncation needs a cap, and the suffix must be short printable text.
+func smsLimitsProblems(limits *BootstrapSMSLimits) []string {
+	if limits == nil {
+		return nil
+	}
+	var problems []string
+	if limits.MaxSegments < 0 {
+		problems = append(problems, "smsLimits.maxSegments must not be negative")
+	}
+	switch strings.ToLower(strings.TrimSpace(limits.OverflowPolicy)) {
+	case "", SMSOverflowReject:
+	case SMSOverflowTruncate:
+		if limits.MaxSegments == 0 {
+			problems = append(problems, "smsLimits.overflowPolicy truncate requires smsLimits.maxSegments")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("smsLimits.overflowPolicy %q must be reject or truncate", limits.OverflowPolicy))
+	}
+	if utf8.RuneCountInString(limits.TruncationSuffix) > maxSMSTruncationSuffixLength || strings.IndexFunc(limits.TruncationSuffix, unicode.IsControl) >= 0 {
+		problems = append(problems, fmt.Sprintf("smsLimits.truncationSuffix must be at most %d characters without control characters", maxSMSTruncationSuffixLength))
+	}
+	return problems
+}
@@ -0,0 +1,60 @@
+package tenant
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BootstrapWebhook configures the endpoint that receives the tenant's signed
+// notification state events.
+type BootstrapWebhook struct {
+	URL    string `json:"url" yaml:"url"`
+	Secret string `json:"secret" yaml:"secret"`
+}
+
+func (webhook *BootstrapWebhook) UnmarshalYAML(value *yaml.Node) error {
+	if value == nil {
+		*webhook = BootstrapWebhook{}
+		return nil
+	}
+	if value.Kind != yaml.MappingNode {
+		return fmt.Errorf("tenant bootstrap: tenants[].webhook must be a mapping")
+	}
+	if unsupportedKey := firstUnsupportedBootstrapYAMLMappingKey(value, "url", "secret"); unsupportedKey != "" {
+		return fmt.Errorf("tenant bootstrap: tenants[].webhook.%s is not supported", unsupportedKey)
+	}
+	type rawBootstrapWebhook BootstrapWebhook
+	var decoded rawBootstrapWebhook
+	if err := value.Decode(&decoded); err != nil {
+		return err
+	}
+	decoded.URL = strings.TrimSpace(decoded.URL)
+	*webhook = BootstrapWebhook(decoded)
+	return nil
+}
+
+// WebhookCredentials exposes the decrypted webhook target.
+type WebhookCredentials struct {
+	URL    string
+	Secret string
+}
+
+// webhookProblems reports why a configured webhook cannot be used: the URL must be an
+// absolute http or https URL and the signing secret must be set.
+func webhookProblems(webhook *BootstrapWebhook) []string {
+	if webhook == nil {
+		return nil
+	}
+	var problems []string
+	parsedURL, err := url.Parse(strings.TrimSpace(webhook.URL))
+	if err != nil || (parsedURL.Scheme != "https" && parsedURL.Scheme != "http") || parsedURL.Host == "" {
+		problems = append(problems, fmt.Sprintf("webhook.url %q must be an absolute http or https URL", webhook.URL))
+	}
+	if strings.TrimSpace(webhook.Secret) == "" {
+		problems = append(problems, "webhook.secret is required to sign webhook events")
+	}
+	return problems
+}
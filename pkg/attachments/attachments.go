@@ -60,11 +60,28 @@ func splitInput(input string) (string, string) {
 	return path, ""
 }
 
+// textContentTypes covers plain-text extensions that the system MIME tables may lack.
+var textContentTypes = map[string]string{
+	".txt": "text/plain; charset=utf-8",
+	".csv": "text/csv; charset=utf-8",
+}
+
+// TypeByExtension returns the content type implied by the filename's extension, or ""
+// when the extension is missing or unknown. The system MIME tables are consulted first.
+func TypeByExtension(filename string) string {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext == "" {
+		return ""
+	}
+	if detected := mime.TypeByExtension(ext); detected != "" {
+		return detected
+	}
+	return textContentTypes[ext]
+}
+
 func inferContentType(path string, data []byte) string {
-	if ext := strings.ToLower(filepath.Ext(path)); ext != "" {
-		if detected := mime.TypeByExtension(ext); detected != "" {
-			return detected
-		}
+	if detected := TypeByExtension(path); detected != "" {
+		return detected
 	}
 	if len(data) > 0 {
 		if sniffed := http.DetectContentType(data); sniffed != "" {
@@ -1,6 +1,7 @@
 package attachments
 
 import (
+	"mime"
 	"os"
 	"path/filepath"
 	"testing"
@@ -97,3 +98,37 @@ func TestInferContentTypeFallbacks(t *testing.T) {
 		t.Fatalf("expected default content type, got %q", inferred)
 	}
 }
+
+func TestLoadInfersContentTypeFromExtension(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{input: "notes.txt", expected: "text/plain"},
+		{input: "export.csv", expected: "text/csv"},
+		{input: "payload.unknownext", expected: defaultContentType},
+		{input: "export.csv::application/vnd.ms-excel", expected: "application/vnd.ms-excel"},
+	}
+	for _, testCase := range testCases {
+		path, explicitType := splitInput(testCase.input)
+		fullPath := filepath.Join(tempDir, path)
+		if err := os.WriteFile(fullPath, []byte{0x00, 0x01, 0x02}, 0o600); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+		input := fullPath
+		if explicitType != "" {
+			input += "::" + explicitType
+		}
+		loaded, err := Load([]string{input})
+		if err != nil {
+			t.Fatalf("load %s: %v", testCase.input, err)
+		}
+		mediaType, _, parseErr := mime.ParseMediaType(loaded[0].ContentType)
+		if parseErr != nil || mediaType != testCase.expected {
+			t.Fatalf("%s: expected %s, got %q", testCase.input, testCase.expected, loaded[0].ContentType)
+		}
+	}
+}
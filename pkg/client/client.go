@@ -4,15 +4,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"iter"
 	"net"
 	"strings"
 	"time"
 
+	"github.com/tyemirov/pinguin/pkg/endpoint"
 	"github.com/tyemirov/pinguin/pkg/grpcapi"
 	"github.com/tyemirov/pinguin/pkg/grpcutil"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"log/slog"
 )
 
@@ -20,29 +26,91 @@ import (
 // required invariants (address, token, or timeout configuration).
 var ErrInvalidSettings = errors.New("invalid_client_settings")
 
+const (
+	// DefaultKeepaliveTime is how long a connection may sit idle before the client pings the server.
+	DefaultKeepaliveTime = 30 * time.Second
+	// DefaultKeepaliveTimeout is how long the client waits for a ping ack before closing the connection.
+	DefaultKeepaliveTimeout = 10 * time.Second
+)
+
+// TimeoutError reports an RPC that ran out of time, naming the method and how long
+// the client waited. It unwraps to the RPC error and to context.DeadlineExceeded, so
+// errors.Is(err, context.DeadlineExceeded) holds even though gRPC reports deadlines
+// as status errors.
+type TimeoutError struct {
+	Method  string
+	Timeout time.Duration
+	Err     error
+}
+
+func (timeoutError *TimeoutError) Error() string {
+	return fmt.Sprintf("%s timed out after %s: %v", timeoutError.Method, timeoutError.Timeout, timeoutError.Err)
+}
+
+func (timeoutError *TimeoutError) Unwrap() []error {
+	return []error{timeoutError.Err, context.DeadlineExceeded}
+}
+
 // Settings captures the reusable connection/authentication parameters for
 // NotificationClient instances. Use NewSettings to construct a validated copy.
 type Settings struct {
 	serverAddress     string
+	grpcTarget        string
+	dialNetwork       string
 	authToken         string
 	tenantID          string
 	connectionTimeout time.Duration
 	operationTimeout  time.Duration
+	methodTimeouts    map[Method]time.Duration
+	metadata          map[string]string
+	keepaliveTime     time.Duration
+	keepaliveTimeout  time.Duration
 }
 
+// Method names a NotificationClient call whose timeout can be overridden.
+type Method string
+
+const (
+	// MethodSend is SendNotification, including the send inside SendNotificationAndWait.
+	MethodSend Method = "send"
+	// MethodStatus is GetNotificationStatus, including the polls inside SendNotificationAndWait, and Ping.
+	MethodStatus Method = "status"
+	// MethodList is ListTenantsStatus.
+	MethodList Method = "list"
+	// MethodWait bounds the whole of SendNotificationAndWait.
+	MethodWait Method = "wait"
+)
+
 // NewSettings validates and normalizes connection/authentication parameters
-// used by NotificationClient.
+// used by NotificationClient. The tenant id is sent as x-tenant-id on every RPC
+// and fills the tenant_id field of requests that leave it empty.
 func NewSettings(serverAddress string, authToken string, tenantID string, connectionTimeoutSeconds int, operationTimeoutSeconds int) (Settings, error) {
+	return newSettings(serverAddress, authToken, tenantID, true, connectionTimeoutSeconds, operationTimeoutSeconds)
+}
+
+// NewAdminSettings validates settings for cross-tenant operator calls such as
+// ListTenantsStatus, which carry no tenant id and need an admin-scoped token.
+func NewAdminSettings(serverAddress string, authToken string, connectionTimeoutSeconds int, operationTimeoutSeconds int) (Settings, error) {
+	return newSettings(serverAddress, authToken, "", false, connectionTimeoutSeconds, operationTimeoutSeconds)
+}
+
+func newSettings(serverAddress string, authToken string, tenantID string, requireTenant bool, connectionTimeoutSeconds int, operationTimeoutSeconds int) (Settings, error) {
 	address := strings.TrimSpace(serverAddress)
 	if address == "" {
 		return Settings{}, fmt.Errorf("%w: empty server address", ErrInvalidSettings)
 	}
+	grpcTarget, dialNetwork := address, endpoint.NetworkTCP
+	if serverEndpoint, endpointErr := endpoint.Parse(address); endpointErr == nil {
+		grpcTarget, dialNetwork = serverEndpoint.GRPCTarget(), serverEndpoint.Network()
+	} else if endpoint.HasScheme(address) {
+		return Settings{}, fmt.Errorf("%w: %v", ErrInvalidSettings, endpointErr)
+	}
 	token := strings.TrimSpace(authToken)
 	if token == "" {
 		return Settings{}, fmt.Errorf("%w: empty auth token", ErrInvalidSettings)
 	}
 	tenant := strings.TrimSpace(tenantID)
-	if tenant == "" {
+	if tenant == "" && requireTenant {
 		return Settings{}, fmt.Errorf("%w: empty tenant id", ErrInvalidSettings)
 	}
 	if connectionTimeoutSeconds <= 0 {
@@ -53,14 +121,19 @@ func NewSettings(serverAddress string, authToken string, tenantID string, connec
 	}
 	return Settings{
 		serverAddress:     address,
+		grpcTarget:        grpcTarget,
+		dialNetwork:       dialNetwork,
 		authToken:         token,
 		tenantID:          tenant,
 		connectionTimeout: time.Duration(connectionTimeoutSeconds) * time.Second,
 		operationTimeout:  time.Duration(operationTimeoutSeconds) * time.Second,
+		keepaliveTime:     DefaultKeepaliveTime,
+		keepaliveTimeout:  DefaultKeepaliveTimeout,
 	}, nil
 }
 
-// ServerAddress returns the normalized gRPC endpoint for this client.
+// ServerAddress returns the normalized gRPC endpoint for this client. Besides
+// host:port it may be "unix:///path/to.sock", "tcp4://host:port", or "tcp6://[host]:port".
 func (s Settings) ServerAddress() string {
 	return s.serverAddress
 }
@@ -87,6 +160,121 @@ func (s Settings) OperationTimeout() time.Duration {
 	return s.operationTimeout
 }
 
+// WithMethodTimeout returns a copy of the settings that gives one method its own
+// timeout instead of the operation timeout.
+func (s Settings) WithMethodTimeout(method Method, timeoutSeconds int) (Settings, error) {
+	switch method {
+	case MethodSend, MethodStatus, MethodList, MethodWait:
+	default:
+		return Settings{}, fmt.Errorf("%w: unknown method %q", ErrInvalidSettings, method)
+	}
+	if timeoutSeconds <= 0 {
+		return Settings{}, fmt.Errorf("%w: %s timeout must be positive", ErrInvalidSettings, method)
+	}
+	overrides := make(map[Method]time.Duration, len(s.methodTimeouts)+1)
+	for existingMethod, timeout := range s.methodTimeouts {
+		overrides[existingMethod] = timeout
+	}
+	overrides[method] = time.Duration(timeoutSeconds) * time.Second
+	s.methodTimeouts = overrides
+	return s, nil
+}
+
+// MethodTimeout returns the timeout applied to method: its override when one was
+// set, otherwise the operation timeout.
+func (s Settings) MethodTimeout(method Method) time.Duration {
+	if timeout, overridden := s.methodTimeouts[method]; overridden {
+		return timeout
+	}
+	return s.operationTimeout
+}
+
+// WithKeepalive returns a copy of the settings that pings the server after the
+// connection has been idle for intervalSeconds and drops it when a ping goes
+// unanswered for timeoutSeconds. gRPC raises intervals under 10 seconds to 10, and
+// the server closes connections that ping more often than it allows (20 seconds by
+// default).
+func (s Settings) WithKeepalive(intervalSeconds int, timeoutSeconds int) (Settings, error) {
+	if intervalSeconds <= 0 {
+		return Settings{}, fmt.Errorf("%w: invalid keepalive interval %d", ErrInvalidSettings, intervalSeconds)
+	}
+	if timeoutSeconds <= 0 {
+		return Settings{}, fmt.Errorf("%w: invalid keepalive timeout %d", ErrInvalidSettings, timeoutSeconds)
+	}
+	s.keepaliveTime = time.Duration(intervalSeconds) * time.Second
+	s.keepaliveTimeout = time.Duration(timeoutSeconds) * time.Second
+	return s, nil
+}
+
+// KeepaliveTime returns how long a connection may sit idle before the client pings.
+func (s Settings) KeepaliveTime() time.Duration {
+	return s.keepaliveTime
+}
+
+// KeepaliveTimeout returns how long the client waits for a keepalive ping ack.
+func (s Settings) KeepaliveTimeout() time.Duration {
+	return s.keepaliveTimeout
+}
+
+// reservedMetadataKeys are set by the client itself and cannot be overridden.
+var reservedMetadataKeys = map[string]struct{}{
+	"authorization": {},
+	"x-tenant-id":   {},
+}
+
+// WithMetadata returns a copy of the settings that also sends the given static
+// metadata (for example x-client-version or x-request-id) on every RPC. Keys are
+// lowercased and must be valid gRPC metadata keys: letters, digits, '-', '_' and
+// '.', not starting with "grpc-" and not ending in "-bin". Values must be printable
+// ASCII. The authorization and x-tenant-id keys are reserved.
+func (s Settings) WithMetadata(entries map[string]string) (Settings, error) {
+	normalized := make(map[string]string, len(s.metadata)+len(entries))
+	for key, value := range s.metadata {
+		normalized[key] = value
+	}
+	for rawKey, value := range entries {
+		key := strings.ToLower(strings.TrimSpace(rawKey))
+		if err := validateMetadataEntry(key, value); err != nil {
+			return Settings{}, fmt.Errorf("%w: %v", ErrInvalidSettings, err)
+		}
+		normalized[key] = value
+	}
+	s.metadata = normalized
+	return s, nil
+}
+
+// Metadata returns a copy of the static metadata attached to every RPC.
+func (s Settings) Metadata() map[string]string {
+	metadataCopy := make(map[string]string, len(s.metadata))
+	for key, value := range s.metadata {
+		metadataCopy[key] = value
+	}
+	return metadataCopy
+}
+
+func validateMetadataEntry(key string, value string) error {
+	if key == "" {
+		return errors.New("empty metadata key")
+	}
+	if _, reserved := reservedMetadataKeys[key]; reserved {
+		return fmt.Errorf("metadata key %q is set by the client", key)
+	}
+	if strings.HasPrefix(key, "grpc-") || strings.HasSuffix(key, "-bin") {
+		return fmt.Errorf("metadata key %q is reserved by gRPC or binary", key)
+	}
+	for _, character := range key {
+		if (character < 'a' || character > 'z') && (character < '0' || character > '9') && character != '-' && character != '_' && character != '.' {
+			return fmt.Errorf("invalid metadata key %q", key)
+		}
+	}
+	for _, character := range value {
+		if character < 0x20 || character > 0x7E {
+			return fmt.Errorf("metadata value for %q must be printable ASCII", key)
+		}
+	}
+	return nil
+}
+
 // NotificationClient is a thin wrapper around the generated gRPC client that
 // automatically wires authentication metadata, call sizing, and optional
 // polling helpers.
@@ -104,18 +292,29 @@ var newGRPCClient = grpc.NewClient
 // NewNotificationClient dials the configured server and returns a ready-to-use
 // NotificationClient.
 func NewNotificationClient(logger *slog.Logger, settings Settings) (*NotificationClient, error) {
-	conn, err := newGRPCClient(
-		settings.ServerAddress(),
-		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
-			dialer := &net.Dialer{}
-			return dialer.DialContext(ctx, "tcp", addr)
-		}),
+	dialOptions := []grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 		grpc.WithDefaultCallOptions(
 			grpc.MaxCallRecvMsgSize(grpcutil.MaxMessageSizeBytes),
 			grpc.MaxCallSendMsgSize(grpcutil.MaxMessageSizeBytes),
 		),
-	)
+	}
+	// Settings built as a zero value rather than by NewSettings keep gRPC's defaults.
+	if settings.keepaliveTime > 0 {
+		dialOptions = append(dialOptions, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                settings.keepaliveTime,
+			Timeout:             settings.keepaliveTimeout,
+			PermitWithoutStream: true,
+		}))
+	}
+	// gRPC dials "unix:" targets itself; TCP targets pin the address family.
+	if settings.dialNetwork != endpoint.NetworkUnix {
+		dialOptions = append(dialOptions, grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			dialer := &net.Dialer{}
+			return dialer.DialContext(ctx, settings.dialNetwork, addr)
+		}))
+	}
+	conn, err := newGRPCClient(settings.grpcTarget, dialOptions...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to dial gRPC server: %w", err)
 	}
@@ -136,23 +335,26 @@ func (clientInstance *NotificationClient) Close() error {
 	return clientInstance.conn.Close()
 }
 
-// SendNotification invokes the SendNotification RPC with the provided context.
+// SendNotification invokes the SendNotification RPC with the provided context,
+// bounded by the send timeout.
 func (clientInstance *NotificationClient) SendNotification(ctx context.Context, req *grpcapi.NotificationRequest) (*grpcapi.NotificationResponse, error) {
+	ctx, cancel, timeout := clientInstance.withMethodTimeout(ctx, MethodSend)
+	defer cancel()
 	ctx = clientInstance.withMetadata(ctx)
 	if req.GetTenantId() == "" {
 		req.TenantId = clientInstance.tenantID
 	}
 	resp, err := clientInstance.grpcClient.SendNotification(ctx, req)
 	if err != nil {
-		return nil, err
+		return nil, wrapTimeout("SendNotification", timeout, err)
 	}
 	return resp, nil
 }
 
 // GetNotificationStatus fetches the latest server status for the supplied
-// notification identifier, applying the client's default timeout.
+// notification identifier, applying the status timeout.
 func (clientInstance *NotificationClient) GetNotificationStatus(notificationID string) (*grpcapi.NotificationResponse, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), clientInstance.settings.OperationTimeout())
+	ctx, cancel, timeout := clientInstance.withMethodTimeout(context.Background(), MethodStatus)
 	defer cancel()
 	ctx = clientInstance.withMetadata(ctx)
 	req := &grpcapi.GetNotificationStatusRequest{
@@ -161,18 +363,74 @@ func (clientInstance *NotificationClient) GetNotificationStatus(notificationID s
 	}
 	resp, err := clientInstance.grpcClient.GetNotificationStatus(ctx, req)
 	if err != nil {
-		return nil, err
+		return nil, wrapTimeout("GetNotificationStatus", timeout, err)
 	}
 	return resp, nil
 }
 
+// ListTenantsStatus fetches the cross-tenant operator view, applying the list
+// timeout. The server only accepts admin-scoped tokens.
+func (clientInstance *NotificationClient) ListTenantsStatus() (*grpcapi.ListTenantsStatusResponse, error) {
+	ctx, cancel, timeout := clientInstance.withMethodTimeout(context.Background(), MethodList)
+	defer cancel()
+	resp, err := clientInstance.grpcClient.ListTenantsStatus(clientInstance.withMetadata(ctx), &grpcapi.ListTenantsStatusRequest{})
+	if err != nil {
+		return nil, wrapTimeout("ListTenantsStatus", timeout, err)
+	}
+	return resp, nil
+}
+
+// Ping checks connectivity and the auth token without touching any tenant, applying
+// the status timeout. Any valid token may call it.
+func (clientInstance *NotificationClient) Ping() (*grpcapi.PingResponse, error) {
+	ctx, cancel, timeout := clientInstance.withMethodTimeout(context.Background(), MethodStatus)
+	defer cancel()
+	resp, err := clientInstance.grpcClient.Ping(clientInstance.withMetadata(ctx), &grpcapi.PingRequest{})
+	if err != nil {
+		return nil, wrapTimeout("Ping", timeout, err)
+	}
+	return resp, nil
+}
+
+// ListNotificationsStream iterates the tenant's notifications oldest first, without
+// attachments, limited to statuses when any are given. No method timeout applies, since
+// a large export can run for minutes; ctx bounds it instead. A failed stream ends the
+// iteration with a nil response and the error, and breaking out early cancels the stream.
+func (clientInstance *NotificationClient) ListNotificationsStream(ctx context.Context, statuses ...grpcapi.Status) iter.Seq2[*grpcapi.NotificationResponse, error] {
+	return func(yield func(*grpcapi.NotificationResponse, error) bool) {
+		streamCtx, cancel := context.WithCancel(clientInstance.withMetadata(ctx))
+		defer cancel()
+		stream, err := clientInstance.grpcClient.ListNotificationsStream(streamCtx, &grpcapi.ListNotificationsRequest{
+			Statuses: statuses,
+			TenantId: clientInstance.tenantID,
+		})
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		for {
+			response, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(response, nil) {
+				return
+			}
+		}
+	}
+}
+
 var sendPollInterval = 2 * time.Second
 
 // SendNotificationAndWait issues a SendNotification RPC and polls for its
-// terminal status until it is either sent, fails, or the client's timeout
-// elapses.
+// terminal status until it is either sent, fails, or the wait timeout elapses.
 func (clientInstance *NotificationClient) SendNotificationAndWait(req *grpcapi.NotificationRequest) (*grpcapi.NotificationResponse, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), clientInstance.settings.OperationTimeout())
+	pollTimeout := clientInstance.settings.MethodTimeout(MethodWait)
+	ctx, cancel := context.WithTimeout(context.Background(), pollTimeout)
 	defer cancel()
 
 	resp, err := clientInstance.SendNotification(ctx, req)
@@ -180,7 +438,6 @@ func (clientInstance *NotificationClient) SendNotificationAndWait(req *grpcapi.N
 		clientInstance.logger.Error("SendNotification failed", "error", err)
 		return nil, err
 	}
-	pollTimeout := clientInstance.settings.OperationTimeout()
 	startTime := time.Now()
 
 	for {
@@ -205,10 +462,35 @@ func (clientInstance *NotificationClient) SendNotificationAndWait(req *grpcapi.N
 	}
 }
 
+// withMethodTimeout bounds ctx by method's timeout and returns the time the call gets,
+// which is shorter than the method timeout when ctx already expires sooner.
+func (clientInstance *NotificationClient) withMethodTimeout(ctx context.Context, method Method) (context.Context, context.CancelFunc, time.Duration) {
+	timeout := clientInstance.settings.MethodTimeout(method)
+	if deadline, hasDeadline := ctx.Deadline(); hasDeadline {
+		if remaining := time.Until(deadline).Round(time.Millisecond); remaining < timeout {
+			timeout = remaining
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, cancel, timeout
+}
+
+// wrapTimeout turns a deadline failure of rpcName into a TimeoutError and returns
+// other errors unchanged.
+func wrapTimeout(rpcName string, timeout time.Duration, err error) error {
+	if !errors.Is(err, context.DeadlineExceeded) && status.Code(err) != codes.DeadlineExceeded {
+		return err
+	}
+	return &TimeoutError{Method: rpcName, Timeout: timeout, Err: err}
+}
+
 func (clientInstance *NotificationClient) withMetadata(ctx context.Context) context.Context {
-	return metadata.AppendToOutgoingContext(
-		ctx,
-		"authorization", "Bearer "+clientInstance.authToken,
-		"x-tenant-id", clientInstance.tenantID,
-	)
+	for key, value := range clientInstance.settings.metadata {
+		ctx = metadata.AppendToOutgoingContext(ctx, key, value)
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+clientInstance.authToken)
+	if clientInstance.tenantID == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, "x-tenant-id", clientInstance.tenantID)
 }
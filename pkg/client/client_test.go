@@ -3,14 +3,23 @@ package client
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/tyemirov/pinguin/pkg/grpcapi"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func TestNewSettingsValidation(t *testing.T) {
@@ -47,15 +56,52 @@ func TestNewSettingsValidation(t *testing.T) {
 
 type fakeNotificationServer struct {
 	grpcapi.UnimplementedNotificationServiceServer
-	initialStatus grpcapi.Status
-	polledStatus  grpcapi.Status
-	statusCalls   int
-	sendErr       error
-	statusErr     error
-	lastRequest   *grpcapi.NotificationRequest
+	initialStatus  grpcapi.Status
+	polledStatus   grpcapi.Status
+	statusCalls    int
+	sendErr        error
+	statusErr      error
+	lastRequest    *grpcapi.NotificationRequest
+	adminMetadata  metadata.MD
+	sendMetadata   metadata.MD
+	statusMetadata metadata.MD
+	statusRequest  *grpcapi.GetNotificationStatusRequest
+	// The *Remaining fields hold the time left before each RPC's deadline on arrival.
+	sendRemaining   time.Duration
+	statusRemaining time.Duration
+	adminRemaining  time.Duration
+	// blockUntilDeadline makes send and status calls wait for the client's deadline.
+	blockUntilDeadline bool
+	// streamCount responses are streamed before the stream ends with streamErr.
+	streamCount    int
+	streamErr      error
+	streamRequest  *grpcapi.ListNotificationsRequest
+	streamMetadata metadata.MD
+	streamSent     atomic.Int32
+	streamDone     chan error
 }
 
-func (s *fakeNotificationServer) SendNotification(_ context.Context, request *grpcapi.NotificationRequest) (*grpcapi.NotificationResponse, error) {
+func remainingUntilDeadline(ctx context.Context) time.Duration {
+	deadline, hasDeadline := ctx.Deadline()
+	if !hasDeadline {
+		return 0
+	}
+	return time.Until(deadline)
+}
+
+func (s *fakeNotificationServer) ListTenantsStatus(ctx context.Context, _ *grpcapi.ListTenantsStatusRequest) (*grpcapi.ListTenantsStatusResponse, error) {
+	s.adminMetadata, _ = metadata.FromIncomingContext(ctx)
+	s.adminRemaining = remainingUntilDeadline(ctx)
+	return &grpcapi.ListTenantsStatusResponse{Tenants: []*grpcapi.TenantStatus{{TenantId: "tenant-one", QueuedCount: 2}}}, nil
+}
+
+func (s *fakeNotificationServer) SendNotification(ctx context.Context, request *grpcapi.NotificationRequest) (*grpcapi.NotificationResponse, error) {
+	s.sendMetadata, _ = metadata.FromIncomingContext(ctx)
+	s.sendRemaining = remainingUntilDeadline(ctx)
+	if s.blockUntilDeadline {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
 	if s.sendErr != nil {
 		return nil, s.sendErr
 	}
@@ -66,7 +112,14 @@ func (s *fakeNotificationServer) SendNotification(_ context.Context, request *gr
 	}, nil
 }
 
-func (s *fakeNotificationServer) GetNotificationStatus(context.Context, *grpcapi.GetNotificationStatusRequest) (*grpcapi.NotificationResponse, error) {
+func (s *fakeNotificationServer) GetNotificationStatus(ctx context.Context, request *grpcapi.GetNotificationStatusRequest) (*grpcapi.NotificationResponse, error) {
+	s.statusMetadata, _ = metadata.FromIncomingContext(ctx)
+	s.statusRemaining = remainingUntilDeadline(ctx)
+	s.statusRequest = request
+	if s.blockUntilDeadline {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
 	if s.statusErr != nil {
 		return nil, s.statusErr
 	}
@@ -78,6 +131,23 @@ func (s *fakeNotificationServer) GetNotificationStatus(context.Context, *grpcapi
 	}, nil
 }
 
+func (s *fakeNotificationServer) ListNotificationsStream(request *grpcapi.ListNotificationsRequest, stream grpcapi.NotificationService_ListNotificationsStreamServer) error {
+	s.streamMetadata, _ = metadata.FromIncomingContext(stream.Context())
+	s.streamRequest = request
+	err := s.streamErr
+	for index := range s.streamCount {
+		if sendErr := stream.Send(&grpcapi.NotificationResponse{NotificationId: fmt.Sprintf("notif-%d", index)}); sendErr != nil {
+			err = sendErr
+			break
+		}
+		s.streamSent.Add(1)
+	}
+	if s.streamDone != nil {
+		s.streamDone <- err
+	}
+	return err
+}
+
 func startFakeServer(t *testing.T, srv grpcapi.NotificationServiceServer) (string, func()) {
 	t.Helper()
 	listener, err := net.Listen("tcp", "127.0.0.1:0")
@@ -249,6 +319,361 @@ func TestNotificationClientPreservesExplicitTenantID(t *testing.T) {
 	}
 }
 
+func TestNotificationClientListTenantsStatusSendsNoTenant(t *testing.T) {
+	server := &fakeNotificationServer{}
+	address, stop := startFakeServer(t, server)
+	defer stop()
+	if _, err := NewAdminSettings(address, "", 5, 5); err == nil {
+		t.Fatalf("expected error for empty admin token")
+	}
+	settings, err := NewAdminSettings(address, "admin-token", 5, 5)
+	if err != nil {
+		t.Fatalf("NewAdminSettings error: %v", err)
+	}
+	clientInstance, err := NewNotificationClient(newTestLogger(), settings)
+	if err != nil {
+		t.Fatalf("NewNotificationClient error: %v", err)
+	}
+	defer clientInstance.Close()
+
+	response, err := clientInstance.ListTenantsStatus()
+	if err != nil {
+		t.Fatalf("ListTenantsStatus failed: %v", err)
+	}
+	if len(response.GetTenants()) != 1 || response.GetTenants()[0].GetQueuedCount() != 2 {
+		t.Fatalf("unexpected tenants %+v", response.GetTenants())
+	}
+	if tenantValues := server.adminMetadata.Get("x-tenant-id"); len(tenantValues) != 0 {
+		t.Fatalf("expected no tenant metadata, got %v", tenantValues)
+	}
+	if authorization := server.adminMetadata.Get("authorization"); len(authorization) != 1 || authorization[0] != "Bearer admin-token" {
+		t.Fatalf("unexpected authorization metadata %v", authorization)
+	}
+}
+
+func TestNotificationClientListNotificationsStream(t *testing.T) {
+	server := &fakeNotificationServer{streamCount: 2500}
+	address, stop := startFakeServer(t, server)
+	defer stop()
+	settings, err := NewSettings(address, "token", "tenant-stream", 5, 5)
+	if err != nil {
+		t.Fatalf("NewSettings error: %v", err)
+	}
+	clientInstance, err := NewNotificationClient(newTestLogger(), settings)
+	if err != nil {
+		t.Fatalf("NewNotificationClient error: %v", err)
+	}
+	defer clientInstance.Close()
+
+	received := 0
+	for response, err := range clientInstance.ListNotificationsStream(context.Background(), grpcapi.Status_SENT) {
+		if err != nil {
+			t.Fatalf("stream error: %v", err)
+		}
+		if response.GetNotificationId() != fmt.Sprintf("notif-%d", received) {
+			t.Fatalf("expected responses in server order, got %q at %d", response.GetNotificationId(), received)
+		}
+		received++
+	}
+	if received != server.streamCount {
+		t.Fatalf("expected %d responses, got %d", server.streamCount, received)
+	}
+	if server.streamRequest.GetTenantId() != "tenant-stream" || len(server.streamRequest.GetStatuses()) != 1 || server.streamRequest.GetStatuses()[0] != grpcapi.Status_SENT {
+		t.Fatalf("unexpected stream request %+v", server.streamRequest)
+	}
+	if authorization := server.streamMetadata.Get("authorization"); len(authorization) != 1 || authorization[0] != "Bearer token" {
+		t.Fatalf("unexpected authorization metadata %v", authorization)
+	}
+
+	server.streamErr = status.Error(codes.Unavailable, "busy")
+	var streamErr error
+	for _, err := range clientInstance.ListNotificationsStream(context.Background()) {
+		streamErr = err
+	}
+	if status.Code(streamErr) != codes.Unavailable {
+		t.Fatalf("expected the stream error to end the iteration, got %v", streamErr)
+	}
+}
+
+func TestNotificationClientListNotificationsStreamStopsOnBreak(t *testing.T) {
+	server := &fakeNotificationServer{streamCount: 1_000_000, streamDone: make(chan error, 1)}
+	address, stop := startFakeServer(t, server)
+	defer stop()
+	settings, err := NewSettings(address, "token", "tenant-stream", 5, 5)
+	if err != nil {
+		t.Fatalf("NewSettings error: %v", err)
+	}
+	clientInstance, err := NewNotificationClient(newTestLogger(), settings)
+	if err != nil {
+		t.Fatalf("NewNotificationClient error: %v", err)
+	}
+	defer clientInstance.Close()
+
+	for _, err := range clientInstance.ListNotificationsStream(context.Background()) {
+		if err != nil {
+			t.Fatalf("stream error: %v", err)
+		}
+		break
+	}
+	select {
+	case err := <-server.streamDone:
+		if status.Code(err) != codes.Canceled {
+			t.Fatalf("expected the server send to fail with Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected breaking out of the iteration to cancel the stream")
+	}
+	if sent := server.streamSent.Load(); sent >= int32(server.streamCount) {
+		t.Fatalf("expected the server to stop short of the full result, sent %d", sent)
+	}
+}
+
+func TestSettingsWithMetadataValidatesKeys(t *testing.T) {
+	settings, err := NewSettings("addr", "token", "tenant", 1, 1)
+	if err != nil {
+		t.Fatalf("NewSettings error: %v", err)
+	}
+	for _, entries := range []map[string]string{
+		{"": "value"},
+		{"Authorization": "Bearer other"},
+		{"x-tenant-id": "tenant-other"},
+		{"grpc-timeout": "1S"},
+		{"x-trace-bin": "value"},
+		{"x client": "value"},
+		{"x-client-version": "1.0\n"},
+	} {
+		if _, err := settings.WithMetadata(entries); !errors.Is(err, ErrInvalidSettings) {
+			t.Fatalf("expected metadata %v to be rejected, got %v", entries, err)
+		}
+	}
+	withMetadata, err := settings.WithMetadata(map[string]string{" X-Client-Version ": "1.2.3"})
+	if err != nil {
+		t.Fatalf("WithMetadata error: %v", err)
+	}
+	withMetadata, err = withMetadata.WithMetadata(map[string]string{"x-request-id": "req-1"})
+	if err != nil {
+		t.Fatalf("WithMetadata error: %v", err)
+	}
+	if got := withMetadata.Metadata(); len(got) != 2 || got["x-client-version"] != "1.2.3" || got["x-request-id"] != "req-1" {
+		t.Fatalf("unexpected metadata %v", got)
+	}
+	if len(settings.Metadata()) != 0 {
+		t.Fatalf("expected WithMetadata to leave the original settings unchanged")
+	}
+}
+
+func TestNotificationClientSendsCustomMetadata(t *testing.T) {
+	server := &fakeNotificationServer{initialStatus: grpcapi.Status_SENT}
+	address, stop := startFakeServer(t, server)
+	defer stop()
+	settings, err := NewSettings(address, "token", "tenant-one", 5, 5)
+	if err != nil {
+		t.Fatalf("NewSettings error: %v", err)
+	}
+	settings, err = settings.WithMetadata(map[string]string{"x-client-version": "1.2.3", "x-request-id": "req-1"})
+	if err != nil {
+		t.Fatalf("WithMetadata error: %v", err)
+	}
+	clientInstance, err := NewNotificationClient(newTestLogger(), settings)
+	if err != nil {
+		t.Fatalf("NewNotificationClient error: %v", err)
+	}
+	defer clientInstance.Close()
+
+	if _, err := clientInstance.SendNotification(context.Background(), &grpcapi.NotificationRequest{}); err != nil {
+		t.Fatalf("SendNotification failed: %v", err)
+	}
+	for key, expected := range map[string]string{
+		"x-client-version": "1.2.3",
+		"x-request-id":     "req-1",
+		"authorization":    "Bearer token",
+		"x-tenant-id":      "tenant-one",
+	} {
+		if values := server.sendMetadata.Get(key); len(values) != 1 || values[0] != expected {
+			t.Fatalf("expected %s=%q to reach the server, got %v", key, expected, values)
+		}
+	}
+}
+
+func TestNotificationClientAttachesTenantToEveryRPC(t *testing.T) {
+	server := &fakeNotificationServer{initialStatus: grpcapi.Status_SENT, polledStatus: grpcapi.Status_SENT}
+	address, stop := startFakeServer(t, server)
+	defer stop()
+	settings, err := NewSettings(address, "token", "tenant-one", 5, 5)
+	if err != nil {
+		t.Fatalf("NewSettings error: %v", err)
+	}
+	clientInstance, err := NewNotificationClient(newTestLogger(), settings)
+	if err != nil {
+		t.Fatalf("NewNotificationClient error: %v", err)
+	}
+	defer clientInstance.Close()
+
+	if _, err := clientInstance.SendNotification(context.Background(), &grpcapi.NotificationRequest{}); err != nil {
+		t.Fatalf("SendNotification failed: %v", err)
+	}
+	if _, err := clientInstance.GetNotificationStatus("notif-123"); err != nil {
+		t.Fatalf("GetNotificationStatus failed: %v", err)
+	}
+	for rpcName, received := range map[string]metadata.MD{"SendNotification": server.sendMetadata, "GetNotificationStatus": server.statusMetadata} {
+		if values := received.Get("x-tenant-id"); len(values) != 1 || values[0] != "tenant-one" {
+			t.Fatalf("expected %s to carry x-tenant-id=tenant-one, got %v", rpcName, values)
+		}
+	}
+	if server.lastRequest.GetTenantId() != "tenant-one" || server.statusRequest.GetTenantId() != "tenant-one" {
+		t.Fatalf("expected the tenant to fill the request fields too, got %q and %q", server.lastRequest.GetTenantId(), server.statusRequest.GetTenantId())
+	}
+}
+
+func TestSettingsWithMethodTimeout(t *testing.T) {
+	settings, err := NewSettings("addr", "token", "tenant", 5, 7)
+	if err != nil {
+		t.Fatalf("NewSettings error: %v", err)
+	}
+	if _, err := settings.WithMethodTimeout(MethodSend, 0); !errors.Is(err, ErrInvalidSettings) {
+		t.Fatalf("expected a non-positive timeout to be rejected, got %v", err)
+	}
+	if _, err := settings.WithMethodTimeout(Method("delete"), 5); !errors.Is(err, ErrInvalidSettings) {
+		t.Fatalf("expected an unknown method to be rejected, got %v", err)
+	}
+	overridden, err := settings.WithMethodTimeout(MethodWait, 60)
+	if err != nil {
+		t.Fatalf("WithMethodTimeout error: %v", err)
+	}
+	if overridden.MethodTimeout(MethodWait) != time.Minute || overridden.MethodTimeout(MethodStatus) != 7*time.Second {
+		t.Fatalf("expected the wait override and the default elsewhere, got %v/%v", overridden.MethodTimeout(MethodWait), overridden.MethodTimeout(MethodStatus))
+	}
+	if settings.MethodTimeout(MethodWait) != 7*time.Second {
+		t.Fatalf("expected WithMethodTimeout to leave the original settings unchanged")
+	}
+}
+
+func TestNotificationClientAppliesMethodTimeouts(t *testing.T) {
+	server := &fakeNotificationServer{initialStatus: grpcapi.Status_SENT, polledStatus: grpcapi.Status_SENT}
+	address, stop := startFakeServer(t, server)
+	defer stop()
+	settings, err := NewSettings(address, "token", "tenant-one", 5, 10)
+	if err != nil {
+		t.Fatalf("NewSettings error: %v", err)
+	}
+	settings, err = settings.WithMethodTimeout(MethodSend, 30)
+	if err != nil {
+		t.Fatalf("WithMethodTimeout error: %v", err)
+	}
+	settings, err = settings.WithMethodTimeout(MethodStatus, 2)
+	if err != nil {
+		t.Fatalf("WithMethodTimeout error: %v", err)
+	}
+	clientInstance, err := NewNotificationClient(newTestLogger(), settings)
+	if err != nil {
+		t.Fatalf("NewNotificationClient error: %v", err)
+	}
+	defer clientInstance.Close()
+
+	if _, err := clientInstance.SendNotification(context.Background(), &grpcapi.NotificationRequest{}); err != nil {
+		t.Fatalf("SendNotification failed: %v", err)
+	}
+	if _, err := clientInstance.GetNotificationStatus("notif-123"); err != nil {
+		t.Fatalf("GetNotificationStatus failed: %v", err)
+	}
+	if _, err := clientInstance.ListTenantsStatus(); err != nil {
+		t.Fatalf("ListTenantsStatus failed: %v", err)
+	}
+	for name, testCase := range map[string]struct {
+		remaining time.Duration
+		expected  time.Duration
+	}{
+		"send":   {remaining: server.sendRemaining, expected: 30 * time.Second},
+		"status": {remaining: server.statusRemaining, expected: 2 * time.Second},
+		"list":   {remaining: server.adminRemaining, expected: 10 * time.Second},
+	} {
+		if testCase.remaining > testCase.expected || testCase.remaining < testCase.expected-time.Second {
+			t.Fatalf("expected the %s deadline about %v away, got %v", name, testCase.expected, testCase.remaining)
+		}
+	}
+}
+
+func TestNotificationClientWaitTimeoutBoundsSendAndWait(t *testing.T) {
+	t.Cleanup(func() { sendPollInterval = 2 * time.Second })
+	sendPollInterval = 10 * time.Millisecond
+	server := &fakeNotificationServer{initialStatus: grpcapi.Status_QUEUED, polledStatus: grpcapi.Status_QUEUED}
+	address, stop := startFakeServer(t, server)
+	defer stop()
+	settings, err := NewSettings(address, "token", "tenant-one", 5, 10)
+	if err != nil {
+		t.Fatalf("NewSettings error: %v", err)
+	}
+	settings, err = settings.WithMethodTimeout(MethodWait, 1)
+	if err != nil {
+		t.Fatalf("WithMethodTimeout error: %v", err)
+	}
+	clientInstance, err := NewNotificationClient(newTestLogger(), settings)
+	if err != nil {
+		t.Fatalf("NewNotificationClient error: %v", err)
+	}
+	defer clientInstance.Close()
+
+	startTime := time.Now()
+	if _, err := clientInstance.SendNotificationAndWait(&grpcapi.NotificationRequest{}); err == nil || err.Error() != "timeout waiting for notification to be sent" {
+		t.Fatalf("expected the wait to time out, got %v", err)
+	}
+	if elapsed := time.Since(startTime); elapsed > 3*time.Second {
+		t.Fatalf("expected the 1s wait timeout instead of the 10s default, took %v", elapsed)
+	}
+	if server.sendRemaining > time.Second {
+		t.Fatalf("expected the send inside the wait to inherit its deadline, got %v", server.sendRemaining)
+	}
+}
+
+func TestNotificationClientNamesTimedOutRPC(t *testing.T) {
+	server := &fakeNotificationServer{blockUntilDeadline: true}
+	address, stop := startFakeServer(t, server)
+	defer stop()
+	settings, err := NewSettings(address, "token", "tenant-one", 5, 30)
+	if err != nil {
+		t.Fatalf("NewSettings error: %v", err)
+	}
+	settings, err = settings.WithMethodTimeout(MethodStatus, 1)
+	if err != nil {
+		t.Fatalf("WithMethodTimeout error: %v", err)
+	}
+	clientInstance, err := NewNotificationClient(newTestLogger(), settings)
+	if err != nil {
+		t.Fatalf("NewNotificationClient error: %v", err)
+	}
+	defer clientInstance.Close()
+
+	_, err = clientInstance.GetNotificationStatus("notif-123")
+	var timeoutError *TimeoutError
+	if !errors.As(err, &timeoutError) || timeoutError.Method != "GetNotificationStatus" || timeoutError.Timeout != time.Second {
+		t.Fatalf("expected a GetNotificationStatus timeout after 1s, got %v", err)
+	}
+	if !strings.HasPrefix(err.Error(), "GetNotificationStatus timed out after 1s: ") {
+		t.Fatalf("unexpected timeout message %q", err.Error())
+	}
+	if !errors.Is(err, context.DeadlineExceeded) || status.Code(timeoutError.Err) != codes.DeadlineExceeded {
+		t.Fatalf("expected the deadline to stay detectable, got %v", err)
+	}
+
+	callerContext, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err = clientInstance.SendNotification(callerContext, &grpcapi.NotificationRequest{})
+	if !errors.As(err, &timeoutError) || timeoutError.Method != "SendNotification" || timeoutError.Timeout > 200*time.Millisecond {
+		t.Fatalf("expected the caller's shorter deadline to be reported, got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected errors.Is(err, context.DeadlineExceeded), got %v", err)
+	}
+
+	server.blockUntilDeadline = false
+	server.sendErr = errors.New("send failed")
+	_, err = clientInstance.SendNotification(context.Background(), &grpcapi.NotificationRequest{})
+	var passedThrough *TimeoutError
+	if err == nil || errors.As(err, &passedThrough) || errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected other errors to pass through unwrapped, got %v", err)
+	}
+}
+
 func TestNewNotificationClientReportsConstructorError(t *testing.T) {
 	originalNewClient := newGRPCClient
 	t.Cleanup(func() { newGRPCClient = originalNewClient })
@@ -265,6 +690,84 @@ func TestNewNotificationClientReportsConstructorError(t *testing.T) {
 	}
 }
 
+// countingListener counts the connections a server accepts.
+type countingListener struct {
+	net.Listener
+	accepted atomic.Int32
+}
+
+func (listener *countingListener) Accept() (net.Conn, error) {
+	connection, err := listener.Listener.Accept()
+	if err == nil {
+		listener.accepted.Add(1)
+	}
+	return connection, err
+}
+
+func TestSettingsWithKeepalive(t *testing.T) {
+	settings, err := NewSettings("addr", "token", "tenant", 5, 7)
+	if err != nil {
+		t.Fatalf("NewSettings error: %v", err)
+	}
+	if settings.KeepaliveTime() != DefaultKeepaliveTime || settings.KeepaliveTimeout() != DefaultKeepaliveTimeout {
+		t.Fatalf("expected keepalive defaults, got %v/%v", settings.KeepaliveTime(), settings.KeepaliveTimeout())
+	}
+	if _, err := settings.WithKeepalive(0, 5); !errors.Is(err, ErrInvalidSettings) {
+		t.Fatalf("expected a non-positive interval to be rejected, got %v", err)
+	}
+	if _, err := settings.WithKeepalive(15, -1); !errors.Is(err, ErrInvalidSettings) {
+		t.Fatalf("expected a non-positive timeout to be rejected, got %v", err)
+	}
+	tuned, err := settings.WithKeepalive(15, 3)
+	if err != nil {
+		t.Fatalf("WithKeepalive error: %v", err)
+	}
+	if tuned.KeepaliveTime() != 15*time.Second || tuned.KeepaliveTimeout() != 3*time.Second {
+		t.Fatalf("unexpected keepalive %v/%v", tuned.KeepaliveTime(), tuned.KeepaliveTimeout())
+	}
+}
+
+func TestNotificationClientKeepsConnectionAcrossIdlePeriod(t *testing.T) {
+	tcpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	listener := &countingListener{Listener: tcpListener}
+	// The server pings after a second of idleness; an unanswered ping would drop the connection.
+	server := grpc.NewServer(
+		grpc.KeepaliveParams(keepalive.ServerParameters{Time: time.Second, Timeout: time.Second}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{MinTime: time.Second, PermitWithoutStream: true}),
+	)
+	grpcapi.RegisterNotificationServiceServer(server, &fakeNotificationServer{polledStatus: grpcapi.Status_SENT})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	settings, err := NewSettings(tcpListener.Addr().String(), "token", "tenant", 5, 5)
+	if err != nil {
+		t.Fatalf("NewSettings error: %v", err)
+	}
+	settings, err = settings.WithKeepalive(10, 1)
+	if err != nil {
+		t.Fatalf("WithKeepalive error: %v", err)
+	}
+	notificationClient, err := NewNotificationClient(newTestLogger(), settings)
+	if err != nil {
+		t.Fatalf("NewNotificationClient error: %v", err)
+	}
+	defer notificationClient.Close()
+
+	if _, err := notificationClient.GetNotificationStatus("notif-123"); err != nil {
+		t.Fatalf("first call error: %v", err)
+	}
+	time.Sleep(2500 * time.Millisecond)
+	if _, err := notificationClient.GetNotificationStatus("notif-123"); err != nil {
+		t.Fatalf("call after idle period error: %v", err)
+	}
+	if accepted := listener.accepted.Load(); accepted != 1 {
+		t.Fatalf("expected the idle connection to be reused, got %d connections", accepted)
+	}
+}
+
 func startServerWithStatuses(t *testing.T, initial, polled grpcapi.Status) string {
 	t.Helper()
 	server := &fakeNotificationServer{
@@ -275,3 +778,44 @@ func startServerWithStatuses(t *testing.T, initial, polled grpcapi.Status) strin
 	t.Cleanup(stop)
 	return address
 }
+
+func TestNotificationClientDialsSchemeAddresses(t *testing.T) {
+	if _, err := NewSettings("unix://", "token", "tenant", 1, 1); !errors.Is(err, ErrInvalidSettings) {
+		t.Fatalf("expected an empty socket path to be rejected, got %v", err)
+	}
+	socketDirectory, err := os.MkdirTemp("", "pinguin")
+	if err != nil {
+		t.Fatalf("temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(socketDirectory) })
+	socketPath := filepath.Join(socketDirectory, "grpc.sock")
+	unixListener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("unix listen: %v", err)
+	}
+	tcp4Listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("tcp4 listen: %v", err)
+	}
+	server := grpc.NewServer()
+	grpcapi.RegisterNotificationServiceServer(server, &fakeNotificationServer{initialStatus: grpcapi.Status_SENT})
+	go server.Serve(unixListener)
+	go server.Serve(tcp4Listener)
+	t.Cleanup(server.Stop)
+
+	for _, address := range []string{"unix://" + socketPath, "tcp4://" + tcp4Listener.Addr().String()} {
+		settings, err := NewSettings(address, "token", "tenant", 5, 5)
+		if err != nil {
+			t.Fatalf("NewSettings(%s) error: %v", address, err)
+		}
+		clientInstance, err := NewNotificationClient(newTestLogger(), settings)
+		if err != nil {
+			t.Fatalf("NewNotificationClient(%s) error: %v", address, err)
+		}
+		response, err := clientInstance.SendNotification(context.Background(), &grpcapi.NotificationRequest{})
+		clientInstance.Close()
+		if err != nil || response.Status != grpcapi.Status_SENT {
+			t.Fatalf("SendNotification over %s failed: resp=%v err=%v", address, response, err)
+		}
+	}
+}
@@ -0,0 +1,4 @@
+// Package endpoint parses the listen and dial addresses shared by the Pinguin
+// servers and clients: plain "host:port", "tcp://", "tcp4://", "tcp6://" and
+// "unix://" forms, and prepares Unix domain sockets for listening.
+package endpoint
@@ -0,0 +1,170 @@
+package endpoint
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// NetworkTCP listens on every address family the host supports.
+	NetworkTCP = "tcp"
+	// NetworkTCP4 restricts the endpoint to IPv4.
+	NetworkTCP4 = "tcp4"
+	// NetworkTCP6 restricts the endpoint to IPv6.
+	NetworkTCP6 = "tcp6"
+	// NetworkUnix is a Unix domain socket path.
+	NetworkUnix = "unix"
+
+	// SocketFileMode is applied to Unix sockets after binding so only the owner
+	// and its group can connect.
+	SocketFileMode os.FileMode = 0o660
+
+	unixSchemePrefix   = "unix:"
+	schemeSeparator    = "://"
+	staleSocketTimeout = 100 * time.Millisecond
+)
+
+// ErrInvalidEndpoint reports an address that does not match any supported form.
+var ErrInvalidEndpoint = errors.New("invalid_endpoint")
+
+// Endpoint is a parsed listen or dial address.
+type Endpoint struct {
+	network string
+	address string
+}
+
+// Parse accepts "unix:///path/to.sock" (or "unix:relative.sock"), "tcp://host:port",
+// "tcp4://host:port", "tcp6://[host]:port", and plain "host:port".
+func Parse(raw string) (Endpoint, error) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return Endpoint{}, fmt.Errorf("%w: empty address", ErrInvalidEndpoint)
+	}
+	if strings.HasPrefix(trimmed, unixSchemePrefix) {
+		path := strings.TrimPrefix(strings.TrimPrefix(trimmed, unixSchemePrefix), "//")
+		if path == "" {
+			return Endpoint{}, fmt.Errorf("%w: %q has no socket path", ErrInvalidEndpoint, raw)
+		}
+		return Endpoint{network: NetworkUnix, address: path}, nil
+	}
+	network, hostPort := NetworkTCP, trimmed
+	if scheme, rest, hasScheme := strings.Cut(trimmed, schemeSeparator); hasScheme {
+		switch scheme {
+		case NetworkTCP, NetworkTCP4, NetworkTCP6:
+			network, hostPort = scheme, rest
+		default:
+			return Endpoint{}, fmt.Errorf("%w: unsupported scheme %q in %q", ErrInvalidEndpoint, scheme, raw)
+		}
+	}
+	if _, port, err := net.SplitHostPort(hostPort); err != nil || port == "" {
+		return Endpoint{}, fmt.Errorf("%w: %q must be host:port", ErrInvalidEndpoint, raw)
+	}
+	return Endpoint{network: network, address: hostPort}, nil
+}
+
+// HasScheme reports whether raw names one of the schemes Parse understands, so
+// callers that also accept other address forms know when a parse error is final.
+func HasScheme(raw string) bool {
+	trimmed := strings.TrimSpace(raw)
+	if strings.HasPrefix(trimmed, unixSchemePrefix) {
+		return true
+	}
+	scheme, _, hasScheme := strings.Cut(trimmed, schemeSeparator)
+	return hasScheme && (scheme == NetworkTCP || scheme == NetworkTCP4 || scheme == NetworkTCP6)
+}
+
+// Network returns the net package network name: tcp, tcp4, tcp6, or unix.
+func (endpoint Endpoint) Network() string {
+	return endpoint.network
+}
+
+// Address returns host:port for TCP endpoints and the socket path for Unix ones.
+func (endpoint Endpoint) Address() string {
+	return endpoint.address
+}
+
+// IsUnix reports whether the endpoint is a Unix domain socket.
+func (endpoint Endpoint) IsUnix() bool {
+	return endpoint.network == NetworkUnix
+}
+
+// String renders the endpoint in the form Parse accepts.
+func (endpoint Endpoint) String() string {
+	switch endpoint.network {
+	case NetworkTCP:
+		return endpoint.address
+	case NetworkUnix:
+		if strings.HasPrefix(endpoint.address, "/") {
+			return unixSchemePrefix + "//" + endpoint.address
+		}
+		return unixSchemePrefix + endpoint.address
+	default:
+		return endpoint.network + schemeSeparator + endpoint.address
+	}
+}
+
+// GRPCTarget returns the target to hand to grpc.NewClient. Unix sockets use
+// gRPC's "unix:" resolver; tcp4 and tcp6 bypass gRPC's DNS resolver so the
+// caller's dialer resolves the host within the chosen address family.
+func (endpoint Endpoint) GRPCTarget() string {
+	switch endpoint.network {
+	case NetworkUnix:
+		return endpoint.String()
+	case NetworkTCP4, NetworkTCP6:
+		return "passthrough:///" + endpoint.address
+	default:
+		return endpoint.address
+	}
+}
+
+// Listen binds the endpoint. See the package-level Listen for Unix socket handling.
+func (endpoint Endpoint) Listen() (net.Listener, error) {
+	return Listen(endpoint.network, endpoint.address)
+}
+
+// Listen binds network/address like net.Listen. For Unix sockets it first removes
+// a stale socket file left by a previous process, refuses to replace a socket
+// that still accepts connections or a path that is not a socket, and applies
+// SocketFileMode. Closing the listener removes the socket file.
+func Listen(network string, address string) (net.Listener, error) {
+	if network != NetworkUnix {
+		return net.Listen(network, address)
+	}
+	if err := removeStaleSocket(address); err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(address, SocketFileMode); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("set socket permissions on %s: %w", address, err)
+	}
+	return listener, nil
+}
+
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("inspect socket %s: %w", path, err)
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("socket path %s exists and is not a socket", path)
+	}
+	if conn, dialErr := net.DialTimeout(NetworkUnix, path, staleSocketTimeout); dialErr == nil {
+		conn.Close()
+		return fmt.Errorf("socket %s is already in use", path)
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("remove stale socket %s: %w", path, err)
+	}
+	return nil
+}
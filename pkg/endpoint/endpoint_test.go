@@ -0,0 +1,113 @@
+package endpoint
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseAcceptsSupportedForms(t *testing.T) {
+	testCases := []struct {
+		raw             string
+		expectedNetwork string
+		expectedAddress string
+		expectedTarget  string
+	}{
+		{raw: ":50051", expectedNetwork: NetworkTCP, expectedAddress: ":50051", expectedTarget: ":50051"},
+		{raw: " localhost:50051 ", expectedNetwork: NetworkTCP, expectedAddress: "localhost:50051", expectedTarget: "localhost:50051"},
+		{raw: "tcp://0.0.0.0:8080", expectedNetwork: NetworkTCP, expectedAddress: "0.0.0.0:8080", expectedTarget: "0.0.0.0:8080"},
+		{raw: "tcp4://0.0.0.0:50051", expectedNetwork: NetworkTCP4, expectedAddress: "0.0.0.0:50051", expectedTarget: "passthrough:///0.0.0.0:50051"},
+		{raw: "tcp6://[::1]:50051", expectedNetwork: NetworkTCP6, expectedAddress: "[::1]:50051", expectedTarget: "passthrough:///[::1]:50051"},
+		{raw: "unix:///var/run/pinguin.sock", expectedNetwork: NetworkUnix, expectedAddress: "/var/run/pinguin.sock", expectedTarget: "unix:///var/run/pinguin.sock"},
+		{raw: "unix:pinguin.sock", expectedNetwork: NetworkUnix, expectedAddress: "pinguin.sock", expectedTarget: "unix:pinguin.sock"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.raw, func(t *testing.T) {
+			parsed, err := Parse(testCase.raw)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if parsed.Network() != testCase.expectedNetwork || parsed.Address() != testCase.expectedAddress {
+				t.Fatalf("expected %s %s, got %s %s", testCase.expectedNetwork, testCase.expectedAddress, parsed.Network(), parsed.Address())
+			}
+			if parsed.GRPCTarget() != testCase.expectedTarget {
+				t.Fatalf("expected target %s, got %s", testCase.expectedTarget, parsed.GRPCTarget())
+			}
+			if reparsed, err := Parse(parsed.String()); err != nil || reparsed != parsed {
+				t.Fatalf("expected %q to round-trip, got %+v (%v)", parsed.String(), reparsed, err)
+			}
+		})
+	}
+}
+
+func TestParseRejectsMalformedAddresses(t *testing.T) {
+	for _, raw := range []string{"", "unix://", "unix:", "http://localhost:80", "localhost", "tcp4://0.0.0.0", "tcp://:"} {
+		if _, err := Parse(raw); !errors.Is(err, ErrInvalidEndpoint) {
+			t.Fatalf("expected %q to be rejected, got %v", raw, err)
+		}
+	}
+}
+
+func TestListenUnixSocketSetsModeReplacesStaleSocketAndCleansUp(t *testing.T) {
+	socketPath := filepath.Join(shortTempDir(t), "pinguin.sock")
+	stale, err := net.Listen(NetworkUnix, socketPath)
+	if err != nil {
+		t.Fatalf("stale listen: %v", err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	parsed, err := Parse("unix://" + socketPath)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	listener, err := parsed.Listen()
+	if err != nil {
+		t.Fatalf("expected the stale socket to be replaced, got %v", err)
+	}
+	info, err := os.Stat(socketPath)
+	if err != nil || info.Mode().Perm() != SocketFileMode {
+		t.Fatalf("expected socket mode %v, got %v (%v)", SocketFileMode, info.Mode().Perm(), err)
+	}
+	if _, err := parsed.Listen(); err == nil {
+		t.Fatalf("expected a live socket not to be replaced")
+	}
+	conn, err := net.Dial(parsed.Network(), parsed.Address())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	conn.Close()
+
+	if err := listener.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if _, err := os.Stat(socketPath); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected the socket file to be removed on close, got %v", err)
+	}
+}
+
+func TestListenRefusesToReplaceRegularFile(t *testing.T) {
+	path := filepath.Join(shortTempDir(t), "pinguin.sock")
+	if err := os.WriteFile(path, []byte("data"), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if _, err := Listen(NetworkUnix, path); err == nil {
+		t.Fatalf("expected a regular file not to be replaced")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected the file to survive, got %v", err)
+	}
+}
+
+// shortTempDir keeps socket paths under the platform's sun_path limit.
+func shortTempDir(t *testing.T) string {
+	t.Helper()
+	directory, err := os.MkdirTemp("", "pinguin")
+	if err != nil {
+		t.Fatalf("temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(directory) })
+	return directory
+}
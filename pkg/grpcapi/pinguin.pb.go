@@ -186,23 +186,83 @@ func (x *EmailAttachment) GetData() []byte {
 	return nil
 }
 
+// iCalendar invite sent as a text/calendar alternative plus an invite.ics attachment.
+type CalendarEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ics           string                 `protobuf:"bytes,1,opt,name=ics,proto3" json:"ics,omitempty"`       // One VCALENDAR with at least one VEVENT carrying UID and DTSTART.
+	Method        string                 `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"` // REQUEST (default) or CANCEL.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CalendarEvent) Reset() {
+	*x = CalendarEvent{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalendarEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalendarEvent) ProtoMessage() {}
+
+func (x *CalendarEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalendarEvent.ProtoReflect.Descriptor instead.
+func (*CalendarEvent) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CalendarEvent) GetIcs() string {
+	if x != nil {
+		return x.Ics
+	}
+	return ""
+}
+
+func (x *CalendarEvent) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
 // Request to send a notification.
 type NotificationRequest struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	NotificationType NotificationType       `protobuf:"varint,1,opt,name=notification_type,json=notificationType,proto3,enum=pinguin.NotificationType" json:"notification_type,omitempty"`
-	Recipient        string                 `protobuf:"bytes,2,opt,name=recipient,proto3" json:"recipient,omitempty"`
-	Subject          string                 `protobuf:"bytes,3,opt,name=subject,proto3" json:"subject,omitempty"` // Optional for SMS.
-	Message          string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
-	ScheduledTime    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=scheduled_time,json=scheduledTime,proto3" json:"scheduled_time,omitempty"`
-	Attachments      []*EmailAttachment     `protobuf:"bytes,6,rep,name=attachments,proto3" json:"attachments,omitempty"`
-	TenantId         string                 `protobuf:"bytes,7,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	NotificationType     NotificationType       `protobuf:"varint,1,opt,name=notification_type,json=notificationType,proto3,enum=pinguin.NotificationType" json:"notification_type,omitempty"`
+	Recipient            string                 `protobuf:"bytes,2,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Subject              string                 `protobuf:"bytes,3,opt,name=subject,proto3" json:"subject,omitempty"` // Optional for SMS.
+	Message              string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	ScheduledTime        *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=scheduled_time,json=scheduledTime,proto3" json:"scheduled_time,omitempty"`
+	Attachments          []*EmailAttachment     `protobuf:"bytes,6,rep,name=attachments,proto3" json:"attachments,omitempty"`
+	TenantId             string                 `protobuf:"bytes,7,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	ExpiresAt            *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`                                              // Optional do-not-send-after time.
+	CalendarEvent        *CalendarEvent         `protobuf:"bytes,9,opt,name=calendar_event,json=calendarEvent,proto3" json:"calendar_event,omitempty"`                                  // Optional; email only.
+	SmsOverflowPolicy    string                 `protobuf:"bytes,10,opt,name=sms_overflow_policy,json=smsOverflowPolicy,proto3" json:"sms_overflow_policy,omitempty"`                   // Optional "reject" or "truncate"; SMS only. Empty uses the tenant default.
+	FailOnImmediateError *bool                  `protobuf:"varint,11,opt,name=fail_on_immediate_error,json=failOnImmediateError,proto3,oneof" json:"fail_on_immediate_error,omitempty"` // Overrides server.failOnImmediateError when set.
+	ContentEncrypted     bool                   `protobuf:"varint,12,opt,name=content_encrypted,json=contentEncrypted,proto3" json:"content_encrypted,omitempty"`                       // message is caller-encrypted: a PGP/MIME or S/MIME entity for email, ciphertext for SMS.
+	ReplyTo              string                 `protobuf:"bytes,13,opt,name=reply_to,json=replyTo,proto3" json:"reply_to,omitempty"`                                                   // Optional Reply-To address; email only. The tenant's senderOverrides policy must allow it.
+	FromDisplayName      string                 `protobuf:"bytes,14,opt,name=from_display_name,json=fromDisplayName,proto3" json:"from_display_name,omitempty"`                         // Optional From display name; email only. The tenant's senderOverrides policy must allow it.
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
 }
 
 func (x *NotificationRequest) Reset() {
 	*x = NotificationRequest{}
-	mi := &file_pkg_proto_pinguin_proto_msgTypes[1]
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -214,7 +274,7 @@ func (x *NotificationRequest) String() string {
 func (*NotificationRequest) ProtoMessage() {}
 
 func (x *NotificationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_pinguin_proto_msgTypes[1]
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -227,7 +287,7 @@ func (x *NotificationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NotificationRequest.ProtoReflect.Descriptor instead.
 func (*NotificationRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{1}
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *NotificationRequest) GetNotificationType() NotificationType {
@@ -279,29 +339,96 @@ func (x *NotificationRequest) GetTenantId() string {
 	return ""
 }
 
+func (x *NotificationRequest) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *NotificationRequest) GetCalendarEvent() *CalendarEvent {
+	if x != nil {
+		return x.CalendarEvent
+	}
+	return nil
+}
+
+func (x *NotificationRequest) GetSmsOverflowPolicy() string {
+	if x != nil {
+		return x.SmsOverflowPolicy
+	}
+	return ""
+}
+
+func (x *NotificationRequest) GetFailOnImmediateError() bool {
+	if x != nil && x.FailOnImmediateError != nil {
+		return *x.FailOnImmediateError
+	}
+	return false
+}
+
+func (x *NotificationRequest) GetContentEncrypted() bool {
+	if x != nil {
+		return x.ContentEncrypted
+	}
+	return false
+}
+
+func (x *NotificationRequest) GetReplyTo() string {
+	if x != nil {
+		return x.ReplyTo
+	}
+	return ""
+}
+
+func (x *NotificationRequest) GetFromDisplayName() string {
+	if x != nil {
+		return x.FromDisplayName
+	}
+	return ""
+}
+
 // Response returned after sending (or when retrieving) a notification.
 type NotificationResponse struct {
-	state             protoimpl.MessageState `protogen:"open.v1"`
-	NotificationId    string                 `protobuf:"bytes,1,opt,name=notification_id,json=notificationId,proto3" json:"notification_id,omitempty"`
-	NotificationType  NotificationType       `protobuf:"varint,2,opt,name=notification_type,json=notificationType,proto3,enum=pinguin.NotificationType" json:"notification_type,omitempty"`
-	Recipient         string                 `protobuf:"bytes,3,opt,name=recipient,proto3" json:"recipient,omitempty"`
-	Subject           string                 `protobuf:"bytes,4,opt,name=subject,proto3" json:"subject,omitempty"`
-	Message           string                 `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
-	Status            Status                 `protobuf:"varint,6,opt,name=status,proto3,enum=pinguin.Status" json:"status,omitempty"`
-	ProviderMessageId string                 `protobuf:"bytes,7,opt,name=provider_message_id,json=providerMessageId,proto3" json:"provider_message_id,omitempty"`
-	RetryCount        int32                  `protobuf:"varint,8,opt,name=retry_count,json=retryCount,proto3" json:"retry_count,omitempty"`
-	CreatedAt         string                 `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt         string                 `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	ScheduledTime     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=scheduled_time,json=scheduledTime,proto3" json:"scheduled_time,omitempty"`
-	Attachments       []*EmailAttachment     `protobuf:"bytes,12,rep,name=attachments,proto3" json:"attachments,omitempty"`
-	TenantId          string                 `protobuf:"bytes,13,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	NotificationId        string                 `protobuf:"bytes,1,opt,name=notification_id,json=notificationId,proto3" json:"notification_id,omitempty"`
+	NotificationType      NotificationType       `protobuf:"varint,2,opt,name=notification_type,json=notificationType,proto3,enum=pinguin.NotificationType" json:"notification_type,omitempty"`
+	Recipient             string                 `protobuf:"bytes,3,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Subject               string                 `protobuf:"bytes,4,opt,name=subject,proto3" json:"subject,omitempty"`
+	Message               string                 `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	Status                Status                 `protobuf:"varint,6,opt,name=status,proto3,enum=pinguin.Status" json:"status,omitempty"`
+	ProviderMessageId     string                 `protobuf:"bytes,7,opt,name=provider_message_id,json=providerMessageId,proto3" json:"provider_message_id,omitempty"`
+	RetryCount            int32                  `protobuf:"varint,8,opt,name=retry_count,json=retryCount,proto3" json:"retry_count,omitempty"`
+	CreatedAt             string                 `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt             string                 `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	ScheduledTime         *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=scheduled_time,json=scheduledTime,proto3" json:"scheduled_time,omitempty"`
+	Attachments           []*EmailAttachment     `protobuf:"bytes,12,rep,name=attachments,proto3" json:"attachments,omitempty"`
+	TenantId              string                 `protobuf:"bytes,13,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	EstimatedCost         float64                `protobuf:"fixed64,14,opt,name=estimated_cost,json=estimatedCost,proto3" json:"estimated_cost,omitempty"`
+	CostCurrency          string                 `protobuf:"bytes,15,opt,name=cost_currency,json=costCurrency,proto3" json:"cost_currency,omitempty"`
+	ExpiresAt             *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	CancelReason          string                 `protobuf:"bytes,17,opt,name=cancel_reason,json=cancelReason,proto3" json:"cancel_reason,omitempty"`
+	Source                string                 `protobuf:"bytes,18,opt,name=source,proto3" json:"source,omitempty"`                                                               // "system-report" for notifications Pinguin creates itself.
+	Truncated             bool                   `protobuf:"varint,19,opt,name=truncated,proto3" json:"truncated,omitempty"`                                                        // The SMS body was cut to the tenant's segment limit.
+	OriginalMessageLength int32                  `protobuf:"varint,20,opt,name=original_message_length,json=originalMessageLength,proto3" json:"original_message_length,omitempty"` // Characters in the body before truncation.
+	Warnings              []string               `protobuf:"bytes,21,rep,name=warnings,proto3" json:"warnings,omitempty"`                                                           // e.g. "sms.truncated"
+	Rendered              *RenderedContent       `protobuf:"bytes,22,opt,name=rendered,proto3" json:"rendered,omitempty"`                                                           // Set only for include_rendered requests when content was recorded.
+	GroupId               string                 `protobuf:"bytes,23,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`                                              // Set for notifications created by SendNotificationGroup.
+	LastError             string                 `protobuf:"bytes,24,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`                                        // Why retries stopped before the retry limit, e.g. "max age exceeded".
+	CancelledBy           string                 `protobuf:"bytes,25,opt,name=cancelled_by,json=cancelledBy,proto3" json:"cancelled_by,omitempty"`                                  // Session email, "grpc", or "system" for cancellations Pinguin made itself.
+	CancelledAt           *timestamppb.Timestamp `protobuf:"bytes,26,opt,name=cancelled_at,json=cancelledAt,proto3" json:"cancelled_at,omitempty"`
+	Opens                 *NotificationOpens     `protobuf:"bytes,27,opt,name=opens,proto3" json:"opens,omitempty"`                                                // Set only for include_rendered requests on email of tenants that track opens.
+	PendingReason         string                 `protobuf:"bytes,28,opt,name=pending_reason,json=pendingReason,proto3" json:"pending_reason,omitempty"`           // GetNotificationStatus only: "scheduled", "circuit_open", "awaiting_retry", "rate_limited" or "due"; empty once no further attempt will be made.
+	ContentEncrypted      bool                   `protobuf:"varint,29,opt,name=content_encrypted,json=contentEncrypted,proto3" json:"content_encrypted,omitempty"` // The message is caller-encrypted and was sent as is.
+	ReplyTo               string                 `protobuf:"bytes,30,opt,name=reply_to,json=replyTo,proto3" json:"reply_to,omitempty"`                             // Reply-To address the email is sent with, when the request set one.
+	FromDisplayName       string                 `protobuf:"bytes,31,opt,name=from_display_name,json=fromDisplayName,proto3" json:"from_display_name,omitempty"`   // From display name the email is sent with, when the request set one.
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
 }
 
 func (x *NotificationResponse) Reset() {
 	*x = NotificationResponse{}
-	mi := &file_pkg_proto_pinguin_proto_msgTypes[2]
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -313,7 +440,7 @@ func (x *NotificationResponse) String() string {
 func (*NotificationResponse) ProtoMessage() {}
 
 func (x *NotificationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_pinguin_proto_msgTypes[2]
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -326,7 +453,7 @@ func (x *NotificationResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NotificationResponse.ProtoReflect.Descriptor instead.
 func (*NotificationResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{2}
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *NotificationResponse) GetNotificationId() string {
@@ -420,82 +547,158 @@ func (x *NotificationResponse) GetTenantId() string {
 	return ""
 }
 
-// Request for retrieving the status.
-type GetNotificationStatusRequest struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	NotificationId string                 `protobuf:"bytes,1,opt,name=notification_id,json=notificationId,proto3" json:"notification_id,omitempty"`
-	TenantId       string                 `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+func (x *NotificationResponse) GetEstimatedCost() float64 {
+	if x != nil {
+		return x.EstimatedCost
+	}
+	return 0
 }
 
-func (x *GetNotificationStatusRequest) Reset() {
-	*x = GetNotificationStatusRequest{}
-	mi := &file_pkg_proto_pinguin_proto_msgTypes[3]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *NotificationResponse) GetCostCurrency() string {
+	if x != nil {
+		return x.CostCurrency
+	}
+	return ""
 }
 
-func (x *GetNotificationStatusRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *NotificationResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
 }
 
-func (*GetNotificationStatusRequest) ProtoMessage() {}
+func (x *NotificationResponse) GetCancelReason() string {
+	if x != nil {
+		return x.CancelReason
+	}
+	return ""
+}
 
-func (x *GetNotificationStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_pinguin_proto_msgTypes[3]
+func (x *NotificationResponse) GetSource() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Source
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use GetNotificationStatusRequest.ProtoReflect.Descriptor instead.
-func (*GetNotificationStatusRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{3}
+func (x *NotificationResponse) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
 }
 
-func (x *GetNotificationStatusRequest) GetNotificationId() string {
+func (x *NotificationResponse) GetOriginalMessageLength() int32 {
 	if x != nil {
-		return x.NotificationId
+		return x.OriginalMessageLength
+	}
+	return 0
+}
+
+func (x *NotificationResponse) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *NotificationResponse) GetRendered() *RenderedContent {
+	if x != nil {
+		return x.Rendered
+	}
+	return nil
+}
+
+func (x *NotificationResponse) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
 	}
 	return ""
 }
 
-func (x *GetNotificationStatusRequest) GetTenantId() string {
+func (x *NotificationResponse) GetLastError() string {
 	if x != nil {
-		return x.TenantId
+		return x.LastError
 	}
 	return ""
 }
 
-// Request for listing notifications.
-type ListNotificationsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Statuses      []Status               `protobuf:"varint,1,rep,packed,name=statuses,proto3,enum=pinguin.Status" json:"statuses,omitempty"`
-	TenantId      string                 `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *NotificationResponse) GetCancelledBy() string {
+	if x != nil {
+		return x.CancelledBy
+	}
+	return ""
 }
 
-func (x *ListNotificationsRequest) Reset() {
-	*x = ListNotificationsRequest{}
+func (x *NotificationResponse) GetCancelledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CancelledAt
+	}
+	return nil
+}
+
+func (x *NotificationResponse) GetOpens() *NotificationOpens {
+	if x != nil {
+		return x.Opens
+	}
+	return nil
+}
+
+func (x *NotificationResponse) GetPendingReason() string {
+	if x != nil {
+		return x.PendingReason
+	}
+	return ""
+}
+
+func (x *NotificationResponse) GetContentEncrypted() bool {
+	if x != nil {
+		return x.ContentEncrypted
+	}
+	return false
+}
+
+func (x *NotificationResponse) GetReplyTo() string {
+	if x != nil {
+		return x.ReplyTo
+	}
+	return ""
+}
+
+func (x *NotificationResponse) GetFromDisplayName() string {
+	if x != nil {
+		return x.FromDisplayName
+	}
+	return ""
+}
+
+// One channel of a multi-channel send. Empty subject and message fall back to the group's.
+type NotificationChannel struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	NotificationType NotificationType       `protobuf:"varint,1,opt,name=notification_type,json=notificationType,proto3,enum=pinguin.NotificationType" json:"notification_type,omitempty"`
+	Recipient        string                 `protobuf:"bytes,2,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Subject          string                 `protobuf:"bytes,3,opt,name=subject,proto3" json:"subject,omitempty"` // Required for email, here or on the group.
+	Message          string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	Attachments      []*EmailAttachment     `protobuf:"bytes,5,rep,name=attachments,proto3" json:"attachments,omitempty"` // Email only.
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *NotificationChannel) Reset() {
+	*x = NotificationChannel{}
 	mi := &file_pkg_proto_pinguin_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListNotificationsRequest) String() string {
+func (x *NotificationChannel) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListNotificationsRequest) ProtoMessage() {}
+func (*NotificationChannel) ProtoMessage() {}
 
-func (x *ListNotificationsRequest) ProtoReflect() protoreflect.Message {
+func (x *NotificationChannel) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_proto_pinguin_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -507,47 +710,73 @@ func (x *ListNotificationsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListNotificationsRequest.ProtoReflect.Descriptor instead.
-func (*ListNotificationsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use NotificationChannel.ProtoReflect.Descriptor instead.
+func (*NotificationChannel) Descriptor() ([]byte, []int) {
 	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *ListNotificationsRequest) GetStatuses() []Status {
+func (x *NotificationChannel) GetNotificationType() NotificationType {
 	if x != nil {
-		return x.Statuses
+		return x.NotificationType
 	}
-	return nil
+	return NotificationType_EMAIL
 }
 
-func (x *ListNotificationsRequest) GetTenantId() string {
+func (x *NotificationChannel) GetRecipient() string {
 	if x != nil {
-		return x.TenantId
+		return x.Recipient
 	}
 	return ""
 }
 
-// Response containing notifications for list requests.
-type ListNotificationsResponse struct {
-	state         protoimpl.MessageState  `protogen:"open.v1"`
-	Notifications []*NotificationResponse `protobuf:"bytes,1,rep,name=notifications,proto3" json:"notifications,omitempty"`
+func (x *NotificationChannel) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *NotificationChannel) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *NotificationChannel) GetAttachments() []*EmailAttachment {
+	if x != nil {
+		return x.Attachments
+	}
+	return nil
+}
+
+// Request to send one notification per channel, linked by a shared group id.
+type NotificationGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Channels      []*NotificationChannel `protobuf:"bytes,1,rep,name=channels,proto3" json:"channels,omitempty"`
+	Subject       string                 `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"` // Default subject for email channels.
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"` // Default message for every channel.
+	ScheduledTime *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=scheduled_time,json=scheduledTime,proto3" json:"scheduled_time,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // Optional do-not-send-after time.
+	TenantId      string                 `protobuf:"bytes,6,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListNotificationsResponse) Reset() {
-	*x = ListNotificationsResponse{}
+func (x *NotificationGroupRequest) Reset() {
+	*x = NotificationGroupRequest{}
 	mi := &file_pkg_proto_pinguin_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListNotificationsResponse) String() string {
+func (x *NotificationGroupRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListNotificationsResponse) ProtoMessage() {}
+func (*NotificationGroupRequest) ProtoMessage() {}
 
-func (x *ListNotificationsResponse) ProtoReflect() protoreflect.Message {
+func (x *NotificationGroupRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_proto_pinguin_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -559,43 +788,2155 @@ func (x *ListNotificationsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListNotificationsResponse.ProtoReflect.Descriptor instead.
-func (*ListNotificationsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use NotificationGroupRequest.ProtoReflect.Descriptor instead.
+func (*NotificationGroupRequest) Descriptor() ([]byte, []int) {
 	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *ListNotificationsResponse) GetNotifications() []*NotificationResponse {
+func (x *NotificationGroupRequest) GetChannels() []*NotificationChannel {
 	if x != nil {
-		return x.Notifications
+		return x.Channels
 	}
 	return nil
 }
 
-// Request to reschedule a queued notification.
-type RescheduleNotificationRequest struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	NotificationId string                 `protobuf:"bytes,1,opt,name=notification_id,json=notificationId,proto3" json:"notification_id,omitempty"`
-	ScheduledTime  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=scheduled_time,json=scheduledTime,proto3" json:"scheduled_time,omitempty"`
-	TenantId       string                 `protobuf:"bytes,3,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
-}
+func (x *NotificationGroupRequest) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *NotificationGroupRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *NotificationGroupRequest) GetScheduledTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledTime
+	}
+	return nil
+}
+
+func (x *NotificationGroupRequest) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *NotificationGroupRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+// Combined state of a multi-channel send. status is QUEUED while any channel awaits
+// dispatch, ERRORED while any awaits a retry, SENT once every channel finished and one
+// was sent, and CANCELLED when every channel was cancelled.
+type NotificationGroupResponse struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	GroupId       string                  `protobuf:"bytes,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	Status        Status                  `protobuf:"varint,2,opt,name=status,proto3,enum=pinguin.Status" json:"status,omitempty"`
+	Notifications []*NotificationResponse `protobuf:"bytes,3,rep,name=notifications,proto3" json:"notifications,omitempty"` // One per channel, in request order.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NotificationGroupResponse) Reset() {
+	*x = NotificationGroupResponse{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationGroupResponse) ProtoMessage() {}
+
+func (x *NotificationGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationGroupResponse.ProtoReflect.Descriptor instead.
+func (*NotificationGroupResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *NotificationGroupResponse) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *NotificationGroupResponse) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_QUEUED
+}
+
+func (x *NotificationGroupResponse) GetNotifications() []*NotificationResponse {
+	if x != nil {
+		return x.Notifications
+	}
+	return nil
+}
+
+// Request for the combined state of a multi-channel send.
+type GetNotificationGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupId       string                 `protobuf:"bytes,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	TenantId      string                 `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNotificationGroupRequest) Reset() {
+	*x = GetNotificationGroupRequest{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNotificationGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNotificationGroupRequest) ProtoMessage() {}
+
+func (x *GetNotificationGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNotificationGroupRequest.ProtoReflect.Descriptor instead.
+func (*GetNotificationGroupRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetNotificationGroupRequest) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *GetNotificationGroupRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+// The subject and body a notification was last dispatched with.
+type RenderedContent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subject       string                 `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	SameAsInput   bool                   `protobuf:"varint,3,opt,name=same_as_input,json=sameAsInput,proto3" json:"same_as_input,omitempty"`    // Subject and message went out as stored on the notification.
+	MimeStructure string                 `protobuf:"bytes,4,opt,name=mime_structure,json=mimeStructure,proto3" json:"mime_structure,omitempty"` // "plain", "mixed" or "alternative" for email; empty for SMS.
+	SizeBytes     int64                  `protobuf:"varint,5,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`            // Raw email message size, or SMS body size.
+	RenderedAt    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=rendered_at,json=renderedAt,proto3" json:"rendered_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenderedContent) Reset() {
+	*x = RenderedContent{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenderedContent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenderedContent) ProtoMessage() {}
+
+func (x *RenderedContent) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenderedContent.ProtoReflect.Descriptor instead.
+func (*RenderedContent) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RenderedContent) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *RenderedContent) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RenderedContent) GetSameAsInput() bool {
+	if x != nil {
+		return x.SameAsInput
+	}
+	return false
+}
+
+func (x *RenderedContent) GetMimeStructure() string {
+	if x != nil {
+		return x.MimeStructure
+	}
+	return ""
+}
+
+func (x *RenderedContent) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *RenderedContent) GetRenderedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RenderedAt
+	}
+	return nil
+}
+
+// Loads of an email's open tracking pixel.
+type NotificationOpens struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OpenCount     int64                  `protobuf:"varint,1,opt,name=open_count,json=openCount,proto3" json:"open_count,omitempty"`
+	FirstOpenedAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=first_opened_at,json=firstOpenedAt,proto3" json:"first_opened_at,omitempty"` // Unset until the first open.
+	LastOpenedAt  *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=last_opened_at,json=lastOpenedAt,proto3" json:"last_opened_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NotificationOpens) Reset() {
+	*x = NotificationOpens{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationOpens) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationOpens) ProtoMessage() {}
+
+func (x *NotificationOpens) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationOpens.ProtoReflect.Descriptor instead.
+func (*NotificationOpens) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *NotificationOpens) GetOpenCount() int64 {
+	if x != nil {
+		return x.OpenCount
+	}
+	return 0
+}
+
+func (x *NotificationOpens) GetFirstOpenedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FirstOpenedAt
+	}
+	return nil
+}
+
+func (x *NotificationOpens) GetLastOpenedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastOpenedAt
+	}
+	return nil
+}
+
+// Size of the message a send request would hand to its provider. message_size_bytes is
+// the full MIME message for email, with attachments base64-encoded, and the body for SMS.
+type NotificationSizeEstimate struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	MessageSizeBytes int64                  `protobuf:"varint,1,opt,name=message_size_bytes,json=messageSizeBytes,proto3" json:"message_size_bytes,omitempty"`
+	AttachmentBytes  int64                  `protobuf:"varint,2,opt,name=attachment_bytes,json=attachmentBytes,proto3" json:"attachment_bytes,omitempty"` // Attachment size before encoding.
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *NotificationSizeEstimate) Reset() {
+	*x = NotificationSizeEstimate{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationSizeEstimate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationSizeEstimate) ProtoMessage() {}
+
+func (x *NotificationSizeEstimate) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationSizeEstimate.ProtoReflect.Descriptor instead.
+func (*NotificationSizeEstimate) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *NotificationSizeEstimate) GetMessageSizeBytes() int64 {
+	if x != nil {
+		return x.MessageSizeBytes
+	}
+	return 0
+}
+
+func (x *NotificationSizeEstimate) GetAttachmentBytes() int64 {
+	if x != nil {
+		return x.AttachmentBytes
+	}
+	return 0
+}
+
+// Request for retrieving the status.
+type GetNotificationStatusRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	NotificationId  string                 `protobuf:"bytes,1,opt,name=notification_id,json=notificationId,proto3" json:"notification_id,omitempty"`
+	TenantId        string                 `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	IncludeRendered bool                   `protobuf:"varint,3,opt,name=include_rendered,json=includeRendered,proto3" json:"include_rendered,omitempty"` // Also return the content the notification was dispatched with.
+	Fields          []string               `protobuf:"bytes,4,rep,name=fields,proto3" json:"fields,omitempty"`                                           // NotificationResponse field names to return, e.g. "notification_id"; empty returns all.
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetNotificationStatusRequest) Reset() {
+	*x = GetNotificationStatusRequest{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNotificationStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNotificationStatusRequest) ProtoMessage() {}
+
+func (x *GetNotificationStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNotificationStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetNotificationStatusRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetNotificationStatusRequest) GetNotificationId() string {
+	if x != nil {
+		return x.NotificationId
+	}
+	return ""
+}
+
+func (x *GetNotificationStatusRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *GetNotificationStatusRequest) GetIncludeRendered() bool {
+	if x != nil {
+		return x.IncludeRendered
+	}
+	return false
+}
+
+func (x *GetNotificationStatusRequest) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+// Request for listing notifications.
+type ListNotificationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Statuses      []Status               `protobuf:"varint,1,rep,packed,name=statuses,proto3,enum=pinguin.Status" json:"statuses,omitempty"`
+	TenantId      string                 `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Fields        []string               `protobuf:"bytes,3,rep,name=fields,proto3" json:"fields,omitempty"` // NotificationResponse field names to return, e.g. "notification_id"; empty returns all.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNotificationsRequest) Reset() {
+	*x = ListNotificationsRequest{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNotificationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNotificationsRequest) ProtoMessage() {}
+
+func (x *ListNotificationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNotificationsRequest.ProtoReflect.Descriptor instead.
+func (*ListNotificationsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListNotificationsRequest) GetStatuses() []Status {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *ListNotificationsRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *ListNotificationsRequest) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+// Response containing notifications for list requests.
+type ListNotificationsResponse struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Notifications []*NotificationResponse `protobuf:"bytes,1,rep,name=notifications,proto3" json:"notifications,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNotificationsResponse) Reset() {
+	*x = ListNotificationsResponse{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNotificationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNotificationsResponse) ProtoMessage() {}
+
+func (x *ListNotificationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNotificationsResponse.ProtoReflect.Descriptor instead.
+func (*ListNotificationsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListNotificationsResponse) GetNotifications() []*NotificationResponse {
+	if x != nil {
+		return x.Notifications
+	}
+	return nil
+}
+
+// Request to reschedule a queued notification.
+type RescheduleNotificationRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	NotificationId string                 `protobuf:"bytes,1,opt,name=notification_id,json=notificationId,proto3" json:"notification_id,omitempty"`
+	ScheduledTime  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=scheduled_time,json=scheduledTime,proto3" json:"scheduled_time,omitempty"`
+	TenantId       string                 `protobuf:"bytes,3,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RescheduleNotificationRequest) Reset() {
+	*x = RescheduleNotificationRequest{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RescheduleNotificationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RescheduleNotificationRequest) ProtoMessage() {}
+
+func (x *RescheduleNotificationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RescheduleNotificationRequest.ProtoReflect.Descriptor instead.
+func (*RescheduleNotificationRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RescheduleNotificationRequest) GetNotificationId() string {
+	if x != nil {
+		return x.NotificationId
+	}
+	return ""
+}
+
+func (x *RescheduleNotificationRequest) GetScheduledTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledTime
+	}
+	return nil
+}
+
+func (x *RescheduleNotificationRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+// Request to cancel a queued notification.
+type CancelNotificationRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	NotificationId string                 `protobuf:"bytes,1,opt,name=notification_id,json=notificationId,proto3" json:"notification_id,omitempty"`
+	TenantId       string                 `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Reason         string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"` // Optional; at most 200 characters. Returned as cancel_reason.
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CancelNotificationRequest) Reset() {
+	*x = CancelNotificationRequest{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelNotificationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelNotificationRequest) ProtoMessage() {}
+
+func (x *CancelNotificationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelNotificationRequest.ProtoReflect.Descriptor instead.
+func (*CancelNotificationRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CancelNotificationRequest) GetNotificationId() string {
+	if x != nil {
+		return x.NotificationId
+	}
+	return ""
+}
+
+func (x *CancelNotificationRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *CancelNotificationRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// Request for aggregated notification spend over [start_time, end_time).
+type CostSummaryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	TenantId      string                 `protobuf:"bytes,3,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CostSummaryRequest) Reset() {
+	*x = CostSummaryRequest{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CostSummaryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CostSummaryRequest) ProtoMessage() {}
+
+func (x *CostSummaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CostSummaryRequest.ProtoReflect.Descriptor instead.
+func (*CostSummaryRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CostSummaryRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *CostSummaryRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *CostSummaryRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+// Estimated spend for one UTC day and notification channel.
+type CostSummaryBucket struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Day               string                 `protobuf:"bytes,1,opt,name=day,proto3" json:"day,omitempty"`
+	NotificationType  NotificationType       `protobuf:"varint,2,opt,name=notification_type,json=notificationType,proto3,enum=pinguin.NotificationType" json:"notification_type,omitempty"`
+	NotificationCount int32                  `protobuf:"varint,3,opt,name=notification_count,json=notificationCount,proto3" json:"notification_count,omitempty"`
+	EstimatedCost     float64                `protobuf:"fixed64,4,opt,name=estimated_cost,json=estimatedCost,proto3" json:"estimated_cost,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CostSummaryBucket) Reset() {
+	*x = CostSummaryBucket{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CostSummaryBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CostSummaryBucket) ProtoMessage() {}
+
+func (x *CostSummaryBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CostSummaryBucket.ProtoReflect.Descriptor instead.
+func (*CostSummaryBucket) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CostSummaryBucket) GetDay() string {
+	if x != nil {
+		return x.Day
+	}
+	return ""
+}
+
+func (x *CostSummaryBucket) GetNotificationType() NotificationType {
+	if x != nil {
+		return x.NotificationType
+	}
+	return NotificationType_EMAIL
+}
+
+func (x *CostSummaryBucket) GetNotificationCount() int32 {
+	if x != nil {
+		return x.NotificationCount
+	}
+	return 0
+}
+
+func (x *CostSummaryBucket) GetEstimatedCost() float64 {
+	if x != nil {
+		return x.EstimatedCost
+	}
+	return 0
+}
+
+// Aggregated estimated spend for a tenant.
+type CostSummaryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Currency      string                 `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	TotalCost     float64                `protobuf:"fixed64,2,opt,name=total_cost,json=totalCost,proto3" json:"total_cost,omitempty"`
+	Buckets       []*CostSummaryBucket   `protobuf:"bytes,3,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CostSummaryResponse) Reset() {
+	*x = CostSummaryResponse{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CostSummaryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CostSummaryResponse) ProtoMessage() {}
+
+func (x *CostSummaryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CostSummaryResponse.ProtoReflect.Descriptor instead.
+func (*CostSummaryResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CostSummaryResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CostSummaryResponse) GetTotalCost() float64 {
+	if x != nil {
+		return x.TotalCost
+	}
+	return 0
+}
+
+func (x *CostSummaryResponse) GetBuckets() []*CostSummaryBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+// Request for the channels and limits available to a tenant.
+type GetCapabilitiesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TenantId      string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCapabilitiesRequest) Reset() {
+	*x = GetCapabilitiesRequest{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCapabilitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCapabilitiesRequest) ProtoMessage() {}
+
+func (x *GetCapabilitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCapabilitiesRequest.ProtoReflect.Descriptor instead.
+func (*GetCapabilitiesRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetCapabilitiesRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+// Channels and limits available to a tenant. A zero max_schedule_horizon_seconds
+// means notifications may be scheduled arbitrarily far ahead.
+type CapabilitiesResponse struct {
+	state                     protoimpl.MessageState `protogen:"open.v1"`
+	NotificationTypes         []NotificationType     `protobuf:"varint,1,rep,packed,name=notification_types,json=notificationTypes,proto3,enum=pinguin.NotificationType" json:"notification_types,omitempty"`
+	MaxAttachmentCount        int32                  `protobuf:"varint,2,opt,name=max_attachment_count,json=maxAttachmentCount,proto3" json:"max_attachment_count,omitempty"`
+	MaxAttachmentSizeBytes    int64                  `protobuf:"varint,3,opt,name=max_attachment_size_bytes,json=maxAttachmentSizeBytes,proto3" json:"max_attachment_size_bytes,omitempty"`
+	MaxAttachmentsTotalBytes  int64                  `protobuf:"varint,4,opt,name=max_attachments_total_bytes,json=maxAttachmentsTotalBytes,proto3" json:"max_attachments_total_bytes,omitempty"`
+	AttachmentDataPersisted   bool                   `protobuf:"varint,5,opt,name=attachment_data_persisted,json=attachmentDataPersisted,proto3" json:"attachment_data_persisted,omitempty"`
+	MaxScheduleHorizonSeconds int64                  `protobuf:"varint,6,opt,name=max_schedule_horizon_seconds,json=maxScheduleHorizonSeconds,proto3" json:"max_schedule_horizon_seconds,omitempty"`
+	unknownFields             protoimpl.UnknownFields
+	sizeCache                 protoimpl.SizeCache
+}
+
+func (x *CapabilitiesResponse) Reset() {
+	*x = CapabilitiesResponse{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CapabilitiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CapabilitiesResponse) ProtoMessage() {}
+
+func (x *CapabilitiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CapabilitiesResponse.ProtoReflect.Descriptor instead.
+func (*CapabilitiesResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CapabilitiesResponse) GetNotificationTypes() []NotificationType {
+	if x != nil {
+		return x.NotificationTypes
+	}
+	return nil
+}
+
+func (x *CapabilitiesResponse) GetMaxAttachmentCount() int32 {
+	if x != nil {
+		return x.MaxAttachmentCount
+	}
+	return 0
+}
+
+func (x *CapabilitiesResponse) GetMaxAttachmentSizeBytes() int64 {
+	if x != nil {
+		return x.MaxAttachmentSizeBytes
+	}
+	return 0
+}
+
+func (x *CapabilitiesResponse) GetMaxAttachmentsTotalBytes() int64 {
+	if x != nil {
+		return x.MaxAttachmentsTotalBytes
+	}
+	return 0
+}
+
+func (x *CapabilitiesResponse) GetAttachmentDataPersisted() bool {
+	if x != nil {
+		return x.AttachmentDataPersisted
+	}
+	return false
+}
+
+func (x *CapabilitiesResponse) GetMaxScheduleHorizonSeconds() int64 {
+	if x != nil {
+		return x.MaxScheduleHorizonSeconds
+	}
+	return 0
+}
+
+// Request to check a tenant's provider credentials without sending a message.
+type TestTenantDeliveryRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	TenantId         string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	NotificationType NotificationType       `protobuf:"varint,2,opt,name=notification_type,json=notificationType,proto3,enum=pinguin.NotificationType" json:"notification_type,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *TestTenantDeliveryRequest) Reset() {
+	*x = TestTenantDeliveryRequest{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestTenantDeliveryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestTenantDeliveryRequest) ProtoMessage() {}
+
+func (x *TestTenantDeliveryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestTenantDeliveryRequest.ProtoReflect.Descriptor instead.
+func (*TestTenantDeliveryRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *TestTenantDeliveryRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *TestTenantDeliveryRequest) GetNotificationType() NotificationType {
+	if x != nil {
+		return x.NotificationType
+	}
+	return NotificationType_EMAIL
+}
+
+// Outcome of a provider credential check; error explains a failed check.
+type TestTenantDeliveryResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	NotificationType NotificationType       `protobuf:"varint,1,opt,name=notification_type,json=notificationType,proto3,enum=pinguin.NotificationType" json:"notification_type,omitempty"`
+	Success          bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Error            string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *TestTenantDeliveryResponse) Reset() {
+	*x = TestTenantDeliveryResponse{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestTenantDeliveryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestTenantDeliveryResponse) ProtoMessage() {}
+
+func (x *TestTenantDeliveryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestTenantDeliveryResponse.ProtoReflect.Descriptor instead.
+func (*TestTenantDeliveryResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *TestTenantDeliveryResponse) GetNotificationType() NotificationType {
+	if x != nil {
+		return x.NotificationType
+	}
+	return NotificationType_EMAIL
+}
+
+func (x *TestTenantDeliveryResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *TestTenantDeliveryResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// Request for the cross-tenant status view; requires an admin-scoped token.
+type ListTenantsStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTenantsStatusRequest) Reset() {
+	*x = ListTenantsStatusRequest{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTenantsStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTenantsStatusRequest) ProtoMessage() {}
+
+func (x *ListTenantsStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTenantsStatusRequest.ProtoReflect.Descriptor instead.
+func (*ListTenantsStatusRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{23}
+}
+
+// Call latency of one provider for a tenant, measured by the serving process.
+type ProviderLatency struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      NotificationType       `protobuf:"varint,1,opt,name=provider,proto3,enum=pinguin.NotificationType" json:"provider,omitempty"`
+	Dispatches    int64                  `protobuf:"varint,2,opt,name=dispatches,proto3" json:"dispatches,omitempty"`
+	AverageMs     int64                  `protobuf:"varint,3,opt,name=average_ms,json=averageMs,proto3" json:"average_ms,omitempty"`
+	MaxMs         int64                  `protobuf:"varint,4,opt,name=max_ms,json=maxMs,proto3" json:"max_ms,omitempty"`
+	LastMs        int64                  `protobuf:"varint,5,opt,name=last_ms,json=lastMs,proto3" json:"last_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProviderLatency) Reset() {
+	*x = ProviderLatency{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProviderLatency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProviderLatency) ProtoMessage() {}
+
+func (x *ProviderLatency) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProviderLatency.ProtoReflect.Descriptor instead.
+func (*ProviderLatency) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ProviderLatency) GetProvider() NotificationType {
+	if x != nil {
+		return x.Provider
+	}
+	return NotificationType_EMAIL
+}
+
+func (x *ProviderLatency) GetDispatches() int64 {
+	if x != nil {
+		return x.Dispatches
+	}
+	return 0
+}
+
+func (x *ProviderLatency) GetAverageMs() int64 {
+	if x != nil {
+		return x.AverageMs
+	}
+	return 0
+}
+
+func (x *ProviderLatency) GetMaxMs() int64 {
+	if x != nil {
+		return x.MaxMs
+	}
+	return 0
+}
+
+func (x *ProviderLatency) GetLastMs() int64 {
+	if x != nil {
+		return x.LastMs
+	}
+	return 0
+}
+
+// Observations at or below upper_bound, like a Prometheus cumulative bucket.
+type HistogramBucket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UpperBound    int64                  `protobuf:"varint,1,opt,name=upper_bound,json=upperBound,proto3" json:"upper_bound,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistogramBucket) Reset() {
+	*x = HistogramBucket{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistogramBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistogramBucket) ProtoMessage() {}
+
+func (x *HistogramBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistogramBucket.ProtoReflect.Descriptor instead.
+func (*HistogramBucket) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *HistogramBucket) GetUpperBound() int64 {
+	if x != nil {
+		return x.UpperBound
+	}
+	return 0
+}
+
+func (x *HistogramBucket) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// Attachments carried by a tenant's stored sends, measured by the serving process.
+// Only counts and byte sizes are kept.
+type AttachmentSizes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sends         int64                  `protobuf:"varint,1,opt,name=sends,proto3" json:"sends,omitempty"` // Sends with at least one attachment.
+	Attachments   int64                  `protobuf:"varint,2,opt,name=attachments,proto3" json:"attachments,omitempty"`
+	TotalBytes    int64                  `protobuf:"varint,3,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"`
+	MaxBytes      int64                  `protobuf:"varint,4,opt,name=max_bytes,json=maxBytes,proto3" json:"max_bytes,omitempty"`
+	SizeBuckets   []*HistogramBucket     `protobuf:"bytes,5,rep,name=size_buckets,json=sizeBuckets,proto3" json:"size_buckets,omitempty"`    // Bytes per attachment.
+	CountBuckets  []*HistogramBucket     `protobuf:"bytes,6,rep,name=count_buckets,json=countBuckets,proto3" json:"count_buckets,omitempty"` // Attachments per send.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttachmentSizes) Reset() {
+	*x = AttachmentSizes{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttachmentSizes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttachmentSizes) ProtoMessage() {}
+
+func (x *AttachmentSizes) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttachmentSizes.ProtoReflect.Descriptor instead.
+func (*AttachmentSizes) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *AttachmentSizes) GetSends() int64 {
+	if x != nil {
+		return x.Sends
+	}
+	return 0
+}
+
+func (x *AttachmentSizes) GetAttachments() int64 {
+	if x != nil {
+		return x.Attachments
+	}
+	return 0
+}
+
+func (x *AttachmentSizes) GetTotalBytes() int64 {
+	if x != nil {
+		return x.TotalBytes
+	}
+	return 0
+}
+
+func (x *AttachmentSizes) GetMaxBytes() int64 {
+	if x != nil {
+		return x.MaxBytes
+	}
+	return 0
+}
+
+func (x *AttachmentSizes) GetSizeBuckets() []*HistogramBucket {
+	if x != nil {
+		return x.SizeBuckets
+	}
+	return nil
+}
+
+func (x *AttachmentSizes) GetCountBuckets() []*HistogramBucket {
+	if x != nil {
+		return x.CountBuckets
+	}
+	return nil
+}
+
+// A tenant channel paused because its provider rejected the tenant's account.
+type ProviderBreaker struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Provider       NotificationType       `protobuf:"varint,1,opt,name=provider,proto3,enum=pinguin.NotificationType" json:"provider,omitempty"`
+	Reason         string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	OpenedAt       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=opened_at,json=openedAt,proto3" json:"opened_at,omitempty"`
+	LastProbeAt    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=last_probe_at,json=lastProbeAt,proto3" json:"last_probe_at,omitempty"` // Unset until the first canary dispatch.
+	NextProbeAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=next_probe_at,json=nextProbeAt,proto3" json:"next_probe_at,omitempty"`
+	AdminsNotified bool                   `protobuf:"varint,6,opt,name=admins_notified,json=adminsNotified,proto3" json:"admins_notified,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ProviderBreaker) Reset() {
+	*x = ProviderBreaker{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProviderBreaker) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProviderBreaker) ProtoMessage() {}
+
+func (x *ProviderBreaker) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProviderBreaker.ProtoReflect.Descriptor instead.
+func (*ProviderBreaker) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ProviderBreaker) GetProvider() NotificationType {
+	if x != nil {
+		return x.Provider
+	}
+	return NotificationType_EMAIL
+}
+
+func (x *ProviderBreaker) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ProviderBreaker) GetOpenedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OpenedAt
+	}
+	return nil
+}
+
+func (x *ProviderBreaker) GetLastProbeAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastProbeAt
+	}
+	return nil
+}
+
+func (x *ProviderBreaker) GetNextProbeAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextProbeAt
+	}
+	return nil
+}
+
+func (x *ProviderBreaker) GetAdminsNotified() bool {
+	if x != nil {
+		return x.AdminsNotified
+	}
+	return false
+}
+
+// Findings of the latest attachment integrity sweep for one tenant.
+type AttachmentIntegrity struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	CheckedAt                *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=checked_at,json=checkedAt,proto3" json:"checked_at,omitempty"`
+	OrphanedAttachments      int64                  `protobuf:"varint,2,opt,name=orphaned_attachments,json=orphanedAttachments,proto3" json:"orphaned_attachments,omitempty"`
+	AttachmentSizeMismatches int64                  `protobuf:"varint,3,opt,name=attachment_size_mismatches,json=attachmentSizeMismatches,proto3" json:"attachment_size_mismatches,omitempty"`
+	DuplicateNotificationIds int64                  `protobuf:"varint,4,opt,name=duplicate_notification_ids,json=duplicateNotificationIds,proto3" json:"duplicate_notification_ids,omitempty"`
+	RepairedOrphans          int64                  `protobuf:"varint,5,opt,name=repaired_orphans,json=repairedOrphans,proto3" json:"repaired_orphans,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *AttachmentIntegrity) Reset() {
+	*x = AttachmentIntegrity{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttachmentIntegrity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttachmentIntegrity) ProtoMessage() {}
+
+func (x *AttachmentIntegrity) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttachmentIntegrity.ProtoReflect.Descriptor instead.
+func (*AttachmentIntegrity) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *AttachmentIntegrity) GetCheckedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CheckedAt
+	}
+	return nil
+}
+
+func (x *AttachmentIntegrity) GetOrphanedAttachments() int64 {
+	if x != nil {
+		return x.OrphanedAttachments
+	}
+	return 0
+}
+
+func (x *AttachmentIntegrity) GetAttachmentSizeMismatches() int64 {
+	if x != nil {
+		return x.AttachmentSizeMismatches
+	}
+	return 0
+}
+
+func (x *AttachmentIntegrity) GetDuplicateNotificationIds() int64 {
+	if x != nil {
+		return x.DuplicateNotificationIds
+	}
+	return 0
+}
+
+func (x *AttachmentIntegrity) GetRepairedOrphans() int64 {
+	if x != nil {
+		return x.RepairedOrphans
+	}
+	return 0
+}
+
+// Operational status of one tenant. Only presence flags are reported for
+// credentials; secrets never leave the server.
+type TenantStatus struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	TenantId                string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	DisplayName             string                 `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Status                  string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	DomainCount             int32                  `protobuf:"varint,4,opt,name=domain_count,json=domainCount,proto3" json:"domain_count,omitempty"`
+	EmailProfileConfigured  bool                   `protobuf:"varint,5,opt,name=email_profile_configured,json=emailProfileConfigured,proto3" json:"email_profile_configured,omitempty"`
+	SmsProfileConfigured    bool                   `protobuf:"varint,6,opt,name=sms_profile_configured,json=smsProfileConfigured,proto3" json:"sms_profile_configured,omitempty"`
+	EmailSenderCached       bool                   `protobuf:"varint,7,opt,name=email_sender_cached,json=emailSenderCached,proto3" json:"email_sender_cached,omitempty"`
+	SmsSenderCached         bool                   `protobuf:"varint,8,opt,name=sms_sender_cached,json=smsSenderCached,proto3" json:"sms_sender_cached,omitempty"`
+	QueuedCount             int64                  `protobuf:"varint,9,opt,name=queued_count,json=queuedCount,proto3" json:"queued_count,omitempty"`
+	ErroredCount            int64                  `protobuf:"varint,10,opt,name=errored_count,json=erroredCount,proto3" json:"errored_count,omitempty"`
+	LastDispatchedAt        *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=last_dispatched_at,json=lastDispatchedAt,proto3" json:"last_dispatched_at,omitempty"`                         // Unset when nothing was sent yet.
+	ProviderLatencies       []*ProviderLatency     `protobuf:"bytes,12,rep,name=provider_latencies,json=providerLatencies,proto3" json:"provider_latencies,omitempty"`                        // Since the serving process started.
+	AttachmentIntegrity     *AttachmentIntegrity   `protobuf:"bytes,13,opt,name=attachment_integrity,json=attachmentIntegrity,proto3" json:"attachment_integrity,omitempty"`                  // Unset until the integrity sweep has run.
+	ProviderBreakers        []*ProviderBreaker     `protobuf:"bytes,14,rep,name=provider_breakers,json=providerBreakers,proto3" json:"provider_breakers,omitempty"`                           // Open breakers only.
+	AttachmentSizes         *AttachmentSizes       `protobuf:"bytes,15,opt,name=attachment_sizes,json=attachmentSizes,proto3" json:"attachment_sizes,omitempty"`                              // Since the serving process started; unset until a send carried attachments.
+	OldestPendingAgeSeconds int64                  `protobuf:"varint,16,opt,name=oldest_pending_age_seconds,json=oldestPendingAgeSeconds,proto3" json:"oldest_pending_age_seconds,omitempty"` // Wait of the longest-waiting due queued notification; 0 when none is due.
+	PendingAgeExceeded      bool                   `protobuf:"varint,17,opt,name=pending_age_exceeded,json=pendingAgeExceeded,proto3" json:"pending_age_exceeded,omitempty"`                  // oldest_pending_age_seconds is above server.maxPendingAgeSec.
+	OpenedCount             int64                  `protobuf:"varint,18,opt,name=opened_count,json=openedCount,proto3" json:"opened_count,omitempty"`                                         // Notifications opened at least once; 0 for tenants that do not track opens.
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *TenantStatus) Reset() {
+	*x = TenantStatus{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TenantStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TenantStatus) ProtoMessage() {}
+
+func (x *TenantStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TenantStatus.ProtoReflect.Descriptor instead.
+func (*TenantStatus) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *TenantStatus) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *TenantStatus) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *TenantStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TenantStatus) GetDomainCount() int32 {
+	if x != nil {
+		return x.DomainCount
+	}
+	return 0
+}
+
+func (x *TenantStatus) GetEmailProfileConfigured() bool {
+	if x != nil {
+		return x.EmailProfileConfigured
+	}
+	return false
+}
+
+func (x *TenantStatus) GetSmsProfileConfigured() bool {
+	if x != nil {
+		return x.SmsProfileConfigured
+	}
+	return false
+}
+
+func (x *TenantStatus) GetEmailSenderCached() bool {
+	if x != nil {
+		return x.EmailSenderCached
+	}
+	return false
+}
+
+func (x *TenantStatus) GetSmsSenderCached() bool {
+	if x != nil {
+		return x.SmsSenderCached
+	}
+	return false
+}
+
+func (x *TenantStatus) GetQueuedCount() int64 {
+	if x != nil {
+		return x.QueuedCount
+	}
+	return 0
+}
+
+func (x *TenantStatus) GetErroredCount() int64 {
+	if x != nil {
+		return x.ErroredCount
+	}
+	return 0
+}
+
+func (x *TenantStatus) GetLastDispatchedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastDispatchedAt
+	}
+	return nil
+}
+
+func (x *TenantStatus) GetProviderLatencies() []*ProviderLatency {
+	if x != nil {
+		return x.ProviderLatencies
+	}
+	return nil
+}
+
+func (x *TenantStatus) GetAttachmentIntegrity() *AttachmentIntegrity {
+	if x != nil {
+		return x.AttachmentIntegrity
+	}
+	return nil
+}
+
+func (x *TenantStatus) GetProviderBreakers() []*ProviderBreaker {
+	if x != nil {
+		return x.ProviderBreakers
+	}
+	return nil
+}
+
+func (x *TenantStatus) GetAttachmentSizes() *AttachmentSizes {
+	if x != nil {
+		return x.AttachmentSizes
+	}
+	return nil
+}
+
+func (x *TenantStatus) GetOldestPendingAgeSeconds() int64 {
+	if x != nil {
+		return x.OldestPendingAgeSeconds
+	}
+	return 0
+}
+
+func (x *TenantStatus) GetPendingAgeExceeded() bool {
+	if x != nil {
+		return x.PendingAgeExceeded
+	}
+	return false
+}
+
+func (x *TenantStatus) GetOpenedCount() int64 {
+	if x != nil {
+		return x.OpenedCount
+	}
+	return 0
+}
+
+// Counts of the message queue consumer since the serving process started.
+type QueueIntakeStats struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Received       int64                  `protobuf:"varint,1,opt,name=received,proto3" json:"received,omitempty"`
+	Accepted       int64                  `protobuf:"varint,2,opt,name=accepted,proto3" json:"accepted,omitempty"`     // Stored as notifications.
+	Duplicates     int64                  `protobuf:"varint,3,opt,name=duplicates,proto3" json:"duplicates,omitempty"` // Acknowledged without a send because the idempotency key was known.
+	DeadLettered   int64                  `protobuf:"varint,4,opt,name=dead_lettered,json=deadLettered,proto3" json:"dead_lettered,omitempty"`
+	Requeued       int64                  `protobuf:"varint,5,opt,name=requeued,proto3" json:"requeued,omitempty"`
+	LastReceivedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_received_at,json=lastReceivedAt,proto3" json:"last_received_at,omitempty"` // Unset until the first message arrives.
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *QueueIntakeStats) Reset() {
+	*x = QueueIntakeStats{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueueIntakeStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueIntakeStats) ProtoMessage() {}
+
+func (x *QueueIntakeStats) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueIntakeStats.ProtoReflect.Descriptor instead.
+func (*QueueIntakeStats) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *QueueIntakeStats) GetReceived() int64 {
+	if x != nil {
+		return x.Received
+	}
+	return 0
+}
+
+func (x *QueueIntakeStats) GetAccepted() int64 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
+
+func (x *QueueIntakeStats) GetDuplicates() int64 {
+	if x != nil {
+		return x.Duplicates
+	}
+	return 0
+}
+
+func (x *QueueIntakeStats) GetDeadLettered() int64 {
+	if x != nil {
+		return x.DeadLettered
+	}
+	return 0
+}
+
+func (x *QueueIntakeStats) GetRequeued() int64 {
+	if x != nil {
+		return x.Requeued
+	}
+	return 0
+}
+
+func (x *QueueIntakeStats) GetLastReceivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastReceivedAt
+	}
+	return nil
+}
+
+// Status of every tenant, suspended ones included.
+type ListTenantsStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tenants       []*TenantStatus        `protobuf:"bytes,1,rep,name=tenants,proto3" json:"tenants,omitempty"`
+	QueueIntake   *QueueIntakeStats      `protobuf:"bytes,2,opt,name=queue_intake,json=queueIntake,proto3" json:"queue_intake,omitempty"` // Unset when the queue consumer is disabled.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTenantsStatusResponse) Reset() {
+	*x = ListTenantsStatusResponse{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTenantsStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTenantsStatusResponse) ProtoMessage() {}
+
+func (x *ListTenantsStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTenantsStatusResponse.ProtoReflect.Descriptor instead.
+func (*ListTenantsStatusResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListTenantsStatusResponse) GetTenants() []*TenantStatus {
+	if x != nil {
+		return x.Tenants
+	}
+	return nil
+}
+
+func (x *ListTenantsStatusResponse) GetQueueIntake() *QueueIntakeStats {
+	if x != nil {
+		return x.QueueIntake
+	}
+	return nil
+}
+
+// Starts draining the serving instance; requires an admin-scoped token.
+type DrainInstanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DrainInstanceRequest) Reset() {
+	*x = DrainInstanceRequest{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DrainInstanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrainInstanceRequest) ProtoMessage() {}
+
+func (x *DrainInstanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrainInstanceRequest.ProtoReflect.Descriptor instead.
+func (*DrainInstanceRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{32}
+}
+
+// Reports drain progress; requires an admin-scoped token.
+type GetDrainStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDrainStatusRequest) Reset() {
+	*x = GetDrainStatusRequest{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDrainStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDrainStatusRequest) ProtoMessage() {}
+
+func (x *GetDrainStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDrainStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetDrainStatusRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{33}
+}
+
+// Drain progress of the serving instance. All fields are unset until a drain starts.
+type DrainStatus struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Draining        bool                   `protobuf:"varint,1,opt,name=draining,proto3" json:"draining,omitempty"`
+	Drained         bool                   `protobuf:"varint,2,opt,name=drained,proto3" json:"drained,omitempty"` // The queues emptied or the deadline passed; the process is exiting.
+	StartedAt       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	Deadline        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	RemainingQueued int64                  `protobuf:"varint,5,opt,name=remaining_queued,json=remainingQueued,proto3" json:"remaining_queued,omitempty"` // Due queued and errored notifications still awaiting dispatch.
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DrainStatus) Reset() {
+	*x = DrainStatus{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DrainStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrainStatus) ProtoMessage() {}
+
+func (x *DrainStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrainStatus.ProtoReflect.Descriptor instead.
+func (*DrainStatus) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *DrainStatus) GetDraining() bool {
+	if x != nil {
+		return x.Draining
+	}
+	return false
+}
+
+func (x *DrainStatus) GetDrained() bool {
+	if x != nil {
+		return x.Drained
+	}
+	return false
+}
+
+func (x *DrainStatus) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *DrainStatus) GetDeadline() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Deadline
+	}
+	return nil
+}
+
+func (x *DrainStatus) GetRemainingQueued() int64 {
+	if x != nil {
+		return x.RemainingQueued
+	}
+	return 0
+}
+
+// Reports the serving process's tenant and sender caches; requires an admin-scoped token.
+type ListCachesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCachesRequest) Reset() {
+	*x = ListCachesRequest{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCachesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCachesRequest) ProtoMessage() {}
+
+func (x *ListCachesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCachesRequest.ProtoReflect.Descriptor instead.
+func (*ListCachesRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{35}
+}
+
+// Empties the serving process's tenant and sender caches; requires an admin-scoped token.
+type FlushCachesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FlushCachesRequest) Reset() {
+	*x = FlushCachesRequest{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FlushCachesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlushCachesRequest) ProtoMessage() {}
+
+func (x *FlushCachesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlushCachesRequest.ProtoReflect.Descriptor instead.
+func (*FlushCachesRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{36}
+}
+
+// One in-process cache: tenant_runtime, tenant_domain, email_sender or sms_sender.
+// Hits and misses count since the process started and survive a flush.
+type CacheState struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Entries       int64                  `protobuf:"varint,2,opt,name=entries,proto3" json:"entries,omitempty"`
+	Hits          uint64                 `protobuf:"varint,3,opt,name=hits,proto3" json:"hits,omitempty"`
+	Misses        uint64                 `protobuf:"varint,4,opt,name=misses,proto3" json:"misses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CacheState) Reset() {
+	*x = CacheState{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CacheState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CacheState) ProtoMessage() {}
+
+func (x *CacheState) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CacheState.ProtoReflect.Descriptor instead.
+func (*CacheState) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *CacheState) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CacheState) GetEntries() int64 {
+	if x != nil {
+		return x.Entries
+	}
+	return 0
+}
+
+func (x *CacheState) GetHits() uint64 {
+	if x != nil {
+		return x.Hits
+	}
+	return 0
+}
+
+func (x *CacheState) GetMisses() uint64 {
+	if x != nil {
+		return x.Misses
+	}
+	return 0
+}
 
-func (x *RescheduleNotificationRequest) Reset() {
-	*x = RescheduleNotificationRequest{}
-	mi := &file_pkg_proto_pinguin_proto_msgTypes[6]
+// FlushCaches reports the caches as they were just before the flush.
+type ListCachesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Caches        []*CacheState          `protobuf:"bytes,1,rep,name=caches,proto3" json:"caches,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCachesResponse) Reset() {
+	*x = ListCachesResponse{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RescheduleNotificationRequest) String() string {
+func (x *ListCachesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RescheduleNotificationRequest) ProtoMessage() {}
+func (*ListCachesResponse) ProtoMessage() {}
 
-func (x *RescheduleNotificationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_pinguin_proto_msgTypes[6]
+func (x *ListCachesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -606,56 +2947,166 @@ func (x *RescheduleNotificationRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RescheduleNotificationRequest.ProtoReflect.Descriptor instead.
-func (*RescheduleNotificationRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use ListCachesResponse.ProtoReflect.Descriptor instead.
+func (*ListCachesResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{38}
 }
 
-func (x *RescheduleNotificationRequest) GetNotificationId() string {
+func (x *ListCachesResponse) GetCaches() []*CacheState {
 	if x != nil {
-		return x.NotificationId
+		return x.Caches
+	}
+	return nil
+}
+
+// Moves notifications from one tenant to another for an org migration; requires an
+// admin-scoped token. Notifications awaiting dispatch stay unless include_queued is set
+// and the destination tenant can send them.
+type TransferNotificationsRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	SourceTenantId      string                 `protobuf:"bytes,1,opt,name=source_tenant_id,json=sourceTenantId,proto3" json:"source_tenant_id,omitempty"`
+	DestinationTenantId string                 `protobuf:"bytes,2,opt,name=destination_tenant_id,json=destinationTenantId,proto3" json:"destination_tenant_id,omitempty"`
+	Statuses            []Status               `protobuf:"varint,3,rep,packed,name=statuses,proto3,enum=pinguin.Status" json:"statuses,omitempty"` // Empty moves every status.
+	IncludeQueued       bool                   `protobuf:"varint,4,opt,name=include_queued,json=includeQueued,proto3" json:"include_queued,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *TransferNotificationsRequest) Reset() {
+	*x = TransferNotificationsRequest{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferNotificationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferNotificationsRequest) ProtoMessage() {}
+
+func (x *TransferNotificationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferNotificationsRequest.ProtoReflect.Descriptor instead.
+func (*TransferNotificationsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *TransferNotificationsRequest) GetSourceTenantId() string {
+	if x != nil {
+		return x.SourceTenantId
 	}
 	return ""
 }
 
-func (x *RescheduleNotificationRequest) GetScheduledTime() *timestamppb.Timestamp {
+func (x *TransferNotificationsRequest) GetDestinationTenantId() string {
 	if x != nil {
-		return x.ScheduledTime
+		return x.DestinationTenantId
+	}
+	return ""
+}
+
+func (x *TransferNotificationsRequest) GetStatuses() []Status {
+	if x != nil {
+		return x.Statuses
 	}
 	return nil
 }
 
-func (x *RescheduleNotificationRequest) GetTenantId() string {
+func (x *TransferNotificationsRequest) GetIncludeQueued() bool {
 	if x != nil {
-		return x.TenantId
+		return x.IncludeQueued
+	}
+	return false
+}
+
+// A moved notification that took a new id because the destination already used its own.
+type NotificationIdMapping struct {
+	state                     protoimpl.MessageState `protogen:"open.v1"`
+	SourceNotificationId      string                 `protobuf:"bytes,1,opt,name=source_notification_id,json=sourceNotificationId,proto3" json:"source_notification_id,omitempty"`
+	DestinationNotificationId string                 `protobuf:"bytes,2,opt,name=destination_notification_id,json=destinationNotificationId,proto3" json:"destination_notification_id,omitempty"`
+	unknownFields             protoimpl.UnknownFields
+	sizeCache                 protoimpl.SizeCache
+}
+
+func (x *NotificationIdMapping) Reset() {
+	*x = NotificationIdMapping{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationIdMapping) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationIdMapping) ProtoMessage() {}
+
+func (x *NotificationIdMapping) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationIdMapping.ProtoReflect.Descriptor instead.
+func (*NotificationIdMapping) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *NotificationIdMapping) GetSourceNotificationId() string {
+	if x != nil {
+		return x.SourceNotificationId
 	}
 	return ""
 }
 
-// Request to cancel a queued notification.
-type CancelNotificationRequest struct {
+func (x *NotificationIdMapping) GetDestinationNotificationId() string {
+	if x != nil {
+		return x.DestinationNotificationId
+	}
+	return ""
+}
+
+// A matching notification that stayed in the source tenant.
+type SkippedNotification struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	NotificationId string                 `protobuf:"bytes,1,opt,name=notification_id,json=notificationId,proto3" json:"notification_id,omitempty"`
-	TenantId       string                 `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Reason         string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
-func (x *CancelNotificationRequest) Reset() {
-	*x = CancelNotificationRequest{}
-	mi := &file_pkg_proto_pinguin_proto_msgTypes[7]
+func (x *SkippedNotification) Reset() {
+	*x = SkippedNotification{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CancelNotificationRequest) String() string {
+func (x *SkippedNotification) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CancelNotificationRequest) ProtoMessage() {}
+func (*SkippedNotification) ProtoMessage() {}
 
-func (x *CancelNotificationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_pinguin_proto_msgTypes[7]
+func (x *SkippedNotification) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -666,21 +3117,227 @@ func (x *CancelNotificationRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CancelNotificationRequest.ProtoReflect.Descriptor instead.
-func (*CancelNotificationRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use SkippedNotification.ProtoReflect.Descriptor instead.
+func (*SkippedNotification) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{41}
 }
 
-func (x *CancelNotificationRequest) GetNotificationId() string {
+func (x *SkippedNotification) GetNotificationId() string {
 	if x != nil {
 		return x.NotificationId
 	}
 	return ""
 }
 
-func (x *CancelNotificationRequest) GetTenantId() string {
+func (x *SkippedNotification) GetReason() string {
 	if x != nil {
-		return x.TenantId
+		return x.Reason
+	}
+	return ""
+}
+
+// Outcome of a transfer. On error the batches already moved are not rolled back.
+type TransferNotificationsResponse struct {
+	state            protoimpl.MessageState   `protogen:"open.v1"`
+	TransferredCount int64                    `protobuf:"varint,1,opt,name=transferred_count,json=transferredCount,proto3" json:"transferred_count,omitempty"`
+	Renamed          []*NotificationIdMapping `protobuf:"bytes,2,rep,name=renamed,proto3" json:"renamed,omitempty"`
+	Skipped          []*SkippedNotification   `protobuf:"bytes,3,rep,name=skipped,proto3" json:"skipped,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *TransferNotificationsResponse) Reset() {
+	*x = TransferNotificationsResponse{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferNotificationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferNotificationsResponse) ProtoMessage() {}
+
+func (x *TransferNotificationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferNotificationsResponse.ProtoReflect.Descriptor instead.
+func (*TransferNotificationsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *TransferNotificationsResponse) GetTransferredCount() int64 {
+	if x != nil {
+		return x.TransferredCount
+	}
+	return 0
+}
+
+func (x *TransferNotificationsResponse) GetRenamed() []*NotificationIdMapping {
+	if x != nil {
+		return x.Renamed
+	}
+	return nil
+}
+
+func (x *TransferNotificationsResponse) GetSkipped() []*SkippedNotification {
+	if x != nil {
+		return x.Skipped
+	}
+	return nil
+}
+
+// Envelope of a send request consumed from the message queue instead of gRPC.
+// idempotency_key is unique per tenant: a redelivered or republished message whose
+// key already created a notification is acknowledged without sending again.
+type QueuedNotificationRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	IdempotencyKey string                 `protobuf:"bytes,1,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"` // Required; at most 200 characters.
+	Request        *NotificationRequest   `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`                                     // request.tenant_id is required.
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *QueuedNotificationRequest) Reset() {
+	*x = QueuedNotificationRequest{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueuedNotificationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueuedNotificationRequest) ProtoMessage() {}
+
+func (x *QueuedNotificationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueuedNotificationRequest.ProtoReflect.Descriptor instead.
+func (*QueuedNotificationRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *QueuedNotificationRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *QueuedNotificationRequest) GetRequest() *NotificationRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+// Request for a connectivity and credential check; needs no tenant.
+type PingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PingRequest) Reset() {
+	*x = PingRequest{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingRequest) ProtoMessage() {}
+
+func (x *PingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PingRequest.ProtoReflect.Descriptor instead.
+func (*PingRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{44}
+}
+
+// Returned once the caller's token was accepted.
+type PingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServerTime    *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=server_time,json=serverTime,proto3" json:"server_time,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"` // Build version of the serving process.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PingResponse) Reset() {
+	*x = PingResponse{}
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingResponse) ProtoMessage() {}
+
+func (x *PingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_pinguin_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PingResponse.ProtoReflect.Descriptor instead.
+func (*PingResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_pinguin_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *PingResponse) GetServerTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ServerTime
+	}
+	return nil
+}
+
+func (x *PingResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
 	}
 	return ""
 }
@@ -693,7 +3350,10 @@ const file_pkg_proto_pinguin_proto_rawDesc = "" +
 	"\x0fEmailAttachment\x12\x1a\n" +
 	"\bfilename\x18\x01 \x01(\tR\bfilename\x12!\n" +
 	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\x12\x12\n" +
-	"\x04data\x18\x03 \x01(\fR\x04data\"\xcb\x02\n" +
+	"\x04data\x18\x03 \x01(\fR\x04data\"9\n" +
+	"\rCalendarEvent\x12\x10\n" +
+	"\x03ics\x18\x01 \x01(\tR\x03ics\x12\x16\n" +
+	"\x06method\x18\x02 \x01(\tR\x06method\"\xc1\x05\n" +
 	"\x13NotificationRequest\x12F\n" +
 	"\x11notification_type\x18\x01 \x01(\x0e2\x19.pinguin.NotificationTypeR\x10notificationType\x12\x1c\n" +
 	"\trecipient\x18\x02 \x01(\tR\trecipient\x12\x18\n" +
@@ -701,7 +3361,18 @@ const file_pkg_proto_pinguin_proto_rawDesc = "" +
 	"\amessage\x18\x04 \x01(\tR\amessage\x12A\n" +
 	"\x0escheduled_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\rscheduledTime\x12:\n" +
 	"\vattachments\x18\x06 \x03(\v2\x18.pinguin.EmailAttachmentR\vattachments\x12\x1b\n" +
-	"\ttenant_id\x18\a \x01(\tR\btenantId\"\xad\x04\n" +
+	"\ttenant_id\x18\a \x01(\tR\btenantId\x129\n" +
+	"\n" +
+	"expires_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12=\n" +
+	"\x0ecalendar_event\x18\t \x01(\v2\x16.pinguin.CalendarEventR\rcalendarEvent\x12.\n" +
+	"\x13sms_overflow_policy\x18\n" +
+	" \x01(\tR\x11smsOverflowPolicy\x12:\n" +
+	"\x17fail_on_immediate_error\x18\v \x01(\bH\x00R\x14failOnImmediateError\x88\x01\x01\x12+\n" +
+	"\x11content_encrypted\x18\f \x01(\bR\x10contentEncrypted\x12\x19\n" +
+	"\breply_to\x18\r \x01(\tR\areplyTo\x12*\n" +
+	"\x11from_display_name\x18\x0e \x01(\tR\x0ffromDisplayNameB\x1a\n" +
+	"\x18_fail_on_immediate_error\"\x82\n" +
+	"\n" +
 	"\x14NotificationResponse\x12'\n" +
 	"\x0fnotification_id\x18\x01 \x01(\tR\x0enotificationId\x12F\n" +
 	"\x11notification_type\x18\x02 \x01(\x0e2\x19.pinguin.NotificationTypeR\x10notificationType\x12\x1c\n" +
@@ -719,22 +3390,225 @@ const file_pkg_proto_pinguin_proto_rawDesc = "" +
 	" \x01(\tR\tupdatedAt\x12A\n" +
 	"\x0escheduled_time\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\rscheduledTime\x12:\n" +
 	"\vattachments\x18\f \x03(\v2\x18.pinguin.EmailAttachmentR\vattachments\x12\x1b\n" +
-	"\ttenant_id\x18\r \x01(\tR\btenantId\"d\n" +
+	"\ttenant_id\x18\r \x01(\tR\btenantId\x12%\n" +
+	"\x0eestimated_cost\x18\x0e \x01(\x01R\restimatedCost\x12#\n" +
+	"\rcost_currency\x18\x0f \x01(\tR\fcostCurrency\x129\n" +
+	"\n" +
+	"expires_at\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12#\n" +
+	"\rcancel_reason\x18\x11 \x01(\tR\fcancelReason\x12\x16\n" +
+	"\x06source\x18\x12 \x01(\tR\x06source\x12\x1c\n" +
+	"\ttruncated\x18\x13 \x01(\bR\ttruncated\x126\n" +
+	"\x17original_message_length\x18\x14 \x01(\x05R\x15originalMessageLength\x12\x1a\n" +
+	"\bwarnings\x18\x15 \x03(\tR\bwarnings\x124\n" +
+	"\brendered\x18\x16 \x01(\v2\x18.pinguin.RenderedContentR\brendered\x12\x19\n" +
+	"\bgroup_id\x18\x17 \x01(\tR\agroupId\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x18 \x01(\tR\tlastError\x12!\n" +
+	"\fcancelled_by\x18\x19 \x01(\tR\vcancelledBy\x12=\n" +
+	"\fcancelled_at\x18\x1a \x01(\v2\x1a.google.protobuf.TimestampR\vcancelledAt\x120\n" +
+	"\x05opens\x18\x1b \x01(\v2\x1a.pinguin.NotificationOpensR\x05opens\x12%\n" +
+	"\x0epending_reason\x18\x1c \x01(\tR\rpendingReason\x12+\n" +
+	"\x11content_encrypted\x18\x1d \x01(\bR\x10contentEncrypted\x12\x19\n" +
+	"\breply_to\x18\x1e \x01(\tR\areplyTo\x12*\n" +
+	"\x11from_display_name\x18\x1f \x01(\tR\x0ffromDisplayName\"\xeb\x01\n" +
+	"\x13NotificationChannel\x12F\n" +
+	"\x11notification_type\x18\x01 \x01(\x0e2\x19.pinguin.NotificationTypeR\x10notificationType\x12\x1c\n" +
+	"\trecipient\x18\x02 \x01(\tR\trecipient\x12\x18\n" +
+	"\asubject\x18\x03 \x01(\tR\asubject\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\x12:\n" +
+	"\vattachments\x18\x05 \x03(\v2\x18.pinguin.EmailAttachmentR\vattachments\"\xa3\x02\n" +
+	"\x18NotificationGroupRequest\x128\n" +
+	"\bchannels\x18\x01 \x03(\v2\x1c.pinguin.NotificationChannelR\bchannels\x12\x18\n" +
+	"\asubject\x18\x02 \x01(\tR\asubject\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12A\n" +
+	"\x0escheduled_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\rscheduledTime\x129\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x1b\n" +
+	"\ttenant_id\x18\x06 \x01(\tR\btenantId\"\xa4\x01\n" +
+	"\x19NotificationGroupResponse\x12\x19\n" +
+	"\bgroup_id\x18\x01 \x01(\tR\agroupId\x12'\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x0f.pinguin.StatusR\x06status\x12C\n" +
+	"\rnotifications\x18\x03 \x03(\v2\x1d.pinguin.NotificationResponseR\rnotifications\"U\n" +
+	"\x1bGetNotificationGroupRequest\x12\x19\n" +
+	"\bgroup_id\x18\x01 \x01(\tR\agroupId\x12\x1b\n" +
+	"\ttenant_id\x18\x02 \x01(\tR\btenantId\"\xec\x01\n" +
+	"\x0fRenderedContent\x12\x18\n" +
+	"\asubject\x18\x01 \x01(\tR\asubject\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\"\n" +
+	"\rsame_as_input\x18\x03 \x01(\bR\vsameAsInput\x12%\n" +
+	"\x0emime_structure\x18\x04 \x01(\tR\rmimeStructure\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x05 \x01(\x03R\tsizeBytes\x12;\n" +
+	"\vrendered_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"renderedAt\"\xb8\x01\n" +
+	"\x11NotificationOpens\x12\x1d\n" +
+	"\n" +
+	"open_count\x18\x01 \x01(\x03R\topenCount\x12B\n" +
+	"\x0ffirst_opened_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\rfirstOpenedAt\x12@\n" +
+	"\x0elast_opened_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\flastOpenedAt\"s\n" +
+	"\x18NotificationSizeEstimate\x12,\n" +
+	"\x12message_size_bytes\x18\x01 \x01(\x03R\x10messageSizeBytes\x12)\n" +
+	"\x10attachment_bytes\x18\x02 \x01(\x03R\x0fattachmentBytes\"\xa7\x01\n" +
 	"\x1cGetNotificationStatusRequest\x12'\n" +
 	"\x0fnotification_id\x18\x01 \x01(\tR\x0enotificationId\x12\x1b\n" +
-	"\ttenant_id\x18\x02 \x01(\tR\btenantId\"d\n" +
+	"\ttenant_id\x18\x02 \x01(\tR\btenantId\x12)\n" +
+	"\x10include_rendered\x18\x03 \x01(\bR\x0fincludeRendered\x12\x16\n" +
+	"\x06fields\x18\x04 \x03(\tR\x06fields\"|\n" +
 	"\x18ListNotificationsRequest\x12+\n" +
 	"\bstatuses\x18\x01 \x03(\x0e2\x0f.pinguin.StatusR\bstatuses\x12\x1b\n" +
-	"\ttenant_id\x18\x02 \x01(\tR\btenantId\"`\n" +
+	"\ttenant_id\x18\x02 \x01(\tR\btenantId\x12\x16\n" +
+	"\x06fields\x18\x03 \x03(\tR\x06fields\"`\n" +
 	"\x19ListNotificationsResponse\x12C\n" +
 	"\rnotifications\x18\x01 \x03(\v2\x1d.pinguin.NotificationResponseR\rnotifications\"\xa8\x01\n" +
 	"\x1dRescheduleNotificationRequest\x12'\n" +
 	"\x0fnotification_id\x18\x01 \x01(\tR\x0enotificationId\x12A\n" +
 	"\x0escheduled_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\rscheduledTime\x12\x1b\n" +
-	"\ttenant_id\x18\x03 \x01(\tR\btenantId\"a\n" +
+	"\ttenant_id\x18\x03 \x01(\tR\btenantId\"y\n" +
 	"\x19CancelNotificationRequest\x12'\n" +
 	"\x0fnotification_id\x18\x01 \x01(\tR\x0enotificationId\x12\x1b\n" +
-	"\ttenant_id\x18\x02 \x01(\tR\btenantId*&\n" +
+	"\ttenant_id\x18\x02 \x01(\tR\btenantId\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"\xa3\x01\n" +
+	"\x12CostSummaryRequest\x129\n" +
+	"\n" +
+	"start_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12\x1b\n" +
+	"\ttenant_id\x18\x03 \x01(\tR\btenantId\"\xc3\x01\n" +
+	"\x11CostSummaryBucket\x12\x10\n" +
+	"\x03day\x18\x01 \x01(\tR\x03day\x12F\n" +
+	"\x11notification_type\x18\x02 \x01(\x0e2\x19.pinguin.NotificationTypeR\x10notificationType\x12-\n" +
+	"\x12notification_count\x18\x03 \x01(\x05R\x11notificationCount\x12%\n" +
+	"\x0eestimated_cost\x18\x04 \x01(\x01R\restimatedCost\"\x86\x01\n" +
+	"\x13CostSummaryResponse\x12\x1a\n" +
+	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x12\x1d\n" +
+	"\n" +
+	"total_cost\x18\x02 \x01(\x01R\ttotalCost\x124\n" +
+	"\abuckets\x18\x03 \x03(\v2\x1a.pinguin.CostSummaryBucketR\abuckets\"5\n" +
+	"\x16GetCapabilitiesRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\"\x89\x03\n" +
+	"\x14CapabilitiesResponse\x12H\n" +
+	"\x12notification_types\x18\x01 \x03(\x0e2\x19.pinguin.NotificationTypeR\x11notificationTypes\x120\n" +
+	"\x14max_attachment_count\x18\x02 \x01(\x05R\x12maxAttachmentCount\x129\n" +
+	"\x19max_attachment_size_bytes\x18\x03 \x01(\x03R\x16maxAttachmentSizeBytes\x12=\n" +
+	"\x1bmax_attachments_total_bytes\x18\x04 \x01(\x03R\x18maxAttachmentsTotalBytes\x12:\n" +
+	"\x19attachment_data_persisted\x18\x05 \x01(\bR\x17attachmentDataPersisted\x12?\n" +
+	"\x1cmax_schedule_horizon_seconds\x18\x06 \x01(\x03R\x19maxScheduleHorizonSeconds\"\x80\x01\n" +
+	"\x19TestTenantDeliveryRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12F\n" +
+	"\x11notification_type\x18\x02 \x01(\x0e2\x19.pinguin.NotificationTypeR\x10notificationType\"\x94\x01\n" +
+	"\x1aTestTenantDeliveryResponse\x12F\n" +
+	"\x11notification_type\x18\x01 \x01(\x0e2\x19.pinguin.NotificationTypeR\x10notificationType\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\x1a\n" +
+	"\x18ListTenantsStatusRequest\"\xb7\x01\n" +
+	"\x0fProviderLatency\x125\n" +
+	"\bprovider\x18\x01 \x01(\x0e2\x19.pinguin.NotificationTypeR\bprovider\x12\x1e\n" +
+	"\n" +
+	"dispatches\x18\x02 \x01(\x03R\n" +
+	"dispatches\x12\x1d\n" +
+	"\n" +
+	"average_ms\x18\x03 \x01(\x03R\taverageMs\x12\x15\n" +
+	"\x06max_ms\x18\x04 \x01(\x03R\x05maxMs\x12\x17\n" +
+	"\alast_ms\x18\x05 \x01(\x03R\x06lastMs\"H\n" +
+	"\x0fHistogramBucket\x12\x1f\n" +
+	"\vupper_bound\x18\x01 \x01(\x03R\n" +
+	"upperBound\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"\x83\x02\n" +
+	"\x0fAttachmentSizes\x12\x14\n" +
+	"\x05sends\x18\x01 \x01(\x03R\x05sends\x12 \n" +
+	"\vattachments\x18\x02 \x01(\x03R\vattachments\x12\x1f\n" +
+	"\vtotal_bytes\x18\x03 \x01(\x03R\n" +
+	"totalBytes\x12\x1b\n" +
+	"\tmax_bytes\x18\x04 \x01(\x03R\bmaxBytes\x12;\n" +
+	"\fsize_buckets\x18\x05 \x03(\v2\x18.pinguin.HistogramBucketR\vsizeBuckets\x12=\n" +
+	"\rcount_buckets\x18\x06 \x03(\v2\x18.pinguin.HistogramBucketR\fcountBuckets\"\xc2\x02\n" +
+	"\x0fProviderBreaker\x125\n" +
+	"\bprovider\x18\x01 \x01(\x0e2\x19.pinguin.NotificationTypeR\bprovider\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x127\n" +
+	"\topened_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\bopenedAt\x12>\n" +
+	"\rlast_probe_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\vlastProbeAt\x12>\n" +
+	"\rnext_probe_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vnextProbeAt\x12'\n" +
+	"\x0fadmins_notified\x18\x06 \x01(\bR\x0eadminsNotified\"\xaa\x02\n" +
+	"\x13AttachmentIntegrity\x129\n" +
+	"\n" +
+	"checked_at\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tcheckedAt\x121\n" +
+	"\x14orphaned_attachments\x18\x02 \x01(\x03R\x13orphanedAttachments\x12<\n" +
+	"\x1aattachment_size_mismatches\x18\x03 \x01(\x03R\x18attachmentSizeMismatches\x12<\n" +
+	"\x1aduplicate_notification_ids\x18\x04 \x01(\x03R\x18duplicateNotificationIds\x12)\n" +
+	"\x10repaired_orphans\x18\x05 \x01(\x03R\x0frepairedOrphans\"\x9f\a\n" +
+	"\fTenantStatus\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12!\n" +
+	"\fdisplay_name\x18\x02 \x01(\tR\vdisplayName\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12!\n" +
+	"\fdomain_count\x18\x04 \x01(\x05R\vdomainCount\x128\n" +
+	"\x18email_profile_configured\x18\x05 \x01(\bR\x16emailProfileConfigured\x124\n" +
+	"\x16sms_profile_configured\x18\x06 \x01(\bR\x14smsProfileConfigured\x12.\n" +
+	"\x13email_sender_cached\x18\a \x01(\bR\x11emailSenderCached\x12*\n" +
+	"\x11sms_sender_cached\x18\b \x01(\bR\x0fsmsSenderCached\x12!\n" +
+	"\fqueued_count\x18\t \x01(\x03R\vqueuedCount\x12#\n" +
+	"\rerrored_count\x18\n" +
+	" \x01(\x03R\ferroredCount\x12H\n" +
+	"\x12last_dispatched_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\x10lastDispatchedAt\x12G\n" +
+	"\x12provider_latencies\x18\f \x03(\v2\x18.pinguin.ProviderLatencyR\x11providerLatencies\x12O\n" +
+	"\x14attachment_integrity\x18\r \x01(\v2\x1c.pinguin.AttachmentIntegrityR\x13attachmentIntegrity\x12E\n" +
+	"\x11provider_breakers\x18\x0e \x03(\v2\x18.pinguin.ProviderBreakerR\x10providerBreakers\x12C\n" +
+	"\x10attachment_sizes\x18\x0f \x01(\v2\x18.pinguin.AttachmentSizesR\x0fattachmentSizes\x12;\n" +
+	"\x1aoldest_pending_age_seconds\x18\x10 \x01(\x03R\x17oldestPendingAgeSeconds\x120\n" +
+	"\x14pending_age_exceeded\x18\x11 \x01(\bR\x12pendingAgeExceeded\x12!\n" +
+	"\fopened_count\x18\x12 \x01(\x03R\vopenedCount\"\xf1\x01\n" +
+	"\x10QueueIntakeStats\x12\x1a\n" +
+	"\breceived\x18\x01 \x01(\x03R\breceived\x12\x1a\n" +
+	"\baccepted\x18\x02 \x01(\x03R\baccepted\x12\x1e\n" +
+	"\n" +
+	"duplicates\x18\x03 \x01(\x03R\n" +
+	"duplicates\x12#\n" +
+	"\rdead_lettered\x18\x04 \x01(\x03R\fdeadLettered\x12\x1a\n" +
+	"\brequeued\x18\x05 \x01(\x03R\brequeued\x12D\n" +
+	"\x10last_received_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x0elastReceivedAt\"\x8a\x01\n" +
+	"\x19ListTenantsStatusResponse\x12/\n" +
+	"\atenants\x18\x01 \x03(\v2\x15.pinguin.TenantStatusR\atenants\x12<\n" +
+	"\fqueue_intake\x18\x02 \x01(\v2\x19.pinguin.QueueIntakeStatsR\vqueueIntake\"\x16\n" +
+	"\x14DrainInstanceRequest\"\x17\n" +
+	"\x15GetDrainStatusRequest\"\xe1\x01\n" +
+	"\vDrainStatus\x12\x1a\n" +
+	"\bdraining\x18\x01 \x01(\bR\bdraining\x12\x18\n" +
+	"\adrained\x18\x02 \x01(\bR\adrained\x129\n" +
+	"\n" +
+	"started_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x126\n" +
+	"\bdeadline\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\bdeadline\x12)\n" +
+	"\x10remaining_queued\x18\x05 \x01(\x03R\x0fremainingQueued\"\x13\n" +
+	"\x11ListCachesRequest\"\x14\n" +
+	"\x12FlushCachesRequest\"f\n" +
+	"\n" +
+	"CacheState\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aentries\x18\x02 \x01(\x03R\aentries\x12\x12\n" +
+	"\x04hits\x18\x03 \x01(\x04R\x04hits\x12\x16\n" +
+	"\x06misses\x18\x04 \x01(\x04R\x06misses\"A\n" +
+	"\x12ListCachesResponse\x12+\n" +
+	"\x06caches\x18\x01 \x03(\v2\x13.pinguin.CacheStateR\x06caches\"\xd0\x01\n" +
+	"\x1cTransferNotificationsRequest\x12(\n" +
+	"\x10source_tenant_id\x18\x01 \x01(\tR\x0esourceTenantId\x122\n" +
+	"\x15destination_tenant_id\x18\x02 \x01(\tR\x13destinationTenantId\x12+\n" +
+	"\bstatuses\x18\x03 \x03(\x0e2\x0f.pinguin.StatusR\bstatuses\x12%\n" +
+	"\x0einclude_queued\x18\x04 \x01(\bR\rincludeQueued\"\x8d\x01\n" +
+	"\x15NotificationIdMapping\x124\n" +
+	"\x16source_notification_id\x18\x01 \x01(\tR\x14sourceNotificationId\x12>\n" +
+	"\x1bdestination_notification_id\x18\x02 \x01(\tR\x19destinationNotificationId\"V\n" +
+	"\x13SkippedNotification\x12'\n" +
+	"\x0fnotification_id\x18\x01 \x01(\tR\x0enotificationId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\xbe\x01\n" +
+	"\x1dTransferNotificationsResponse\x12+\n" +
+	"\x11transferred_count\x18\x01 \x01(\x03R\x10transferredCount\x128\n" +
+	"\arenamed\x18\x02 \x03(\v2\x1e.pinguin.NotificationIdMappingR\arenamed\x126\n" +
+	"\askipped\x18\x03 \x03(\v2\x1c.pinguin.SkippedNotificationR\askipped\"|\n" +
+	"\x19QueuedNotificationRequest\x12'\n" +
+	"\x0fidempotency_key\x18\x01 \x01(\tR\x0eidempotencyKey\x126\n" +
+	"\arequest\x18\x02 \x01(\v2\x1c.pinguin.NotificationRequestR\arequest\"\r\n" +
+	"\vPingRequest\"e\n" +
+	"\fPingResponse\x12;\n" +
+	"\vserver_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"serverTime\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion*&\n" +
 	"\x10NotificationType\x12\t\n" +
 	"\x05EMAIL\x10\x00\x12\a\n" +
 	"\x03SMS\x10\x01*G\n" +
@@ -744,13 +3618,28 @@ const file_pkg_proto_pinguin_proto_rawDesc = "" +
 	"\x04SENT\x10\x01\x12\v\n" +
 	"\aUNKNOWN\x10\x03\x12\r\n" +
 	"\tCANCELLED\x10\x04\x12\v\n" +
-	"\aERRORED\x10\x052\xdb\x03\n" +
+	"\aERRORED\x10\x052\xef\f\n" +
 	"\x13NotificationService\x12O\n" +
-	"\x10SendNotification\x12\x1c.pinguin.NotificationRequest\x1a\x1d.pinguin.NotificationResponse\x12]\n" +
+	"\x10SendNotification\x12\x1c.pinguin.NotificationRequest\x1a\x1d.pinguin.NotificationResponse\x12[\n" +
+	"\x18EstimateNotificationSize\x12\x1c.pinguin.NotificationRequest\x1a!.pinguin.NotificationSizeEstimate\x12^\n" +
+	"\x15SendNotificationGroup\x12!.pinguin.NotificationGroupRequest\x1a\".pinguin.NotificationGroupResponse\x12`\n" +
+	"\x14GetNotificationGroup\x12$.pinguin.GetNotificationGroupRequest\x1a\".pinguin.NotificationGroupResponse\x12]\n" +
 	"\x15GetNotificationStatus\x12%.pinguin.GetNotificationStatusRequest\x1a\x1d.pinguin.NotificationResponse\x12Z\n" +
-	"\x11ListNotifications\x12!.pinguin.ListNotificationsRequest\x1a\".pinguin.ListNotificationsResponse\x12_\n" +
+	"\x11ListNotifications\x12!.pinguin.ListNotificationsRequest\x1a\".pinguin.ListNotificationsResponse\x12]\n" +
+	"\x17ListNotificationsStream\x12!.pinguin.ListNotificationsRequest\x1a\x1d.pinguin.NotificationResponse0\x01\x12_\n" +
 	"\x16RescheduleNotification\x12&.pinguin.RescheduleNotificationRequest\x1a\x1d.pinguin.NotificationResponse\x12W\n" +
-	"\x12CancelNotification\x12\".pinguin.CancelNotificationRequest\x1a\x1d.pinguin.NotificationResponseB1Z/github.com/tyemirov/pinguin/pkg/grpcapi;grpcapib\x06proto3"
+	"\x12CancelNotification\x12\".pinguin.CancelNotificationRequest\x1a\x1d.pinguin.NotificationResponse\x12K\n" +
+	"\x0eGetCostSummary\x12\x1b.pinguin.CostSummaryRequest\x1a\x1c.pinguin.CostSummaryResponse\x12Q\n" +
+	"\x0fGetCapabilities\x12\x1f.pinguin.GetCapabilitiesRequest\x1a\x1d.pinguin.CapabilitiesResponse\x12]\n" +
+	"\x12TestTenantDelivery\x12\".pinguin.TestTenantDeliveryRequest\x1a#.pinguin.TestTenantDeliveryResponse\x12Z\n" +
+	"\x11ListTenantsStatus\x12!.pinguin.ListTenantsStatusRequest\x1a\".pinguin.ListTenantsStatusResponse\x12D\n" +
+	"\rDrainInstance\x12\x1d.pinguin.DrainInstanceRequest\x1a\x14.pinguin.DrainStatus\x12F\n" +
+	"\x0eGetDrainStatus\x12\x1e.pinguin.GetDrainStatusRequest\x1a\x14.pinguin.DrainStatus\x12f\n" +
+	"\x15TransferNotifications\x12%.pinguin.TransferNotificationsRequest\x1a&.pinguin.TransferNotificationsResponse\x12E\n" +
+	"\n" +
+	"ListCaches\x12\x1a.pinguin.ListCachesRequest\x1a\x1b.pinguin.ListCachesResponse\x12G\n" +
+	"\vFlushCaches\x12\x1b.pinguin.FlushCachesRequest\x1a\x1b.pinguin.ListCachesResponse\x123\n" +
+	"\x04Ping\x12\x14.pinguin.PingRequest\x1a\x15.pinguin.PingResponseB1Z/github.com/tyemirov/pinguin/pkg/grpcapi;grpcapib\x06proto3"
 
 var (
 	file_pkg_proto_pinguin_proto_rawDescOnce sync.Once
@@ -765,46 +3654,159 @@ func file_pkg_proto_pinguin_proto_rawDescGZIP() []byte {
 }
 
 var file_pkg_proto_pinguin_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_pkg_proto_pinguin_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_pkg_proto_pinguin_proto_msgTypes = make([]protoimpl.MessageInfo, 46)
 var file_pkg_proto_pinguin_proto_goTypes = []any{
 	(NotificationType)(0),                 // 0: pinguin.NotificationType
 	(Status)(0),                           // 1: pinguin.Status
 	(*EmailAttachment)(nil),               // 2: pinguin.EmailAttachment
-	(*NotificationRequest)(nil),           // 3: pinguin.NotificationRequest
-	(*NotificationResponse)(nil),          // 4: pinguin.NotificationResponse
-	(*GetNotificationStatusRequest)(nil),  // 5: pinguin.GetNotificationStatusRequest
-	(*ListNotificationsRequest)(nil),      // 6: pinguin.ListNotificationsRequest
-	(*ListNotificationsResponse)(nil),     // 7: pinguin.ListNotificationsResponse
-	(*RescheduleNotificationRequest)(nil), // 8: pinguin.RescheduleNotificationRequest
-	(*CancelNotificationRequest)(nil),     // 9: pinguin.CancelNotificationRequest
-	(*timestamppb.Timestamp)(nil),         // 10: google.protobuf.Timestamp
+	(*CalendarEvent)(nil),                 // 3: pinguin.CalendarEvent
+	(*NotificationRequest)(nil),           // 4: pinguin.NotificationRequest
+	(*NotificationResponse)(nil),          // 5: pinguin.NotificationResponse
+	(*NotificationChannel)(nil),           // 6: pinguin.NotificationChannel
+	(*NotificationGroupRequest)(nil),      // 7: pinguin.NotificationGroupRequest
+	(*NotificationGroupResponse)(nil),     // 8: pinguin.NotificationGroupResponse
+	(*GetNotificationGroupRequest)(nil),   // 9: pinguin.GetNotificationGroupRequest
+	(*RenderedContent)(nil),               // 10: pinguin.RenderedContent
+	(*NotificationOpens)(nil),             // 11: pinguin.NotificationOpens
+	(*NotificationSizeEstimate)(nil),      // 12: pinguin.NotificationSizeEstimate
+	(*GetNotificationStatusRequest)(nil),  // 13: pinguin.GetNotificationStatusRequest
+	(*ListNotificationsRequest)(nil),      // 14: pinguin.ListNotificationsRequest
+	(*ListNotificationsResponse)(nil),     // 15: pinguin.ListNotificationsResponse
+	(*RescheduleNotificationRequest)(nil), // 16: pinguin.RescheduleNotificationRequest
+	(*CancelNotificationRequest)(nil),     // 17: pinguin.CancelNotificationRequest
+	(*CostSummaryRequest)(nil),            // 18: pinguin.CostSummaryRequest
+	(*CostSummaryBucket)(nil),             // 19: pinguin.CostSummaryBucket
+	(*CostSummaryResponse)(nil),           // 20: pinguin.CostSummaryResponse
+	(*GetCapabilitiesRequest)(nil),        // 21: pinguin.GetCapabilitiesRequest
+	(*CapabilitiesResponse)(nil),          // 22: pinguin.CapabilitiesResponse
+	(*TestTenantDeliveryRequest)(nil),     // 23: pinguin.TestTenantDeliveryRequest
+	(*TestTenantDeliveryResponse)(nil),    // 24: pinguin.TestTenantDeliveryResponse
+	(*ListTenantsStatusRequest)(nil),      // 25: pinguin.ListTenantsStatusRequest
+	(*ProviderLatency)(nil),               // 26: pinguin.ProviderLatency
+	(*HistogramBucket)(nil),               // 27: pinguin.HistogramBucket
+	(*AttachmentSizes)(nil),               // 28: pinguin.AttachmentSizes
+	(*ProviderBreaker)(nil),               // 29: pinguin.ProviderBreaker
+	(*AttachmentIntegrity)(nil),           // 30: pinguin.AttachmentIntegrity
+	(*TenantStatus)(nil),                  // 31: pinguin.TenantStatus
+	(*QueueIntakeStats)(nil),              // 32: pinguin.QueueIntakeStats
+	(*ListTenantsStatusResponse)(nil),     // 33: pinguin.ListTenantsStatusResponse
+	(*DrainInstanceRequest)(nil),          // 34: pinguin.DrainInstanceRequest
+	(*GetDrainStatusRequest)(nil),         // 35: pinguin.GetDrainStatusRequest
+	(*DrainStatus)(nil),                   // 36: pinguin.DrainStatus
+	(*ListCachesRequest)(nil),             // 37: pinguin.ListCachesRequest
+	(*FlushCachesRequest)(nil),            // 38: pinguin.FlushCachesRequest
+	(*CacheState)(nil),                    // 39: pinguin.CacheState
+	(*ListCachesResponse)(nil),            // 40: pinguin.ListCachesResponse
+	(*TransferNotificationsRequest)(nil),  // 41: pinguin.TransferNotificationsRequest
+	(*NotificationIdMapping)(nil),         // 42: pinguin.NotificationIdMapping
+	(*SkippedNotification)(nil),           // 43: pinguin.SkippedNotification
+	(*TransferNotificationsResponse)(nil), // 44: pinguin.TransferNotificationsResponse
+	(*QueuedNotificationRequest)(nil),     // 45: pinguin.QueuedNotificationRequest
+	(*PingRequest)(nil),                   // 46: pinguin.PingRequest
+	(*PingResponse)(nil),                  // 47: pinguin.PingResponse
+	(*timestamppb.Timestamp)(nil),         // 48: google.protobuf.Timestamp
 }
 var file_pkg_proto_pinguin_proto_depIdxs = []int32{
 	0,  // 0: pinguin.NotificationRequest.notification_type:type_name -> pinguin.NotificationType
-	10, // 1: pinguin.NotificationRequest.scheduled_time:type_name -> google.protobuf.Timestamp
+	48, // 1: pinguin.NotificationRequest.scheduled_time:type_name -> google.protobuf.Timestamp
 	2,  // 2: pinguin.NotificationRequest.attachments:type_name -> pinguin.EmailAttachment
-	0,  // 3: pinguin.NotificationResponse.notification_type:type_name -> pinguin.NotificationType
-	1,  // 4: pinguin.NotificationResponse.status:type_name -> pinguin.Status
-	10, // 5: pinguin.NotificationResponse.scheduled_time:type_name -> google.protobuf.Timestamp
-	2,  // 6: pinguin.NotificationResponse.attachments:type_name -> pinguin.EmailAttachment
-	1,  // 7: pinguin.ListNotificationsRequest.statuses:type_name -> pinguin.Status
-	4,  // 8: pinguin.ListNotificationsResponse.notifications:type_name -> pinguin.NotificationResponse
-	10, // 9: pinguin.RescheduleNotificationRequest.scheduled_time:type_name -> google.protobuf.Timestamp
-	3,  // 10: pinguin.NotificationService.SendNotification:input_type -> pinguin.NotificationRequest
-	5,  // 11: pinguin.NotificationService.GetNotificationStatus:input_type -> pinguin.GetNotificationStatusRequest
-	6,  // 12: pinguin.NotificationService.ListNotifications:input_type -> pinguin.ListNotificationsRequest
-	8,  // 13: pinguin.NotificationService.RescheduleNotification:input_type -> pinguin.RescheduleNotificationRequest
-	9,  // 14: pinguin.NotificationService.CancelNotification:input_type -> pinguin.CancelNotificationRequest
-	4,  // 15: pinguin.NotificationService.SendNotification:output_type -> pinguin.NotificationResponse
-	4,  // 16: pinguin.NotificationService.GetNotificationStatus:output_type -> pinguin.NotificationResponse
-	7,  // 17: pinguin.NotificationService.ListNotifications:output_type -> pinguin.ListNotificationsResponse
-	4,  // 18: pinguin.NotificationService.RescheduleNotification:output_type -> pinguin.NotificationResponse
-	4,  // 19: pinguin.NotificationService.CancelNotification:output_type -> pinguin.NotificationResponse
-	15, // [15:20] is the sub-list for method output_type
-	10, // [10:15] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	48, // 3: pinguin.NotificationRequest.expires_at:type_name -> google.protobuf.Timestamp
+	3,  // 4: pinguin.NotificationRequest.calendar_event:type_name -> pinguin.CalendarEvent
+	0,  // 5: pinguin.NotificationResponse.notification_type:type_name -> pinguin.NotificationType
+	1,  // 6: pinguin.NotificationResponse.status:type_name -> pinguin.Status
+	48, // 7: pinguin.NotificationResponse.scheduled_time:type_name -> google.protobuf.Timestamp
+	2,  // 8: pinguin.NotificationResponse.attachments:type_name -> pinguin.EmailAttachment
+	48, // 9: pinguin.NotificationResponse.expires_at:type_name -> google.protobuf.Timestamp
+	10, // 10: pinguin.NotificationResponse.rendered:type_name -> pinguin.RenderedContent
+	48, // 11: pinguin.NotificationResponse.cancelled_at:type_name -> google.protobuf.Timestamp
+	11, // 12: pinguin.NotificationResponse.opens:type_name -> pinguin.NotificationOpens
+	0,  // 13: pinguin.NotificationChannel.notification_type:type_name -> pinguin.NotificationType
+	2,  // 14: pinguin.NotificationChannel.attachments:type_name -> pinguin.EmailAttachment
+	6,  // 15: pinguin.NotificationGroupRequest.channels:type_name -> pinguin.NotificationChannel
+	48, // 16: pinguin.NotificationGroupRequest.scheduled_time:type_name -> google.protobuf.Timestamp
+	48, // 17: pinguin.NotificationGroupRequest.expires_at:type_name -> google.protobuf.Timestamp
+	1,  // 18: pinguin.NotificationGroupResponse.status:type_name -> pinguin.Status
+	5,  // 19: pinguin.NotificationGroupResponse.notifications:type_name -> pinguin.NotificationResponse
+	48, // 20: pinguin.RenderedContent.rendered_at:type_name -> google.protobuf.Timestamp
+	48, // 21: pinguin.NotificationOpens.first_opened_at:type_name -> google.protobuf.Timestamp
+	48, // 22: pinguin.NotificationOpens.last_opened_at:type_name -> google.protobuf.Timestamp
+	1,  // 23: pinguin.ListNotificationsRequest.statuses:type_name -> pinguin.Status
+	5,  // 24: pinguin.ListNotificationsResponse.notifications:type_name -> pinguin.NotificationResponse
+	48, // 25: pinguin.RescheduleNotificationRequest.scheduled_time:type_name -> google.protobuf.Timestamp
+	48, // 26: pinguin.CostSummaryRequest.start_time:type_name -> google.protobuf.Timestamp
+	48, // 27: pinguin.CostSummaryRequest.end_time:type_name -> google.protobuf.Timestamp
+	0,  // 28: pinguin.CostSummaryBucket.notification_type:type_name -> pinguin.NotificationType
+	19, // 29: pinguin.CostSummaryResponse.buckets:type_name -> pinguin.CostSummaryBucket
+	0,  // 30: pinguin.CapabilitiesResponse.notification_types:type_name -> pinguin.NotificationType
+	0,  // 31: pinguin.TestTenantDeliveryRequest.notification_type:type_name -> pinguin.NotificationType
+	0,  // 32: pinguin.TestTenantDeliveryResponse.notification_type:type_name -> pinguin.NotificationType
+	0,  // 33: pinguin.ProviderLatency.provider:type_name -> pinguin.NotificationType
+	27, // 34: pinguin.AttachmentSizes.size_buckets:type_name -> pinguin.HistogramBucket
+	27, // 35: pinguin.AttachmentSizes.count_buckets:type_name -> pinguin.HistogramBucket
+	0,  // 36: pinguin.ProviderBreaker.provider:type_name -> pinguin.NotificationType
+	48, // 37: pinguin.ProviderBreaker.opened_at:type_name -> google.protobuf.Timestamp
+	48, // 38: pinguin.ProviderBreaker.last_probe_at:type_name -> google.protobuf.Timestamp
+	48, // 39: pinguin.ProviderBreaker.next_probe_at:type_name -> google.protobuf.Timestamp
+	48, // 40: pinguin.AttachmentIntegrity.checked_at:type_name -> google.protobuf.Timestamp
+	48, // 41: pinguin.TenantStatus.last_dispatched_at:type_name -> google.protobuf.Timestamp
+	26, // 42: pinguin.TenantStatus.provider_latencies:type_name -> pinguin.ProviderLatency
+	30, // 43: pinguin.TenantStatus.attachment_integrity:type_name -> pinguin.AttachmentIntegrity
+	29, // 44: pinguin.TenantStatus.provider_breakers:type_name -> pinguin.ProviderBreaker
+	28, // 45: pinguin.TenantStatus.attachment_sizes:type_name -> pinguin.AttachmentSizes
+	48, // 46: pinguin.QueueIntakeStats.last_received_at:type_name -> google.protobuf.Timestamp
+	31, // 47: pinguin.ListTenantsStatusResponse.tenants:type_name -> pinguin.TenantStatus
+	32, // 48: pinguin.ListTenantsStatusResponse.queue_intake:type_name -> pinguin.QueueIntakeStats
+	48, // 49: pinguin.DrainStatus.started_at:type_name -> google.protobuf.Timestamp
+	48, // 50: pinguin.DrainStatus.deadline:type_name -> google.protobuf.Timestamp
+	39, // 51: pinguin.ListCachesResponse.caches:type_name -> pinguin.CacheState
+	1,  // 52: pinguin.TransferNotificationsRequest.statuses:type_name -> pinguin.Status
+	42, // 53: pinguin.TransferNotificationsResponse.renamed:type_name -> pinguin.NotificationIdMapping
+	43, // 54: pinguin.TransferNotificationsResponse.skipped:type_name -> pinguin.SkippedNotification
+	4,  // 55: pinguin.QueuedNotificationRequest.request:type_name -> pinguin.NotificationRequest
+	48, // 56: pinguin.PingResponse.server_time:type_name -> google.protobuf.Timestamp
+	4,  // 57: pinguin.NotificationService.SendNotification:input_type -> pinguin.NotificationRequest
+	4,  // 58: pinguin.NotificationService.EstimateNotificationSize:input_type -> pinguin.NotificationRequest
+	7,  // 59: pinguin.NotificationService.SendNotificationGroup:input_type -> pinguin.NotificationGroupRequest
+	9,  // 60: pinguin.NotificationService.GetNotificationGroup:input_type -> pinguin.GetNotificationGroupRequest
+	13, // 61: pinguin.NotificationService.GetNotificationStatus:input_type -> pinguin.GetNotificationStatusRequest
+	14, // 62: pinguin.NotificationService.ListNotifications:input_type -> pinguin.ListNotificationsRequest
+	14, // 63: pinguin.NotificationService.ListNotificationsStream:input_type -> pinguin.ListNotificationsRequest
+	16, // 64: pinguin.NotificationService.RescheduleNotification:input_type -> pinguin.RescheduleNotificationRequest
+	17, // 65: pinguin.NotificationService.CancelNotification:input_type -> pinguin.CancelNotificationRequest
+	18, // 66: pinguin.NotificationService.GetCostSummary:input_type -> pinguin.CostSummaryRequest
+	21, // 67: pinguin.NotificationService.GetCapabilities:input_type -> pinguin.GetCapabilitiesRequest
+	23, // 68: pinguin.NotificationService.TestTenantDelivery:input_type -> pinguin.TestTenantDeliveryRequest
+	25, // 69: pinguin.NotificationService.ListTenantsStatus:input_type -> pinguin.ListTenantsStatusRequest
+	34, // 70: pinguin.NotificationService.DrainInstance:input_type -> pinguin.DrainInstanceRequest
+	35, // 71: pinguin.NotificationService.GetDrainStatus:input_type -> pinguin.GetDrainStatusRequest
+	41, // 72: pinguin.NotificationService.TransferNotifications:input_type -> pinguin.TransferNotificationsRequest
+	37, // 73: pinguin.NotificationService.ListCaches:input_type -> pinguin.ListCachesRequest
+	38, // 74: pinguin.NotificationService.FlushCaches:input_type -> pinguin.FlushCachesRequest
+	46, // 75: pinguin.NotificationService.Ping:input_type -> pinguin.PingRequest
+	5,  // 76: pinguin.NotificationService.SendNotification:output_type -> pinguin.NotificationResponse
+	12, // 77: pinguin.NotificationService.EstimateNotificationSize:output_type -> pinguin.NotificationSizeEstimate
+	8,  // 78: pinguin.NotificationService.SendNotificationGroup:output_type -> pinguin.NotificationGroupResponse
+	8,  // 79: pinguin.NotificationService.GetNotificationGroup:output_type -> pinguin.NotificationGroupResponse
+	5,  // 80: pinguin.NotificationService.GetNotificationStatus:output_type -> pinguin.NotificationResponse
+	15, // 81: pinguin.NotificationService.ListNotifications:output_type -> pinguin.ListNotificationsResponse
+	5,  // 82: pinguin.NotificationService.ListNotificationsStream:output_type -> pinguin.NotificationResponse
+	5,  // 83: pinguin.NotificationService.RescheduleNotification:output_type -> pinguin.NotificationResponse
+	5,  // 84: pinguin.NotificationService.CancelNotification:output_type -> pinguin.NotificationResponse
+	20, // 85: pinguin.NotificationService.GetCostSummary:output_type -> pinguin.CostSummaryResponse
+	22, // 86: pinguin.NotificationService.GetCapabilities:output_type -> pinguin.CapabilitiesResponse
+	24, // 87: pinguin.NotificationService.TestTenantDelivery:output_type -> pinguin.TestTenantDeliveryResponse
+	33, // 88: pinguin.NotificationService.ListTenantsStatus:output_type -> pinguin.ListTenantsStatusResponse
+	36, // 89: pinguin.NotificationService.DrainInstance:output_type -> pinguin.DrainStatus
+	36, // 90: pinguin.NotificationService.GetDrainStatus:output_type -> pinguin.DrainStatus
+	44, // 91: pinguin.NotificationService.TransferNotifications:output_type -> pinguin.TransferNotificationsResponse
+	40, // 92: pinguin.NotificationService.ListCaches:output_type -> pinguin.ListCachesResponse
+	40, // 93: pinguin.NotificationService.FlushCaches:output_type -> pinguin.ListCachesResponse
+	47, // 94: pinguin.NotificationService.Ping:output_type -> pinguin.PingResponse
+	76, // [76:95] is the sub-list for method output_type
+	57, // [57:76] is the sub-list for method input_type
+	57, // [57:57] is the sub-list for extension type_name
+	57, // [57:57] is the sub-list for extension extendee
+	0,  // [0:57] is the sub-list for field type_name
 }
 
 func init() { file_pkg_proto_pinguin_proto_init() }
@@ -812,13 +3814,14 @@ func file_pkg_proto_pinguin_proto_init() {
 	if File_pkg_proto_pinguin_proto != nil {
 		return
 	}
+	file_pkg_proto_pinguin_proto_msgTypes[2].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_proto_pinguin_proto_rawDesc), len(file_pkg_proto_pinguin_proto_rawDesc)),
 			NumEnums:      2,
-			NumMessages:   8,
+			NumMessages:   46,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,11 +19,25 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	NotificationService_SendNotification_FullMethodName       = "/pinguin.NotificationService/SendNotification"
-	NotificationService_GetNotificationStatus_FullMethodName  = "/pinguin.NotificationService/GetNotificationStatus"
-	NotificationService_ListNotifications_FullMethodName      = "/pinguin.NotificationService/ListNotifications"
-	NotificationService_RescheduleNotification_FullMethodName = "/pinguin.NotificationService/RescheduleNotification"
-	NotificationService_CancelNotification_FullMethodName     = "/pinguin.NotificationService/CancelNotification"
+	NotificationService_SendNotification_FullMethodName         = "/pinguin.NotificationService/SendNotification"
+	NotificationService_EstimateNotificationSize_FullMethodName = "/pinguin.NotificationService/EstimateNotificationSize"
+	NotificationService_SendNotificationGroup_FullMethodName    = "/pinguin.NotificationService/SendNotificationGroup"
+	NotificationService_GetNotificationGroup_FullMethodName     = "/pinguin.NotificationService/GetNotificationGroup"
+	NotificationService_GetNotificationStatus_FullMethodName    = "/pinguin.NotificationService/GetNotificationStatus"
+	NotificationService_ListNotifications_FullMethodName        = "/pinguin.NotificationService/ListNotifications"
+	NotificationService_ListNotificationsStream_FullMethodName  = "/pinguin.NotificationService/ListNotificationsStream"
+	NotificationService_RescheduleNotification_FullMethodName   = "/pinguin.NotificationService/RescheduleNotification"
+	NotificationService_CancelNotification_FullMethodName       = "/pinguin.NotificationService/CancelNotification"
+	NotificationService_GetCostSummary_FullMethodName           = "/pinguin.NotificationService/GetCostSummary"
+	NotificationService_GetCapabilities_FullMethodName          = "/pinguin.NotificationService/GetCapabilities"
+	NotificationService_TestTenantDelivery_FullMethodName       = "/pinguin.NotificationService/TestTenantDelivery"
+	NotificationService_ListTenantsStatus_FullMethodName        = "/pinguin.NotificationService/ListTenantsStatus"
+	NotificationService_DrainInstance_FullMethodName            = "/pinguin.NotificationService/DrainInstance"
+	NotificationService_GetDrainStatus_FullMethodName           = "/pinguin.NotificationService/GetDrainStatus"
+	NotificationService_TransferNotifications_FullMethodName    = "/pinguin.NotificationService/TransferNotifications"
+	NotificationService_ListCaches_FullMethodName               = "/pinguin.NotificationService/ListCaches"
+	NotificationService_FlushCaches_FullMethodName              = "/pinguin.NotificationService/FlushCaches"
+	NotificationService_Ping_FullMethodName                     = "/pinguin.NotificationService/Ping"
 )
 
 // NotificationServiceClient is the client API for NotificationService service.
@@ -33,10 +47,24 @@ const (
 // NotificationService defines two RPC methods.
 type NotificationServiceClient interface {
 	SendNotification(ctx context.Context, in *NotificationRequest, opts ...grpc.CallOption) (*NotificationResponse, error)
+	EstimateNotificationSize(ctx context.Context, in *NotificationRequest, opts ...grpc.CallOption) (*NotificationSizeEstimate, error)
+	SendNotificationGroup(ctx context.Context, in *NotificationGroupRequest, opts ...grpc.CallOption) (*NotificationGroupResponse, error)
+	GetNotificationGroup(ctx context.Context, in *GetNotificationGroupRequest, opts ...grpc.CallOption) (*NotificationGroupResponse, error)
 	GetNotificationStatus(ctx context.Context, in *GetNotificationStatusRequest, opts ...grpc.CallOption) (*NotificationResponse, error)
 	ListNotifications(ctx context.Context, in *ListNotificationsRequest, opts ...grpc.CallOption) (*ListNotificationsResponse, error)
+	ListNotificationsStream(ctx context.Context, in *ListNotificationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[NotificationResponse], error)
 	RescheduleNotification(ctx context.Context, in *RescheduleNotificationRequest, opts ...grpc.CallOption) (*NotificationResponse, error)
 	CancelNotification(ctx context.Context, in *CancelNotificationRequest, opts ...grpc.CallOption) (*NotificationResponse, error)
+	GetCostSummary(ctx context.Context, in *CostSummaryRequest, opts ...grpc.CallOption) (*CostSummaryResponse, error)
+	GetCapabilities(ctx context.Context, in *GetCapabilitiesRequest, opts ...grpc.CallOption) (*CapabilitiesResponse, error)
+	TestTenantDelivery(ctx context.Context, in *TestTenantDeliveryRequest, opts ...grpc.CallOption) (*TestTenantDeliveryResponse, error)
+	ListTenantsStatus(ctx context.Context, in *ListTenantsStatusRequest, opts ...grpc.CallOption) (*ListTenantsStatusResponse, error)
+	DrainInstance(ctx context.Context, in *DrainInstanceRequest, opts ...grpc.CallOption) (*DrainStatus, error)
+	GetDrainStatus(ctx context.Context, in *GetDrainStatusRequest, opts ...grpc.CallOption) (*DrainStatus, error)
+	TransferNotifications(ctx context.Context, in *TransferNotificationsRequest, opts ...grpc.CallOption) (*TransferNotificationsResponse, error)
+	ListCaches(ctx context.Context, in *ListCachesRequest, opts ...grpc.CallOption) (*ListCachesResponse, error)
+	FlushCaches(ctx context.Context, in *FlushCachesRequest, opts ...grpc.CallOption) (*ListCachesResponse, error)
+	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error)
 }
 
 type notificationServiceClient struct {
@@ -57,6 +85,36 @@ func (c *notificationServiceClient) SendNotification(ctx context.Context, in *No
 	return out, nil
 }
 
+func (c *notificationServiceClient) EstimateNotificationSize(ctx context.Context, in *NotificationRequest, opts ...grpc.CallOption) (*NotificationSizeEstimate, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NotificationSizeEstimate)
+	err := c.cc.Invoke(ctx, NotificationService_EstimateNotificationSize_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceClient) SendNotificationGroup(ctx context.Context, in *NotificationGroupRequest, opts ...grpc.CallOption) (*NotificationGroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NotificationGroupResponse)
+	err := c.cc.Invoke(ctx, NotificationService_SendNotificationGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceClient) GetNotificationGroup(ctx context.Context, in *GetNotificationGroupRequest, opts ...grpc.CallOption) (*NotificationGroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NotificationGroupResponse)
+	err := c.cc.Invoke(ctx, NotificationService_GetNotificationGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *notificationServiceClient) GetNotificationStatus(ctx context.Context, in *GetNotificationStatusRequest, opts ...grpc.CallOption) (*NotificationResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(NotificationResponse)
@@ -77,6 +135,25 @@ func (c *notificationServiceClient) ListNotifications(ctx context.Context, in *L
 	return out, nil
 }
 
+func (c *notificationServiceClient) ListNotificationsStream(ctx context.Context, in *ListNotificationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[NotificationResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NotificationService_ServiceDesc.Streams[0], NotificationService_ListNotificationsStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListNotificationsRequest, NotificationResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NotificationService_ListNotificationsStreamClient = grpc.ServerStreamingClient[NotificationResponse]
+
 func (c *notificationServiceClient) RescheduleNotification(ctx context.Context, in *RescheduleNotificationRequest, opts ...grpc.CallOption) (*NotificationResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(NotificationResponse)
@@ -97,6 +174,106 @@ func (c *notificationServiceClient) CancelNotification(ctx context.Context, in *
 	return out, nil
 }
 
+func (c *notificationServiceClient) GetCostSummary(ctx context.Context, in *CostSummaryRequest, opts ...grpc.CallOption) (*CostSummaryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CostSummaryResponse)
+	err := c.cc.Invoke(ctx, NotificationService_GetCostSummary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceClient) GetCapabilities(ctx context.Context, in *GetCapabilitiesRequest, opts ...grpc.CallOption) (*CapabilitiesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CapabilitiesResponse)
+	err := c.cc.Invoke(ctx, NotificationService_GetCapabilities_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceClient) TestTenantDelivery(ctx context.Context, in *TestTenantDeliveryRequest, opts ...grpc.CallOption) (*TestTenantDeliveryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TestTenantDeliveryResponse)
+	err := c.cc.Invoke(ctx, NotificationService_TestTenantDelivery_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceClient) ListTenantsStatus(ctx context.Context, in *ListTenantsStatusRequest, opts ...grpc.CallOption) (*ListTenantsStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTenantsStatusResponse)
+	err := c.cc.Invoke(ctx, NotificationService_ListTenantsStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceClient) DrainInstance(ctx context.Context, in *DrainInstanceRequest, opts ...grpc.CallOption) (*DrainStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DrainStatus)
+	err := c.cc.Invoke(ctx, NotificationService_DrainInstance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceClient) GetDrainStatus(ctx context.Context, in *GetDrainStatusRequest, opts ...grpc.CallOption) (*DrainStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DrainStatus)
+	err := c.cc.Invoke(ctx, NotificationService_GetDrainStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceClient) TransferNotifications(ctx context.Context, in *TransferNotificationsRequest, opts ...grpc.CallOption) (*TransferNotificationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransferNotificationsResponse)
+	err := c.cc.Invoke(ctx, NotificationService_TransferNotifications_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceClient) ListCaches(ctx context.Context, in *ListCachesRequest, opts ...grpc.CallOption) (*ListCachesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCachesResponse)
+	err := c.cc.Invoke(ctx, NotificationService_ListCaches_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceClient) FlushCaches(ctx context.Context, in *FlushCachesRequest, opts ...grpc.CallOption) (*ListCachesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCachesResponse)
+	err := c.cc.Invoke(ctx, NotificationService_FlushCaches_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceClient) Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PingResponse)
+	err := c.cc.Invoke(ctx, NotificationService_Ping_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // NotificationServiceServer is the server API for NotificationService service.
 // All implementations must embed UnimplementedNotificationServiceServer
 // for forward compatibility.
@@ -104,10 +281,24 @@ func (c *notificationServiceClient) CancelNotification(ctx context.Context, in *
 // NotificationService defines two RPC methods.
 type NotificationServiceServer interface {
 	SendNotification(context.Context, *NotificationRequest) (*NotificationResponse, error)
+	EstimateNotificationSize(context.Context, *NotificationRequest) (*NotificationSizeEstimate, error)
+	SendNotificationGroup(context.Context, *NotificationGroupRequest) (*NotificationGroupResponse, error)
+	GetNotificationGroup(context.Context, *GetNotificationGroupRequest) (*NotificationGroupResponse, error)
 	GetNotificationStatus(context.Context, *GetNotificationStatusRequest) (*NotificationResponse, error)
 	ListNotifications(context.Context, *ListNotificationsRequest) (*ListNotificationsResponse, error)
+	ListNotificationsStream(*ListNotificationsRequest, grpc.ServerStreamingServer[NotificationResponse]) error
 	RescheduleNotification(context.Context, *RescheduleNotificationRequest) (*NotificationResponse, error)
 	CancelNotification(context.Context, *CancelNotificationRequest) (*NotificationResponse, error)
+	GetCostSummary(context.Context, *CostSummaryRequest) (*CostSummaryResponse, error)
+	GetCapabilities(context.Context, *GetCapabilitiesRequest) (*CapabilitiesResponse, error)
+	TestTenantDelivery(context.Context, *TestTenantDeliveryRequest) (*TestTenantDeliveryResponse, error)
+	ListTenantsStatus(context.Context, *ListTenantsStatusRequest) (*ListTenantsStatusResponse, error)
+	DrainInstance(context.Context, *DrainInstanceRequest) (*DrainStatus, error)
+	GetDrainStatus(context.Context, *GetDrainStatusRequest) (*DrainStatus, error)
+	TransferNotifications(context.Context, *TransferNotificationsRequest) (*TransferNotificationsResponse, error)
+	ListCaches(context.Context, *ListCachesRequest) (*ListCachesResponse, error)
+	FlushCaches(context.Context, *FlushCachesRequest) (*ListCachesResponse, error)
+	Ping(context.Context, *PingRequest) (*PingResponse, error)
 	mustEmbedUnimplementedNotificationServiceServer()
 }
 
@@ -121,18 +312,60 @@ type UnimplementedNotificationServiceServer struct{}
 func (UnimplementedNotificationServiceServer) SendNotification(context.Context, *NotificationRequest) (*NotificationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SendNotification not implemented")
 }
+func (UnimplementedNotificationServiceServer) EstimateNotificationSize(context.Context, *NotificationRequest) (*NotificationSizeEstimate, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EstimateNotificationSize not implemented")
+}
+func (UnimplementedNotificationServiceServer) SendNotificationGroup(context.Context, *NotificationGroupRequest) (*NotificationGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendNotificationGroup not implemented")
+}
+func (UnimplementedNotificationServiceServer) GetNotificationGroup(context.Context, *GetNotificationGroupRequest) (*NotificationGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNotificationGroup not implemented")
+}
 func (UnimplementedNotificationServiceServer) GetNotificationStatus(context.Context, *GetNotificationStatusRequest) (*NotificationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetNotificationStatus not implemented")
 }
 func (UnimplementedNotificationServiceServer) ListNotifications(context.Context, *ListNotificationsRequest) (*ListNotificationsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListNotifications not implemented")
 }
+func (UnimplementedNotificationServiceServer) ListNotificationsStream(*ListNotificationsRequest, grpc.ServerStreamingServer[NotificationResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ListNotificationsStream not implemented")
+}
 func (UnimplementedNotificationServiceServer) RescheduleNotification(context.Context, *RescheduleNotificationRequest) (*NotificationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RescheduleNotification not implemented")
 }
 func (UnimplementedNotificationServiceServer) CancelNotification(context.Context, *CancelNotificationRequest) (*NotificationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CancelNotification not implemented")
 }
+func (UnimplementedNotificationServiceServer) GetCostSummary(context.Context, *CostSummaryRequest) (*CostSummaryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCostSummary not implemented")
+}
+func (UnimplementedNotificationServiceServer) GetCapabilities(context.Context, *GetCapabilitiesRequest) (*CapabilitiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCapabilities not implemented")
+}
+func (UnimplementedNotificationServiceServer) TestTenantDelivery(context.Context, *TestTenantDeliveryRequest) (*TestTenantDeliveryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TestTenantDelivery not implemented")
+}
+func (UnimplementedNotificationServiceServer) ListTenantsStatus(context.Context, *ListTenantsStatusRequest) (*ListTenantsStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTenantsStatus not implemented")
+}
+func (UnimplementedNotificationServiceServer) DrainInstance(context.Context, *DrainInstanceRequest) (*DrainStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DrainInstance not implemented")
+}
+func (UnimplementedNotificationServiceServer) GetDrainStatus(context.Context, *GetDrainStatusRequest) (*DrainStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDrainStatus not implemented")
+}
+func (UnimplementedNotificationServiceServer) TransferNotifications(context.Context, *TransferNotificationsRequest) (*TransferNotificationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransferNotifications not implemented")
+}
+func (UnimplementedNotificationServiceServer) ListCaches(context.Context, *ListCachesRequest) (*ListCachesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCaches not implemented")
+}
+func (UnimplementedNotificationServiceServer) FlushCaches(context.Context, *FlushCachesRequest) (*ListCachesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FlushCaches not implemented")
+}
+func (UnimplementedNotificationServiceServer) Ping(context.Context, *PingRequest) (*PingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
+}
 func (UnimplementedNotificationServiceServer) mustEmbedUnimplementedNotificationServiceServer() {}
 func (UnimplementedNotificationServiceServer) testEmbeddedByValue()                             {}
 
@@ -172,6 +405,60 @@ func _NotificationService_SendNotification_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NotificationService_EstimateNotificationSize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NotificationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).EstimateNotificationSize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotificationService_EstimateNotificationSize_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).EstimateNotificationSize(ctx, req.(*NotificationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationService_SendNotificationGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NotificationGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).SendNotificationGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotificationService_SendNotificationGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).SendNotificationGroup(ctx, req.(*NotificationGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationService_GetNotificationGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNotificationGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).GetNotificationGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotificationService_GetNotificationGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).GetNotificationGroup(ctx, req.(*GetNotificationGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _NotificationService_GetNotificationStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetNotificationStatusRequest)
 	if err := dec(in); err != nil {
@@ -208,6 +495,17 @@ func _NotificationService_ListNotifications_Handler(srv interface{}, ctx context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NotificationService_ListNotificationsStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListNotificationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NotificationServiceServer).ListNotificationsStream(m, &grpc.GenericServerStream[ListNotificationsRequest, NotificationResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NotificationService_ListNotificationsStreamServer = grpc.ServerStreamingServer[NotificationResponse]
+
 func _NotificationService_RescheduleNotification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RescheduleNotificationRequest)
 	if err := dec(in); err != nil {
@@ -244,6 +542,186 @@ func _NotificationService_CancelNotification_Handler(srv interface{}, ctx contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NotificationService_GetCostSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CostSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).GetCostSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotificationService_GetCostSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).GetCostSummary(ctx, req.(*CostSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationService_GetCapabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCapabilitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).GetCapabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotificationService_GetCapabilities_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).GetCapabilities(ctx, req.(*GetCapabilitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationService_TestTenantDelivery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TestTenantDeliveryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).TestTenantDelivery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotificationService_TestTenantDelivery_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).TestTenantDelivery(ctx, req.(*TestTenantDeliveryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationService_ListTenantsStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTenantsStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).ListTenantsStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotificationService_ListTenantsStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).ListTenantsStatus(ctx, req.(*ListTenantsStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationService_DrainInstance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrainInstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).DrainInstance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotificationService_DrainInstance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).DrainInstance(ctx, req.(*DrainInstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationService_GetDrainStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDrainStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).GetDrainStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotificationService_GetDrainStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).GetDrainStatus(ctx, req.(*GetDrainStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationService_TransferNotifications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferNotificationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).TransferNotifications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotificationService_TransferNotifications_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).TransferNotifications(ctx, req.(*TransferNotificationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationService_ListCaches_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCachesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).ListCaches(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotificationService_ListCaches_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).ListCaches(ctx, req.(*ListCachesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationService_FlushCaches_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FlushCachesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).FlushCaches(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotificationService_FlushCaches_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).FlushCaches(ctx, req.(*FlushCachesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationService_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).Ping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotificationService_Ping_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).Ping(ctx, req.(*PingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // NotificationService_ServiceDesc is the grpc.ServiceDesc for NotificationService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -255,6 +733,18 @@ var NotificationService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendNotification",
 			Handler:    _NotificationService_SendNotification_Handler,
 		},
+		{
+			MethodName: "EstimateNotificationSize",
+			Handler:    _NotificationService_EstimateNotificationSize_Handler,
+		},
+		{
+			MethodName: "SendNotificationGroup",
+			Handler:    _NotificationService_SendNotificationGroup_Handler,
+		},
+		{
+			MethodName: "GetNotificationGroup",
+			Handler:    _NotificationService_GetNotificationGroup_Handler,
+		},
 		{
 			MethodName: "GetNotificationStatus",
 			Handler:    _NotificationService_GetNotificationStatus_Handler,
@@ -271,7 +761,53 @@ var NotificationService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CancelNotification",
 			Handler:    _NotificationService_CancelNotification_Handler,
 		},
+		{
+			MethodName: "GetCostSummary",
+			Handler:    _NotificationService_GetCostSummary_Handler,
+		},
+		{
+			MethodName: "GetCapabilities",
+			Handler:    _NotificationService_GetCapabilities_Handler,
+		},
+		{
+			MethodName: "TestTenantDelivery",
+			Handler:    _NotificationService_TestTenantDelivery_Handler,
+		},
+		{
+			MethodName: "ListTenantsStatus",
+			Handler:    _NotificationService_ListTenantsStatus_Handler,
+		},
+		{
+			MethodName: "DrainInstance",
+			Handler:    _NotificationService_DrainInstance_Handler,
+		},
+		{
+			MethodName: "GetDrainStatus",
+			Handler:    _NotificationService_GetDrainStatus_Handler,
+		},
+		{
+			MethodName: "TransferNotifications",
+			Handler:    _NotificationService_TransferNotifications_Handler,
+		},
+		{
+			MethodName: "ListCaches",
+			Handler:    _NotificationService_ListCaches_Handler,
+		},
+		{
+			MethodName: "FlushCaches",
+			Handler:    _NotificationService_FlushCaches_Handler,
+		},
+		{
+			MethodName: "Ping",
+			Handler:    _NotificationService_Ping_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListNotificationsStream",
+			Handler:       _NotificationService_ListNotificationsStream_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/proto/pinguin.proto",
 }
@@ -0,0 +1,113 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// DebugHeader is the HTTP header and gRPC metadata key that asks for one request's
+// logs at DEBUG regardless of the configured level. Only the value "1" turns it on.
+const DebugHeader = "x-debug"
+
+const redactedValue = "[redacted]"
+
+type debugContextKey struct{}
+
+// WithDebug marks ctx so every record logged with it is written, DEBUG included.
+func WithDebug(ctx context.Context) context.Context {
+	return context.WithValue(ctx, debugContextKey{}, true)
+}
+
+// DebugRequested reports whether ctx was marked by WithDebug.
+func DebugRequested(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	requested, _ := ctx.Value(debugContextKey{}).(bool)
+	return requested
+}
+
+// DebugHeaderEnabled reports whether a DebugHeader value turns the override on.
+func DebugHeaderEnabled(value string) bool {
+	return strings.TrimSpace(value) == "1"
+}
+
+// sensitiveKeyParts mark attribute keys whose values a debug override never writes out.
+var sensitiveKeyParts = []string{"password", "secret", "token", "authorization", "cookie", "credential", "api_key", "apikey"}
+
+// contentKeys name attributes carrying message content or addresses.
+var contentKeys = map[string]bool{"recipient": true, "to": true, "from": true, "subject": true, "message": true, "body": true, "ics": true}
+
+// debugOverrideHandler passes records at or above level to the wrapped handler, and any
+// record logged with a WithDebug context. Records written only because of the override
+// have secret-looking and content attributes redacted, so raising one request's
+// verbosity cannot expose credentials, message bodies or addresses.
+type debugOverrideHandler struct {
+	plain    slog.Handler
+	redacted slog.Handler
+	level    slog.Leveler
+}
+
+// NewDebugOverrideHandler wraps inner, which must accept DEBUG records, so it logs at
+// level except for requests marked by WithDebug.
+func NewDebugOverrideHandler(inner slog.Handler, level slog.Leveler) slog.Handler {
+	return &debugOverrideHandler{plain: inner, redacted: inner, level: level}
+}
+
+func (handler *debugOverrideHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= handler.level.Level() || DebugRequested(ctx)
+}
+
+func (handler *debugOverrideHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level >= handler.level.Level() {
+		return handler.plain.Handle(ctx, record)
+	}
+	redactedRecord := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redactedRecord.AddAttrs(redactAttr(attr))
+		return true
+	})
+	return handler.redacted.Handle(ctx, redactedRecord)
+}
+
+func (handler *debugOverrideHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redactedAttrs := make([]slog.Attr, len(attrs))
+	for index, attr := range attrs {
+		redactedAttrs[index] = redactAttr(attr)
+	}
+	return &debugOverrideHandler{plain: handler.plain.WithAttrs(attrs), redacted: handler.redacted.WithAttrs(redactedAttrs), level: handler.level}
+}
+
+func (handler *debugOverrideHandler) WithGroup(name string) slog.Handler {
+	return &debugOverrideHandler{plain: handler.plain.WithGroup(name), redacted: handler.redacted.WithGroup(name), level: handler.level}
+}
+
+func redactAttr(attr slog.Attr) slog.Attr {
+	value := attr.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		members := value.Group()
+		redactedMembers := make([]any, len(members))
+		for index, member := range members {
+			redactedMembers[index] = redactAttr(member)
+		}
+		return slog.Group(attr.Key, redactedMembers...)
+	}
+	if sensitiveKey(attr.Key) {
+		return slog.String(attr.Key, redactedValue)
+	}
+	return slog.Attr{Key: attr.Key, Value: value}
+}
+
+func sensitiveKey(key string) bool {
+	normalized := strings.ToLower(key)
+	if contentKeys[normalized] {
+		return true
+	}
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(normalized, part) {
+			return true
+		}
+	}
+	return false
+}
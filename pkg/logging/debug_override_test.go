@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func newBufferedOverrideLogger(level slog.Level) (*slog.Logger, *bytes.Buffer) {
+	var buffer bytes.Buffer
+	inner := slog.NewTextHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelDebug})
+	return slog.New(NewDebugOverrideHandler(inner, level)), &buffer
+}
+
+func TestDebugOverrideElevatesOnlyMarkedContexts(t *testing.T) {
+	logger, buffer := newBufferedOverrideLogger(slog.LevelInfo)
+	plainCtx := context.Background()
+	debugCtx := WithDebug(plainCtx)
+
+	logger.DebugContext(plainCtx, "plain_debug")
+	logger.DebugContext(debugCtx, "elevated_debug")
+	logger.DebugContext(plainCtx, "plain_debug_after")
+	output := buffer.String()
+	if strings.Contains(output, "plain_debug") {
+		t.Fatalf("expected unmarked debug records to stay filtered, got %q", output)
+	}
+	if !strings.Contains(output, "msg=elevated_debug") {
+		t.Fatalf("expected the marked record to be written, got %q", output)
+	}
+	if !logger.Enabled(debugCtx, slog.LevelDebug) || logger.Enabled(plainCtx, slog.LevelDebug) {
+		t.Fatalf("expected DEBUG to be enabled for the marked context only")
+	}
+}
+
+func TestDebugOverrideRedactsSecretsInElevatedRecords(t *testing.T) {
+	logger, buffer := newBufferedOverrideLogger(slog.LevelInfo)
+	debugCtx := WithDebug(context.Background())
+
+	logger.With("auth_token", "tok-123").DebugContext(debugCtx, "elevated",
+		"recipient", "user@example.com",
+		"recipient_digest", "abc123",
+		"smtp", slog.GroupValue(slog.String("password", "hunter2"), slog.String("host", "smtp.example.com")),
+		"Authorization", "Bearer tok-456",
+		"message", "hello there",
+	)
+	output := buffer.String()
+	for _, secret := range []string{"tok-123", "user@example.com", "hunter2", "tok-456", "hello there"} {
+		if strings.Contains(output, secret) {
+			t.Fatalf("expected %q to be redacted, got %q", secret, output)
+		}
+	}
+	for _, kept := range []string{"recipient_digest=abc123", "smtp.host=smtp.example.com", "recipient=[redacted]"} {
+		if !strings.Contains(output, kept) {
+			t.Fatalf("expected %q in the output, got %q", kept, output)
+		}
+	}
+
+	buffer.Reset()
+	logger.InfoContext(debugCtx, "regular", "recipient", "user@example.com")
+	if !strings.Contains(buffer.String(), "recipient=user@example.com") {
+		t.Fatalf("expected records at the configured level to be unchanged, got %q", buffer.String())
+	}
+}
+
+func TestDebugHeaderEnabled(t *testing.T) {
+	for value, expected := range map[string]bool{"1": true, " 1 ": true, "true": false, "0": false, "": false} {
+		if DebugHeaderEnabled(value) != expected {
+			t.Fatalf("DebugHeaderEnabled(%q) != %v", value, expected)
+		}
+	}
+}
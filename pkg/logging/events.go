@@ -0,0 +1,84 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
+// EventKey is the attribute that carries a record's lifecycle event name.
+const EventKey = "event"
+
+// Event names a lifecycle step. Event names are a contract: log-based alerting matches
+// the event attribute, so a published name never changes, while the message logged
+// beside it may be reworded freely.
+type Event string
+
+const (
+	// EventNotificationReceived is logged when a send request reaches the service.
+	EventNotificationReceived Event = "notification.received"
+	// EventNotificationRejected is logged when a send request fails validation or tenant policy.
+	EventNotificationRejected Event = "notification.rejected"
+	// EventNotificationPersisted is logged once a notification is stored.
+	EventNotificationPersisted Event = "notification.persisted"
+	// EventNotificationDeferred is logged when an immediate send is left to the retry
+	// worker by pacing, a provider breaker or the in-flight limit.
+	EventNotificationDeferred Event = "notification.deferred"
+	// EventNotificationDispatchAttempted is logged after each provider call that did not fail.
+	EventNotificationDispatchAttempted Event = "notification.dispatch_attempted"
+	// EventNotificationDispatchFailed is logged after each provider call that failed.
+	EventNotificationDispatchFailed Event = "notification.dispatch_failed"
+	// EventNotificationRetryScheduled is logged when a failed notification awaits another attempt.
+	EventNotificationRetryScheduled Event = "notification.retry_scheduled"
+	// EventNotificationTerminal is logged when a notification is sent, cancelled or dead-lettered.
+	EventNotificationTerminal Event = "notification.terminal"
+	// EventTenantResolved is logged when a request is bound to its tenant.
+	EventTenantResolved Event = "tenant.resolved"
+	// EventTenantResolutionFailed is logged when a request names a tenant that cannot be loaded.
+	EventTenantResolutionFailed Event = "tenant.resolution_failed"
+	// EventAuthRejected is logged when a session, bearer token or caller address is refused.
+	EventAuthRejected Event = "auth.rejected"
+	// EventGRPCReady is logged once the gRPC listener is bound; deployment waits for it.
+	EventGRPCReady Event = "pinguin.grpc.ready"
+)
+
+// eventLevels is the one place event severity is decided, so alert noise is tuned here
+// rather than at each log site.
+var eventLevels = map[Event]slog.Level{
+	EventNotificationReceived:          slog.LevelInfo,
+	EventNotificationRejected:          slog.LevelDebug,
+	EventNotificationPersisted:         slog.LevelInfo,
+	EventNotificationDeferred:          slog.LevelInfo,
+	EventNotificationDispatchAttempted: slog.LevelInfo,
+	EventNotificationDispatchFailed:    slog.LevelError,
+	EventNotificationRetryScheduled:    slog.LevelWarn,
+	EventNotificationTerminal:          slog.LevelInfo,
+	EventTenantResolved:                slog.LevelDebug,
+	EventTenantResolutionFailed:        slog.LevelWarn,
+	EventAuthRejected:                  slog.LevelWarn,
+	EventGRPCReady:                     slog.LevelInfo,
+}
+
+// Events returns every defined event, sorted by name.
+func Events() []Event {
+	events := make([]Event, 0, len(eventLevels))
+	for event := range eventLevels {
+		events = append(events, event)
+	}
+	slices.Sort(events)
+	return events
+}
+
+// Level returns the severity event is logged at.
+func (event Event) Level() slog.Level {
+	return eventLevels[event]
+}
+
+// LogEvent logs msg at event's level with the event attribute ahead of args. Records
+// honor a WithDebug ctx like any other.
+func LogEvent(ctx context.Context, logger *slog.Logger, event Event, msg string, args ...any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	logger.Log(ctx, event.Level(), msg, append([]any{EventKey, string(event)}, args...)...)
+}
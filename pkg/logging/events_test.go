@@ -0,0 +1,107 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// moduleRoot is the repository root relative to this package.
+const moduleRoot = "../.."
+
+var eventNamePattern = regexp.MustCompile(`^[a-z]+(\.[a-z_]+)+$`)
+
+func TestLogEventUsesTheEventLevelAndAttribute(t *testing.T) {
+	var output bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&output, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	LogEvent(context.Background(), logger, EventNotificationDispatchFailed, "provider_dispatch", "notification_id", "notif-1")
+
+	expected := `level=ERROR msg=provider_dispatch event=notification.dispatch_failed notification_id=notif-1`
+	if !strings.Contains(output.String(), expected) {
+		t.Fatalf("expected %q, got %q", expected, output.String())
+	}
+}
+
+func TestLogEventHonorsTheConfiguredLevel(t *testing.T) {
+	var output bytes.Buffer
+	logger := slog.New(NewDebugOverrideHandler(slog.NewTextHandler(&output, &slog.HandlerOptions{Level: slog.LevelDebug}), slog.LevelInfo))
+
+	LogEvent(context.Background(), logger, EventTenantResolved, "tenant_resolved")
+	if output.Len() != 0 {
+		t.Fatalf("expected the DEBUG event to be filtered at INFO, got %q", output.String())
+	}
+	LogEvent(WithDebug(context.Background()), logger, EventTenantResolved, "tenant_resolved")
+	if !strings.Contains(output.String(), "event=tenant.resolved") {
+		t.Fatalf("expected a debug-marked request to log the event, got %q", output.String())
+	}
+}
+
+func TestEventsAreNamedAndLeveled(t *testing.T) {
+	events := Events()
+	if len(events) != len(eventLevels) {
+		t.Fatalf("expected %d events, got %d", len(eventLevels), len(events))
+	}
+	for _, event := range events {
+		if !eventNamePattern.MatchString(string(event)) {
+			t.Fatalf("event %q must be dotted lowercase, such as notification.received", event)
+		}
+	}
+}
+
+// TestLogSitesUseEventConstants keeps event names in this package: any other Go source
+// that spells out the event key or a published event name bypasses the constants and
+// their centralized severity.
+func TestLogSitesUseEventConstants(t *testing.T) {
+	eventNames := make(map[string]bool, len(eventLevels))
+	for _, event := range Events() {
+		eventNames[string(event)] = true
+	}
+	fileSet := token.NewFileSet()
+	walkErr := filepath.WalkDir(moduleRoot, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			switch entry.Name() {
+			case ".git", "node_modules", "docs", "web":
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") || strings.HasSuffix(path, ".pb.go") || filepath.Base(path) == "events.go" {
+			return nil
+		}
+		file, parseErr := parser.ParseFile(fileSet, path, nil, parser.SkipObjectResolution)
+		if parseErr != nil {
+			return parseErr
+		}
+		ast.Inspect(file, func(node ast.Node) bool {
+			literal, ok := node.(*ast.BasicLit)
+			if !ok || literal.Kind != token.STRING {
+				return true
+			}
+			value, unquoteErr := strconv.Unquote(literal.Value)
+			if unquoteErr != nil {
+				return true
+			}
+			if value == EventKey || eventNames[value] {
+				t.Errorf("%s: %q is spelled out; log it with logging.LogEvent and an Event constant", fileSet.Position(literal.Pos()), value)
+			}
+			return true
+		})
+		return nil
+	})
+	if walkErr != nil {
+		t.Fatalf("walk error: %v", walkErr)
+	}
+}
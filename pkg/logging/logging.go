@@ -7,7 +7,8 @@ import (
 )
 
 // NewLogger creates a slog.Logger configured according to the provided log
-// level string (DEBUG/INFO/WARN/ERROR), defaulting to INFO.
+// level string (DEBUG/INFO/WARN/ERROR), defaulting to INFO. Requests whose context
+// was marked by WithDebug log at DEBUG regardless.
 func NewLogger(levelString string) *slog.Logger {
 	var level slog.Level
 	switch strings.ToUpper(levelString) {
@@ -24,7 +25,7 @@ func NewLogger(levelString string) *slog.Logger {
 	}
 
 	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
-		Level: level,
+		Level: slog.LevelDebug,
 	})
-	return slog.New(handler)
+	return slog.New(NewDebugOverrideHandler(handler, level))
 }
@@ -113,7 +113,7 @@ func TestMultitenantIsolation(t *testing.T) {
 	}
 
 	// 8. Verify Tenant B cannot Cancel A's notification
-	_, err = svc.CancelNotification(ctxB, respA.NotificationID)
+	_, err = svc.CancelNotification(ctxB, respA.NotificationID, "", "")
 	if err == nil {
 		t.Fatal("expected error cancelling Tenant A notification from Tenant B, got nil")
 	}
@@ -221,7 +221,7 @@ func setupTestDB(t *testing.T) (*gorm.DB, *tenant.SecretKeeper) {
 		t.Fatalf("gorm.Open failed: %v", err)
 	}
 
-	err = db.AutoMigrate(&model.Notification{}, &model.NotificationAttachment{}, &tenant.Tenant{}, &tenant.TenantDomain{}, &tenant.TenantAdmin{}, &tenant.EmailProfile{}, &tenant.SMSProfile{})
+	err = db.AutoMigrate(&model.Notification{}, &model.NotificationAttachment{}, &model.AttachmentBlob{}, &tenant.Tenant{}, &tenant.TenantDomain{}, &tenant.TenantAdmin{}, &tenant.EmailProfile{}, &tenant.SMSProfile{})
 	if err != nil {
 		t.Fatalf("AutoMigrate failed: %v", err)
 	}
@@ -88,7 +88,7 @@ func openIntegrationDatabase(t *testing.T) *gorm.DB {
 	if err != nil {
 		t.Fatalf("sqlite open error: %v", err)
 	}
-	if migrateErr := database.AutoMigrate(&model.Notification{}, &model.NotificationAttachment{}); migrateErr != nil {
+	if migrateErr := database.AutoMigrate(&model.Notification{}, &model.NotificationAttachment{}, &model.AttachmentBlob{}); migrateErr != nil {
 		t.Fatalf("migration error: %v", migrateErr)
 	}
 	return database
@@ -0,0 +1,54 @@
+package integrationtest
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/tyemirov/pinguin/internal/config"
+	"github.com/tyemirov/pinguin/internal/model"
+	"github.com/tyemirov/pinguin/internal/seed"
+	"github.com/tyemirov/pinguin/internal/service"
+	"github.com/tyemirov/pinguin/internal/tenant"
+)
+
+func TestSeededDatabaseListsPerTenant(t *testing.T) {
+	db, secretKeeper := setupTestDB(t)
+	options := seed.Options{Seed: 11, Count: 120, BaseTime: time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC)}
+	summary, err := seed.Run(context.Background(), db, secretKeeper, options)
+	if err != nil {
+		t.Fatalf("seed.Run failed: %v", err)
+	}
+
+	expected := map[string]int{}
+	for _, notification := range seed.Notifications(options) {
+		expected[notification.TenantID]++
+	}
+	repo := tenant.NewRepository(db, secretKeeper)
+	svc := service.NewNotificationService(db, slog.New(slog.NewTextHandler(io.Discard, nil)), config.Config{MaxRetries: 3, RetryIntervalSec: 1}, repo)
+	listed := 0
+	for tenantID, count := range expected {
+		ctx, err := resolveContext(db, repo, tenantID)
+		if err != nil {
+			t.Fatalf("resolveContext(%s) failed: %v", tenantID, err)
+		}
+		responses, err := svc.ListNotifications(ctx, model.NotificationListFilters{})
+		if err != nil {
+			t.Fatalf("ListNotifications(%s) failed: %v", tenantID, err)
+		}
+		if len(responses) != count {
+			t.Fatalf("expected %d notifications for %s, got %d", count, tenantID, len(responses))
+		}
+		for _, response := range responses {
+			if response.Status == seed.LegacyStatusFailed {
+				t.Fatalf("expected legacy statuses to be canonicalized, got %q", response.Status)
+			}
+		}
+		listed += len(responses)
+	}
+	if listed != summary.Notifications {
+		t.Fatalf("expected %d listed notifications, got %d", summary.Notifications, listed)
+	}
+}